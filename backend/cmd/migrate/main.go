@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/souta/ai-orchestration/pkg/database/migrate"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: migrate [flags] <command>
+
+Commands:
+  up       Apply all pending migrations
+  down     Roll back the most recent migration(s) (see -steps)
+  status   Show applied and pending migrations
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+func main() {
+	dir := flag.String("dir", "migrations", "Directory containing NNN_name.sql and NNN_name.down.sql files")
+	steps := flag.Int("steps", 1, "Number of migrations to roll back with down")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+	if command != "up" && command != "down" && command != "status" {
+		fmt.Printf("❌ Unknown command: %s\n", command)
+		usage()
+		os.Exit(2)
+	}
+
+	migrations, err := migrate.Load(os.DirFS(*dir))
+	if err != nil {
+		fmt.Printf("❌ Failed to load migrations: %v\n", err)
+		os.Exit(1)
+	}
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		fmt.Println("❌ DATABASE_URL environment variable is required")
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, dbURL)
+	if err != nil {
+		fmt.Printf("❌ Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close(context.Background())
+
+	migrator := migrate.NewMigrator(migrate.NewPostgresStore(conn), migrations)
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("✅ Applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(applied) == 0 {
+			fmt.Println("✅ Already up to date")
+		}
+
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			fmt.Printf("↩️  Reverted %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("✅ Nothing to roll back")
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		pending := 0
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			} else {
+				pending++
+			}
+			down := ""
+			if !s.HasDown {
+				down = " (no down)"
+			}
+			fmt.Printf("  %03d_%-32s %s%s\n", s.Version, s.Name, state, down)
+		}
+		fmt.Printf("\n%d migration(s), %d pending\n", len(statuses), pending)
+	}
+}
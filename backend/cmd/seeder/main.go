@@ -25,6 +25,7 @@ func main() {
 	verbose := flag.Bool("verbose", false, "Show detailed output")
 	blocksOnly := flag.Bool("blocks-only", false, "Only migrate blocks, skip projects")
 	projectsOnly := flag.Bool("projects-only", false, "Only migrate projects, skip blocks")
+	prune := flag.Bool("prune", false, "After migration, delete system blocks/projects no longer in the registry")
 	tenantIDStr := flag.String("tenant-id", defaultTenantID, "Tenant ID for project migration")
 	flag.Parse()
 
@@ -85,7 +86,7 @@ func main() {
 	}
 	ctx := context.Background()
 
-	pool, err := database.NewPool(ctx, database.DefaultConfig(dbURL))
+	pool, err := database.NewPool(ctx, database.ConfigFromEnv(dbURL))
 	if err != nil {
 		fmt.Printf("❌ Failed to connect to database: %v\n", err)
 		os.Exit(1)
@@ -99,12 +100,26 @@ func main() {
 	stepRepo := postgres.NewStepRepository(pool)
 	edgeRepo := postgres.NewEdgeRepository(pool)
 	blockGroupRepo := postgres.NewBlockGroupRepository(pool)
+	projectVersionRepo := postgres.NewProjectVersionRepository(pool)
+	runRepo := postgres.NewRunRepository(pool)
 
 	// Create migrators
 	blockMigrator := migration.NewMigrator(blockRepo, versionRepo)
 	projectMigrator := migration.NewProjectMigrator(projectRepo, stepRepo, edgeRepo).
 		WithBlockRepo(blockRepo).
-		WithBlockGroupRepo(blockGroupRepo)
+		WithBlockGroupRepo(blockGroupRepo).
+		WithVersionRepo(projectVersionRepo).
+		WithRunRepo(runRepo)
+	pruner := migration.NewPruner(blockRepo, projectRepo)
+
+	// Registries considered for pruning (nil skips that kind)
+	pruneBlockRegistry, pruneWorkflowRegistry := blockRegistry, workflowRegistry
+	if *projectsOnly {
+		pruneBlockRegistry = nil
+	}
+	if *blocksOnly {
+		pruneWorkflowRegistry = nil
+	}
 
 	if *dryRun {
 		// Dry run mode
@@ -196,10 +211,38 @@ func main() {
 				}
 			}
 
+			if len(projectChanges.Warnings) > 0 {
+				fmt.Printf("\n⚠️  Steps referenced by recent runs would be removed:\n")
+				for _, warning := range projectChanges.Warnings {
+					fmt.Printf("   %s\n", warning)
+				}
+			}
+
 			fmt.Printf("\n📊 Project Summary: %d to create, %d to update, %d unchanged\n",
 				len(projectChanges.ToCreate), len(projectChanges.ToUpdate), len(projectChanges.Unchanged))
 		}
 
+		// Prune preview
+		if *prune {
+			plan, err := pruner.Plan(ctx, pruneBlockRegistry, pruneWorkflowRegistry, tenantID)
+			if err != nil {
+				fmt.Printf("❌ Prune dry run failed: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("\n=== PRUNE ===\n")
+			for _, target := range plan.Projects {
+				fmt.Printf("   - project %s\n", target.Slug)
+			}
+			for _, target := range plan.Blocks {
+				fmt.Printf("   - block %s\n", target.Slug)
+			}
+			printPruneSkips(plan.Skipped)
+
+			fmt.Printf("\n📊 Prune Summary: %d blocks and %d projects to delete, %d skipped\n",
+				len(plan.Blocks), len(plan.Projects), len(plan.Skipped))
+		}
+
 		os.Exit(0)
 	}
 
@@ -270,6 +313,13 @@ func main() {
 		fmt.Printf("   Created: %d, Updated: %d, Unchanged: %d\n",
 			len(projectResult.Created), len(projectResult.Updated), len(projectResult.Unchanged))
 
+		if len(projectResult.Warnings) > 0 {
+			fmt.Printf("\n⚠️  Steps referenced by recent runs were removed:\n")
+			for _, warning := range projectResult.Warnings {
+				fmt.Printf("   %s\n", warning)
+			}
+		}
+
 		if len(projectResult.Errors) > 0 {
 			fmt.Printf("\n⚠️  Project Warnings:\n")
 			for _, err := range projectResult.Errors {
@@ -278,9 +328,52 @@ func main() {
 		}
 	}
 
+	// Prune system blocks/projects removed from the registries
+	if *prune {
+		fmt.Printf("\n=== PRUNE ===\n")
+		plan, err := pruner.Plan(ctx, pruneBlockRegistry, pruneWorkflowRegistry, tenantID)
+		if err != nil {
+			fmt.Printf("❌ Prune failed: %v\n", err)
+			os.Exit(1)
+		}
+		pruneResult := pruner.Apply(ctx, plan, tenantID)
+
+		if *verbose {
+			for _, slug := range pruneResult.Projects {
+				fmt.Printf("   - project %s\n", slug)
+			}
+			for _, slug := range pruneResult.Blocks {
+				fmt.Printf("   - block %s\n", slug)
+			}
+		}
+		printPruneSkips(pruneResult.Skipped)
+
+		fmt.Printf("✅ Prune completed!\n")
+		fmt.Printf("   Deleted blocks: %d, Deleted projects: %d, Skipped: %d\n",
+			len(pruneResult.Blocks), len(pruneResult.Projects), len(pruneResult.Skipped))
+
+		if len(pruneResult.Errors) > 0 {
+			fmt.Printf("\n⚠️  Prune Warnings:\n")
+			for _, err := range pruneResult.Errors {
+				fmt.Printf("   %v\n", err)
+			}
+		}
+	}
+
 	fmt.Printf("\n✅ All migrations completed successfully!\n")
 }
 
+// printPruneSkips warns about removed blocks that are kept because they are still referenced
+func printPruneSkips(skipped []migration.PruneSkip) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Printf("\n⚠️  Kept blocks still in use (%d):\n", len(skipped))
+	for _, skip := range skipped {
+		fmt.Printf("   ! %s - %s\n", skip.Slug, skip.Reason)
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -8,7 +8,10 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
 )
 
 // AnthropicAdapter implements the Adapter interface for Anthropic Claude API
@@ -18,20 +21,27 @@ type AnthropicAdapter struct {
 	httpClient *http.Client
 	apiKey     string
 	baseURL    string
+	endpoints  *EndpointAllowlist // Regional and extra endpoints step and tenant config may select
 }
 
 // AnthropicConfig holds the configuration for Anthropic adapter
 type AnthropicConfig struct {
-	Model        string   `json:"model"`         // claude-3-opus-20240229, claude-3-sonnet-20240229, claude-3-haiku-20240307
-	Prompt       string   `json:"prompt"`        // User prompt template with {{variable}} placeholders
-	UserPrompt   string   `json:"user_prompt"`   // Alternative field name for user prompt (for LLM block compatibility)
-	System       string   `json:"system"`        // System message
-	SystemPrompt string   `json:"system_prompt"` // Alternative field name for system prompt (for LLM block compatibility)
-	MaxTokens    int      `json:"max_tokens"`    // Maximum tokens to generate (required by Anthropic)
-	Temperature  *float64 `json:"temperature"`   // 0.0 - 1.0 (nil = use default 0.7)
-	TopP         float64  `json:"top_p"`         // Nucleus sampling
-	TopK         int      `json:"top_k"`         // Top-k sampling
-	Stop         []string `json:"stop"`          // Stop sequences
+	Model        string      `json:"model"`         // claude-3-opus-20240229, claude-3-sonnet-20240229, claude-3-haiku-20240307
+	Prompt       string      `json:"prompt"`        // User prompt template with {{variable}} placeholders
+	UserPrompt   string      `json:"user_prompt"`   // Alternative field name for user prompt (for LLM block compatibility)
+	System       string      `json:"system"`        // System message
+	SystemPrompt string      `json:"system_prompt"` // Alternative field name for system prompt (for LLM block compatibility)
+	MaxTokens    int         `json:"max_tokens"`    // Maximum tokens to generate (required by Anthropic)
+	Temperature  *float64    `json:"temperature"`   // 0.0 - 1.0 (nil = use default 0.7)
+	TopP         float64     `json:"top_p"`         // Nucleus sampling
+	TopK         int         `json:"top_k"`         // Top-k sampling
+	Stop         []string    `json:"stop"`          // Stop sequences
+	Messages     []Message   `json:"messages"`      // Message array sent instead of the prompt and system fields when set
+	Images       ImageInputs `json:"images"`        // Images attached to the user prompt (vision models only)
+
+	ResponseSchema *ResponseSchema `json:"response_schema"` // Structured output: content is JSON matching the schema
+
+	domain.EndpointOverride // base_url or region: routes the request to a regional endpoint
 }
 
 // Anthropic API request/response types
@@ -44,11 +54,65 @@ type anthropicRequest struct {
 	TopP        float64            `json:"top_p,omitempty"`
 	TopK        int                `json:"top_k,omitempty"`
 	StopSeq     []string           `json:"stop_sequences,omitempty"`
+
+	Tools      []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+// anthropicTool defines a tool. Structured output is requested as a single tool whose input
+// schema is the response schema, with tool_choice forcing the model to call it.
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"` // tool
+	Name string `json:"name"`
 }
 
 type anthropicMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	// Images turn the content into image and text blocks when sending a request
+	Images []ImageInput `json:"-"`
+}
+
+type anthropicContentBlock struct {
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *anthropicImageSource `json:"source,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"` // base64 or url
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// MarshalJSON sends a message with images as content blocks. Anthropic recommends placing
+// images before the text that refers to them.
+func (m anthropicMessage) MarshalJSON() ([]byte, error) {
+	if len(m.Images) == 0 {
+		type plain anthropicMessage
+		return json.Marshal(plain(m))
+	}
+	blocks := make([]anthropicContentBlock, 0, len(m.Images)+1)
+	for _, image := range m.Images {
+		source := &anthropicImageSource{Type: "url", URL: image.URL}
+		if image.URL == "" {
+			source = &anthropicImageSource{Type: "base64", MediaType: image.MediaType, Data: image.Data}
+		}
+		blocks = append(blocks, anthropicContentBlock{Type: "image", Source: source})
+	}
+	blocks = append(blocks, anthropicContentBlock{Type: "text", Text: m.Content})
+	return json.Marshal(struct {
+		Role    string                  `json:"role"`
+		Content []anthropicContentBlock `json:"content"`
+	}{Role: m.Role, Content: blocks})
 }
 
 type anthropicResponse struct {
@@ -69,6 +133,15 @@ type anthropicResponse struct {
 	Error *anthropicError `json:"error,omitempty"`
 }
 
+// anthropicToolUse holds the tool_use blocks of a response
+type anthropicToolUse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+}
+
 type anthropicError struct {
 	Type    string `json:"type"`
 	Message string `json:"message"`
@@ -82,8 +155,9 @@ func NewAnthropicAdapter() *AnthropicAdapter {
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
-		apiKey:  os.Getenv("ANTHROPIC_API_KEY"),
-		baseURL: getEnvOrDefault("ANTHROPIC_BASE_URL", "https://api.anthropic.com"),
+		apiKey:    os.Getenv("ANTHROPIC_API_KEY"),
+		baseURL:   getEnvOrDefault("ANTHROPIC_BASE_URL", "https://api.anthropic.com"),
+		endpoints: endpointAllowlistFromEnv("anthropic", "ANTHROPIC_ENDPOINT_ALLOWLIST"),
 	}
 }
 
@@ -115,12 +189,17 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 		}
 	}
 
+	baseURL, err := a.endpoints.Resolve(ctx, "anthropic", a.baseURL, config.EndpointOverride)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Anthropic config: %w", err)
+	}
+
 	// Set defaults
 	if config.Model == "" {
 		config.Model = "claude-3-sonnet-20240229"
 	}
 	if config.MaxTokens == 0 {
-		config.MaxTokens = 4096
+		config.MaxTokens = defaultMaxTokens("anthropic", config.Model, 4096)
 	}
 
 	// Handle temperature: use default 0.7 only if not explicitly set (nil)
@@ -154,6 +233,37 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 		},
 	}
 
+	// A configured message array takes precedence over the prompt and system fields. Anthropic
+	// takes the system prompt separately, so system messages are joined into it.
+	if len(config.Messages) > 0 {
+		if err := validateMessages(config.Messages); err != nil {
+			return nil, fmt.Errorf("invalid Anthropic config: %w", err)
+		}
+		var systemParts []string
+		apiReq.Messages = make([]anthropicMessage, 0, len(config.Messages))
+		for _, msg := range config.Messages {
+			if msg.Role == RoleSystem {
+				systemParts = append(systemParts, msg.Content)
+				continue
+			}
+			apiReq.Messages = append(apiReq.Messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+		}
+		system = strings.Join(systemParts, "\n\n")
+	}
+
+	// Images go with the last user message
+	if err := ValidateImages(config.Images, "anthropic", config.Model); err != nil {
+		return nil, fmt.Errorf("invalid Anthropic config: %w", err)
+	}
+	if len(config.Images) > 0 {
+		for i := len(apiReq.Messages) - 1; i >= 0; i-- {
+			if apiReq.Messages[i].Role == RoleUser {
+				apiReq.Messages[i].Images = config.Images
+				break
+			}
+		}
+	}
+
 	if system != "" {
 		apiReq.System = system
 	}
@@ -168,6 +278,17 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 	if len(config.Stop) > 0 {
 		apiReq.StopSeq = config.Stop
 	}
+	if config.ResponseSchema != nil {
+		if err := config.ResponseSchema.validate(); err != nil {
+			return nil, fmt.Errorf("invalid Anthropic config: %w", err)
+		}
+		apiReq.Tools = []anthropicTool{{
+			Name:        config.ResponseSchema.Name,
+			Description: config.ResponseSchema.Description,
+			InputSchema: config.ResponseSchema.Schema,
+		}}
+		apiReq.ToolChoice = &anthropicToolChoice{Type: "tool", Name: config.ResponseSchema.Name}
+	}
 
 	// Make HTTP request
 	reqBody, err := json.Marshal(apiReq)
@@ -175,7 +296,7 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/v1/messages", bytes.NewReader(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/v1/messages", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -195,6 +316,10 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(a.name, resp, body)
+	}
+
 	var apiResp anthropicResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -210,11 +335,27 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 		return nil, fmt.Errorf("Anthropic API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// Extract content
+	// Extract content. With a response schema the content is the input of the forced tool call.
 	var content string
-	for _, block := range apiResp.Content {
-		if block.Type == "text" {
-			content += block.Text
+	if config.ResponseSchema != nil {
+		var toolUse anthropicToolUse
+		if err := json.Unmarshal(body, &toolUse); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, block := range toolUse.Content {
+			if block.Type == "tool_use" && block.Name == config.ResponseSchema.Name {
+				content = string(block.Input)
+				break
+			}
+		}
+		if content == "" {
+			return nil, fmt.Errorf("Anthropic API returned no %s tool call for the response schema", config.ResponseSchema.Name)
+		}
+	} else {
+		for _, block := range apiResp.Content {
+			if block.Type == "text" {
+				content += block.Text
+			}
 		}
 	}
 
@@ -248,6 +389,12 @@ func (a *AnthropicAdapter) Execute(ctx context.Context, req *Request) (*Response
 	}, nil
 }
 
+// SupportsStructuredOutput reports that response_schema is sent as a forced tool call
+func (a *AnthropicAdapter) SupportsStructuredOutput() bool { return true }
+
+// IsConfigured reports whether an API key is set
+func (a *AnthropicAdapter) IsConfigured() bool { return a.apiKey != "" }
+
 func (a *AnthropicAdapter) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
 		"type": "object",
@@ -341,3 +341,127 @@ func TestAnthropicAdapter_Execute_TemperatureDefault(t *testing.T) {
 	// Verify that default temperature 0.7 was used
 	assert.Equal(t, 0.7, receivedTemperature, "default temperature should be 0.7 when not specified")
 }
+
+func TestAnthropicAdapter_Execute_Messages(t *testing.T) {
+	var reqBody anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "claude-3-haiku-20240307", "content": [{"type": "text", "text": "positive"}]}`))
+	}))
+	defer server.Close()
+
+	adapter := &AnthropicAdapter{id: "anthropic", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	config, _ := json.Marshal(AnthropicConfig{
+		Prompt:       "ignored when messages are set",
+		SystemPrompt: "ignored as well",
+		Messages: []Message{
+			{Role: "system", Content: "Classify the sentiment."},
+			{Role: "user", Content: "I love it"},
+			{Role: "assistant", Content: "positive"},
+			{Role: "system", Content: "Answer with one word."},
+			{Role: "user", Content: "Works great"},
+		},
+	})
+	_, err := adapter.Execute(context.Background(), &Request{Config: config})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Classify the sentiment.\n\nAnswer with one word.", reqBody.System, "system messages go to the system prompt")
+	assert.Equal(t, []anthropicMessage{
+		{Role: "user", Content: "I love it"},
+		{Role: "assistant", Content: "positive"},
+		{Role: "user", Content: "Works great"},
+	}, reqBody.Messages)
+}
+
+func TestAnthropicAdapter_Execute_Images(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "claude-3-5-sonnet-20241022", "content": [{"type": "text", "text": "a cat"}], "usage": {"input_tokens": 900, "output_tokens": 3}}`))
+	}))
+	defer server.Close()
+
+	adapter := &AnthropicAdapter{id: "anthropic", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	resp, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"model": "claude-3-5-sonnet-20241022",
+		"user_prompt": "What is this?",
+		"images": ["data:image/jpeg;base64,/9j/4AAQ", {"url": "https://example.com/cat.png"}]
+	}`)})
+
+	require.NoError(t, err)
+	messages := body["messages"].([]interface{})
+	require.Len(t, messages, 1)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "image", "source": map[string]interface{}{"type": "base64", "media_type": "image/jpeg", "data": "/9j/4AAQ"}},
+		map[string]interface{}{"type": "image", "source": map[string]interface{}{"type": "url", "url": "https://example.com/cat.png"}},
+		map[string]interface{}{"type": "text", "text": "What is this?"},
+	}, messages[0].(map[string]interface{})["content"])
+	assert.Equal(t, "900", resp.Metadata["input_tokens"])
+}
+
+func TestAnthropicAdapter_Execute_ImagesRejectedForTextModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for a model without vision support")
+	}))
+	defer server.Close()
+
+	adapter := &AnthropicAdapter{id: "anthropic", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	_, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"model": "claude-3-5-haiku-20241022",
+		"prompt": "What is this?",
+		"images": ["https://example.com/cat.png"]
+	}`)})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support image input")
+}
+
+func TestAnthropicAdapter_Execute_ResponseSchema(t *testing.T) {
+	var reqBody anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "claude-3-5-sonnet-20241022", "stop_reason": "tool_use", "content": [
+			{"type": "text", "text": "Routing to billing."},
+			{"type": "tool_use", "id": "toolu_01", "name": "route_selection", "input": {"route": "billing"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	adapter := &AnthropicAdapter{id: "anthropic", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	resp, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"model": "claude-3-5-sonnet-20241022",
+		"prompt": "Route this ticket",
+		"response_schema": {"name": "route_selection", "description": "The route", "schema": {"type": "object", "properties": {"route": {"type": "string", "enum": ["billing", "support"]}}}}
+	}`)})
+
+	require.NoError(t, err)
+	assert.True(t, adapter.SupportsStructuredOutput())
+	require.Len(t, reqBody.Tools, 1)
+	assert.Equal(t, "route_selection", reqBody.Tools[0].Name)
+	assert.JSONEq(t, `{"type": "object", "properties": {"route": {"type": "string", "enum": ["billing", "support"]}}}`, string(reqBody.Tools[0].InputSchema))
+	assert.Equal(t, &anthropicToolChoice{Type: "tool", Name: "route_selection"}, reqBody.ToolChoice)
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Output, &output))
+	assert.JSONEq(t, `{"route": "billing"}`, output["content"].(string), "content is the tool input, not the text")
+}
+
+func TestAnthropicAdapter_Execute_ResponseSchemaWithoutToolCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "claude-3-5-sonnet-20241022", "content": [{"type": "text", "text": "billing"}]}`))
+	}))
+	defer server.Close()
+
+	adapter := &AnthropicAdapter{id: "anthropic", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	_, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"prompt": "Route this ticket",
+		"response_schema": {"name": "route_selection", "schema": {"type": "object"}}
+	}`)})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no route_selection tool call")
+}
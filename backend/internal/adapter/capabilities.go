@@ -0,0 +1,12 @@
+package adapter
+
+import "github.com/souta/ai-orchestration/internal/domain"
+
+// defaultMaxTokens returns the max_tokens an adapter sends when the config leaves it unset: the
+// adapter's own default, lowered to the model's maximum output tokens when the model is known
+func defaultMaxTokens(provider, model string, fallback int) int {
+	if caps := domain.GetModelCapabilities(provider, model); caps != nil && caps.MaxOutputTokens > 0 && caps.MaxOutputTokens < fallback {
+		return caps.MaxOutputTokens
+	}
+	return fallback
+}
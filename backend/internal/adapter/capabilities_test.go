@@ -0,0 +1,18 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMaxTokens(t *testing.T) {
+	t.Cleanup(domain.ResetModelCapabilities)
+	require.NoError(t, domain.LoadModelCapabilities([]byte(`[{"provider": "openai", "model": "small-llm", "context_window": 4096, "max_output_tokens": 1024}]`)))
+
+	assert.Equal(t, 1024, defaultMaxTokens("openai", "small-llm", 2048), "lowered to the model maximum")
+	assert.Equal(t, 2048, defaultMaxTokens("openai", "gpt-4o", 2048), "below the model maximum")
+	assert.Equal(t, 2048, defaultMaxTokens("openai", "unknown-llm", 2048), "unknown models keep the adapter default")
+}
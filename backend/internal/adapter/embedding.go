@@ -0,0 +1,264 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// embeddingMaxBatchSize is the maximum number of inputs the OpenAI embeddings API accepts per request
+const embeddingMaxBatchSize = 2048
+
+// EmbeddingAdapter implements the Adapter and BatchAdapter interfaces for the OpenAI embeddings API
+type EmbeddingAdapter struct {
+	id         string
+	name       string
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	endpoints  *EndpointAllowlist // Regional and extra endpoints step and tenant config may select
+}
+
+// EmbeddingConfig holds the configuration for the embedding adapter
+type EmbeddingConfig struct {
+	Model      string `json:"model"`      // text-embedding-3-small, text-embedding-3-large
+	Text       string `json:"text"`       // Text to embed, usually a template such as {{content}}; defaults to the input
+	Dimensions int    `json:"dimensions"` // Optional output dimension (text-embedding-3 models)
+
+	domain.EndpointOverride // base_url or region: routes the request to a regional endpoint
+}
+
+type embeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Model string `json:"model"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *openAIError `json:"error,omitempty"`
+}
+
+// NewEmbeddingAdapter creates a new embedding adapter
+func NewEmbeddingAdapter() *EmbeddingAdapter {
+	return &EmbeddingAdapter{
+		id:   "embedding",
+		name: "OpenAI Embeddings",
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		apiKey:    os.Getenv("OPENAI_API_KEY"),
+		baseURL:   getEnvOrDefault("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		endpoints: endpointAllowlistFromEnv("openai", "OPENAI_ENDPOINT_ALLOWLIST"),
+	}
+}
+
+func (a *EmbeddingAdapter) ID() string   { return a.id }
+func (a *EmbeddingAdapter) Name() string { return a.name }
+
+// IsConfigured reports whether an API key is set
+func (a *EmbeddingAdapter) IsConfigured() bool { return a.apiKey != "" }
+
+// Execute embeds the text of a single request
+func (a *EmbeddingAdapter) Execute(ctx context.Context, req *Request) (*Response, error) {
+	resps, err := a.ExecuteBatch(ctx, []*Request{req})
+	if err != nil {
+		return nil, err
+	}
+	return resps[0], nil
+}
+
+// ExecuteBatch embeds the texts of all requests with one API call per model (and per
+// embeddingMaxBatchSize inputs). Each response carries its own embedding; the usage of a call is
+// split evenly across its requests in the response metadata.
+func (a *EmbeddingAdapter) ExecuteBatch(ctx context.Context, reqs []*Request) ([]*Response, error) {
+	start := time.Now()
+
+	if a.apiKey == "" {
+		return nil, fmt.Errorf("OpenAI API key not configured")
+	}
+
+	// Group requests by endpoint, model and dimensions, keeping request order within each group
+	type batchKey struct {
+		baseURL    string
+		model      string
+		dimensions int
+	}
+	groups := make(map[batchKey][]int)
+	keys := make([]batchKey, 0)
+	texts := make([]string, len(reqs))
+	for i, req := range reqs {
+		config, text, err := parseEmbeddingRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", i, err)
+		}
+		baseURL, err := a.endpoints.Resolve(ctx, "openai", a.baseURL, config.EndpointOverride)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: invalid embedding config: %w", i, err)
+		}
+		texts[i] = text
+		key := batchKey{baseURL: baseURL, model: config.Model, dimensions: config.Dimensions}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	resps := make([]*Response, len(reqs))
+	for _, key := range keys {
+		indexes := groups[key]
+		for from := 0; from < len(indexes); from += embeddingMaxBatchSize {
+			to := from + embeddingMaxBatchSize
+			if to > len(indexes) {
+				to = len(indexes)
+			}
+			chunk := indexes[from:to]
+
+			inputs := make([]string, len(chunk))
+			for i, idx := range chunk {
+				inputs[i] = texts[idx]
+			}
+			apiResp, err := a.embed(ctx, key.baseURL, embeddingRequest{Model: key.model, Input: inputs, Dimensions: key.dimensions})
+			if err != nil {
+				return nil, err
+			}
+			if len(apiResp.Data) != len(chunk) {
+				return nil, fmt.Errorf("OpenAI embeddings API returned %d embeddings, expected %d", len(apiResp.Data), len(chunk))
+			}
+
+			tokensPerRequest := apiResp.Usage.TotalTokens / len(chunk)
+			for _, data := range apiResp.Data {
+				if data.Index < 0 || data.Index >= len(chunk) {
+					return nil, fmt.Errorf("OpenAI embeddings API returned unexpected index %d", data.Index)
+				}
+				output, err := json.Marshal(map[string]interface{}{
+					"embedding": data.Embedding,
+					"model":     apiResp.Model,
+					"dimension": len(data.Embedding),
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal output: %w", err)
+				}
+				resps[chunk[data.Index]] = &Response{
+					Output:     output,
+					DurationMs: int(time.Since(start).Milliseconds()),
+					Metadata: map[string]string{
+						"adapter":       a.id,
+						"model":         apiResp.Model,
+						"prompt_tokens": fmt.Sprintf("%d", tokensPerRequest),
+						"total_tokens":  fmt.Sprintf("%d", tokensPerRequest),
+						"batch_size":    fmt.Sprintf("%d", len(chunk)),
+					},
+				}
+			}
+		}
+	}
+
+	return resps, nil
+}
+
+// parseEmbeddingRequest returns the config of a request and the text to embed
+func parseEmbeddingRequest(req *Request) (EmbeddingConfig, string, error) {
+	var config EmbeddingConfig
+	if req.Config != nil {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return config, "", fmt.Errorf("invalid embedding config: %w", err)
+		}
+	}
+	if config.Model == "" {
+		config.Model = "text-embedding-3-small"
+	}
+
+	// Config templates are expanded by Executor before reaching the adapter
+	text := config.Text
+	if text == "" {
+		// Embed the input itself: a JSON string as-is, anything else as its JSON text
+		if err := json.Unmarshal(req.Input, &text); err != nil {
+			text = string(req.Input)
+		}
+	}
+	if text == "" {
+		return config, "", fmt.Errorf("no text to embed")
+	}
+	return config, text, nil
+}
+
+// embed calls the OpenAI embeddings API at baseURL
+func (a *EmbeddingAdapter) embed(ctx context.Context, baseURL string, apiReq embeddingRequest) (*embeddingResponse, error) {
+	reqBody, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/embeddings", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+a.apiKey)
+
+	resp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call OpenAI embeddings API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(a.name, resp, body)
+	}
+
+	var apiResp embeddingResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return nil, fmt.Errorf("OpenAI API error: %s (type: %s, code: %s)",
+			apiResp.Error.Message, apiResp.Error.Type, apiResp.Error.Code)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI embeddings API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return &apiResp, nil
+}
+
+func (a *EmbeddingAdapter) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"description": "Text to embed, or data for the text template in config",
+		"additionalProperties": true
+	}`)
+}
+
+func (a *EmbeddingAdapter) OutputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"embedding": {"type": "array", "items": {"type": "number"}, "description": "Embedding vector"},
+			"model": {"type": "string", "description": "Model used"},
+			"dimension": {"type": "integer", "description": "Vector dimension"}
+		}
+	}`)
+}
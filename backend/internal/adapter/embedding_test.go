@@ -0,0 +1,134 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEmbeddingTestServer returns embeddings of [len(input)] in reverse order and records each request
+func newEmbeddingTestServer(t *testing.T, requests *[]embeddingRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+
+		var req embeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+
+		var resp embeddingResponse
+		resp.Model = req.Model
+		resp.Usage.TotalTokens = 2 * len(req.Input)
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			}{Index: i, Embedding: []float32{float32(len(req.Input[i]))}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func newTestEmbeddingAdapter(server *httptest.Server) *EmbeddingAdapter {
+	return &EmbeddingAdapter{
+		id:         "embedding",
+		name:       "OpenAI Embeddings",
+		httpClient: server.Client(),
+		apiKey:     "test-api-key",
+		baseURL:    server.URL,
+	}
+}
+
+func TestEmbeddingAdapter_ExecuteBatch_SingleCall(t *testing.T) {
+	var requests []embeddingRequest
+	server := newEmbeddingTestServer(t, &requests)
+	defer server.Close()
+	adp := newTestEmbeddingAdapter(server)
+
+	reqs := []*Request{
+		{Input: json.RawMessage(`"a"`)},
+		{Input: json.RawMessage(`"bbb"`)},
+		{Config: json.RawMessage(`{"text": "cc"}`), Input: json.RawMessage(`{"ignored": true}`)},
+	}
+	resps, err := adp.ExecuteBatch(context.Background(), reqs)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1, "all texts of the same model should be sent in one request")
+	assert.Equal(t, "text-embedding-3-small", requests[0].Model)
+	assert.Equal(t, []string{"a", "bbb", "cc"}, requests[0].Input)
+
+	require.Len(t, resps, 3)
+	for i, want := range []float64{1, 3, 2} {
+		var output map[string]interface{}
+		require.NoError(t, json.Unmarshal(resps[i].Output, &output))
+		assert.Equal(t, []interface{}{want}, output["embedding"], "response %d should match request %d", i, i)
+		assert.Equal(t, "2", resps[i].Metadata["total_tokens"])
+	}
+}
+
+func TestEmbeddingAdapter_ExecuteBatch_GroupsByModel(t *testing.T) {
+	var requests []embeddingRequest
+	server := newEmbeddingTestServer(t, &requests)
+	defer server.Close()
+	adp := newTestEmbeddingAdapter(server)
+
+	reqs := []*Request{
+		{Input: json.RawMessage(`"a"`), Config: json.RawMessage(`{"model": "text-embedding-3-large"}`)},
+		{Input: json.RawMessage(`"bb"`)},
+		{Input: json.RawMessage(`"ccc"`), Config: json.RawMessage(`{"model": "text-embedding-3-large"}`)},
+	}
+	resps, err := adp.ExecuteBatch(context.Background(), reqs)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, []string{"a", "ccc"}, requests[0].Input)
+	assert.Equal(t, []string{"bb"}, requests[1].Input)
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(resps[2].Output, &output))
+	assert.Equal(t, []interface{}{float64(3)}, output["embedding"])
+}
+
+func TestEmbeddingAdapter_Execute(t *testing.T) {
+	var requests []embeddingRequest
+	server := newEmbeddingTestServer(t, &requests)
+	defer server.Close()
+
+	resp, err := newTestEmbeddingAdapter(server).Execute(context.Background(), &Request{Input: json.RawMessage(`"hello"`)})
+	require.NoError(t, err)
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Output, &output))
+	assert.Equal(t, []interface{}{float64(5)}, output["embedding"])
+	assert.Equal(t, float64(1), output["dimension"])
+}
+
+func TestEmbeddingAdapter_EmptyText(t *testing.T) {
+	adp := &EmbeddingAdapter{id: "embedding", apiKey: "test-api-key"}
+	_, err := adp.ExecuteBatch(context.Background(), []*Request{{Input: json.RawMessage(`""`)}})
+	assert.Error(t, err)
+}
+
+func TestRegistry_SetLimit_KeepsBatchCapability(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewEmbeddingAdapter())
+	registry.SetLimit("embedding", ProviderLimit{MaxInFlight: 1})
+
+	adp, ok := registry.Get("embedding")
+	require.True(t, ok)
+	_, isBatch := adp.(BatchAdapter)
+	assert.True(t, isBatch, "a provider limit must not hide the batch capability")
+
+	registry.Register(&countingAdapter{id: "openai"})
+	registry.SetLimit("openai", ProviderLimit{MaxInFlight: 1})
+	adp, ok = registry.Get("openai")
+	require.True(t, ok)
+	_, isBatch = adp.(BatchAdapter)
+	assert.False(t, isBatch)
+}
@@ -0,0 +1,160 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// builtinRegions lists the regional endpoints each provider publishes
+var builtinRegions = map[string]map[string]string{
+	"openai": {
+		"us": "https://us.api.openai.com/v1",
+		"eu": "https://eu.api.openai.com/v1",
+	},
+}
+
+// EndpointAllowlist lists the endpoints a provider's requests may be routed to besides the
+// adapter's default: the provider's regions and the base URLs the deployment adds. Overrides
+// naming any other endpoint are rejected, so step and tenant config cannot point requests (and
+// the API key) at arbitrary hosts.
+type EndpointAllowlist struct {
+	regions map[string]string // region -> base URL
+	urls    map[string]bool   // allowed base URLs, including the regions'
+}
+
+// NewEndpointAllowlist creates the allowlist of a provider from its built-in regions and the
+// given entries. An entry is a base URL, or region=URL to add or replace a region.
+func NewEndpointAllowlist(provider string, entries ...string) (*EndpointAllowlist, error) {
+	l := &EndpointAllowlist{regions: make(map[string]string), urls: make(map[string]bool)}
+	for region, baseURL := range builtinRegions[provider] {
+		l.regions[region] = baseURL
+		l.urls[baseURL] = true
+	}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		region, rawURL, hasRegion := strings.Cut(entry, "=")
+		if !hasRegion {
+			rawURL = entry
+		}
+		baseURL, err := normalizeBaseURL(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s endpoint %q: %w", provider, entry, err)
+		}
+		if hasRegion {
+			region = strings.ToLower(strings.TrimSpace(region))
+			if region == "" {
+				return nil, fmt.Errorf("invalid %s endpoint %q: region is empty", provider, entry)
+			}
+			l.regions[region] = baseURL
+		}
+		l.urls[baseURL] = true
+	}
+	return l, nil
+}
+
+// endpointAllowlistFromEnv creates the allowlist of a provider from the comma-separated entries
+// of an environment variable. An invalid variable is logged and leaves the built-in regions only.
+func endpointAllowlistFromEnv(provider, key string) *EndpointAllowlist {
+	l, err := NewEndpointAllowlist(provider, strings.Split(os.Getenv(key), ",")...)
+	if err != nil {
+		slog.Warn("Invalid endpoint allowlist, using the built-in regions only", "env", key, "error", err)
+		l, _ = NewEndpointAllowlist(provider)
+	}
+	return l
+}
+
+// Resolve returns the base URL of a request to the provider: the override from the step config,
+// else the tenant's override carried by ctx, else defaultURL. An override outside the allowlist
+// is an error rather than a fallback to the default, so a residency requirement is never
+// silently dropped. A nil allowlist accepts the default endpoint only.
+func (l *EndpointAllowlist) Resolve(ctx context.Context, provider, defaultURL string, override domain.EndpointOverride) (string, error) {
+	if override.IsZero() {
+		override = EndpointOverridesFromContext(ctx)[provider]
+	}
+	if err := override.Validate(); err != nil {
+		return "", err
+	}
+
+	if override.Region != "" {
+		region := strings.ToLower(strings.TrimSpace(override.Region))
+		if l != nil {
+			if baseURL, ok := l.regions[region]; ok {
+				return baseURL, nil
+			}
+		}
+		return "", fmt.Errorf("unknown %s region %q (available: %s)", provider, override.Region, l.regionNames())
+	}
+
+	if override.BaseURL != "" {
+		baseURL, err := normalizeBaseURL(override.BaseURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid base_url %q: %w", override.BaseURL, err)
+		}
+		if def, err := normalizeBaseURL(defaultURL); err == nil && baseURL == def {
+			return defaultURL, nil
+		}
+		if l == nil || !l.urls[baseURL] {
+			return "", fmt.Errorf("base_url %q is not an allowed %s endpoint", override.BaseURL, provider)
+		}
+		return baseURL, nil
+	}
+
+	return defaultURL, nil
+}
+
+// regionNames returns the sorted region names for error messages
+func (l *EndpointAllowlist) regionNames() string {
+	if l == nil || len(l.regions) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(l.regions))
+	for region := range l.regions {
+		names = append(names, region)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// normalizeBaseURL checks that a base URL is an absolute http(s) URL without credentials, query
+// or fragment, and returns it with a lowercase scheme and host and no trailing slash
+func normalizeBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "https" && scheme != "http" {
+		return "", fmt.Errorf("scheme must be https or http")
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("host is required")
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("credentials, query and fragment are not allowed")
+	}
+	return scheme + "://" + strings.ToLower(u.Host) + strings.TrimRight(u.EscapedPath(), "/"), nil
+}
+
+type endpointOverridesKey struct{}
+
+// WithEndpointOverrides returns a context carrying a tenant's endpoint overrides by provider
+// ID, used for requests whose config sets no override
+func WithEndpointOverrides(ctx context.Context, overrides map[string]domain.EndpointOverride) context.Context {
+	return context.WithValue(ctx, endpointOverridesKey{}, overrides)
+}
+
+// EndpointOverridesFromContext returns the endpoint overrides carried by ctx, or nil
+func EndpointOverridesFromContext(ctx context.Context) map[string]domain.EndpointOverride {
+	overrides, _ := ctx.Value(endpointOverridesKey{}).(map[string]domain.EndpointOverride)
+	return overrides
+}
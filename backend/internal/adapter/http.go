@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/souta/ai-orchestration/pkg/netguard"
 )
 
 // HTTPAdapter implements the Adapter interface for HTTP requests
@@ -16,6 +18,8 @@ type HTTPAdapter struct {
 	id         string
 	name       string
 	httpClient *http.Client
+	cache      ResponseCache
+	now        func() time.Time
 }
 
 // HTTPConfig holds the configuration for HTTP adapter
@@ -28,6 +32,7 @@ type HTTPConfig struct {
 	QueryParams map[string]string `json:"query_params"` // Query parameters
 	TimeoutSec  int               `json:"timeout_sec"`  // Request timeout in seconds
 	FollowRedirects bool          `json:"follow_redirects"` // Follow HTTP redirects
+	Cache           bool          `json:"cache"`            // Cache GET responses per Cache-Control/ETag (requires a response cache)
 }
 
 // HTTPOutput represents the output of an HTTP request
@@ -38,6 +43,7 @@ type HTTPOutput struct {
 	Body       interface{}       `json:"body"`
 	BodyRaw    string            `json:"body_raw"`
 	DurationMs int               `json:"duration_ms"`
+	Cached     bool              `json:"cached,omitempty"` // Served from the response cache
 }
 
 // NewHTTPAdapter creates a new HTTP adapter
@@ -48,9 +54,18 @@ func NewHTTPAdapter() *HTTPAdapter {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		now: time.Now,
 	}
 }
 
+// WithResponseCache enables response caching for steps that set `cache: true`. Only GET and
+// HEAD responses are cached, for as long as their Cache-Control or Expires headers allow, and
+// stale responses with an ETag or Last-Modified are revalidated with a conditional request.
+func (a *HTTPAdapter) WithResponseCache(cache ResponseCache) *HTTPAdapter {
+	a.cache = cache
+	return a
+}
+
 func (a *HTTPAdapter) ID() string   { return a.id }
 func (a *HTTPAdapter) Name() string { return a.name }
 
@@ -139,6 +154,35 @@ func (a *HTTPAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 		}
 	}
 
+	// Block internal addresses when the caller carries a guard (SSRF protection)
+	guard := netguard.FromContext(ctx)
+	if guard != nil {
+		transport := guard.Transport()
+		defer transport.CloseIdleConnections()
+		guarded := *client
+		guarded.Transport = transport
+		client = &guarded
+	}
+
+	// Serve fresh responses from the cache, and revalidate stale ones
+	var cacheKey string
+	var cached *cachedResponse
+	useCache := config.Cache && a.cache != nil && isCacheableMethod(config.Method)
+	if useCache {
+		// A hit never dials, so the guard is applied to the target before the lookup
+		if err := guard.CheckTarget(ctx, httpReq.URL.Hostname()); err != nil {
+			return nil, fmt.Errorf("HTTP request failed: %w", err)
+		}
+		cacheKey = responseCacheKey(TenantIDFromContext(ctx), httpReq)
+		cached = a.loadCachedResponse(ctx, cacheKey)
+		if cached != nil {
+			if a.now().Before(cached.FreshUntil) {
+				return a.buildResponse(config.Method, cached.StatusCode, cached.Status, cached.Headers, cached.Body, start, "hit")
+			}
+			cached.setConditionalHeaders(httpReq)
+		}
+	}
+
 	// Execute request
 	resp, err := client.Do(httpReq)
 	if err != nil {
@@ -152,16 +196,35 @@ func (a *HTTPAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if useCache {
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			a.refreshCachedResponse(ctx, cacheKey, cached, resp.Header)
+			return a.buildResponse(config.Method, cached.StatusCode, cached.Status, cached.Headers, cached.Body, start, "revalidated")
+		}
+		a.storeCachedResponse(ctx, cacheKey, resp, respBody)
+	}
+
 	// Parse response headers
 	respHeaders := make(map[string]string)
 	for key := range resp.Header {
 		respHeaders[key] = resp.Header.Get(key)
 	}
 
+	cacheStatus := ""
+	if useCache {
+		cacheStatus = "miss"
+	}
+	return a.buildResponse(config.Method, resp.StatusCode, resp.Status, respHeaders, respBody, start, cacheStatus)
+}
+
+// buildResponse builds the adapter response for an HTTP response, returning an error along with
+// it for 4xx/5xx status codes. cacheStatus (hit, revalidated or miss) is reported in the metadata
+// when the request used the response cache.
+func (a *HTTPAdapter) buildResponse(method string, statusCode int, status string, headers map[string]string, body []byte, start time.Time, cacheStatus string) (*Response, error) {
 	// Try to parse body as JSON
 	var parsedBody interface{}
-	if len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, &parsedBody); err != nil {
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &parsedBody); err != nil {
 			// Not JSON, use raw string
 			parsedBody = nil
 		}
@@ -169,12 +232,13 @@ func (a *HTTPAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 
 	// Build output
 	output := HTTPOutput{
-		StatusCode: resp.StatusCode,
-		Status:     resp.Status,
-		Headers:    respHeaders,
+		StatusCode: statusCode,
+		Status:     status,
+		Headers:    headers,
 		Body:       parsedBody,
-		BodyRaw:    string(respBody),
+		BodyRaw:    string(body),
 		DurationMs: int(time.Since(start).Milliseconds()),
+		Cached:     cacheStatus == "hit" || cacheStatus == "revalidated",
 	}
 
 	outputJSON, err := json.Marshal(output)
@@ -185,17 +249,20 @@ func (a *HTTPAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 	// Check for error status codes
 	metadata := map[string]string{
 		"adapter":     a.id,
-		"status_code": fmt.Sprintf("%d", resp.StatusCode),
-		"method":      config.Method,
+		"status_code": fmt.Sprintf("%d", statusCode),
+		"method":      method,
+	}
+	if cacheStatus != "" {
+		metadata["cache"] = cacheStatus
 	}
 
 	// Return error for 4xx/5xx status codes
-	if resp.StatusCode >= 400 {
+	if statusCode >= 400 {
 		return &Response{
 			Output:     outputJSON,
 			DurationMs: int(time.Since(start).Milliseconds()),
 			Metadata:   metadata,
-		}, fmt.Errorf("HTTP request returned status %d: %s", resp.StatusCode, string(respBody))
+		}, fmt.Errorf("HTTP request returned status %d: %s", statusCode, string(body))
 	}
 
 	return &Response{
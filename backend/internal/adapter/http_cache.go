@@ -0,0 +1,239 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRevalidateTTL is how long a response with an ETag or Last-Modified validator is kept
+// after it becomes stale, so that later requests revalidate it with a conditional request
+// instead of downloading it again
+const DefaultRevalidateTTL = time.Hour
+
+// ResponseCache stores HTTP responses cached by the http adapter
+type ResponseCache interface {
+	// Get returns the stored value, or false when there is none
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value until ttl elapses
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// RedisResponseCache is a ResponseCache backed by Redis, shared by all workers
+type RedisResponseCache struct {
+	client *redis.Client
+}
+
+// NewRedisResponseCache creates a Redis-backed response cache
+func NewRedisResponseCache(client *redis.Client) *RedisResponseCache {
+	return &RedisResponseCache{client: client}
+}
+
+const responseCacheKeyPrefix = "http_cache:"
+
+// Get implements ResponseCache
+func (c *RedisResponseCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, responseCacheKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements ResponseCache
+func (c *RedisResponseCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, responseCacheKeyPrefix+key, value, ttl).Err()
+}
+
+type tenantIDKey struct{}
+
+// WithTenantID returns a context carrying the tenant a request is made for, which scopes the
+// responses the http adapter caches
+func WithTenantID(ctx context.Context, tenantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// TenantIDFromContext returns the tenant carried by ctx, or uuid.Nil
+func TenantIDFromContext(ctx context.Context) uuid.UUID {
+	tenantID, _ := ctx.Value(tenantIDKey{}).(uuid.UUID)
+	return tenantID
+}
+
+// cachedResponse is a response stored in the ResponseCache
+type cachedResponse struct {
+	StatusCode   int               `json:"status_code"`
+	Status       string            `json:"status"`
+	Headers      map[string]string `json:"headers"`
+	Body         []byte            `json:"body"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	FreshUntil   time.Time         `json:"fresh_until"`
+}
+
+// isCacheableMethod reports whether responses to method may be cached: only idempotent reads
+func isCacheableMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// responseCacheKey identifies a request by tenant, method, URL and headers, so that neither
+// another tenant nor requests made with different credentials share a cached response
+func responseCacheKey(tenantID uuid.UUID, req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(tenantID.String() + "\n"))
+	h.Write([]byte(req.Method + " " + req.URL.String() + "\n"))
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.Write([]byte(name + ": " + strings.Join(req.Header.Values(name), ", ") + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// freshnessLifetime returns how long a response stays fresh according to its Cache-Control
+// (max-age, s-maxage, no-cache) or Expires header, and whether it may be stored at all. The
+// cache is shared by every worker, so private responses are not stored.
+func freshnessLifetime(header http.Header, now time.Time) (time.Duration, bool) {
+	var maxAge time.Duration
+	hasMaxAge := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		name = strings.ToLower(name)
+		switch name {
+		case "no-store", "private":
+			return 0, false
+		case "no-cache":
+			return 0, true
+		case "max-age", "s-maxage":
+			// s-maxage applies to shared caches such as this one and takes precedence
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && (!hasMaxAge || name == "s-maxage") {
+				maxAge = time.Duration(seconds) * time.Second
+				hasMaxAge = true
+			}
+		}
+	}
+	if hasMaxAge {
+		return maxAge, true
+	}
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		if lifetime := expires.Sub(now); lifetime > 0 {
+			return lifetime, true
+		}
+	}
+	return 0, true
+}
+
+// storeTTL returns how long to keep the response in the cache: while it is fresh, plus
+// DefaultRevalidateTTL when it has a validator to revalidate it with. Zero means not storing it.
+func (c *cachedResponse) storeTTL(now time.Time) time.Duration {
+	ttl := c.FreshUntil.Sub(now)
+	if ttl < 0 {
+		ttl = 0
+	}
+	if c.ETag != "" || c.LastModified != "" {
+		ttl += DefaultRevalidateTTL
+	}
+	return ttl
+}
+
+// setConditionalHeaders adds the validators of a stale cached response to the request
+func (c *cachedResponse) setConditionalHeaders(req *http.Request) {
+	if c.ETag != "" {
+		req.Header.Set("If-None-Match", c.ETag)
+	}
+	if c.LastModified != "" {
+		req.Header.Set("If-Modified-Since", c.LastModified)
+	}
+}
+
+// loadCachedResponse returns the cached response for key, or nil. Cache errors are logged and
+// treated as a miss so that the request still goes out.
+func (a *HTTPAdapter) loadCachedResponse(ctx context.Context, key string) *cachedResponse {
+	data, ok, err := a.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("failed to read HTTP response cache", "adapter", a.id, "error", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		slog.Warn("invalid HTTP response cache entry", "adapter", a.id, "error", err)
+		return nil
+	}
+	return &cached
+}
+
+// storeCachedResponse caches a successful response when its headers allow it
+func (a *HTTPAdapter) storeCachedResponse(ctx context.Context, key string, resp *http.Response, body []byte) {
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	now := a.now()
+	lifetime, storable := freshnessLifetime(resp.Header, now)
+	if !storable {
+		return
+	}
+	headers := make(map[string]string, len(resp.Header))
+	for name := range resp.Header {
+		headers[name] = resp.Header.Get(name)
+	}
+	a.saveCachedResponse(ctx, key, &cachedResponse{
+		StatusCode:   resp.StatusCode,
+		Status:       resp.Status,
+		Headers:      headers,
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FreshUntil:   now.Add(lifetime),
+	}, now)
+}
+
+// refreshCachedResponse extends a cached response confirmed by a 304 Not Modified, using the
+// freshness and validators sent with the 304
+func (a *HTTPAdapter) refreshCachedResponse(ctx context.Context, key string, cached *cachedResponse, header http.Header) {
+	now := a.now()
+	lifetime, storable := freshnessLifetime(header, now)
+	if !storable {
+		return
+	}
+	if etag := header.Get("ETag"); etag != "" {
+		cached.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		cached.LastModified = lastModified
+	}
+	cached.FreshUntil = now.Add(lifetime)
+	a.saveCachedResponse(ctx, key, cached, now)
+}
+
+func (a *HTTPAdapter) saveCachedResponse(ctx context.Context, key string, cached *cachedResponse, now time.Time) {
+	ttl := cached.storeTTL(now)
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := a.cache.Set(ctx, key, data, ttl); err != nil {
+		slog.Warn("failed to write HTTP response cache", "adapter", a.id, "error", err)
+	}
+}
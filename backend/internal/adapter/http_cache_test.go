@@ -0,0 +1,256 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/pkg/netguard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryResponseCache is an in-memory ResponseCache whose entries expire on a fake clock
+type memoryResponseCache struct {
+	mu      sync.Mutex
+	now     *time.Time
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func newMemoryResponseCache(now *time.Time) *memoryResponseCache {
+	return &memoryResponseCache{now: now, entries: make(map[string]memoryCacheEntry)}
+}
+
+func (c *memoryResponseCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !c.now.Before(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *memoryResponseCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = memoryCacheEntry{value: value, expiresAt: c.now.Add(ttl)}
+	return nil
+}
+
+// versionedServer serves {"version": version} with the given ETag and Cache-Control, answering
+// 304 when If-None-Match matches, and records the conditional headers it receives
+type versionedServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	version      string
+	cacheControl string
+	calls        int
+	ifNoneMatch  []string
+}
+
+func newVersionedServer(cacheControl string) *versionedServer {
+	s := &versionedServer{version: "v1", cacheControl: cacheControl}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.calls++
+		s.ifNoneMatch = append(s.ifNoneMatch, r.Header.Get("If-None-Match"))
+		etag := `"` + s.version + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", s.cacheControl)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"version": s.version})
+	}))
+	return s
+}
+
+func newCachingAdapter(now *time.Time) *HTTPAdapter {
+	adapter := NewHTTPAdapter().WithResponseCache(newMemoryResponseCache(now))
+	adapter.now = func() time.Time { return *now }
+	return adapter
+}
+
+func executeCachedGet(t *testing.T, adapter *HTTPAdapter, config HTTPConfig) (HTTPOutput, *Response) {
+	t.Helper()
+	return executeCachedGetWithContext(t, context.Background(), adapter, config)
+}
+
+func executeCachedGetWithContext(t *testing.T, ctx context.Context, adapter *HTTPAdapter, config HTTPConfig) (HTTPOutput, *Response) {
+	t.Helper()
+	configJSON, _ := json.Marshal(config)
+	resp, err := adapter.Execute(ctx, &Request{Config: configJSON})
+	require.NoError(t, err)
+	var output HTTPOutput
+	require.NoError(t, json.Unmarshal(resp.Output, &output))
+	return output, resp
+}
+
+func TestHTTPAdapter_Cache_RevalidatesWithETag(t *testing.T) {
+	server := newVersionedServer("max-age=60")
+	defer server.Close()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	adapter := newCachingAdapter(&now)
+	config := HTTPConfig{URL: server.URL, Method: "GET", Cache: true}
+
+	output, resp := executeCachedGet(t, adapter, config)
+	assert.Equal(t, "miss", resp.Metadata["cache"])
+	assert.False(t, output.Cached)
+
+	// Fresh: served from the cache without a request
+	output, resp = executeCachedGet(t, adapter, config)
+	assert.Equal(t, "hit", resp.Metadata["cache"])
+	assert.True(t, output.Cached)
+	assert.Equal(t, 1, server.calls)
+
+	// Stale: revalidated with If-None-Match, and the 304 returns the stored body
+	now = now.Add(61 * time.Second)
+	output, resp = executeCachedGet(t, adapter, config)
+	assert.Equal(t, "revalidated", resp.Metadata["cache"])
+	assert.Equal(t, 2, server.calls)
+	assert.Equal(t, `"v1"`, server.ifNoneMatch[1])
+	assert.Equal(t, 200, output.StatusCode)
+	assert.Equal(t, map[string]interface{}{"version": "v1"}, output.Body)
+
+	// The 304 made the entry fresh again
+	_, resp = executeCachedGet(t, adapter, config)
+	assert.Equal(t, "hit", resp.Metadata["cache"])
+	assert.Equal(t, 2, server.calls)
+}
+
+func TestHTTPAdapter_Cache_RefetchesWhenExpired(t *testing.T) {
+	server := newVersionedServer("max-age=60")
+	defer server.Close()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	adapter := newCachingAdapter(&now)
+	config := HTTPConfig{URL: server.URL, Method: "GET", Cache: true}
+
+	executeCachedGet(t, adapter, config)
+
+	// Past the freshness lifetime and the revalidation window, the entry is gone
+	server.version = "v2"
+	now = now.Add(60*time.Second + DefaultRevalidateTTL)
+	output, resp := executeCachedGet(t, adapter, config)
+	assert.Equal(t, "miss", resp.Metadata["cache"])
+	assert.Equal(t, 2, server.calls)
+	assert.Empty(t, server.ifNoneMatch[1], "an expired entry is refetched unconditionally")
+	assert.Equal(t, map[string]interface{}{"version": "v2"}, output.Body)
+}
+
+func TestHTTPAdapter_Cache_OnlyWhenAllowed(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		cacheControl string
+		config       func(url string) HTTPConfig
+	}{
+		{"opt-in required", "max-age=60", func(url string) HTTPConfig { return HTTPConfig{URL: url, Method: "GET"} }},
+		{"non-idempotent method", "max-age=60", func(url string) HTTPConfig { return HTTPConfig{URL: url, Method: "POST", Cache: true} }},
+		{"no-store", "no-store", func(url string) HTTPConfig { return HTTPConfig{URL: url, Method: "GET", Cache: true} }},
+		{"private", "private, max-age=60", func(url string) HTTPConfig { return HTTPConfig{URL: url, Method: "GET", Cache: true} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newVersionedServer(tt.cacheControl)
+			defer server.Close()
+			adapter := newCachingAdapter(&now)
+
+			executeCachedGet(t, adapter, tt.config(server.URL))
+			executeCachedGet(t, adapter, tt.config(server.URL))
+
+			assert.Equal(t, 2, server.calls)
+			assert.Empty(t, server.ifNoneMatch[1])
+		})
+	}
+}
+
+func TestHTTPAdapter_Cache_ScopedByTenant(t *testing.T) {
+	server := newVersionedServer("max-age=60")
+	defer server.Close()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	adapter := newCachingAdapter(&now)
+	config := HTTPConfig{URL: server.URL, Method: "GET", Cache: true}
+	tenantA := WithTenantID(context.Background(), uuid.New())
+	tenantB := WithTenantID(context.Background(), uuid.New())
+
+	executeCachedGetWithContext(t, tenantA, adapter, config)
+	_, resp := executeCachedGetWithContext(t, tenantB, adapter, config)
+	assert.Equal(t, "miss", resp.Metadata["cache"], "another tenant's cached response is not served")
+	assert.Empty(t, server.ifNoneMatch[1])
+
+	_, resp = executeCachedGetWithContext(t, tenantA, adapter, config)
+	assert.Equal(t, "hit", resp.Metadata["cache"])
+	assert.Equal(t, 2, server.calls)
+}
+
+func TestHTTPAdapter_Cache_GuardAppliesToHits(t *testing.T) {
+	server := newVersionedServer("max-age=60")
+	defer server.Close()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	adapter := newCachingAdapter(&now)
+	config := HTTPConfig{URL: server.URL, Method: "GET", Cache: true}
+	configJSON, _ := json.Marshal(config)
+	loopback, err := netguard.ParsePrefixes([]string{"127.0.0.0/8", "::1"})
+	require.NoError(t, err)
+	tenant := WithTenantID(context.Background(), uuid.New())
+
+	// Warm the cache under a guard that allows the loopback server
+	executeCachedGetWithContext(t, netguard.WithContext(tenant, netguard.New(loopback...)), adapter, config)
+
+	tests := []struct {
+		name    string
+		guard   *netguard.Guard
+		wantErr error
+	}{
+		{"internal address", netguard.New(), netguard.ErrBlocked},
+		{"denied domain", netguard.New(loopback...).WithDomains(nil, []string{"127.0.0.1"}), netguard.ErrDomainNotPermitted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := adapter.Execute(netguard.WithContext(tenant, tt.guard), &Request{Config: configJSON})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	assert.Equal(t, 1, server.calls)
+}
+
+func TestFreshnessLifetime(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		header       http.Header
+		wantLifetime time.Duration
+		wantStore    bool
+	}{
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=300"}}, 300 * time.Second, true},
+		{"s-maxage wins", http.Header{"Cache-Control": {"s-maxage=10, max-age=300"}}, 10 * time.Second, true},
+		{"no-cache", http.Header{"Cache-Control": {"no-cache"}}, 0, true},
+		{"no-store", http.Header{"Cache-Control": {"max-age=300, no-store"}}, 0, false},
+		{"private", http.Header{"Cache-Control": {"private, max-age=300"}}, 0, false},
+		{"expires", http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, time.Hour, true},
+		{"no headers", http.Header{}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lifetime, store := freshnessLifetime(tt.header, now)
+			assert.Equal(t, tt.wantLifetime, lifetime)
+			assert.Equal(t, tt.wantStore, store)
+		})
+	}
+}
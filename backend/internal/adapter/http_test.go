@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/souta/ai-orchestration/pkg/netguard"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -415,3 +417,55 @@ func TestHTTPAdapter_Execute_DELETE(t *testing.T) {
 	require.NotNil(t, resp)
 	assert.Equal(t, "204", resp.Metadata["status_code"])
 }
+
+func TestHTTPAdapter_Execute_BlocksInternalAddresses(t *testing.T) {
+	adapter := NewHTTPAdapter()
+	ctx := netguard.WithContext(context.Background(), netguard.New())
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data/", "http://10.0.0.1/"} {
+		configJSON, _ := json.Marshal(HTTPConfig{URL: url, Method: "GET"})
+
+		_, err := adapter.Execute(ctx, &Request{Config: configJSON})
+
+		require.Error(t, err, url)
+		assert.ErrorIs(t, err, netguard.ErrBlocked, url)
+		assert.Contains(t, err.Error(), "blocked: internal address", url)
+	}
+}
+
+func TestHTTPAdapter_Execute_EgressDomainRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}))
+	defer server.Close()
+	localhostURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	loopback, err := netguard.ParsePrefixes([]string{"127.0.0.0/8", "::1"})
+	require.NoError(t, err)
+	adapter := NewHTTPAdapter()
+
+	execute := func(guard *netguard.Guard, url string) (*Response, error) {
+		configJSON, _ := json.Marshal(HTTPConfig{URL: url, Method: "GET"})
+		return adapter.Execute(netguard.WithContext(context.Background(), guard), &Request{Config: configJSON})
+	}
+
+	t.Run("allowlisted domain succeeds", func(t *testing.T) {
+		resp, err := execute(netguard.New(loopback...).WithDomains([]string{"localhost"}, nil), localhostURL)
+		require.NoError(t, err)
+		var output HTTPOutput
+		require.NoError(t, json.Unmarshal(resp.Output, &output))
+		assert.Equal(t, 200, output.StatusCode)
+	})
+
+	t.Run("domain outside the allowlist is blocked", func(t *testing.T) {
+		_, err := execute(netguard.New(loopback...).WithDomains([]string{"example.com"}, nil), localhostURL)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, netguard.ErrDomainNotPermitted)
+		assert.Contains(t, err.Error(), "domain not permitted: localhost")
+	})
+
+	t.Run("denied domain is blocked", func(t *testing.T) {
+		_, err := execute(netguard.New(loopback...).WithDomains(nil, []string{"localhost"}), localhostURL)
+		assert.ErrorIs(t, err, netguard.ErrDomainNotPermitted)
+	})
+}
@@ -23,6 +23,49 @@ type Adapter interface {
 	OutputSchema() json.RawMessage
 }
 
+// BatchAdapter is implemented by adapters whose provider can process many requests in one call
+// (e.g. embedding APIs that accept an array of inputs). Map steps use ExecuteBatch instead of one
+// Execute per item when the adapter implements it.
+type BatchAdapter interface {
+	Adapter
+
+	// ExecuteBatch runs all requests and returns one response per request, in request order.
+	// An error fails the whole batch.
+	ExecuteBatch(ctx context.Context, reqs []*Request) ([]*Response, error)
+}
+
+// StructuredOutputAdapter is implemented by LLM adapters that accept a response_schema config
+// (see ResponseSchema) and make the provider answer with JSON matching it instead of free text.
+type StructuredOutputAdapter interface {
+	Adapter
+
+	// SupportsStructuredOutput reports whether response_schema is honored. Wrappers such as the
+	// registry's limited adapters implement it by delegating to the adapter they wrap.
+	SupportsStructuredOutput() bool
+}
+
+// SupportsStructuredOutput reports whether the adapter honors the response_schema config
+func SupportsStructuredOutput(adapter Adapter) bool {
+	structured, ok := adapter.(StructuredOutputAdapter)
+	return ok && structured.SupportsStructuredOutput()
+}
+
+// ConfigurableAdapter is implemented by adapters that need deployment configuration, such as an
+// API key, before they can execute
+type ConfigurableAdapter interface {
+	Adapter
+
+	// IsConfigured reports whether the adapter has the configuration it needs
+	IsConfigured() bool
+}
+
+// IsConfigured reports whether the adapter can execute. Adapters that need no configuration are
+// always configured.
+func IsConfigured(adapter Adapter) bool {
+	configurable, ok := adapter.(ConfigurableAdapter)
+	return !ok || configurable.IsConfigured()
+}
+
 // Request represents an adapter execution request
 type Request struct {
 	Input         json.RawMessage   `json:"input"`
@@ -39,15 +82,33 @@ type Response struct {
 	Metadata  map[string]string `json:"metadata"`
 }
 
-// Registry holds all registered adapters
+// Registry holds all registered adapters.
+// Adapters and limits are configured at startup; the registry is read-only afterwards.
 type Registry struct {
 	adapters map[string]Adapter
+	limiters map[string]*providerLimiter
 }
 
 // NewRegistry creates a new adapter registry
 func NewRegistry() *Registry {
 	return &Registry{
 		adapters: make(map[string]Adapter),
+		limiters: make(map[string]*providerLimiter),
+	}
+}
+
+// SetLimit enforces a concurrency/rate limit on every Execute of the adapter with the given ID.
+// The limit is shared by all callers, so bursts from any workflow step are smoothed together.
+func (r *Registry) SetLimit(id string, limit ProviderLimit) {
+	r.limiters[id] = newProviderLimiter(limit)
+}
+
+// SetLimitsFromEnv applies ProviderLimitFromEnv to every registered adapter
+func (r *Registry) SetLimitsFromEnv() {
+	for id := range r.adapters {
+		if limit, ok := ProviderLimitFromEnv(id); ok {
+			r.SetLimit(id, limit)
+		}
 	}
 }
 
@@ -56,17 +117,32 @@ func (r *Registry) Register(adapter Adapter) {
 	r.adapters[adapter.ID()] = adapter
 }
 
-// Get retrieves an adapter by ID
+// Get retrieves an adapter by ID, wrapped with its provider limit if one is set
 func (r *Registry) Get(id string) (Adapter, bool) {
 	adapter, ok := r.adapters[id]
-	return adapter, ok
+	if !ok {
+		return nil, false
+	}
+	return r.limited(adapter), true
 }
 
 // List returns all registered adapters
 func (r *Registry) List() []Adapter {
 	adapters := make([]Adapter, 0, len(r.adapters))
 	for _, adapter := range r.adapters {
-		adapters = append(adapters, adapter)
+		adapters = append(adapters, r.limited(adapter))
 	}
 	return adapters
 }
+
+func (r *Registry) limited(adapter Adapter) Adapter {
+	limiter, ok := r.limiters[adapter.ID()]
+	if !ok {
+		return adapter
+	}
+	limited := &limitedAdapter{Adapter: adapter, limiter: limiter}
+	if batch, ok := adapter.(BatchAdapter); ok {
+		return &limitedBatchAdapter{limitedAdapter: limited, batch: batch}
+	}
+	return limited
+}
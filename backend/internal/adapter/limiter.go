@@ -0,0 +1,278 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderLimit bounds how hard a provider is called, across every workflow step that uses it
+type ProviderLimit struct {
+	MaxInFlight       int // Concurrent Execute calls (0 = unlimited)
+	RequestsPerMinute int // Token bucket refill rate (0 = unlimited)
+	Burst             int // Token bucket capacity (0 = 1, so bursts are spread evenly over the minute)
+	MaxRetries        int // Retries after a provider rate-limit (429) response
+}
+
+// Environment variable suffixes for provider limits, prefixed with the upper-cased adapter ID
+// (e.g. OPENAI_MAX_IN_FLIGHT, ANTHROPIC_REQUESTS_PER_MINUTE)
+const (
+	EnvSuffixMaxInFlight       = "_MAX_IN_FLIGHT"
+	EnvSuffixRequestsPerMinute = "_REQUESTS_PER_MINUTE"
+	EnvSuffixBurst             = "_RATE_BURST"
+	EnvSuffixMaxRetries        = "_MAX_RETRIES"
+)
+
+// DefaultRateLimitRetries is the number of 429 retries when <ID>_MAX_RETRIES is not set
+const DefaultRateLimitRetries = 3
+
+// maxRateLimitBackoff caps the wait between 429 retries when the provider sends no Retry-After
+const maxRateLimitBackoff = 30 * time.Second
+
+// ProviderLimitFromEnv reads the limit for an adapter ID from the environment.
+// The second return value is false when neither a concurrency nor a rate limit is configured.
+// Invalid values are logged and ignored.
+func ProviderLimitFromEnv(id string) (ProviderLimit, bool) {
+	prefix := strings.ToUpper(id)
+	limit := ProviderLimit{
+		MaxInFlight:       envLimit(prefix+EnvSuffixMaxInFlight, 0),
+		RequestsPerMinute: envLimit(prefix+EnvSuffixRequestsPerMinute, 0),
+		Burst:             envLimit(prefix+EnvSuffixBurst, 0),
+		MaxRetries:        envLimit(prefix+EnvSuffixMaxRetries, DefaultRateLimitRetries),
+	}
+	return limit, limit.MaxInFlight > 0 || limit.RequestsPerMinute > 0
+}
+
+func envLimit(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		slog.Warn("ignoring invalid provider limit setting", "env", key, "value", value)
+		return defaultValue
+	}
+	return n
+}
+
+// RateLimitError is returned by adapters when the provider responds with 429 Too Many Requests
+type RateLimitError struct {
+	Provider   string
+	RetryAfter time.Duration // Zero when the provider did not send Retry-After
+	Body       string
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s API rate limit exceeded (429): %s", e.Provider, e.Body)
+}
+
+// newRateLimitError builds a RateLimitError from a 429 response
+func newRateLimitError(provider string, resp *http.Response, body []byte) *RateLimitError {
+	return &RateLimitError{
+		Provider:   provider,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Body:       string(body),
+	}
+}
+
+// parseRetryAfter accepts delay-seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// providerLimiter combines an in-flight semaphore with a token bucket.
+// A 429 from the provider pauses every caller until the retry time has passed.
+type providerLimiter struct {
+	limit ProviderLimit
+	slots chan struct{} // nil when MaxInFlight is 0
+
+	mu           sync.Mutex
+	tokens       float64 // May go negative: each waiting caller reserves a token
+	lastRefill   time.Time
+	blockedUntil time.Time
+}
+
+func newProviderLimiter(limit ProviderLimit) *providerLimiter {
+	l := &providerLimiter{limit: limit, lastRefill: time.Now()}
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	l.tokens = float64(l.burst())
+	return l
+}
+
+func (l *providerLimiter) burst() int {
+	if l.limit.Burst > 0 {
+		return l.limit.Burst
+	}
+	return 1
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (l *providerLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	if l.limit.RequestsPerMinute > 0 {
+		perSecond := float64(l.limit.RequestsPerMinute) / 60
+		l.tokens += now.Sub(l.lastRefill).Seconds() * perSecond
+		if capacity := float64(l.burst()); l.tokens > capacity {
+			l.tokens = capacity
+		}
+		l.lastRefill = now
+		l.tokens--
+		if l.tokens < 0 {
+			wait = time.Duration(-l.tokens / perSecond * float64(time.Second))
+		}
+	}
+	if blocked := l.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// unreserve returns a token whose caller gave up before using it
+func (l *providerLimiter) unreserve() {
+	if l.limit.RequestsPerMinute == 0 {
+		return
+	}
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// block pauses all callers until now+d
+func (l *providerLimiter) block(d time.Duration) {
+	l.mu.Lock()
+	if until := time.Now().Add(d); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+	l.mu.Unlock()
+}
+
+// acquire waits for a rate token and an in-flight slot; release must be called when the call finishes
+func (l *providerLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if wait := l.reserve(time.Now()); wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			l.unreserve()
+			return nil, err
+		}
+	}
+
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// limitedAdapter enforces a providerLimiter around Execute and retries 429 responses
+type limitedAdapter struct {
+	Adapter
+	limiter *providerLimiter
+}
+
+// Execute waits for the provider limiter, then retries RateLimitErrors up to MaxRetries
+// using the provider's Retry-After (or exponential backoff from 1s)
+func (a *limitedAdapter) Execute(ctx context.Context, req *Request) (*Response, error) {
+	var resp *Response
+	err := a.call(ctx, func() error {
+		var err error
+		resp, err = a.Adapter.Execute(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// call runs fn as one provider call under the limiter, retrying RateLimitErrors
+func (a *limitedAdapter) call(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		release, err := a.limiter.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		err = fn()
+		release()
+
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) || attempt >= a.limiter.limit.MaxRetries {
+			return err
+		}
+
+		wait := rateLimitErr.RetryAfter
+		if wait <= 0 {
+			wait = time.Second << attempt
+		}
+		if wait > maxRateLimitBackoff {
+			wait = maxRateLimitBackoff
+		}
+		slog.Warn("provider rate limit exceeded, retrying",
+			"adapter", a.ID(),
+			"attempt", attempt+1,
+			"max_retries", a.limiter.limit.MaxRetries,
+			"retry_after", wait,
+		)
+		a.limiter.block(wait)
+	}
+}
+
+// SupportsStructuredOutput keeps the structured output capability of the wrapped adapter
+func (a *limitedAdapter) SupportsStructuredOutput() bool {
+	return SupportsStructuredOutput(a.Adapter)
+}
+
+// IsConfigured keeps the configuration status of the wrapped adapter
+func (a *limitedAdapter) IsConfigured() bool {
+	return IsConfigured(a.Adapter)
+}
+
+// limitedBatchAdapter keeps the BatchAdapter capability of a limited adapter.
+// A batch counts as a single provider call against the limit.
+type limitedBatchAdapter struct {
+	*limitedAdapter
+	batch BatchAdapter
+}
+
+// ExecuteBatch runs the batch under the provider limiter, retrying RateLimitErrors like Execute
+func (a *limitedBatchAdapter) ExecuteBatch(ctx context.Context, reqs []*Request) ([]*Response, error) {
+	var resps []*Response
+	err := a.call(ctx, func() error {
+		var err error
+		resps, err = a.batch.ExecuteBatch(ctx, reqs)
+		return err
+	})
+	return resps, err
+}
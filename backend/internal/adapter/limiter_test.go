@@ -0,0 +1,209 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAdapter records peak concurrency and can fail the first calls with a RateLimitError
+type countingAdapter struct {
+	id         string
+	delay      time.Duration
+	rateLimits int32 // Number of calls that return RateLimitError before succeeding
+
+	inFlight int32
+	peak     int32
+	calls    int32
+}
+
+func (a *countingAdapter) ID() string                    { return a.id }
+func (a *countingAdapter) Name() string                  { return a.id }
+func (a *countingAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *countingAdapter) OutputSchema() json.RawMessage { return nil }
+
+func (a *countingAdapter) Execute(ctx context.Context, req *Request) (*Response, error) {
+	call := atomic.AddInt32(&a.calls, 1)
+	current := atomic.AddInt32(&a.inFlight, 1)
+	defer atomic.AddInt32(&a.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&a.peak)
+		if current <= peak || atomic.CompareAndSwapInt32(&a.peak, peak, current) {
+			break
+		}
+	}
+
+	time.Sleep(a.delay)
+	if call <= a.rateLimits {
+		return nil, &RateLimitError{Provider: a.id, RetryAfter: 10 * time.Millisecond}
+	}
+	return &Response{Output: json.RawMessage(`{}`)}, nil
+}
+
+func TestRegistry_SetLimit_ConcurrencyNeverExceedsLimit(t *testing.T) {
+	inner := &countingAdapter{id: "openai", delay: 5 * time.Millisecond}
+	registry := NewRegistry()
+	registry.Register(inner)
+	registry.SetLimit("openai", ProviderLimit{MaxInFlight: 3})
+
+	adp, ok := registry.Get("openai")
+	require.True(t, ok)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := adp.Execute(context.Background(), &Request{})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(100), inner.calls)
+	assert.LessOrEqual(t, inner.peak, int32(3))
+	assert.Equal(t, int32(3), inner.peak, "burst should use all available slots")
+}
+
+func TestRegistry_SetLimit_SharedAcrossGets(t *testing.T) {
+	inner := &countingAdapter{id: "openai", delay: 5 * time.Millisecond}
+	registry := NewRegistry()
+	registry.Register(inner)
+	registry.SetLimit("openai", ProviderLimit{MaxInFlight: 2})
+
+	// Each step fetches the adapter separately; the limit must still be global
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adp, _ := registry.Get("openai")
+			_, _ = adp.Execute(context.Background(), &Request{})
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, inner.peak, int32(2))
+}
+
+func TestRegistry_SetLimit_RequestsPerMinute(t *testing.T) {
+	inner := &countingAdapter{id: "openai"}
+	registry := NewRegistry()
+	registry.Register(inner)
+	registry.SetLimit("openai", ProviderLimit{RequestsPerMinute: 1200}) // One request per 50ms
+
+	adp, _ := registry.Get("openai")
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := adp.Execute(context.Background(), &Request{})
+		require.NoError(t, err)
+	}
+
+	// The first request uses the initial token; the other four wait ~50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+}
+
+func TestRegistry_SetLimit_ContextCancelledWhileWaiting(t *testing.T) {
+	inner := &countingAdapter{id: "openai"}
+	registry := NewRegistry()
+	registry.Register(inner)
+	registry.SetLimit("openai", ProviderLimit{RequestsPerMinute: 1})
+
+	adp, _ := registry.Get("openai")
+	_, err := adp.Execute(context.Background(), &Request{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = adp.Execute(ctx, &Request{})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), inner.calls)
+}
+
+func TestRegistry_SetLimit_RetriesRateLimitErrors(t *testing.T) {
+	inner := &countingAdapter{id: "openai", rateLimits: 2}
+	registry := NewRegistry()
+	registry.Register(inner)
+	registry.SetLimit("openai", ProviderLimit{MaxInFlight: 1, MaxRetries: 3})
+
+	adp, _ := registry.Get("openai")
+	resp, err := adp.Execute(context.Background(), &Request{})
+
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, int32(3), inner.calls)
+}
+
+func TestRegistry_SetLimit_RetriesExhausted(t *testing.T) {
+	inner := &countingAdapter{id: "openai", rateLimits: 5}
+	registry := NewRegistry()
+	registry.Register(inner)
+	registry.SetLimit("openai", ProviderLimit{MaxInFlight: 1, MaxRetries: 1})
+
+	adp, _ := registry.Get("openai")
+	_, err := adp.Execute(context.Background(), &Request{})
+
+	var rateLimitErr *RateLimitError
+	assert.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, int32(2), inner.calls)
+}
+
+func TestRegistry_Get_NoLimit(t *testing.T) {
+	inner := &countingAdapter{id: "mock"}
+	registry := NewRegistry()
+	registry.Register(inner)
+
+	adp, ok := registry.Get("mock")
+	require.True(t, ok)
+	assert.Same(t, inner, adp)
+
+	_, ok = registry.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestProviderLimitFromEnv(t *testing.T) {
+	t.Setenv("OPENAI_MAX_IN_FLIGHT", "8")
+	t.Setenv("OPENAI_REQUESTS_PER_MINUTE", "500")
+	t.Setenv("OPENAI_MAX_RETRIES", "not-a-number")
+
+	limit, ok := ProviderLimitFromEnv("openai")
+	assert.True(t, ok)
+	assert.Equal(t, ProviderLimit{MaxInFlight: 8, RequestsPerMinute: 500, MaxRetries: DefaultRateLimitRetries}, limit)
+
+	_, ok = ProviderLimitFromEnv("anthropic")
+	assert.False(t, ok)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, 10*time.Second, parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+func TestOpenAIAdapter_Execute_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": {"message": "Rate limit reached", "type": "requests"}}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{id: "openai", name: "OpenAI", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	_, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{"prompt": "hi"}`)})
+
+	var rateLimitErr *RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, 2*time.Second, rateLimitErr.RetryAfter)
+}
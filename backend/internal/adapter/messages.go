@@ -0,0 +1,173 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// Chat message roles accepted in the messages config of LLM adapters
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one entry of the messages config of LLM adapters, used for few-shot examples and
+// conversation history. When messages are configured they replace the single prompt and system
+// fields.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// validateMessages checks that every message has a supported role
+func validateMessages(messages []Message) error {
+	for i, msg := range messages {
+		switch msg.Role {
+		case RoleSystem, RoleUser, RoleAssistant:
+		default:
+			return fmt.Errorf("messages[%d]: unsupported role %q (expected system, user or assistant)", i, msg.Role)
+		}
+	}
+	return nil
+}
+
+// ResponseSchema is the response_schema config of LLM adapters that implement
+// StructuredOutputAdapter: the provider is constrained to answer with a JSON document matching
+// Schema, which the adapter returns as the content of its output.
+type ResponseSchema struct {
+	Name        string          `json:"name"` // Identifier sent to the provider (letters, digits, _ and -)
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema"`
+}
+
+// validate checks that a response schema can be sent to a provider
+func (s *ResponseSchema) validate() error {
+	if !responseSchemaNamePattern.MatchString(s.Name) {
+		return fmt.Errorf("response_schema.name must be 1-64 letters, digits, underscores or hyphens")
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(s.Schema, &schema); err != nil || schema == nil {
+		return fmt.Errorf("response_schema.schema must be a JSON Schema object")
+	}
+	return nil
+}
+
+// responseSchemaNamePattern matches the names both OpenAI and Anthropic accept for schemas and tools
+var responseSchemaNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Media types accepted for base64 image input by both OpenAI and Anthropic
+var supportedImageMediaTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ImageInput is an image sent with the user prompt to a vision model: either a URL or base64 data
+// with its media type. In config it is an object or a string holding a URL or a data: URI.
+type ImageInput struct {
+	URL       string `json:"url,omitempty"`
+	Data      string `json:"data,omitempty"`       // Base64-encoded image
+	MediaType string `json:"media_type,omitempty"` // Required with data, e.g. image/png
+}
+
+// UnmarshalJSON accepts an image object, a URL or a data: URI
+func (i *ImageInput) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		if mediaType, encoded, ok := parseDataURI(text); ok {
+			*i = ImageInput{Data: encoded, MediaType: mediaType}
+		} else {
+			*i = ImageInput{URL: text}
+		}
+		return nil
+	}
+	type plain ImageInput
+	return json.Unmarshal(data, (*plain)(i))
+}
+
+// DataURI returns the image as a URL: its URL, or its data as a data: URI
+func (i ImageInput) DataURI() string {
+	if i.URL != "" {
+		return i.URL
+	}
+	return "data:" + i.MediaType + ";base64," + i.Data
+}
+
+// ImageInputs is the images config of LLM adapters. Template expansion can leave a single image
+// instead of a list, so one image is accepted as a list of one.
+type ImageInputs []ImageInput
+
+// UnmarshalJSON accepts a list of images or a single image
+func (i *ImageInputs) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case trimmed == "null" || trimmed == `""`:
+		*i = nil
+		return nil
+	case strings.HasPrefix(trimmed, "["):
+		var images []ImageInput
+		if err := json.Unmarshal(data, &images); err != nil {
+			return err
+		}
+		*i = images
+		return nil
+	}
+	var image ImageInput
+	if err := json.Unmarshal(data, &image); err != nil {
+		return err
+	}
+	*i = ImageInputs{image}
+	return nil
+}
+
+// parseDataURI splits a base64 data: URI into its media type and data
+func parseDataURI(value string) (mediaType, data string, ok bool) {
+	rest, found := strings.CutPrefix(value, "data:")
+	if !found {
+		return "", "", false
+	}
+	header, data, found := strings.Cut(rest, ",")
+	if !found {
+		return "", "", false
+	}
+	mediaType, found = strings.CutSuffix(header, ";base64")
+	if !found {
+		return "", "", false
+	}
+	return mediaType, data, true
+}
+
+// ValidateImages checks images config and that the provider's model accepts image input
+func ValidateImages(images ImageInputs, provider, model string) error {
+	if len(images) == 0 {
+		return nil
+	}
+	if caps := domain.LookupModelCapabilities(provider, model); !caps.SupportsVision {
+		return fmt.Errorf("images: model %q does not support image input", model)
+	}
+	for i, image := range images {
+		switch {
+		case image.URL != "" && image.Data != "":
+			return fmt.Errorf("images[%d]: set either url or data, not both", i)
+		case image.URL != "":
+			parsed, err := url.Parse(image.URL)
+			if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+				return fmt.Errorf("images[%d]: url must be an http(s) URL or a data: URI", i)
+			}
+		case image.Data != "":
+			if !supportedImageMediaTypes[image.MediaType] {
+				return fmt.Errorf("images[%d]: unsupported media_type %q (expected image/jpeg, image/png, image/gif or image/webp)", i, image.MediaType)
+			}
+		default:
+			return fmt.Errorf("images[%d]: url or data is required", i)
+		}
+	}
+	return nil
+}
@@ -32,6 +32,29 @@ type MockConfig struct {
 	SuccessRate float64 `json:"success_rate"`
 	Response    string  `json:"response"`
 	ErrorMsg    string  `json:"error_message"`
+
+	// Seed makes the mock deterministic: the same seed always picks the same entry
+	// from Responses (or the default canned responses) and the same failure outcome
+	Seed      *int              `json:"seed"`
+	Responses []json.RawMessage `json:"responses"`
+}
+
+// mockCannedResponses are picked by seed when no Response or Responses are configured
+var mockCannedResponses = []string{
+	"The quick brown fox jumps over the lazy dog.",
+	"All systems are operating normally.",
+	"Here is a concise summary of the provided input.",
+	"The request has been processed successfully.",
+	"No issues were found in the supplied data.",
+}
+
+// seedIndex maps a seed onto [0, n)
+func seedIndex(seed, n int) int {
+	idx := seed % n
+	if idx < 0 {
+		idx += n
+	}
+	return idx
 }
 
 // Execute runs the mock adapter
@@ -62,7 +85,11 @@ func (a *MockAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 	}
 
 	// Simulate failure based on success rate
-	if rand.Float64() > config.SuccessRate {
+	roll := rand.Float64()
+	if config.Seed != nil {
+		roll = rand.New(rand.NewSource(int64(*config.Seed))).Float64()
+	}
+	if roll > config.SuccessRate {
 		errMsg := config.ErrorMsg
 		if errMsg == "" {
 			errMsg = "mock adapter simulated failure"
@@ -75,6 +102,17 @@ func (a *MockAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 	var err error
 	if config.Response != "" {
 		output = json.RawMessage(config.Response)
+	} else if config.Seed != nil && len(config.Responses) > 0 {
+		output = config.Responses[seedIndex(*config.Seed, len(config.Responses))]
+	} else if config.Seed != nil {
+		output, err = json.Marshal(map[string]interface{}{
+			"success": true,
+			"content": mockCannedResponses[seedIndex(*config.Seed, len(mockCannedResponses))],
+			"seed":    *config.Seed,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal mock response: %w", err)
+		}
 	} else {
 		// Echo input with success status
 		output, err = json.Marshal(map[string]interface{}{
@@ -87,12 +125,17 @@ func (a *MockAdapter) Execute(ctx context.Context, req *Request) (*Response, err
 		}
 	}
 
+	metadata := map[string]string{
+		"adapter": a.id,
+	}
+	if config.Seed != nil {
+		metadata["seed"] = fmt.Sprintf("%d", *config.Seed)
+	}
+
 	return &Response{
 		Output:     output,
 		DurationMs: int(time.Since(start).Milliseconds()),
-		Metadata: map[string]string{
-			"adapter": a.id,
-		},
+		Metadata:   metadata,
 	}, nil
 }
 
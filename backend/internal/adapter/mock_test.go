@@ -64,6 +64,62 @@ func TestMockAdapter_Execute_CustomResponse(t *testing.T) {
 	assert.JSONEq(t, customResponse, string(resp.Output))
 }
 
+func TestMockAdapter_Execute_SameSeedSameOutput(t *testing.T) {
+	adapter := NewMockAdapter()
+
+	run := func(config string) *Response {
+		resp, err := adapter.Execute(context.Background(), &Request{
+			Input:  json.RawMessage(`{"message": "hello"}`),
+			Config: json.RawMessage(config),
+		})
+		assert.NoError(t, err)
+		return resp
+	}
+
+	first := run(`{"delay_ms": 1, "seed": 42}`)
+	second := run(`{"delay_ms": 1, "seed": 42}`)
+	assert.JSONEq(t, string(first.Output), string(second.Output))
+	assert.Equal(t, "42", first.Metadata["seed"])
+
+	var output map[string]interface{}
+	assert.NoError(t, json.Unmarshal(first.Output, &output))
+	assert.NotEmpty(t, output["content"])
+	assert.Equal(t, float64(42), output["seed"])
+
+	// Every seed maps onto a canned response, including negative seeds
+	seen := map[string]bool{}
+	for seed := -5; seed < 5; seed++ {
+		config, _ := json.Marshal(MockConfig{DelayMs: 1, Seed: &seed})
+		var out map[string]interface{}
+		assert.NoError(t, json.Unmarshal(run(string(config)).Output, &out))
+		seen[out["content"].(string)] = true
+	}
+	assert.Len(t, seen, len(mockCannedResponses))
+}
+
+func TestMockAdapter_Execute_SeedSelectsConfiguredResponse(t *testing.T) {
+	adapter := NewMockAdapter()
+
+	config := `{"delay_ms": 1, "seed": 7, "responses": [{"answer": "a"}, {"answer": "b"}, {"answer": "c"}]}`
+	for i := 0; i < 3; i++ {
+		resp, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(config)})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"answer": "b"}`, string(resp.Output))
+	}
+}
+
+func TestMockAdapter_Execute_SeededFailureIsDeterministic(t *testing.T) {
+	adapter := NewMockAdapter()
+
+	seed := 1
+	config, _ := json.Marshal(MockConfig{DelayMs: 1, SuccessRate: 0.5, Seed: &seed})
+	_, firstErr := adapter.Execute(context.Background(), &Request{Config: config})
+	for i := 0; i < 5; i++ {
+		_, err := adapter.Execute(context.Background(), &Request{Config: config})
+		assert.Equal(t, firstErr == nil, err == nil)
+	}
+}
+
 func TestMockAdapter_Execute_Failure(t *testing.T) {
 	adapter := NewMockAdapter()
 
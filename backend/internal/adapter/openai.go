@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
 )
 
 // OpenAIAdapter implements the Adapter interface for OpenAI API
@@ -18,6 +20,7 @@ type OpenAIAdapter struct {
 	httpClient *http.Client
 	apiKey     string
 	baseURL    string
+	endpoints  *EndpointAllowlist // Regional and extra endpoints step and tenant config may select
 }
 
 // OpenAIConfig holds the configuration for OpenAI adapter
@@ -29,6 +32,12 @@ type OpenAIConfig struct {
 	MaxTokens   int      `json:"max_tokens"`   // Maximum tokens to generate
 	TopP        float64  `json:"top_p"`        // Nucleus sampling
 	Stop        []string `json:"stop"`         // Stop sequences
+	Seed        *int     `json:"seed"`         // Best-effort deterministic sampling (nil = not sent)
+	Messages    []Message `json:"messages"`    // Message array sent instead of prompt and system when set
+	Images      ImageInputs `json:"images"`    // Images attached to the user prompt (vision models only)
+	ResponseSchema *ResponseSchema `json:"response_schema"` // Structured output: content is JSON matching the schema
+
+	domain.EndpointOverride // base_url or region: routes the request to a regional endpoint
 }
 
 // OpenAI API request/response types
@@ -39,19 +48,65 @@ type openAIRequest struct {
 	MaxTokens   int             `json:"max_tokens,omitempty"`
 	TopP        float64         `json:"top_p,omitempty"`
 	Stop        []string        `json:"stop,omitempty"`
+	Seed        *int            `json:"seed,omitempty"`
+
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIResponseFormat requests structured output with a strict JSON Schema
+type openAIResponseFormat struct {
+	Type       string           `json:"type"` // json_schema
+	JSONSchema openAIJSONSchema `json:"json_schema"`
+}
+
+type openAIJSONSchema struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema"`
+	Strict      bool            `json:"strict"`
 }
 
 type openAIMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	// Images turn the content into text and image_url parts when sending a request
+	Images []ImageInput `json:"-"`
+}
+
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"` // http(s) URL or base64 data: URI
+}
+
+// MarshalJSON sends a message with images as content parts
+func (m openAIMessage) MarshalJSON() ([]byte, error) {
+	if len(m.Images) == 0 {
+		type plain openAIMessage
+		return json.Marshal(plain(m))
+	}
+	parts := []openAIContentPart{{Type: "text", Text: m.Content}}
+	for _, image := range m.Images {
+		parts = append(parts, openAIContentPart{Type: "image_url", ImageURL: &openAIImageURL{URL: image.DataURI()}})
+	}
+	return json.Marshal(struct {
+		Role    string              `json:"role"`
+		Content []openAIContentPart `json:"content"`
+	}{Role: m.Role, Content: parts})
 }
 
 type openAIResponse struct {
-	ID      string `json:"id"`
-	Object  string `json:"object"`
-	Created int64  `json:"created"`
-	Model   string `json:"model"`
-	Choices []struct {
+	ID                string `json:"id"`
+	Object            string `json:"object"`
+	Created           int64  `json:"created"`
+	Model             string `json:"model"`
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	Choices           []struct {
 		Index        int           `json:"index"`
 		Message      openAIMessage `json:"message"`
 		FinishReason string        `json:"finish_reason"`
@@ -78,8 +133,9 @@ func NewOpenAIAdapter() *OpenAIAdapter {
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
-		apiKey:  os.Getenv("OPENAI_API_KEY"),
-		baseURL: getEnvOrDefault("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		apiKey:    os.Getenv("OPENAI_API_KEY"),
+		baseURL:   getEnvOrDefault("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		endpoints: endpointAllowlistFromEnv("openai", "OPENAI_ENDPOINT_ALLOWLIST"),
 	}
 }
 
@@ -111,12 +167,17 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 		}
 	}
 
+	baseURL, err := a.endpoints.Resolve(ctx, "openai", a.baseURL, config.EndpointOverride)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAI config: %w", err)
+	}
+
 	// Set defaults
 	if config.Model == "" {
 		config.Model = "gpt-4"
 	}
 	if config.MaxTokens == 0 {
-		config.MaxTokens = 2048
+		config.MaxTokens = defaultMaxTokens("openai", config.Model, 2048)
 	}
 
 	// Handle temperature: use default 0.7 only if not explicitly set (nil)
@@ -129,18 +190,40 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 	// Prompt can be used directly from config
 	prompt := config.Prompt
 
-	// Build messages
+	// Build messages: a configured message array takes precedence over prompt and system
 	messages := []openAIMessage{}
-	if config.System != "" {
+	if len(config.Messages) > 0 {
+		if err := validateMessages(config.Messages); err != nil {
+			return nil, fmt.Errorf("invalid OpenAI config: %w", err)
+		}
+		for _, msg := range config.Messages {
+			messages = append(messages, openAIMessage{Role: msg.Role, Content: msg.Content})
+		}
+	} else {
+		if config.System != "" {
+			messages = append(messages, openAIMessage{
+				Role:    "system",
+				Content: config.System,
+			})
+		}
 		messages = append(messages, openAIMessage{
-			Role:    "system",
-			Content: config.System,
+			Role:    "user",
+			Content: prompt,
 		})
 	}
-	messages = append(messages, openAIMessage{
-		Role:    "user",
-		Content: prompt,
-	})
+
+	// Images go with the last user message
+	if err := ValidateImages(config.Images, "openai", config.Model); err != nil {
+		return nil, fmt.Errorf("invalid OpenAI config: %w", err)
+	}
+	if len(config.Images) > 0 {
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Role == RoleUser {
+				messages[i].Images = config.Images
+				break
+			}
+		}
+	}
 
 	// Build request
 	apiReq := openAIRequest{
@@ -155,6 +238,21 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 	if len(config.Stop) > 0 {
 		apiReq.Stop = config.Stop
 	}
+	apiReq.Seed = config.Seed
+	if config.ResponseSchema != nil {
+		if err := config.ResponseSchema.validate(); err != nil {
+			return nil, fmt.Errorf("invalid OpenAI config: %w", err)
+		}
+		apiReq.ResponseFormat = &openAIResponseFormat{
+			Type: "json_schema",
+			JSONSchema: openAIJSONSchema{
+				Name:        config.ResponseSchema.Name,
+				Description: config.ResponseSchema.Description,
+				Schema:      config.ResponseSchema.Schema,
+				Strict:      true,
+			},
+		}
+	}
 
 	// Make HTTP request
 	reqBody, err := json.Marshal(apiReq)
@@ -162,7 +260,7 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/chat/completions", bytes.NewReader(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -181,6 +279,10 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(a.name, resp, body)
+	}
+
 	var apiResp openAIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -212,6 +314,24 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 		},
 	}
 
+	metadata := map[string]string{
+		"adapter":           a.id,
+		"model":             apiResp.Model,
+		"prompt_tokens":     fmt.Sprintf("%d", apiResp.Usage.PromptTokens),
+		"completion_tokens": fmt.Sprintf("%d", apiResp.Usage.CompletionTokens),
+		"total_tokens":      fmt.Sprintf("%d", apiResp.Usage.TotalTokens),
+	}
+
+	// Seed and system fingerprint identify reproducible responses
+	if config.Seed != nil {
+		output["seed"] = *config.Seed
+		metadata["seed"] = fmt.Sprintf("%d", *config.Seed)
+	}
+	if apiResp.SystemFingerprint != "" {
+		output["system_fingerprint"] = apiResp.SystemFingerprint
+		metadata["system_fingerprint"] = apiResp.SystemFingerprint
+	}
+
 	outputJSON, err := json.Marshal(output)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal output: %w", err)
@@ -220,16 +340,17 @@ func (a *OpenAIAdapter) Execute(ctx context.Context, req *Request) (*Response, e
 	return &Response{
 		Output:     outputJSON,
 		DurationMs: int(time.Since(start).Milliseconds()),
-		Metadata: map[string]string{
-			"adapter":           a.id,
-			"model":             apiResp.Model,
-			"prompt_tokens":     fmt.Sprintf("%d", apiResp.Usage.PromptTokens),
-			"completion_tokens": fmt.Sprintf("%d", apiResp.Usage.CompletionTokens),
-			"total_tokens":      fmt.Sprintf("%d", apiResp.Usage.TotalTokens),
-		},
+		Metadata:   metadata,
 	}, nil
 }
 
+// SupportsStructuredOutput reports that response_schema is sent as a strict json_schema
+// response format
+func (a *OpenAIAdapter) SupportsStructuredOutput() bool { return true }
+
+// IsConfigured reports whether an API key is set
+func (a *OpenAIAdapter) IsConfigured() bool { return a.apiKey != "" }
+
 func (a *OpenAIAdapter) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
 		"type": "object",
@@ -245,6 +366,8 @@ func (a *OpenAIAdapter) OutputSchema() json.RawMessage {
 			"content": {"type": "string", "description": "Generated text content"},
 			"model": {"type": "string", "description": "Model used"},
 			"finish_reason": {"type": "string", "description": "Reason for completion"},
+			"seed": {"type": "integer", "description": "Seed sent with the request"},
+			"system_fingerprint": {"type": "string", "description": "Backend configuration that served the request"},
 			"usage": {
 				"type": "object",
 				"properties": {
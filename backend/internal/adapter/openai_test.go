@@ -310,3 +310,195 @@ func TestOpenAIAdapter_Execute_TemperatureDefault(t *testing.T) {
 	// Verify that default temperature 0.7 was used
 	assert.Equal(t, 0.7, receivedTemperature, "default temperature should be 0.7 when not specified")
 }
+
+func TestOpenAIAdapter_Execute_Seed(t *testing.T) {
+	var receivedSeed *int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody openAIRequest
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		receivedSeed = reqBody.Seed
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "chatcmpl-123",
+			"model": "gpt-4",
+			"system_fingerprint": "fp_44709d6fcb",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2}
+		}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{
+		id:         "openai",
+		name:       "OpenAI",
+		httpClient: server.Client(),
+		apiKey:     "test-api-key",
+		baseURL:    server.URL,
+	}
+
+	seed := 1234
+	config, _ := json.Marshal(OpenAIConfig{Model: "gpt-4", Prompt: "Test prompt", Seed: &seed})
+	resp, err := adapter.Execute(context.Background(), &Request{Config: config})
+
+	require.NoError(t, err)
+	require.NotNil(t, receivedSeed)
+	assert.Equal(t, 1234, *receivedSeed)
+	assert.Equal(t, "1234", resp.Metadata["seed"])
+	assert.Equal(t, "fp_44709d6fcb", resp.Metadata["system_fingerprint"])
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Output, &output))
+	assert.Equal(t, "fp_44709d6fcb", output["system_fingerprint"])
+}
+
+func TestOpenAIAdapter_Execute_NoSeedOmitted(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "gpt-4", "choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{id: "openai", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	resp, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{"prompt": "hi"}`)})
+
+	require.NoError(t, err)
+	assert.NotContains(t, body, "seed")
+	assert.NotContains(t, resp.Metadata, "seed")
+	assert.NotContains(t, resp.Metadata, "system_fingerprint")
+}
+
+func TestOpenAIAdapter_Execute_Messages(t *testing.T) {
+	var reqBody openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "gpt-4", "choices": [{"message": {"role": "assistant", "content": "positive"}}]}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{id: "openai", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	config, _ := json.Marshal(OpenAIConfig{
+		Prompt: "ignored when messages are set",
+		System: "ignored as well",
+		Messages: []Message{
+			{Role: "system", Content: "Classify the sentiment."},
+			{Role: "user", Content: "I love it"},
+			{Role: "assistant", Content: "positive"},
+			{Role: "user", Content: "Works great"},
+		},
+	})
+	_, err := adapter.Execute(context.Background(), &Request{Config: config})
+
+	require.NoError(t, err)
+	assert.Equal(t, []openAIMessage{
+		{Role: "system", Content: "Classify the sentiment."},
+		{Role: "user", Content: "I love it"},
+		{Role: "assistant", Content: "positive"},
+		{Role: "user", Content: "Works great"},
+	}, reqBody.Messages)
+}
+
+func TestOpenAIAdapter_Execute_MessagesInvalidRole(t *testing.T) {
+	adapter := &OpenAIAdapter{id: "openai", httpClient: http.DefaultClient, apiKey: "test-api-key", baseURL: "http://127.0.0.1:0"}
+	config := json.RawMessage(`{"messages": [{"role": "tool", "content": "x"}]}`)
+
+	_, err := adapter.Execute(context.Background(), &Request{Config: config})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `messages[0]: unsupported role "tool"`)
+}
+
+func TestOpenAIAdapter_Execute_Images(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": "a cat"}}], "usage": {"prompt_tokens": 800, "completion_tokens": 2, "total_tokens": 802}}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{id: "openai", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	resp, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"model": "gpt-4o",
+		"system": "Describe images briefly.",
+		"prompt": "What is this?",
+		"images": ["https://example.com/cat.png", {"data": "iVBORw0KGgo=", "media_type": "image/png"}]
+	}`)})
+
+	require.NoError(t, err)
+	messages := body["messages"].([]interface{})
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Describe images briefly.", messages[0].(map[string]interface{})["content"], "system message stays text")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "text", "text": "What is this?"},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "https://example.com/cat.png"}},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "data:image/png;base64,iVBORw0KGgo="}},
+	}, messages[1].(map[string]interface{})["content"])
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Output, &output))
+	assert.Equal(t, "a cat", output["content"])
+	assert.Equal(t, map[string]interface{}{"prompt_tokens": float64(800), "completion_tokens": float64(2), "total_tokens": float64(802)}, output["usage"])
+}
+
+func TestOpenAIAdapter_Execute_ImagesRejectedForTextModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for a model without vision support")
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{id: "openai", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	_, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"model": "gpt-3.5-turbo",
+		"prompt": "What is this?",
+		"images": "https://example.com/cat.png"
+	}`)})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `model "gpt-3.5-turbo" does not support image input`)
+}
+
+func TestOpenAIAdapter_Execute_ResponseSchema(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "gpt-4o", "choices": [{"message": {"role": "assistant", "content": "{\"route\":\"billing\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenAIAdapter{id: "openai", httpClient: server.Client(), apiKey: "test-api-key", baseURL: server.URL}
+	resp, err := adapter.Execute(context.Background(), &Request{Config: json.RawMessage(`{
+		"model": "gpt-4o",
+		"prompt": "Route this ticket",
+		"response_schema": {"name": "route_selection", "schema": {"type": "object", "properties": {"route": {"type": "string", "enum": ["billing", "support"]}}}}
+	}`)})
+
+	require.NoError(t, err)
+	assert.True(t, adapter.SupportsStructuredOutput())
+	assert.Equal(t, map[string]interface{}{
+		"type": "json_schema",
+		"json_schema": map[string]interface{}{
+			"name":   "route_selection",
+			"strict": true,
+			"schema": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"route": map[string]interface{}{"type": "string", "enum": []interface{}{"billing", "support"}}}},
+		},
+	}, body["response_format"])
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Output, &output))
+	assert.Equal(t, `{"route":"billing"}`, output["content"])
+}
+
+func TestOpenAIAdapter_Execute_ResponseSchemaInvalidName(t *testing.T) {
+	adapter := &OpenAIAdapter{id: "openai", httpClient: http.DefaultClient, apiKey: "test-api-key", baseURL: "http://127.0.0.1:0"}
+	config := json.RawMessage(`{"prompt": "hi", "response_schema": {"name": "route selection", "schema": {"type": "object"}}}`)
+
+	_, err := adapter.Execute(context.Background(), &Request{Config: config})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "response_schema.name")
+}
@@ -0,0 +1,143 @@
+package sandbox
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+// DangerousCodePatterns are commands and calls that can damage systems or hijack an LLM.
+// Kept in sync with the check_security copilot tool (see seed/workflows/copilot.go).
+var DangerousCodePatterns = []string{
+	"rm -rf", "rm -f", "DROP TABLE", "DELETE FROM", "TRUNCATE",
+	"eval(", "exec(", "system(", "os.system", "subprocess.", "child_process", "__import__",
+	`require("fs")`, "fs.unlink", "fs.rmdir", "process.exit",
+	"curl |", "wget |", "base64 -d", "; bash", "| sh",
+	"ignore previous", "ignore above", "ignore all", "disregard previous", "system:", "jailbreak", "dan mode",
+}
+
+// SensitiveCodePatterns suggest hardcoded secrets or credential handling
+var SensitiveCodePatterns = []string{
+	"password", "api_key", "api-key", "secret", "token", "credential",
+	"private_key", "private-key", "access_key", "access-key",
+}
+
+// CodeFindingCategory classifies a code analysis finding
+type CodeFindingCategory string
+
+const (
+	CodeFindingDangerous CodeFindingCategory = "dangerous" // Matches DangerousCodePatterns (high risk)
+	CodeFindingSensitive CodeFindingCategory = "sensitive" // Matches SensitiveCodePatterns (medium risk)
+	CodeFindingSyntax    CodeFindingCategory = "syntax"    // Code does not compile in the sandbox
+)
+
+// CodeRiskLevel is the overall risk of analyzed code
+type CodeRiskLevel string
+
+const (
+	CodeRiskLow    CodeRiskLevel = "low"
+	CodeRiskMedium CodeRiskLevel = "medium"
+	CodeRiskHigh   CodeRiskLevel = "high"
+)
+
+// CodeFinding is a single issue found in code
+type CodeFinding struct {
+	Category CodeFindingCategory `json:"category"`
+	Risk     CodeRiskLevel       `json:"risk"`
+	Pattern  string              `json:"pattern,omitempty"`
+	Line     int                 `json:"line,omitempty"` // 1-based line in the analyzed code (first occurrence)
+	Message  string              `json:"message"`
+}
+
+// CodeAnalysis is the result of AnalyzeCode
+type CodeAnalysis struct {
+	Safe        bool          `json:"safe"` // No dangerous patterns
+	SyntaxValid bool          `json:"syntax_valid"`
+	RiskLevel   CodeRiskLevel `json:"risk_level"`
+	Findings    []CodeFinding `json:"findings"`
+}
+
+// syntaxErrorPattern extracts the position and message from a goja compile error
+var syntaxErrorPattern = regexp.MustCompile(`Line (\d+):\d+ (.+?)(?: \(and \d+ more errors?\))?$`)
+
+// AnalyzeCode checks block code for dangerous and sensitive patterns (case-insensitive)
+// and compiles it the way the sandbox would, without executing it
+func AnalyzeCode(code string) *CodeAnalysis {
+	analysis := &CodeAnalysis{
+		Safe:        true,
+		SyntaxValid: true,
+		RiskLevel:   CodeRiskLow,
+		Findings:    []CodeFinding{},
+	}
+
+	lower := strings.ToLower(code)
+	for _, pattern := range DangerousCodePatterns {
+		if line := findPatternLine(lower, strings.ToLower(pattern)); line > 0 {
+			analysis.Findings = append(analysis.Findings, CodeFinding{
+				Category: CodeFindingDangerous,
+				Risk:     CodeRiskHigh,
+				Pattern:  pattern,
+				Line:     line,
+				Message:  "potentially dangerous command or call: " + pattern,
+			})
+			analysis.Safe = false
+			analysis.RiskLevel = CodeRiskHigh
+		}
+	}
+	for _, pattern := range SensitiveCodePatterns {
+		if line := findPatternLine(lower, pattern); line > 0 {
+			analysis.Findings = append(analysis.Findings, CodeFinding{
+				Category: CodeFindingSensitive,
+				Risk:     CodeRiskMedium,
+				Pattern:  pattern,
+				Line:     line,
+				Message:  "possible sensitive data reference: " + pattern + " (use credentials instead of hardcoding secrets)",
+			})
+			if analysis.RiskLevel == CodeRiskLow {
+				analysis.RiskLevel = CodeRiskMedium
+			}
+		}
+	}
+
+	if finding := checkSyntax(code); finding != nil {
+		analysis.Findings = append(analysis.Findings, *finding)
+		analysis.SyntaxValid = false
+	}
+
+	return analysis
+}
+
+// findPatternLine returns the 1-based line of the first occurrence of pattern, or 0 if absent
+func findPatternLine(content, pattern string) int {
+	idx := strings.Index(content, pattern)
+	if idx < 0 {
+		return 0
+	}
+	return strings.Count(content[:idx], "\n") + 1
+}
+
+// checkSyntax compiles code as the sandbox wraps it and reports the first syntax error
+func checkSyntax(code string) *CodeFinding {
+	if strings.TrimSpace(code) == "" {
+		return nil
+	}
+	wrapped := wrapUserCode(code)
+	if _, err := goja.Compile("code", wrapped, false); err != nil {
+		finding := &CodeFinding{
+			Category: CodeFindingSyntax,
+			Risk:     CodeRiskLow,
+			Message:  sanitizeError(err).Error(),
+		}
+		if m := syntaxErrorPattern.FindStringSubmatch(err.Error()); m != nil {
+			// Map the line in the wrapped script back to the user's code
+			offset := strings.Index(wrapped, code)
+			wrappedLine, _ := strconv.Atoi(m[1])
+			finding.Line = wrappedLine - strings.Count(wrapped[:offset], "\n")
+			finding.Message = m[2]
+		}
+		return finding
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeCode_DangerousCommand(t *testing.T) {
+	code := "const target = input.dir;\nreturn ctx.shell('RM -RF ' + target);"
+
+	analysis := AnalyzeCode(code)
+
+	assert.False(t, analysis.Safe)
+	assert.True(t, analysis.SyntaxValid)
+	assert.Equal(t, CodeRiskHigh, analysis.RiskLevel)
+	require.NotEmpty(t, analysis.Findings)
+	assert.Equal(t, CodeFinding{
+		Category: CodeFindingDangerous,
+		Risk:     CodeRiskHigh,
+		Pattern:  "rm -rf",
+		Line:     2,
+		Message:  "potentially dangerous command or call: rm -rf",
+	}, analysis.Findings[0])
+}
+
+func TestAnalyzeCode_CredentialPatterns(t *testing.T) {
+	code := "const headers = {\n  Authorization: 'Bearer ' + input.token,\n};\nconst api_key = 'sk-123';\nreturn { headers };"
+
+	analysis := AnalyzeCode(code)
+
+	assert.True(t, analysis.Safe, "credential references are warnings, not blockers")
+	assert.Equal(t, CodeRiskMedium, analysis.RiskLevel)
+
+	lines := map[string]int{}
+	for _, finding := range analysis.Findings {
+		assert.Equal(t, CodeFindingSensitive, finding.Category)
+		lines[finding.Pattern] = finding.Line
+	}
+	assert.Equal(t, map[string]int{"token": 2, "api_key": 4}, lines)
+}
+
+func TestAnalyzeCode_CleanCode(t *testing.T) {
+	for _, code := range []string{
+		"const items = input.items || [];\nreturn { count: items.length };",
+		"function execute(input, context) {\n  return { doubled: input.value * 2 };\n}",
+	} {
+		analysis := AnalyzeCode(code)
+
+		assert.True(t, analysis.Safe, code)
+		assert.True(t, analysis.SyntaxValid, code)
+		assert.Equal(t, CodeRiskLow, analysis.RiskLevel, code)
+		assert.Empty(t, analysis.Findings, code)
+	}
+}
+
+func TestAnalyzeCode_SyntaxError(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		wantLine int
+	}{
+		{name: "function body", code: "const a = 1;\nreturn { a: };", wantLine: 2},
+		{name: "execute function", code: "function execute(input) {\n  const b = 2;\n  return (;\n}", wantLine: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeCode(tt.code)
+
+			assert.False(t, analysis.SyntaxValid)
+			assert.True(t, analysis.Safe)
+			require.Len(t, analysis.Findings, 1)
+			assert.Equal(t, CodeFindingSyntax, analysis.Findings[0].Category)
+			assert.Equal(t, tt.wantLine, analysis.Findings[0].Line)
+			assert.Contains(t, analysis.Findings[0].Message, "Unexpected token")
+		})
+	}
+}
@@ -0,0 +1,561 @@
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Document formats supported by ctx.documents.load
+const (
+	DocumentFormatText     = "text"
+	DocumentFormatMarkdown = "markdown"
+	DocumentFormatHTML     = "html"
+	DocumentFormatCSV      = "csv"
+	DocumentFormatJSON     = "json"
+)
+
+// DefaultMaxDocumentBytes bounds a single document load when no max_bytes is given (50 MiB)
+const DefaultMaxDocumentBytes int64 = 50 << 20
+
+// documentReadBufferSize is the read size used while streaming a document
+const documentReadBufferSize = 64 << 10
+
+var (
+	ErrDocumentTooLarge          = errors.New("document exceeds maximum size")
+	ErrUnsupportedDocumentFormat = errors.New("unsupported document format")
+)
+
+var supportedDocumentFormats = map[string]bool{
+	DocumentFormatText:     true,
+	DocumentFormatMarkdown: true,
+	DocumentFormatHTML:     true,
+	DocumentFormatCSV:      true,
+	DocumentFormatJSON:     true,
+}
+
+// DocumentLoadOptions configures how a document is streamed and split
+type DocumentLoadOptions struct {
+	Format       string // Empty = detect from Content-Type and URL extension (text for inline content)
+	MaxBytes     int64  // 0 = DefaultMaxDocumentBytes
+	ChunkSize    int    // Max characters per chunk (0 = the whole document as one chunk)
+	ChunkOverlap int    // Characters repeated from the end of the previous chunk
+	Separator    string // Defaults to "\n\n" (csv always splits by record)
+	KeepHTML     bool   // Load html as raw text instead of stripping tags
+}
+
+// Validate checks the options and applies defaults
+func (o *DocumentLoadOptions) Validate() error {
+	if o.Format != "" && !supportedDocumentFormats[o.Format] {
+		return unsupportedDocumentFormat(o.Format)
+	}
+	if o.MaxBytes < 0 {
+		return fmt.Errorf("max_bytes must not be negative")
+	}
+	if o.MaxBytes == 0 {
+		o.MaxBytes = DefaultMaxDocumentBytes
+	}
+	if o.ChunkSize < 0 || o.ChunkOverlap < 0 {
+		return fmt.Errorf("chunk_size and chunk_overlap must not be negative")
+	}
+	if o.ChunkSize > 0 && o.ChunkOverlap >= o.ChunkSize {
+		return fmt.Errorf("chunk_overlap must be smaller than chunk_size")
+	}
+	if o.Separator == "" {
+		o.Separator = "\n\n"
+	}
+	return nil
+}
+
+func unsupportedDocumentFormat(format string) error {
+	return fmt.Errorf("%w: %s (supported: text, markdown, html, csv, json)", ErrUnsupportedDocumentFormat, format)
+}
+
+// DocumentChunk is one piece of a streamed document
+type DocumentChunk struct {
+	Content   string `json:"content"`
+	Index     int    `json:"chunk_index"`
+	CharCount int    `json:"char_count"`
+}
+
+// DocumentStats summarizes a streamed document
+type DocumentStats struct {
+	Format      string `json:"format"`
+	ContentType string `json:"content_type,omitempty"`
+	BytesRead   int64  `json:"bytes_read"`
+	ChunkCount  int    `json:"chunk_count"`
+}
+
+// DetectDocumentFormat picks a format from the Content-Type, falling back to the source's file extension.
+// Binary formats such as PDF or Office documents return ErrUnsupportedDocumentFormat.
+func DetectDocumentFormat(contentType, source string) (string, error) {
+	mediaType := ""
+	if contentType != "" {
+		if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+			mediaType = parsed
+		}
+	}
+
+	switch {
+	case mediaType == "text/html", mediaType == "application/xhtml+xml":
+		return DocumentFormatHTML, nil
+	case mediaType == "text/csv":
+		return DocumentFormatCSV, nil
+	case mediaType == "text/markdown", mediaType == "text/x-markdown":
+		return DocumentFormatMarkdown, nil
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return DocumentFormatJSON, nil
+	}
+
+	ext := ""
+	if u, err := url.Parse(source); err == nil {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+	switch ext {
+	case ".html", ".htm":
+		return DocumentFormatHTML, nil
+	case ".csv":
+		return DocumentFormatCSV, nil
+	case ".md", ".markdown":
+		return DocumentFormatMarkdown, nil
+	case ".json":
+		return DocumentFormatJSON, nil
+	case ".txt":
+		return DocumentFormatText, nil
+	}
+
+	switch {
+	case mediaType == "", mediaType == "application/octet-stream":
+		if ext == "" {
+			return DocumentFormatText, nil
+		}
+		return "", unsupportedDocumentFormat(strings.TrimPrefix(ext, "."))
+	case strings.HasPrefix(mediaType, "text/"):
+		return DocumentFormatText, nil
+	default:
+		return "", unsupportedDocumentFormat(mediaType)
+	}
+}
+
+// LoadDocumentURL fetches a document and streams it through StreamDocument without buffering the body
+func LoadDocumentURL(ctx context.Context, client *HTTPClient, rawURL string, options map[string]interface{}, opts DocumentLoadOptions, emit func(DocumentChunk) error) (*DocumentStats, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Open(ctx, http.MethodGet, rawURL, nil, options)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch document: HTTP %d", resp.StatusCode)
+	}
+	if resp.ContentLength > opts.MaxBytes {
+		return nil, fmt.Errorf("%w (%d bytes, limit %d)", ErrDocumentTooLarge, resp.ContentLength, opts.MaxBytes)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if opts.Format == "" {
+		if opts.Format, err = DetectDocumentFormat(contentType, rawURL); err != nil {
+			return nil, err
+		}
+	}
+
+	stats, err := StreamDocument(resp.Body, opts, emit)
+	if stats != nil {
+		stats.ContentType = contentType
+	}
+	return stats, err
+}
+
+// StreamDocument reads r incrementally and calls emit for every chunk as soon as it is complete.
+// Memory use is bounded by the chunk size and read buffer, not by the document size.
+// Returns ErrDocumentTooLarge once more than MaxBytes have been read.
+func StreamDocument(r io.Reader, opts DocumentLoadOptions, emit func(DocumentChunk) error) (*DocumentStats, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Format == "" {
+		opts.Format = DocumentFormatText
+	}
+
+	limited := &maxBytesReader{r: r, remaining: opts.MaxBytes, max: opts.MaxBytes}
+	stats := &DocumentStats{Format: opts.Format}
+	chunker := &textChunker{size: opts.ChunkSize, overlap: opts.ChunkOverlap, sep: opts.Separator, emit: emit}
+
+	var src io.Reader = limited
+	if opts.Format == DocumentFormatHTML && !opts.KeepHTML {
+		src = newHTMLTextReader(limited)
+		chunker.unescape = true
+	}
+
+	var err error
+	if opts.Format == DocumentFormatCSV {
+		err = streamCSV(src, chunker)
+	} else {
+		err = streamText(src, chunker)
+	}
+	if err == nil {
+		err = chunker.flush()
+	}
+
+	stats.BytesRead = limited.read
+	stats.ChunkCount = chunker.count
+	return stats, err
+}
+
+// streamText splits the stream on the separator without holding more than one chunk of pending text
+func streamText(r io.Reader, c *textChunker) error {
+	sep := []byte(c.sep)
+	// Text without separators is cut once it could no longer fit in a chunk
+	maxPending := c.size * utf8.UTFMax
+	buf := make([]byte, documentReadBufferSize)
+	var pending []byte
+
+	for {
+		n, readErr := r.Read(buf)
+		pending = append(pending, buf[:n]...)
+
+		for {
+			if idx := bytes.Index(pending, sep); idx >= 0 {
+				if err := c.add(string(pending[:idx])); err != nil {
+					return err
+				}
+				pending = pending[idx+len(sep):]
+				continue
+			}
+			if c.size > 0 && len(pending) > maxPending {
+				cut := runePrefixLen(pending, c.size)
+				if err := c.add(string(pending[:cut])); err != nil {
+					return err
+				}
+				pending = pending[cut:]
+				continue
+			}
+			break
+		}
+		// Move the remainder to the front so the buffer does not grow with the document
+		pending = append(pending[:0:0], pending...)
+
+		if readErr == io.EOF {
+			return c.add(string(pending))
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// streamCSV splits by record and repeats the header row at the top of every chunk.
+// Records are re-encoded, so quoted fields containing newlines stay intact.
+func streamCSV(r io.Reader, c *textChunker) error {
+	reader := csv.NewReader(bufio.NewReaderSize(r, documentReadBufferSize))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return unwrapCSVError(err)
+	}
+	headerLine := encodeCSVRecord(header)
+
+	c.sep = "\n"
+	c.overlap = 0
+	c.prefix = headerLine + "\n"
+	if c.size > 0 {
+		headerLen := utf8.RuneCountInString(c.prefix)
+		if headerLen >= c.size {
+			return fmt.Errorf("csv header (%d characters) does not fit in chunk_size %d", headerLen, c.size)
+		}
+		c.size -= headerLen
+	}
+
+	rows := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return unwrapCSVError(err)
+		}
+		rows++
+		if err := c.add(encodeCSVRecord(record)); err != nil {
+			return err
+		}
+	}
+	if rows == 0 {
+		// A header-only file still produces one chunk
+		c.prefix = ""
+		return c.add(headerLine)
+	}
+	return nil
+}
+
+func encodeCSVRecord(record []string) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write(record)
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// unwrapCSVError keeps ErrDocumentTooLarge visible through csv.ParseError
+func unwrapCSVError(err error) error {
+	if errors.Is(err, ErrDocumentTooLarge) {
+		return err
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) && parseErr.Err != nil && errors.Is(parseErr.Err, ErrDocumentTooLarge) {
+		return parseErr.Err
+	}
+	return fmt.Errorf("invalid csv: %w", err)
+}
+
+// textChunker builds chunks of at most size characters from separator-delimited segments,
+// in the same way as the text-splitter block
+type textChunker struct {
+	size     int // 0 = no splitting
+	overlap  int
+	sep      string
+	prefix   string // Prepended to every chunk (csv header)
+	unescape bool   // Decode HTML entities in chunk content
+	emit     func(DocumentChunk) error
+
+	current    []byte
+	currentLen int // Characters in current
+	count      int
+}
+
+// add appends a segment, emitting the current chunk first if the segment does not fit
+func (c *textChunker) add(segment string) error {
+	if c.currentLen > 0 || len(c.current) > 0 {
+		if c.size > 0 && c.currentLen+utf8.RuneCountInString(c.sep)+utf8.RuneCountInString(segment) > c.size {
+			tail := overlapTail(c.current, c.overlap)
+			if err := c.flush(); err != nil {
+				return err
+			}
+			if tail != "" {
+				c.append(tail + c.sep)
+			}
+		} else {
+			c.append(c.sep)
+		}
+	}
+	c.append(segment)
+
+	// A single segment longer than a chunk is cut at character boundaries
+	for c.size > 0 && c.currentLen > c.size {
+		cut := runePrefixLen(c.current, c.size)
+		head := c.current[:cut]
+		rest := string(c.current[cut:])
+		tail := overlapTail(head, c.overlap)
+		c.current = head
+		if err := c.flush(); err != nil {
+			return err
+		}
+		c.append(tail + rest)
+	}
+	return nil
+}
+
+func (c *textChunker) append(s string) {
+	c.current = append(c.current, s...)
+	c.currentLen += utf8.RuneCountInString(s)
+}
+
+// flush emits the current chunk (if it has any content) and resets it
+func (c *textChunker) flush() error {
+	content := strings.TrimSpace(string(c.current))
+	c.current = c.current[:0]
+	c.currentLen = 0
+	if content == "" {
+		return nil
+	}
+	if c.unescape {
+		content = html.UnescapeString(content)
+	}
+	content = c.prefix + content
+
+	chunk := DocumentChunk{Content: content, Index: c.count, CharCount: utf8.RuneCountInString(content)}
+	c.count++
+	if c.emit == nil {
+		return nil
+	}
+	return c.emit(chunk)
+}
+
+// overlapTail returns the last n characters of b, starting at a word boundary when one exists
+func overlapTail(b []byte, n int) string {
+	if n <= 0 || len(b) == 0 {
+		return ""
+	}
+	start := len(b)
+	for i := 0; i < n && start > 0; i++ {
+		_, size := utf8.DecodeLastRune(b[:start])
+		start -= size
+	}
+	tail := b[start:]
+	if start > 0 {
+		if idx := bytes.IndexFunc(tail, unicode.IsSpace); idx >= 0 && idx < len(tail)-1 {
+			tail = tail[idx+1:]
+		}
+	}
+	return strings.TrimSpace(string(tail))
+}
+
+// runePrefixLen returns the byte length of the first n characters of b
+func runePrefixLen(b []byte, n int) int {
+	offset := 0
+	for i := 0; i < n && offset < len(b); i++ {
+		_, size := utf8.DecodeRune(b[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// maxBytesReader counts bytes read and fails with ErrDocumentTooLarge past max
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+	max       int64
+	read      int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining <= 0 {
+		// Probe for one more byte to tell "exactly max" from "too large"
+		var probe [1]byte
+		if n, _ := io.ReadFull(m.r, probe[:]); n > 0 {
+			return 0, fmt.Errorf("%w (limit %d bytes)", ErrDocumentTooLarge, m.max)
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	m.read += int64(n)
+	return n, err
+}
+
+// htmlBlockTags end a paragraph when stripped, so the default "\n\n" separator still applies
+var htmlBlockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "section": true, "article": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "pre": true, "blockquote": true,
+}
+
+// htmlTextReader strips tags, scripts and styles from an HTML stream byte by byte
+type htmlTextReader struct {
+	r        *bufio.Reader
+	inTag    bool
+	tagName  []byte
+	nameDone bool
+	skipTo   string // Closing tag being searched for inside script/style
+	matched  int
+	out      [2]byte
+	pending  []byte
+}
+
+func newHTMLTextReader(r io.Reader) *htmlTextReader {
+	return &htmlTextReader{r: bufio.NewReaderSize(r, documentReadBufferSize)}
+}
+
+func (h *htmlTextReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(h.pending) > 0 {
+			copied := copy(p[n:], h.pending)
+			h.pending = h.pending[copied:]
+			n += copied
+			continue
+		}
+		b, err := h.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		h.pending = h.process(b)
+	}
+	return n, nil
+}
+
+// process consumes one input byte and returns the text bytes it produces
+func (h *htmlTextReader) process(b byte) []byte {
+	lower := b
+	if 'A' <= b && b <= 'Z' {
+		lower = b + ('a' - 'A')
+	}
+
+	switch {
+	case h.skipTo != "":
+		if lower == h.skipTo[h.matched] {
+			h.matched++
+			if h.matched == len(h.skipTo) {
+				// Consume the rest of the closing tag like any other end tag
+				h.skipTo, h.matched = "", 0
+				h.inTag, h.nameDone = true, true
+				h.tagName = append(h.tagName[:0], '/')
+			}
+		} else if lower == '<' {
+			h.matched = 1
+		} else {
+			h.matched = 0
+		}
+		return nil
+
+	case h.inTag:
+		if b == '>' {
+			h.inTag = false
+			name := strings.TrimPrefix(string(h.tagName), "/")
+			if (name == "script" || name == "style") && !strings.HasPrefix(string(h.tagName), "/") {
+				h.skipTo = "</" + name
+				return nil
+			}
+			if htmlBlockTags[name] {
+				h.out = [2]byte{'\n', '\n'}
+				return h.out[:2]
+			}
+			h.out[0] = ' '
+			return h.out[:1]
+		}
+		if !h.nameDone {
+			if unicode.IsSpace(rune(b)) || b == '/' && len(h.tagName) > 0 {
+				h.nameDone = true
+			} else if len(h.tagName) < 16 {
+				h.tagName = append(h.tagName, lower)
+			}
+		}
+		return nil
+
+	case b == '<':
+		h.inTag, h.nameDone = true, false
+		h.tagName = h.tagName[:0]
+		return nil
+
+	default:
+		h.out[0] = b
+		return h.out[:1]
+	}
+}
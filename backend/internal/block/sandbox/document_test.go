@@ -0,0 +1,301 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paragraphReader generates numbered paragraphs on the fly so a large document never exists in memory
+type paragraphReader struct {
+	total   int
+	next    int
+	pending []byte
+}
+
+func (r *paragraphReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.pending) == 0 {
+			if r.next == r.total {
+				if n == 0 {
+					return 0, io.EOF
+				}
+				return n, nil
+			}
+			r.pending = []byte(fmt.Sprintf("p%08d Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor.\n\n", r.next))
+			r.next++
+		}
+		copied := copy(p[n:], r.pending)
+		r.pending = r.pending[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+func collectChunks(t *testing.T, r io.Reader, opts DocumentLoadOptions) ([]DocumentChunk, *DocumentStats) {
+	t.Helper()
+	var chunks []DocumentChunk
+	stats, err := StreamDocument(r, opts, func(chunk DocumentChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	return chunks, stats
+}
+
+func TestStreamDocument_LargeDocumentBoundedMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("streams a 64MB synthetic document")
+	}
+
+	const paragraphs = 700_000 // ~64MB
+	const chunkSize = 1000
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	var peakHeap uint64
+	seen := 0
+	chunkCount := 0
+	stats, err := StreamDocument(&paragraphReader{total: paragraphs}, DocumentLoadOptions{ChunkSize: chunkSize, MaxBytes: 1 << 30}, func(chunk DocumentChunk) error {
+		if chunk.CharCount > chunkSize {
+			return fmt.Errorf("chunk %d has %d characters", chunk.Index, chunk.CharCount)
+		}
+		if chunk.Index != chunkCount {
+			return fmt.Errorf("chunk index %d, want %d", chunk.Index, chunkCount)
+		}
+		chunkCount++
+		seen += strings.Count(chunk.Content, " Lorem ipsum")
+		if chunkCount%500 == 0 {
+			var m runtime.MemStats
+			runtime.ReadMemStats(&m)
+			if m.HeapAlloc > peakHeap {
+				peakHeap = m.HeapAlloc
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Greater(t, stats.BytesRead, int64(60<<20))
+	assert.Equal(t, paragraphs, seen, "every paragraph should appear in exactly one chunk")
+	assert.Equal(t, chunkCount, stats.ChunkCount)
+	assert.Greater(t, chunkCount, 60_000)
+
+	growth := int64(peakHeap) - int64(before.HeapAlloc)
+	assert.Less(t, growth, int64(32<<20), "heap grew by %d bytes while streaming a %d byte document", growth, stats.BytesRead)
+}
+
+func TestStreamDocument_Overlap(t *testing.T) {
+	text := "alpha beta gamma\n\ndelta epsilon zeta\n\neta theta iota"
+	chunks, _ := collectChunks(t, strings.NewReader(text), DocumentLoadOptions{ChunkSize: 30, ChunkOverlap: 10})
+
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "alpha beta gamma", chunks[0].Content)
+	assert.True(t, strings.HasPrefix(chunks[1].Content, "gamma\n\ndelta"), chunks[1].Content)
+	assert.True(t, strings.HasSuffix(chunks[2].Content, "eta theta iota"), chunks[2].Content)
+}
+
+func TestStreamDocument_WholeDocumentWithoutChunkSize(t *testing.T) {
+	chunks, stats := collectChunks(t, strings.NewReader("  one\n\ntwo  "), DocumentLoadOptions{})
+
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "one\n\ntwo", chunks[0].Content)
+	assert.Equal(t, DocumentFormatText, stats.Format)
+	assert.Equal(t, int64(12), stats.BytesRead)
+}
+
+func TestStreamDocument_LongSegmentSplitAtCharacterBoundaries(t *testing.T) {
+	text := strings.Repeat("あいうえお", 2000) // 10,000 characters, no separators
+
+	chunks, _ := collectChunks(t, iotest.HalfReader(strings.NewReader(text)), DocumentLoadOptions{ChunkSize: 300})
+
+	total := 0
+	for _, chunk := range chunks {
+		assert.True(t, utf8.ValidString(chunk.Content))
+		assert.LessOrEqual(t, chunk.CharCount, 300)
+		total += chunk.CharCount
+	}
+	assert.Equal(t, 10000, total)
+}
+
+func TestStreamDocument_MaxBytes(t *testing.T) {
+	_, err := StreamDocument(&paragraphReader{total: 1000}, DocumentLoadOptions{ChunkSize: 500, MaxBytes: 4096}, nil)
+	assert.ErrorIs(t, err, ErrDocumentTooLarge)
+
+	// Exactly at the limit is allowed
+	_, err = StreamDocument(strings.NewReader("12345"), DocumentLoadOptions{MaxBytes: 5}, nil)
+	assert.NoError(t, err)
+}
+
+func TestStreamDocument_HTML(t *testing.T) {
+	page := `<html><head><style>p { color: red; }</style><SCRIPT>var x = "<p>";</SCRIPT></head>` +
+		`<body><h1>Title</h1><p>Tom &amp; Jerry</p><p>Second<br/>line</p></body></html>`
+
+	chunks, _ := collectChunks(t, iotest.OneByteReader(strings.NewReader(page)), DocumentLoadOptions{Format: DocumentFormatHTML, ChunkSize: 100})
+
+	var contents []string
+	for _, chunk := range chunks {
+		contents = append(contents, chunk.Content)
+	}
+	joined := strings.Join(contents, "|")
+	assert.NotContains(t, joined, "color")
+	assert.NotContains(t, joined, "var x")
+	assert.NotContains(t, joined, "<")
+	assert.Contains(t, joined, "Tom & Jerry")
+	assert.Contains(t, joined, "Title")
+
+	chunks, _ = collectChunks(t, strings.NewReader(page), DocumentLoadOptions{Format: DocumentFormatHTML, KeepHTML: true})
+	assert.Contains(t, chunks[0].Content, "<SCRIPT>")
+}
+
+func TestStreamDocument_CSVRepeatsHeader(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,name,notes\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, "%d,item-%d,\"line one\nline two\"\n", i, i)
+	}
+
+	chunks, _ := collectChunks(t, strings.NewReader(b.String()), DocumentLoadOptions{Format: DocumentFormatCSV, ChunkSize: 200})
+
+	require.Greater(t, len(chunks), 1)
+	rows := 0
+	for _, chunk := range chunks {
+		assert.True(t, strings.HasPrefix(chunk.Content, "id,name,notes\n"), chunk.Content)
+		assert.LessOrEqual(t, chunk.CharCount, 200)
+		rows += strings.Count(chunk.Content, "\"line one\nline two\"")
+	}
+	assert.Equal(t, 50, rows, "quoted multi-line fields should never be split")
+}
+
+func TestStreamDocument_InvalidOptions(t *testing.T) {
+	_, err := StreamDocument(strings.NewReader("x"), DocumentLoadOptions{Format: "pdf"}, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedDocumentFormat)
+
+	_, err = StreamDocument(strings.NewReader("x"), DocumentLoadOptions{ChunkSize: 100, ChunkOverlap: 100}, nil)
+	assert.Error(t, err)
+}
+
+func TestDetectDocumentFormat(t *testing.T) {
+	tests := []struct {
+		contentType string
+		source      string
+		want        string
+		wantErr     bool
+	}{
+		{contentType: "text/html; charset=utf-8", source: "https://example.com/", want: DocumentFormatHTML},
+		{contentType: "text/csv", source: "https://example.com/export", want: DocumentFormatCSV},
+		{contentType: "application/json", source: "https://example.com/api", want: DocumentFormatJSON},
+		{contentType: "text/plain", source: "https://example.com/README.md", want: DocumentFormatMarkdown},
+		{contentType: "text/plain", source: "https://example.com/notes", want: DocumentFormatText},
+		{contentType: "", source: "https://example.com/data.csv?dl=1", want: DocumentFormatCSV},
+		{contentType: "application/octet-stream", source: "https://example.com/file", want: DocumentFormatText},
+		{contentType: "application/pdf", source: "https://example.com/manual.pdf", wantErr: true},
+		{contentType: "application/octet-stream", source: "https://example.com/report.docx", wantErr: true},
+		{contentType: "image/png", source: "https://example.com/logo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType+" "+tt.source, func(t *testing.T) {
+			got, err := DetectDocumentFormat(tt.contentType, tt.source)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedDocumentFormat)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadDocumentURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/guide.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<p>Install</p><p>Configure</p>"))
+		case "/manual.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.7"))
+		case "/big.txt":
+			w.Header().Set("Content-Length", "1000000")
+			w.Write([]byte(strings.Repeat("a", 1000000)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := NewHTTPClient(DefaultConfig().Timeout)
+
+	var chunks []DocumentChunk
+	stats, err := LoadDocumentURL(context.Background(), client, server.URL+"/guide.html", nil, DocumentLoadOptions{ChunkSize: 10}, func(chunk DocumentChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DocumentFormatHTML, stats.Format)
+	assert.Equal(t, "text/html", stats.ContentType)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "Install", chunks[0].Content)
+	assert.Equal(t, "Configure", chunks[1].Content)
+
+	_, err = LoadDocumentURL(context.Background(), client, server.URL+"/manual.pdf", nil, DocumentLoadOptions{}, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedDocumentFormat)
+
+	_, err = LoadDocumentURL(context.Background(), client, server.URL+"/big.txt", nil, DocumentLoadOptions{MaxBytes: 1000}, nil)
+	assert.ErrorIs(t, err, ErrDocumentTooLarge)
+
+	_, err = LoadDocumentURL(context.Background(), client, server.URL+"/missing", nil, DocumentLoadOptions{}, nil)
+	assert.ErrorContains(t, err, "HTTP 404")
+}
+
+func TestSandbox_DocumentsLoad(t *testing.T) {
+	sb := New(DefaultConfig())
+	code := `
+const collected = ctx.documents.load({content: input.text}, {chunk_size: 20});
+const streamed = [];
+const summary = ctx.documents.load({content: input.text}, {chunk_size: 20}, (chunk) => { streamed.push(chunk.chunk_index); });
+return {count: collected.chunk_count, first: collected.chunks[0].content, streamed: streamed, has_chunks: summary.chunks !== undefined};
+`
+	result, err := sb.Execute(context.Background(), code, map[string]interface{}{
+		"text": "first paragraph\n\nsecond paragraph\n\nthird paragraph",
+	}, &ExecutionContext{})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, result["count"])
+	assert.Equal(t, "first paragraph", result["first"])
+	assert.Len(t, result["streamed"], 3)
+	assert.Equal(t, false, result["has_chunks"])
+}
+
+func TestSandbox_DocumentsLoad_CallbackErrorStopsLoading(t *testing.T) {
+	sb := New(DefaultConfig())
+	code := `
+let calls = 0;
+try {
+  ctx.documents.load({content: input.text}, {chunk_size: 20}, () => { calls++; throw new Error('stop'); });
+} catch (e) {
+  return {calls: calls, message: String(e)};
+}
+return {calls: calls};
+`
+	result, err := sb.Execute(context.Background(), code, map[string]interface{}{
+		"text": "first paragraph\n\nsecond paragraph\n\nthird paragraph",
+	}, &ExecutionContext{})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, result["calls"])
+	assert.Contains(t, result["message"], "stop")
+}
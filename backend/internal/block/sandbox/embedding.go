@@ -15,8 +15,22 @@ import (
 type EmbeddingService interface {
 	// Embed converts texts to vector embeddings
 	Embed(provider, model string, texts []string) (*EmbeddingResult, error)
+
+	// EmbedBatch embeds any number of texts, splitting them into requests of at most batchSize
+	// texts (0 = the provider's maximum). Vectors are returned in the order of texts.
+	EmbedBatch(provider, model string, texts []string, batchSize int) (*EmbeddingResult, error)
+}
+
+// EmbeddingMaxBatchSize is the maximum number of inputs each provider accepts per request
+var EmbeddingMaxBatchSize = map[string]int{
+	"openai": 2048,
+	"cohere": 96,
+	"voyage": 128,
 }
 
+// defaultEmbeddingBatchSize is used for providers missing from EmbeddingMaxBatchSize
+const defaultEmbeddingBatchSize = 96
+
 // EmbeddingResult contains the embedding response
 type EmbeddingResult struct {
 	Vectors   [][]float32   `json:"vectors"`
@@ -83,6 +97,53 @@ func (s *EmbeddingServiceImpl) Embed(provider, model string, texts []string) (*E
 	}
 }
 
+// EmbedBatch embeds texts in provider-sized chunks and concatenates the results in order
+func (s *EmbeddingServiceImpl) EmbedBatch(provider, model string, texts []string, batchSize int) (*EmbeddingResult, error) {
+	return embedInBatches(s.Embed, provider, model, texts, batchSize)
+}
+
+// embedInBatches splits texts into chunks no larger than batchSize (capped at the provider's maximum),
+// calls embed once per chunk, and merges vectors and usage
+func embedInBatches(embed func(provider, model string, texts []string) (*EmbeddingResult, error), provider, model string, texts []string, batchSize int) (*EmbeddingResult, error) {
+	maxBatch, ok := EmbeddingMaxBatchSize[provider]
+	if !ok {
+		maxBatch = defaultEmbeddingBatchSize
+	}
+	if batchSize <= 0 || batchSize > maxBatch {
+		batchSize = maxBatch
+	}
+
+	merged := &EmbeddingResult{
+		Vectors: make([][]float32, 0, len(texts)),
+		Model:   model,
+	}
+	for start := 0; start < len(texts); start += batchSize {
+		end := start + batchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		result, err := embed(provider, model, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("embedding batch %d-%d failed: %w", start, end-1, err)
+		}
+		if len(result.Vectors) != end-start {
+			return nil, fmt.Errorf("embedding batch %d-%d returned %d vectors, expected %d", start, end-1, len(result.Vectors), end-start)
+		}
+
+		merged.Vectors = append(merged.Vectors, result.Vectors...)
+		merged.Usage.TotalTokens += result.Usage.TotalTokens
+		if result.Model != "" {
+			merged.Model = result.Model
+		}
+		if merged.Dimension == 0 {
+			merged.Dimension = result.Dimension
+		}
+	}
+
+	return merged, nil
+}
+
 // embedOpenAI calls OpenAI's embedding API
 func (s *EmbeddingServiceImpl) embedOpenAI(model string, texts []string) (*EmbeddingResult, error) {
 	apiKey := os.Getenv("OPENAI_API_KEY")
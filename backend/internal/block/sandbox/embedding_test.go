@@ -3,6 +3,7 @@ package sandbox
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -246,3 +247,97 @@ func TestEmbeddingService_MissingAPIKey(t *testing.T) {
 	assert.Contains(t, err.Error(), "not configured")
 	assert.Contains(t, err.Error(), "voyage")
 }
+
+// newBatchEmbeddingServer returns an OpenAI-compatible server that encodes each input text
+// (e.g. "t7") as the vector [7] and lists the data in reverse index order
+func newBatchEmbeddingServer(t *testing.T, batchSizes *[]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*batchSizes = append(*batchSizes, len(req.Input))
+
+		data := make([]map[string]interface{}, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			var n float32
+			_, err := fmt.Sscanf(req.Input[i], "t%g", &n)
+			require.NoError(t, err)
+			data = append(data, map[string]interface{}{"index": i, "embedding": []float32{n}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model": "text-embedding-3-small",
+			"data":  data,
+			"usage": map[string]interface{}{"total_tokens": len(req.Input)},
+		})
+	}))
+}
+
+func TestEmbeddingService_EmbedBatch_ChunksAtBoundary(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-api-key")
+
+	tests := []struct {
+		name      string
+		texts     int
+		batchSize int
+		want      []int
+	}{
+		{name: "exactly one batch", texts: 3, batchSize: 3, want: []int{3}},
+		{name: "one over the boundary", texts: 4, batchSize: 3, want: []int{3, 1}},
+		{name: "multiple full batches", texts: 6, batchSize: 3, want: []int{3, 3}},
+		{name: "batch size above provider max is capped", texts: 2050, batchSize: 5000, want: []int{2048, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batchSizes []int
+			server := newBatchEmbeddingServer(t, &batchSizes)
+			defer server.Close()
+
+			service := NewEmbeddingService(context.Background())
+			service.SetOpenAIBaseURL(server.URL)
+
+			texts := make([]string, tt.texts)
+			for i := range texts {
+				texts[i] = fmt.Sprintf("t%d", i)
+			}
+			result, err := service.EmbedBatch("openai", "text-embedding-3-small", texts, tt.batchSize)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, batchSizes)
+			require.Len(t, result.Vectors, tt.texts)
+			for i, vector := range result.Vectors {
+				require.Equal(t, []float32{float32(i)}, vector, "vector %d out of order", i)
+			}
+			assert.Equal(t, tt.texts, result.Usage.TotalTokens)
+			assert.Equal(t, 1, result.Dimension)
+		})
+	}
+}
+
+func TestEmbeddingService_EmbedBatch_DefaultsToProviderMax(t *testing.T) {
+	var batches [][]string
+	embed := func(provider, model string, texts []string) (*EmbeddingResult, error) {
+		batches = append(batches, texts)
+		return &EmbeddingResult{Vectors: make([][]float32, len(texts)), Model: model}, nil
+	}
+
+	texts := make([]string, EmbeddingMaxBatchSize["cohere"]+1)
+	result, err := embedInBatches(embed, "cohere", "embed-english-v3.0", texts, 0)
+
+	require.NoError(t, err)
+	assert.Len(t, result.Vectors, len(texts))
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 96)
+	assert.Len(t, batches[1], 1)
+}
+
+func TestEmbeddingService_EmbedBatch_VectorCountMismatch(t *testing.T) {
+	embed := func(provider, model string, texts []string) (*EmbeddingResult, error) {
+		return &EmbeddingResult{Vectors: make([][]float32, len(texts)-1)}, nil
+	}
+
+	_, err := embedInBatches(embed, "openai", "m", []string{"a", "b"}, 0)
+	assert.ErrorContains(t, err, "returned 1 vectors, expected 2")
+}
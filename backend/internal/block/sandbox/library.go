@@ -0,0 +1,95 @@
+package sandbox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+// requirePattern matches require() calls with a literal library function name
+var requirePattern = regexp.MustCompile("\\brequire\\s*\\(\\s*[\"'`]([A-Za-z][A-Za-z0-9_-]*)[\"'`]\\s*\\)")
+
+// RequiredLibraries returns the distinct names code passes to require() as string literals, in
+// order of appearance. Names built at run time are not found.
+func RequiredLibraries(code string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range requirePattern.FindAllStringSubmatch(code, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// wrapLibraryCode wraps library function code as a CommonJS module function. The code starts on
+// the first line so error positions match the lines of the library function.
+func wrapLibraryCode(code string) string {
+	return fmt.Sprintf("(function(module, exports, require) { %s\n})", code)
+}
+
+// CheckLibrarySyntax compiles library function code the way require() loads it and returns the
+// syntax error, if any
+func CheckLibrarySyntax(code string) error {
+	if _, err := goja.Compile("library", wrapLibraryCode(code), false); err != nil {
+		return sanitizeError(err)
+	}
+	return nil
+}
+
+// libraryLoader implements require() over the library functions of an execution. Each library
+// runs once; later calls return its cached module.exports.
+type libraryLoader struct {
+	vm        *goja.Runtime
+	libraries map[string]string
+	exports   map[string]goja.Value
+	loading   []string // Libraries being loaded, outermost first, to detect circular imports
+}
+
+func (l *libraryLoader) require(call goja.FunctionCall) goja.Value {
+	name := call.Argument(0).String()
+	if exports, ok := l.exports[name]; ok {
+		return exports
+	}
+	for i, loading := range l.loading {
+		if loading == name {
+			cycle := append(append([]string{}, l.loading[i:]...), name)
+			panic(l.vm.ToValue(fmt.Sprintf("circular import: %s", strings.Join(cycle, " -> "))))
+		}
+	}
+	code, ok := l.libraries[name]
+	if !ok {
+		panic(l.vm.ToValue(fmt.Sprintf("library function %q not found", name)))
+	}
+
+	program, err := goja.Compile("library:"+name, wrapLibraryCode(code), false)
+	if err != nil {
+		panic(l.vm.ToValue(fmt.Sprintf("library function %q: %v", name, sanitizeError(err))))
+	}
+	value, err := l.vm.RunProgram(program)
+	if err != nil {
+		panic(err)
+	}
+	moduleFunc, ok := goja.AssertFunction(value)
+	if !ok {
+		panic(l.vm.ToValue(fmt.Sprintf("library function %q could not be loaded", name)))
+	}
+
+	module := l.vm.NewObject()
+	exports := l.vm.NewObject()
+	_ = module.Set("exports", exports)
+	l.loading = append(l.loading, name)
+	_, err = moduleFunc(goja.Undefined(), module, exports, l.vm.ToValue(l.require))
+	l.loading = l.loading[:len(l.loading)-1]
+	if err != nil {
+		// Rethrow exceptions of the library as they are; interrupts (timeouts) stay uncatchable
+		panic(err)
+	}
+
+	result := module.Get("exports")
+	l.exports[name] = result
+	return result
+}
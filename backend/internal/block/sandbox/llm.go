@@ -91,6 +91,19 @@ func (s *LLMServiceImpl) chatOpenAI(model string, request map[string]interface{}
 		openaiReq["messages"] = messages
 	}
 
+	// Images go with the last user message as image_url content parts
+	images, err := requestImages("openai", model, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) > 0 {
+		messages := chatMessages(request["messages"])
+		if err := attachImages(messages, openAIImageParts(images)); err != nil {
+			return nil, err
+		}
+		openaiReq["messages"] = messages
+	}
+
 	// Copy optional parameters
 	if temp, ok := request["temperature"]; ok {
 		openaiReq["temperature"] = temp
@@ -104,6 +117,9 @@ func (s *LLMServiceImpl) chatOpenAI(model string, request map[string]interface{}
 	if stop, ok := request["stop"]; ok {
 		openaiReq["stop"] = stop
 	}
+	if seed, ok := request["seed"]; ok && seed != nil {
+		openaiReq["seed"] = seed
+	}
 
 	// Copy tool parameters for OpenAI Function Calling API (tools API, not legacy functions API)
 	// See: https://platform.openai.com/docs/guides/function-calling
@@ -150,8 +166,9 @@ func (s *LLMServiceImpl) chatOpenAI(model string, request map[string]interface{}
 	// Parse response with tool calls support
 	// Response struct includes: ID, Choices (with Message, FinishReason), and Usage (with token counts)
 	var respData struct {
-		ID      string `json:"id"`
-		Choices []struct {
+		ID                string `json:"id"`
+		SystemFingerprint string `json:"system_fingerprint"`
+		Choices           []struct {
 			Message struct {
 				Role       string     `json:"role"`
 				Content    string     `json:"content"`
@@ -204,6 +221,12 @@ func (s *LLMServiceImpl) chatOpenAI(model string, request map[string]interface{}
 	if len(toolCalls) > 0 {
 		result["tool_calls"] = toolCalls
 	}
+	if seed, ok := openaiReq["seed"]; ok {
+		result["seed"] = seed
+	}
+	if respData.SystemFingerprint != "" {
+		result["system_fingerprint"] = respData.SystemFingerprint
+	}
 
 	return result, nil
 }
@@ -226,6 +249,11 @@ func (s *LLMServiceImpl) chatAnthropic(model string, request map[string]interfac
 		"model": model,
 	}
 
+	images, err := requestImages("anthropic", model, request)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert messages format (Anthropic requires system message separate)
 	// Support both []interface{} and []map[string]interface{} types
 	var rawMessages []interface{}
@@ -338,6 +366,14 @@ func (s *LLMServiceImpl) chatAnthropic(model string, request map[string]interfac
 		anthropicReq["messages"] = anthropicMsgs
 	}
 
+	// Images go with the last user message as image content blocks
+	if len(images) > 0 {
+		messages, _ := anthropicReq["messages"].([]map[string]interface{})
+		if err := attachImages(messages, anthropicImageBlocks(images)); err != nil {
+			return nil, err
+		}
+	}
+
 	// Max tokens is required for Anthropic
 	if maxTokens, ok := request["max_tokens"]; ok {
 		anthropicReq["max_tokens"] = maxTokens
@@ -0,0 +1,97 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/souta/ai-orchestration/internal/adapter"
+)
+
+// requestImages reads the images of a chat request (URLs, data: URIs or {url} / {data, media_type}
+// objects) and checks that the model accepts image input
+func requestImages(provider, model string, request map[string]interface{}) (adapter.ImageInputs, error) {
+	raw, ok := request["images"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid images: %w", err)
+	}
+	var images adapter.ImageInputs
+	if err := json.Unmarshal(data, &images); err != nil {
+		return nil, fmt.Errorf("invalid images: %w", err)
+	}
+	if err := adapter.ValidateImages(images, provider, model); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// chatMessages returns the messages of a chat request as maps
+func chatMessages(value interface{}) []map[string]interface{} {
+	switch msgs := value.(type) {
+	case []map[string]interface{}:
+		return msgs
+	case []interface{}:
+		messages := make([]map[string]interface{}, 0, len(msgs))
+		for _, m := range msgs {
+			if msg, ok := m.(map[string]interface{}); ok {
+				messages = append(messages, msg)
+			}
+		}
+		return messages
+	}
+	return nil
+}
+
+// attachImages replaces the text content of the last user message with the content built by parts
+func attachImages(messages []map[string]interface{}, parts func(text string) []map[string]interface{}) error {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if role, _ := messages[i]["role"].(string); role != "user" {
+			continue
+		}
+		text, ok := messages[i]["content"].(string)
+		if !ok {
+			return fmt.Errorf("images: the last user message must have text content")
+		}
+		msg := make(map[string]interface{}, len(messages[i]))
+		for k, v := range messages[i] {
+			msg[k] = v
+		}
+		msg["content"] = parts(text)
+		messages[i] = msg
+		return nil
+	}
+	return fmt.Errorf("images: a user message is required")
+}
+
+// openAIImageParts returns the content parts of a user message with images for OpenAI
+func openAIImageParts(images adapter.ImageInputs) func(text string) []map[string]interface{} {
+	return func(text string) []map[string]interface{} {
+		parts := []map[string]interface{}{{"type": "text", "text": text}}
+		for _, image := range images {
+			parts = append(parts, map[string]interface{}{
+				"type":      "image_url",
+				"image_url": map[string]interface{}{"url": image.DataURI()},
+			})
+		}
+		return parts
+	}
+}
+
+// anthropicImageBlocks returns the content blocks of a user message with images for Anthropic,
+// which recommends placing images before the text
+func anthropicImageBlocks(images adapter.ImageInputs) func(text string) []map[string]interface{} {
+	return func(text string) []map[string]interface{} {
+		blocks := make([]map[string]interface{}, 0, len(images)+1)
+		for _, image := range images {
+			source := map[string]interface{}{"type": "url", "url": image.URL}
+			if image.URL == "" {
+				source = map[string]interface{}{"type": "base64", "media_type": image.MediaType, "data": image.Data}
+			}
+			blocks = append(blocks, map[string]interface{}{"type": "image", "source": source})
+		}
+		return append(blocks, map[string]interface{}{"type": "text", "text": text})
+	}
+}
@@ -0,0 +1,91 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLLMService_Chat_OpenAIImages(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-api-key")
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "a cat"}}], "usage": {"prompt_tokens": 800, "completion_tokens": 2, "total_tokens": 802}}`))
+	}))
+	defer server.Close()
+
+	service := NewLLMService(context.Background())
+	service.openaiBaseURL = server.URL
+	result, err := service.Chat("openai", "gpt-4o-mini", map[string]interface{}{
+		"messages": []interface{}{
+			map[string]interface{}{"role": "system", "content": "Describe images briefly."},
+			map[string]interface{}{"role": "user", "content": "What is this?"},
+		},
+		"images": []interface{}{"https://example.com/cat.png"},
+	})
+
+	require.NoError(t, err)
+	messages := body["messages"].([]interface{})
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Describe images briefly.", messages[0].(map[string]interface{})["content"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "text", "text": "What is this?"},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "https://example.com/cat.png"}},
+	}, messages[1].(map[string]interface{})["content"])
+	assert.NotContains(t, body, "images")
+	assert.Equal(t, map[string]interface{}{"input_tokens": 800, "output_tokens": 2, "total_tokens": 802}, result["usage"])
+}
+
+func TestLLMService_Chat_AnthropicImages(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-api-key")
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "text", "text": "a cat"}], "stop_reason": "end_turn", "usage": {"input_tokens": 900, "output_tokens": 3}}`))
+	}))
+	defer server.Close()
+
+	service := NewLLMService(context.Background())
+	service.anthropicBaseURL = server.URL
+	result, err := service.Chat("anthropic", "", map[string]interface{}{
+		"messages": []interface{}{
+			map[string]interface{}{"role": "user", "content": "What is this?"},
+		},
+		"images": []interface{}{map[string]interface{}{"data": "iVBORw0KGgo=", "media_type": "image/png"}},
+	})
+
+	require.NoError(t, err)
+	messages := body["messages"].([]interface{})
+	require.Len(t, messages, 1)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "image", "source": map[string]interface{}{"type": "base64", "media_type": "image/png", "data": "iVBORw0KGgo="}},
+		map[string]interface{}{"type": "text", "text": "What is this?"},
+	}, messages[0].(map[string]interface{})["content"])
+	assert.Equal(t, map[string]interface{}{"input_tokens": 900, "output_tokens": 3, "total_tokens": 903}, result["usage"])
+}
+
+func TestLLMService_Chat_ImagesRejectedForTextModel(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-api-key")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for a model without vision support")
+	}))
+	defer server.Close()
+
+	service := NewLLMService(context.Background())
+	service.openaiBaseURL = server.URL
+	_, err := service.Chat("openai", "gpt-4", map[string]interface{}{
+		"messages": []interface{}{map[string]interface{}{"role": "user", "content": "What is this?"}},
+		"images":   []interface{}{"https://example.com/cat.png"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `model "gpt-4" does not support image input`)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/dop251/goja"
 	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/netguard"
 )
 
 // Errors
@@ -213,6 +215,15 @@ type ExecutionContext struct {
 	// e.g., context.credentials.api_key.access_token
 	Credentials map[string]interface{}
 	Logger      func(args ...interface{})
+	// Progress receives progress reports from ctx.progress(percent, message) while the script
+	// runs. Reports are informational only and never change the script's result. Optional.
+	Progress func(percent float64, message string)
+	// Trigger describes how the run was triggered (see domain.Run.TriggerInfo). Accessible in
+	// scripts as ctx.trigger, e.g. ctx.trigger.type. Optional.
+	Trigger map[string]interface{}
+	// Libraries maps the names of tenant library functions to their code. When set, scripts load
+	// them with require(name), which returns the library's module.exports. Optional.
+	Libraries map[string]string
 	// TargetProjectID is the project ID that Copilot tools operate on
 	// This is set from the workflow input (workflow_id parameter) and allows
 	// tools to automatically use the current project without requiring explicit project_id
@@ -302,6 +313,14 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 		}
 	}
 
+	// require() loads only the tenant library functions given in the execution context
+	if execCtx != nil && execCtx.Libraries != nil {
+		loader := &libraryLoader{vm: vm, libraries: execCtx.Libraries, exports: make(map[string]goja.Value)}
+		if err := vm.Set("require", loader.require); err != nil {
+			return err
+		}
+	}
+
 	// SECURITY: Block eval and Function constructor to prevent dynamic code execution
 	// Create a dummy that throws an error when called
 	blockedFunc := func(call goja.FunctionCall) goja.Value {
@@ -432,6 +451,11 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 		}); err != nil {
 			return err
 		}
+		if err := embeddingObj.Set("embedBatch", func(call goja.FunctionCall) goja.Value {
+			return s.embeddingEmbedBatch(vm, execCtx.Embedding, call)
+		}); err != nil {
+			return err
+		}
 		if err := contextObj.Set("embedding", embeddingObj); err != nil {
 			return err
 		}
@@ -644,11 +668,27 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 		}); err != nil {
 			return err
 		}
+		if err := searchObj.Set("provider", execCtx.Search.Provider()); err != nil {
+			return err
+		}
 		if err := contextObj.Set("search", searchObj); err != nil {
 			return err
 		}
 	}
 
+	// Add document loading (streams url or inline content through the text splitter)
+	if execCtx != nil {
+		documentsObj := vm.NewObject()
+		if err := documentsObj.Set("load", func(call goja.FunctionCall) goja.Value {
+			return s.documentsLoad(vm, execCtx.HTTP, call)
+		}); err != nil {
+			return err
+		}
+		if err := contextObj.Set("documents", documentsObj); err != nil {
+			return err
+		}
+	}
+
 	// Add credentials map if available
 	if execCtx != nil && execCtx.Credentials != nil {
 		if err := contextObj.Set("credentials", execCtx.Credentials); err != nil {
@@ -656,6 +696,13 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 		}
 	}
 
+	// Add trigger metadata of the run
+	if execCtx != nil && execCtx.Trigger != nil {
+		if err := contextObj.Set("trigger", execCtx.Trigger); err != nil {
+			return err
+		}
+	}
+
 	// Add targetProjectId for Copilot tools (allows tools to operate on the current project)
 	if execCtx != nil && execCtx.TargetProjectID != "" {
 		if err := contextObj.Set("targetProjectId", execCtx.TargetProjectID); err != nil {
@@ -663,6 +710,11 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 		}
 	}
 
+	// Add the deployment default timezone (DEFAULT_TIMEZONE) for scripts that build dates or schedules
+	if err := contextObj.Set("defaultTimezone", domain.DefaultTimezone()); err != nil {
+		return err
+	}
+
 	// Add logger
 	if execCtx != nil && execCtx.Logger != nil {
 		if err := contextObj.Set("log", execCtx.Logger); err != nil {
@@ -675,6 +727,26 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 		}
 	}
 
+	// Add progress reporting (no-op when no receiver is configured)
+	if err := contextObj.Set("progress", func(call goja.FunctionCall) goja.Value {
+		if execCtx == nil || execCtx.Progress == nil {
+			return goja.Undefined()
+		}
+		percent := call.Argument(0).ToFloat()
+		if math.IsNaN(percent) {
+			percent = 0
+		}
+		percent = math.Max(0, math.Min(100, percent))
+		message := ""
+		if arg := call.Argument(1); !goja.IsUndefined(arg) && !goja.IsNull(arg) {
+			message = arg.String()
+		}
+		execCtx.Progress(percent, message)
+		return goja.Undefined()
+	}); err != nil {
+		return err
+	}
+
 	if err := vm.Set("context", contextObj); err != nil {
 		return err
 	}
@@ -704,6 +776,11 @@ func (s *Sandbox) setupGlobals(vm *goja.Runtime, input map[string]interface{}, e
 
 // wrapCode wraps user code in a function structure
 func (s *Sandbox) wrapCode(code string) string {
+	return wrapUserCode(code)
+}
+
+// wrapUserCode wraps user code the way the sandbox runs it
+func wrapUserCode(code string) string {
 	// Check if code already defines an execute function
 	if strings.Contains(code, "function execute") || strings.Contains(code, "async function execute") {
 		// User provided an execute function, call it
@@ -811,6 +888,15 @@ func NewHTTPClientWithContext(ctx context.Context, timeout time.Duration) *HTTPC
 	}
 }
 
+// WithGuard makes the client refuse connections to internal addresses not allowed by guard
+// (SSRF protection). A nil guard leaves the client unrestricted.
+func (c *HTTPClient) WithGuard(guard *netguard.Guard) *HTTPClient {
+	if guard != nil {
+		c.client.Transport = guard.Transport()
+	}
+	return c
+}
+
 // Context returns the context associated with this client
 func (c *HTTPClient) Context() context.Context {
 	if c.ctx == nil {
@@ -839,6 +925,37 @@ func (c *HTTPClient) getHeaders() map[string]string {
 
 // Request performs an HTTP request with context support for cancellation and timeout
 func (c *HTTPClient) Request(ctx context.Context, method, url string, body interface{}, options map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := c.Open(ctx, method, url, body, options)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status":     resp.StatusCode,
+		"statusText": resp.Status,
+		"headers":    headersToMap(resp.Header),
+	}
+
+	// Try to parse JSON response
+	var jsonData interface{}
+	if err := json.Unmarshal(respBody, &jsonData); err == nil {
+		result["data"] = jsonData
+	} else {
+		result["data"] = string(respBody)
+	}
+
+	return result, nil
+}
+
+// Open performs an HTTP request and returns the response with its body unread.
+// The caller must close the body; used to stream large responses.
+func (c *HTTPClient) Open(ctx context.Context, method, url string, body interface{}, options map[string]interface{}) (*http.Response, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -891,28 +1008,7 @@ func (c *HTTPClient) Request(ctx context.Context, method, url string, body inter
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	result := map[string]interface{}{
-		"status":     resp.StatusCode,
-		"statusText": resp.Status,
-		"headers":    headersToMap(resp.Header),
-	}
-
-	// Try to parse JSON response
-	var jsonData interface{}
-	if err := json.Unmarshal(respBody, &jsonData); err == nil {
-		result["data"] = jsonData
-	} else {
-		result["data"] = string(respBody)
-	}
-
-	return result, nil
+	return resp, nil
 }
 
 // headersToMap converts http.Header to map[string]string
@@ -1178,28 +1274,57 @@ func (s *Sandbox) embeddingEmbed(vm *goja.Runtime, service EmbeddingService, cal
 
 	provider := call.Arguments[0].String()
 	model := call.Arguments[1].String()
+	texts := embeddingTexts(vm, "ctx.embedding.embed", call.Arguments[2])
 
-	// Handle both single string and array of strings
-	textsArg := call.Arguments[2].Export()
-	var texts []string
+	result, err := service.Embed(provider, model, texts)
+	if err != nil {
+		panic(vm.ToValue(fmt.Sprintf("Embedding failed: %v", err)))
+	}
+
+	return embeddingResultValue(vm, result)
+}
 
-	switch v := textsArg.(type) {
+// embeddingEmbedBatch handles ctx.embedding.embedBatch(provider, model, texts, batchSize?) calls.
+// Texts are sent in chunks of at most batchSize (default: provider maximum); vectors keep input order.
+func (s *Sandbox) embeddingEmbedBatch(vm *goja.Runtime, service EmbeddingService, call goja.FunctionCall) goja.Value {
+	if len(call.Arguments) < 3 {
+		panic(vm.ToValue("ctx.embedding.embedBatch requires provider, model, and texts arguments"))
+	}
+
+	provider := call.Arguments[0].String()
+	model := call.Arguments[1].String()
+	texts := embeddingTexts(vm, "ctx.embedding.embedBatch", call.Arguments[2])
+	batchSize := 0
+	if len(call.Arguments) > 3 && !goja.IsUndefined(call.Arguments[3]) && !goja.IsNull(call.Arguments[3]) {
+		batchSize = int(call.Arguments[3].ToInteger())
+	}
+
+	result, err := service.EmbedBatch(provider, model, texts, batchSize)
+	if err != nil {
+		panic(vm.ToValue(fmt.Sprintf("Embedding failed: %v", err)))
+	}
+
+	return embeddingResultValue(vm, result)
+}
+
+// embeddingTexts accepts a single string or an array of strings
+func embeddingTexts(vm *goja.Runtime, fn string, arg goja.Value) []string {
+	switch v := arg.Export().(type) {
 	case string:
-		texts = []string{v}
+		return []string{v}
 	case []interface{}:
-		texts = make([]string, len(v))
+		texts := make([]string, len(v))
 		for i, t := range v {
 			texts[i] = fmt.Sprintf("%v", t)
 		}
+		return texts
 	default:
-		panic(vm.ToValue("ctx.embedding.embed texts must be a string or array of strings"))
-	}
-
-	result, err := service.Embed(provider, model, texts)
-	if err != nil {
-		panic(vm.ToValue(fmt.Sprintf("Embedding failed: %v", err)))
+		panic(vm.ToValue(fn + " texts must be a string or array of strings"))
 	}
+}
 
+// embeddingResultValue converts an EmbeddingResult to a JS object
+func embeddingResultValue(vm *goja.Runtime, result *EmbeddingResult) goja.Value {
 	// Convert to JS-compatible format
 	vectors := make([]interface{}, len(result.Vectors))
 	for i, v := range result.Vectors {
@@ -1671,6 +1796,110 @@ func (s *Sandbox) edgesDelete(vm *goja.Runtime, service EdgesService, call goja.
 	return vm.ToValue(map[string]interface{}{"success": true})
 }
 
+// ============================================================================
+// Document Loading Methods
+// ============================================================================
+
+// documentsLoad handles ctx.documents.load(source, options, onChunk) calls
+// source: {url, headers} or {content}
+// options: {format, max_bytes, chunk_size, chunk_overlap, separator, strip_html}
+// When onChunk is given, each chunk is passed to it as soon as it is read and chunks are not
+// collected, so arbitrarily large documents are processed in bounded memory.
+func (s *Sandbox) documentsLoad(vm *goja.Runtime, client *HTTPClient, call goja.FunctionCall) goja.Value {
+	if len(call.Arguments) < 1 {
+		panic(vm.ToValue("ctx.documents.load requires a source argument"))
+	}
+	source, ok := call.Arguments[0].Export().(map[string]interface{})
+	if !ok {
+		panic(vm.ToValue("ctx.documents.load source must be an object with url or content"))
+	}
+
+	opts := DocumentLoadOptions{}
+	if len(call.Arguments) > 1 {
+		if options, ok := call.Arguments[1].Export().(map[string]interface{}); ok {
+			opts = documentLoadOptionsFromMap(options)
+		}
+	}
+
+	var chunks []interface{}
+	emit := func(chunk DocumentChunk) error {
+		chunks = append(chunks, map[string]interface{}{
+			"content":     chunk.Content,
+			"chunk_index": chunk.Index,
+			"char_count":  chunk.CharCount,
+		})
+		return nil
+	}
+	onChunk, hasCallback := goja.AssertFunction(call.Argument(2))
+	if hasCallback {
+		emit = func(chunk DocumentChunk) error {
+			_, err := onChunk(goja.Undefined(), vm.ToValue(map[string]interface{}{
+				"content":     chunk.Content,
+				"chunk_index": chunk.Index,
+				"char_count":  chunk.CharCount,
+			}))
+			return err
+		}
+	}
+
+	var stats *DocumentStats
+	var err error
+	if rawURL, ok := source["url"].(string); ok && rawURL != "" {
+		if client == nil {
+			panic(vm.ToValue("ctx.documents.load: HTTP client not available"))
+		}
+		var options map[string]interface{}
+		if headers, ok := source["headers"].(map[string]interface{}); ok {
+			options = map[string]interface{}{"headers": headers}
+		}
+		stats, err = LoadDocumentURL(client.Context(), client, rawURL, options, opts, emit)
+	} else if content, ok := source["content"].(string); ok {
+		stats, err = StreamDocument(strings.NewReader(content), opts, emit)
+	} else {
+		panic(vm.ToValue("ctx.documents.load source must have url or content"))
+	}
+	if err != nil {
+		// Errors thrown by onChunk are rethrown unchanged
+		var jsErr *goja.Exception
+		if errors.As(err, &jsErr) {
+			panic(jsErr)
+		}
+		panic(vm.ToValue(fmt.Sprintf("Document load failed: %v", err)))
+	}
+
+	result := map[string]interface{}{
+		"format":       stats.Format,
+		"content_type": stats.ContentType,
+		"bytes_read":   stats.BytesRead,
+		"chunk_count":  stats.ChunkCount,
+	}
+	if !hasCallback {
+		if chunks == nil {
+			chunks = []interface{}{}
+		}
+		result["chunks"] = chunks
+	}
+	return vm.ToValue(result)
+}
+
+// documentLoadOptionsFromMap reads ctx.documents.load options
+func documentLoadOptionsFromMap(options map[string]interface{}) DocumentLoadOptions {
+	opts := DocumentLoadOptions{}
+	if format, ok := options["format"].(string); ok && format != "auto" {
+		opts.Format = format
+	}
+	if separator, ok := options["separator"].(string); ok {
+		opts.Separator = separator
+	}
+	if stripHTML, ok := options["strip_html"].(bool); ok {
+		opts.KeepHTML = !stripHTML
+	}
+	opts.MaxBytes = int64(toFloat64(options["max_bytes"]))
+	opts.ChunkSize = int(toFloat64(options["chunk_size"]))
+	opts.ChunkOverlap = int(toFloat64(options["chunk_overlap"]))
+	return opts
+}
+
 // ============================================================================
 // Search Service Methods (Web search for Copilot)
 // ============================================================================
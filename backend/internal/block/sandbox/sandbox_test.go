@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/netguard"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -136,6 +137,48 @@ return { done: true };
 	assert.Contains(t, logged, "context log")
 }
 
+func TestSandbox_Execute_WithProgress(t *testing.T) {
+	sb := New(DefaultConfig())
+
+	type report struct {
+		percent float64
+		message string
+	}
+	var reports []report
+	execCtx := &ExecutionContext{
+		Progress: func(percent float64, message string) {
+			reports = append(reports, report{percent, message})
+		},
+	}
+
+	code := `
+ctx.progress(10, "loading");
+context.progress(150);
+ctx.progress(-5, "rewind");
+return { done: true };
+`
+
+	result, err := sb.Execute(context.Background(), code, map[string]interface{}{}, execCtx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"done": true}, result)
+	assert.Equal(t, []report{{10, "loading"}, {100, ""}, {0, "rewind"}}, reports)
+}
+
+func TestSandbox_Execute_ProgressWithoutReceiver(t *testing.T) {
+	sb := New(DefaultConfig())
+
+	code := `
+var returned = ctx.progress(50, "halfway");
+return { returned: returned === undefined };
+`
+
+	for _, execCtx := range []*ExecutionContext{nil, {}} {
+		result, err := sb.Execute(context.Background(), code, map[string]interface{}{}, execCtx)
+		require.NoError(t, err)
+		assert.Equal(t, true, result["returned"])
+	}
+}
+
 func TestSandbox_Execute_ComplexDataTransformation(t *testing.T) {
 	sb := New(DefaultConfig())
 
@@ -206,6 +249,29 @@ function execute(input, context) {
 	assert.Equal(t, "Hello from server", result["message"])
 }
 
+func TestSandbox_Execute_HTTP_BlocksInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	sb := New(DefaultConfig())
+
+	execCtx := &ExecutionContext{
+		HTTP: NewHTTPClient(10 * time.Second).WithGuard(netguard.New()),
+	}
+
+	code := `
+function execute(input, context) {
+	return context.http.get(input.url);
+}
+`
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data/", server.URL} {
+		_, err := sb.Execute(context.Background(), code, map[string]interface{}{"url": url}, execCtx)
+		require.Error(t, err, url)
+		assert.Contains(t, err.Error(), "blocked: internal address", url)
+	}
+}
+
 func TestSandbox_Execute_HTTP_POST(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -4,15 +4,41 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// SearchService provides web search capabilities to scripts using Tavily API
+// Search providers selectable with SEARCH_PROVIDER
+const (
+	SearchProviderTavily  = "tavily"
+	SearchProviderBing    = "bing"
+	SearchProviderGoogle  = "google"
+	SearchProviderSerpAPI = "serpapi"
+)
+
+// SearchProviders lists the supported search providers
+var SearchProviders = []string{SearchProviderTavily, SearchProviderBing, SearchProviderGoogle, SearchProviderSerpAPI}
+
+// SearchProvider is a web search backend. Providers return results in the common SearchResult
+// shape, so scripts see the same ctx.search whichever backend is configured.
+type SearchProvider interface {
+	// Name returns the provider name, e.g. tavily
+	Name() string
+	// Search returns up to numResults results for a non-empty query
+	Search(query string, numResults int) ([]SearchResult, error)
+	// IsConfigured reports whether the provider has the credentials it needs
+	IsConfigured() bool
+}
+
+// SearchService provides web search capabilities to scripts through a SearchProvider
 type SearchService struct {
-	apiKey string
-	client *http.Client
+	provider SearchProvider
+	err      error // Why no provider is set, reported by Search
 }
 
 // SearchResult represents a single search result
@@ -22,47 +48,60 @@ type SearchResult struct {
 	Snippet string `json:"snippet"`
 }
 
-// NewSearchService creates a new SearchService
-// Reads configuration from environment variable:
-// - TAVILY_API_KEY: Tavily API key
+// NewSearchService creates a SearchService with the provider named by SEARCH_PROVIDER (default
+// tavily), configured from its environment variables:
+// - tavily: TAVILY_API_KEY
+// - bing: BING_SEARCH_API_KEY (and optionally BING_SEARCH_ENDPOINT)
+// - google: GOOGLE_SEARCH_API_KEY and GOOGLE_SEARCH_ENGINE_ID
+// - serpapi: SERPAPI_API_KEY
 func NewSearchService() *SearchService {
-	return &SearchService{
-		apiKey: os.Getenv("TAVILY_API_KEY"),
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	return NewSearchServiceFor(os.Getenv("SEARCH_PROVIDER"))
+}
+
+// NewSearchServiceFor creates a SearchService with the named provider, configured from its
+// environment variables. An empty name selects tavily. An unknown name is logged and leaves the
+// service unconfigured.
+func NewSearchServiceFor(name string) *SearchService {
+	provider, err := NewSearchProviderFromEnv(name)
+	if err != nil {
+		slog.Warn("Invalid search provider, web search is disabled", "provider", name, "error", err)
+		return &SearchService{err: err}
 	}
+	return NewSearchServiceWithProvider(provider)
 }
 
-// tavilyRequest represents the request body for Tavily API
-type tavilyRequest struct {
-	APIKey            string `json:"api_key"`
-	Query             string `json:"query"`
-	SearchDepth       string `json:"search_depth"`
-	IncludeAnswer     bool   `json:"include_answer"`
-	IncludeRawContent bool   `json:"include_raw_content"`
-	MaxResults        int    `json:"max_results"`
+// NewSearchServiceWithProvider creates a SearchService backed by provider
+func NewSearchServiceWithProvider(provider SearchProvider) *SearchService {
+	return &SearchService{provider: provider}
 }
 
-// tavilyResponse represents the response from Tavily API
-type tavilyResponse struct {
-	Query   string `json:"query"`
-	Results []struct {
-		Title   string  `json:"title"`
-		URL     string  `json:"url"`
-		Content string  `json:"content"`
-		Score   float64 `json:"score"`
-	} `json:"results"`
-	Error string `json:"error,omitempty"`
+// NewSearchProviderFromEnv creates the named provider from its environment variables
+func NewSearchProviderFromEnv(name string) (SearchProvider, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", SearchProviderTavily:
+		return &tavilyProvider{apiKey: os.Getenv("TAVILY_API_KEY"), endpoint: "https://api.tavily.com/search", client: client}, nil
+	case SearchProviderBing:
+		return &bingProvider{apiKey: os.Getenv("BING_SEARCH_API_KEY"), endpoint: getEnvOrDefault("BING_SEARCH_ENDPOINT", "https://api.bing.microsoft.com/v7.0/search"), client: client}, nil
+	case SearchProviderGoogle:
+		return &googleProvider{apiKey: os.Getenv("GOOGLE_SEARCH_API_KEY"), engineID: os.Getenv("GOOGLE_SEARCH_ENGINE_ID"), endpoint: "https://www.googleapis.com/customsearch/v1", client: client}, nil
+	case SearchProviderSerpAPI:
+		return &serpAPIProvider{apiKey: os.Getenv("SERPAPI_API_KEY"), endpoint: "https://serpapi.com/search.json", client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown search provider %q (supported: %s)", name, strings.Join(SearchProviders, ", "))
+	}
 }
 
-// Search performs a web search using Tavily API
+// Search performs a web search with the configured provider
 // query: the search query string
 // numResults: number of results to return (1-10, default 5)
 // Returns a slice of SearchResult or error
 func (s *SearchService) Search(query string, numResults int) ([]SearchResult, error) {
-	if s.apiKey == "" {
-		return nil, fmt.Errorf("Tavily API not configured. Set TAVILY_API_KEY environment variable")
+	if s.provider == nil {
+		return nil, fmt.Errorf("web search not configured: %v", s.err)
+	}
+	if !s.provider.IsConfigured() {
+		return nil, fmt.Errorf("%s search is not configured. Set its API key environment variable", s.provider.Name())
 	}
 
 	if query == "" {
@@ -74,64 +113,220 @@ func (s *SearchService) Search(query string, numResults int) ([]SearchResult, er
 		numResults = 5
 	}
 
-	// Build the request body
-	reqBody := tavilyRequest{
-		APIKey:            s.apiKey,
-		Query:             query,
-		SearchDepth:       "basic",
-		IncludeAnswer:     false,
-		IncludeRawContent: false,
-		MaxResults:        numResults,
+	results, err := s.provider.Search(query, numResults)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > numResults {
+		results = results[:numResults]
 	}
+	return results, nil
+}
 
-	reqJSON, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// IsConfigured returns true if the search provider has valid configuration
+func (s *SearchService) IsConfigured() bool {
+	return s.provider != nil && s.provider.IsConfigured()
+}
+
+// Provider returns the name of the search provider, or "" when none is set
+func (s *SearchService) Provider() string {
+	if s.provider == nil {
+		return ""
 	}
+	return s.provider.Name()
+}
 
-	// Make the request
-	req, err := http.NewRequest("POST", "https://api.tavily.com/search", bytes.NewReader(reqJSON))
+// searchGet sends a GET search request and decodes the JSON response into out
+func searchGet(client *http.Client, endpoint string, query url.Values, header http.Header, out interface{}) error {
+	req, err := http.NewRequest("GET", endpoint+"?"+query.Encode(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return doSearchRequest(client, req, out)
+}
 
-	resp, err := s.client.Do(req)
+// doSearchRequest sends a search request and decodes the JSON response into out
+func doSearchRequest(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("search request failed: %w", err)
+		return fmt.Errorf("search request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check for HTTP errors
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search API returned status %d", resp.StatusCode)
+		return fmt.Errorf("search API returned status %d", resp.StatusCode)
 	}
 
-	// Parse the response
-	var apiResponse tavilyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse search response: %w", err)
+	}
+	return nil
+}
+
+// tavilyProvider searches with the Tavily API
+type tavilyProvider struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// tavilyRequest represents the request body for Tavily API
+type tavilyRequest struct {
+	APIKey            string `json:"api_key"`
+	Query             string `json:"query"`
+	SearchDepth       string `json:"search_depth"`
+	IncludeAnswer     bool   `json:"include_answer"`
+	IncludeRawContent bool   `json:"include_raw_content"`
+	MaxResults        int    `json:"max_results"`
+}
+
+// tavilyResponse represents the response from Tavily API
+type tavilyResponse struct {
+	Query   string `json:"query"`
+	Results []struct {
+		Title   string  `json:"title"`
+		URL     string  `json:"url"`
+		Content string  `json:"content"`
+		Score   float64 `json:"score"`
+	} `json:"results"`
+	Error string `json:"error,omitempty"`
+}
+
+func (p *tavilyProvider) Name() string       { return SearchProviderTavily }
+func (p *tavilyProvider) IsConfigured() bool { return p.apiKey != "" }
+
+func (p *tavilyProvider) Search(query string, numResults int) ([]SearchResult, error) {
+	reqJSON, err := json.Marshal(tavilyRequest{
+		APIKey:      p.apiKey,
+		Query:       query,
+		SearchDepth: "basic",
+		MaxResults:  numResults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequest("POST", p.endpoint, bytes.NewReader(reqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
+	var apiResponse tavilyResponse
+	if err := doSearchRequest(p.client, req, &apiResponse); err != nil {
+		return nil, err
+	}
 	// Check for API-level errors
 	if apiResponse.Error != "" {
 		return nil, fmt.Errorf("search API error: %s", apiResponse.Error)
 	}
 
-	// Convert to SearchResult slice
 	results := make([]SearchResult, len(apiResponse.Results))
 	for i, item := range apiResponse.Results {
-		results[i] = SearchResult{
-			Title:   item.Title,
-			URL:     item.URL,
-			Snippet: item.Content,
-		}
+		results[i] = SearchResult{Title: item.Title, URL: item.URL, Snippet: item.Content}
+	}
+	return results, nil
+}
+
+// bingProvider searches with the Bing Web Search API
+type bingProvider struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+func (p *bingProvider) Name() string       { return SearchProviderBing }
+func (p *bingProvider) IsConfigured() bool { return p.apiKey != "" }
+
+func (p *bingProvider) Search(query string, numResults int) ([]SearchResult, error) {
+	var apiResponse struct {
+		WebPages struct {
+			Value []struct {
+				Name    string `json:"name"`
+				URL     string `json:"url"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	params := url.Values{"q": {query}, "count": {strconv.Itoa(numResults)}}
+	header := http.Header{"Ocp-Apim-Subscription-Key": {p.apiKey}}
+	if err := searchGet(p.client, p.endpoint, params, header, &apiResponse); err != nil {
+		return nil, err
 	}
 
+	results := make([]SearchResult, len(apiResponse.WebPages.Value))
+	for i, item := range apiResponse.WebPages.Value {
+		results[i] = SearchResult{Title: item.Name, URL: item.URL, Snippet: item.Snippet}
+	}
 	return results, nil
 }
 
-// IsConfigured returns true if the search service has valid configuration
-func (s *SearchService) IsConfigured() bool {
-	return s.apiKey != ""
+// googleProvider searches with the Google Custom Search JSON API
+type googleProvider struct {
+	apiKey   string
+	engineID string // Programmable Search Engine ID (cx)
+	endpoint string
+	client   *http.Client
+}
+
+func (p *googleProvider) Name() string       { return SearchProviderGoogle }
+func (p *googleProvider) IsConfigured() bool { return p.apiKey != "" && p.engineID != "" }
+
+func (p *googleProvider) Search(query string, numResults int) ([]SearchResult, error) {
+	var apiResponse struct {
+		Items []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"items"`
+	}
+	params := url.Values{"key": {p.apiKey}, "cx": {p.engineID}, "q": {query}, "num": {strconv.Itoa(numResults)}}
+	if err := searchGet(p.client, p.endpoint, params, nil, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, len(apiResponse.Items))
+	for i, item := range apiResponse.Items {
+		results[i] = SearchResult{Title: item.Title, URL: item.Link, Snippet: item.Snippet}
+	}
+	return results, nil
+}
+
+// serpAPIProvider searches Google through SerpAPI
+type serpAPIProvider struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+func (p *serpAPIProvider) Name() string       { return SearchProviderSerpAPI }
+func (p *serpAPIProvider) IsConfigured() bool { return p.apiKey != "" }
+
+func (p *serpAPIProvider) Search(query string, numResults int) ([]SearchResult, error) {
+	var apiResponse struct {
+		OrganicResults []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"organic_results"`
+		Error string `json:"error,omitempty"`
+	}
+	params := url.Values{"engine": {"google"}, "q": {query}, "num": {strconv.Itoa(numResults)}, "api_key": {p.apiKey}}
+	if err := searchGet(p.client, p.endpoint, params, nil, &apiResponse); err != nil {
+		return nil, err
+	}
+	if apiResponse.Error != "" {
+		return nil, fmt.Errorf("search API error: %s", apiResponse.Error)
+	}
+
+	results := make([]SearchResult, len(apiResponse.OrganicResults))
+	for i, item := range apiResponse.OrganicResults {
+		results[i] = SearchResult{Title: item.Title, URL: item.Link, Snippet: item.Snippet}
+	}
+	return results, nil
 }
@@ -0,0 +1,173 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSearchProvider returns fixed results and records the queries it receives
+type mockSearchProvider struct {
+	name       string
+	configured bool
+	results    []SearchResult
+	queries    []string
+}
+
+func (p *mockSearchProvider) Name() string       { return p.name }
+func (p *mockSearchProvider) IsConfigured() bool { return p.configured }
+
+func (p *mockSearchProvider) Search(query string, numResults int) ([]SearchResult, error) {
+	p.queries = append(p.queries, query)
+	return p.results, nil
+}
+
+var testSearchResults = []SearchResult{
+	{Title: "Go", URL: "https://go.dev", Snippet: "The Go programming language"},
+	{Title: "Go docs", URL: "https://go.dev/doc", Snippet: "Documentation"},
+}
+
+func TestSandbox_Search_MockProvider(t *testing.T) {
+	sb := New(DefaultConfig())
+	provider := &mockSearchProvider{name: "mock", configured: true, results: testSearchResults}
+
+	code := `
+const results = ctx.search.search(input.query, 1);
+return { configured: ctx.search.isConfigured(), provider: ctx.search.provider, results: results };
+`
+	result, err := sb.Execute(context.Background(), code, map[string]interface{}{"query": "golang"},
+		&ExecutionContext{Search: NewSearchServiceWithProvider(provider)})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, result["configured"])
+	assert.Equal(t, "mock", result["provider"])
+	assert.Equal(t, []string{"golang"}, provider.queries)
+	results, ok := result["results"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, results, 1, "results should be truncated to numResults")
+	assert.Equal(t, "https://go.dev", results[0].(map[string]interface{})["url"])
+}
+
+func TestSandbox_Search_UnconfiguredProvider(t *testing.T) {
+	sb := New(DefaultConfig())
+	execCtx := &ExecutionContext{Search: NewSearchServiceWithProvider(&mockSearchProvider{name: "mock"})}
+
+	result, err := sb.Execute(context.Background(), `return { configured: ctx.search.isConfigured() };`, nil, execCtx)
+	require.NoError(t, err)
+	assert.Equal(t, false, result["configured"])
+
+	_, err = sb.Execute(context.Background(), `return ctx.search.search("golang");`, nil, execCtx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mock search is not configured")
+}
+
+func TestSearchProviders_NormalizeResults(t *testing.T) {
+	respond := func(t *testing.T, body interface{}, check func(r *http.Request)) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			check(r)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(body)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	items := func(title, url, snippet string) []map[string]string {
+		out := make([]map[string]string, len(testSearchResults))
+		for i, r := range testSearchResults {
+			out[i] = map[string]string{title: r.Title, url: r.URL, snippet: r.Snippet}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		provider func(t *testing.T) SearchProvider
+	}{
+		{
+			name: SearchProviderTavily,
+			provider: func(t *testing.T) SearchProvider {
+				server := respond(t, map[string]interface{}{"results": items("title", "url", "content")}, func(r *http.Request) {
+					var req tavilyRequest
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+					assert.Equal(t, "tvly-key", req.APIKey)
+					assert.Equal(t, "golang", req.Query)
+				})
+				return &tavilyProvider{apiKey: "tvly-key", endpoint: server.URL, client: server.Client()}
+			},
+		},
+		{
+			name: SearchProviderBing,
+			provider: func(t *testing.T) SearchProvider {
+				body := map[string]interface{}{"webPages": map[string]interface{}{"value": items("name", "url", "snippet")}}
+				server := respond(t, body, func(r *http.Request) {
+					assert.Equal(t, "bing-key", r.Header.Get("Ocp-Apim-Subscription-Key"))
+					assert.Equal(t, "golang", r.URL.Query().Get("q"))
+				})
+				return &bingProvider{apiKey: "bing-key", endpoint: server.URL, client: server.Client()}
+			},
+		},
+		{
+			name: SearchProviderGoogle,
+			provider: func(t *testing.T) SearchProvider {
+				server := respond(t, map[string]interface{}{"items": items("title", "link", "snippet")}, func(r *http.Request) {
+					assert.Equal(t, "google-key", r.URL.Query().Get("key"))
+					assert.Equal(t, "engine", r.URL.Query().Get("cx"))
+					assert.Equal(t, "golang", r.URL.Query().Get("q"))
+				})
+				return &googleProvider{apiKey: "google-key", engineID: "engine", endpoint: server.URL, client: server.Client()}
+			},
+		},
+		{
+			name: SearchProviderSerpAPI,
+			provider: func(t *testing.T) SearchProvider {
+				server := respond(t, map[string]interface{}{"organic_results": items("title", "link", "snippet")}, func(r *http.Request) {
+					assert.Equal(t, "serp-key", r.URL.Query().Get("api_key"))
+					assert.Equal(t, "golang", r.URL.Query().Get("q"))
+				})
+				return &serpAPIProvider{apiKey: "serp-key", endpoint: server.URL, client: server.Client()}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSearchServiceWithProvider(tt.provider(t))
+			assert.True(t, service.IsConfigured())
+			assert.Equal(t, tt.name, service.Provider())
+
+			results, err := service.Search("golang", 5)
+			require.NoError(t, err)
+			assert.Equal(t, testSearchResults, results)
+		})
+	}
+}
+
+func TestNewSearchServiceFor(t *testing.T) {
+	t.Setenv("TAVILY_API_KEY", "")
+	t.Setenv("BING_SEARCH_API_KEY", "bing-key")
+	t.Setenv("GOOGLE_SEARCH_API_KEY", "google-key")
+	t.Setenv("GOOGLE_SEARCH_ENGINE_ID", "")
+
+	tavily := NewSearchServiceFor("")
+	assert.Equal(t, SearchProviderTavily, tavily.Provider())
+	assert.False(t, tavily.IsConfigured())
+
+	bing := NewSearchServiceFor("Bing")
+	assert.Equal(t, SearchProviderBing, bing.Provider())
+	assert.True(t, bing.IsConfigured())
+
+	google := NewSearchServiceFor(SearchProviderGoogle)
+	assert.False(t, google.IsConfigured(), "google needs an engine ID as well as an API key")
+
+	unknown := NewSearchServiceFor("altavista")
+	assert.False(t, unknown.IsConfigured())
+	assert.Empty(t, unknown.Provider())
+	_, err := unknown.Search("golang", 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown search provider")
+}
@@ -160,7 +160,7 @@ func (s *BlocksServiceImpl) GetWithSchema(slug string) (map[string]interface{},
 	// Get the block with all schema-related fields
 	query := `
 		SELECT id, slug, name, description, category, config_schema, config_defaults,
-		       output_schema, required_credentials, output_ports, parent_block_id
+		       output_schema, required_credentials, output_ports, parent_block_id, examples
 		FROM block_definitions
 		WHERE slug = $1 AND (tenant_id = $2 OR tenant_id IS NULL)
 		LIMIT 1
@@ -178,11 +178,12 @@ func (s *BlocksServiceImpl) GetWithSchema(slug string) (map[string]interface{},
 		requiredCredentials []byte
 		outputPorts         []byte
 		parentBlockID       *uuid.UUID
+		examples            []byte
 	)
 
 	err := s.pool.QueryRow(s.ctx, query, slug, s.tenantID).Scan(
 		&id, &blockSlug, &name, &description, &category, &configSchema, &configDefaults,
-		&outputSchema, &requiredCredentials, &outputPorts, &parentBlockID,
+		&outputSchema, &requiredCredentials, &outputPorts, &parentBlockID, &examples,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -257,6 +258,14 @@ func (s *BlocksServiceImpl) GetWithSchema(slug string) (map[string]interface{},
 		}
 	}
 
+	// Parse examples: sample config, input and expected output to model step configs on
+	if len(examples) > 0 {
+		var parsed []interface{}
+		if err := json.Unmarshal(examples, &parsed); err == nil && len(parsed) > 0 {
+			block["examples"] = parsed
+		}
+	}
+
 	return block, nil
 }
 
@@ -5,21 +5,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
 )
 
-// VectorService provides vector DB operations with strict tenant isolation
+// VectorService provides vector DB operations with strict tenant isolation.
+// Collection names are logical: they resolve within the service's tenant, except
+// SystemVectorCollections, which are shared and read-only (writes return domain.ErrVectorCollectionReadOnly).
 type VectorService interface {
 	// Upsert adds or updates documents in a collection
 	// ⚠️ tenant_id is automatically applied, cannot be overridden
 	Upsert(collection string, documents []VectorDocument, opts *UpsertOptions) (*UpsertResult, error)
 
 	// Query performs similarity search
-	// ⚠️ tenant_id filter is automatically applied
+	// ⚠️ tenant_id filter is automatically applied (system collections use SystemVectorTenantID)
 	Query(collection string, vector []float32, opts *QueryOptions) (*QueryResult, error)
 
 	// Delete removes documents from a collection
@@ -29,6 +34,100 @@ type VectorService interface {
 	// ListCollections returns all collections for the tenant
 	// ⚠️ Only returns collections belonging to the tenant
 	ListCollections() ([]CollectionInfo, error)
+
+	// CreateCollection creates an empty collection for the tenant
+	// Returns domain.ErrVectorCollectionExists if the tenant already has a collection with that name
+	CreateCollection(name string, opts *CreateCollectionOptions) (*CollectionInfo, error)
+
+	// DeleteCollection removes a collection and all of its documents
+	// ⚠️ Only the tenant's own collection can be deleted; returns domain.ErrVectorCollectionNotFound otherwise
+	DeleteCollection(name string) error
+}
+
+// Distance metrics supported by collections (pgvector operators)
+const (
+	VectorMetricCosine       = "cosine"        // <=> (default)
+	VectorMetricL2           = "l2"            // <->
+	VectorMetricInnerProduct = "inner_product" // <#>
+)
+
+// MaxVectorDimension is the largest vector dimension pgvector supports
+const MaxVectorDimension = 16000
+
+// collectionNamePattern allows lowercase letters, digits, '-' and '_' (max 100 characters)
+var collectionNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+// ValidateCollectionName checks that a collection name is safe to use as an identifier
+func ValidateCollectionName(name string) error {
+	if !collectionNamePattern.MatchString(name) {
+		return domain.NewValidationError("name", "collection name must be 1-100 lowercase letters, digits, '-' or '_' and start with a letter or digit")
+	}
+	return nil
+}
+
+// SystemVectorTenantID owns the shared system collections (the tenant system projects are seeded into)
+var SystemVectorTenantID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// SystemVectorCollections are platform-managed collections shared by every tenant.
+// Any tenant can query them; only SystemVectorTenantID can write to them.
+var SystemVectorCollections = map[string]bool{
+	"platform-docs":    true, // Copilot documentation search
+	"block-embeddings": true, // Copilot block search
+}
+
+// collectionScope is the physical collection a logical name resolves to.
+// Collections are keyed by (tenant_id, name), so the owner tenant is the name's prefix.
+type collectionScope struct {
+	tenantID uuid.UUID
+	name     string
+}
+
+// resolveCollection scopes a logical collection name to the tenant that owns it.
+// Tenant collections always resolve within the caller's tenant, so two tenants using the same
+// name never share data. System collections resolve to SystemVectorTenantID and are read-only:
+// writes return domain.ErrVectorCollectionReadOnly unless the caller is the system tenant.
+func resolveCollection(tenantID uuid.UUID, name string, write bool) (collectionScope, error) {
+	if !SystemVectorCollections[name] || tenantID == SystemVectorTenantID {
+		return collectionScope{tenantID: tenantID, name: name}, nil
+	}
+	if write {
+		return collectionScope{}, fmt.Errorf("%w: %s", domain.ErrVectorCollectionReadOnly, name)
+	}
+	return collectionScope{tenantID: SystemVectorTenantID, name: name}, nil
+}
+
+// CreateCollectionOptions configures a new collection; zero values use the upsert defaults
+type CreateCollectionOptions struct {
+	Description       string `json:"description,omitempty"`
+	EmbeddingProvider string `json:"embedding_provider,omitempty"`
+	EmbeddingModel    string `json:"embedding_model,omitempty"`
+	Dimension         int    `json:"dimension,omitempty"`
+	Metric            string `json:"metric,omitempty"`
+}
+
+// Validate applies defaults and checks dimension and metric
+func (o *CreateCollectionOptions) Validate() error {
+	if o.EmbeddingProvider == "" {
+		o.EmbeddingProvider = "openai"
+	}
+	if o.EmbeddingModel == "" {
+		o.EmbeddingModel = "text-embedding-3-small"
+	}
+	if o.Dimension == 0 {
+		o.Dimension = 1536
+	}
+	if o.Dimension < 1 || o.Dimension > MaxVectorDimension {
+		return domain.NewValidationError("dimension", fmt.Sprintf("dimension must be between 1 and %d", MaxVectorDimension))
+	}
+	if o.Metric == "" {
+		o.Metric = VectorMetricCosine
+	}
+	switch o.Metric {
+	case VectorMetricCosine, VectorMetricL2, VectorMetricInnerProduct:
+	default:
+		return domain.NewValidationError("metric", "metric must be cosine, l2, or inner_product")
+	}
+	return nil
 }
 
 // VectorDocument represents a document with optional embedding
@@ -82,10 +181,15 @@ type DeleteResult struct {
 
 // CollectionInfo contains information about a collection
 type CollectionInfo struct {
-	Name          string `json:"name"`
-	DocumentCount int    `json:"document_count"`
-	Dimension     int    `json:"dimension"`
-	CreatedAt     string `json:"created_at"`
+	Name              string `json:"name"`
+	Description       string `json:"description,omitempty"`
+	DocumentCount     int    `json:"document_count"`
+	Dimension         int    `json:"dimension"`
+	Metric            string `json:"metric"`
+	EmbeddingProvider string `json:"embedding_provider"`
+	EmbeddingModel    string `json:"embedding_model"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at,omitempty"`
 }
 
 // VectorServiceImpl implements VectorService with PGVector backend
@@ -114,8 +218,13 @@ func (s *VectorServiceImpl) Upsert(collection string, documents []VectorDocument
 		return &UpsertResult{UpsertedCount: 0, IDs: []string{}}, nil
 	}
 
+	scope, err := resolveCollection(s.tenantID, collection, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get or create collection (with tenant_id)
-	collectionID, err := s.getOrCreateCollection(collection, opts)
+	collectionID, err := s.getOrCreateCollection(scope, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get/create collection: %w", err)
 	}
@@ -148,7 +257,7 @@ func (s *VectorServiceImpl) Upsert(collection string, documents []VectorDocument
 		// Convert vector to pgvector format
 		vectorStr := s.vectorToString(doc.Vector)
 
-		// ⚠️ tenant_id is always set from s.tenantID
+		// ⚠️ tenant_id is always the resolved owner, which for writes is s.tenantID
 		query := `
 			INSERT INTO vector_documents (id, tenant_id, collection_id, content, metadata, embedding, source_type)
 			VALUES ($1, $2, $3, $4, $5, $6::vector, $7)
@@ -160,7 +269,7 @@ func (s *VectorServiceImpl) Upsert(collection string, documents []VectorDocument
 
 		_, err = tx.Exec(s.ctx, query,
 			docID,
-			s.tenantID, // ⚠️ Forced tenant isolation
+			scope.tenantID, // ⚠️ Forced tenant isolation
 			collectionID,
 			doc.Content,
 			metadataJSON,
@@ -204,18 +313,32 @@ func (s *VectorServiceImpl) Query(collection string, vector []float32, opts *Que
 		opts.TopK = 5
 	}
 
+	scope, err := resolveCollection(s.tenantID, collection, false)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use hybrid search if keyword is provided
 	if opts.Keyword != "" {
-		return s.queryHybrid(collection, vector, opts)
+		return s.queryHybrid(scope, vector, opts)
 	}
 
-	return s.queryVector(collection, vector, opts)
+	return s.queryVector(scope, vector, opts)
 }
 
 // queryVector performs pure vector similarity search
-func (s *VectorServiceImpl) queryVector(collection string, vector []float32, opts *QueryOptions) (*QueryResult, error) {
+func (s *VectorServiceImpl) queryVector(scope collectionScope, vector []float32, opts *QueryOptions) (*QueryResult, error) {
 	vectorStr := s.vectorToString(vector)
 
+	metric, exists, err := s.collectionMetric(scope)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &QueryResult{Matches: []QueryMatch{}}, nil
+	}
+	distance, score := metricExpressions(metric)
+
 	// Build query with mandatory tenant filter
 	// ⚠️ Both collection and documents are filtered by tenant_id
 	query := `
@@ -223,7 +346,7 @@ func (s *VectorServiceImpl) queryVector(collection string, vector []float32, opt
 			vd.id,
 			vd.content,
 			vd.metadata,
-			1 - (vd.embedding <=> $3::vector) as score
+			` + score + ` as score
 		FROM vector_documents vd
 		JOIN vector_collections vc ON vd.collection_id = vc.id
 		WHERE vc.tenant_id = $1
@@ -231,12 +354,12 @@ func (s *VectorServiceImpl) queryVector(collection string, vector []float32, opt
 		  AND vd.tenant_id = $1
 	`
 
-	args := []interface{}{s.tenantID, collection, vectorStr}
+	args := []interface{}{scope.tenantID, scope.name, vectorStr}
 	argIndex := 4
 
 	// Add threshold filter if specified
 	if opts.Threshold > 0 {
-		query += fmt.Sprintf(" AND 1 - (vd.embedding <=> $3::vector) >= $%d", argIndex)
+		query += fmt.Sprintf(" AND %s >= $%d", score, argIndex)
 		args = append(args, opts.Threshold)
 		argIndex++
 	}
@@ -255,7 +378,7 @@ func (s *VectorServiceImpl) queryVector(collection string, vector []float32, opt
 		}
 	}
 
-	query += fmt.Sprintf(" ORDER BY vd.embedding <=> $3::vector LIMIT $%d", argIndex)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", distance, argIndex)
 	args = append(args, opts.TopK)
 
 	rows, err := s.pool.Query(s.ctx, query, args...)
@@ -268,9 +391,18 @@ func (s *VectorServiceImpl) queryVector(collection string, vector []float32, opt
 }
 
 // queryHybrid performs hybrid search (vector + keyword) using RRF
-func (s *VectorServiceImpl) queryHybrid(collection string, vector []float32, opts *QueryOptions) (*QueryResult, error) {
+func (s *VectorServiceImpl) queryHybrid(scope collectionScope, vector []float32, opts *QueryOptions) (*QueryResult, error) {
 	vectorStr := s.vectorToString(vector)
 
+	metric, exists, err := s.collectionMetric(scope)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &QueryResult{Matches: []QueryMatch{}}, nil
+	}
+	distance, score := metricExpressions(metric)
+
 	// Default alpha: 0.7 (70% vector, 30% keyword)
 	alpha := opts.HybridAlpha
 	if alpha <= 0 || alpha > 1 {
@@ -285,8 +417,8 @@ func (s *VectorServiceImpl) queryHybrid(collection string, vector []float32, opt
 				vd.id,
 				vd.content,
 				vd.metadata,
-				ROW_NUMBER() OVER (ORDER BY vd.embedding <=> $3::vector) as v_rank,
-				1 - (vd.embedding <=> $3::vector) as v_score
+				ROW_NUMBER() OVER (ORDER BY ` + distance + `) as v_rank,
+				` + score + ` as v_score
 			FROM vector_documents vd
 			JOIN vector_collections vc ON vd.collection_id = vc.id
 			WHERE vc.tenant_id = $1
@@ -322,7 +454,7 @@ func (s *VectorServiceImpl) queryHybrid(collection string, vector []float32, opt
 		LIMIT $6
 	`
 
-	args := []interface{}{s.tenantID, collection, vectorStr, opts.Keyword, alpha, opts.TopK}
+	args := []interface{}{scope.tenantID, scope.name, vectorStr, opts.Keyword, alpha, opts.TopK}
 
 	rows, err := s.pool.Query(s.ctx, query, args...)
 	if err != nil {
@@ -367,9 +499,14 @@ func (s *VectorServiceImpl) Delete(collection string, ids []string) (*DeleteResu
 		return &DeleteResult{DeletedCount: 0}, nil
 	}
 
+	scope, err := resolveCollection(s.tenantID, collection, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build placeholder list for IDs
 	placeholders := make([]string, len(ids))
-	args := []interface{}{s.tenantID, collection}
+	args := []interface{}{scope.tenantID, scope.name}
 	for i, id := range ids {
 		placeholders[i] = fmt.Sprintf("$%d", i+3)
 		args = append(args, id)
@@ -396,14 +533,17 @@ func (s *VectorServiceImpl) Delete(collection string, ids []string) (*DeleteResu
 	}, nil
 }
 
-// ListCollections returns all collections for the tenant
+// ListCollections returns all collections for the tenant with live document counts
 func (s *VectorServiceImpl) ListCollections() ([]CollectionInfo, error) {
 	// ⚠️ Only returns collections belonging to the tenant
 	query := `
-		SELECT name, document_count, dimension, created_at
-		FROM vector_collections
-		WHERE tenant_id = $1
-		ORDER BY created_at DESC
+		SELECT vc.name, COALESCE(vc.description, ''),
+		       (SELECT COUNT(*) FROM vector_documents vd WHERE vd.collection_id = vc.id AND vd.tenant_id = vc.tenant_id),
+		       vc.dimension, vc.distance_metric, vc.embedding_provider, vc.embedding_model,
+		       vc.created_at, vc.updated_at
+		FROM vector_collections vc
+		WHERE vc.tenant_id = $1
+		ORDER BY vc.created_at DESC
 	`
 
 	rows, err := s.pool.Query(s.ctx, query, s.tenantID)
@@ -415,26 +555,126 @@ func (s *VectorServiceImpl) ListCollections() ([]CollectionInfo, error) {
 	collections := []CollectionInfo{}
 	for rows.Next() {
 		var info CollectionInfo
-		var createdAt interface{}
+		var createdAt, updatedAt *time.Time
 
-		if err := rows.Scan(&info.Name, &info.DocumentCount, &info.Dimension, &createdAt); err != nil {
+		if err := rows.Scan(&info.Name, &info.Description, &info.DocumentCount, &info.Dimension, &info.Metric,
+			&info.EmbeddingProvider, &info.EmbeddingModel, &createdAt, &updatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-
-		if t, ok := createdAt.(string); ok {
-			info.CreatedAt = t
-		} else {
-			info.CreatedAt = fmt.Sprintf("%v", createdAt)
-		}
+		info.CreatedAt = formatCollectionTime(createdAt)
+		info.UpdatedAt = formatCollectionTime(updatedAt)
 
 		collections = append(collections, info)
 	}
 
-	return collections, nil
+	return collections, rows.Err()
+}
+
+// CreateCollection creates an empty collection for the tenant
+func (s *VectorServiceImpl) CreateCollection(name string, opts *CreateCollectionOptions) (*CollectionInfo, error) {
+	if err := ValidateCollectionName(name); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &CreateCollectionOptions{}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	scope, err := resolveCollection(s.tenantID, name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// ⚠️ Uniqueness is per tenant (unique_collection_per_tenant)
+	var createdAt time.Time
+	err = s.pool.QueryRow(s.ctx, `
+		INSERT INTO vector_collections (id, tenant_id, name, description, embedding_provider, embedding_model, dimension, distance_metric)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, name) DO NOTHING
+		RETURNING created_at
+	`, uuid.New(), scope.tenantID, scope.name, opts.Description, opts.EmbeddingProvider, opts.EmbeddingModel, opts.Dimension, opts.Metric,
+	).Scan(&createdAt)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrVectorCollectionExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	return &CollectionInfo{
+		Name:              name,
+		Description:       opts.Description,
+		Dimension:         opts.Dimension,
+		Metric:            opts.Metric,
+		EmbeddingProvider: opts.EmbeddingProvider,
+		EmbeddingModel:    opts.EmbeddingModel,
+		CreatedAt:         formatCollectionTime(&createdAt),
+	}, nil
+}
+
+// DeleteCollection removes the tenant's collection; documents are removed by ON DELETE CASCADE
+func (s *VectorServiceImpl) DeleteCollection(name string) error {
+	scope, err := resolveCollection(s.tenantID, name, true)
+	if err != nil {
+		return err
+	}
+
+	// ⚠️ tenant_id filter ensures only the tenant's collection can be deleted
+	result, err := s.pool.Exec(s.ctx,
+		"DELETE FROM vector_collections WHERE tenant_id = $1 AND name = $2",
+		scope.tenantID, scope.name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrVectorCollectionNotFound
+	}
+	return nil
+}
+
+func formatCollectionTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// collectionMetric returns the collection's distance metric (false if the collection does not exist)
+func (s *VectorServiceImpl) collectionMetric(scope collectionScope) (string, bool, error) {
+	var metric string
+	err := s.pool.QueryRow(s.ctx,
+		"SELECT distance_metric FROM vector_collections WHERE tenant_id = $1 AND name = $2",
+		scope.tenantID, scope.name,
+	).Scan(&metric)
+	if err == pgx.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to query collection: %w", err)
+	}
+	return metric, true, nil
+}
+
+// metricExpressions returns the SQL distance (ascending = more similar) and score (higher = more similar)
+// against the query vector parameter $3 for a distance metric
+func metricExpressions(metric string) (distance, score string) {
+	switch metric {
+	case VectorMetricL2:
+		distance = "(vd.embedding <-> $3::vector)"
+		return distance, "1 / (1 + " + distance + ")"
+	case VectorMetricInnerProduct:
+		distance = "(vd.embedding <#> $3::vector)" // Negative inner product
+		return distance, "-" + distance
+	default:
+		distance = "(vd.embedding <=> $3::vector)"
+		return distance, "1 - " + distance
+	}
 }
 
 // getOrCreateCollection gets or creates a collection with tenant isolation
-func (s *VectorServiceImpl) getOrCreateCollection(name string, opts *UpsertOptions) (uuid.UUID, error) {
+func (s *VectorServiceImpl) getOrCreateCollection(scope collectionScope, opts *UpsertOptions) (uuid.UUID, error) {
 	provider := "openai"
 	model := "text-embedding-3-small"
 	dimension := 1536
@@ -456,7 +696,7 @@ func (s *VectorServiceImpl) getOrCreateCollection(name string, opts *UpsertOptio
 	var collectionID uuid.UUID
 	err := s.pool.QueryRow(s.ctx,
 		"SELECT id FROM vector_collections WHERE tenant_id = $1 AND name = $2",
-		s.tenantID, name,
+		scope.tenantID, scope.name,
 	).Scan(&collectionID)
 
 	if err == nil {
@@ -472,13 +712,13 @@ func (s *VectorServiceImpl) getOrCreateCollection(name string, opts *UpsertOptio
 	_, err = s.pool.Exec(s.ctx,
 		`INSERT INTO vector_collections (id, tenant_id, name, embedding_provider, embedding_model, dimension)
 		 VALUES ($1, $2, $3, $4, $5, $6)`,
-		collectionID, s.tenantID, name, provider, model, dimension,
+		collectionID, scope.tenantID, scope.name, provider, model, dimension,
 	)
 	if err != nil {
 		// Check if another request created it
 		err2 := s.pool.QueryRow(s.ctx,
 			"SELECT id FROM vector_collections WHERE tenant_id = $1 AND name = $2",
-			s.tenantID, name,
+			scope.tenantID, scope.name,
 		).Scan(&collectionID)
 		if err2 == nil {
 			return collectionID, nil
@@ -518,7 +758,7 @@ func (s *VectorServiceImpl) generateMissingEmbeddings(documents []VectorDocument
 		}
 	}
 
-	result, err := s.embeddingService.Embed(provider, model, textsToEmbed)
+	result, err := s.embeddingService.EmbedBatch(provider, model, textsToEmbed, 0)
 	if err != nil {
 		return fmt.Errorf("embedding failed: %w", err)
 	}
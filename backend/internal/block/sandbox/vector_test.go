@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,6 +31,10 @@ func (m *MockEmbeddingService) Embed(provider, model string, texts []string) (*E
 	}, nil
 }
 
+func (m *MockEmbeddingService) EmbedBatch(provider, model string, texts []string, batchSize int) (*EmbeddingResult, error) {
+	return embedInBatches(m.Embed, provider, model, texts, batchSize)
+}
+
 func TestVectorDocument_Structure(t *testing.T) {
 	doc := VectorDocument{
 		ID:      "doc-1",
@@ -454,3 +459,91 @@ func TestFilterBuilder_InvalidFieldName(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid character")
 }
+
+func TestValidateCollectionName(t *testing.T) {
+	for _, name := range []string{"kb", "platform-docs", "block_embeddings", "2024-reports"} {
+		assert.NoError(t, ValidateCollectionName(name), name)
+	}
+	for _, name := range []string{"", "-kb", "KB", "kb docs", "tenant/kb", string(make([]byte, 101))} {
+		assert.Error(t, ValidateCollectionName(name), name)
+	}
+}
+
+func TestCreateCollectionOptions_Validate(t *testing.T) {
+	opts := &CreateCollectionOptions{}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, 1536, opts.Dimension)
+	assert.Equal(t, VectorMetricCosine, opts.Metric)
+	assert.Equal(t, "openai", opts.EmbeddingProvider)
+
+	assert.Error(t, (&CreateCollectionOptions{Dimension: -1}).Validate())
+	assert.Error(t, (&CreateCollectionOptions{Dimension: MaxVectorDimension + 1}).Validate())
+	assert.Error(t, (&CreateCollectionOptions{Metric: "hamming"}).Validate())
+	assert.NoError(t, (&CreateCollectionOptions{Metric: VectorMetricInnerProduct}).Validate())
+}
+
+func TestMetricExpressions(t *testing.T) {
+	distance, score := metricExpressions(VectorMetricCosine)
+	assert.Equal(t, "(vd.embedding <=> $3::vector)", distance)
+	assert.Equal(t, "1 - (vd.embedding <=> $3::vector)", score)
+
+	distance, _ = metricExpressions(VectorMetricL2)
+	assert.Contains(t, distance, "<->")
+
+	distance, score = metricExpressions(VectorMetricInnerProduct)
+	assert.Contains(t, distance, "<#>")
+	assert.Equal(t, "-"+distance, score)
+}
+
+func TestResolveCollection_SameNameIsolatedPerTenant(t *testing.T) {
+	tenantA := uuid.New()
+	tenantB := uuid.New()
+
+	scopeA, err := resolveCollection(tenantA, "kb", true)
+	assert.NoError(t, err)
+	scopeB, err := resolveCollection(tenantB, "kb", true)
+	assert.NoError(t, err)
+
+	assert.Equal(t, collectionScope{tenantID: tenantA, name: "kb"}, scopeA)
+	assert.Equal(t, collectionScope{tenantID: tenantB, name: "kb"}, scopeB)
+	assert.NotEqual(t, scopeA, scopeB, "the same logical name must resolve to a different collection per tenant")
+
+	// Reads resolve the same way as writes, so tenant B never sees tenant A's documents
+	readB, err := resolveCollection(tenantB, "kb", false)
+	assert.NoError(t, err)
+	assert.Equal(t, scopeB, readB)
+}
+
+func TestResolveCollection_SystemCollections(t *testing.T) {
+	tenant := uuid.New()
+
+	for name := range SystemVectorCollections {
+		scope, err := resolveCollection(tenant, name, false)
+		assert.NoError(t, err, name)
+		assert.Equal(t, collectionScope{tenantID: SystemVectorTenantID, name: name}, scope, "system collections are shared for reads")
+
+		_, err = resolveCollection(tenant, name, true)
+		assert.ErrorIs(t, err, domain.ErrVectorCollectionReadOnly, name)
+
+		scope, err = resolveCollection(SystemVectorTenantID, name, true)
+		assert.NoError(t, err, "the system tenant maintains system collections")
+		assert.Equal(t, SystemVectorTenantID, scope.tenantID)
+	}
+}
+
+func TestVectorServiceImpl_SystemCollectionWritesRejected(t *testing.T) {
+	// Writes fail before touching the database, so no pool is needed
+	svc := NewVectorService(context.Background(), uuid.New(), nil, &MockEmbeddingService{})
+
+	_, err := svc.Upsert("platform-docs", []VectorDocument{{Content: "injected", Vector: []float32{0.1}}}, nil)
+	assert.ErrorIs(t, err, domain.ErrVectorCollectionReadOnly)
+
+	_, err = svc.Delete("platform-docs", []string{"doc-1"})
+	assert.ErrorIs(t, err, domain.ErrVectorCollectionReadOnly)
+
+	_, err = svc.CreateCollection("block-embeddings", nil)
+	assert.ErrorIs(t, err, domain.ErrVectorCollectionReadOnly)
+
+	err = svc.DeleteCollection("block-embeddings")
+	assert.ErrorIs(t, err, domain.ErrVectorCollectionReadOnly)
+}
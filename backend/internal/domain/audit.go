@@ -16,6 +16,8 @@ const (
 	AuditActionProjectUpdate  AuditAction = "project.update"
 	AuditActionProjectDelete  AuditAction = "project.delete"
 	AuditActionProjectPublish AuditAction = "project.publish"
+	AuditActionProjectPause   AuditAction = "project.pause"
+	AuditActionProjectResume  AuditAction = "project.resume"
 
 	// Step actions
 	AuditActionStepCreate AuditAction = "step.create"
@@ -31,12 +33,14 @@ const (
 	AuditActionRunCancel AuditAction = "run.cancel"
 
 	// Schedule actions
-	AuditActionScheduleCreate  AuditAction = "schedule.create"
-	AuditActionScheduleUpdate  AuditAction = "schedule.update"
-	AuditActionScheduleDelete  AuditAction = "schedule.delete"
-	AuditActionSchedulePause   AuditAction = "schedule.pause"
-	AuditActionScheduleResume  AuditAction = "schedule.resume"
-	AuditActionScheduleTrigger AuditAction = "schedule.trigger"
+	AuditActionScheduleCreate    AuditAction = "schedule.create"
+	AuditActionScheduleUpdate    AuditAction = "schedule.update"
+	AuditActionScheduleDelete    AuditAction = "schedule.delete"
+	AuditActionSchedulePause     AuditAction = "schedule.pause"
+	AuditActionScheduleResume    AuditAction = "schedule.resume"
+	AuditActionScheduleTrigger   AuditAction = "schedule.trigger"
+	AuditActionSchedulePauseAll  AuditAction = "schedule.pause_all"
+	AuditActionScheduleResumeAll AuditAction = "schedule.resume_all"
 
 	// Webhook actions
 	AuditActionWebhookCreate           AuditAction = "webhook.create"
@@ -62,6 +66,23 @@ const (
 	AuditActionCredentialDelete   AuditAction = "credential.delete"
 	AuditActionCredentialRevoke   AuditAction = "credential.revoke"
 	AuditActionCredentialActivate AuditAction = "credential.activate"
+	AuditActionCredentialTest     AuditAction = "credential.test"
+	AuditActionCredentialAccess   AuditAction = "credential.access"
+
+	// Project permission actions
+	AuditActionProjectPermissionGrant  AuditAction = "project_permission.grant"
+	AuditActionProjectPermissionRevoke AuditAction = "project_permission.revoke"
+
+	// Notification channel actions
+	AuditActionNotificationChannelCreate AuditAction = "notification_channel.create"
+	AuditActionNotificationChannelUpdate AuditAction = "notification_channel.update"
+	AuditActionNotificationChannelDelete AuditAction = "notification_channel.delete"
+	AuditActionNotificationDeliver       AuditAction = "notification.deliver"
+
+	// Library function actions
+	AuditActionLibraryFunctionCreate AuditAction = "library_function.create"
+	AuditActionLibraryFunctionUpdate AuditAction = "library_function.update"
+	AuditActionLibraryFunctionDelete AuditAction = "library_function.delete"
 
 	// OAuth2 App actions
 	AuditActionOAuth2AppCreate AuditAction = "oauth2_app.create"
@@ -96,6 +117,9 @@ const (
 	AuditResourceCredential      AuditResourceType = "credential"
 	AuditResourceOAuth2App       AuditResourceType = "oauth2_app"
 	AuditResourceCredentialShare AuditResourceType = "credential_share"
+
+	AuditResourceNotificationChannel AuditResourceType = "notification_channel"
+	AuditResourceLibraryFunction     AuditResourceType = "library_function"
 )
 
 // AuditLog represents an audit log entry
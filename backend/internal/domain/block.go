@@ -109,6 +109,16 @@ type ErrorCodeDef struct {
 	Retryable   bool   `json:"retryable"`   // Can this error be retried?
 }
 
+// BlockExample is a sample use of a block: the config and input of a step and the output the
+// block produces for them. Examples are shown in the block palette and guide the copilot when it
+// configures a step.
+type BlockExample struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config,omitempty"` // Step config, merged over the block's config defaults
+	Input  json.RawMessage `json:"input,omitempty"`  // Output of the previous step
+	Output json.RawMessage `json:"output"`           // Expected block output
+}
+
 // OutputPort defines an output connection point for a block
 type OutputPort struct {
 	Name        string          `json:"name"`                  // Unique identifier (e.g., "true", "false", "default")
@@ -209,6 +219,9 @@ type BlockDefinition struct {
 	// Error handling
 	ErrorCodes []ErrorCodeDef `json:"error_codes"`
 
+	// Examples: Sample config, input and expected output
+	Examples []BlockExample `json:"examples,omitempty"`
+
 	// === Block Inheritance/Extension fields ===
 	// ParentBlockID: Reference to parent block for inheritance (only blocks with code can be inherited)
 	ParentBlockID *uuid.UUID `json:"parent_block_id,omitempty"`
@@ -414,6 +427,7 @@ type BlockVersion struct {
 	// Change tracking
 	ChangeSummary string     `json:"change_summary,omitempty"`
 	ChangedBy     *uuid.UUID `json:"changed_by,omitempty"`
+	ContentHash   string     `json:"content_hash,omitempty"` // Set by the seeder (SHA-256 of the seeded content)
 
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -436,3 +450,21 @@ func NewBlockVersion(block *BlockDefinition, changeSummary string, changedBy *uu
 
 // ErrBlockVersionNotFound is returned when a block version is not found
 var ErrBlockVersionNotFound = fmt.Errorf("block version not found")
+
+// BlockReferences lists what still depends on a block definition
+type BlockReferences struct {
+	ProjectIDs    []uuid.UUID `json:"project_ids"`     // Non-deleted projects with steps or groups using the block
+	ChildBlockIDs []uuid.UUID `json:"child_block_ids"` // Blocks inheriting from the block
+}
+
+// IsReferenced reports whether anything still depends on the block
+func (r *BlockReferences) IsReferenced() bool {
+	return len(r.ProjectIDs) > 0 || len(r.ChildBlockIDs) > 0
+}
+
+// BlockUsage summarizes how a tenant's workflows use a block
+type BlockUsage struct {
+	Slug       string    `json:"slug"`
+	StepCount  int       `json:"step_count"`   // Steps of the block in non-deleted projects
+	LastUsedAt time.Time `json:"last_used_at"` // When the latest of those steps was added
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlockExportFormatVersion is the current version of the portable block format
+const BlockExportFormatVersion = 1
+
+// BlockExport is a portable, tenant-independent representation of a custom block.
+// IDs, tenant ownership, versions, and timestamps are stripped; the parent block is
+// referenced by slug and re-linked when imported.
+type BlockExport struct {
+	FormatVersion int                   `json:"format_version"`
+	ExportedAt    time.Time             `json:"exported_at"`
+	Block         BlockExportDefinition `json:"block"`
+}
+
+// BlockExportDefinition holds the portable fields of a block definition
+type BlockExportDefinition struct {
+	Slug        string           `json:"slug"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Category    BlockCategory    `json:"category"`
+	Subcategory BlockSubcategory `json:"subcategory,omitempty"`
+	Icon        string           `json:"icon,omitempty"`
+
+	ConfigSchema        json.RawMessage `json:"config_schema"`
+	OutputSchema        json.RawMessage `json:"output_schema,omitempty"`
+	OutputPorts         []OutputPort    `json:"output_ports,omitempty"`
+	Code                string          `json:"code,omitempty"`
+	UIConfig            json.RawMessage `json:"ui_config,omitempty"`
+	RequiredCredentials json.RawMessage `json:"required_credentials,omitempty"`
+	ErrorCodes          []ErrorCodeDef  `json:"error_codes,omitempty"`
+
+	// ParentSlug references the parent block by slug (resolved in the importing tenant)
+	ParentSlug     string          `json:"parent_slug,omitempty"`
+	ConfigDefaults json.RawMessage `json:"config_defaults,omitempty"`
+	PreProcess     string          `json:"pre_process,omitempty"`
+	PostProcess    string          `json:"post_process,omitempty"`
+	InternalSteps  []InternalStep  `json:"internal_steps,omitempty"`
+
+	Request  *RequestConfig  `json:"request,omitempty"`
+	Response *ResponseConfig `json:"response,omitempty"`
+
+	GroupKind   BlockGroupKind `json:"group_kind,omitempty"`
+	IsContainer bool           `json:"is_container,omitempty"`
+}
+
+// NewBlockExport creates a portable export of a block.
+// parentSlug is the slug of the block's parent, or empty when it has none.
+func NewBlockExport(block *BlockDefinition, parentSlug string) *BlockExport {
+	return &BlockExport{
+		FormatVersion: BlockExportFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Block: BlockExportDefinition{
+			Slug:                block.Slug,
+			Name:                block.Name,
+			Description:         block.Description,
+			Category:            block.Category,
+			Subcategory:         block.Subcategory,
+			Icon:                block.Icon,
+			ConfigSchema:        block.ConfigSchema,
+			OutputSchema:        block.OutputSchema,
+			OutputPorts:         block.OutputPorts,
+			Code:                block.Code,
+			UIConfig:            block.UIConfig,
+			RequiredCredentials: block.RequiredCredentials,
+			ErrorCodes:          block.ErrorCodes,
+			ParentSlug:          parentSlug,
+			ConfigDefaults:      block.ConfigDefaults,
+			PreProcess:          block.PreProcess,
+			PostProcess:         block.PostProcess,
+			InternalSteps:       block.InternalSteps,
+			Request:             block.Request,
+			Response:            block.Response,
+			GroupKind:           block.GroupKind,
+			IsContainer:         block.IsContainer,
+		},
+	}
+}
+
+// Validate checks that the export can be imported
+func (e *BlockExport) Validate() error {
+	if e.FormatVersion < 1 || e.FormatVersion > BlockExportFormatVersion {
+		return NewValidationError("format_version", "unsupported block export format version")
+	}
+	if e.Block.Slug == "" || e.Block.Name == "" {
+		return NewValidationError("block", "slug and name are required")
+	}
+	if !e.Block.Category.IsValid() {
+		return NewValidationError("block.category", "invalid category")
+	}
+	if !e.Block.GroupKind.IsValid() {
+		return NewValidationError("block.group_kind", "invalid group kind")
+	}
+	return nil
+}
+
+// ToBlockDefinition creates a new tenant block from the export with a fresh ID.
+// parentID is the re-linked parent block in the importing tenant, if any.
+func (d *BlockExportDefinition) ToBlockDefinition(tenantID uuid.UUID, parentID *uuid.UUID) *BlockDefinition {
+	block := NewBlockDefinition(&tenantID, d.Slug, d.Name, d.Category)
+	block.Description = d.Description
+	block.Subcategory = d.Subcategory
+	block.Icon = d.Icon
+	if len(d.ConfigSchema) > 0 {
+		block.ConfigSchema = d.ConfigSchema
+	}
+	block.OutputSchema = d.OutputSchema
+	if len(d.OutputPorts) > 0 {
+		block.OutputPorts = d.OutputPorts
+	}
+	block.Code = d.Code
+	block.UIConfig = d.UIConfig
+	block.RequiredCredentials = d.RequiredCredentials
+	if d.ErrorCodes != nil {
+		block.ErrorCodes = d.ErrorCodes
+	}
+	block.ParentBlockID = parentID
+	block.ConfigDefaults = d.ConfigDefaults
+	block.PreProcess = d.PreProcess
+	block.PostProcess = d.PostProcess
+	block.InternalSteps = d.InternalSteps
+	block.Request = d.Request
+	block.Response = d.Response
+	block.GroupKind = d.GroupKind
+	block.IsContainer = d.IsContainer
+	return block
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxBlockTypeSuggestions is the maximum number of suggestions returned for an unknown step type
+const MaxBlockTypeSuggestions = 3
+
+// BlockTypeAliases maps common names for a block to its slug (e.g. "gpt" → "llm").
+// Shared by step type validation and the Copilot fix_block_type tool.
+var BlockTypeAliases = map[string]string{
+	"trigger":        "manual_trigger",
+	"start":          "manual_trigger",
+	"begin":          "manual_trigger",
+	"ai":             "llm",
+	"gpt":            "llm",
+	"openai":         "llm",
+	"claude":         "llm",
+	"anthropic":      "llm",
+	"if":             "condition",
+	"branch":         "condition",
+	"conditional":    "condition",
+	"case":           "switch",
+	"delay":          "delay",
+	"sleep":          "delay",
+	"timer":          "delay",
+	"api":            "http",
+	"request":        "http",
+	"cron":           "schedule_trigger",
+	"schedule":       "schedule_trigger",
+	"scheduled":      "schedule_trigger",
+	"debug":          "log",
+	"print":          "log",
+	"console":        "log",
+	"parallel":       "map",
+	"foreach":        "map",
+	"loop":           "loop",
+	"merge":          "join",
+	"aggregate":      "aggregate",
+	"collect":        "join",
+	"human":          "human-in-loop",
+	"approval":       "human-in-loop",
+	"human_approval": "human-in-loop",
+}
+
+// InvalidStepTypeError reports a step type that does not resolve to a built-in type
+// or block definition, with the closest valid slugs
+type InvalidStepTypeError struct {
+	Type        string   `json:"type"`
+	Suggestions []string `json:"suggestions"`
+}
+
+func (e *InvalidStepTypeError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("invalid step type %q", e.Type)
+	}
+	return fmt.Sprintf("invalid step type %q (did you mean %s?)", e.Type, strings.Join(e.Suggestions, ", "))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidStepType)
+func (e *InvalidStepTypeError) Unwrap() error {
+	return ErrInvalidStepType
+}
+
+// SuggestBlockTypes returns up to MaxBlockTypeSuggestions slugs from available that are closest to invalid.
+// Candidates are ranked as the fix_block_type tool does: alias mapping first, then substring
+// matches, then slugs within a small edit distance.
+func SuggestBlockTypes(invalid string, available []string) []string {
+	needle := strings.ToLower(strings.TrimSpace(invalid))
+	if needle == "" {
+		return []string{}
+	}
+
+	known := make(map[string]bool, len(available))
+	for _, slug := range available {
+		known[slug] = true
+	}
+
+	suggestions := make([]string, 0, MaxBlockTypeSuggestions)
+	seen := make(map[string]bool)
+	add := func(slug string) {
+		if !seen[slug] && len(suggestions) < MaxBlockTypeSuggestions {
+			seen[slug] = true
+			suggestions = append(suggestions, slug)
+		}
+	}
+
+	if mapped, ok := BlockTypeAliases[needle]; ok && known[mapped] {
+		add(mapped)
+	}
+
+	// Sort for deterministic output regardless of repository ordering
+	sorted := append([]string(nil), available...)
+	sort.Strings(sorted)
+
+	for _, slug := range sorted {
+		lower := strings.ToLower(slug)
+		if strings.Contains(lower, needle) || strings.Contains(needle, lower) {
+			add(slug)
+		}
+	}
+
+	type candidate struct {
+		slug     string
+		distance int
+	}
+	maxDistance := len(needle) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	var near []candidate
+	for _, slug := range sorted {
+		if d := levenshtein(needle, strings.ToLower(slug)); d <= maxDistance {
+			near = append(near, candidate{slug: slug, distance: d})
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool { return near[i].distance < near[j].distance })
+	for _, c := range near {
+		add(c.slug)
+	}
+
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
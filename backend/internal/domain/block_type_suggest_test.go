@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSuggestBlockTypes(t *testing.T) {
+	available := []string{"llm", "http", "slack", "discord", "manual_trigger", "schedule_trigger", "condition", "switch"}
+
+	tests := []struct {
+		name    string
+		invalid string
+		want    []string
+	}{
+		{name: "alias", invalid: "gpt", want: []string{"llm"}},
+		{name: "alias is case-insensitive", invalid: "GPT", want: []string{"llm"}},
+		{name: "alias ranks before substring", invalid: "schedule", want: []string{"schedule_trigger"}},
+		{name: "substring", invalid: "trigger", want: []string{"manual_trigger", "schedule_trigger"}},
+		{name: "typo", invalid: "slak", want: []string{"slack"}},
+		{name: "alias target unavailable falls back to fuzzy", invalid: "delay", want: []string{}},
+		{name: "no match", invalid: "zzzzzzzz", want: []string{}},
+		{name: "empty", invalid: "", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SuggestBlockTypes(tt.invalid, available)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestBlockTypes(%q) = %v, want %v", tt.invalid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestBlockTypes_Limit(t *testing.T) {
+	available := []string{"http_get", "http_post", "http_put", "http_delete", "http_patch"}
+	if got := SuggestBlockTypes("http", available); len(got) != MaxBlockTypeSuggestions {
+		t.Errorf("got %d suggestions, want %d", len(got), MaxBlockTypeSuggestions)
+	}
+}
+
+func TestInvalidStepTypeError(t *testing.T) {
+	err := error(&InvalidStepTypeError{Type: "gpt", Suggestions: []string{"llm"}})
+	if !errors.Is(err, ErrInvalidStepType) {
+		t.Error("InvalidStepTypeError should match ErrInvalidStepType")
+	}
+	if err.Error() != `invalid step type "gpt" (did you mean llm?)` {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -71,6 +72,11 @@ type CopilotSession struct {
 	Spec      json.RawMessage `json:"spec,omitempty"`       // WorkflowSpec as JSON
 	ProjectID *uuid.UUID      `json:"project_id,omitempty"` // Generated/modified project
 
+	// Agent memory: MemoryWindow overrides the agent's memory_window (nil = workflow default);
+	// messages created before MemoryClearedAt are kept for display but not sent as history
+	MemoryWindow    *int       `json:"memory_window,omitempty"`
+	MemoryClearedAt *time.Time `json:"memory_cleared_at,omitempty"`
+
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -179,6 +185,42 @@ func (s *CopilotSession) Abandon() {
 	s.UpdatedAt = time.Now().UTC()
 }
 
+// MaxCopilotMemoryWindow is the largest per-session memory window
+const MaxCopilotMemoryWindow = 200
+
+// SetMemoryWindow sets the per-session memory window; nil restores the workflow default
+func (s *CopilotSession) SetMemoryWindow(window *int) error {
+	if window != nil && (*window < 1 || *window > MaxCopilotMemoryWindow) {
+		return NewValidationError("memory_window", fmt.Sprintf("memory_window must be between 1 and %d", MaxCopilotMemoryWindow))
+	}
+	s.MemoryWindow = window
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// ClearMemory makes the agent forget all messages so far without deleting them
+func (s *CopilotSession) ClearMemory() {
+	now := time.Now().UTC()
+	s.MemoryClearedAt = &now
+	s.UpdatedAt = now
+}
+
+// MemoryMessages returns the loaded messages the agent remembers:
+// those created after the last ClearMemory, limited to the last MemoryWindow messages when set
+func (s *CopilotSession) MemoryMessages() []CopilotMessage {
+	messages := make([]CopilotMessage, 0, len(s.Messages))
+	for _, msg := range s.Messages {
+		if s.MemoryClearedAt != nil && !msg.CreatedAt.After(*s.MemoryClearedAt) {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	if s.MemoryWindow != nil && len(messages) > *s.MemoryWindow {
+		messages = messages[len(messages)-*s.MemoryWindow:]
+	}
+	return messages
+}
+
 // IsActive returns true if the session is still active
 func (s *CopilotSession) IsActive() bool {
 	return s.Status != CopilotSessionStatusCompleted && s.Status != CopilotSessionStatusAbandoned
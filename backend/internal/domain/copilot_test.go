@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCopilotSession_ClearMemory(t *testing.T) {
+	projectID := uuid.New()
+	session := NewCopilotSession(uuid.New(), "user-1", &projectID, CopilotSessionModeEnhance)
+	session.AddMessage("user", "add a slack step")
+	session.AddMessage("assistant", "done")
+
+	if got := len(session.MemoryMessages()); got != 2 {
+		t.Fatalf("MemoryMessages() before clear = %d messages, want 2", got)
+	}
+
+	session.ClearMemory()
+	if session.MemoryClearedAt == nil {
+		t.Fatal("MemoryClearedAt is nil after ClearMemory()")
+	}
+	if got := len(session.MemoryMessages()); got != 0 {
+		t.Errorf("MemoryMessages() after clear = %d messages, want 0", got)
+	}
+	if len(session.Messages) != 2 {
+		t.Errorf("ClearMemory() removed messages from the transcript: %d left", len(session.Messages))
+	}
+
+	time.Sleep(time.Millisecond)
+	session.AddMessage("user", "start over")
+	memory := session.MemoryMessages()
+	if len(memory) != 1 || memory[0].Content != "start over" {
+		t.Errorf("MemoryMessages() = %+v, want only the message sent after clearing", memory)
+	}
+}
+
+func TestCopilotSession_SetMemoryWindow(t *testing.T) {
+	session := NewCopilotSession(uuid.New(), "user-1", nil, CopilotSessionModeCreate)
+	for _, content := range []string{"one", "two", "three"} {
+		session.AddMessage("user", content)
+	}
+
+	var validationErr ValidationError
+	for _, invalid := range []int{0, -1, MaxCopilotMemoryWindow + 1} {
+		window := invalid
+		if err := session.SetMemoryWindow(&window); !errors.As(err, &validationErr) || validationErr.Field != "memory_window" {
+			t.Errorf("SetMemoryWindow(%d) error = %v, want memory_window validation error", invalid, err)
+		}
+	}
+
+	window := 2
+	if err := session.SetMemoryWindow(&window); err != nil {
+		t.Fatalf("SetMemoryWindow(2) error = %v", err)
+	}
+	memory := session.MemoryMessages()
+	if len(memory) != 2 || memory[0].Content != "two" || memory[1].Content != "three" {
+		t.Errorf("MemoryMessages() = %+v, want the last 2 messages", memory)
+	}
+
+	if err := session.SetMemoryWindow(nil); err != nil {
+		t.Fatalf("SetMemoryWindow(nil) error = %v", err)
+	}
+	if got := len(session.MemoryMessages()); got != 3 {
+		t.Errorf("MemoryMessages() after reset = %d messages, want 3", got)
+	}
+}
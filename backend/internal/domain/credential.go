@@ -3,6 +3,8 @@ package domain
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -436,7 +438,41 @@ func ParseRequiredCredentials(data json.RawMessage) ([]RequiredCredential, error
 	return creds, nil
 }
 
-// ParseCredentialBindings parses JSON object of credential bindings
+// IsCredentialBindingTemplate reports whether a credential binding is a template such as
+// {{$.credential_id}}, resolved against the step input and variables at execution time
+func IsCredentialBindingTemplate(ref string) bool {
+	return strings.Contains(ref, "{{")
+}
+
+// ParseCredentialBindingRefs parses JSON object of credential bindings, keeping each binding as
+// written: a credential ID or a template. Empty bindings are skipped.
+func ParseCredentialBindingRefs(data json.RawMessage) (map[string]string, error) {
+	if data == nil || len(data) == 0 || string(data) == "null" {
+		return map[string]string{}, nil
+	}
+
+	var bindings map[string]string
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for name, ref := range bindings {
+		if ref == "" {
+			continue
+		}
+		if !IsCredentialBindingTemplate(ref) {
+			if _, err := uuid.Parse(ref); err != nil {
+				return nil, fmt.Errorf("credential binding %q: %w", name, err)
+			}
+		}
+		result[name] = ref
+	}
+	return result, nil
+}
+
+// ParseCredentialBindings parses JSON object of credential bindings into the statically bound
+// credential IDs. Template bindings are left out; see ParseCredentialBindingRefs.
 func ParseCredentialBindings(data json.RawMessage) (map[string]uuid.UUID, error) {
 	if data == nil || len(data) == 0 || string(data) == "null" {
 		return map[string]uuid.UUID{}, nil
@@ -449,6 +485,9 @@ func ParseCredentialBindings(data json.RawMessage) (map[string]uuid.UUID, error)
 
 	result := make(map[string]uuid.UUID)
 	for name, idStr := range bindings {
+		if IsCredentialBindingTemplate(idStr) {
+			continue
+		}
 		id, err := uuid.Parse(idStr)
 		if err != nil {
 			return nil, err
@@ -457,3 +496,21 @@ func ParseCredentialBindings(data json.RawMessage) (map[string]uuid.UUID, error)
 	}
 	return result, nil
 }
+
+// CredentialAccessPurpose is why a credential's secret was decrypted
+type CredentialAccessPurpose string
+
+const (
+	CredentialAccessExecution      CredentialAccessPurpose = "execution"       // Bound to a step of a run
+	CredentialAccessWebhook        CredentialAccessPurpose = "webhook"         // Verifying a webhook signature
+	CredentialAccessConnectionTest CredentialAccessPurpose = "connection_test" // Testing the credential against its provider
+)
+
+// CredentialAccess identifies who decrypted a credential and for what, for the credential.access
+// audit log. The secret itself is never recorded.
+type CredentialAccess struct {
+	Purpose CredentialAccessPurpose
+	RunID   *uuid.UUID
+	StepID  *uuid.UUID
+	ActorID *uuid.UUID // User who triggered the run or made the request, if any
+}
@@ -14,6 +14,9 @@ var (
 	ErrProjectHasUnreachable    = errors.New("project has unreachable steps")
 	ErrProjectBranchOutsideGroup = errors.New("branching blocks (condition/switch) with multiple outputs must be inside a Block Group")
 	ErrProjectVersionNotFound   = errors.New("project version not found")
+	ErrProjectAccessDenied      = errors.New("access to workflow denied")
+	ErrProjectPermissionNotFound = errors.New("workflow permission not found")
+	ErrProjectPaused            = errors.New("project is paused; resume it to start runs")
 
 	// Step errors
 	ErrStepNotFound     = errors.New("step not found")
@@ -32,6 +35,12 @@ var (
 	ErrRunNotFound      = errors.New("run not found")
 	ErrRunNotCancellable = errors.New("run cannot be cancelled")
 	ErrRunNotResumable  = errors.New("run cannot be resumed")
+	ErrRunAnnotationNotFound = errors.New("run annotation not found")
+	ErrRunCheckpointNotFound = errors.New("run checkpoint not found")
+	ErrQueueUnavailable      = errors.New("run queue is unavailable")
+	ErrMaintenanceMode       = errors.New("service is in read-only maintenance mode")
+	ErrQueueSaturated        = errors.New("run queue is saturated")
+	ErrTenantQueueSaturated  = errors.New("too many queued runs for the tenant")
 
 	// Step Run errors
 	ErrStepRunNotFound = errors.New("step run not found")
@@ -94,7 +103,10 @@ var (
 	ErrForbidden      = errors.New("forbidden")
 
 	// Template errors
-	ErrTemplateNotFound = errors.New("template not found")
+	ErrTemplateNotFound       = errors.New("template not found")
+	ErrTemplateReviewExists   = errors.New("user already reviewed this template")
+	ErrTemplateReviewNotFound = errors.New("template review not found")
+	ErrTemplateNotUsed        = errors.New("template must be used before it can be reviewed")
 
 	// Git Sync errors
 	ErrGitSyncNotFound = errors.New("git sync configuration not found")
@@ -102,6 +114,22 @@ var (
 	// Block Package errors
 	ErrBlockPackageNotFound = errors.New("block package not found")
 
+	// Vector collection errors
+	ErrVectorCollectionNotFound = errors.New("vector collection not found")
+	ErrVectorCollectionExists   = errors.New("vector collection already exists")
+	ErrVectorCollectionReadOnly = errors.New("vector collection is a read-only system collection")
+
+	// Notification errors
+	ErrNotificationChannelNotFound = errors.New("notification channel not found")
+
+	// Library function errors
+	ErrLibraryFunctionNotFound = errors.New("library function not found")
+	ErrLibraryFunctionExists   = errors.New("library function already exists")
+
+	// Concurrent update errors
+	ErrConcurrentModification = errors.New("resource was modified by another request")
+	ErrJSONPatchTestFailed    = errors.New("json patch test operation failed")
+
 	// Validation errors
 	ErrValidation = errors.New("validation error")
 )
@@ -17,6 +17,8 @@ var ErrorMessages = map[string]LocalizedText{
 	"PROJECT_ALREADY_PUBLISHED":  L("Project is already published", "プロジェクトは既に公開されています"),
 	"PROJECT_NOT_PUBLISHED":      L("Project is not published", "プロジェクトは公開されていません"),
 	"PROJECT_NOT_EDITABLE":       L("Published project cannot be edited", "公開済みのプロジェクトは編集できません"),
+	"PROJECT_PAUSED":             L("Project is paused; resume it to start runs", "プロジェクトは一時停止中です。実行するには再開してください"),
+	"WORKFLOW_ACCESS_DENIED":     L("Your role on this workflow does not allow this action", "このワークフローでのロールではこの操作はできません"),
 	"PROJECT_HAS_CYCLE":          L("Project contains a cycle", "プロジェクトに循環参照があります"),
 	"PROJECT_HAS_UNCONNECTED":    L("Project has unconnected steps", "プロジェクトに未接続のステップがあります"),
 	"PROJECT_HAS_UNREACHABLE":    L("Project has unreachable steps", "プロジェクトに到達不能なステップがあります"),
@@ -41,6 +43,10 @@ var ErrorMessages = map[string]LocalizedText{
 	"RUN_NOT_CANCELLABLE": L("Run cannot be cancelled", "実行をキャンセルできません"),
 	"RUN_NOT_RESUMABLE":  L("Run cannot be resumed", "実行を再開できません"),
 	"STEP_RUN_NOT_FOUND": L("Step run not found", "ステップ実行が見つかりません"),
+	"QUEUE_UNAVAILABLE":  L("Runs cannot be started right now; please retry shortly", "現在 Run を開始できません。しばらくしてから再試行してください"),
+	"MAINTENANCE_MODE":   L("The service is in read-only maintenance mode; changes are temporarily disabled", "メンテナンス中のため読み取り専用です。変更は一時的に無効になっています"),
+	"QUEUE_SATURATED":    L("Too many runs are waiting to start; please retry later", "開始待ちの Run が多すぎます。しばらくしてから再試行してください"),
+	"TENANT_QUEUE_SATURATED": L("Your organization has too many runs waiting to start; please retry later", "組織の開始待ちの Run が上限に達しています。しばらくしてから再試行してください"),
 
 	// Block Group errors
 	"BLOCK_GROUP_NOT_FOUND":    L("Block group not found", "ブロックグループが見つかりません"),
@@ -90,7 +96,9 @@ var ErrorMessages = map[string]LocalizedText{
 	"COPILOT_SESSION_NOT_FOUND": L("Copilot session not found", "Copilotセッションが見つかりません"),
 
 	// Template errors
-	"TEMPLATE_NOT_FOUND": L("Template not found", "テンプレートが見つかりません"),
+	"TEMPLATE_NOT_FOUND":     L("Template not found", "テンプレートが見つかりません"),
+	"TEMPLATE_REVIEW_EXISTS": L("You have already reviewed this template", "このテンプレートは既にレビュー済みです"),
+	"TEMPLATE_NOT_USED":      L("Use the template before reviewing it", "レビューするにはテンプレートを使用する必要があります"),
 
 	// Git Sync errors
 	"GIT_SYNC_NOT_FOUND": L("Git sync configuration not found", "Git同期設定が見つかりません"),
@@ -98,6 +106,13 @@ var ErrorMessages = map[string]LocalizedText{
 	// Block Package errors
 	"BLOCK_PACKAGE_NOT_FOUND": L("Block package not found", "ブロックパッケージが見つかりません"),
 
+	// Vector collection errors
+	"VECTOR_COLLECTION_READ_ONLY": L("System vector collections are read-only", "システムのベクトルコレクションは読み取り専用です"),
+
+	// Concurrent update errors
+	"CONCURRENT_MODIFICATION": L("The resource was modified by another request; reload it and retry", "リソースが他のリクエストによって更新されました。再読み込みしてから再試行してください"),
+	"PATCH_TEST_FAILED":       L("A test operation of the patch failed", "パッチの test 操作が失敗しました"),
+
 	// Schema validation errors
 	"SCHEMA_VALIDATION_ERROR": L("Input validation failed", "入力値の検証に失敗しました"),
 
@@ -17,6 +17,7 @@ func TestDomainErrors_NotNil(t *testing.T) {
 		ErrProjectHasUnreachable,
 		ErrProjectBranchOutsideGroup,
 		ErrProjectVersionNotFound,
+		ErrProjectPaused,
 		ErrStepNotFound,
 		ErrInvalidStepType,
 		ErrStepConfigInvalid,
@@ -70,6 +71,8 @@ func TestDomainErrors_NotNil(t *testing.T) {
 		ErrTemplateNotFound,
 		ErrGitSyncNotFound,
 		ErrBlockPackageNotFound,
+		ErrLibraryFunctionNotFound,
+		ErrLibraryFunctionExists,
 		ErrValidation,
 	}
 
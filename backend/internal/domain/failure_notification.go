@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MaxFailureNotificationRules caps the failure notification rules of a workflow
+const MaxFailureNotificationRules = 10
+
+// FailureNotificationRule sends a notification to a channel when a run of the workflow fails.
+// Without conditions every failure notifies; ConsecutiveFailures and Categories narrow it down so
+// flaky workflows do not cause alert fatigue while critical failures still reach someone.
+type FailureNotificationRule struct {
+	ChannelID           uuid.UUID `json:"channel_id"`                     // Notification channel of the workflow's tenant
+	ConsecutiveFailures int       `json:"consecutive_failures,omitempty"` // Notify on every Nth consecutive failure (default 1: every failure)
+	Categories          []string  `json:"categories,omitempty"`           // Error categories that notify (default all)
+}
+
+// Validate checks the channel, threshold and error categories of the rule
+func (r *FailureNotificationRule) Validate() error {
+	if r.ChannelID == uuid.Nil {
+		return NewValidationError("failure_notifications.channel_id", "channel_id is required")
+	}
+	if r.ConsecutiveFailures < 0 {
+		return NewValidationError("failure_notifications.consecutive_failures", "consecutive_failures must not be negative")
+	}
+	for _, category := range r.Categories {
+		if !IsRunErrorCategory(category) {
+			return NewValidationError("failure_notifications.categories", fmt.Sprintf("unknown error category %q", category))
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a failed run notifies, given its error category and the number of
+// consecutive failed runs of the workflow including it. With a threshold of N the rule notifies
+// when the streak reaches N and again every N failures while it lasts; a completed run ends the
+// streak.
+func (r *FailureNotificationRule) Matches(category string, consecutiveFailures int) bool {
+	if len(r.Categories) > 0 {
+		matched := false
+		for _, c := range r.Categories {
+			if c == category {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	threshold := r.ConsecutiveFailures
+	if threshold <= 1 {
+		return true
+	}
+	return consecutiveFailures > 0 && consecutiveFailures%threshold == 0
+}
+
+// ValidateFailureNotificationRules checks the number of rules and each rule
+func ValidateFailureNotificationRules(rules []FailureNotificationRule) error {
+	if len(rules) > MaxFailureNotificationRules {
+		return NewValidationError("failure_notifications", fmt.Sprintf("at most %d failure notification rules are allowed", MaxFailureNotificationRules))
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFailureNotificationRule_Matches(t *testing.T) {
+	channelID := uuid.New()
+	tests := []struct {
+		name        string
+		rule        FailureNotificationRule
+		category    string
+		consecutive int
+		want        bool
+	}{
+		{"every failure by default", FailureNotificationRule{ChannelID: channelID}, RunErrorCategoryOther, 1, true},
+		{"threshold of one is every failure", FailureNotificationRule{ChannelID: channelID, ConsecutiveFailures: 1}, RunErrorCategoryOther, 4, true},
+		{"below the threshold", FailureNotificationRule{ChannelID: channelID, ConsecutiveFailures: 3}, RunErrorCategoryOther, 2, false},
+		{"reaching the threshold", FailureNotificationRule{ChannelID: channelID, ConsecutiveFailures: 3}, RunErrorCategoryOther, 3, true},
+		{"between multiples of the threshold", FailureNotificationRule{ChannelID: channelID, ConsecutiveFailures: 3}, RunErrorCategoryOther, 4, false},
+		{"next multiple of the threshold", FailureNotificationRule{ChannelID: channelID, ConsecutiveFailures: 3}, RunErrorCategoryOther, 6, true},
+		{"listed category", FailureNotificationRule{ChannelID: channelID, Categories: []string{RunErrorCategoryTimeout, RunErrorCategoryServerError}}, RunErrorCategoryServerError, 1, true},
+		{"unlisted category", FailureNotificationRule{ChannelID: channelID, Categories: []string{RunErrorCategoryTimeout}}, RunErrorCategoryRateLimit, 1, false},
+		{"unlisted category at the threshold", FailureNotificationRule{ChannelID: channelID, ConsecutiveFailures: 2, Categories: []string{RunErrorCategoryTimeout}}, RunErrorCategoryOther, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.category, tt.consecutive); got != tt.want {
+				t.Errorf("Matches(%q, %d) = %v, want %v", tt.category, tt.consecutive, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFailureNotificationRules(t *testing.T) {
+	channelID := uuid.New()
+	tests := []struct {
+		name    string
+		rules   []FailureNotificationRule
+		wantErr bool
+	}{
+		{"no rules", nil, false},
+		{"valid rule", []FailureNotificationRule{{ChannelID: channelID, ConsecutiveFailures: 3, Categories: []string{RunErrorCategoryTimeout}}}, false},
+		{"missing channel", []FailureNotificationRule{{ConsecutiveFailures: 3}}, true},
+		{"negative threshold", []FailureNotificationRule{{ChannelID: channelID, ConsecutiveFailures: -1}}, true},
+		{"unknown category", []FailureNotificationRule{{ChannelID: channelID, Categories: []string{"flaky"}}}, true},
+		{"too many rules", make([]FailureNotificationRule, MaxFailureNotificationRules+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFailureNotificationRules(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFailureNotificationRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
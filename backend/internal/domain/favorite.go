@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FavoriteTargetType represents the kind of resource a user can favorite
+type FavoriteTargetType string
+
+const (
+	FavoriteTargetProject  FavoriteTargetType = "project"
+	FavoriteTargetTemplate FavoriteTargetType = "template"
+)
+
+// IsValid checks if the target type is valid
+func (t FavoriteTargetType) IsValid() bool {
+	switch t {
+	case FavoriteTargetProject, FavoriteTargetTemplate:
+		return true
+	}
+	return false
+}
+
+// Favorite is a resource pinned by a user within a tenant
+type Favorite struct {
+	TenantID   uuid.UUID          `json:"tenant_id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	TargetType FavoriteTargetType `json:"target_type"`
+	TargetID   uuid.UUID          `json:"target_id"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// NewFavorite creates a new favorite
+func NewFavorite(tenantID, userID uuid.UUID, targetType FavoriteTargetType, targetID uuid.UUID) *Favorite {
+	return &Favorite{
+		TenantID:   tenantID,
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
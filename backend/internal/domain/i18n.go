@@ -110,6 +110,24 @@ func (e LocalizedErrorCodeDef) ToErrorCodeDef(lang string) ErrorCodeDef {
 	}
 }
 
+// LocalizedBlockExample represents a block example with a localized name
+type LocalizedBlockExample struct {
+	Name   LocalizedText   `json:"name"`
+	Config json.RawMessage `json:"config,omitempty"`
+	Input  json.RawMessage `json:"input,omitempty"`
+	Output json.RawMessage `json:"output"`
+}
+
+// ToBlockExample converts to BlockExample for the specified language
+func (e LocalizedBlockExample) ToBlockExample(lang string) BlockExample {
+	return BlockExample{
+		Name:   e.Name.Get(lang),
+		Config: e.Config,
+		Input:  e.Input,
+		Output: e.Output,
+	}
+}
+
 // LocalizedConfigSchema holds config schemas for each language
 type LocalizedConfigSchema struct {
 	EN json.RawMessage `json:"en"`
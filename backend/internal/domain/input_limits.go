@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Default input limits. Run input arrives from API callers and webhooks, so it is held to a
+// tighter size than step input, which also carries the output of earlier steps.
+const (
+	DefaultMaxInputDepth     = 64
+	DefaultMaxRunInputBytes  = 1 << 20  // 1MB
+	DefaultMaxStepInputBytes = 16 << 20 // 16MB
+)
+
+// InputLimits bounds the JSON input accepted for a run or a step before it reaches template
+// resolution and the sandbox, where pathological nesting or size is expensive to process
+type InputLimits struct {
+	MaxDepth int `json:"max_depth"` // Maximum nesting depth of objects and arrays (0 = unlimited)
+	MaxBytes int `json:"max_bytes"` // Maximum serialized size in bytes (0 = unlimited)
+}
+
+// DefaultRunInputLimits returns the default limits for run input
+func DefaultRunInputLimits() InputLimits {
+	return InputLimits{MaxDepth: DefaultMaxInputDepth, MaxBytes: DefaultMaxRunInputBytes}
+}
+
+// DefaultStepInputLimits returns the default limits for step input
+func DefaultStepInputLimits() InputLimits {
+	return InputLimits{MaxDepth: DefaultMaxInputDepth, MaxBytes: DefaultMaxStepInputBytes}
+}
+
+// ForTenant returns the limits with the tenant's input limits applied. Positive tenant values
+// replace the corresponding limit; zero keeps it.
+func (l InputLimits) ForTenant(limits *TenantLimits) InputLimits {
+	if limits == nil {
+		return l
+	}
+	if limits.MaxInputDepth > 0 {
+		l.MaxDepth = limits.MaxInputDepth
+	}
+	if limits.MaxInputBytes > 0 {
+		l.MaxBytes = limits.MaxInputBytes
+	}
+	return l
+}
+
+// Validate checks input against the limits and returns a ValidationError for field when the
+// input is too large or too deeply nested. The size is checked first so an oversized payload is
+// rejected without being scanned.
+func (l InputLimits) Validate(field string, input json.RawMessage) error {
+	if l.MaxBytes > 0 && len(input) > l.MaxBytes {
+		return NewValidationError(field, fmt.Sprintf("%s is %d bytes, exceeding the maximum of %d bytes", field, len(input), l.MaxBytes))
+	}
+	if l.MaxDepth > 0 && exceedsJSONDepth(input, l.MaxDepth) {
+		return NewValidationError(field, fmt.Sprintf("%s is nested deeper than the maximum depth of %d", field, l.MaxDepth))
+	}
+	return nil
+}
+
+// exceedsJSONDepth reports whether objects and arrays in data nest deeper than maxDepth. It scans
+// the raw bytes without decoding, skipping brackets inside strings, and stops at the first
+// level past the limit.
+func exceedsJSONDepth(data []byte, maxDepth int) bool {
+	depth := 0
+	inString := false
+	escaped := false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return false
+}
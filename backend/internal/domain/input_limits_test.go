@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func nestedJSON(depth int) json.RawMessage {
+	return json.RawMessage(strings.Repeat(`{"a":`, depth) + `1` + strings.Repeat(`}`, depth))
+}
+
+func TestInputLimits_Validate(t *testing.T) {
+	limits := InputLimits{MaxDepth: 4, MaxBytes: 64}
+
+	tests := []struct {
+		name    string
+		input   json.RawMessage
+		wantErr bool
+	}{
+		{name: "within limits", input: json.RawMessage(`{"items": [{"id": 1}, {"id": 2}]}`)},
+		{name: "at max depth", input: nestedJSON(4)},
+		{name: "too deep", input: nestedJSON(5), wantErr: true},
+		{name: "deep arrays", input: json.RawMessage(`[[[[[1]]]]]`), wantErr: true},
+		{name: "brackets inside strings do not count", input: json.RawMessage(`{"s": "[[[[[[{{{{\"]]]"}`)},
+		{name: "oversized", input: json.RawMessage(`{"s": "` + strings.Repeat("x", 64) + `"}`), wantErr: true},
+		{name: "empty", input: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.Validate("input", tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var validationErr ValidationError
+			if err != nil && (!errors.As(err, &validationErr) || validationErr.Field != "input") {
+				t.Errorf("Validate() error = %#v, want a ValidationError for field input", err)
+			}
+		})
+	}
+}
+
+func TestInputLimits_ZeroIsUnlimited(t *testing.T) {
+	if err := (InputLimits{}).Validate("input", nestedJSON(1000)); err != nil {
+		t.Errorf("Validate() error = %v, want nil without limits", err)
+	}
+}
+
+func TestInputLimits_ForTenant(t *testing.T) {
+	defaults := DefaultRunInputLimits()
+
+	got := defaults.ForTenant(&TenantLimits{MaxInputDepth: 8})
+	want := InputLimits{MaxDepth: 8, MaxBytes: DefaultMaxRunInputBytes}
+	if got != want {
+		t.Errorf("ForTenant() = %+v, want %+v", got, want)
+	}
+	if got := defaults.ForTenant(nil); got != defaults {
+		t.Errorf("ForTenant(nil) = %+v, want defaults %+v", got, defaults)
+	}
+}
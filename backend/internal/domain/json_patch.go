@@ -0,0 +1,352 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchContentType is the media type of a JSON Patch (RFC 6902) request body
+const JSONPatchContentType = "application/json-patch+json"
+
+// MaxJSONPatchOperations caps the number of operations of one JSON Patch document
+const MaxJSONPatchOperations = 100
+
+// JSONPatch operation names
+const (
+	JSONPatchOpAdd     = "add"
+	JSONPatchOpRemove  = "remove"
+	JSONPatchOpReplace = "replace"
+	JSONPatchOpMove    = "move"
+	JSONPatchOpCopy    = "copy"
+	JSONPatchOpTest    = "test"
+)
+
+// JSONPatchOperation is one operation of a JSON Patch document
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`  // Source pointer of move and copy
+	Value json.RawMessage `json:"value,omitempty"` // Value of add, replace and test
+}
+
+// JSONPatch is a JSON Patch (RFC 6902) document: operations applied in order, all or nothing
+type JSONPatch []JSONPatchOperation
+
+// ParseJSONPatch parses and checks a JSON Patch document
+func ParseJSONPatch(data []byte) (JSONPatch, error) {
+	var patch JSONPatch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, NewValidationError("patch", "patch must be an array of JSON Patch operations")
+	}
+	if len(patch) == 0 {
+		return nil, NewValidationError("patch", "patch must contain at least one operation")
+	}
+	if len(patch) > MaxJSONPatchOperations {
+		return nil, NewValidationError("patch", fmt.Sprintf("patch must not contain more than %d operations", MaxJSONPatchOperations))
+	}
+	for i, op := range patch {
+		switch op.Op {
+		case JSONPatchOpAdd, JSONPatchOpReplace, JSONPatchOpTest:
+			if op.Value == nil {
+				return nil, op.invalid(i, "value is required")
+			}
+		case JSONPatchOpMove, JSONPatchOpCopy:
+			if _, err := parseJSONPointer(op.From); err != nil {
+				return nil, op.invalid(i, "from: "+err.Error())
+			}
+		case JSONPatchOpRemove:
+		default:
+			return nil, op.invalid(i, "unknown op")
+		}
+		if _, err := parseJSONPointer(op.Path); err != nil {
+			return nil, op.invalid(i, "path: "+err.Error())
+		}
+	}
+	return patch, nil
+}
+
+// Apply applies the patch to a JSON document and returns the patched document. The input is
+// left as it is, so a failing operation leaves nothing half-applied. A failing test operation
+// returns ErrJSONPatchTestFailed; any other failure is a ValidationError.
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	value, err := decodeJSONValue(doc)
+	if err != nil {
+		return nil, NewValidationError("patch", "document is not valid JSON")
+	}
+	for i, op := range p {
+		if value, err = op.apply(value); err != nil {
+			if err == ErrJSONPatchTestFailed {
+				return nil, fmt.Errorf("%w: operation %d (%s %s)", ErrJSONPatchTestFailed, i, op.Op, op.Path)
+			}
+			return nil, op.invalid(i, err.Error())
+		}
+	}
+	return json.Marshal(value)
+}
+
+// invalid returns the ValidationError of the i-th operation
+func (op JSONPatchOperation) invalid(i int, msg string) error {
+	return NewValidationError("patch", fmt.Sprintf("operation %d (%s %s): %s", i, op.Op, op.Path, msg))
+}
+
+// apply applies one operation to a decoded document and returns the new document
+func (op JSONPatchOperation) apply(doc interface{}) (interface{}, error) {
+	path, _ := parseJSONPointer(op.Path)
+	switch op.Op {
+	case JSONPatchOpAdd:
+		value, err := decodeJSONValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPatchAdd(doc, path, value)
+	case JSONPatchOpRemove:
+		value, _, err := jsonPatchRemove(doc, path)
+		return value, err
+	case JSONPatchOpReplace:
+		value, err := decodeJSONValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = jsonPatchRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return jsonPatchAdd(doc, path, value)
+	case JSONPatchOpMove:
+		from, _ := parseJSONPointer(op.From)
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move a value into one of its children")
+		}
+		doc, value, err := jsonPatchRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPatchAdd(doc, path, value)
+	case JSONPatchOpCopy:
+		from, _ := parseJSONPointer(op.From)
+		value, err := jsonPointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		// Copy through JSON so the document does not share maps and slices
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = decodeJSONValue(raw); err != nil {
+			return nil, err
+		}
+		return jsonPatchAdd(doc, path, value)
+	case JSONPatchOpTest:
+		expected, err := decodeJSONValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := jsonPointerGet(doc, path)
+		if err != nil || !jsonValuesEqual(actual, expected) {
+			return nil, ErrJSONPatchTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op")
+}
+
+// decodeJSONValue decodes a JSON value, keeping numbers as json.Number so they round-trip exactly
+func decodeJSONValue(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON value")
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON value")
+	}
+	return value, nil
+}
+
+// parseJSONPointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer must be empty or start with /")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// jsonPointerGet returns the value a pointer references
+func jsonPointerGet(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("path does not exist")
+			}
+			doc = value
+		case []interface{}:
+			index, err := jsonArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[index]
+		default:
+			return nil, fmt.Errorf("path does not exist")
+		}
+	}
+	return doc, nil
+}
+
+// jsonPatchAdd adds a value at a pointer: it sets an object member, inserts into an array
+// ("-" appends) or replaces the whole document
+func jsonPatchAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := jsonPointerGet(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		container[token] = value
+		return doc, nil
+	case []interface{}:
+		index := len(container)
+		if token != "-" {
+			if index, err = jsonArrayIndex(token, len(container)); err != nil {
+				return nil, err
+			}
+		}
+		grown := make([]interface{}, 0, len(container)+1)
+		grown = append(grown, container[:index]...)
+		grown = append(grown, value)
+		grown = append(grown, container[index:]...)
+		return jsonPointerSet(doc, path[:len(path)-1], grown)
+	default:
+		return nil, fmt.Errorf("parent of path is not an object or array")
+	}
+}
+
+// jsonPatchRemove removes the value at a pointer and returns the new document and the removed value
+func jsonPatchRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	parent, err := jsonPointerGet(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := path[len(path)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		value, ok := container[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("path does not exist")
+		}
+		delete(container, token)
+		return doc, value, nil
+	case []interface{}:
+		index, err := jsonArrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := container[index]
+		shrunk := make([]interface{}, 0, len(container)-1)
+		shrunk = append(shrunk, container[:index]...)
+		shrunk = append(shrunk, container[index+1:]...)
+		doc, err = jsonPointerSet(doc, path[:len(path)-1], shrunk)
+		return doc, value, err
+	default:
+		return nil, nil, fmt.Errorf("path does not exist")
+	}
+}
+
+// jsonPointerSet replaces the existing value a pointer references
+func jsonPointerSet(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := jsonPointerGet(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		container[token] = value
+	case []interface{}:
+		index, err := jsonArrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = value
+	}
+	return doc, nil
+}
+
+// jsonArrayIndex parses an array index token and checks it is within 0..max
+func jsonArrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if index > max {
+		return 0, fmt.Errorf("array index %d is out of range", index)
+	}
+	return index, nil
+}
+
+// jsonValuesEqual compares decoded JSON values; numbers are equal when their values are
+func jsonValuesEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !jsonValuesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonValuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if av == bv {
+			return true
+		}
+		af, errA := av.Float64()
+		bf, errB := bv.Float64()
+		return errA == nil && errB == nil && af == bf
+	default:
+		return a == b
+	}
+}
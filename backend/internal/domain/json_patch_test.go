@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSONPatch_Apply(t *testing.T) {
+	doc := `{"name":"a","config":{"model":"gpt-4o","temperature":0.2,"tags":["x","y"]}}`
+
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace nested field",
+			patch: `[{"op":"replace","path":"/config/model","value":"gpt-4o-mini"}]`,
+			want:  `{"name":"a","config":{"model":"gpt-4o-mini","temperature":0.2,"tags":["x","y"]}}`,
+		},
+		{
+			name:  "add member and insert into array",
+			patch: `[{"op":"add","path":"/config/max_tokens","value":512},{"op":"add","path":"/config/tags/1","value":"z"}]`,
+			want:  `{"name":"a","config":{"model":"gpt-4o","temperature":0.2,"max_tokens":512,"tags":["x","z","y"]}}`,
+		},
+		{
+			name:  "append to array",
+			patch: `[{"op":"add","path":"/config/tags/-","value":"z"}]`,
+			want:  `{"name":"a","config":{"model":"gpt-4o","temperature":0.2,"tags":["x","y","z"]}}`,
+		},
+		{
+			name:  "remove",
+			patch: `[{"op":"remove","path":"/config/tags/0"},{"op":"remove","path":"/config/temperature"}]`,
+			want:  `{"name":"a","config":{"model":"gpt-4o","tags":["y"]}}`,
+		},
+		{
+			name:  "move and copy",
+			patch: `[{"op":"move","from":"/config/model","path":"/model"},{"op":"copy","from":"/name","path":"/config/name"}]`,
+			want:  `{"name":"a","model":"gpt-4o","config":{"name":"a","temperature":0.2,"tags":["x","y"]}}`,
+		},
+		{
+			name:  "passing test",
+			patch: `[{"op":"test","path":"/config/temperature","value":0.20},{"op":"replace","path":"/name","value":"b"}]`,
+			want:  `{"name":"b","config":{"model":"gpt-4o","temperature":0.2,"tags":["x","y"]}}`,
+		},
+		{
+			name:  "escaped pointer tokens",
+			patch: `[{"op":"add","path":"/config/a~1b~0c","value":true}]`,
+			want:  `{"name":"a","config":{"model":"gpt-4o","temperature":0.2,"tags":["x","y"],"a/b~c":true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParseJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParseJSONPatch() error = %v", err)
+			}
+			got, err := patch.Apply([]byte(doc))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestJSONPatch_ApplyErrors(t *testing.T) {
+	doc := `{"config":{"tags":["x"]}}`
+
+	tests := []struct {
+		name     string
+		patch    string
+		wantTest bool
+	}{
+		{name: "remove missing member", patch: `[{"op":"remove","path":"/config/model"}]`},
+		{name: "replace missing member", patch: `[{"op":"replace","path":"/name","value":"a"}]`},
+		{name: "array index out of range", patch: `[{"op":"add","path":"/config/tags/2","value":"y"}]`},
+		{name: "leading zero index", patch: `[{"op":"remove","path":"/config/tags/00"}]`},
+		{name: "add under missing parent", patch: `[{"op":"add","path":"/a/b","value":1}]`},
+		{name: "move into own child", patch: `[{"op":"move","from":"/config","path":"/config/inner"}]`},
+		{name: "failing test", patch: `[{"op":"test","path":"/config/tags/0","value":"y"}]`, wantTest: true},
+		{name: "test of missing path", patch: `[{"op":"test","path":"/name","value":"a"}]`, wantTest: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParseJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParseJSONPatch() error = %v", err)
+			}
+			_, err = patch.Apply([]byte(doc))
+			if tt.wantTest {
+				if !errors.Is(err, ErrJSONPatchTestFailed) {
+					t.Errorf("Apply() error = %v, want ErrJSONPatchTestFailed", err)
+				}
+				return
+			}
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Apply() error = %v, want ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestParseJSONPatch_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{name: "not an array", patch: `{"op":"add"}`},
+		{name: "empty", patch: `[]`},
+		{name: "unknown op", patch: `[{"op":"merge","path":"/a","value":1}]`},
+		{name: "missing value", patch: `[{"op":"add","path":"/a"}]`},
+		{name: "relative path", patch: `[{"op":"remove","path":"a"}]`},
+		{name: "move without from", patch: `[{"op":"move","from":"a","path":"/b"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSONPatch([]byte(tt.patch))
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("ParseJSONPatch() error = %v, want ValidationError", err)
+			}
+		})
+	}
+
+	// An explicit null is a value
+	if _, err := ParseJSONPatch([]byte(`[{"op":"replace","path":"/a","value":null}]`)); err != nil {
+		t.Errorf("ParseJSONPatch() with null value error = %v", err)
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+	gotJSON, _ := json.Marshal(gotValue)
+	wantJSON, _ := json.Marshal(wantValue)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("got %s, want %s", gotJSON, wantJSON)
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxLibraryFunctionCodeBytes caps the code of one library function
+	MaxLibraryFunctionCodeBytes = 64 * 1024
+	// MaxLibraryFunctionImports caps the library functions a function step loads, including the
+	// ones required by other library functions
+	MaxLibraryFunctionImports = 32
+	// MaxLibraryBundleBytes caps the total code of the library functions a function step loads
+	MaxLibraryBundleBytes = 512 * 1024
+)
+
+// libraryFunctionNamePattern matches the names scripts pass to require()
+var libraryFunctionNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,63}$`)
+
+// LibraryFunction is a named JavaScript helper shared by the function steps of a tenant. The
+// code is a CommonJS module: it assigns what it provides to module.exports (or exports), and
+// scripts load it with require(name).
+type LibraryFunction struct {
+	ID          uuid.UUID  `json:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Code        string     `json:"code"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewLibraryFunction creates a new library function
+func NewLibraryFunction(tenantID uuid.UUID, name, description, code string, createdBy *uuid.UUID) *LibraryFunction {
+	now := time.Now().UTC()
+	return &LibraryFunction{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Name:        name,
+		Description: description,
+		Code:        code,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate checks the name and the code size of the library function
+func (f *LibraryFunction) Validate() error {
+	if !libraryFunctionNamePattern.MatchString(f.Name) {
+		return NewValidationError("name", "name must start with a letter and contain only letters, digits, '_' or '-' (max 64 characters)")
+	}
+	if strings.TrimSpace(f.Code) == "" {
+		return NewValidationError("code", "code is required")
+	}
+	if len(f.Code) > MaxLibraryFunctionCodeBytes {
+		return NewValidationError("code", fmt.Sprintf("code must not exceed %d bytes", MaxLibraryFunctionCodeBytes))
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Capabilities assumed for models the registry does not know
+const (
+	DefaultModelContextWindow   = 8192
+	DefaultModelMaxOutputTokens = 4096
+)
+
+// ModelCapabilities describes what an LLM model supports and its token limits
+type ModelCapabilities struct {
+	Provider           string `json:"provider"`             // LLM provider (openai, anthropic, google)
+	Model              string `json:"model"`                // Model identifier; dated versions match by prefix
+	ContextWindow      int    `json:"context_window"`       // Maximum input and output tokens of one request
+	MaxOutputTokens    int    `json:"max_output_tokens"`    // Maximum tokens the model generates in one response
+	SupportsJSONSchema bool   `json:"supports_json_schema"` // Native structured output constrained by a JSON schema
+	SupportsTools      bool   `json:"supports_tools"`       // Tool (function) calling
+	SupportsVision     bool   `json:"supports_vision"`      // Image input
+
+	// Known is false for the defaults LookupModelCapabilities returns for an unregistered model
+	Known bool `json:"known"`
+}
+
+// Pricing returns the token pricing of the model, or nil if it has none
+func (c ModelCapabilities) Pricing() *TokenPricing {
+	return GetPricing(c.Provider, c.Model)
+}
+
+// DefaultModelCapabilities contains the capabilities of common LLM models
+var DefaultModelCapabilities = []ModelCapabilities{
+	// OpenAI models
+	{Provider: "openai", Model: "gpt-4o", ContextWindow: 128000, MaxOutputTokens: 16384, SupportsJSONSchema: true, SupportsTools: true, SupportsVision: true},
+	{Provider: "openai", Model: "gpt-4o-mini", ContextWindow: 128000, MaxOutputTokens: 16384, SupportsJSONSchema: true, SupportsTools: true, SupportsVision: true},
+	{Provider: "openai", Model: "gpt-4-turbo", ContextWindow: 128000, MaxOutputTokens: 4096, SupportsTools: true, SupportsVision: true},
+	{Provider: "openai", Model: "gpt-4", ContextWindow: 8192, MaxOutputTokens: 8192, SupportsTools: true},
+	{Provider: "openai", Model: "gpt-3.5-turbo", ContextWindow: 16385, MaxOutputTokens: 4096, SupportsTools: true},
+
+	// Anthropic models
+	{Provider: "anthropic", Model: "claude-3-opus", ContextWindow: 200000, MaxOutputTokens: 4096, SupportsTools: true, SupportsVision: true},
+	{Provider: "anthropic", Model: "claude-3-sonnet", ContextWindow: 200000, MaxOutputTokens: 4096, SupportsTools: true, SupportsVision: true},
+	{Provider: "anthropic", Model: "claude-3-haiku", ContextWindow: 200000, MaxOutputTokens: 4096, SupportsTools: true, SupportsVision: true},
+	{Provider: "anthropic", Model: "claude-3-5-sonnet", ContextWindow: 200000, MaxOutputTokens: 8192, SupportsTools: true, SupportsVision: true},
+	{Provider: "anthropic", Model: "claude-3-5-haiku", ContextWindow: 200000, MaxOutputTokens: 8192, SupportsTools: true},
+	{Provider: "anthropic", Model: "claude-sonnet-4", ContextWindow: 200000, MaxOutputTokens: 64000, SupportsTools: true, SupportsVision: true},
+
+	// Google models
+	{Provider: "google", Model: "gemini-1.5-pro", ContextWindow: 2097152, MaxOutputTokens: 8192, SupportsJSONSchema: true, SupportsTools: true, SupportsVision: true},
+	{Provider: "google", Model: "gemini-1.5-flash", ContextWindow: 1048576, MaxOutputTokens: 8192, SupportsJSONSchema: true, SupportsTools: true, SupportsVision: true},
+	{Provider: "google", Model: "gemini-1.0-pro", ContextWindow: 32760, MaxOutputTokens: 8192, SupportsTools: true},
+}
+
+var (
+	modelCapabilitiesMu sync.RWMutex
+	modelCapabilities   = withKnown(DefaultModelCapabilities)
+)
+
+// withKnown returns a copy of the entries marked as known
+func withKnown(entries []ModelCapabilities) []ModelCapabilities {
+	known := make([]ModelCapabilities, len(entries))
+	for i, entry := range entries {
+		entry.Known = true
+		known[i] = entry
+	}
+	return known
+}
+
+// LoadModelCapabilities adds the models of a JSON array of capabilities to the registry
+// (MODEL_CAPABILITIES_FILE). An entry for a provider and model already registered replaces it,
+// so a deployment can correct the built-in values as well as add its own models.
+func LoadModelCapabilities(data []byte) error {
+	var entries []ModelCapabilities
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("invalid model capabilities: %w", err)
+	}
+	for i, entry := range entries {
+		if entry.Provider == "" || entry.Model == "" {
+			return fmt.Errorf("model capabilities[%d]: provider and model are required", i)
+		}
+		if entry.ContextWindow < 0 || entry.MaxOutputTokens < 0 {
+			return fmt.Errorf("model capabilities[%d]: token limits must not be negative", i)
+		}
+	}
+
+	modelCapabilitiesMu.Lock()
+	defer modelCapabilitiesMu.Unlock()
+	for _, entry := range withKnown(entries) {
+		replaced := false
+		for i := range modelCapabilities {
+			if modelCapabilities[i].Provider == entry.Provider && modelCapabilities[i].Model == entry.Model {
+				modelCapabilities[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			modelCapabilities = append(modelCapabilities, entry)
+		}
+	}
+	return nil
+}
+
+// ResetModelCapabilities restores the built-in registry (used by tests)
+func ResetModelCapabilities() {
+	modelCapabilitiesMu.Lock()
+	defer modelCapabilitiesMu.Unlock()
+	modelCapabilities = withKnown(DefaultModelCapabilities)
+}
+
+// GetModelCapabilities returns the capabilities of a provider's model. A dated or suffixed model
+// name (e.g. gpt-4o-2024-08-06) matches the longest known model it starts with.
+// Returns nil if the model is unknown.
+func GetModelCapabilities(provider, model string) *ModelCapabilities {
+	modelCapabilitiesMu.RLock()
+	defer modelCapabilitiesMu.RUnlock()
+
+	var best *ModelCapabilities
+	for i := range modelCapabilities {
+		caps := &modelCapabilities[i]
+		if caps.Provider != provider || !strings.HasPrefix(model, caps.Model) {
+			continue
+		}
+		if len(model) > len(caps.Model) && model[len(caps.Model)] != '-' {
+			continue
+		}
+		if best == nil || len(caps.Model) > len(best.Model) {
+			best = caps
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	found := *best
+	return &found
+}
+
+// LookupModelCapabilities returns the capabilities of a provider's model, or conservative
+// defaults (Known false) for an unknown model: a small context window and no optional features
+func LookupModelCapabilities(provider, model string) ModelCapabilities {
+	if caps := GetModelCapabilities(provider, model); caps != nil {
+		return *caps
+	}
+	return ModelCapabilities{
+		Provider:        provider,
+		Model:           model,
+		ContextWindow:   DefaultModelContextWindow,
+		MaxOutputTokens: DefaultModelMaxOutputTokens,
+	}
+}
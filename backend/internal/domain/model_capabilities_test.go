@@ -0,0 +1,92 @@
+package domain
+
+import "testing"
+
+func TestGetModelCapabilities(t *testing.T) {
+	tests := []struct {
+		provider, model string
+		wantModel       string // "" when unknown
+	}{
+		{"openai", "gpt-4o", "gpt-4o"},
+		{"openai", "gpt-4o-2024-08-06", "gpt-4o"},
+		{"openai", "gpt-4o-mini-2024-07-18", "gpt-4o-mini"},
+		{"openai", "gpt-4-0613", "gpt-4"},
+		{"anthropic", "claude-3-5-haiku-20241022", "claude-3-5-haiku"},
+		{"openai", "gpt-4oo", ""},
+		{"anthropic", "gpt-4o", ""},
+		{"openai", "", ""},
+	}
+	for _, tt := range tests {
+		caps := GetModelCapabilities(tt.provider, tt.model)
+		if tt.wantModel == "" {
+			if caps != nil {
+				t.Errorf("GetModelCapabilities(%q, %q) = %s, want nil", tt.provider, tt.model, caps.Model)
+			}
+			continue
+		}
+		if caps == nil || caps.Model != tt.wantModel {
+			t.Errorf("GetModelCapabilities(%q, %q) = %v, want %s", tt.provider, tt.model, caps, tt.wantModel)
+		}
+	}
+}
+
+func TestLookupModelCapabilities(t *testing.T) {
+	caps := LookupModelCapabilities("openai", "gpt-4o-2024-08-06")
+	if !caps.Known || caps.ContextWindow != 128000 || !caps.SupportsJSONSchema || !caps.SupportsTools || !caps.SupportsVision {
+		t.Errorf("LookupModelCapabilities(gpt-4o-2024-08-06) = %+v", caps)
+	}
+	if pricing := caps.Pricing(); pricing == nil || pricing.Model != "gpt-4o" {
+		t.Errorf("Pricing() = %+v, want the gpt-4o pricing", pricing)
+	}
+
+	// Unknown models get conservative defaults
+	unknown := LookupModelCapabilities("openai", "in-house-llm")
+	if unknown.Known {
+		t.Error("Known = true for an unregistered model")
+	}
+	if unknown.Provider != "openai" || unknown.Model != "in-house-llm" {
+		t.Errorf("Provider, Model = %q, %q; want the requested model", unknown.Provider, unknown.Model)
+	}
+	if unknown.ContextWindow != DefaultModelContextWindow || unknown.MaxOutputTokens != DefaultModelMaxOutputTokens {
+		t.Errorf("limits = %d/%d, want the defaults", unknown.ContextWindow, unknown.MaxOutputTokens)
+	}
+	if unknown.SupportsJSONSchema || unknown.SupportsTools || unknown.SupportsVision {
+		t.Errorf("unknown model has optional features: %+v", unknown)
+	}
+	if unknown.Pricing() != nil {
+		t.Error("Pricing() != nil for an unknown model")
+	}
+}
+
+func TestLoadModelCapabilities(t *testing.T) {
+	t.Cleanup(ResetModelCapabilities)
+
+	err := LoadModelCapabilities([]byte(`[
+		{"provider": "openai", "model": "in-house-llm", "context_window": 32000, "max_output_tokens": 2000, "supports_tools": true},
+		{"provider": "openai", "model": "gpt-4", "context_window": 8192, "max_output_tokens": 4096}
+	]`))
+	if err != nil {
+		t.Fatalf("LoadModelCapabilities() error = %v", err)
+	}
+
+	added := LookupModelCapabilities("openai", "in-house-llm")
+	if !added.Known || added.ContextWindow != 32000 || added.MaxOutputTokens != 2000 || !added.SupportsTools || added.SupportsVision {
+		t.Errorf("added model = %+v", added)
+	}
+	if replaced := LookupModelCapabilities("openai", "gpt-4"); replaced.MaxOutputTokens != 4096 || replaced.SupportsTools {
+		t.Errorf("replaced model = %+v, want the loaded values", replaced)
+	}
+	if kept := LookupModelCapabilities("openai", "gpt-4o"); kept.MaxOutputTokens != 16384 {
+		t.Errorf("built-in model = %+v, want unchanged", kept)
+	}
+
+	for _, invalid := range []string{
+		`{"provider": "openai"}`,
+		`[{"provider": "openai", "context_window": 1000}]`,
+		`[{"provider": "openai", "model": "x", "max_output_tokens": -1}]`,
+	} {
+		if err := LoadModelCapabilities([]byte(invalid)); err == nil {
+			t.Errorf("LoadModelCapabilities(%s) error = nil, want error", invalid)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannelType represents the delivery mechanism of a notification channel
+type NotificationChannelType string
+
+const (
+	// NotificationChannelSlack posts to a Slack incoming webhook
+	NotificationChannelSlack NotificationChannelType = "slack"
+	// NotificationChannelEmail sends an email through SendGrid
+	NotificationChannelEmail NotificationChannelType = "email"
+	// NotificationChannelWebhook posts the notification as JSON to a URL
+	NotificationChannelWebhook NotificationChannelType = "webhook"
+)
+
+// ValidNotificationChannelTypes returns all valid notification channel types
+func ValidNotificationChannelTypes() []NotificationChannelType {
+	return []NotificationChannelType{
+		NotificationChannelSlack,
+		NotificationChannelEmail,
+		NotificationChannelWebhook,
+	}
+}
+
+// IsValid checks if the channel type is valid
+func (t NotificationChannelType) IsValid() bool {
+	for _, valid := range ValidNotificationChannelTypes() {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationEvent represents a system event that channels can subscribe to
+type NotificationEvent string
+
+const (
+	// NotificationEventBudgetAlert is sent when spend crosses a budget's alert threshold
+	NotificationEventBudgetAlert NotificationEvent = "budget.alert"
+	// NotificationEventRunFailed is sent when a run fails
+	NotificationEventRunFailed NotificationEvent = "run.failed"
+	// NotificationEventApprovalRequested is sent when a human-in-loop step waits for approval
+	NotificationEventApprovalRequested NotificationEvent = "approval.requested"
+	// NotificationEventTest is sent by the channel test endpoint, whatever the channel subscribes to
+	NotificationEventTest NotificationEvent = "notification.test"
+)
+
+// ValidNotificationEvents returns the events channels can subscribe to
+func ValidNotificationEvents() []NotificationEvent {
+	return []NotificationEvent{
+		NotificationEventBudgetAlert,
+		NotificationEventRunFailed,
+		NotificationEventApprovalRequested,
+	}
+}
+
+// IsValid checks if the event can be subscribed to
+func (e NotificationEvent) IsValid() bool {
+	for _, valid := range ValidNotificationEvents() {
+		if e == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannel is a tenant's destination for system event notifications. Config holds the
+// settings of its type: SlackChannelConfig, EmailChannelConfig or WebhookChannelConfig.
+type NotificationChannel struct {
+	ID        uuid.UUID               `json:"id"`
+	TenantID  uuid.UUID               `json:"tenant_id"`
+	Name      string                  `json:"name"`
+	Type      NotificationChannelType `json:"type"`
+	Config    json.RawMessage         `json:"config"`
+	Events    []NotificationEvent     `json:"events"` // Empty subscribes to every event
+	Enabled   bool                    `json:"enabled"`
+	CreatedBy *uuid.UUID              `json:"created_by,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+// NewNotificationChannel creates a new enabled notification channel
+func NewNotificationChannel(tenantID uuid.UUID, name string, channelType NotificationChannelType, config json.RawMessage, events []NotificationEvent, createdBy *uuid.UUID) *NotificationChannel {
+	now := time.Now().UTC()
+	return &NotificationChannel{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Name:      name,
+		Type:      channelType,
+		Config:    config,
+		Events:    events,
+		Enabled:   true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Subscribes reports whether the channel receives notifications of the event
+func (c *NotificationChannel) Subscribes(event NotificationEvent) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range c.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the name, type, events and the config of the channel type
+func (c *NotificationChannel) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return NewValidationError("name", "name is required")
+	}
+	if !c.Type.IsValid() {
+		return NewValidationError("type", "type must be one of slack, email, webhook")
+	}
+	for _, event := range c.Events {
+		if !event.IsValid() {
+			return NewValidationError("events", fmt.Sprintf("unknown event %q", event))
+		}
+	}
+
+	switch c.Type {
+	case NotificationChannelSlack:
+		var config SlackChannelConfig
+		if err := json.Unmarshal(c.Config, &config); err != nil {
+			return NewValidationError("config", "invalid slack config")
+		}
+		return validateNotificationURL("config.webhook_url", config.WebhookURL)
+	case NotificationChannelEmail:
+		var config EmailChannelConfig
+		if err := json.Unmarshal(c.Config, &config); err != nil {
+			return NewValidationError("config", "invalid email config")
+		}
+		if len(config.To) == 0 {
+			return NewValidationError("config.to", "at least one recipient is required")
+		}
+		for _, address := range config.To {
+			if !strings.Contains(address, "@") {
+				return NewValidationError("config.to", fmt.Sprintf("invalid email address %q", address))
+			}
+		}
+		if config.From != "" && !strings.Contains(config.From, "@") {
+			return NewValidationError("config.from", fmt.Sprintf("invalid email address %q", config.From))
+		}
+	case NotificationChannelWebhook:
+		var config WebhookChannelConfig
+		if err := json.Unmarshal(c.Config, &config); err != nil {
+			return NewValidationError("config", "invalid webhook config")
+		}
+		return validateNotificationURL("config.url", config.URL)
+	}
+	return nil
+}
+
+func validateNotificationURL(field, value string) error {
+	if value == "" {
+		return NewValidationError(field, "url is required")
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return NewValidationError(field, "url must be an http or https URL")
+	}
+	return nil
+}
+
+// SlackChannelConfig configures a Slack channel
+type SlackChannelConfig struct {
+	WebhookURL string `json:"webhook_url"` // Slack incoming webhook URL
+}
+
+// EmailChannelConfig configures an email channel. The SendGrid API key comes from the
+// SENDGRID_API_KEY environment variable, not from the tenant.
+type EmailChannelConfig struct {
+	To   []string `json:"to"`
+	From string   `json:"from,omitempty"` // Defaults to NOTIFICATION_EMAIL_FROM
+}
+
+// WebhookChannelConfig configures a generic webhook channel
+type WebhookChannelConfig struct {
+	URL     string            `json:"url"`
+	Secret  string            `json:"secret,omitempty"` // Signs the body with HMAC-SHA256 in X-Webhook-Signature
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Notification is a system event message delivered to notification channels
+type Notification struct {
+	Event     NotificationEvent      `json:"event"`
+	Title     string                 `json:"title"`
+	Message   string                 `json:"message"`
+	URL       string                 `json:"url,omitempty"` // Link to the resource, e.g. the run or the approval
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
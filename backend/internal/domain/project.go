@@ -2,6 +2,8 @@ package domain
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,6 +38,7 @@ type Project struct {
 	Status      ProjectStatus   `json:"status"`
 	Version     int             `json:"version"`             // Current saved version (0 = never saved)
 	Variables   json.RawMessage `json:"variables,omitempty"` // Project-level shared variables
+	Tags        []string        `json:"tags"`                // Normalized (lowercase) organizational tags
 	Draft       json.RawMessage `json:"draft,omitempty"`     // Draft state (unsaved changes)
 	CreatedBy   *uuid.UUID      `json:"created_by,omitempty"`
 	PublishedAt *time.Time      `json:"published_at,omitempty"`
@@ -51,6 +54,18 @@ type Project struct {
 	ErrorWorkflowID     *uuid.UUID      `json:"error_workflow_id,omitempty"`     // Project to execute on failure
 	ErrorWorkflowConfig json.RawMessage `json:"error_workflow_config,omitempty"` // Error workflow configuration
 
+	// Opt-in retry of the whole run on retryable failures; nil disables it
+	RunRetry *RunRetryPolicy `json:"run_retry,omitempty"`
+
+	// Explicit selection of the run output; nil takes it from the terminal steps
+	RunOutput *RunOutputConfig `json:"run_output,omitempty"`
+
+	// Notification rules for failed runs; empty notifies the tenant channels subscribed to run.failed
+	FailureNotifications []FailureNotificationRule `json:"failure_notifications,omitempty"`
+
+	// Paused blocks every trigger (API, webhook, schedule) from starting runs; in-flight runs continue
+	Paused bool `json:"paused"`
+
 	// Loaded relations
 	Steps       []Step       `json:"steps,omitempty"`
 	Edges       []Edge       `json:"edges,omitempty"`
@@ -71,6 +86,7 @@ func NewProject(tenantID uuid.UUID, name, description string) *Project {
 		Status:      ProjectStatusDraft,
 		Version:     0, // No versions yet
 		Variables:   json.RawMessage(`{}`),
+		Tags:        []string{},
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -133,6 +149,49 @@ func (p *Project) IncrementVersion() {
 	}
 }
 
+// SetTags normalizes and sets the project's tags
+func (p *Project) SetTags(tags []string) {
+	p.Tags = NormalizeTags(tags)
+}
+
+// HasTags returns true if the project carries all of the given tags (AND semantics).
+// Matching is case-insensitive.
+func (p *Project) HasTags(tags []string) bool {
+	have := make(map[string]bool, len(p.Tags))
+	for _, t := range NormalizeTags(p.Tags) {
+		have[t] = true
+	}
+	for _, t := range NormalizeTags(tags) {
+		if !have[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeTags lowercases and trims tags, drops empty values and duplicates,
+// and returns them sorted so that storage and matching are case-insensitive.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ProjectTagCount represents a tag and the number of projects using it
+type ProjectTagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
 // GetStartSteps returns all Start blocks in this project
 func (p *Project) GetStartSteps() []Step {
 	var startSteps []Step
@@ -152,6 +211,8 @@ type ProjectVersion struct {
 	Definition json.RawMessage `json:"definition"`
 	SavedBy    *uuid.UUID      `json:"saved_by,omitempty"`
 	SavedAt    time.Time       `json:"saved_at"`
+	// ContentHash is set by the seeder for system projects (SHA-256 of the seed definition)
+	ContentHash string `json:"content_hash,omitempty"`
 }
 
 // ProjectDefinition contains the complete project structure for versioning
@@ -162,4 +223,6 @@ type ProjectDefinition struct {
 	Steps       []Step          `json:"steps"`
 	Edges       []Edge          `json:"edges"`
 	BlockGroups []BlockGroup    `json:"block_groups,omitempty"`
+
+	RunOutput *RunOutputConfig `json:"run_output,omitempty"`
 }
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowRole is a user's access level on a workflow. Each role includes the ones below it:
+// owner > editor > runner > viewer.
+type WorkflowRole string
+
+const (
+	// WorkflowRoleViewer allows reading the workflow, its steps and runs
+	WorkflowRoleViewer WorkflowRole = "viewer"
+	// WorkflowRoleRunner also allows triggering runs
+	WorkflowRoleRunner WorkflowRole = "runner"
+	// WorkflowRoleEditor also allows changing the workflow
+	WorkflowRoleEditor WorkflowRole = "editor"
+	// WorkflowRoleOwner also allows managing the workflow's permissions
+	WorkflowRoleOwner WorkflowRole = "owner"
+)
+
+// ValidWorkflowRoles returns all valid workflow roles, from the lowest to the highest
+func ValidWorkflowRoles() []WorkflowRole {
+	return []WorkflowRole{
+		WorkflowRoleViewer,
+		WorkflowRoleRunner,
+		WorkflowRoleEditor,
+		WorkflowRoleOwner,
+	}
+}
+
+// level returns the rank of the role, 0 for an invalid role
+func (r WorkflowRole) level() int {
+	for i, role := range ValidWorkflowRoles() {
+		if r == role {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// IsValid checks if the workflow role is valid
+func (r WorkflowRole) IsValid() bool {
+	return r.level() > 0
+}
+
+// Allows reports whether the role grants the required role
+func (r WorkflowRole) Allows(required WorkflowRole) bool {
+	return r.IsValid() && r.level() >= required.level()
+}
+
+// ProjectPermission grants a user a role on a workflow. A workflow without permissions is open
+// to every user of the tenant; once it has any, only the listed users (and tenant admins) can
+// access it.
+type ProjectPermission struct {
+	TenantID  uuid.UUID    `json:"tenant_id"`
+	ProjectID uuid.UUID    `json:"project_id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Role      WorkflowRole `json:"role"`
+	GrantedBy *uuid.UUID   `json:"granted_by,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// NewProjectPermission creates a new project permission
+func NewProjectPermission(tenantID, projectID, userID uuid.UUID, role WorkflowRole, grantedBy *uuid.UUID) *ProjectPermission {
+	now := time.Now().UTC()
+	return &ProjectPermission{
+		TenantID:  tenantID,
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: grantedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -201,3 +201,55 @@ func TestProject_SetDraft_Nil(t *testing.T) {
 		t.Error("SetDraft(nil) should set HasDraft to false")
 	}
 }
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: []string{}},
+		{name: "lowercases and trims", tags: []string{" Sales ", "CRM"}, want: []string{"crm", "sales"}},
+		{name: "drops empty and duplicates", tags: []string{"ops", "", "OPS", "  "}, want: []string{"ops"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeTags(tt.tags)
+			if len(got) != len(tt.want) {
+				t.Fatalf("NormalizeTags() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("NormalizeTags()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProject_HasTags(t *testing.T) {
+	project := NewProject(uuid.New(), "Test", "")
+	project.SetTags([]string{"Sales", "crm", "weekly"})
+
+	tests := []struct {
+		name   string
+		filter []string
+		want   bool
+	}{
+		{name: "no filter matches", filter: nil, want: true},
+		{name: "single tag", filter: []string{"sales"}, want: true},
+		{name: "single tag case-insensitive", filter: []string{"CRM"}, want: true},
+		{name: "multiple tags all present", filter: []string{"sales", "weekly"}, want: true},
+		{name: "multiple tags one missing", filter: []string{"sales", "support"}, want: false},
+		{name: "single tag missing", filter: []string{"support"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := project.HasTags(tt.filter); got != tt.want {
+				t.Errorf("HasTags(%v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
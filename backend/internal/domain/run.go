@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"bytes"
 	"encoding/json"
 	"time"
 
@@ -47,18 +48,41 @@ type Run struct {
 	CompletedAt     *time.Time     `json:"completed_at,omitempty"`
 	CreatedAt       time.Time      `json:"created_at"`
 
-	// Internal trigger metadata (for TriggerTypeInternal)
-	TriggerSource   *string         `json:"trigger_source,omitempty"`   // e.g., "copilot", "audit-system"
-	TriggerMetadata json.RawMessage `json:"trigger_metadata,omitempty"` // e.g., {"feature": "generate", "user_id": "..."}
+	// Trigger metadata: the internal caller, the schedule or the webhook request that started the run
+	TriggerSource   *string         `json:"trigger_source,omitempty"`   // e.g., "copilot", "audit-system", "schedule", "webhook"
+	TriggerMetadata json.RawMessage `json:"trigger_metadata,omitempty"` // e.g., {"feature": "generate", "user_id": "..."}, {"schedule_id": "..."}
 
 	// Error Workflow tracking
 	ParentRunID        *uuid.UUID      `json:"parent_run_id,omitempty"`        // Parent run that triggered this error workflow
 	ErrorTriggerSource json.RawMessage `json:"error_trigger_source,omitempty"` // Error info from parent run
 
+	// Cancellation tracking
+	CancelledBy  *uuid.UUID `json:"cancelled_by,omitempty"`  // User who cancelled the run (nil for automated cancellations)
+	CancelReason *string    `json:"cancel_reason,omitempty"` // e.g., "wrong input", "budget exceeded"
+
+	// Aggregates persisted at completion, so list views need not load step runs
+	Summary *RunSummary `json:"summary,omitempty"`
+
+	// Automatic run retry tracking
+	RetryOfRunID *uuid.UUID `json:"retry_of_run_id,omitempty"` // First run of the retry chain; nil for the original run
+	RetryAttempt int        `json:"retry_attempt"`             // Retries before this run (0 for the original run)
+
+	// Variables resolved when the run was created. Execution, checkpoint resumes and automatic
+	// retries use the snapshot instead of the live variables, so replays are reproducible.
+	VariablesSnapshot *RunVariables `json:"-"`
+
 	// Loaded relations
 	StepRuns []StepRun `json:"step_runs,omitempty"`
 }
 
+// RunVariables is the snapshot of the organization, project and personal variables a run
+// executes with. Secret variables keep their stored, encrypted form.
+type RunVariables struct {
+	Org      map[string]interface{} `json:"org"`
+	Project  map[string]interface{} `json:"project"`
+	Personal map[string]interface{} `json:"personal"`
+}
+
 // NewRun creates a new run
 func NewRun(tenantID, projectID uuid.UUID, projectVersion int, input json.RawMessage, triggerType TriggerType) *Run {
 	return &Run{
@@ -113,11 +137,69 @@ func (r *Run) Fail(err string) {
 	r.CompletedAt = &now
 }
 
-// Cancel marks the run as cancelled
-func (r *Run) Cancel() {
+// Cancel marks the run as cancelled, recording who cancelled it (nil for automated
+// cancellations) and the optional reason
+func (r *Run) Cancel(cancelledBy *uuid.UUID, reason string) {
 	now := time.Now().UTC()
 	r.Status = RunStatusCancelled
 	r.CompletedAt = &now
+	r.CancelledBy = cancelledBy
+	r.CancelReason = nil
+	if reason != "" {
+		r.CancelReason = &reason
+	}
+}
+
+// RunSummaryPreviewLength is the maximum number of characters kept in RunSummary.OutputPreview
+const RunSummaryPreviewLength = 200
+
+// RunSummary aggregates a finished run's step runs and usage
+type RunSummary struct {
+	StepCount       int     `json:"step_count"`
+	FailedStepCount int     `json:"failed_step_count"`
+	TotalCostUSD    float64 `json:"total_cost_usd"`
+	TotalTokens     int     `json:"total_tokens"`
+	TotalDurationMs int64   `json:"total_duration_ms"` // Sum of the step run durations
+	OutputPreview   string  `json:"output_preview,omitempty"`
+}
+
+// NewRunSummary aggregates the step runs of every attempt of a run, its usage records, and the
+// start of its output
+func NewRunSummary(stepRuns []*StepRun, usage []UsageRecord, output json.RawMessage) *RunSummary {
+	summary := &RunSummary{StepCount: len(stepRuns)}
+	for _, sr := range stepRuns {
+		if sr.Status == StepRunStatusFailed {
+			summary.FailedStepCount++
+		}
+		if sr.DurationMs != nil {
+			summary.TotalDurationMs += int64(*sr.DurationMs)
+		}
+	}
+	for _, record := range usage {
+		summary.TotalTokens += record.TotalTokens
+		summary.TotalCostUSD += record.TotalCostUSD
+	}
+	summary.OutputPreview = outputPreview(output)
+	return summary
+}
+
+// outputPreview returns the compacted output truncated to RunSummaryPreviewLength characters
+func outputPreview(output json.RawMessage) string {
+	if len(output) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	preview := string(output)
+	if err := json.Compact(&buf, output); err == nil {
+		preview = buf.String()
+	}
+	if preview == "null" {
+		return ""
+	}
+	if runes := []rune(preview); len(runes) > RunSummaryPreviewLength {
+		preview = string(runes[:RunSummaryPreviewLength])
+	}
+	return preview
 }
 
 // DurationMs returns the duration in milliseconds
@@ -131,6 +213,12 @@ func (r *Run) DurationMs() *int64 {
 
 // SetInternalTrigger sets the internal trigger metadata
 func (r *Run) SetInternalTrigger(source string, metadata map[string]interface{}) error {
+	return r.SetTriggerSource(source, metadata)
+}
+
+// SetTriggerSource records what started the run, such as a schedule or a webhook request, and
+// its metadata
+func (r *Run) SetTriggerSource(source string, metadata map[string]interface{}) error {
 	r.TriggerSource = &source
 	if metadata != nil {
 		metaJSON, err := json.Marshal(metadata)
@@ -142,6 +230,34 @@ func (r *Run) SetInternalTrigger(source string, metadata map[string]interface{})
 	return nil
 }
 
+// TriggerInfo describes how the run was triggered, for {{$trigger}} templates and ctx.trigger:
+// type, triggered_by (the user ID, or nil), timestamp (creation time, RFC 3339) and source, plus
+// the keys of the trigger metadata such as schedule_id, or remote_ip and user_agent of a webhook
+// request. Metadata keys never replace the fixed keys.
+func (r *Run) TriggerInfo() map[string]interface{} {
+	info := make(map[string]interface{})
+	if len(r.TriggerMetadata) > 0 {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal(r.TriggerMetadata, &metadata); err == nil {
+			for key, value := range metadata {
+				info[key] = value
+			}
+		}
+	}
+
+	info["type"] = string(r.TriggeredBy)
+	info["triggered_by"] = nil
+	if r.TriggeredByUser != nil {
+		info["triggered_by"] = r.TriggeredByUser.String()
+	}
+	info["timestamp"] = r.CreatedAt.UTC().Format(time.RFC3339)
+	info["source"] = nil
+	if r.TriggerSource != nil {
+		info["source"] = *r.TriggerSource
+	}
+	return info
+}
+
 // ErrorTriggerInfo contains information about the error that triggered an error workflow
 type ErrorTriggerInfo struct {
 	OriginalRunID   uuid.UUID `json:"original_run_id"`
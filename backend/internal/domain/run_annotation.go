@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxRunAnnotationLength is the maximum length of an annotation comment in characters
+const MaxRunAnnotationLength = 10000
+
+// RunAnnotation is a user note attached to a run, optionally pointing at one of its steps
+type RunAnnotation struct {
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	RunID     uuid.UUID  `json:"run_id"`
+	StepID    *uuid.UUID `json:"step_id,omitempty"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+	Comment   string     `json:"comment"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewRunAnnotation creates a new run annotation
+func NewRunAnnotation(tenantID, runID, authorID uuid.UUID, stepID *uuid.UUID, comment string) *RunAnnotation {
+	now := time.Now().UTC()
+	return &RunAnnotation{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		RunID:     runID,
+		StepID:    stepID,
+		AuthorID:  authorID,
+		Comment:   strings.TrimSpace(comment),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the annotation comment
+func (a *RunAnnotation) Validate() error {
+	if a.Comment == "" {
+		return NewValidationError("comment", "comment is required")
+	}
+	if len([]rune(a.Comment)) > MaxRunAnnotationLength {
+		return NewValidationError("comment", "comment is too long")
+	}
+	return nil
+}
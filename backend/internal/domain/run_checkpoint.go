@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunCheckpoint is the state of a run after a step marked with `checkpoint: true` completed.
+// Outputs holds every step (and block group) output accumulated up to that point, keyed by ID,
+// so a retry can inject them and continue downstream of the checkpoint step.
+// OutputPorts holds the output port each of those steps and groups completed with, so a retry
+// also continues parallel branches that had not finished when the checkpoint was taken.
+type RunCheckpoint struct {
+	ID          uuid.UUID                  `json:"id"`
+	TenantID    uuid.UUID                  `json:"tenant_id"`
+	RunID       uuid.UUID                  `json:"run_id"`
+	StepID      uuid.UUID                  `json:"step_id"`
+	OutputPort  string                     `json:"output_port"`
+	Outputs     map[string]json.RawMessage `json:"outputs"`
+	OutputPorts map[string]string          `json:"output_ports,omitempty"`
+	CreatedAt   time.Time                  `json:"created_at"`
+}
+
+// NewRunCheckpoint creates a new run checkpoint
+func NewRunCheckpoint(tenantID, runID, stepID uuid.UUID, outputPort string, outputs map[string]json.RawMessage) *RunCheckpoint {
+	return &RunCheckpoint{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		RunID:      runID,
+		StepID:     stepID,
+		OutputPort: outputPort,
+		Outputs:    outputs,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RunOutputConfig selects the output of a workflow's runs explicitly, giving webhook and callback
+// consumers a stable output contract. Without it the run output is taken from the terminal steps
+// (steps without outgoing edges): the output of the only terminal step, or a map keyed by step ID
+// when there are several.
+type RunOutputConfig struct {
+	StepID  *uuid.UUID           `json:"step_id,omitempty"` // Step whose output is the run output
+	Mapping map[string]uuid.UUID `json:"mapping,omitempty"` // Run output fields and the step whose output each field holds
+}
+
+// Validate checks that exactly one of step_id and mapping is set and that every step exists
+func (c *RunOutputConfig) Validate(steps []Step) error {
+	if (c.StepID == nil) == (len(c.Mapping) == 0) {
+		return NewValidationError("run_output", "set either step_id or mapping")
+	}
+	known := make(map[uuid.UUID]bool, len(steps))
+	for _, step := range steps {
+		known[step.ID] = true
+	}
+	if c.StepID != nil && !known[*c.StepID] {
+		return NewValidationError("run_output.step_id", fmt.Sprintf("step %s is not in the workflow", *c.StepID))
+	}
+	for field, stepID := range c.Mapping {
+		if field == "" {
+			return NewValidationError("run_output.mapping", "mapping fields must not be empty")
+		}
+		if !known[stepID] {
+			return NewValidationError("run_output.mapping", fmt.Sprintf("step %s of field %q is not in the workflow", stepID, field))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Error categories of failed runs, matched by the retry_on list of a RunRetryPolicy and the
+// categories of a FailureNotificationRule
+const (
+	RunErrorCategoryTimeout     = "timeout"      // A step or request timed out
+	RunErrorCategoryRateLimit   = "rate_limit"   // A provider rejected the request with 429
+	RunErrorCategoryNetwork     = "network"      // Connection refused, reset or DNS failure
+	RunErrorCategoryServerError = "server_error" // A provider answered with a 5xx status
+	RunErrorCategoryMaxDuration = "max_duration" // The run exceeded its maximum wall-clock duration
+	RunErrorCategoryOther       = "other"
+)
+
+// DefaultRunRetryCategories are retried when a policy does not list its own
+var DefaultRunRetryCategories = []string{
+	RunErrorCategoryTimeout,
+	RunErrorCategoryRateLimit,
+	RunErrorCategoryNetwork,
+	RunErrorCategoryServerError,
+}
+
+const (
+	// MaxRunRetryAttempts caps the runs of a retry chain, including the original run
+	MaxRunRetryAttempts = 10
+	// MaxRunRetryBackoff caps the delay before a retry
+	MaxRunRetryBackoff = time.Hour
+	// defaultRunRetryBackoffMultiplier grows the delay between consecutive retries
+	defaultRunRetryBackoffMultiplier = 2
+)
+
+// RunRetryPolicy re-runs a whole workflow when a run fails with a retryable error. It is opt-in
+// per project, since a fresh run repeats the side effects of every step that already succeeded.
+type RunRetryPolicy struct {
+	MaxAttempts       int      `json:"max_attempts"`                 // Runs of the chain including the original; 1 disables retries
+	BackoffSeconds    int      `json:"backoff_seconds,omitempty"`    // Delay before the first retry
+	BackoffMultiplier float64  `json:"backoff_multiplier,omitempty"` // Growth of the delay per retry (default 2)
+	RetryOn           []string `json:"retry_on,omitempty"`           // Error categories to retry (default DefaultRunRetryCategories)
+}
+
+// Validate checks the policy limits and error categories
+func (p *RunRetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 || p.MaxAttempts > MaxRunRetryAttempts {
+		return NewValidationError("run_retry.max_attempts", fmt.Sprintf("max_attempts must be between 1 and %d", MaxRunRetryAttempts))
+	}
+	if p.BackoffSeconds < 0 {
+		return NewValidationError("run_retry.backoff_seconds", "backoff_seconds must not be negative")
+	}
+	if p.BackoffMultiplier != 0 && p.BackoffMultiplier < 1 {
+		return NewValidationError("run_retry.backoff_multiplier", "backoff_multiplier must be at least 1")
+	}
+	for _, category := range p.RetryOn {
+		if !IsRunErrorCategory(category) {
+			return NewValidationError("run_retry.retry_on", fmt.Sprintf("unknown error category %q", category))
+		}
+	}
+	return nil
+}
+
+// IsRunErrorCategory checks if category is one of the RunErrorCategory* values
+func IsRunErrorCategory(category string) bool {
+	switch category {
+	case RunErrorCategoryTimeout, RunErrorCategoryRateLimit, RunErrorCategoryNetwork,
+		RunErrorCategoryServerError, RunErrorCategoryMaxDuration, RunErrorCategoryOther:
+		return true
+	}
+	return false
+}
+
+// Retries reports whether a failed run of an error category is retried
+func (p *RunRetryPolicy) Retries(category string) bool {
+	categories := p.RetryOn
+	if len(categories) == 0 {
+		categories = DefaultRunRetryCategories
+	}
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay before the retry that follows the given number of earlier retries
+func (p *RunRetryPolicy) Backoff(retryAttempt int) time.Duration {
+	multiplier := p.BackoffMultiplier
+	if multiplier == 0 {
+		multiplier = defaultRunRetryBackoffMultiplier
+	}
+	delay := time.Duration(float64(p.BackoffSeconds) * math.Pow(multiplier, float64(retryAttempt)) * float64(time.Second))
+	if delay > MaxRunRetryBackoff || delay < 0 {
+		return MaxRunRetryBackoff
+	}
+	return delay
+}
+
+// NextRetry returns the run that retries a failed run of the given error category, or nil when
+// the category is not retried or the chain has used up its attempts. The retry is a fresh run
+// with the same input and variables snapshot, linked to the first run of the chain.
+func (p *RunRetryPolicy) NextRetry(run *Run, category string) *Run {
+	if p == nil || run.Status != RunStatusFailed || !p.Retries(category) {
+		return nil
+	}
+	if run.RetryAttempt+1 >= p.MaxAttempts {
+		return nil
+	}
+
+	retry := NewRun(run.TenantID, run.ProjectID, run.ProjectVersion, run.Input, run.TriggeredBy)
+	retry.StartStepID = run.StartStepID
+	retry.TriggeredByUser = run.TriggeredByUser
+	retry.TriggerSource = run.TriggerSource
+	retry.TriggerMetadata = run.TriggerMetadata
+	retry.VariablesSnapshot = run.VariablesSnapshot
+	retry.RetryAttempt = run.RetryAttempt + 1
+	origin := run.RetryChainID()
+	retry.RetryOfRunID = &origin
+	return retry
+}
+
+// RetryChainID returns the ID of the first run of the retry chain the run belongs to
+func (r *Run) RetryChainID() uuid.UUID {
+	if r.RetryOfRunID != nil {
+		return *r.RetryOfRunID
+	}
+	return r.ID
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func failedRun(run *Run) *Run {
+	run.Start()
+	run.Fail("OpenAI API returned status 503: overloaded")
+	return run
+}
+
+func TestRunRetryPolicy_RetriesUpToLimitThenGivesUp(t *testing.T) {
+	policy := &RunRetryPolicy{MaxAttempts: 3, BackoffSeconds: 10}
+	original := failedRun(NewRun(uuid.New(), uuid.New(), 2, json.RawMessage(`{"q": "hi"}`), TriggerTypeManual))
+
+	run := original
+	var delays []time.Duration
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		delays = append(delays, policy.Backoff(run.RetryAttempt))
+		retry := policy.NextRetry(run, RunErrorCategoryServerError)
+		if retry == nil {
+			t.Fatalf("attempt %d: NextRetry() = nil, want a retry", attempt)
+		}
+		if retry.RetryAttempt != attempt {
+			t.Errorf("attempt %d: RetryAttempt = %d", attempt, retry.RetryAttempt)
+		}
+		if retry.RetryOfRunID == nil || *retry.RetryOfRunID != original.ID {
+			t.Errorf("attempt %d: RetryOfRunID = %v, want the original run %s", attempt, retry.RetryOfRunID, original.ID)
+		}
+		if retry.Status != RunStatusPending || retry.ID == run.ID {
+			t.Errorf("attempt %d: retry must be a fresh pending run, got %+v", attempt, retry)
+		}
+		if string(retry.Input) != string(original.Input) || retry.ProjectVersion != original.ProjectVersion {
+			t.Errorf("attempt %d: retry must repeat the input and version of the original run", attempt)
+		}
+		run = failedRun(retry)
+	}
+
+	if retry := policy.NextRetry(run, RunErrorCategoryServerError); retry != nil {
+		t.Errorf("NextRetry() after %d runs = %+v, want nil", policy.MaxAttempts, retry)
+	}
+	if want := []time.Duration{10 * time.Second, 20 * time.Second}; len(delays) != 2 || delays[0] != want[0] || delays[1] != want[1] {
+		t.Errorf("backoff delays = %v, want %v", delays, want)
+	}
+}
+
+func TestRunRetryPolicy_NextRetrySkips(t *testing.T) {
+	failed := failedRun(NewRun(uuid.New(), uuid.New(), 1, nil, TriggerTypeManual))
+	completed := NewRun(uuid.New(), uuid.New(), 1, nil, TriggerTypeManual)
+	completed.Complete(nil)
+
+	var disabled *RunRetryPolicy
+	policy := &RunRetryPolicy{MaxAttempts: 3}
+	tests := []struct {
+		name     string
+		policy   *RunRetryPolicy
+		run      *Run
+		category string
+	}{
+		{"no policy", disabled, failed, RunErrorCategoryTimeout},
+		{"single attempt", &RunRetryPolicy{MaxAttempts: 1}, failed, RunErrorCategoryTimeout},
+		{"category not retried by default", policy, failed, RunErrorCategoryOther},
+		{"category not listed", &RunRetryPolicy{MaxAttempts: 3, RetryOn: []string{RunErrorCategoryRateLimit}}, failed, RunErrorCategoryTimeout},
+		{"run not failed", policy, completed, RunErrorCategoryTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if retry := tt.policy.NextRetry(tt.run, tt.category); retry != nil {
+				t.Errorf("NextRetry() = %+v, want nil", retry)
+			}
+		})
+	}
+}
+
+func TestRunRetryPolicy_BackoffIsCapped(t *testing.T) {
+	policy := &RunRetryPolicy{MaxAttempts: 10, BackoffSeconds: 600, BackoffMultiplier: 3}
+	if got := policy.Backoff(5); got != MaxRunRetryBackoff {
+		t.Errorf("Backoff(5) = %v, want %v", got, MaxRunRetryBackoff)
+	}
+}
+
+func TestRunRetryPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RunRetryPolicy
+		wantErr bool
+	}{
+		{"valid", RunRetryPolicy{MaxAttempts: 3, BackoffSeconds: 30, RetryOn: []string{RunErrorCategoryTimeout}}, false},
+		{"zero attempts", RunRetryPolicy{MaxAttempts: 0}, true},
+		{"too many attempts", RunRetryPolicy{MaxAttempts: MaxRunRetryAttempts + 1}, true},
+		{"negative backoff", RunRetryPolicy{MaxAttempts: 2, BackoffSeconds: -1}, true},
+		{"shrinking backoff", RunRetryPolicy{MaxAttempts: 2, BackoffMultiplier: 0.5}, true},
+		{"unknown category", RunRetryPolicy{MaxAttempts: 2, RetryOn: []string{"bad_input"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package domain
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -127,7 +129,7 @@ func TestRun_Cancel(t *testing.T) {
 	run := NewRun(uuid.New(), uuid.New(), 1, nil, TriggerTypeManual)
 	run.Start()
 
-	run.Cancel()
+	run.Cancel(nil, "")
 
 	if run.Status != RunStatusCancelled {
 		t.Errorf("Cancel() Status = %v, want %v", run.Status, RunStatusCancelled)
@@ -135,6 +137,23 @@ func TestRun_Cancel(t *testing.T) {
 	if run.CompletedAt == nil {
 		t.Error("Cancel() CompletedAt should not be nil")
 	}
+	if run.CancelledBy != nil || run.CancelReason != nil {
+		t.Errorf("Cancel() CancelledBy = %v, CancelReason = %v, want nil", run.CancelledBy, run.CancelReason)
+	}
+}
+
+func TestRun_CancelWithReason(t *testing.T) {
+	run := NewRun(uuid.New(), uuid.New(), 1, nil, TriggerTypeManual)
+	userID := uuid.New()
+
+	run.Cancel(&userID, "wrong input")
+
+	if run.CancelledBy == nil || *run.CancelledBy != userID {
+		t.Errorf("Cancel() CancelledBy = %v, want %v", run.CancelledBy, userID)
+	}
+	if run.CancelReason == nil || *run.CancelReason != "wrong input" {
+		t.Errorf("Cancel() CancelReason = %v, want %q", run.CancelReason, "wrong input")
+	}
 }
 
 func TestRun_DurationMs(t *testing.T) {
@@ -244,3 +263,74 @@ func TestRun_SetErrorTrigger(t *testing.T) {
 		t.Errorf("GetErrorTriggerInfo() OriginalProject = %v, want %v", gotInfo.OriginalProject, info.OriginalProject)
 	}
 }
+
+func TestNewRunSummary(t *testing.T) {
+	ms := func(v int) *int { return &v }
+	stepRuns := []*StepRun{
+		{Status: StepRunStatusCompleted, Attempt: 1, DurationMs: ms(120)},
+		{Status: StepRunStatusFailed, Attempt: 1, DurationMs: ms(30)},
+		{Status: StepRunStatusCompleted, Attempt: 2, DurationMs: ms(250)},
+		{Status: StepRunStatusSkipped, Attempt: 2},
+	}
+	usage := []UsageRecord{
+		{TotalTokens: 1000, TotalCostUSD: 0.002},
+		{TotalTokens: 500, TotalCostUSD: 0.0005},
+	}
+
+	summary := NewRunSummary(stepRuns, usage, json.RawMessage(`{"answer": "42"}`))
+
+	// The summary matches the aggregates of the step runs and usage records
+	var wantFailed int
+	var wantDuration int64
+	for _, sr := range stepRuns {
+		if sr.Status == StepRunStatusFailed {
+			wantFailed++
+		}
+		if sr.DurationMs != nil {
+			wantDuration += int64(*sr.DurationMs)
+		}
+	}
+	if summary.StepCount != len(stepRuns) {
+		t.Errorf("StepCount = %d, want %d", summary.StepCount, len(stepRuns))
+	}
+	if summary.FailedStepCount != wantFailed {
+		t.Errorf("FailedStepCount = %d, want %d", summary.FailedStepCount, wantFailed)
+	}
+	if summary.TotalDurationMs != wantDuration {
+		t.Errorf("TotalDurationMs = %d, want %d", summary.TotalDurationMs, wantDuration)
+	}
+	if summary.TotalTokens != 1500 {
+		t.Errorf("TotalTokens = %d, want 1500", summary.TotalTokens)
+	}
+	if diff := summary.TotalCostUSD - 0.0025; diff > 1e-12 || diff < -1e-12 {
+		t.Errorf("TotalCostUSD = %v, want 0.0025", summary.TotalCostUSD)
+	}
+	if summary.OutputPreview != `{"answer":"42"}` {
+		t.Errorf("OutputPreview = %q, want compacted output", summary.OutputPreview)
+	}
+}
+
+func TestNewRunSummary_OutputPreview(t *testing.T) {
+	long, _ := json.Marshal(map[string]string{"text": strings.Repeat("あ", 300)})
+
+	tests := []struct {
+		name   string
+		output json.RawMessage
+		want   int // length of the preview in characters
+	}{
+		{"no output", nil, 0},
+		{"null output", json.RawMessage(`null`), 0},
+		{"truncated to the preview length", long, RunSummaryPreviewLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := NewRunSummary(nil, nil, tt.output)
+			if got := utf8.RuneCountInString(summary.OutputPreview); got != tt.want {
+				t.Errorf("OutputPreview length = %d, want %d", got, tt.want)
+			}
+			if summary.StepCount != 0 || summary.TotalTokens != 0 {
+				t.Errorf("empty run summary = %+v", summary)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +17,21 @@ const (
 	ScheduleStatusDisabled ScheduleStatus = "disabled"
 )
 
+// InvalidCronError reports a cron expression that cannot be parsed, with the parser's reason
+type InvalidCronError struct {
+	Expression string `json:"expression"`
+	Reason     string `json:"reason"`
+}
+
+func (e *InvalidCronError) Error() string {
+	return fmt.Sprintf("invalid cron expression %q: %s", e.Expression, e.Reason)
+}
+
+// Unwrap allows errors.Is(err, ErrScheduleInvalidCron)
+func (e *InvalidCronError) Unwrap() error {
+	return ErrScheduleInvalidCron
+}
+
 // Schedule represents a scheduled project execution
 type Schedule struct {
 	ID             uuid.UUID       `json:"id"`
@@ -33,6 +49,7 @@ type Schedule struct {
 	LastRunAt      *time.Time      `json:"last_run_at,omitempty"`
 	LastRunID      *uuid.UUID      `json:"last_run_id,omitempty"`
 	RunCount       int             `json:"run_count"`
+	BulkPaused     bool            `json:"bulk_paused,omitempty"` // Paused by pause-all; resume-all reactivates it
 	CreatedBy      *uuid.UUID      `json:"created_by,omitempty"`
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
@@ -71,18 +88,21 @@ func (s *Schedule) IsActive() bool {
 // Pause pauses the schedule
 func (s *Schedule) Pause() {
 	s.Status = ScheduleStatusPaused
+	s.BulkPaused = false
 	s.UpdatedAt = time.Now().UTC()
 }
 
 // Resume resumes a paused schedule
 func (s *Schedule) Resume() {
 	s.Status = ScheduleStatusActive
+	s.BulkPaused = false
 	s.UpdatedAt = time.Now().UTC()
 }
 
 // Disable disables the schedule
 func (s *Schedule) Disable() {
 	s.Status = ScheduleStatusDisabled
+	s.BulkPaused = false
 	s.UpdatedAt = time.Now().UTC()
 }
 
@@ -148,6 +148,7 @@ type Step struct {
 
 	// Credential bindings: maps required credential names to actual credential IDs
 	// Format: {"credential_name": "uuid-of-tenant-credential", ...}
+	// A binding can also be a template ({{$.credential_id}}) resolved at execution time
 	CredentialBindings json.RawMessage `json:"credential_bindings,omitempty"`
 
 	// Retry configuration for error handling
@@ -168,6 +169,11 @@ func (s *Step) GetCredentialBindings() (map[string]uuid.UUID, error) {
 	return ParseCredentialBindings(s.CredentialBindings)
 }
 
+// GetCredentialBindingRefs returns the credential bindings as written, including templates
+func (s *Step) GetCredentialBindingRefs() (map[string]string, error) {
+	return ParseCredentialBindingRefs(s.CredentialBindings)
+}
+
 // NewStep creates a new step
 func NewStep(tenantID, projectID uuid.UUID, name string, stepType StepType, config json.RawMessage) *Step {
 	now := time.Now().UTC()
@@ -314,9 +320,11 @@ type StartStepConfig struct {
 
 // WebhookTriggerConfig represents configuration for webhook-triggered Start blocks
 type WebhookTriggerConfig struct {
-	Secret       string          `json:"secret"`                  // Webhook secret for verification
-	InputMapping json.RawMessage `json:"input_mapping,omitempty"` // How to map webhook payload to input
-	Enabled      bool            `json:"enabled"`                 // Whether webhook is enabled
+	Secret             string          `json:"secret"`                         // Webhook secret for verification
+	SecretCredentialID *uuid.UUID      `json:"secret_credential_id,omitempty"` // Credential holding the secret, used instead of Secret
+	SignatureScheme    string          `json:"signature_scheme,omitempty"`     // github, stripe, slack or hmac (default: hmac)
+	InputMapping       json.RawMessage `json:"input_mapping,omitempty"`        // How to map webhook payload to input
+	Enabled            bool            `json:"enabled"`                        // Whether webhook is enabled
 }
 
 // ScheduleTriggerConfig represents configuration for schedule-triggered Start blocks
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// StepProfile aggregates the durations of one step's runs across several workflow runs
+type StepProfile struct {
+	StepID      uuid.UUID `json:"step_id"`
+	StepName    string    `json:"step_name"`
+	SampleCount int       `json:"sample_count"` // Step runs with a recorded duration
+	FailedCount int       `json:"failed_count"`
+	P50Ms       int       `json:"p50_ms"`
+	P95Ms       int       `json:"p95_ms"`
+	MaxMs       int       `json:"max_ms"`
+	TotalMs     int64     `json:"total_ms"`
+	TimeShare   float64   `json:"time_share"` // Fraction of the total duration of all profiled steps
+}
+
+// NewStepProfiles aggregates step runs by step, slowest first by p95 duration. Step runs without
+// a duration (not yet finished) are not sampled; steps with no sample are left out.
+func NewStepProfiles(stepRuns []*StepRun) []StepProfile {
+	durations := make(map[uuid.UUID][]int)
+	profiles := make(map[uuid.UUID]*StepProfile)
+	var totalMs int64
+	for _, sr := range stepRuns {
+		if sr.DurationMs == nil {
+			continue
+		}
+		profile, ok := profiles[sr.StepID]
+		if !ok {
+			profile = &StepProfile{StepID: sr.StepID, StepName: sr.StepName}
+			profiles[sr.StepID] = profile
+		}
+		if sr.Status == StepRunStatusFailed {
+			profile.FailedCount++
+		}
+		durations[sr.StepID] = append(durations[sr.StepID], *sr.DurationMs)
+		profile.TotalMs += int64(*sr.DurationMs)
+		totalMs += int64(*sr.DurationMs)
+	}
+
+	result := make([]StepProfile, 0, len(profiles))
+	for stepID, profile := range profiles {
+		samples := durations[stepID]
+		sort.Ints(samples)
+		profile.SampleCount = len(samples)
+		profile.P50Ms = DurationPercentile(samples, 50)
+		profile.P95Ms = DurationPercentile(samples, 95)
+		profile.MaxMs = samples[len(samples)-1]
+		if totalMs > 0 {
+			profile.TimeShare = float64(profile.TotalMs) / float64(totalMs)
+		}
+		result = append(result, *profile)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].P95Ms != result[j].P95Ms {
+			return result[i].P95Ms > result[j].P95Ms
+		}
+		if result[i].TotalMs != result[j].TotalMs {
+			return result[i].TotalMs > result[j].TotalMs
+		}
+		return result[i].StepName < result[j].StepName
+	})
+	return result
+}
+
+// DurationPercentile returns the nearest-rank percentile (0-100] of ascending sorted durations,
+// or 0 when there are none
+func DurationPercentile(sorted []int, percentile float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func stepRunWithDuration(stepID uuid.UUID, name string, status StepRunStatus, durationMs int) *StepRun {
+	sr := NewStepRun(uuid.New(), uuid.New(), stepID, name, 1)
+	sr.Status = status
+	sr.DurationMs = &durationMs
+	return sr
+}
+
+func TestDurationPercentile(t *testing.T) {
+	samples := []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	tests := []struct {
+		name       string
+		sorted     []int
+		percentile float64
+		want       int
+	}{
+		{"p50 of ten", samples, 50, 50},
+		{"p95 of ten", samples, 95, 100},
+		{"p90 of ten", samples, 90, 90},
+		{"p50 of odd count", []int{5, 7, 100}, 50, 7},
+		{"single sample", []int{42}, 95, 42},
+		{"no samples", nil, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DurationPercentile(tt.sorted, tt.percentile); got != tt.want {
+				t.Errorf("DurationPercentile(%v, %v) = %d, want %d", tt.sorted, tt.percentile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStepProfiles(t *testing.T) {
+	fetchID, summarizeID, pendingID := uuid.New(), uuid.New(), uuid.New()
+	var stepRuns []*StepRun
+	// fetch: 20 runs of 100..2000ms, one of which failed
+	for i := 1; i <= 20; i++ {
+		status := StepRunStatusCompleted
+		if i == 7 {
+			status = StepRunStatusFailed
+		}
+		stepRuns = append(stepRuns, stepRunWithDuration(fetchID, "fetch", status, i*100))
+	}
+	// summarize: consistently fast
+	for i := 0; i < 4; i++ {
+		stepRuns = append(stepRuns, stepRunWithDuration(summarizeID, "summarize", StepRunStatusCompleted, 50))
+	}
+	// A step run still in progress has no duration and is not sampled
+	stepRuns = append(stepRuns, NewStepRun(uuid.New(), uuid.New(), pendingID, "pending", 1))
+
+	profiles := NewStepProfiles(stepRuns)
+	if len(profiles) != 2 {
+		t.Fatalf("len(profiles) = %d, want 2: %+v", len(profiles), profiles)
+	}
+
+	fetch := profiles[0]
+	if fetch.StepID != fetchID {
+		t.Fatalf("slowest step = %s, want fetch", fetch.StepName)
+	}
+	if fetch.SampleCount != 20 || fetch.FailedCount != 1 {
+		t.Errorf("fetch samples = %d failed = %d, want 20 and 1", fetch.SampleCount, fetch.FailedCount)
+	}
+	if fetch.P50Ms != 1000 || fetch.P95Ms != 1900 || fetch.MaxMs != 2000 {
+		t.Errorf("fetch p50/p95/max = %d/%d/%d, want 1000/1900/2000", fetch.P50Ms, fetch.P95Ms, fetch.MaxMs)
+	}
+	if fetch.TotalMs != 21000 {
+		t.Errorf("fetch total = %d, want 21000", fetch.TotalMs)
+	}
+
+	summarize := profiles[1]
+	if summarize.P50Ms != 50 || summarize.P95Ms != 50 || summarize.MaxMs != 50 {
+		t.Errorf("summarize p50/p95/max = %d/%d/%d, want 50", summarize.P50Ms, summarize.P95Ms, summarize.MaxMs)
+	}
+	if share := fetch.TimeShare + summarize.TimeShare; math.Abs(share-1) > 1e-9 {
+		t.Errorf("time shares sum to %v, want 1", share)
+	}
+}
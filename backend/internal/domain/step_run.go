@@ -39,6 +39,11 @@ type StepRun struct {
 	// Debug features
 	PinnedInput     json.RawMessage `json:"pinned_input,omitempty"`     // Pinned input for debugging/replay
 	StreamingOutput json.RawMessage `json:"streaming_output,omitempty"` // Streaming output chunks
+	Evaluation      json.RawMessage `json:"evaluation,omitempty"`       // How a condition or switch step chose its branch
+	ItemIndex       *int            `json:"item_index,omitempty"`       // Map item a streaming map's item step ran for
+
+	// Set when output retention cleared Input and Output; status, timing and errors are kept
+	OutputsClearedAt *time.Time `json:"outputs_cleared_at,omitempty"`
 }
 
 // NewStepRun creates a new step run
@@ -105,7 +110,13 @@ func (sr *StepRun) Fail(err string) {
 
 // Skip marks the step run as skipped
 func (sr *StepRun) Skip() {
+	now := time.Now().UTC()
 	sr.Status = StepRunStatusSkipped
+	sr.CompletedAt = &now
+	if sr.StartedAt != nil {
+		ms := int(now.Sub(*sr.StartedAt).Milliseconds())
+		sr.DurationMs = &ms
+	}
 }
 
 // Retry increments the attempt counter and resets status
@@ -25,6 +25,24 @@ const (
 	TemplateReviewStatusRejected TemplateReviewStatus = "rejected"
 )
 
+// TemplateSort represents the ordering of marketplace template listings
+type TemplateSort string
+
+const (
+	TemplateSortRating     TemplateSort = "rating"     // Highest average rating first
+	TemplateSortPopularity TemplateSort = "popularity" // Most used first
+	TemplateSortRecent     TemplateSort = "recent"     // Newest first
+)
+
+// IsValid checks if the sort is valid. Empty means the default (featured, then popular) ordering.
+func (s TemplateSort) IsValid() bool {
+	switch s {
+	case "", TemplateSortRating, TemplateSortPopularity, TemplateSortRecent:
+		return true
+	}
+	return false
+}
+
 // ProjectTemplate represents a reusable workflow template
 type ProjectTemplate struct {
 	ID            uuid.UUID          `json:"id"`
@@ -149,6 +167,22 @@ func (t *ProjectTemplate) UpdateRating(newRating int) {
 	t.UpdatedAt = time.Now().UTC()
 }
 
+// RecalculateRating sets the aggregate rating (average and count) from all of the template's reviews.
+// Recomputing from scratch keeps the aggregate correct when reviews are edited or removed.
+func (t *ProjectTemplate) RecalculateRating(reviews []*TemplateReview) {
+	t.ReviewCount = len(reviews)
+	t.Rating = nil
+	if len(reviews) > 0 {
+		total := 0
+		for _, r := range reviews {
+			total += r.Rating
+		}
+		avg := float64(total) / float64(len(reviews))
+		t.Rating = &avg
+	}
+	t.UpdatedAt = time.Now().UTC()
+}
+
 // TemplateReview represents a user review for a template
 type TemplateReview struct {
 	ID         uuid.UUID `json:"id"`
@@ -193,3 +227,25 @@ func DefaultTemplateCategories() []TemplateCategory {
 		{Slug: "other", Name: "Other", Description: "Other workflows", Icon: "folder"},
 	}
 }
+
+// TemplateUsage records that a user created a project from a template
+type TemplateUsage struct {
+	ID         uuid.UUID  `json:"id"`
+	TemplateID uuid.UUID  `json:"template_id"`
+	TenantID   uuid.UUID  `json:"tenant_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	ProjectID  *uuid.UUID `json:"project_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewTemplateUsage creates a new template usage record
+func NewTemplateUsage(templateID, tenantID, userID, projectID uuid.UUID) *TemplateUsage {
+	return &TemplateUsage{
+		ID:         uuid.New(),
+		TemplateID: templateID,
+		TenantID:   tenantID,
+		UserID:     userID,
+		ProjectID:  &projectID,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
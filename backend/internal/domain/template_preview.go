@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// TemplateParameterSource identifies where a template parameter is declared
+type TemplateParameterSource string
+
+const (
+	TemplateParameterSourceInput    TemplateParameterSource = "input"    // Start block input_schema
+	TemplateParameterSourceVariable TemplateParameterSource = "variable" // Workflow variables
+)
+
+// TemplatePreview is a read-only structural view of a template for rendering a diagram.
+// It deliberately omits step configs, trigger configs, variable values, and credential IDs
+// so that private settings and secrets are never exposed.
+type TemplatePreview struct {
+	ID                  uuid.UUID              `json:"id"`
+	Name                string                 `json:"name"`
+	Description         string                 `json:"description,omitempty"`
+	Category            string                 `json:"category,omitempty"`
+	Tags                []string               `json:"tags,omitempty"`
+	AuthorName          string                 `json:"author_name,omitempty"`
+	Steps               []TemplatePreviewStep  `json:"steps"`
+	Edges               []TemplatePreviewEdge  `json:"edges"`
+	BlockGroups         []TemplatePreviewGroup `json:"block_groups"`
+	Parameters          []TemplatePreviewParam `json:"parameters"`
+	RequiredCredentials []string               `json:"required_credentials"`
+}
+
+// TemplatePreviewStep is a step without its configuration
+type TemplatePreviewStep struct {
+	ID           uuid.UUID        `json:"id"`
+	Name         string           `json:"name"`
+	Type         StepType         `json:"type"`
+	TriggerType  *StepTriggerType `json:"trigger_type,omitempty"`
+	BlockGroupID *uuid.UUID       `json:"block_group_id,omitempty"`
+	GroupRole    string           `json:"group_role,omitempty"`
+	PositionX    int              `json:"position_x"`
+	PositionY    int              `json:"position_y"`
+}
+
+// TemplatePreviewEdge is an edge without its condition expression
+type TemplatePreviewEdge struct {
+	ID                 uuid.UUID  `json:"id"`
+	SourceStepID       *uuid.UUID `json:"source_step_id,omitempty"`
+	TargetStepID       *uuid.UUID `json:"target_step_id,omitempty"`
+	SourceBlockGroupID *uuid.UUID `json:"source_block_group_id,omitempty"`
+	TargetBlockGroupID *uuid.UUID `json:"target_block_group_id,omitempty"`
+	SourcePort         string     `json:"source_port,omitempty"`
+}
+
+// TemplatePreviewGroup is a block group without its configuration or pre/post-process code
+type TemplatePreviewGroup struct {
+	ID            uuid.UUID      `json:"id"`
+	Name          string         `json:"name"`
+	Type          BlockGroupType `json:"type"`
+	ParentGroupID *uuid.UUID     `json:"parent_group_id,omitempty"`
+	PositionX     int            `json:"position_x"`
+	PositionY     int            `json:"position_y"`
+	Width         int            `json:"width"`
+	Height        int            `json:"height"`
+}
+
+// TemplatePreviewParam is a declared parameter; only its name and type are exposed, never its value
+type TemplatePreviewParam struct {
+	Name        string                  `json:"name"`
+	Type        string                  `json:"type,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Required    bool                    `json:"required"`
+	Source      TemplateParameterSource `json:"source"`
+	StepID      *uuid.UUID              `json:"step_id,omitempty"` // Start block declaring an input parameter
+}
+
+// NewTemplatePreview builds a sanitized preview from a template's definition
+func NewTemplatePreview(t *ProjectTemplate) (*TemplatePreview, error) {
+	def, err := t.GetDefinition()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &TemplatePreview{
+		ID:                  t.ID,
+		Name:                t.Name,
+		Description:         t.Description,
+		Category:            t.Category,
+		Tags:                t.Tags,
+		AuthorName:          t.AuthorName,
+		Steps:               make([]TemplatePreviewStep, 0, len(def.Steps)),
+		Edges:               make([]TemplatePreviewEdge, 0, len(def.Edges)),
+		BlockGroups:         make([]TemplatePreviewGroup, 0, len(def.BlockGroups)),
+		Parameters:          []TemplatePreviewParam{},
+		RequiredCredentials: []string{},
+	}
+
+	credentialNames := make(map[string]bool)
+	for _, step := range def.Steps {
+		preview.Steps = append(preview.Steps, TemplatePreviewStep{
+			ID:           step.ID,
+			Name:         step.Name,
+			Type:         step.Type,
+			TriggerType:  step.TriggerType,
+			BlockGroupID: step.BlockGroupID,
+			GroupRole:    step.GroupRole,
+			PositionX:    step.PositionX,
+			PositionY:    step.PositionY,
+		})
+
+		// Binding names describe what must be connected; the bound credential IDs are dropped
+		var bindings map[string]json.RawMessage
+		if len(step.CredentialBindings) > 0 && json.Unmarshal(step.CredentialBindings, &bindings) == nil {
+			for name := range bindings {
+				credentialNames[name] = true
+			}
+		}
+
+		if step.Type == StepTypeStart || step.TriggerType != nil {
+			stepID := step.ID
+			preview.Parameters = append(preview.Parameters, inputSchemaParams(&stepID, step.TriggerConfig, step.Config)...)
+		}
+	}
+
+	for _, edge := range def.Edges {
+		preview.Edges = append(preview.Edges, TemplatePreviewEdge{
+			ID:                 edge.ID,
+			SourceStepID:       edge.SourceStepID,
+			TargetStepID:       edge.TargetStepID,
+			SourceBlockGroupID: edge.SourceBlockGroupID,
+			TargetBlockGroupID: edge.TargetBlockGroupID,
+			SourcePort:         edge.SourcePort,
+		})
+	}
+
+	for _, group := range def.BlockGroups {
+		preview.BlockGroups = append(preview.BlockGroups, TemplatePreviewGroup{
+			ID:            group.ID,
+			Name:          group.Name,
+			Type:          group.Type,
+			ParentGroupID: group.ParentGroupID,
+			PositionX:     group.PositionX,
+			PositionY:     group.PositionY,
+			Width:         group.Width,
+			Height:        group.Height,
+		})
+	}
+
+	variables := def.Variables
+	if len(variables) == 0 {
+		variables = t.Variables
+	}
+	preview.Parameters = append(preview.Parameters, variableParams(variables)...)
+
+	for name := range credentialNames {
+		preview.RequiredCredentials = append(preview.RequiredCredentials, name)
+	}
+	sort.Strings(preview.RequiredCredentials)
+
+	return preview, nil
+}
+
+// inputSchemaParams extracts parameters from the first input_schema found in the given JSON objects
+func inputSchemaParams(stepID *uuid.UUID, sources ...json.RawMessage) []TemplatePreviewParam {
+	for _, source := range sources {
+		var holder struct {
+			InputSchema *InputSchema `json:"input_schema"`
+		}
+		if len(source) == 0 || json.Unmarshal(source, &holder) != nil || holder.InputSchema == nil {
+			continue
+		}
+		schema := holder.InputSchema
+		if len(schema.Properties) == 0 {
+			continue
+		}
+
+		required := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		params := make([]TemplatePreviewParam, 0, len(names))
+		for _, name := range names {
+			prop := schema.Properties[name]
+			params = append(params, TemplatePreviewParam{
+				Name:        name,
+				Type:        prop.Type,
+				Description: prop.Description,
+				Required:    required[name],
+				Source:      TemplateParameterSourceInput,
+				StepID:      stepID,
+			})
+		}
+		return params
+	}
+	return nil
+}
+
+// variableParams lists workflow variable names with their JSON type; values are never included
+func variableParams(variables json.RawMessage) []TemplatePreviewParam {
+	var values map[string]interface{}
+	if len(variables) == 0 || json.Unmarshal(variables, &values) != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]TemplatePreviewParam, 0, len(names))
+	for _, name := range names {
+		params = append(params, TemplatePreviewParam{
+			Name:   name,
+			Type:   jsonTypeName(values[name]),
+			Source: TemplateParameterSourceVariable,
+		})
+	}
+	return params
+}
+
+// jsonTypeName returns the JSON Schema type name of a decoded JSON value
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newPreviewTemplate(t *testing.T) (*ProjectTemplate, uuid.UUID) {
+	t.Helper()
+	tenantID := uuid.New()
+	credentialID := uuid.New()
+	manual := StepTriggerTypeManual
+
+	start := Step{
+		ID:            uuid.New(),
+		Name:          "Start",
+		Type:          StepTypeStart,
+		TriggerType:   &manual,
+		Config:        json.RawMessage(`{}`),
+		TriggerConfig: json.RawMessage(`{"webhook_secret":"whsec_super_secret","input_schema":{"type":"object","properties":{"email":{"type":"string","description":"Customer email"},"limit":{"type":"number"}},"required":["email"]}}`),
+	}
+	llm := Step{
+		ID:                 uuid.New(),
+		Name:               "Summarize",
+		Type:               StepTypeLLM,
+		Config:             json.RawMessage(`{"api_key":"sk-live-PRIVATE","prompt":"Summarize {{input.email}}"}`),
+		CredentialBindings: json.RawMessage(`{"openai":"` + credentialID.String() + `"}`),
+	}
+	notify := Step{
+		ID:                 uuid.New(),
+		Name:               "Notify",
+		Type:               "slack",
+		Config:             json.RawMessage(`{"webhook_url":"https://hooks.slack.com/services/T000/B000/XXXX"}`),
+		CredentialBindings: json.RawMessage(`{"slack":"` + uuid.New().String() + `","openai":""}`),
+	}
+	condition := "$.score > 0.9"
+	group := BlockGroup{ID: uuid.New(), Name: "Retry", Type: BlockGroupTypeWhile, Config: json.RawMessage(`{"token":"grp-secret"}`)}
+	notify.BlockGroupID = &group.ID
+
+	def := &TemplateDefinition{
+		Name:      "Support Triage",
+		Variables: json.RawMessage(`{"db_password":"hunter2","region":"eu","retries":3}`),
+		Steps:     []Step{start, llm, notify},
+		Edges: []Edge{
+			{ID: uuid.New(), SourceStepID: &start.ID, TargetStepID: &llm.ID},
+			{ID: uuid.New(), SourceStepID: &llm.ID, TargetBlockGroupID: &group.ID, SourcePort: "out", Condition: &condition},
+		},
+		BlockGroups: []BlockGroup{group},
+	}
+
+	template := NewProjectTemplate(&tenantID, "Support Triage", "Triage tickets", nil)
+	if err := template.SetDefinition(def); err != nil {
+		t.Fatalf("SetDefinition() error = %v", err)
+	}
+	return template, credentialID
+}
+
+func TestNewTemplatePreview_Structure(t *testing.T) {
+	template, _ := newPreviewTemplate(t)
+
+	preview, err := NewTemplatePreview(template)
+	if err != nil {
+		t.Fatalf("NewTemplatePreview() error = %v", err)
+	}
+
+	if len(preview.Steps) != 3 || len(preview.Edges) != 2 || len(preview.BlockGroups) != 1 {
+		t.Fatalf("got %d steps, %d edges, %d groups; want 3, 2, 1", len(preview.Steps), len(preview.Edges), len(preview.BlockGroups))
+	}
+	if preview.Steps[1].Name != "Summarize" || preview.Steps[1].Type != StepTypeLLM {
+		t.Errorf("Steps[1] = %+v, want Summarize (llm)", preview.Steps[1])
+	}
+	if preview.Steps[2].BlockGroupID == nil || *preview.Steps[2].BlockGroupID != preview.BlockGroups[0].ID {
+		t.Error("step group membership should be preserved")
+	}
+	if preview.Edges[1].TargetBlockGroupID == nil || preview.Edges[1].SourcePort != "out" {
+		t.Errorf("Edges[1] = %+v, want group target with port", preview.Edges[1])
+	}
+
+	wantParams := []struct {
+		name     string
+		typ      string
+		required bool
+		source   TemplateParameterSource
+	}{
+		{"email", "string", true, TemplateParameterSourceInput},
+		{"limit", "number", false, TemplateParameterSourceInput},
+		{"db_password", "string", false, TemplateParameterSourceVariable},
+		{"region", "string", false, TemplateParameterSourceVariable},
+		{"retries", "number", false, TemplateParameterSourceVariable},
+	}
+	if len(preview.Parameters) != len(wantParams) {
+		t.Fatalf("Parameters = %+v, want %d", preview.Parameters, len(wantParams))
+	}
+	for i, want := range wantParams {
+		got := preview.Parameters[i]
+		if got.Name != want.name || got.Type != want.typ || got.Required != want.required || got.Source != want.source {
+			t.Errorf("Parameters[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if strings.Join(preview.RequiredCredentials, ",") != "openai,slack" {
+		t.Errorf("RequiredCredentials = %v, want [openai slack]", preview.RequiredCredentials)
+	}
+}
+
+func TestNewTemplatePreview_StripsSecrets(t *testing.T) {
+	template, credentialID := newPreviewTemplate(t)
+
+	preview, err := NewTemplatePreview(template)
+	if err != nil {
+		t.Fatalf("NewTemplatePreview() error = %v", err)
+	}
+	data, err := json.Marshal(preview)
+	if err != nil {
+		t.Fatalf("marshal preview: %v", err)
+	}
+
+	for _, secret := range []string{
+		"whsec_super_secret",      // Trigger config
+		"sk-live-PRIVATE",         // Step config
+		"hooks.slack.com",         // Step config
+		"grp-secret",              // Block group config
+		"hunter2",                 // Variable value
+		credentialID.String(),     // Credential binding value
+		"$.score",                 // Edge condition
+		"Summarize {{input.email", // Prompt
+	} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("preview leaks %q: %s", secret, data)
+		}
+	}
+}
+
+func TestNewTemplatePreview_InvalidDefinition(t *testing.T) {
+	template := NewProjectTemplate(nil, "Broken", "", json.RawMessage(`{"steps":"nope"}`))
+	if _, err := NewTemplatePreview(template); err == nil {
+		t.Error("NewTemplatePreview() should fail for an invalid definition")
+	}
+}
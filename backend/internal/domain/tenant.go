@@ -3,6 +3,9 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -111,6 +114,18 @@ type TenantLimits struct {
 	MaxCredentials int `json:"max_credentials"`
 	MaxStorageMB   int `json:"max_storage_mb"`
 	RetentionDays  int `json:"retention_days"`
+	MaxInputDepth  int `json:"max_input_depth,omitempty"` // Overrides the default run input depth limit when positive
+	MaxInputBytes  int `json:"max_input_bytes,omitempty"` // Overrides the default run input size limit when positive
+
+	MaxRunDurationSeconds int `json:"max_run_duration_seconds,omitempty"` // Overrides the default wall-clock limit of a run when positive
+
+	// OutputRetentionDays clears step run inputs and outputs older than this many days when
+	// positive. It is separate from RetentionDays, which covers the run records themselves.
+	OutputRetentionDays int `json:"output_retention_days,omitempty"`
+
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"` // Ceiling of max_tokens for LLM steps when positive
+
+	MaxQueuedRuns int `json:"max_queued_runs,omitempty"` // Overrides the default limit of the tenant's runs waiting in the queue when positive
 }
 
 // DefaultLimits returns default limits for a plan
@@ -155,6 +170,65 @@ func DefaultLimits(plan TenantPlan) TenantLimits {
 	}
 }
 
+// TenantSettingEgress is the tenant settings key holding the TenantEgressPolicy
+const TenantSettingEgress = "egress"
+
+// TenantEgressPolicy restricts the external domains a tenant's workflows may call over HTTP.
+// A domain matches itself and its subdomains; denied domains take precedence over allowed ones.
+type TenantEgressPolicy struct {
+	AllowedDomains []string `json:"allowed_domains,omitempty"` // Empty allows every domain
+	DeniedDomains  []string `json:"denied_domains,omitempty"`
+}
+
+// TenantSettingEndpoints is the tenant settings key holding the tenant's EndpointOverride per
+// provider
+const TenantSettingEndpoints = "endpoints"
+
+// EndpointOverride routes a provider's requests to a regional or alternative endpoint, for
+// tenants with data residency requirements. At most one of the fields may be set; adapters only
+// accept endpoints on their allowlist.
+type EndpointOverride struct {
+	BaseURL string `json:"base_url,omitempty"` // e.g. https://eu.api.openai.com/v1
+	Region  string `json:"region,omitempty"`   // e.g. eu
+}
+
+// IsZero reports whether the override selects no endpoint
+func (o EndpointOverride) IsZero() bool {
+	return o.BaseURL == "" && o.Region == ""
+}
+
+// Validate checks that the override sets at most one of base_url and region, and that base_url
+// is an absolute http(s) URL. Whether the endpoint is allowed is checked by the adapter.
+func (o EndpointOverride) Validate() error {
+	if o.BaseURL != "" && o.Region != "" {
+		return NewValidationError("base_url", "set either base_url or region, not both")
+	}
+	if o.BaseURL != "" {
+		u, err := url.Parse(o.BaseURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return NewValidationError("base_url", "base_url must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
+
+// EndpointProviders lists the provider IDs whose endpoints tenants can override
+var EndpointProviders = []string{"openai", "anthropic"}
+
+// ValidateEndpoints checks tenant endpoint overrides: every key must be one of EndpointProviders,
+// so a misspelled provider cannot silently leave requests on the default endpoint
+func ValidateEndpoints(endpoints map[string]EndpointOverride) error {
+	for provider, override := range endpoints {
+		if !slices.Contains(EndpointProviders, provider) {
+			return NewValidationError("endpoints", fmt.Sprintf("unknown provider %q (expected one of %s)", provider, strings.Join(EndpointProviders, ", ")))
+		}
+		if err := override.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // TenantMetadata contains additional tenant information
 type TenantMetadata struct {
 	Industry    string `json:"industry,omitempty"`
@@ -251,6 +325,64 @@ func (t *Tenant) GetLimits() (*TenantLimits, error) {
 	return &limits, nil
 }
 
+// GetEgressPolicy parses and returns the egress policy from settings. A tenant without one gets
+// an empty policy, which allows every domain.
+func (t *Tenant) GetEgressPolicy() (*TenantEgressPolicy, error) {
+	var settings struct {
+		Egress TenantEgressPolicy `json:"egress"`
+	}
+	if len(t.Settings) > 0 {
+		if err := json.Unmarshal(t.Settings, &settings); err != nil {
+			return nil, err
+		}
+	}
+	return &settings.Egress, nil
+}
+
+// SetEgressPolicy stores the egress policy in settings, keeping the other settings
+func (t *Tenant) SetEgressPolicy(policy TenantEgressPolicy) error {
+	return t.setSetting(TenantSettingEgress, policy)
+}
+
+// GetEndpoints parses and returns the endpoint overrides by provider from settings
+func (t *Tenant) GetEndpoints() (map[string]EndpointOverride, error) {
+	var settings struct {
+		Endpoints map[string]EndpointOverride `json:"endpoints"`
+	}
+	if len(t.Settings) > 0 {
+		if err := json.Unmarshal(t.Settings, &settings); err != nil {
+			return nil, err
+		}
+	}
+	return settings.Endpoints, nil
+}
+
+// SetEndpoints stores the endpoint overrides by provider in settings, keeping the other settings
+func (t *Tenant) SetEndpoints(endpoints map[string]EndpointOverride) error {
+	return t.setSetting(TenantSettingEndpoints, endpoints)
+}
+
+// setSetting stores a value under a settings key, keeping the other settings
+func (t *Tenant) setSetting(key string, value interface{}) error {
+	settings := make(map[string]json.RawMessage)
+	if len(t.Settings) > 0 {
+		if err := json.Unmarshal(t.Settings, &settings); err != nil {
+			return fmt.Errorf("failed to parse settings: %w", err)
+		}
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s setting: %w", key, err)
+	}
+	settings[key] = valueJSON
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	t.Settings = settingsJSON
+	return nil
+}
+
 // GetMetadata parses and returns metadata
 func (t *Tenant) GetMetadata() (*TenantMetadata, error) {
 	var metadata TenantMetadata
@@ -218,6 +218,79 @@ func TestTenant_GetVariables(t *testing.T) {
 	}
 }
 
+func TestTenant_EgressPolicy(t *testing.T) {
+	tenant, _ := NewTenant("Test", "test", TenantPlanFree)
+	tenant.Settings = json.RawMessage(`{"ssrf_allowlist": ["10.1.0.0/16"]}`)
+
+	// Test with no policy
+	policy, err := tenant.GetEgressPolicy()
+	if err != nil {
+		t.Fatalf("GetEgressPolicy() error = %v", err)
+	}
+	if len(policy.AllowedDomains) != 0 || len(policy.DeniedDomains) != 0 {
+		t.Errorf("GetEgressPolicy() = %+v, want empty", policy)
+	}
+
+	// Test setting a policy keeps other settings
+	if err := tenant.SetEgressPolicy(TenantEgressPolicy{AllowedDomains: []string{"example.com"}}); err != nil {
+		t.Fatalf("SetEgressPolicy() error = %v", err)
+	}
+	policy, err = tenant.GetEgressPolicy()
+	if err != nil {
+		t.Fatalf("GetEgressPolicy() error = %v", err)
+	}
+	if len(policy.AllowedDomains) != 1 || policy.AllowedDomains[0] != "example.com" {
+		t.Errorf("GetEgressPolicy() AllowedDomains = %v, want [example.com]", policy.AllowedDomains)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(tenant.Settings, &settings); err != nil {
+		t.Fatalf("invalid settings: %v", err)
+	}
+	if _, ok := settings["ssrf_allowlist"]; !ok {
+		t.Errorf("SetEgressPolicy() dropped existing settings: %s", tenant.Settings)
+	}
+}
+
+func TestTenant_Endpoints(t *testing.T) {
+	tenant, _ := NewTenant("Test", "test", TenantPlanFree)
+	tenant.Settings = json.RawMessage(`{"egress": {"allowed_domains": ["example.com"]}}`)
+
+	if err := tenant.SetEndpoints(map[string]EndpointOverride{"openai": {Region: "eu"}}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	endpoints, err := tenant.GetEndpoints()
+	if err != nil {
+		t.Fatalf("GetEndpoints() error = %v", err)
+	}
+	if endpoints["openai"].Region != "eu" {
+		t.Errorf("GetEndpoints() = %+v, want openai in eu", endpoints)
+	}
+	if policy, _ := tenant.GetEgressPolicy(); len(policy.AllowedDomains) != 1 {
+		t.Errorf("SetEndpoints() dropped existing settings: %s", tenant.Settings)
+	}
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints map[string]EndpointOverride
+		wantErr   bool
+	}{
+		{"region", map[string]EndpointOverride{"openai": {Region: "eu"}}, false},
+		{"base_url", map[string]EndpointOverride{"anthropic": {BaseURL: "https://anthropic.eu.example.com"}}, false},
+		{"unknown provider", map[string]EndpointOverride{"open-ai": {Region: "eu"}}, true},
+		{"base_url and region", map[string]EndpointOverride{"openai": {BaseURL: "https://eu.api.openai.com/v1", Region: "eu"}}, true},
+		{"relative base_url", map[string]EndpointOverride{"openai": {BaseURL: "eu.api.openai.com/v1"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateEndpoints(tt.endpoints); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestTenant_Suspend(t *testing.T) {
 	tenant, _ := NewTenant("Test", "test", TenantPlanFree)
 	reason := "Payment overdue"
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FallbackTimezone is used when the deployment does not configure DEFAULT_TIMEZONE
+const FallbackTimezone = "UTC"
+
+var (
+	defaultTimezoneMu   sync.RWMutex
+	defaultTimezoneName = FallbackTimezone
+	defaultLocation     = time.UTC
+)
+
+// SetDefaultTimezone sets the deployment-wide default timezone (DEFAULT_TIMEZONE), used when a
+// schedule, a template date or the copilot trigger setup does not specify one. The name must be
+// an IANA timezone such as "Asia/Tokyo"; an empty name resets it to FallbackTimezone.
+func SetDefaultTimezone(name string) error {
+	if name == "" {
+		name = FallbackTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+
+	defaultTimezoneMu.Lock()
+	defer defaultTimezoneMu.Unlock()
+	defaultTimezoneName = name
+	defaultLocation = loc
+	return nil
+}
+
+// DefaultTimezone returns the name of the deployment default timezone
+func DefaultTimezone() string {
+	defaultTimezoneMu.RLock()
+	defer defaultTimezoneMu.RUnlock()
+	return defaultTimezoneName
+}
+
+// DefaultLocation returns the deployment default timezone as a location
+func DefaultLocation() *time.Location {
+	defaultTimezoneMu.RLock()
+	defer defaultTimezoneMu.RUnlock()
+	return defaultLocation
+}
+
+// ValidateTimezone checks that an IANA timezone name can be loaded
+func ValidateTimezone(name string) error {
+	if _, err := time.LoadLocation(name); err != nil {
+		return NewValidationError("timezone", fmt.Sprintf("unknown timezone %q", name))
+	}
+	return nil
+}
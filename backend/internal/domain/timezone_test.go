@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestSetDefaultTimezone(t *testing.T) {
+	t.Cleanup(func() { _ = SetDefaultTimezone("") })
+
+	if got := DefaultTimezone(); got != FallbackTimezone {
+		t.Errorf("DefaultTimezone() = %q, want %q before configuration", got, FallbackTimezone)
+	}
+
+	if err := SetDefaultTimezone("Asia/Tokyo"); err != nil {
+		t.Fatalf("SetDefaultTimezone() error = %v", err)
+	}
+	if DefaultTimezone() != "Asia/Tokyo" || DefaultLocation().String() != "Asia/Tokyo" {
+		t.Errorf("default = %q (%s), want Asia/Tokyo", DefaultTimezone(), DefaultLocation())
+	}
+
+	// An invalid name is rejected and keeps the previous default
+	if err := SetDefaultTimezone("Tokyo"); err == nil {
+		t.Error("SetDefaultTimezone(\"Tokyo\") error = nil, want error")
+	}
+	if DefaultTimezone() != "Asia/Tokyo" {
+		t.Errorf("DefaultTimezone() = %q after a rejected value, want Asia/Tokyo", DefaultTimezone())
+	}
+
+	if err := SetDefaultTimezone(""); err != nil || DefaultTimezone() != FallbackTimezone {
+		t.Errorf("SetDefaultTimezone(\"\") = %v, default %q; want reset to %q", err, DefaultTimezone(), FallbackTimezone)
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// executeGroupWithRetry runs a block group under its error_handling config (the same shape as a
+// step's: enabled, retry.max_retries, retry.interval_seconds, retry.backoff_strategy and
+// timeout_seconds). Every attempt re-runs the group's body from its original input; newContext
+// builds a fresh BlockGroupContext for each attempt because ExecuteGroup replaces the context's
+// input with the pre_process result. It returns the output or last error and the attempts made.
+func (e *Executor) executeGroupWithRetry(ctx context.Context, groupExecutor *BlockGroupExecutor, group *domain.BlockGroup, newContext func() *BlockGroupContext) (json.RawMessage, int, error) {
+	ehConfig := getErrorHandlingConfig(group.Config)
+	maxAttempts := 1
+	if ehConfig != nil && ehConfig.Enabled && ehConfig.Retry != nil {
+		maxAttempts = ehConfig.Retry.MaxRetries + 1
+	}
+
+	var (
+		output  json.RawMessage
+		err     error
+		attempt int
+	)
+	for attempt = 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			// Wait before retry
+			interval := ehConfig.Retry.IntervalSeconds
+			if ehConfig.Retry.BackoffStrategy == "exponential" {
+				interval = interval * (1 << (attempt - 2)) // Exponential backoff
+			}
+			e.logger.Info("Retrying block group execution",
+				"group_id", group.ID,
+				"attempt", attempt,
+				"wait_seconds", interval,
+			)
+			select {
+			case <-ctx.Done():
+				return nil, attempt - 1, ctx.Err()
+			case <-time.After(time.Duration(interval) * time.Second):
+			}
+		}
+
+		output, err = e.executeGroupAttempt(ctx, groupExecutor, ehConfig, newContext())
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		e.logger.Warn("Block group execution failed",
+			"group_id", group.ID,
+			"attempt", attempt,
+			"error", err,
+		)
+	}
+	if attempt > maxAttempts {
+		attempt = maxAttempts
+	}
+	return output, attempt, err
+}
+
+// executeGroupAttempt runs one attempt of a block group, applying timeout_seconds when set
+func (e *Executor) executeGroupAttempt(ctx context.Context, groupExecutor *BlockGroupExecutor, ehConfig *ErrorHandlingConfig, bgCtx *BlockGroupContext) (json.RawMessage, error) {
+	if ehConfig != nil && ehConfig.Enabled && ehConfig.TimeoutSeconds != nil && *ehConfig.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*ehConfig.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	return groupExecutor.ExecuteGroup(ctx, bgCtx)
+}
+
+// groupErrorOutput builds the output routed to a block group's "error" port, mirroring a step's
+// error port. It reports false when nothing is connected to the port, in which case the error
+// fails the run as before.
+func (e *Executor) groupErrorOutput(graph *Graph, group *domain.BlockGroup, input json.RawMessage, attempts int, err error) (json.RawMessage, bool) {
+	connected := false
+	for _, edge := range graph.GroupOutEdges[group.ID] {
+		if edge.SourcePort == "error" {
+			connected = true
+			break
+		}
+	}
+	if !connected {
+		return nil, false
+	}
+
+	// An input that is not valid JSON is passed on as a string rather than dropped
+	errorOutput, marshalErr := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": err.Error(),
+			"type":    "execution_error",
+		},
+		"input":    runOutputValue(input),
+		"attempts": attempts,
+	})
+	if marshalErr != nil {
+		e.logger.Error("Failed to marshal block group error output",
+			"group_id", group.ID,
+			"error", marshalErr,
+			"group_error", err,
+		)
+		return nil, false
+	}
+	return errorOutput, true
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// boundaryPipeline builds start -> group(body) -> after, plus group -error-> handler when
+// withErrorPort is set. The group is a fail-fast parallel group so a body failure fails the group.
+func boundaryPipeline(groupConfig string, withErrorPort bool) *domain.ProjectDefinition {
+	def := &domain.ProjectDefinition{Name: "boundary"}
+	addStep := func(label string, groupID *uuid.UUID) uuid.UUID {
+		id := uuid.New()
+		config, _ := json.Marshal(map[string]string{"adapter_id": "recorder", "label": label})
+		def.Steps = append(def.Steps, domain.Step{ID: id, Name: label, Type: domain.StepTypeTool, Config: config, BlockGroupID: groupID})
+		return id
+	}
+
+	startID := uuid.New()
+	def.Steps = append(def.Steps, domain.Step{ID: startID, Name: "start", Type: domain.StepTypeStart})
+	group := domain.BlockGroup{ID: uuid.New(), Name: "group", Type: domain.BlockGroupTypeParallel, Config: json.RawMessage(groupConfig)}
+	def.BlockGroups = append(def.BlockGroups, group)
+	addStep("body", &group.ID)
+	after := addStep("after", nil)
+
+	def.Edges = append(def.Edges,
+		domain.Edge{ID: uuid.New(), SourceStepID: &startID, TargetBlockGroupID: &group.ID},
+		domain.Edge{ID: uuid.New(), SourceBlockGroupID: &group.ID, TargetStepID: &after, SourcePort: "out"},
+	)
+	if withErrorPort {
+		handler := addStep("handler", nil)
+		def.Edges = append(def.Edges, domain.Edge{ID: uuid.New(), SourceBlockGroupID: &group.ID, TargetStepID: &handler, SourcePort: "error"})
+	}
+	return def
+}
+
+const retryingGroupConfig = `{
+	"fail_fast": true,
+	"error_handling": {"enabled": true, "retry": {"max_retries": 2, "interval_seconds": 0}}
+}`
+
+func TestExecute_BlockGroupRetriesThenSucceeds(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["body"] = 1
+	executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"query":"x"}`), domain.TriggerTypeManual)
+
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, boundaryPipeline(retryingGroupConfig, true))))
+
+	assert.Equal(t, []string{"body", "body", "after"}, recorder.executed, "the group is re-run after the transient failure")
+}
+
+func TestExecute_BlockGroupRoutesToErrorPort(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["body"] = 10
+	executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"query":"x"}`), domain.TriggerTypeManual)
+
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, boundaryPipeline(retryingGroupConfig, true))))
+
+	assert.Equal(t, []string{"body", "body", "body", "handler"}, recorder.executed, "retries are exhausted before routing to the error port")
+
+	var handlerInput struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error"`
+		Input    map[string]interface{} `json:"input"`
+		Attempts int                    `json:"attempts"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.inputs["handler"], &handlerInput))
+	assert.Contains(t, handlerInput.Error.Message, "transient failure in body")
+	assert.Equal(t, "execution_error", handlerInput.Error.Type)
+	assert.Equal(t, map[string]interface{}{"query": "x"}, handlerInput.Input)
+	assert.Equal(t, 3, handlerInput.Attempts)
+}
+
+func TestExecute_BlockGroupFailureWithoutErrorPortFailsRun(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["body"] = 10
+	executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	err := executor.Execute(context.Background(), NewExecutionContext(run, boundaryPipeline(`{"fail_fast": true}`, false)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transient failure in body")
+	assert.Equal(t, []string{"body"}, recorder.executed, "groups are not retried without error_handling")
+}
+
+func TestExecute_BlockGroupErrorDoesNotFollowPortlessEdges(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["body"] = 10
+	executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	// The success edge has no port; the group fails through its connected error port
+	def := boundaryPipeline(`{"fail_fast": true}`, true)
+	for i := range def.Edges {
+		if def.Edges[i].SourcePort == "out" {
+			def.Edges[i].SourcePort = ""
+		}
+	}
+
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, def)))
+	assert.Equal(t, []string{"body", "handler"}, recorder.executed, "a port-less edge is only followed on the default port")
+
+	// On success the same port-less edge is followed
+	recorder.reset()
+	recorder.failures["body"] = 0
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, def)))
+	assert.Equal(t, []string{"body", "after"}, recorder.executed)
+}
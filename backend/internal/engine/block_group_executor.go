@@ -5,11 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/adapter"
 	"github.com/souta/ai-orchestration/internal/block/sandbox"
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -48,6 +49,8 @@ type BlockGroupContext struct {
 	Input    json.RawMessage
 	ExecCtx  *ExecutionContext
 	Graph    *Graph
+
+	stepsExecuted atomic.Int64 // Child step executions, reported when the group completes
 }
 
 // BlockGroupResult represents the result of a block group execution
@@ -68,12 +71,18 @@ func (e *BlockGroupExecutor) ExecuteGroup(ctx context.Context, bgCtx *BlockGroup
 	)
 	defer span.End()
 
-	e.logger.Info("Executing block group",
+	startTime := time.Now()
+	span.SetAttributes(
+		attribute.Int("step_count", len(bgCtx.Steps)),
+		attribute.Int("input_bytes", len(bgCtx.Input)),
+	)
+	e.logger.Info("Block group started",
+		"run_id", bgCtx.ExecCtx.Run.ID,
 		"group_id", bgCtx.Group.ID,
 		"group_name", bgCtx.Group.Name,
 		"group_type", bgCtx.Group.Type,
 		"step_count", len(bgCtx.Steps),
-		"input_raw", string(bgCtx.Input),
+		"input_bytes", len(bgCtx.Input),
 	)
 
 	// 1. Run pre_process to transform external input to internal input
@@ -81,6 +90,7 @@ func (e *BlockGroupExecutor) ExecuteGroup(ctx context.Context, bgCtx *BlockGroup
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "pre_process failed")
+		e.logGroupFailed(bgCtx, startTime, err)
 		return nil, fmt.Errorf("pre_process failed: %w", err)
 	}
 
@@ -107,6 +117,7 @@ func (e *BlockGroupExecutor) ExecuteGroup(ctx context.Context, bgCtx *BlockGroup
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
+		e.logGroupFailed(bgCtx, startTime, err)
 		return nil, err
 	}
 
@@ -115,13 +126,62 @@ func (e *BlockGroupExecutor) ExecuteGroup(ctx context.Context, bgCtx *BlockGroup
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "post_process failed")
+		e.logGroupFailed(bgCtx, startTime, err)
 		return nil, fmt.Errorf("post_process failed: %w", err)
 	}
 
+	outputPort := groupOutputPort(externalOutput)
+	stepsExecuted := bgCtx.stepsExecuted.Load()
+	span.SetAttributes(
+		attribute.String("output_port", outputPort),
+		attribute.Int("output_bytes", len(externalOutput)),
+		attribute.Int64("steps_executed", stepsExecuted),
+	)
+	e.logger.Info("Block group completed",
+		"run_id", bgCtx.ExecCtx.Run.ID,
+		"group_id", bgCtx.Group.ID,
+		"group_name", bgCtx.Group.Name,
+		"group_type", bgCtx.Group.Type,
+		"output_port", outputPort,
+		"output_bytes", len(externalOutput),
+		"steps_executed", stepsExecuted,
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+
 	span.SetStatus(codes.Ok, "block group completed")
 	return externalOutput, nil
 }
 
+// logGroupFailed logs the end of a block group execution that returned an error
+func (e *BlockGroupExecutor) logGroupFailed(bgCtx *BlockGroupContext, startTime time.Time, err error) {
+	e.logger.Info("Block group failed",
+		"run_id", bgCtx.ExecCtx.Run.ID,
+		"group_id", bgCtx.Group.ID,
+		"group_name", bgCtx.Group.Name,
+		"group_type", bgCtx.Group.Type,
+		"steps_executed", bgCtx.stepsExecuted.Load(),
+		"duration_ms", time.Since(startTime).Milliseconds(),
+		"error", err,
+	)
+}
+
+// groupOutputPort determines the port a block group's output leaves through: "out" (the default
+// output port in block definitions) unless the output names one in __port, or "error" when it
+// sets __error
+func groupOutputPort(output json.RawMessage) string {
+	outputPort := "out"
+	var outputMap map[string]interface{}
+	if err := json.Unmarshal(output, &outputMap); err == nil {
+		if port, ok := outputMap["__port"].(string); ok {
+			outputPort = port
+		}
+		if isError, ok := outputMap["__error"].(bool); ok && isError {
+			outputPort = "error"
+		}
+	}
+	return outputPort
+}
+
 // runPreProcess executes the pre_process JavaScript to transform input
 func (e *BlockGroupExecutor) runPreProcess(ctx context.Context, group *domain.BlockGroup, input json.RawMessage) (json.RawMessage, error) {
 	if group.PreProcess == nil || *group.PreProcess == "" {
@@ -140,7 +200,7 @@ func (e *BlockGroupExecutor) runPreProcess(ctx context.Context, group *domain.Bl
 
 	// Create sandbox context with HTTP client
 	sandboxCtx := &sandbox.ExecutionContext{
-		HTTP: sandbox.NewHTTPClient(30 * time.Second),
+		HTTP: newSandboxHTTPClient(ctx),
 		Logger: func(args ...interface{}) {
 			e.logger.Info("pre_process log", "group_id", group.ID, "message", fmt.Sprint(args...))
 		},
@@ -175,7 +235,7 @@ func (e *BlockGroupExecutor) runPostProcess(ctx context.Context, group *domain.B
 
 	// Create sandbox context with HTTP client
 	sandboxCtx := &sandbox.ExecutionContext{
-		HTTP: sandbox.NewHTTPClient(30 * time.Second),
+		HTTP: newSandboxHTTPClient(ctx),
 		Logger: func(args ...interface{}) {
 			e.logger.Info("post_process log", "group_id", group.ID, "message", fmt.Sprint(args...))
 		},
@@ -204,7 +264,43 @@ func wrapTransformCode(code string) string {
 `, code)
 }
 
-// executeParallel executes all steps in parallel and waits for all to complete
+// parallelBranch is one entry point of a parallel group together with the chain of steps it runs
+type parallelBranch struct {
+	Name  string
+	Chain []*domain.Step
+}
+
+// buildParallelBranches splits a parallel group's body into branches, one per entry point.
+// Branches are ordered by name (then step ID) and duplicate names get a numeric suffix,
+// so the merged output never depends on map iteration or branch completion order.
+func buildParallelBranches(group *domain.BlockGroup, bodySteps []*domain.Step, edges []*domain.Edge) []parallelBranch {
+	chainBuilder := NewChainBuilder(bodySteps, edges)
+	entryPoints := chainBuilder.FindEntryPoints(group.ID)
+	sort.Slice(entryPoints, func(i, j int) bool {
+		if entryPoints[i].Name != entryPoints[j].Name {
+			return entryPoints[i].Name < entryPoints[j].Name
+		}
+		return entryPoints[i].ID.String() < entryPoints[j].ID.String()
+	})
+
+	branches := make([]parallelBranch, 0, len(entryPoints))
+	seen := make(map[string]int)
+	for _, entry := range entryPoints {
+		name := entry.Name
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, seen[name])
+		}
+		branches = append(branches, parallelBranch{Name: name, Chain: chainBuilder.BuildChain(entry)})
+	}
+	return branches
+}
+
+// executeParallel runs each branch of the group concurrently and joins them.
+// Every branch has finished (or been cancelled by fail_fast) before the group returns, and the
+// output maps branch names to the output of each branch's last step:
+//
+//	{"results": {"<branch>": <output>}, "errors": {"<branch>": "<message>"}, "branches": [...], "completed": bool, "count": n}
 func (e *BlockGroupExecutor) executeParallel(ctx context.Context, bgCtx *BlockGroupContext) (json.RawMessage, error) {
 	ctx, span := tracer.Start(ctx, "block_group.parallel",
 		trace.WithAttributes(
@@ -234,73 +330,70 @@ func (e *BlockGroupExecutor) executeParallel(ctx context.Context, bgCtx *BlockGr
 		return json.RawMessage("{}"), nil
 	}
 
-	span.SetAttributes(attribute.Int("body_step_count", len(bodySteps)))
+	branches := buildParallelBranches(bgCtx.Group, bodySteps, bgCtx.Edges)
+	span.SetAttributes(
+		attribute.Int("body_step_count", len(bodySteps)),
+		attribute.Int("branch_count", len(branches)),
+	)
 
 	// Determine concurrency limit
 	maxConcurrent := config.MaxConcurrent
 	if maxConcurrent <= 0 {
-		maxConcurrent = len(bodySteps) // Unlimited = all at once
+		maxConcurrent = len(branches) // Unlimited = all at once
 	}
 
-	// Results collection
-	results := make(map[string]interface{})
-	var resultsMu sync.Mutex
+	// fail_fast cancels the branches still running once one fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Each branch writes only its own slot, so results are merged in branch order
+	outputs := make([]json.RawMessage, len(branches))
+	errs := make([]error, len(branches))
 	var firstError error
 	var errorMu sync.Mutex
 
-	// Semaphore for concurrency control
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
-	for _, step := range bodySteps {
-		// Check if we should fail fast
-		if config.FailFast {
-			errorMu.Lock()
-			if firstError != nil {
-				errorMu.Unlock()
-				break
-			}
-			errorMu.Unlock()
-		}
-
+	for i := range branches {
 		wg.Add(1)
-		go func(s *domain.Step) {
+		go func(i int, branch parallelBranch) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			// Execute step
-			output, err := e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, s, bgCtx.Input)
-
-			if err != nil {
-				e.logger.Error("Parallel step failed",
-					"step_id", s.ID,
-					"step_name", s.Name,
-					"error", err,
-				)
-				if config.FailFast {
-					errorMu.Lock()
-					if firstError == nil {
-						firstError = err
-					}
-					errorMu.Unlock()
-				}
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
 				return
 			}
 
-			// Store result
-			resultsMu.Lock()
-			var outputData interface{}
-			if err := json.Unmarshal(output, &outputData); err == nil {
-				results[s.Name] = outputData
-			} else {
-				results[s.Name] = string(output)
+			// The entry step receives the group input; later steps resolve theirs from the chain's edges
+			input := bgCtx.Input
+			for _, step := range branch.Chain {
+				output, err := e.executeStep(ctx, bgCtx, step, input)
+				if err != nil {
+					e.logger.Error("Parallel branch failed",
+						"branch", branch.Name,
+						"step_id", step.ID,
+						"step_name", step.Name,
+						"error", err,
+					)
+					errs[i] = err
+					if config.FailFast {
+						errorMu.Lock()
+						if firstError == nil {
+							firstError = err
+							cancel()
+						}
+						errorMu.Unlock()
+					}
+					return
+				}
+				outputs[i] = output
+				input = nil
 			}
-			resultsMu.Unlock()
-
-		}(step)
+		}(i, branches[i])
 	}
 
 	wg.Wait()
@@ -310,11 +403,32 @@ func (e *BlockGroupExecutor) executeParallel(ctx context.Context, bgCtx *BlockGr
 		return nil, firstError
 	}
 
+	results := make(map[string]interface{}, len(branches))
+	branchErrors := make(map[string]string)
+	names := make([]string, len(branches))
+	for i, branch := range branches {
+		names[i] = branch.Name
+		if errs[i] != nil {
+			branchErrors[branch.Name] = errs[i].Error()
+			continue
+		}
+		var outputData interface{}
+		if err := json.Unmarshal(outputs[i], &outputData); err == nil {
+			results[branch.Name] = outputData
+		} else {
+			results[branch.Name] = string(outputs[i])
+		}
+	}
+
 	// Build output
 	output := map[string]interface{}{
 		"results":   results,
-		"completed": true,
-		"count":     len(bodySteps),
+		"branches":  names,
+		"completed": len(branchErrors) == 0,
+		"count":     len(branches),
+	}
+	if len(branchErrors) > 0 {
+		output["errors"] = branchErrors
 	}
 
 	return json.Marshal(output)
@@ -323,45 +437,51 @@ func (e *BlockGroupExecutor) executeParallel(ctx context.Context, bgCtx *BlockGr
 // executeStep executes a single step within a block group context
 // The input parameter is used for tool chain execution where the step input
 // comes from LLM tool arguments rather than DAG edges
-func (e *BlockGroupExecutor) executeStep(ctx context.Context, execCtx *ExecutionContext, graph *Graph, step *domain.Step, input json.RawMessage) (json.RawMessage, error) {
+func (e *BlockGroupExecutor) executeStep(ctx context.Context, bgCtx *BlockGroupContext, step *domain.Step, input json.RawMessage) (json.RawMessage, error) {
 	ctx, span := tracer.Start(ctx, "block_group.step",
 		trace.WithAttributes(
+			attribute.String("group_id", bgCtx.Group.ID.String()),
 			attribute.String("step_id", step.ID.String()),
 			attribute.String("step_name", step.Name),
 			attribute.String("step_type", string(step.Type)),
+			attribute.Int("input_bytes", len(input)),
 		),
 	)
 	defer span.End()
 
-	// Set tool input override so prepareStepInput uses the provided input
-	// instead of resolving input from DAG edges (which don't exist for tool steps)
-	if len(input) > 0 {
-		execCtx.mu.Lock()
-		if execCtx.ToolInputOverride == nil {
-			execCtx.ToolInputOverride = make(map[uuid.UUID]json.RawMessage)
-		}
-		execCtx.ToolInputOverride[step.ID] = input
-		execCtx.mu.Unlock()
-
-		// Clean up the override after execution
-		defer func() {
-			execCtx.mu.Lock()
-			delete(execCtx.ToolInputOverride, step.ID)
-			execCtx.mu.Unlock()
-		}()
-	}
+	bgCtx.stepsExecuted.Add(1)
+	startTime := time.Now()
+	e.logger.Debug("Executing block group step",
+		"group_id", bgCtx.Group.ID,
+		"step_id", step.ID,
+		"step_name", step.Name,
+		"step_type", step.Type,
+		"input_bytes", len(input),
+	)
 
 	// Use the main executor's step execution logic
-	err := e.executor.executeNode(ctx, execCtx, graph, step.ID)
+	output, err := e.executor.executeStepWithInput(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, input)
 	if err != nil {
-		return nil, err
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		e.logger.Info("Block group step failed",
+			"group_id", bgCtx.Group.ID,
+			"step_id", step.ID,
+			"step_name", step.Name,
+			"duration_ms", time.Since(startTime).Milliseconds(),
+			"error", err,
+		)
+		return output, err
 	}
 
-	// Get output from execution context
-	execCtx.mu.RLock()
-	output := execCtx.StepData[step.ID]
-	execCtx.mu.RUnlock()
-
+	span.SetAttributes(attribute.Int("output_bytes", len(output)))
+	e.logger.Info("Block group step completed",
+		"group_id", bgCtx.Group.ID,
+		"step_id", step.ID,
+		"step_name", step.Name,
+		"output_bytes", len(output),
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
 	return output, nil
 }
 
@@ -413,7 +533,7 @@ func (e *BlockGroupExecutor) executeTryCatch(ctx context.Context, bgCtx *BlockGr
 		lastError = nil
 		for _, step := range bodySteps {
 			var err error
-			output, err = e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, bgCtx.Input)
+			output, err = e.executeStep(ctx, bgCtx, step, bgCtx.Input)
 			if err != nil {
 				lastError = err
 				break
@@ -534,7 +654,7 @@ func (e *BlockGroupExecutor) executeForeach(ctx context.Context, bgCtx *BlockGro
 
 				var lastOutput interface{}
 				for _, step := range bodySteps {
-					output, err := e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, iterInputJSON)
+					output, err := e.executeStep(ctx, bgCtx, step, iterInputJSON)
 					if err != nil {
 						e.logger.Error("Foreach step failed", "index", idx, "step_id", step.ID, "error", err)
 						continue
@@ -581,7 +701,7 @@ func (e *BlockGroupExecutor) executeForeach(ctx context.Context, bgCtx *BlockGro
 
 			var lastOutput interface{}
 			for _, step := range bodySteps {
-				output, err := e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, iterInputJSON)
+				output, err := e.executeStep(ctx, bgCtx, step, iterInputJSON)
 				if err != nil {
 					e.logger.Error("Foreach step failed", "index", i, "step_id", step.ID, "error", err)
 					continue
@@ -641,7 +761,7 @@ func (e *BlockGroupExecutor) executeWhile(ctx context.Context, bgCtx *BlockGroup
 			// Execute body
 			var lastOutput interface{}
 			for _, step := range bodySteps {
-				output, err := e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, currentInput)
+				output, err := e.executeStep(ctx, bgCtx, step, currentInput)
 				if err != nil {
 					return nil, err
 				}
@@ -666,7 +786,7 @@ func (e *BlockGroupExecutor) executeWhile(ctx context.Context, bgCtx *BlockGroup
 		if !config.DoWhile || iterations > 0 {
 			var lastOutput interface{}
 			for _, step := range bodySteps {
-				output, err := e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, currentInput)
+				output, err := e.executeStep(ctx, bgCtx, step, currentInput)
 				if err != nil {
 					return nil, err
 				}
@@ -782,7 +902,7 @@ func (e *BlockGroupExecutor) executeAgent(ctx context.Context, bgCtx *BlockGroup
 
 	// Add conversation history if memory is enabled
 	if config.EnableMemory {
-		messages = e.appendHistoryMessages(messages, inputData, config.MemoryWindow)
+		messages = e.appendHistoryMessages(messages, inputData, agentMemoryWindow(config.MemoryWindow, inputData))
 	}
 
 	// Add current user message
@@ -836,6 +956,14 @@ func (e *BlockGroupExecutor) executeAgent(ctx context.Context, bgCtx *BlockGroup
 		content, _ := response["content"].(string)
 		toolCalls, hasToolCalls := response["tool_calls"].([]map[string]interface{})
 
+		// Stream assistant text (reasoning between tool calls, or the final answer)
+		if content != "" {
+			e.emitAgentEvent(bgCtx, EventPartialText, PartialTextData{
+				Iteration: iteration,
+				Content:   content,
+			})
+		}
+
 		if !hasToolCalls || len(toolCalls) == 0 {
 			// No tool calls - final response
 			finalResponse = content
@@ -856,91 +984,11 @@ func (e *BlockGroupExecutor) executeAgent(ctx context.Context, bgCtx *BlockGroup
 
 		// Execute each tool call
 		for _, toolCall := range toolCalls {
-			toolCallID, _ := toolCall["id"].(string)
-			fn, _ := toolCall["function"].(map[string]interface{})
-			toolName, _ := fn["name"].(string)
-			argsStr, _ := fn["arguments"].(string)
-
-			e.logger.Info("Agent executing tool",
-				"group_id", bgCtx.Group.ID,
-				"tool_name", toolName,
-				"tool_call_id", toolCallID,
-			)
-
-			// Parse arguments
-			var toolArgs map[string]interface{}
-			if err := json.Unmarshal([]byte(argsStr), &toolArgs); err != nil {
-				toolArgs = map[string]interface{}{}
-			}
-
-			// Emit tool_call event
-			argsJSON, _ := json.Marshal(toolArgs)
-			e.emitAgentEvent(bgCtx, EventToolCall, ToolCallData{
-				ToolName:   toolName,
-				ToolCallID: toolCallID,
-				Arguments:  argsJSON,
-			})
-
-			// Find and execute the corresponding tool chain
-			var toolResult interface{}
-			var isError bool
-			if toolChain, ok := toolChainMap[toolName]; ok {
-				// Validate tool arguments against schema before execution
-				if validationErr := validateToolArgs(toolArgs, toolChain.InputSchema); validationErr != nil {
-					e.logger.Warn("Tool argument validation failed",
-						"tool_name", toolName,
-						"missing_fields", validationErr.MissingFields,
-					)
-					toolResult = map[string]interface{}{
-						"error":          validationErr.Message,
-						"missing_fields": validationErr.MissingFields,
-						"suggestion":     "Please provide all required parameters and retry the tool call.",
-					}
-					isError = true
-				} else {
-					toolInput, _ := json.Marshal(toolArgs)
-					output, err := e.executeToolChain(ctx, bgCtx, toolChain, toolInput)
-					if err != nil {
-						toolResult = map[string]interface{}{
-							"error": err.Error(),
-						}
-						isError = true
-					} else {
-						if err := json.Unmarshal(output, &toolResult); err != nil {
-							toolResult = string(output)
-						}
-						// Check if the tool output contains an error field
-						// This handles cases where the tool execution succeeded but returned an error message
-						// (e.g., validation errors from create_step like "project_id, name, and type are required")
-						if resultMap, ok := toolResult.(map[string]interface{}); ok {
-							if _, hasError := resultMap["error"]; hasError {
-								isError = true
-							}
-						}
-					}
-				}
-			} else {
-				toolResult = map[string]interface{}{
-					"error": fmt.Sprintf("Tool not found: %s", toolName),
-				}
-				isError = true
-			}
-
-			// Add tool result to messages
-			toolResultStr, _ := json.Marshal(toolResult)
-
-			// Emit tool_result event
-			e.emitAgentEvent(bgCtx, EventToolResult, ToolResultData{
-				ToolName:   toolName,
-				ToolCallID: toolCallID,
-				Result:     toolResultStr,
-				IsError:    isError,
-			})
-
+			toolCallID, toolResult := e.executeAgentToolCall(ctx, bgCtx, toolChainMap, toolCall, iteration)
 			messages = append(messages, map[string]interface{}{
 				"role":         "tool",
 				"tool_call_id": toolCallID,
-				"content":      string(toolResultStr),
+				"content":      string(toolResult),
 			})
 		}
 	}
@@ -976,6 +1024,105 @@ func (e *BlockGroupExecutor) executeAgent(ctx context.Context, bgCtx *BlockGroup
 	return json.Marshal(output)
 }
 
+// executeAgentToolCall runs a single tool call requested by the LLM and returns its ID and JSON result.
+// A tool:call event is emitted before execution and a tool:result event after it, so streaming
+// clients can show a live activity log. The event result is truncated; the LLM receives it in full.
+func (e *BlockGroupExecutor) executeAgentToolCall(
+	ctx context.Context,
+	bgCtx *BlockGroupContext,
+	toolChainMap map[string]*ToolChain,
+	toolCall map[string]interface{},
+	iteration int,
+) (string, json.RawMessage) {
+	toolCallID, _ := toolCall["id"].(string)
+	fn, _ := toolCall["function"].(map[string]interface{})
+	toolName, _ := fn["name"].(string)
+	argsStr, _ := fn["arguments"].(string)
+
+	e.logger.Info("Agent executing tool",
+		"group_id", bgCtx.Group.ID,
+		"tool_name", toolName,
+		"tool_call_id", toolCallID,
+	)
+
+	// Parse arguments
+	var toolArgs map[string]interface{}
+	if err := json.Unmarshal([]byte(argsStr), &toolArgs); err != nil {
+		toolArgs = map[string]interface{}{}
+	}
+
+	// Emit tool_call event
+	argsJSON, _ := json.Marshal(toolArgs)
+	e.emitAgentEvent(bgCtx, EventToolCall, ToolCallData{
+		Iteration:  iteration,
+		ToolName:   toolName,
+		ToolCallID: toolCallID,
+		Arguments:  argsJSON,
+	})
+	startTime := time.Now()
+
+	// Find and execute the corresponding tool chain
+	var toolResult interface{}
+	var isError bool
+	if toolChain, ok := toolChainMap[toolName]; ok {
+		// Validate tool arguments against schema before execution
+		if validationErr := validateToolArgs(toolArgs, toolChain.InputSchema); validationErr != nil {
+			e.logger.Warn("Tool argument validation failed",
+				"tool_name", toolName,
+				"missing_fields", validationErr.MissingFields,
+			)
+			toolResult = map[string]interface{}{
+				"error":          validationErr.Message,
+				"missing_fields": validationErr.MissingFields,
+				"suggestion":     "Please provide all required parameters and retry the tool call.",
+			}
+			isError = true
+		} else {
+			toolInput, _ := json.Marshal(toolArgs)
+			output, err := e.executeToolChain(ctx, bgCtx, toolChain, toolInput)
+			if err != nil {
+				toolResult = map[string]interface{}{
+					"error": err.Error(),
+				}
+				isError = true
+			} else {
+				if err := json.Unmarshal(output, &toolResult); err != nil {
+					toolResult = string(output)
+				}
+				// Check if the tool output contains an error field
+				// This handles cases where the tool execution succeeded but returned an error message
+				// (e.g., validation errors from create_step like "project_id, name, and type are required")
+				if resultMap, ok := toolResult.(map[string]interface{}); ok {
+					if _, hasError := resultMap["error"]; hasError {
+						isError = true
+					}
+				}
+			}
+		}
+	} else {
+		toolResult = map[string]interface{}{
+			"error": fmt.Sprintf("Tool not found: %s", toolName),
+		}
+		isError = true
+	}
+
+	toolResultJSON, _ := json.Marshal(toolResult)
+
+	// Emit tool_result event
+	eventResult, truncated := truncateEventResult(toolResultJSON, MaxToolResultEventBytes)
+	e.emitAgentEvent(bgCtx, EventToolResult, ToolResultData{
+		Iteration:  iteration,
+		ToolName:   toolName,
+		ToolCallID: toolCallID,
+		Result:     eventResult,
+		Truncated:  truncated,
+		IsError:    isError,
+		Duration:   time.Since(startTime).Milliseconds(),
+	})
+
+	return toolCallID, toolResultJSON
+}
+
 // emitAgentEvent emits an event for agent execution if an emitter is configured
 func (e *BlockGroupExecutor) emitAgentEvent(bgCtx *BlockGroupContext, eventType ExecutionEventType, data interface{}) {
 	if bgCtx.ExecCtx == nil || bgCtx.ExecCtx.EventEmitter == nil {
@@ -984,6 +1131,15 @@ func (e *BlockGroupExecutor) emitAgentEvent(bgCtx *BlockGroupContext, eventType
 	bgCtx.ExecCtx.EventEmitter.Emit(NewExecutionEvent(bgCtx.ExecCtx.Run.ID, eventType, data))
 }
 
+// agentMemoryWindow returns the memory window for this run.
+// A positive "memory_window" in the input (e.g. a copilot session override) takes precedence over the group config.
+func agentMemoryWindow(configured int, inputData map[string]interface{}) int {
+	if window, ok := inputData["memory_window"].(float64); ok && window >= 1 {
+		return int(window)
+	}
+	return configured
+}
+
 // appendHistoryMessages appends conversation history messages to the message array
 // This enables memory for agents by including previous user and assistant messages
 // History is limited by memoryWindow (0 = no limit)
@@ -1107,7 +1263,7 @@ func (e *BlockGroupExecutor) executeToolChain(ctx context.Context, bgCtx *BlockG
 	currentInput := input
 
 	for _, step := range toolChain.Chain {
-		output, err := e.executeStep(ctx, bgCtx.ExecCtx, bgCtx.Graph, step, currentInput)
+		output, err := e.executeStep(ctx, bgCtx, step, currentInput)
 		if err != nil {
 			return nil, fmt.Errorf("step %s failed: %w", step.Name, err)
 		}
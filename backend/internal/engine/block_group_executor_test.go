@@ -0,0 +1,337 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmitter collects emitted events in order. Parallel steps emit concurrently.
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []ExecutionEvent
+}
+
+func (r *recordingEmitter) Emit(event ExecutionEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingEmitter) Close() {}
+
+func newAgentTestContext(emitter EventEmitter) *BlockGroupContext {
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{})
+	execCtx.EventEmitter = emitter
+	return &BlockGroupContext{
+		Group:   &domain.BlockGroup{ID: uuid.New(), Type: domain.BlockGroupTypeAgent},
+		ExecCtx: execCtx,
+	}
+}
+
+func agentToolCall(id, name, args string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       id,
+		"type":     "function",
+		"function": map[string]interface{}{"name": name, "arguments": args},
+	}
+}
+
+func TestExecuteAgentToolCall_EmitsEventsAroundEachCall(t *testing.T) {
+	emitter := &recordingEmitter{}
+	bgCtx := newAgentTestContext(emitter)
+	executor := NewBlockGroupExecutor(nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	toolChainMap := map[string]*ToolChain{
+		"add_step": {
+			ToolName: "add_step",
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"name"},
+			},
+		},
+	}
+
+	calls := []map[string]interface{}{
+		agentToolCall("call_1", "add_step", `{}`),
+		agentToolCall("call_2", "get_block_schema", `{"slug": "llm"}`),
+	}
+	for _, call := range calls {
+		executor.executeAgentToolCall(context.Background(), bgCtx, toolChainMap, call, 3)
+	}
+
+	require.Len(t, emitter.events, 4)
+	wantTypes := []ExecutionEventType{EventToolCall, EventToolResult, EventToolCall, EventToolResult}
+	wantIDs := []string{"call_1", "call_1", "call_2", "call_2"}
+	for i, event := range emitter.events {
+		assert.Equal(t, wantTypes[i], event.Type, "event %d type", i)
+		assert.Equal(t, bgCtx.ExecCtx.Run.ID, event.RunID)
+
+		var data struct {
+			Iteration  int    `json:"iteration"`
+			ToolCallID string `json:"tool_call_id"`
+			IsError    bool   `json:"is_error"`
+		}
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		assert.Equal(t, wantIDs[i], data.ToolCallID, "event %d tool_call_id", i)
+		assert.Equal(t, 3, data.Iteration)
+		if event.Type == EventToolResult {
+			assert.True(t, data.IsError, "event %d should report the tool error", i)
+		}
+	}
+
+	var started ToolCallData
+	require.NoError(t, json.Unmarshal(emitter.events[2].Data, &started))
+	assert.Equal(t, "get_block_schema", started.ToolName)
+	assert.JSONEq(t, `{"slug": "llm"}`, string(started.Arguments))
+}
+
+func TestExecuteAgentToolCall_TruncatesEventResultOnly(t *testing.T) {
+	emitter := &recordingEmitter{}
+	bgCtx := newAgentTestContext(emitter)
+	executor := NewBlockGroupExecutor(nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	longName := strings.Repeat("あ", MaxToolResultEventBytes)
+	_, result := executor.executeAgentToolCall(context.Background(), bgCtx, nil, agentToolCall("call_1", longName, `{}`), 0)
+
+	assert.Contains(t, string(result), longName, "LLM receives the full result")
+
+	var data ToolResultData
+	require.NoError(t, json.Unmarshal(emitter.events[1].Data, &data))
+	assert.True(t, data.Truncated)
+	assert.LessOrEqual(t, len(data.Result), MaxToolResultEventBytes+16)
+
+	var preview string
+	require.NoError(t, json.Unmarshal(data.Result, &preview))
+	assert.True(t, strings.HasSuffix(preview, "..."))
+}
+
+func TestTruncateEventResult(t *testing.T) {
+	short := json.RawMessage(`{"ok": true}`)
+	got, truncated := truncateEventResult(short, 100)
+	assert.False(t, truncated)
+	assert.Equal(t, short, got)
+
+	got, truncated = truncateEventResult(json.RawMessage(`"héllo"`), 3)
+	assert.True(t, truncated)
+	assert.Equal(t, `"\"h..."`, string(got), "cut backs off to a rune boundary")
+}
+
+func TestAgentMemoryWindow(t *testing.T) {
+	assert.Equal(t, 30, agentMemoryWindow(30, map[string]interface{}{}))
+	assert.Equal(t, 30, agentMemoryWindow(30, map[string]interface{}{"memory_window": float64(0)}))
+	assert.Equal(t, 5, agentMemoryWindow(30, map[string]interface{}{"memory_window": float64(5)}))
+	assert.Equal(t, 50, agentMemoryWindow(30, map[string]interface{}{"memory_window": float64(50)}))
+}
+
+func TestAppendHistoryMessages_UsesSessionWindow(t *testing.T) {
+	executor := NewBlockGroupExecutor(nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	var inputData map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"memory_window": 2,
+		"history": [
+			{"role": "user", "content": "first"},
+			{"role": "assistant", "content": "second"},
+			{"role": "user", "content": "third"}
+		]
+	}`), &inputData))
+
+	messages := executor.appendHistoryMessages(nil, inputData, agentMemoryWindow(30, inputData))
+	require.Len(t, messages, 2)
+	assert.Equal(t, "second", messages[0]["content"])
+	assert.Equal(t, "third", messages[1]["content"])
+}
+
+// delayAdapter sleeps for the configured delay, then echoes its label and input
+type delayAdapter struct {
+	mu        sync.Mutex
+	completed []string
+}
+
+func (a *delayAdapter) ID() string                    { return "delay" }
+func (a *delayAdapter) Name() string                  { return "Delay" }
+func (a *delayAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *delayAdapter) OutputSchema() json.RawMessage { return nil }
+
+func (a *delayAdapter) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	var config struct {
+		Label   string `json:"label"`
+		DelayMs int    `json:"delay_ms"`
+		Fail    bool   `json:"fail"`
+	}
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-time.After(time.Duration(config.DelayMs) * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	a.mu.Lock()
+	a.completed = append(a.completed, config.Label)
+	a.mu.Unlock()
+
+	if config.Fail {
+		return nil, errors.New(config.Label + " failed")
+	}
+	output, _ := json.Marshal(map[string]interface{}{"label": config.Label, "input": json.RawMessage(req.Input)})
+	return &adapter.Response{Output: output}, nil
+}
+
+// parallelTestGroup builds a project with a single parallel group
+type parallelTestGroup struct {
+	def   *domain.ProjectDefinition
+	group domain.BlockGroup
+}
+
+func newParallelTestGroup(config string) *parallelTestGroup {
+	group := domain.BlockGroup{ID: uuid.New(), Name: "fan-out", Type: domain.BlockGroupTypeParallel, Config: json.RawMessage(config)}
+	return &parallelTestGroup{
+		def:   &domain.ProjectDefinition{Name: "parallel", BlockGroups: []domain.BlockGroup{group}},
+		group: group,
+	}
+}
+
+// addStep adds a delay step to the group, chained after `after` when it is not nil
+func (p *parallelTestGroup) addStep(name string, delayMs int, fail bool, after *uuid.UUID) uuid.UUID {
+	id := uuid.New()
+	config, _ := json.Marshal(map[string]interface{}{"adapter_id": "delay", "label": name, "delay_ms": delayMs, "fail": fail})
+	groupID := p.group.ID
+	p.def.Steps = append(p.def.Steps, domain.Step{ID: id, Name: name, Type: domain.StepTypeTool, Config: config, BlockGroupID: &groupID})
+	if after != nil {
+		source := *after
+		p.def.Edges = append(p.def.Edges, domain.Edge{ID: uuid.New(), SourceStepID: &source, TargetStepID: &id})
+	}
+	return id
+}
+
+func (p *parallelTestGroup) run(t *testing.T, recorder *delayAdapter, input string) (map[string]interface{}, error) {
+	t.Helper()
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(input), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, p.def)
+	graph := executor.buildGraph(p.def)
+
+	output, _, err := executor.executeBlockGroup(context.Background(), execCtx, graph, &p.group, json.RawMessage(input))
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return result, nil
+}
+
+func TestExecuteParallel_MergedOutputIndependentOfCompletionOrder(t *testing.T) {
+	var outputs []json.RawMessage
+	var completionOrders [][]string
+	for _, delays := range [][]int{{1, 20, 40}, {40, 20, 1}} {
+		group := newParallelTestGroup(`{}`)
+		group.addStep("charlie", delays[0], false, nil)
+		group.addStep("alpha", delays[1], false, nil)
+		group.addStep("bravo", delays[2], false, nil)
+
+		recorder := &delayAdapter{}
+		result, err := group.run(t, recorder, `{"q":1}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, true, result["completed"])
+		assert.Equal(t, float64(3), result["count"])
+		assert.Equal(t, []interface{}{"alpha", "bravo", "charlie"}, result["branches"])
+		assert.NotContains(t, result, "errors")
+
+		merged, err := json.Marshal(result)
+		require.NoError(t, err)
+		outputs = append(outputs, merged)
+		completionOrders = append(completionOrders, recorder.completed)
+	}
+
+	assert.NotEqual(t, completionOrders[0], completionOrders[1], "branches should finish in different orders")
+	assert.JSONEq(t, string(outputs[0]), string(outputs[1]))
+}
+
+func TestExecuteParallel_BranchChainOutputsLastStep(t *testing.T) {
+	group := newParallelTestGroup(`{}`)
+	fetch := group.addStep("fetch", 5, false, nil)
+	group.addStep("parse", 0, false, &fetch)
+	group.addStep("other", 0, false, nil)
+
+	result, err := group.run(t, &delayAdapter{}, `{"q":1}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), result["count"])
+	results := result["results"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"label": "parse",
+		"input": map[string]interface{}{"label": "fetch", "input": map[string]interface{}{"q": float64(1)}},
+	}, results["fetch"], "branch output is the last step's output and each step feeds the next")
+	assert.Equal(t, "other", results["other"].(map[string]interface{})["label"])
+}
+
+func TestExecuteParallel_BranchErrorsAreReported(t *testing.T) {
+	group := newParallelTestGroup(`{}`)
+	group.addStep("ok", 5, false, nil)
+	group.addStep("broken", 0, true, nil)
+
+	result, err := group.run(t, &delayAdapter{}, `{}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, false, result["completed"])
+	assert.Equal(t, map[string]interface{}{"broken": "broken failed"}, result["errors"])
+	results := result["results"].(map[string]interface{})
+	assert.Contains(t, results, "ok")
+	assert.NotContains(t, results, "broken")
+}
+
+func TestExecuteParallel_FailFastCancelsOtherBranches(t *testing.T) {
+	group := newParallelTestGroup(`{"fail_fast": true}`)
+	group.addStep("slow", 2000, false, nil)
+	group.addStep("broken", 0, true, nil)
+
+	recorder := &delayAdapter{}
+	start := time.Now()
+	_, err := group.run(t, recorder, `{}`)
+
+	assert.ErrorContains(t, err, "broken failed")
+	assert.Less(t, time.Since(start), time.Second, "the slow branch is cancelled")
+	assert.Equal(t, []string{"broken"}, recorder.completed)
+}
+
+func TestBuildParallelBranches_DuplicateNames(t *testing.T) {
+	group := newParallelTestGroup(`{}`)
+	first := group.addStep("call", 0, false, nil)
+	second := group.addStep("call", 0, false, nil)
+
+	steps := make([]*domain.Step, len(group.def.Steps))
+	for i := range group.def.Steps {
+		steps[i] = &group.def.Steps[i]
+	}
+	branches := buildParallelBranches(&group.group, steps, nil)
+
+	require.Len(t, branches, 2)
+	assert.Equal(t, "call", branches[0].Name)
+	assert.Equal(t, "call_2", branches[1].Name)
+	if first.String() < second.String() {
+		assert.Equal(t, first, branches[0].Chain[0].ID)
+	} else {
+		assert.Equal(t, second, branches[0].Chain[0].ID)
+	}
+}
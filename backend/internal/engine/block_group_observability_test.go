@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// logCapture is a slog.Handler that keeps every record's message and attributes
+type logCapture struct {
+	mu      sync.Mutex
+	records []capturedLog
+}
+
+type capturedLog struct {
+	message string
+	attrs   map[string]interface{}
+}
+
+func (c *logCapture) Enabled(context.Context, slog.Level) bool { return true }
+
+func (c *logCapture) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]interface{})
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value.Any()
+		return true
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = append(c.records, capturedLog{message: record.Message, attrs: attrs})
+	return nil
+}
+
+func (c *logCapture) WithAttrs([]slog.Attr) slog.Handler { return c }
+
+func (c *logCapture) WithGroup(string) slog.Handler { return c }
+
+// find returns the records with the given message
+func (c *logCapture) find(message string) []capturedLog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var found []capturedLog
+	for _, record := range c.records {
+		if record.message == message {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+var (
+	spanRecorderOnce sync.Once
+	spanRecorder     *tracetest.SpanRecorder
+)
+
+// recordSpans installs a span recorder as the global tracer provider. The engine tracer only
+// delegates to the first provider set, so the recorder is shared and tests filter its spans.
+func recordSpans() *tracetest.SpanRecorder {
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+	return spanRecorder
+}
+
+// groupSpans returns the ended spans with the given name that carry the group's ID
+func groupSpans(recorder *tracetest.SpanRecorder, name string, groupID uuid.UUID) []sdktrace.ReadOnlySpan {
+	var found []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() != name {
+			continue
+		}
+		for _, attr := range span.Attributes() {
+			if attr.Key == "group_id" && attr.Value.AsString() == groupID.String() {
+				found = append(found, span)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// descendsFrom reports whether span is nested, at any depth, under ancestor
+func descendsFrom(recorder *tracetest.SpanRecorder, span, ancestor sdktrace.ReadOnlySpan) bool {
+	byID := make(map[string]sdktrace.ReadOnlySpan)
+	for _, ended := range recorder.Ended() {
+		byID[ended.SpanContext().SpanID().String()] = ended
+	}
+	for current, ok := span, true; ok; current, ok = byID[current.Parent().SpanID().String()] {
+		if current.Parent().SpanID() == ancestor.SpanContext().SpanID() {
+			return true
+		}
+	}
+	return false
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestExecuteGroup_LogsAndTracesBoundaries(t *testing.T) {
+	spans := recordSpans()
+	logs := &logCapture{}
+	recorder := newStepRecorder()
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	executor := NewExecutor(registry, slog.New(logs))
+
+	def := boundaryPipeline(`{}`, false)
+	group := def.BlockGroups[0]
+	input := `{"query":"x"}`
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(input), domain.TriggerTypeManual)
+
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, def)))
+
+	started := logs.find("Block group started")
+	require.Len(t, started, 1)
+	assert.Equal(t, group.ID, started[0].attrs["group_id"])
+	assert.Equal(t, int64(1), started[0].attrs["step_count"])
+	assert.Equal(t, int64(len(input)), started[0].attrs["input_bytes"])
+	assert.NotContains(t, started[0].attrs, "input_raw", "the input itself is not logged")
+
+	completed := logs.find("Block group completed")
+	require.Len(t, completed, 1)
+	assert.Equal(t, group.ID, completed[0].attrs["group_id"])
+	assert.Equal(t, "out", completed[0].attrs["output_port"])
+	assert.Equal(t, int64(1), completed[0].attrs["steps_executed"])
+	assert.Positive(t, completed[0].attrs["output_bytes"])
+	assert.Contains(t, completed[0].attrs, "duration_ms")
+
+	stepLogs := logs.find("Block group step completed")
+	require.Len(t, stepLogs, 1)
+	assert.Equal(t, group.ID, stepLogs[0].attrs["group_id"])
+	assert.Equal(t, "body", stepLogs[0].attrs["step_name"])
+	assert.Positive(t, stepLogs[0].attrs["output_bytes"])
+
+	groupSpan := groupSpans(spans, "block_group.execute", group.ID)
+	require.Len(t, groupSpan, 1)
+	attrs := spanAttributes(groupSpan[0])
+	assert.Equal(t, "out", attrs["output_port"].AsString())
+	assert.Equal(t, int64(len(input)), attrs["input_bytes"].AsInt64())
+	assert.Positive(t, attrs["output_bytes"].AsInt64())
+	assert.Equal(t, int64(1), attrs["steps_executed"].AsInt64())
+
+	stepSpans := groupSpans(spans, "block_group.step", group.ID)
+	require.Len(t, stepSpans, 1)
+	assert.True(t, descendsFrom(spans, stepSpans[0], groupSpan[0]), "child steps are traced under the group")
+	stepAttrs := spanAttributes(stepSpans[0])
+	assert.Equal(t, "body", stepAttrs["step_name"].AsString())
+	assert.Equal(t, int64(len(input)), stepAttrs["input_bytes"].AsInt64())
+}
+
+func TestExecuteGroup_LogsFailure(t *testing.T) {
+	logs := &logCapture{}
+	recorder := newStepRecorder()
+	recorder.failures["body"] = 1
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	executor := NewExecutor(registry, slog.New(logs))
+
+	def := boundaryPipeline(`{"fail_fast": true}`, false)
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	require.Error(t, executor.Execute(context.Background(), NewExecutionContext(run, def)))
+
+	assert.Empty(t, logs.find("Block group completed"))
+	failed := logs.find("Block group failed")
+	require.Len(t, failed, 1)
+	assert.Equal(t, def.BlockGroups[0].ID, failed[0].attrs["group_id"])
+	assert.Equal(t, int64(1), failed[0].attrs["steps_executed"])
+	assert.Contains(t, failed[0].attrs["error"].(error).Error(), "transient failure in body")
+
+	stepFailed := logs.find("Block group step failed")
+	require.Len(t, stepFailed, 1)
+	assert.Equal(t, "body", stepFailed[0].attrs["step_name"])
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// Condition and switch steps pass their input through unchanged to the selected port. Their raw
+// output wraps the input with the port, and extractOutputPortAndData unwraps it before the data
+// is stored and handed downstream.
+const (
+	branchPortKey = "__port"
+	branchDataKey = "__branch_data"
+)
+
+// branchOutput builds the raw output of a condition or switch step that routes input to port.
+// Empty input is passed on as an empty object and input that is not valid JSON as a string.
+func branchOutput(port string, input json.RawMessage) (json.RawMessage, error) {
+	var data interface{} = json.RawMessage(input)
+	if len(input) == 0 {
+		data = json.RawMessage(`{}`)
+	} else if !json.Valid(input) {
+		data = string(input)
+	}
+	return json.Marshal(map[string]interface{}{branchPortKey: port, branchDataKey: data})
+}
+
+// parseBranchOutput returns the port and passed-through input of a branchOutput
+func parseBranchOutput(output json.RawMessage) (string, json.RawMessage, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(output, &fields); err != nil || len(fields) != 2 {
+		return "", nil, false
+	}
+	data, ok := fields[branchDataKey]
+	if !ok {
+		return "", nil, false
+	}
+	var port string
+	if err := json.Unmarshal(fields[branchPortKey], &port); err != nil || port == "" {
+		return "", nil, false
+	}
+	return port, data, true
+}
+
+// recordEvaluation stores how a condition or switch step chose its branch on the step run, for
+// debugging, without it becoming part of the data passed downstream
+func (e *Executor) recordEvaluation(stepRun *domain.StepRun, evaluation map[string]interface{}) {
+	if stepRun == nil {
+		return
+	}
+	raw, err := json.Marshal(evaluation)
+	if err != nil {
+		e.logger.Warn("Failed to record step evaluation", "step_id", stepRun.StepID, "error", err)
+		return
+	}
+	stepRun.Evaluation = raw
+}
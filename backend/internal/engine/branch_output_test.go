@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// branchPipeline builds start -> branch -> recorder steps, one per port, where each recorder is
+// labelled with the port it is connected to
+func branchPipeline(branchType domain.StepType, config string, ports ...string) (*domain.ProjectDefinition, uuid.UUID) {
+	startID, branchID := uuid.New(), uuid.New()
+	def := &domain.ProjectDefinition{
+		Name: "branch",
+		Steps: []domain.Step{
+			{ID: startID, Name: "start", Type: domain.StepTypeStart},
+			{ID: branchID, Name: "branch", Type: branchType, Config: json.RawMessage(config)},
+		},
+		Edges: []domain.Edge{{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &branchID}},
+	}
+	for _, port := range ports {
+		id := uuid.New()
+		stepConfig, _ := json.Marshal(map[string]string{"adapter_id": "recorder", "label": port})
+		def.Steps = append(def.Steps, domain.Step{ID: id, Name: port, Type: domain.StepTypeTool, Config: stepConfig})
+		def.Edges = append(def.Edges, domain.Edge{ID: uuid.New(), SourceStepID: &branchID, TargetStepID: &id, SourcePort: port})
+	}
+	return def, branchID
+}
+
+func TestExecute_ConditionPassesInputThrough(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantPort       string
+		wantEvaluation map[string]interface{}
+	}{
+		{
+			name:           "true branch",
+			input:          `{"name":"Aiko","score":92}`,
+			wantPort:       "true",
+			wantEvaluation: map[string]interface{}{"expression": "$.score >= 80", "result": true, "port": "true"},
+		},
+		{
+			name:           "false branch",
+			input:          `{"name":"Ken","score":41,"tags":["a","b"]}`,
+			wantPort:       "false",
+			wantEvaluation: map[string]interface{}{"expression": "$.score >= 80", "result": false, "port": "false"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newStepRecorder()
+			executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+			def, branchID := branchPipeline(domain.StepTypeCondition, `{"expression": "$.score >= 80"}`, "true", "false")
+			run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(tt.input), domain.TriggerTypeManual)
+			execCtx := NewExecutionContext(run, def)
+
+			require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+			assert.Equal(t, []string{tt.wantPort}, recorder.executed)
+			assert.JSONEq(t, tt.input, string(recorder.inputs[tt.wantPort]), "the branch receives the original input")
+			assert.JSONEq(t, tt.input, string(execCtx.StepData[branchID]))
+
+			var evaluation map[string]interface{}
+			require.NoError(t, json.Unmarshal(execCtx.StepRuns[branchID].Evaluation, &evaluation))
+			assert.Equal(t, tt.wantEvaluation, evaluation)
+		})
+	}
+}
+
+func TestExecute_SwitchPassesInputThrough(t *testing.T) {
+	config := `{"cases": [
+		{"name": "case_1", "expression": "$.kind == \"invoice\""},
+		{"name": "case_2", "expression": "$.amount > 100"},
+		{"name": "default", "is_default": true}
+	]}`
+	tests := []struct {
+		name     string
+		input    string
+		wantPort string
+		wantCase interface{}
+	}{
+		{name: "first matching case", input: `{"kind":"invoice","amount":500}`, wantPort: "case_1", wantCase: "case_1"},
+		{name: "later case", input: `{"kind":"receipt","amount":500}`, wantPort: "case_2", wantCase: "case_2"},
+		{name: "default case", input: `{"kind":"receipt","amount":5}`, wantPort: "default", wantCase: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newStepRecorder()
+			executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+			def, branchID := branchPipeline(domain.StepTypeSwitch, config, "case_1", "case_2", "default")
+			run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(tt.input), domain.TriggerTypeManual)
+			execCtx := NewExecutionContext(run, def)
+
+			require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+			assert.Equal(t, []string{tt.wantPort}, recorder.executed)
+			assert.JSONEq(t, tt.input, string(recorder.inputs[tt.wantPort]), "the case receives the original input")
+
+			var evaluation map[string]interface{}
+			require.NoError(t, json.Unmarshal(execCtx.StepRuns[branchID].Evaluation, &evaluation))
+			assert.Equal(t, tt.wantCase, evaluation["matched_case"])
+			assert.Equal(t, tt.wantPort, evaluation["port"])
+		})
+	}
+}
+
+func TestBranchOutput_RoundTrip(t *testing.T) {
+	for _, input := range []string{`{"a":1}`, `[1,2,3]`, `"text"`, `42`, `{"__port":"user"}`} {
+		output, err := branchOutput("true", json.RawMessage(input))
+		require.NoError(t, err)
+		port, data, ok := parseBranchOutput(output)
+		require.True(t, ok, input)
+		assert.Equal(t, "true", port)
+		assert.JSONEq(t, input, string(data))
+	}
+
+	output, err := branchOutput("false", nil)
+	require.NoError(t, err)
+	_, data, ok := parseBranchOutput(output)
+	require.True(t, ok)
+	assert.JSONEq(t, `{}`, string(data), "empty input is passed on as an empty object")
+
+	_, _, ok = parseBranchOutput(json.RawMessage(`{"__port":"true","value":1}`))
+	assert.False(t, ok, "ordinary __port output is not a branch output")
+}
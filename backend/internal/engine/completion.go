@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// completionTracker records which steps and block groups of one execution have completed and
+// which are already scheduled. A single tracker is shared by every goroutine and recursion level
+// of the execution, and a step or group is claimed in the same critical section that checks its
+// dependencies, so a step reachable from several parallel branches runs once and a group waits
+// for all of its inputs.
+type completionTracker struct {
+	mu              sync.Mutex
+	completed       map[uuid.UUID]bool
+	completedGroups map[uuid.UUID]bool
+	scheduled       map[uuid.UUID]bool // steps and groups claimed for execution
+}
+
+func newCompletionTracker() *completionTracker {
+	return &completionTracker{
+		completed:       make(map[uuid.UUID]bool),
+		completedGroups: make(map[uuid.UUID]bool),
+		scheduled:       make(map[uuid.UUID]bool),
+	}
+}
+
+// schedule claims steps or groups that are executed unconditionally (e.g. the start steps)
+func (t *completionTracker) schedule(ids ...uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range ids {
+		t.scheduled[id] = true
+	}
+}
+
+// completeStep marks a step as completed
+func (t *completionTracker) completeStep(id uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scheduled[id] = true
+	t.completed[id] = true
+}
+
+// completeGroup marks a block group as completed
+func (t *completionTracker) completeGroup(id uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scheduled[id] = true
+	t.completedGroups[id] = true
+}
+
+// claim schedules a step or group that has not been scheduled yet. It reports false when another
+// branch already claimed it.
+func (t *completionTracker) claim(id uuid.UUID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.scheduled[id] {
+		return false
+	}
+	t.scheduled[id] = true
+	return true
+}
+
+// claimStep schedules a step once at least one of its incoming edges comes from a completed step
+// or group (OR semantics for multiple inputs)
+func (t *completionTracker) claimStep(graph *Graph, id uuid.UUID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.scheduled[id] {
+		return false
+	}
+	for _, inEdge := range graph.InEdges[id] {
+		if (inEdge.SourceStepID != nil && t.completed[*inEdge.SourceStepID]) ||
+			(inEdge.SourceBlockGroupID != nil && t.completedGroups[*inEdge.SourceBlockGroupID]) {
+			t.scheduled[id] = true
+			return true
+		}
+	}
+	return false
+}
+
+// claimGroup schedules a block group once all of its incoming edges come from completed steps or
+// groups
+func (t *completionTracker) claimGroup(graph *Graph, id uuid.UUID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.scheduled[id] {
+		return false
+	}
+	for _, inEdge := range graph.GroupInEdges[id] {
+		if inEdge.SourceStepID != nil && !t.completed[*inEdge.SourceStepID] {
+			return false
+		}
+		if inEdge.SourceBlockGroupID != nil && !t.completedGroups[*inEdge.SourceBlockGroupID] {
+			return false
+		}
+	}
+	t.scheduled[id] = true
+	return true
+}
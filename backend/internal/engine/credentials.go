@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// CredentialResolver resolves the credentials bound to a step into the data blocks read from
+// ctx.credentials. Credentials are looked up within the tenant, so an ID of another tenant's
+// credential must fail. access identifies the run and step for auditing the decryption.
+type CredentialResolver interface {
+	ResolveBoundCredentials(ctx context.Context, tenantID uuid.UUID, bindings map[string]uuid.UUID, access domain.CredentialAccess) (map[string]interface{}, error)
+}
+
+// WithCredentialResolver sets the resolver of step credential bindings
+func WithCredentialResolver(resolver CredentialResolver) ExecutorOption {
+	return func(e *Executor) {
+		e.credentials = resolver
+	}
+}
+
+// resolveStepCredentials resolves the credential bindings of a block step. A binding is a
+// credential ID or a template such as {{$.credential_id}}, expanded against the step input and
+// variables so a workflow can choose the credential per run (e.g. per-customer accounts).
+// Returns nil when the step binds no credentials.
+func (e *Executor) resolveStepCredentials(ctx context.Context, execCtx *ExecutionContext, step domain.Step, input json.RawMessage) (map[string]interface{}, error) {
+	if e.credentials == nil {
+		return nil, nil
+	}
+	refs, err := step.GetCredentialBindingRefs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential bindings: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	if execCtx == nil || execCtx.Run == nil {
+		return nil, fmt.Errorf("credential bindings of step %s require execution context with run information", step.Name)
+	}
+
+	bindings := make(map[string]uuid.UUID, len(refs))
+	for name, ref := range refs {
+		if !domain.IsCredentialBindingTemplate(ref) {
+			bindings[name] = uuid.MustParse(ref)
+			continue
+		}
+		credID, err := e.expandCredentialBinding(execCtx, ref, input)
+		if err != nil {
+			return nil, fmt.Errorf("credential binding '%s': %w", name, err)
+		}
+		bindings[name] = credID
+	}
+
+	return e.credentials.ResolveBoundCredentials(ctx, execCtx.Run.TenantID, bindings, domain.CredentialAccess{
+		Purpose: domain.CredentialAccessExecution,
+		RunID:   &execCtx.Run.ID,
+		StepID:  &step.ID,
+		ActorID: execCtx.Run.TriggeredByUser,
+	})
+}
+
+// expandCredentialBinding expands a template credential binding to a credential ID
+func (e *Executor) expandCredentialBinding(execCtx *ExecutionContext, ref string, input json.RawMessage) (uuid.UUID, error) {
+	template, err := json.Marshal(ref)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	expanded, err := ExpandConfigTemplatesWithScopes(template, input, execCtx.ScopedVars)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	var value string
+	if err := json.Unmarshal(expanded, &value); err != nil || value == "" || domain.IsCredentialBindingTemplate(value) {
+		return uuid.Nil, fmt.Errorf("%s did not resolve to a credential ID", ref)
+	}
+	credID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s resolved to %q, which is not a credential ID", ref, value)
+	}
+	return credID, nil
+}
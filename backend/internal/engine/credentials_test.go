@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantCredentialResolver resolves credentials registered per tenant, like the tenant-scoped
+// credential lookup of the real resolver
+type tenantCredentialResolver struct {
+	credentials map[uuid.UUID]map[uuid.UUID]map[string]interface{}
+}
+
+func (r *tenantCredentialResolver) add(tenantID uuid.UUID, data map[string]interface{}) uuid.UUID {
+	id := uuid.New()
+	if r.credentials[tenantID] == nil {
+		r.credentials[tenantID] = make(map[uuid.UUID]map[string]interface{})
+	}
+	r.credentials[tenantID][id] = data
+	return id
+}
+
+func (r *tenantCredentialResolver) ResolveBoundCredentials(ctx context.Context, tenantID uuid.UUID, bindings map[string]uuid.UUID, access domain.CredentialAccess) (map[string]interface{}, error) {
+	credentials := make(map[string]interface{}, len(bindings))
+	for name, id := range bindings {
+		data, ok := r.credentials[tenantID][id]
+		if !ok {
+			return nil, domain.ErrCredentialNotFound
+		}
+		credentials[name] = data
+	}
+	return credentials, nil
+}
+
+// runCredentialBlock runs a block returning the api_key of its "account" credential
+func runCredentialBlock(t *testing.T, resolver CredentialResolver, tenantID uuid.UUID, bindings string, input string) (json.RawMessage, error) {
+	t.Helper()
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)), WithCredentialResolver(resolver))
+	run := domain.NewRun(tenantID, uuid.New(), 1, json.RawMessage(input), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Name: "credentials"})
+
+	blockDef := domain.NewBlockDefinition(nil, "crm-lookup", "CRM Lookup", domain.BlockCategoryApps)
+	blockDef.Code = `return { api_key: ctx.credentials && ctx.credentials.account ? ctx.credentials.account.api_key : null };`
+	step := domain.Step{ID: uuid.New(), Name: "lookup", Type: "crm-lookup", Config: json.RawMessage(`{}`), CredentialBindings: json.RawMessage(bindings)}
+
+	return executor.executeBlockDefinition(context.Background(), execCtx, step, blockDef, json.RawMessage(input))
+}
+
+func TestExecuteBlockDefinition_CredentialBindings(t *testing.T) {
+	tenantID := uuid.New()
+	resolver := &tenantCredentialResolver{credentials: make(map[uuid.UUID]map[uuid.UUID]map[string]interface{})}
+	acme := resolver.add(tenantID, map[string]interface{}{"type": "api_key", "api_key": "acme-key"})
+	globex := resolver.add(tenantID, map[string]interface{}{"type": "api_key", "api_key": "globex-key"})
+
+	t.Run("static ID", func(t *testing.T) {
+		output, err := runCredentialBlock(t, resolver, tenantID, `{"account": "`+acme.String()+`"}`, `{}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"api_key": "acme-key"}`, string(output))
+	})
+
+	t.Run("template resolved per run", func(t *testing.T) {
+		for id, want := range map[uuid.UUID]string{acme: "acme-key", globex: "globex-key"} {
+			output, err := runCredentialBlock(t, resolver, tenantID, `{"account": "{{$.credential_id}}"}`, `{"credential_id": "`+id.String()+`"}`)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"api_key": "`+want+`"}`, string(output))
+		}
+	})
+
+	t.Run("template without a credential ID", func(t *testing.T) {
+		_, err := runCredentialBlock(t, resolver, tenantID, `{"account": "{{$.credential_id}}"}`, `{"credential_id": "acme"}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "credential binding 'account'")
+	})
+
+	t.Run("no bindings", func(t *testing.T) {
+		output, err := runCredentialBlock(t, resolver, tenantID, `{}`, `{}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"api_key": null}`, string(output))
+	})
+}
+
+func TestExecuteBlockDefinition_CredentialBindingOfAnotherTenant(t *testing.T) {
+	tenantID, otherTenantID := uuid.New(), uuid.New()
+	resolver := &tenantCredentialResolver{credentials: make(map[uuid.UUID]map[uuid.UUID]map[string]interface{})}
+	foreign := resolver.add(otherTenantID, map[string]interface{}{"type": "api_key", "api_key": "other-tenant-key"})
+
+	output, err := runCredentialBlock(t, resolver, tenantID, `{"account": "{{$.credential_id}}"}`, `{"credential_id": "`+foreign.String()+`"}`)
+
+	require.ErrorIs(t, err, domain.ErrCredentialNotFound)
+	assert.Nil(t, output)
+}
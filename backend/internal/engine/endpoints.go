@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// withRunTenant returns ctx carrying the run's tenant, which adapters use to keep tenant-scoped
+// state such as cached HTTP responses apart
+func withRunTenant(ctx context.Context, execCtx *ExecutionContext) context.Context {
+	if execCtx.Run == nil {
+		return ctx
+	}
+	return adapter.WithTenantID(ctx, execCtx.Run.TenantID)
+}
+
+// withEndpointOverrides returns ctx carrying the tenant's provider endpoint overrides from its
+// settings. LLM and embedding adapters route requests whose step config selects no endpoint to
+// the tenant's, and reject any override outside their allowlist.
+func (e *Executor) withEndpointOverrides(ctx context.Context, execCtx *ExecutionContext) context.Context {
+	if e.pool == nil || execCtx.Run == nil {
+		return ctx
+	}
+	var endpointsJSON []byte
+	err := e.pool.QueryRow(ctx,
+		`SELECT COALESCE(settings->'`+domain.TenantSettingEndpoints+`', '{}'::jsonb) FROM tenants WHERE id = $1 AND deleted_at IS NULL`,
+		execCtx.Run.TenantID,
+	).Scan(&endpointsJSON)
+	if err != nil {
+		e.logger.Debug("Failed to load tenant endpoints", "error", err)
+		return ctx
+	}
+
+	var endpoints map[string]domain.EndpointOverride
+	if err := json.Unmarshal(endpointsJSON, &endpoints); err != nil {
+		e.logger.Warn("Invalid tenant endpoints", "tenant_id", execCtx.Run.TenantID, "error", err)
+		return ctx
+	}
+	if len(endpoints) == 0 {
+		return ctx
+	}
+	return adapter.WithEndpointOverrides(ctx, endpoints)
+}
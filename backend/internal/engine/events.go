@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -16,6 +17,7 @@ const (
 	EventStepStarted   ExecutionEventType = "step:started"
 	EventStepCompleted ExecutionEventType = "step:completed"
 	EventStepFailed    ExecutionEventType = "step:failed"
+	EventStepSkipped   ExecutionEventType = "step:skipped"
 
 	// Agent group events
 	EventThinking    ExecutionEventType = "thinking"
@@ -76,30 +78,70 @@ type StepFailedData struct {
 	Error    string `json:"error"`
 }
 
+// StepSkippedData represents data for step:skipped event
+type StepSkippedData struct {
+	StepID   string `json:"step_id"`
+	StepName string `json:"step_name"`
+	RunIf    string `json:"run_if"`
+}
+
 // ThinkingData represents data for thinking event
 type ThinkingData struct {
 	Iteration int    `json:"iteration"`
 	Content   string `json:"content,omitempty"`
 }
 
-// ToolCallData represents data for tool:call event
+// ToolCallData represents data for tool:call event (emitted before the tool runs)
 type ToolCallData struct {
+	Iteration  int             `json:"iteration"`
 	ToolName   string          `json:"tool_name"`
 	ToolCallID string          `json:"tool_call_id,omitempty"`
 	Arguments  json.RawMessage `json:"arguments,omitempty"`
 }
 
-// ToolResultData represents data for tool:result event
+// ToolResultData represents data for tool:result event (emitted after the tool runs)
 type ToolResultData struct {
+	Iteration  int             `json:"iteration"`
 	ToolName   string          `json:"tool_name"`
 	ToolCallID string          `json:"tool_call_id,omitempty"`
 	Result     json.RawMessage `json:"result,omitempty"`
+	Truncated  bool            `json:"truncated,omitempty"` // Result was cut to MaxToolResultEventBytes
 	IsError    bool            `json:"is_error"`
+	Duration   int64           `json:"duration_ms"`
 }
 
-// PartialTextData represents data for partial_text event
+// MaxToolResultEventBytes caps the tool result included in a tool:result event
+const MaxToolResultEventBytes = 2000
+
+// truncateEventResult shortens a JSON result for event payloads.
+// Results over max bytes are replaced by a JSON string holding the first max bytes followed by "...".
+func truncateEventResult(result json.RawMessage, max int) (json.RawMessage, bool) {
+	if len(result) <= max {
+		return result, false
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(result[cut]) {
+		cut--
+	}
+	truncated, _ := json.Marshal(string(result[:cut]) + "...")
+	return truncated, true
+}
+
+// PartialTextData represents data for partial_text event (assistant text from one agent iteration)
 type PartialTextData struct {
-	Content string `json:"content"`
+	Iteration int    `json:"iteration"`
+	Content   string `json:"content"`
+}
+
+// progressMinInterval is the minimum time between progress events of one step
+const progressMinInterval = 100 * time.Millisecond
+
+// StepProgressData represents data for progress event (reported by code via ctx.progress)
+type StepProgressData struct {
+	StepID   string  `json:"step_id"`
+	StepName string  `json:"step_name"`
+	Percent  float64 `json:"percent"` // 0-100
+	Message  string  `json:"message,omitempty"`
 }
 
 // RunStartedData represents data for run:started event
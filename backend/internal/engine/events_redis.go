@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	runEventChannelPrefix = "aio:runs:events:"
+	// eventPublishTimeout bounds how long a step waits on Redis when publishing an event
+	eventPublishTimeout = time.Second
+)
+
+// RunEventChannel returns the Redis pub/sub channel carrying the execution events of a run
+func RunEventChannel(runID uuid.UUID) string {
+	return runEventChannelPrefix + runID.String()
+}
+
+// RedisEventEmitter publishes execution events to the run's Redis pub/sub channel so that
+// processes other than the executing worker (e.g. the API's SSE run stream) can relay them.
+// Publishing is best effort: with a nil client it does nothing, and failures are only logged.
+type RedisEventEmitter struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+// NewRedisEventEmitter creates a new Redis-backed event emitter
+func NewRedisEventEmitter(client *redis.Client, logger *slog.Logger) *RedisEventEmitter {
+	return &RedisEventEmitter{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Emit publishes the event to the run's channel
+func (e *RedisEventEmitter) Emit(event ExecutionEvent) {
+	if e == nil || e.client == nil {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), eventPublishTimeout)
+	defer cancel()
+	if err := e.client.Publish(ctx, RunEventChannel(event.RunID), payload).Err(); err != nil && e.logger != nil {
+		e.logger.Warn("Failed to publish execution event", "run_id", event.RunID, "type", event.Type, "error", err)
+	}
+}
+
+// Close does nothing; the Redis client is owned by the caller
+func (e *RedisEventEmitter) Close() {}
+
+// SubscribeRunEvents subscribes to the execution events published for a run. The returned
+// channel is closed when ctx is done. It returns nil when client is nil.
+func SubscribeRunEvents(ctx context.Context, client *redis.Client, runID uuid.UUID) <-chan ExecutionEvent {
+	if client == nil {
+		return nil
+	}
+
+	pubsub := client.Subscribe(ctx, RunEventChannel(runID))
+	events := make(chan ExecutionEvent, 100)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event ExecutionEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					continue
+				}
+				select {
+				case events <- event:
+				default:
+					// Subscriber is slow, skip event
+				}
+			}
+		}
+	}()
+	return events
+}
@@ -3,8 +3,11 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +17,8 @@ import (
 	"github.com/souta/ai-orchestration/internal/adapter"
 	"github.com/souta/ai-orchestration/internal/block/sandbox"
 	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/crypto"
+	"github.com/souta/ai-orchestration/pkg/netguard"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -28,6 +33,11 @@ type BlockDefinitionGetter interface {
 	GetBySlug(ctx context.Context, tenantID *uuid.UUID, slug string) (*domain.BlockDefinition, error)
 }
 
+// CheckpointStore persists run checkpoints taken at steps marked with `checkpoint: true`
+type CheckpointStore interface {
+	Save(ctx context.Context, checkpoint *domain.RunCheckpoint) error
+}
+
 // Executor executes a project DAG
 type Executor struct {
 	registry      *adapter.Registry
@@ -37,6 +47,17 @@ type Executor struct {
 	usageRecorder *UsageRecorder
 	pool          *pgxpool.Pool           // Database pool for sandbox services
 	blockDefRepo  BlockDefinitionGetter   // Repository for custom block definitions
+	checkpoints   CheckpointStore         // Optional store for run checkpoints
+	publisher     EventEmitter            // Optional emitter receiving every event of every run (e.g. Redis pub/sub)
+	inputLimits   domain.InputLimits      // Depth and size limits checked on every step input
+	netGuard      *netguard.Guard         // Blocks workflow HTTP requests to internal addresses; nil disables it
+	maxDuration   time.Duration           // Wall-clock limit of an execution; 0 disables it
+	maxTokens     int                     // Ceiling of LLM max_tokens; 0 disables it
+	encryptor     *crypto.Encryptor       // Decrypts secret variables; nil leaves them unresolved
+	credentials   CredentialResolver      // Resolves credentials bound to block steps; nil leaves them unset
+	notifier      Notifier                // Announces approval requests; nil disables notifications
+	// Library functions that function steps load with require(); nil leaves require undefined
+	functionLibrary FunctionLibrary
 }
 
 // ExecutorOption is a functional option for Executor
@@ -63,6 +84,29 @@ func WithBlockDefinitionRepository(repo BlockDefinitionGetter) ExecutorOption {
 	}
 }
 
+// WithCheckpointStore sets the store used to persist run checkpoints
+func WithCheckpointStore(store CheckpointStore) ExecutorOption {
+	return func(e *Executor) {
+		e.checkpoints = store
+	}
+}
+
+// WithEventPublisher sets an emitter that receives the events of every run in addition to the
+// run's own EventEmitter, such as a RedisEventEmitter relaying events to SSE run streams
+func WithEventPublisher(publisher EventEmitter) ExecutorOption {
+	return func(e *Executor) {
+		e.publisher = publisher
+	}
+}
+
+// WithInputLimits sets the depth and size limits checked on every step input before the step
+// runs. A step whose input exceeds them fails without being executed.
+func WithInputLimits(limits domain.InputLimits) ExecutorOption {
+	return func(e *Executor) {
+		e.inputLimits = limits
+	}
+}
+
 // NewExecutor creates a new executor
 func NewExecutor(registry *adapter.Registry, logger *slog.Logger, opts ...ExecutorOption) *Executor {
 	e := &Executor{
@@ -70,6 +114,10 @@ func NewExecutor(registry *adapter.Registry, logger *slog.Logger, opts ...Execut
 		logger:    logger,
 		evaluator: NewConditionEvaluator(),
 		sandbox:   sandbox.New(sandbox.DefaultConfig()),
+
+		inputLimits: domain.DefaultStepInputLimits(),
+		netGuard:    netguard.New(),
+		maxDuration: DefaultMaxRunDuration,
 	}
 	for _, opt := range opts {
 		opt(e)
@@ -100,6 +148,11 @@ func (e *Executor) loadScopedVariables(ctx context.Context, tenantID, projectID,
 		if err := json.Unmarshal(tenantVarsJSON, &scopes.Org); err != nil {
 			e.logger.Warn("Failed to unmarshal tenant variables", "error", err)
 		}
+		stored := scopes.Org
+		if scopes.Org, err = OpenVariables(e.encryptor, stored); err != nil {
+			e.logger.Warn("Failed to decrypt secret tenant variables", "error", err)
+		}
+		scopes.Secrets = append(scopes.Secrets, secretValues(stored, scopes.Org)...)
 	} else {
 		e.logger.Debug("Failed to load tenant variables", "error", err)
 	}
@@ -122,6 +175,11 @@ func (e *Executor) loadScopedVariables(ctx context.Context, tenantID, projectID,
 			if err := json.Unmarshal(userVarsJSON, &scopes.Personal); err != nil {
 				e.logger.Warn("Failed to unmarshal user variables", "error", err)
 			}
+			stored := scopes.Personal
+			if scopes.Personal, err = OpenVariables(e.encryptor, stored); err != nil {
+				e.logger.Warn("Failed to decrypt secret user variables", "error", err)
+			}
+			scopes.Secrets = append(scopes.Secrets, secretValues(stored, scopes.Personal)...)
 		} else {
 			e.logger.Debug("Failed to load user variables", "error", err)
 		}
@@ -135,14 +193,18 @@ type ExecutionContext struct {
 	Run               *domain.Run
 	Definition        *domain.ProjectDefinition
 	StepRuns          map[uuid.UUID]*domain.StepRun
+	ItemStepRuns      []*domain.StepRun             // per-item step runs of streaming map item steps (StepRun.ItemIndex set)
 	StepData          map[uuid.UUID]json.RawMessage // step outputs
-	StepOutputPorts   map[uuid.UUID]string          // output port used by each step (for port-based routing)
+	StepOutputPorts   map[uuid.UUID]string          // output port used by each step and block group (for port-based routing)
 	GroupData         map[uuid.UUID]json.RawMessage // block group outputs
 	InjectedOutputs   map[string]json.RawMessage    // pre-injected outputs for partial execution
 	ToolInputOverride map[uuid.UUID]json.RawMessage // tool input override for agent tool calls (bypasses edge resolution)
-	ScopedVars        *ScopedVariables              // scoped variables (org, project, personal)
+	ScopedVars        *ScopedVariables              // scoped variables (org, project, personal) and trigger metadata
 	EventEmitter      EventEmitter                  // optional event emitter for streaming progress
 	sequenceCounter   int                           // counter for step execution order within an attempt
+	lastCheckpoint    *domain.RunCheckpoint         // latest persisted checkpoint of this run
+	tenantMaxTokens   *int                          // tenant limits.max_output_tokens, loaded on first use
+	searchProvider    *string                       // tenant settings.search_provider, loaded on first use
 	mu                sync.RWMutex
 }
 
@@ -175,6 +237,34 @@ func (ec *ExecutionContext) SetSequenceCounter(value int) {
 	ec.sequenceCounter = value
 }
 
+// AllStepRuns returns the step runs of this execution to persist: one per executed step, plus
+// one per item for the item steps of a streaming map
+func (ec *ExecutionContext) AllStepRuns() []*domain.StepRun {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	stepRuns := make([]*domain.StepRun, 0, len(ec.StepRuns)+len(ec.ItemStepRuns))
+	for _, stepRun := range ec.StepRuns {
+		stepRuns = append(stepRuns, stepRun)
+	}
+	return append(stepRuns, ec.ItemStepRuns...)
+}
+
+// LastCheckpoint returns the latest checkpoint taken (or resumed from) during this execution, or nil
+func (ec *ExecutionContext) LastCheckpoint() *domain.RunCheckpoint {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	return ec.lastCheckpoint
+}
+
+// redactSecrets masks the decrypted secret variable values of the run in step data that is
+// persisted on a step run or streamed in an event. Execution itself keeps the plain values.
+func (ec *ExecutionContext) redactSecrets(data json.RawMessage) json.RawMessage {
+	if ec.ScopedVars == nil {
+		return data
+	}
+	return redactSecrets(data, ec.ScopedVars.Secrets)
+}
+
 // InjectPreviousOutputs injects outputs from a previous run for partial execution
 func (ec *ExecutionContext) InjectPreviousOutputs(outputs map[string]json.RawMessage) {
 	ec.mu.Lock()
@@ -199,6 +289,10 @@ func (e *Executor) ExecuteSingleStep(ctx context.Context, execCtx *ExecutionCont
 	)
 	defer span.End()
 
+	ctx = e.withNetGuard(ctx, execCtx)
+	ctx = e.withEndpointOverrides(ctx, execCtx)
+	ctx = withRunTenant(ctx, execCtx)
+
 	// Find the step in the definition
 	var targetStep *domain.Step
 	for i := range execCtx.Definition.Steps {
@@ -239,7 +333,13 @@ func (e *Executor) ExecuteSingleStep(ctx context.Context, execCtx *ExecutionCont
 			return nil, fmt.Errorf("failed to prepare step input: %w", err)
 		}
 	}
-	stepRun.Start(stepInput)
+	if err := e.inputLimits.Validate("step input", stepInput); err != nil {
+		stepRun.Fail(err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return stepRun, fmt.Errorf("step %s: %w", targetStep.Name, err)
+	}
+	stepRun.Start(execCtx.redactSecrets(stepInput))
 
 	// Execute step using unified dispatch
 	output, err := e.dispatchStepExecution(ctx, execCtx, *targetStep, stepRun, stepInput)
@@ -256,7 +356,7 @@ func (e *Executor) ExecuteSingleStep(ctx context.Context, execCtx *ExecutionCont
 		return stepRun, err
 	}
 
-	stepRun.Complete(output)
+	stepRun.Complete(execCtx.redactSecrets(output))
 
 	execCtx.mu.Lock()
 	execCtx.StepData[targetStep.ID] = output
@@ -281,6 +381,12 @@ func (e *Executor) ExecuteFromStep(ctx context.Context, execCtx *ExecutionContex
 	)
 	defer span.End()
 
+	ctx = e.withNetGuard(ctx, execCtx)
+	ctx = e.withEndpointOverrides(ctx, execCtx)
+	ctx = withRunTenant(ctx, execCtx)
+	ctx, cancel := e.withRunDeadline(ctx, execCtx)
+	defer cancel()
+
 	// Verify the starting step exists
 	var found bool
 	for i := range execCtx.Definition.Steps {
@@ -313,7 +419,10 @@ func (e *Executor) ExecuteFromStep(ctx context.Context, execCtx *ExecutionContex
 	}
 
 	// Execute from start step
-	if err := e.executeNodes(ctx, execCtx, graph, []uuid.UUID{startStepID}); err != nil {
+	tracker := newCompletionTracker()
+	tracker.schedule(startStepID)
+	if err := e.executeNodes(ctx, execCtx, graph, []uuid.UUID{startStepID}, tracker); err != nil {
+		err = runDeadlineError(ctx, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return err
@@ -323,6 +432,181 @@ func (e *Executor) ExecuteFromStep(ctx context.Context, execCtx *ExecutionContex
 	return nil
 }
 
+// ExecuteFromCheckpoint continues a run downstream of a checkpoint.
+// The checkpoint's accumulated outputs are injected, so the checkpoint step and everything
+// upstream of it are not executed again. Routing continues from every completed step and group
+// of the checkpoint, so parallel branches that had not finished or had failed run again too.
+func (e *Executor) ExecuteFromCheckpoint(ctx context.Context, execCtx *ExecutionContext, checkpoint *domain.RunCheckpoint) error {
+	ctx, span := tracer.Start(ctx, "workflow.execute_from_checkpoint",
+		trace.WithAttributes(
+			attribute.String("run_id", execCtx.Run.ID.String()),
+			attribute.String("checkpoint_step_id", checkpoint.StepID.String()),
+		),
+	)
+	defer span.End()
+
+	ctx = e.withNetGuard(ctx, execCtx)
+	ctx = e.withEndpointOverrides(ctx, execCtx)
+	ctx = withRunTenant(ctx, execCtx)
+	ctx, cancel := e.withRunDeadline(ctx, execCtx)
+	defer cancel()
+
+	graph := e.buildGraph(execCtx.Definition)
+	if _, ok := graph.Steps[checkpoint.StepID]; !ok {
+		err := fmt.Errorf("checkpoint step not found: %s", checkpoint.StepID)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	e.logger.Info("Resuming from checkpoint",
+		"run_id", execCtx.Run.ID,
+		"checkpoint_step_id", checkpoint.StepID,
+		"injected_outputs", len(checkpoint.Outputs),
+	)
+
+	execCtx.ScopedVars = e.runScopedVariables(ctx, execCtx)
+
+	execCtx.InjectPreviousOutputs(checkpoint.Outputs)
+
+	// Everything with an injected output counts as completed, so routing from the
+	// checkpoint only reaches steps that have not run yet
+	tracker := newCompletionTracker()
+	execCtx.mu.Lock()
+	for idStr, port := range checkpoint.OutputPorts {
+		if id, err := uuid.Parse(idStr); err == nil {
+			execCtx.StepOutputPorts[id] = port
+		}
+	}
+	execCtx.StepOutputPorts[checkpoint.StepID] = checkpoint.OutputPort
+	execCtx.lastCheckpoint = checkpoint
+	for id, data := range execCtx.StepData {
+		if _, ok := graph.BlockGroups[id]; ok {
+			execCtx.GroupData[id] = data
+			tracker.completeGroup(id)
+		} else {
+			tracker.completeStep(id)
+		}
+	}
+	execCtx.mu.Unlock()
+
+	frontier := checkpointFrontier(graph, checkpoint)
+	var wg sync.WaitGroup
+	errChan := make(chan error, len(frontier))
+	for _, id := range frontier {
+		wg.Add(1)
+		go func(id uuid.UUID) {
+			defer wg.Done()
+			if err := e.continueFromCheckpoint(ctx, execCtx, graph, id, tracker); err != nil {
+				errChan <- err
+			}
+		}(id)
+	}
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		err = runDeadlineError(ctx, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "execution from checkpoint completed")
+	return nil
+}
+
+// checkpointFrontier returns the completed top-level steps and block groups of a checkpoint that
+// a resume routes from, in a stable order. Checkpoints saved without output ports only resume
+// downstream of the checkpoint step, as the ports the other steps completed with are unknown.
+func checkpointFrontier(graph *Graph, checkpoint *domain.RunCheckpoint) []uuid.UUID {
+	if len(checkpoint.OutputPorts) == 0 {
+		return []uuid.UUID{checkpoint.StepID}
+	}
+
+	var frontier []uuid.UUID
+	for idStr := range checkpoint.Outputs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			continue
+		}
+		_, isGroup := graph.BlockGroups[id]
+		step, isStep := graph.Steps[id]
+		// Steps inside a group are routed by the group
+		if isGroup || (isStep && step.BlockGroupID == nil) {
+			frontier = append(frontier, id)
+		}
+	}
+	sort.Slice(frontier, func(i, j int) bool { return frontier[i].String() < frontier[j].String() })
+	return frontier
+}
+
+// continueFromCheckpoint routes from a step or block group restored from a checkpoint to the
+// steps and groups downstream of it that have not completed
+func (e *Executor) continueFromCheckpoint(ctx context.Context, execCtx *ExecutionContext, graph *Graph, id uuid.UUID, tracker *completionTracker) error {
+	if _, ok := graph.BlockGroups[id]; ok {
+		execCtx.mu.RLock()
+		output := execCtx.GroupData[id]
+		outputPort := execCtx.StepOutputPorts[id]
+		execCtx.mu.RUnlock()
+		if outputPort == "" {
+			outputPort = groupOutputPort(output)
+		}
+		return e.executeFromGroupOutput(ctx, execCtx, graph, id, output, outputPort, tracker)
+	}
+
+	if err := e.executeNextGroups(ctx, execCtx, graph, id, tracker); err != nil {
+		return err
+	}
+	nextNodes := e.findNextNodes(ctx, execCtx, graph, id, tracker)
+	if len(nextNodes) > 0 {
+		return e.executeNodes(ctx, execCtx, graph, nextNodes, tracker)
+	}
+	return nil
+}
+
+// saveCheckpoint snapshots the outputs accumulated so far after a checkpoint step completed.
+// A failed save is logged and leaves the previous checkpoint as the resume point.
+func (e *Executor) saveCheckpoint(ctx context.Context, execCtx *ExecutionContext, step domain.Step, outputPort string) {
+	execCtx.mu.RLock()
+	outputs := make(map[string]json.RawMessage, len(execCtx.StepData)+len(execCtx.GroupData))
+	outputPorts := make(map[string]string, len(execCtx.StepData)+len(execCtx.GroupData))
+	for _, data := range []map[uuid.UUID]json.RawMessage{execCtx.StepData, execCtx.GroupData} {
+		for id, output := range data {
+			outputs[id.String()] = output
+			outputPorts[id.String()] = "output"
+			if port, ok := execCtx.StepOutputPorts[id]; ok && port != "" {
+				outputPorts[id.String()] = port
+			}
+		}
+	}
+	execCtx.mu.RUnlock()
+	outputPorts[step.ID.String()] = outputPort
+
+	checkpoint := domain.NewRunCheckpoint(execCtx.Run.TenantID, execCtx.Run.ID, step.ID, outputPort, outputs)
+	checkpoint.OutputPorts = outputPorts
+	if e.checkpoints != nil {
+		if err := e.checkpoints.Save(ctx, checkpoint); err != nil {
+			e.logger.Warn("Failed to save checkpoint",
+				"run_id", execCtx.Run.ID,
+				"step_id", step.ID,
+				"error", err,
+			)
+			return
+		}
+	}
+
+	execCtx.mu.Lock()
+	execCtx.lastCheckpoint = checkpoint
+	execCtx.mu.Unlock()
+
+	e.logger.Info("Checkpoint saved",
+		"run_id", execCtx.Run.ID,
+		"step_id", step.ID,
+		"outputs", len(outputs),
+	)
+}
+
 // dispatchStepExecution routes step execution to the appropriate handler based on step type.
 // This is the central dispatch point for all step type execution logic.
 func (e *Executor) dispatchStepExecution(ctx context.Context, execCtx *ExecutionContext, step domain.Step, stepRun *domain.StepRun, input json.RawMessage) (json.RawMessage, error) {
@@ -334,7 +618,7 @@ func (e *Executor) dispatchStepExecution(ctx context.Context, execCtx *Execution
 	case domain.StepTypeLLM:
 		return e.executeLLMStep(ctx, execCtx, step, stepRun, input)
 	case domain.StepTypeCondition:
-		return e.executeConditionStep(ctx, execCtx, step, input)
+		return e.executeConditionStep(ctx, execCtx, step, stepRun, input)
 	case domain.StepTypeMap:
 		return e.executeMapStep(ctx, execCtx, step, input)
 	case domain.StepTypeWait:
@@ -346,7 +630,7 @@ func (e *Executor) dispatchStepExecution(ctx context.Context, execCtx *Execution
 	case domain.StepTypeHumanInLoop:
 		return e.executeHumanInLoopStep(ctx, execCtx, step, input)
 	case domain.StepTypeSwitch:
-		return e.executeSwitchStep(ctx, execCtx, step, input)
+		return e.executeSwitchStep(ctx, execCtx, step, stepRun, input)
 	case domain.StepTypeFilter:
 		return e.executeFilterStep(ctx, step, input)
 	case domain.StepTypeSplit:
@@ -413,6 +697,12 @@ func (e *Executor) Execute(ctx context.Context, execCtx *ExecutionContext) error
 	)
 	defer span.End()
 
+	ctx = e.withNetGuard(ctx, execCtx)
+	ctx = e.withEndpointOverrides(ctx, execCtx)
+	ctx = withRunTenant(ctx, execCtx)
+	ctx, cancel := e.withRunDeadline(ctx, execCtx)
+	defer cancel()
+
 	e.logger.Info("Starting project execution",
 		"run_id", execCtx.Run.ID,
 		"project_id", execCtx.Run.ProjectID,
@@ -424,12 +714,8 @@ func (e *Executor) Execute(ctx context.Context, execCtx *ExecutionContext) error
 		ProjectName: execCtx.Definition.Name,
 	})
 
-	// Load scoped variables (org, project, personal)
-	userID := uuid.Nil
-	if execCtx.Run.TriggeredByUser != nil {
-		userID = *execCtx.Run.TriggeredByUser
-	}
-	execCtx.ScopedVars = e.loadScopedVariables(ctx, execCtx.Run.TenantID, execCtx.Run.ProjectID, userID, execCtx.Definition.Variables)
+	// Load scoped variables (org, project, personal), from the run's snapshot when it has one
+	execCtx.ScopedVars = e.runScopedVariables(ctx, execCtx)
 
 	// Build execution graph
 	graph := e.buildGraph(execCtx.Definition)
@@ -459,7 +745,10 @@ func (e *Executor) Execute(ctx context.Context, execCtx *ExecutionContext) error
 
 	// Execute from start nodes
 	startTime := time.Now()
-	if err := e.executeNodes(ctx, execCtx, graph, startNodes); err != nil {
+	tracker := newCompletionTracker()
+	tracker.schedule(startNodes...)
+	if err := e.executeNodes(ctx, execCtx, graph, startNodes, tracker); err != nil {
+		err = runDeadlineError(ctx, err)
 		// Emit run failed event
 		e.emitEvent(execCtx, EventRunFailed, RunFailedData{
 			Error: err.Error(),
@@ -480,10 +769,44 @@ func (e *Executor) Execute(ctx context.Context, execCtx *ExecutionContext) error
 
 // emitEvent emits an execution event if an emitter is configured
 func (e *Executor) emitEvent(execCtx *ExecutionContext, eventType ExecutionEventType, data interface{}) {
-	if execCtx.EventEmitter == nil {
+	if execCtx.EventEmitter == nil && e.publisher == nil {
 		return
 	}
-	execCtx.EventEmitter.Emit(NewExecutionEvent(execCtx.Run.ID, eventType, data))
+	event := NewExecutionEvent(execCtx.Run.ID, eventType, data)
+	if execCtx.EventEmitter != nil {
+		execCtx.EventEmitter.Emit(event)
+	}
+	if e.publisher != nil {
+		e.publisher.Emit(event)
+	}
+}
+
+// progressReporter returns the sandbox callback behind ctx.progress(percent, message) for a step.
+// Reports are emitted as progress events at most every progressMinInterval, except the final
+// 100% report; they never affect the step's output.
+func (e *Executor) progressReporter(execCtx *ExecutionContext, step domain.Step) func(percent float64, message string) {
+	if execCtx == nil || execCtx.Run == nil || (execCtx.EventEmitter == nil && e.publisher == nil) {
+		return nil
+	}
+	var mu sync.Mutex
+	var last time.Time
+	return func(percent float64, message string) {
+		mu.Lock()
+		now := time.Now()
+		if percent < 100 && !last.IsZero() && now.Sub(last) < progressMinInterval {
+			mu.Unlock()
+			return
+		}
+		last = now
+		mu.Unlock()
+
+		e.emitEvent(execCtx, EventProgress, StepProgressData{
+			StepID:   step.ID.String(),
+			StepName: step.Name,
+			Percent:  percent,
+			Message:  message,
+		})
+	}
 }
 
 // Graph represents the execution graph
@@ -498,6 +821,11 @@ type Graph struct {
 }
 
 func (e *Executor) buildGraph(def *domain.ProjectDefinition) *Graph {
+	return BuildGraph(def)
+}
+
+// BuildGraph builds the execution graph of a project definition
+func BuildGraph(def *domain.ProjectDefinition) *Graph {
 	graph := &Graph{
 		Steps:         make(map[uuid.UUID]domain.Step),
 		BlockGroups:   make(map[uuid.UUID]domain.BlockGroup),
@@ -557,12 +885,9 @@ func (e *Executor) findStartNodes(graph *Graph) []uuid.UUID {
 	return startNodes
 }
 
-func (e *Executor) executeNodes(ctx context.Context, execCtx *ExecutionContext, graph *Graph, nodeIDs []uuid.UUID) error {
-	// Track completed nodes and groups
-	completed := make(map[uuid.UUID]bool)
-	completedGroups := make(map[uuid.UUID]bool)
-	var mu sync.Mutex
-
+// executeNodes executes the given steps in parallel, then the steps and groups they lead to.
+// The steps must already be claimed in tracker, which is shared by the whole execution.
+func (e *Executor) executeNodes(ctx context.Context, execCtx *ExecutionContext, graph *Graph, nodeIDs []uuid.UUID, tracker *completionTracker) error {
 	// Use a WaitGroup to wait for parallel executions
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(nodeIDs))
@@ -578,20 +903,18 @@ func (e *Executor) executeNodes(ctx context.Context, execCtx *ExecutionContext,
 				return
 			}
 
-			mu.Lock()
-			completed[id] = true
-			mu.Unlock()
+			tracker.completeStep(id)
 
 			// Check for group edges and execute groups
-			if err := e.executeNextGroups(ctx, execCtx, graph, id, completed, completedGroups, &mu); err != nil {
+			if err := e.executeNextGroups(ctx, execCtx, graph, id, tracker); err != nil {
 				errChan <- err
 				return
 			}
 
 			// Find next step nodes to execute
-			nextNodes := e.findNextNodes(ctx, execCtx, graph, id, completed, completedGroups, &mu)
+			nextNodes := e.findNextNodes(ctx, execCtx, graph, id, tracker)
 			if len(nextNodes) > 0 {
-				if err := e.executeNodes(ctx, execCtx, graph, nextNodes); err != nil {
+				if err := e.executeNodes(ctx, execCtx, graph, nextNodes, tracker); err != nil {
 					errChan <- err
 				}
 			}
@@ -612,7 +935,7 @@ func (e *Executor) executeNodes(ctx context.Context, execCtx *ExecutionContext,
 }
 
 // executeNextGroups finds and executes block groups that are next in the execution flow
-func (e *Executor) executeNextGroups(ctx context.Context, execCtx *ExecutionContext, graph *Graph, currentStepID uuid.UUID, completed, completedGroups map[uuid.UUID]bool, mu *sync.Mutex) error {
+func (e *Executor) executeNextGroups(ctx context.Context, execCtx *ExecutionContext, graph *Graph, currentStepID uuid.UUID, tracker *completionTracker) error {
 	execCtx.mu.RLock()
 	currentOutput := execCtx.StepData[currentStepID]
 	currentOutputPort := execCtx.StepOutputPorts[currentStepID]
@@ -650,28 +973,8 @@ func (e *Executor) executeNextGroups(ctx context.Context, execCtx *ExecutionCont
 			}
 		}
 
-		// Check if group already completed and all dependencies are met (single lock)
-		shouldExecute := func() bool {
-			mu.Lock()
-			defer mu.Unlock()
-
-			if completedGroups[groupID] {
-				return false
-			}
-
-			// Check if all incoming edges to this group are satisfied
-			for _, inEdge := range graph.GroupInEdges[groupID] {
-				if inEdge.SourceStepID != nil && !completed[*inEdge.SourceStepID] {
-					return false
-				}
-				if inEdge.SourceBlockGroupID != nil && !completedGroups[*inEdge.SourceBlockGroupID] {
-					return false
-				}
-			}
-			return true
-		}()
-
-		if !shouldExecute {
+		// Claim the group once all incoming edges are satisfied, so only one branch executes it
+		if !tracker.claimGroup(graph, groupID) {
 			continue
 		}
 
@@ -693,16 +996,14 @@ func (e *Executor) executeNextGroups(ctx context.Context, execCtx *ExecutionCont
 			return fmt.Errorf("block group %s execution failed: %w", group.Name, err)
 		}
 
-		mu.Lock()
-		completedGroups[groupID] = true
-		mu.Unlock()
-
 		execCtx.mu.Lock()
 		execCtx.GroupData[groupID] = groupOutput
+		execCtx.StepOutputPorts[groupID] = outputPort
 		execCtx.mu.Unlock()
+		tracker.completeGroup(groupID)
 
 		// Execute next steps/groups from this group's output
-		if err := e.executeFromGroupOutput(ctx, execCtx, graph, groupID, groupOutput, outputPort, completed, completedGroups, mu); err != nil {
+		if err := e.executeFromGroupOutput(ctx, execCtx, graph, groupID, groupOutput, outputPort, tracker); err != nil {
 			return err
 		}
 	}
@@ -730,40 +1031,37 @@ func (e *Executor) executeBlockGroup(ctx context.Context, execCtx *ExecutionCont
 		edges[i] = &graph.AllEdges[i]
 	}
 
-	// Create context for group execution
-	bgCtx := &BlockGroupContext{
-		Group:   group,
-		Steps:   groupSteps,
-		Edges:   edges,
-		Input:   input,
-		ExecCtx: execCtx,
-		Graph:   graph,
-	}
-
-	// Execute group
-	output, err := blockGroupExecutor.ExecuteGroup(ctx, bgCtx)
-	if err != nil {
-		return nil, "error", err
-	}
-
-	// Determine output port based on output content
-	// Default to "out" which matches the default output port in block definitions
-	outputPort := "out"
-	var outputMap map[string]interface{}
-	if err := json.Unmarshal(output, &outputMap); err == nil {
-		if port, ok := outputMap["__port"].(string); ok {
-			outputPort = port
+	// Execute group, retrying it as a whole when its error_handling config asks for it
+	output, attempts, err := e.executeGroupWithRetry(ctx, blockGroupExecutor, group, func() *BlockGroupContext {
+		return &BlockGroupContext{
+			Group:   group,
+			Steps:   groupSteps,
+			Edges:   edges,
+			Input:   input,
+			ExecCtx: execCtx,
+			Graph:   graph,
 		}
-		if isError, ok := outputMap["__error"].(bool); ok && isError {
-			outputPort = "error"
+	})
+	if err != nil {
+		// Route the failure to the group's error port when one is connected
+		if ctx.Err() == nil {
+			if errorOutput, ok := e.groupErrorOutput(graph, group, input, attempts, err); ok {
+				e.logger.Info("Block group error routed to error port",
+					"run_id", execCtx.Run.ID,
+					"group_id", group.ID,
+					"error", err.Error(),
+				)
+				return errorOutput, "error", nil
+			}
 		}
+		return nil, "error", err
 	}
 
-	return output, outputPort, nil
+	return output, groupOutputPort(output), nil
 }
 
 // executeFromGroupOutput handles execution after a group completes
-func (e *Executor) executeFromGroupOutput(ctx context.Context, execCtx *ExecutionContext, graph *Graph, groupID uuid.UUID, output json.RawMessage, outputPort string, completed, completedGroups map[uuid.UUID]bool, mu *sync.Mutex) error {
+func (e *Executor) executeFromGroupOutput(ctx context.Context, execCtx *ExecutionContext, graph *Graph, groupID uuid.UUID, output json.RawMessage, outputPort string, tracker *completionTracker) error {
 	// Find edges from this group and execute next nodes
 	for _, edge := range graph.GroupOutEdges[groupID] {
 		// Check if this edge's source port matches the output port
@@ -771,16 +1069,19 @@ func (e *Executor) executeFromGroupOutput(ctx context.Context, execCtx *Executio
 			if edge.SourcePort != outputPort {
 				continue // Skip edges that don't match the output port
 			}
+		} else if outputPort != "out" {
+			// No port specified - only follow the default "out" port, never "error" or custom ports
+			continue
 		}
 
-		// Execute next step
-		if edge.TargetStepID != nil {
+		// Execute next step, unless another branch already reached it
+		if edge.TargetStepID != nil && tracker.claim(*edge.TargetStepID) {
 			// Store group output for the next step's input preparation
 			execCtx.mu.Lock()
 			execCtx.StepData[groupID] = output
 			execCtx.mu.Unlock()
 
-			if err := e.executeNodes(ctx, execCtx, graph, []uuid.UUID{*edge.TargetStepID}); err != nil {
+			if err := e.executeNodes(ctx, execCtx, graph, []uuid.UUID{*edge.TargetStepID}, tracker); err != nil {
 				return err
 			}
 		}
@@ -788,11 +1089,7 @@ func (e *Executor) executeFromGroupOutput(ctx context.Context, execCtx *Executio
 		// Execute next group
 		if edge.TargetBlockGroupID != nil {
 			nextGroupID := *edge.TargetBlockGroupID
-			mu.Lock()
-			alreadyCompleted := completedGroups[nextGroupID]
-			mu.Unlock()
-
-			if !alreadyCompleted {
+			if tracker.claim(nextGroupID) {
 				nextGroup := graph.BlockGroups[nextGroupID]
 				e.logger.Info("Executing next block group from group output",
 					"from_group_id", groupID,
@@ -805,16 +1102,14 @@ func (e *Executor) executeFromGroupOutput(ctx context.Context, execCtx *Executio
 					return err
 				}
 
-				mu.Lock()
-				completedGroups[nextGroupID] = true
-				mu.Unlock()
-
 				execCtx.mu.Lock()
 				execCtx.GroupData[nextGroupID] = groupOutput
+				execCtx.StepOutputPorts[nextGroupID] = nextOutputPort
 				execCtx.mu.Unlock()
+				tracker.completeGroup(nextGroupID)
 
 				// Recursively handle output from this group
-				if err := e.executeFromGroupOutput(ctx, execCtx, graph, nextGroupID, groupOutput, nextOutputPort, completed, completedGroups, mu); err != nil {
+				if err := e.executeFromGroupOutput(ctx, execCtx, graph, nextGroupID, groupOutput, nextOutputPort, tracker); err != nil {
 					return err
 				}
 			}
@@ -824,7 +1119,9 @@ func (e *Executor) executeFromGroupOutput(ctx context.Context, execCtx *Executio
 	return nil
 }
 
-func (e *Executor) findNextNodes(ctx context.Context, execCtx *ExecutionContext, graph *Graph, currentID uuid.UUID, completed, completedGroups map[uuid.UUID]bool, mu *sync.Mutex) []uuid.UUID {
+// findNextNodes returns the steps downstream of currentID whose edges match its output port and
+// conditions, claimed in tracker so that no other branch schedules them again
+func (e *Executor) findNextNodes(ctx context.Context, execCtx *ExecutionContext, graph *Graph, currentID uuid.UUID, tracker *completionTracker) []uuid.UUID {
 	ctx, span := tracer.Start(ctx, "workflow.find_next_nodes",
 		trace.WithAttributes(
 			attribute.String("current_step_id", currentID.String()),
@@ -903,30 +1200,10 @@ func (e *Executor) findNextNodes(ctx context.Context, execCtx *ExecutionContext,
 		}
 
 		// Check if ANY incoming edge's source is completed (OR semantics for multiple inputs)
-		// This allows loop structures and alternative paths to work correctly
+		// This allows alternative paths to work correctly. The target is claimed in the same
+		// critical section, so a step reached by several branches at once runs only once.
 		// Note: Implicit parallel execution is not allowed - use Parallel block explicitly
-		canExecute := func() bool {
-			mu.Lock()
-			defer mu.Unlock()
-
-			// If already completed, don't execute again
-			if completed[targetID] {
-				return false
-			}
-
-			// Check if at least one incoming edge's source is completed
-			for _, inEdge := range graph.InEdges[targetID] {
-				if inEdge.SourceStepID != nil && completed[*inEdge.SourceStepID] {
-					return true
-				}
-				if inEdge.SourceBlockGroupID != nil && completedGroups[*inEdge.SourceBlockGroupID] {
-					return true
-				}
-			}
-			return false
-		}()
-
-		if canExecute {
+		if tracker.claimStep(graph, targetID) {
 			nextNodes = append(nextNodes, targetID)
 		}
 	}
@@ -971,11 +1248,39 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 		stepRun.Fail(fmt.Sprintf("failed to prepare step input: %v", err))
 		return fmt.Errorf("failed to prepare step input: %w", err)
 	}
+	if err := e.inputLimits.Validate("step input", input); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		stepRun.Fail(err.Error())
+		return fmt.Errorf("step %s: %w", step.Name, err)
+	}
+
+	// Gate the step on its run_if expression; a skipped step passes its input through
+	if runIf := getConfigString(step.Config, "run_if"); runIf != "" {
+		shouldRun, err := e.evaluator.Evaluate(runIf, input)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			stepRun.Start(execCtx.redactSecrets(input))
+			stepRun.Fail(fmt.Sprintf("run_if evaluation failed: %v", err))
+			return fmt.Errorf("run_if evaluation failed: %w", err)
+		}
+		if !shouldRun {
+			e.skipStep(execCtx, step, stepRun, input, runIf)
+			span.SetStatus(codes.Ok, "step skipped by run_if")
+			return nil
+		}
+	}
 
 	// Get step config as map for scripts
 	var stepConfigMap map[string]interface{}
 	if step.Config != nil {
-		json.Unmarshal(step.Config, &stepConfigMap)
+		if err := json.Unmarshal(step.Config, &stepConfigMap); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			stepRun.Fail(fmt.Sprintf("invalid step config: %v", err))
+			return fmt.Errorf("invalid step config: %w", err)
+		}
 	}
 
 	// Run prescript if enabled
@@ -991,14 +1296,14 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 		}
 	}
 
-	stepRun.Start(input)
+	stepRun.Start(execCtx.redactSecrets(input))
 
 	// Emit step started event
 	e.emitEvent(execCtx, EventStepStarted, StepStartedData{
 		StepID:   step.ID.String(),
 		StepName: step.Name,
 		StepType: string(step.Type),
-		Input:    input,
+		Input:    execCtx.redactSecrets(input),
 	})
 	stepStartTime := time.Now()
 
@@ -1068,7 +1373,7 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 					"error", err.Error(),
 				)
 				output = []byte(`{"skipped": true}`)
-				stepRun.Complete(output)
+				stepRun.Complete(execCtx.redactSecrets(output))
 				execCtx.mu.Lock()
 				execCtx.StepData[step.ID] = output
 				execCtx.StepOutputPorts[step.ID] = "output"
@@ -1083,11 +1388,16 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 					"error", err.Error(),
 				)
 				if ehConfig.FallbackValue != nil {
-					output, _ = json.Marshal(ehConfig.FallbackValue)
+					fallback, marshalErr := json.Marshal(ehConfig.FallbackValue)
+					if marshalErr != nil {
+						stepRun.Fail(fmt.Sprintf("failed to marshal fallback value: %v", marshalErr))
+						return fmt.Errorf("failed to marshal fallback value: %w", marshalErr)
+					}
+					output = fallback
 				} else {
 					output = []byte(`{}`)
 				}
-				stepRun.Complete(output)
+				stepRun.Complete(execCtx.redactSecrets(output))
 				execCtx.mu.Lock()
 				execCtx.StepData[step.ID] = output
 				execCtx.StepOutputPorts[step.ID] = "output"
@@ -1102,17 +1412,23 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 		enableErrorPort := getConfigBool(step.Config, "enable_error_port")
 		if enableErrorPort && e.hasEdgeFromPort(graph, step.ID, "error") {
 			// Route to error port instead of failing
+			// An input that is not valid JSON is passed on as a string rather than dropped
 			errorOutput := map[string]interface{}{
 				"error": map[string]interface{}{
 					"message": err.Error(),
 					"type":    "execution_error",
 				},
-				"input": json.RawMessage(input),
+				"input": runOutputValue(input),
 			}
-			output, _ = json.Marshal(errorOutput)
+			errorOutputJSON, marshalErr := json.Marshal(errorOutput)
+			if marshalErr != nil {
+				stepRun.Fail(fmt.Sprintf("failed to marshal error output: %v", marshalErr))
+				return fmt.Errorf("failed to marshal error output: %w", marshalErr)
+			}
+			output = errorOutputJSON
 			outputPort = "error"
 
-			stepRun.Complete(output)
+			stepRun.Complete(execCtx.redactSecrets(output))
 			e.logger.Info("Step error routed to error port",
 				"run_id", execCtx.Run.ID,
 				"step_id", step.ID,
@@ -1156,7 +1472,7 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 			}
 		}
 
-		stepRun.Complete(output)
+		stepRun.Complete(execCtx.redactSecrets(output))
 	}
 
 	execCtx.mu.Lock()
@@ -1164,6 +1480,12 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 	execCtx.StepOutputPorts[step.ID] = outputPort
 	execCtx.mu.Unlock()
 
+	// Checkpoints are only taken on the main flow; steps inside block groups are
+	// re-run together with their group
+	if outputPort != "error" && step.BlockGroupID == nil && getConfigBool(step.Config, "checkpoint") {
+		e.saveCheckpoint(ctx, execCtx, step, outputPort)
+	}
+
 	if stepRun.DurationMs != nil {
 		span.SetAttributes(attribute.Int64("duration_ms", int64(*stepRun.DurationMs)))
 	}
@@ -1173,7 +1495,7 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 	e.emitEvent(execCtx, EventStepCompleted, StepCompletedData{
 		StepID:   step.ID.String(),
 		StepName: step.Name,
-		Output:   output,
+		Output:   execCtx.redactSecrets(output),
 		Duration: time.Since(stepStartTime).Milliseconds(),
 	})
 
@@ -1187,6 +1509,39 @@ func (e *Executor) executeNode(ctx context.Context, execCtx *ExecutionContext, g
 	return nil
 }
 
+// executeStepWithInput runs a step outside the normal DAG traversal and returns its output.
+// A non-empty input replaces the input the step would otherwise resolve from its incoming edges.
+func (e *Executor) executeStepWithInput(ctx context.Context, execCtx *ExecutionContext, graph *Graph, step *domain.Step, input json.RawMessage) (json.RawMessage, error) {
+	// Set tool input override so prepareStepInput uses the provided input
+	// instead of resolving input from DAG edges (which don't exist for tool steps)
+	if len(input) > 0 {
+		execCtx.mu.Lock()
+		if execCtx.ToolInputOverride == nil {
+			execCtx.ToolInputOverride = make(map[uuid.UUID]json.RawMessage)
+		}
+		execCtx.ToolInputOverride[step.ID] = input
+		execCtx.mu.Unlock()
+
+		// Clean up the override after execution
+		defer func() {
+			execCtx.mu.Lock()
+			delete(execCtx.ToolInputOverride, step.ID)
+			execCtx.mu.Unlock()
+		}()
+	}
+
+	if err := e.executeNode(ctx, execCtx, graph, step.ID); err != nil {
+		return nil, err
+	}
+
+	// Get output from execution context
+	execCtx.mu.RLock()
+	output := execCtx.StepData[step.ID]
+	execCtx.mu.RUnlock()
+
+	return output, nil
+}
+
 // getConfigBool extracts a boolean value from step config
 func getConfigBool(config json.RawMessage, key string) bool {
 	if config == nil {
@@ -1202,6 +1557,46 @@ func getConfigBool(config json.RawMessage, key string) bool {
 	return false
 }
 
+// skipStep records a step whose run_if evaluated to false.
+// Its input becomes its output on the default port, so downstream steps still run.
+func (e *Executor) skipStep(execCtx *ExecutionContext, step domain.Step, stepRun *domain.StepRun, input json.RawMessage, runIf string) {
+	stepRun.Start(execCtx.redactSecrets(input))
+	stepRun.Output = stepRun.Input
+	stepRun.Skip()
+
+	execCtx.mu.Lock()
+	execCtx.StepData[step.ID] = input
+	execCtx.StepOutputPorts[step.ID] = "output"
+	execCtx.mu.Unlock()
+
+	e.emitEvent(execCtx, EventStepSkipped, StepSkippedData{
+		StepID:   step.ID.String(),
+		StepName: step.Name,
+		RunIf:    runIf,
+	})
+
+	e.logger.Info("Step skipped (run_if is false)",
+		"run_id", execCtx.Run.ID,
+		"step_id", step.ID,
+		"run_if", runIf,
+	)
+}
+
+// getConfigString extracts a string value from step config
+func getConfigString(config json.RawMessage, key string) string {
+	if config == nil {
+		return ""
+	}
+	var configMap map[string]interface{}
+	if err := json.Unmarshal(config, &configMap); err != nil {
+		return ""
+	}
+	if val, ok := configMap[key].(string); ok {
+		return val
+	}
+	return ""
+}
+
 // getConfigStringArray extracts a string array from step config
 func getConfigStringArray(config json.RawMessage, key string) []string {
 	if config == nil {
@@ -1359,7 +1754,12 @@ func (e *Executor) extractOutputPortAndData(step domain.Step, output json.RawMes
 	// Check if this is a code/function block with custom output ports
 	customPorts := getConfigStringArray(step.Config, "custom_output_ports")
 	if len(customPorts) == 0 {
-		// Check for __port in output (used by condition/switch blocks)
+		// Condition and switch blocks pass their input through to the selected port
+		if port, data, ok := parseBranchOutput(output); ok {
+			return port, data
+		}
+
+		// Check for __port in output
 		var outputMap map[string]interface{}
 		if err := json.Unmarshal(output, &outputMap); err == nil {
 			if port, ok := outputMap["__port"].(string); ok {
@@ -1576,12 +1976,19 @@ func (e *Executor) executeToolStep(ctx context.Context, execCtx *ExecutionContex
 func (e *Executor) executeLLMStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, stepRun *domain.StepRun, input json.RawMessage) (json.RawMessage, error) {
 	// Parse step config to determine which LLM provider to use
 	var config struct {
-		Provider          string   `json:"provider"`           // openai, anthropic, etc.
-		PassthroughFields []string `json:"passthrough_fields"` // Fields from input to include in output
+		Provider          string      `json:"provider"`             // openai, anthropic, etc.
+		ModelTiers        []modelTier `json:"model_tiers"`          // Models chosen by estimated input size
+		OutputParser      string      `json:"output_parser"`        // none, json or markdown_code
+		RetryOnParseError bool        `json:"retry_on_parse_error"` // Retry once when the json parser fails
+
+		llmPassthroughConfig // passthrough_fields, preserve_input and merge_input
 	}
 	if err := json.Unmarshal(step.Config, &config); err != nil {
 		return nil, fmt.Errorf("invalid LLM step config: %w", err)
 	}
+	if !validOutputParser(config.OutputParser) {
+		return nil, fmt.Errorf("unknown output_parser %q (expected none, json or markdown_code)", config.OutputParser)
+	}
 
 	// Determine adapter ID (default to OpenAI)
 	adapterID := config.Provider
@@ -1609,11 +2016,84 @@ func (e *Executor) executeLLMStep(ctx context.Context, execCtx *ExecutionContext
 		return nil, fmt.Errorf("failed to expand config templates: %w", err)
 	}
 
+	// Pick the model by the size of the expanded prompts
+	tier, tierModel := -1, ""
+	if len(config.ModelTiers) > 0 {
+		var inputTokens int
+		expandedConfig, inputTokens, tier, err = applyModelTiers(expandedConfig, config.ModelTiers)
+		if err != nil {
+			return nil, err
+		}
+		if tier >= 0 {
+			tierModel = config.ModelTiers[tier].Model
+			e.logger.Info("Selected model tier",
+				"step_id", step.ID,
+				"tier", tier,
+				"model", tierModel,
+				"estimated_input_tokens", inputTokens,
+			)
+		}
+	}
+
+	// Cap max_tokens to the model's maximum output and the tenant (or deployment) ceiling
+	expandedConfig, clamp, err := clampMaxTokens(expandedConfig, adapterID, e.maxTokensCeiling(ctx, execCtx))
+	if err != nil {
+		return nil, err
+	}
+	if clamp != nil {
+		e.logger.Info("Clamped max_tokens",
+			"step_id", step.ID,
+			"requested", clamp.Requested,
+			"applied", clamp.Applied,
+			"limit", clamp.Limit,
+		)
+	}
+
 	// Execute adapter
+	resp, err := e.callLLMAdapter(ctx, execCtx, stepRun, adp, input, expandedConfig, tier, tierModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response content, retrying once with a stricter instruction when it holds no JSON
+	output, err := parseLLMOutput(config.OutputParser, resp.Output)
+	if errors.Is(err, ErrNoJSONInOutput) && config.RetryOnParseError {
+		e.logger.Warn("LLM output is not valid JSON, retrying with a stricter instruction",
+			"step_id", step.ID,
+		)
+		strictConfig, cfgErr := withStrictJSONInstruction(expandedConfig)
+		if cfgErr != nil {
+			return nil, fmt.Errorf("failed to build retry config: %w", cfgErr)
+		}
+		resp, err = e.callLLMAdapter(ctx, execCtx, stepRun, adp, input, strictConfig, tier, tierModel)
+		if err != nil {
+			return nil, err
+		}
+		output, err = parseLLMOutput(config.OutputParser, resp.Output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("output_parser %s: %w", config.OutputParser, err)
+	}
+
+	return mergeLLMInput(config.llmPassthroughConfig, input, output), nil
+}
+
+// callLLMAdapter executes an LLM adapter with an expanded config and records its usage
+func (e *Executor) callLLMAdapter(ctx context.Context, execCtx *ExecutionContext, stepRun *domain.StepRun, adp adapter.Adapter, input, expandedConfig json.RawMessage, tier int, tierModel string) (*adapter.Response, error) {
 	resp, err := adp.Execute(ctx, &adapter.Request{
 		Input:  input,
 		Config: expandedConfig,
 	})
+	if resp != nil && tierModel != "" {
+		// Usage is recorded against the chosen model even when the adapter does not report it
+		if resp.Metadata == nil {
+			resp.Metadata = make(map[string]string)
+		}
+		if resp.Metadata["model"] == "" {
+			resp.Metadata["model"] = tierModel
+		}
+		resp.Metadata["model_tier"] = strconv.Itoa(tier)
+	}
 
 	// Record usage regardless of success/failure
 	if e.usageRecorder != nil && resp != nil {
@@ -1640,29 +2120,12 @@ func (e *Executor) executeLLMStep(ctx context.Context, execCtx *ExecutionContext
 	if err != nil {
 		return nil, err
 	}
-
-	// If passthrough_fields are specified, merge them from input to output
-	if len(config.PassthroughFields) > 0 {
-		var inputData map[string]interface{}
-		if err := json.Unmarshal(input, &inputData); err == nil {
-			var outputData map[string]interface{}
-			if err := json.Unmarshal(resp.Output, &outputData); err == nil {
-				for _, field := range config.PassthroughFields {
-					if val, exists := inputData[field]; exists {
-						outputData[field] = val
-					}
-				}
-				if merged, err := json.Marshal(outputData); err == nil {
-					return merged, nil
-				}
-			}
-		}
-	}
-
-	return resp.Output, nil
+	return resp, nil
 }
 
-func (e *Executor) executeConditionStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
+// executeConditionStep evaluates the condition and passes the input through unchanged to the
+// "true" or "false" port. The evaluation details are recorded on the step run.
+func (e *Executor) executeConditionStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, stepRun *domain.StepRun, input json.RawMessage) (json.RawMessage, error) {
 	// Parse condition config
 	var config struct {
 		Expression string `json:"expression"`
@@ -1682,29 +2145,23 @@ func (e *Executor) executeConditionStep(ctx context.Context, execCtx *ExecutionC
 		condResult = true
 	}
 
-	// Set __port field for routing based on condition result
+	// Route to the port matching the condition result
 	portValue := "false"
 	if condResult {
 		portValue = "true"
 	}
 
-	// Parse input to passthrough all existing fields
-	var inputData map[string]interface{}
-	if err := json.Unmarshal(input, &inputData); err != nil {
-		// If input is not an object, create a new map
-		inputData = make(map[string]interface{})
+	evaluation := map[string]interface{}{
+		"expression": config.Expression,
+		"result":     condResult,
+		"port":       portValue,
 	}
-
-	// Add condition result fields to the passthrough data
-	inputData["__condition_result"] = condResult
-	inputData["__condition_expression"] = config.Expression
-	inputData["__port"] = portValue
-
-	// Include evaluation error in result for debugging
+	// Include evaluation error for debugging
 	if evalErr != nil {
-		inputData["__condition_evaluation_error"] = evalErr.Error()
-		inputData["__condition_defaulted"] = true
+		evaluation["evaluation_error"] = evalErr.Error()
+		evaluation["defaulted"] = true
 	}
+	e.recordEvaluation(stepRun, evaluation)
 
 	e.logger.Info("Condition step evaluated",
 		"step_id", step.ID,
@@ -1712,17 +2169,22 @@ func (e *Executor) executeConditionStep(ctx context.Context, execCtx *ExecutionC
 		"result", condResult,
 	)
 
-	return json.Marshal(inputData)
+	return branchOutput(portValue, input)
+}
+
+// mapStepConfig is the config of a map step
+type mapStepConfig struct {
+	InputPath  string `json:"input_path"`  // JSON path to array (e.g., "$.items")
+	AdapterID  string `json:"adapter_id"`  // Optional: adapter to apply to each item
+	Parallel   bool   `json:"parallel"`    // Execute in parallel
+	MaxWorkers int    `json:"max_workers"` // Max parallel workers
+	Stream     bool   `json:"stream"`      // Hand each item to the "item" port steps as soon as it completes
+	Ordered    bool   `json:"ordered"`     // Stream items in input order instead of completion order
 }
 
 func (e *Executor) executeMapStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
 	// Parse map config
-	var config struct {
-		InputPath  string `json:"input_path"`  // JSON path to array (e.g., "$.items")
-		AdapterID  string `json:"adapter_id"`  // Optional: adapter to apply to each item
-		Parallel   bool   `json:"parallel"`    // Execute in parallel
-		MaxWorkers int    `json:"max_workers"` // Max parallel workers
-	}
+	var config mapStepConfig
 	if err := json.Unmarshal(step.Config, &config); err != nil {
 		return nil, fmt.Errorf("invalid map config: %w", err)
 	}
@@ -1757,8 +2219,13 @@ func (e *Executor) executeMapStep(ctx context.Context, execCtx *ExecutionContext
 		"step_id", step.ID,
 		"item_count", len(items),
 		"parallel", config.Parallel,
+		"stream", config.Stream,
 	)
 
+	if config.Stream {
+		return e.executeMapStream(ctx, execCtx, step, config, items)
+	}
+
 	// If no adapter specified, just pass through items
 	if config.AdapterID == "" {
 		result := map[string]interface{}{
@@ -1779,7 +2246,10 @@ func (e *Executor) executeMapStep(ctx context.Context, execCtx *ExecutionContext
 	results := make([]interface{}, len(items))
 	errors := make([]error, len(items))
 
-	if config.Parallel {
+	if batch, ok := adp.(adapter.BatchAdapter); ok {
+		// Batch execution: one provider call for all items
+		e.executeMapBatch(ctx, execCtx, step, batch, items, results, errors)
+	} else if config.Parallel {
 		// Parallel execution
 		maxWorkers := config.MaxWorkers
 		if maxWorkers <= 0 {
@@ -1796,32 +2266,16 @@ func (e *Executor) executeMapStep(ctx context.Context, execCtx *ExecutionContext
 				sem <- struct{}{}        // Acquire
 				defer func() { <-sem }() // Release
 
-				itemJSON, err := json.Marshal(itm)
-				if err != nil {
-					e.logger.Warn("Failed to marshal map item", "index", idx, "error", err)
-					errors[idx] = err
-					return
-				}
-				// Expand template variables in config for each item
-				expandedConfig, err := ExpandConfigTemplatesWithScopes(step.Config, itemJSON, execCtx.ScopedVars)
-				if err != nil {
-					e.logger.Warn("Failed to expand config templates", "index", idx, "error", err)
-					errors[idx] = err
-					return
-				}
-				resp, err := adp.Execute(ctx, &adapter.Request{
-					Input:  itemJSON,
-					Config: expandedConfig,
-				})
+				resp, err := e.executeMapItem(ctx, execCtx, step, adp, idx, itm)
 				if err != nil {
 					errors[idx] = err
 					return
 				}
 
 				var output interface{}
-				if err := json.Unmarshal(resp.Output, &output); err != nil {
+				if err := json.Unmarshal(resp, &output); err != nil {
 					e.logger.Warn("Failed to unmarshal map item output", "index", idx, "error", err)
-					output = string(resp.Output)
+					output = string(resp)
 				}
 				results[idx] = output
 			}(i, item)
@@ -1830,32 +2284,16 @@ func (e *Executor) executeMapStep(ctx context.Context, execCtx *ExecutionContext
 	} else {
 		// Sequential execution
 		for i, item := range items {
-			itemJSON, err := json.Marshal(item)
-			if err != nil {
-				e.logger.Warn("Failed to marshal map item", "index", i, "error", err)
-				errors[i] = err
-				continue
-			}
-			// Expand template variables in config for each item
-			expandedConfig, err := ExpandConfigTemplatesWithScopes(step.Config, itemJSON, execCtx.ScopedVars)
-			if err != nil {
-				e.logger.Warn("Failed to expand config templates", "index", i, "error", err)
-				errors[i] = err
-				continue
-			}
-			resp, err := adp.Execute(ctx, &adapter.Request{
-				Input:  itemJSON,
-				Config: expandedConfig,
-			})
+			resp, err := e.executeMapItem(ctx, execCtx, step, adp, i, item)
 			if err != nil {
 				errors[i] = err
 				continue
 			}
 
 			var output interface{}
-			if err := json.Unmarshal(resp.Output, &output); err != nil {
+			if err := json.Unmarshal(resp, &output); err != nil {
 				e.logger.Warn("Failed to unmarshal map item output", "index", i, "error", err)
-				output = string(resp.Output)
+				output = string(resp)
 			}
 			results[i] = output
 		}
@@ -1894,6 +2332,88 @@ func (e *Executor) executeMapStep(ctx context.Context, execCtx *ExecutionContext
 	return json.Marshal(result)
 }
 
+// executeMapItem applies the map step's adapter to a single item, expanding the step config
+// templates against the item first
+func (e *Executor) executeMapItem(ctx context.Context, execCtx *ExecutionContext, step domain.Step, adp adapter.Adapter, index int, item interface{}) (json.RawMessage, error) {
+	itemJSON, err := json.Marshal(item)
+	if err != nil {
+		e.logger.Warn("Failed to marshal map item", "index", index, "error", err)
+		return nil, err
+	}
+	// Expand template variables in config for each item
+	expandedConfig, err := ExpandConfigTemplatesWithScopes(step.Config, itemJSON, execCtx.ScopedVars)
+	if err != nil {
+		e.logger.Warn("Failed to expand config templates", "index", index, "error", err)
+		return nil, err
+	}
+	resp, err := adp.Execute(ctx, &adapter.Request{
+		Input:  itemJSON,
+		Config: expandedConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Output, nil
+}
+
+// executeMapBatch runs the map items through a batch adapter in a single ExecuteBatch call,
+// filling results and errors by item index. Items whose request cannot be built fail on their
+// own; a batch error fails every item in the batch.
+func (e *Executor) executeMapBatch(ctx context.Context, execCtx *ExecutionContext, step domain.Step, batch adapter.BatchAdapter, items []interface{}, results []interface{}, errors []error) {
+	reqs := make([]*adapter.Request, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		itemJSON, err := json.Marshal(item)
+		if err != nil {
+			e.logger.Warn("Failed to marshal map item", "index", i, "error", err)
+			errors[i] = err
+			continue
+		}
+		// Expand template variables in config for each item
+		expandedConfig, err := ExpandConfigTemplatesWithScopes(step.Config, itemJSON, execCtx.ScopedVars)
+		if err != nil {
+			e.logger.Warn("Failed to expand config templates", "index", i, "error", err)
+			errors[i] = err
+			continue
+		}
+		reqs = append(reqs, &adapter.Request{
+			Input:  itemJSON,
+			Config: expandedConfig,
+		})
+		indexes = append(indexes, i)
+	}
+	if len(reqs) == 0 {
+		return
+	}
+
+	e.logger.Info("Executing map step as batch",
+		"step_id", step.ID,
+		"adapter_id", batch.ID(),
+		"batch_size", len(reqs),
+	)
+
+	resps, err := batch.ExecuteBatch(ctx, reqs)
+	if err == nil && len(resps) != len(reqs) {
+		err = fmt.Errorf("batch adapter %s returned %d responses for %d requests", batch.ID(), len(resps), len(reqs))
+	}
+	for n, idx := range indexes {
+		if err != nil {
+			errors[idx] = err
+			continue
+		}
+		if resps[n] == nil {
+			errors[idx] = fmt.Errorf("batch adapter %s returned no response for item %d", batch.ID(), idx)
+			continue
+		}
+		var output interface{}
+		if err := json.Unmarshal(resps[n].Output, &output); err != nil {
+			e.logger.Warn("Failed to unmarshal map item output", "index", idx, "error", err)
+			output = string(resps[n].Output)
+		}
+		results[idx] = output
+	}
+}
+
 func (e *Executor) executeWaitStep(ctx context.Context, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
 	// Parse wait config
 	var config domain.WaitStepConfig
@@ -1971,14 +2491,28 @@ func (e *Executor) executeFunctionStep(ctx context.Context, execCtx *ExecutionCo
 
 	// Create sandbox execution context with HTTP client and logger
 	sandboxCtx := &sandbox.ExecutionContext{
-		HTTP: sandbox.NewHTTPClient(30 * time.Second),
+		HTTP: newSandboxHTTPClient(ctx),
 		Logger: func(args ...interface{}) {
 			e.logger.Info("Script log", "step_id", step.ID, "message", fmt.Sprint(args...))
 		},
 	}
+	sandboxCtx.Progress = e.progressReporter(execCtx, step)
 
 	// Initialize Search service for web search (used by Copilot)
-	sandboxCtx.Search = sandbox.NewSearchService()
+	sandboxCtx.Search = e.newSearchService(ctx, execCtx)
+
+	if execCtx != nil && execCtx.Run != nil {
+		sandboxCtx.Trigger = execCtx.Run.TriggerInfo()
+	}
+
+	// Load the tenant library functions the code requires
+	if execCtx != nil && execCtx.Run != nil {
+		libraries, err := e.loadLibraries(ctx, execCtx.Run.TenantID, config.Code)
+		if err != nil {
+			return nil, err
+		}
+		sandboxCtx.Libraries = libraries
+	}
 
 	// Add sandbox services if database pool is available (for Copilot/meta-project features)
 	if e.pool != nil && execCtx != nil && execCtx.Run != nil {
@@ -2040,6 +2574,14 @@ func (e *Executor) executeFunctionStep(ctx context.Context, execCtx *ExecutionCo
 	return output, nil
 }
 
+// routerResponseSchemaName names the structured output a router step asks its LLM for
+const routerResponseSchemaName = "route_selection"
+
+// executeRouterStep asks an LLM which route the input should take. Providers with structured
+// output are constrained to a schema whose only value is an enum of the route names, so the
+// selection is always a defined route. Other providers answer in text, which must equal a route
+// name; the output is then flagged as a fallback. A failed call or an answer that is not a route
+// fails the step instead of silently taking the first route.
 func (e *Executor) executeRouterStep(ctx context.Context, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
 	// Parse router config
 	var config domain.RouterStepConfig
@@ -2057,8 +2599,13 @@ func (e *Executor) executeRouterStep(ctx context.Context, step domain.Step, inpu
 	}
 
 	// Build route descriptions for the LLM
+	routeNames := make([]string, len(config.Routes))
 	routeDescriptions := make([]string, len(config.Routes))
 	for i, route := range config.Routes {
+		if route.Name == "" {
+			return nil, fmt.Errorf("router route %d has no name", i+1)
+		}
+		routeNames[i] = route.Name
 		routeDescriptions[i] = fmt.Sprintf("%d. %s: %s", i+1, route.Name, route.Description)
 	}
 
@@ -2077,18 +2624,9 @@ func (e *Executor) executeRouterStep(ctx context.Context, step domain.Step, inpu
 	// Get LLM adapter
 	adp, ok := e.registry.Get(provider)
 	if !ok {
-		// Fallback: just return first route
-		e.logger.Warn("Router LLM adapter not found, using first route",
-			"step_id", step.ID,
-			"provider", provider,
-		)
-		output := map[string]interface{}{
-			"selected_route": config.Routes[0].Name,
-			"confidence":     0.0,
-			"fallback":       true,
-		}
-		return json.Marshal(output)
+		return nil, fmt.Errorf("router LLM adapter not found: %s", provider)
 	}
+	structured := adapter.SupportsStructuredOutput(adp)
 
 	// Build LLM request
 	llmConfig := map[string]interface{}{
@@ -2098,6 +2636,9 @@ func (e *Executor) executeRouterStep(ctx context.Context, step domain.Step, inpu
 			{"role": "user", "content": string(input)},
 		},
 	}
+	if structured {
+		llmConfig["response_schema"] = routerResponseSchema(routeNames)
+	}
 	llmConfigJSON, err := json.Marshal(llmConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal LLM config: %w", err)
@@ -2108,46 +2649,89 @@ func (e *Executor) executeRouterStep(ctx context.Context, step domain.Step, inpu
 		Config: llmConfigJSON,
 	})
 	if err != nil {
-		// Fallback on error
-		e.logger.Warn("Router LLM call failed, using first route",
-			"step_id", step.ID,
-			"error", err,
-		)
-		output := map[string]interface{}{
-			"selected_route": config.Routes[0].Name,
-			"confidence":     0.0,
-			"error":          err.Error(),
-		}
-		return json.Marshal(output)
+		return nil, fmt.Errorf("router LLM call failed: %w", err)
 	}
 
-	// Parse LLM response to determine route
 	var llmOutput map[string]interface{}
 	if err := json.Unmarshal(resp.Output, &llmOutput); err != nil {
-		e.logger.Warn("Failed to parse LLM router response", "error", err)
-		llmOutput = map[string]interface{}{}
+		return nil, fmt.Errorf("failed to parse router LLM response: %w", err)
 	}
+	content, _ := llmOutput["content"].(string)
 
-	// Try to extract route from response
-	selectedRoute := config.Routes[0].Name
-	if content, ok := llmOutput["content"].(string); ok {
-		// Check if response matches any route name
-		for _, route := range config.Routes {
-			if stringContains(content, route.Name) {
-				selectedRoute = route.Name
-				break
-			}
-		}
+	var selectedRoute string
+	if structured {
+		selectedRoute, ok = parseStructuredRoute(content, routeNames)
+	} else {
+		e.logger.Warn("Router provider does not support structured output, matching the response text",
+			"step_id", step.ID,
+			"provider", provider,
+		)
+		selectedRoute, ok = matchRouteName(content, routeNames)
+	}
+	if !ok {
+		return nil, fmt.Errorf("router LLM response does not select a defined route (routes: %s): %q",
+			stringJoin(routeNames, ", "), truncateString(content, 200))
 	}
 
 	output := map[string]interface{}{
 		"selected_route": selectedRoute,
+		"structured":     structured,
 		"llm_response":   llmOutput,
+		"__port":         selectedRoute,
+	}
+	if !structured {
+		output["fallback"] = true
+		output["fallback_reason"] = fmt.Sprintf("provider %s does not support structured output; the route was matched from the response text", provider)
 	}
 
 	return json.Marshal(output)
 }
 
+// routerResponseSchema returns the structured output schema allowing exactly the route names
+func routerResponseSchema(routeNames []string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        routerResponseSchemaName,
+		"description": "The route the input should take",
+		"schema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"route": map[string]interface{}{"type": "string", "enum": routeNames},
+			},
+			"required":             []string{"route"},
+			"additionalProperties": false,
+		},
+	}
+}
+
+// parseStructuredRoute returns the route of a structured router response. The provider enforces
+// the enum, but the route is still checked against the definitions.
+func parseStructuredRoute(content string, routeNames []string) (string, bool) {
+	var selection struct {
+		Route string `json:"route"`
+	}
+	if err := json.Unmarshal([]byte(content), &selection); err != nil {
+		return "", false
+	}
+	for _, name := range routeNames {
+		if selection.Route == name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// matchRouteName returns the route whose name a text response equals, ignoring case,
+// surrounding whitespace, quotes and a trailing period
+func matchRouteName(content string, routeNames []string) (string, bool) {
+	answer := strings.Trim(strings.TrimSpace(content), "\"'`.")
+	for _, name := range routeNames {
+		if strings.EqualFold(answer, name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 func (e *Executor) executeHumanInLoopStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
 	// Parse human-in-loop config
 	var config domain.HumanInLoopStepConfig
@@ -2166,13 +2750,15 @@ func (e *Executor) executeHumanInLoopStep(ctx context.Context, execCtx *Executio
 
 	// In a real implementation, this would:
 	// 1. Store the pending approval in the database
-	// 2. Send notification if configured
-	// 3. Update run status to "waiting_approval"
-	// 4. Return and let the project pause
-	// 5. Resume when approval is received
+	// 2. Update run status to "waiting_approval"
+	// 3. Return and let the project pause
+	// 4. Resume when approval is received
 
 	// For now, auto-approve in test mode
 	autoApprove := execCtx.Run.TriggeredBy == domain.TriggerTypeTest
+	if !autoApprove {
+		e.notifyApprovalRequested(ctx, execCtx, step, config, approvalURL)
+	}
 
 	output := map[string]interface{}{
 		"approval_id":     approvalID,
@@ -2199,7 +2785,7 @@ func (e *Executor) executeHumanInLoopStep(ctx context.Context, execCtx *Executio
 	return json.Marshal(output)
 }
 
-func (e *Executor) executeSwitchStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
+func (e *Executor) executeSwitchStep(ctx context.Context, execCtx *ExecutionContext, step domain.Step, stepRun *domain.StepRun, input json.RawMessage) (json.RawMessage, error) {
 	// Parse switch config
 	var config domain.SwitchStepConfig
 	if err := json.Unmarshal(step.Config, &config); err != nil {
@@ -2219,6 +2805,7 @@ func (e *Executor) executeSwitchStep(ctx context.Context, execCtx *ExecutionCont
 	// Evaluate each case in order
 	var matchedCase *domain.SwitchCase
 	var defaultCase *domain.SwitchCase
+	caseErrors := make(map[string]string)
 
 	for i := range config.Cases {
 		c := &config.Cases[i]
@@ -2236,6 +2823,7 @@ func (e *Executor) executeSwitchStep(ctx context.Context, execCtx *ExecutionCont
 				"expression", c.Expression,
 				"error", err,
 			)
+			caseErrors[c.Name] = err.Error()
 			continue
 		}
 
@@ -2250,34 +2838,30 @@ func (e *Executor) executeSwitchStep(ctx context.Context, execCtx *ExecutionCont
 		matchedCase = defaultCase
 	}
 
-	// Parse input to preserve original data structure
-	var inputData map[string]interface{}
-	if err := json.Unmarshal(input, &inputData); err != nil {
-		// If input is not an object, wrap it
-		inputData = map[string]interface{}{
-			"input": json.RawMessage(input),
-		}
+	// The input is passed through unchanged to the matched case's port; the evaluation details
+	// are recorded on the step run
+	evaluation := map[string]interface{}{"matched_case": nil}
+	if len(caseErrors) > 0 {
+		evaluation["case_errors"] = caseErrors
 	}
-
-	// Add matched_case and __port to the output without wrapping
+	port := "default"
 	if matchedCase != nil {
-		inputData["matched_case"] = matchedCase.Name
-		// Set __port for output port routing (used by extractOutputPortAndData)
-		inputData["__port"] = matchedCase.Name
+		evaluation["matched_case"] = matchedCase.Name
+		port = matchedCase.Name
 		e.logger.Info("Switch case matched",
 			"step_id", step.ID,
 			"case_name", matchedCase.Name,
 		)
 	} else {
-		inputData["matched_case"] = nil
 		// Use default port when no case matched
-		inputData["__port"] = "default"
 		e.logger.Info("Switch no case matched",
 			"step_id", step.ID,
 		)
 	}
+	evaluation["port"] = port
+	e.recordEvaluation(stepRun, evaluation)
 
-	return json.Marshal(inputData)
+	return branchOutput(port, input)
 }
 
 func (e *Executor) executeFilterStep(ctx context.Context, step domain.Step, input json.RawMessage) (json.RawMessage, error) {
@@ -2631,7 +3215,10 @@ func (e *Executor) executeLogStep(ctx context.Context, execCtx *ExecutionContext
 	message := config.Message
 	if message != "" {
 		// Use the unified template expansion with scopes
-		msgJSON, _ := json.Marshal(map[string]string{"msg": message})
+		msgJSON, err := json.Marshal(map[string]string{"msg": message})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal log message: %w", err)
+		}
 		expanded, err := ExpandConfigTemplatesWithScopes(msgJSON, input, execCtx.ScopedVars)
 		if err == nil {
 			var expandedMap map[string]string
@@ -2651,10 +3238,16 @@ func (e *Executor) executeLogStep(ctx context.Context, execCtx *ExecutionContext
 	// If data path is specified, extract and include that data
 	if config.Data != "" && input != nil {
 		var inputData interface{}
-		if err := json.Unmarshal(input, &inputData); err == nil {
-			if extracted := extractLogJSONPath(inputData, config.Data); extracted != nil {
-				logOutput["data"] = extracted
-			}
+		if err := json.Unmarshal(input, &inputData); err != nil {
+			// The path cannot be resolved, so the step reports why the data is missing
+			e.logger.Warn("Log step input is not valid JSON, data path not extracted",
+				"step_id", step.ID,
+				"data_path", config.Data,
+				"error", err,
+			)
+			logOutput["data_error"] = fmt.Sprintf("input is not valid JSON: %v", err)
+		} else if extracted := extractLogJSONPath(inputData, config.Data); extracted != nil {
+			logOutput["data"] = extracted
 		}
 	}
 
@@ -2757,6 +3350,12 @@ func (e *Executor) executeBlockDefinition(ctx context.Context, execCtx *Executio
 
 	// Create sandbox execution context
 	sandboxCtx := e.createSandboxContext(ctx, execCtx, step.ID, blockDef.Slug)
+	sandboxCtx.Progress = e.progressReporter(execCtx, step)
+	credentials, err := e.resolveStepCredentials(ctx, execCtx, step, input)
+	if err != nil {
+		return nil, err
+	}
+	sandboxCtx.Credentials = credentials
 
 	// === Phase 1: Execute preProcess chain (child -> root order) ===
 	currentInput := inputMap
@@ -2890,11 +3489,10 @@ func (e *Executor) executeBlockDefinition(ctx context.Context, execCtx *Executio
 	}
 
 	// Debug: log the output before postProcess (temporarily using Info level)
-	outputJSON, _ := json.Marshal(output)
 	e.logger.Info("Block output before postProcess",
 		"step_id", step.ID,
 		"block", blockDef.Slug,
-		"output_preview", truncateString(string(outputJSON), 500),
+		"output_preview", jsonPreview(output, 500),
 	)
 
 	if len(blockDef.PostProcessChain) > 0 {
@@ -2919,12 +3517,11 @@ func (e *Executor) executeBlockDefinition(ctx context.Context, execCtx *Executio
 				return nil, fmt.Errorf("postProcess hook %d failed: %w", i, err)
 			}
 			// Debug: log output after each postProcess hook
-			processedJSON, _ := json.Marshal(processed)
 			e.logger.Info("PostProcess hook result",
 				"step_id", step.ID,
 				"block", blockDef.Slug,
 				"hook_index", i,
-				"output_preview", truncateString(string(processedJSON), 500),
+				"output_preview", jsonPreview(processed, 500),
 			)
 			currentOutput = processed
 		}
@@ -2980,6 +3577,12 @@ func (e *Executor) executeBlockDefinition(ctx context.Context, execCtx *Executio
 
 // mergeBlockConfig merges step config with block's resolved config defaults
 func (e *Executor) mergeBlockConfig(stepConfig json.RawMessage, defaults json.RawMessage) map[string]interface{} {
+	return MergeBlockConfig(stepConfig, defaults)
+}
+
+// MergeBlockConfig returns the effective config of a custom block step: the block's resolved
+// config defaults, with each top-level key of the step config overriding the default
+func MergeBlockConfig(stepConfig json.RawMessage, defaults json.RawMessage) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	// First apply defaults
@@ -3005,7 +3608,7 @@ func (e *Executor) mergeBlockConfig(stepConfig json.RawMessage, defaults json.Ra
 // "undefined" errors when blocks access ctx.* properties in JavaScript.
 func (e *Executor) createSandboxContext(ctx context.Context, execCtx *ExecutionContext, stepID uuid.UUID, blockSlug string) *sandbox.ExecutionContext {
 	sandboxCtx := &sandbox.ExecutionContext{
-		HTTP: sandbox.NewHTTPClient(30 * time.Second),
+		HTTP: newSandboxHTTPClient(ctx),
 		Logger: func(args ...interface{}) {
 			e.logger.Info("Custom block script log", "step_id", stepID, "block", blockSlug, "message", fmt.Sprint(args...))
 		},
@@ -3027,7 +3630,11 @@ func (e *Executor) createSandboxContext(ctx context.Context, execCtx *ExecutionC
 	sandboxCtx.Adapter = sandbox.NewAdapterService()
 
 	// Initialize Search service for web search (used by Copilot)
-	sandboxCtx.Search = sandbox.NewSearchService()
+	sandboxCtx.Search = e.newSearchService(ctx, execCtx)
+
+	if execCtx != nil && execCtx.Run != nil {
+		sandboxCtx.Trigger = execCtx.Run.TriggerInfo()
+	}
 
 	if e.pool != nil && execCtx != nil && execCtx.Run != nil {
 		sandboxCtx.Blocks = sandbox.NewBlocksService(ctx, e.pool, execCtx.Run.TenantID)
@@ -3422,21 +4029,17 @@ func parseISO8601(s string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("cannot parse time: %s", s)
 }
 
-func stringJoin(strs []string, sep string) string {
-	return strings.Join(strs, sep)
+// jsonPreview returns the JSON of a value truncated for logging, or the marshal error
+func jsonPreview(value interface{}, maxLen int) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("<unmarshalable output: %v>", err)
+	}
+	return truncateString(string(data), maxLen)
 }
 
-// stringContains performs case-insensitive substring search.
-// Both strings are converted to lowercase for comparison.
-// Early returns handle edge cases (empty substring, impossible match).
-func stringContains(s, substr string) bool {
-	if len(substr) == 0 {
-		return true
-	}
-	if len(substr) > len(s) {
-		return false
-	}
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+func stringJoin(strs []string, sep string) string {
+	return strings.Join(strs, sep)
 }
 
 // Time functions (for testing)
@@ -0,0 +1,296 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stepRecorder is a tool adapter that records which steps ran and can fail a step a number of times
+type stepRecorder struct {
+	mu       sync.Mutex
+	executed []string
+	inputs   map[string]json.RawMessage
+	failures map[string]int // label -> remaining failures
+}
+
+func newStepRecorder() *stepRecorder {
+	return &stepRecorder{inputs: make(map[string]json.RawMessage), failures: make(map[string]int)}
+}
+
+func (r *stepRecorder) ID() string                    { return "recorder" }
+func (r *stepRecorder) Name() string                  { return "Recorder" }
+func (r *stepRecorder) InputSchema() json.RawMessage  { return nil }
+func (r *stepRecorder) OutputSchema() json.RawMessage { return nil }
+
+func (r *stepRecorder) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	var config struct {
+		Label string `json:"label"`
+	}
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executed = append(r.executed, config.Label)
+	r.inputs[config.Label] = req.Input
+	if r.failures[config.Label] > 0 {
+		r.failures[config.Label]--
+		return nil, errors.New("transient failure in " + config.Label)
+	}
+	output, _ := json.Marshal(map[string]string{"from": config.Label})
+	return &adapter.Response{Output: output}, nil
+}
+
+func (r *stepRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executed = nil
+	r.inputs = make(map[string]json.RawMessage)
+}
+
+// memoryCheckpointStore keeps the latest checkpoint per run in memory
+type memoryCheckpointStore struct {
+	mu     sync.Mutex
+	latest map[uuid.UUID]*domain.RunCheckpoint
+	saves  int
+	err    error
+}
+
+func (s *memoryCheckpointStore) Save(ctx context.Context, checkpoint *domain.RunCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.latest == nil {
+		s.latest = make(map[uuid.UUID]*domain.RunCheckpoint)
+	}
+	s.latest[checkpoint.RunID] = checkpoint
+	s.saves++
+	return nil
+}
+
+// linearPipeline builds start -> a -> b -> c -> d where the steps listed in checkpoints are checkpoints
+func linearPipeline(checkpoints ...string) (*domain.ProjectDefinition, map[string]uuid.UUID) {
+	isCheckpoint := make(map[string]bool)
+	for _, label := range checkpoints {
+		isCheckpoint[label] = true
+	}
+
+	ids := map[string]uuid.UUID{"start": uuid.New()}
+	def := &domain.ProjectDefinition{
+		Name:  "pipeline",
+		Steps: []domain.Step{{ID: ids["start"], Name: "start", Type: domain.StepTypeStart}},
+	}
+	prev := ids["start"]
+	for _, label := range []string{"a", "b", "c", "d"} {
+		ids[label] = uuid.New()
+		config, _ := json.Marshal(map[string]interface{}{
+			"adapter_id": "recorder",
+			"label":      label,
+			"checkpoint": isCheckpoint[label],
+		})
+		def.Steps = append(def.Steps, domain.Step{ID: ids[label], Name: label, Type: domain.StepTypeTool, Config: config})
+
+		source, target := prev, ids[label]
+		def.Edges = append(def.Edges, domain.Edge{ID: uuid.New(), SourceStepID: &source, TargetStepID: &target})
+		prev = ids[label]
+	}
+	return def, ids
+}
+
+func newCheckpointTestExecutor(recorder *stepRecorder, store CheckpointStore) *Executor {
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	return NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)), WithCheckpointStore(store))
+}
+
+func TestExecuteFromCheckpoint_SkipsUpstreamSteps(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["c"] = 1
+	store := &memoryCheckpointStore{}
+	executor := newCheckpointTestExecutor(recorder, store)
+
+	def, ids := linearPipeline("b")
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"query":"x"}`), domain.TriggerTypeManual)
+
+	// First attempt fails at c, after checkpoint b
+	execCtx := NewExecutionContext(run, def)
+	err := executor.Execute(context.Background(), execCtx)
+	require.Error(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, recorder.executed)
+
+	checkpoint := store.latest[run.ID]
+	require.NotNil(t, checkpoint)
+	assert.Same(t, checkpoint, execCtx.LastCheckpoint())
+	assert.Equal(t, ids["b"], checkpoint.StepID)
+	assert.Equal(t, "output", checkpoint.OutputPort)
+	assert.Contains(t, checkpoint.Outputs, ids["start"].String())
+	assert.Contains(t, checkpoint.Outputs, ids["a"].String())
+	assert.JSONEq(t, `{"from":"b"}`, string(checkpoint.Outputs[ids["b"].String()]))
+	assert.NotContains(t, checkpoint.Outputs, ids["c"].String())
+
+	// Retry resumes downstream of b with the checkpoint's outputs injected
+	recorder.reset()
+	resumeCtx := NewExecutionContext(run, def)
+	require.NoError(t, executor.ExecuteFromCheckpoint(context.Background(), resumeCtx, checkpoint))
+
+	assert.Equal(t, []string{"c", "d"}, recorder.executed)
+	assert.JSONEq(t, `{"from":"b"}`, string(recorder.inputs["c"]))
+	for _, label := range []string{"start", "a", "b"} {
+		assert.NotContains(t, resumeCtx.StepRuns, ids[label], "%s must not be re-executed", label)
+	}
+	assert.Contains(t, resumeCtx.StepRuns, ids["c"])
+	assert.Contains(t, resumeCtx.StepRuns, ids["d"])
+	assert.JSONEq(t, `{"from":"d"}`, string(resumeCtx.StepData[ids["d"]]))
+}
+
+// fanOutPipeline builds start -> a -> {b, c} -> d where the steps listed in checkpoints are checkpoints
+func fanOutPipeline(checkpoints ...string) (*domain.ProjectDefinition, map[string]uuid.UUID) {
+	isCheckpoint := make(map[string]bool)
+	for _, label := range checkpoints {
+		isCheckpoint[label] = true
+	}
+
+	ids := map[string]uuid.UUID{"start": uuid.New()}
+	def := &domain.ProjectDefinition{
+		Name:  "fan-out",
+		Steps: []domain.Step{{ID: ids["start"], Name: "start", Type: domain.StepTypeStart}},
+	}
+	for _, label := range []string{"a", "b", "c", "d"} {
+		ids[label] = uuid.New()
+		config, _ := json.Marshal(map[string]interface{}{
+			"adapter_id": "recorder",
+			"label":      label,
+			"checkpoint": isCheckpoint[label],
+		})
+		def.Steps = append(def.Steps, domain.Step{ID: ids[label], Name: label, Type: domain.StepTypeTool, Config: config})
+	}
+	for _, pair := range [][2]string{{"start", "a"}, {"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		source, target := ids[pair[0]], ids[pair[1]]
+		def.Edges = append(def.Edges, domain.Edge{ID: uuid.New(), SourceStepID: &source, TargetStepID: &target})
+	}
+	return def, ids
+}
+
+func TestExecuteFromCheckpoint_ResumesIncompleteParallelBranches(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["c"] = 1
+	store := &memoryCheckpointStore{}
+	executor := newCheckpointTestExecutor(recorder, store)
+
+	def, ids := fanOutPipeline("b")
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	// c fails in parallel with the checkpoint b, so the checkpoint is taken on the other branch
+	require.Error(t, executor.Execute(context.Background(), NewExecutionContext(run, def)))
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, recorder.executed)
+
+	checkpoint := store.latest[run.ID]
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, ids["b"], checkpoint.StepID)
+	assert.NotContains(t, checkpoint.Outputs, ids["c"].String())
+	assert.Equal(t, "output", checkpoint.OutputPorts[ids["a"].String()])
+
+	// The failed sibling c runs again and the join d runs once
+	recorder.reset()
+	resumeCtx := NewExecutionContext(run, def)
+	require.NoError(t, executor.ExecuteFromCheckpoint(context.Background(), resumeCtx, checkpoint))
+
+	assert.ElementsMatch(t, []string{"c", "d"}, recorder.executed)
+	assert.JSONEq(t, `{"from":"a"}`, string(recorder.inputs["c"]))
+	for _, label := range []string{"start", "a", "b"} {
+		assert.NotContains(t, resumeCtx.StepRuns, ids[label], "%s must not be re-executed", label)
+	}
+	assert.Contains(t, resumeCtx.StepData, ids["c"])
+	assert.Contains(t, resumeCtx.StepData, ids["d"])
+}
+
+func TestExecuteFromCheckpoint_LegacyCheckpointResumesFromCheckpointStep(t *testing.T) {
+	recorder := newStepRecorder()
+	executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+
+	def, ids := fanOutPipeline()
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	// Checkpoints saved before output ports were recorded only continue from the checkpoint step
+	checkpoint := domain.NewRunCheckpoint(run.TenantID, run.ID, ids["b"], "output", map[string]json.RawMessage{
+		ids["start"].String(): json.RawMessage(`{}`),
+		ids["a"].String():     json.RawMessage(`{"from":"a"}`),
+		ids["b"].String():     json.RawMessage(`{"from":"b"}`),
+	})
+	require.NoError(t, executor.ExecuteFromCheckpoint(context.Background(), NewExecutionContext(run, def), checkpoint))
+	assert.Equal(t, []string{"d"}, recorder.executed)
+}
+
+func TestExecuteFromCheckpoint_UsesLatestCheckpoint(t *testing.T) {
+	recorder := newStepRecorder()
+	recorder.failures["d"] = 1
+	store := &memoryCheckpointStore{}
+	executor := newCheckpointTestExecutor(recorder, store)
+
+	def, ids := linearPipeline("a", "c")
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	require.Error(t, executor.Execute(context.Background(), NewExecutionContext(run, def)))
+	assert.Equal(t, 2, store.saves)
+
+	checkpoint := store.latest[run.ID]
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, ids["c"], checkpoint.StepID)
+
+	recorder.reset()
+	require.NoError(t, executor.ExecuteFromCheckpoint(context.Background(), NewExecutionContext(run, def), checkpoint))
+	assert.Equal(t, []string{"d"}, recorder.executed)
+}
+
+func TestExecuteFromCheckpoint_UnknownStep(t *testing.T) {
+	executor := newCheckpointTestExecutor(newStepRecorder(), &memoryCheckpointStore{})
+	def, _ := linearPipeline()
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	checkpoint := domain.NewRunCheckpoint(run.TenantID, run.ID, uuid.New(), "output", nil)
+	err := executor.ExecuteFromCheckpoint(context.Background(), NewExecutionContext(run, def), checkpoint)
+	assert.ErrorContains(t, err, "checkpoint step not found")
+}
+
+func TestExecute_NoCheckpointWithoutFlag(t *testing.T) {
+	recorder := newStepRecorder()
+	store := &memoryCheckpointStore{}
+	executor := newCheckpointTestExecutor(recorder, store)
+
+	def, _ := linearPipeline()
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, recorder.executed)
+	assert.Zero(t, store.saves)
+	assert.Nil(t, execCtx.LastCheckpoint())
+}
+
+func TestExecute_CheckpointSaveFailureKeepsRunGoing(t *testing.T) {
+	recorder := newStepRecorder()
+	store := &memoryCheckpointStore{err: errors.New("database unavailable")}
+	executor := newCheckpointTestExecutor(recorder, store)
+
+	def, _ := linearPipeline("b")
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, recorder.executed)
+	assert.Nil(t, execCtx.LastCheckpoint(), "an unsaved checkpoint is not a resume point")
+}
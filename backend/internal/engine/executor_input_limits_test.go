@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_StepInputOverLimitsFails(t *testing.T) {
+	startID, growID, consumeID := uuid.New(), uuid.New(), uuid.New()
+	growConfig, _ := json.Marshal(map[string]interface{}{"code": `let v = 1; for (let i = 0; i < 10; i++) { v = { v }; } return v;`})
+	consumeConfig, _ := json.Marshal(map[string]interface{}{"code": `return { consumed: true };`})
+	def := &domain.ProjectDefinition{
+		Name: "limits",
+		Steps: []domain.Step{
+			{ID: startID, Name: "start", Type: domain.StepTypeStart},
+			{ID: growID, Name: "grow", Type: domain.StepTypeFunction, Config: growConfig},
+			{ID: consumeID, Name: "consume", Type: domain.StepTypeFunction, Config: consumeConfig},
+		},
+		Edges: []domain.Edge{
+			{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &growID},
+			{ID: uuid.New(), SourceStepID: &growID, TargetStepID: &consumeID},
+		},
+	}
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithInputLimits(domain.InputLimits{MaxDepth: 5, MaxBytes: 1024}))
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"n":1}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	err := executor.Execute(context.Background(), execCtx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum depth of 5")
+
+	consume := execCtx.StepRuns[consumeID]
+	require.NotNil(t, consume)
+	assert.Equal(t, domain.StepRunStatusFailed, consume.Status)
+	assert.Nil(t, consume.Output, "the step never runs")
+	assert.Nil(t, consume.Input, "the oversized input is not recorded")
+}
+
+func TestExecuteSingleStep_InputOverLimitsFails(t *testing.T) {
+	stepID := uuid.New()
+	config, _ := json.Marshal(map[string]interface{}{"code": `return input;`})
+	def := &domain.ProjectDefinition{Steps: []domain.Step{{ID: stepID, Name: "echo", Type: domain.StepTypeFunction, Config: config}}}
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithInputLimits(domain.InputLimits{MaxBytes: 16}))
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	stepRun, err := executor.ExecuteSingleStep(context.Background(), NewExecutionContext(run, def), stepID, json.RawMessage(`{"text":"more than sixteen bytes"}`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeding the maximum of 16 bytes")
+	assert.Equal(t, domain.StepRunStatusFailed, stepRun.Status)
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runLogStep(t *testing.T, config domain.LogStepConfig, input json.RawMessage) map[string]interface{} {
+	t.Helper()
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	raw, err := json.Marshal(config)
+	require.NoError(t, err)
+	step := domain.Step{ID: uuid.New(), Name: "log", Type: domain.StepTypeLog, Config: raw}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Steps: []domain.Step{step}})
+
+	output, err := executor.executeLogStep(context.Background(), execCtx, step, input)
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return result
+}
+
+func TestExecuteLogStep_DataPath(t *testing.T) {
+	result := runLogStep(t, domain.LogStepConfig{Message: "order received", Data: "$.order.id"}, json.RawMessage(`{"order": {"id": 42}}`))
+
+	assert.Equal(t, float64(42), result["data"])
+	assert.NotContains(t, result, "data_error")
+}
+
+func TestExecuteLogStep_DataPathOnInvalidInput(t *testing.T) {
+	result := runLogStep(t, domain.LogStepConfig{Message: "order received", Data: "$.order.id"}, json.RawMessage(`{"order": `))
+
+	assert.NotContains(t, result, "data")
+	assert.Contains(t, result["data_error"], "input is not valid JSON", "the missing data is reported instead of silently dropped")
+}
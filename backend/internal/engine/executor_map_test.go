@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// itemAdapter echoes each item's "text" config and counts Execute calls
+type itemAdapter struct {
+	id string
+
+	mu       sync.Mutex
+	executes int
+}
+
+func (a *itemAdapter) ID() string                    { return a.id }
+func (a *itemAdapter) Name() string                  { return a.id }
+func (a *itemAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *itemAdapter) OutputSchema() json.RawMessage { return nil }
+
+func (a *itemAdapter) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	a.mu.Lock()
+	a.executes++
+	a.mu.Unlock()
+	return echoText(req), nil
+}
+
+func echoText(req *adapter.Request) *adapter.Response {
+	var config struct {
+		Text string `json:"text"`
+	}
+	_ = json.Unmarshal(req.Config, &config)
+	output, _ := json.Marshal(map[string]string{"echo": config.Text})
+	return &adapter.Response{Output: output}
+}
+
+// batchItemAdapter additionally implements adapter.BatchAdapter and records batch sizes
+type batchItemAdapter struct {
+	itemAdapter
+	batches []int
+}
+
+func (a *batchItemAdapter) ExecuteBatch(ctx context.Context, reqs []*adapter.Request) ([]*adapter.Response, error) {
+	a.batches = append(a.batches, len(reqs))
+	resps := make([]*adapter.Response, len(reqs))
+	for i, req := range reqs {
+		resps[i] = echoText(req)
+	}
+	return resps, nil
+}
+
+func runMapStep(t *testing.T, adp adapter.Adapter, parallel bool) map[string]interface{} {
+	t.Helper()
+	registry := adapter.NewRegistry()
+	registry.Register(adp)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	config, _ := json.Marshal(map[string]interface{}{
+		"input_path": "$.docs",
+		"adapter_id": adp.ID(),
+		"parallel":   parallel,
+		"text":       "{{name}}",
+	})
+	step := domain.Step{ID: uuid.New(), Name: "embed", Type: domain.StepTypeMap, Config: config}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Steps: []domain.Step{step}})
+
+	input := json.RawMessage(`{"docs": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}`)
+	output, err := executor.executeMapStep(context.Background(), execCtx, step, input)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return result
+}
+
+func TestExecuteMapStep_UsesBatchAdapter(t *testing.T) {
+	adp := &batchItemAdapter{itemAdapter: itemAdapter{id: "batcher"}}
+
+	result := runMapStep(t, adp, true)
+
+	assert.Equal(t, []int{3}, adp.batches, "all items should go through a single ExecuteBatch call")
+	assert.Equal(t, 0, adp.executes, "Execute should not be called per item")
+	assert.Equal(t, float64(3), result["success_count"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"echo": "a"},
+		map[string]interface{}{"echo": "b"},
+		map[string]interface{}{"echo": "c"},
+	}, result["items"])
+}
+
+func TestExecuteMapStep_FallsBackToPerItemExecute(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		adp := &itemAdapter{id: "single"}
+
+		result := runMapStep(t, adp, parallel)
+
+		assert.Equal(t, 3, adp.executes, "parallel=%v: Execute should run once per item", parallel)
+		assert.Equal(t, float64(3), result["success_count"])
+	}
+}
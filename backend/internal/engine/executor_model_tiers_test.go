@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelEchoAdapter returns the model it was configured with, without reporting it in metadata
+type modelEchoAdapter struct {
+	configs []map[string]interface{}
+}
+
+func (a *modelEchoAdapter) ID() string                    { return "tiered" }
+func (a *modelEchoAdapter) Name() string                  { return "tiered" }
+func (a *modelEchoAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *modelEchoAdapter) OutputSchema() json.RawMessage { return nil }
+
+func (a *modelEchoAdapter) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	var config map[string]interface{}
+	_ = json.Unmarshal(req.Config, &config)
+	a.configs = append(a.configs, config)
+	output, _ := json.Marshal(map[string]interface{}{"model": config["model"]})
+	return &adapter.Response{Output: output, Metadata: map[string]string{"provider": "openai", "prompt_tokens": "8", "completion_tokens": "2"}}, nil
+}
+
+// recordingUsageRepo captures the usage records created by the usage recorder
+type recordingUsageRepo struct {
+	repository.UsageRepository
+
+	mu      sync.Mutex
+	records []*domain.UsageRecord
+}
+
+func (r *recordingUsageRepo) Create(ctx context.Context, record *domain.UsageRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	return nil
+}
+
+func runTieredLLMStep(t *testing.T, text string) (*modelEchoAdapter, *recordingUsageRepo, map[string]interface{}) {
+	t.Helper()
+	adp := &modelEchoAdapter{}
+	registry := adapter.NewRegistry()
+	registry.Register(adp)
+	usage := &recordingUsageRepo{}
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithUsageRecorder(NewUsageRecorder(usage, nil)))
+
+	config, _ := json.Marshal(map[string]interface{}{
+		"provider": "tiered",
+		"model":    "configured-model",
+		"system":   "Summarize the text.",
+		"prompt":   "{{text}}",
+		"model_tiers": []map[string]interface{}{
+			{"max_input_tokens": 2000, "model": "gpt-4o-mini"},
+			{"model": "gpt-4o"},
+		},
+	})
+	step := domain.Step{ID: uuid.New(), Name: "summarize", Type: domain.StepTypeLLM, Config: config}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Steps: []domain.Step{step}})
+	stepRun := domain.NewStepRun(run.TenantID, run.ID, step.ID, step.Name, 1)
+
+	input, _ := json.Marshal(map[string]string{"text": text})
+	output, err := executor.executeLLMStep(context.Background(), execCtx, step, stepRun, input)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return adp, usage, result
+}
+
+func TestExecuteLLMStep_ModelTiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantModel string
+	}{
+		{"small input picks the cheap tier", "A short note.", "gpt-4o-mini"},
+		{"large input picks the bigger tier", strings.Repeat("long document ", 1000), "gpt-4o"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adp, usage, result := runTieredLLMStep(t, tt.text)
+
+			require.Len(t, adp.configs, 1)
+			assert.Equal(t, tt.wantModel, adp.configs[0]["model"])
+			assert.Equal(t, tt.wantModel, result["model"])
+
+			require.Len(t, usage.records, 1)
+			assert.Equal(t, tt.wantModel, usage.records[0].Model, "usage is recorded against the chosen model")
+		})
+	}
+}
+
+func TestApplyModelTiers(t *testing.T) {
+	config := json.RawMessage(`{"model":"configured","prompt":"` + strings.Repeat("x", 400) + `"}`)
+
+	// 400 characters estimate to 100 tokens
+	_, tokens, tier, err := applyModelTiers(config, []modelTier{{MaxInputTokens: 100, Model: "small"}})
+	require.NoError(t, err)
+	assert.Equal(t, 100, tokens)
+	assert.Equal(t, 0, tier)
+
+	// No matching tier keeps the configured model
+	updated, _, tier, err := applyModelTiers(config, []modelTier{{MaxInputTokens: 99, Model: "small"}})
+	require.NoError(t, err)
+	assert.Equal(t, -1, tier)
+	assert.JSONEq(t, string(config), string(updated))
+
+	_, _, _, err = applyModelTiers(config, []modelTier{{MaxInputTokens: 10}})
+	assert.ErrorContains(t, err, "model_tiers[0]: model is required")
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressPipeline builds start -> report, where report is a function step running code
+func progressPipeline(code string) (*domain.ProjectDefinition, uuid.UUID) {
+	startID, reportID := uuid.New(), uuid.New()
+	config, _ := json.Marshal(map[string]interface{}{"code": code})
+	return &domain.ProjectDefinition{
+		Name: "progress",
+		Steps: []domain.Step{
+			{ID: startID, Name: "start", Type: domain.StepTypeStart},
+			{ID: reportID, Name: "report", Type: domain.StepTypeFunction, Config: config},
+		},
+		Edges: []domain.Edge{{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &reportID}},
+	}, reportID
+}
+
+func progressEvents(t *testing.T, events []ExecutionEvent) []StepProgressData {
+	t.Helper()
+	var progress []StepProgressData
+	for _, event := range events {
+		if event.Type == EventProgress {
+			var data StepProgressData
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			progress = append(progress, data)
+		}
+	}
+	return progress
+}
+
+func TestExecute_FunctionStepProgress(t *testing.T) {
+	def, reportID := progressPipeline(`ctx.progress(0, "starting"); ctx.progress(100, "done"); return { total: input.n * 2 };`)
+	publisher := &recordingEmitter{}
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)), WithEventPublisher(publisher))
+
+	emitter := &recordingEmitter{}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"n":21}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+	execCtx.EventEmitter = emitter
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+	progress := progressEvents(t, emitter.events)
+	require.Len(t, progress, 2)
+	assert.Equal(t, StepProgressData{StepID: reportID.String(), StepName: "report", Percent: 0, Message: "starting"}, progress[0])
+	assert.Equal(t, StepProgressData{StepID: reportID.String(), StepName: "report", Percent: 100, Message: "done"}, progress[1])
+	assert.Equal(t, progress, progressEvents(t, publisher.events), "the publisher receives the same progress events")
+
+	assert.JSONEq(t, `{"total":42}`, string(execCtx.StepRuns[reportID].Output), "progress reports do not change the output")
+}
+
+func TestExecute_FunctionStepProgressWithoutSubscriber(t *testing.T) {
+	def, reportID := progressPipeline(`ctx.progress(50, "halfway"); return { ok: true };`)
+	executor := newCheckpointTestExecutor(newStepRecorder(), nil)
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.JSONEq(t, `{"ok":true}`, string(execCtx.StepRuns[reportID].Output))
+}
+
+func TestProgressReporter_Throttles(t *testing.T) {
+	executor := newCheckpointTestExecutor(newStepRecorder(), nil)
+	emitter := &recordingEmitter{}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{})
+	execCtx.EventEmitter = emitter
+
+	report := executor.progressReporter(execCtx, domain.Step{ID: uuid.New(), Name: "loop"})
+	for i := 0; i < 50; i++ {
+		report(float64(i), "")
+	}
+	report(100, "finished")
+
+	progress := progressEvents(t, emitter.events)
+	require.Len(t, progress, 2, "reports within progressMinInterval are dropped, except 100%")
+	assert.Equal(t, float64(0), progress[0].Percent)
+	assert.Equal(t, "finished", progress[1].Message)
+}
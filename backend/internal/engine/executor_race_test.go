@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// widePipeline builds start -> a{i} -> b{i} -> join -> tail for width branches, plus
+// start -> x, start -> y -> group -> after where the block group needs both x and y.
+// Every eighth a{i} is a checkpoint, so checkpoints are saved while other branches write outputs.
+func widePipeline(width int) *domain.ProjectDefinition {
+	def := &domain.ProjectDefinition{Name: "wide"}
+	addStep := func(label string, checkpoint bool, groupID *uuid.UUID) uuid.UUID {
+		id := uuid.New()
+		config, _ := json.Marshal(map[string]interface{}{"adapter_id": "recorder", "label": label, "checkpoint": checkpoint})
+		def.Steps = append(def.Steps, domain.Step{ID: id, Name: label, Type: domain.StepTypeTool, Config: config, BlockGroupID: groupID})
+		return id
+	}
+	addEdge := func(edge domain.Edge) {
+		edge.ID = uuid.New()
+		def.Edges = append(def.Edges, edge)
+	}
+	stepEdge := func(source, target uuid.UUID) {
+		addEdge(domain.Edge{SourceStepID: &source, TargetStepID: &target})
+	}
+
+	startID := uuid.New()
+	def.Steps = append(def.Steps, domain.Step{ID: startID, Name: "start", Type: domain.StepTypeStart})
+	joinID := addStep("join", false, nil)
+	stepEdge(joinID, addStep("tail", false, nil))
+	for i := 0; i < width; i++ {
+		a := addStep(fmt.Sprintf("a%d", i), i%8 == 0, nil)
+		b := addStep(fmt.Sprintf("b%d", i), false, nil)
+		stepEdge(startID, a)
+		stepEdge(a, b)
+		stepEdge(b, joinID)
+	}
+
+	group := domain.BlockGroup{ID: uuid.New(), Name: "both", Type: domain.BlockGroupTypeParallel}
+	def.BlockGroups = append(def.BlockGroups, group)
+	addStep("in-group", false, &group.ID)
+	x, y, after := addStep("x", false, nil), addStep("y", false, nil), addStep("after", false, nil)
+	stepEdge(startID, x)
+	stepEdge(startID, y)
+	addEdge(domain.Edge{SourceStepID: &x, TargetBlockGroupID: &group.ID})
+	addEdge(domain.Edge{SourceStepID: &y, TargetBlockGroupID: &group.ID})
+	addEdge(domain.Edge{SourceBlockGroupID: &group.ID, TargetStepID: &after})
+	return def
+}
+
+// TestExecute_WideParallelDAG stresses the parallel scheduling paths. Run it with -race (as CI
+// does): every step must run exactly once however the branches interleave.
+func TestExecute_WideParallelDAG(t *testing.T) {
+	const width, rounds = 64, 20
+	def := widePipeline(width)
+
+	for round := 0; round < rounds; round++ {
+		recorder := newStepRecorder()
+		executor := newCheckpointTestExecutor(recorder, &memoryCheckpointStore{})
+		run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+		execCtx := NewExecutionContext(run, def)
+		execCtx.EventEmitter = &recordingEmitter{}
+
+		require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+		counts := make(map[string]int)
+		for _, label := range recorder.executed {
+			counts[label]++
+		}
+		for i := 0; i < width; i++ {
+			require.Equal(t, 1, counts[fmt.Sprintf("a%d", i)], "round %d: a%d", round, i)
+			require.Equal(t, 1, counts[fmt.Sprintf("b%d", i)], "round %d: b%d", round, i)
+		}
+		for _, label := range []string{"join", "tail", "x", "y", "in-group", "after"} {
+			require.Equal(t, 1, counts[label], "round %d: %s must run exactly once", round, label)
+		}
+		assert.Len(t, execCtx.StepRuns, 1+2*width+6)
+		assert.NotNil(t, execCtx.LastCheckpoint())
+	}
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitDefinition(waitMs int64) (*domain.ProjectDefinition, uuid.UUID, uuid.UUID) {
+	startID, waitID, afterID := uuid.New(), uuid.New(), uuid.New()
+	waitConfig, _ := json.Marshal(domain.WaitStepConfig{DurationMs: waitMs})
+	afterConfig, _ := json.Marshal(map[string]interface{}{"code": `return { done: true };`})
+	def := &domain.ProjectDefinition{
+		Name: "long wait",
+		Steps: []domain.Step{
+			{ID: startID, Name: "start", Type: domain.StepTypeStart},
+			{ID: waitID, Name: "wait", Type: domain.StepTypeWait, Config: waitConfig},
+			{ID: afterID, Name: "after", Type: domain.StepTypeFunction, Config: afterConfig},
+		},
+		Edges: []domain.Edge{
+			{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &waitID},
+			{ID: uuid.New(), SourceStepID: &waitID, TargetStepID: &afterID},
+		},
+	}
+	return def, waitID, afterID
+}
+
+func TestExecute_RunExceedingMaxDurationIsTerminated(t *testing.T) {
+	def, waitID, afterID := waitDefinition(60_000)
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithMaxRunDuration(100*time.Millisecond))
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	started := time.Now()
+	err := executor.Execute(context.Background(), execCtx)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRunExceededMaxDuration)
+	assert.Contains(t, err.Error(), "run exceeded max duration")
+	assert.Less(t, time.Since(started), 5*time.Second, "the wait is cancelled instead of running to completion")
+
+	wait := execCtx.StepRuns[waitID]
+	require.NotNil(t, wait)
+	assert.Equal(t, domain.StepRunStatusFailed, wait.Status)
+	assert.Nil(t, execCtx.StepRuns[afterID], "no step runs after the deadline")
+}
+
+func TestExecute_WithinMaxDurationCompletes(t *testing.T) {
+	def, _, afterID := waitDefinition(10)
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithMaxRunDuration(5*time.Second))
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	require.NotNil(t, execCtx.StepRuns[afterID])
+	assert.Equal(t, domain.StepRunStatusCompleted, execCtx.StepRuns[afterID].Status)
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runIfPipeline builds start -> gated -> after, where gated has the given run_if expression
+func runIfPipeline(runIf string) (*domain.ProjectDefinition, map[string]uuid.UUID) {
+	ids := map[string]uuid.UUID{"start": uuid.New(), "gated": uuid.New(), "after": uuid.New()}
+	gatedConfig, _ := json.Marshal(map[string]interface{}{"adapter_id": "recorder", "label": "gated", "run_if": runIf})
+	afterConfig, _ := json.Marshal(map[string]interface{}{"adapter_id": "recorder", "label": "after"})
+
+	def := &domain.ProjectDefinition{
+		Name: "run_if",
+		Steps: []domain.Step{
+			{ID: ids["start"], Name: "start", Type: domain.StepTypeStart},
+			{ID: ids["gated"], Name: "gated", Type: domain.StepTypeTool, Config: gatedConfig},
+			{ID: ids["after"], Name: "after", Type: domain.StepTypeTool, Config: afterConfig},
+		},
+	}
+	for _, pair := range [][2]string{{"start", "gated"}, {"gated", "after"}} {
+		source, target := ids[pair[0]], ids[pair[1]]
+		def.Edges = append(def.Edges, domain.Edge{ID: uuid.New(), SourceStepID: &source, TargetStepID: &target})
+	}
+	return def, ids
+}
+
+func TestExecute_RunIfFalseSkipsStep(t *testing.T) {
+	recorder := newStepRecorder()
+	executor := newCheckpointTestExecutor(recorder, nil)
+	def, ids := runIfPipeline("$.enabled == true")
+
+	emitter := &recordingEmitter{}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"enabled":false}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+	execCtx.EventEmitter = emitter
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+	assert.Equal(t, []string{"after"}, recorder.executed)
+	assert.JSONEq(t, `{"enabled":false}`, string(recorder.inputs["after"]), "the skipped step passes its input through")
+
+	stepRun := execCtx.StepRuns[ids["gated"]]
+	require.NotNil(t, stepRun)
+	assert.Equal(t, domain.StepRunStatusSkipped, stepRun.Status)
+	assert.JSONEq(t, `{"enabled":false}`, string(stepRun.Output))
+	assert.NotNil(t, stepRun.CompletedAt)
+	assert.Equal(t, domain.StepRunStatusCompleted, execCtx.StepRuns[ids["after"]].Status)
+
+	var skipped []StepSkippedData
+	for _, event := range emitter.events {
+		if event.Type == EventStepSkipped {
+			var data StepSkippedData
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			skipped = append(skipped, data)
+		}
+	}
+	require.Len(t, skipped, 1)
+	assert.Equal(t, ids["gated"].String(), skipped[0].StepID)
+	assert.Equal(t, "$.enabled == true", skipped[0].RunIf)
+}
+
+func TestExecute_RunIfTrueExecutesStep(t *testing.T) {
+	recorder := newStepRecorder()
+	executor := newCheckpointTestExecutor(recorder, nil)
+	def, ids := runIfPipeline("$.enabled == true")
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"enabled":true}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+	assert.Equal(t, []string{"gated", "after"}, recorder.executed)
+	assert.Equal(t, domain.StepRunStatusCompleted, execCtx.StepRuns[ids["gated"]].Status)
+	assert.JSONEq(t, `{"from":"gated"}`, string(recorder.inputs["after"]))
+}
+
+func TestExecute_RunIfMissingFieldSkipsStep(t *testing.T) {
+	recorder := newStepRecorder()
+	executor := newCheckpointTestExecutor(recorder, nil)
+	def, ids := runIfPipeline("$.user.email")
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"user":{}}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.Equal(t, domain.StepRunStatusSkipped, execCtx.StepRuns[ids["gated"]].Status)
+	assert.Equal(t, []string{"after"}, recorder.executed)
+}
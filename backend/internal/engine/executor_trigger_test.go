@@ -0,0 +1,26 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_FunctionStepReadsTrigger(t *testing.T) {
+	def, stepID := progressPipeline(`return { type: ctx.trigger.type, schedule_id: ctx.trigger.schedule_id || null, source: ctx.trigger.source };`)
+	executor := newCheckpointTestExecutor(newStepRecorder(), nil)
+
+	scheduleID := uuid.New()
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeSchedule)
+	require.NoError(t, run.SetTriggerSource("schedule", map[string]interface{}{"schedule_id": scheduleID.String()}))
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.JSONEq(t, `{"type":"schedule","schedule_id":"`+scheduleID.String()+`","source":"schedule"}`, string(execCtx.StepRuns[stepID].Output))
+	assert.Equal(t, "schedule", execCtx.ScopedVars.Trigger["type"], "templates of the run resolve {{$trigger}}")
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// FunctionLibrary looks up the library functions of a tenant by name, such as
+// postgres.LibraryFunctionRepository. Names without a library function are left out.
+type FunctionLibrary interface {
+	GetByNames(ctx context.Context, tenantID uuid.UUID, names []string) ([]*domain.LibraryFunction, error)
+}
+
+// WithFunctionLibrary sets the library that function steps load helpers from with require()
+func WithFunctionLibrary(library FunctionLibrary) ExecutorOption {
+	return func(e *Executor) {
+		e.functionLibrary = library
+	}
+}
+
+// loadLibraries loads the library functions code requires, and the ones they require in turn,
+// as name -> code for the sandbox. Names that are not in the library are left out, so
+// require() of them fails when the script calls it. Fails when the step would load more than
+// domain.MaxLibraryFunctionImports functions or domain.MaxLibraryBundleBytes of code. Returns
+// nil when no library is configured.
+func (e *Executor) loadLibraries(ctx context.Context, tenantID uuid.UUID, code string) (map[string]string, error) {
+	if e.functionLibrary == nil {
+		return nil, nil
+	}
+	libraries := make(map[string]string)
+	looked := make(map[string]bool)
+	pending := sandbox.RequiredLibraries(code)
+	bundleBytes := 0
+	for len(pending) > 0 {
+		var names []string
+		for _, name := range pending {
+			if !looked[name] {
+				looked[name] = true
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			break
+		}
+		if len(looked) > domain.MaxLibraryFunctionImports {
+			return nil, fmt.Errorf("function imports more than %d library functions", domain.MaxLibraryFunctionImports)
+		}
+
+		functions, err := e.functionLibrary.GetByNames(ctx, tenantID, names)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load library functions: %w", err)
+		}
+		pending = nil
+		for _, function := range functions {
+			bundleBytes += len(function.Code)
+			if bundleBytes > domain.MaxLibraryBundleBytes {
+				return nil, fmt.Errorf("library functions imported by the function exceed %d bytes", domain.MaxLibraryBundleBytes)
+			}
+			libraries[function.Name] = function.Code
+			pending = append(pending, sandbox.RequiredLibraries(function.Code)...)
+		}
+	}
+	return libraries, nil
+}
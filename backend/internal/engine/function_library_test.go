@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryFunctionLibrary holds library functions per tenant and counts lookups
+type memoryFunctionLibrary struct {
+	functions map[uuid.UUID]map[string]string
+	lookups   int
+}
+
+func (l *memoryFunctionLibrary) GetByNames(ctx context.Context, tenantID uuid.UUID, names []string) ([]*domain.LibraryFunction, error) {
+	l.lookups++
+	var functions []*domain.LibraryFunction
+	for _, name := range names {
+		if code, ok := l.functions[tenantID][name]; ok {
+			functions = append(functions, domain.NewLibraryFunction(tenantID, name, "", code, nil))
+		}
+	}
+	return functions, nil
+}
+
+// runLibraryFunctionStep runs a function step of the tenant with the library
+func runLibraryFunctionStep(t *testing.T, library FunctionLibrary, tenantID uuid.UUID, code string) (*ExecutionContext, uuid.UUID, error) {
+	t.Helper()
+	def, stepID := progressPipeline(code)
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)), WithFunctionLibrary(library))
+	run := domain.NewRun(tenantID, uuid.New(), 1, json.RawMessage(`{"title":"Hello World"}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+	return execCtx, stepID, executor.Execute(context.Background(), execCtx)
+}
+
+func TestExecute_FunctionStepRequiresLibraryFunction(t *testing.T) {
+	tenantID := uuid.New()
+	library := &memoryFunctionLibrary{functions: map[uuid.UUID]map[string]string{
+		tenantID: {
+			"text-utils": `const lower = require("lower"); exports.slugify = function(s) { return lower.lower(s).split(" ").join("-"); };`,
+			"lower":      `module.exports = { lower: function(s) { return s.toLowerCase(); } };`,
+		},
+	}}
+
+	execCtx, stepID, err := runLibraryFunctionStep(t, library, tenantID,
+		`const utils = require('text-utils'); const again = require('text-utils'); return { slug: utils.slugify(input.title), cached: utils === again };`)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"slug":"hello-world","cached":true}`, string(execCtx.StepRuns[stepID].Output))
+	assert.Equal(t, 2, library.lookups, "the step's imports and the ones they require are looked up level by level")
+}
+
+func TestExecute_FunctionStepMissingLibraryFunction(t *testing.T) {
+	tenantID := uuid.New()
+	library := &memoryFunctionLibrary{functions: map[uuid.UUID]map[string]string{
+		// Library functions of another tenant are not visible
+		uuid.New(): {"helpers": `exports.ok = true;`},
+	}}
+
+	_, _, err := runLibraryFunctionStep(t, library, tenantID, `const helpers = require("helpers"); return { ok: helpers.ok };`)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `library function "helpers" not found`)
+}
+
+func TestExecute_FunctionStepMissingLibraryFunctionCanBeCaught(t *testing.T) {
+	library := &memoryFunctionLibrary{functions: map[uuid.UUID]map[string]string{}}
+
+	execCtx, stepID, err := runLibraryFunctionStep(t, library, uuid.New(),
+		`try { require("optional"); return { loaded: true }; } catch (e) { return { loaded: false, error: String(e) }; }`)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"loaded":false,"error":"library function \"optional\" not found"}`, string(execCtx.StepRuns[stepID].Output))
+}
+
+func TestExecute_FunctionStepCircularLibraryImport(t *testing.T) {
+	tenantID := uuid.New()
+	library := &memoryFunctionLibrary{functions: map[uuid.UUID]map[string]string{
+		tenantID: {
+			"a": `const b = require("b"); exports.name = "a";`,
+			"b": `const a = require("a"); exports.name = "b";`,
+		},
+	}}
+
+	_, _, err := runLibraryFunctionStep(t, library, tenantID, `return { name: require("a").name };`)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "circular import: a -> b -> a")
+}
+
+func TestExecute_FunctionStepLibraryImportLimits(t *testing.T) {
+	t.Run("count", func(t *testing.T) {
+		tenantID := uuid.New()
+		functions := make(map[string]string)
+		var requires []string
+		for i := 0; i <= domain.MaxLibraryFunctionImports; i++ {
+			name := "helper" + strings.Repeat("x", i)
+			functions[name] = `exports.ok = true;`
+			requires = append(requires, `require("`+name+`");`)
+		}
+		library := &memoryFunctionLibrary{functions: map[uuid.UUID]map[string]string{tenantID: functions}}
+
+		_, _, err := runLibraryFunctionStep(t, library, tenantID, strings.Join(requires, " ")+` return {};`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "more than 32 library functions")
+		assert.Zero(t, library.lookups, "the import count is checked before the lookup")
+	})
+
+	t.Run("size", func(t *testing.T) {
+		tenantID := uuid.New()
+		large := `exports.data = "` + strings.Repeat("x", domain.MaxLibraryFunctionCodeBytes-32) + `";`
+		functions := make(map[string]string)
+		var requires []string
+		for i := 0; i < domain.MaxLibraryBundleBytes/domain.MaxLibraryFunctionCodeBytes+1; i++ {
+			name := "large" + strings.Repeat("x", i)
+			functions[name] = large
+			requires = append(requires, `require("`+name+`");`)
+		}
+		library := &memoryFunctionLibrary{functions: map[uuid.UUID]map[string]string{tenantID: functions}}
+
+		_, _, err := runLibraryFunctionStep(t, library, tenantID, strings.Join(requires, " ")+` return {};`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceed 524288 bytes")
+	})
+}
+
+func TestExecute_FunctionStepWithoutLibrary(t *testing.T) {
+	def, stepID := progressPipeline(`return { hasRequire: typeof require !== "undefined" };`)
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.JSONEq(t, `{"hasRequire":false}`, string(execCtx.StepRuns[stepID].Output), "require stays blocked without a library")
+}
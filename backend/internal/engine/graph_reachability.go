@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// ReachableSteps returns the steps reachable from the root steps by following edges. Reaching a
+// block group reaches every step and nested group inside it.
+func (g *Graph) ReachableSteps(roots []uuid.UUID) map[uuid.UUID]bool {
+	groupSteps := make(map[uuid.UUID][]uuid.UUID)
+	for id, step := range g.Steps {
+		if step.BlockGroupID != nil {
+			groupSteps[*step.BlockGroupID] = append(groupSteps[*step.BlockGroupID], id)
+		}
+	}
+	childGroups := make(map[uuid.UUID][]uuid.UUID)
+	for id, group := range g.BlockGroups {
+		if group.ParentGroupID != nil {
+			childGroups[*group.ParentGroupID] = append(childGroups[*group.ParentGroupID], id)
+		}
+	}
+
+	reachedSteps := make(map[uuid.UUID]bool)
+	reachedGroups := make(map[uuid.UUID]bool)
+	var visitStep, visitGroup func(id uuid.UUID)
+	visitEdges := func(edges []domain.Edge) {
+		for _, edge := range edges {
+			if edge.TargetStepID != nil {
+				visitStep(*edge.TargetStepID)
+			}
+			if edge.TargetBlockGroupID != nil {
+				visitGroup(*edge.TargetBlockGroupID)
+			}
+		}
+	}
+	visitStep = func(id uuid.UUID) {
+		if reachedSteps[id] {
+			return
+		}
+		reachedSteps[id] = true
+		visitEdges(g.OutEdges[id])
+	}
+	visitGroup = func(id uuid.UUID) {
+		if reachedGroups[id] {
+			return
+		}
+		reachedGroups[id] = true
+		for _, stepID := range groupSteps[id] {
+			visitStep(stepID)
+		}
+		for _, childID := range childGroups[id] {
+			visitGroup(childID)
+		}
+		visitEdges(g.GroupOutEdges[id])
+	}
+
+	for _, id := range roots {
+		visitStep(id)
+	}
+	return reachedSteps
+}
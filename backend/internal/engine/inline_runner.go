@@ -74,6 +74,13 @@ func (r *InlineRunner) RunWithEvents(ctx context.Context, input RunInput, events
 		if err != nil {
 			return nil, fmt.Errorf("get run: %w", err)
 		}
+		project, err := r.projectRepo.GetByID(ctx, input.TenantID, run.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get project: %w", err)
+		}
+		if project.Paused {
+			return nil, domain.ErrProjectPaused
+		}
 	} else {
 		// Create new run
 		run = domain.NewRun(
@@ -91,6 +98,9 @@ func (r *InlineRunner) RunWithEvents(ctx context.Context, input RunInput, events
 		if err != nil {
 			return nil, fmt.Errorf("get project: %w", err)
 		}
+		if project.Paused {
+			return nil, domain.ErrProjectPaused
+		}
 		run.ProjectVersion = project.Version
 
 		if err := r.runRepo.Create(ctx, run); err != nil {
@@ -117,7 +127,7 @@ func (r *InlineRunner) RunWithEvents(ctx context.Context, input RunInput, events
 	execErr := r.executor.ExecuteWithEvents(ctx, execCtx, events)
 
 	// Save step runs
-	for _, stepRun := range execCtx.StepRuns {
+	for _, stepRun := range execCtx.AllStepRuns() {
 		if err := r.stepRunRepo.Create(ctx, stepRun); err != nil {
 			r.logger.Warn("Failed to save step run", "step_run_id", stepRun.ID, "error", err)
 		}
@@ -126,6 +136,14 @@ func (r *InlineRunner) RunWithEvents(ctx context.Context, input RunInput, events
 	// Update run status
 	if execErr != nil {
 		run.Fail(execErr.Error())
+	} else if def.RunOutput != nil {
+		// The workflow selects its run output explicitly
+		finalOutput, err := execCtx.RunOutput()
+		if err != nil {
+			run.Fail(err.Error())
+		} else {
+			run.Complete(finalOutput)
+		}
 	} else {
 		// Get final output from execution context
 		var finalOutput json.RawMessage
@@ -138,7 +156,7 @@ func (r *InlineRunner) RunWithEvents(ctx context.Context, input RunInput, events
 			}
 			execCtx.mu.RUnlock()
 		}
-		run.Complete(finalOutput)
+		run.Complete(execCtx.redactSecrets(finalOutput))
 	}
 
 	if err := r.runRepo.Update(ctx, run); err != nil {
@@ -174,6 +192,7 @@ func (r *InlineRunner) getProjectDefinition(ctx context.Context, tenantID, proje
 		Steps:       project.Steps,
 		Edges:       project.Edges,
 		BlockGroups: project.BlockGroups,
+		RunOutput:   project.RunOutput,
 	}, nil
 }
 
@@ -183,6 +202,7 @@ func CreateExecutorForInlineExecution(
 	pool *pgxpool.Pool,
 	blockDefRepo repository.BlockDefinitionRepository,
 	logger *slog.Logger,
+	opts ...ExecutorOption,
 ) *Executor {
 	// Create a registry with basic adapters for inline execution
 	// For most workflows (especially Copilot), the sandbox-based execution is used
@@ -193,12 +213,14 @@ func CreateExecutorForInlineExecution(
 	registry.Register(adapter.NewMockAdapter())
 	registry.Register(adapter.NewOpenAIAdapter())
 	registry.Register(adapter.NewAnthropicAdapter())
+	registry.Register(adapter.NewEmbeddingAdapter())
 	registry.Register(adapter.NewHTTPAdapter())
 
-	return NewExecutor(registry, logger,
+	opts = append([]ExecutorOption{
 		WithDatabase(pool),
 		WithBlockDefinitionRepository(blockDefRepo),
-	)
+	}, opts...)
+	return NewExecutor(registry, logger, opts...)
 }
 
 // InlineRunnerFactory creates InlineRunner instances with proper configuration
@@ -211,6 +233,7 @@ type InlineRunnerFactory struct {
 	blockDefRepo repository.BlockDefinitionRepository
 	logger       *slog.Logger
 	executor     *Executor
+	executorOpts []ExecutorOption
 }
 
 // NewInlineRunnerFactory creates a new inline runner factory
@@ -234,11 +257,17 @@ func NewInlineRunnerFactory(
 	}
 }
 
+// WithExecutorOptions sets additional options for the executor created on first use
+func (f *InlineRunnerFactory) WithExecutorOptions(opts ...ExecutorOption) *InlineRunnerFactory {
+	f.executorOpts = opts
+	return f
+}
+
 // Create creates a new InlineRunner
 // The executor is lazily initialized on first call
 func (f *InlineRunnerFactory) Create() *InlineRunner {
 	if f.executor == nil {
-		f.executor = CreateExecutorForInlineExecution(f.pool, f.blockDefRepo, f.logger)
+		f.executor = CreateExecutorForInlineExecution(f.pool, f.blockDefRepo, f.logger, f.executorOpts...)
 	}
 	return NewInlineRunner(
 		f.executor,
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pausedProjectRepo serves one project
+type pausedProjectRepo struct {
+	repository.ProjectRepository
+	project *domain.Project
+}
+
+func (r *pausedProjectRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error) {
+	if r.project.ID != id || r.project.TenantID != tenantID {
+		return nil, domain.ErrProjectNotFound
+	}
+	return r.project, nil
+}
+
+// recordingRunRepo serves the runs it holds and records the runs created through it
+type recordingRunRepo struct {
+	repository.RunRepository
+	runs    map[uuid.UUID]*domain.Run
+	created []*domain.Run
+}
+
+func (r *recordingRunRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error) {
+	if run, ok := r.runs[id]; ok && run.TenantID == tenantID {
+		return run, nil
+	}
+	return nil, domain.ErrRunNotFound
+}
+
+func (r *recordingRunRepo) Create(ctx context.Context, run *domain.Run) error {
+	r.created = append(r.created, run)
+	return nil
+}
+
+func TestInlineRunner_RejectsPausedProject(t *testing.T) {
+	tenantID := uuid.New()
+	project := domain.NewProject(tenantID, "Nightly Sync", "")
+	project.Paused = true
+	existing := domain.NewRun(tenantID, project.ID, 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+
+	tests := []struct {
+		name  string
+		runID uuid.UUID
+	}{
+		{"new run", uuid.Nil},
+		{"existing run", existing.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runRepo := &recordingRunRepo{runs: map[uuid.UUID]*domain.Run{existing.ID: existing}}
+			runner := NewInlineRunner(nil, &pausedProjectRepo{project: project}, runRepo, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			events := make(chan ExecutionEvent, 10)
+
+			run, err := runner.RunWithEvents(context.Background(), RunInput{
+				TenantID:    tenantID,
+				ProjectID:   project.ID,
+				RunID:       tt.runID,
+				Input:       json.RawMessage(`{}`),
+				TriggeredBy: domain.TriggerTypeManual,
+			}, events)
+
+			require.ErrorIs(t, err, domain.ErrProjectPaused)
+			assert.Nil(t, run)
+			assert.Empty(t, runRepo.created, "no run is created for a paused project")
+			_, open := <-events
+			assert.False(t, open, "the event stream is closed")
+		})
+	}
+}
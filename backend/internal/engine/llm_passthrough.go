@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// llmPassthroughConfig selects which fields of an LLM step's input are carried into its output,
+// so context keeps flowing to later steps without a separate set-variables step
+type llmPassthroughConfig struct {
+	PassthroughFields []string `json:"passthrough_fields"` // Input fields copied into the output; they replace output fields of the same name
+	PreserveInput     bool     `json:"preserve_input"`     // Merge the whole input into the output; output fields take precedence
+	MergeInput        bool     `json:"merge_input"`        // Alias of preserve_input
+}
+
+// enabled reports whether any input is carried into the output
+func (c llmPassthroughConfig) enabled() bool {
+	return len(c.PassthroughFields) > 0 || c.PreserveInput || c.MergeInput
+}
+
+// mergeLLMInput carries the configured input fields into an LLM step's output. With
+// preserve_input, every input field except internal "__" fields is merged under the output;
+// passthrough_fields are then copied over it. Input or output that is not a JSON object is left
+// as is.
+func mergeLLMInput(config llmPassthroughConfig, input, output json.RawMessage) json.RawMessage {
+	if !config.enabled() {
+		return output
+	}
+	var inputData, outputData map[string]interface{}
+	if err := json.Unmarshal(input, &inputData); err != nil || inputData == nil {
+		return output
+	}
+	if err := json.Unmarshal(output, &outputData); err != nil || outputData == nil {
+		return output
+	}
+
+	merged := make(map[string]interface{}, len(inputData)+len(outputData))
+	if config.PreserveInput || config.MergeInput {
+		for key, value := range inputData {
+			if !strings.HasPrefix(key, "__") {
+				merged[key] = value
+			}
+		}
+	}
+	for key, value := range outputData {
+		merged[key] = value
+	}
+	for _, field := range config.PassthroughFields {
+		if value, exists := inputData[field]; exists {
+			merged[field] = value
+		}
+	}
+
+	result, err := json.Marshal(merged)
+	if err != nil {
+		return output
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runLLMStepWithInput executes an LLM step answered by content with the given config and input
+func runLLMStepWithInput(t *testing.T, content string, config map[string]interface{}, input string) map[string]interface{} {
+	t.Helper()
+	registry := adapter.NewRegistry()
+	registry.Register(&scriptedLLMAdapter{contents: []string{content}})
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	config["provider"] = "scripted"
+	config["prompt"] = "Summarize {{message}}"
+	raw, _ := json.Marshal(config)
+	step := domain.Step{ID: uuid.New(), Name: "summarize", Type: domain.StepTypeLLM, Config: raw}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Steps: []domain.Step{step}})
+	stepRun := domain.NewStepRun(run.TenantID, run.ID, step.ID, step.Name, 1)
+
+	output, err := executor.executeLLMStep(context.Background(), execCtx, step, stepRun, json.RawMessage(input))
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return result
+}
+
+func TestExecuteLLMStep_PassthroughFields(t *testing.T) {
+	input := `{"message": "hello", "session_id": "s-1", "config": {"model": "gpt-4o-mini"}, "secret": "x"}`
+	result := runLLMStepWithInput(t, "A greeting.", map[string]interface{}{
+		"passthrough_fields": []string{"session_id", "config", "missing"},
+	}, input)
+
+	assert.Equal(t, "A greeting.", result["content"])
+	assert.Equal(t, "s-1", result["session_id"])
+	assert.Equal(t, map[string]interface{}{"model": "gpt-4o-mini"}, result["config"])
+	assert.NotContains(t, result, "message", "fields that are not listed are not passed through")
+	assert.NotContains(t, result, "secret")
+	assert.NotContains(t, result, "missing")
+}
+
+func TestExecuteLLMStep_PreserveInput(t *testing.T) {
+	input := `{"message": "hello", "session_id": "s-1", "content": "from input", "__internal": true}`
+	for _, option := range []string{"preserve_input", "merge_input"} {
+		t.Run(option, func(t *testing.T) {
+			result := runLLMStepWithInput(t, "A greeting.", map[string]interface{}{option: true}, input)
+
+			assert.Equal(t, "hello", result["message"])
+			assert.Equal(t, "s-1", result["session_id"])
+			assert.Equal(t, "A greeting.", result["content"], "the LLM output takes precedence over input fields")
+			assert.Equal(t, "stop", result["finish_reason"])
+			assert.NotContains(t, result, "__internal", "internal fields are not merged")
+		})
+	}
+}
+
+func TestExecuteLLMStep_PreserveInputWithOutputParser(t *testing.T) {
+	result := runLLMStepWithInput(t, `{"intent": "create"}`, map[string]interface{}{
+		"output_parser":      "json",
+		"preserve_input":     true,
+		"passthrough_fields": []string{"content"},
+	}, `{"message": "add a slack step", "content": "original"}`)
+
+	assert.Equal(t, map[string]interface{}{"intent": "create"}, result["parsed"])
+	assert.Equal(t, "add a slack step", result["message"])
+	assert.Equal(t, "original", result["content"], "passthrough_fields replace output fields of the same name")
+}
+
+func TestMergeLLMInput_NonObject(t *testing.T) {
+	config := llmPassthroughConfig{PreserveInput: true, PassthroughFields: []string{"a"}}
+	assert.JSONEq(t, `{"content": "x"}`, string(mergeLLMInput(config, json.RawMessage(`[1, 2]`), json.RawMessage(`{"content": "x"}`))))
+	assert.JSONEq(t, `[1]`, string(mergeLLMInput(config, json.RawMessage(`{"a": 1}`), json.RawMessage(`[1]`))))
+	assert.JSONEq(t, `{"content": "x"}`, string(mergeLLMInput(llmPassthroughConfig{}, json.RawMessage(`{"a": 1}`), json.RawMessage(`{"content": "x"}`))))
+}
@@ -0,0 +1,262 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+const (
+	// mapStreamItemPort is the map step port whose steps process each item as it completes
+	mapStreamItemPort = "item"
+	// mapStreamCompletePort is the map step port that receives the aggregated results
+	mapStreamCompletePort = "complete"
+)
+
+// mapStreamItem is one item flowing through a streaming map pipeline
+type mapStreamItem struct {
+	index  int
+	output json.RawMessage
+	err    error
+}
+
+// executeMapStream runs a map step in streaming mode. Items are mapped concurrently (bounded by
+// max_workers) and each one is handed to the steps on the "item" port as soon as it completes,
+// instead of waiting for the whole array. Every step of that chain is a pipeline stage that
+// processes one item at a time, so a stage works on early items while later items are still
+// being mapped. Once every item has passed through the chain, the step outputs the final chain
+// outputs in input order, routed to the "complete" port when it is connected. Each chain step
+// records a step run per item (ExecutionContext.ItemStepRuns) and ends with its per-item outputs,
+// in input order, as its output.
+func (e *Executor) executeMapStream(ctx context.Context, execCtx *ExecutionContext, step domain.Step, config mapStepConfig, items []interface{}) (json.RawMessage, error) {
+	var adp adapter.Adapter
+	if config.AdapterID != "" {
+		var ok bool
+		adp, ok = e.registry.Get(config.AdapterID)
+		if !ok {
+			return nil, fmt.Errorf("adapter not found: %s", config.AdapterID)
+		}
+	}
+
+	graph := e.buildGraph(execCtx.Definition)
+	chain := e.mapStreamChain(graph, step.ID)
+
+	maxWorkers := config.MaxWorkers
+	if maxWorkers <= 0 {
+		maxWorkers = 10 // Default max workers
+	}
+
+	e.logger.Info("Streaming map items",
+		"step_id", step.ID,
+		"item_count", len(items),
+		"chain_length", len(chain),
+		"ordered", config.Ordered,
+	)
+
+	// Map every item; the buffer lets workers finish without waiting on the pipeline
+	mapped := make(chan mapStreamItem, len(items))
+	sem := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func(idx int, itm interface{}) {
+			defer wg.Done()
+			sem <- struct{}{}        // Acquire
+			defer func() { <-sem }() // Release
+
+			mapped <- e.mapStreamValue(ctx, execCtx, step, adp, idx, itm)
+		}(i, item)
+	}
+	go func() {
+		wg.Wait()
+		close(mapped)
+	}()
+
+	var stream <-chan mapStreamItem = mapped
+	if config.Ordered {
+		stream = orderMapStream(stream)
+	}
+	for _, chainStep := range chain {
+		stream = e.mapStreamStage(ctx, execCtx, graph, chainStep, len(items), stream)
+	}
+
+	// Collect the chain outputs in input order
+	results := make([]interface{}, len(items))
+	var firstError error
+	successCount := 0
+	for item := range stream {
+		if item.err != nil {
+			if firstError == nil {
+				firstError = item.err
+			}
+			e.logger.Warn("Map item failed",
+				"step_id", step.ID,
+				"item_index", item.index,
+				"error", item.err,
+			)
+			continue
+		}
+		var output interface{}
+		if err := json.Unmarshal(item.output, &output); err != nil {
+			e.logger.Warn("Failed to unmarshal map item output", "index", item.index, "error", err)
+			output = string(item.output)
+		}
+		results[item.index] = output
+		successCount++
+	}
+
+	// If all items failed, return error
+	if successCount == 0 && len(items) > 0 {
+		return nil, fmt.Errorf("all map items failed: %w", firstError)
+	}
+
+	result := map[string]interface{}{
+		"items":         results,
+		"count":         len(items),
+		"success_count": successCount,
+		"error_count":   len(items) - successCount,
+		"streamed":      true,
+	}
+	if e.hasEdgeFromPort(graph, step.ID, mapStreamCompletePort) {
+		result["__port"] = mapStreamCompletePort
+	}
+	return json.Marshal(result)
+}
+
+// mapStreamValue maps a single item, passing it through unchanged when the step has no adapter
+func (e *Executor) mapStreamValue(ctx context.Context, execCtx *ExecutionContext, step domain.Step, adp adapter.Adapter, index int, item interface{}) mapStreamItem {
+	if adp == nil {
+		output, err := json.Marshal(item)
+		return mapStreamItem{index: index, output: output, err: err}
+	}
+	output, err := e.executeMapItem(ctx, execCtx, step, adp, index, item)
+	return mapStreamItem{index: index, output: output, err: err}
+}
+
+// mapStreamStage runs step for every item received from in, one item at a time, and forwards the
+// results. Failed items are forwarded without running the step. Every run of the step is kept as
+// a step run of its item, and once all items passed, the step's output becomes the list of its
+// per-item outputs in input order (null for items it did not process).
+func (e *Executor) mapStreamStage(ctx context.Context, execCtx *ExecutionContext, graph *Graph, step *domain.Step, itemCount int, in <-chan mapStreamItem) <-chan mapStreamItem {
+	out := make(chan mapStreamItem)
+	go func() {
+		defer close(out)
+		outputs := make([]json.RawMessage, itemCount)
+		for item := range in {
+			if item.err == nil {
+				output, err := e.executeStepWithInput(ctx, execCtx, graph, step, item.output)
+				execCtx.recordItemStepRun(step.ID, item.index)
+				if err != nil {
+					item.err = fmt.Errorf("step %s failed: %w", step.Name, err)
+				} else {
+					item.output = output
+					outputs[item.index] = output
+				}
+			}
+			out <- item
+		}
+		execCtx.setItemOutputs(step.ID, outputs)
+	}()
+	return out
+}
+
+// recordItemStepRun moves the step run of step's latest execution to the per-item step runs,
+// tagged with the item it ran for, so the next item does not overwrite it
+func (ec *ExecutionContext) recordItemStepRun(stepID uuid.UUID, index int) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	stepRun, ok := ec.StepRuns[stepID]
+	if !ok {
+		return
+	}
+	delete(ec.StepRuns, stepID)
+	itemIndex := index
+	stepRun.ItemIndex = &itemIndex
+	ec.ItemStepRuns = append(ec.ItemStepRuns, stepRun)
+}
+
+// setItemOutputs replaces the output of a streaming map item step, which holds the output of
+// whichever item ran last, with the outputs of all items in input order
+func (ec *ExecutionContext) setItemOutputs(stepID uuid.UUID, outputs []json.RawMessage) {
+	list := make([]interface{}, len(outputs))
+	for i, output := range outputs {
+		if output != nil {
+			list[i] = output
+		}
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return
+	}
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.StepData[stepID] = data
+}
+
+// orderMapStream forwards the items of in sorted by index, holding back each item until all
+// items before it have been forwarded
+func orderMapStream(in <-chan mapStreamItem) <-chan mapStreamItem {
+	out := make(chan mapStreamItem)
+	go func() {
+		defer close(out)
+		pending := make(map[int]mapStreamItem)
+		next := 0
+		for item := range in {
+			pending[item.index] = item
+			for {
+				ready, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				out <- ready
+				next++
+			}
+		}
+	}()
+	return out
+}
+
+// mapStreamChain returns the steps each streamed item runs through: the step connected to the map
+// step's "item" port, followed along default-port edges. The chain ends before a step that the map
+// step feeds directly (such as the join on the "complete" port) or one already in the chain.
+func (e *Executor) mapStreamChain(graph *Graph, mapStepID uuid.UUID) []*domain.Step {
+	stop := map[uuid.UUID]bool{mapStepID: true}
+	var next *uuid.UUID
+	for _, edge := range graph.OutEdges[mapStepID] {
+		if edge.TargetStepID == nil {
+			continue
+		}
+		if edge.SourcePort == mapStreamItemPort {
+			if next == nil {
+				next = edge.TargetStepID
+			}
+			continue
+		}
+		stop[*edge.TargetStepID] = true
+	}
+
+	var chain []*domain.Step
+	for next != nil && !stop[*next] {
+		step, ok := graph.Steps[*next]
+		if !ok {
+			break
+		}
+		chain = append(chain, &step)
+		stop[step.ID] = true
+
+		next = nil
+		for _, edge := range graph.OutEdges[step.ID] {
+			if edge.TargetStepID != nil && (edge.SourcePort == "" || edge.SourcePort == "output") {
+				next = edge.TargetStepID
+				break
+			}
+		}
+	}
+	return chain
+}
@@ -0,0 +1,216 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stageEmitter records the inputs of step:started events per step name and signals when the
+// named step first starts. It is safe for the concurrent emits of a streaming map.
+type stageEmitter struct {
+	watch   string
+	started chan struct{}
+	once    sync.Once
+
+	mu     sync.Mutex
+	inputs map[string][]json.RawMessage
+}
+
+func newStageEmitter(watch string) *stageEmitter {
+	return &stageEmitter{watch: watch, started: make(chan struct{}), inputs: make(map[string][]json.RawMessage)}
+}
+
+func (s *stageEmitter) Emit(event ExecutionEvent) {
+	if event.Type != EventStepStarted {
+		return
+	}
+	var data StepStartedData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.inputs[data.StepName] = append(s.inputs[data.StepName], data.Input)
+	s.mu.Unlock()
+	if data.StepName == s.watch {
+		s.once.Do(func() { close(s.started) })
+	}
+}
+
+func (s *stageEmitter) Close() {}
+
+func (s *stageEmitter) stepInputs(name string) []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inputs[name]
+}
+
+// gatedAdapter echoes each item's name. The item named by gate blocks until release is closed,
+// and delayed items sleep before answering.
+type gatedAdapter struct {
+	gate    string
+	release <-chan struct{}
+	delayed map[string]time.Duration
+}
+
+func (a *gatedAdapter) ID() string                    { return "gated" }
+func (a *gatedAdapter) Name() string                  { return "gated" }
+func (a *gatedAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *gatedAdapter) OutputSchema() json.RawMessage { return nil }
+
+func (a *gatedAdapter) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	var item struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(req.Input, &item)
+	if item.Name == a.gate {
+		select {
+		case <-a.release:
+		case <-time.After(2 * time.Second):
+			return nil, fmt.Errorf("item %s was not released: downstream never started", item.Name)
+		}
+	}
+	time.Sleep(a.delayed[item.Name])
+	output, _ := json.Marshal(map[string]string{"name": item.Name})
+	return &adapter.Response{Output: output}, nil
+}
+
+// streamPipeline builds start -> map (stream) -item-> shout, with map -complete-> join
+func streamPipeline(ordered bool) *domain.ProjectDefinition {
+	startID, mapID, shoutID, joinID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mapConfig, _ := json.Marshal(map[string]interface{}{
+		"input_path":  "$.docs",
+		"adapter_id":  "gated",
+		"stream":      true,
+		"ordered":     ordered,
+		"max_workers": 3,
+	})
+	shoutConfig, _ := json.Marshal(map[string]interface{}{"code": `return { shout: input.name.toUpperCase() };`})
+	joinConfig, _ := json.Marshal(map[string]interface{}{"code": `return { joined: input.items.map(i => i.shout).join(","), count: input.count };`})
+	return &domain.ProjectDefinition{
+		Name: "stream",
+		Steps: []domain.Step{
+			{ID: startID, Name: "start", Type: domain.StepTypeStart},
+			{ID: mapID, Name: "map", Type: domain.StepTypeMap, Config: mapConfig},
+			{ID: shoutID, Name: "shout", Type: domain.StepTypeFunction, Config: shoutConfig},
+			{ID: joinID, Name: "join", Type: domain.StepTypeFunction, Config: joinConfig},
+		},
+		Edges: []domain.Edge{
+			{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &mapID},
+			{ID: uuid.New(), SourceStepID: &mapID, TargetStepID: &shoutID, SourcePort: "item"},
+			{ID: uuid.New(), SourceStepID: &mapID, TargetStepID: &joinID, SourcePort: "complete"},
+		},
+	}
+}
+
+func runStreamPipeline(t *testing.T, def *domain.ProjectDefinition, adp adapter.Adapter, emitter *stageEmitter) *ExecutionContext {
+	t.Helper()
+	registry := adapter.NewRegistry()
+	registry.Register(adp)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{"docs": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}`), domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, def)
+	execCtx.EventEmitter = emitter
+
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	return execCtx
+}
+
+func stepOutput(t *testing.T, execCtx *ExecutionContext, name string) map[string]interface{} {
+	t.Helper()
+	for _, step := range execCtx.Definition.Steps {
+		if step.Name == name {
+			var output map[string]interface{}
+			require.NoError(t, json.Unmarshal(execCtx.StepData[step.ID], &output))
+			return output
+		}
+	}
+	t.Fatalf("step %s not found", name)
+	return nil
+}
+
+func TestExecuteMapStream_DownstreamStartsBeforeAllItemsFinish(t *testing.T) {
+	emitter := newStageEmitter("shout")
+	// Item "c" is only mapped once the downstream step has started on an earlier item
+	adp := &gatedAdapter{gate: "c", release: emitter.started}
+
+	execCtx := runStreamPipeline(t, streamPipeline(false), adp, emitter)
+
+	assert.Len(t, emitter.stepInputs("shout"), 3, "the item step runs once per item")
+	assert.Len(t, emitter.stepInputs("join"), 1, "the join step runs once, after all items")
+
+	mapOutput := stepOutput(t, execCtx, "map")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"shout": "A"},
+		map[string]interface{}{"shout": "B"},
+		map[string]interface{}{"shout": "C"},
+	}, mapOutput["items"], "results are aggregated in input order")
+	assert.Equal(t, float64(3), mapOutput["success_count"])
+	assert.NotContains(t, mapOutput, "__port")
+
+	assert.Equal(t, map[string]interface{}{"joined": "A,B,C", "count": float64(3)}, stepOutput(t, execCtx, "join"))
+}
+
+func TestExecuteMapStream_OrderedFeedsItemsInInputOrder(t *testing.T) {
+	emitter := newStageEmitter("shout")
+	adp := &gatedAdapter{delayed: map[string]time.Duration{"a": 100 * time.Millisecond}}
+
+	execCtx := runStreamPipeline(t, streamPipeline(true), adp, emitter)
+
+	inputs := emitter.stepInputs("shout")
+	require.Len(t, inputs, 3)
+	for i, name := range []string{"a", "b", "c"} {
+		assert.JSONEq(t, fmt.Sprintf(`{"name":%q}`, name), string(inputs[i]), "item %d", i)
+	}
+	assert.Equal(t, map[string]interface{}{"joined": "A,B,C", "count": float64(3)}, stepOutput(t, execCtx, "join"))
+}
+
+func TestExecuteMapStream_FailedItemsAreCounted(t *testing.T) {
+	def := streamPipeline(false)
+	// shout fails on item "b"
+	def.Steps[2].Config, _ = json.Marshal(map[string]interface{}{"code": `if (input.name === "b") { throw new Error("bad item"); } return { shout: input.name.toUpperCase() };`})
+	def.Steps[3].Config, _ = json.Marshal(map[string]interface{}{"code": `return { errors: input.error_count, ok: input.success_count };`})
+
+	execCtx := runStreamPipeline(t, def, &gatedAdapter{}, newStageEmitter(""))
+
+	mapOutput := stepOutput(t, execCtx, "map")
+	assert.Equal(t, []interface{}{map[string]interface{}{"shout": "A"}, nil, map[string]interface{}{"shout": "C"}}, mapOutput["items"])
+	assert.Equal(t, map[string]interface{}{"errors": float64(1), "ok": float64(2)}, stepOutput(t, execCtx, "join"))
+}
+
+func TestExecuteMapStream_RecordsStepRunPerItem(t *testing.T) {
+	def := streamPipeline(false)
+	// shout fails on item "b"
+	def.Steps[2].Config, _ = json.Marshal(map[string]interface{}{"code": `if (input.name === "b") { throw new Error("bad item"); } return { shout: input.name.toUpperCase() };`})
+	def.Steps[3].Config, _ = json.Marshal(map[string]interface{}{"code": `return { ok: input.success_count };`})
+	shoutID := def.Steps[2].ID
+
+	execCtx := runStreamPipeline(t, def, &gatedAdapter{}, newStageEmitter(""))
+
+	assert.NotContains(t, execCtx.StepRuns, shoutID, "item runs are not kept as the step's single run")
+	byItem := make(map[int]*domain.StepRun)
+	for _, stepRun := range execCtx.ItemStepRuns {
+		require.Equal(t, shoutID, stepRun.StepID)
+		require.NotNil(t, stepRun.ItemIndex)
+		byItem[*stepRun.ItemIndex] = stepRun
+	}
+	require.Len(t, byItem, 3, "one step run per item")
+	assert.Equal(t, domain.StepRunStatusCompleted, byItem[0].Status)
+	assert.Equal(t, domain.StepRunStatusFailed, byItem[1].Status)
+	assert.JSONEq(t, `{"shout":"C"}`, string(byItem[2].Output))
+	assert.Len(t, execCtx.AllStepRuns(), len(execCtx.StepRuns)+3)
+
+	assert.JSONEq(t, `[{"shout":"A"},null,{"shout":"C"}]`, string(execCtx.StepData[shoutID]), "the step's output lists every item's output in input order")
+}
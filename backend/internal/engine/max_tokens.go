@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// WithMaxTokens sets the deployment ceiling of max_tokens for LLM steps. Zero disables it.
+// A tenant's limits.max_output_tokens takes precedence.
+func WithMaxTokens(ceiling int) ExecutorOption {
+	return func(e *Executor) {
+		e.maxTokens = ceiling
+	}
+}
+
+// maxTokensClamp records a max_tokens value lowered by clampMaxTokens
+type maxTokensClamp struct {
+	Requested int    // max_tokens the step asked for (0 when it relied on the adapter default)
+	Applied   int    // max_tokens sent to the adapter
+	Limit     string // "model" or "ceiling" (tenant or deployment)
+}
+
+// clampMaxTokens caps max_tokens of an expanded LLM config to the model's maximum output tokens
+// (when the model is known) and to the ceiling (when positive). A config without max_tokens gets the
+// ceiling, since the adapter default may exceed it. It returns the clamp applied, or nil.
+func clampMaxTokens(expandedConfig json.RawMessage, provider string, ceiling int) (json.RawMessage, *maxTokensClamp, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal(expandedConfig, &config); err != nil {
+		return nil, nil, fmt.Errorf("invalid LLM step config: %w", err)
+	}
+
+	requested, ok := maxTokensValue(config["max_tokens"])
+	if !ok && config["max_tokens"] != nil {
+		// Not a number; the adapter reports it
+		return expandedConfig, nil, nil
+	}
+
+	applied, limit := requested, ""
+	if ceiling > 0 && (applied == 0 || applied > ceiling) {
+		applied, limit = ceiling, "ceiling"
+	}
+	if model, _ := config["model"].(string); model != "" {
+		if caps := domain.GetModelCapabilities(provider, model); caps != nil && caps.MaxOutputTokens > 0 && applied > caps.MaxOutputTokens {
+			applied, limit = caps.MaxOutputTokens, "model"
+		}
+	}
+	if limit == "" {
+		return expandedConfig, nil, nil
+	}
+
+	config["max_tokens"] = applied
+	updated, err := json.Marshal(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, &maxTokensClamp{Requested: requested, Applied: applied, Limit: limit}, nil
+}
+
+// maxTokensValue reads a max_tokens config value, which template expansion may leave as a string
+func maxTokensValue(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// maxTokensCeiling returns the tenant's limits.max_output_tokens when positive, otherwise the
+// executor's ceiling. The tenant value is loaded once per execution.
+func (e *Executor) maxTokensCeiling(ctx context.Context, execCtx *ExecutionContext) int {
+	if e.pool == nil || execCtx.Run == nil {
+		return e.maxTokens
+	}
+
+	execCtx.mu.Lock()
+	defer execCtx.mu.Unlock()
+	if execCtx.tenantMaxTokens == nil {
+		var tokens int
+		err := e.pool.QueryRow(ctx,
+			`SELECT COALESCE((limits->>'max_output_tokens')::int, 0) FROM tenants WHERE id = $1 AND deleted_at IS NULL`,
+			execCtx.Run.TenantID,
+		).Scan(&tokens)
+		if err != nil {
+			e.logger.Debug("Failed to load tenant max output tokens", "error", err)
+			return e.maxTokens
+		}
+		execCtx.tenantMaxTokens = &tokens
+	}
+	if *execCtx.tenantMaxTokens > 0 {
+		return *execCtx.tenantMaxTokens
+	}
+	return e.maxTokens
+}
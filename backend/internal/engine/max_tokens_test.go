@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClampMaxTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		provider  string
+		ceiling   int
+		wantMax   interface{} // max_tokens sent to the adapter (nil when absent)
+		wantLimit string      // "" when nothing is clamped
+	}{
+		{name: "within model max", config: `{"model":"gpt-4o","max_tokens":1000}`, provider: "openai", wantMax: 1000.0},
+		{name: "above model max", config: `{"model":"gpt-4-turbo","max_tokens":100000}`, provider: "openai", wantMax: 4096.0, wantLimit: "model"},
+		{name: "dated model version", config: `{"model":"claude-3-5-sonnet-20241022","max_tokens":20000}`, provider: "anthropic", wantMax: 8192.0, wantLimit: "model"},
+		{name: "unknown model", config: `{"model":"in-house","max_tokens":100000}`, provider: "openai", wantMax: 100000.0},
+		{name: "above ceiling", config: `{"model":"gpt-4o","max_tokens":8000}`, provider: "openai", ceiling: 2000, wantMax: 2000.0, wantLimit: "ceiling"},
+		{name: "ceiling above model max", config: `{"model":"gpt-4-turbo","max_tokens":8000}`, provider: "openai", ceiling: 6000, wantMax: 4096.0, wantLimit: "model"},
+		{name: "default replaced by ceiling", config: `{"model":"gpt-4o"}`, provider: "openai", ceiling: 1024, wantMax: 1024.0, wantLimit: "ceiling"},
+		{name: "default without ceiling", config: `{"model":"gpt-4o"}`, provider: "openai"},
+		{name: "templated string value", config: `{"model":"gpt-4o","max_tokens":"3000"}`, provider: "openai", ceiling: 500, wantMax: 500.0, wantLimit: "ceiling"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, clamp, err := clampMaxTokens(json.RawMessage(tt.config), tt.provider, tt.ceiling)
+			require.NoError(t, err)
+
+			var config map[string]interface{}
+			require.NoError(t, json.Unmarshal(updated, &config))
+			if tt.wantMax == nil {
+				assert.NotContains(t, config, "max_tokens")
+			} else if tt.wantLimit == "" {
+				assert.JSONEq(t, tt.config, string(updated), "an unclamped config is passed through")
+			} else {
+				assert.Equal(t, tt.wantMax, config["max_tokens"])
+			}
+
+			if tt.wantLimit == "" {
+				assert.Nil(t, clamp)
+				return
+			}
+			require.NotNil(t, clamp)
+			assert.Equal(t, tt.wantLimit, clamp.Limit)
+		})
+	}
+}
+
+func TestExecuteLLMStep_ClampsMaxTokens(t *testing.T) {
+	adp := &modelEchoAdapter{}
+	registry := adapter.NewRegistry()
+	registry.Register(adp)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)), WithMaxTokens(3000))
+
+	step := domain.Step{ID: uuid.New(), Name: "summarize", Type: domain.StepTypeLLM,
+		Config: json.RawMessage(`{"provider":"tiered","model":"gpt-4o","prompt":"Hi","max_tokens":50000}`)}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Steps: []domain.Step{step}})
+	stepRun := domain.NewStepRun(run.TenantID, run.ID, step.ID, step.Name, 1)
+
+	_, err := executor.executeLLMStep(context.Background(), execCtx, step, stepRun, json.RawMessage(`{}`))
+	require.NoError(t, err)
+
+	require.Len(t, adp.configs, 1)
+	assert.Equal(t, 3000.0, adp.configs[0]["max_tokens"])
+}
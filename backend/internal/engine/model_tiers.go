@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// charsPerToken approximates how many characters of prompt text make up one token
+const charsPerToken = 4
+
+// modelTier selects the model of an LLM step for inputs of up to MaxInputTokens estimated
+// tokens. A tier without MaxInputTokens matches any input, so it usually comes last.
+type modelTier struct {
+	MaxInputTokens int    `json:"max_input_tokens,omitempty"`
+	Model          string `json:"model"`
+}
+
+// promptFields are the LLM config fields whose text is sent to the model
+var promptFields = []string{"system", "system_prompt", "prompt", "user_prompt"}
+
+// estimateInputTokens estimates the input tokens of an expanded LLM config from the length of
+// its messages, or of its prompt fields when it has none
+func estimateInputTokens(config map[string]interface{}) int {
+	chars := 0
+	if messages, ok := config["messages"].([]interface{}); ok && len(messages) > 0 {
+		for _, msg := range messages {
+			if m, ok := msg.(map[string]interface{}); ok {
+				if text, ok := m["content"].(string); ok {
+					chars += len(text)
+				}
+			}
+		}
+	} else {
+		for _, field := range promptFields {
+			if text, ok := config[field].(string); ok {
+				chars += len(text)
+			}
+		}
+	}
+	return (chars + charsPerToken - 1) / charsPerToken
+}
+
+// selectModelTier returns the index of the first tier matching the estimated input tokens, or -1
+// when none does
+func selectModelTier(tiers []modelTier, inputTokens int) int {
+	for i, tier := range tiers {
+		if tier.MaxInputTokens <= 0 || inputTokens <= tier.MaxInputTokens {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyModelTiers sets the model of an expanded LLM config to the first tier matching its
+// estimated input tokens. When no tier matches, the config's own model is kept. It returns the
+// config, the estimated tokens and the index of the chosen tier (-1 for none).
+func applyModelTiers(expandedConfig json.RawMessage, tiers []modelTier) (json.RawMessage, int, int, error) {
+	for i, tier := range tiers {
+		if tier.Model == "" {
+			return nil, 0, -1, fmt.Errorf("model_tiers[%d]: model is required", i)
+		}
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(expandedConfig, &config); err != nil {
+		return nil, 0, -1, fmt.Errorf("invalid LLM step config: %w", err)
+	}
+	inputTokens := estimateInputTokens(config)
+	index := selectModelTier(tiers, inputTokens)
+	if index < 0 {
+		return expandedConfig, inputTokens, index, nil
+	}
+	config["model"] = tiers[index].Model
+	updated, err := json.Marshal(config)
+	if err != nil {
+		return nil, 0, -1, err
+	}
+	return updated, inputTokens, index, nil
+}
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/netguard"
+)
+
+// tenantAllowlistSetting is the tenant settings key listing internal prefixes (CIDRs or single
+// addresses) that the tenant's workflows may reach despite SSRF protection
+const tenantAllowlistSetting = "ssrf_allowlist"
+
+// WithNetGuard sets the guard that blocks HTTP requests made by workflows (the http adapter and
+// ctx.http in scripts) from reaching internal addresses. A nil guard disables the protection.
+func WithNetGuard(guard *netguard.Guard) ExecutorOption {
+	return func(e *Executor) {
+		e.netGuard = guard
+	}
+}
+
+// withNetGuard returns ctx carrying the run's guard: the executor's guard extended with the
+// tenant's allowlist and egress policy. Adapters and sandbox HTTP clients pick the guard up from
+// the context. The egress policy applies even when SSRF protection is disabled.
+func (e *Executor) withNetGuard(ctx context.Context, execCtx *ExecutionContext) context.Context {
+	prefixes, egress := e.loadTenantNetPolicy(ctx, execCtx)
+	guard := e.netGuard.Allow(prefixes...).WithDomains(egress.AllowedDomains, egress.DeniedDomains)
+	if guard == nil {
+		return ctx
+	}
+	return netguard.WithContext(ctx, guard)
+}
+
+// loadTenantNetPolicy loads the tenant's SSRF allowlist and egress policy from its settings.
+// An invalid allowlist is logged and ignored as a whole, leaving only the executor's allowlist in
+// effect. An invalid egress policy, or settings that cannot be loaded, are also logged, but deny
+// every domain rather than lifting the tenant's restrictions.
+func (e *Executor) loadTenantNetPolicy(ctx context.Context, execCtx *ExecutionContext) ([]netip.Prefix, domain.TenantEgressPolicy) {
+	var egress domain.TenantEgressPolicy
+	if e.pool == nil || execCtx.Run == nil {
+		return nil, egress
+	}
+	var allowlistJSON, egressJSON []byte
+	err := e.pool.QueryRow(ctx,
+		`SELECT COALESCE(settings->'`+tenantAllowlistSetting+`', '[]'::jsonb), COALESCE(settings->'`+domain.TenantSettingEgress+`', '{}'::jsonb)
+		FROM tenants WHERE id = $1 AND deleted_at IS NULL`,
+		execCtx.Run.TenantID,
+	).Scan(&allowlistJSON, &egressJSON)
+	if err != nil {
+		e.logger.Warn("Failed to load tenant network policy, denying all domains", "tenant_id", execCtx.Run.TenantID, "error", err)
+		return nil, domain.TenantEgressPolicy{AllowedDomains: denyAllDomains}
+	}
+
+	egress, err = parseEgressPolicy(egressJSON)
+	if err != nil {
+		e.logger.Warn("Invalid tenant egress policy, denying all domains", "tenant_id", execCtx.Run.TenantID, "error", err)
+		egress = domain.TenantEgressPolicy{AllowedDomains: denyAllDomains}
+	}
+
+	var entries []string
+	if err := json.Unmarshal(allowlistJSON, &entries); err != nil {
+		e.logger.Warn("Invalid tenant SSRF allowlist", "tenant_id", execCtx.Run.TenantID, "error", err)
+		return nil, egress
+	}
+	prefixes, err := netguard.ParsePrefixes(entries)
+	if err != nil {
+		e.logger.Warn("Invalid tenant SSRF allowlist", "tenant_id", execCtx.Run.TenantID, "error", err)
+		return nil, egress
+	}
+	return prefixes, egress
+}
+
+// denyAllDomains is an egress allowlist that permits no real host: "invalid" is a reserved TLD
+// that never resolves (RFC 2606)
+var denyAllDomains = []string{"invalid"}
+
+// parseEgressPolicy parses an egress policy and normalizes its domains
+func parseEgressPolicy(data []byte) (domain.TenantEgressPolicy, error) {
+	var policy domain.TenantEgressPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, err
+	}
+	var err error
+	if policy.AllowedDomains, err = netguard.ParseDomains(policy.AllowedDomains); err != nil {
+		return policy, err
+	}
+	if policy.DeniedDomains, err = netguard.ParseDomains(policy.DeniedDomains); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// newSandboxHTTPClient creates the ctx.http client for a script, restricted by the guard carried
+// by ctx
+func newSandboxHTTPClient(ctx context.Context) *sandbox.HTTPClient {
+	return sandbox.NewHTTPClient(30 * time.Second).WithGuard(netguard.FromContext(ctx))
+}
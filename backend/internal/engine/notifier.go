@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// Notifier delivers system event notifications to the notification channels of a tenant, such
+// as usecase.NotificationDispatcher
+type Notifier interface {
+	Notify(ctx context.Context, tenantID uuid.UUID, notification domain.Notification) error
+}
+
+// WithNotifier sets the notifier that announces approval requests of human-in-loop steps
+func WithNotifier(notifier Notifier) ExecutorOption {
+	return func(e *Executor) {
+		e.notifier = notifier
+	}
+}
+
+// notifyApprovalRequested announces a pending human-in-loop approval. A failed notification is
+// logged and does not fail the step.
+func (e *Executor) notifyApprovalRequested(ctx context.Context, execCtx *ExecutionContext, step domain.Step, config domain.HumanInLoopStepConfig, approvalURL string) {
+	if e.notifier == nil {
+		return
+	}
+	message := config.Instructions
+	if message == "" {
+		message = fmt.Sprintf("Step %q is waiting for approval.", step.Name)
+	}
+	err := e.notifier.Notify(ctx, execCtx.Run.TenantID, domain.Notification{
+		Event:   domain.NotificationEventApprovalRequested,
+		Title:   fmt.Sprintf("Approval requested: %s", step.Name),
+		Message: message,
+		URL:     approvalURL,
+		Data: map[string]interface{}{
+			"run_id":     execCtx.Run.ID,
+			"project_id": execCtx.Run.ProjectID,
+			"step_id":    step.ID,
+		},
+	})
+	if err != nil {
+		e.logger.Warn("Failed to send approval notification",
+			"step_id", step.ID,
+			"run_id", execCtx.Run.ID,
+			"error", err,
+		)
+	}
+}
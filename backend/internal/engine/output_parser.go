@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Output parsers applied to the content of an LLM step's response (the output_parser config)
+const (
+	OutputParserNone         = "none"
+	OutputParserJSON         = "json"
+	OutputParserMarkdownCode = "markdown_code"
+)
+
+// ErrNoJSONInOutput is returned by the json output parser when the response contains no valid JSON
+var ErrNoJSONInOutput = errors.New("no valid JSON found in LLM response")
+
+// strictJSONInstruction is appended to the prompt when a response is retried after the json
+// parser failed
+const strictJSONInstruction = "Respond with only valid JSON. Do not wrap it in markdown code fences and do not add any explanation."
+
+// codeFencePattern matches a fenced markdown code block, capturing its language and body
+var codeFencePattern = regexp.MustCompile("(?s)```([\\w+#.-]*)[^\\n]*\\n(.*?)```")
+
+// validOutputParser reports whether parser is a known output parser ("" means none)
+func validOutputParser(parser string) bool {
+	switch parser {
+	case "", OutputParserNone, OutputParserJSON, OutputParserMarkdownCode:
+		return true
+	}
+	return false
+}
+
+// parseLLMOutput applies the output parser to the content of an LLM response and stores the
+// result in its "parsed" field, keeping the raw content. Outputs without a string content are
+// returned unchanged.
+func parseLLMOutput(parser string, output json.RawMessage) (json.RawMessage, error) {
+	if parser == "" || parser == OutputParserNone {
+		return output, nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(output, &data); err != nil {
+		return output, nil
+	}
+	content, ok := data["content"].(string)
+	if !ok {
+		return output, nil
+	}
+
+	switch parser {
+	case OutputParserJSON:
+		parsed, err := extractJSON(content)
+		if err != nil {
+			return nil, err
+		}
+		data["parsed"] = parsed
+	case OutputParserMarkdownCode:
+		data["parsed"] = extractCodeBlock(content)
+	default:
+		return nil, fmt.Errorf("unknown output_parser %q", parser)
+	}
+	return json.Marshal(data)
+}
+
+// extractJSON returns the JSON value in text: the whole text once markdown code fences are
+// stripped, otherwise the first object or array that parses, which skips leading prose
+func extractJSON(text string) (interface{}, error) {
+	var value interface{}
+	stripped := extractCodeBlock(text)
+	if err := json.Unmarshal([]byte(stripped), &value); err == nil {
+		return value, nil
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' && text[i] != '[' {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(text[i:]))
+		if err := decoder.Decode(&value); err == nil {
+			return value, nil
+		}
+	}
+	return nil, ErrNoJSONInOutput
+}
+
+// extractCodeBlock returns the body of the first fenced code block in text, or the trimmed text
+// when it has none
+func extractCodeBlock(text string) string {
+	if match := codeFencePattern.FindStringSubmatch(text); match != nil {
+		return strings.TrimSpace(match[2])
+	}
+	return strings.TrimSpace(text)
+}
+
+// withStrictJSONInstruction returns an expanded LLM config asking for a bare JSON response: the
+// instruction is appended to the last user message, or to the prompt when there are no messages
+func withStrictJSONInstruction(expandedConfig json.RawMessage) (json.RawMessage, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal(expandedConfig, &config); err != nil {
+		return nil, err
+	}
+	appendTo := func(text interface{}) string {
+		s, _ := text.(string)
+		if s == "" {
+			return strictJSONInstruction
+		}
+		return s + "\n\n" + strictJSONInstruction
+	}
+
+	if messages, ok := config["messages"].([]interface{}); ok && len(messages) > 0 {
+		last, _ := messages[len(messages)-1].(map[string]interface{})
+		if last != nil && last["role"] == "user" {
+			last["content"] = appendTo(last["content"])
+		} else {
+			config["messages"] = append(messages, map[string]interface{}{"role": "user", "content": strictJSONInstruction})
+		}
+	} else if prompt, _ := config["prompt"].(string); prompt == "" && config["user_prompt"] != nil {
+		config["user_prompt"] = appendTo(config["user_prompt"])
+	} else {
+		config["prompt"] = appendTo(config["prompt"])
+	}
+	return json.Marshal(config)
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedLLMAdapter answers each call with the next of its contents, recording the configs
+type scriptedLLMAdapter struct {
+	contents []string
+	configs  []map[string]interface{}
+}
+
+func (a *scriptedLLMAdapter) ID() string                    { return "scripted" }
+func (a *scriptedLLMAdapter) Name() string                  { return "scripted" }
+func (a *scriptedLLMAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *scriptedLLMAdapter) OutputSchema() json.RawMessage { return nil }
+
+func (a *scriptedLLMAdapter) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	var config map[string]interface{}
+	_ = json.Unmarshal(req.Config, &config)
+	a.configs = append(a.configs, config)
+	content := a.contents[len(a.configs)-1]
+	output, _ := json.Marshal(map[string]interface{}{"content": content, "finish_reason": "stop"})
+	return &adapter.Response{Output: output}, nil
+}
+
+func runParsedLLMStep(t *testing.T, adp *scriptedLLMAdapter, config map[string]interface{}) (map[string]interface{}, error) {
+	t.Helper()
+	registry := adapter.NewRegistry()
+	registry.Register(adp)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	config["provider"] = "scripted"
+	config["prompt"] = "Classify the ticket."
+	raw, _ := json.Marshal(config)
+	step := domain.Step{ID: uuid.New(), Name: "classify", Type: domain.StepTypeLLM, Config: raw}
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, nil, domain.TriggerTypeManual)
+	execCtx := NewExecutionContext(run, &domain.ProjectDefinition{Steps: []domain.Step{step}})
+	stepRun := domain.NewStepRun(run.TenantID, run.ID, step.ID, step.Name, 1)
+
+	output, err := executor.executeLLMStep(context.Background(), execCtx, step, stepRun, json.RawMessage(`{}`))
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return result, nil
+}
+
+func TestExecuteLLMStep_OutputParserJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    interface{}
+	}{
+		{"fenced JSON", "```json\n{\"category\": \"billing\"}\n```", map[string]interface{}{"category": "billing"}},
+		{"leading prose", "Sure! Here is the result:\n{\"category\": \"billing\"} Let me know if you need more.", map[string]interface{}{"category": "billing"}},
+		{"array", "[1, 2, 3]", []interface{}{1.0, 2.0, 3.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adp := &scriptedLLMAdapter{contents: []string{tt.content}}
+			result, err := runParsedLLMStep(t, adp, map[string]interface{}{"output_parser": "json"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, result["parsed"])
+			assert.Equal(t, tt.content, result["content"], "the raw content is kept")
+		})
+	}
+}
+
+func TestExecuteLLMStep_OutputParserInvalidJSON(t *testing.T) {
+	adp := &scriptedLLMAdapter{contents: []string{"I could not decide {category: billing"}}
+	_, err := runParsedLLMStep(t, adp, map[string]interface{}{"output_parser": "json"})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoJSONInOutput)
+	assert.Len(t, adp.configs, 1, "no retry unless retry_on_parse_error is set")
+}
+
+func TestExecuteLLMStep_OutputParserRetry(t *testing.T) {
+	adp := &scriptedLLMAdapter{contents: []string{"The category is billing.", `{"category":"billing"}`}}
+	result, err := runParsedLLMStep(t, adp, map[string]interface{}{
+		"output_parser":        "json",
+		"retry_on_parse_error": true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"category": "billing"}, result["parsed"])
+	require.Len(t, adp.configs, 2)
+	assert.Equal(t, "Classify the ticket.", adp.configs[0]["prompt"])
+	assert.Equal(t, "Classify the ticket.\n\n"+strictJSONInstruction, adp.configs[1]["prompt"])
+}
+
+func TestExecuteLLMStep_OutputParserRetryStillInvalid(t *testing.T) {
+	adp := &scriptedLLMAdapter{contents: []string{"billing", "still billing"}}
+	_, err := runParsedLLMStep(t, adp, map[string]interface{}{
+		"output_parser":        "json",
+		"retry_on_parse_error": true,
+	})
+
+	assert.ErrorIs(t, err, ErrNoJSONInOutput)
+	assert.Len(t, adp.configs, 2, "the response is retried only once")
+}
+
+func TestExecuteLLMStep_OutputParserMarkdownCode(t *testing.T) {
+	adp := &scriptedLLMAdapter{contents: []string{"Here you go:\n```python\nprint(\"hi\")\n```\nEnjoy."}}
+	result, err := runParsedLLMStep(t, adp, map[string]interface{}{"output_parser": "markdown_code"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `print("hi")`, result["parsed"])
+}
+
+func TestExecuteLLMStep_UnknownOutputParser(t *testing.T) {
+	adp := &scriptedLLMAdapter{contents: []string{"{}"}}
+	_, err := runParsedLLMStep(t, adp, map[string]interface{}{"output_parser": "yaml"})
+
+	assert.ErrorContains(t, err, `unknown output_parser "yaml"`)
+	assert.Empty(t, adp.configs)
+}
+
+func TestWithStrictJSONInstruction_Messages(t *testing.T) {
+	config := json.RawMessage(`{"messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"Classify."}]}`)
+	updated, err := withStrictJSONInstruction(config)
+	require.NoError(t, err)
+
+	var result struct {
+		Messages []adapter.Message `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(updated, &result))
+	require.Len(t, result.Messages, 2)
+	assert.Equal(t, "Classify.\n\n"+strictJSONInstruction, result.Messages[1].Content)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,8 +15,45 @@ import (
 const (
 	jobQueueKey     = "aio:jobs:pending"
 	jobDataKeyPrefix = "aio:jobs:data:"
+	// jobDelayedKey is a sorted set of job IDs scored by the Unix time they become due
+	jobDelayedKey = "aio:jobs:delayed"
+	// jobTenantDepthKey is a hash of tenant ID -> jobs of the tenant waiting in the queue
+	jobTenantDepthKey = "aio:jobs:tenant_depth"
+	// jobTenantKey is a hash of job ID -> tenant ID of the jobs counted in jobTenantDepthKey
+	jobTenantKey = "aio:jobs:tenant"
 )
 
+// adjustTenantDepthScript adds ARGV[2] to the tenant's queued job count, never leaving it below
+// zero (or below the increment), so a count that drifted negative is reset by the next update
+var adjustTenantDepthScript = redis.NewScript(`
+local depth = redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+local floor = math.max(tonumber(ARGV[2]), 0)
+if depth < floor then
+	redis.call('HSET', KEYS[1], ARGV[1], floor)
+end
+return 1
+`)
+
+// countJobOutScript removes a job from its tenant's queued job count. The tenant comes from the
+// record made at enqueue (KEYS[1]), or ARGV[2] for jobs enqueued without one. Returns 0 when
+// the tenant is unknown.
+var countJobOutScript = redis.NewScript(`
+local tenant = redis.call('HGET', KEYS[1], ARGV[1])
+if tenant then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+else
+	tenant = ARGV[2]
+end
+if tenant == '' then
+	return 0
+end
+local depth = redis.call('HINCRBY', KEYS[2], tenant, -1)
+if depth < 0 then
+	redis.call('HSET', KEYS[2], tenant, 0)
+end
+return 1
+`)
+
 // ExecutionMode represents the type of execution
 type ExecutionMode string
 
@@ -26,6 +64,8 @@ const (
 	ExecutionModeSingleStep ExecutionMode = "single_step"
 	// ExecutionModeResume resumes execution from a specific step
 	ExecutionModeResume ExecutionMode = "resume"
+	// ExecutionModeCheckpoint resumes execution downstream of the run's latest checkpoint
+	ExecutionModeCheckpoint ExecutionMode = "checkpoint"
 )
 
 // Job represents a project execution job
@@ -43,10 +83,13 @@ type Job struct {
 	ProjectTenantID *uuid.UUID `json:"project_tenant_id,omitempty"`
 
 	// Partial execution fields
-	ExecutionMode   ExecutionMode              `json:"execution_mode,omitempty"`   // "full", "single_step", "resume"
+	ExecutionMode   ExecutionMode              `json:"execution_mode,omitempty"`   // "full", "single_step", "resume", "checkpoint"
 	TargetStepID    *uuid.UUID                 `json:"target_step_id,omitempty"`   // Target step for single_step/resume
 	StepInput       json.RawMessage            `json:"step_input,omitempty"`       // Custom input for the target step
 	InjectedOutputs map[string]json.RawMessage `json:"injected_outputs,omitempty"` // Previous step outputs to inject
+
+	// CheckpointResumes counts the automatic checkpoint resumes already made for this run
+	CheckpointResumes int `json:"checkpoint_resumes,omitempty"`
 }
 
 // Queue manages the job queue
@@ -66,7 +109,75 @@ func (q *Queue) Enqueue(ctx context.Context, job *Job) error {
 
 	slog.Info("Enqueuing job", "job_id", job.ID, "run_id", job.RunID, "project_id", job.ProjectID)
 
-	// Store job data
+	if err := q.storeJob(ctx, job); err != nil {
+		return err
+	}
+
+	// Add to queue
+	if err := q.client.LPush(ctx, jobQueueKey, job.ID).Err(); err != nil {
+		slog.Error("Failed to enqueue job", "error", err)
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	q.countJobIn(ctx, job)
+
+	slog.Info("Job enqueued successfully", "job_id", job.ID, "queue_key", jobQueueKey)
+	return nil
+}
+
+// EnqueueDelayed adds a job that becomes pending after the delay. Due jobs are moved to the
+// pending queue by Dequeue, so a delayed job waits at most one dequeue timeout past its due time.
+func (q *Queue) EnqueueDelayed(ctx context.Context, job *Job, delay time.Duration) error {
+	if delay <= 0 {
+		return q.Enqueue(ctx, job)
+	}
+
+	job.ID = uuid.New().String()
+	job.CreatedAt = time.Now().UTC()
+
+	slog.Info("Enqueuing delayed job", "job_id", job.ID, "run_id", job.RunID, "project_id", job.ProjectID, "delay", delay)
+
+	if err := q.storeJob(ctx, job); err != nil {
+		return err
+	}
+
+	dueAt := job.CreatedAt.Add(delay)
+	if err := q.client.ZAdd(ctx, jobDelayedKey, redis.Z{Score: float64(dueAt.Unix()), Member: job.ID}).Err(); err != nil {
+		slog.Error("Failed to enqueue delayed job", "error", err)
+		return fmt.Errorf("failed to enqueue delayed job: %w", err)
+	}
+	q.countJobIn(ctx, job)
+	return nil
+}
+
+// countJobIn adds a queued job to its tenant's queued job count and records the job's tenant, so
+// the job is counted out on dequeue even when its data is gone. The count only feeds
+// backpressure, so a failed update is logged rather than failing the enqueue or dequeue.
+func (q *Queue) countJobIn(ctx context.Context, job *Job) {
+	if err := q.client.HSet(ctx, jobTenantKey, job.ID, job.TenantID.String()).Err(); err != nil {
+		slog.Warn("Failed to record the tenant of a queued job", "job_id", job.ID, "tenant_id", job.TenantID, "error", err)
+	}
+	if err := adjustTenantDepthScript.Run(ctx, q.client, []string{jobTenantDepthKey}, job.TenantID.String(), 1).Err(); err != nil {
+		slog.Warn("Failed to update tenant queue depth", "tenant_id", job.TenantID, "error", err)
+	}
+}
+
+// countJobOut removes a dequeued job from its tenant's queued job count, using fallback as the
+// tenant when none was recorded at enqueue (uuid.Nil when unknown). Reports whether it did.
+func (q *Queue) countJobOut(ctx context.Context, jobID string, fallback uuid.UUID) bool {
+	fallbackTenant := ""
+	if fallback != uuid.Nil {
+		fallbackTenant = fallback.String()
+	}
+	counted, err := countJobOutScript.Run(ctx, q.client, []string{jobTenantKey, jobTenantDepthKey}, jobID, fallbackTenant).Int()
+	if err != nil {
+		slog.Warn("Failed to update tenant queue depth", "job_id", jobID, "error", err)
+		return false
+	}
+	return counted == 1
+}
+
+// storeJob stores the job data under its ID
+func (q *Queue) storeJob(ctx context.Context, job *Job) error {
 	data, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("failed to marshal job: %w", err)
@@ -77,19 +188,38 @@ func (q *Queue) Enqueue(ctx context.Context, job *Job) error {
 		slog.Error("Failed to store job data", "error", err)
 		return fmt.Errorf("failed to store job data: %w", err)
 	}
+	return nil
+}
 
-	// Add to queue
-	if err := q.client.LPush(ctx, jobQueueKey, job.ID).Err(); err != nil {
-		slog.Error("Failed to enqueue job", "error", err)
-		return fmt.Errorf("failed to enqueue job: %w", err)
+// promoteDueJobs moves delayed jobs whose due time has passed to the pending queue. A job is
+// pushed only by the worker whose ZREM removed it, so concurrent workers never push it twice.
+func (q *Queue) promoteDueJobs(ctx context.Context) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	jobIDs, err := q.client.ZRangeByScore(ctx, jobDelayedKey, &redis.ZRangeBy{Min: "-inf", Max: now}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list due jobs: %w", err)
+	}
+	for _, jobID := range jobIDs {
+		removed, err := q.client.ZRem(ctx, jobDelayedKey, jobID).Result()
+		if err != nil {
+			return fmt.Errorf("failed to claim due job %s: %w", jobID, err)
+		}
+		if removed != 1 {
+			continue
+		}
+		if err := q.client.LPush(ctx, jobQueueKey, jobID).Err(); err != nil {
+			return fmt.Errorf("failed to enqueue due job %s: %w", jobID, err)
+		}
 	}
-
-	slog.Info("Job enqueued successfully", "job_id", job.ID, "queue_key", jobQueueKey)
 	return nil
 }
 
 // Dequeue retrieves a job from the queue (blocking)
 func (q *Queue) Dequeue(ctx context.Context, timeout time.Duration) (*Job, error) {
+	if err := q.promoteDueJobs(ctx); err != nil {
+		slog.Warn("Failed to promote delayed jobs", "error", err)
+	}
+
 	result, err := q.client.BRPop(ctx, timeout, jobQueueKey).Result()
 	if err != nil {
 		if err == redis.Nil {
@@ -103,6 +233,8 @@ func (q *Queue) Dequeue(ctx context.Context, timeout time.Duration) (*Job, error
 	}
 
 	jobID := result[1]
+	// The job has left the queue: count it out before anything below can fail
+	counted := q.countJobOut(ctx, jobID, uuid.Nil)
 
 	// Get job data
 	dataKey := jobDataKeyPrefix + jobID
@@ -115,6 +247,10 @@ func (q *Queue) Dequeue(ctx context.Context, timeout time.Duration) (*Job, error
 	if err := json.Unmarshal(data, &job); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal job %s: %w", jobID, err)
 	}
+	if !counted {
+		// Enqueued before job tenants were recorded
+		q.countJobOut(ctx, jobID, job.TenantID)
+	}
 
 	// Delete job data after dequeue (best effort, log errors)
 	if err := q.client.Del(ctx, dataKey).Err(); err != nil {
@@ -132,3 +268,21 @@ func (q *Queue) Dequeue(ctx context.Context, timeout time.Duration) (*Job, error
 func (q *Queue) Length(ctx context.Context) (int64, error) {
 	return q.client.LLen(ctx, jobQueueKey).Result()
 }
+
+// Depth returns the number of jobs waiting in the queue, pending and delayed, in total and for
+// the tenant
+func (q *Queue) Depth(ctx context.Context, tenantID uuid.UUID) (total, tenant int64, err error) {
+	pipe := q.client.Pipeline()
+	pending := pipe.LLen(ctx, jobQueueKey)
+	delayed := pipe.ZCard(ctx, jobDelayedKey)
+	tenantDepth := pipe.HGet(ctx, jobTenantDepthKey, tenantID.String())
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, 0, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+	tenant, err = tenantDepth.Int64()
+	if err != nil && err != redis.Nil {
+		return 0, 0, fmt.Errorf("failed to get tenant queue depth: %w", err)
+	}
+	// Counts stored before updates were clamped can still be negative
+	return pending.Val() + delayed.Val(), max(tenant, 0), nil
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// structuredLLMAdapter is a scriptedLLMAdapter that supports structured output
+type structuredLLMAdapter struct {
+	scriptedLLMAdapter
+}
+
+func (a *structuredLLMAdapter) SupportsStructuredOutput() bool { return true }
+
+var testRoutes = []domain.RouterRoute{
+	{Name: "billing", Description: "Invoices and payments"},
+	{Name: "support", Description: "Product problems"},
+	{Name: "sales", Description: "Buying more seats"},
+}
+
+func runRouterStep(t *testing.T, adp adapter.Adapter) (map[string]interface{}, error) {
+	t.Helper()
+	registry := adapter.NewRegistry()
+	registry.Register(adp)
+	// Limited adapters must keep the structured output capability
+	registry.SetLimit(adp.ID(), adapter.ProviderLimit{MaxInFlight: 1})
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	config, _ := json.Marshal(domain.RouterStepConfig{Routes: testRoutes, Provider: adp.ID()})
+	step := domain.Step{ID: uuid.New(), Name: "route", Type: domain.StepTypeRouter, Config: config}
+	output, err := executor.executeRouterStep(context.Background(), step, json.RawMessage(`{"ticket": "my card was charged twice"}`))
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	return result, nil
+}
+
+func routeNames() []interface{} {
+	names := make([]interface{}, len(testRoutes))
+	for i, route := range testRoutes {
+		names[i] = route.Name
+	}
+	return names
+}
+
+func TestExecuteRouterStep_StructuredOutput(t *testing.T) {
+	for _, route := range testRoutes {
+		t.Run(route.Name, func(t *testing.T) {
+			adp := &structuredLLMAdapter{scriptedLLMAdapter{contents: []string{`{"route":"` + route.Name + `"}`}}}
+			result, err := runRouterStep(t, adp)
+			require.NoError(t, err)
+
+			assert.Equal(t, route.Name, result["selected_route"])
+			assert.Equal(t, route.Name, result["__port"])
+			assert.Equal(t, true, result["structured"])
+			assert.NotContains(t, result, "fallback")
+
+			schema := adp.configs[0]["response_schema"].(map[string]interface{})["schema"].(map[string]interface{})
+			routeProperty := schema["properties"].(map[string]interface{})["route"].(map[string]interface{})
+			assert.Equal(t, routeNames(), routeProperty["enum"], "the schema allows exactly the route names")
+		})
+	}
+}
+
+func TestExecuteRouterStep_StructuredOutputUndefinedRoute(t *testing.T) {
+	for _, content := range []string{`{"route":"refunds"}`, `{"route":"Billing"}`, `billing`} {
+		t.Run(content, func(t *testing.T) {
+			adp := &structuredLLMAdapter{scriptedLLMAdapter{contents: []string{content}}}
+			_, err := runRouterStep(t, adp)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "does not select a defined route")
+		})
+	}
+}
+
+func TestExecuteRouterStep_TextFallback(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"billing", "billing"},
+		{"  Support.\n", "support"},
+		{`"sales"`, "sales"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			adp := &scriptedLLMAdapter{contents: []string{tt.content}}
+			result, err := runRouterStep(t, adp)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, result["selected_route"])
+			assert.Equal(t, false, result["structured"])
+			assert.Equal(t, true, result["fallback"])
+			assert.NotEmpty(t, result["fallback_reason"])
+			assert.NotContains(t, adp.configs[0], "response_schema")
+		})
+	}
+}
+
+func TestExecuteRouterStep_TextFallbackNoMatch(t *testing.T) {
+	// Substring matching used to pick "billing" here because it is the first route mentioned in the list
+	adp := &scriptedLLMAdapter{contents: []string{"This is not a support question, it is about billing"}}
+	_, err := runRouterStep(t, adp)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not select a defined route")
+}
+
+func TestExecuteRouterStep_MissingProvider(t *testing.T) {
+	executor := NewExecutor(adapter.NewRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	config, _ := json.Marshal(domain.RouterStepConfig{Routes: testRoutes, Provider: "missing"})
+	step := domain.Step{ID: uuid.New(), Type: domain.StepTypeRouter, Config: config}
+
+	_, err := executor.executeRouterStep(context.Background(), step, json.RawMessage(`{}`))
+
+	require.Error(t, err, "a missing provider no longer falls back to the first route")
+}
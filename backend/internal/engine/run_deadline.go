@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultMaxRunDuration is the wall-clock limit of an execution when WithMaxRunDuration is not set
+const DefaultMaxRunDuration = time.Hour
+
+// ErrRunExceededMaxDuration is returned when an execution runs past its maximum duration
+var ErrRunExceededMaxDuration = errors.New("run exceeded max duration")
+
+// WithMaxRunDuration sets the wall-clock limit of each execution (Execute, ExecuteFromStep and
+// ExecuteFromCheckpoint). It is a safety net above step timeouts for runs that loop or wait
+// pathologically: when it is reached, in-flight steps are cancelled and the execution fails with
+// ErrRunExceededMaxDuration. Zero disables the limit. A tenant's limits.max_run_duration_seconds
+// takes precedence.
+func WithMaxRunDuration(d time.Duration) ExecutorOption {
+	return func(e *Executor) {
+		e.maxDuration = d
+	}
+}
+
+// withRunDeadline returns ctx bounded by the run's maximum duration. The caller must call the
+// returned cancel function, and pass execution errors through runDeadlineError.
+func (e *Executor) withRunDeadline(ctx context.Context, execCtx *ExecutionContext) (context.Context, context.CancelFunc) {
+	d := e.loadTenantMaxRunDuration(ctx, execCtx)
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, d, fmt.Errorf("%w of %s", ErrRunExceededMaxDuration, d))
+}
+
+// runDeadlineError replaces err with the maximum duration error when the run's deadline is what
+// stopped the execution, so the run fails with it rather than with a cancelled step's error
+func runDeadlineError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, ErrRunExceededMaxDuration) {
+		return cause
+	}
+	return err
+}
+
+// loadTenantMaxRunDuration returns the tenant's limits.max_run_duration_seconds when positive,
+// otherwise the executor's maximum run duration
+func (e *Executor) loadTenantMaxRunDuration(ctx context.Context, execCtx *ExecutionContext) time.Duration {
+	if e.pool == nil || execCtx.Run == nil {
+		return e.maxDuration
+	}
+	var seconds int
+	err := e.pool.QueryRow(ctx,
+		`SELECT COALESCE((limits->>'max_run_duration_seconds')::int, 0) FROM tenants WHERE id = $1 AND deleted_at IS NULL`,
+		execCtx.Run.TenantID,
+	).Scan(&seconds)
+	if err != nil {
+		e.logger.Debug("Failed to load tenant max run duration", "error", err)
+		return e.maxDuration
+	}
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return e.maxDuration
+}
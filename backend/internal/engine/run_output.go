@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// CollectRunOutput returns the output of a completed run from its step outputs. The workflow's
+// run output selection decides it when set: the output of the designated step, or an object with
+// the output of each mapped step (null for steps that did not run, e.g. on an untaken branch).
+// Otherwise the output comes from the terminal steps: the output of the only terminal step, or a
+// map keyed by step ID when there are several.
+//
+// A step output that is not valid JSON is kept as a JSON string of its raw bytes rather than
+// dropped. An error means the output could not be assembled; the run must then fail instead of
+// completing with an empty output.
+func CollectRunOutput(def *domain.ProjectDefinition, stepData map[uuid.UUID]json.RawMessage) (json.RawMessage, error) {
+	if def.RunOutput != nil {
+		return selectRunOutput(def, stepData)
+	}
+	if len(stepData) == 0 {
+		return nil, nil
+	}
+
+	// Find terminal nodes (steps with no outgoing edges)
+	terminalSteps := findTerminalSteps(def.Steps, def.Edges)
+	if len(terminalSteps) == 0 {
+		// Fallback: use last executed step output
+		var output json.RawMessage
+		for _, data := range stepData {
+			output = data
+		}
+		return marshalRunOutput(runOutputValue(output))
+	}
+
+	outputs := make(map[string]interface{})
+	for _, stepID := range terminalSteps {
+		if data, ok := stepData[stepID]; ok {
+			outputs[stepID.String()] = runOutputValue(data)
+		}
+	}
+	// If only one terminal step, use its output directly
+	if len(outputs) == 1 {
+		for _, v := range outputs {
+			return marshalRunOutput(v)
+		}
+	}
+	return marshalRunOutput(outputs)
+}
+
+// RunOutput collects the run output of the execution (see CollectRunOutput) with the values of
+// secret variables masked, since the run output is persisted and returned by the API
+func (ec *ExecutionContext) RunOutput() (json.RawMessage, error) {
+	ec.mu.RLock()
+	output, err := CollectRunOutput(ec.Definition, ec.StepData)
+	ec.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return ec.redactSecrets(output), nil
+}
+
+// selectRunOutput applies the workflow's explicit run output selection
+func selectRunOutput(def *domain.ProjectDefinition, stepData map[uuid.UUID]json.RawMessage) (json.RawMessage, error) {
+	if err := def.RunOutput.Validate(def.Steps); err != nil {
+		return nil, fmt.Errorf("invalid run output selection: %w", err)
+	}
+	if def.RunOutput.StepID != nil {
+		return marshalRunOutput(runOutputValue(stepData[*def.RunOutput.StepID]))
+	}
+
+	outputs := make(map[string]interface{}, len(def.RunOutput.Mapping))
+	for field, stepID := range def.RunOutput.Mapping {
+		outputs[field] = runOutputValue(stepData[stepID])
+	}
+	return marshalRunOutput(outputs)
+}
+
+// runOutputValue decodes a step output for the run output. A missing output is null, and one
+// that is not valid JSON is preserved as a string of its raw bytes.
+func runOutputValue(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	return value
+}
+
+// marshalRunOutput encodes the run output; a nil value is an empty output
+func marshalRunOutput(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	output, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal run output: %w", err)
+	}
+	return output, nil
+}
+
+// findTerminalSteps returns step IDs that have no outgoing edges
+func findTerminalSteps(steps []domain.Step, edges []domain.Edge) []uuid.UUID {
+	// Build set of steps that have outgoing edges
+	hasOutgoing := make(map[uuid.UUID]bool)
+	for _, edge := range edges {
+		if edge.SourceStepID != nil {
+			hasOutgoing[*edge.SourceStepID] = true
+		}
+	}
+
+	// Find steps with no outgoing edges
+	var terminal []uuid.UUID
+	for _, step := range steps {
+		if !hasOutgoing[step.ID] {
+			terminal = append(terminal, step.ID)
+		}
+	}
+	return terminal
+}
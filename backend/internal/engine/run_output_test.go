@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRunOutputDefinition returns a start -> transform -> notify workflow where notify is the only
+// terminal step, with the output of each step
+func newRunOutputDefinition() (*domain.ProjectDefinition, map[uuid.UUID]json.RawMessage, []domain.Step) {
+	steps := []domain.Step{
+		{ID: uuid.New(), Name: "start", Type: domain.StepTypeStart},
+		{ID: uuid.New(), Name: "transform", Type: domain.StepTypeFunction},
+		{ID: uuid.New(), Name: "notify", Type: domain.StepTypeTool},
+	}
+	def := &domain.ProjectDefinition{
+		Steps: steps,
+		Edges: []domain.Edge{
+			{ID: uuid.New(), SourceStepID: &steps[0].ID, TargetStepID: &steps[1].ID},
+			{ID: uuid.New(), SourceStepID: &steps[1].ID, TargetStepID: &steps[2].ID},
+		},
+	}
+	stepData := map[uuid.UUID]json.RawMessage{
+		steps[0].ID: json.RawMessage(`{"order_id": 42}`),
+		steps[1].ID: json.RawMessage(`{"total": 99.5}`),
+		steps[2].ID: json.RawMessage(`{"sent": true}`),
+	}
+	return def, stepData, steps
+}
+
+func TestCollectRunOutput_TerminalStep(t *testing.T) {
+	def, stepData, _ := newRunOutputDefinition()
+
+	output, err := CollectRunOutput(def, stepData)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sent": true}`, string(output))
+}
+
+func TestCollectRunOutput_OutputStepOverridesTerminalSteps(t *testing.T) {
+	def, stepData, steps := newRunOutputDefinition()
+	def.RunOutput = &domain.RunOutputConfig{StepID: &steps[1].ID}
+
+	output, err := CollectRunOutput(def, stepData)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total": 99.5}`, string(output))
+}
+
+func TestCollectRunOutput_Mapping(t *testing.T) {
+	def, stepData, steps := newRunOutputDefinition()
+	delete(stepData, steps[2].ID) // notify did not run
+	def.RunOutput = &domain.RunOutputConfig{Mapping: map[string]uuid.UUID{
+		"order":        steps[0].ID,
+		"invoice":      steps[1].ID,
+		"notification": steps[2].ID,
+	}}
+
+	output, err := CollectRunOutput(def, stepData)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"order": {"order_id": 42}, "invoice": {"total": 99.5}, "notification": null}`, string(output))
+}
+
+func TestCollectRunOutput_UnknownOutputStep(t *testing.T) {
+	def, stepData, _ := newRunOutputDefinition()
+	missing := uuid.New()
+	def.RunOutput = &domain.RunOutputConfig{StepID: &missing}
+
+	_, err := CollectRunOutput(def, stepData)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not in the workflow")
+}
+
+func TestCollectRunOutput_MalformedStepOutputPreserved(t *testing.T) {
+	def, stepData, steps := newRunOutputDefinition()
+	stepData[steps[2].ID] = json.RawMessage(`{"sent": tru`)
+
+	output, err := CollectRunOutput(def, stepData)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `"{\"sent\": tru"`, string(output), "the raw output is kept as a string instead of null")
+}
+
+func TestCollectRunOutput_MalformedOutputOfSeveralTerminalSteps(t *testing.T) {
+	def, stepData, steps := newRunOutputDefinition()
+	def.Edges = def.Edges[:1] // transform and notify are both terminal
+	stepData[steps[1].ID] = json.RawMessage(`not json`)
+
+	output, err := CollectRunOutput(def, stepData)
+
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &result))
+	assert.Equal(t, "not json", result[steps[1].ID.String()])
+	assert.Equal(t, map[string]interface{}{"sent": true}, result[steps[2].ID.String()])
+}
+
+func TestCollectRunOutput_MalformedOutputStepPreserved(t *testing.T) {
+	def, stepData, steps := newRunOutputDefinition()
+	def.RunOutput = &domain.RunOutputConfig{StepID: &steps[1].ID}
+	stepData[steps[1].ID] = json.RawMessage(`{"total": 99.5`)
+
+	output, err := CollectRunOutput(def, stepData)
+
+	require.NoError(t, err)
+	assert.True(t, json.Valid(output), "the run output must be storable JSON")
+	assert.JSONEq(t, `"{\"total\": 99.5"`, string(output))
+}
+
+func TestMarshalRunOutput_FailureIsAnError(t *testing.T) {
+	output, err := marshalRunOutput(map[string]interface{}{"score": math.Inf(1)})
+
+	require.Error(t, err, "an output that cannot be marshaled fails the run")
+	assert.Contains(t, err.Error(), "failed to marshal run output")
+	assert.Nil(t, output)
+}
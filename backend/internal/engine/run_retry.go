@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// statusCodePattern matches the "status NNN" adapters put in errors of failed API requests
+var statusCodePattern = regexp.MustCompile(`status (\d{3})`)
+
+// CategorizeRunError returns the domain.RunErrorCategory* of the error a run failed with, for
+// matching against the retry_on list of a run retry policy. Errors cross adapters and step
+// executors as wrapped strings, so the category is mostly derived from the message.
+func CategorizeRunError(err error) string {
+	if err == nil {
+		return domain.RunErrorCategoryOther
+	}
+	if errors.Is(err, ErrRunExceededMaxDuration) {
+		return domain.RunErrorCategoryMaxDuration
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return domain.RunErrorCategoryTimeout
+	}
+
+	msg := strings.ToLower(err.Error())
+	if match := statusCodePattern.FindStringSubmatch(msg); match != nil {
+		switch {
+		case match[1] == "429":
+			return domain.RunErrorCategoryRateLimit
+		case match[1] == "408" || match[1] == "504":
+			return domain.RunErrorCategoryTimeout
+		case match[1][0] == '5':
+			return domain.RunErrorCategoryServerError
+		}
+	}
+
+	switch {
+	case strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests"):
+		return domain.RunErrorCategoryRateLimit
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out") ||
+		strings.Contains(msg, "deadline exceeded"):
+		return domain.RunErrorCategoryTimeout
+	case strings.Contains(msg, "connection refused") || strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "no such host") || strings.Contains(msg, "broken pipe") ||
+		strings.HasSuffix(msg, "eof"):
+		return domain.RunErrorCategoryNetwork
+	}
+	return domain.RunErrorCategoryOther
+}
+
+// IsTransientRunError reports whether a run failed with an error that may not recur when the
+// failed steps run again: a timeout, a rate limit, a network failure or a provider server error.
+// Deterministic failures, such as invalid input or a step's own error, are not transient.
+func IsTransientRunError(err error) bool {
+	switch CategorizeRunError(err) {
+	case domain.RunErrorCategoryTimeout, domain.RunErrorCategoryRateLimit,
+		domain.RunErrorCategoryNetwork, domain.RunErrorCategoryServerError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorizeRunError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"max duration", fmt.Errorf("step llm: %w", ErrRunExceededMaxDuration), domain.RunErrorCategoryMaxDuration},
+		{"deadline", fmt.Errorf("step llm: %w", context.DeadlineExceeded), domain.RunErrorCategoryTimeout},
+		{"rate limit status", errors.New("OpenAI API returned status 429: slow down"), domain.RunErrorCategoryRateLimit},
+		{"server error status", errors.New("Anthropic API returned status 529: overloaded"), domain.RunErrorCategoryServerError},
+		{"gateway timeout status", errors.New("HTTP request returned status 504: "), domain.RunErrorCategoryTimeout},
+		{"client error status", errors.New("HTTP request returned status 400: bad request"), domain.RunErrorCategoryOther},
+		{"timeout message", errors.New("request timed out after 30s"), domain.RunErrorCategoryTimeout},
+		{"connection refused", errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), domain.RunErrorCategoryNetwork},
+		{"unexpected eof", errors.New("read response: unexpected EOF"), domain.RunErrorCategoryNetwork},
+		{"other", errors.New("output_parser json: no JSON found in output"), domain.RunErrorCategoryOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CategorizeRunError(tt.err))
+		})
+	}
+}
+
+func TestIsTransientRunError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", fmt.Errorf("step llm: %w", context.DeadlineExceeded), true},
+		{"rate limit", errors.New("OpenAI API returned status 429: slow down"), true},
+		{"server error", errors.New("Anthropic API returned status 529: overloaded"), true},
+		{"network", errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), true},
+		{"max duration", fmt.Errorf("step llm: %w", ErrRunExceededMaxDuration), false},
+		{"validation", domain.NewValidationError("input", "name is required"), false},
+		{"client error", errors.New("HTTP request returned status 400: bad request"), false},
+		{"cancelled", fmt.Errorf("step llm: %w", context.Canceled), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransientRunError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// runScopedVariables returns the variables the run executes with and its trigger metadata. A run
+// with a variables snapshot uses it, so resumes and retries see the values the run was created
+// with; older runs without one fall back to the live organization, project and personal variables.
+func (e *Executor) runScopedVariables(ctx context.Context, execCtx *ExecutionContext) *ScopedVariables {
+	run := execCtx.Run
+	var scopes *ScopedVariables
+	if run.VariablesSnapshot != nil {
+		scopes = e.openVariablesSnapshot(run.VariablesSnapshot)
+	} else {
+		userID := uuid.Nil
+		if run.TriggeredByUser != nil {
+			userID = *run.TriggeredByUser
+		}
+		scopes = e.loadScopedVariables(ctx, run.TenantID, run.ProjectID, userID, execCtx.Definition.Variables)
+	}
+	scopes.Trigger = run.TriggerInfo()
+	return scopes
+}
+
+// openVariablesSnapshot decrypts the secret variables of a run's snapshot for template resolution
+func (e *Executor) openVariablesSnapshot(snapshot *domain.RunVariables) *ScopedVariables {
+	scopes := &ScopedVariables{
+		Org:      make(map[string]interface{}),
+		Project:  make(map[string]interface{}),
+		Personal: make(map[string]interface{}),
+	}
+	var err error
+	if scopes.Org, err = OpenVariables(e.encryptor, snapshot.Org); err != nil {
+		e.logger.Warn("Failed to decrypt secret tenant variables", "error", err)
+	}
+	for key, value := range snapshot.Project {
+		scopes.Project[key] = value
+	}
+	if scopes.Personal, err = OpenVariables(e.encryptor, snapshot.Personal); err != nil {
+		e.logger.Warn("Failed to decrypt secret user variables", "error", err)
+	}
+	scopes.Secrets = append(secretValues(snapshot.Org, scopes.Org), secretValues(snapshot.Personal, scopes.Personal)...)
+	return scopes
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// variablesPipeline builds start -> tool where the tool step's label is rendered from variables
+func variablesPipeline(projectVariables string) *domain.ProjectDefinition {
+	startID, toolID := uuid.New(), uuid.New()
+	config, _ := json.Marshal(map[string]interface{}{
+		"adapter_id": "recorder",
+		"label":      "{{$project.region}}:{{$org.api_key}}",
+	})
+	return &domain.ProjectDefinition{
+		Name:      "variables",
+		Variables: json.RawMessage(projectVariables),
+		Steps: []domain.Step{
+			{ID: startID, Name: "start", Type: domain.StepTypeStart},
+			{ID: toolID, Name: "tool", Type: domain.StepTypeTool, Config: config},
+		},
+		Edges: []domain.Edge{{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &toolID}},
+	}
+}
+
+func TestExecute_UsesRunVariablesSnapshot(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	recorder := newStepRecorder()
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)), WithSecretEncryptor(encryptor))
+
+	sealed, err := SealVariables(encryptor, map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-snapshot"},
+	}, nil)
+	require.NoError(t, err)
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	run.VariablesSnapshot = &domain.RunVariables{
+		Org:      storedVariables(t, sealed),
+		Project:  map[string]interface{}{"region": "eu"},
+		Personal: map[string]interface{}{},
+	}
+
+	raw, _ := json.Marshal(run.VariablesSnapshot)
+	assert.NotContains(t, string(raw), "sk-snapshot", "secrets stay encrypted in the snapshot")
+
+	// The live project variables changed after the run was created
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, variablesPipeline(`{"region": "us"}`))))
+	assert.Equal(t, []string{"eu:sk-snapshot"}, recorder.executed)
+
+	// Re-executing the run (e.g. a resume) keeps using the snapshot
+	recorder.reset()
+	require.NoError(t, executor.Execute(context.Background(), NewExecutionContext(run, variablesPipeline(`{"region": "ap"}`))))
+	assert.Equal(t, []string{"eu:sk-snapshot"}, recorder.executed)
+
+	retry := (&domain.RunRetryPolicy{MaxAttempts: 2}).NextRetry(failedRunCopy(run), domain.RunErrorCategoryTimeout)
+	require.NotNil(t, retry)
+	assert.Same(t, run.VariablesSnapshot, retry.VariablesSnapshot, "automatic retries carry the snapshot")
+}
+
+func TestExecute_RedactsSecretsFromStepRuns(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	recorder := newStepRecorder()
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)), WithSecretEncryptor(encryptor))
+
+	sealed, err := SealVariables(encryptor, map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-plaintext"},
+	}, nil)
+	require.NoError(t, err)
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	run.VariablesSnapshot = &domain.RunVariables{
+		Org:      storedVariables(t, sealed),
+		Project:  map[string]interface{}{"region": "eu"},
+		Personal: map[string]interface{}{},
+	}
+
+	// start -> tool (outputs the rendered secret) -> echo (receives it as input)
+	def := variablesPipeline(`{}`)
+	toolID := def.Steps[1].ID
+	echoID := uuid.New()
+	echoConfig, _ := json.Marshal(map[string]interface{}{"adapter_id": "recorder", "label": "echo"})
+	def.Steps = append(def.Steps, domain.Step{ID: echoID, Name: "echo", Type: domain.StepTypeTool, Config: echoConfig})
+	def.Edges = append(def.Edges, domain.Edge{ID: uuid.New(), SourceStepID: &toolID, TargetStepID: &echoID})
+
+	execCtx := NewExecutionContext(run, def)
+	emitter := &recordingEmitter{}
+	execCtx.EventEmitter = emitter
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+
+	// Execution uses the plain value
+	assert.Equal(t, []string{"eu:sk-plaintext", "echo"}, recorder.executed)
+	assert.Contains(t, string(recorder.inputs["echo"]), "sk-plaintext")
+
+	// What is stored on the step runs and streamed is masked
+	assert.JSONEq(t, `{"from":"eu:***"}`, string(execCtx.StepRuns[toolID].Output))
+	assert.JSONEq(t, `{"from":"eu:***"}`, string(execCtx.StepRuns[echoID].Input))
+	assert.NotContains(t, string(execCtx.StepRuns[echoID].Input), "sk-plaintext")
+	for _, event := range emitter.events {
+		raw, err := json.Marshal(event)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "sk-plaintext", "%s event", event.Type)
+	}
+}
+
+func TestExecutionContext_RunOutputRedactsSecrets(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	recorder := newStepRecorder()
+	registry := adapter.NewRegistry()
+	registry.Register(recorder)
+	executor := NewExecutor(registry, slog.New(slog.NewTextHandler(io.Discard, nil)), WithSecretEncryptor(encryptor))
+
+	sealed, err := SealVariables(encryptor, map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-plaintext"},
+	}, nil)
+	require.NoError(t, err)
+	run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), domain.TriggerTypeManual)
+	run.VariablesSnapshot = &domain.RunVariables{
+		Org:      storedVariables(t, sealed),
+		Project:  map[string]interface{}{"region": "eu"},
+		Personal: map[string]interface{}{},
+	}
+
+	// start -> tool, whose output renders the secret, is the terminal step
+	def := variablesPipeline(`{}`)
+	toolID := def.Steps[1].ID
+	execCtx := NewExecutionContext(run, def)
+	require.NoError(t, executor.Execute(context.Background(), execCtx))
+	assert.Contains(t, string(execCtx.StepData[toolID]), "sk-plaintext", "downstream steps get the plain value")
+
+	output, err := execCtx.RunOutput()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"from":"eu:***"}`, string(output))
+
+	// An explicit run output selection is masked too
+	def.RunOutput = &domain.RunOutputConfig{Mapping: map[string]uuid.UUID{"result": toolID}}
+	output, err = execCtx.RunOutput()
+	require.NoError(t, err)
+	assert.NotContains(t, string(output), "sk-plaintext")
+	assert.JSONEq(t, `{"result":{"from":"eu:***"}}`, string(output))
+}
+
+// failedRunCopy returns a failed copy of run, as the worker sees it before scheduling a retry
+func failedRunCopy(run *domain.Run) *domain.Run {
+	failed := *run
+	failed.Start()
+	failed.Fail("timeout")
+	return &failed
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+)
+
+// tenantSearchProviderSetting is the tenant settings key naming the web search provider that
+// ctx.search uses for the tenant's runs instead of the deployment's SEARCH_PROVIDER
+const tenantSearchProviderSetting = "search_provider"
+
+// newSearchService creates the ctx.search service of a script: the tenant's preferred provider
+// when its settings name one, else the deployment's. Providers are configured from the
+// deployment's environment either way, so a tenant can only pick among the providers it has keys
+// for.
+func (e *Executor) newSearchService(ctx context.Context, execCtx *ExecutionContext) *sandbox.SearchService {
+	if provider := e.tenantSearchProvider(ctx, execCtx); provider != "" {
+		return sandbox.NewSearchServiceFor(provider)
+	}
+	return sandbox.NewSearchService()
+}
+
+// tenantSearchProvider returns the search provider named by the tenant's settings, or ""
+func (e *Executor) tenantSearchProvider(ctx context.Context, execCtx *ExecutionContext) string {
+	if e.pool == nil || execCtx == nil || execCtx.Run == nil {
+		return ""
+	}
+
+	execCtx.mu.Lock()
+	defer execCtx.mu.Unlock()
+	if execCtx.searchProvider == nil {
+		var provider string
+		err := e.pool.QueryRow(ctx,
+			`SELECT COALESCE(settings->>'`+tenantSearchProviderSetting+`', '') FROM tenants WHERE id = $1 AND deleted_at IS NULL`,
+			execCtx.Run.TenantID,
+		).Scan(&provider)
+		if err != nil {
+			e.logger.Debug("Failed to load tenant search provider", "error", err)
+			return ""
+		}
+		execCtx.searchProvider = &provider
+	}
+	return *execCtx.searchProvider
+}
@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/souta/ai-orchestration/pkg/crypto"
+)
+
+// MaskedVariableValue replaces the value of secret variables in API responses
+const MaskedVariableValue = "***"
+
+// ErrSecretValueRequired is returned when a secret variable is saved without a value and has no
+// stored value to keep
+var ErrSecretValueRequired = errors.New("secret variable requires a value")
+
+// secretVariable is the form of a variable marked secret. Clients send {"secret": true, "value":
+// "..."}; the value is stored encrypted and read back as the mask.
+type secretVariable struct {
+	Secret    bool                  `json:"secret"`
+	Value     string                `json:"value,omitempty"`
+	Encrypted *crypto.EncryptedData `json:"encrypted,omitempty"`
+}
+
+// asSecretVariable returns the secret form of a variable value, or false when it is a plain value
+func asSecretVariable(value interface{}) (*secretVariable, bool) {
+	obj, ok := value.(map[string]interface{})
+	if !ok || obj["secret"] != true {
+		return nil, false
+	}
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false
+	}
+	var secret secretVariable
+	if err := json.Unmarshal(raw, &secret); err != nil {
+		return nil, false
+	}
+	return &secret, true
+}
+
+// SealVariables prepares variables sent by a client for storage: secret values are encrypted,
+// and a secret sent without a new value (or with the mask) keeps its stored value from current.
+// Plain values are stored as they are.
+func SealVariables(encryptor *crypto.Encryptor, update, current map[string]interface{}) (map[string]interface{}, error) {
+	sealed := make(map[string]interface{}, len(update))
+	for key, value := range update {
+		secret, ok := asSecretVariable(value)
+		if !ok {
+			sealed[key] = value
+			continue
+		}
+
+		if secret.Value == "" || secret.Value == MaskedVariableValue {
+			stored, ok := asSecretVariable(current[key])
+			if !ok || stored.Encrypted == nil {
+				return nil, fmt.Errorf("%w: %s", ErrSecretValueRequired, key)
+			}
+			sealed[key] = map[string]interface{}{"secret": true, "encrypted": stored.Encrypted}
+			continue
+		}
+
+		encrypted, err := encryptor.Encrypt([]byte(secret.Value))
+		if err != nil {
+			return nil, fmt.Errorf("encrypt variable %s: %w", key, err)
+		}
+		sealed[key] = map[string]interface{}{"secret": true, "encrypted": encrypted}
+	}
+	return sealed, nil
+}
+
+// MaskVariables returns stored variables with the values of secret variables replaced by the mask
+func MaskVariables(vars map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(vars))
+	for key, value := range vars {
+		if _, ok := asSecretVariable(value); ok {
+			masked[key] = map[string]interface{}{"secret": true, "value": MaskedVariableValue}
+			continue
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+// OpenVariables returns stored variables with secret variables decrypted to their plain values,
+// for template resolution during a run. Secret variables that cannot be decrypted are left out
+// and reported in the error.
+func OpenVariables(encryptor *crypto.Encryptor, vars map[string]interface{}) (map[string]interface{}, error) {
+	opened := make(map[string]interface{}, len(vars))
+	var errs []error
+	for key, value := range vars {
+		secret, ok := asSecretVariable(value)
+		if !ok {
+			opened[key] = value
+			continue
+		}
+		if encryptor == nil || secret.Encrypted == nil {
+			errs = append(errs, fmt.Errorf("secret variable %s cannot be decrypted", key))
+			continue
+		}
+		plaintext, err := encryptor.Decrypt(secret.Encrypted)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("decrypt variable %s: %w", key, err))
+			continue
+		}
+		opened[key] = string(plaintext)
+	}
+	return opened, errors.Join(errs...)
+}
+
+// secretValues returns the decrypted values of the secret variables in stored, as opened by
+// OpenVariables
+func secretValues(stored, opened map[string]interface{}) []string {
+	var values []string
+	for key, value := range stored {
+		if _, ok := asSecretVariable(value); !ok {
+			continue
+		}
+		if plaintext, ok := opened[key].(string); ok && plaintext != "" {
+			values = append(values, plaintext)
+		}
+	}
+	return values
+}
+
+// redactSecrets replaces the secret values in the strings and keys of a JSON document with the
+// mask. A document that is not valid JSON is redacted as raw text.
+func redactSecrets(data json.RawMessage, secrets []string) json.RawMessage {
+	if len(data) == 0 || len(secrets) == 0 {
+		return data
+	}
+	if !containsSecret(data, secrets) {
+		return data
+	}
+
+	// Longer values first, so a secret containing another one is masked as a whole
+	ordered := append([]string(nil), secrets...)
+	sort.Slice(ordered, func(i, j int) bool { return len(ordered[i]) > len(ordered[j]) })
+	redact := func(s string) string {
+		for _, secret := range ordered {
+			s = strings.ReplaceAll(s, secret, MaskedVariableValue)
+		}
+		return s
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return json.RawMessage(redact(string(data)))
+	}
+	var walk func(value interface{}) interface{}
+	walk = func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case string:
+			return redact(v)
+		case []interface{}:
+			for i := range v {
+				v[i] = walk(v[i])
+			}
+			return v
+		case map[string]interface{}:
+			redacted := make(map[string]interface{}, len(v))
+			for key, item := range v {
+				redacted[redact(key)] = walk(item)
+			}
+			return redacted
+		}
+		return value
+	}
+	redacted, err := json.Marshal(walk(doc))
+	if err != nil {
+		return json.RawMessage(redact(string(data)))
+	}
+	return redacted
+}
+
+// containsSecret reports whether data contains a secret value, as is or JSON-escaped
+func containsSecret(data json.RawMessage, secrets []string) bool {
+	for _, secret := range secrets {
+		if bytes.Contains(data, []byte(secret)) {
+			return true
+		}
+		if escaped, err := json.Marshal(secret); err == nil && bytes.Contains(data, escaped[1:len(escaped)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSecretEncryptor sets the encryptor that decrypts secret organization and personal variables
+func WithSecretEncryptor(encryptor *crypto.Encryptor) ExecutorOption {
+	return func(e *Executor) {
+		e.encryptor = encryptor
+	}
+}
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/souta/ai-orchestration/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncryptor(t *testing.T) *crypto.Encryptor {
+	t.Helper()
+	encryptor, err := crypto.NewEncryptorWithKey(bytes.Repeat([]byte{7}, crypto.KeySize))
+	require.NoError(t, err)
+	return encryptor
+}
+
+// storedVariables round-trips variables through JSON as the JSONB column does
+func storedVariables(t *testing.T, vars map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(vars)
+	require.NoError(t, err)
+	var stored map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &stored))
+	return stored
+}
+
+func TestSecretVariables_MaskedInReadsUsableInRuns(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	sealed, err := SealVariables(encryptor, map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-live-123"},
+		"region":  "us",
+	}, nil)
+	require.NoError(t, err)
+	stored := storedVariables(t, sealed)
+
+	raw, _ := json.Marshal(stored)
+	assert.NotContains(t, string(raw), "sk-live-123", "the secret is encrypted at rest")
+
+	masked := MaskVariables(stored)
+	assert.Equal(t, map[string]interface{}{"secret": true, "value": MaskedVariableValue}, masked["api_key"])
+	assert.Equal(t, "us", masked["region"])
+
+	opened, err := OpenVariables(encryptor, stored)
+	require.NoError(t, err)
+	expanded, err := ExpandConfigTemplatesWithScopes(
+		json.RawMessage(`{"headers": {"Authorization": "Bearer {{$org.api_key}}"}, "region": "{{region}}"}`),
+		nil,
+		&ScopedVariables{Org: opened},
+	)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"headers": {"Authorization": "Bearer sk-live-123"}, "region": "us"}`, string(expanded))
+}
+
+func TestSealVariables_KeepsStoredSecretWithoutNewValue(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	sealed, err := SealVariables(encryptor, map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-old"},
+	}, nil)
+	require.NoError(t, err)
+	current := storedVariables(t, sealed)
+
+	// Sending back what a read returned keeps the stored value
+	resealed, err := SealVariables(encryptor, MaskVariables(current), current)
+	require.NoError(t, err)
+	opened, err := OpenVariables(encryptor, storedVariables(t, resealed))
+	require.NoError(t, err)
+	assert.Equal(t, "sk-old", opened["api_key"])
+
+	// A new value replaces it
+	replaced, err := SealVariables(encryptor, map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-new"},
+	}, current)
+	require.NoError(t, err)
+	opened, err = OpenVariables(encryptor, storedVariables(t, replaced))
+	require.NoError(t, err)
+	assert.Equal(t, "sk-new", opened["api_key"])
+}
+
+func TestSealVariables_NewSecretRequiresValue(t *testing.T) {
+	_, err := SealVariables(newTestEncryptor(t), map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": MaskedVariableValue},
+	}, map[string]interface{}{"api_key": "plain"})
+
+	assert.ErrorIs(t, err, ErrSecretValueRequired)
+	assert.ErrorContains(t, err, "api_key")
+}
+
+func TestOpenVariables_WithoutEncryptorLeavesSecretsOut(t *testing.T) {
+	sealed, err := SealVariables(newTestEncryptor(t), map[string]interface{}{
+		"api_key": map[string]interface{}{"secret": true, "value": "sk-live-123"},
+		"region":  "us",
+	}, nil)
+	require.NoError(t, err)
+
+	opened, err := OpenVariables(nil, storedVariables(t, sealed))
+	assert.Error(t, err)
+	assert.Equal(t, map[string]interface{}{"region": "us"}, opened)
+}
+
+func TestRedactSecrets(t *testing.T) {
+	secrets := []string{"sk-live-123", "sk-live-123-extended", `pa"ss`}
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no secret keeps the document as is", `{"b": 1,  "a": "x"}`, `{"b": 1,  "a": "x"}`},
+		{"nested strings", `{"headers": {"Authorization": "Bearer sk-live-123"}, "list": ["sk-live-123", 2]}`,
+			`{"headers": {"Authorization": "Bearer ***"}, "list": ["***", 2]}`},
+		{"longer secret masked as a whole", `{"key": "sk-live-123-extended"}`, `{"key": "***"}`},
+		{"escaped secret", `{"password": "pa\"ss"}`, `{"password": "***"}`},
+		{"keys", `{"sk-live-123": true}`, `{"***": true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactSecrets(json.RawMessage(tt.data), secrets)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	assert.Equal(t, "token sk-live-123 ***", string(redactSecrets(json.RawMessage("token sk-live-123 ***"), nil)))
+	assert.Equal(t, "token ***", string(redactSecrets(json.RawMessage("token sk-live-123"), secrets)), "invalid JSON is redacted as text")
+}
@@ -2,14 +2,23 @@ package engine
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
 )
 
+// templateNow returns the current time for date variables (replaced in tests)
+var templateNow = time.Now
+
 // ScopedVariables holds variables for different scopes
 type ScopedVariables struct {
 	Org      map[string]interface{} // Organization (tenant) variables - {{$org.xxx}}
 	Project  map[string]interface{} // Project variables - {{$project.xxx}}
 	Personal map[string]interface{} // Personal (user) variables - {{$personal.xxx}}
+	Trigger  map[string]interface{} // How the run was triggered (domain.Run.TriggerInfo) - {{$trigger.xxx}}
+	Secrets  []string               // Decrypted values of secret variables, redacted from persisted and streamed step data
 }
 
 // ExpandConfigTemplates expands all template variables in config using values from input.
@@ -28,12 +37,17 @@ func ExpandConfigTemplates(config json.RawMessage, input json.RawMessage) (json.
 
 // ExpandConfigTemplatesWithScopes expands template variables including scoped variables.
 // Supported syntax:
-//   - {{field}} - top-level key from input
+//   - {{field}} - top-level key from input, falling back to project, personal and organization
+//     variables in that order (see VariableResolver)
 //   - {{$.field}} - JSONPath syntax (for compatibility)
 //   - {{$org.field}} - organization (tenant) variables
 //   - {{$project.field}} - project variables
 //   - {{$personal.field}} - personal (user) variables
 //   - {{$input.field}} - explicit input reference (same as {{field}})
+//   - {{$trigger.field}} - how the run was triggered: type, triggered_by, timestamp, source and
+//     trigger metadata such as schedule_id; {{$trigger}} is the whole object
+//   - {{$now}}, {{$today}}, {{$timezone}} - current time (RFC 3339), current date (YYYY-MM-DD)
+//     and timezone name, in the deployment default timezone (DEFAULT_TIMEZONE)
 //   - {{nested.field}} - nested path from input
 func ExpandConfigTemplatesWithScopes(config json.RawMessage, input json.RawMessage, scopes *ScopedVariables) (json.RawMessage, error) {
 	if len(config) == 0 {
@@ -205,7 +219,7 @@ func expandValueWithScopes(value interface{}, inputData map[string]interface{},
 }
 
 // expandStringWithScopes expands template variables in a string with scope support.
-// Supported scopes: $org, $project, $personal, $input
+// Supported scopes: $org, $project, $personal, $input, $trigger
 func expandStringWithScopes(s string, inputData map[string]interface{}, scopes *ScopedVariables) interface{} {
 	// Check if the entire string is a single template variable
 	trimmed := strings.TrimSpace(s)
@@ -258,8 +272,8 @@ func expandStringWithScopes(s string, inputData map[string]interface{}, scopes *
 }
 
 // extractPathWithScopes extracts a value using scope-aware path resolution.
-// Supported prefixes: $org., $project., $personal., $input.
-// Without prefix, defaults to input data.
+// Supported prefixes: $org., $project., $personal., $input., $trigger.
+// Without prefix, the path is resolved by precedence: input, project, personal, organization.
 func extractPathWithScopes(path string, inputData map[string]interface{}, scopes *ScopedVariables) interface{} {
 	// Remove leading $ for standard JSONPath compatibility
 	path = strings.TrimPrefix(path, "$.")
@@ -290,8 +304,148 @@ func extractPathWithScopes(path string, inputData map[string]interface{}, scopes
 		subPath := strings.TrimPrefix(path, "$input.")
 		return extractPath(inputData, subPath)
 	}
+	if path == "$trigger" || strings.HasPrefix(path, "$trigger.") {
+		if scopes == nil || scopes.Trigger == nil {
+			return nil
+		}
+		return extractPath(scopes.Trigger, strings.TrimPrefix(path, "$trigger"))
+	}
+	if value, ok := dateVariable(path); ok {
+		return value
+	}
 
-	// No scope prefix - default to input data
-	path = strings.TrimPrefix(path, "$")
-	return extractPath(inputData, path)
+	// No scope prefix - resolve by precedence, starting with input data
+	value, _ := NewVariableResolver(scopes).Resolve(path, inputData)
+	return value
+}
+
+// dateVariable resolves the built-in date variables, rendered in the deployment default timezone
+func dateVariable(path string) (string, bool) {
+	switch path {
+	case "$now":
+		return templateNow().In(domain.DefaultLocation()).Format(time.RFC3339), true
+	case "$today":
+		return templateNow().In(domain.DefaultLocation()).Format("2006-01-02"), true
+	case "$timezone":
+		return domain.DefaultTimezone(), true
+	}
+	return "", false
+}
+
+// FindUnresolvedTemplatePaths returns the template variable paths in config that do not
+// resolve against input and scopes, without duplicates. Object keys are visited in sorted
+// order so the result is deterministic.
+// These are the references ExpandConfigTemplatesWithScopes would replace with an empty value.
+func FindUnresolvedTemplatePaths(config json.RawMessage, input json.RawMessage, scopes *ScopedVariables) ([]string, error) {
+	missing := []string{}
+	if len(config) == 0 {
+		return missing, nil
+	}
+
+	inputData := make(map[string]interface{})
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &inputData); err != nil || inputData == nil {
+			inputData = make(map[string]interface{})
+		}
+	}
+	if scopes == nil {
+		scopes = &ScopedVariables{}
+	}
+
+	var configData interface{}
+	if err := json.Unmarshal(config, &configData); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			for _, path := range templatePaths(v) {
+				if !seen[path] && extractPathWithScopes(path, inputData, scopes) == nil {
+					seen[path] = true
+					missing = append(missing, path)
+				}
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key])
+			}
+		case []interface{}:
+			for _, val := range v {
+				walk(val)
+			}
+		}
+	}
+	walk(configData)
+
+	return missing, nil
+}
+
+// TemplateReferences returns the template variable paths referenced anywhere in config, without
+// duplicates. Object keys are visited in sorted order so the result is deterministic.
+func TemplateReferences(config json.RawMessage) ([]string, error) {
+	refs := []string{}
+	if len(config) == 0 {
+		return refs, nil
+	}
+	var configData interface{}
+	if err := json.Unmarshal(config, &configData); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			for _, path := range templatePaths(v) {
+				if !seen[path] {
+					seen[path] = true
+					refs = append(refs, path)
+				}
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key])
+			}
+		case []interface{}:
+			for _, val := range v {
+				walk(val)
+			}
+		}
+	}
+	walk(configData)
+
+	return refs, nil
+}
+
+// templatePaths returns the trimmed paths of all {{...}} variables in s
+func templatePaths(s string) []string {
+	var paths []string
+	for {
+		start := strings.Index(s, "{{")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(s[start:], "}}")
+		if end == -1 {
+			break
+		}
+		end += start
+		paths = append(paths, strings.TrimSpace(s[start+2:end]))
+		s = s[end+2:]
+	}
+	return paths
 }
@@ -3,6 +3,10 @@ package engine
 import (
 	"encoding/json"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
 )
 
 func TestExpandConfigTemplates(t *testing.T) {
@@ -336,3 +340,147 @@ func TestExtractPath(t *testing.T) {
 		})
 	}
 }
+
+func TestFindUnresolvedTemplatePaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		input    string
+		scopes   *ScopedVariables
+		expected []string
+	}{
+		{
+			name:     "all paths resolve",
+			config:   `{"to": "{{user.email}}", "subject": "Hi {{$.user.name}}"}`,
+			input:    `{"user": {"email": "a@example.com", "name": "Ann"}}`,
+			expected: []string{},
+		},
+		{
+			name:     "missing nested key",
+			config:   `{"to": "{{user.mail}}", "body": "{{user.name}}"}`,
+			input:    `{"user": {"name": "Ann"}}`,
+			expected: []string{"user.mail"},
+		},
+		{
+			name:     "duplicates reported once, keys in sorted order",
+			config:   `{"b": "{{x}} and {{x}}", "a": ["{{y}}", {"c": "{{x}}"}]}`,
+			input:    `{}`,
+			expected: []string{"y", "x"},
+		},
+		{
+			name:     "scoped variable without scope",
+			config:   `{"url": "{{$org.base_url}}/items"}`,
+			input:    `{}`,
+			expected: []string{"$org.base_url"},
+		},
+		{
+			name:     "scoped variable resolves",
+			config:   `{"url": "{{$org.base_url}}/items"}`,
+			input:    `{}`,
+			scopes:   &ScopedVariables{Org: map[string]interface{}{"base_url": "https://api"}},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindUnresolvedTemplatePaths(json.RawMessage(tt.config), json.RawMessage(tt.input), tt.scopes)
+			if err != nil {
+				t.Fatalf("FindUnresolvedTemplatePaths() error = %v", err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("got %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("got %v, want %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandConfigTemplates_DateVariables(t *testing.T) {
+	originalNow := templateNow
+	templateNow = func() time.Time { return time.Date(2026, 3, 1, 20, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() {
+		templateNow = originalNow
+		_ = domain.SetDefaultTimezone("")
+	})
+
+	config := `{"subject": "Report for {{$today}}", "at": "{{$now}}", "tz": "{{$timezone}}"}`
+
+	got, err := ExpandConfigTemplates(json.RawMessage(config), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ExpandConfigTemplates() error = %v", err)
+	}
+	if string(got) != `{"at":"2026-03-01T20:30:00Z","subject":"Report for 2026-03-01","tz":"UTC"}` {
+		t.Errorf("got %s", got)
+	}
+
+	// 20:30 UTC is already the next day in Tokyo
+	if err := domain.SetDefaultTimezone("Asia/Tokyo"); err != nil {
+		t.Fatalf("SetDefaultTimezone() error = %v", err)
+	}
+	got, err = ExpandConfigTemplates(json.RawMessage(config), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ExpandConfigTemplates() error = %v", err)
+	}
+	if string(got) != `{"at":"2026-03-02T05:30:00+09:00","subject":"Report for 2026-03-02","tz":"Asia/Tokyo"}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestExpandConfigTemplates_TriggerVariables(t *testing.T) {
+	userID := uuid.New()
+	scheduleID := uuid.New()
+	createdAt := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	config := `{"type": "{{$trigger.type}}", "by": "{{$trigger.triggered_by}}", "at": "{{$trigger.timestamp}}", "note": "via {{$trigger.type}} ({{$trigger.schedule_id}})"}`
+
+	newRun := func(triggerType domain.TriggerType) *domain.Run {
+		run := domain.NewRun(uuid.New(), uuid.New(), 1, json.RawMessage(`{}`), triggerType)
+		run.CreatedAt = createdAt
+		return run
+	}
+	manual := newRun(domain.TriggerTypeManual)
+	manual.TriggeredByUser = &userID
+	schedule := newRun(domain.TriggerTypeSchedule)
+	if err := schedule.SetTriggerSource("schedule", map[string]interface{}{"schedule_id": scheduleID.String()}); err != nil {
+		t.Fatalf("SetTriggerSource() error = %v", err)
+	}
+	webhook := newRun(domain.TriggerTypeWebhook)
+	if err := webhook.SetTriggerSource("webhook", map[string]interface{}{"remote_ip": "203.0.113.7"}); err != nil {
+		t.Fatalf("SetTriggerSource() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		run      *domain.Run
+		expected string
+	}{
+		{"manual", manual, `{"at":"2026-10-14T09:00:00Z","by":"` + userID.String() + `","note":"via manual ()","type":"manual"}`},
+		{"schedule", schedule, `{"at":"2026-10-14T09:00:00Z","by":"","note":"via schedule (` + scheduleID.String() + `)","type":"schedule"}`},
+		{"webhook", webhook, `{"at":"2026-10-14T09:00:00Z","by":"","note":"via webhook ()","type":"webhook"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandConfigTemplatesWithScopes(json.RawMessage(config), json.RawMessage(`{}`), &ScopedVariables{Trigger: tt.run.TriggerInfo()})
+			if err != nil {
+				t.Fatalf("ExpandConfigTemplatesWithScopes() error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+
+	// The whole object keeps its type, and metadata appears next to the fixed keys
+	got, err := ExpandConfigTemplatesWithScopes(json.RawMessage(`{"trigger": "{{$trigger}}"}`), json.RawMessage(`{}`), &ScopedVariables{Trigger: webhook.TriggerInfo()})
+	if err != nil {
+		t.Fatalf("ExpandConfigTemplatesWithScopes() error = %v", err)
+	}
+	expected := `{"trigger":{"remote_ip":"203.0.113.7","source":"webhook","timestamp":"2026-10-14T09:00:00Z","triggered_by":null,"type":"webhook"}}`
+	if string(got) != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
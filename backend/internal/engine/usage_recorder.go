@@ -2,8 +2,11 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -14,6 +17,13 @@ import (
 type UsageRecorder struct {
 	repo   repository.UsageRepository
 	logger *slog.Logger
+
+	// Budget alerts, enabled by WithBudgetAlerts
+	budgetRepo repository.BudgetRepository
+	notifier   Notifier
+	alertMu    sync.Mutex
+	alerted    map[uuid.UUID]string // Budget ID -> period already alerted in this process
+	now        func() time.Time
 }
 
 // NewUsageRecorder creates a new UsageRecorder
@@ -22,11 +32,22 @@ func NewUsageRecorder(repo repository.UsageRepository, logger *slog.Logger) *Usa
 		logger = slog.Default()
 	}
 	return &UsageRecorder{
-		repo:   repo,
-		logger: logger,
+		repo:    repo,
+		logger:  logger,
+		alerted: make(map[uuid.UUID]string),
+		now:     time.Now,
 	}
 }
 
+// WithBudgetAlerts makes the recorder notify the tenant when recorded usage brings the spend of a
+// tenant or workflow budget past its alert threshold. Each budget alerts once per budget period
+// (day or month) and process.
+func (r *UsageRecorder) WithBudgetAlerts(budgetRepo repository.BudgetRepository, notifier Notifier) *UsageRecorder {
+	r.budgetRepo = budgetRepo
+	r.notifier = notifier
+	return r
+}
+
 // RecordParams contains parameters for recording usage
 type RecordParams struct {
 	TenantID     uuid.UUID
@@ -89,9 +110,96 @@ func (r *UsageRecorder) Record(ctx context.Context, params RecordParams) error {
 		"cost_usd", record.TotalCostUSD,
 	)
 
+	r.checkBudgets(ctx, params.TenantID, params.ProjectID)
 	return nil
 }
 
+// checkBudgets sends a budget alert for each enabled budget of the tenant, and of the workflow
+// when set, whose current spend has reached its alert threshold
+func (r *UsageRecorder) checkBudgets(ctx context.Context, tenantID uuid.UUID, projectID *uuid.UUID) {
+	if r.budgetRepo == nil || r.notifier == nil {
+		return
+	}
+	scopes := []*uuid.UUID{nil}
+	if projectID != nil {
+		scopes = append(scopes, projectID)
+	}
+	for _, scope := range scopes {
+		for _, budgetType := range []domain.BudgetType{domain.BudgetTypeDaily, domain.BudgetTypeMonthly} {
+			budget, err := r.budgetRepo.GetByProject(ctx, tenantID, scope, budgetType)
+			if err != nil {
+				r.logger.Warn("Failed to load budget for alert check", "tenant_id", tenantID, "error", err)
+				continue
+			}
+			if budget == nil || !budget.Enabled || budget.BudgetAmountUSD <= 0 {
+				continue
+			}
+			r.checkBudget(ctx, budget)
+		}
+	}
+}
+
+// checkBudget alerts on a budget past its threshold, unless it already alerted for the period
+func (r *UsageRecorder) checkBudget(ctx context.Context, budget *domain.UsageBudget) {
+	period := budgetPeriod(budget.BudgetType, r.now())
+	r.alertMu.Lock()
+	alreadyAlerted := r.alerted[budget.ID] == period
+	r.alertMu.Unlock()
+	if alreadyAlerted {
+		return
+	}
+
+	spend, err := r.repo.GetCurrentSpend(ctx, budget.TenantID, budget.ProjectID, budget.BudgetType)
+	if err != nil {
+		r.logger.Warn("Failed to load spend for budget alert check", "budget_id", budget.ID, "error", err)
+		return
+	}
+	consumed := spend / budget.BudgetAmountUSD
+	if consumed < budget.AlertThreshold {
+		return
+	}
+
+	r.alertMu.Lock()
+	if r.alerted[budget.ID] == period {
+		r.alertMu.Unlock()
+		return
+	}
+	r.alerted[budget.ID] = period
+	r.alertMu.Unlock()
+
+	scope := "tenant"
+	data := map[string]interface{}{
+		"budget_id":         budget.ID,
+		"budget_type":       budget.BudgetType,
+		"budget_amount_usd": budget.BudgetAmountUSD,
+		"current_spend_usd": spend,
+		"consumed_percent":  consumed * 100,
+		"alert_threshold":   budget.AlertThreshold,
+	}
+	if budget.ProjectID != nil {
+		scope = "workflow"
+		data["project_id"] = *budget.ProjectID
+	}
+	err = r.notifier.Notify(ctx, budget.TenantID, domain.Notification{
+		Event: domain.NotificationEventBudgetAlert,
+		Title: fmt.Sprintf("%s %s budget at %.0f%%", budget.BudgetType, scope, consumed*100),
+		Message: fmt.Sprintf("Spend of $%.2f has reached %.0f%% of the %s budget of $%.2f (alert threshold %.0f%%).",
+			spend, consumed*100, budget.BudgetType, budget.BudgetAmountUSD, budget.AlertThreshold*100),
+		Data: data,
+	})
+	if err != nil {
+		r.logger.Warn("Failed to send budget alert", "budget_id", budget.ID, "error", err)
+	}
+}
+
+// budgetPeriod identifies the current period of a budget type, e.g. "2026-10-14" or "2026-10"
+func budgetPeriod(budgetType domain.BudgetType, now time.Time) string {
+	if budgetType == domain.BudgetTypeDaily {
+		return now.UTC().Format("2006-01-02")
+	}
+	return now.UTC().Format("2006-01")
+}
+
 // RecordFromMetadata records usage from adapter response metadata
 func (r *UsageRecorder) RecordFromMetadata(
 	ctx context.Context,
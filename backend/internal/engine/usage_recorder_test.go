@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spendUsageRepo reports a fixed current spend
+type spendUsageRepo struct {
+	recordingUsageRepo
+	spend float64
+}
+
+func (r *spendUsageRepo) GetCurrentSpend(ctx context.Context, tenantID uuid.UUID, projectID *uuid.UUID, budgetType domain.BudgetType) (float64, error) {
+	return r.spend, nil
+}
+
+// monthlyBudgetRepo has one monthly tenant budget
+type monthlyBudgetRepo struct {
+	repository.BudgetRepository
+	budget *domain.UsageBudget
+}
+
+func (r *monthlyBudgetRepo) GetByProject(ctx context.Context, tenantID uuid.UUID, projectID *uuid.UUID, budgetType domain.BudgetType) (*domain.UsageBudget, error) {
+	if projectID == nil && budgetType == domain.BudgetTypeMonthly {
+		return r.budget, nil
+	}
+	return nil, nil
+}
+
+// recordingNotifier captures notifications
+type recordingNotifier struct {
+	notifications []domain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, tenantID uuid.UUID, notification domain.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestUsageRecorder_BudgetAlertOncePerPeriod(t *testing.T) {
+	tenantID := uuid.New()
+	usage := &spendUsageRepo{spend: 70}
+	budget := domain.NewUsageBudget(tenantID, nil, domain.BudgetTypeMonthly, 100, 0.8)
+	notifier := &recordingNotifier{}
+	recorder := NewUsageRecorder(usage, nil).WithBudgetAlerts(&monthlyBudgetRepo{budget: budget}, notifier)
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+	record := func() {
+		require.NoError(t, recorder.Record(context.Background(), RecordParams{TenantID: tenantID, Provider: "openai", Model: "gpt-4o", InputTokens: 10}))
+	}
+
+	record()
+	assert.Empty(t, notifier.notifications, "70% of the budget is below the threshold")
+
+	usage.spend = 85
+	record()
+	record()
+	require.Len(t, notifier.notifications, 1, "the budget alerts once per period")
+	alert := notifier.notifications[0]
+	assert.Equal(t, domain.NotificationEventBudgetAlert, alert.Event)
+	assert.Equal(t, budget.ID, alert.Data["budget_id"])
+	assert.InDelta(t, 85.0, alert.Data["consumed_percent"], 0.001)
+
+	now = now.AddDate(0, 1, 0)
+	record()
+	assert.Len(t, notifier.notifications, 2, "a new month alerts again")
+}
@@ -0,0 +1,72 @@
+package engine
+
+import "strings"
+
+// Variable sources, from the highest precedence to the lowest
+const (
+	VariableSourceInput    = "input"
+	VariableSourceProject  = "project"
+	VariableSourcePersonal = "personal"
+	VariableSourceOrg      = "org"
+)
+
+// VariableResolver resolves template variables without a scope prefix. The same key may exist
+// at several levels; the value is taken from the step input first, then the project, personal
+// (user) and organization (tenant) variables.
+type VariableResolver struct {
+	scopes *ScopedVariables
+}
+
+// NewVariableResolver creates a resolver over the scoped variables of a run
+func NewVariableResolver(scopes *ScopedVariables) *VariableResolver {
+	if scopes == nil {
+		scopes = &ScopedVariables{}
+	}
+	return &VariableResolver{scopes: scopes}
+}
+
+// Resolve returns the value of an unprefixed path and the source it came from, or nil and ""
+// when no level defines it
+func (r *VariableResolver) Resolve(path string, inputData map[string]interface{}) (interface{}, string) {
+	path = strings.TrimPrefix(path, "$")
+	for _, level := range r.levels(inputData) {
+		if level.vars == nil {
+			continue
+		}
+		if value := extractPath(level.vars, path); value != nil {
+			return value, level.source
+		}
+	}
+	return nil, ""
+}
+
+// Merged returns the effective top-level variables without step input, and the source of each
+// key
+func (r *VariableResolver) Merged() (map[string]interface{}, map[string]string) {
+	merged := make(map[string]interface{})
+	sources := make(map[string]string)
+	levels := r.levels(nil)
+	// Apply the lowest precedence first so higher levels overwrite it
+	for i := len(levels) - 1; i >= 0; i-- {
+		for key, value := range levels[i].vars {
+			merged[key] = value
+			sources[key] = levels[i].source
+		}
+	}
+	return merged, sources
+}
+
+type variableLevel struct {
+	source string
+	vars   map[string]interface{}
+}
+
+// levels returns the variable levels in precedence order
+func (r *VariableResolver) levels(inputData map[string]interface{}) []variableLevel {
+	return []variableLevel{
+		{VariableSourceInput, inputData},
+		{VariableSourceProject, r.scopes.Project},
+		{VariableSourcePersonal, r.scopes.Personal},
+		{VariableSourceOrg, r.scopes.Org},
+	}
+}
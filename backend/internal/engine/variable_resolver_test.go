@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVariableResolver_Precedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      map[string]interface{}
+		scopes     *ScopedVariables
+		wantValue  interface{}
+		wantSource string
+	}{
+		{
+			name:       "org only",
+			scopes:     &ScopedVariables{Org: map[string]interface{}{"region": "org"}},
+			wantValue:  "org",
+			wantSource: VariableSourceOrg,
+		},
+		{
+			name: "personal overrides org",
+			scopes: &ScopedVariables{
+				Org:      map[string]interface{}{"region": "org"},
+				Personal: map[string]interface{}{"region": "personal"},
+			},
+			wantValue:  "personal",
+			wantSource: VariableSourcePersonal,
+		},
+		{
+			name: "project overrides personal and org",
+			scopes: &ScopedVariables{
+				Org:      map[string]interface{}{"region": "org"},
+				Personal: map[string]interface{}{"region": "personal"},
+				Project:  map[string]interface{}{"region": "project"},
+			},
+			wantValue:  "project",
+			wantSource: VariableSourceProject,
+		},
+		{
+			name:  "step input overrides every scope",
+			input: map[string]interface{}{"region": "input"},
+			scopes: &ScopedVariables{
+				Org:      map[string]interface{}{"region": "org"},
+				Personal: map[string]interface{}{"region": "personal"},
+				Project:  map[string]interface{}{"region": "project"},
+			},
+			wantValue:  "input",
+			wantSource: VariableSourceInput,
+		},
+		{
+			name:   "undefined",
+			scopes: &ScopedVariables{Org: map[string]interface{}{"other": "org"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, source := NewVariableResolver(tt.scopes).Resolve("region", tt.input)
+			if value != tt.wantValue {
+				t.Errorf("Resolve() value = %v, want %v", value, tt.wantValue)
+			}
+			if source != tt.wantSource {
+				t.Errorf("Resolve() source = %q, want %q", source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestVariableResolver_Merged(t *testing.T) {
+	resolver := NewVariableResolver(&ScopedVariables{
+		Org:      map[string]interface{}{"region": "org", "api_url": "org", "team": "org"},
+		Personal: map[string]interface{}{"api_url": "personal", "team": "personal"},
+		Project:  map[string]interface{}{"team": "project"},
+	})
+
+	variables, sources := resolver.Merged()
+
+	want := map[string]string{"region": "org", "api_url": "personal", "team": "project"}
+	for key, source := range want {
+		if variables[key] != source {
+			t.Errorf("variables[%q] = %v, want %q", key, variables[key], source)
+		}
+		if sources[key] != source {
+			t.Errorf("sources[%q] = %q, want %q", key, sources[key], source)
+		}
+	}
+	if len(variables) != len(want) {
+		t.Errorf("got %d variables, want %d", len(variables), len(want))
+	}
+}
+
+func TestExpandConfigTemplatesWithScopes_UnprefixedPrecedence(t *testing.T) {
+	scopes := &ScopedVariables{
+		Org:     map[string]interface{}{"model": "gpt-4o-mini", "region": "us"},
+		Project: map[string]interface{}{"model": "gpt-4o"},
+	}
+	config := json.RawMessage(`{"model": "{{model}}", "prompt": "Region {{region}}, topic {{topic}}", "org": "{{$org.model}}"}`)
+
+	result, err := ExpandConfigTemplatesWithScopes(config, json.RawMessage(`{"topic": "billing"}`), scopes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(result, &got); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if got["model"] != "gpt-4o" {
+		t.Errorf("model = %v, want project value", got["model"])
+	}
+	if got["prompt"] != "Region us, topic billing" {
+		t.Errorf("prompt = %v", got["prompt"])
+	}
+	if got["org"] != "gpt-4o-mini" {
+		t.Errorf("explicit $org prefix = %v, want org value", got["org"])
+	}
+}
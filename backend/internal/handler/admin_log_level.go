@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/logging"
+)
+
+// AdminLogLevelHandler handles runtime log level changes (operator-only)
+type AdminLogLevelHandler struct {
+	level *slog.LevelVar
+}
+
+// NewAdminLogLevelHandler creates a new AdminLogLevelHandler.
+// level must be the LevelVar the process logger was built with.
+func NewAdminLogLevelHandler(level *slog.LevelVar) *AdminLogLevelHandler {
+	return &AdminLogLevelHandler{level: level}
+}
+
+// SetLogLevelRequest represents the request body for changing the log level
+type SetLogLevelRequest struct {
+	Level string `json:"level"` // debug, info, warn, error
+}
+
+// LogLevelResponse represents the current log level
+type LogLevelResponse struct {
+	Level    string `json:"level"`
+	Previous string `json:"previous,omitempty"`
+}
+
+// Get handles GET /api/v1/admin/log-level
+func (h *AdminLogLevelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	JSONData(w, http.StatusOK, LogLevelResponse{Level: strings.ToLower(h.level.Level().String())})
+}
+
+// Set handles POST /api/v1/admin/log-level
+func (h *AdminLogLevelHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req SetLogLevelRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Level) == "" {
+		HandleErrorL(w, r, domain.NewValidationError("level", "level is required"))
+		return
+	}
+
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		HandleErrorL(w, r, domain.NewValidationError("level", err.Error()))
+		return
+	}
+
+	previous := h.level.Level()
+	h.level.Set(level)
+
+	// Logged at warn so the change is visible regardless of the new level
+	slog.Warn("log level changed at runtime",
+		"previous", previous.String(),
+		"level", level.String(),
+		"user_id", getUserID(r),
+	)
+
+	JSONData(w, http.StatusOK, LogLevelResponse{
+		Level:    strings.ToLower(level.String()),
+		Previous: strings.ToLower(previous.String()),
+	})
+}
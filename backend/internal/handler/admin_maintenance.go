@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/middleware"
+)
+
+// AdminMaintenanceHandler toggles the read-only maintenance mode (operator-only)
+type AdminMaintenanceHandler struct {
+	maintenance *middleware.MaintenanceMode
+}
+
+// NewAdminMaintenanceHandler creates a new AdminMaintenanceHandler
+func NewAdminMaintenanceHandler(maintenance *middleware.MaintenanceMode) *AdminMaintenanceHandler {
+	return &AdminMaintenanceHandler{maintenance: maintenance}
+}
+
+// SetMaintenanceRequest represents the request body for toggling maintenance mode
+type SetMaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"` // Optional message returned to blocked requests
+}
+
+// Get handles GET /api/v1/admin/maintenance
+func (h *AdminMaintenanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	JSONData(w, http.StatusOK, h.maintenance.State(r.Context()))
+}
+
+// Set handles POST /api/v1/admin/maintenance
+func (h *AdminMaintenanceHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req SetMaintenanceRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	state := middleware.MaintenanceState{Enabled: req.Enabled}
+	if req.Enabled {
+		now := time.Now().UTC()
+		state.Message = req.Message
+		state.EnabledAt = &now
+		state.EnabledBy = getUserID(r).String()
+	}
+	if err := h.maintenance.Set(r.Context(), state); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	// Logged at warn so the change is visible regardless of the log level
+	slog.Warn("maintenance mode changed",
+		"enabled", state.Enabled,
+		"message", state.Message,
+		"user_id", getUserID(r),
+	)
+
+	JSONData(w, http.StatusOK, state)
+}
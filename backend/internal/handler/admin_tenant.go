@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/pkg/netguard"
 )
 
 // AdminTenantHandler handles HTTP requests for tenant management (operator-only)
@@ -35,6 +36,8 @@ type CreateTenantRequest struct {
 	Metadata     *domain.TenantMetadata    `json:"metadata,omitempty"`
 	FeatureFlags *domain.TenantFeatureFlags `json:"feature_flags,omitempty"`
 	Limits       *domain.TenantLimits      `json:"limits,omitempty"`
+	Egress       *domain.TenantEgressPolicy `json:"egress,omitempty"`
+	Endpoints    map[string]domain.EndpointOverride `json:"endpoints,omitempty"` // Provider endpoint overrides, e.g. {"openai": {"region": "eu"}}
 }
 
 // UpdateTenantRequest represents the request body for updating a tenant
@@ -48,6 +51,8 @@ type UpdateTenantRequest struct {
 	Metadata     *domain.TenantMetadata    `json:"metadata,omitempty"`
 	FeatureFlags *domain.TenantFeatureFlags `json:"feature_flags,omitempty"`
 	Limits       *domain.TenantLimits      `json:"limits,omitempty"`
+	Egress       *domain.TenantEgressPolicy `json:"egress,omitempty"`
+	Endpoints    map[string]domain.EndpointOverride `json:"endpoints,omitempty"` // Provider endpoint overrides, e.g. {"openai": {"region": "eu"}}
 }
 
 // SuspendTenantRequest represents the request body for suspending a tenant
@@ -237,6 +242,14 @@ func (h *AdminTenantHandler) Create(w http.ResponseWriter, r *http.Request) {
 		tenant.Limits = limitsJSON
 	}
 
+	// Apply egress policy if provided
+	if req.Egress != nil && !h.applyEgressPolicy(w, tenant, *req.Egress) {
+		return
+	}
+	if req.Endpoints != nil && !h.applyEndpoints(w, tenant, req.Endpoints) {
+		return
+	}
+
 	// Apply custom metadata if provided
 	if req.Metadata != nil {
 		metadataJSON, err := json.Marshal(req.Metadata)
@@ -358,6 +371,12 @@ func (h *AdminTenantHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 		tenant.Limits = limitsJSON
 	}
+	if req.Egress != nil && !h.applyEgressPolicy(w, tenant, *req.Egress) {
+		return
+	}
+	if req.Endpoints != nil && !h.applyEndpoints(w, tenant, req.Endpoints) {
+		return
+	}
 	if req.Metadata != nil {
 		metadataJSON, err := json.Marshal(req.Metadata)
 		if err != nil {
@@ -385,6 +404,40 @@ func (h *AdminTenantHandler) Update(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, h.toResponse(tenant, stats))
 }
 
+// applyEgressPolicy validates and normalizes the policy's domains and stores it in the tenant's
+// settings. It writes the error response and returns false on failure.
+func (h *AdminTenantHandler) applyEgressPolicy(w http.ResponseWriter, tenant *domain.Tenant, policy domain.TenantEgressPolicy) bool {
+	var err error
+	if policy.AllowedDomains, err = netguard.ParseDomains(policy.AllowedDomains); err == nil {
+		policy.DeniedDomains, err = netguard.ParseDomains(policy.DeniedDomains)
+	}
+	if err != nil {
+		Error(w, http.StatusBadRequest, "INVALID_EGRESS_POLICY", err.Error(), nil)
+		return false
+	}
+	if err := tenant.SetEgressPolicy(policy); err != nil {
+		slog.Error("failed to store egress policy", "error", err, "tenant_id", tenant.ID)
+		Error(w, http.StatusInternalServerError, "MARSHAL_ERROR", "Failed to serialize egress policy", nil)
+		return false
+	}
+	return true
+}
+
+// applyEndpoints validates the provider endpoint overrides and stores them in the tenant's
+// settings. It writes the error response and returns false on failure.
+func (h *AdminTenantHandler) applyEndpoints(w http.ResponseWriter, tenant *domain.Tenant, endpoints map[string]domain.EndpointOverride) bool {
+	if err := domain.ValidateEndpoints(endpoints); err != nil {
+		Error(w, http.StatusBadRequest, "INVALID_ENDPOINTS", err.Error(), nil)
+		return false
+	}
+	if err := tenant.SetEndpoints(endpoints); err != nil {
+		slog.Error("failed to store endpoints", "error", err, "tenant_id", tenant.ID)
+		Error(w, http.StatusInternalServerError, "MARSHAL_ERROR", "Failed to serialize endpoints", nil)
+		return false
+	}
+	return true
+}
+
 // Delete soft-deletes a tenant
 func (h *AdminTenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "tenant_id")
@@ -26,6 +26,7 @@ func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	input := usecase.ListAuditLogsInput{
 		TenantID: tenantID,
+		Cursor:   r.URL.Query().Get("cursor"),
 		Page:     parseIntQuery(r, "page", 1),
 		Limit:    parseIntQuery(r, "limit", 50),
 	}
@@ -71,7 +72,7 @@ func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	JSONList(w, http.StatusOK, output.Logs, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, output.Logs, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
 }
 
 // GetByResource gets audit logs for a specific resource
@@ -89,6 +89,12 @@ func (h *BlockHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// API version 2 lists return every block in a single page
+	if wantsListEnvelope(r) {
+		JSONPage(w, r, blocks, ListPage{Total: len(blocks)})
+		return
+	}
+
 	// Group blocks by category for frontend convenience
 	type BlockListResponse struct {
 		Blocks []*domain.BlockDefinition `json:"blocks"`
@@ -97,6 +103,21 @@ func (h *BlockHandler) List(w http.ResponseWriter, r *http.Request) {
 	JSONData(w, http.StatusOK, BlockListResponse{Blocks: blocks})
 }
 
+// Catalog handles GET /api/v1/blocks/catalog
+// Returns the enabled blocks grouped by category for the editor's block palette, with recently
+// used and popular sections
+func (h *BlockHandler) Catalog(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+
+	catalog, err := h.blockUsecase.Catalog(r.Context(), tenantID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, catalog)
+}
+
 // Get handles GET /api/v1/blocks/{slug}
 func (h *BlockHandler) Get(w http.ResponseWriter, r *http.Request) {
 	tenantID := getTenantID(r)
@@ -434,6 +455,45 @@ func (h *BlockHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Export handles GET /api/v1/blocks/{slug}/export
+// Returns a portable block definition without tenant-specific IDs
+func (h *BlockHandler) Export(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	slug := chi.URLParam(r, "slug")
+
+	export, err := h.blockUsecase.ExportBlock(r.Context(), tenantID, slug)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, export)
+}
+
+// Import handles POST /api/v1/blocks/import
+// The request body is a block export document. The optional "slug" query parameter
+// overrides the exported slug.
+func (h *BlockHandler) Import(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+
+	var export domain.BlockExport
+	if !decodeJSONBody(w, r, &export) {
+		return
+	}
+
+	block, err := h.blockUsecase.ImportBlock(r.Context(), usecase.ImportBlockInput{
+		TenantID: tenantID,
+		Export:   &export,
+		Slug:     r.URL.Query().Get("slug"),
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusCreated, block)
+}
+
 // ============================================================================
 // Admin endpoints for system block management
 // ============================================================================
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// CapabilitiesHandler handles HTTP requests for the capabilities of the deployment and tenant
+type CapabilitiesHandler struct {
+	usecase *usecase.CapabilitiesUsecase
+}
+
+// NewCapabilitiesHandler creates a new CapabilitiesHandler
+func NewCapabilitiesHandler(uc *usecase.CapabilitiesUsecase) *CapabilitiesHandler {
+	return &CapabilitiesHandler{usecase: uc}
+}
+
+// Get handles GET /api/v1/capabilities
+func (h *CapabilitiesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	capabilities, err := h.usecase.Get(r.Context(), getTenantID(r))
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, capabilities)
+}
@@ -153,28 +153,13 @@ func (h *CopilotAgentHandler) GetAgentSession(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Convert messages to DTO
-	messages := make([]map[string]interface{}, 0, len(session.Messages))
-	for _, msg := range session.Messages {
-		msgDTO := map[string]interface{}{
-			"id":         msg.ID.String(),
-			"role":       msg.Role,
-			"content":    msg.Content,
-			"created_at": msg.CreatedAt.Format(time.RFC3339),
-		}
-		if len(msg.ExtractedData) > 0 {
-			msgDTO["extracted_data"] = msg.ExtractedData
-		}
-		messages = append(messages, msgDTO)
-	}
-
 	JSON(w, http.StatusOK, map[string]interface{}{
 		"id":         session.ID.String(),
 		"status":     string(session.Status),
 		"phase":      string(session.HearingPhase),
 		"progress":   session.HearingProgress,
 		"mode":       string(session.Mode),
-		"messages":   messages,
+		"messages":   agentMessageDTOs(session.Messages),
 		"created_at": session.CreatedAt.Format(time.RFC3339),
 		"updated_at": session.UpdatedAt.Format(time.RFC3339),
 	})
@@ -368,7 +353,7 @@ func (h *CopilotAgentHandler) SendAgentMessage(w http.ResponseWriter, r *http.Re
 		input["workflow_id"] = session.ContextProjectID.String() // For ctx.targetProjectId in tools
 	}
 	// Add conversation history for memory-enabled agents
-	input["history"] = convertMessagesToHistory(session.Messages)
+	addAgentMemory(input, session)
 	inputJSON, _ := json.Marshal(input)
 
 	// Execute workflow synchronously (without SSE streaming)
@@ -502,7 +487,7 @@ func (h *CopilotAgentHandler) StreamAgentMessage(w http.ResponseWriter, r *http.
 		input["workflow_id"] = session.ContextProjectID.String() // For ctx.targetProjectId in tools
 	}
 	// Add conversation history for memory-enabled agents
-	input["history"] = convertMessagesToHistory(session.Messages)
+	addAgentMemory(input, session)
 	inputJSON, _ := json.Marshal(input)
 
 	// Start workflow execution in goroutine
@@ -731,6 +716,24 @@ func (h *CopilotAgentHandler) saveMessages(ctx context.Context, tenantID, sessio
 	}
 }
 
+// agentMessageDTOs converts session messages to their API representation
+func agentMessageDTOs(messages []domain.CopilotMessage) []map[string]interface{} {
+	dtos := make([]map[string]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		msgDTO := map[string]interface{}{
+			"id":         msg.ID.String(),
+			"role":       msg.Role,
+			"content":    msg.Content,
+			"created_at": msg.CreatedAt.Format(time.RFC3339),
+		}
+		if len(msg.ExtractedData) > 0 {
+			msgDTO["extracted_data"] = msg.ExtractedData
+		}
+		dtos = append(dtos, msgDTO)
+	}
+	return dtos
+}
+
 // convertMessagesToHistory converts session messages to the format expected by the workflow engine
 // Only user and assistant messages are included (system messages are handled by the agent's system prompt)
 func convertMessagesToHistory(messages []domain.CopilotMessage) []map[string]string {
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/middleware"
+)
+
+// UpdateAgentMemoryRequest represents the request to adjust a session's memory window
+type UpdateAgentMemoryRequest struct {
+	MemoryWindow *int `json:"memory_window"` // null restores the workflow default
+}
+
+// addAgentMemory adds the messages the session remembers (and its memory window override) to workflow input
+func addAgentMemory(input map[string]interface{}, session *domain.CopilotSession) {
+	input["history"] = convertMessagesToHistory(session.MemoryMessages())
+	if session.MemoryWindow != nil {
+		input["memory_window"] = *session.MemoryWindow
+	}
+}
+
+// agentMemoryResponse builds the memory view of a session
+func agentMemoryResponse(session *domain.CopilotSession) map[string]interface{} {
+	memory := session.MemoryMessages()
+	resp := map[string]interface{}{
+		"session_id":        session.ID.String(),
+		"memory_window":     session.MemoryWindow,
+		"memory_cleared_at": nil,
+		"message_count":     len(memory),
+		"messages":          agentMessageDTOs(memory),
+	}
+	if session.MemoryClearedAt != nil {
+		resp["memory_cleared_at"] = session.MemoryClearedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// getOwnedAgentSession loads the session in the URL with its messages.
+// Sessions of other tenants, projects, or users are reported as not found.
+func (h *CopilotAgentHandler) getOwnedAgentSession(w http.ResponseWriter, r *http.Request) (*domain.CopilotSession, bool) {
+	ctx := r.Context()
+	tenantID := middleware.GetTenantID(ctx)
+	userID := middleware.GetUserID(ctx)
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "INVALID_PROJECT_ID", "Invalid project ID", nil)
+		return nil, false
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "session_id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "INVALID_SESSION_ID", "Invalid session ID", nil)
+		return nil, false
+	}
+
+	session, err := h.sessionRepo.GetWithMessages(ctx, tenantID, sessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCopilotSessionNotFound) {
+			Error(w, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found", nil)
+			return nil, false
+		}
+		Error(w, http.StatusInternalServerError, "GET_SESSION_FAILED", err.Error(), nil)
+		return nil, false
+	}
+
+	if session.ContextProjectID == nil || *session.ContextProjectID != projectID || session.UserID != userID.String() {
+		Error(w, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found", nil)
+		return nil, false
+	}
+
+	return session, true
+}
+
+// GetAgentMemory handles GET /api/v1/projects/{project_id}/copilot/agent/sessions/{session_id}/memory
+// Returns the messages that will be sent to the agent as history on the next message.
+func (h *CopilotAgentHandler) GetAgentMemory(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.getOwnedAgentSession(w, r)
+	if !ok {
+		return
+	}
+
+	JSON(w, http.StatusOK, agentMemoryResponse(session))
+}
+
+// UpdateAgentMemory handles PUT /api/v1/projects/{project_id}/copilot/agent/sessions/{session_id}/memory
+func (h *CopilotAgentHandler) UpdateAgentMemory(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.getOwnedAgentSession(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateAgentMemoryRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	if err := session.SetMemoryWindow(req.MemoryWindow); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	if err := h.sessionRepo.Update(r.Context(), session); err != nil {
+		Error(w, http.StatusInternalServerError, "UPDATE_SESSION_FAILED", err.Error(), nil)
+		return
+	}
+
+	JSON(w, http.StatusOK, agentMemoryResponse(session))
+}
+
+// ClearAgentMemory handles DELETE /api/v1/projects/{project_id}/copilot/agent/sessions/{session_id}/memory
+// Messages stay in the session transcript but are no longer sent to the agent.
+func (h *CopilotAgentHandler) ClearAgentMemory(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.getOwnedAgentSession(w, r)
+	if !ok {
+		return
+	}
+
+	session.ClearMemory()
+	if err := h.sessionRepo.Update(r.Context(), session); err != nil {
+		Error(w, http.StatusInternalServerError, "UPDATE_SESSION_FAILED", err.Error(), nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// mockCopilotSessionRepo stores sessions in memory; only the methods used by the memory handlers are implemented
+type mockCopilotSessionRepo struct {
+	repository.CopilotSessionRepository
+	sessions map[uuid.UUID]*domain.CopilotSession
+}
+
+func (m *mockCopilotSessionRepo) GetWithMessages(ctx context.Context, tenantID, id uuid.UUID) (*domain.CopilotSession, error) {
+	session, ok := m.sessions[id]
+	if !ok || session.TenantID != tenantID {
+		return nil, domain.ErrCopilotSessionNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (m *mockCopilotSessionRepo) Update(ctx context.Context, session *domain.CopilotSession) error {
+	copied := *session
+	m.sessions[session.ID] = &copied
+	return nil
+}
+
+func newAgentMemoryRequest(method string, projectID, sessionID uuid.UUID, body interface{}) *http.Request {
+	req := createTestRequest(method, "/api/v1/projects/"+projectID.String()+"/copilot/agent/sessions/"+sessionID.String()+"/memory", body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", projectID.String())
+	rctx.URLParams.Add("session_id", sessionID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func setupAgentMemoryHandler(t *testing.T, userID string) (*CopilotAgentHandler, *mockCopilotSessionRepo, *domain.CopilotSession) {
+	t.Helper()
+	projectID := uuid.New()
+	session := domain.NewCopilotSession(uuid.MustParse("00000000-0000-0000-0000-000000000001"), userID, &projectID, domain.CopilotSessionModeEnhance)
+	session.AddMessage("user", "add a slack step")
+	session.AddMessage("assistant", "added slack_1")
+
+	repo := &mockCopilotSessionRepo{sessions: map[uuid.UUID]*domain.CopilotSession{session.ID: session}}
+	return NewCopilotAgentHandler(repo, nil, nil, slog.Default()), repo, session
+}
+
+func TestCopilotAgentHandler_ClearAgentMemory(t *testing.T) {
+	h, repo, session := setupAgentMemoryHandler(t, "00000000-0000-0000-0000-000000000002")
+
+	w := httptest.NewRecorder()
+	h.ClearAgentMemory(w, newAgentMemoryRequest(http.MethodDelete, *session.ContextProjectID, session.ID, nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204 (body: %s)", w.Code, w.Body.String())
+	}
+
+	stored := repo.sessions[session.ID]
+	if stored.MemoryClearedAt == nil {
+		t.Fatal("MemoryClearedAt was not persisted")
+	}
+	if len(stored.Messages) != 2 {
+		t.Errorf("transcript has %d messages, want 2 kept", len(stored.Messages))
+	}
+
+	// The next LLM call only receives messages sent after clearing
+	time.Sleep(time.Millisecond)
+	stored.AddMessage("user", "start over: build a daily report")
+	input := map[string]interface{}{}
+	addAgentMemory(input, stored)
+
+	history := input["history"].([]map[string]string)
+	if len(history) != 1 || history[0]["content"] != "start over: build a daily report" {
+		t.Errorf("history = %v, want only the post-clear message", history)
+	}
+}
+
+func TestCopilotAgentHandler_UpdateAgentMemory(t *testing.T) {
+	h, repo, session := setupAgentMemoryHandler(t, "00000000-0000-0000-0000-000000000002")
+
+	w := httptest.NewRecorder()
+	h.UpdateAgentMemory(w, newAgentMemoryRequest(http.MethodPut, *session.ContextProjectID, session.ID, map[string]int{"memory_window": 1}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		MemoryWindow *int                     `json:"memory_window"`
+		Messages     []map[string]interface{} `json:"messages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.MemoryWindow == nil || *resp.MemoryWindow != 1 || len(resp.Messages) != 1 {
+		t.Errorf("response = %+v, want window 1 with the last message", resp)
+	}
+	if stored := repo.sessions[session.ID]; stored.MemoryWindow == nil || *stored.MemoryWindow != 1 {
+		t.Errorf("stored MemoryWindow = %v, want 1", stored.MemoryWindow)
+	}
+
+	input := map[string]interface{}{}
+	addAgentMemory(input, repo.sessions[session.ID])
+	if input["memory_window"] != 1 {
+		t.Errorf("input memory_window = %v, want 1", input["memory_window"])
+	}
+
+	w = httptest.NewRecorder()
+	h.UpdateAgentMemory(w, newAgentMemoryRequest(http.MethodPut, *session.ContextProjectID, session.ID, map[string]int{"memory_window": 0}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid window: status = %d, want 400", w.Code)
+	}
+}
+
+func TestCopilotAgentHandler_AgentMemoryIsolation(t *testing.T) {
+	h, repo, session := setupAgentMemoryHandler(t, "someone-else")
+
+	tests := []struct {
+		name      string
+		projectID uuid.UUID
+	}{
+		{name: "other user's session", projectID: *session.ContextProjectID},
+		{name: "session of another project", projectID: uuid.New()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ClearAgentMemory(w, newAgentMemoryRequest(http.MethodDelete, tt.projectID, session.ID, nil))
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404", w.Code)
+			}
+			if repo.sessions[session.ID].MemoryClearedAt != nil {
+				t.Error("memory was cleared for a session the caller does not own")
+			}
+		})
+	}
+
+	otherTenant := domain.NewCopilotSession(uuid.New(), "00000000-0000-0000-0000-000000000002", session.ContextProjectID, domain.CopilotSessionModeEnhance)
+	repo.sessions[otherTenant.ID] = otherTenant
+	w := httptest.NewRecorder()
+	h.GetAgentMemory(w, newAgentMemoryRequest(http.MethodGet, *session.ContextProjectID, otherTenant.ID, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("other tenant: status = %d, want 404", w.Code)
+	}
+}
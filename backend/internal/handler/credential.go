@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/usecase"
 )
@@ -100,6 +101,7 @@ func (h *CredentialHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	input := usecase.ListCredentialsInput{
 		TenantID: tenantID,
+		Cursor:   r.URL.Query().Get("cursor"),
 		Page:     parseIntQuery(r, "page", 1),
 		Limit:    parseIntQuery(r, "limit", 20),
 	}
@@ -126,7 +128,7 @@ func (h *CredentialHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	// Return safe responses (no encrypted data)
 	responses := h.usecase.ToResponses(output.Credentials)
-	JSONList(w, http.StatusOK, responses, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, responses, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
 }
 
 // UpdateCredentialRequest represents the request body for updating a credential
@@ -245,3 +247,31 @@ func (h *CredentialHandler) Activate(w http.ResponseWriter, r *http.Request) {
 
 	JSON(w, http.StatusOK, h.usecase.ToResponse(credential))
 }
+
+// Test validates a credential with a lightweight call to its provider
+func (h *CredentialHandler) Test(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "credential_id", "credential ID")
+	if !ok {
+		return
+	}
+
+	tenantID := getTenantID(r)
+	var actorID *uuid.UUID
+	if userID := getUserID(r); userID != uuid.Nil {
+		actorID = &userID
+	}
+
+	result, err := h.usecase.TestConnection(r.Context(), tenantID, id, actorID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	// Log audit event
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionCredentialTest, domain.AuditResourceCredential, &id, map[string]interface{}{
+		"provider": result.Provider,
+		"success":  result.Success,
+	})
+
+	JSON(w, http.StatusOK, result)
+}
@@ -3,7 +3,9 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
+	"mime"
 	"net"
 	"net/http"
 	"strconv"
@@ -86,6 +88,20 @@ func parseIntQuery(r *http.Request, key string, defaultValue int) int {
 	return val
 }
 
+// parseListQuery parses a multi-value query parameter.
+// Supports both repeated keys (?tag=a&tag=b) and comma-separated values (?tag=a,b).
+func parseListQuery(r *http.Request, key string) []string {
+	var values []string
+	for _, raw := range r.URL.Query()[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
 // parseIntFromString parses an integer from a string with a default value
 func parseIntFromString(str string, defaultValue int) int {
 	if str == "" {
@@ -131,6 +147,28 @@ func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) bool
 	return true
 }
 
+// isJSONPatchRequest reports whether the request body is a JSON Patch (RFC 6902) document
+func isJSONPatchRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == domain.JSONPatchContentType
+}
+
+// decodeJSONPatchBody decodes a JSON Patch request body.
+// Returns the patch, or writes an error response and returns false if it is not a valid patch.
+func decodeJSONPatchBody(w http.ResponseWriter, r *http.Request) (domain.JSONPatch, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid request body", nil)
+		return nil, false
+	}
+	patch, err := domain.ParseJSONPatch(body)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return nil, false
+	}
+	return patch, true
+}
+
 // StepData represents step data in save request
 type StepData struct {
 	ID        string          `json:"id"`
@@ -234,12 +272,26 @@ func logAudit(
 	resourceType domain.AuditResourceType,
 	resourceID *uuid.UUID,
 	metadata map[string]interface{},
+) {
+	logAuditForTenant(ctx, auditService, r, getTenantID(r), action, resourceType, resourceID, metadata)
+}
+
+// logAuditForTenant logs an audit event in the given tenant, for admin actions on another
+// tenant than the caller's
+func logAuditForTenant(
+	ctx context.Context,
+	auditService *usecase.AuditService,
+	r *http.Request,
+	tenantID uuid.UUID,
+	action domain.AuditAction,
+	resourceType domain.AuditResourceType,
+	resourceID *uuid.UUID,
+	metadata map[string]interface{},
 ) {
 	if auditService == nil {
 		return
 	}
 
-	tenantID := getTenantID(r)
 	userID := getUserID(r)
 	userEmail := getUserEmail(r)
 
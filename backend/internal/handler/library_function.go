@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// LibraryFunctionHandler handles HTTP requests for library functions
+type LibraryFunctionHandler struct {
+	usecase      *usecase.LibraryFunctionUsecase
+	auditService *usecase.AuditService
+}
+
+// NewLibraryFunctionHandler creates a new LibraryFunctionHandler
+func NewLibraryFunctionHandler(uc *usecase.LibraryFunctionUsecase, auditService *usecase.AuditService) *LibraryFunctionHandler {
+	return &LibraryFunctionHandler{
+		usecase:      uc,
+		auditService: auditService,
+	}
+}
+
+// CreateLibraryFunctionRequest represents the request body for creating a library function
+type CreateLibraryFunctionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Code        string `json:"code"`
+}
+
+// UpdateLibraryFunctionRequest represents the request body for updating a library function
+type UpdateLibraryFunctionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Code        *string `json:"code"`
+}
+
+// List handles GET /api/v1/library-functions
+func (h *LibraryFunctionHandler) List(w http.ResponseWriter, r *http.Request) {
+	functions, err := h.usecase.List(r.Context(), getTenantID(r))
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, functions)
+}
+
+// Create handles POST /api/v1/library-functions
+func (h *LibraryFunctionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateLibraryFunctionRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID := getUserID(r); userID != uuid.Nil {
+		createdBy = &userID
+	}
+	function, err := h.usecase.Create(r.Context(), usecase.CreateLibraryFunctionInput{
+		TenantID:    getTenantID(r),
+		Name:        req.Name,
+		Description: req.Description,
+		Code:        req.Code,
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionLibraryFunctionCreate, domain.AuditResourceLibraryFunction, &function.ID, map[string]interface{}{
+		"name": function.Name,
+	})
+
+	JSONData(w, http.StatusCreated, function)
+}
+
+// Get handles GET /api/v1/library-functions/{id}
+func (h *LibraryFunctionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "library function ID")
+	if !ok {
+		return
+	}
+
+	function, err := h.usecase.Get(r.Context(), getTenantID(r), id)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, function)
+}
+
+// Update handles PUT /api/v1/library-functions/{id}
+func (h *LibraryFunctionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "library function ID")
+	if !ok {
+		return
+	}
+	var req UpdateLibraryFunctionRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	function, err := h.usecase.Update(r.Context(), usecase.UpdateLibraryFunctionInput{
+		TenantID:    getTenantID(r),
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Code:        req.Code,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionLibraryFunctionUpdate, domain.AuditResourceLibraryFunction, &id, map[string]interface{}{
+		"name": function.Name,
+	})
+
+	JSONData(w, http.StatusOK, function)
+}
+
+// Delete handles DELETE /api/v1/library-functions/{id}
+func (h *LibraryFunctionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "library function ID")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), getTenantID(r), id); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionLibraryFunctionDelete, domain.AuditResourceLibraryFunction, &id, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+var listTestTenantID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// keysetWindow returns the [start, end) range of a page of positions ordered newest first, the
+// way the postgres list queries select it
+func keysetWindow(positions []repository.ListCursor, after *repository.ListCursor, page, limit int) (int, int) {
+	start := (page - 1) * limit
+	if after != nil {
+		start = len(positions)
+		for i, p := range positions {
+			if p.Time.Before(after.Time) || (p.Time.Equal(after.Time) && p.ID.String() < after.ID.String()) {
+				start = i
+				break
+			}
+		}
+	}
+	start = min(start, len(positions))
+	return start, min(start+limit, len(positions))
+}
+
+// listTimes returns n distinct timestamps, newest first
+func listTimes(n int) []time.Time {
+	base := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = base.Add(-time.Duration(i) * time.Minute)
+	}
+	return times
+}
+
+type pagedProjectRepo struct {
+	repository.ProjectRepository
+	projects []*domain.Project // Newest first
+}
+
+func (r *pagedProjectRepo) List(ctx context.Context, tenantID uuid.UUID, filter repository.ProjectFilter) ([]*domain.Project, int, error) {
+	positions := make([]repository.ListCursor, len(r.projects))
+	for i, p := range r.projects {
+		positions[i] = repository.ListCursor{Time: p.UpdatedAt, ID: p.ID}
+	}
+	start, end := keysetWindow(positions, filter.After, filter.Page, filter.Limit)
+	return r.projects[start:end], len(r.projects), nil
+}
+
+type pagedScheduleRepo struct {
+	repository.ScheduleRepository
+	schedules []*domain.Schedule // Newest first
+}
+
+func (r *pagedScheduleRepo) ListByTenant(ctx context.Context, tenantID uuid.UUID, filter repository.ScheduleFilter) ([]*domain.Schedule, int, error) {
+	positions := make([]repository.ListCursor, len(r.schedules))
+	for i, s := range r.schedules {
+		positions[i] = repository.ListCursor{Time: s.CreatedAt, ID: s.ID}
+	}
+	start, end := keysetWindow(positions, filter.After, filter.Page, filter.Limit)
+	return r.schedules[start:end], len(r.schedules), nil
+}
+
+type pagedAuditLogRepo struct {
+	repository.AuditLogRepository
+	logs []*domain.AuditLog // Newest first
+}
+
+func (r *pagedAuditLogRepo) ListByTenant(ctx context.Context, tenantID uuid.UUID, filter repository.AuditLogFilter) ([]*domain.AuditLog, int, error) {
+	positions := make([]repository.ListCursor, len(r.logs))
+	for i, l := range r.logs {
+		positions[i] = repository.ListCursor{Time: l.CreatedAt, ID: l.ID}
+	}
+	start, end := keysetWindow(positions, filter.After, filter.Page, filter.Limit)
+	return r.logs[start:end], len(r.logs), nil
+}
+
+// listEndpoint is a list handler with the IDs of its items in list order
+type listEndpoint struct {
+	name string
+	path string
+	list http.HandlerFunc
+	ids  []uuid.UUID
+}
+
+func newListEndpoints() []listEndpoint {
+	times := listTimes(5)
+
+	projectRepo := &pagedProjectRepo{}
+	scheduleRepo := &pagedScheduleRepo{}
+	auditRepo := &pagedAuditLogRepo{}
+	var projectIDs, scheduleIDs, auditIDs []uuid.UUID
+	for _, at := range times {
+		project := domain.NewProject(listTestTenantID, "Workflow", "")
+		project.UpdatedAt = at
+		projectRepo.projects = append(projectRepo.projects, project)
+		projectIDs = append(projectIDs, project.ID)
+
+		schedule := &domain.Schedule{ID: uuid.New(), TenantID: listTestTenantID, CreatedAt: at}
+		scheduleRepo.schedules = append(scheduleRepo.schedules, schedule)
+		scheduleIDs = append(scheduleIDs, schedule.ID)
+
+		log := &domain.AuditLog{ID: uuid.New(), TenantID: listTestTenantID, Action: domain.AuditActionProjectCreate, CreatedAt: at}
+		auditRepo.logs = append(auditRepo.logs, log)
+		auditIDs = append(auditIDs, log.ID)
+	}
+
+	projectHandler := NewProjectHandler(usecase.NewProjectUsecase(projectRepo, nil, nil, nil, nil), nil)
+	scheduleHandler := NewScheduleHandler(usecase.NewScheduleUsecase(scheduleRepo, nil, nil), nil)
+	auditHandler := NewAuditHandler(usecase.NewAuditService(auditRepo))
+	return []listEndpoint{
+		{name: "projects", path: "/api/v1/projects", list: projectHandler.List, ids: projectIDs},
+		{name: "schedules", path: "/api/v1/schedules", list: scheduleHandler.List, ids: scheduleIDs},
+		{name: "audit logs", path: "/api/v1/audit-logs", list: auditHandler.List, ids: auditIDs},
+	}
+}
+
+func TestListEndpoints_EnvelopeCursorRoundTrip(t *testing.T) {
+	for _, endpoint := range newListEndpoints() {
+		t.Run(endpoint.name, func(t *testing.T) {
+			var seen []uuid.UUID
+			cursor := ""
+			for pages := 0; pages < 10; pages++ {
+				path := endpoint.path + "?limit=2"
+				if cursor != "" {
+					path += "&cursor=" + cursor
+				}
+				req := createTestRequest(http.MethodGet, path, nil)
+				req.Header.Set(APIVersionHeader, "2")
+				rr := httptest.NewRecorder()
+				endpoint.list(rr, req)
+
+				if rr.Code != http.StatusOK {
+					t.Fatalf("status = %d, body %s", rr.Code, rr.Body.String())
+				}
+				var body map[string]json.RawMessage
+				if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if _, ok := body["data"]; ok {
+					t.Fatalf("version 2 response must not use the data envelope: %s", rr.Body.String())
+				}
+				var page struct {
+					Items      []struct{ ID uuid.UUID } `json:"items"`
+					NextCursor string                   `json:"next_cursor"`
+					Total      int                      `json:"total"`
+				}
+				if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+					t.Fatalf("decode envelope: %v", err)
+				}
+				if page.Total != len(endpoint.ids) {
+					t.Errorf("total = %d, want %d", page.Total, len(endpoint.ids))
+				}
+				if len(page.Items) > 2 {
+					t.Fatalf("page has %d items, want at most the limit of 2", len(page.Items))
+				}
+				for _, item := range page.Items {
+					seen = append(seen, item.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				cursor = page.NextCursor
+			}
+
+			if len(seen) != len(endpoint.ids) {
+				t.Fatalf("walked %d items over the cursors, want %d", len(seen), len(endpoint.ids))
+			}
+			for i := range seen {
+				if seen[i] != endpoint.ids[i] {
+					t.Errorf("item %d = %s, want %s", i, seen[i], endpoint.ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListEndpoints_LegacyResponseKeepsDataEnvelope(t *testing.T) {
+	for _, endpoint := range newListEndpoints() {
+		t.Run(endpoint.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			endpoint.list(rr, createTestRequest(http.MethodGet, endpoint.path+"?limit=2&page=1", nil))
+
+			var body struct {
+				Data []struct{ ID uuid.UUID } `json:"data"`
+				Meta Meta                     `json:"meta"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(body.Data) != 2 || body.Meta.Page != 1 || body.Meta.Total != len(endpoint.ids) {
+				t.Errorf("legacy response = %s", rr.Body.String())
+			}
+			if body.Meta.NextCursor == "" {
+				t.Error("legacy meta should carry the next cursor")
+			}
+		})
+	}
+}
+
+func TestJSONPage_EmptyListIsArray(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schedules", nil)
+	req.Header.Set(APIVersionHeader, "2")
+	rr := httptest.NewRecorder()
+	var schedules []*domain.Schedule
+	JSONPage(rr, req, schedules, ListPage{})
+
+	if got := rr.Body.String(); got != "{\"items\":[],\"total\":0}\n" {
+		t.Errorf("body = %s", got)
+	}
+	if got := rr.Header().Get(APIVersionHeader); got != "2" {
+		t.Errorf("%s header = %q, want 2", APIVersionHeader, got)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// NotificationChannelHandler handles HTTP requests for notification channels
+type NotificationChannelHandler struct {
+	usecase      *usecase.NotificationChannelUsecase
+	auditService *usecase.AuditService
+}
+
+// NewNotificationChannelHandler creates a new NotificationChannelHandler
+func NewNotificationChannelHandler(uc *usecase.NotificationChannelUsecase, auditService *usecase.AuditService) *NotificationChannelHandler {
+	return &NotificationChannelHandler{
+		usecase:      uc,
+		auditService: auditService,
+	}
+}
+
+// CreateNotificationChannelRequest represents the request body for creating a notification channel
+type CreateNotificationChannelRequest struct {
+	Name    string                         `json:"name"`
+	Type    domain.NotificationChannelType `json:"type"`
+	Config  json.RawMessage                `json:"config"`
+	Events  []domain.NotificationEvent     `json:"events"`
+	Enabled *bool                          `json:"enabled"`
+}
+
+// UpdateNotificationChannelRequest represents the request body for updating a notification channel
+type UpdateNotificationChannelRequest struct {
+	Name    *string                    `json:"name"`
+	Config  json.RawMessage            `json:"config"`
+	Events  []domain.NotificationEvent `json:"events"`
+	Enabled *bool                      `json:"enabled"`
+}
+
+// List handles GET /api/v1/notification-channels
+func (h *NotificationChannelHandler) List(w http.ResponseWriter, r *http.Request) {
+	channels, err := h.usecase.List(r.Context(), getTenantID(r))
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, channels)
+}
+
+// Create handles POST /api/v1/notification-channels
+func (h *NotificationChannelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateNotificationChannelRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID := getUserID(r); userID != uuid.Nil {
+		createdBy = &userID
+	}
+	channel, err := h.usecase.Create(r.Context(), usecase.CreateNotificationChannelInput{
+		TenantID:  getTenantID(r),
+		Name:      req.Name,
+		Type:      req.Type,
+		Config:    req.Config,
+		Events:    req.Events,
+		Enabled:   req.Enabled,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionNotificationChannelCreate, domain.AuditResourceNotificationChannel, &channel.ID, map[string]interface{}{
+		"name": channel.Name,
+		"type": channel.Type,
+	})
+
+	JSONData(w, http.StatusCreated, channel)
+}
+
+// Get handles GET /api/v1/notification-channels/{id}
+func (h *NotificationChannelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "notification channel ID")
+	if !ok {
+		return
+	}
+
+	channel, err := h.usecase.Get(r.Context(), getTenantID(r), id)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, channel)
+}
+
+// Update handles PUT /api/v1/notification-channels/{id}
+func (h *NotificationChannelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "notification channel ID")
+	if !ok {
+		return
+	}
+	var req UpdateNotificationChannelRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	channel, err := h.usecase.Update(r.Context(), usecase.UpdateNotificationChannelInput{
+		TenantID: getTenantID(r),
+		ID:       id,
+		Name:     req.Name,
+		Config:   req.Config,
+		Events:   req.Events,
+		Enabled:  req.Enabled,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionNotificationChannelUpdate, domain.AuditResourceNotificationChannel, &id, nil)
+
+	JSONData(w, http.StatusOK, channel)
+}
+
+// Delete handles DELETE /api/v1/notification-channels/{id}
+func (h *NotificationChannelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "notification channel ID")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), getTenantID(r), id); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionNotificationChannelDelete, domain.AuditResourceNotificationChannel, &id, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Test handles POST /api/v1/notification-channels/{id}/test. A failed delivery is reported in
+// the response rather than as an error status.
+func (h *NotificationChannelHandler) Test(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUID(w, r, "id", "notification channel ID")
+	if !ok {
+		return
+	}
+
+	err := h.usecase.Test(r.Context(), getTenantID(r), id)
+	var deliveryErr *usecase.NotificationDeliveryError
+	if errors.As(err, &deliveryErr) {
+		JSONData(w, http.StatusOK, map[string]interface{}{
+			"success": false,
+			"message": deliveryErr.Error(),
+		})
+		return
+	}
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "test notification delivered",
+	})
+}
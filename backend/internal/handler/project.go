@@ -2,6 +2,8 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -28,6 +30,7 @@ type CreateProjectRequest struct {
 	Name        string          `json:"name"`
 	Description string          `json:"description"`
 	Variables   json.RawMessage `json:"variables,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
 }
 
 // Create handles POST /api/v1/projects
@@ -44,6 +47,7 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Name:        req.Name,
 		Description: req.Description,
 		Variables:   req.Variables,
+		Tags:        req.Tags,
 	})
 	if err != nil {
 		HandleErrorL(w, r, err)
@@ -73,18 +77,40 @@ func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 		status = &s
 	}
 
-	output, err := h.projectUsecase.List(r.Context(), usecase.ListProjectsInput{
+	input := usecase.ListProjectsInput{
 		TenantID: tenantID,
 		Status:   status,
+		Tags:     parseListQuery(r, "tag"),
+		Cursor:   r.URL.Query().Get("cursor"),
 		Page:     page,
 		Limit:    limit,
-	})
+	}
+	if r.URL.Query().Get("favorites") == "true" {
+		userID := getUserID(r)
+		input.FavoritesOf = &userID
+	}
+
+	output, err := h.projectUsecase.List(r.Context(), input)
 	if err != nil {
 		HandleErrorL(w, r, err)
 		return
 	}
 
-	JSONList(w, http.StatusOK, output.Projects, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, output.Projects, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
+}
+
+// ListTags handles GET /api/v1/projects/tags
+// Returns the tenant's distinct tags with the number of projects using each
+func (h *ProjectHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+
+	tags, err := h.projectUsecase.ListTags(r.Context(), tenantID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, tags)
 }
 
 // Get handles GET /api/v1/projects/{id}
@@ -109,9 +135,15 @@ type UpdateProjectRequest struct {
 	Name        string          `json:"name"`
 	Description string          `json:"description"`
 	Variables   json.RawMessage `json:"variables,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	RunRetry    json.RawMessage `json:"run_retry,omitempty"`
+	RunOutput   json.RawMessage `json:"run_output,omitempty"`
+
+	FailureNotifications json.RawMessage `json:"failure_notifications,omitempty"`
 }
 
 // Update handles PUT /api/v1/projects/{id}
+// A body of Content-Type application/json-patch+json is applied as a JSON Patch (RFC 6902)
 func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	tenantID := getTenantID(r)
 	id, ok := parseUUID(w, r, "id", "project ID")
@@ -119,18 +151,36 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req UpdateProjectRequest
-	if !decodeJSONBody(w, r, &req) {
-		return
+	var project *domain.Project
+	var err error
+	if isJSONPatchRequest(r) {
+		patch, ok := decodeJSONPatchBody(w, r)
+		if !ok {
+			return
+		}
+		project, err = h.projectUsecase.Patch(r.Context(), usecase.PatchProjectInput{
+			TenantID: tenantID,
+			ID:       id,
+			Patch:    patch,
+		})
+	} else {
+		var req UpdateProjectRequest
+		if !decodeJSONBody(w, r, &req) {
+			return
+		}
+		project, err = h.projectUsecase.Update(r.Context(), usecase.UpdateProjectInput{
+			TenantID:    tenantID,
+			ID:          id,
+			Name:        req.Name,
+			Description: req.Description,
+			Variables:   req.Variables,
+			Tags:        req.Tags,
+			RunRetry:    req.RunRetry,
+			RunOutput:   req.RunOutput,
+
+			FailureNotifications: req.FailureNotifications,
+		})
 	}
-
-	project, err := h.projectUsecase.Update(r.Context(), usecase.UpdateProjectInput{
-		TenantID:    tenantID,
-		ID:          id,
-		Name:        req.Name,
-		Description: req.Description,
-		Variables:   req.Variables,
-	})
 	if err != nil {
 		HandleErrorL(w, r, err)
 		return
@@ -163,6 +213,66 @@ func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Pause handles POST /api/v1/projects/{id}/pause
+func (h *ProjectHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, true, domain.AuditActionProjectPause)
+}
+
+// Resume handles POST /api/v1/projects/{id}/resume
+func (h *ProjectHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, false, domain.AuditActionProjectResume)
+}
+
+func (h *ProjectHandler) setPaused(w http.ResponseWriter, r *http.Request, paused bool, action domain.AuditAction) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	project, err := h.projectUsecase.SetPaused(r.Context(), tenantID, id, paused)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, action, domain.AuditResourceProject, &id, nil)
+
+	JSONData(w, http.StatusOK, project)
+}
+
+// Favorite handles POST /api/v1/projects/{id}/favorite
+func (h *ProjectHandler) Favorite(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	if err := h.projectUsecase.Favorite(r.Context(), tenantID, getUserID(r), id); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unfavorite handles DELETE /api/v1/projects/{id}/favorite
+func (h *ProjectHandler) Unfavorite(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	if err := h.projectUsecase.Unfavorite(r.Context(), tenantID, getUserID(r), id); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // SaveProjectRequest represents a save project request
 type SaveProjectRequest struct {
 	Name        string          `json:"name"`
@@ -397,3 +507,124 @@ func (h *ProjectHandler) Validate(w http.ResponseWriter, r *http.Request) {
 
 	JSONData(w, http.StatusOK, result)
 }
+
+// Describe handles GET /api/v1/projects/{id}/describe
+// Returns a natural-language summary of the workflow
+func (h *ProjectHandler) Describe(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	description, err := h.projectUsecase.Describe(r.Context(), tenantID, id)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, description)
+}
+
+// Contract handles GET /api/v1/projects/{id}/contract
+// Returns the workflow's input/output schemas inferred from its start and terminal steps
+func (h *ProjectHandler) Contract(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	contract, err := h.projectUsecase.InferContract(r.Context(), tenantID, id)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, contract)
+}
+
+// SuggestEdges handles GET /api/v1/workflows/{id}/suggest-edges
+// Returns proposed incoming edges for steps that are not connected to the workflow
+func (h *ProjectHandler) SuggestEdges(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.projectUsecase.SuggestEdges(r.Context(), tenantID, id)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, suggestions)
+}
+
+// EstimateCostRequest represents a cost estimate request
+type EstimateCostRequest struct {
+	Input json.RawMessage `json:"input,omitempty"` // Sample run input, used for loop iteration counts
+}
+
+// EstimateCost handles POST /api/v1/workflows/{id}/estimate-cost
+// Returns a rough cost range of running the workflow; the request body is optional
+func (h *ProjectHandler) EstimateCost(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	var req EstimateCostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid request body", nil)
+		return
+	}
+
+	estimate, err := h.projectUsecase.EstimateCost(r.Context(), tenantID, id, req.Input)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, estimate)
+}
+
+// CloneProjectRequest represents a clone project request
+type CloneProjectRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+// Clone handles POST /api/v1/workflows/{id}/clone
+// Duplicates the workflow within the tenant; the request body is optional
+func (h *ProjectHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	var req CloneProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid request body", nil)
+		return
+	}
+
+	project, err := h.projectUsecase.Clone(r.Context(), usecase.CloneProjectInput{
+		TenantID:  tenantID,
+		ProjectID: id,
+		Name:      req.Name,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionProjectCreate, domain.AuditResourceProject, &project.ID, map[string]interface{}{
+		"name":        project.Name,
+		"cloned_from": id.String(),
+	})
+
+	JSONData(w, http.StatusCreated, project)
+}
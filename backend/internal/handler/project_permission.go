@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/middleware"
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// ProjectPermissionHandler handles HTTP requests for workflow permissions and enforces them on
+// the workflow routes
+type ProjectPermissionHandler struct {
+	usecase      *usecase.ProjectPermissionUsecase
+	auditService *usecase.AuditService
+}
+
+// NewProjectPermissionHandler creates a new ProjectPermissionHandler
+func NewProjectPermissionHandler(uc *usecase.ProjectPermissionUsecase, auditService *usecase.AuditService) *ProjectPermissionHandler {
+	return &ProjectPermissionHandler{
+		usecase:      uc,
+		auditService: auditService,
+	}
+}
+
+// workflowActor returns the user making the request
+func workflowActor(r *http.Request) usecase.WorkflowActor {
+	return usecase.WorkflowActor{
+		TenantID: getTenantID(r),
+		UserID:   getUserID(r),
+		IsAdmin:  middleware.IsAdmin(r.Context()),
+	}
+}
+
+// Require returns middleware rejecting requests to the workflow in the {id} URL parameter unless
+// the user has at least the given role on it
+func (h *ProjectPermissionHandler) Require(role domain.WorkflowRole) func(http.Handler) http.Handler {
+	return requirePermission("id", "project ID", func(r *http.Request, projectID uuid.UUID) error {
+		return h.usecase.Check(r.Context(), workflowActor(r), projectID, role)
+	})
+}
+
+// RequireForRun returns middleware rejecting requests to the run in the {run_id} URL parameter
+// unless the user has at least the given role on the run's workflow
+func (h *ProjectPermissionHandler) RequireForRun(role domain.WorkflowRole) func(http.Handler) http.Handler {
+	return requirePermission("run_id", "run ID", func(r *http.Request, runID uuid.UUID) error {
+		return h.usecase.CheckRun(r.Context(), workflowActor(r), runID, role)
+	})
+}
+
+// RequireForSchedule returns middleware rejecting requests to the schedule in the {schedule_id}
+// URL parameter unless the user has at least the given role on the schedule's workflow
+func (h *ProjectPermissionHandler) RequireForSchedule(role domain.WorkflowRole) func(http.Handler) http.Handler {
+	return requirePermission("schedule_id", "schedule ID", func(r *http.Request, scheduleID uuid.UUID) error {
+		return h.usecase.CheckSchedule(r.Context(), workflowActor(r), scheduleID, role)
+	})
+}
+
+// requirePermission returns middleware running check on the ID in the paramName URL parameter
+func requirePermission(paramName, resourceName string, check func(r *http.Request, id uuid.UUID) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := parseUUID(w, r, paramName, resourceName)
+			if !ok {
+				return
+			}
+			if err := check(r, id); err != nil {
+				HandleErrorL(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// filterViewable splits items of several workflows into those the user may view and the rest.
+// Without permissions every item is kept.
+func filterViewable[T any](r *http.Request, permissions *usecase.ProjectPermissionUsecase, items []T, projectID func(T) uuid.UUID) (kept, removed []T, err error) {
+	if permissions == nil || len(items) == 0 {
+		return items, nil, nil
+	}
+	projectIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		projectIDs[i] = projectID(item)
+	}
+	viewable, err := permissions.Viewable(r.Context(), workflowActor(r), projectIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	kept = make([]T, 0, len(items))
+	for _, item := range items {
+		if viewable[projectID(item)] {
+			kept = append(kept, item)
+		} else {
+			removed = append(removed, item)
+		}
+	}
+	return kept, removed, nil
+}
+
+// List handles GET /api/v1/workflows/{id}/permissions
+func (h *ProjectPermissionHandler) List(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	permissions, err := h.usecase.List(r.Context(), getTenantID(r), projectID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, map[string]interface{}{
+		"restricted":  len(permissions) > 0,
+		"permissions": permissions,
+	})
+}
+
+// GrantProjectPermissionRequest represents the request body for granting a workflow permission
+type GrantProjectPermissionRequest struct {
+	Role domain.WorkflowRole `json:"role"`
+}
+
+// Grant handles PUT /api/v1/workflows/{id}/permissions/{user_id}
+func (h *ProjectPermissionHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseUUID(w, r, "user_id", "user ID")
+	if !ok {
+		return
+	}
+	var req GrantProjectPermissionRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	permission, err := h.usecase.Grant(r.Context(), usecase.GrantProjectPermissionInput{
+		Actor:     workflowActor(r),
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      req.Role,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionProjectPermissionGrant, domain.AuditResourceProject, &projectID, map[string]interface{}{
+		"user_id": userID.String(),
+		"role":    permission.Role,
+	})
+
+	JSONData(w, http.StatusOK, permission)
+}
+
+// Revoke handles DELETE /api/v1/workflows/{id}/permissions/{user_id}
+func (h *ProjectPermissionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseUUID(w, r, "user_id", "user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Revoke(r.Context(), getTenantID(r), projectID, userID); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionProjectPermissionRevoke, domain.AuditResourceProject, &projectID, map[string]interface{}{
+		"user_id": userID.String(),
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
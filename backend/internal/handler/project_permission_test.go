@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// stubProjectPermissionRepo returns fixed permissions per workflow
+type stubProjectPermissionRepo struct {
+	permissions map[uuid.UUID][]*domain.ProjectPermission
+}
+
+func (s *stubProjectPermissionRepo) Upsert(ctx context.Context, permission *domain.ProjectPermission) error {
+	return nil
+}
+
+func (s *stubProjectPermissionRepo) Delete(ctx context.Context, tenantID, projectID, userID uuid.UUID) error {
+	return nil
+}
+
+func (s *stubProjectPermissionRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.ProjectPermission, error) {
+	return s.permissions[projectID], nil
+}
+
+// stubPermissionRunRepo resolves runs to their workflow
+type stubPermissionRunRepo struct {
+	repository.RunRepository
+	runs map[uuid.UUID]*domain.Run
+}
+
+func (s *stubPermissionRunRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error) {
+	if run, ok := s.runs[id]; ok && run.TenantID == tenantID {
+		return run, nil
+	}
+	return nil, domain.ErrRunNotFound
+}
+
+// stubPermissionScheduleRepo resolves schedules to their workflow
+type stubPermissionScheduleRepo struct {
+	repository.ScheduleRepository
+	schedules map[uuid.UUID]*domain.Schedule
+}
+
+func (s *stubPermissionScheduleRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Schedule, error) {
+	if schedule, ok := s.schedules[id]; ok && schedule.TenantID == tenantID {
+		return schedule, nil
+	}
+	return nil, domain.ErrScheduleNotFound
+}
+
+// permissionTestRouter mirrors the run and schedule routes of the API with a workflow restricted
+// to the test user as a viewer. Requests that pass the permission check reach a 204 handler.
+func permissionTestRouter(t *testing.T) (http.Handler, *domain.Run, *domain.Schedule) {
+	t.Helper()
+	req := createTestRequest(http.MethodGet, "/", nil)
+	tenantID, userID := getTenantID(req), getUserID(req)
+
+	projectID := uuid.New()
+	run := &domain.Run{ID: uuid.New(), TenantID: tenantID, ProjectID: projectID}
+	schedule := &domain.Schedule{ID: uuid.New(), TenantID: tenantID, ProjectID: projectID}
+	permissions := usecase.NewProjectPermissionUsecase(&stubProjectPermissionRepo{permissions: map[uuid.UUID][]*domain.ProjectPermission{
+		projectID: {
+			domain.NewProjectPermission(tenantID, projectID, uuid.New(), domain.WorkflowRoleOwner, nil),
+			domain.NewProjectPermission(tenantID, projectID, userID, domain.WorkflowRoleViewer, nil),
+		},
+	}}, nil).
+		WithRunRepository(&stubPermissionRunRepo{runs: map[uuid.UUID]*domain.Run{run.ID: run}}).
+		WithScheduleRepository(&stubPermissionScheduleRepo{schedules: map[uuid.UUID]*domain.Schedule{schedule.ID: schedule}})
+	h := NewProjectPermissionHandler(permissions, nil)
+
+	allowed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r := chi.NewRouter()
+	r.With(h.RequireForRun(domain.WorkflowRoleViewer)).Get("/runs/{run_id}", allowed)
+	r.With(h.RequireForRun(domain.WorkflowRoleRunner)).Post("/runs/{run_id}/resume", allowed)
+	r.With(h.RequireForRun(domain.WorkflowRoleRunner)).Post("/runs/{run_id}/steps/{step_id}/execute", allowed)
+	r.With(h.RequireForSchedule(domain.WorkflowRoleViewer)).Get("/schedules/{schedule_id}", allowed)
+	r.With(h.RequireForSchedule(domain.WorkflowRoleRunner)).Post("/schedules/{schedule_id}/trigger", allowed)
+	r.With(h.RequireForSchedule(domain.WorkflowRoleEditor)).Put("/schedules/{schedule_id}", allowed)
+	r.Post("/schedules", NewScheduleHandler(nil, nil).WithProjectPermissions(permissions).Create)
+	return r, run, schedule
+}
+
+func TestProjectPermissions_RunAndScheduleRoutes(t *testing.T) {
+	router, run, schedule := permissionTestRouter(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       interface{}
+		wantStatus int
+	}{
+		{"viewer reads a run", http.MethodGet, "/runs/" + run.ID.String(), nil, http.StatusNoContent},
+		{"viewer cannot resume a run", http.MethodPost, "/runs/" + run.ID.String() + "/resume", nil, http.StatusForbidden},
+		{"viewer cannot execute a step", http.MethodPost, "/runs/" + run.ID.String() + "/steps/" + uuid.NewString() + "/execute", nil, http.StatusForbidden},
+		{"unknown run", http.MethodPost, "/runs/" + uuid.NewString() + "/resume", nil, http.StatusNotFound},
+		{"viewer reads a schedule", http.MethodGet, "/schedules/" + schedule.ID.String(), nil, http.StatusNoContent},
+		{"viewer cannot trigger a schedule", http.MethodPost, "/schedules/" + schedule.ID.String() + "/trigger", nil, http.StatusForbidden},
+		{"viewer cannot update a schedule", http.MethodPut, "/schedules/" + schedule.ID.String(), nil, http.StatusForbidden},
+		{"viewer cannot create a schedule", http.MethodPost, "/schedules", map[string]string{
+			"project_id": run.ProjectID.String(), "name": "nightly", "cron_expression": "0 0 * * *", "start_step_id": uuid.NewString(),
+		}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, createTestRequest(tt.method, tt.path, tt.body))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
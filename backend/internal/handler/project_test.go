@@ -270,6 +270,12 @@ func TestHandleErrorL(t *testing.T) {
 			wantStatus: http.StatusConflict,
 			wantCode:   "RUN_NOT_CANCELLABLE",
 		},
+		{
+			name:       "queue unavailable",
+			err:        domain.ErrQueueUnavailable,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "QUEUE_UNAVAILABLE",
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"reflect"
 
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/middleware"
@@ -18,9 +19,29 @@ type Response struct {
 
 // Meta contains metadata about the response
 type Meta struct {
-	Page  int `json:"page,omitempty"`
-	Limit int `json:"limit,omitempty"`
-	Total int `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Total      int    `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// APIVersionHeader is the request header selecting the API version. Version 2 returns lists in
+// the ListEnvelope; other requests keep the Response format during the transition.
+const APIVersionHeader = "X-API-Version"
+
+// ListEnvelope represents a list response of API version 2
+type ListEnvelope struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"` // Empty on the last page
+	Total      int         `json:"total"`
+}
+
+// ListPage describes the page of a list response
+type ListPage struct {
+	Page       int
+	Limit      int
+	Total      int
+	NextCursor string
 }
 
 // ErrorResponse represents an error response
@@ -63,6 +84,52 @@ func JSONList(w http.ResponseWriter, status int, data interface{}, page, limit,
 	})
 }
 
+// JSONCursorList writes a keyset-paginated list response.
+// nextCursor is empty when there are no more results.
+func JSONCursorList(w http.ResponseWriter, status int, data interface{}, limit int, nextCursor string) {
+	JSON(w, status, Response{
+		Data: data,
+		Meta: &Meta{
+			Limit:      limit,
+			NextCursor: nextCursor,
+		},
+	})
+}
+
+// wantsListEnvelope reports whether the request asks for API version 2 list responses
+func wantsListEnvelope(r *http.Request) bool {
+	return r.Header.Get(APIVersionHeader) == "2"
+}
+
+// JSONPage writes a page of a list: a ListEnvelope for API version 2 requests, otherwise a
+// paginated Response that also carries the next cursor in its meta
+func JSONPage(w http.ResponseWriter, r *http.Request, items interface{}, page ListPage) {
+	w.Header().Add("Vary", APIVersionHeader)
+	if !wantsListEnvelope(r) {
+		JSON(w, http.StatusOK, Response{
+			Data: items,
+			Meta: &Meta{
+				Page:       page.Page,
+				Limit:      page.Limit,
+				Total:      page.Total,
+				NextCursor: page.NextCursor,
+			},
+		})
+		return
+	}
+
+	// An empty list is [] rather than null
+	if v := reflect.ValueOf(items); v.Kind() == reflect.Slice && v.IsNil() {
+		items = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	w.Header().Set(APIVersionHeader, "2")
+	JSON(w, http.StatusOK, ListEnvelope{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
 // Error writes an error response (uses message as-is)
 func Error(w http.ResponseWriter, status int, code, message string, details interface{}) {
 	JSON(w, status, ErrorResponse{
@@ -109,6 +176,18 @@ func HandleErrorL(w http.ResponseWriter, r *http.Request, err error) {
 		return
 	}
 
+	var stepTypeErr *domain.InvalidStepTypeError
+	if errors.As(err, &stepTypeErr) {
+		Error(w, http.StatusBadRequest, "INVALID_STEP_TYPE", domain.GetErrorMessage(lang, "INVALID_STEP_TYPE"), stepTypeErr)
+		return
+	}
+
+	var cronErr *domain.InvalidCronError
+	if errors.As(err, &cronErr) {
+		Error(w, http.StatusBadRequest, "SCHEDULE_INVALID_CRON", domain.GetErrorMessage(lang, "SCHEDULE_INVALID_CRON"), cronErr)
+		return
+	}
+
 	// Map domain errors to error codes
 	type errorMapping struct {
 		err    error
@@ -125,6 +204,9 @@ func HandleErrorL(w http.ResponseWriter, r *http.Request, err error) {
 		domain.ErrBlockDefinitionNotFound, domain.ErrStepRunNotFound,
 		domain.ErrOAuth2ProviderNotFound, domain.ErrOAuth2AppNotFound,
 		domain.ErrOAuth2ConnectionNotFound, domain.ErrCredentialShareNotFound,
+		domain.ErrTemplateNotFound, domain.ErrRunAnnotationNotFound,
+		domain.ErrVectorCollectionNotFound, domain.ErrProjectPermissionNotFound,
+		domain.ErrNotificationChannelNotFound, domain.ErrLibraryFunctionNotFound,
 	}
 	for _, e := range notFoundErrors {
 		if errors.Is(err, e) {
@@ -138,6 +220,18 @@ func HandleErrorL(w http.ResponseWriter, r *http.Request, err error) {
 		Error(w, http.StatusConflict, "RUN_NOT_CANCELLABLE", domain.GetErrorMessage(lang, "RUN_NOT_CANCELLABLE"), nil)
 	case errors.Is(err, domain.ErrRunNotResumable):
 		Error(w, http.StatusConflict, "RUN_NOT_RESUMABLE", domain.GetErrorMessage(lang, "RUN_NOT_RESUMABLE"), nil)
+	case errors.Is(err, domain.ErrQueueUnavailable):
+		w.Header().Set("Retry-After", "30")
+		Error(w, http.StatusServiceUnavailable, "QUEUE_UNAVAILABLE", domain.GetErrorMessage(lang, "QUEUE_UNAVAILABLE"), nil)
+	case errors.Is(err, domain.ErrMaintenanceMode):
+		w.Header().Set("Retry-After", "60")
+		Error(w, http.StatusServiceUnavailable, "MAINTENANCE_MODE", domain.GetErrorMessage(lang, "MAINTENANCE_MODE"), nil)
+	case errors.Is(err, domain.ErrTenantQueueSaturated):
+		w.Header().Set("Retry-After", "30")
+		Error(w, http.StatusTooManyRequests, "TENANT_QUEUE_SATURATED", domain.GetErrorMessage(lang, "TENANT_QUEUE_SATURATED"), nil)
+	case errors.Is(err, domain.ErrQueueSaturated):
+		w.Header().Set("Retry-After", "30")
+		Error(w, http.StatusServiceUnavailable, "QUEUE_SATURATED", domain.GetErrorMessage(lang, "QUEUE_SATURATED"), nil)
 	case errors.Is(err, domain.ErrScheduleDisabled):
 		Error(w, http.StatusConflict, "SCHEDULE_DISABLED", domain.GetErrorMessage(lang, "SCHEDULE_DISABLED"), nil)
 
@@ -154,8 +248,11 @@ func HandleErrorL(w http.ResponseWriter, r *http.Request, err error) {
 
 	case errors.Is(err, domain.ErrOAuth2AppAlreadyExists):
 		Error(w, http.StatusConflict, "OAUTH2_APP_ALREADY_EXISTS", domain.GetErrorMessage(lang, "OAUTH2_APP_ALREADY_EXISTS"), nil)
-	case errors.Is(err, domain.ErrCredentialShareDuplicate):
+	case errors.Is(err, domain.ErrCredentialShareDuplicate), errors.Is(err, domain.ErrVectorCollectionExists),
+		errors.Is(err, domain.ErrLibraryFunctionExists):
 		Error(w, http.StatusConflict, "ALREADY_EXISTS", domain.GetErrorMessage(lang, "ALREADY_EXISTS"), nil)
+	case errors.Is(err, domain.ErrVectorCollectionReadOnly):
+		Error(w, http.StatusForbidden, "VECTOR_COLLECTION_READ_ONLY", domain.GetErrorMessage(lang, "VECTOR_COLLECTION_READ_ONLY"), nil)
 
 	case errors.Is(err, domain.ErrOAuth2InvalidState):
 		Error(w, http.StatusBadRequest, "OAUTH2_INVALID_STATE", domain.GetErrorMessage(lang, "OAUTH2_INVALID_STATE"), nil)
@@ -172,12 +269,25 @@ func HandleErrorL(w http.ResponseWriter, r *http.Request, err error) {
 	case errors.Is(err, domain.ErrBlockCodeHidden):
 		Error(w, http.StatusForbidden, "BLOCK_CODE_HIDDEN", domain.GetErrorMessage(lang, "BLOCK_CODE_HIDDEN"), nil)
 
+	case errors.Is(err, domain.ErrTemplateReviewExists):
+		Error(w, http.StatusConflict, "TEMPLATE_REVIEW_EXISTS", domain.GetErrorMessage(lang, "TEMPLATE_REVIEW_EXISTS"), nil)
+	case errors.Is(err, domain.ErrTemplateNotUsed):
+		Error(w, http.StatusForbidden, "TEMPLATE_NOT_USED", domain.GetErrorMessage(lang, "TEMPLATE_NOT_USED"), nil)
+
 	case errors.Is(err, domain.ErrProjectAlreadyPublished):
 		Error(w, http.StatusConflict, "PROJECT_ALREADY_PUBLISHED", domain.GetErrorMessage(lang, "PROJECT_ALREADY_PUBLISHED"), nil)
+	case errors.Is(err, domain.ErrProjectAccessDenied):
+		Error(w, http.StatusForbidden, "WORKFLOW_ACCESS_DENIED", domain.GetErrorMessage(lang, "WORKFLOW_ACCESS_DENIED"), nil)
 	case errors.Is(err, domain.ErrProjectNotEditable):
 		Error(w, http.StatusConflict, "PROJECT_NOT_EDITABLE", domain.GetErrorMessage(lang, "PROJECT_NOT_EDITABLE"), nil)
+	case errors.Is(err, domain.ErrProjectPaused):
+		Error(w, http.StatusConflict, "PROJECT_PAUSED", domain.GetErrorMessage(lang, "PROJECT_PAUSED"), nil)
 	case errors.Is(err, domain.ErrEdgeDuplicate):
 		Error(w, http.StatusConflict, "EDGE_DUPLICATE", domain.GetErrorMessage(lang, "EDGE_DUPLICATE"), nil)
+	case errors.Is(err, domain.ErrConcurrentModification):
+		Error(w, http.StatusConflict, "CONCURRENT_MODIFICATION", domain.GetErrorMessage(lang, "CONCURRENT_MODIFICATION"), nil)
+	case errors.Is(err, domain.ErrJSONPatchTestFailed):
+		Error(w, http.StatusConflict, "PATCH_TEST_FAILED", domain.GetErrorMessage(lang, "PATCH_TEST_FAILED"), nil)
 
 	case errors.Is(err, domain.ErrProjectHasCycle):
 		Error(w, http.StatusBadRequest, "PROJECT_HAS_CYCLE", domain.GetErrorMessage(lang, "PROJECT_HAS_CYCLE"), nil)
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -15,6 +17,7 @@ import (
 type RunHandler struct {
 	runUsecase   *usecase.RunUsecase
 	auditService *usecase.AuditService
+	permissions  *usecase.ProjectPermissionUsecase // Optional; hides runs of workflows the user cannot view
 }
 
 // NewRunHandler creates a new RunHandler
@@ -25,6 +28,13 @@ func NewRunHandler(runUsecase *usecase.RunUsecase, auditService *usecase.AuditSe
 	}
 }
 
+// WithProjectPermissions enforces workflow permissions on run listings that span workflows:
+// search and batch only return runs of workflows the user may view
+func (h *RunHandler) WithProjectPermissions(permissions *usecase.ProjectPermissionUsecase) *RunHandler {
+	h.permissions = permissions
+	return h
+}
+
 // CreateRunRequest represents a create run request
 type CreateRunRequest struct {
 	Input       json.RawMessage `json:"input"`
@@ -117,6 +127,7 @@ func (h *RunHandler) List(w http.ResponseWriter, r *http.Request) {
 	output, err := h.runUsecase.List(r.Context(), usecase.ListRunsInput{
 		TenantID:  tenantID,
 		ProjectID: projectID,
+		Cursor:    r.URL.Query().Get("cursor"),
 		Page:      page,
 		Limit:     limit,
 	})
@@ -125,7 +136,131 @@ func (h *RunHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	JSONList(w, http.StatusOK, output.Runs, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, output.Runs, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
+}
+
+// Profile handles GET /api/v1/projects/{project_id}/profile
+// Query parameters: runs (number of most recent runs to aggregate, default 20, max 100)
+func (h *RunHandler) Profile(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	projectID, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	profile, err := h.runUsecase.Profile(r.Context(), usecase.ProfileProjectInput{
+		TenantID:  tenantID,
+		ProjectID: projectID,
+		Runs:      parseIntQuery(r, "runs", 20),
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, profile)
+}
+
+// Search handles GET /api/v1/runs/search
+// Query parameters: project_id, status, from, to (RFC3339), input, output, metadata
+// (JSON documents matched by containment), cursor, limit
+func (h *RunHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := usecase.SearchRunsInput{
+		TenantID:         getTenantID(r),
+		InputContains:    json.RawMessage(query.Get("input")),
+		OutputContains:   json.RawMessage(query.Get("output")),
+		MetadataContains: json.RawMessage(query.Get("metadata")),
+		Cursor:           query.Get("cursor"),
+		Limit:            parseIntQuery(r, "limit", 20),
+	}
+
+	if projectIDStr := query.Get("project_id"); projectIDStr != "" {
+		projectID, ok := parseUUIDString(w, projectIDStr, "project ID")
+		if !ok {
+			return
+		}
+		if h.permissions != nil {
+			if err := h.permissions.Check(r.Context(), workflowActor(r), projectID, domain.WorkflowRoleViewer); err != nil {
+				HandleErrorL(w, r, err)
+				return
+			}
+		}
+		input.ProjectID = &projectID
+	}
+
+	if statusStr := query.Get("status"); statusStr != "" {
+		status := domain.RunStatus(statusStr)
+		input.Status = &status
+	}
+
+	for _, param := range []struct {
+		key  string
+		dest **time.Time
+	}{
+		{"from", &input.CreatedFrom},
+		{"to", &input.CreatedTo},
+	} {
+		value := query.Get(param.key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			HandleErrorL(w, r, domain.NewValidationError(param.key, "must be an RFC3339 timestamp"))
+			return
+		}
+		*param.dest = &t
+	}
+
+	output, err := h.runUsecase.Search(r.Context(), input)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	// Filtering after the query may shorten a page; the cursor still continues after it
+	output.Runs, _, err = filterViewable(r, h.permissions, output.Runs, func(run *domain.Run) uuid.UUID { return run.ProjectID })
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONCursorList(w, http.StatusOK, output.Runs, output.Limit, output.NextCursor)
+}
+
+// Batch handles GET /api/v1/runs/batch
+// Query parameters: ids (comma-separated run IDs, at most usecase.MaxBatchRunIDs)
+func (h *RunHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var ids []uuid.UUID
+	for _, idStr := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		idStr = strings.TrimSpace(idStr)
+		if idStr == "" {
+			continue
+		}
+		id, ok := parseUUIDString(w, idStr, "run ID")
+		if !ok {
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	output, err := h.runUsecase.GetBatchStatus(r.Context(), getTenantID(r), ids)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	// Runs of workflows the user cannot view are reported as not found
+	var hidden []usecase.RunStatusSummary
+	output.Runs, hidden, err = filterViewable(r, h.permissions, output.Runs, func(run usecase.RunStatusSummary) uuid.UUID { return run.ProjectID })
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	for _, run := range hidden {
+		output.NotFound = append(output.NotFound, run.ID)
+	}
+
+	JSONData(w, http.StatusOK, output)
 }
 
 // Get handles GET /api/v1/runs/{run_id}
@@ -150,7 +285,13 @@ func (h *RunHandler) Get(w http.ResponseWriter, r *http.Request) {
 	JSONData(w, http.StatusOK, response)
 }
 
+// CancelRunRequest represents a cancel run request
+type CancelRunRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
 // Cancel handles POST /api/v1/runs/{run_id}/cancel
+// The request body is optional; the cancelling user is taken from the auth context
 func (h *RunHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	tenantID := getTenantID(r)
 	runID, ok := parseUUID(w, r, "run_id", "run ID")
@@ -158,14 +299,34 @@ func (h *RunHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	run, err := h.runUsecase.Cancel(r.Context(), tenantID, runID)
+	var req CancelRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid request body", nil)
+		return
+	}
+
+	var cancelledBy *uuid.UUID
+	if userID := getUserID(r); userID != uuid.Nil {
+		cancelledBy = &userID
+	}
+
+	run, err := h.runUsecase.Cancel(r.Context(), usecase.CancelRunInput{
+		TenantID:    tenantID,
+		RunID:       runID,
+		CancelledBy: cancelledBy,
+		Reason:      req.Reason,
+	})
 	if err != nil {
 		HandleErrorL(w, r, err)
 		return
 	}
 
 	// Log audit event
-	logAudit(r.Context(), h.auditService, r, domain.AuditActionRunCancel, domain.AuditResourceRun, &runID, nil)
+	var metadata map[string]interface{}
+	if run.CancelReason != nil {
+		metadata = map[string]interface{}{"reason": *run.CancelReason}
+	}
+	logAudit(r.Context(), h.auditService, r, domain.AuditActionRunCancel, domain.AuditResourceRun, &runID, metadata)
 
 	JSONData(w, http.StatusOK, run)
 }
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// CreateRunAnnotationRequest represents a run annotation request
+type CreateRunAnnotationRequest struct {
+	Comment string `json:"comment"`
+	StepID  string `json:"step_id,omitempty"`
+}
+
+// CreateAnnotation handles POST /api/v1/runs/{run_id}/annotations
+func (h *RunHandler) CreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	runID, ok := parseUUID(w, r, "run_id", "run ID")
+	if !ok {
+		return
+	}
+
+	var req CreateRunAnnotationRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	input := usecase.CreateRunAnnotationInput{
+		TenantID: tenantID,
+		RunID:    runID,
+		AuthorID: getUserID(r),
+		Comment:  req.Comment,
+	}
+	if req.StepID != "" {
+		stepID, ok := parseUUIDString(w, req.StepID, "step ID")
+		if !ok {
+			return
+		}
+		input.StepID = &stepID
+	}
+
+	annotation, err := h.runUsecase.CreateAnnotation(r.Context(), input)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusCreated, annotation)
+}
+
+// ListAnnotations handles GET /api/v1/runs/{run_id}/annotations
+func (h *RunHandler) ListAnnotations(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	runID, ok := parseUUID(w, r, "run_id", "run ID")
+	if !ok {
+		return
+	}
+
+	annotations, err := h.runUsecase.ListAnnotations(r.Context(), tenantID, runID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, annotations)
+}
+
+// DeleteAnnotation handles DELETE /api/v1/runs/{run_id}/annotations/{annotation_id}
+func (h *RunHandler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	runID, ok := parseUUID(w, r, "run_id", "run ID")
+	if !ok {
+		return
+	}
+	annotationID, ok := parseUUID(w, r, "annotation_id", "annotation ID")
+	if !ok {
+		return
+	}
+
+	if err := h.runUsecase.DeleteAnnotation(r.Context(), tenantID, runID, annotationID, getUserID(r)); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/engine"
 	"github.com/souta/ai-orchestration/internal/middleware"
@@ -18,6 +19,8 @@ import (
 type RunStreamHandler struct {
 	runUsecase    *usecase.RunUsecase
 	runnerFactory *engine.InlineRunnerFactory
+	eventClient   *redis.Client                     // Optional; relays events published by workers
+	permissions   *usecase.ProjectPermissionUsecase // Optional; requires the runner role to stream a run
 }
 
 // NewRunStreamHandler creates a new run stream handler
@@ -28,6 +31,20 @@ func NewRunStreamHandler(runUsecase *usecase.RunUsecase, factory *engine.InlineR
 	}
 }
 
+// WithEventSubscriber makes StreamRunExecution relay the step and progress events that workers
+// publish to the run's Redis channel (see engine.RedisEventEmitter)
+func (h *RunStreamHandler) WithEventSubscriber(client *redis.Client) *RunStreamHandler {
+	h.eventClient = client
+	return h
+}
+
+// WithProjectPermissions enforces workflow permissions on streamed runs: starting one requires
+// the runner role on the workflow
+func (h *RunStreamHandler) WithProjectPermissions(permissions *usecase.ProjectPermissionUsecase) *RunStreamHandler {
+	h.permissions = permissions
+	return h
+}
+
 // StreamRunExecution handles GET /runs/{run_id}/stream
 // This endpoint is available for ALL workflows, not just Copilot
 // It streams execution events via Server-Sent Events (SSE)
@@ -87,8 +104,10 @@ func (h *RunStreamHandler) StreamRunExecution(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// For pending/running runs, poll for updates
-	// This is a simple polling approach - can be enhanced with pub/sub later
+	// For pending/running runs, poll for status updates and relay the step and progress
+	// events published by the worker (if an event subscriber is configured)
+	runEvents := engine.SubscribeRunEvents(ctx, h.eventClient, runID)
+
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
@@ -145,6 +164,16 @@ func (h *RunStreamHandler) StreamRunExecution(w http.ResponseWriter, r *http.Req
 				return
 			}
 
+		case event, ok := <-runEvents:
+			if !ok {
+				runEvents = nil
+				continue
+			}
+			// Run lifecycle events are derived from the polled status above
+			if relayedRunEvent(event.Type) {
+				h.sendSSEEvent(w, flusher, string(event.Type), event.Data)
+			}
+
 		case <-heartbeatTicker.C:
 			h.sendSSEEvent(w, flusher, "heartbeat", map[string]interface{}{
 				"timestamp": time.Now().Unix(),
@@ -156,6 +185,15 @@ func (h *RunStreamHandler) StreamRunExecution(w http.ResponseWriter, r *http.Req
 	}
 }
 
+// relayedRunEvent reports whether a published event is forwarded by StreamRunExecution
+func relayedRunEvent(eventType engine.ExecutionEventType) bool {
+	switch eventType {
+	case engine.EventStepStarted, engine.EventStepCompleted, engine.EventStepFailed, engine.EventStepSkipped, engine.EventProgress:
+		return true
+	}
+	return false
+}
+
 // CreateAndStreamRunRequest represents the request body for creating and streaming a run
 type CreateAndStreamRunRequest struct {
 	ProjectID   string                 `json:"project_id"`
@@ -183,6 +221,12 @@ func (h *RunStreamHandler) CreateAndStreamRun(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Invalid project_id", http.StatusBadRequest)
 		return
 	}
+	if h.permissions != nil {
+		if err := h.permissions.Check(ctx, workflowActor(r), projectID, domain.WorkflowRoleRunner); err != nil {
+			HandleErrorL(w, r, err)
+			return
+		}
+	}
 
 	var startStepID *uuid.UUID
 	if req.StartStepID != "" {
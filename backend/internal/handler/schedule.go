@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -13,6 +14,7 @@ import (
 type ScheduleHandler struct {
 	usecase      *usecase.ScheduleUsecase
 	auditService *usecase.AuditService
+	permissions  *usecase.ProjectPermissionUsecase // Optional; enforces workflow permissions on routes without a schedule ID
 }
 
 // NewScheduleHandler creates a new ScheduleHandler
@@ -23,6 +25,23 @@ func NewScheduleHandler(uc *usecase.ScheduleUsecase, auditService *usecase.Audit
 	}
 }
 
+// WithProjectPermissions enforces workflow permissions on the schedule routes that name no
+// schedule: creating one requires the editor role on its workflow, and listing only returns
+// schedules of workflows the user may view
+func (h *ScheduleHandler) WithProjectPermissions(permissions *usecase.ProjectPermissionUsecase) *ScheduleHandler {
+	h.permissions = permissions
+	return h
+}
+
+// checkProject returns an error unless the user has at least the role on the workflow, when
+// permissions are enforced
+func (h *ScheduleHandler) checkProject(r *http.Request, projectID uuid.UUID, role domain.WorkflowRole) error {
+	if h.permissions == nil {
+		return nil
+	}
+	return h.permissions.Check(r.Context(), workflowActor(r), projectID, role)
+}
+
 // CreateScheduleRequest represents the request body for creating a schedule
 type CreateScheduleRequest struct {
 	ProjectID      string          `json:"project_id"`
@@ -45,6 +64,10 @@ func (h *ScheduleHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	if err := h.checkProject(r, projectID, domain.WorkflowRoleEditor); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
 
 	tenantID := getTenantID(r)
 	userID := getUserID(r)
@@ -115,6 +138,7 @@ func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	input := usecase.ListSchedulesInput{
 		TenantID: tenantID,
+		Cursor:   r.URL.Query().Get("cursor"),
 		Page:     parseIntQuery(r, "page", 1),
 		Limit:    parseIntQuery(r, "limit", 20),
 	}
@@ -122,6 +146,10 @@ func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Optional project filter
 	if projectIDStr := r.URL.Query().Get("project_id"); projectIDStr != "" {
 		if projectID, err := uuid.Parse(projectIDStr); err == nil {
+			if err := h.checkProject(r, projectID, domain.WorkflowRoleViewer); err != nil {
+				HandleErrorL(w, r, err)
+				return
+			}
 			input.ProjectID = &projectID
 		}
 	}
@@ -137,8 +165,13 @@ func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
 		HandleErrorL(w, r, err)
 		return
 	}
+	output.Schedules, _, err = filterViewable(r, h.permissions, output.Schedules, func(schedule *domain.Schedule) uuid.UUID { return schedule.ProjectID })
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
 
-	JSONList(w, http.StatusOK, output.Schedules, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, output.Schedules, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
 }
 
 // UpdateScheduleRequest represents the request body for updating a schedule
@@ -282,3 +315,117 @@ func (h *ScheduleHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 
 	JSONData(w, http.StatusOK, run)
 }
+
+// PreviewScheduleRequest represents the request body for previewing a schedule
+type PreviewScheduleRequest struct {
+	CronExpression string          `json:"cron_expression"`
+	Timezone       string          `json:"timezone,omitempty"`
+	Start          *time.Time      `json:"start,omitempty"`
+	End            *time.Time      `json:"end,omitempty"`
+	Limit          int             `json:"limit,omitempty"`
+	ProjectID      *string         `json:"project_id,omitempty"` // Optional: estimates the cost of the runs
+	Input          json.RawMessage `json:"input,omitempty"`
+}
+
+// Preview handles POST /api/v1/schedules/preview
+// Lists the fire times of a cron expression within a range and estimates the runs' cost,
+// without creating a schedule.
+func (h *ScheduleHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req PreviewScheduleRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	var projectID *uuid.UUID
+	if req.ProjectID != nil && *req.ProjectID != "" {
+		id, ok := parseUUIDString(w, *req.ProjectID, "project ID")
+		if !ok {
+			return
+		}
+		if err := h.checkProject(r, id, domain.WorkflowRoleViewer); err != nil {
+			HandleErrorL(w, r, err)
+			return
+		}
+		projectID = &id
+	}
+
+	preview, err := h.usecase.Preview(r.Context(), usecase.PreviewScheduleInput{
+		TenantID:       getTenantID(r),
+		CronExpression: req.CronExpression,
+		Timezone:       req.Timezone,
+		Start:          req.Start,
+		End:            req.End,
+		Limit:          req.Limit,
+		ProjectID:      projectID,
+		Input:          req.Input,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	JSONData(w, http.StatusOK, preview)
+}
+
+// BulkScheduleResponse represents the result of pausing or resuming all schedules of a tenant
+type BulkScheduleResponse struct {
+	ScheduleIDs []uuid.UUID `json:"schedule_ids"`
+	Count       int         `json:"count"`
+}
+
+// PauseAll handles POST /api/v1/schedules/pause-all
+func (h *ScheduleHandler) PauseAll(w http.ResponseWriter, r *http.Request) {
+	h.pauseAll(w, r, getTenantID(r))
+}
+
+// ResumeAll handles POST /api/v1/schedules/resume-all
+func (h *ScheduleHandler) ResumeAll(w http.ResponseWriter, r *http.Request) {
+	h.resumeAll(w, r, getTenantID(r))
+}
+
+// AdminPauseAll handles POST /api/v1/admin/tenants/{tenant_id}/schedules/pause-all
+func (h *ScheduleHandler) AdminPauseAll(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseUUID(w, r, "tenant_id", "tenant ID")
+	if !ok {
+		return
+	}
+	h.pauseAll(w, r, tenantID)
+}
+
+// AdminResumeAll handles POST /api/v1/admin/tenants/{tenant_id}/schedules/resume-all
+func (h *ScheduleHandler) AdminResumeAll(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseUUID(w, r, "tenant_id", "tenant ID")
+	if !ok {
+		return
+	}
+	h.resumeAll(w, r, tenantID)
+}
+
+func (h *ScheduleHandler) pauseAll(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) {
+	ids, err := h.usecase.PauseAll(r.Context(), tenantID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAuditForTenant(r.Context(), h.auditService, r, tenantID, domain.AuditActionSchedulePauseAll, domain.AuditResourceSchedule, nil, map[string]interface{}{
+		"count":        len(ids),
+		"schedule_ids": ids,
+	})
+
+	JSONData(w, http.StatusOK, BulkScheduleResponse{ScheduleIDs: ids, Count: len(ids)})
+}
+
+func (h *ScheduleHandler) resumeAll(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) {
+	ids, err := h.usecase.ResumeAll(r.Context(), tenantID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	logAuditForTenant(r.Context(), h.auditService, r, tenantID, domain.AuditActionScheduleResumeAll, domain.AuditResourceSchedule, nil, map[string]interface{}{
+		"count":        len(ids),
+		"schedule_ids": ids,
+	})
+
+	JSONData(w, http.StatusOK, BulkScheduleResponse{ScheduleIDs: ids, Count: len(ids)})
+}
@@ -34,24 +34,11 @@ type CreateStepRequest struct {
 }
 
 // validateCredentialBindings validates the credential_bindings JSON structure
-// Expected format: {"credential_name": "credential_uuid", ...}
+// Expected format: {"credential_name": "credential_uuid" or "{{$.credential_id}}", ...}
 func validateCredentialBindings(data json.RawMessage) error {
-	if len(data) == 0 || string(data) == "null" {
-		return nil
-	}
-	var bindings map[string]string
-	if err := json.Unmarshal(data, &bindings); err != nil {
+	if _, err := domain.ParseCredentialBindingRefs(data); err != nil {
 		return domain.ErrValidation
 	}
-	// Validate UUID format for each credential ID
-	for _, credID := range bindings {
-		if credID == "" {
-			continue
-		}
-		if _, err := uuid.Parse(credID); err != nil {
-			return domain.ErrValidation
-		}
-	}
 	return nil
 }
 
@@ -74,7 +61,8 @@ func (h *StepHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	step, err := h.stepUsecase.Create(r.Context(), usecase.CreateStepInput{
+	// Idempotent by name: a step with the same name in the project is returned with 200
+	step, created, err := h.stepUsecase.CreateOrGet(r.Context(), usecase.CreateStepInput{
 		TenantID:           tenantID,
 		ProjectID:          projectID,
 		Name:               req.Name,
@@ -91,6 +79,10 @@ func (h *StepHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !created {
+		JSONData(w, http.StatusOK, step)
+		return
+	}
 	JSONData(w, http.StatusCreated, step)
 }
 
@@ -126,6 +118,7 @@ type UpdateStepRequest struct {
 }
 
 // Update handles PUT /api/v1/projects/{project_id}/steps/{step_id}
+// A body of Content-Type application/json-patch+json is applied as a JSON Patch (RFC 6902)
 func (h *StepHandler) Update(w http.ResponseWriter, r *http.Request) {
 	tenantID := getTenantID(r)
 	projectID, ok := parseUUID(w, r, "id", "project ID")
@@ -137,6 +130,25 @@ func (h *StepHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isJSONPatchRequest(r) {
+		patch, ok := decodeJSONPatchBody(w, r)
+		if !ok {
+			return
+		}
+		step, err := h.stepUsecase.Patch(r.Context(), usecase.PatchStepInput{
+			TenantID:  tenantID,
+			ProjectID: projectID,
+			StepID:    stepID,
+			Patch:     patch,
+		})
+		if err != nil {
+			HandleErrorL(w, r, err)
+			return
+		}
+		JSONData(w, http.StatusOK, step)
+		return
+	}
+
 	var req UpdateStepRequest
 	if !decodeJSONBody(w, r, &req) {
 		return
@@ -193,6 +205,43 @@ func (h *StepHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// DeleteStepsRequest represents a bulk step deletion request
+type DeleteStepsRequest struct {
+	StepIDs []string `json:"step_ids"`
+}
+
+// DeleteBatch handles DELETE /api/v1/projects/{project_id}/steps/batch
+// Deletes the given steps and all connected edges atomically
+func (h *StepHandler) DeleteBatch(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	projectID, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+
+	var req DeleteStepsRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	stepIDs := make([]uuid.UUID, 0, len(req.StepIDs))
+	for _, idStr := range req.StepIDs {
+		stepID, ok := parseUUIDString(w, idStr, "step ID")
+		if !ok {
+			return
+		}
+		stepIDs = append(stepIDs, stepID)
+	}
+
+	output, err := h.stepUsecase.DeleteBatch(r.Context(), tenantID, projectID, stepIDs)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, output)
+}
+
 // UpdateRetryConfigRequest represents a retry config update request
 type UpdateRetryConfigRequest struct {
 	MaxRetries         int      `json:"max_retries"`
@@ -353,3 +402,24 @@ func (h *StepHandler) GetTriggerStatus(w http.ResponseWriter, r *http.Request) {
 
 	JSONData(w, http.StatusOK, TriggerStatusResponse{Enabled: enabled})
 }
+
+// GetEffectiveConfig handles GET /api/v1/projects/{project_id}/steps/{step_id}/effective-config
+func (h *StepHandler) GetEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	projectID, ok := parseUUID(w, r, "id", "project ID")
+	if !ok {
+		return
+	}
+	stepID, ok := parseUUID(w, r, "step_id", "step ID")
+	if !ok {
+		return
+	}
+
+	config, err := h.stepUsecase.GetEffectiveConfig(r.Context(), tenantID, projectID, stepID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, config)
+}
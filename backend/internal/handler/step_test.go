@@ -48,6 +48,11 @@ func TestValidateCredentialBindings(t *testing.T) {
 			input:   json.RawMessage(`{"api_key": ""}`),
 			wantErr: nil,
 		},
+		{
+			name:    "valid template binding",
+			input:   json.RawMessage(`{"api_key": "{{$.credential_id}}"}`),
+			wantErr: nil,
+		},
 		{
 			name:    "invalid JSON",
 			input:   json.RawMessage(`{invalid`),
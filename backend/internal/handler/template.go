@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -125,8 +126,9 @@ func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
 	scope := r.URL.Query().Get("scope") // "my", "tenant", "public"
 
 	input := usecase.ListTemplatesInput{
-		Page:  page,
-		Limit: limit,
+		Cursor: r.URL.Query().Get("cursor"),
+		Page:   page,
+		Limit:  limit,
 	}
 
 	if category != "" {
@@ -145,6 +147,9 @@ func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
 		// Default: show both tenant and public templates
 		input.TenantID = &tenantID
 	}
+	if r.URL.Query().Get("favorites") == "true" {
+		input.FavoritesOf = &usecase.FavoriteOwner{TenantID: tenantID, UserID: getUserID(r)}
+	}
 
 	output, err := h.templateUsecase.List(r.Context(), input)
 	if err != nil {
@@ -152,7 +157,7 @@ func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	JSONList(w, http.StatusOK, output.Templates, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, output.Templates, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
 }
 
 // ListPublic handles GET /api/v1/templates/marketplace
@@ -165,8 +170,9 @@ func (h *TemplateHandler) ListPublic(w http.ResponseWriter, r *http.Request) {
 	featured := r.URL.Query().Get("featured")
 
 	input := usecase.ListTemplatesInput{
-		Page:  page,
-		Limit: limit,
+		Cursor: r.URL.Query().Get("cursor"),
+		Page:   page,
+		Limit:  limit,
 	}
 
 	if category != "" {
@@ -179,6 +185,18 @@ func (h *TemplateHandler) ListPublic(w http.ResponseWriter, r *http.Request) {
 		isFeatured := true
 		input.IsFeatured = &isFeatured
 	}
+	if r.URL.Query().Get("favorites") == "true" {
+		input.FavoritesOf = &usecase.FavoriteOwner{TenantID: getTenantID(r), UserID: getUserID(r)}
+	}
+	input.Sort = domain.TemplateSort(r.URL.Query().Get("sort"))
+	if minRating := r.URL.Query().Get("min_rating"); minRating != "" {
+		value, err := strconv.ParseFloat(minRating, 64)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid min_rating", nil)
+			return
+		}
+		input.MinRating = &value
+	}
 
 	output, err := h.templateUsecase.List(r.Context(), input)
 	if err != nil {
@@ -186,7 +204,7 @@ func (h *TemplateHandler) ListPublic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	JSONList(w, http.StatusOK, output.Templates, output.Page, output.Limit, output.Total)
+	JSONPage(w, r, output.Templates, ListPage{Page: output.Page, Limit: output.Limit, Total: output.Total, NextCursor: output.NextCursor})
 }
 
 // Get handles GET /api/v1/templates/{id}
@@ -206,6 +224,25 @@ func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
 	JSONData(w, http.StatusOK, template)
 }
 
+// Preview handles GET /api/v1/marketplace/templates/{id}/preview
+// Returns a read-only structural view suitable for rendering a diagram
+func (h *TemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid template ID", nil)
+		return
+	}
+
+	preview, err := h.templateUsecase.Preview(r.Context(), tenantID, id)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, preview)
+}
+
 // UpdateTemplateRequest represents an update template request
 type UpdateTemplateRequest struct {
 	Name        string                      `json:"name"`
@@ -267,6 +304,40 @@ func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Favorite handles POST /api/v1/templates/{id}/favorite
+func (h *TemplateHandler) Favorite(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid template ID", nil)
+		return
+	}
+
+	if err := h.templateUsecase.Favorite(r.Context(), tenantID, getUserID(r), id); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unfavorite handles DELETE /api/v1/templates/{id}/favorite
+func (h *TemplateHandler) Unfavorite(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", "invalid template ID", nil)
+		return
+	}
+
+	if err := h.templateUsecase.Unfavorite(r.Context(), tenantID, getUserID(r), id); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // UseTemplateRequest represents a use template request
 type UseTemplateRequest struct {
 	ProjectName string `json:"project_name"`
@@ -286,7 +357,7 @@ func (h *TemplateHandler) Use(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	project, err := h.templateUsecase.UseTemplate(r.Context(), tenantID, id, req.ProjectName)
+	project, err := h.templateUsecase.UseTemplate(r.Context(), tenantID, getUserID(r), id, req.ProjectName)
 	if err != nil {
 		HandleErrorL(w, r, err)
 		return
@@ -303,6 +374,15 @@ type AddReviewRequest struct {
 
 // AddReview handles POST /api/v1/templates/{id}/reviews
 func (h *TemplateHandler) AddReview(w http.ResponseWriter, r *http.Request) {
+	h.saveReview(w, r, false)
+}
+
+// UpsertReview handles PUT /api/v1/templates/{id}/reviews (create or edit the user's own review)
+func (h *TemplateHandler) UpsertReview(w http.ResponseWriter, r *http.Request) {
+	h.saveReview(w, r, true)
+}
+
+func (h *TemplateHandler) saveReview(w http.ResponseWriter, r *http.Request, upsert bool) {
 	tenantID := getTenantID(r)
 	userID := getUserID(r)
 	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -316,12 +396,23 @@ func (h *TemplateHandler) AddReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	review, err := h.templateUsecase.AddReview(r.Context(), tenantID, templateID, userID, req.Rating, req.Comment)
+	review, err := h.templateUsecase.AddReview(r.Context(), usecase.AddReviewInput{
+		TenantID:   tenantID,
+		TemplateID: templateID,
+		UserID:     userID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+		Upsert:     upsert,
+	})
 	if err != nil {
 		HandleErrorL(w, r, err)
 		return
 	}
 
+	if upsert {
+		JSONData(w, http.StatusOK, review)
+		return
+	}
 	JSONData(w, http.StatusCreated, review)
 }
 
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/engine"
+	"github.com/souta/ai-orchestration/internal/usecase"
+)
+
+// ToolsHandler handles editor helper endpoints that test expressions and templates
+// against sample data or check code without running a workflow
+type ToolsHandler struct {
+	evaluator *engine.ConditionEvaluator
+	now       func() time.Time
+}
+
+// NewToolsHandler creates a new ToolsHandler
+func NewToolsHandler() *ToolsHandler {
+	return &ToolsHandler{evaluator: engine.NewConditionEvaluator(), now: time.Now}
+}
+
+// ExpressionMode selects how an expression is evaluated
+type ExpressionMode string
+
+const (
+	ExpressionModeCondition ExpressionMode = "condition" // Boolean condition, as used by condition/switch/filter blocks
+	ExpressionModeResolve   ExpressionMode = "resolve"   // Single value or path (e.g. $.user.email)
+)
+
+// EvaluateExpressionRequest represents an expression test request
+type EvaluateExpressionRequest struct {
+	Expression string          `json:"expression"`
+	Input      json.RawMessage `json:"input,omitempty"`
+	Mode       ExpressionMode  `json:"mode,omitempty"` // Defaults to condition
+}
+
+// EvaluateExpressionResponse is the result of a successful evaluation
+type EvaluateExpressionResponse struct {
+	Mode   ExpressionMode `json:"mode"`
+	Result interface{}    `json:"result"`
+}
+
+// ExpressionErrorDetails describes why an expression could not be evaluated
+type ExpressionErrorDetails struct {
+	Expression string `json:"expression"`
+	Reason     string `json:"reason"`
+}
+
+// EvaluateExpression handles POST /api/v1/tools/evaluate-expression
+// Evaluation failures are reported as 422 EXPRESSION_EVALUATION_FAILED with the reason in details.
+func (h *ToolsHandler) EvaluateExpression(w http.ResponseWriter, r *http.Request) {
+	var req EvaluateExpressionRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = ExpressionModeCondition
+	}
+	if req.Mode != ExpressionModeCondition && req.Mode != ExpressionModeResolve {
+		HandleErrorL(w, r, domain.NewValidationError("mode", "mode must be condition or resolve"))
+		return
+	}
+	if strings.TrimSpace(req.Expression) == "" {
+		HandleErrorL(w, r, domain.NewValidationError("expression", "expression is required"))
+		return
+	}
+
+	var result interface{}
+	var err error
+	switch req.Mode {
+	case ExpressionModeResolve:
+		result, err = h.evaluator.ResolveValue(req.Expression, sampleInputMap(req.Input))
+	default:
+		result, err = h.evaluator.Evaluate(req.Expression, req.Input)
+	}
+	if err != nil {
+		Error(w, http.StatusUnprocessableEntity, "EXPRESSION_EVALUATION_FAILED", "expression could not be evaluated",
+			ExpressionErrorDetails{Expression: req.Expression, Reason: err.Error()})
+		return
+	}
+
+	JSONData(w, http.StatusOK, EvaluateExpressionResponse{Mode: req.Mode, Result: result})
+}
+
+// sampleInputMap decodes sample input the way ConditionEvaluator.Evaluate does:
+// objects are used as-is and any other JSON value is exposed as "value"
+func sampleInputMap(input json.RawMessage) map[string]interface{} {
+	data := make(map[string]interface{})
+	if len(input) == 0 {
+		return data
+	}
+	if err := json.Unmarshal(input, &data); err != nil || data == nil {
+		var value interface{}
+		_ = json.Unmarshal(input, &value)
+		return map[string]interface{}{"value": value}
+	}
+	return data
+}
+
+// RenderTemplateRequest represents a template substitution test request.
+// Exactly one of Template (a string) or Config (any JSON value, e.g. a step config) is required.
+type RenderTemplateRequest struct {
+	Template  *string                  `json:"template,omitempty"`
+	Config    json.RawMessage          `json:"config,omitempty"`
+	Input     json.RawMessage          `json:"input,omitempty"`
+	Variables *RenderTemplateVariables `json:"variables,omitempty"`
+}
+
+// RenderTemplateVariables holds sample values for scoped variables ({{$org.x}}, {{$project.x}}, {{$personal.x}})
+// and the trigger metadata ({{$trigger.x}})
+type RenderTemplateVariables struct {
+	Org      map[string]interface{} `json:"org,omitempty"`
+	Project  map[string]interface{} `json:"project,omitempty"`
+	Personal map[string]interface{} `json:"personal,omitempty"`
+	Trigger  map[string]interface{} `json:"trigger,omitempty"`
+}
+
+// RenderTemplateResponse is the rendered value with any template paths that did not resolve
+type RenderTemplateResponse struct {
+	Result  json.RawMessage `json:"result"`
+	Missing []string        `json:"missing"`
+}
+
+// RenderTemplate handles POST /api/v1/tools/render-template
+// Uses the executor's substitution logic; unresolved paths render as empty values and are listed in missing.
+func (h *ToolsHandler) RenderTemplate(w http.ResponseWriter, r *http.Request) {
+	var req RenderTemplateRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	hasConfig := len(req.Config) > 0 && string(req.Config) != "null"
+	if (req.Template == nil) == !hasConfig {
+		HandleErrorL(w, r, domain.NewValidationError("template", "exactly one of template or config is required"))
+		return
+	}
+
+	config := req.Config
+	if req.Template != nil {
+		config, _ = json.Marshal(*req.Template)
+	}
+
+	var scopes *engine.ScopedVariables
+	if req.Variables != nil {
+		scopes = &engine.ScopedVariables{
+			Org:      req.Variables.Org,
+			Project:  req.Variables.Project,
+			Personal: req.Variables.Personal,
+			Trigger:  req.Variables.Trigger,
+		}
+	}
+
+	rendered, err := engine.ExpandConfigTemplatesWithScopes(config, req.Input, scopes)
+	if err != nil {
+		HandleErrorL(w, r, domain.NewValidationError("config", "config must be valid JSON"))
+		return
+	}
+	missing, err := engine.FindUnresolvedTemplatePaths(config, req.Input, scopes)
+	if err != nil {
+		HandleErrorL(w, r, domain.NewValidationError("config", "config must be valid JSON"))
+		return
+	}
+
+	JSONData(w, http.StatusOK, RenderTemplateResponse{Result: rendered, Missing: missing})
+}
+
+// AnalyzeCodeRequest represents a code analysis request for function/code blocks
+type AnalyzeCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// AnalyzeCode handles POST /api/v1/tools/analyze-code
+// Runs the check_security pattern checks and a sandbox compile; findings never block the request.
+func (h *ToolsHandler) AnalyzeCode(w http.ResponseWriter, r *http.Request) {
+	var req AnalyzeCodeRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	if strings.TrimSpace(req.Code) == "" {
+		HandleErrorL(w, r, domain.NewValidationError("code", "code is required"))
+		return
+	}
+
+	JSONData(w, http.StatusOK, sandbox.AnalyzeCode(req.Code))
+}
+
+// DefaultCronPreviewRuns is the number of fire times a cron preview returns by default
+const DefaultCronPreviewRuns = 5
+
+// CronPreviewRequest represents a cron expression check request
+type CronPreviewRequest struct {
+	Expression string `json:"expression"`
+	Timezone   string `json:"timezone,omitempty"` // Defaults to the deployment default timezone
+	Count      int    `json:"count,omitempty"`    // Number of fire times, defaults to DefaultCronPreviewRuns
+}
+
+// CronPreviewResponse reports whether a cron expression is valid, with its description and next
+// fire times when it is, or the reason when it is not
+type CronPreviewResponse struct {
+	Valid       bool        `json:"valid"`
+	Expression  string      `json:"expression"`
+	Timezone    string      `json:"timezone"`
+	Normalized  string      `json:"normalized,omitempty"` // Expression as a schedule stores it
+	Description string      `json:"description,omitempty"`
+	NextRuns    []time.Time `json:"next_runs,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// Cron handles POST /api/v1/tools/cron
+// Uses the scheduler's cron parser, so the preview matches when schedules fire. An invalid
+// expression is reported with valid=false rather than an error status.
+func (h *ToolsHandler) Cron(w http.ResponseWriter, r *http.Request) {
+	var req CronPreviewRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = domain.DefaultTimezone()
+	}
+	if err := domain.ValidateTimezone(req.Timezone); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	if req.Count == 0 {
+		req.Count = DefaultCronPreviewRuns
+	}
+	if req.Count < 1 || req.Count > usecase.MaxCronPreviewRuns {
+		HandleErrorL(w, r, domain.NewValidationError("count", fmt.Sprintf("count must be between 1 and %d", usecase.MaxCronPreviewRuns)))
+		return
+	}
+
+	resp := CronPreviewResponse{Expression: req.Expression, Timezone: req.Timezone}
+	runs, err := usecase.NextCronRuns(req.Expression, req.Timezone, h.now(), req.Count)
+	var cronErr *domain.InvalidCronError
+	if errors.As(err, &cronErr) {
+		resp.Error = cronErr.Reason
+		JSONData(w, http.StatusOK, resp)
+		return
+	}
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	resp.Valid = true
+	resp.NextRuns = runs
+	resp.Normalized, _ = usecase.NormalizeCron(req.Expression)
+	resp.Description, _ = usecase.DescribeCron(req.Expression)
+	JSONData(w, http.StatusOK, resp)
+}
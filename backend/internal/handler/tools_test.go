@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToolsHandler_EvaluateExpression(t *testing.T) {
+	sample := json.RawMessage(`{"status": 200, "user": {"email": "a@example.com", "tags": ["vip"]}}`)
+
+	tests := []struct {
+		name       string
+		body       EvaluateExpressionRequest
+		wantStatus int
+		wantResult interface{}
+		wantCode   string
+	}{
+		{
+			name:       "numeric comparison",
+			body:       EvaluateExpressionRequest{Expression: "$.status >= 200", Input: sample},
+			wantStatus: http.StatusOK,
+			wantResult: true,
+		},
+		{
+			name:       "string equality on nested path",
+			body:       EvaluateExpressionRequest{Expression: `$.user.email == "b@example.com"`, Input: sample},
+			wantStatus: http.StatusOK,
+			wantResult: false,
+		},
+		{
+			name:       "resolve nested path",
+			body:       EvaluateExpressionRequest{Expression: "$.user.email", Input: sample, Mode: ExpressionModeResolve},
+			wantStatus: http.StatusOK,
+			wantResult: "a@example.com",
+		},
+		{
+			name:       "resolve array value",
+			body:       EvaluateExpressionRequest{Expression: "$.user.tags", Input: sample, Mode: ExpressionModeResolve},
+			wantStatus: http.StatusOK,
+			wantResult: []interface{}{"vip"},
+		},
+		{
+			name:       "resolve non-object input as value",
+			body:       EvaluateExpressionRequest{Expression: "value", Input: json.RawMessage(`42`), Mode: ExpressionModeResolve},
+			wantStatus: http.StatusOK,
+			wantResult: float64(42),
+		},
+		{
+			name:       "resolve missing field",
+			body:       EvaluateExpressionRequest{Expression: "$.user.phone", Input: sample, Mode: ExpressionModeResolve},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "EXPRESSION_EVALUATION_FAILED",
+		},
+		{
+			name:       "malformed comparison",
+			body:       EvaluateExpressionRequest{Expression: "$.status == ", Input: sample},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "EXPRESSION_EVALUATION_FAILED",
+		},
+		{
+			name:       "empty expression",
+			body:       EvaluateExpressionRequest{Input: sample},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+		{
+			name:       "unknown mode",
+			body:       EvaluateExpressionRequest{Expression: "true", Mode: "javascript"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+	}
+
+	h := NewToolsHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest(http.MethodPost, "/api/v1/tools/evaluate-expression", tt.body)
+			w := httptest.NewRecorder()
+
+			h.EvaluateExpression(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to decode error response: %v", err)
+				}
+				if resp.Error.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", resp.Error.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp struct {
+				Data EvaluateExpressionResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(resp.Data.Result, tt.wantResult) {
+				t.Errorf("result = %#v, want %#v", resp.Data.Result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestToolsHandler_EvaluateExpression_ReportsReason(t *testing.T) {
+	body := EvaluateExpressionRequest{Expression: "$.missing.path", Input: json.RawMessage(`{}`), Mode: ExpressionModeResolve}
+	req := createTestRequest(http.MethodPost, "/api/v1/tools/evaluate-expression", body)
+	w := httptest.NewRecorder()
+
+	NewToolsHandler().EvaluateExpression(w, req)
+
+	var resp struct {
+		Error struct {
+			Code    string                 `json:"code"`
+			Details ExpressionErrorDetails `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Details.Expression != "$.missing.path" || resp.Error.Details.Reason != "field not found: missing" {
+		t.Errorf("details = %+v, want expression and reason", resp.Error.Details)
+	}
+}
+
+func TestToolsHandler_RenderTemplate(t *testing.T) {
+	sample := json.RawMessage(`{"user": {"name": "Ann", "roles": ["admin", "dev"], "address": {"city": "Tokyo"}}}`)
+	template := func(s string) *string { return &s }
+
+	tests := []struct {
+		name        string
+		body        RenderTemplateRequest
+		wantResult  string
+		wantMissing []string
+	}{
+		{
+			name:        "nested path in string",
+			body:        RenderTemplateRequest{Template: template("Hello {{user.name}} from {{$.user.address.city}}"), Input: sample},
+			wantResult:  `"Hello Ann from Tokyo"`,
+			wantMissing: []string{},
+		},
+		{
+			name:        "missing key renders empty and is reported",
+			body:        RenderTemplateRequest{Template: template("Dear {{user.nmae}},"), Input: sample},
+			wantResult:  `"Dear ,"`,
+			wantMissing: []string{"user.nmae"},
+		},
+		{
+			name:        "whole-value template keeps array and object types",
+			body:        RenderTemplateRequest{Config: json.RawMessage(`{"roles": "{{user.roles}}", "address": "{{user.address}}"}`), Input: sample},
+			wantResult:  `{"address": {"city": "Tokyo"}, "roles": ["admin", "dev"]}`,
+			wantMissing: []string{},
+		},
+		{
+			name:        "object embedded in string is JSON encoded",
+			body:        RenderTemplateRequest{Template: template("addr={{user.address}}"), Input: sample},
+			wantResult:  `"addr={\"city\":\"Tokyo\"}"`,
+			wantMissing: []string{},
+		},
+		{
+			name: "scoped variables",
+			body: RenderTemplateRequest{
+				Config:    json.RawMessage(`{"url": "{{$project.base_url}}/users/{{user.name}}", "token": "{{$org.token}}"}`),
+				Input:     sample,
+				Variables: &RenderTemplateVariables{Project: map[string]interface{}{"base_url": "https://api"}},
+			},
+			wantResult:  `{"token": "", "url": "https://api/users/Ann"}`,
+			wantMissing: []string{"$org.token"},
+		},
+	}
+
+	h := NewToolsHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest(http.MethodPost, "/api/v1/tools/render-template", tt.body)
+			w := httptest.NewRecorder()
+
+			h.RenderTemplate(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body: %s)", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Data RenderTemplateResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			var got, want interface{}
+			_ = json.Unmarshal(resp.Data.Result, &got)
+			_ = json.Unmarshal([]byte(tt.wantResult), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("result = %s, want %s", resp.Data.Result, tt.wantResult)
+			}
+			if !reflect.DeepEqual(resp.Data.Missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", resp.Data.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestToolsHandler_RenderTemplate_RequiresOneSource(t *testing.T) {
+	template := "{{x}}"
+	for _, body := range []RenderTemplateRequest{
+		{},
+		{Template: &template, Config: json.RawMessage(`{"a": "{{x}}"}`)},
+	} {
+		req := createTestRequest(http.MethodPost, "/api/v1/tools/render-template", body)
+		w := httptest.NewRecorder()
+
+		NewToolsHandler().RenderTemplate(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("body %+v: status = %d, want 400", body, w.Code)
+		}
+	}
+}
+
+func TestToolsHandler_AnalyzeCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		wantSafe     bool
+		wantRisk     string
+		wantPatterns []string
+	}{
+		{
+			name:         "dangerous shell command",
+			code:         "return ctx.exec('rm -rf /tmp/' + input.dir);",
+			wantSafe:     false,
+			wantRisk:     "high",
+			wantPatterns: []string{"rm -rf", "exec("},
+		},
+		{
+			name:         "hardcoded credential",
+			code:         "const password = 'hunter2';\nreturn { ok: true };",
+			wantSafe:     true,
+			wantRisk:     "medium",
+			wantPatterns: []string{"password"},
+		},
+		{
+			name:         "clean code",
+			code:         "return { total: input.items.length };",
+			wantSafe:     true,
+			wantRisk:     "low",
+			wantPatterns: []string{},
+		},
+	}
+
+	h := NewToolsHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest(http.MethodPost, "/api/v1/tools/analyze-code", AnalyzeCodeRequest{Code: tt.code})
+			w := httptest.NewRecorder()
+
+			h.AnalyzeCode(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body: %s)", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Data struct {
+					Safe      bool   `json:"safe"`
+					RiskLevel string `json:"risk_level"`
+					Findings  []struct {
+						Pattern string `json:"pattern"`
+					} `json:"findings"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			patterns := []string{}
+			for _, finding := range resp.Data.Findings {
+				patterns = append(patterns, finding.Pattern)
+			}
+			if resp.Data.Safe != tt.wantSafe || resp.Data.RiskLevel != tt.wantRisk || !reflect.DeepEqual(patterns, tt.wantPatterns) {
+				t.Errorf("got safe=%v risk=%s patterns=%v, want safe=%v risk=%s patterns=%v",
+					resp.Data.Safe, resp.Data.RiskLevel, patterns, tt.wantSafe, tt.wantRisk, tt.wantPatterns)
+			}
+		})
+	}
+}
+
+func TestToolsHandler_AnalyzeCode_RequiresCode(t *testing.T) {
+	req := createTestRequest(http.MethodPost, "/api/v1/tools/analyze-code", AnalyzeCodeRequest{Code: "   "})
+	w := httptest.NewRecorder()
+
+	NewToolsHandler().AnalyzeCode(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestToolsHandler_Cron(t *testing.T) {
+	h := NewToolsHandler()
+	h.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name         string
+		body         CronPreviewRequest
+		wantValid    bool
+		wantRuns     []time.Time
+		wantDescribe string
+	}{
+		{
+			name:         "valid expression in timezone",
+			body:         CronPreviewRequest{Expression: "0 9 * * 1-5", Timezone: "Asia/Tokyo", Count: 2},
+			wantValid:    true,
+			wantRuns:     []time.Time{time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+			wantDescribe: "At 09:00 on Monday through Friday",
+		},
+		{
+			name:         "default count and timezone",
+			body:         CronPreviewRequest{Expression: "0 * * * *"},
+			wantValid:    true,
+			wantRuns:     []time.Time{time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 16, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)},
+			wantDescribe: "Every hour",
+		},
+		{
+			name: "invalid expression",
+			body: CronPreviewRequest{Expression: "0 9 * *"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest(http.MethodPost, "/api/v1/tools/cron", tt.body)
+			w := httptest.NewRecorder()
+
+			h.Cron(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body: %s)", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Data CronPreviewResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Data.Valid != tt.wantValid {
+				t.Fatalf("valid = %v, want %v (body: %s)", resp.Data.Valid, tt.wantValid, w.Body.String())
+			}
+			if !tt.wantValid {
+				if resp.Data.Error == "" {
+					t.Errorf("invalid expression reported without an error")
+				}
+				return
+			}
+			if resp.Data.Description != tt.wantDescribe {
+				t.Errorf("description = %q, want %q", resp.Data.Description, tt.wantDescribe)
+			}
+			if len(resp.Data.NextRuns) != len(tt.wantRuns) {
+				t.Fatalf("next_runs = %v, want %v", resp.Data.NextRuns, tt.wantRuns)
+			}
+			for i, run := range resp.Data.NextRuns {
+				if !run.Equal(tt.wantRuns[i]) {
+					t.Errorf("next_runs[%d] = %v, want %v", i, run.UTC(), tt.wantRuns[i])
+				}
+			}
+		})
+	}
+}
+
+func TestToolsHandler_Cron_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body CronPreviewRequest
+	}{
+		{name: "unknown timezone", body: CronPreviewRequest{Expression: "0 9 * * *", Timezone: "Mars/Olympus_Mons"}},
+		{name: "count too large", body: CronPreviewRequest{Expression: "0 9 * * *", Count: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest(http.MethodPost, "/api/v1/tools/cron", tt.body)
+			w := httptest.NewRecorder()
+
+			NewToolsHandler().Cron(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (body: %s)", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -2,32 +2,48 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/engine"
 	"github.com/souta/ai-orchestration/internal/repository/postgres"
+	"github.com/souta/ai-orchestration/pkg/crypto"
 )
 
 // VariablesHandler handles HTTP requests for environment variables
 type VariablesHandler struct {
-	tenantRepo *postgres.TenantRepository
-	userRepo   *postgres.UserRepository
+	tenantRepo  *postgres.TenantRepository
+	userRepo    *postgres.UserRepository
+	projectRepo *postgres.ProjectRepository
+	encryptor   *crypto.Encryptor
 }
 
-// NewVariablesHandler creates a new VariablesHandler
-func NewVariablesHandler(pool *pgxpool.Pool) *VariablesHandler {
+// NewVariablesHandler creates a new VariablesHandler. The encryptor seals secret variables.
+func NewVariablesHandler(pool *pgxpool.Pool, encryptor *crypto.Encryptor) *VariablesHandler {
 	return &VariablesHandler{
-		tenantRepo: postgres.NewTenantRepository(pool),
-		userRepo:   postgres.NewUserRepository(pool),
+		tenantRepo:  postgres.NewTenantRepository(pool),
+		userRepo:    postgres.NewUserRepository(pool),
+		projectRepo: postgres.NewProjectRepository(pool),
+		encryptor:   encryptor,
 	}
 }
 
-// VariablesResponse represents the response for variables
+// VariablesResponse represents the response for variables. Secret variables are returned as
+// {"secret": true, "value": "***"}.
 type VariablesResponse struct {
 	Variables map[string]interface{} `json:"variables"`
 }
 
+// ResolvedVariablesResponse represents the effective variables of a project and the level
+// (project, personal or org) each one comes from
+type ResolvedVariablesResponse struct {
+	Variables map[string]interface{} `json:"variables"`
+	Sources   map[string]string      `json:"sources"`
+}
+
 // UpdateVariablesRequest represents the request body for updating variables
 type UpdateVariablesRequest struct {
 	Variables map[string]interface{} `json:"variables"`
@@ -47,7 +63,7 @@ func (h *VariablesHandler) GetTenantVariables(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	JSON(w, http.StatusOK, VariablesResponse{Variables: variables})
+	JSON(w, http.StatusOK, VariablesResponse{Variables: engine.MaskVariables(variables)})
 }
 
 // UpdateTenantVariables updates organization-level variables
@@ -68,12 +84,22 @@ func (h *VariablesHandler) UpdateTenantVariables(w http.ResponseWriter, r *http.
 		req.Variables = make(map[string]interface{})
 	}
 
-	if err := h.tenantRepo.UpdateVariables(r.Context(), tenantID, req.Variables); err != nil {
+	current, err := h.tenantRepo.GetVariables(r.Context(), tenantID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	variables, ok := h.sealVariables(w, r, req.Variables, current)
+	if !ok {
+		return
+	}
+
+	if err := h.tenantRepo.UpdateVariables(r.Context(), tenantID, variables); err != nil {
 		HandleErrorL(w, r, err)
 		return
 	}
 
-	JSON(w, http.StatusOK, VariablesResponse{Variables: req.Variables})
+	JSON(w, http.StatusOK, VariablesResponse{Variables: engine.MaskVariables(variables)})
 }
 
 // GetUserVariables retrieves personal variables for the current user
@@ -96,7 +122,7 @@ func (h *VariablesHandler) GetUserVariables(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	JSON(w, http.StatusOK, VariablesResponse{Variables: variables})
+	JSON(w, http.StatusOK, VariablesResponse{Variables: engine.MaskVariables(variables)})
 }
 
 // UpdateUserVariables updates personal variables for the current user
@@ -123,10 +149,85 @@ func (h *VariablesHandler) UpdateUserVariables(w http.ResponseWriter, r *http.Re
 		req.Variables = make(map[string]interface{})
 	}
 
-	if err := h.userRepo.UpdateVariables(r.Context(), tenantID, userID, req.Variables); err != nil {
+	current, err := h.userRepo.GetVariables(r.Context(), tenantID, userID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+	variables, ok := h.sealVariables(w, r, req.Variables, current)
+	if !ok {
+		return
+	}
+
+	if err := h.userRepo.UpdateVariables(r.Context(), tenantID, userID, variables); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, VariablesResponse{Variables: engine.MaskVariables(variables)})
+}
+
+// GetResolvedVariables returns the variables a run of the project would resolve without a scope
+// prefix: project variables override the current user's personal variables, which override the
+// organization variables. Step input, which has the highest precedence, is not included.
+func (h *VariablesHandler) GetResolvedVariables(w http.ResponseWriter, r *http.Request) {
+	tenantID := getTenantID(r)
+	if tenantID == uuid.Nil {
+		Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "tenant ID not found", nil)
+		return
+	}
+
+	rawID := r.URL.Query().Get("workflow_id")
+	if rawID == "" {
+		rawID = r.URL.Query().Get("project_id")
+	}
+	projectID, err := uuid.Parse(rawID)
+	if err != nil {
+		Error(w, http.StatusBadRequest, "INVALID_ID", "workflow_id is required and must be a UUID", nil)
+		return
+	}
+
+	project, err := h.projectRepo.GetByID(r.Context(), tenantID, projectID)
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	scopes := &engine.ScopedVariables{}
+	if scopes.Org, err = h.tenantRepo.GetVariables(r.Context(), tenantID); err != nil {
 		HandleErrorL(w, r, err)
 		return
 	}
+	if userID := getUserID(r); userID != uuid.Nil {
+		if scopes.Personal, err = h.userRepo.GetVariables(r.Context(), tenantID, userID); err != nil {
+			HandleErrorL(w, r, err)
+			return
+		}
+	}
+	if len(project.Variables) > 0 {
+		if err := json.Unmarshal(project.Variables, &scopes.Project); err != nil {
+			HandleErrorL(w, r, fmt.Errorf("unmarshal project variables: %w", err))
+			return
+		}
+	}
+
+	scopes.Org = engine.MaskVariables(scopes.Org)
+	scopes.Personal = engine.MaskVariables(scopes.Personal)
 
-	JSON(w, http.StatusOK, VariablesResponse{Variables: req.Variables})
+	variables, sources := engine.NewVariableResolver(scopes).Merged()
+	JSON(w, http.StatusOK, ResolvedVariablesResponse{Variables: variables, Sources: sources})
+}
+
+// sealVariables encrypts the secret variables of an update, writing the error response on failure
+func (h *VariablesHandler) sealVariables(w http.ResponseWriter, r *http.Request, update, current map[string]interface{}) (map[string]interface{}, bool) {
+	variables, err := engine.SealVariables(h.encryptor, update, current)
+	if errors.Is(err, engine.ErrSecretValueRequired) {
+		Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return nil, false
+	}
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return nil, false
+	}
+	return variables, true
 }
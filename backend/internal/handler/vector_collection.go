@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+)
+
+// VectorServiceFactory creates a VectorService bound to a tenant
+type VectorServiceFactory func(ctx context.Context, tenantID uuid.UUID) sandbox.VectorService
+
+// VectorCollectionHandler manages the tenant's RAG vector collections.
+// Every request uses a VectorService bound to the caller's tenant, so collection names
+// only ever resolve within that tenant.
+type VectorCollectionHandler struct {
+	newService VectorServiceFactory
+}
+
+// NewVectorCollectionHandler creates a new VectorCollectionHandler
+func NewVectorCollectionHandler(newService VectorServiceFactory) *VectorCollectionHandler {
+	return &VectorCollectionHandler{newService: newService}
+}
+
+// CreateVectorCollectionRequest represents a collection creation request
+type CreateVectorCollectionRequest struct {
+	Name              string `json:"name"`
+	Description       string `json:"description,omitempty"`
+	EmbeddingProvider string `json:"embedding_provider,omitempty"`
+	EmbeddingModel    string `json:"embedding_model,omitempty"`
+	Dimension         int    `json:"dimension,omitempty"` // Defaults to 1536
+	Metric            string `json:"metric,omitempty"`    // cosine (default), l2, inner_product
+}
+
+// List handles GET /api/v1/vector-collections
+func (h *VectorCollectionHandler) List(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.newService(r.Context(), getTenantID(r)).ListCollections()
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusOK, collections)
+}
+
+// Create handles POST /api/v1/vector-collections
+func (h *VectorCollectionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateVectorCollectionRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	if err := sandbox.ValidateCollectionName(req.Name); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	collection, err := h.newService(r.Context(), getTenantID(r)).CreateCollection(req.Name, &sandbox.CreateCollectionOptions{
+		Description:       req.Description,
+		EmbeddingProvider: req.EmbeddingProvider,
+		EmbeddingModel:    req.EmbeddingModel,
+		Dimension:         req.Dimension,
+		Metric:            req.Metric,
+	})
+	if err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	JSONData(w, http.StatusCreated, collection)
+}
+
+// Delete handles DELETE /api/v1/vector-collections/{name}
+// Deletes the collection and all of its documents.
+func (h *VectorCollectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := sandbox.ValidateCollectionName(name); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	if err := h.newService(r.Context(), getTenantID(r)).DeleteCollection(name); err != nil {
+		HandleErrorL(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
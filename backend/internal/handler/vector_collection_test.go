@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/middleware"
+)
+
+// memoryVectorStore holds collections per tenant, keyed like unique_collection_per_tenant
+type memoryVectorStore struct {
+	collections map[uuid.UUID]map[string]sandbox.CollectionInfo
+}
+
+// memoryVectorService is a tenant-bound view of memoryVectorStore
+type memoryVectorService struct {
+	sandbox.VectorService
+	store    *memoryVectorStore
+	tenantID uuid.UUID
+}
+
+func (s *memoryVectorService) ListCollections() ([]sandbox.CollectionInfo, error) {
+	result := []sandbox.CollectionInfo{}
+	for _, info := range s.store.collections[s.tenantID] {
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+func (s *memoryVectorService) CreateCollection(name string, opts *sandbox.CreateCollectionOptions) (*sandbox.CollectionInfo, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if sandbox.SystemVectorCollections[name] {
+		return nil, domain.ErrVectorCollectionReadOnly
+	}
+	if _, exists := s.store.collections[s.tenantID][name]; exists {
+		return nil, domain.ErrVectorCollectionExists
+	}
+	if s.store.collections[s.tenantID] == nil {
+		s.store.collections[s.tenantID] = make(map[string]sandbox.CollectionInfo)
+	}
+	info := sandbox.CollectionInfo{Name: name, Dimension: opts.Dimension, Metric: opts.Metric}
+	s.store.collections[s.tenantID][name] = info
+	return &info, nil
+}
+
+func (s *memoryVectorService) DeleteCollection(name string) error {
+	if _, exists := s.store.collections[s.tenantID][name]; !exists {
+		return domain.ErrVectorCollectionNotFound
+	}
+	delete(s.store.collections[s.tenantID], name)
+	return nil
+}
+
+func newTestVectorCollectionHandler() *VectorCollectionHandler {
+	store := &memoryVectorStore{collections: make(map[uuid.UUID]map[string]sandbox.CollectionInfo)}
+	return NewVectorCollectionHandler(func(ctx context.Context, tenantID uuid.UUID) sandbox.VectorService {
+		return &memoryVectorService{store: store, tenantID: tenantID}
+	})
+}
+
+// withTenant replaces the tenant set by createTestRequest
+func withTenant(r *http.Request, tenantID uuid.UUID) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), middleware.TenantIDKey, tenantID))
+}
+
+func listCollectionNames(t *testing.T, h *VectorCollectionHandler, tenantID uuid.UUID) []string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.List(w, withTenant(createTestRequest(http.MethodGet, "/api/v1/vector-collections", nil), tenantID))
+	if w.Code != http.StatusOK {
+		t.Fatalf("List status = %d, want 200", w.Code)
+	}
+	var resp struct {
+		Data []sandbox.CollectionInfo `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	names := []string{}
+	for _, info := range resp.Data {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+func TestVectorCollectionHandler_CreateListDelete(t *testing.T) {
+	h := newTestVectorCollectionHandler()
+	tenantID := uuid.New()
+
+	w := httptest.NewRecorder()
+	body := CreateVectorCollectionRequest{Name: "product-docs", Dimension: 1024, Metric: sandbox.VectorMetricL2}
+	h.Create(w, withTenant(createTestRequest(http.MethodPost, "/api/v1/vector-collections", body), tenantID))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create status = %d, want 201 (body: %s)", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data sandbox.CollectionInfo `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Data.Dimension != 1024 || created.Data.Metric != "l2" {
+		t.Errorf("created = %+v, want dimension 1024 and metric l2", created.Data)
+	}
+
+	if names := listCollectionNames(t, h, tenantID); len(names) != 1 || names[0] != "product-docs" {
+		t.Fatalf("collections = %v, want [product-docs]", names)
+	}
+
+	w = httptest.NewRecorder()
+	h.Create(w, withTenant(createTestRequest(http.MethodPost, "/api/v1/vector-collections", body), tenantID))
+	if w.Code != http.StatusConflict {
+		t.Errorf("duplicate Create status = %d, want 409", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req := setChiURLParam(createTestRequest(http.MethodDelete, "/api/v1/vector-collections/product-docs", nil), "name", "product-docs")
+	h.Delete(w, withTenant(req, tenantID))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Delete status = %d, want 204", w.Code)
+	}
+	if names := listCollectionNames(t, h, tenantID); len(names) != 0 {
+		t.Errorf("collections after delete = %v, want none", names)
+	}
+}
+
+func TestVectorCollectionHandler_TenantIsolation(t *testing.T) {
+	h := newTestVectorCollectionHandler()
+	tenantA, tenantB := uuid.New(), uuid.New()
+
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(createTestRequest(http.MethodPost, "/api/v1/vector-collections", CreateVectorCollectionRequest{Name: "kb"}), tenantA))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create status = %d, want 201", w.Code)
+	}
+
+	if names := listCollectionNames(t, h, tenantB); len(names) != 0 {
+		t.Errorf("tenant B sees %v, want no collections", names)
+	}
+
+	// Tenant B cannot delete tenant A's collection by name
+	w = httptest.NewRecorder()
+	req := setChiURLParam(createTestRequest(http.MethodDelete, "/api/v1/vector-collections/kb", nil), "name", "kb")
+	h.Delete(w, withTenant(req, tenantB))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("cross-tenant Delete status = %d, want 404", w.Code)
+	}
+	if names := listCollectionNames(t, h, tenantA); len(names) != 1 {
+		t.Errorf("tenant A collections = %v, want [kb]", names)
+	}
+
+	// The same name is available to tenant B
+	w = httptest.NewRecorder()
+	h.Create(w, withTenant(createTestRequest(http.MethodPost, "/api/v1/vector-collections", CreateVectorCollectionRequest{Name: "kb"}), tenantB))
+	if w.Code != http.StatusCreated {
+		t.Errorf("tenant B Create status = %d, want 201", w.Code)
+	}
+}
+
+func TestVectorCollectionHandler_Create_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body CreateVectorCollectionRequest
+	}{
+		{name: "missing name", body: CreateVectorCollectionRequest{}},
+		{name: "invalid characters", body: CreateVectorCollectionRequest{Name: "../other-tenant"}},
+		{name: "dimension too large", body: CreateVectorCollectionRequest{Name: "kb", Dimension: sandbox.MaxVectorDimension + 1}},
+		{name: "unknown metric", body: CreateVectorCollectionRequest{Name: "kb", Metric: "manhattan"}},
+	}
+
+	h := newTestVectorCollectionHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Create(w, createTestRequest(http.MethodPost, "/api/v1/vector-collections", tt.body))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (body: %s)", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestVectorCollectionHandler_Create_SystemCollectionForbidden(t *testing.T) {
+	h := newTestVectorCollectionHandler()
+	w := httptest.NewRecorder()
+
+	h.Create(w, createTestRequest(http.MethodPost, "/api/v1/vector-collections", CreateVectorCollectionRequest{Name: "platform-docs"}))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403 (body: %s)", w.Code, w.Body.String())
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Error.Code != "VECTOR_COLLECTION_READ_ONLY" {
+		t.Errorf("code = %q, want VECTOR_COLLECTION_READ_ONLY", resp.Error.Code)
+	}
+}
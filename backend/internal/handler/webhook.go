@@ -1,34 +1,50 @@
 package handler
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
 	"net/http"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/usecase"
+	"github.com/souta/ai-orchestration/pkg/webhook"
 )
 
 // WebhookHandler handles webhook HTTP requests (public, no auth required)
 type WebhookHandler struct {
-	runUsecase  *usecase.RunUsecase
-	stepUsecase *usecase.StepUsecase
+	runUsecase        *usecase.RunUsecase
+	stepUsecase       *usecase.StepUsecase
+	credentialUsecase *usecase.CredentialUsecase
+	verifiers         *webhook.Registry
 }
 
-// NewWebhookHandler creates a new WebhookHandler
+// NewWebhookHandler creates a new WebhookHandler that verifies signatures with the built-in
+// schemes (see webhook.NewRegistry)
 func NewWebhookHandler(runUsecase *usecase.RunUsecase, stepUsecase *usecase.StepUsecase) *WebhookHandler {
 	return &WebhookHandler{
 		runUsecase:  runUsecase,
 		stepUsecase: stepUsecase,
+		verifiers:   webhook.NewRegistry(),
 	}
 }
 
+// WithCredentialUsecase lets webhook triggers keep their secret in a credential
+// (secret_credential_id)
+func (h *WebhookHandler) WithCredentialUsecase(credentialUsecase *usecase.CredentialUsecase) *WebhookHandler {
+	h.credentialUsecase = credentialUsecase
+	return h
+}
+
+// WithVerifiers replaces the signature verifier registry
+func (h *WebhookHandler) WithVerifiers(verifiers *webhook.Registry) *WebhookHandler {
+	h.verifiers = verifiers
+	return h
+}
+
 // WebhookResponse represents the response from webhook trigger
 type WebhookResponse struct {
 	RunID  string `json:"run_id"`
@@ -104,16 +120,8 @@ func (h *WebhookHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify signature if secret is configured
-	if triggerConfig.Secret != "" {
-		signature := r.Header.Get("X-Webhook-Signature")
-		if signature == "" {
-			http.Error(w, `{"error": "missing signature"}`, http.StatusUnauthorized)
-			return
-		}
-		if !verifyWebhookSignature(body, triggerConfig.Secret, signature) {
-			http.Error(w, `{"error": "invalid signature"}`, http.StatusUnauthorized)
-			return
-		}
+	if !h.verifySignature(w, r, step, &triggerConfig, body) {
+		return
 	}
 
 	// Parse input from request body
@@ -126,17 +134,37 @@ func (h *WebhookHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 
 	// Create run
 	run, err := h.runUsecase.Create(ctx, usecase.CreateRunInput{
-		TenantID:    step.TenantID,
-		ProjectID:   projectID,
-		Input:       input,
-		TriggeredBy: domain.TriggerTypeWebhook,
-		StartStepID: &stepID,
+		TenantID:      step.TenantID,
+		ProjectID:     projectID,
+		Input:         input,
+		TriggeredBy:   domain.TriggerTypeWebhook,
+		StartStepID:   &stepID,
+		TriggerSource: "webhook",
+		TriggerMetadata: map[string]interface{}{
+			"webhook_step_id": stepID.String(),
+			"remote_ip":       getClientIP(r),
+			"user_agent":      r.UserAgent(),
+		},
 	})
 	if err != nil {
 		if err == domain.ErrStepNotFound {
 			http.Error(w, `{"error": "workflow not found"}`, http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, domain.ErrProjectPaused) {
+			http.Error(w, `{"error": "workflow is paused"}`, http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrTenantQueueSaturated) {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, `{"error": "too many queued runs"}`, http.StatusTooManyRequests)
+			return
+		}
+		if errors.Is(err, domain.ErrQueueSaturated) {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, `{"error": "run queue is saturated"}`, http.StatusServiceUnavailable)
+			return
+		}
 		http.Error(w, `{"error": "failed to trigger workflow"}`, http.StatusInternalServerError)
 		return
 	}
@@ -151,14 +179,66 @@ func (h *WebhookHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
-// verifyWebhookSignature verifies HMAC-SHA256 signature
-func verifyWebhookSignature(body []byte, secret, signature string) bool {
-	// Support both "sha256=xxx" and plain "xxx" formats
-	signature = strings.TrimPrefix(signature, "sha256=")
+// verifySignature verifies the request signature with the trigger's scheme (default: hmac) when
+// the trigger has a secret, either inline or in a credential. It writes the error response and
+// returns false when the request must be rejected.
+func (h *WebhookHandler) verifySignature(w http.ResponseWriter, r *http.Request, step *domain.Step, config *domain.WebhookTriggerConfig, body []byte) bool {
+	secret := config.Secret
+	if config.SecretCredentialID != nil {
+		if h.credentialUsecase == nil {
+			http.Error(w, `{"error": "invalid trigger configuration"}`, http.StatusInternalServerError)
+			return false
+		}
+		credential, err := h.credentialUsecase.GetDecrypted(r.Context(), step.TenantID, *config.SecretCredentialID, domain.CredentialAccess{
+			Purpose: domain.CredentialAccessWebhook,
+			StepID:  &step.ID,
+		})
+		if err != nil {
+			slog.Error("failed to load webhook secret credential", "step_id", step.ID, "error", err)
+			http.Error(w, `{"error": "invalid trigger configuration"}`, http.StatusInternalServerError)
+			return false
+		}
+		secret = webhookSecret(credential.Data)
+		if secret == "" {
+			http.Error(w, `{"error": "invalid trigger configuration"}`, http.StatusInternalServerError)
+			return false
+		}
+	}
+	if secret == "" {
+		// A scheme without a secret would accept unsigned requests, so treat it as misconfigured
+		if config.SignatureScheme != "" {
+			http.Error(w, `{"error": "invalid trigger configuration"}`, http.StatusInternalServerError)
+			return false
+		}
+		return true
+	}
 
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(body)
-	expectedMAC := hex.EncodeToString(mac.Sum(nil))
+	scheme := config.SignatureScheme
+	if scheme == "" {
+		scheme = webhook.SchemeHMAC
+	}
+	err := h.verifiers.Verify(scheme, r.Header, body, secret)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, webhook.ErrUnknownScheme):
+		http.Error(w, `{"error": "invalid trigger configuration"}`, http.StatusInternalServerError)
+	case errors.Is(err, webhook.ErrMissingSignature):
+		http.Error(w, `{"error": "missing signature"}`, http.StatusUnauthorized)
+	default:
+		http.Error(w, `{"error": "invalid signature"}`, http.StatusUnauthorized)
+	}
+	return false
+}
 
-	return hmac.Equal([]byte(signature), []byte(expectedMAC))
+// webhookSecret returns the signing secret held by a credential: the primary secret value, or
+// the "secret" field of a custom credential
+func webhookSecret(data *domain.CredentialData) string {
+	if data == nil {
+		return ""
+	}
+	if secret := data.GetSecretValue(); secret != "" {
+		return secret
+	}
+	return data.Custom["secret"]
 }
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/internal/usecase"
+	"github.com/souta/ai-orchestration/pkg/webhook"
+)
+
+// webhookStepRepo serves a single step by ID
+type webhookStepRepo struct {
+	repository.StepRepository
+	step *domain.Step
+}
+
+func (r *webhookStepRepo) GetByIDOnly(ctx context.Context, id uuid.UUID) (*domain.Step, error) {
+	if r.step == nil || r.step.ID != id {
+		return nil, domain.ErrStepNotFound
+	}
+	return r.step, nil
+}
+
+func newWebhookTestRequest(step *domain.Step, body []byte, header http.Header) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/projects/"+step.ProjectID.String()+"/webhook/"+step.ID.String(), bytes.NewReader(body))
+	req.Header = header
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", step.ProjectID.String())
+	rctx.URLParams.Add("step_id", step.ID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func githubSignature(secret string, body []byte) http.Header {
+	return http.Header{"X-Hub-Signature-256": {"sha256=" + webhook.Sign(secret, body)}}
+}
+
+func TestWebhookHandler_Trigger_RejectsInvalidSignature(t *testing.T) {
+	triggerType := domain.StepTriggerTypeWebhook
+	config, _ := json.Marshal(domain.WebhookTriggerConfig{Secret: "s3cret", SignatureScheme: webhook.SchemeGitHub, Enabled: true})
+	step := &domain.Step{ID: uuid.New(), TenantID: uuid.New(), ProjectID: uuid.New(), TriggerType: &triggerType, TriggerConfig: config}
+	h := NewWebhookHandler(nil, usecase.NewStepUsecase(nil, &webhookStepRepo{step: step}, nil, nil))
+	body := []byte(`{"ref":"refs/heads/main"}`)
+
+	tests := []struct {
+		name     string
+		body     []byte
+		header   http.Header
+		wantBody string
+	}{
+		{"missing signature", body, http.Header{}, `{"error": "missing signature"}`},
+		{"tampered payload", []byte(`{"ref":"refs/heads/evil"}`), githubSignature("s3cret", body), `{"error": "invalid signature"}`},
+		{"wrong secret", body, githubSignature("other", body), `{"error": "invalid signature"}`},
+		{"generic hmac header", body, http.Header{"X-Webhook-Signature": {webhook.Sign("s3cret", body)}}, `{"error": "missing signature"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Trigger(w, newWebhookTestRequest(step, tt.body, tt.header))
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+			if got := bytes.TrimSpace(w.Body.Bytes()); string(got) != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_Trigger_UnknownSchemeIsMisconfiguration(t *testing.T) {
+	triggerType := domain.StepTriggerTypeWebhook
+	config, _ := json.Marshal(domain.WebhookTriggerConfig{Secret: "s3cret", SignatureScheme: "unknown", Enabled: true})
+	step := &domain.Step{ID: uuid.New(), TenantID: uuid.New(), ProjectID: uuid.New(), TriggerType: &triggerType, TriggerConfig: config}
+	h := NewWebhookHandler(nil, usecase.NewStepUsecase(nil, &webhookStepRepo{step: step}, nil, nil))
+
+	w := httptest.NewRecorder()
+	h.Trigger(w, newWebhookTestRequest(step, []byte(`{}`), http.Header{}))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
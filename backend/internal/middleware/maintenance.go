@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// maintenanceKey is the Redis key holding the maintenance mode state shared by all API instances
+const maintenanceKey = "aio:maintenance"
+
+// defaultMaintenanceCacheTTL is how long an instance reuses the state it last read from Redis
+const defaultMaintenanceCacheTTL = 2 * time.Second
+
+// MaintenanceState is the admin-controlled read-only mode of the deployment
+type MaintenanceState struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`    // Shown to clients instead of the default message
+	EnabledAt *time.Time `json:"enabled_at,omitempty"` // When the mode was last switched on
+	EnabledBy string     `json:"enabled_by,omitempty"` // User who switched it on
+}
+
+// maintenanceBackend is the subset of the Redis client the maintenance mode uses
+type maintenanceBackend interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+}
+
+// MaintenanceMode blocks mutating API requests while an admin has switched the deployment to
+// read-only mode. The state lives in Redis so it can be toggled without a redeploy, and is cached
+// briefly per instance so reads do not add a Redis round trip to every request.
+type MaintenanceMode struct {
+	backend  maintenanceBackend
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	cached   MaintenanceState
+	cachedAt time.Time
+}
+
+// NewMaintenanceMode creates a Redis-backed maintenance mode
+func NewMaintenanceMode(client *redis.Client) *MaintenanceMode {
+	return &MaintenanceMode{backend: client, cacheTTL: defaultMaintenanceCacheTTL}
+}
+
+// State returns the current maintenance state. When Redis cannot be read the last known state
+// is kept (off if none), so a Redis outage does not turn the API read-only.
+func (m *MaintenanceMode) State(ctx context.Context) MaintenanceState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.cachedAt.IsZero() && time.Since(m.cachedAt) < m.cacheTTL {
+		return m.cached
+	}
+
+	raw, err := m.backend.Get(ctx, maintenanceKey).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		m.cached = MaintenanceState{}
+	case err != nil:
+		slog.Debug("failed to read maintenance mode", "error", err)
+		return m.cached
+	default:
+		var state MaintenanceState
+		if err := json.Unmarshal(raw, &state); err != nil {
+			slog.Warn("invalid maintenance mode state in Redis", "error", err)
+			return m.cached
+		}
+		m.cached = state
+	}
+	m.cachedAt = time.Now()
+	return m.cached
+}
+
+// Enabled reports whether read-only mode is on
+func (m *MaintenanceMode) Enabled(ctx context.Context) bool {
+	return m.State(ctx).Enabled
+}
+
+// Set stores a new maintenance state for all instances; it applies to this instance immediately
+func (m *MaintenanceMode) Set(ctx context.Context, state MaintenanceState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := m.backend.Set(ctx, maintenanceKey, raw, 0).Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.cached = state
+	m.cachedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// Middleware rejects POST, PUT, PATCH and DELETE requests with 503 while read-only mode is on.
+// Requests under the exempt path prefixes (the admin toggle itself) and all reads pass through.
+func (m *MaintenanceMode) Middleware(exemptPrefixes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, prefix := range exemptPrefixes {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			state := m.State(r.Context())
+			if !state.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeMaintenanceError(w, r, state)
+		})
+	}
+}
+
+// isMutatingMethod reports whether an HTTP method changes state
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// writeMaintenanceError writes the 503 response of a request blocked by read-only mode
+func writeMaintenanceError(w http.ResponseWriter, r *http.Request, state MaintenanceState) {
+	message := state.Message
+	if message == "" {
+		message = domain.GetErrorMessage(GetLanguage(r.Context()), "MAINTENANCE_MODE")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "60")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    "MAINTENANCE_MODE",
+			"message": message,
+		},
+	}); err != nil {
+		slog.Error("failed to encode maintenance mode error response", "error", err)
+	}
+}
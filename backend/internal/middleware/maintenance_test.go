@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMaintenanceBackend stores the maintenance state in memory
+type fakeMaintenanceBackend struct {
+	values map[string]string
+	err    error
+	gets   int
+}
+
+func newFakeMaintenanceBackend() *fakeMaintenanceBackend {
+	return &fakeMaintenanceBackend{values: make(map[string]string)}
+}
+
+func (f *fakeMaintenanceBackend) Get(ctx context.Context, key string) *redis.StringCmd {
+	f.gets++
+	if f.err != nil {
+		return redis.NewStringResult("", f.err)
+	}
+	value, ok := f.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func (f *fakeMaintenanceBackend) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	if f.err != nil {
+		return redis.NewStatusResult("", f.err)
+	}
+	f.values[key] = string(value.([]byte))
+	return redis.NewStatusResult("OK", nil)
+}
+
+func newTestMaintenanceMode(backend *fakeMaintenanceBackend) *MaintenanceMode {
+	return &MaintenanceMode{backend: backend, cacheTTL: defaultMaintenanceCacheTTL}
+}
+
+func serveMaintenance(m *MaintenanceMode, method, path string) *httptest.ResponseRecorder {
+	handler := m.Middleware("/api/v1/admin/maintenance")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestMaintenanceMode_BlocksWritesAllowsReads(t *testing.T) {
+	m := newTestMaintenanceMode(newFakeMaintenanceBackend())
+	require.NoError(t, m.Set(context.Background(), MaintenanceState{Enabled: true}))
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		rec := serveMaintenance(m, method, "/api/v1/workflows")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, method)
+		assert.Contains(t, rec.Body.String(), "MAINTENANCE_MODE", method)
+		assert.Equal(t, "60", rec.Header().Get("Retry-After"), method)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		rec := serveMaintenance(m, method, "/api/v1/workflows")
+		assert.Equal(t, http.StatusOK, rec.Code, method)
+	}
+
+	// The admin toggle stays writable so the mode can be switched off
+	rec := serveMaintenance(m, http.MethodPost, "/api/v1/admin/maintenance")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestMaintenanceMode_CustomMessage(t *testing.T) {
+	m := newTestMaintenanceMode(newFakeMaintenanceBackend())
+	require.NoError(t, m.Set(context.Background(), MaintenanceState{Enabled: true, Message: "Database migration until 10:00 UTC"}))
+
+	rec := serveMaintenance(m, http.MethodPost, "/api/v1/workflows")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Database migration until 10:00 UTC")
+}
+
+func TestMaintenanceMode_Disabled(t *testing.T) {
+	m := newTestMaintenanceMode(newFakeMaintenanceBackend())
+
+	rec := serveMaintenance(m, http.MethodPost, "/api/v1/workflows")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	require.NoError(t, m.Set(context.Background(), MaintenanceState{Enabled: true}))
+	require.NoError(t, m.Set(context.Background(), MaintenanceState{Enabled: false}))
+	rec = serveMaintenance(m, http.MethodPost, "/api/v1/workflows")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestMaintenanceMode_StateSharedThroughRedis(t *testing.T) {
+	backend := newFakeMaintenanceBackend()
+	writer := newTestMaintenanceMode(backend)
+	reader := newTestMaintenanceMode(backend)
+	reader.cacheTTL = 0
+
+	assert.False(t, reader.Enabled(context.Background()))
+	require.NoError(t, writer.Set(context.Background(), MaintenanceState{Enabled: true}))
+	assert.True(t, reader.Enabled(context.Background()), "another instance sees the toggle")
+}
+
+func TestMaintenanceMode_CachesState(t *testing.T) {
+	backend := newFakeMaintenanceBackend()
+	m := newTestMaintenanceMode(backend)
+
+	for i := 0; i < 5; i++ {
+		m.Enabled(context.Background())
+	}
+	assert.Equal(t, 1, backend.gets)
+}
+
+func TestMaintenanceMode_RedisErrorKeepsLastState(t *testing.T) {
+	backend := newFakeMaintenanceBackend()
+	m := newTestMaintenanceMode(backend)
+	m.cacheTTL = 0
+
+	assert.False(t, m.Enabled(context.Background()), "off when Redis has never been read")
+
+	require.NoError(t, m.Set(context.Background(), MaintenanceState{Enabled: true}))
+	backend.err = errors.New("connection refused")
+	assert.True(t, m.Enabled(context.Background()), "last known state is kept during an outage")
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -38,6 +39,14 @@ type RateLimitConfig struct {
 	// Webhook-level limits (requests per window per webhook key)
 	WebhookLimit  int
 	WebhookWindow time.Duration
+
+	// BypassWorkflowIDs lists workflows that skip the workflow-level limit
+	// (tenant-level limits still apply)
+	BypassWorkflowIDs []uuid.UUID
+
+	// FailClosed rejects requests with 503 when limits cannot be checked (e.g. Redis is down)
+	// instead of letting them through
+	FailClosed bool
 }
 
 // DefaultRateLimitConfig returns default rate limiting configuration
@@ -53,10 +62,19 @@ func DefaultRateLimitConfig() *RateLimitConfig {
 	}
 }
 
+// WorkflowBypassFunc reports whether a workflow skips the workflow-level limit,
+// e.g. because it is a system workflow such as the copilot
+type WorkflowBypassFunc func(ctx context.Context, tenantID, workflowID uuid.UUID) bool
+
 // RateLimiter handles rate limiting using Redis
 type RateLimiter struct {
 	redis  *redis.Client
 	config *RateLimitConfig
+	bypass WorkflowBypassFunc
+	// available reports whether Redis is reachable; checks fail fast while it reports false
+	available func() bool
+	// counter replaces the Redis sliding window when set (used in tests)
+	counter func(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -70,6 +88,33 @@ func NewRateLimiter(redisClient *redis.Client, config *RateLimitConfig) *RateLim
 	}
 }
 
+// WithWorkflowBypass sets a check that exempts workflows from the workflow-level limit in
+// addition to RateLimitConfig.BypassWorkflowIDs
+func (rl *RateLimiter) WithWorkflowBypass(bypass WorkflowBypassFunc) *RateLimiter {
+	rl.bypass = bypass
+	return rl
+}
+
+// WithAvailability sets a check that reports whether Redis is reachable, so that requests do not
+// wait on connection attempts during an outage
+func (rl *RateLimiter) WithAvailability(available func() bool) *RateLimiter {
+	rl.available = available
+	return rl
+}
+
+// errLimiterUnavailable is returned by limit checks while Redis is known to be unreachable
+var errLimiterUnavailable = errors.New("rate limiter unavailable")
+
+// bypassesWorkflowLimit reports whether the workflow skips the workflow-level limit
+func (rl *RateLimiter) bypassesWorkflowLimit(ctx context.Context, tenantID, workflowID uuid.UUID) bool {
+	for _, id := range rl.config.BypassWorkflowIDs {
+		if id == workflowID {
+			return true
+		}
+	}
+	return rl.bypass != nil && rl.bypass(ctx, tenantID, workflowID)
+}
+
 // RateLimitResult contains the result of a rate limit check
 type RateLimitResult struct {
 	Allowed   bool
@@ -80,6 +125,13 @@ type RateLimitResult struct {
 
 // checkLimit performs the rate limit check using Redis sliding window
 func (rl *RateLimiter) checkLimit(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	if rl.counter != nil {
+		return rl.counter(ctx, key, limit, window)
+	}
+	if rl.available != nil && !rl.available() {
+		return nil, errLimiterUnavailable
+	}
+
 	now := time.Now()
 	windowStart := now.Add(-window)
 	resetAt := now.Add(window)
@@ -176,6 +228,27 @@ func writeRateLimitError(w http.ResponseWriter, result *RateLimitResult, scope R
 	}
 }
 
+// allowOnError reports whether a request whose limit check failed may proceed. With FailClosed it
+// writes a 503 response and returns false.
+func (rl *RateLimiter) allowOnError(w http.ResponseWriter, scope RateLimitScope) bool {
+	if !rl.config.FailClosed {
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "30")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    "RATE_LIMIT_UNAVAILABLE",
+			"message": fmt.Sprintf("Rate limit for %s scope cannot be checked", scope),
+			"scope":   scope,
+		},
+	}); err != nil {
+		slog.Error("failed to encode rate limit unavailable response", "error", err, "scope", scope)
+	}
+	return false
+}
+
 // TenantRateLimitMiddleware creates a middleware that rate limits by tenant
 func (rl *RateLimiter) TenantRateLimitMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -194,12 +267,14 @@ func (rl *RateLimiter) TenantRateLimitMiddleware() func(http.Handler) http.Handl
 
 			result, err := rl.CheckTenant(r.Context(), tenantID)
 			if err != nil {
-				// On error, allow the request but log for debugging
+				// On error, allow the request (unless failing closed) but log for debugging
 				slog.Error("rate limit check failed for tenant",
 					"tenant_id", tenantID.String(),
 					"error", err,
 				)
-				next.ServeHTTP(w, r)
+				if rl.allowOnError(w, RateLimitScopeTenant) {
+					next.ServeHTTP(w, r)
+				}
 				return
 			}
 
@@ -216,7 +291,8 @@ func (rl *RateLimiter) TenantRateLimitMiddleware() func(http.Handler) http.Handl
 }
 
 // WorkflowRateLimitMiddleware creates a middleware that rate limits by workflow
-// This should be used on workflow-specific endpoints
+// This should be used on workflow-specific endpoints. Bypassed workflows are passed through
+// without being counted; the tenant-level middleware still counts their requests.
 func (rl *RateLimiter) WorkflowRateLimitMiddleware(getWorkflowID func(*http.Request) (uuid.UUID, error)) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -232,14 +308,16 @@ func (rl *RateLimiter) WorkflowRateLimitMiddleware(getWorkflowID func(*http.Requ
 			}
 
 			workflowID, err := getWorkflowID(r)
-			if err != nil {
+			if err != nil || rl.bypassesWorkflowLimit(r.Context(), tenantID, workflowID) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			result, err := rl.CheckWorkflow(r.Context(), tenantID, workflowID)
 			if err != nil {
-				next.ServeHTTP(w, r)
+				if rl.allowOnError(w, RateLimitScopeWorkflow) {
+					next.ServeHTTP(w, r)
+				}
 				return
 			}
 
@@ -272,7 +350,9 @@ func (rl *RateLimiter) WebhookRateLimitMiddleware(getWebhookKey func(*http.Reque
 
 			result, err := rl.CheckWebhook(r.Context(), webhookKey)
 			if err != nil {
-				next.ServeHTTP(w, r)
+				if rl.allowOnError(w, RateLimitScopeWebhook) {
+					next.ServeHTTP(w, r)
+				}
 				return
 			}
 
@@ -314,6 +394,8 @@ func (rl *RateLimiter) MultiScopeRateLimitMiddleware(
 							writeRateLimitError(w, result, RateLimitScopeWebhook)
 							return
 						}
+					} else if !rl.allowOnError(w, RateLimitScopeWebhook) {
+						return
 					}
 				}
 			}
@@ -328,12 +410,14 @@ func (rl *RateLimiter) MultiScopeRateLimitMiddleware(
 						writeRateLimitError(w, result, RateLimitScopeTenant)
 						return
 					}
+				} else if !rl.allowOnError(w, RateLimitScopeTenant) {
+					return
 				}
 
 				// Check workflow limit (if applicable)
 				if getWorkflowID != nil {
 					workflowID, err := getWorkflowID(r)
-					if err == nil {
+					if err == nil && !rl.bypassesWorkflowLimit(ctx, tenantID, workflowID) {
 						result, err := rl.CheckWorkflow(ctx, tenantID, workflowID)
 						if err == nil {
 							setRateLimitHeaders(w, result, RateLimitScopeWorkflow)
@@ -341,6 +425,8 @@ func (rl *RateLimiter) MultiScopeRateLimitMiddleware(
 								writeRateLimitError(w, result, RateLimitScopeWorkflow)
 								return
 							}
+						} else if !rl.allowOnError(w, RateLimitScopeWorkflow) {
+							return
 						}
 					}
 				}
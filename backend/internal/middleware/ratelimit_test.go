@@ -231,3 +231,96 @@ func TestTenantRateLimitMiddleware_ErrorHandling_Integration(t *testing.T) {
 	assert.True(t, handlerCalled)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+// newCountingRateLimiter creates a rate limiter with an in-memory fixed counter per key
+func newCountingRateLimiter(config *RateLimitConfig) (*RateLimiter, map[string]int) {
+	counts := make(map[string]int)
+	rl := NewRateLimiter(nil, config)
+	rl.counter = func(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+		if counts[key] >= limit {
+			return &RateLimitResult{Allowed: false, ResetAt: time.Now().Add(window), Limit: limit}, nil
+		}
+		counts[key]++
+		return &RateLimitResult{Allowed: true, Remaining: limit - counts[key], ResetAt: time.Now().Add(window), Limit: limit}, nil
+	}
+	return rl, counts
+}
+
+// TestWorkflowRateLimitMiddleware_Bypass tests that bypassed workflows skip the workflow limit
+func TestWorkflowRateLimitMiddleware_Bypass(t *testing.T) {
+	tenantID := uuid.New()
+	normalID := uuid.New()
+	systemID := uuid.New()
+	allowlistedID := uuid.New()
+
+	rl, counts := newCountingRateLimiter(&RateLimitConfig{
+		Enabled:           true,
+		TenantLimit:       100,
+		TenantWindow:      time.Minute,
+		WorkflowLimit:     2,
+		WorkflowWindow:    time.Minute,
+		BypassWorkflowIDs: []uuid.UUID{allowlistedID},
+	})
+	rl.WithWorkflowBypass(func(ctx context.Context, tid, workflowID uuid.UUID) bool {
+		return tid == tenantID && workflowID == systemID
+	})
+
+	handler := rl.TenantRateLimitMiddleware()(rl.WorkflowRateLimitMiddleware(func(r *http.Request) (uuid.UUID, error) {
+		return uuid.Parse(r.Header.Get("X-Workflow-ID"))
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	send := func(workflowID uuid.UUID) int {
+		req := httptest.NewRequest(http.MethodPost, "/runs", nil)
+		req.Header.Set("X-Workflow-ID", workflowID.String())
+		req = req.WithContext(context.WithValue(req.Context(), TenantIDKey, tenantID))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send(systemID), "system workflow request %d", i)
+		assert.Equal(t, http.StatusOK, send(allowlistedID), "allowlisted workflow request %d", i)
+	}
+
+	assert.Equal(t, http.StatusOK, send(normalID))
+	assert.Equal(t, http.StatusOK, send(normalID))
+	assert.Equal(t, http.StatusTooManyRequests, send(normalID), "normal workflow is limited")
+
+	assert.Equal(t, 13, counts["ratelimit:tenant:"+tenantID.String()], "bypassed requests still count toward the tenant limit")
+	assert.Zero(t, counts["ratelimit:workflow:"+tenantID.String()+":"+systemID.String()])
+}
+
+// TestRateLimitMiddleware_RedisUnavailable tests the fail-open and fail-closed modes during an outage
+func TestRateLimitMiddleware_RedisUnavailable(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		failClosed bool
+		wantStatus int
+	}{
+		{name: "fail open", failClosed: false, wantStatus: http.StatusOK},
+		{name: "fail closed", failClosed: true, wantStatus: http.StatusServiceUnavailable},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := NewRateLimiter(nil, &RateLimitConfig{
+				Enabled:      true,
+				TenantLimit:  100,
+				TenantWindow: time.Minute,
+				FailClosed:   tt.failClosed,
+			}).WithAvailability(func() bool { return false })
+
+			handler := rl.TenantRateLimitMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req = req.WithContext(context.WithValue(req.Context(), TenantIDKey, uuid.New()))
+			rec := httptest.NewRecorder()
+
+			assert.NotPanics(t, func() { handler.ServeHTTP(rec, req) }, "the nil Redis client is never used while unavailable")
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
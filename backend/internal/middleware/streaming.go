@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NoWriteDeadline removes the server's write deadline for long-lived responses such as SSE
+// streams. http.Server.WriteTimeout bounds the whole response, so without this a stream is cut
+// off once the timeout elapses; clearing it per request keeps the timeout in force for all
+// other endpoints.
+func NoWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.Warn("failed to clear write deadline for streaming response", "path", r.URL.Path, "error", err)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
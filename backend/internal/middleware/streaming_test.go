@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvents is the number of events streamHandler writes, 50ms apart
+const sseEvents = 8
+
+// streamHandler writes an SSE stream that outlasts a 100ms server write timeout
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher := w.(http.Flusher)
+	for i := 0; i < sseEvents; i++ {
+		fmt.Fprintf(w, "event: tick\ndata: %d\n\n", i)
+		flusher.Flush()
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// readEvents returns the number of SSE events read before the stream ended
+func readEvents(t *testing.T, url string) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "data: ") {
+			count++
+		}
+	}
+	return count
+}
+
+func newWriteTimeoutServer(handler http.Handler) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	return server
+}
+
+func TestNoWriteDeadline_LongStreamIsNotCut(t *testing.T) {
+	server := newWriteTimeoutServer(NoWriteDeadline(http.HandlerFunc(streamHandler)))
+	defer server.Close()
+
+	assert.Equal(t, sseEvents, readEvents(t, server.URL))
+}
+
+func TestNoWriteDeadline_WithoutMiddlewareStreamIsCut(t *testing.T) {
+	server := newWriteTimeoutServer(http.HandlerFunc(streamHandler))
+	defer server.Close()
+
+	assert.Less(t, readEvents(t, server.URL), sseEvents, "the server write timeout ends the stream")
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,15 +15,25 @@ type ProjectRepository interface {
 	GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error)
 	List(ctx context.Context, tenantID uuid.UUID, filter ProjectFilter) ([]*domain.Project, int, error)
 	Update(ctx context.Context, project *domain.Project) error
+	// UpdateIfUnmodified updates a project only if its updated_at still equals unmodifiedSince,
+	// and returns ErrConcurrentModification otherwise
+	UpdateIfUnmodified(ctx context.Context, project *domain.Project, unmodifiedSince time.Time) error
+	// SetPaused pauses or resumes a project without changing its other fields
+	SetPaused(ctx context.Context, tenantID, id uuid.UUID, paused bool) error
 	Delete(ctx context.Context, tenantID, id uuid.UUID) error
 	GetWithStepsAndEdges(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error)
 	// GetSystemBySlug retrieves a system project by its slug (accessible across all tenants)
 	GetSystemBySlug(ctx context.Context, slug string) (*domain.Project, error)
+	// ListTags returns the tenant's distinct project tags with usage counts
+	ListTags(ctx context.Context, tenantID uuid.UUID) ([]domain.ProjectTagCount, error)
 }
 
 // ProjectFilter defines filtering options for project list
 type ProjectFilter struct {
 	Status *domain.ProjectStatus
+	Tags   []string    // Projects must have all of these tags (AND semantics)
+	IDs    []uuid.UUID // Restricts results to these project IDs when non-nil
+	After  *ListCursor // Keyset cursor on updated_at; replaces Page when set
 	Page   int
 	Limit  int
 }
@@ -41,7 +52,13 @@ type StepRepository interface {
 	// GetStartStepByTriggerType returns a Start block by its trigger type
 	GetStartStepByTriggerType(ctx context.Context, tenantID, projectID uuid.UUID, triggerType domain.StepTriggerType) (*domain.Step, error)
 	Update(ctx context.Context, step *domain.Step) error
+	// UpdateIfUnmodified updates a step only if its updated_at still equals unmodifiedSince,
+	// and returns ErrConcurrentModification otherwise
+	UpdateIfUnmodified(ctx context.Context, step *domain.Step, unmodifiedSince time.Time) error
 	Delete(ctx context.Context, tenantID, projectID, id uuid.UUID) error
+	// DeleteBatch deletes steps and all edges connected to them in a single transaction,
+	// returning the removed edge IDs. Nothing is deleted if any step is not in the project.
+	DeleteBatch(ctx context.Context, tenantID, projectID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error)
 }
 
 // EdgeRepository defines the interface for edge persistence
@@ -57,22 +74,77 @@ type EdgeRepository interface {
 type RunRepository interface {
 	Create(ctx context.Context, run *domain.Run) error
 	GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error)
+	// GetByIDs returns the tenant's runs with the given IDs (without input and output), leaving
+	// out IDs that do not exist or belong to another tenant
+	GetByIDs(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]*domain.Run, error)
 	ListByProject(ctx context.Context, tenantID, projectID uuid.UUID, filter RunFilter) ([]*domain.Run, int, error)
 	// ListByStartStep returns runs for a specific Start block
 	ListByStartStep(ctx context.Context, tenantID, projectID, startStepID uuid.UUID, filter RunFilter) ([]*domain.Run, int, error)
 	Update(ctx context.Context, run *domain.Run) error
 	GetWithStepRuns(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error)
+	// Search returns tenant runs across all projects, newest first, using keyset pagination
+	Search(ctx context.Context, tenantID uuid.UUID, filter RunSearchFilter) ([]*domain.Run, error)
+	// ListReferencedStepIDs returns the step IDs (start steps and executed steps) referenced by
+	// the project's runs created since the given time, across all tenants
+	ListReferencedStepIDs(ctx context.Context, projectID uuid.UUID, since time.Time) ([]uuid.UUID, error)
+	// CountConsecutiveFailures returns the number of the tenant's failed runs of the project that
+	// finished after its latest completed run
+	CountConsecutiveFailures(ctx context.Context, tenantID, projectID uuid.UUID) (int, error)
 }
 
 // RunFilter defines filtering options for run list
 type RunFilter struct {
 	Status      *domain.RunStatus
 	TriggeredBy *domain.TriggerType
-	StartStepID *uuid.UUID // Filter by specific Start block
+	StartStepID *uuid.UUID  // Filter by specific Start block
+	After       *ListCursor // Keyset cursor on created_at; replaces Page when set
 	Page        int
 	Limit       int
 }
 
+// RunSearchFilter defines filtering options for tenant-wide run search
+type RunSearchFilter struct {
+	ProjectID        *uuid.UUID
+	Status           *domain.RunStatus
+	CreatedFrom      *time.Time       // Inclusive lower bound on created_at
+	CreatedTo        *time.Time       // Exclusive upper bound on created_at
+	InputContains    json.RawMessage  // JSONB containment (@>) match on input
+	OutputContains   json.RawMessage  // JSONB containment (@>) match on output
+	MetadataContains json.RawMessage  // JSONB containment (@>) match on trigger_metadata
+	After            *RunSearchCursor // Keyset cursor: return runs strictly older than this position
+	Limit            int
+}
+
+// ListCursor identifies a position in a (timestamp DESC, id DESC) keyset ordering of a list:
+// created_at for most lists, updated_at for projects
+type ListCursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// RunSearchCursor identifies a position in the (created_at DESC, id DESC) run ordering
+type RunSearchCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// RunAnnotationRepository defines the interface for run annotation persistence
+type RunAnnotationRepository interface {
+	Create(ctx context.Context, annotation *domain.RunAnnotation) error
+	GetByID(ctx context.Context, tenantID, runID, id uuid.UUID) (*domain.RunAnnotation, error)
+	// ListByRun returns a run's annotations, oldest first
+	ListByRun(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.RunAnnotation, error)
+	Delete(ctx context.Context, tenantID, runID, id uuid.UUID) error
+}
+
+// RunCheckpointRepository defines the interface for run checkpoint persistence
+type RunCheckpointRepository interface {
+	// Save stores a checkpoint, replacing an earlier checkpoint of the same step in the run
+	Save(ctx context.Context, checkpoint *domain.RunCheckpoint) error
+	// GetLatest returns the run's most recently saved checkpoint
+	GetLatest(ctx context.Context, tenantID, runID uuid.UUID) (*domain.RunCheckpoint, error)
+}
+
 // StepRunRepository defines the interface for step run persistence
 type StepRunRepository interface {
 	Create(ctx context.Context, stepRun *domain.StepRun) error
@@ -92,11 +164,16 @@ type StepRunRepository interface {
 	ListCompletedByRun(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.StepRun, error)
 	// ListByStep returns all StepRuns for a specific step in a run (for history)
 	ListByStep(ctx context.Context, tenantID, runID, stepID uuid.UUID) ([]*domain.StepRun, error)
+	// ClearOutputsBefore clears the input/output payloads of the tenant's step runs created before
+	// the time, keeping status, timing and errors, and returns how many were cleared
+	ClearOutputsBefore(ctx context.Context, tenantID uuid.UUID, before time.Time) (int64, error)
 }
 
 // ProjectVersionRepository defines the interface for project version persistence
 type ProjectVersionRepository interface {
 	Create(ctx context.Context, version *domain.ProjectVersion) error
+	// Upsert creates a version or replaces the snapshot of an existing version number (used by the seeder)
+	Upsert(ctx context.Context, version *domain.ProjectVersion) error
 	GetByProjectAndVersion(ctx context.Context, projectID uuid.UUID, version int) (*domain.ProjectVersion, error)
 	GetLatestByProject(ctx context.Context, projectID uuid.UUID) (*domain.ProjectVersion, error)
 	ListByProject(ctx context.Context, projectID uuid.UUID) ([]*domain.ProjectVersion, error)
@@ -114,6 +191,15 @@ type ScheduleRepository interface {
 	Delete(ctx context.Context, tenantID, id uuid.UUID) error
 	// GetDueSchedules returns schedules that are due to run
 	GetDueSchedules(ctx context.Context, limit int) ([]*domain.Schedule, error)
+	// PauseAll pauses every active schedule of a tenant, marking them bulk-paused, and
+	// returns the IDs of the paused schedules
+	PauseAll(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error)
+	// ListBulkPaused returns the schedules of a tenant that are paused by PauseAll
+	ListBulkPaused(ctx context.Context, tenantID uuid.UUID) ([]*domain.Schedule, error)
+	// ResumeBulkPaused reactivates the given bulk-paused schedules with their next run times
+	// in a single transaction and returns the IDs of the resumed schedules. Schedules that are
+	// no longer bulk-paused are skipped.
+	ResumeBulkPaused(ctx context.Context, tenantID uuid.UUID, nextRuns map[uuid.UUID]*time.Time) ([]uuid.UUID, error)
 }
 
 // ScheduleFilter defines filtering options for schedule list
@@ -121,6 +207,7 @@ type ScheduleFilter struct {
 	ProjectID   *uuid.UUID
 	StartStepID *uuid.UUID // Filter by specific Start block
 	Status      *domain.ScheduleStatus
+	After       *ListCursor // Keyset cursor on created_at; replaces Page when set
 	Page        int
 	Limit       int
 }
@@ -140,6 +227,7 @@ type AuditLogFilter struct {
 	ResourceID   *uuid.UUID
 	StartTime    *time.Time
 	EndTime      *time.Time
+	After        *ListCursor // Keyset cursor on created_at; replaces Page when set
 	Page         int
 	Limit        int
 }
@@ -158,6 +246,12 @@ type BlockDefinitionRepository interface {
 	Update(ctx context.Context, block *domain.BlockDefinition) error
 	// Delete deletes a block definition (only custom blocks)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// DeleteSystem deletes a system block definition (used by the seeder to prune removed blocks)
+	DeleteSystem(ctx context.Context, id uuid.UUID) error
+	// GetReferences returns the projects and child blocks that depend on a block, by ID or slug
+	GetReferences(ctx context.Context, id uuid.UUID, slug string) (*domain.BlockReferences, error)
+	// GetUsage returns how many steps of each block slug the tenant's non-deleted projects contain
+	GetUsage(ctx context.Context, tenantID uuid.UUID) ([]domain.BlockUsage, error)
 	// ValidateInheritance validates that a block can inherit from the specified parent
 	// Checks for circular inheritance, inheritance depth, and parent inheritability
 	ValidateInheritance(ctx context.Context, blockID uuid.UUID, parentBlockID uuid.UUID) error
@@ -221,6 +315,7 @@ type CredentialFilter struct {
 	Scope          *domain.OwnerScope // Filter by ownership scope
 	ProjectID      *uuid.UUID         // Filter by project (for scope=project)
 	OwnerUserID    *uuid.UUID         // Filter by owner user (for scope=personal)
+	After          *ListCursor        // Keyset cursor on created_at; replaces Page when set
 	Page           int
 	Limit          int
 }
@@ -515,6 +610,8 @@ type TemplateFilter struct {
 	IsFeatured *bool
 	MinRating  *float64
 	Visibility *domain.TemplateVisibility
+	Sort       domain.TemplateSort
+	IDs        []uuid.UUID // Restricts results to these template IDs when non-nil
 	Page       int
 	Limit      int
 }
@@ -524,10 +621,29 @@ type TemplateReviewRepository interface {
 	Create(ctx context.Context, review *domain.TemplateReview) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.TemplateReview, error)
 	ListByTemplate(ctx context.Context, templateID uuid.UUID) ([]*domain.TemplateReview, error)
+	// GetByTemplateAndUser returns ErrTemplateReviewNotFound when the user has not reviewed the template
 	GetByTemplateAndUser(ctx context.Context, templateID, userID uuid.UUID) (*domain.TemplateReview, error)
+	// Upsert creates the review, or updates rating and comment of the user's existing review
+	Upsert(ctx context.Context, review *domain.TemplateReview) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// TemplateUsageRepository defines the interface for template usage persistence
+type TemplateUsageRepository interface {
+	Create(ctx context.Context, usage *domain.TemplateUsage) error
+	HasUsed(ctx context.Context, templateID, userID uuid.UUID) (bool, error)
+}
+
+// FavoriteRepository defines the interface for per-user favorites persistence
+type FavoriteRepository interface {
+	// Add favorites a target; adding an existing favorite is a no-op
+	Add(ctx context.Context, favorite *domain.Favorite) error
+	// Remove unfavorites a target; removing a missing favorite is a no-op
+	Remove(ctx context.Context, tenantID, userID uuid.UUID, targetType domain.FavoriteTargetType, targetID uuid.UUID) error
+	// ListTargetIDs returns the IDs the user has favorited for a target type, newest first
+	ListTargetIDs(ctx context.Context, tenantID, userID uuid.UUID, targetType domain.FavoriteTargetType) ([]uuid.UUID, error)
+}
+
 // ProjectGitSyncRepository defines the interface for git sync persistence
 type ProjectGitSyncRepository interface {
 	Create(ctx context.Context, gitSync *domain.ProjectGitSync) error
@@ -558,3 +674,45 @@ type BlockPackageFilter struct {
 	Page   int
 	Limit  int
 }
+
+// ProjectPermissionRepository defines the interface for per-user workflow permission persistence
+type ProjectPermissionRepository interface {
+	// Upsert grants a permission, replacing the user's existing role on the workflow
+	Upsert(ctx context.Context, permission *domain.ProjectPermission) error
+	// Delete revokes a user's permission on a workflow
+	Delete(ctx context.Context, tenantID, projectID, userID uuid.UUID) error
+	// ListByProject returns the permissions of a workflow, owners first
+	ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.ProjectPermission, error)
+}
+
+// NotificationChannelRepository defines the interface for notification channel persistence
+type NotificationChannelRepository interface {
+	// Create creates a new notification channel
+	Create(ctx context.Context, channel *domain.NotificationChannel) error
+	// GetByID retrieves a notification channel by ID
+	GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.NotificationChannel, error)
+	// ListByTenant retrieves all notification channels of a tenant, oldest first
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.NotificationChannel, error)
+	// Update updates a notification channel
+	Update(ctx context.Context, channel *domain.NotificationChannel) error
+	// Delete deletes a notification channel
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// LibraryFunctionRepository defines the interface for library function persistence
+type LibraryFunctionRepository interface {
+	// Create creates a new library function. Returns domain.ErrLibraryFunctionExists if the
+	// tenant already has a library function with that name.
+	Create(ctx context.Context, function *domain.LibraryFunction) error
+	// GetByID retrieves a library function by ID
+	GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.LibraryFunction, error)
+	// GetByNames retrieves the library functions of a tenant with the given names. Names
+	// without a library function are left out.
+	GetByNames(ctx context.Context, tenantID uuid.UUID, names []string) ([]*domain.LibraryFunction, error)
+	// ListByTenant retrieves all library functions of a tenant, ordered by name
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.LibraryFunction, error)
+	// Update updates a library function
+	Update(ctx context.Context, function *domain.LibraryFunction) error
+	// Delete deletes a library function
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
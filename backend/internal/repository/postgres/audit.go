@@ -98,17 +98,11 @@ func (r *AuditLogRepository) ListByTenant(ctx context.Context, tenantID uuid.UUI
 		SELECT id, tenant_id, actor_id, actor_email, action, resource_type,
 			   resource_id, metadata, ip_address::text, user_agent, created_at
 		FROM audit_logs
-		WHERE %s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIdx, argIdx+1)
+		WHERE %s`, whereClause)
 
-	page := filter.Page
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * filter.Limit
-	args = append(args, filter.Limit, offset)
+	pageClause, pageArgs := listPageClause("created_at", filter.After, filter.Page, filter.Limit, argIdx)
+	query += pageClause
+	args = append(args, pageArgs...)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -27,6 +27,19 @@ func NewBlockDefinitionRepository(pool *pgxpool.Pool) *BlockDefinitionRepository
 // MaxInheritanceDepth is the maximum allowed inheritance depth
 const MaxInheritanceDepth = 10
 
+// marshalBlockExamples encodes block examples for the examples column, storing NULL when there
+// are none
+func marshalBlockExamples(examples []domain.BlockExample) ([]byte, error) {
+	if len(examples) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(examples)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal examples: %w", err)
+	}
+	return data, nil
+}
+
 func (r *BlockDefinitionRepository) Create(ctx context.Context, block *domain.BlockDefinition) error {
 	errorCodesJSON, err := json.Marshal(block.ErrorCodes)
 	if err != nil {
@@ -43,6 +56,11 @@ func (r *BlockDefinitionRepository) Create(ctx context.Context, block *domain.Bl
 		return fmt.Errorf("failed to marshal internal steps: %w", err)
 	}
 
+	examplesJSON, err := marshalBlockExamples(block.Examples)
+	if err != nil {
+		return err
+	}
+
 	// Convert empty GroupKind to nil for database
 	var groupKind *string
 	if block.GroupKind != "" {
@@ -79,9 +97,9 @@ func (r *BlockDefinitionRepository) Create(ctx context.Context, block *domain.Bl
 			error_codes, required_credentials, is_public,
 			code, ui_config, is_system, version,
 			parent_block_id, config_defaults, pre_process, post_process, internal_steps,
-			group_kind, is_container, request, response,
+			group_kind, is_container, request, response, examples,
 			enabled, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31)
 	`
 
 	_, err = r.pool.Exec(ctx, query,
@@ -112,6 +130,7 @@ func (r *BlockDefinitionRepository) Create(ctx context.Context, block *domain.Bl
 		block.IsContainer,
 		requestJSON,
 		responseJSON,
+		examplesJSON,
 		block.Enabled,
 		block.CreatedAt,
 		block.UpdatedAt,
@@ -153,7 +172,7 @@ func (r *BlockDefinitionRepository) getByIDRaw(ctx context.Context, id uuid.UUID
 			   COALESCE(error_codes, '[]'::jsonb), required_credentials, COALESCE(is_public, false),
 			   COALESCE(code, ''), COALESCE(ui_config, '{}'), COALESCE(is_system, false), COALESCE(version, 1),
 			   parent_block_id, COALESCE(config_defaults, '{}'), COALESCE(pre_process, ''), COALESCE(post_process, ''), COALESCE(internal_steps, '[]'),
-			   group_kind, COALESCE(is_container, false), request, response,
+			   group_kind, COALESCE(is_container, false), request, response, COALESCE(examples, '[]'),
 			   enabled, created_at, updated_at
 		FROM block_definitions
 		WHERE id = $1
@@ -165,6 +184,7 @@ func (r *BlockDefinitionRepository) getByIDRaw(ctx context.Context, id uuid.UUID
 	var internalStepsJSON []byte
 	var requestJSON []byte
 	var responseJSON []byte
+	var examplesJSON []byte
 	var groupKind *string
 	var subcategory *string
 
@@ -196,6 +216,7 @@ func (r *BlockDefinitionRepository) getByIDRaw(ctx context.Context, id uuid.UUID
 		&block.IsContainer,
 		&requestJSON,
 		&responseJSON,
+		&examplesJSON,
 		&block.Enabled,
 		&block.CreatedAt,
 		&block.UpdatedAt,
@@ -245,6 +266,12 @@ func (r *BlockDefinitionRepository) getByIDRaw(ctx context.Context, id uuid.UUID
 		}
 	}
 
+	if len(examplesJSON) > 0 {
+		if err := json.Unmarshal(examplesJSON, &block.Examples); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal examples: %w", err)
+		}
+	}
+
 	return block, nil
 }
 
@@ -275,7 +302,7 @@ func (r *BlockDefinitionRepository) getBySlugRaw(ctx context.Context, tenantID *
 			   COALESCE(error_codes, '[]'::jsonb), required_credentials, COALESCE(is_public, false),
 			   COALESCE(code, ''), COALESCE(ui_config, '{}'), COALESCE(is_system, false), COALESCE(version, 1),
 			   parent_block_id, COALESCE(config_defaults, '{}'), COALESCE(pre_process, ''), COALESCE(post_process, ''), COALESCE(internal_steps, '[]'),
-			   group_kind, COALESCE(is_container, false), request, response,
+			   group_kind, COALESCE(is_container, false), request, response, COALESCE(examples, '[]'),
 			   enabled, created_at, updated_at
 		FROM block_definitions
 		WHERE slug = $1 AND ((tenant_id = $2) OR ($2 IS NULL AND tenant_id IS NULL) OR tenant_id IS NULL)
@@ -289,6 +316,7 @@ func (r *BlockDefinitionRepository) getBySlugRaw(ctx context.Context, tenantID *
 	var internalStepsJSON []byte
 	var requestJSON []byte
 	var responseJSON []byte
+	var examplesJSON []byte
 	var groupKind *string
 	var subcategory *string
 
@@ -320,6 +348,7 @@ func (r *BlockDefinitionRepository) getBySlugRaw(ctx context.Context, tenantID *
 		&block.IsContainer,
 		&requestJSON,
 		&responseJSON,
+		&examplesJSON,
 		&block.Enabled,
 		&block.CreatedAt,
 		&block.UpdatedAt,
@@ -370,6 +399,12 @@ func (r *BlockDefinitionRepository) getBySlugRaw(ctx context.Context, tenantID *
 		}
 	}
 
+	if len(examplesJSON) > 0 {
+		if err := json.Unmarshal(examplesJSON, &block.Examples); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal examples: %w", err)
+		}
+	}
+
 	return block, nil
 }
 
@@ -424,7 +459,7 @@ func (r *BlockDefinitionRepository) List(ctx context.Context, tenantID *uuid.UUI
 			   COALESCE(error_codes, '[]'::jsonb), required_credentials, COALESCE(is_public, false),
 			   COALESCE(code, ''), COALESCE(ui_config, '{}'), COALESCE(is_system, false), COALESCE(version, 1),
 			   parent_block_id, COALESCE(config_defaults, '{}'), COALESCE(pre_process, ''), COALESCE(post_process, ''), COALESCE(internal_steps, '[]'),
-			   group_kind, COALESCE(is_container, false), request, response,
+			   group_kind, COALESCE(is_container, false), request, response, COALESCE(examples, '[]'),
 			   enabled, created_at, updated_at
 		FROM block_definitions
 		%s
@@ -445,6 +480,7 @@ func (r *BlockDefinitionRepository) List(ctx context.Context, tenantID *uuid.UUI
 		var internalStepsJSON []byte
 		var requestJSON []byte
 		var responseJSON []byte
+		var examplesJSON []byte
 		var groupKind *string
 		var subcategory *string
 
@@ -476,6 +512,8 @@ func (r *BlockDefinitionRepository) List(ctx context.Context, tenantID *uuid.UUI
 			&block.IsContainer,
 			&requestJSON,
 			&responseJSON,
+			&examplesJSON,
+			&examplesJSON,
 			&block.Enabled,
 			&block.CreatedAt,
 			&block.UpdatedAt,
@@ -523,6 +561,12 @@ func (r *BlockDefinitionRepository) List(ctx context.Context, tenantID *uuid.UUI
 			}
 		}
 
+		if len(examplesJSON) > 0 {
+			if err := json.Unmarshal(examplesJSON, &block.Examples); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal examples: %w", err)
+			}
+		}
+
 		blocks = append(blocks, block)
 	}
 
@@ -549,6 +593,11 @@ func (r *BlockDefinitionRepository) Update(ctx context.Context, block *domain.Bl
 		return fmt.Errorf("failed to marshal internal steps: %w", err)
 	}
 
+	examplesJSON, err := marshalBlockExamples(block.Examples)
+	if err != nil {
+		return err
+	}
+
 	// Marshal request/response configs
 	var requestJSON, responseJSON []byte
 	if block.Request != nil {
@@ -585,8 +634,8 @@ func (r *BlockDefinitionRepository) Update(ctx context.Context, block *domain.Bl
 			error_codes = $10, required_credentials = $11, is_public = $12,
 			code = $13, ui_config = $14, is_system = $15, version = $16,
 			parent_block_id = $17, config_defaults = $18, pre_process = $19, post_process = $20, internal_steps = $21,
-			group_kind = $22, is_container = $23, request = $24, response = $25,
-			enabled = $26, updated_at = NOW()
+			group_kind = $22, is_container = $23, request = $24, response = $25, examples = $26,
+			enabled = $27, updated_at = NOW()
 		WHERE id = $1
 	`
 
@@ -616,6 +665,7 @@ func (r *BlockDefinitionRepository) Update(ctx context.Context, block *domain.Bl
 		block.IsContainer,
 		requestJSON,
 		responseJSON,
+		examplesJSON,
 		block.Enabled,
 	)
 	if err != nil {
@@ -648,6 +698,101 @@ func (r *BlockDefinitionRepository) Delete(ctx context.Context, id uuid.UUID) er
 	return nil
 }
 
+// DeleteSystem deletes a system block definition (tenant_id IS NULL)
+func (r *BlockDefinitionRepository) DeleteSystem(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM block_definitions
+		WHERE id = $1 AND tenant_id IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete system block definition: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrBlockDefinitionNotFound
+	}
+
+	return nil
+}
+
+// GetReferences returns the non-deleted projects whose steps or block groups use the block
+// (by block_definition_id or type slug, across all tenants) and the blocks inheriting from it
+func (r *BlockDefinitionRepository) GetReferences(ctx context.Context, id uuid.UUID, slug string) (*domain.BlockReferences, error) {
+	projectQuery := `
+		SELECT DISTINCT p.id
+		FROM projects p
+		WHERE p.deleted_at IS NULL AND (
+			EXISTS (SELECT 1 FROM steps s WHERE s.project_id = p.id AND (s.block_definition_id = $1 OR s.type = $2))
+			OR EXISTS (SELECT 1 FROM block_groups g WHERE g.project_id = p.id AND g.type = $2)
+		)
+		ORDER BY p.id
+	`
+	projectIDs, err := r.queryIDs(ctx, projectQuery, id, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects referencing block: %w", err)
+	}
+
+	childQuery := `
+		SELECT id FROM block_definitions
+		WHERE parent_block_id = $1
+		ORDER BY id
+	`
+	childIDs, err := r.queryIDs(ctx, childQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child blocks: %w", err)
+	}
+
+	return &domain.BlockReferences{ProjectIDs: projectIDs, ChildBlockIDs: childIDs}, nil
+}
+
+// GetUsage returns how many steps of each block slug the tenant's non-deleted projects contain
+func (r *BlockDefinitionRepository) GetUsage(ctx context.Context, tenantID uuid.UUID) ([]domain.BlockUsage, error) {
+	query := `
+		SELECT s.type, COUNT(*), MAX(s.created_at)
+		FROM steps s
+		JOIN projects p ON p.id = s.project_id
+		WHERE s.tenant_id = $1 AND p.deleted_at IS NULL
+		GROUP BY s.type
+		ORDER BY s.type
+	`
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make([]domain.BlockUsage, 0)
+	for rows.Next() {
+		var u domain.BlockUsage
+		if err := rows.Scan(&u.Slug, &u.StepCount, &u.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan block usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// queryIDs runs a query selecting a single UUID column
+func (r *BlockDefinitionRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // resolveInheritance resolves the inheritance chain for a block and populates resolved fields
 func (r *BlockDefinitionRepository) resolveInheritance(ctx context.Context, block *domain.BlockDefinition) (*domain.BlockDefinition, error) {
 	if block.ParentBlockID == nil {
@@ -720,6 +865,9 @@ func (r *BlockDefinitionRepository) resolveInheritance(ctx context.Context, bloc
 		Request:  block.Request,
 		Response: block.Response,
 
+		// Examples describe the child's own config defaults, so they are not inherited
+		Examples: block.Examples,
+
 		// Schemas - use child's if set, otherwise inherit from parent chain
 		ConfigSchema: block.ConfigSchema,
 		OutputSchema: block.OutputSchema,
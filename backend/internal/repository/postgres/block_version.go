@@ -26,8 +26,8 @@ func (r *BlockVersionRepository) Create(ctx context.Context, version *domain.Blo
 		INSERT INTO block_versions (
 			id, block_id, version,
 			code, config_schema, output_schema, ui_config,
-			change_summary, changed_by, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			change_summary, changed_by, created_at, content_hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''))
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -41,6 +41,7 @@ func (r *BlockVersionRepository) Create(ctx context.Context, version *domain.Blo
 		version.ChangeSummary,
 		version.ChangedBy,
 		version.CreatedAt,
+		version.ContentHash,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create block version: %w", err)
@@ -53,7 +54,7 @@ func (r *BlockVersionRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 	query := `
 		SELECT id, block_id, version,
 			   code, config_schema, output_schema, ui_config,
-			   change_summary, changed_by, created_at
+			   change_summary, changed_by, created_at, COALESCE(content_hash, '')
 		FROM block_versions
 		WHERE id = $1
 	`
@@ -70,6 +71,7 @@ func (r *BlockVersionRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 		&version.ChangeSummary,
 		&version.ChangedBy,
 		&version.CreatedAt,
+		&version.ContentHash,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -85,7 +87,7 @@ func (r *BlockVersionRepository) GetByBlockAndVersion(ctx context.Context, block
 	query := `
 		SELECT id, block_id, version,
 			   code, config_schema, output_schema, ui_config,
-			   change_summary, changed_by, created_at
+			   change_summary, changed_by, created_at, COALESCE(content_hash, '')
 		FROM block_versions
 		WHERE block_id = $1 AND version = $2
 	`
@@ -102,6 +104,7 @@ func (r *BlockVersionRepository) GetByBlockAndVersion(ctx context.Context, block
 		&version.ChangeSummary,
 		&version.ChangedBy,
 		&version.CreatedAt,
+		&version.ContentHash,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -117,7 +120,7 @@ func (r *BlockVersionRepository) ListByBlock(ctx context.Context, blockID uuid.U
 	query := `
 		SELECT id, block_id, version,
 			   code, config_schema, output_schema, ui_config,
-			   change_summary, changed_by, created_at
+			   change_summary, changed_by, created_at, COALESCE(content_hash, '')
 		FROM block_versions
 		WHERE block_id = $1
 		ORDER BY version DESC
@@ -143,6 +146,7 @@ func (r *BlockVersionRepository) ListByBlock(ctx context.Context, blockID uuid.U
 			&version.ChangeSummary,
 			&version.ChangedBy,
 			&version.CreatedAt,
+			&version.ContentHash,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan block version: %w", err)
@@ -161,7 +165,7 @@ func (r *BlockVersionRepository) GetLatestByBlock(ctx context.Context, blockID u
 	query := `
 		SELECT id, block_id, version,
 			   code, config_schema, output_schema, ui_config,
-			   change_summary, changed_by, created_at
+			   change_summary, changed_by, created_at, COALESCE(content_hash, '')
 		FROM block_versions
 		WHERE block_id = $1
 		ORDER BY version DESC
@@ -180,6 +184,7 @@ func (r *BlockVersionRepository) GetLatestByBlock(ctx context.Context, blockID u
 		&version.ChangeSummary,
 		&version.ChangedBy,
 		&version.CreatedAt,
+		&version.ContentHash,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
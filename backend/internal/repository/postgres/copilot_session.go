@@ -30,9 +30,9 @@ func (r *CopilotSessionRepository) Create(ctx context.Context, session *domain.C
 		INSERT INTO copilot_sessions (
 			id, tenant_id, user_id, context_project_id, mode, title,
 			status, hearing_phase, hearing_progress,
-			spec, project_id, created_at, updated_at
+			spec, project_id, memory_window, memory_cleared_at, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -47,6 +47,8 @@ func (r *CopilotSessionRepository) Create(ctx context.Context, session *domain.C
 		session.HearingProgress,
 		session.Spec,
 		session.ProjectID,
+		session.MemoryWindow,
+		session.MemoryClearedAt,
 		session.CreatedAt,
 		session.UpdatedAt,
 	)
@@ -62,7 +64,7 @@ func (r *CopilotSessionRepository) GetByID(ctx context.Context, tenantID uuid.UU
 	query := `
 		SELECT id, tenant_id, user_id, context_project_id, mode, title,
 			   status, hearing_phase, hearing_progress,
-			   spec, project_id, created_at, updated_at
+			   spec, project_id, memory_window, memory_cleared_at, created_at, updated_at
 		FROM copilot_sessions
 		WHERE id = $1 AND tenant_id = $2
 	`
@@ -85,6 +87,8 @@ func (r *CopilotSessionRepository) GetByID(ctx context.Context, tenantID uuid.UU
 		&session.HearingProgress,
 		&spec,
 		&projectID,
+		&session.MemoryWindow,
+		&session.MemoryClearedAt,
 		&session.CreatedAt,
 		&session.UpdatedAt,
 	)
@@ -181,7 +185,7 @@ func (r *CopilotSessionRepository) GetActiveByUser(ctx context.Context, tenantID
 	query := `
 		SELECT id, tenant_id, user_id, context_project_id, mode, title,
 			   status, hearing_phase, hearing_progress,
-			   spec, project_id, created_at, updated_at
+			   spec, project_id, memory_window, memory_cleared_at, created_at, updated_at
 		FROM copilot_sessions
 		WHERE tenant_id = $1 AND user_id = $2
 		  AND status NOT IN ('completed', 'abandoned')
@@ -207,6 +211,8 @@ func (r *CopilotSessionRepository) GetActiveByUser(ctx context.Context, tenantID
 		&session.HearingProgress,
 		&spec,
 		&projectID,
+		&session.MemoryWindow,
+		&session.MemoryClearedAt,
 		&session.CreatedAt,
 		&session.UpdatedAt,
 	)
@@ -240,7 +246,7 @@ func (r *CopilotSessionRepository) GetActiveByUserAndProject(ctx context.Context
 	query := `
 		SELECT id, tenant_id, user_id, context_project_id, mode, title,
 			   status, hearing_phase, hearing_progress,
-			   spec, project_id, created_at, updated_at
+			   spec, project_id, memory_window, memory_cleared_at, created_at, updated_at
 		FROM copilot_sessions
 		WHERE tenant_id = $1 AND user_id = $2 AND context_project_id = $3
 		  AND status NOT IN ('completed', 'abandoned')
@@ -266,6 +272,8 @@ func (r *CopilotSessionRepository) GetActiveByUserAndProject(ctx context.Context
 		&session.HearingProgress,
 		&spec,
 		&genProjectID,
+		&session.MemoryWindow,
+		&session.MemoryClearedAt,
 		&session.CreatedAt,
 		&session.UpdatedAt,
 	)
@@ -387,7 +395,7 @@ func (r *CopilotSessionRepository) ListByUser(ctx context.Context, tenantID uuid
 	query := `
 		SELECT id, tenant_id, user_id, context_project_id, mode, title,
 			   status, hearing_phase, hearing_progress,
-			   spec, project_id, created_at, updated_at
+			   spec, project_id, memory_window, memory_cleared_at, created_at, updated_at
 		FROM copilot_sessions
 		WHERE tenant_id = $1 AND user_id = $2
 	`
@@ -439,6 +447,8 @@ func (r *CopilotSessionRepository) ListByUser(ctx context.Context, tenantID uuid
 			&session.HearingProgress,
 			&spec,
 			&projectID,
+			&session.MemoryWindow,
+			&session.MemoryClearedAt,
 			&session.CreatedAt,
 			&session.UpdatedAt,
 		)
@@ -476,7 +486,7 @@ func (r *CopilotSessionRepository) ListByUserAndProject(ctx context.Context, ten
 	query := `
 		SELECT id, tenant_id, user_id, context_project_id, mode, title,
 			   status, hearing_phase, hearing_progress,
-			   spec, project_id, created_at, updated_at
+			   spec, project_id, memory_window, memory_cleared_at, created_at, updated_at
 		FROM copilot_sessions
 		WHERE tenant_id = $1 AND user_id = $2 AND context_project_id = $3
 		ORDER BY created_at DESC
@@ -508,6 +518,8 @@ func (r *CopilotSessionRepository) ListByUserAndProject(ctx context.Context, ten
 			&session.HearingProgress,
 			&spec,
 			&genProjectID,
+			&session.MemoryWindow,
+			&session.MemoryClearedAt,
 			&session.CreatedAt,
 			&session.UpdatedAt,
 		)
@@ -545,8 +557,8 @@ func (r *CopilotSessionRepository) Update(ctx context.Context, session *domain.C
 	query := `
 		UPDATE copilot_sessions
 		SET title = $1, status = $2, hearing_phase = $3, hearing_progress = $4,
-			spec = $5, project_id = $6, updated_at = $7
-		WHERE id = $8 AND tenant_id = $9
+			spec = $5, project_id = $6, memory_window = $7, memory_cleared_at = $8, updated_at = $9
+		WHERE id = $10 AND tenant_id = $11
 	`
 
 	result, err := r.pool.Exec(ctx, query,
@@ -556,6 +568,8 @@ func (r *CopilotSessionRepository) Update(ctx context.Context, session *domain.C
 		session.HearingProgress,
 		session.Spec,
 		session.ProjectID,
+		session.MemoryWindow,
+		session.MemoryClearedAt,
 		session.UpdatedAt,
 		session.ID,
 		session.TenantID,
@@ -190,13 +190,9 @@ func (r *CredentialRepository) List(ctx context.Context, tenantID uuid.UUID, fil
 			   metadata, expires_at, status, created_at, updated_at
 		FROM credentials` + whereClause
 
-	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, argIdx, argIdx+1)
-	page := filter.Page
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * filter.Limit
-	args = append(args, filter.Limit, offset)
+	pageClause, pageArgs := listPageClause("created_at", filter.After, filter.Page, filter.Limit, argIdx)
+	query += pageClause
+	args = append(args, pageArgs...)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// FavoriteRepository implements repository.FavoriteRepository
+type FavoriteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFavoriteRepository creates a new FavoriteRepository
+func NewFavoriteRepository(pool *pgxpool.Pool) *FavoriteRepository {
+	return &FavoriteRepository{pool: pool}
+}
+
+// Add favorites a target for the user
+func (r *FavoriteRepository) Add(ctx context.Context, favorite *domain.Favorite) error {
+	query := `
+		INSERT INTO favorites (tenant_id, user_id, target_type, target_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, user_id, target_type, target_id) DO NOTHING
+	`
+	_, err := r.pool.Exec(ctx, query,
+		favorite.TenantID, favorite.UserID, favorite.TargetType, favorite.TargetID, favorite.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("add favorite: %w", err)
+	}
+	return nil
+}
+
+// Remove unfavorites a target for the user
+func (r *FavoriteRepository) Remove(ctx context.Context, tenantID, userID uuid.UUID, targetType domain.FavoriteTargetType, targetID uuid.UUID) error {
+	query := `
+		DELETE FROM favorites
+		WHERE tenant_id = $1 AND user_id = $2 AND target_type = $3 AND target_id = $4
+	`
+	if _, err := r.pool.Exec(ctx, query, tenantID, userID, targetType, targetID); err != nil {
+		return fmt.Errorf("remove favorite: %w", err)
+	}
+	return nil
+}
+
+// ListTargetIDs returns the IDs the user has favorited for a target type
+func (r *FavoriteRepository) ListTargetIDs(ctx context.Context, tenantID, userID uuid.UUID, targetType domain.FavoriteTargetType) ([]uuid.UUID, error) {
+	query := `
+		SELECT target_id
+		FROM favorites
+		WHERE tenant_id = $1 AND user_id = $2 AND target_type = $3
+		ORDER BY created_at DESC
+	`
+	rows, err := r.pool.Query(ctx, query, tenantID, userID, targetType)
+	if err != nil {
+		return nil, fmt.Errorf("list favorites: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan favorite: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
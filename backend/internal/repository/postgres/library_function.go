@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// LibraryFunctionRepository implements repository.LibraryFunctionRepository
+type LibraryFunctionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLibraryFunctionRepository creates a new LibraryFunctionRepository
+func NewLibraryFunctionRepository(pool *pgxpool.Pool) *LibraryFunctionRepository {
+	return &LibraryFunctionRepository{pool: pool}
+}
+
+const libraryFunctionColumns = `id, tenant_id, name, description, code, created_by, created_at, updated_at`
+
+// Create creates a new library function
+func (r *LibraryFunctionRepository) Create(ctx context.Context, function *domain.LibraryFunction) error {
+	query := `
+		INSERT INTO library_functions (` + libraryFunctionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		function.ID, function.TenantID, function.Name, function.Description, function.Code,
+		function.CreatedBy, function.CreatedAt, function.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrLibraryFunctionExists
+	}
+	if err != nil {
+		return fmt.Errorf("create library function: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a library function by ID
+func (r *LibraryFunctionRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.LibraryFunction, error) {
+	query := `
+		SELECT ` + libraryFunctionColumns + `
+		FROM library_functions
+		WHERE tenant_id = $1 AND id = $2
+	`
+	function, err := scanLibraryFunction(r.pool.QueryRow(ctx, query, tenantID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrLibraryFunctionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get library function: %w", err)
+	}
+	return function, nil
+}
+
+// GetByNames retrieves the library functions of a tenant with the given names
+func (r *LibraryFunctionRepository) GetByNames(ctx context.Context, tenantID uuid.UUID, names []string) ([]*domain.LibraryFunction, error) {
+	query := `
+		SELECT ` + libraryFunctionColumns + `
+		FROM library_functions
+		WHERE tenant_id = $1 AND name = ANY($2)
+	`
+	return r.queryLibraryFunctions(ctx, query, tenantID, names)
+}
+
+// ListByTenant retrieves all library functions of a tenant, ordered by name
+func (r *LibraryFunctionRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.LibraryFunction, error) {
+	query := `
+		SELECT ` + libraryFunctionColumns + `
+		FROM library_functions
+		WHERE tenant_id = $1
+		ORDER BY name
+	`
+	return r.queryLibraryFunctions(ctx, query, tenantID)
+}
+
+func (r *LibraryFunctionRepository) queryLibraryFunctions(ctx context.Context, query string, args ...interface{}) ([]*domain.LibraryFunction, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list library functions: %w", err)
+	}
+	defer rows.Close()
+
+	functions := make([]*domain.LibraryFunction, 0)
+	for rows.Next() {
+		function, err := scanLibraryFunction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan library function: %w", err)
+		}
+		functions = append(functions, function)
+	}
+	return functions, rows.Err()
+}
+
+// Update updates a library function
+func (r *LibraryFunctionRepository) Update(ctx context.Context, function *domain.LibraryFunction) error {
+	function.UpdatedAt = time.Now().UTC()
+	query := `
+		UPDATE library_functions
+		SET name = $3, description = $4, code = $5, updated_at = $6
+		WHERE tenant_id = $1 AND id = $2
+	`
+	result, err := r.pool.Exec(ctx, query,
+		function.TenantID, function.ID, function.Name, function.Description, function.Code, function.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrLibraryFunctionExists
+	}
+	if err != nil {
+		return fmt.Errorf("update library function: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrLibraryFunctionNotFound
+	}
+	return nil
+}
+
+// Delete deletes a library function
+func (r *LibraryFunctionRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM library_functions WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("delete library function: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrLibraryFunctionNotFound
+	}
+	return nil
+}
+
+func scanLibraryFunction(row pgx.Row) (*domain.LibraryFunction, error) {
+	var f domain.LibraryFunction
+	if err := row.Scan(
+		&f.ID, &f.TenantID, &f.Name, &f.Description, &f.Code,
+		&f.CreatedBy, &f.CreatedAt, &f.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// listPageClause returns the SQL that follows the WHERE clause of a list ordered newest first by
+// column, with id as the tie-breaker. With a keyset cursor the page starts after it; otherwise
+// page and limit select an offset page, and a limit of 0 returns every row. argIndex is the
+// number of the first placeholder the clause may use.
+func listPageClause(column string, after *repository.ListCursor, page, limit, argIndex int) (string, []interface{}) {
+	var clause string
+	var args []interface{}
+	if after != nil {
+		clause = fmt.Sprintf(` AND (%s, id) < ($%d, $%d)`, column, argIndex, argIndex+1)
+		args = append(args, after.Time, after.ID)
+		argIndex += 2
+	}
+
+	clause += fmt.Sprintf(` ORDER BY %s DESC, id DESC`, column)
+
+	if limit > 0 {
+		clause += fmt.Sprintf(` LIMIT $%d`, argIndex)
+		args = append(args, limit)
+		if after == nil {
+			if page < 1 {
+				page = 1
+			}
+			clause += fmt.Sprintf(` OFFSET $%d`, argIndex+1)
+			args = append(args, (page-1)*limit)
+		}
+	}
+	return clause, args
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+func TestListPageClause(t *testing.T) {
+	cursor := &repository.ListCursor{
+		Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ID:   uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+	}
+
+	tests := []struct {
+		name       string
+		after      *repository.ListCursor
+		page       int
+		limit      int
+		wantClause string
+		wantArgs   []interface{}
+	}{
+		{
+			name:       "offset page",
+			page:       3,
+			limit:      20,
+			wantClause: " ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3",
+			wantArgs:   []interface{}{20, 40},
+		},
+		{
+			name:       "page below one is the first page",
+			limit:      20,
+			wantClause: " ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3",
+			wantArgs:   []interface{}{20, 0},
+		},
+		{
+			name:       "keyset cursor replaces the offset",
+			after:      cursor,
+			page:       3,
+			limit:      21,
+			wantClause: " AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4",
+			wantArgs:   []interface{}{cursor.Time, cursor.ID, 21},
+		},
+		{
+			name:       "no limit",
+			wantClause: " ORDER BY created_at DESC, id DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, args := listPageClause("created_at", tt.after, tt.page, tt.limit, 2)
+			if clause != tt.wantClause {
+				t.Errorf("clause = %q, want %q", clause, tt.wantClause)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// NotificationChannelRepository implements repository.NotificationChannelRepository
+type NotificationChannelRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNotificationChannelRepository creates a new NotificationChannelRepository
+func NewNotificationChannelRepository(pool *pgxpool.Pool) *NotificationChannelRepository {
+	return &NotificationChannelRepository{pool: pool}
+}
+
+const notificationChannelColumns = `id, tenant_id, name, type, config, events, enabled, created_by, created_at, updated_at`
+
+// Create creates a new notification channel
+func (r *NotificationChannelRepository) Create(ctx context.Context, channel *domain.NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (` + notificationChannelColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		channel.ID, channel.TenantID, channel.Name, channel.Type, channel.Config,
+		notificationEventStrings(channel.Events), channel.Enabled, channel.CreatedBy,
+		channel.CreatedAt, channel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create notification channel: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a notification channel by ID
+func (r *NotificationChannelRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.NotificationChannel, error) {
+	query := `
+		SELECT ` + notificationChannelColumns + `
+		FROM notification_channels
+		WHERE tenant_id = $1 AND id = $2
+	`
+	channel, err := scanNotificationChannel(r.pool.QueryRow(ctx, query, tenantID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotificationChannelNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get notification channel: %w", err)
+	}
+	return channel, nil
+}
+
+// ListByTenant retrieves all notification channels of a tenant, oldest first
+func (r *NotificationChannelRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.NotificationChannel, error) {
+	query := `
+		SELECT ` + notificationChannelColumns + `
+		FROM notification_channels
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := make([]*domain.NotificationChannel, 0)
+	for rows.Next() {
+		channel, err := scanNotificationChannel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan notification channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+// Update updates a notification channel
+func (r *NotificationChannelRepository) Update(ctx context.Context, channel *domain.NotificationChannel) error {
+	channel.UpdatedAt = time.Now().UTC()
+	query := `
+		UPDATE notification_channels
+		SET name = $3, config = $4, events = $5, enabled = $6, updated_at = $7
+		WHERE tenant_id = $1 AND id = $2
+	`
+	result, err := r.pool.Exec(ctx, query,
+		channel.TenantID, channel.ID, channel.Name, channel.Config,
+		notificationEventStrings(channel.Events), channel.Enabled, channel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update notification channel: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotificationChannelNotFound
+	}
+	return nil
+}
+
+// Delete deletes a notification channel
+func (r *NotificationChannelRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM notification_channels WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("delete notification channel: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotificationChannelNotFound
+	}
+	return nil
+}
+
+func scanNotificationChannel(row pgx.Row) (*domain.NotificationChannel, error) {
+	var c domain.NotificationChannel
+	var events []string
+	if err := row.Scan(
+		&c.ID, &c.TenantID, &c.Name, &c.Type, &c.Config, &events,
+		&c.Enabled, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	c.Events = make([]domain.NotificationEvent, len(events))
+	for i, event := range events {
+		c.Events[i] = domain.NotificationEvent(event)
+	}
+	return &c, nil
+}
+
+// notificationEventStrings converts events for the TEXT[] column
+func notificationEventStrings(events []domain.NotificationEvent) []string {
+	values := make([]string, len(events))
+	for i, event := range events {
+		values[i] = string(event)
+	}
+	return values
+}
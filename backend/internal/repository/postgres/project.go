@@ -32,13 +32,13 @@ func NewProjectRepositoryWithDB(db DB) *ProjectRepository {
 // Create creates a new project
 func (r *ProjectRepository) Create(ctx context.Context, p *domain.Project) error {
 	query := `
-		INSERT INTO projects (id, tenant_id, name, description, status, version, variables, draft, created_by, created_at, updated_at, is_system, system_slug)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		INSERT INTO projects (id, tenant_id, name, description, status, version, variables, draft, created_by, created_at, updated_at, is_system, system_slug, tags, run_retry, run_output, failure_notifications, paused)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`
 	_, err := r.db.Exec(ctx, query,
 		p.ID, p.TenantID, p.Name, p.Description, p.Status, p.Version,
 		p.Variables, p.Draft, p.CreatedBy, p.CreatedAt, p.UpdatedAt,
-		p.IsSystem, p.SystemSlug,
+		p.IsSystem, p.SystemSlug, nonNilTags(p.Tags), p.RunRetry, p.RunOutput, failureNotificationsJSON(p.FailureNotifications), p.Paused,
 	)
 	if err != nil {
 		return fmt.Errorf("create project: %w", err)
@@ -51,7 +51,7 @@ func (r *ProjectRepository) Create(ctx context.Context, p *domain.Project) error
 func (r *ProjectRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error) {
 	query := `
 		SELECT id, tenant_id, name, description, status, version, variables, draft,
-		       created_by, published_at, created_at, updated_at, deleted_at, is_system, system_slug
+		       created_by, published_at, created_at, updated_at, deleted_at, is_system, system_slug, tags, run_retry, run_output, failure_notifications, paused
 		FROM projects
 		WHERE id = $1 AND deleted_at IS NULL
 		  AND (tenant_id = $2 OR is_system = TRUE)
@@ -60,7 +60,7 @@ func (r *ProjectRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID)
 	err := r.db.QueryRow(ctx, query, id, tenantID).Scan(
 		&p.ID, &p.TenantID, &p.Name, &p.Description, &p.Status, &p.Version,
 		&p.Variables, &p.Draft, &p.CreatedBy, &p.PublishedAt,
-		&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.IsSystem, &p.SystemSlug,
+		&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.IsSystem, &p.SystemSlug, &p.Tags, &p.RunRetry, &p.RunOutput, &p.FailureNotifications, &p.Paused,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrProjectNotFound
@@ -75,16 +75,11 @@ func (r *ProjectRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID)
 
 // List retrieves projects with pagination
 func (r *ProjectRepository) List(ctx context.Context, tenantID uuid.UUID, filter repository.ProjectFilter) ([]*domain.Project, int, error) {
-	// Count query
-	countQuery := `SELECT COUNT(*) FROM projects WHERE tenant_id = $1 AND deleted_at IS NULL`
-	args := []interface{}{tenantID}
-	argIndex := 2
+	where, args := buildProjectListWhere(tenantID, filter)
+	argIndex := len(args) + 1
 
-	if filter.Status != nil {
-		countQuery += fmt.Sprintf(` AND status = $%d`, argIndex)
-		args = append(args, *filter.Status)
-		argIndex++
-	}
+	// Count query
+	countQuery := `SELECT COUNT(*) FROM projects ` + where
 
 	var total int
 	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
@@ -94,30 +89,13 @@ func (r *ProjectRepository) List(ctx context.Context, tenantID uuid.UUID, filter
 	// List query
 	query := `
 		SELECT id, tenant_id, name, description, status, version, variables, draft,
-		       created_by, published_at, created_at, updated_at, deleted_at, is_system, system_slug
+		       created_by, published_at, created_at, updated_at, deleted_at, is_system, system_slug, tags, run_retry, run_output, failure_notifications, paused
 		FROM projects
-		WHERE tenant_id = $1 AND deleted_at IS NULL
-	`
-	args = []interface{}{tenantID}
-	argIndex = 2
+	` + where
 
-	if filter.Status != nil {
-		query += fmt.Sprintf(` AND status = $%d`, argIndex)
-		args = append(args, *filter.Status)
-		argIndex++
-	}
-
-	query += ` ORDER BY updated_at DESC`
-
-	if filter.Limit > 0 {
-		page := filter.Page
-		if page < 1 {
-			page = 1
-		}
-		offset := (page - 1) * filter.Limit
-		query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
-		args = append(args, filter.Limit, offset)
-	}
+	pageClause, pageArgs := listPageClause("updated_at", filter.After, filter.Page, filter.Limit, argIndex)
+	query += pageClause
+	args = append(args, pageArgs...)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -131,7 +109,7 @@ func (r *ProjectRepository) List(ctx context.Context, tenantID uuid.UUID, filter
 		if err := rows.Scan(
 			&p.ID, &p.TenantID, &p.Name, &p.Description, &p.Status, &p.Version,
 			&p.Variables, &p.Draft, &p.CreatedBy, &p.PublishedAt,
-			&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.IsSystem, &p.SystemSlug,
+			&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.IsSystem, &p.SystemSlug, &p.Tags, &p.RunRetry, &p.RunOutput, &p.FailureNotifications, &p.Paused,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan project: %w", err)
 		}
@@ -147,26 +125,142 @@ func (r *ProjectRepository) List(ctx context.Context, tenantID uuid.UUID, filter
 	return projects, total, nil
 }
 
+// buildProjectListWhere builds the WHERE clause and arguments for listing projects.
+// Tags are matched with array containment, so a project must carry all requested tags.
+func buildProjectListWhere(tenantID uuid.UUID, filter repository.ProjectFilter) (string, []interface{}) {
+	where := `WHERE tenant_id = $1 AND deleted_at IS NULL`
+	args := []interface{}{tenantID}
+	argIndex := 2
+
+	if filter.Status != nil {
+		where += fmt.Sprintf(` AND status = $%d`, argIndex)
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	if tags := domain.NormalizeTags(filter.Tags); len(tags) > 0 {
+		where += fmt.Sprintf(` AND tags @> $%d::text[]`, argIndex)
+		args = append(args, tags)
+		argIndex++
+	}
+
+	if filter.IDs != nil {
+		where += fmt.Sprintf(` AND id = ANY($%d)`, argIndex)
+		args = append(args, filter.IDs)
+	}
+
+	return where, args
+}
+
+// ListTags returns the tenant's distinct project tags with the number of projects using each
+func (r *ProjectRepository) ListTags(ctx context.Context, tenantID uuid.UUID) ([]domain.ProjectTagCount, error) {
+	query := `
+		SELECT tag, COUNT(*)
+		FROM projects, unnest(tags) AS tag
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag
+	`
+	rows, err := r.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list project tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]domain.ProjectTagCount, 0)
+	for rows.Next() {
+		var tc domain.ProjectTagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, fmt.Errorf("scan project tag: %w", err)
+		}
+		tags = append(tags, tc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate project tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// nonNilTags ensures tags are stored as an empty array rather than NULL
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// failureNotificationsJSON stores a project without failure notification rules as NULL
+func failureNotificationsJSON(rules []domain.FailureNotificationRule) interface{} {
+	if len(rules) == 0 {
+		return nil
+	}
+	return rules
+}
+
 // Update updates a project
 func (r *ProjectRepository) Update(ctx context.Context, p *domain.Project) error {
-	p.UpdatedAt = time.Now().UTC()
+	return r.update(ctx, p, nil)
+}
+
+// UpdateIfUnmodified updates a project only if no other write changed it since it was read
+func (r *ProjectRepository) UpdateIfUnmodified(ctx context.Context, p *domain.Project, unmodifiedSince time.Time) error {
+	return r.update(ctx, p, &unmodifiedSince)
+}
+
+// SetPaused pauses or resumes a project. It leaves updated_at alone so that concurrent edits are
+// not rejected as modifications.
+func (r *ProjectRepository) SetPaused(ctx context.Context, tenantID, id uuid.UUID, paused bool) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE projects SET paused = $1 WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL`,
+		paused, id, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("set project paused: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrProjectNotFound
+	}
+	return nil
+}
+
+// update writes a project; with unmodifiedSince set the write is conditional on updated_at
+func (r *ProjectRepository) update(ctx context.Context, p *domain.Project, unmodifiedSince *time.Time) error {
+	updatedAt := time.Now().UTC()
 	query := `
 		UPDATE projects
 		SET name = $1, description = $2, status = $3, version = $4,
-		    variables = $5, draft = $6, published_at = $7, updated_at = $8
-		WHERE id = $9 AND tenant_id = $10 AND deleted_at IS NULL
+		    variables = $5, draft = $6, published_at = $7, updated_at = $8, tags = $9,
+		    run_retry = $10, run_output = $11, failure_notifications = $12
+		WHERE id = $13 AND tenant_id = $14 AND deleted_at IS NULL
+		    AND ($15::timestamptz IS NULL OR updated_at = $15)
 	`
 	result, err := r.db.Exec(ctx, query,
 		p.Name, p.Description, p.Status, p.Version,
-		p.Variables, p.Draft, p.PublishedAt, p.UpdatedAt,
-		p.ID, p.TenantID,
+		p.Variables, p.Draft, p.PublishedAt, updatedAt, nonNilTags(p.Tags),
+		p.RunRetry, p.RunOutput, failureNotificationsJSON(p.FailureNotifications),
+		p.ID, p.TenantID, unmodifiedSince,
 	)
 	if err != nil {
 		return fmt.Errorf("update project: %w", err)
 	}
 	if result.RowsAffected() == 0 {
+		if unmodifiedSince != nil {
+			var exists bool
+			if err := r.db.QueryRow(ctx,
+				`SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL)`,
+				p.ID, p.TenantID,
+			).Scan(&exists); err != nil {
+				return fmt.Errorf("update project: %w", err)
+			}
+			if exists {
+				return domain.ErrConcurrentModification
+			}
+		}
 		return domain.ErrProjectNotFound
 	}
+	p.UpdatedAt = updatedAt
 	return nil
 }
 
@@ -310,7 +404,7 @@ func (r *ProjectRepository) GetWithStepsAndEdges(ctx context.Context, tenantID,
 func (r *ProjectRepository) GetSystemBySlug(ctx context.Context, slug string) (*domain.Project, error) {
 	query := `
 		SELECT id, tenant_id, name, description, status, version, variables, draft,
-		       created_by, published_at, created_at, updated_at, deleted_at, is_system, system_slug
+		       created_by, published_at, created_at, updated_at, deleted_at, is_system, system_slug, tags, run_retry, run_output, failure_notifications, paused
 		FROM projects
 		WHERE system_slug = $1 AND is_system = TRUE AND deleted_at IS NULL
 	`
@@ -318,7 +412,7 @@ func (r *ProjectRepository) GetSystemBySlug(ctx context.Context, slug string) (*
 	err := r.db.QueryRow(ctx, query, slug).Scan(
 		&p.ID, &p.TenantID, &p.Name, &p.Description, &p.Status, &p.Version,
 		&p.Variables, &p.Draft, &p.CreatedBy, &p.PublishedAt,
-		&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.IsSystem, &p.SystemSlug,
+		&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.IsSystem, &p.SystemSlug, &p.Tags, &p.RunRetry, &p.RunOutput, &p.FailureNotifications, &p.Paused,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrProjectNotFound
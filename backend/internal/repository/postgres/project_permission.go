@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// ProjectPermissionRepository implements repository.ProjectPermissionRepository
+type ProjectPermissionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewProjectPermissionRepository creates a new ProjectPermissionRepository
+func NewProjectPermissionRepository(pool *pgxpool.Pool) *ProjectPermissionRepository {
+	return &ProjectPermissionRepository{pool: pool}
+}
+
+// Upsert grants a permission, replacing the user's existing role on the workflow
+func (r *ProjectPermissionRepository) Upsert(ctx context.Context, permission *domain.ProjectPermission) error {
+	query := `
+		INSERT INTO project_permissions (tenant_id, project_id, user_id, role, granted_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (project_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.pool.Exec(ctx, query,
+		permission.TenantID, permission.ProjectID, permission.UserID, permission.Role,
+		permission.GrantedBy, permission.CreatedAt, permission.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert project permission: %w", err)
+	}
+	return nil
+}
+
+// Delete revokes a user's permission on a workflow
+func (r *ProjectPermissionRepository) Delete(ctx context.Context, tenantID, projectID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM project_permissions
+		WHERE tenant_id = $1 AND project_id = $2 AND user_id = $3
+	`
+	result, err := r.pool.Exec(ctx, query, tenantID, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("delete project permission: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrProjectPermissionNotFound
+	}
+	return nil
+}
+
+// ListByProject returns the permissions of a workflow, owners first
+func (r *ProjectPermissionRepository) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.ProjectPermission, error) {
+	query := `
+		SELECT tenant_id, project_id, user_id, role, granted_by, created_at, updated_at
+		FROM project_permissions
+		WHERE tenant_id = $1 AND project_id = $2
+		ORDER BY CASE role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 WHEN 'runner' THEN 2 ELSE 3 END, created_at
+	`
+	rows, err := r.pool.Query(ctx, query, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list project permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := make([]*domain.ProjectPermission, 0)
+	for rows.Next() {
+		var p domain.ProjectPermission
+		if err := rows.Scan(&p.TenantID, &p.ProjectID, &p.UserID, &p.Role, &p.GrantedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan project permission: %w", err)
+		}
+		permissions = append(permissions, &p)
+	}
+	return permissions, rows.Err()
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+func TestBuildProjectListWhere(t *testing.T) {
+	tenantID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	published := domain.ProjectStatusPublished
+
+	tests := []struct {
+		name      string
+		filter    repository.ProjectFilter
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "no filters",
+			filter:    repository.ProjectFilter{},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL",
+			wantArgs:  []interface{}{tenantID},
+		},
+		{
+			name:      "single tag",
+			filter:    repository.ProjectFilter{Tags: []string{"Sales"}},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND tags @> $2::text[]",
+			wantArgs:  []interface{}{tenantID, []string{"sales"}},
+		},
+		{
+			name:      "multiple tags use a single containment (AND) condition",
+			filter:    repository.ProjectFilter{Tags: []string{"weekly", "CRM", "crm"}},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND tags @> $2::text[]",
+			wantArgs:  []interface{}{tenantID, []string{"crm", "weekly"}},
+		},
+		{
+			name:      "status and tags",
+			filter:    repository.ProjectFilter{Status: &published, Tags: []string{"ops"}},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND status = $2 AND tags @> $3::text[]",
+			wantArgs:  []interface{}{tenantID, published, []string{"ops"}},
+		},
+		{
+			name:      "tags and IDs",
+			filter:    repository.ProjectFilter{Tags: []string{"ops"}, IDs: []uuid.UUID{tenantID}},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND tags @> $2::text[] AND id = ANY($3)",
+			wantArgs:  []interface{}{tenantID, []string{"ops"}, []uuid.UUID{tenantID}},
+		},
+		{
+			name:      "blank tags are ignored",
+			filter:    repository.ProjectFilter{Tags: []string{" "}},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL",
+			wantArgs:  []interface{}{tenantID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := buildProjectListWhere(tenantID, tt.filter)
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
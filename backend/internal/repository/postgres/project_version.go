@@ -23,11 +23,28 @@ func NewProjectVersionRepository(pool *pgxpool.Pool) *ProjectVersionRepository {
 // Create creates a new project version snapshot
 func (r *ProjectVersionRepository) Create(ctx context.Context, v *domain.ProjectVersion) error {
 	query := `
-		INSERT INTO project_versions (id, project_id, version, definition, saved_by, saved_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO project_versions (id, project_id, version, definition, saved_by, saved_at, content_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
 	`
 	_, err := r.pool.Exec(ctx, query,
-		v.ID, v.ProjectID, v.Version, v.Definition, v.SavedBy, v.SavedAt,
+		v.ID, v.ProjectID, v.Version, v.Definition, v.SavedBy, v.SavedAt, v.ContentHash,
+	)
+	return err
+}
+
+// Upsert creates a project version snapshot, replacing the snapshot of an existing version number
+func (r *ProjectVersionRepository) Upsert(ctx context.Context, v *domain.ProjectVersion) error {
+	query := `
+		INSERT INTO project_versions (id, project_id, version, definition, saved_by, saved_at, content_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		ON CONFLICT (project_id, version) DO UPDATE SET
+			definition = EXCLUDED.definition,
+			saved_by = EXCLUDED.saved_by,
+			saved_at = EXCLUDED.saved_at,
+			content_hash = EXCLUDED.content_hash
+	`
+	_, err := r.pool.Exec(ctx, query,
+		v.ID, v.ProjectID, v.Version, v.Definition, v.SavedBy, v.SavedAt, v.ContentHash,
 	)
 	return err
 }
@@ -35,13 +52,13 @@ func (r *ProjectVersionRepository) Create(ctx context.Context, v *domain.Project
 // GetByProjectAndVersion retrieves a specific version of a project
 func (r *ProjectVersionRepository) GetByProjectAndVersion(ctx context.Context, projectID uuid.UUID, version int) (*domain.ProjectVersion, error) {
 	query := `
-		SELECT id, project_id, version, definition, saved_by, saved_at
+		SELECT id, project_id, version, definition, saved_by, saved_at, COALESCE(content_hash, '')
 		FROM project_versions
 		WHERE project_id = $1 AND version = $2
 	`
 	var v domain.ProjectVersion
 	err := r.pool.QueryRow(ctx, query, projectID, version).Scan(
-		&v.ID, &v.ProjectID, &v.Version, &v.Definition, &v.SavedBy, &v.SavedAt,
+		&v.ID, &v.ProjectID, &v.Version, &v.Definition, &v.SavedBy, &v.SavedAt, &v.ContentHash,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrProjectVersionNotFound
@@ -55,7 +72,7 @@ func (r *ProjectVersionRepository) GetByProjectAndVersion(ctx context.Context, p
 // GetLatestByProject retrieves the latest version of a project
 func (r *ProjectVersionRepository) GetLatestByProject(ctx context.Context, projectID uuid.UUID) (*domain.ProjectVersion, error) {
 	query := `
-		SELECT id, project_id, version, definition, saved_by, saved_at
+		SELECT id, project_id, version, definition, saved_by, saved_at, COALESCE(content_hash, '')
 		FROM project_versions
 		WHERE project_id = $1
 		ORDER BY version DESC
@@ -63,7 +80,7 @@ func (r *ProjectVersionRepository) GetLatestByProject(ctx context.Context, proje
 	`
 	var v domain.ProjectVersion
 	err := r.pool.QueryRow(ctx, query, projectID).Scan(
-		&v.ID, &v.ProjectID, &v.Version, &v.Definition, &v.SavedBy, &v.SavedAt,
+		&v.ID, &v.ProjectID, &v.Version, &v.Definition, &v.SavedBy, &v.SavedAt, &v.ContentHash,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrProjectVersionNotFound
@@ -77,7 +94,7 @@ func (r *ProjectVersionRepository) GetLatestByProject(ctx context.Context, proje
 // ListByProject retrieves all versions of a project
 func (r *ProjectVersionRepository) ListByProject(ctx context.Context, projectID uuid.UUID) ([]*domain.ProjectVersion, error) {
 	query := `
-		SELECT id, project_id, version, definition, saved_by, saved_at
+		SELECT id, project_id, version, definition, saved_by, saved_at, COALESCE(content_hash, '')
 		FROM project_versions
 		WHERE project_id = $1
 		ORDER BY version DESC
@@ -92,7 +109,7 @@ func (r *ProjectVersionRepository) ListByProject(ctx context.Context, projectID
 	for rows.Next() {
 		var v domain.ProjectVersion
 		if err := rows.Scan(
-			&v.ID, &v.ProjectID, &v.Version, &v.Definition, &v.SavedBy, &v.SavedAt,
+			&v.ID, &v.ProjectID, &v.Version, &v.Definition, &v.SavedBy, &v.SavedAt, &v.ContentHash,
 		); err != nil {
 			return nil, err
 		}
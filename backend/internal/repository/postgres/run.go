@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -27,19 +28,36 @@ func NewRunRepositoryWithDB(db DB) *RunRepository {
 	return &RunRepository{db: db}
 }
 
-// Create creates a new run
+// Create creates a new run. Unless the run already carries a variables snapshot (e.g. a retry),
+// the tenant's, project's and triggering user's variables are snapshotted in the same statement.
+// Project variables come from the project version being run, falling back to the live project
+// only when that version does not exist (e.g. version 0 for inline step tests). Every lookup is
+// scoped to the run's tenant; system projects, which any tenant can run, are the only exception.
 func (r *RunRepository) Create(ctx context.Context, run *domain.Run) error {
 	query := `
 		INSERT INTO runs (id, tenant_id, project_id, project_version, start_step_id, status, input,
-		                  triggered_by, triggered_by_user, created_at, trigger_source, trigger_metadata)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING run_number
+		                  triggered_by, triggered_by_user, created_at, trigger_source, trigger_metadata,
+		                  retry_of_run_id, retry_attempt, variables_snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+		        COALESCE($15, jsonb_build_object(
+		            'org', COALESCE((SELECT variables FROM tenants WHERE id = $2), '{}'::jsonb),
+		            'project', COALESCE(
+		                (SELECT NULLIF(pv.definition->'variables', 'null'::jsonb)
+		                 FROM project_versions pv
+		                 JOIN projects p ON p.id = pv.project_id
+		                 WHERE pv.project_id = $3 AND pv.version = $4 AND (p.tenant_id = $2 OR p.is_system = TRUE)),
+		                (SELECT variables FROM projects WHERE id = $3 AND (tenant_id = $2 OR is_system = TRUE)),
+		                '{}'::jsonb),
+		            'personal', COALESCE((SELECT variables FROM users WHERE id = $9 AND tenant_id = $2), '{}'::jsonb)
+		        )))
+		RETURNING run_number, variables_snapshot
 	`
 	err := r.db.QueryRow(ctx, query,
 		run.ID, run.TenantID, run.ProjectID, run.ProjectVersion, run.StartStepID, run.Status,
 		run.Input, run.TriggeredBy, run.TriggeredByUser, run.CreatedAt,
 		run.TriggerSource, run.TriggerMetadata,
-	).Scan(&run.RunNumber)
+		run.RetryOfRunID, run.RetryAttempt, run.VariablesSnapshot,
+	).Scan(&run.RunNumber, &run.VariablesSnapshot)
 	if err != nil {
 		return fmt.Errorf("failed to create run: %w", err)
 	}
@@ -51,7 +69,8 @@ func (r *RunRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*d
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, status, input, output, error,
 		       triggered_by, run_number, triggered_by_user, started_at, completed_at, created_at,
-		       trigger_source, trigger_metadata
+		       trigger_source, trigger_metadata, cancelled_by, cancel_reason, summary,
+		       retry_of_run_id, retry_attempt, variables_snapshot
 		FROM runs
 		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 	`
@@ -60,7 +79,8 @@ func (r *RunRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*d
 		&run.ID, &run.TenantID, &run.ProjectID, &run.ProjectVersion, &run.StartStepID, &run.Status,
 		&run.Input, &run.Output, &run.Error, &run.TriggeredBy, &run.RunNumber, &run.TriggeredByUser,
 		&run.StartedAt, &run.CompletedAt, &run.CreatedAt,
-		&run.TriggerSource, &run.TriggerMetadata,
+		&run.TriggerSource, &run.TriggerMetadata, &run.CancelledBy, &run.CancelReason, &run.Summary,
+		&run.RetryOfRunID, &run.RetryAttempt, &run.VariablesSnapshot,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrRunNotFound
@@ -71,6 +91,44 @@ func (r *RunRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*d
 	return &run, nil
 }
 
+// GetByIDs retrieves the tenant's runs with the given IDs, without their input and output.
+// IDs that do not exist or belong to another tenant are left out.
+func (r *RunRepository) GetByIDs(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]*domain.Run, error) {
+	query := `
+		SELECT id, tenant_id, project_id, project_version, start_step_id, status, error,
+		       triggered_by, run_number, triggered_by_user, started_at, completed_at, created_at,
+		       trigger_source, cancelled_by, cancel_reason, summary,
+		       retry_of_run_id, retry_attempt
+		FROM runs
+		WHERE id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL
+	`
+	rows, err := r.db.Query(ctx, query, ids, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runs by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]*domain.Run, 0, len(ids))
+	for rows.Next() {
+		var run domain.Run
+		if err := rows.Scan(
+			&run.ID, &run.TenantID, &run.ProjectID, &run.ProjectVersion, &run.StartStepID, &run.Status,
+			&run.Error, &run.TriggeredBy, &run.RunNumber, &run.TriggeredByUser,
+			&run.StartedAt, &run.CompletedAt, &run.CreatedAt,
+			&run.TriggerSource, &run.CancelledBy, &run.CancelReason, &run.Summary,
+			&run.RetryOfRunID, &run.RetryAttempt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get runs by IDs: %w", err)
+	}
+
+	return runs, nil
+}
+
 // ListByProject retrieves runs for a project with pagination
 func (r *RunRepository) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID, filter repository.RunFilter) ([]*domain.Run, int, error) {
 	// Count query
@@ -86,21 +144,14 @@ func (r *RunRepository) ListByProject(ctx context.Context, tenantID, projectID u
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, status, input, output, error,
 		       triggered_by, run_number, triggered_by_user, started_at, completed_at, created_at,
-		       trigger_source, trigger_metadata
+		       trigger_source, trigger_metadata, cancelled_by, cancel_reason, summary,
+		       retry_of_run_id, retry_attempt
 		FROM runs
-		WHERE tenant_id = $1 AND project_id = $2 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-	`
+		WHERE tenant_id = $1 AND project_id = $2 AND deleted_at IS NULL`
 
-	if filter.Limit > 0 {
-		page := filter.Page
-		if page < 1 {
-			page = 1
-		}
-		offset := (page - 1) * filter.Limit
-		query += ` LIMIT $3 OFFSET $4`
-		args = append(args, filter.Limit, offset)
-	}
+	pageClause, pageArgs := listPageClause("created_at", filter.After, filter.Page, filter.Limit, 3)
+	query += pageClause
+	args = append(args, pageArgs...)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -115,7 +166,8 @@ func (r *RunRepository) ListByProject(ctx context.Context, tenantID, projectID u
 			&run.ID, &run.TenantID, &run.ProjectID, &run.ProjectVersion, &run.StartStepID, &run.Status,
 			&run.Input, &run.Output, &run.Error, &run.TriggeredBy, &run.RunNumber, &run.TriggeredByUser,
 			&run.StartedAt, &run.CompletedAt, &run.CreatedAt,
-			&run.TriggerSource, &run.TriggerMetadata,
+			&run.TriggerSource, &run.TriggerMetadata, &run.CancelledBy, &run.CancelReason, &run.Summary,
+			&run.RetryOfRunID, &run.RetryAttempt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan run: %w", err)
 		}
@@ -140,21 +192,14 @@ func (r *RunRepository) ListByStartStep(ctx context.Context, tenantID, projectID
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, status, input, output, error,
 		       triggered_by, run_number, triggered_by_user, started_at, completed_at, created_at,
-		       trigger_source, trigger_metadata
+		       trigger_source, trigger_metadata, cancelled_by, cancel_reason, summary,
+		       retry_of_run_id, retry_attempt
 		FROM runs
-		WHERE tenant_id = $1 AND project_id = $2 AND start_step_id = $3 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-	`
+		WHERE tenant_id = $1 AND project_id = $2 AND start_step_id = $3 AND deleted_at IS NULL`
 
-	if filter.Limit > 0 {
-		page := filter.Page
-		if page < 1 {
-			page = 1
-		}
-		offset := (page - 1) * filter.Limit
-		query += ` LIMIT $4 OFFSET $5`
-		args = append(args, filter.Limit, offset)
-	}
+	pageClause, pageArgs := listPageClause("created_at", filter.After, filter.Page, filter.Limit, 4)
+	query += pageClause
+	args = append(args, pageArgs...)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -169,7 +214,8 @@ func (r *RunRepository) ListByStartStep(ctx context.Context, tenantID, projectID
 			&run.ID, &run.TenantID, &run.ProjectID, &run.ProjectVersion, &run.StartStepID, &run.Status,
 			&run.Input, &run.Output, &run.Error, &run.TriggeredBy, &run.RunNumber, &run.TriggeredByUser,
 			&run.StartedAt, &run.CompletedAt, &run.CreatedAt,
-			&run.TriggerSource, &run.TriggerMetadata,
+			&run.TriggerSource, &run.TriggerMetadata, &run.CancelledBy, &run.CancelReason, &run.Summary,
+			&run.RetryOfRunID, &run.RetryAttempt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan run: %w", err)
 		}
@@ -183,11 +229,13 @@ func (r *RunRepository) ListByStartStep(ctx context.Context, tenantID, projectID
 func (r *RunRepository) Update(ctx context.Context, run *domain.Run) error {
 	query := `
 		UPDATE runs
-		SET status = $1, output = $2, error = $3, started_at = $4, completed_at = $5
-		WHERE id = $6 AND tenant_id = $7
+		SET status = $1, output = $2, error = $3, started_at = $4, completed_at = $5,
+		    cancelled_by = $6, cancel_reason = $7, summary = $8
+		WHERE id = $9 AND tenant_id = $10
 	`
 	result, err := r.db.Exec(ctx, query,
 		run.Status, run.Output, run.Error, run.StartedAt, run.CompletedAt,
+		run.CancelledBy, run.CancelReason, run.Summary,
 		run.ID, run.TenantID,
 	)
 	if err != nil {
@@ -210,7 +258,7 @@ func (r *RunRepository) GetWithStepRuns(ctx context.Context, tenantID, id uuid.U
 	query := `
 		SELECT sr.id, sr.run_id, sr.step_id, sr.step_name, sr.status, sr.attempt, sr.sequence_number,
 		       sr.input, sr.output, sr.error, sr.started_at, sr.completed_at,
-		       sr.duration_ms, sr.created_at
+		       sr.duration_ms, sr.created_at, sr.outputs_cleared_at, sr.evaluation, sr.item_index
 		FROM step_runs sr
 		JOIN runs r ON r.id = sr.run_id AND r.tenant_id = $2
 		WHERE sr.run_id = $1
@@ -227,7 +275,7 @@ func (r *RunRepository) GetWithStepRuns(ctx context.Context, tenantID, id uuid.U
 		if err := rows.Scan(
 			&sr.ID, &sr.RunID, &sr.StepID, &sr.StepName, &sr.Status, &sr.Attempt, &sr.SequenceNumber,
 			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt,
-			&sr.DurationMs, &sr.CreatedAt,
+			&sr.DurationMs, &sr.CreatedAt, &sr.OutputsClearedAt, &sr.Evaluation, &sr.ItemIndex,
 		); err != nil {
 			return nil, err
 		}
@@ -236,3 +284,138 @@ func (r *RunRepository) GetWithStepRuns(ctx context.Context, tenantID, id uuid.U
 
 	return run, nil
 }
+
+// ListReferencedStepIDs returns the distinct start and executed step IDs of the project's recent runs
+func (r *RunRepository) ListReferencedStepIDs(ctx context.Context, projectID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT start_step_id FROM runs
+		WHERE project_id = $1 AND created_at >= $2 AND start_step_id IS NOT NULL AND deleted_at IS NULL
+		UNION
+		SELECT sr.step_id FROM step_runs sr
+		JOIN runs r ON r.id = sr.run_id
+		WHERE r.project_id = $1 AND r.created_at >= $2 AND r.deleted_at IS NULL
+	`
+	rows, err := r.db.Query(ctx, query, projectID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referenced step IDs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan referenced step ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CountConsecutiveFailures counts the failed runs of a project since its latest completed run.
+// Cancelled runs neither count nor end the streak.
+func (r *RunRepository) CountConsecutiveFailures(ctx context.Context, tenantID, projectID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM runs
+		WHERE tenant_id = $1 AND project_id = $2 AND status = $3 AND deleted_at IS NULL
+		    AND completed_at > COALESCE((
+		        SELECT MAX(completed_at) FROM runs
+		        WHERE tenant_id = $1 AND project_id = $2 AND status = $4 AND deleted_at IS NULL
+		    ), '-infinity'::timestamptz)
+	`
+	var count int
+	if err := r.db.QueryRow(ctx, query, tenantID, projectID, domain.RunStatusFailed, domain.RunStatusCompleted).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count consecutive failures: %w", err)
+	}
+	return count, nil
+}
+
+// Search retrieves tenant runs matching the filter, ordered by created_at DESC, id DESC
+func (r *RunRepository) Search(ctx context.Context, tenantID uuid.UUID, filter repository.RunSearchFilter) ([]*domain.Run, error) {
+	where, args := buildRunSearchWhere(tenantID, filter)
+
+	query := `
+		SELECT id, tenant_id, project_id, project_version, start_step_id, status, input, output, error,
+		       triggered_by, run_number, triggered_by_user, started_at, completed_at, created_at,
+		       trigger_source, trigger_metadata, cancelled_by, cancel_reason, summary,
+		       retry_of_run_id, retry_attempt
+		FROM runs
+		` + where + `
+		ORDER BY created_at DESC, id DESC
+	`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT $%d`, len(args)+1)
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]*domain.Run, 0)
+	for rows.Next() {
+		var run domain.Run
+		if err := rows.Scan(
+			&run.ID, &run.TenantID, &run.ProjectID, &run.ProjectVersion, &run.StartStepID, &run.Status,
+			&run.Input, &run.Output, &run.Error, &run.TriggeredBy, &run.RunNumber, &run.TriggeredByUser,
+			&run.StartedAt, &run.CompletedAt, &run.CreatedAt,
+			&run.TriggerSource, &run.TriggerMetadata, &run.CancelledBy, &run.CancelReason, &run.Summary,
+			&run.RetryOfRunID, &run.RetryAttempt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// buildRunSearchWhere builds the WHERE clause and arguments for Search.
+// JSON filters use the JSONB containment operator so they can be served by the GIN indexes.
+func buildRunSearchWhere(tenantID uuid.UUID, filter repository.RunSearchFilter) (string, []interface{}) {
+	where := `WHERE tenant_id = $1 AND deleted_at IS NULL`
+	args := []interface{}{tenantID}
+	argIndex := 2
+
+	addCond := func(format string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i := range values {
+			placeholders[i] = argIndex
+			argIndex++
+		}
+		where += ` AND ` + fmt.Sprintf(format, placeholders...)
+		args = append(args, values...)
+	}
+
+	if filter.ProjectID != nil {
+		addCond(`project_id = $%d`, *filter.ProjectID)
+	}
+	if filter.Status != nil {
+		addCond(`status = $%d`, *filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		addCond(`created_at >= $%d`, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCond(`created_at < $%d`, *filter.CreatedTo)
+	}
+	if len(filter.InputContains) > 0 {
+		addCond(`input @> $%d::jsonb`, string(filter.InputContains))
+	}
+	if len(filter.OutputContains) > 0 {
+		addCond(`output @> $%d::jsonb`, string(filter.OutputContains))
+	}
+	if len(filter.MetadataContains) > 0 {
+		addCond(`trigger_metadata @> $%d::jsonb`, string(filter.MetadataContains))
+	}
+	if filter.After != nil {
+		addCond(`(created_at, id) < ($%d, $%d)`, filter.After.CreatedAt, filter.After.ID)
+	}
+
+	return where, args
+}
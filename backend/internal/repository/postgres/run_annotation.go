@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// RunAnnotationRepository implements repository.RunAnnotationRepository
+type RunAnnotationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRunAnnotationRepository creates a new RunAnnotationRepository
+func NewRunAnnotationRepository(pool *pgxpool.Pool) *RunAnnotationRepository {
+	return &RunAnnotationRepository{pool: pool}
+}
+
+// Create creates a new run annotation
+func (r *RunAnnotationRepository) Create(ctx context.Context, a *domain.RunAnnotation) error {
+	query := `
+		INSERT INTO run_annotations (id, tenant_id, run_id, step_id, author_id, comment, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		a.ID, a.TenantID, a.RunID, a.StepID, a.AuthorID, a.Comment, a.CreatedAt, a.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a run annotation by ID
+func (r *RunAnnotationRepository) GetByID(ctx context.Context, tenantID, runID, id uuid.UUID) (*domain.RunAnnotation, error) {
+	query := `
+		SELECT id, tenant_id, run_id, step_id, author_id, comment, created_at, updated_at
+		FROM run_annotations
+		WHERE id = $1 AND run_id = $2 AND tenant_id = $3
+	`
+	var a domain.RunAnnotation
+	err := r.pool.QueryRow(ctx, query, id, runID, tenantID).Scan(
+		&a.ID, &a.TenantID, &a.RunID, &a.StepID, &a.AuthorID, &a.Comment, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRunAnnotationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListByRun retrieves all annotations for a run, oldest first
+func (r *RunAnnotationRepository) ListByRun(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.RunAnnotation, error) {
+	query := `
+		SELECT id, tenant_id, run_id, step_id, author_id, comment, created_at, updated_at
+		FROM run_annotations
+		WHERE run_id = $1 AND tenant_id = $2
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := r.pool.Query(ctx, query, runID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	annotations := make([]*domain.RunAnnotation, 0)
+	for rows.Next() {
+		var a domain.RunAnnotation
+		if err := rows.Scan(
+			&a.ID, &a.TenantID, &a.RunID, &a.StepID, &a.AuthorID, &a.Comment, &a.CreatedAt, &a.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, &a)
+	}
+	return annotations, rows.Err()
+}
+
+// Delete deletes a run annotation
+func (r *RunAnnotationRepository) Delete(ctx context.Context, tenantID, runID, id uuid.UUID) error {
+	query := `DELETE FROM run_annotations WHERE id = $1 AND run_id = $2 AND tenant_id = $3`
+	result, err := r.pool.Exec(ctx, query, id, runID, tenantID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrRunAnnotationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// RunCheckpointRepository implements repository.RunCheckpointRepository
+type RunCheckpointRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRunCheckpointRepository creates a new RunCheckpointRepository
+func NewRunCheckpointRepository(pool *pgxpool.Pool) *RunCheckpointRepository {
+	return &RunCheckpointRepository{pool: pool}
+}
+
+// Save stores a checkpoint, replacing an earlier checkpoint of the same step in the run
+func (r *RunCheckpointRepository) Save(ctx context.Context, c *domain.RunCheckpoint) error {
+	outputs, err := json.Marshal(c.Outputs)
+	if err != nil {
+		return err
+	}
+	outputPorts := c.OutputPorts
+	if outputPorts == nil {
+		outputPorts = map[string]string{}
+	}
+	ports, err := json.Marshal(outputPorts)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO run_checkpoints (id, tenant_id, run_id, step_id, output_port, outputs, output_ports, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (run_id, step_id) DO UPDATE
+		SET output_port = EXCLUDED.output_port, outputs = EXCLUDED.outputs,
+		    output_ports = EXCLUDED.output_ports, created_at = EXCLUDED.created_at
+	`
+	_, err = r.pool.Exec(ctx, query,
+		c.ID, c.TenantID, c.RunID, c.StepID, c.OutputPort, outputs, ports, c.CreatedAt,
+	)
+	return err
+}
+
+// GetLatest retrieves the most recently saved checkpoint of a run
+func (r *RunCheckpointRepository) GetLatest(ctx context.Context, tenantID, runID uuid.UUID) (*domain.RunCheckpoint, error) {
+	query := `
+		SELECT id, tenant_id, run_id, step_id, output_port, outputs, output_ports, created_at
+		FROM run_checkpoints
+		WHERE run_id = $1 AND tenant_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	var c domain.RunCheckpoint
+	var outputs, ports []byte
+	err := r.pool.QueryRow(ctx, query, runID, tenantID).Scan(
+		&c.ID, &c.TenantID, &c.RunID, &c.StepID, &c.OutputPort, &outputs, &ports, &c.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRunCheckpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(outputs, &c.Outputs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ports, &c.OutputPorts); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+func TestBuildRunSearchWhere(t *testing.T) {
+	tenantID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	projectID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	cursorID := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	failed := domain.RunStatusFailed
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    repository.RunSearchFilter
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "no filters is still tenant scoped",
+			filter:    repository.RunSearchFilter{},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL",
+			wantArgs:  []interface{}{tenantID},
+		},
+		{
+			name: "nested input containment",
+			filter: repository.RunSearchFilter{
+				InputContains: json.RawMessage(`{"customer":{"address":{"country":"JP"}}}`),
+			},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND input @> $2::jsonb",
+			wantArgs:  []interface{}{tenantID, `{"customer":{"address":{"country":"JP"}}}`},
+		},
+		{
+			name: "date range",
+			filter: repository.RunSearchFilter{
+				CreatedFrom: &from,
+				CreatedTo:   &to,
+			},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND created_at >= $2 AND created_at < $3",
+			wantArgs:  []interface{}{tenantID, from, to},
+		},
+		{
+			name: "all filters with cursor",
+			filter: repository.RunSearchFilter{
+				ProjectID:        &projectID,
+				Status:           &failed,
+				CreatedFrom:      &from,
+				OutputContains:   json.RawMessage(`{"ok":false}`),
+				MetadataContains: json.RawMessage(`{"feature":"copilot"}`),
+				After:            &repository.RunSearchCursor{CreatedAt: to, ID: cursorID},
+			},
+			wantWhere: "WHERE tenant_id = $1 AND deleted_at IS NULL AND project_id = $2 AND status = $3" +
+				" AND created_at >= $4 AND output @> $5::jsonb AND trigger_metadata @> $6::jsonb" +
+				" AND (created_at, id) < ($7, $8)",
+			wantArgs: []interface{}{tenantID, projectID, failed, from, `{"ok":false}`, `{"feature":"copilot"}`, to, cursorID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := buildRunSearchWhere(tenantID, tt.filter)
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
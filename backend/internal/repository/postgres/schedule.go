@@ -56,7 +56,7 @@ func (r *ScheduleRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, name, description,
 			   cron_expression, timezone, input, status, next_run_at, last_run_at,
-			   last_run_id, run_count, created_by, created_at, updated_at
+			   last_run_id, run_count, bulk_paused, created_by, created_at, updated_at
 		FROM schedules
 		WHERE tenant_id = $1 AND id = $2
 	`
@@ -78,6 +78,7 @@ func (r *ScheduleRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID
 		&schedule.LastRunAt,
 		&schedule.LastRunID,
 		&schedule.RunCount,
+		&schedule.BulkPaused,
 		&schedule.CreatedBy,
 		&schedule.CreatedAt,
 		&schedule.UpdatedAt,
@@ -118,7 +119,7 @@ func (r *ScheduleRepository) ListByTenant(ctx context.Context, tenantID uuid.UUI
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, name, description,
 			   cron_expression, timezone, input, status, next_run_at, last_run_at,
-			   last_run_id, run_count, created_by, created_at, updated_at
+			   last_run_id, run_count, bulk_paused, created_by, created_at, updated_at
 		FROM schedules
 		WHERE tenant_id = $1
 	`
@@ -136,13 +137,9 @@ func (r *ScheduleRepository) ListByTenant(ctx context.Context, tenantID uuid.UUI
 		argIdx++
 	}
 
-	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, argIdx, argIdx+1)
-	page := filter.Page
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * filter.Limit
-	args = append(args, filter.Limit, offset)
+	pageClause, pageArgs := listPageClause("created_at", filter.After, filter.Page, filter.Limit, argIdx)
+	query += pageClause
+	args = append(args, pageArgs...)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -157,7 +154,7 @@ func (r *ScheduleRepository) ListByTenant(ctx context.Context, tenantID uuid.UUI
 			&s.ID, &s.TenantID, &s.ProjectID, &s.ProjectVersion,
 			&s.StartStepID, &s.Name, &s.Description, &s.CronExpression, &s.Timezone,
 			&s.Input, &s.Status, &s.NextRunAt, &s.LastRunAt,
-			&s.LastRunID, &s.RunCount, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
+			&s.LastRunID, &s.RunCount, &s.BulkPaused, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("scan schedule: %w", err)
@@ -176,7 +173,7 @@ func (r *ScheduleRepository) ListByProject(ctx context.Context, tenantID, projec
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, name, description,
 			   cron_expression, timezone, input, status, next_run_at, last_run_at,
-			   last_run_id, run_count, created_by, created_at, updated_at
+			   last_run_id, run_count, bulk_paused, created_by, created_at, updated_at
 		FROM schedules
 		WHERE tenant_id = $1 AND project_id = $2
 		ORDER BY created_at DESC
@@ -195,7 +192,7 @@ func (r *ScheduleRepository) ListByProject(ctx context.Context, tenantID, projec
 			&s.ID, &s.TenantID, &s.ProjectID, &s.ProjectVersion,
 			&s.StartStepID, &s.Name, &s.Description, &s.CronExpression, &s.Timezone,
 			&s.Input, &s.Status, &s.NextRunAt, &s.LastRunAt,
-			&s.LastRunID, &s.RunCount, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
+			&s.LastRunID, &s.RunCount, &s.BulkPaused, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -210,7 +207,7 @@ func (r *ScheduleRepository) ListByStartStep(ctx context.Context, tenantID, proj
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, name, description,
 			   cron_expression, timezone, input, status, next_run_at, last_run_at,
-			   last_run_id, run_count, created_by, created_at, updated_at
+			   last_run_id, run_count, bulk_paused, created_by, created_at, updated_at
 		FROM schedules
 		WHERE tenant_id = $1 AND project_id = $2 AND start_step_id = $3
 		ORDER BY created_at DESC
@@ -229,7 +226,7 @@ func (r *ScheduleRepository) ListByStartStep(ctx context.Context, tenantID, proj
 			&s.ID, &s.TenantID, &s.ProjectID, &s.ProjectVersion,
 			&s.StartStepID, &s.Name, &s.Description, &s.CronExpression, &s.Timezone,
 			&s.Input, &s.Status, &s.NextRunAt, &s.LastRunAt,
-			&s.LastRunID, &s.RunCount, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
+			&s.LastRunID, &s.RunCount, &s.BulkPaused, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -253,7 +250,8 @@ func (r *ScheduleRepository) Update(ctx context.Context, schedule *domain.Schedu
 			last_run_at = $10,
 			last_run_id = $11,
 			run_count = $12,
-			updated_at = $13
+			bulk_paused = $13,
+			updated_at = $14
 		WHERE tenant_id = $1 AND id = $2
 	`
 
@@ -270,6 +268,7 @@ func (r *ScheduleRepository) Update(ctx context.Context, schedule *domain.Schedu
 		schedule.LastRunAt,
 		schedule.LastRunID,
 		schedule.RunCount,
+		schedule.BulkPaused,
 		schedule.UpdatedAt,
 	)
 
@@ -301,7 +300,7 @@ func (r *ScheduleRepository) GetDueSchedules(ctx context.Context, limit int) ([]
 	query := `
 		SELECT id, tenant_id, project_id, project_version, start_step_id, name, description,
 			   cron_expression, timezone, input, status, next_run_at, last_run_at,
-			   last_run_id, run_count, created_by, created_at, updated_at
+			   last_run_id, run_count, bulk_paused, created_by, created_at, updated_at
 		FROM schedules
 		WHERE status = $1 AND next_run_at <= $2
 		ORDER BY next_run_at ASC
@@ -321,7 +320,7 @@ func (r *ScheduleRepository) GetDueSchedules(ctx context.Context, limit int) ([]
 			&s.ID, &s.TenantID, &s.ProjectID, &s.ProjectVersion,
 			&s.StartStepID, &s.Name, &s.Description, &s.CronExpression, &s.Timezone,
 			&s.Input, &s.Status, &s.NextRunAt, &s.LastRunAt,
-			&s.LastRunID, &s.RunCount, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
+			&s.LastRunID, &s.RunCount, &s.BulkPaused, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -331,3 +330,109 @@ func (r *ScheduleRepository) GetDueSchedules(ctx context.Context, limit int) ([]
 
 	return schedules, nil
 }
+
+// PauseAll pauses the active schedules of a tenant in a single statement
+func (r *ScheduleRepository) PauseAll(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		UPDATE schedules SET
+			status = $2,
+			bulk_paused = true,
+			updated_at = $3
+		WHERE tenant_id = $1 AND status = $4
+		RETURNING id
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, domain.ScheduleStatusPaused, time.Now().UTC(), domain.ScheduleStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("pause schedules: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan paused schedule: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate paused schedules: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *ScheduleRepository) ListBulkPaused(ctx context.Context, tenantID uuid.UUID) ([]*domain.Schedule, error) {
+	query := `
+		SELECT id, tenant_id, project_id, project_version, start_step_id, name, description,
+			   cron_expression, timezone, input, status, next_run_at, last_run_at,
+			   last_run_id, run_count, bulk_paused, created_by, created_at, updated_at
+		FROM schedules
+		WHERE tenant_id = $1 AND status = $2 AND bulk_paused
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, domain.ScheduleStatusPaused)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*domain.Schedule
+	for rows.Next() {
+		var s domain.Schedule
+		err := rows.Scan(
+			&s.ID, &s.TenantID, &s.ProjectID, &s.ProjectVersion,
+			&s.StartStepID, &s.Name, &s.Description, &s.CronExpression, &s.Timezone,
+			&s.Input, &s.Status, &s.NextRunAt, &s.LastRunAt,
+			&s.LastRunID, &s.RunCount, &s.BulkPaused, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &s)
+	}
+
+	return schedules, rows.Err()
+}
+
+// ResumeBulkPaused reactivates bulk-paused schedules in a single transaction
+func (r *ScheduleRepository) ResumeBulkPaused(ctx context.Context, tenantID uuid.UUID, nextRuns map[uuid.UUID]*time.Time) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	if len(nextRuns) == 0 {
+		return ids, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only schedules still paused by pause-all are resumed; one paused or resumed
+	// individually since then keeps its state
+	query := `
+		UPDATE schedules SET
+			status = $3,
+			bulk_paused = false,
+			next_run_at = $4,
+			updated_at = $5
+		WHERE tenant_id = $1 AND id = $2 AND status = $6 AND bulk_paused
+	`
+	now := time.Now().UTC()
+	for id, nextRunAt := range nextRuns {
+		result, err := tx.Exec(ctx, query, tenantID, id, domain.ScheduleStatusActive, nextRunAt, now, domain.ScheduleStatusPaused)
+		if err != nil {
+			return nil, fmt.Errorf("resume schedule %s: %w", id, err)
+		}
+		if result.RowsAffected() > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
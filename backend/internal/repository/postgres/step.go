@@ -284,26 +284,50 @@ func (r *StepRepository) GetStartStepByTriggerType(ctx context.Context, tenantID
 
 // Update updates a step
 func (r *StepRepository) Update(ctx context.Context, s *domain.Step) error {
-	s.UpdatedAt = time.Now().UTC()
+	return r.update(ctx, s, nil)
+}
+
+// UpdateIfUnmodified updates a step only if no other write changed it since it was read
+func (r *StepRepository) UpdateIfUnmodified(ctx context.Context, s *domain.Step, unmodifiedSince time.Time) error {
+	return r.update(ctx, s, &unmodifiedSince)
+}
+
+// update writes a step; with unmodifiedSince set the write is conditional on updated_at
+func (r *StepRepository) update(ctx context.Context, s *domain.Step, unmodifiedSince *time.Time) error {
+	updatedAt := time.Now().UTC()
 	query := `
 		UPDATE steps
 		SET name = $1, type = $2, config = $3, block_group_id = $4, group_role = $5, position_x = $6, position_y = $7,
 			block_definition_id = $8, credential_bindings = $9, trigger_type = $10, trigger_config = $11,
 			tool_name = $12, tool_description = $13, tool_input_schema = $14, updated_at = $15
 		WHERE id = $16 AND project_id = $17 AND tenant_id = $18
+			AND ($19::timestamptz IS NULL OR updated_at = $19)
 	`
 	result, err := r.pool.Exec(ctx, query,
 		s.Name, s.Type, s.Config, s.BlockGroupID, s.GroupRole, s.PositionX, s.PositionY,
 		s.BlockDefinitionID, s.CredentialBindings, s.TriggerType, s.TriggerConfig,
-		s.ToolName, s.ToolDescription, s.ToolInputSchema, s.UpdatedAt,
-		s.ID, s.ProjectID, s.TenantID,
+		s.ToolName, s.ToolDescription, s.ToolInputSchema, updatedAt,
+		s.ID, s.ProjectID, s.TenantID, unmodifiedSince,
 	)
 	if err != nil {
 		return err
 	}
 	if result.RowsAffected() == 0 {
+		if unmodifiedSince != nil {
+			var exists bool
+			if err := r.pool.QueryRow(ctx,
+				`SELECT EXISTS(SELECT 1 FROM steps WHERE id = $1 AND project_id = $2 AND tenant_id = $3)`,
+				s.ID, s.ProjectID, s.TenantID,
+			).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return domain.ErrConcurrentModification
+			}
+		}
 		return domain.ErrStepNotFound
 	}
+	s.UpdatedAt = updatedAt
 	return nil
 }
 
@@ -319,3 +343,55 @@ func (r *StepRepository) Delete(ctx context.Context, tenantID, projectID, id uui
 	}
 	return nil
 }
+
+// DeleteBatch deletes steps and their connected edges in a single transaction.
+// Returns the IDs of the removed edges, or ErrStepNotFound (with nothing deleted)
+// if any step does not belong to the project.
+func (r *StepRepository) DeleteBatch(ctx context.Context, tenantID, projectID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Delete edges explicitly (rather than relying on ON DELETE CASCADE) to report their IDs
+	rows, err := tx.Query(ctx, `
+		DELETE FROM edges
+		WHERE project_id = $1 AND tenant_id = $2
+			AND (source_step_id = ANY($3) OR target_step_id = ANY($3))
+		RETURNING id
+	`, projectID, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	edgeIDs := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		edgeIDs = append(edgeIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM steps WHERE id = ANY($1) AND project_id = $2 AND tenant_id = $3`, ids, projectID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() != int64(len(ids)) {
+		return nil, domain.ErrStepNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return edgeIDs, nil
+}
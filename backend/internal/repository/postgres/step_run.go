@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,12 +24,12 @@ func NewStepRunRepository(pool *pgxpool.Pool) *StepRunRepository {
 // Create creates a new step run
 func (r *StepRunRepository) Create(ctx context.Context, sr *domain.StepRun) error {
 	query := `
-		INSERT INTO step_runs (id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		INSERT INTO step_runs (id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at, evaluation, item_index)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 	_, err := r.pool.Exec(ctx, query,
 		sr.ID, sr.TenantID, sr.RunID, sr.StepID, sr.StepName, sr.Status, sr.Attempt, sr.SequenceNumber,
-		sr.Input, sr.Output, sr.Error, sr.StartedAt, sr.CompletedAt, sr.DurationMs, sr.CreatedAt,
+		sr.Input, sr.Output, sr.Error, sr.StartedAt, sr.CompletedAt, sr.DurationMs, sr.CreatedAt, sr.Evaluation, sr.ItemIndex,
 	)
 	return err
 }
@@ -36,14 +37,14 @@ func (r *StepRunRepository) Create(ctx context.Context, sr *domain.StepRun) erro
 // GetByID retrieves a step run by ID
 func (r *StepRunRepository) GetByID(ctx context.Context, tenantID, runID, id uuid.UUID) (*domain.StepRun, error) {
 	query := `
-		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at
+		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at, outputs_cleared_at, evaluation, item_index
 		FROM step_runs
 		WHERE id = $1 AND run_id = $2 AND tenant_id = $3
 	`
 	var sr domain.StepRun
 	err := r.pool.QueryRow(ctx, query, id, runID, tenantID).Scan(
 		&sr.ID, &sr.TenantID, &sr.RunID, &sr.StepID, &sr.StepName, &sr.Status, &sr.Attempt, &sr.SequenceNumber,
-		&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt,
+		&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt, &sr.OutputsClearedAt, &sr.Evaluation, &sr.ItemIndex,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrStepRunNotFound
@@ -57,7 +58,7 @@ func (r *StepRunRepository) GetByID(ctx context.Context, tenantID, runID, id uui
 // ListByRun retrieves all step runs for a given run
 func (r *StepRunRepository) ListByRun(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.StepRun, error) {
 	query := `
-		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at
+		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at, outputs_cleared_at, evaluation, item_index
 		FROM step_runs
 		WHERE run_id = $1 AND tenant_id = $2
 		ORDER BY sequence_number ASC, created_at ASC
@@ -73,7 +74,7 @@ func (r *StepRunRepository) ListByRun(ctx context.Context, tenantID, runID uuid.
 		var sr domain.StepRun
 		if err := rows.Scan(
 			&sr.ID, &sr.TenantID, &sr.RunID, &sr.StepID, &sr.StepName, &sr.Status, &sr.Attempt, &sr.SequenceNumber,
-			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt,
+			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt, &sr.OutputsClearedAt, &sr.Evaluation, &sr.ItemIndex,
 		); err != nil {
 			return nil, err
 		}
@@ -87,11 +88,11 @@ func (r *StepRunRepository) ListByRun(ctx context.Context, tenantID, runID uuid.
 func (r *StepRunRepository) Update(ctx context.Context, sr *domain.StepRun) error {
 	query := `
 		UPDATE step_runs
-		SET status = $1, attempt = $2, input = $3, output = $4, error = $5, started_at = $6, completed_at = $7, duration_ms = $8
-		WHERE id = $9 AND tenant_id = $10
+		SET status = $1, attempt = $2, input = $3, output = $4, error = $5, started_at = $6, completed_at = $7, duration_ms = $8, evaluation = $9
+		WHERE id = $10 AND tenant_id = $11
 	`
 	result, err := r.pool.Exec(ctx, query,
-		sr.Status, sr.Attempt, sr.Input, sr.Output, sr.Error, sr.StartedAt, sr.CompletedAt, sr.DurationMs,
+		sr.Status, sr.Attempt, sr.Input, sr.Output, sr.Error, sr.StartedAt, sr.CompletedAt, sr.DurationMs, sr.Evaluation,
 		sr.ID, sr.TenantID,
 	)
 	if err != nil {
@@ -139,7 +140,7 @@ func (r *StepRunRepository) GetMaxSequenceNumberForRun(ctx context.Context, tena
 // GetLatestByStep returns the most recent StepRun for a step in a run
 func (r *StepRunRepository) GetLatestByStep(ctx context.Context, tenantID, runID, stepID uuid.UUID) (*domain.StepRun, error) {
 	query := `
-		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at
+		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at, outputs_cleared_at, evaluation, item_index
 		FROM step_runs
 		WHERE run_id = $1 AND step_id = $2 AND tenant_id = $3
 		ORDER BY attempt DESC
@@ -148,7 +149,7 @@ func (r *StepRunRepository) GetLatestByStep(ctx context.Context, tenantID, runID
 	var sr domain.StepRun
 	err := r.pool.QueryRow(ctx, query, runID, stepID, tenantID).Scan(
 		&sr.ID, &sr.TenantID, &sr.RunID, &sr.StepID, &sr.StepName, &sr.Status, &sr.Attempt, &sr.SequenceNumber,
-		&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt,
+		&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt, &sr.OutputsClearedAt, &sr.Evaluation, &sr.ItemIndex,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrStepRunNotFound
@@ -159,13 +160,14 @@ func (r *StepRunRepository) GetLatestByStep(ctx context.Context, tenantID, runID
 	return &sr, nil
 }
 
-// ListCompletedByRun returns the latest completed StepRun for each step in a run
+// ListCompletedByRun returns the latest completed StepRun for each step in a run. The per-item
+// step runs of a streaming map are left out, as none of them is the step's output.
 func (r *StepRunRepository) ListCompletedByRun(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.StepRun, error) {
 	query := `
 		SELECT DISTINCT ON (step_id)
-			id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at
+			id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at, outputs_cleared_at, evaluation, item_index
 		FROM step_runs
-		WHERE run_id = $1 AND tenant_id = $2 AND status = 'completed'
+		WHERE run_id = $1 AND tenant_id = $2 AND status = 'completed' AND item_index IS NULL
 		ORDER BY step_id, attempt DESC
 	`
 	rows, err := r.pool.Query(ctx, query, runID, tenantID)
@@ -179,7 +181,7 @@ func (r *StepRunRepository) ListCompletedByRun(ctx context.Context, tenantID, ru
 		var sr domain.StepRun
 		if err := rows.Scan(
 			&sr.ID, &sr.TenantID, &sr.RunID, &sr.StepID, &sr.StepName, &sr.Status, &sr.Attempt, &sr.SequenceNumber,
-			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt,
+			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt, &sr.OutputsClearedAt, &sr.Evaluation, &sr.ItemIndex,
 		); err != nil {
 			return nil, err
 		}
@@ -192,7 +194,7 @@ func (r *StepRunRepository) ListCompletedByRun(ctx context.Context, tenantID, ru
 // ListByStep returns all StepRuns for a specific step in a run (for history)
 func (r *StepRunRepository) ListByStep(ctx context.Context, tenantID, runID, stepID uuid.UUID) ([]*domain.StepRun, error) {
 	query := `
-		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at
+		SELECT id, tenant_id, run_id, step_id, step_name, status, attempt, sequence_number, input, output, error, started_at, completed_at, duration_ms, created_at, outputs_cleared_at, evaluation, item_index
 		FROM step_runs
 		WHERE run_id = $1 AND step_id = $2 AND tenant_id = $3
 		ORDER BY attempt ASC
@@ -208,7 +210,7 @@ func (r *StepRunRepository) ListByStep(ctx context.Context, tenantID, runID, ste
 		var sr domain.StepRun
 		if err := rows.Scan(
 			&sr.ID, &sr.TenantID, &sr.RunID, &sr.StepID, &sr.StepName, &sr.Status, &sr.Attempt, &sr.SequenceNumber,
-			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt,
+			&sr.Input, &sr.Output, &sr.Error, &sr.StartedAt, &sr.CompletedAt, &sr.DurationMs, &sr.CreatedAt, &sr.OutputsClearedAt, &sr.Evaluation, &sr.ItemIndex,
 		); err != nil {
 			return nil, err
 		}
@@ -217,3 +219,19 @@ func (r *StepRunRepository) ListByStep(ctx context.Context, tenantID, runID, ste
 
 	return stepRuns, nil
 }
+
+// ClearOutputsBefore clears the input and output payloads of a tenant's step runs created before
+// the given time, keeping their status, error, timing and attempt. Already cleared step runs are
+// skipped. Returns the number of step runs cleared.
+func (r *StepRunRepository) ClearOutputsBefore(ctx context.Context, tenantID uuid.UUID, before time.Time) (int64, error) {
+	query := `
+		UPDATE step_runs
+		SET input = NULL, output = NULL, streaming_output = '[]'::jsonb, outputs_cleared_at = NOW()
+		WHERE tenant_id = $1 AND created_at < $2 AND outputs_cleared_at IS NULL
+	`
+	result, err := r.pool.Exec(ctx, query, tenantID, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -113,6 +115,21 @@ func (r *ProjectTemplateRepository) ListByTenant(ctx context.Context, tenantID u
 	return r.listWithConditions(ctx, filter, fmt.Sprintf("tenant_id = '%s'", tenantID))
 }
 
+// templateOrderBy returns the ORDER BY clause for a template sort.
+// id is the final tie-breaker so pagination is stable.
+func templateOrderBy(sort domain.TemplateSort) string {
+	switch sort {
+	case domain.TemplateSortRating:
+		return "rating DESC NULLS LAST, review_count DESC, download_count DESC, id"
+	case domain.TemplateSortPopularity:
+		return "download_count DESC, created_at DESC, id"
+	case domain.TemplateSortRecent:
+		return "created_at DESC, id"
+	default:
+		return "is_featured DESC, download_count DESC, created_at DESC, id"
+	}
+}
+
 func (r *ProjectTemplateRepository) listWithConditions(ctx context.Context, filter repository.TemplateFilter, baseCondition string) ([]*domain.ProjectTemplate, int, error) {
 	// Build WHERE clause
 	conditions := []string{}
@@ -159,6 +176,12 @@ func (r *ProjectTemplateRepository) listWithConditions(ctx context.Context, filt
 		argIndex++
 	}
 
+	if filter.IDs != nil {
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d)", argIndex))
+		args = append(args, filter.IDs)
+		argIndex++
+	}
+
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
@@ -178,7 +201,7 @@ func (r *ProjectTemplateRepository) listWithConditions(ctx context.Context, filt
 		       visibility, review_status, price_usd, rating, review_count,
 		       created_at, updated_at
 		FROM project_templates
-	` + whereClause + " ORDER BY is_featured DESC, download_count DESC, created_at DESC"
+	` + whereClause + " ORDER BY " + templateOrderBy(filter.Sort)
 
 	if filter.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT $%d", argIndex)
@@ -303,10 +326,34 @@ func (r *TemplateReviewRepository) Create(ctx context.Context, review *domain.Te
 		review.Comment,
 		review.CreatedAt,
 	)
+	if isUniqueViolation(err) {
+		return domain.ErrTemplateReviewExists
+	}
 
 	return err
 }
 
+// Upsert creates a review or edits the user's existing one for the template.
+// On conflict the existing review's ID and created_at are kept and written back to review.
+func (r *TemplateReviewRepository) Upsert(ctx context.Context, review *domain.TemplateReview) error {
+	query := `
+		INSERT INTO template_reviews (id, template_id, user_id, rating, comment, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (template_id, user_id) DO UPDATE
+		SET rating = EXCLUDED.rating, comment = EXCLUDED.comment
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		review.ID,
+		review.TemplateID,
+		review.UserID,
+		review.Rating,
+		review.Comment,
+		review.CreatedAt,
+	).Scan(&review.ID, &review.CreatedAt)
+}
+
 // GetByID retrieves a template review by ID
 func (r *TemplateReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.TemplateReview, error) {
 	query := `
@@ -383,6 +430,9 @@ func (r *TemplateReviewRepository) GetByTemplateAndUser(ctx context.Context, tem
 		&review.Comment,
 		&review.CreatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrTemplateReviewNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -396,3 +446,43 @@ func (r *TemplateReviewRepository) Delete(ctx context.Context, id uuid.UUID) err
 	_, err := r.db.Exec(ctx, query, id)
 	return err
 }
+
+// TemplateUsageRepository handles template usage persistence
+type TemplateUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTemplateUsageRepository creates a new TemplateUsageRepository
+func NewTemplateUsageRepository(db *pgxpool.Pool) *TemplateUsageRepository {
+	return &TemplateUsageRepository{db: db}
+}
+
+// Create records a template usage
+func (r *TemplateUsageRepository) Create(ctx context.Context, usage *domain.TemplateUsage) error {
+	query := `
+		INSERT INTO template_usages (id, template_id, tenant_id, user_id, project_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		usage.ID,
+		usage.TemplateID,
+		usage.TenantID,
+		usage.UserID,
+		usage.ProjectID,
+		usage.CreatedAt,
+	)
+
+	return err
+}
+
+// HasUsed reports whether the user has ever created a project from the template
+func (r *TemplateUsageRepository) HasUsed(ctx context.Context, templateID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM template_usages WHERE template_id = $1 AND user_id = $2)`
+
+	var used bool
+	if err := r.db.QueryRow(ctx, query, templateID, userID).Scan(&used); err != nil {
+		return false, err
+	}
+	return used, nil
+}
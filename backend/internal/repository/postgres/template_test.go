@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func TestTemplateOrderBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		sort       domain.TemplateSort
+		wantPrefix string
+	}{
+		{name: "rating", sort: domain.TemplateSortRating, wantPrefix: "rating DESC NULLS LAST, review_count DESC"},
+		{name: "popularity", sort: domain.TemplateSortPopularity, wantPrefix: "download_count DESC"},
+		{name: "recent", sort: domain.TemplateSortRecent, wantPrefix: "created_at DESC"},
+		{name: "default", sort: "", wantPrefix: "is_featured DESC, download_count DESC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := templateOrderBy(tt.sort)
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("templateOrderBy(%q) = %q, want prefix %q", tt.sort, got, tt.wantPrefix)
+			}
+			if !strings.HasSuffix(got, ", id") {
+				t.Errorf("templateOrderBy(%q) = %q, want id tiebreaker", tt.sort, got)
+			}
+		})
+	}
+}
@@ -17,7 +17,7 @@ func (r *Registry) registerAIBlocks() {
 func LLMBlock() *SystemBlockDefinition {
 	return &SystemBlockDefinition{
 		Slug:        "llm",
-		Version:     1,
+		Version:     2, // Incremented for seed config
 		Name:        LText("LLM", "LLM"),
 		Description: LText("Execute LLM prompts with various providers", "様々なプロバイダーでLLMプロンプトを実行"),
 		Category:    domain.BlockCategoryAI,
@@ -36,8 +36,26 @@ func LLMBlock() *SystemBlockDefinition {
 				},
 				"max_tokens": {"type": "integer", "default": 4096, "maximum": 128000},
 				"temperature": {"type": "number", "default": 0.7, "maximum": 2},
+				"seed": {"type": "integer", "title": "Seed", "description": "Request reproducible sampling from providers that support it (OpenAI)"},
 				"user_prompt": {"type": "string", "maxLength": 50000},
 				"system_prompt": {"type": "string", "maxLength": 10000},
+				"images": {
+					"type": "array",
+					"title": "Images",
+					"description": "Images sent with the user prompt: URLs, data: URIs or {data, media_type} objects with base64 data. Requires a vision-capable model."
+				},
+				"passthrough_fields": {
+					"type": "array",
+					"items": {"type": "string"},
+					"title": "Passthrough Fields",
+					"description": "Input fields copied into the output as they are"
+				},
+				"preserve_input": {
+					"type": "boolean",
+					"title": "Preserve Input",
+					"description": "Merge the whole input into the output (LLM output fields take precedence)",
+					"default": false
+				},
 				"enable_error_port": {
 					"type": "boolean",
 					"title": "Enable Error Port",
@@ -58,8 +76,26 @@ func LLMBlock() *SystemBlockDefinition {
 				},
 				"max_tokens": {"type": "integer", "default": 4096, "maximum": 128000},
 				"temperature": {"type": "number", "default": 0.7, "maximum": 2},
+				"seed": {"type": "integer", "title": "シード", "description": "対応プロバイダー（OpenAI）に再現可能なサンプリングを要求します"},
 				"user_prompt": {"type": "string", "maxLength": 50000},
 				"system_prompt": {"type": "string", "maxLength": 10000},
+				"images": {
+					"type": "array",
+					"title": "画像",
+					"description": "ユーザープロンプトと一緒に送る画像（URL、data: URI、または base64 データの {data, media_type} オブジェクト）。画像入力に対応したモデルが必要です。"
+				},
+				"passthrough_fields": {
+					"type": "array",
+					"items": {"type": "string"},
+					"title": "引き継ぐフィールド",
+					"description": "入力のうち出力にそのまま含めるフィールド"
+				},
+				"preserve_input": {
+					"type": "boolean",
+					"title": "入力を保持",
+					"description": "入力全体を出力にマージします（LLM の出力のフィールドが優先）",
+					"default": false
+				},
 				"enable_error_port": {
 					"type": "boolean",
 					"title": "エラーハンドルを有効化",
@@ -75,17 +111,23 @@ func LLMBlock() *SystemBlockDefinition {
 		Code: `
 const prompt = renderTemplate(config.user_prompt || '', input);
 const systemPrompt = config.system_prompt || '';
+const images = [].concat(config.images || [])
+    .map(image => typeof image === 'string' ? renderTemplate(image, input) : image)
+    .filter(image => image);
 const response = ctx.llm.chat(config.provider, config.model, {
     messages: [
         ...(systemPrompt ? [{ role: 'system', content: systemPrompt }] : []),
         { role: 'user', content: prompt }
     ],
     temperature: config.temperature ?? 0.7,
-    maxTokens: config.max_tokens ?? 1000
+    maxTokens: config.max_tokens ?? 1000,
+    ...(config.seed != null ? { seed: config.seed } : {}),
+    ...(images.length > 0 ? { images } : {})
 });
 return {
     content: response.content,
-    usage: response.usage
+    usage: response.usage,
+    ...(response.system_fingerprint ? { system_fingerprint: response.system_fingerprint } : {})
 };
 `,
 		UIConfig: LSchema(`{
@@ -126,7 +168,13 @@ return {
 			LError("LLM_004", "API_ERROR", "APIエラー", "LLM API error", "LLM APIエラー", true),
 		},
 		RequiredCredentials: json.RawMessage(`[{"name": "llm_api_key", "type": "api_key", "scope": "system", "required": true, "description": "LLM Provider API Key"}]`),
-		Enabled:             true,
+		Examples: []domain.LocalizedBlockExample{
+			LExample("Summarize a support ticket", "問い合わせの要約",
+				`{"provider": "openai", "model": "gpt-4o-mini", "system_prompt": "You are a support lead. Answer in one sentence.", "user_prompt": "Summarize this ticket: {{ticket.body}}", "temperature": 0.2}`,
+				`{"ticket": {"id": "T-1042", "body": "Since this morning the export button returns a 500 error for every report in our workspace."}}`,
+				`{"content": "Report exports have failed with a 500 error for the whole workspace since this morning.", "usage": {"input_tokens": 48, "output_tokens": 17, "total_tokens": 65}}`),
+		},
+		Enabled: true,
 		TestCases: []BlockTestCase{
 			{
 				Name:   "basic LLM call",
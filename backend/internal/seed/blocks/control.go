@@ -272,7 +272,7 @@ func ScheduleTriggerBlock() *SystemBlockDefinition {
 				"timezone": {
 					"type": "string",
 					"title": "Timezone",
-					"default": "Asia/Tokyo",
+					"description": "Defaults to the deployment timezone (DEFAULT_TIMEZONE) when omitted",
 					"enum": ["Asia/Tokyo", "UTC", "America/New_York", "Europe/London"]
 				},
 				"enabled": {
@@ -299,7 +299,7 @@ func ScheduleTriggerBlock() *SystemBlockDefinition {
 				"timezone": {
 					"type": "string",
 					"title": "タイムゾーン",
-					"default": "Asia/Tokyo",
+					"description": "省略時はデプロイのデフォルトタイムゾーン（DEFAULT_TIMEZONE）",
 					"enum": ["Asia/Tokyo", "UTC", "America/New_York", "Europe/London"]
 				},
 				"enabled": {
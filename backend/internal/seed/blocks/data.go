@@ -52,7 +52,13 @@ return {
 `,
 		UIConfig:   LSchema(`{"icon": "scissors", "color": "#06B6D4"}`, `{"icon": "scissors", "color": "#06B6D4"}`),
 		ErrorCodes: []domain.LocalizedErrorCodeDef{},
-		Enabled:    true,
+		Examples: []domain.LocalizedBlockExample{
+			LExample("Batches of two", "2件ずつのバッチ",
+				`{"input_path": "items", "batch_size": 2}`,
+				`{"items": [1, 2, 3, 4, 5]}`,
+				`{"items": [1, 2, 3, 4, 5], "batches": [[1, 2], [3, 4], [5]], "batch_count": 3, "total_items": 5}`),
+		},
+		Enabled: true,
 		TestCases: []BlockTestCase{
 			{
 				Name:   "split array into batches of 2",
@@ -134,14 +140,18 @@ func MapBlock() *SystemBlockDefinition {
 			"properties": {
 				"parallel": {"type": "boolean", "title": "Parallel", "description": "Process items in parallel"},
 				"input_path": {"type": "string", "title": "Input Path", "description": "JSONPath to the array"},
-				"max_workers": {"type": "integer", "title": "Max Workers", "description": "Maximum parallel workers"}
+				"max_workers": {"type": "integer", "title": "Max Workers", "description": "Maximum parallel workers"},
+				"stream": {"type": "boolean", "title": "Stream", "description": "Pass each item to the item port as soon as it completes"},
+				"ordered": {"type": "boolean", "title": "Ordered", "description": "Stream items in input order instead of completion order"}
 			}
 		}`, `{
 			"type": "object",
 			"properties": {
 				"parallel": {"type": "boolean", "title": "並列処理", "description": "アイテムを並列で処理"},
 				"input_path": {"type": "string", "title": "入力パス", "description": "配列へのJSONPath"},
-				"max_workers": {"type": "integer", "title": "最大ワーカー数", "description": "最大並列ワーカー数"}
+				"max_workers": {"type": "integer", "title": "最大ワーカー数", "description": "最大並列ワーカー数"},
+				"stream": {"type": "boolean", "title": "ストリーミング", "description": "完了したアイテムから順にitemポートへ渡す"},
+				"ordered": {"type": "boolean", "title": "順序保持", "description": "完了順ではなく入力順にストリーミング"}
 			}
 		}`),
 		OutputPorts: []domain.LocalizedOutputPort{
@@ -45,6 +45,9 @@ type SystemBlockDefinition struct {
 	ErrorCodes          []domain.LocalizedErrorCodeDef `json:"error_codes"`
 	RequiredCredentials json.RawMessage                `json:"required_credentials,omitempty"`
 
+	// Examples: Sample config, input and expected output (validated against the schemas at seed time)
+	Examples []domain.LocalizedBlockExample `json:"examples,omitempty"`
+
 	// Flags
 	Enabled bool `json:"enabled"`
 
@@ -139,3 +142,19 @@ func LError(code, nameEN, nameJA, descEN, descJA string, retryable bool) domain.
 		Retryable:   retryable,
 	}
 }
+
+// LExample creates a LocalizedBlockExample from JSON config, input and output. Empty config or
+// input is omitted.
+func LExample(nameEN, nameJA, config, input, output string) domain.LocalizedBlockExample {
+	example := domain.LocalizedBlockExample{
+		Name:   domain.L(nameEN, nameJA),
+		Output: json.RawMessage(output),
+	}
+	if config != "" {
+		example.Config = json.RawMessage(config)
+	}
+	if input != "" {
+		example.Input = json.RawMessage(input)
+	}
+	return example
+}
@@ -768,7 +768,7 @@ return {
 func EmbeddingBlock() *SystemBlockDefinition {
 	return &SystemBlockDefinition{
 		Slug:        "embedding",
-		Version:     1,
+		Version:     2, // Incremented for batched embedding
 		Name:        LText("Embedding", "埋め込み"),
 		Description: LText("Convert text to vector embeddings", "テキストをベクトル埋め込みに変換"),
 		Category:    domain.BlockCategoryAI,
@@ -778,13 +778,15 @@ func EmbeddingBlock() *SystemBlockDefinition {
 			"type": "object",
 			"properties": {
 				"provider": {"type": "string", "enum": ["openai", "cohere", "voyage"], "default": "openai", "title": "Provider", "description": "Embedding provider"},
-				"model": {"type": "string", "default": "text-embedding-3-small", "title": "Model", "description": "Embedding model"}
+				"model": {"type": "string", "default": "text-embedding-3-small", "title": "Model", "description": "Embedding model"},
+				"batch_size": {"type": "integer", "minimum": 1, "title": "Batch Size", "description": "Texts per API request (default: provider maximum)"}
 			}
 		}`, `{
 			"type": "object",
 			"properties": {
 				"provider": {"type": "string", "enum": ["openai", "cohere", "voyage"], "default": "openai", "title": "プロバイダー", "description": "埋め込みプロバイダー"},
-				"model": {"type": "string", "default": "text-embedding-3-small", "title": "モデル", "description": "埋め込みモデル"}
+				"model": {"type": "string", "default": "text-embedding-3-small", "title": "モデル", "description": "埋め込みモデル"},
+				"batch_size": {"type": "integer", "minimum": 1, "title": "バッチサイズ", "description": "1リクエストあたりのテキスト数（デフォルト: プロバイダーの上限）"}
 			}
 		}`),
 		OutputPorts: []domain.LocalizedOutputPort{
@@ -796,7 +798,7 @@ if (documents.length === 0) throw new Error('[EMB_002] No text provided for embe
 const provider = config.provider || 'openai';
 const model = config.model || 'text-embedding-3-small';
 const texts = documents.map(d => d.content);
-const result = ctx.embedding.embed(provider, model, texts);
+const result = ctx.embedding.embedBatch(provider, model, texts, config.batch_size);
 const docsWithVectors = documents.map((doc, i) => ({...doc, vector: result.vectors[i]}));
 return {documents: docsWithVectors, vectors: result.vectors, model: result.model, dimension: result.dimension, usage: result.usage};
 `,
@@ -96,6 +96,12 @@ return {
 			LError("COND_001", "INVALID_EXPR", "無効な式", "Invalid condition expression", "無効な条件式です", false),
 			LError("COND_002", "EVAL_ERROR", "評価エラー", "Expression evaluation error", "式の評価中にエラーが発生しました", false),
 		},
+		Examples: []domain.LocalizedBlockExample{
+			LExample("Route high scores", "高スコアの振り分け",
+				`{"expression": "$.score >= 80"}`,
+				`{"name": "Aiko", "score": 92}`,
+				`{"name": "Aiko", "score": 92, "__branch": "true"}`),
+		},
 		Enabled: true,
 	}
 }
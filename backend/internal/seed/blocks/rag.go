@@ -15,7 +15,7 @@ func (r *Registry) registerRAGBlocks() {
 func DocLoaderBlock() *SystemBlockDefinition {
 	return &SystemBlockDefinition{
 		Slug:        "doc-loader",
-		Version:     1,
+		Version:     2, // Incremented for streaming load with chunking and size guard
 		Name:        LText("Document Loader", "ドキュメントローダー"),
 		Description: LText("Load documents from URL, text, or JSON", "URL、テキスト、またはJSONからドキュメントを読み込み"),
 		Category:    domain.BlockCategoryAI,
@@ -27,7 +27,11 @@ func DocLoaderBlock() *SystemBlockDefinition {
 				"source_type": {"type": "string", "enum": ["url", "text", "json"], "default": "url", "title": "Source Type", "description": "Document source type"},
 				"url": {"type": "string", "title": "URL", "description": "URL to load document from"},
 				"content": {"type": "string", "title": "Text Content", "description": "Text content to load"},
-				"strip_html": {"type": "boolean", "default": true, "title": "Strip HTML Tags", "description": "Remove HTML tags from content"}
+				"strip_html": {"type": "boolean", "default": true, "title": "Strip HTML Tags", "description": "Remove HTML tags from content"},
+				"format": {"type": "string", "enum": ["auto", "text", "markdown", "html", "csv", "json"], "default": "auto", "title": "Format", "description": "Document format (auto detects from Content-Type and file extension; PDF and other binary formats are not supported)"},
+				"chunk_size": {"type": "integer", "default": 0, "minimum": 0, "maximum": 8000, "title": "Chunk Size (chars)", "description": "Split while loading into chunks of at most this many characters (0 = one document). Use for large files"},
+				"chunk_overlap": {"type": "integer", "default": 0, "minimum": 0, "title": "Overlap (chars)", "description": "Character overlap between chunks"},
+				"max_bytes": {"type": "integer", "default": 52428800, "minimum": 1, "title": "Max Size (bytes)", "description": "Fail if the document is larger than this (default 50MB)"}
 			}
 		}`, `{
 			"type": "object",
@@ -35,13 +39,18 @@ func DocLoaderBlock() *SystemBlockDefinition {
 				"source_type": {"type": "string", "enum": ["url", "text", "json"], "default": "url", "title": "ソースタイプ", "description": "ドキュメントのソースタイプ"},
 				"url": {"type": "string", "title": "URL", "description": "ドキュメントを読み込むURL"},
 				"content": {"type": "string", "title": "テキストコンテンツ", "description": "読み込むテキストコンテンツ"},
-				"strip_html": {"type": "boolean", "default": true, "title": "HTMLタグを除去", "description": "コンテンツからHTMLタグを除去"}
+				"strip_html": {"type": "boolean", "default": true, "title": "HTMLタグを除去", "description": "コンテンツからHTMLタグを除去"},
+				"format": {"type": "string", "enum": ["auto", "text", "markdown", "html", "csv", "json"], "default": "auto", "title": "フォーマット", "description": "ドキュメントの形式（autoはContent-Typeと拡張子から判定。PDFなどのバイナリ形式は非対応）"},
+				"chunk_size": {"type": "integer", "default": 0, "minimum": 0, "maximum": 8000, "title": "チャンクサイズ（文字数）", "description": "読み込みながらこの文字数以下のチャンクに分割（0 = 1ドキュメント）。大きなファイル向け"},
+				"chunk_overlap": {"type": "integer", "default": 0, "minimum": 0, "title": "オーバーラップ（文字数）", "description": "チャンク間の文字オーバーラップ"},
+				"max_bytes": {"type": "integer", "default": 52428800, "minimum": 1, "title": "最大サイズ（バイト）", "description": "ドキュメントがこのサイズを超える場合はエラー（デフォルト50MB）"}
 			}
 		}`),
 		OutputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
-				"documents": {"type": "array"}
+				"documents": {"type": "array"},
+				"chunk_count": {"type": "integer"}
 			}
 		}`),
 		OutputPorts: []domain.LocalizedOutputPort{
@@ -69,12 +78,29 @@ function validateExternalUrl(rawUrl) {
   if (hostname.endsWith('.local') || hostname.endsWith('.internal')) throw new Error('[DOC_001] Access to internal hostnames is not allowed');
   return parsed.toString();
 }
+function loadStreaming(source, metadata, format) {
+  let loaded;
+  try {
+    loaded = ctx.documents.load(source, {format, chunk_size: config.chunk_size || 0, chunk_overlap: config.chunk_overlap || 0, max_bytes: config.max_bytes, strip_html: config.strip_html !== false});
+  } catch (e) {
+    const message = String(e && e.message ? e.message : e);
+    if (message.includes('exceeds maximum size')) throw new Error('[DOC_003] ' + message);
+    if (message.includes('unsupported document format')) throw new Error('[DOC_004] ' + message);
+    throw new Error('[DOC_001] ' + message);
+  }
+  if (loaded.chunk_count === 0) throw new Error('[DOC_002] No content provided');
+  const base = {...metadata, format: loaded.format, bytes_read: loaded.bytes_read};
+  if (loaded.content_type) base.content_type = loaded.content_type;
+  const documents = loaded.chunks.map((c) => ({content: c.content, metadata: config.chunk_size ? {...base, chunk_index: c.chunk_index, chunk_total: loaded.chunk_count} : base, char_count: c.char_count}));
+  return {documents, chunk_count: loaded.chunk_count};
+}
 const sourceType = config.source_type || 'url';
 let content, metadata;
 if (sourceType === 'url') {
   const rawUrl = config.url || input.url;
   if (!rawUrl) throw new Error('[DOC_002] URL is required for url source type');
   const url = validateExternalUrl(rawUrl);
+  if (ctx.documents) return loadStreaming({url}, {source: url, source_type: 'url', fetched_at: new Date().toISOString()}, config.format);
   const response = ctx.http.get(url);
   content = typeof response.data === 'string' ? response.data : JSON.stringify(response.data);
   metadata = {source: url, source_type: 'url', content_type: response.headers['Content-Type'], fetched_at: new Date().toISOString()};
@@ -82,6 +108,10 @@ if (sourceType === 'url') {
   content = config.content || input.content || input.text;
   if (!content) throw new Error('[DOC_002] No content provided');
   metadata = {source_type: 'text'};
+  if (ctx.documents && (config.chunk_size || config.format)) {
+    const format = config.format && config.format !== 'auto' ? config.format : (config.strip_html && content.includes('<') ? 'html' : 'text');
+    return loadStreaming({content}, metadata, format);
+  }
 } else if (sourceType === 'json') {
   const data = input.data || input;
   content = config.content_path ? getPath(data, config.content_path) : JSON.stringify(data);
@@ -95,6 +125,8 @@ return {documents: [{content, metadata, char_count: content.length}]};`,
 		ErrorCodes: []domain.LocalizedErrorCodeDef{
 			LError("DOC_001", "FETCH_ERROR", "取得エラー", "Failed to fetch URL", "URLの取得に失敗しました", true),
 			LError("DOC_002", "EMPTY_CONTENT", "空のコンテンツ", "No content provided", "コンテンツが提供されていません", false),
+			LError("DOC_003", "DOCUMENT_TOO_LARGE", "サイズ超過", "Document exceeds max_bytes", "ドキュメントがmax_bytesを超えています", false),
+			LError("DOC_004", "UNSUPPORTED_FORMAT", "非対応フォーマット", "Unsupported document format (supported: text, markdown, html, csv, json)", "非対応のドキュメント形式です（対応: text, markdown, html, csv, json）", false),
 		},
 		Enabled: true,
 	}
@@ -169,6 +201,12 @@ return {documents: result, chunk_count: result.length, original_count: documents
 		ErrorCodes: []domain.LocalizedErrorCodeDef{
 			LError("SPLIT_001", "NO_CONTENT", "コンテンツなし", "No content to split", "分割するコンテンツがありません", false),
 		},
+		Examples: []domain.LocalizedBlockExample{
+			LExample("Short text in one chunk", "1チャンクに収まる短いテキスト",
+				`{"chunk_size": 500, "chunk_overlap": 50}`,
+				`{"content": "First paragraph.\n\nSecond paragraph."}`,
+				`{"documents": [{"content": "First paragraph.\n\nSecond paragraph.", "metadata": {"chunk_index": 0, "chunk_total": 1}, "char_count": 35}], "chunk_count": 1, "original_count": 1}`),
+		},
 		Enabled: true,
 	}
 }
@@ -54,6 +54,9 @@ type YAMLBlockDefinition struct {
 	ErrorCodes          []YAMLErrorCodeDef `yaml:"error_codes,omitempty"`
 	RequiredCredentials interface{}        `yaml:"required_credentials,omitempty"`
 
+	// Examples: sample config, input and expected output
+	Examples []YAMLBlockExample `yaml:"examples,omitempty"`
+
 	// Flags
 	Enabled bool `yaml:"enabled"`
 
@@ -82,6 +85,14 @@ type YAMLErrorCodeDef struct {
 	Retryable   bool        `yaml:"retryable"`
 }
 
+// YAMLBlockExample represents a block example in YAML
+type YAMLBlockExample struct {
+	Name   interface{} `yaml:"name"` // string or {en: "", ja: ""}
+	Config interface{} `yaml:"config,omitempty"`
+	Input  interface{} `yaml:"input,omitempty"`
+	Output interface{} `yaml:"output"`
+}
+
 // YAMLRequestConfig represents declarative request config in YAML
 type YAMLRequestConfig struct {
 	URL         string            `yaml:"url,omitempty"`
@@ -306,6 +317,30 @@ func convertYAMLToSystemBlock(y *YAMLBlockDefinition) (*SystemBlockDefinition, e
 		})
 	}
 
+	// Convert examples
+	for i, e := range y.Examples {
+		example := domain.LocalizedBlockExample{Name: parseLocalizedText(e.Name)}
+		for _, field := range []struct {
+			name  string
+			value interface{}
+			dest  *json.RawMessage
+		}{
+			{"config", e.Config, &example.Config},
+			{"input", e.Input, &example.Input},
+			{"output", e.Output, &example.Output},
+		} {
+			if field.value == nil {
+				continue
+			}
+			jsonData, err := toJSONRawMessage(field.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of examples[%d]: %w", field.name, i, err)
+			}
+			*field.dest = jsonData
+		}
+		block.Examples = append(block.Examples, example)
+	}
+
 	// Convert internal steps
 	for _, s := range y.InternalSteps {
 		step := domain.InternalStep{
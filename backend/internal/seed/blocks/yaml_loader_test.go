@@ -222,6 +222,40 @@ enabled: true
 	assert.True(t, slugs["block3"])
 }
 
+func TestYAMLLoader_LoadBlockWithExamples(t *testing.T) {
+	tmpDir := t.TempDir()
+	blockYAML := `
+slug: greet
+version: 1
+name: Greet
+category: apps
+enabled: true
+examples:
+  - name:
+      en: Greet by name
+      ja: 名前で挨拶
+    config:
+      template: "Hello {{name}}"
+    input:
+      name: Aiko
+    output:
+      message: Hello Aiko
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "greet.yaml"), []byte(blockYAML), 0644))
+
+	blocks, err := NewYAMLLoader(tmpDir).LoadAll()
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	require.Len(t, blocks[0].Examples, 1)
+
+	example := blocks[0].Examples[0]
+	assert.Equal(t, "Greet by name", example.Name.EN)
+	assert.Equal(t, "名前で挨拶", example.Name.JA)
+	assert.JSONEq(t, `{"template": "Hello {{name}}"}`, string(example.Config))
+	assert.JSONEq(t, `{"name": "Aiko"}`, string(example.Input))
+	assert.JSONEq(t, `{"message": "Hello Aiko"}`, string(example.Output))
+}
+
 func TestYAMLLoader_SlackBlock(t *testing.T) {
 	testDir := filepath.Join("yaml")
 	loader := NewYAMLLoader(testDir)
@@ -0,0 +1,163 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/seed/blocks"
+	"github.com/souta/ai-orchestration/internal/seed/workflows"
+)
+
+// blockContent is the part of a block definition covered by its content hash.
+// Version, IDs, timestamps and parent references are excluded so that only real content changes
+// alter the hash.
+type blockContent struct {
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description"`
+	Category            domain.BlockCategory    `json:"category"`
+	Subcategory         domain.BlockSubcategory `json:"subcategory"`
+	Icon                string                  `json:"icon"`
+	ConfigSchema        interface{}             `json:"config_schema"`
+	OutputSchema        interface{}             `json:"output_schema"`
+	OutputPorts         []domain.OutputPort     `json:"output_ports"`
+	Code                string                  `json:"code"`
+	UIConfig            interface{}             `json:"ui_config"`
+	ErrorCodes          []domain.ErrorCodeDef   `json:"error_codes"`
+	RequiredCredentials interface{}             `json:"required_credentials"`
+	Enabled             bool                    `json:"enabled"`
+	GroupKind           domain.BlockGroupKind   `json:"group_kind"`
+	IsContainer         bool                    `json:"is_container"`
+	ConfigDefaults      interface{}             `json:"config_defaults"`
+	PreProcess          string                  `json:"pre_process"`
+	PostProcess         string                  `json:"post_process"`
+	InternalSteps       []internalStepContent   `json:"internal_steps"`
+	Request             *domain.RequestConfig   `json:"request"`
+	Response            *domain.ResponseConfig  `json:"response"`
+	Examples            []blockExampleContent   `json:"examples,omitempty"`
+}
+
+type blockExampleContent struct {
+	Name   string      `json:"name"`
+	Config interface{} `json:"config"`
+	Input  interface{} `json:"input"`
+	Output interface{} `json:"output"`
+}
+
+type internalStepContent struct {
+	Type      string      `json:"type"`
+	Config    interface{} `json:"config"`
+	OutputKey string      `json:"output_key"`
+}
+
+// BlockContentHash returns the SHA-256 content hash of a stored block definition
+func BlockContentHash(block *domain.BlockDefinition) string {
+	content := blockContent{
+		Name:                block.Name,
+		Description:         block.Description,
+		Category:            block.Category,
+		Subcategory:         block.Subcategory,
+		Icon:                block.Icon,
+		ConfigSchema:        normalizeJSON(block.ConfigSchema),
+		OutputSchema:        normalizeJSON(block.OutputSchema),
+		Code:                block.Code,
+		UIConfig:            normalizeJSON(block.UIConfig),
+		RequiredCredentials: normalizeJSON(block.RequiredCredentials),
+		Enabled:             block.Enabled,
+		GroupKind:           block.GroupKind,
+		IsContainer:         block.IsContainer,
+		ConfigDefaults:      normalizeJSON(block.ConfigDefaults),
+		PreProcess:          block.PreProcess,
+		PostProcess:         block.PostProcess,
+		Request:             block.Request,
+		Response:            block.Response,
+	}
+	for _, port := range block.OutputPorts {
+		port.Schema = normalizeRaw(port.Schema)
+		content.OutputPorts = append(content.OutputPorts, port)
+	}
+	content.ErrorCodes = append(content.ErrorCodes, block.ErrorCodes...)
+	for _, step := range block.InternalSteps {
+		content.InternalSteps = append(content.InternalSteps, internalStepContent{
+			Type:      step.Type,
+			Config:    normalizeJSON(step.Config),
+			OutputKey: step.OutputKey,
+		})
+	}
+	content.Examples = examplesContent(block.Examples)
+	return hashContent(content)
+}
+
+// examplesContent normalizes block examples for hashing
+func examplesContent(examples []domain.BlockExample) []blockExampleContent {
+	var content []blockExampleContent
+	for _, example := range examples {
+		content = append(content, blockExampleContent{
+			Name:   example.Name,
+			Config: normalizeJSON(example.Config),
+			Input:  normalizeJSON(example.Input),
+			Output: normalizeJSON(example.Output),
+		})
+	}
+	return content
+}
+
+// SeedBlockContentHash returns the content hash a seed block will have once stored in the database
+func SeedBlockContentHash(seed *blocks.SystemBlockDefinition) string {
+	block := &domain.BlockDefinition{}
+	applySeedBlock(block, seed, defaultMigrationLanguage)
+	return BlockContentHash(block)
+}
+
+// WorkflowContentHash returns the SHA-256 content hash of a seed workflow definition.
+// The version is excluded so that a version bump without content changes keeps the same hash.
+func WorkflowContentHash(seed *workflows.SystemWorkflowDefinition) string {
+	content := *seed
+	content.Version = 0
+	return hashContent(content)
+}
+
+func hashContent(content interface{}) string {
+	data, err := json.Marshal(content)
+	if err != nil {
+		// Invalid raw JSON cannot be marshaled; hash its Go representation instead
+		data = []byte(fmt.Sprintf("%#v", content))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeJSON decodes raw JSON so that formatting and key order do not affect the hash.
+// Empty values ({}, [], null, missing) all normalize to nil, since the database stores
+// defaults for some of them.
+func normalizeJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+	return value
+}
+
+func normalizeRaw(raw json.RawMessage) json.RawMessage {
+	value := normalizeJSON(raw)
+	if value == nil {
+		return nil
+	}
+	data, _ := json.Marshal(value)
+	return data
+}
@@ -202,44 +202,17 @@ func (m *Migrator) createBlock(ctx context.Context, seedBlock *blocks.SystemBloc
 		parentBlockID = &parentBlock.ID
 	}
 
-	// Convert localized fields to single language for DB storage
-	outputPorts := convertLocalizedOutputPorts(seedBlock.OutputPorts, lang)
-	errorCodes := convertLocalizedErrorCodes(seedBlock.ErrorCodes, lang)
-
 	block := &domain.BlockDefinition{
-		ID:                  uuid.New(),
-		TenantID:            nil, // System block
-		Slug:                seedBlock.Slug,
-		Name:                seedBlock.Name.Get(lang),
-		Description:         seedBlock.Description.Get(lang),
-		Category:            seedBlock.Category,
-		Subcategory:         seedBlock.Subcategory,
-		Icon:                seedBlock.Icon,
-		ConfigSchema:        seedBlock.ConfigSchema.Get(lang),
-		OutputSchema:        seedBlock.OutputSchema,
-		OutputPorts:         outputPorts,
-		Code:                seedBlock.Code,
-		UIConfig:            seedBlock.UIConfig.Get(lang),
-		ErrorCodes:          errorCodes,
-		RequiredCredentials: seedBlock.RequiredCredentials,
-		IsSystem:            true,
-		IsPublic:            false,
-		Version:             seedBlock.Version, // Use explicit version from seed
-		Enabled:             seedBlock.Enabled,
-		GroupKind:           seedBlock.GroupKind,
-		IsContainer:         seedBlock.IsContainer,
-		// Inheritance fields
-		ParentBlockID:  parentBlockID,
-		ConfigDefaults: seedBlock.ConfigDefaults,
-		PreProcess:     seedBlock.PreProcess,
-		PostProcess:    seedBlock.PostProcess,
-		InternalSteps:  seedBlock.InternalSteps,
-		// Declarative request/response
-		Request:   seedBlock.Request,
-		Response:  seedBlock.Response,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            uuid.New(),
+		TenantID:      nil, // System block
+		Slug:          seedBlock.Slug,
+		IsSystem:      true,
+		IsPublic:      false,
+		ParentBlockID: parentBlockID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
+	applySeedBlock(block, seedBlock, lang)
 
 	if err := m.blockRepo.Create(ctx, block); err != nil {
 		return "", fmt.Errorf("failed to create block: %w", err)
@@ -248,6 +221,7 @@ func (m *Migrator) createBlock(ctx context.Context, seedBlock *blocks.SystemBloc
 	// Create initial version snapshot
 	if m.versionRepo != nil {
 		version := domain.NewBlockVersion(block, "Initial seed", nil)
+		version.ContentHash = BlockContentHash(block)
 		if err := m.versionRepo.Create(ctx, version); err != nil {
 			// Log warning but don't fail the migration
 			fmt.Printf("Warning: failed to create initial version for %s: %v\n", seedBlock.Slug, err)
@@ -264,6 +238,7 @@ func (m *Migrator) updateBlock(ctx context.Context, existing *domain.BlockDefini
 	// Create version snapshot BEFORE updating only if version changes
 	if m.versionRepo != nil && existing.Version != seedBlock.Version {
 		version := domain.NewBlockVersion(existing, "Migration update", nil)
+		version.ContentHash = BlockContentHash(existing)
 		if err := m.versionRepo.Create(ctx, version); err != nil {
 			return "", fmt.Errorf("failed to create version snapshot: %w", err)
 		}
@@ -282,36 +257,8 @@ func (m *Migrator) updateBlock(ctx context.Context, existing *domain.BlockDefini
 		parentBlockID = &parentBlock.ID
 	}
 
-	// Convert localized fields to single language for DB storage
-	outputPorts := convertLocalizedOutputPorts(seedBlock.OutputPorts, lang)
-	errorCodes := convertLocalizedErrorCodes(seedBlock.ErrorCodes, lang)
-
-	// Update fields (version is explicitly set from seedBlock)
-	existing.Name = seedBlock.Name.Get(lang)
-	existing.Description = seedBlock.Description.Get(lang)
-	existing.Category = seedBlock.Category
-	existing.Subcategory = seedBlock.Subcategory
-	existing.Icon = seedBlock.Icon
-	existing.ConfigSchema = seedBlock.ConfigSchema.Get(lang)
-	existing.OutputSchema = seedBlock.OutputSchema
-	existing.OutputPorts = outputPorts
-	existing.Code = seedBlock.Code
-	existing.UIConfig = seedBlock.UIConfig.Get(lang)
-	existing.ErrorCodes = errorCodes
-	existing.RequiredCredentials = seedBlock.RequiredCredentials
-	existing.Enabled = seedBlock.Enabled
-	existing.Version = seedBlock.Version // Use explicit version from seed (no auto-increment)
-	existing.GroupKind = seedBlock.GroupKind
-	existing.IsContainer = seedBlock.IsContainer
-	// Inheritance fields
+	applySeedBlock(existing, seedBlock, lang)
 	existing.ParentBlockID = parentBlockID
-	existing.ConfigDefaults = seedBlock.ConfigDefaults
-	existing.PreProcess = seedBlock.PreProcess
-	existing.PostProcess = seedBlock.PostProcess
-	existing.InternalSteps = seedBlock.InternalSteps
-	// Declarative request/response
-	existing.Request = seedBlock.Request
-	existing.Response = seedBlock.Response
 	existing.UpdatedAt = time.Now().UTC()
 
 	if err := m.blockRepo.Update(ctx, existing); err != nil {
@@ -321,83 +268,41 @@ func (m *Migrator) updateBlock(ctx context.Context, existing *domain.BlockDefini
 	return "updated", nil
 }
 
-// hasChanges compares existing block with seed definition
-func (m *Migrator) hasChanges(existing *domain.BlockDefinition, seed *blocks.SystemBlockDefinition) bool {
-	lang := defaultMigrationLanguage
-
-	// Compare version first
-	if existing.Version != seed.Version {
-		return true
-	}
-
-	// Compare key fields that would indicate a change (using localized values)
-	if existing.Name != seed.Name.Get(lang) {
-		return true
-	}
-	if existing.Description != seed.Description.Get(lang) {
-		return true
-	}
-	if existing.Category != seed.Category {
-		return true
-	}
-	if existing.Subcategory != seed.Subcategory {
-		return true
-	}
-	if existing.Icon != seed.Icon {
-		return true
-	}
-	if existing.Code != seed.Code {
-		return true
-	}
-	if existing.Enabled != seed.Enabled {
-		return true
-	}
-	if existing.GroupKind != seed.GroupKind {
-		return true
-	}
-	if existing.IsContainer != seed.IsContainer {
-		return true
-	}
-
-	// Compare inheritance fields
-	if existing.PreProcess != seed.PreProcess {
-		return true
-	}
-	if existing.PostProcess != seed.PostProcess {
-		return true
-	}
-	if !jsonEqual(existing.ConfigDefaults, seed.ConfigDefaults) {
-		return true
-	}
-	if !internalStepsEqual(existing.InternalSteps, seed.InternalSteps) {
-		return true
-	}
-
-	// Compare JSON fields (using localized schema)
-	if !jsonEqual(existing.ConfigSchema, seed.ConfigSchema.Get(lang)) {
-		return true
-	}
-	if !jsonEqual(existing.OutputSchema, seed.OutputSchema) {
-		return true
-	}
-	if !jsonEqual(existing.UIConfig, seed.UIConfig.Get(lang)) {
-		return true
-	}
-	if !jsonEqual(existing.RequiredCredentials, seed.RequiredCredentials) {
-		return true
-	}
-
-	// Compare ports and error codes (using converted values)
-	seedOutputPorts := convertLocalizedOutputPorts(seed.OutputPorts, lang)
-	seedErrorCodes := convertLocalizedErrorCodes(seed.ErrorCodes, lang)
-	if !outputPortsEqual(existing.OutputPorts, seedOutputPorts) {
-		return true
-	}
-	if !errorCodesEqual(existing.ErrorCodes, seedErrorCodes) {
-		return true
-	}
+// applySeedBlock copies the content and version of a seed definition onto a block,
+// converting localized fields to the language stored in the database
+func applySeedBlock(block *domain.BlockDefinition, seed *blocks.SystemBlockDefinition, lang string) {
+	block.Name = seed.Name.Get(lang)
+	block.Description = seed.Description.Get(lang)
+	block.Category = seed.Category
+	block.Subcategory = seed.Subcategory
+	block.Icon = seed.Icon
+	block.ConfigSchema = seed.ConfigSchema.Get(lang)
+	block.OutputSchema = seed.OutputSchema
+	block.OutputPorts = convertLocalizedOutputPorts(seed.OutputPorts, lang)
+	block.Code = seed.Code
+	block.UIConfig = seed.UIConfig.Get(lang)
+	block.ErrorCodes = convertLocalizedErrorCodes(seed.ErrorCodes, lang)
+	block.RequiredCredentials = seed.RequiredCredentials
+	block.Examples = convertLocalizedExamples(seed.Examples, lang)
+	block.Enabled = seed.Enabled
+	block.Version = seed.Version // Use explicit version from seed (no auto-increment)
+	block.GroupKind = seed.GroupKind
+	block.IsContainer = seed.IsContainer
+	// Inheritance fields
+	block.ConfigDefaults = seed.ConfigDefaults
+	block.PreProcess = seed.PreProcess
+	block.PostProcess = seed.PostProcess
+	block.InternalSteps = seed.InternalSteps
+	// Declarative request/response
+	block.Request = seed.Request
+	block.Response = seed.Response
+}
 
-	return false
+// hasChanges compares the content hash of the existing block with the seed definition.
+// The version alone does not decide: a content change without a version bump is an update,
+// and a version bump without a content change leaves the block unchanged.
+func (m *Migrator) hasChanges(existing *domain.BlockDefinition, seed *blocks.SystemBlockDefinition) bool {
+	return BlockContentHash(existing) != SeedBlockContentHash(seed)
 }
 
 // internalStepsEqual compares internal steps
@@ -558,9 +463,15 @@ func (m *Migrator) describeChanges(existing *domain.BlockDefinition, seed *block
 	if !internalStepsEqual(existing.InternalSteps, seed.InternalSteps) {
 		changes = append(changes, "internal_steps")
 	}
+	if hashContent(examplesContent(existing.Examples)) != hashContent(examplesContent(convertLocalizedExamples(seed.Examples, lang))) {
+		changes = append(changes, "examples")
+	}
 
 	if len(changes) == 0 {
-		return "no specific changes"
+		changes = append(changes, "other fields")
+	}
+	if existing.Version == seed.Version {
+		changes = append(changes, "no version bump")
 	}
 
 	result := changes[0]
@@ -598,3 +509,15 @@ func convertLocalizedErrorCodes(codes []domain.LocalizedErrorCodeDef, lang strin
 	}
 	return result
 }
+
+// convertLocalizedExamples converts localized block examples to domain block examples
+func convertLocalizedExamples(examples []domain.LocalizedBlockExample, lang string) []domain.BlockExample {
+	if len(examples) == 0 {
+		return nil
+	}
+	result := make([]domain.BlockExample, len(examples))
+	for i, e := range examples {
+		result[i] = e.ToBlockExample(lang)
+	}
+	return result
+}
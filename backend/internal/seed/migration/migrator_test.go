@@ -369,3 +369,71 @@ func TestTopologicalSort_MissingParent(t *testing.T) {
 		t.Errorf("expected error for missing parent, but got none")
 	}
 }
+
+func TestHasChanges_ContentHash(t *testing.T) {
+	migrator := &Migrator{}
+	seed := &blocks.SystemBlockDefinition{
+		Slug:         "hash-test",
+		Version:      3,
+		Name:         blocks.LText("Hash Test", "ハッシュテスト"),
+		Category:     domain.BlockCategoryFlow,
+		Code:         "return input;",
+		ConfigSchema: domain.LocalizedConfigSchema{EN: json.RawMessage(`{"type": "object"}`), JA: json.RawMessage(`{"type":"object"}`)},
+		Enabled:      true,
+	}
+
+	// The block as stored by a previous migration of the same seed
+	stored := &domain.BlockDefinition{}
+	applySeedBlock(stored, seed, defaultMigrationLanguage)
+	stored.ConfigDefaults = json.RawMessage(`{}`) // Database default for an unset column
+
+	if migrator.hasChanges(stored, seed) {
+		t.Error("hasChanges() = true for a block stored from the same seed")
+	}
+
+	t.Run("content change with same version is an update", func(t *testing.T) {
+		changed := *seed
+		changed.Code = "return { changed: true };"
+		if !migrator.hasChanges(stored, &changed) {
+			t.Error("hasChanges() = false, want true")
+		}
+		if reason := migrator.describeChanges(stored, &changed); !contains(reason, "code") || !contains(reason, "no version bump") {
+			t.Errorf("describeChanges() = %q, want code change without version bump", reason)
+		}
+	})
+
+	t.Run("version bump without content change is unchanged", func(t *testing.T) {
+		bumped := *seed
+		bumped.Version = 4
+		if migrator.hasChanges(stored, &bumped) {
+			t.Error("hasChanges() = true, want false")
+		}
+	})
+
+	t.Run("hash ignores JSON formatting", func(t *testing.T) {
+		reformatted := *stored
+		reformatted.ConfigSchema = json.RawMessage(`{ "type" : "object" }`)
+		if BlockContentHash(&reformatted) != BlockContentHash(stored) {
+			t.Error("BlockContentHash() differs for equivalent JSON")
+		}
+	})
+
+	t.Run("examples are part of the content", func(t *testing.T) {
+		withExamples := *seed
+		withExamples.Examples = []domain.LocalizedBlockExample{
+			blocks.LExample("Echo", "エコー", "", `{"text": "hi"}`, `{"text": "hi"}`),
+		}
+		if !migrator.hasChanges(stored, &withExamples) {
+			t.Error("hasChanges() = false for added examples, want true")
+		}
+		if reason := migrator.describeChanges(stored, &withExamples); !contains(reason, "examples") {
+			t.Errorf("describeChanges() = %q, want an examples change", reason)
+		}
+
+		updated := &domain.BlockDefinition{}
+		applySeedBlock(updated, &withExamples, "en")
+		if len(updated.Examples) != 1 || updated.Examples[0].Name != "Echo" {
+			t.Errorf("applySeedBlock() examples = %+v, want the English example", updated.Examples)
+		}
+	})
+}
@@ -18,6 +18,7 @@ type ProjectMigrationResult struct {
 	Created   []string // SystemSlugs of newly created projects
 	Updated   []string // SystemSlugs of updated projects
 	Unchanged []string // SystemSlugs of unchanged projects
+	Warnings  []string // Steps referenced by recent runs that the migration removed
 	Errors    []error
 }
 
@@ -26,6 +27,7 @@ type ProjectDryRunResult struct {
 	ToCreate  []string            // SystemSlugs of projects to create
 	ToUpdate  []ProjectUpdateInfo // Info about projects to update
 	Unchanged []string            // SystemSlugs of unchanged projects
+	Warnings  []string            // Steps referenced by recent runs that the migration would remove
 }
 
 // ProjectUpdateInfo provides details about a project update
@@ -43,6 +45,8 @@ type ProjectMigrator struct {
 	edgeRepo       repository.EdgeRepository
 	blockRepo      repository.BlockDefinitionRepository
 	blockGroupRepo repository.BlockGroupRepository
+	versionRepo    repository.ProjectVersionRepository
+	runRepo        repository.RunRepository
 }
 
 // NewProjectMigrator creates a new project migrator
@@ -70,17 +74,36 @@ func (m *ProjectMigrator) WithBlockGroupRepo(blockGroupRepo repository.BlockGrou
 	return m
 }
 
+// WithVersionRepo sets the project version repository.
+// When set, each migrated project gets a version snapshot carrying the seed's content hash,
+// which later migrations compare to detect steps/edges changes.
+func (m *ProjectMigrator) WithVersionRepo(versionRepo repository.ProjectVersionRepository) *ProjectMigrator {
+	m.versionRepo = versionRepo
+	return m
+}
+
+// WithRunRepo sets the run repository used to warn when a migration removes steps that recent
+// runs reference
+func (m *ProjectMigrator) WithRunRepo(runRepo repository.RunRepository) *ProjectMigrator {
+	m.runRepo = runRepo
+	return m
+}
+
 // Migrate performs UPSERT for all projects in the registry
 func (m *ProjectMigrator) Migrate(ctx context.Context, registry *workflows.Registry, tenantID uuid.UUID) (*ProjectMigrationResult, error) {
 	result := &ProjectMigrationResult{
 		Created:   make([]string, 0),
 		Updated:   make([]string, 0),
 		Unchanged: make([]string, 0),
+		Warnings:  make([]string, 0),
 		Errors:    make([]error, 0),
 	}
 
 	for _, seedProject := range registry.GetAll() {
-		action, err := m.upsertProject(ctx, seedProject, tenantID)
+		action, warning, err := m.upsertProject(ctx, seedProject, tenantID)
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
 		if err != nil {
 			result.Errors = append(result.Errors,
 				fmt.Errorf("project %s: %w", seedProject.SystemSlug, err))
@@ -100,12 +123,13 @@ func (m *ProjectMigrator) Migrate(ctx context.Context, registry *workflows.Regis
 	return result, nil
 }
 
-// upsertProject creates or updates a single project with its steps and edges
-func (m *ProjectMigrator) upsertProject(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID) (string, error) {
+// upsertProject creates or updates a single project with its steps and edges.
+// The returned warning is set when the update removes steps referenced by recent runs.
+func (m *ProjectMigrator) upsertProject(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID) (string, string, error) {
 	// Parse the project ID from the seed
 	projectID, err := uuid.Parse(seedProject.ID)
 	if err != nil {
-		return "", fmt.Errorf("invalid project ID: %w", err)
+		return "", "", fmt.Errorf("invalid project ID: %w", err)
 	}
 
 	// Look up existing project by ID
@@ -113,18 +137,29 @@ func (m *ProjectMigrator) upsertProject(ctx context.Context, seedProject *workfl
 	if err != nil {
 		if errors.Is(err, domain.ErrProjectNotFound) {
 			// Project doesn't exist, create it
-			return m.createProject(ctx, seedProject, tenantID, projectID)
+			action, err := m.createProject(ctx, seedProject, tenantID, projectID)
+			return action, "", err
 		}
-		return "", fmt.Errorf("failed to get existing project: %w", err)
+		return "", "", fmt.Errorf("failed to get existing project: %w", err)
 	}
 
 	// Check if update is needed
-	if m.hasChanges(existing, seedProject) {
-		// UPDATE existing project
-		return m.updateProject(ctx, existing, seedProject, tenantID)
+	changed, err := m.contentChanged(ctx, existing, seedProject)
+	if err != nil {
+		return "", "", err
+	}
+	if !changed {
+		return "unchanged", "", nil
 	}
 
-	return "unchanged", nil
+	warning, err := m.orphanWarning(ctx, existing, seedProject, tenantID)
+	if err != nil {
+		return "", "", err
+	}
+
+	// UPDATE existing project
+	action, err := m.updateProject(ctx, existing, seedProject, tenantID)
+	return action, warning, err
 }
 
 // createProject creates a new system project with steps and edges
@@ -152,13 +187,13 @@ func (m *ProjectMigrator) createProject(ctx context.Context, seedProject *workfl
 	}
 
 	// Create block groups and build temp_id -> actual_id mapping
-	groupIDMap, err := m.createBlockGroups(ctx, seedProject, tenantID, projectID)
+	groupIDMap, err := m.saveBlockGroups(ctx, seedProject, tenantID, projectID, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create block groups: %w", err)
 	}
 
 	// Create steps and build temp_id -> actual_id mapping
-	stepIDMap, err := m.createSteps(ctx, seedProject, tenantID, projectID, groupIDMap)
+	stepIDMap, err := m.saveSteps(ctx, seedProject, tenantID, projectID, groupIDMap, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create steps: %w", err)
 	}
@@ -168,23 +203,30 @@ func (m *ProjectMigrator) createProject(ctx context.Context, seedProject *workfl
 		return "", fmt.Errorf("failed to create edges: %w", err)
 	}
 
+	if err := m.saveVersion(ctx, seedProject, tenantID, projectID); err != nil {
+		return "", fmt.Errorf("failed to save project version: %w", err)
+	}
+
 	return "created", nil
 }
 
-// createBlockGroups creates all block groups for a project and returns a temp_id -> group_id mapping
-func (m *ProjectMigrator) createBlockGroups(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID, projectID uuid.UUID) (map[string]uuid.UUID, error) {
-	groupIDMap := make(map[string]uuid.UUID)
+// saveBlockGroups creates or updates the block groups of a project and returns a temp_id -> group_id mapping.
+// Groups listed in existing keep their ID (see resolveGroupIDs) and are updated in place.
+func (m *ProjectMigrator) saveBlockGroups(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID, projectID uuid.UUID, existing []*domain.BlockGroup) (map[string]uuid.UUID, error) {
+	if m.blockGroupRepo == nil {
+		return make(map[string]uuid.UUID), nil
+	}
 
-	if len(seedProject.BlockGroups) == 0 || m.blockGroupRepo == nil {
-		return groupIDMap, nil
+	groupIDMap := resolveGroupIDs(projectID, seedProject, existing)
+	existingIDs := make(map[uuid.UUID]bool, len(existing))
+	for _, group := range existing {
+		existingIDs[group.ID] = true
 	}
 
 	now := time.Now().UTC()
 
-	// First pass: create all groups without parent references
 	for _, seedGroup := range seedProject.BlockGroups {
-		groupID := uuid.New()
-		groupIDMap[seedGroup.TempID] = groupID
+		groupID := groupIDMap[seedGroup.TempID]
 
 		width := seedGroup.Width
 		if width == 0 {
@@ -227,6 +269,12 @@ func (m *ProjectMigrator) createBlockGroups(ctx context.Context, seedProject *wo
 			}
 		}
 
+		if existingIDs[groupID] {
+			if err := m.blockGroupRepo.Update(ctx, group); err != nil {
+				return nil, fmt.Errorf("failed to update block group %s: %w", seedGroup.Name, err)
+			}
+			continue
+		}
 		if err := m.blockGroupRepo.Create(ctx, group); err != nil {
 			return nil, fmt.Errorf("failed to create block group %s: %w", seedGroup.Name, err)
 		}
@@ -235,14 +283,19 @@ func (m *ProjectMigrator) createBlockGroups(ctx context.Context, seedProject *wo
 	return groupIDMap, nil
 }
 
-// createSteps creates all steps for a project and returns a temp_id -> step_id mapping
-func (m *ProjectMigrator) createSteps(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID, projectID uuid.UUID, groupIDMap map[string]uuid.UUID) (map[string]uuid.UUID, error) {
-	stepIDMap := make(map[string]uuid.UUID)
+// saveSteps creates or updates the steps of a project and returns a temp_id -> step_id mapping.
+// Steps listed in existing keep their ID (see resolveStepIDs) and are updated in place, so
+// versioned runs and resumes that reference them stay valid.
+func (m *ProjectMigrator) saveSteps(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID, projectID uuid.UUID, groupIDMap map[string]uuid.UUID, existing []*domain.Step) (map[string]uuid.UUID, error) {
+	stepIDMap := resolveStepIDs(projectID, seedProject, existing)
+	existingIDs := make(map[uuid.UUID]bool, len(existing))
+	for _, step := range existing {
+		existingIDs[step.ID] = true
+	}
 	now := time.Now().UTC()
 
 	for _, seedStep := range seedProject.Steps {
-		stepID := uuid.New()
-		stepIDMap[seedStep.TempID] = stepID
+		stepID := stepIDMap[seedStep.TempID]
 
 		var blockDefID *uuid.UUID
 
@@ -304,6 +357,12 @@ func (m *ProjectMigrator) createSteps(ctx context.Context, seedProject *workflow
 			UpdatedAt:          now,
 		}
 
+		if existingIDs[stepID] {
+			if err := m.stepRepo.Update(ctx, step); err != nil {
+				return nil, fmt.Errorf("failed to update step %s: %w", seedStep.Name, err)
+			}
+			continue
+		}
 		if err := m.stepRepo.Create(ctx, step); err != nil {
 			return nil, fmt.Errorf("failed to create step %s: %w", seedStep.Name, err)
 		}
@@ -505,8 +564,7 @@ func (m *ProjectMigrator) updateProject(ctx context.Context, existing *domain.Pr
 		return "", fmt.Errorf("failed to update project: %w", err)
 	}
 
-	// Delete existing steps, edges, and block groups, then recreate
-	// This is simpler than trying to diff and update individual items
+	// Edges are recreated; block groups and steps are updated in place so that they keep their IDs
 	existingSteps, err := m.stepRepo.ListByProject(ctx, tenantID, existing.ID)
 	if err != nil {
 		return "", fmt.Errorf("failed to list existing steps: %w", err)
@@ -517,6 +575,14 @@ func (m *ProjectMigrator) updateProject(ctx context.Context, existing *domain.Pr
 		return "", fmt.Errorf("failed to list existing edges: %w", err)
 	}
 
+	var existingGroups []*domain.BlockGroup
+	if m.blockGroupRepo != nil {
+		existingGroups, err = m.blockGroupRepo.ListByProject(ctx, tenantID, existing.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list existing block groups: %w", err)
+		}
+	}
+
 	// Delete edges first (due to foreign key constraints)
 	for _, edge := range existingEdges {
 		if err := m.edgeRepo.Delete(ctx, tenantID, existing.ID, edge.ID); err != nil {
@@ -524,42 +590,110 @@ func (m *ProjectMigrator) updateProject(ctx context.Context, existing *domain.Pr
 		}
 	}
 
-	// Delete steps (before block groups due to foreign key)
-	for _, step := range existingSteps {
+	groupIDMap, err := m.saveBlockGroups(ctx, seedProject, tenantID, existing.ID, existingGroups)
+	if err != nil {
+		return "", fmt.Errorf("failed to save block groups: %w", err)
+	}
+
+	stepIDMap, err := m.saveSteps(ctx, seedProject, tenantID, existing.ID, groupIDMap, existingSteps)
+	if err != nil {
+		return "", fmt.Errorf("failed to save steps: %w", err)
+	}
+
+	// Delete steps removed from the seed (before block groups due to foreign key)
+	for _, step := range removedSteps(existingSteps, stepIDMap) {
 		if err := m.stepRepo.Delete(ctx, tenantID, existing.ID, step.ID); err != nil {
 			return "", fmt.Errorf("failed to delete step: %w", err)
 		}
 	}
 
-	// Delete existing block groups
-	if m.blockGroupRepo != nil {
-		existingGroups, err := m.blockGroupRepo.ListByProject(ctx, tenantID, existing.ID)
-		if err != nil {
-			return "", fmt.Errorf("failed to list existing block groups: %w", err)
+	// Delete block groups removed from the seed
+	keptGroups := make(map[uuid.UUID]bool, len(groupIDMap))
+	for _, id := range groupIDMap {
+		keptGroups[id] = true
+	}
+	for _, group := range existingGroups {
+		if keptGroups[group.ID] {
+			continue
 		}
-		for _, group := range existingGroups {
-			if err := m.blockGroupRepo.Delete(ctx, tenantID, existing.ID, group.ID); err != nil {
-				return "", fmt.Errorf("failed to delete block group: %w", err)
-			}
+		if err := m.blockGroupRepo.Delete(ctx, tenantID, existing.ID, group.ID); err != nil {
+			return "", fmt.Errorf("failed to delete block group: %w", err)
 		}
 	}
 
-	// Recreate block groups, steps, and edges
-	groupIDMap, err := m.createBlockGroups(ctx, seedProject, tenantID, existing.ID)
+	if err := m.createEdgesWithGroupMap(ctx, seedProject, tenantID, existing.ID, stepIDMap, groupIDMap); err != nil {
+		return "", fmt.Errorf("failed to create edges: %w", err)
+	}
+
+	if err := m.saveVersion(ctx, seedProject, tenantID, existing.ID); err != nil {
+		return "", fmt.Errorf("failed to save project version: %w", err)
+	}
+
+	return "updated", nil
+}
+
+// saveVersion stores a version snapshot of the migrated project with the seed's content hash
+func (m *ProjectMigrator) saveVersion(ctx context.Context, seedProject *workflows.SystemWorkflowDefinition, tenantID uuid.UUID, projectID uuid.UUID) error {
+	if m.versionRepo == nil {
+		return nil
+	}
+
+	project, err := m.projectRepo.GetWithStepsAndEdges(ctx, tenantID, projectID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create block groups: %w", err)
+		return err
 	}
+	definition, err := json.Marshal(domain.ProjectDefinition{
+		Name:        project.Name,
+		Description: project.Description,
+		Variables:   project.Variables,
+		Steps:       project.Steps,
+		Edges:       project.Edges,
+		BlockGroups: project.BlockGroups,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Upsert: a content change without a version bump replaces the snapshot of the same version
+	return m.versionRepo.Upsert(ctx, &domain.ProjectVersion{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		Version:     seedProject.Version,
+		Definition:  definition,
+		SavedAt:     time.Now().UTC(),
+		ContentHash: WorkflowContentHash(seedProject),
+	})
+}
 
-	stepIDMap, err := m.createSteps(ctx, seedProject, tenantID, existing.ID, groupIDMap)
+// storedContentHash returns the content hash recorded on the project's current version, if any
+func (m *ProjectMigrator) storedContentHash(ctx context.Context, existing *domain.Project) (string, error) {
+	version, err := m.versionRepo.GetByProjectAndVersion(ctx, existing.ID, existing.Version)
 	if err != nil {
-		return "", fmt.Errorf("failed to create steps: %w", err)
+		if errors.Is(err, domain.ErrProjectVersionNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get project version: %w", err)
+	}
+	if version == nil {
+		return "", nil
 	}
+	return version.ContentHash, nil
+}
 
-	if err := m.createEdgesWithGroupMap(ctx, seedProject, tenantID, existing.ID, stepIDMap, groupIDMap); err != nil {
-		return "", fmt.Errorf("failed to create edges: %w", err)
+// contentChanged reports whether the seed differs from what was last migrated.
+// With a version repository the decision is made by content hash (so steps/edges changes are
+// detected and version-only bumps are not); a project without a recorded hash is updated once
+// to record it. Without a version repository it falls back to comparing project fields.
+func (m *ProjectMigrator) contentChanged(ctx context.Context, existing *domain.Project, seed *workflows.SystemWorkflowDefinition) (bool, error) {
+	if m.versionRepo == nil {
+		return m.hasChanges(existing, seed), nil
 	}
 
-	return "updated", nil
+	stored, err := m.storedContentHash(ctx, existing)
+	if err != nil {
+		return false, err
+	}
+	return stored != WorkflowContentHash(seed), nil
 }
 
 // hasChanges compares existing project with seed definition
@@ -591,6 +725,7 @@ func (m *ProjectMigrator) DryRun(ctx context.Context, registry *workflows.Regist
 		ToCreate:  make([]string, 0),
 		ToUpdate:  make([]ProjectUpdateInfo, 0),
 		Unchanged: make([]string, 0),
+		Warnings:  make([]string, 0),
 	}
 
 	for _, seedProject := range registry.GetAll() {
@@ -608,8 +743,22 @@ func (m *ProjectMigrator) DryRun(ctx context.Context, registry *workflows.Regist
 			return nil, fmt.Errorf("failed to get existing project %s: %w", seedProject.SystemSlug, err)
 		}
 
-		if m.hasChanges(existing, seedProject) {
-			reason := m.describeChanges(existing, seedProject)
+		changed, err := m.contentChanged(ctx, existing, seedProject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare project %s: %w", seedProject.SystemSlug, err)
+		}
+		if changed {
+			reason, err := m.describeContentChanges(ctx, existing, seedProject)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare project %s: %w", seedProject.SystemSlug, err)
+			}
+			warning, err := m.orphanWarning(ctx, existing, seedProject, tenantID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check runs of project %s: %w", seedProject.SystemSlug, err)
+			}
+			if warning != "" {
+				result.Warnings = append(result.Warnings, warning)
+			}
 			result.ToUpdate = append(result.ToUpdate, ProjectUpdateInfo{
 				SystemSlug: seedProject.SystemSlug,
 				OldVersion: existing.Version,
@@ -651,3 +800,23 @@ func (m *ProjectMigrator) describeChanges(existing *domain.Project, seed *workfl
 	}
 	return result
 }
+
+// describeContentChanges describes a content-hash based change for dry runs
+func (m *ProjectMigrator) describeContentChanges(ctx context.Context, existing *domain.Project, seed *workflows.SystemWorkflowDefinition) (string, error) {
+	reason := m.describeChanges(existing, seed)
+	if m.versionRepo == nil {
+		return reason, nil
+	}
+
+	stored, err := m.storedContentHash(ctx, existing)
+	if err != nil {
+		return "", err
+	}
+	if stored == "" {
+		return reason + " (no content hash recorded)", nil
+	}
+	if existing.Version == seed.Version {
+		return reason + ", no version bump", nil
+	}
+	return reason, nil
+}
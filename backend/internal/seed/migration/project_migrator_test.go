@@ -0,0 +1,332 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/internal/seed/workflows"
+)
+
+// stubProjectRepo serves projects by ID; other methods are not used by the comparison
+type stubProjectRepo struct {
+	repository.ProjectRepository
+	projects map[uuid.UUID]*domain.Project
+}
+
+func (r *stubProjectRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error) {
+	if project, ok := r.projects[id]; ok {
+		return project, nil
+	}
+	return nil, domain.ErrProjectNotFound
+}
+
+// stubProjectVersionRepo keeps project versions by project and version number
+type stubProjectVersionRepo struct {
+	repository.ProjectVersionRepository
+	versions map[uuid.UUID]map[int]*domain.ProjectVersion
+}
+
+func (r *stubProjectVersionRepo) GetByProjectAndVersion(ctx context.Context, projectID uuid.UUID, version int) (*domain.ProjectVersion, error) {
+	if v, ok := r.versions[projectID][version]; ok {
+		return v, nil
+	}
+	return nil, domain.ErrProjectVersionNotFound
+}
+
+func seedWorkflow(id uuid.UUID) *workflows.SystemWorkflowDefinition {
+	return &workflows.SystemWorkflowDefinition{
+		ID:         id.String(),
+		SystemSlug: "hash-workflow",
+		Name:       "Hash Workflow",
+		Version:    2,
+		Steps: []workflows.SystemStepDefinition{
+			{TempID: "start", Name: "Start", Type: "start"},
+			{TempID: "llm", Name: "Summarize", Type: "llm", Config: json.RawMessage(`{"prompt": "Summarize {{input}}"}`)},
+		},
+		Edges: []workflows.SystemEdgeDefinition{{SourceTempID: "start", TargetTempID: "llm"}},
+	}
+}
+
+func newHashTestMigrator(seed *workflows.SystemWorkflowDefinition, storedHash string) *ProjectMigrator {
+	projectID := uuid.MustParse(seed.ID)
+	projects := &stubProjectRepo{projects: map[uuid.UUID]*domain.Project{
+		projectID: {ID: projectID, Name: seed.Name, Description: seed.Description, Version: seed.Version},
+	}}
+	versions := &stubProjectVersionRepo{versions: map[uuid.UUID]map[int]*domain.ProjectVersion{}}
+	if storedHash != "" {
+		versions.versions[projectID] = map[int]*domain.ProjectVersion{
+			seed.Version: {ProjectID: projectID, Version: seed.Version, ContentHash: storedHash},
+		}
+	}
+	return NewProjectMigrator(projects, nil, nil).WithVersionRepo(versions)
+}
+
+// compareSeed runs the dry-run comparison of seed against the single existing project
+func compareSeed(t *testing.T, migrator *ProjectMigrator, seed *workflows.SystemWorkflowDefinition) (bool, string) {
+	t.Helper()
+	existing, err := migrator.projectRepo.GetByID(context.Background(), uuid.Nil, uuid.MustParse(seed.ID))
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	changed, err := migrator.contentChanged(context.Background(), existing, seed)
+	if err != nil {
+		t.Fatalf("contentChanged() error = %v", err)
+	}
+	if !changed {
+		return false, ""
+	}
+	reason, err := migrator.describeContentChanges(context.Background(), existing, seed)
+	if err != nil {
+		t.Fatalf("describeContentChanges() error = %v", err)
+	}
+	return true, reason
+}
+
+func TestProjectMigrator_ContentChanged(t *testing.T) {
+	id := uuid.New()
+	migrated := seedWorkflow(id)
+	storedHash := WorkflowContentHash(migrated)
+
+	t.Run("same content is unchanged", func(t *testing.T) {
+		if changed, reason := compareSeed(t, newHashTestMigrator(migrated, storedHash), seedWorkflow(id)); changed {
+			t.Errorf("contentChanged() = true (%s), want false", reason)
+		}
+	})
+
+	t.Run("step change with same version is an update", func(t *testing.T) {
+		changed := seedWorkflow(id)
+		changed.Steps[1].Config = json.RawMessage(`{"prompt": "Translate {{input}}"}`)
+
+		isChanged, reason := compareSeed(t, newHashTestMigrator(migrated, storedHash), changed)
+		if !isChanged {
+			t.Fatal("contentChanged() = false, want true")
+		}
+		if reason != "steps/edges changed, no version bump" {
+			t.Errorf("reason = %q, want steps/edges change without version bump", reason)
+		}
+	})
+
+	t.Run("version bump without content change is unchanged", func(t *testing.T) {
+		// The stored hash belongs to the existing version 2; version 3 has no record yet
+		bumped := seedWorkflow(id)
+		bumped.Version = 3
+		if WorkflowContentHash(bumped) != storedHash {
+			t.Fatal("WorkflowContentHash() must not depend on the version")
+		}
+		if changed, reason := compareSeed(t, newHashTestMigrator(migrated, storedHash), bumped); changed {
+			t.Errorf("contentChanged() = true (%s), want false", reason)
+		}
+	})
+
+	t.Run("missing hash is updated once to record it", func(t *testing.T) {
+		changed, reason := compareSeed(t, newHashTestMigrator(migrated, ""), seedWorkflow(id))
+		if !changed || !contains(reason, "no content hash recorded") {
+			t.Errorf("contentChanged() = %v (%s), want update recording the content hash", changed, reason)
+		}
+	})
+}
+
+func (r *stubProjectRepo) Create(ctx context.Context, project *domain.Project) error {
+	r.projects[project.ID] = project
+	return nil
+}
+
+func (r *stubProjectRepo) Update(ctx context.Context, project *domain.Project) error {
+	r.projects[project.ID] = project
+	return nil
+}
+
+// memStepRepo keeps the steps of all projects in memory
+type memStepRepo struct {
+	repository.StepRepository
+	steps map[uuid.UUID]*domain.Step
+}
+
+func (r *memStepRepo) Create(ctx context.Context, step *domain.Step) error {
+	r.steps[step.ID] = step
+	return nil
+}
+
+func (r *memStepRepo) Update(ctx context.Context, step *domain.Step) error {
+	r.steps[step.ID] = step
+	return nil
+}
+
+func (r *memStepRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.Step, error) {
+	steps := make([]*domain.Step, 0)
+	for _, step := range r.steps {
+		if step.ProjectID == projectID {
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
+func (r *memStepRepo) Delete(ctx context.Context, tenantID, projectID, id uuid.UUID) error {
+	delete(r.steps, id)
+	return nil
+}
+
+// memEdgeRepo keeps the edges of all projects in memory
+type memEdgeRepo struct {
+	repository.EdgeRepository
+	edges map[uuid.UUID]*domain.Edge
+}
+
+func (r *memEdgeRepo) Create(ctx context.Context, edge *domain.Edge) error {
+	r.edges[edge.ID] = edge
+	return nil
+}
+
+func (r *memEdgeRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.Edge, error) {
+	edges := make([]*domain.Edge, 0)
+	for _, edge := range r.edges {
+		if edge.ProjectID == projectID {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
+
+func (r *memEdgeRepo) Delete(ctx context.Context, tenantID, projectID, id uuid.UUID) error {
+	delete(r.edges, id)
+	return nil
+}
+
+// stubRunRepo reports a fixed set of step IDs as referenced by recent runs
+type stubRunRepo struct {
+	repository.RunRepository
+	referenced []uuid.UUID
+}
+
+func (r *stubRunRepo) ListReferencedStepIDs(ctx context.Context, projectID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	return r.referenced, nil
+}
+
+func (r *stubRunRepo) CountConsecutiveFailures(ctx context.Context, tenantID, projectID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func newMemMigrator() (*ProjectMigrator, *memStepRepo) {
+	projects := &stubProjectRepo{projects: map[uuid.UUID]*domain.Project{}}
+	steps := &memStepRepo{steps: map[uuid.UUID]*domain.Step{}}
+	edges := &memEdgeRepo{edges: map[uuid.UUID]*domain.Edge{}}
+	return NewProjectMigrator(projects, steps, edges), steps
+}
+
+// migrateSeed creates or updates the project of the seed and returns the migration warning
+func migrateSeed(t *testing.T, migrator *ProjectMigrator, seed *workflows.SystemWorkflowDefinition) (string, string) {
+	t.Helper()
+	action, warning, err := migrator.upsertProject(context.Background(), seed, uuid.Nil)
+	if err != nil {
+		t.Fatalf("upsertProject() error = %v", err)
+	}
+	return action, warning
+}
+
+// stepIDsByName returns the IDs of the stored steps of a project, keyed by name
+func stepIDsByName(t *testing.T, steps *memStepRepo, projectID uuid.UUID) map[string]uuid.UUID {
+	t.Helper()
+	list, _ := steps.ListByProject(context.Background(), uuid.Nil, projectID)
+	ids := make(map[string]uuid.UUID, len(list))
+	for _, step := range list {
+		ids[step.Name] = step.ID
+	}
+	return ids
+}
+
+func TestProjectMigrator_StableStepIDs(t *testing.T) {
+	t.Run("config change with version bump keeps step IDs", func(t *testing.T) {
+		id := uuid.New()
+		migrator, steps := newMemMigrator()
+		migrateSeed(t, migrator, seedWorkflow(id))
+		before := stepIDsByName(t, steps, id)
+
+		bumped := seedWorkflow(id)
+		bumped.Version = 3
+		bumped.Steps[1].Config = json.RawMessage(`{"prompt": "Translate {{input}}"}`)
+		if action, _ := migrateSeed(t, migrator, bumped); action != "updated" {
+			t.Fatalf("action = %q, want updated", action)
+		}
+
+		after := stepIDsByName(t, steps, id)
+		if len(after) != len(before) {
+			t.Fatalf("steps after migration = %d, want %d", len(after), len(before))
+		}
+		for name, stepID := range before {
+			if after[name] != stepID {
+				t.Errorf("step %s ID = %s, want %s", name, after[name], stepID)
+			}
+		}
+		if got := string(steps.steps[before["Summarize"]].Config); !contains(got, "Translate") {
+			t.Errorf("Summarize config = %s, want updated prompt", got)
+		}
+	})
+
+	t.Run("legacy random step IDs are matched by name", func(t *testing.T) {
+		id := uuid.New()
+		migrator, steps := newMemMigrator()
+		migrateSeed(t, migrator, seedWorkflow(id))
+
+		// Simulate steps created before stable IDs: re-key them with random UUIDs
+		legacy := make(map[string]uuid.UUID)
+		for stepID, step := range steps.steps {
+			delete(steps.steps, stepID)
+			step.ID = uuid.New()
+			steps.steps[step.ID] = step
+			legacy[step.Name] = step.ID
+		}
+
+		bumped := seedWorkflow(id)
+		bumped.Version = 3
+		migrateSeed(t, migrator, bumped)
+
+		after := stepIDsByName(t, steps, id)
+		for name, stepID := range legacy {
+			if after[name] != stepID {
+				t.Errorf("step %s ID = %s, want legacy ID %s", name, after[name], stepID)
+			}
+		}
+	})
+
+	t.Run("removing a step referenced by runs warns", func(t *testing.T) {
+		id := uuid.New()
+		migrator, steps := newMemMigrator()
+		migrateSeed(t, migrator, seedWorkflow(id))
+		summarizeID := stepIDsByName(t, steps, id)["Summarize"]
+		migrator.WithRunRepo(&stubRunRepo{referenced: []uuid.UUID{summarizeID}})
+
+		removed := seedWorkflow(id)
+		removed.Version = 3
+		removed.Steps[1] = workflows.SystemStepDefinition{TempID: "translate", Name: "Translate", Type: "llm"}
+		removed.Edges[0].TargetTempID = "translate"
+		_, warning := migrateSeed(t, migrator, removed)
+
+		if !contains(warning, summarizeID.String()) {
+			t.Errorf("warning = %q, want a warning naming step %s", warning, summarizeID)
+		}
+		if _, ok := steps.steps[summarizeID]; ok {
+			t.Error("removed step still stored")
+		}
+	})
+
+	t.Run("removing an unreferenced step does not warn", func(t *testing.T) {
+		id := uuid.New()
+		migrator, _ := newMemMigrator()
+		migrateSeed(t, migrator, seedWorkflow(id))
+		migrator.WithRunRepo(&stubRunRepo{referenced: []uuid.UUID{uuid.New()}})
+
+		removed := seedWorkflow(id)
+		removed.Version = 3
+		removed.Steps = removed.Steps[:1]
+		removed.Edges = nil
+		if _, warning := migrateSeed(t, migrator, removed); warning != "" {
+			t.Errorf("warning = %q, want none", warning)
+		}
+	})
+}
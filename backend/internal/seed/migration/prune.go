@@ -0,0 +1,238 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/internal/seed/blocks"
+	"github.com/souta/ai-orchestration/internal/seed/workflows"
+)
+
+// PruneTarget is a system block or project that is no longer in the registry
+type PruneTarget struct {
+	ID   uuid.UUID
+	Slug string
+}
+
+// PruneSkip is a system block that is no longer in the registry but is still referenced
+type PruneSkip struct {
+	Slug   string
+	Reason string
+}
+
+// PrunePlan lists what pruning would delete. Blocks are ordered so that children are deleted
+// before their parents.
+type PrunePlan struct {
+	Blocks   []PruneTarget
+	Projects []PruneTarget
+	Skipped  []PruneSkip
+}
+
+// PruneResult tracks what happened during pruning
+type PruneResult struct {
+	Blocks   []string // Slugs of deleted blocks
+	Projects []string // SystemSlugs of deleted projects
+	Skipped  []PruneSkip
+	Errors   []error
+}
+
+// Pruner removes system blocks and projects that were deleted from the seed registries.
+// Only system-owned rows are considered; tenant blocks and projects are never touched.
+type Pruner struct {
+	blockRepo   repository.BlockDefinitionRepository
+	projectRepo repository.ProjectRepository
+}
+
+// NewPruner creates a new pruner
+func NewPruner(
+	blockRepo repository.BlockDefinitionRepository,
+	projectRepo repository.ProjectRepository,
+) *Pruner {
+	return &Pruner{
+		blockRepo:   blockRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// Plan computes what would be pruned without applying it.
+// A nil registry skips that kind (e.g. blocks are not pruned with -projects-only).
+func (p *Pruner) Plan(ctx context.Context, blockRegistry *blocks.Registry, workflowRegistry *workflows.Registry, tenantID uuid.UUID) (*PrunePlan, error) {
+	plan := &PrunePlan{
+		Blocks:   make([]PruneTarget, 0),
+		Projects: make([]PruneTarget, 0),
+		Skipped:  make([]PruneSkip, 0),
+	}
+
+	if workflowRegistry != nil {
+		projects, _, err := p.projectRepo.List(ctx, tenantID, repository.ProjectFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects: %w", err)
+		}
+		plan.Projects = projectsToPrune(projects, workflowRegistry)
+	}
+
+	if blockRegistry != nil {
+		existing, err := p.blockRepo.List(ctx, nil, repository.BlockDefinitionFilter{SystemOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list system blocks: %w", err)
+		}
+
+		candidates := blocksNotInRegistry(existing, blockRegistry)
+		references := make(map[uuid.UUID]*domain.BlockReferences, len(candidates))
+		for _, block := range candidates {
+			refs, err := p.blockRepo.GetReferences(ctx, block.ID, block.Slug)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check references of block %s: %w", block.Slug, err)
+			}
+			references[block.ID] = refs
+		}
+
+		plan.Blocks, plan.Skipped = blocksToPrune(candidates, references, plan.Projects)
+	}
+
+	return plan, nil
+}
+
+// Apply deletes the blocks and projects in the plan
+func (p *Pruner) Apply(ctx context.Context, plan *PrunePlan, tenantID uuid.UUID) *PruneResult {
+	result := &PruneResult{
+		Blocks:   make([]string, 0),
+		Projects: make([]string, 0),
+		Skipped:  plan.Skipped,
+		Errors:   make([]error, 0),
+	}
+
+	// Projects first, so that blocks only they referenced are no longer in use
+	for _, target := range plan.Projects {
+		if err := p.projectRepo.Delete(ctx, tenantID, target.ID); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("project %s: %w", target.Slug, err))
+			continue
+		}
+		result.Projects = append(result.Projects, target.Slug)
+	}
+
+	for _, target := range plan.Blocks {
+		if err := p.blockRepo.DeleteSystem(ctx, target.ID); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("block %s: %w", target.Slug, err))
+			continue
+		}
+		result.Blocks = append(result.Blocks, target.Slug)
+	}
+
+	return result
+}
+
+// projectsToPrune returns the system projects whose slug is no longer in the registry
+func projectsToPrune(projects []*domain.Project, registry *workflows.Registry) []PruneTarget {
+	targets := make([]PruneTarget, 0)
+	for _, project := range projects {
+		if !project.IsSystem || project.SystemSlug == nil {
+			continue
+		}
+		if _, ok := registry.GetBySlug(*project.SystemSlug); ok {
+			continue
+		}
+		targets = append(targets, PruneTarget{ID: project.ID, Slug: *project.SystemSlug})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Slug < targets[j].Slug })
+	return targets
+}
+
+// blocksNotInRegistry returns the system blocks whose slug is no longer in the registry
+func blocksNotInRegistry(existing []*domain.BlockDefinition, registry *blocks.Registry) []*domain.BlockDefinition {
+	candidates := make([]*domain.BlockDefinition, 0)
+	for _, block := range existing {
+		if block.TenantID != nil || !block.IsSystem {
+			continue
+		}
+		if _, ok := registry.GetBySlug(block.Slug); ok {
+			continue
+		}
+		candidates = append(candidates, block)
+	}
+	return candidates
+}
+
+// blocksToPrune decides which candidate blocks can be deleted. A block is skipped while a
+// project that is not itself being pruned uses it, or while a block that is not being pruned
+// inherits from it. The returned targets list children before their parents.
+func blocksToPrune(candidates []*domain.BlockDefinition, references map[uuid.UUID]*domain.BlockReferences, prunedProjects []PruneTarget) ([]PruneTarget, []PruneSkip) {
+	projectPruned := make(map[uuid.UUID]bool, len(prunedProjects))
+	for _, project := range prunedProjects {
+		projectPruned[project.ID] = true
+	}
+
+	// Skip blocks used by remaining projects
+	pruning := make(map[uuid.UUID]*domain.BlockDefinition, len(candidates))
+	skipped := make([]PruneSkip, 0)
+	for _, block := range candidates {
+		var inUse int
+		if refs := references[block.ID]; refs != nil {
+			for _, projectID := range refs.ProjectIDs {
+				if !projectPruned[projectID] {
+					inUse++
+				}
+			}
+		}
+		if inUse > 0 {
+			skipped = append(skipped, PruneSkip{Slug: block.Slug, Reason: fmt.Sprintf("used by %d project(s)", inUse)})
+			continue
+		}
+		pruning[block.ID] = block
+	}
+
+	// Skip blocks inherited by kept blocks, until no more blocks are skipped
+	for changed := true; changed; {
+		changed = false
+		for id, block := range pruning {
+			for _, childID := range childBlockIDs(references, id) {
+				if _, ok := pruning[childID]; !ok {
+					skipped = append(skipped, PruneSkip{Slug: block.Slug, Reason: "inherited by a block that is kept"})
+					delete(pruning, id)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// Order children before parents: repeatedly take blocks none of whose children remain
+	targets := make([]PruneTarget, 0, len(pruning))
+	for len(pruning) > 0 {
+		ready := make([]*domain.BlockDefinition, 0)
+		for id, block := range pruning {
+			hasChild := false
+			for _, childID := range childBlockIDs(references, id) {
+				if _, ok := pruning[childID]; ok {
+					hasChild = true
+					break
+				}
+			}
+			if !hasChild {
+				ready = append(ready, block)
+			}
+		}
+		if len(ready) == 0 {
+			break // Inheritance cycle; cannot happen with a valid catalog
+		}
+		sort.Slice(ready, func(i, j int) bool { return ready[i].Slug < ready[j].Slug })
+		for _, block := range ready {
+			targets = append(targets, PruneTarget{ID: block.ID, Slug: block.Slug})
+			delete(pruning, block.ID)
+		}
+	}
+
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Slug < skipped[j].Slug })
+	return targets, skipped
+}
+
+func childBlockIDs(references map[uuid.UUID]*domain.BlockReferences, id uuid.UUID) []uuid.UUID {
+	if refs := references[id]; refs != nil {
+		return refs.ChildBlockIDs
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/seed/blocks"
+	"github.com/souta/ai-orchestration/internal/seed/workflows"
+)
+
+func systemBlock(slug string) *domain.BlockDefinition {
+	return &domain.BlockDefinition{ID: uuid.New(), Slug: slug, IsSystem: true}
+}
+
+func targetSlugs(targets []PruneTarget) []string {
+	slugs := make([]string, len(targets))
+	for i, target := range targets {
+		slugs[i] = target.Slug
+	}
+	return slugs
+}
+
+func TestProjectsToPrune(t *testing.T) {
+	registry := workflows.NewRegistry()
+	kept := registry.GetAll()[0].SystemSlug
+	removed := "removed-workflow"
+
+	projects := []*domain.Project{
+		{ID: uuid.New(), IsSystem: true, SystemSlug: &kept},
+		{ID: uuid.New(), IsSystem: true, SystemSlug: &removed},
+		{ID: uuid.New(), Name: "tenant project"}, // Tenant data is never pruned
+	}
+
+	targets := projectsToPrune(projects, registry)
+	if len(targets) != 1 || targets[0].Slug != removed || targets[0].ID != projects[1].ID {
+		t.Errorf("projectsToPrune() = %+v, want only %s", targets, removed)
+	}
+}
+
+func TestBlocksNotInRegistry(t *testing.T) {
+	registry := blocks.NewRegistry()
+	seeded := registry.GetAll()[0].Slug
+	tenantID := uuid.New()
+
+	tenantBlock := systemBlock("tenant-custom")
+	tenantBlock.TenantID = &tenantID
+	removed := systemBlock("removed-block")
+
+	candidates := blocksNotInRegistry([]*domain.BlockDefinition{systemBlock(seeded), tenantBlock, removed}, registry)
+	if len(candidates) != 1 || candidates[0] != removed {
+		t.Errorf("blocksNotInRegistry() = %v, want only removed-block", candidates)
+	}
+}
+
+func TestBlocksToPrune_SkipsReferencedBlocks(t *testing.T) {
+	unused := systemBlock("unused")
+	used := systemBlock("used")
+	usedByPruned := systemBlock("used-by-pruned-project")
+
+	prunedProject := PruneTarget{ID: uuid.New(), Slug: "removed-workflow"}
+	references := map[uuid.UUID]*domain.BlockReferences{
+		unused.ID:       {},
+		used.ID:         {ProjectIDs: []uuid.UUID{uuid.New(), prunedProject.ID}},
+		usedByPruned.ID: {ProjectIDs: []uuid.UUID{prunedProject.ID}},
+	}
+
+	targets, skipped := blocksToPrune([]*domain.BlockDefinition{unused, used, usedByPruned}, references, []PruneTarget{prunedProject})
+
+	if got := targetSlugs(targets); len(got) != 2 || got[0] != "unused" || got[1] != "used-by-pruned-project" {
+		t.Errorf("targets = %v, want [unused used-by-pruned-project]", got)
+	}
+	if len(skipped) != 1 || skipped[0].Slug != "used" || skipped[0].Reason != "used by 1 project(s)" {
+		t.Errorf("skipped = %+v, want used skipped for 1 project", skipped)
+	}
+}
+
+func TestBlocksToPrune_Inheritance(t *testing.T) {
+	parent := systemBlock("parent")
+	child := systemBlock("child")
+	grandchild := systemBlock("grandchild")
+	keptParent := systemBlock("kept-parent")
+	keptChildID := uuid.New() // Still seeded (or a tenant block), so not a candidate
+
+	references := map[uuid.UUID]*domain.BlockReferences{
+		parent.ID:     {ChildBlockIDs: []uuid.UUID{child.ID}},
+		child.ID:      {ChildBlockIDs: []uuid.UUID{grandchild.ID}},
+		grandchild.ID: {},
+		keptParent.ID: {ChildBlockIDs: []uuid.UUID{keptChildID}},
+	}
+
+	targets, skipped := blocksToPrune([]*domain.BlockDefinition{parent, keptParent, child, grandchild}, references, nil)
+
+	if got := targetSlugs(targets); len(got) != 3 || got[0] != "grandchild" || got[1] != "child" || got[2] != "parent" {
+		t.Errorf("targets = %v, want children before parents", got)
+	}
+	if len(skipped) != 1 || skipped[0].Slug != "kept-parent" {
+		t.Errorf("skipped = %+v, want kept-parent", skipped)
+	}
+}
+
+func TestBlocksToPrune_ReferencedChildKeepsParent(t *testing.T) {
+	parent := systemBlock("parent")
+	child := systemBlock("child")
+
+	references := map[uuid.UUID]*domain.BlockReferences{
+		parent.ID: {ChildBlockIDs: []uuid.UUID{child.ID}},
+		child.ID:  {ProjectIDs: []uuid.UUID{uuid.New()}},
+	}
+
+	targets, skipped := blocksToPrune([]*domain.BlockDefinition{parent, child}, references, nil)
+
+	if len(targets) != 0 {
+		t.Errorf("targets = %v, want none", targetSlugs(targets))
+	}
+	if len(skipped) != 2 || skipped[0].Slug != "child" || skipped[1].Slug != "parent" {
+		t.Errorf("skipped = %+v, want child and parent", skipped)
+	}
+}
@@ -0,0 +1,164 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/seed/workflows"
+)
+
+// orphanCheckWindow is how far back runs are checked for references to removed steps
+const orphanCheckWindow = 7 * 24 * time.Hour
+
+// stableStepID returns the ID a seed step keeps across migrations, derived from its TempID
+func stableStepID(projectID uuid.UUID, tempID string) uuid.UUID {
+	return uuid.NewSHA1(projectID, []byte("step:"+tempID))
+}
+
+// stableGroupID returns the ID a seed block group keeps across migrations, derived from its TempID
+func stableGroupID(projectID uuid.UUID, tempID string) uuid.UUID {
+	return uuid.NewSHA1(projectID, []byte("group:"+tempID))
+}
+
+// identity is a stored row that a seed entry may match
+type identity struct {
+	ID   uuid.UUID
+	Name string
+}
+
+// matchIdentities picks the ID of each seed entry. An entry keeps an existing row stored with
+// its stable ID. Rows created before stable IDs were introduced (random UUIDs) are matched by
+// name, once each, so that the first migration after the upgrade does not replace them either.
+// Entries without a match get their stable ID.
+func matchIdentities(stableIDs []uuid.UUID, names []string, existing []identity) []uuid.UUID {
+	isStable := make(map[uuid.UUID]bool, len(stableIDs))
+	for _, id := range stableIDs {
+		isStable[id] = true
+	}
+
+	stored := make(map[uuid.UUID]bool, len(existing))
+	legacyByName := make(map[string][]uuid.UUID)
+	for _, row := range existing {
+		stored[row.ID] = true
+		if !isStable[row.ID] {
+			legacyByName[row.Name] = append(legacyByName[row.Name], row.ID)
+		}
+	}
+
+	ids := make([]uuid.UUID, len(stableIDs))
+	for i, id := range stableIDs {
+		if stored[id] {
+			ids[i] = id
+			continue
+		}
+		if legacy := legacyByName[names[i]]; len(legacy) > 0 {
+			ids[i] = legacy[0]
+			legacyByName[names[i]] = legacy[1:]
+			continue
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+// resolveStepIDs maps the seed's step TempIDs to the step IDs they keep in the project
+func resolveStepIDs(projectID uuid.UUID, seed *workflows.SystemWorkflowDefinition, existing []*domain.Step) map[string]uuid.UUID {
+	stableIDs := make([]uuid.UUID, len(seed.Steps))
+	names := make([]string, len(seed.Steps))
+	for i, step := range seed.Steps {
+		stableIDs[i] = stableStepID(projectID, step.TempID)
+		names[i] = step.Name
+	}
+	rows := make([]identity, len(existing))
+	for i, step := range existing {
+		rows[i] = identity{ID: step.ID, Name: step.Name}
+	}
+
+	ids := matchIdentities(stableIDs, names, rows)
+	result := make(map[string]uuid.UUID, len(ids))
+	for i, step := range seed.Steps {
+		result[step.TempID] = ids[i]
+	}
+	return result
+}
+
+// resolveGroupIDs maps the seed's block group TempIDs to the group IDs they keep in the project
+func resolveGroupIDs(projectID uuid.UUID, seed *workflows.SystemWorkflowDefinition, existing []*domain.BlockGroup) map[string]uuid.UUID {
+	stableIDs := make([]uuid.UUID, len(seed.BlockGroups))
+	names := make([]string, len(seed.BlockGroups))
+	for i, group := range seed.BlockGroups {
+		stableIDs[i] = stableGroupID(projectID, group.TempID)
+		names[i] = group.Name
+	}
+	rows := make([]identity, len(existing))
+	for i, group := range existing {
+		rows[i] = identity{ID: group.ID, Name: group.Name}
+	}
+
+	ids := matchIdentities(stableIDs, names, rows)
+	result := make(map[string]uuid.UUID, len(ids))
+	for i, group := range seed.BlockGroups {
+		result[group.TempID] = ids[i]
+	}
+	return result
+}
+
+// removedSteps returns the existing steps that no seed step keeps
+func removedSteps(existing []*domain.Step, stepIDMap map[string]uuid.UUID) []*domain.Step {
+	kept := make(map[uuid.UUID]bool, len(stepIDMap))
+	for _, id := range stepIDMap {
+		kept[id] = true
+	}
+	removed := make([]*domain.Step, 0)
+	for _, step := range existing {
+		if !kept[step.ID] {
+			removed = append(removed, step)
+		}
+	}
+	return removed
+}
+
+// orphanWarning returns a warning when migrating the project to the seed would remove steps
+// that recent runs reference (their versioned runs and resumes would no longer find them).
+// It returns "" when nothing would be orphaned or no run repository is configured.
+func (m *ProjectMigrator) orphanWarning(ctx context.Context, existing *domain.Project, seed *workflows.SystemWorkflowDefinition, tenantID uuid.UUID) (string, error) {
+	if m.runRepo == nil {
+		return "", nil
+	}
+
+	steps, err := m.stepRepo.ListByProject(ctx, tenantID, existing.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list existing steps: %w", err)
+	}
+	removed := removedSteps(steps, resolveStepIDs(existing.ID, seed, steps))
+	if len(removed) == 0 {
+		return "", nil
+	}
+
+	referenced, err := m.runRepo.ListReferencedStepIDs(ctx, existing.ID, time.Now().Add(-orphanCheckWindow))
+	if err != nil {
+		return "", fmt.Errorf("failed to list step IDs referenced by runs: %w", err)
+	}
+	isReferenced := make(map[uuid.UUID]bool, len(referenced))
+	for _, id := range referenced {
+		isReferenced[id] = true
+	}
+
+	orphaned := make([]string, 0)
+	for _, step := range removed {
+		if isReferenced[step.ID] {
+			orphaned = append(orphaned, fmt.Sprintf("%s (%s)", step.Name, step.ID))
+		}
+	}
+	if len(orphaned) == 0 {
+		return "", nil
+	}
+	sort.Strings(orphaned)
+	return fmt.Sprintf("project %s: migration removes %d step(s) referenced by runs in the last %d days: %s",
+		seed.SystemSlug, len(orphaned), int(orphanCheckWindow.Hours()/24), strings.Join(orphaned, ", ")), nil
+}
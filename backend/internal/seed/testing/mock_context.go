@@ -147,6 +147,11 @@ func (m *MockEmbeddingService) Embed(provider, model string, texts []string) (*s
 	}, nil
 }
 
+// EmbedBatch returns the same result as Embed for all texts in one call
+func (m *MockEmbeddingService) EmbedBatch(provider, model string, texts []string, batchSize int) (*sandbox.EmbeddingResult, error) {
+	return m.Embed(provider, model, texts)
+}
+
 // MockVectorService mocks the Vector service
 type MockVectorService struct {
 	UpsertResponse       *sandbox.UpsertResult
@@ -216,6 +221,17 @@ func (m *MockVectorService) ListCollections() ([]sandbox.CollectionInfo, error)
 	}, nil
 }
 
+func (m *MockVectorService) CreateCollection(name string, opts *sandbox.CreateCollectionOptions) (*sandbox.CollectionInfo, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return &sandbox.CollectionInfo{Name: name, Dimension: 1536, Metric: sandbox.VectorMetricCosine}, nil
+}
+
+func (m *MockVectorService) DeleteCollection(name string) error {
+	return m.Error
+}
+
 // MockBlocksService mocks the Blocks service
 type MockBlocksService struct {
 	ListResponse          []map[string]interface{}
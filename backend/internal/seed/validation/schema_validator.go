@@ -2,7 +2,12 @@ package validation
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/souta/ai-orchestration/internal/domain"
 )
 
 // SchemaValidator validates JSON schemas
@@ -104,6 +109,56 @@ func (v *SchemaValidator) ValidateSchema(schema json.RawMessage) error {
 	return nil
 }
 
+// ValidateData checks that JSON data matches an object schema: required properties are present
+// and property types match. Schemas that are not object schemas with properties accept any data.
+func (v *SchemaValidator) ValidateData(data json.RawMessage, schema json.RawMessage) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("invalid JSON")
+	}
+	err := domain.ValidateInputSchema(data, schema)
+	var validationErrs *domain.InputValidationErrors
+	if errors.As(err, &validationErrs) {
+		messages := make([]string, len(validationErrs.Errors))
+		for i, e := range validationErrs.Errors {
+			messages[i] = e.Message
+		}
+		sort.Strings(messages)
+		return fmt.Errorf("does not match schema: %s", strings.Join(messages, "; "))
+	}
+	return err
+}
+
+// ValidateDeclaredProperties checks that every property of a JSON object is declared in an object
+// schema, unless the schema sets additionalProperties to true or declares no properties
+func (v *SchemaValidator) ValidateDeclaredProperties(data json.RawMessage, schema json.RawMessage) error {
+	var schemaMap struct {
+		Type                 string                     `json:"type"`
+		Properties           map[string]json.RawMessage `json:"properties"`
+		AdditionalProperties interface{}                `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(schema, &schemaMap); err != nil || schemaMap.Type != "object" || len(schemaMap.Properties) == 0 {
+		return nil
+	}
+	if allowed, ok := schemaMap.AdditionalProperties.(bool); ok && allowed {
+		return nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+	var unknown []string
+	for key := range values {
+		if _, ok := schemaMap.Properties[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("properties not declared in the schema: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
 // ValidateJSONArray checks if a JSON array is valid
 func (v *SchemaValidator) ValidateJSONArray(data json.RawMessage) error {
 	if len(data) == 0 || string(data) == "null" {
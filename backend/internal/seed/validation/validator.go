@@ -78,6 +78,9 @@ func (v *BlockValidator) ValidateBlock(block *blocks.SystemBlockDefinition) []Va
 		}
 	}
 
+	// Validate examples against the config and output schemas
+	errors = append(errors, v.validateExamples(block)...)
+
 	// Validate error codes JSON
 	if len(block.ErrorCodes) > 0 {
 		errorCodesJSON, err := json.Marshal(block.ErrorCodes)
@@ -98,6 +101,57 @@ func (v *BlockValidator) ValidateBlock(block *blocks.SystemBlockDefinition) []Va
 	return errors
 }
 
+// validateExamples checks that each example has a name and an output, that its config merged over
+// the block's config defaults matches the config schema and sets only declared fields, and that
+// its output matches the output schema
+func (v *BlockValidator) validateExamples(block *blocks.SystemBlockDefinition) []ValidationError {
+	var errors []ValidationError
+	for i, example := range block.Examples {
+		field := fmt.Sprintf("examples[%d]", i)
+		if example.Name.EN == "" && example.Name.JA == "" {
+			errors = append(errors, ValidationError{block.Slug, field + ".name", "example name is required"})
+		}
+
+		config, err := mergeJSONObjects(block.ConfigDefaults, example.Config)
+		if err != nil {
+			errors = append(errors, ValidationError{block.Slug, field + ".config", err.Error()})
+		} else if err := v.schemaValidator.ValidateData(config, block.ConfigSchema.EN); err != nil {
+			errors = append(errors, ValidationError{block.Slug, field + ".config", err.Error()})
+		} else if err := v.schemaValidator.ValidateDeclaredProperties(example.Config, block.ConfigSchema.EN); err != nil {
+			errors = append(errors, ValidationError{block.Slug, field + ".config", err.Error()})
+		}
+
+		if len(example.Input) > 0 && !json.Valid(example.Input) {
+			errors = append(errors, ValidationError{block.Slug, field + ".input", "input is not valid JSON"})
+		}
+
+		if len(example.Output) == 0 || string(example.Output) == "null" {
+			errors = append(errors, ValidationError{block.Slug, field + ".output", "example output is required"})
+		} else if err := v.schemaValidator.ValidateData(example.Output, block.OutputSchema); err != nil {
+			errors = append(errors, ValidationError{block.Slug, field + ".output", err.Error()})
+		}
+	}
+	return errors
+}
+
+// mergeJSONObjects returns the keys of override set over base. Both must be JSON objects or empty.
+func mergeJSONObjects(base, override json.RawMessage) (json.RawMessage, error) {
+	merged := make(map[string]interface{})
+	for _, raw := range []json.RawMessage{base, override} {
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+		var values map[string]interface{}
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("must be a JSON object: %w", err)
+		}
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
 // ValidateAll validates all blocks in a registry
 func (v *BlockValidator) ValidateAll(registry *blocks.Registry) []ValidationError {
 	var allErrors []ValidationError
@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/seed/blocks"
+)
+
+func exampleBlock(examples ...domain.LocalizedBlockExample) *blocks.SystemBlockDefinition {
+	schema := `{
+		"type": "object",
+		"required": ["model", "prompt"],
+		"properties": {
+			"model": {"type": "string"},
+			"prompt": {"type": "string"},
+			"temperature": {"type": "number"}
+		}
+	}`
+	return &blocks.SystemBlockDefinition{
+		Slug:           "example-test",
+		Version:        1,
+		Name:           blocks.LText("Example Test", "例のテスト"),
+		Category:       domain.BlockCategoryAI,
+		Code:           "return { text: input.text };",
+		ConfigSchema:   blocks.LSchema(schema, schema),
+		ConfigDefaults: json.RawMessage(`{"model": "gpt-4o-mini"}`),
+		OutputSchema:   json.RawMessage(`{"type": "object", "required": ["text"], "properties": {"text": {"type": "string"}}}`),
+		Examples:       examples,
+	}
+}
+
+func TestBlockValidator_ValidateBlock_Examples(t *testing.T) {
+	tests := []struct {
+		name      string
+		example   domain.LocalizedBlockExample
+		wantField string
+		wantError string
+	}{
+		{
+			name:    "valid example with a required field from config defaults",
+			example: blocks.LExample("Greeting", "挨拶", `{"prompt": "Say hi"}`, `{"name": "Aiko"}`, `{"text": "Hi Aiko"}`),
+		},
+		{
+			name:      "missing required config field",
+			example:   blocks.LExample("No prompt", "プロンプトなし", `{"temperature": 0.2}`, "", `{"text": "Hi"}`),
+			wantField: "examples[0].config",
+			wantError: "prompt is required",
+		},
+		{
+			name:      "config field of the wrong type",
+			example:   blocks.LExample("Bad type", "型の誤り", `{"prompt": "Say hi", "temperature": "warm"}`, "", `{"text": "Hi"}`),
+			wantField: "examples[0].config",
+			wantError: "temperature must be of type number",
+		},
+		{
+			name:      "undeclared config field",
+			example:   blocks.LExample("Typo", "タイプミス", `{"prompt": "Say hi", "temprature": 0.2}`, "", `{"text": "Hi"}`),
+			wantField: "examples[0].config",
+			wantError: "temprature",
+		},
+		{
+			name:      "output not matching the output schema",
+			example:   blocks.LExample("Bad output", "誤った出力", `{"prompt": "Say hi"}`, "", `{"text": 42}`),
+			wantField: "examples[0].output",
+			wantError: "text must be of type string",
+		},
+		{
+			name:      "missing output",
+			example:   blocks.LExample("No output", "出力なし", `{"prompt": "Say hi"}`, "", ""),
+			wantField: "examples[0].output",
+			wantError: "example output is required",
+		},
+		{
+			name:      "invalid input JSON",
+			example:   blocks.LExample("Bad input", "誤った入力", `{"prompt": "Say hi"}`, `{"name":`, `{"text": "Hi"}`),
+			wantField: "examples[0].input",
+			wantError: "not valid JSON",
+		},
+		{
+			name:      "missing name",
+			example:   blocks.LExample("", "", `{"prompt": "Say hi"}`, "", `{"text": "Hi"}`),
+			wantField: "examples[0].name",
+			wantError: "example name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewBlockValidator().ValidateBlock(exampleBlock(tt.example))
+			if tt.wantField == "" {
+				if len(errs) != 0 {
+					t.Fatalf("ValidateBlock() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("ValidateBlock() = %v, want one error", errs)
+			}
+			if errs[0].Field != tt.wantField || !strings.Contains(errs[0].Message, tt.wantError) {
+				t.Errorf("ValidateBlock() = %v, want %s error containing %q", errs[0], tt.wantField, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_ValidateDeclaredProperties_AdditionalProperties(t *testing.T) {
+	schema := json.RawMessage(`{"type": "object", "additionalProperties": true, "properties": {"url": {"type": "string"}}}`)
+	if err := NewSchemaValidator().ValidateDeclaredProperties(json.RawMessage(`{"url": "https://example.com", "extra": 1}`), schema); err != nil {
+		t.Errorf("ValidateDeclaredProperties() error = %v, want extra properties allowed", err)
+	}
+}
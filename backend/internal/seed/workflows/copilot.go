@@ -1,6 +1,13 @@
 package workflows
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
 
 func (r *Registry) registerCopilotWorkflows() {
 	r.register(CopilotWorkflow())
@@ -35,7 +42,7 @@ func CopilotWorkflow() *SystemWorkflowDefinition {
 		SystemSlug:  "copilot",
 		Name:        "Copilot AI Assistant",
 		Description: "AI assistant for workflow building and platform guidance",
-		Version:     37,
+		Version:     38,
 		IsSystem:    true,
 		Steps: []SystemStepDefinition{
 			// ============================
@@ -335,7 +342,7 @@ func CopilotWorkflow() *SystemWorkflowDefinition {
 				BlockGroupTempID: "copilot_agent_group",
 				Config: json.RawMessage(`{
 					"code": "if (!input.slug) return { error: 'slug is required' }; const block = ctx.blocks.getWithSchema(input.slug); if (!block) return { error: 'Block not found: ' + input.slug }; return block;",
-					"description": "Get the detailed configuration schema for a specific block",
+					"description": "Get the detailed configuration schema for a specific block, including examples of config, input and expected output when available",
 					"input_schema": {
 						"type": "object",
 						"required": ["slug"],
@@ -536,7 +543,7 @@ func CopilotWorkflow() *SystemWorkflowDefinition {
 				PositionY:        540,
 				BlockGroupTempID: "copilot_agent_group",
 				Config: json.RawMessage(`{
-					"code": "if (!input.query) return { error: 'query is required' }; if (!ctx.search || !ctx.search.isConfigured()) return { error: 'Web search is not configured (TAVILY_API_KEY not set). Use fetch_url with known documentation URLs instead.' }; try { const results = ctx.search.search(input.query, input.num_results || 5); return { results: results }; } catch(e) { return { error: 'Search failed: ' + e.message }; }",
+					"code": "if (!input.query) return { error: 'query is required' }; if (!ctx.search || !ctx.search.isConfigured()) return { error: 'Web search is not configured (no search provider API key is set). Use fetch_url with known documentation URLs instead.' }; try { const results = ctx.search.search(input.query, input.num_results || 5); return { results: results }; } catch(e) { return { error: 'Search failed: ' + e.message }; }",
 					"description": "Search the web using Tavily API. Use this to find official API documentation URLs for services that don't have preset blocks.",
 					"input_schema": {
 						"type": "object",
@@ -599,7 +606,7 @@ func CopilotWorkflow() *SystemWorkflowDefinition {
 				PositionY:        640,
 				BlockGroupTempID: "copilot_agent_group",
 				Config: json.RawMessage(`{
-					"code": "const wfId = ctx.targetProjectId; if (!wfId) return { error: 'No target project' }; if (!input.trigger_type) return { error: 'trigger_type is required (schedule, webhook, manual, slack_event)' }; const wf = ctx.workflows.get(wfId); if (!wf) return { error: 'Workflow not found' }; const steps = wf.steps || []; const startStep = steps.find(s => s.type === 'start'); if (!startStep) return { error: 'No start step found. Create a workflow structure first.' }; const config = {}; if (input.trigger_type === 'schedule') { config.schedule = input.schedule || '0 9 * * *'; config.timezone = input.timezone || 'Asia/Tokyo'; } else if (input.trigger_type === 'webhook') { config.method = input.method || 'POST'; } const updated = ctx.steps.update(startStep.id, { trigger_type: input.trigger_type, trigger_config: config }); return { success: true, stepId: startStep.id, triggerType: input.trigger_type, config: config };",
+					"code": "const wfId = ctx.targetProjectId; if (!wfId) return { error: 'No target project' }; if (!input.trigger_type) return { error: 'trigger_type is required (schedule, webhook, manual, slack_event)' }; const wf = ctx.workflows.get(wfId); if (!wf) return { error: 'Workflow not found' }; const steps = wf.steps || []; const startStep = steps.find(s => s.type === 'start'); if (!startStep) return { error: 'No start step found. Create a workflow structure first.' }; const config = {}; if (input.trigger_type === 'schedule') { config.schedule = input.schedule || '0 9 * * *'; config.timezone = input.timezone || ctx.defaultTimezone || 'UTC'; } else if (input.trigger_type === 'webhook') { config.method = input.method || 'POST'; } const updated = ctx.steps.update(startStep.id, { trigger_type: input.trigger_type, trigger_config: config }); return { success: true, stepId: startStep.id, triggerType: input.trigger_type, config: config };",
 					"description": "Configure the workflow trigger (schedule, webhook, manual, or slack_event). Updates the start step's trigger configuration.",
 					"input_schema": {
 						"type": "object",
@@ -607,7 +614,7 @@ func CopilotWorkflow() *SystemWorkflowDefinition {
 						"properties": {
 							"trigger_type": {"type": "string", "enum": ["schedule", "webhook", "manual", "slack_event"], "description": "Type of trigger"},
 							"schedule": {"type": "string", "description": "Cron expression for schedule trigger (e.g., '0 9 * * *' for daily 9am)"},
-							"timezone": {"type": "string", "description": "Timezone for schedule (default: Asia/Tokyo)"},
+							"timezone": {"type": "string", "description": "Timezone for schedule (default: the deployment default timezone)"},
 							"method": {"type": "string", "description": "HTTP method for webhook trigger (default: POST)"}
 						}
 					}
@@ -999,7 +1006,7 @@ add_step(name="処理", type="http", from="トリガー")  // ← トリガー
 
 ` + "```" + `
 // Step 1: Create trigger (no 'from' needed)
-add_step(name="スケジュール実行", type="schedule_trigger", config={schedule: "0 9 * * *", timezone: "Asia/Tokyo"})
+add_step(name="スケジュール実行", type="schedule_trigger", config={schedule: "0 9 * * *"})
 → Returns: {step_id: "abc-123", step_created: true}
 
 // Step 2: HTTP step connected to trigger
@@ -1162,8 +1169,9 @@ Use this reference for fast access to common blocks. For detailed configuration,
 When creating steps:
 1. Call get_block_schema(slug) to get the config schema
 2. Read required fields and defaults from the response
-3. Generate config based on the schema
-4. Include all required fields in the step config
+3. If the response has examples, start from the example closest to the user's intent: copy its config and adapt the values. The example input and output show what the previous step must provide and what later steps can reference
+4. Generate config based on the schema
+5. Include all required fields in the step config
 
 ### Two-Step Pattern (MANDATORY for configured steps)
 
@@ -1228,7 +1236,7 @@ add_step(name="失敗処理", type="log", from="条件分岐", from_port="false"
 ### Block Discovery & Search Tools
 - **search_blocks**: Semantic search for blocks by natural language description (USE THIS when unsure which block to use!)
 - **list_blocks**: Get all available blocks with basic info
-- **get_block_schema**: Get detailed configuration schema for a specific block (CALL THIS FIRST before creating steps!)
+- **get_block_schema**: Get detailed configuration schema for a specific block, with example config, input and output when the block has them (CALL THIS FIRST before creating steps!)
 
 ### Workflow Tools
 - **list_workflows**: List user's workflows
@@ -1249,7 +1257,7 @@ add_step(name="失敗処理", type="log", from="条件分岐", from_port="false"
 - **get_relevant_examples**: Get workflow examples based on intent and keywords (use for reference when creating workflows)
 
 ### External Documentation Tools
-- **web_search**: Search the web for API documentation (requires TAVILY_API_KEY)
+- **web_search**: Search the web for API documentation (requires a configured search provider: Tavily, Bing, Google or SerpAPI)
 - **fetch_url**: Fetch content from a URL to read API documentation
 
 ## Recommended Flow for Unknown Block Types
@@ -1610,10 +1618,11 @@ Example:
 // ============================================================================
 
 // fixBlockTypeToolConfig returns the configuration for the fix_block_type tool
-// This migrates the hardcoded findSimilarBlockType() from copilot_autofix.go
+// This migrates the hardcoded findSimilarBlockType() from copilot_autofix.go.
+// The alias table comes from domain.BlockTypeAliases so the tool and step type validation agree.
 func fixBlockTypeToolConfig() string {
 	return `{
-		"code": "if (!input.invalid_type) return { error: 'invalid_type is required' }; const invalidLower = input.invalid_type.toLowerCase(); const mappings = { 'trigger': 'manual_trigger', 'start': 'manual_trigger', 'begin': 'manual_trigger', 'ai': 'llm', 'gpt': 'llm', 'openai': 'llm', 'claude': 'llm', 'anthropic': 'llm', 'if': 'condition', 'branch': 'condition', 'conditional': 'condition', 'case': 'switch', 'delay': 'delay', 'sleep': 'delay', 'timer': 'delay', 'api': 'http', 'request': 'http', 'cron': 'schedule_trigger', 'schedule': 'schedule_trigger', 'scheduled': 'schedule_trigger', 'debug': 'log', 'print': 'log', 'console': 'log', 'parallel': 'map', 'foreach': 'map', 'loop': 'loop', 'merge': 'join', 'aggregate': 'aggregate', 'collect': 'join', 'human': 'human-in-loop', 'approval': 'human-in-loop', 'human_approval': 'human-in-loop' }; if (mappings[invalidLower]) { const mapped = mappings[invalidLower]; const block = ctx.blocks.getWithSchema(mapped); if (block) return { fixed: true, original_type: input.invalid_type, suggested_type: mapped, block_name: block.name, block_description: block.description }; } const blocks = ctx.blocks.list(); for (const block of blocks) { const slugLower = block.slug.toLowerCase(); if (slugLower.includes(invalidLower) || invalidLower.includes(slugLower)) { return { fixed: true, original_type: input.invalid_type, suggested_type: block.slug, block_name: block.name, block_description: block.description }; } } return { fixed: false, original_type: input.invalid_type, error: 'No similar block type found', available_types: blocks.slice(0, 20).map(b => b.slug) };",
+		"code": "if (!input.invalid_type) return { error: 'invalid_type is required' }; const invalidLower = input.invalid_type.toLowerCase(); const mappings = ` + blockTypeAliasesJS() + `; if (mappings[invalidLower]) { const mapped = mappings[invalidLower]; const block = ctx.blocks.getWithSchema(mapped); if (block) return { fixed: true, original_type: input.invalid_type, suggested_type: mapped, block_name: block.name, block_description: block.description }; } const blocks = ctx.blocks.list(); for (const block of blocks) { const slugLower = block.slug.toLowerCase(); if (slugLower.includes(invalidLower) || invalidLower.includes(slugLower)) { return { fixed: true, original_type: input.invalid_type, suggested_type: block.slug, block_name: block.name, block_description: block.description }; } } return { fixed: false, original_type: input.invalid_type, error: 'No similar block type found', available_types: blocks.slice(0, 20).map(b => b.slug) };",
 		"description": "Find a valid block type similar to an invalid one. Uses a mapping table and fuzzy matching to suggest corrections. This replaces hardcoded mappings in copilot_autofix.go.",
 		"input_schema": {
 			"type": "object",
@@ -1625,6 +1634,21 @@ func fixBlockTypeToolConfig() string {
 	}`
 }
 
+// blockTypeAliasesJS renders domain.BlockTypeAliases as a JavaScript object literal with sorted keys
+func blockTypeAliasesJS() string {
+	keys := make([]string, 0, len(domain.BlockTypeAliases))
+	for alias := range domain.BlockTypeAliases {
+		keys = append(keys, alias)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, alias := range keys {
+		pairs = append(pairs, fmt.Sprintf("'%s': '%s'", alias, domain.BlockTypeAliases[alias]))
+	}
+	return "{ " + strings.Join(pairs, ", ") + " }"
+}
+
 // autoFixErrorsToolConfig returns the configuration for the auto_fix_errors tool
 // This migrates auto-fix logic from copilot_autofix.go
 func autoFixErrorsToolConfig() string {
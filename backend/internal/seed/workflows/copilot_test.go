@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
 	"github.com/souta/ai-orchestration/internal/engine"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -268,3 +269,15 @@ func TestCopilotWorkflow_AgentGroupHasTools(t *testing.T) {
 		assert.True(t, toolsByName[tool], "Tool %s should exist in agent group", tool)
 	}
 }
+
+func TestCheckSecurityToolConfig_MatchesAnalyzerPatterns(t *testing.T) {
+	var config struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(checkSecurityToolConfig()), &config))
+
+	// The JS tool and the Go analyzer behind /tools/analyze-code must flag the same patterns
+	for _, pattern := range append(append([]string{}, sandbox.DangerousCodePatterns...), sandbox.SensitiveCodePatterns...) {
+		assert.Contains(t, config.Code, "'"+pattern+"'", "check_security is missing pattern %q", pattern)
+	}
+}
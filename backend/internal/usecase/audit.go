@@ -67,21 +67,26 @@ type ListAuditLogsInput struct {
 	ResourceID   *uuid.UUID
 	StartTime    *time.Time
 	EndTime      *time.Time
+	Cursor       string // Opaque cursor returned as NextCursor by a previous page; replaces Page
 	Page         int
 	Limit        int
 }
 
 // ListAuditLogsOutput represents output for listing audit logs
 type ListAuditLogsOutput struct {
-	Logs  []*domain.AuditLog
-	Total int
-	Page  int
-	Limit int
+	Logs       []*domain.AuditLog
+	Total      int
+	Page       int
+	Limit      int
+	NextCursor string // Empty on the last page
 }
 
-// List lists audit logs with pagination
+// List lists audit logs, newest first, by page or keyset cursor
 func (s *AuditService) List(ctx context.Context, input ListAuditLogsInput) (*ListAuditLogsOutput, error) {
-	input.Page, input.Limit = NormalizePaginationWithLimit(input.Page, input.Limit, DefaultAuditLimit)
+	pagination, err := newListPagination(input.Page, input.Limit, DefaultAuditLimit, input.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := repository.AuditLogFilter{
 		ActorID:      input.ActorID,
@@ -90,8 +95,9 @@ func (s *AuditService) List(ctx context.Context, input ListAuditLogsInput) (*Lis
 		ResourceID:   input.ResourceID,
 		StartTime:    input.StartTime,
 		EndTime:      input.EndTime,
-		Page:         input.Page,
-		Limit:        input.Limit,
+		After:        pagination.After,
+		Page:         pagination.Page,
+		Limit:        pagination.FetchLimit(),
 	}
 
 	logs, total, err := s.repo.ListByTenant(ctx, input.TenantID, filter)
@@ -99,12 +105,18 @@ func (s *AuditService) List(ctx context.Context, input ListAuditLogsInput) (*Lis
 		return nil, err
 	}
 
-	return &ListAuditLogsOutput{
-		Logs:  logs,
+	n, more := pagination.Next(len(logs), total)
+	output := &ListAuditLogsOutput{
+		Logs:  logs[:n],
 		Total: total,
-		Page:  input.Page,
-		Limit: input.Limit,
-	}, nil
+		Page:  pagination.Page,
+		Limit: pagination.Limit,
+	}
+	if more {
+		last := output.Logs[n-1]
+		output.NextCursor = encodeListCursor(repository.ListCursor{Time: last.CreatedAt, ID: last.ID})
+	}
+	return output, nil
 }
 
 // ListByResource lists audit logs for a specific resource
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// blockCatalogSectionSize is the number of blocks in the recently used and popular sections
+const blockCatalogSectionSize = 6
+
+// BlockCatalogEntry is a block as shown in the editor's block palette
+type BlockCatalogEntry struct {
+	ID                 uuid.UUID               `json:"id"`
+	Slug               string                  `json:"slug"`
+	Name               string                  `json:"name"`
+	Description        string                  `json:"description,omitempty"`
+	Category           domain.BlockCategory    `json:"category"`
+	Subcategory        domain.BlockSubcategory `json:"subcategory,omitempty"`
+	Icon               string                  `json:"icon,omitempty"`
+	IsSystem           bool                    `json:"is_system"`
+	RequiresCredential bool                    `json:"requires_credential"` // A required credential must be bound before the block runs
+	UsageCount         int                     `json:"usage_count"`         // Steps of the block in the tenant's workflows
+	Examples           []domain.BlockExample   `json:"examples,omitempty"`  // Sample config, input and output to preview the block
+}
+
+// BlockCatalogCategory lists the blocks of one category, sorted by name
+type BlockCatalogCategory struct {
+	Category domain.BlockCategory `json:"category"`
+	Blocks   []BlockCatalogEntry  `json:"blocks"`
+}
+
+// BlockCatalog is the enabled blocks of a tenant grouped for the block palette
+type BlockCatalog struct {
+	Categories   []BlockCatalogCategory `json:"categories"`    // Non-empty categories in display order
+	RecentlyUsed []BlockCatalogEntry    `json:"recently_used"` // Blocks most recently added to a workflow
+	Popular      []BlockCatalogEntry    `json:"popular"`       // Blocks with the most steps in the tenant's workflows
+}
+
+// Catalog returns the tenant's enabled blocks grouped by category, with the recently used and
+// popular sections taken from the steps of the tenant's workflows
+func (u *BlockUsecase) Catalog(ctx context.Context, tenantID uuid.UUID) (*BlockCatalog, error) {
+	blocks, err := u.blockRepo.List(ctx, &tenantID, repository.BlockDefinitionFilter{EnabledOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	usage, err := u.blockRepo.GetUsage(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	usageBySlug := make(map[string]domain.BlockUsage, len(usage))
+	for _, stat := range usage {
+		usageBySlug[stat.Slug] = stat
+	}
+	blocksByID := make(map[uuid.UUID]*domain.BlockDefinition, len(blocks))
+	for _, block := range blocks {
+		blocksByID[block.ID] = block
+	}
+
+	// A tenant block shadows the system block with the same slug, as in GetBySlug
+	visible := make(map[string]*domain.BlockDefinition, len(blocks))
+	for _, block := range blocks {
+		if existing, ok := visible[block.Slug]; !ok || (existing.IsSystemBlock() && !block.IsSystemBlock()) {
+			visible[block.Slug] = block
+		}
+	}
+
+	byCategory := make(map[domain.BlockCategory][]BlockCatalogEntry)
+	var used []BlockCatalogEntry
+	for _, block := range visible {
+		entry := BlockCatalogEntry{
+			ID:                 block.ID,
+			Slug:               block.Slug,
+			Name:               block.Name,
+			Description:        block.Description,
+			Category:           block.Category,
+			Subcategory:        block.Subcategory,
+			Icon:               block.Icon,
+			IsSystem:           block.IsSystemBlock(),
+			RequiresCredential: requiresCredential(block, blocksByID),
+			UsageCount:         usageBySlug[block.Slug].StepCount,
+			Examples:           block.Examples,
+		}
+		byCategory[block.Category] = append(byCategory[block.Category], entry)
+		if entry.UsageCount > 0 {
+			used = append(used, entry)
+		}
+	}
+
+	catalog := &BlockCatalog{
+		Categories:   make([]BlockCatalogCategory, 0, len(byCategory)),
+		RecentlyUsed: make([]BlockCatalogEntry, 0),
+		Popular:      make([]BlockCatalogEntry, 0),
+	}
+	for _, category := range domain.ValidBlockCategories() {
+		entries := byCategory[category]
+		if len(entries) == 0 {
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Name != entries[j].Name {
+				return entries[i].Name < entries[j].Name
+			}
+			return entries[i].Slug < entries[j].Slug
+		})
+		catalog.Categories = append(catalog.Categories, BlockCatalogCategory{Category: category, Blocks: entries})
+	}
+
+	sort.Slice(used, func(i, j int) bool {
+		a, b := usageBySlug[used[i].Slug], usageBySlug[used[j].Slug]
+		if !a.LastUsedAt.Equal(b.LastUsedAt) {
+			return a.LastUsedAt.After(b.LastUsedAt)
+		}
+		return a.Slug < b.Slug
+	})
+	catalog.RecentlyUsed = append(catalog.RecentlyUsed, used[:min(len(used), blockCatalogSectionSize)]...)
+
+	sort.Slice(used, func(i, j int) bool {
+		if used[i].UsageCount != used[j].UsageCount {
+			return used[i].UsageCount > used[j].UsageCount
+		}
+		return used[i].Slug < used[j].Slug
+	})
+	catalog.Popular = append(catalog.Popular, used[:min(len(used), blockCatalogSectionSize)]...)
+
+	return catalog, nil
+}
+
+// requiresCredential reports whether a block, or the block it inherits from when it declares no
+// credentials itself, has a required credential
+func requiresCredential(block *domain.BlockDefinition, blocksByID map[uuid.UUID]*domain.BlockDefinition) bool {
+	visited := make(map[uuid.UUID]bool)
+	for block != nil && !visited[block.ID] {
+		visited[block.ID] = true
+		credentials, err := block.GetRequiredCredentials()
+		if err == nil && len(credentials) > 0 {
+			for _, credential := range credentials {
+				if credential.Required {
+					return true
+				}
+			}
+			return false
+		}
+		if block.ParentBlockID == nil {
+			return false
+		}
+		block = blocksByID[*block.ParentBlockID]
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func catalogSlugs(entries []BlockCatalogEntry) []string {
+	slugs := make([]string, len(entries))
+	for i, entry := range entries {
+		slugs[i] = entry.Slug
+	}
+	return slugs
+}
+
+func equalSlugs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBlockUsecase_Catalog_GroupsByCategory(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockBlockRepo()
+	addBlock := func(tenant *uuid.UUID, slug, name string, category domain.BlockCategory) *domain.BlockDefinition {
+		block := domain.NewBlockDefinition(tenant, slug, name, category)
+		block.IsSystem = tenant == nil
+		repo.blocks[block.ID] = block
+		return block
+	}
+	llm := addBlock(nil, "llm", "LLM", domain.BlockCategoryAI)
+	llm.Examples = []domain.BlockExample{{
+		Name:   "Summarize",
+		Config: json.RawMessage(`{"user_prompt": "Summarize {{text}}"}`),
+		Output: json.RawMessage(`{"content": "A summary"}`),
+	}}
+	addBlock(nil, "condition", "Condition", domain.BlockCategoryFlow)
+	addBlock(nil, "code", "Code", domain.BlockCategoryFlow)
+	addBlock(nil, "slack", "Slack", domain.BlockCategoryApps)
+	addBlock(&tenantID, "slack", "Slack (custom)", domain.BlockCategoryApps)
+	addBlock(&uuid.UUID{1}, "other-tenant", "Other Tenant Block", domain.BlockCategoryCustom)
+
+	now := time.Now()
+	repo.usage = []domain.BlockUsage{
+		{Slug: "llm", StepCount: 9, LastUsedAt: now.Add(-48 * time.Hour)},
+		{Slug: "code", StepCount: 2, LastUsedAt: now.Add(-time.Hour)},
+		{Slug: "slack", StepCount: 5, LastUsedAt: now.Add(-24 * time.Hour)},
+		{Slug: "deleted-block", StepCount: 3, LastUsedAt: now},
+	}
+
+	catalog, err := NewBlockUsecase(repo, nil).Catalog(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+
+	wantCategories := []struct {
+		category domain.BlockCategory
+		slugs    []string
+	}{
+		{domain.BlockCategoryAI, []string{"llm"}},
+		{domain.BlockCategoryFlow, []string{"code", "condition"}},
+		{domain.BlockCategoryApps, []string{"slack"}},
+	}
+	if len(catalog.Categories) != len(wantCategories) {
+		t.Fatalf("categories = %+v, want %d non-empty categories", catalog.Categories, len(wantCategories))
+	}
+	for i, want := range wantCategories {
+		got := catalog.Categories[i]
+		if got.Category != want.category || !equalSlugs(catalogSlugs(got.Blocks), want.slugs) {
+			t.Errorf("categories[%d] = %s %v, want %s %v", i, got.Category, catalogSlugs(got.Blocks), want.category, want.slugs)
+		}
+	}
+
+	if examples := catalog.Categories[0].Blocks[0].Examples; len(examples) != 1 || examples[0].Name != "Summarize" {
+		t.Errorf("llm examples = %+v, want the block's example", examples)
+	}
+
+	slack := catalog.Categories[2].Blocks[0]
+	if slack.Name != "Slack (custom)" || slack.IsSystem {
+		t.Errorf("slack entry = %+v, want the tenant block shadowing the system block", slack)
+	}
+	if slack.UsageCount != 5 {
+		t.Errorf("slack usage count = %d, want 5", slack.UsageCount)
+	}
+
+	if got, want := catalogSlugs(catalog.RecentlyUsed), []string{"code", "slack", "llm"}; !equalSlugs(got, want) {
+		t.Errorf("recently used = %v, want %v", got, want)
+	}
+	if got, want := catalogSlugs(catalog.Popular), []string{"llm", "slack", "code"}; !equalSlugs(got, want) {
+		t.Errorf("popular = %v, want %v", got, want)
+	}
+}
+
+func TestBlockUsecase_Catalog_RequiresCredential(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockBlockRepo()
+
+	required := domain.NewBlockDefinition(nil, "notion", "Notion", domain.BlockCategoryApps)
+	required.RequiredCredentials = json.RawMessage(`[{"name": "api_key", "type": "api_key", "scope": "tenant", "required": true}]`)
+	optional := domain.NewBlockDefinition(nil, "http", "HTTP", domain.BlockCategoryApps)
+	optional.RequiredCredentials = json.RawMessage(`[{"name": "token", "type": "bearer", "scope": "tenant", "required": false}]`)
+	none := domain.NewBlockDefinition(nil, "code", "Code", domain.BlockCategoryFlow)
+	inherited := domain.NewBlockDefinition(&tenantID, "notion-pages", "Notion Pages", domain.BlockCategoryCustom)
+	inherited.ParentBlockID = &required.ID
+	for _, block := range []*domain.BlockDefinition{required, optional, none, inherited} {
+		repo.blocks[block.ID] = block
+	}
+
+	catalog, err := NewBlockUsecase(repo, nil).Catalog(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+
+	want := map[string]bool{"notion": true, "http": false, "code": false, "notion-pages": true}
+	for _, category := range catalog.Categories {
+		for _, entry := range category.Blocks {
+			if entry.RequiresCredential != want[entry.Slug] {
+				t.Errorf("%s: RequiresCredential = %v, want %v", entry.Slug, entry.RequiresCredential, want[entry.Slug])
+			}
+			delete(want, entry.Slug)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("blocks missing from the catalog: %v", want)
+	}
+	if len(catalog.RecentlyUsed) != 0 || len(catalog.Popular) != 0 {
+		t.Errorf("usage sections = %v / %v, want empty without usage", catalog.RecentlyUsed, catalog.Popular)
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// ExportBlock returns a portable export of a tenant's custom block.
+// The parent block, if any, is referenced by slug.
+func (u *BlockUsecase) ExportBlock(ctx context.Context, tenantID uuid.UUID, slug string) (*domain.BlockExport, error) {
+	block, err := u.blockRepo.GetBySlug(ctx, &tenantID, slug)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, domain.ErrBlockDefinitionNotFound
+	}
+	if block.IsSystemBlock() {
+		return nil, domain.NewValidationError("slug", "system blocks are available to every tenant and cannot be exported")
+	}
+
+	parentSlug := ""
+	if block.ParentBlockID != nil {
+		parent, err := u.blockRepo.GetByID(ctx, *block.ParentBlockID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, domain.ErrParentBlockNotFound
+		}
+		parentSlug = parent.Slug
+	}
+
+	return domain.NewBlockExport(block, parentSlug), nil
+}
+
+// ImportBlockInput represents input for importing a block
+type ImportBlockInput struct {
+	TenantID uuid.UUID
+	Export   *domain.BlockExport
+	Slug     string // Optional: overrides the exported slug (e.g. to avoid a conflict)
+}
+
+// ImportBlock recreates an exported block under the importing tenant.
+// The parent and internal step blocks are resolved by slug in the importing tenant.
+func (u *BlockUsecase) ImportBlock(ctx context.Context, input ImportBlockInput) (*domain.BlockDefinition, error) {
+	if input.Export == nil {
+		return nil, domain.NewValidationError("block", "block export is required")
+	}
+	def := input.Export.Block
+	if input.Slug != "" {
+		def.Slug = input.Slug
+	}
+	export := *input.Export
+	export.Block = def
+	if err := export.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.blockRepo.GetBySlug(ctx, &input.TenantID, def.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrBlockDefinitionSlugExists
+	}
+
+	var parentID *uuid.UUID
+	if def.ParentSlug != "" {
+		parent, err := u.blockRepo.GetBySlug(ctx, &input.TenantID, def.ParentSlug)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, domain.NewValidationError("block.parent_slug", "parent block not found: "+def.ParentSlug)
+		}
+		if !parent.CanBeInherited() {
+			return nil, domain.NewValidationError("block.parent_slug", domain.ErrBlockNotInheritable.Error())
+		}
+		parentID = &parent.ID
+	}
+
+	for _, step := range def.InternalSteps {
+		stepBlock, err := u.blockRepo.GetBySlug(ctx, &input.TenantID, step.Type)
+		if err != nil {
+			return nil, err
+		}
+		if stepBlock == nil {
+			return nil, domain.NewValidationError("block.internal_steps", "internal step block type not found: "+step.Type)
+		}
+	}
+
+	block := def.ToBlockDefinition(input.TenantID, parentID)
+
+	if parentID != nil {
+		if err := u.blockRepo.ValidateInheritance(ctx, block.ID, *parentID); err != nil {
+			if errors.Is(err, domain.ErrCircularInheritance) || errors.Is(err, domain.ErrInheritanceDepthExceeded) {
+				return nil, domain.NewValidationError("block.parent_slug", err.Error())
+			}
+			return nil, err
+		}
+	}
+
+	if err := u.blockRepo.Create(ctx, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
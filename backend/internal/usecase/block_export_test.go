@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// ============================================================================
+// Mock Repository for Block Tests
+// ============================================================================
+
+// mockBlockRepo implements repository.BlockDefinitionRepository.
+// GetBySlug prefers the tenant's own block and falls back to system blocks, like the real repository.
+type mockBlockRepo struct {
+	blocks map[uuid.UUID]*domain.BlockDefinition
+	usage  []domain.BlockUsage
+}
+
+func newMockBlockRepo() *mockBlockRepo {
+	return &mockBlockRepo{blocks: make(map[uuid.UUID]*domain.BlockDefinition)}
+}
+
+func (m *mockBlockRepo) Create(ctx context.Context, block *domain.BlockDefinition) error {
+	m.blocks[block.ID] = block
+	return nil
+}
+
+func (m *mockBlockRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.BlockDefinition, error) {
+	return m.blocks[id], nil
+}
+
+func (m *mockBlockRepo) GetBySlug(ctx context.Context, tenantID *uuid.UUID, slug string) (*domain.BlockDefinition, error) {
+	var system *domain.BlockDefinition
+	for _, b := range m.blocks {
+		if b.Slug != slug {
+			continue
+		}
+		if b.TenantID == nil {
+			system = b
+		} else if tenantID != nil && *b.TenantID == *tenantID {
+			return b, nil
+		}
+	}
+	return system, nil
+}
+
+func (m *mockBlockRepo) List(ctx context.Context, tenantID *uuid.UUID, filter repository.BlockDefinitionFilter) ([]*domain.BlockDefinition, error) {
+	var result []*domain.BlockDefinition
+	for _, b := range m.blocks {
+		if b.TenantID == nil || (tenantID != nil && *b.TenantID == *tenantID) {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockBlockRepo) Update(ctx context.Context, block *domain.BlockDefinition) error {
+	m.blocks[block.ID] = block
+	return nil
+}
+
+func (m *mockBlockRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.blocks, id)
+	return nil
+}
+
+func (m *mockBlockRepo) DeleteSystem(ctx context.Context, id uuid.UUID) error {
+	return m.Delete(ctx, id)
+}
+
+func (m *mockBlockRepo) GetReferences(ctx context.Context, id uuid.UUID, slug string) (*domain.BlockReferences, error) {
+	return &domain.BlockReferences{}, nil
+}
+
+func (m *mockBlockRepo) GetUsage(ctx context.Context, tenantID uuid.UUID) ([]domain.BlockUsage, error) {
+	return m.usage, nil
+}
+
+func (m *mockBlockRepo) ValidateInheritance(ctx context.Context, blockID uuid.UUID, parentBlockID uuid.UUID) error {
+	return nil
+}
+
+// addSystemBlock registers a system block with code so it can be inherited
+func (m *mockBlockRepo) addSystemBlock(slug string) *domain.BlockDefinition {
+	block := domain.NewBlockDefinition(nil, slug, slug, domain.BlockCategoryApps)
+	block.IsSystem = true
+	block.Code = "return input;"
+	m.blocks[block.ID] = block
+	return block
+}
+
+// ============================================================================
+// Tests for Export / Import
+// ============================================================================
+
+func newExportableBlock(tenantID uuid.UUID, parent *domain.BlockDefinition) *domain.BlockDefinition {
+	block := domain.NewBlockDefinition(&tenantID, "crm-lookup", "CRM Lookup", domain.BlockCategoryCustom)
+	block.Description = "Looks up a customer in the CRM"
+	block.Code = "const res = ctx.http.get(config.url); return { customer: res.body };"
+	block.ConfigSchema = json.RawMessage(`{"type":"object","properties":{"url":{"type":"string"}},"required":["url"]}`)
+	block.OutputSchema = json.RawMessage(`{"type":"object","properties":{"customer":{"type":"object"}}}`)
+	block.ConfigDefaults = json.RawMessage(`{"timeout":30}`)
+	block.PreProcess = "input.id = String(input.id); return input;"
+	block.PostProcess = "return { ...input, fetched: true };"
+	block.InternalSteps = []domain.InternalStep{{Type: "http", Config: json.RawMessage(`{"method":"GET"}`), OutputKey: "raw"}}
+	block.Version = 7
+	block.IsPublic = true
+	if parent != nil {
+		block.ParentBlockID = &parent.ID
+	}
+	return block
+}
+
+func TestBlockUsecase_ExportImport_RoundTrip(t *testing.T) {
+	sourceTenant, targetTenant := uuid.New(), uuid.New()
+	repo := newMockBlockRepo()
+	httpBlock := repo.addSystemBlock("http")
+	original := newExportableBlock(sourceTenant, httpBlock)
+	repo.blocks[original.ID] = original
+
+	uc := NewBlockUsecase(repo, nil)
+
+	export, err := uc.ExportBlock(context.Background(), sourceTenant, "crm-lookup")
+	if err != nil {
+		t.Fatalf("ExportBlock() error = %v", err)
+	}
+	if export.Block.ParentSlug != "http" {
+		t.Errorf("ParentSlug = %q, want %q", export.Block.ParentSlug, "http")
+	}
+
+	// The export must survive a JSON round trip and contain no tenant-specific IDs
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("marshal export: %v", err)
+	}
+	for _, id := range []uuid.UUID{sourceTenant, original.ID, httpBlock.ID} {
+		if strings.Contains(string(data), id.String()) {
+			t.Errorf("export contains tenant-specific ID %s", id)
+		}
+	}
+	var decoded domain.BlockExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal export: %v", err)
+	}
+
+	imported, err := uc.ImportBlock(context.Background(), ImportBlockInput{TenantID: targetTenant, Export: &decoded})
+	if err != nil {
+		t.Fatalf("ImportBlock() error = %v", err)
+	}
+
+	if imported.ID == original.ID {
+		t.Error("imported block should have a new ID")
+	}
+	if imported.TenantID == nil || *imported.TenantID != targetTenant {
+		t.Errorf("TenantID = %v, want %v", imported.TenantID, targetTenant)
+	}
+	if imported.ParentBlockID == nil || *imported.ParentBlockID != httpBlock.ID {
+		t.Errorf("ParentBlockID = %v, want re-linked to %v", imported.ParentBlockID, httpBlock.ID)
+	}
+	if imported.Code != original.Code {
+		t.Errorf("Code = %q, want %q", imported.Code, original.Code)
+	}
+	if imported.PreProcess != original.PreProcess || imported.PostProcess != original.PostProcess {
+		t.Error("pre/post process code not preserved")
+	}
+	assertJSONEqual(t, "ConfigSchema", imported.ConfigSchema, original.ConfigSchema)
+	assertJSONEqual(t, "OutputSchema", imported.OutputSchema, original.OutputSchema)
+	assertJSONEqual(t, "ConfigDefaults", imported.ConfigDefaults, original.ConfigDefaults)
+	if len(imported.InternalSteps) != 1 || imported.InternalSteps[0].OutputKey != "raw" {
+		t.Errorf("InternalSteps = %+v, want preserved", imported.InternalSteps)
+	}
+	if imported.Version != 0 || imported.IsPublic {
+		t.Errorf("Version = %d, IsPublic = %v; want fresh, private block", imported.Version, imported.IsPublic)
+	}
+}
+
+func TestBlockUsecase_ImportBlock_Errors(t *testing.T) {
+	sourceTenant, targetTenant := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(repo *mockBlockRepo) *domain.BlockExport
+		slug    string
+		wantErr error
+	}{
+		{
+			name: "slug already exists in target tenant",
+			setup: func(repo *mockBlockRepo) *domain.BlockExport {
+				repo.addSystemBlock("http")
+				existing := domain.NewBlockDefinition(&targetTenant, "crm-lookup", "Existing", domain.BlockCategoryCustom)
+				repo.blocks[existing.ID] = existing
+				return domain.NewBlockExport(newExportableBlock(sourceTenant, nil), "")
+			},
+			wantErr: domain.ErrBlockDefinitionSlugExists,
+		},
+		{
+			name: "parent only exists in source tenant",
+			setup: func(repo *mockBlockRepo) *domain.BlockExport {
+				repo.addSystemBlock("http")
+				return domain.NewBlockExport(newExportableBlock(sourceTenant, nil), "source-private-base")
+			},
+		},
+		{
+			name: "internal step type not available",
+			setup: func(repo *mockBlockRepo) *domain.BlockExport {
+				return domain.NewBlockExport(newExportableBlock(sourceTenant, nil), "")
+			},
+		},
+		{
+			name: "unsupported format version",
+			setup: func(repo *mockBlockRepo) *domain.BlockExport {
+				export := domain.NewBlockExport(newExportableBlock(sourceTenant, nil), "")
+				export.FormatVersion = 99
+				return export
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockBlockRepo()
+			export := tt.setup(repo)
+			uc := NewBlockUsecase(repo, nil)
+
+			_, err := uc.ImportBlock(context.Background(), ImportBlockInput{TenantID: targetTenant, Export: export, Slug: tt.slug})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ImportBlock() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("ImportBlock() error = %v, want ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestBlockUsecase_ImportBlock_SlugOverride(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockBlockRepo()
+	repo.addSystemBlock("http")
+	original := newExportableBlock(tenantID, nil)
+	repo.blocks[original.ID] = original
+
+	uc := NewBlockUsecase(repo, nil)
+	export, err := uc.ExportBlock(context.Background(), tenantID, "crm-lookup")
+	if err != nil {
+		t.Fatalf("ExportBlock() error = %v", err)
+	}
+
+	// Re-importing into the same tenant works with a new slug
+	imported, err := uc.ImportBlock(context.Background(), ImportBlockInput{TenantID: tenantID, Export: export, Slug: "crm-lookup-copy"})
+	if err != nil {
+		t.Fatalf("ImportBlock() error = %v", err)
+	}
+	if imported.Slug != "crm-lookup-copy" {
+		t.Errorf("Slug = %q, want %q", imported.Slug, "crm-lookup-copy")
+	}
+	if export.Block.Slug != "crm-lookup" {
+		t.Error("slug override must not mutate the export")
+	}
+}
+
+func TestBlockUsecase_ExportBlock_SystemBlockRejected(t *testing.T) {
+	repo := newMockBlockRepo()
+	repo.addSystemBlock("http")
+	uc := NewBlockUsecase(repo, nil)
+
+	_, err := uc.ExportBlock(context.Background(), uuid.New(), "http")
+	var validationErr domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("ExportBlock() error = %v, want ValidationError", err)
+	}
+
+	if _, err := uc.ExportBlock(context.Background(), uuid.New(), "missing"); !errors.Is(err, domain.ErrBlockDefinitionNotFound) {
+		t.Errorf("ExportBlock() error = %v, want ErrBlockDefinitionNotFound", err)
+	}
+}
+
+func assertJSONEqual(t *testing.T, field string, got, want json.RawMessage) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("%s: invalid JSON %s: %v", field, got, err)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("%s: invalid JSON %s: %v", field, want, err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("%s = %s, want %s", field, got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// CapabilitiesUsecase reports what the deployment and a tenant can use, so clients and the
+// copilot agent can check at runtime instead of assuming
+type CapabilitiesUsecase struct {
+	tenantRepo repository.TenantRepository
+	registry   *adapter.Registry
+	services   map[string]bool
+}
+
+// NewCapabilitiesUsecase creates a new CapabilitiesUsecase advertising the adapters of registry
+func NewCapabilitiesUsecase(tenantRepo repository.TenantRepository, registry *adapter.Registry) *CapabilitiesUsecase {
+	return &CapabilitiesUsecase{
+		tenantRepo: tenantRepo,
+		registry:   registry,
+		services:   make(map[string]bool),
+	}
+}
+
+// WithService advertises a script service (ctx.<name>) and whether the deployment configures it
+func (u *CapabilitiesUsecase) WithService(name string, configured bool) *CapabilitiesUsecase {
+	u.services[name] = configured
+	return u
+}
+
+// Capabilities lists the registered adapters, script services, block categories and the
+// tenant's feature flags and limits
+type Capabilities struct {
+	Adapters        []AdapterCapability       `json:"adapters"`
+	Services        map[string]bool           `json:"services"` // Service name -> configured
+	BlockCategories []domain.BlockCategory    `json:"block_categories"`
+	Features        domain.TenantFeatureFlags `json:"features"`
+	Limits          domain.TenantLimits       `json:"limits"`
+}
+
+// AdapterCapability describes a registered adapter. An adapter that is not configured (e.g.
+// without an API key) fails every step that uses it.
+type AdapterCapability struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Configured       bool   `json:"configured"`
+	StructuredOutput bool   `json:"structured_output"` // Honors response_schema
+	Batch            bool   `json:"batch"`             // Processes map items in batches
+}
+
+// Get returns the capabilities available to the tenant
+func (u *CapabilitiesUsecase) Get(ctx context.Context, tenantID uuid.UUID) (*Capabilities, error) {
+	tenant, err := u.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	features, err := tenant.GetFeatureFlags()
+	if err != nil {
+		return nil, err
+	}
+	limits, err := tenant.GetLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := &Capabilities{
+		Adapters:        []AdapterCapability{},
+		Services:        u.services,
+		BlockCategories: domain.ValidBlockCategories(),
+		Features:        *features,
+		Limits:          *limits,
+	}
+	if u.registry != nil {
+		for _, adp := range u.registry.List() {
+			_, batch := adp.(adapter.BatchAdapter)
+			capabilities.Adapters = append(capabilities.Adapters, AdapterCapability{
+				ID:               adp.ID(),
+				Name:             adp.Name(),
+				Configured:       adapter.IsConfigured(adp),
+				StructuredOutput: adapter.SupportsStructuredOutput(adp),
+				Batch:            batch,
+			})
+		}
+	}
+	sort.Slice(capabilities.Adapters, func(i, j int) bool {
+		return capabilities.Adapters[i].ID < capabilities.Adapters[j].ID
+	})
+	return capabilities, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// planTenantRepo returns the tenant it holds, or ErrTenantNotFound for any other ID
+type planTenantRepo struct {
+	repository.TenantRepository
+	tenant *domain.Tenant
+}
+
+func (r *planTenantRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+	if r.tenant == nil || r.tenant.ID != id {
+		return nil, domain.ErrTenantNotFound
+	}
+	return r.tenant, nil
+}
+
+func TestCapabilitiesUsecase_Get(t *testing.T) {
+	tenant, _ := domain.NewTenant("Acme", "acme", domain.TenantPlanFree)
+	registry := adapter.NewRegistry()
+	registry.Register(adapter.NewHTTPAdapter())
+	registry.Register(adapter.NewOpenAIAdapterWithKey("sk-test"))
+	registry.Register(adapter.NewMockAdapter())
+	uc := NewCapabilitiesUsecase(&planTenantRepo{tenant: tenant}, registry).WithService("search", false)
+
+	capabilities, err := uc.Get(context.Background(), tenant.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	var ids []string
+	for _, adp := range capabilities.Adapters {
+		ids = append(ids, adp.ID)
+	}
+	if len(ids) != 3 || ids[0] != "http" || ids[1] != "mock" || ids[2] != "openai" {
+		t.Fatalf("Adapters = %v, want the registered adapters sorted by ID", ids)
+	}
+	if openai := capabilities.Adapters[2]; !openai.Configured || !openai.StructuredOutput {
+		t.Errorf("openai = %+v, want configured with structured output", openai)
+	}
+	if configured, ok := capabilities.Services["search"]; !ok || configured {
+		t.Errorf("Services = %v, want search listed as not configured", capabilities.Services)
+	}
+
+	defaults := domain.DefaultFeatureFlags(domain.TenantPlanFree)
+	if capabilities.Features != defaults {
+		t.Errorf("Features = %+v, want the free plan flags %+v", capabilities.Features, defaults)
+	}
+	if capabilities.Limits.MaxWorkflows != domain.DefaultLimits(domain.TenantPlanFree).MaxWorkflows {
+		t.Errorf("Limits = %+v, want the free plan limits", capabilities.Limits)
+	}
+	if len(capabilities.BlockCategories) != len(domain.ValidBlockCategories()) {
+		t.Errorf("BlockCategories = %v", capabilities.BlockCategories)
+	}
+}
+
+func TestCapabilitiesUsecase_Get_TenantFeatureFlags(t *testing.T) {
+	tenant, _ := domain.NewTenant("Acme", "acme", domain.TenantPlanFree)
+	flags := domain.DefaultFeatureFlags(domain.TenantPlanFree)
+	flags.CopilotEnabled = true
+	flags.MaxConcurrentRuns = 7
+	tenant.FeatureFlags, _ = json.Marshal(flags)
+	registry := adapter.NewRegistry()
+	registry.Register(adapter.NewOpenAIAdapterWithKey(""))
+	uc := NewCapabilitiesUsecase(&planTenantRepo{tenant: tenant}, registry)
+
+	capabilities, err := uc.Get(context.Background(), tenant.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !capabilities.Features.CopilotEnabled || capabilities.Features.MaxConcurrentRuns != 7 {
+		t.Errorf("Features = %+v, want the tenant's flags", capabilities.Features)
+	}
+	if len(capabilities.Adapters) != 1 || capabilities.Adapters[0].Configured {
+		t.Errorf("Adapters = %+v, want openai without an API key reported as not configured", capabilities.Adapters)
+	}
+
+	if _, err := uc.Get(context.Background(), uuid.New()); !errors.Is(err, domain.ErrTenantNotFound) {
+		t.Errorf("Get() of an unknown tenant error = %v, want ErrTenantNotFound", err)
+	}
+}
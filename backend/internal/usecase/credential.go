@@ -9,12 +9,16 @@ import (
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/repository"
 	"github.com/souta/ai-orchestration/pkg/crypto"
+	"github.com/souta/ai-orchestration/pkg/netguard"
 )
 
 // CredentialUsecase handles credential business logic
 type CredentialUsecase struct {
 	credentialRepo repository.CredentialRepository
 	encryptor      *crypto.Encryptor
+	testers        map[string]CredentialTester // Connection testers by service name or credential type
+	netGuard       *netguard.Guard
+	accessAuditor  *CredentialAccessAuditor
 }
 
 // NewCredentialUsecase creates a new CredentialUsecase
@@ -25,9 +29,16 @@ func NewCredentialUsecase(
 	return &CredentialUsecase{
 		credentialRepo: credentialRepo,
 		encryptor:      encryptor,
+		testers:        defaultCredentialTesters(),
 	}
 }
 
+// WithAccessAuditor records each decryption of a credential in the audit log
+func (u *CredentialUsecase) WithAccessAuditor(auditor *CredentialAccessAuditor) *CredentialUsecase {
+	u.accessAuditor = auditor
+	return u
+}
+
 // CreateCredentialInput represents input for creating a credential
 type CreateCredentialInput struct {
 	TenantID       uuid.UUID
@@ -94,8 +105,8 @@ func (u *CredentialUsecase) GetByID(ctx context.Context, tenantID, id uuid.UUID)
 	return u.credentialRepo.GetByID(ctx, tenantID, id)
 }
 
-// GetDecrypted retrieves a credential with decrypted data
-func (u *CredentialUsecase) GetDecrypted(ctx context.Context, tenantID, id uuid.UUID) (*domain.DecryptedCredential, error) {
+// GetDecrypted retrieves a credential with decrypted data, auditing the decryption as access
+func (u *CredentialUsecase) GetDecrypted(ctx context.Context, tenantID, id uuid.UUID, access domain.CredentialAccess) (*domain.DecryptedCredential, error) {
 	credential, err := u.credentialRepo.GetByID(ctx, tenantID, id)
 	if err != nil {
 		return nil, err
@@ -130,6 +141,7 @@ func (u *CredentialUsecase) GetDecrypted(ctx context.Context, tenantID, id uuid.
 	if err != nil {
 		return nil, err
 	}
+	u.accessAuditor.Record(ctx, tenantID, id, access)
 
 	data, err := domain.CredentialDataFromJSON(dataJSON)
 	if err != nil {
@@ -147,6 +159,7 @@ type ListCredentialsInput struct {
 	TenantID       uuid.UUID
 	CredentialType *domain.CredentialType
 	Status         *domain.CredentialStatus
+	Cursor         string // Opaque cursor returned as NextCursor by a previous page; replaces Page
 	Page           int
 	Limit          int
 }
@@ -157,17 +170,22 @@ type ListCredentialsOutput struct {
 	Total       int
 	Page        int
 	Limit       int
+	NextCursor  string // Empty on the last page
 }
 
-// List lists credentials with pagination (without decrypting data)
+// List lists credentials, newest first, by page or keyset cursor (without decrypting data)
 func (u *CredentialUsecase) List(ctx context.Context, input ListCredentialsInput) (*ListCredentialsOutput, error) {
-	input.Page, input.Limit = NormalizePagination(input.Page, input.Limit)
+	pagination, err := newListPagination(input.Page, input.Limit, DefaultLimit, input.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := repository.CredentialFilter{
 		CredentialType: input.CredentialType,
 		Status:         input.Status,
-		Page:           input.Page,
-		Limit:          input.Limit,
+		After:          pagination.After,
+		Page:           pagination.Page,
+		Limit:          pagination.FetchLimit(),
 	}
 
 	credentials, total, err := u.credentialRepo.List(ctx, input.TenantID, filter)
@@ -175,12 +193,18 @@ func (u *CredentialUsecase) List(ctx context.Context, input ListCredentialsInput
 		return nil, err
 	}
 
-	return &ListCredentialsOutput{
-		Credentials: credentials,
+	n, more := pagination.Next(len(credentials), total)
+	output := &ListCredentialsOutput{
+		Credentials: credentials[:n],
 		Total:       total,
-		Page:        input.Page,
-		Limit:       input.Limit,
-	}, nil
+		Page:        pagination.Page,
+		Limit:       pagination.Limit,
+	}
+	if more {
+		last := output.Credentials[n-1]
+		output.NextCursor = encodeListCursor(repository.ListCursor{Time: last.CreatedAt, ID: last.ID})
+	}
+	return output, nil
 }
 
 // UpdateCredentialInput represents input for updating a credential
@@ -296,13 +320,13 @@ func (u *CredentialUsecase) GetByName(ctx context.Context, tenantID uuid.UUID, n
 }
 
 // GetDecryptedByName retrieves a credential by name with decrypted data
-func (u *CredentialUsecase) GetDecryptedByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.DecryptedCredential, error) {
+func (u *CredentialUsecase) GetDecryptedByName(ctx context.Context, tenantID uuid.UUID, name string, access domain.CredentialAccess) (*domain.DecryptedCredential, error) {
 	credential, err := u.credentialRepo.GetByName(ctx, tenantID, name)
 	if err != nil {
 		return nil, err
 	}
 
-	return u.GetDecrypted(ctx, tenantID, credential.ID)
+	return u.GetDecrypted(ctx, tenantID, credential.ID, access)
 }
 
 // ToCredentialMap converts CredentialData to map for use in sandbox context
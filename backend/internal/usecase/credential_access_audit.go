@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// credentialAccessAuditWindow is how long repeated accesses of a credential by the same step (or
+// actor) are folded into the entry already logged for it
+const credentialAccessAuditWindow = time.Minute
+
+// credentialAccessPruneSize is the number of tracked accesses above which expired ones are dropped
+const credentialAccessPruneSize = 10000
+
+// CredentialAccessAuditor records credential decryptions in the audit log as credential.access
+// entries. To avoid flooding the log from high-frequency runs, an access repeating one logged
+// within the window is only counted; the count is reported as suppressed_accesses in the next
+// entry for the same access.
+type CredentialAccessAuditor struct {
+	auditService *AuditService
+	window       time.Duration
+	now          func() time.Time
+
+	mu       sync.Mutex
+	accesses map[credentialAccessKey]*credentialAccessRecord
+}
+
+// credentialAccessKey identifies accesses that are batched together
+type credentialAccessKey struct {
+	tenantID     uuid.UUID
+	credentialID uuid.UUID
+	purpose      domain.CredentialAccessPurpose
+	stepID       uuid.UUID
+	actorID      uuid.UUID
+}
+
+// credentialAccessRecord tracks the last logged entry of an access
+type credentialAccessRecord struct {
+	loggedAt   time.Time
+	suppressed int
+}
+
+// NewCredentialAccessAuditor creates a new CredentialAccessAuditor
+func NewCredentialAccessAuditor(auditService *AuditService) *CredentialAccessAuditor {
+	return &CredentialAccessAuditor{
+		auditService: auditService,
+		window:       credentialAccessAuditWindow,
+		now:          time.Now,
+		accesses:     make(map[credentialAccessKey]*credentialAccessRecord),
+	}
+}
+
+// Record logs the decryption of a tenant credential. Logging is best effort, like the audit
+// entries of API requests: a failure does not fail the access.
+func (a *CredentialAccessAuditor) Record(ctx context.Context, tenantID, credentialID uuid.UUID, access domain.CredentialAccess) {
+	if a == nil || a.auditService == nil {
+		return
+	}
+
+	key := credentialAccessKey{tenantID: tenantID, credentialID: credentialID, purpose: access.Purpose}
+	if access.StepID != nil {
+		key.stepID = *access.StepID
+	}
+	if access.ActorID != nil {
+		key.actorID = *access.ActorID
+	}
+
+	now := a.now()
+	a.mu.Lock()
+	record, ok := a.accesses[key]
+	if ok && now.Sub(record.loggedAt) < a.window {
+		record.suppressed++
+		a.mu.Unlock()
+		return
+	}
+	suppressed := 0
+	if ok {
+		suppressed = record.suppressed
+	}
+	a.accesses[key] = &credentialAccessRecord{loggedAt: now}
+	if len(a.accesses) > credentialAccessPruneSize {
+		a.prune(now)
+	}
+	a.mu.Unlock()
+
+	metadata := map[string]interface{}{"purpose": access.Purpose}
+	if access.RunID != nil {
+		metadata["run_id"] = access.RunID.String()
+	}
+	if access.StepID != nil {
+		metadata["step_id"] = access.StepID.String()
+	}
+	if suppressed > 0 {
+		metadata["suppressed_accesses"] = suppressed
+	}
+	_ = a.auditService.Log(ctx, LogAuditInput{
+		TenantID:     tenantID,
+		ActorID:      access.ActorID,
+		Action:       domain.AuditActionCredentialAccess,
+		ResourceType: domain.AuditResourceCredential,
+		ResourceID:   &credentialID,
+		Metadata:     metadata,
+	})
+}
+
+// prune drops accesses whose window has passed. Their suppressed counts, if any, are not reported.
+func (a *CredentialAccessAuditor) prune(now time.Time) {
+	for key, record := range a.accesses {
+		if now.Sub(record.loggedAt) >= a.window {
+			delete(a.accesses, key)
+		}
+	}
+}
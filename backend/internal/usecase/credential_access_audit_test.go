@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/pkg/crypto"
+)
+
+// mockAuditLogRepo records created audit logs
+type mockAuditLogRepo struct {
+	logs []*domain.AuditLog
+}
+
+func (m *mockAuditLogRepo) Create(ctx context.Context, log *domain.AuditLog) error {
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+func (m *mockAuditLogRepo) ListByTenant(ctx context.Context, tenantID uuid.UUID, filter repository.AuditLogFilter) ([]*domain.AuditLog, int, error) {
+	return m.logs, len(m.logs), nil
+}
+
+func (m *mockAuditLogRepo) ListByResource(ctx context.Context, tenantID uuid.UUID, resourceType domain.AuditResourceType, resourceID uuid.UUID) ([]*domain.AuditLog, error) {
+	return m.logs, nil
+}
+
+func TestCredentialResolver_ResolveBoundCredentials_AuditsAccess(t *testing.T) {
+	encryptor, err := crypto.NewEncryptorWithKey([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewEncryptorWithKey() error = %v", err)
+	}
+	tenantID := uuid.New()
+	repo := newMockCredentialRepoForShare()
+	cred := newEncryptedCredential(t, encryptor, tenantID, "secret-key")
+	repo.addCredential(cred)
+	auditRepo := &mockAuditLogRepo{}
+	resolver := NewCredentialResolver(repo, nil, encryptor).WithAccessAuditor(NewCredentialAccessAuditor(NewAuditService(auditRepo)))
+
+	runID, stepID, userID := uuid.New(), uuid.New(), uuid.New()
+	access := domain.CredentialAccess{Purpose: domain.CredentialAccessExecution, RunID: &runID, StepID: &stepID, ActorID: &userID}
+	if _, err := resolver.ResolveBoundCredentials(context.Background(), tenantID, map[string]uuid.UUID{"account": cred.ID}, access); err != nil {
+		t.Fatalf("ResolveBoundCredentials() error = %v", err)
+	}
+
+	if len(auditRepo.logs) != 1 {
+		t.Fatalf("audit logs = %d, want 1", len(auditRepo.logs))
+	}
+	log := auditRepo.logs[0]
+	if log.Action != domain.AuditActionCredentialAccess || log.ResourceID == nil || *log.ResourceID != cred.ID {
+		t.Errorf("audit log = %s %v, want credential.access of %s", log.Action, log.ResourceID, cred.ID)
+	}
+	if log.TenantID != tenantID || log.ActorID == nil || *log.ActorID != userID {
+		t.Errorf("audit log tenant/actor = %s/%v, want %s/%s", log.TenantID, log.ActorID, tenantID, userID)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(log.Metadata, &metadata); err != nil {
+		t.Fatalf("metadata error = %v", err)
+	}
+	if metadata["purpose"] != "execution" || metadata["run_id"] != runID.String() || metadata["step_id"] != stepID.String() {
+		t.Errorf("metadata = %v, want the execution purpose with run and step IDs", metadata)
+	}
+	if strings.Contains(string(log.Metadata), "secret-key") {
+		t.Errorf("metadata %s contains the secret", log.Metadata)
+	}
+}
+
+func TestCredentialAccessAuditor_Throttles(t *testing.T) {
+	auditRepo := &mockAuditLogRepo{}
+	auditor := NewCredentialAccessAuditor(NewAuditService(auditRepo))
+	now := time.Now()
+	auditor.now = func() time.Time { return now }
+
+	tenantID, credID := uuid.New(), uuid.New()
+	stepID, otherStepID := uuid.New(), uuid.New()
+	access := domain.CredentialAccess{Purpose: domain.CredentialAccessExecution, StepID: &stepID}
+	for i := 0; i < 5; i++ {
+		auditor.Record(context.Background(), tenantID, credID, access)
+	}
+	auditor.Record(context.Background(), tenantID, credID, domain.CredentialAccess{Purpose: domain.CredentialAccessExecution, StepID: &otherStepID})
+	if len(auditRepo.logs) != 2 {
+		t.Fatalf("audit logs within the window = %d, want 2 (one per step)", len(auditRepo.logs))
+	}
+
+	now = now.Add(credentialAccessAuditWindow)
+	auditor.Record(context.Background(), tenantID, credID, access)
+	if len(auditRepo.logs) != 3 {
+		t.Fatalf("audit logs after the window = %d, want 3", len(auditRepo.logs))
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(auditRepo.logs[2].Metadata, &metadata); err != nil {
+		t.Fatalf("metadata error = %v", err)
+	}
+	if metadata["suppressed_accesses"] != float64(4) {
+		t.Errorf("suppressed_accesses = %v, want 4", metadata["suppressed_accesses"])
+	}
+}
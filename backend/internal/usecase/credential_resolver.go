@@ -16,6 +16,7 @@ type CredentialResolver struct {
 	credentialRepo       repository.CredentialRepository
 	systemCredentialRepo repository.SystemCredentialRepository
 	encryptor            *crypto.Encryptor
+	accessAuditor        *CredentialAccessAuditor
 }
 
 // NewCredentialResolver creates a new CredentialResolver
@@ -31,6 +32,12 @@ func NewCredentialResolver(
 	}
 }
 
+// WithAccessAuditor records each decryption of a tenant credential in the audit log
+func (r *CredentialResolver) WithAccessAuditor(auditor *CredentialAccessAuditor) *CredentialResolver {
+	r.accessAuditor = auditor
+	return r
+}
+
 // ResolvedCredentials contains the resolved and decrypted credentials for a block
 type ResolvedCredentials struct {
 	// Map of credential name to decrypted credential data
@@ -82,7 +89,10 @@ func (r *CredentialResolver) ResolveForStep(
 				}
 				continue // Skip optional unbound credentials
 			}
-			credData, resolveErr = r.resolveTenantCredential(ctx, tenantID, credID)
+			credData, resolveErr = r.resolveTenantCredential(ctx, tenantID, credID, domain.CredentialAccess{
+				Purpose: domain.CredentialAccessExecution,
+				StepID:  &step.ID,
+			})
 
 		default:
 			return nil, fmt.Errorf("unknown credential scope: %s", req.Scope)
@@ -101,6 +111,21 @@ func (r *CredentialResolver) ResolveForStep(
 	return result, nil
 }
 
+// ResolveBoundCredentials resolves credentials bound to a step by ID for the executor. Each
+// credential is looked up within the tenant, so an ID of another tenant's credential fails, and
+// each decryption is audited as the given access.
+func (r *CredentialResolver) ResolveBoundCredentials(ctx context.Context, tenantID uuid.UUID, bindings map[string]uuid.UUID, access domain.CredentialAccess) (map[string]interface{}, error) {
+	credentials := make(map[string]interface{}, len(bindings))
+	for name, credID := range bindings {
+		credData, err := r.resolveTenantCredential(ctx, tenantID, credID, access)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve credential '%s': %w", name, err)
+		}
+		credentials[name] = credData
+	}
+	return credentials, nil
+}
+
 // resolveSystemCredential resolves a system credential by name
 func (r *CredentialResolver) resolveSystemCredential(ctx context.Context, name string) (map[string]interface{}, error) {
 	cred, err := r.systemCredentialRepo.GetByName(ctx, name)
@@ -136,8 +161,8 @@ func (r *CredentialResolver) resolveSystemCredential(ctx context.Context, name s
 	return CredentialDataToMap(credData), nil
 }
 
-// resolveTenantCredential resolves a tenant credential by ID
-func (r *CredentialResolver) resolveTenantCredential(ctx context.Context, tenantID, credID uuid.UUID) (map[string]interface{}, error) {
+// resolveTenantCredential resolves a tenant credential by ID, auditing the decryption as access
+func (r *CredentialResolver) resolveTenantCredential(ctx context.Context, tenantID, credID uuid.UUID, access domain.CredentialAccess) (map[string]interface{}, error) {
 	cred, err := r.credentialRepo.GetByID(ctx, tenantID, credID)
 	if err != nil {
 		return nil, err
@@ -161,6 +186,7 @@ func (r *CredentialResolver) resolveTenantCredential(ctx context.Context, tenant
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt credential: %w", err)
 	}
+	r.accessAuditor.Record(ctx, tenantID, credID, access)
 
 	// Parse credential data
 	credData, err := domain.CredentialDataFromJSON(plaintext)
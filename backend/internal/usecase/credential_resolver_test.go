@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/crypto"
+)
+
+func newEncryptedCredential(t *testing.T, encryptor *crypto.Encryptor, tenantID uuid.UUID, apiKey string) *domain.Credential {
+	t.Helper()
+	data, err := (&domain.CredentialData{Type: "api_key", APIKey: apiKey}).ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	encrypted, err := encryptor.Encrypt(data)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	cred := domain.NewCredential(tenantID, "crm-"+apiKey, domain.CredentialTypeAPIKey)
+	cred.EncryptedData = encrypted.Ciphertext
+	cred.EncryptedDEK = encrypted.EncryptedDEK
+	cred.DataNonce = encrypted.DataNonce
+	cred.DEKNonce = encrypted.DEKNonce
+	return cred
+}
+
+func TestCredentialResolver_ResolveBoundCredentials(t *testing.T) {
+	encryptor, err := crypto.NewEncryptorWithKey([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewEncryptorWithKey() error = %v", err)
+	}
+	tenantID, otherTenantID := uuid.New(), uuid.New()
+	repo := newMockCredentialRepoForShare()
+	own := newEncryptedCredential(t, encryptor, tenantID, "own-key")
+	foreign := newEncryptedCredential(t, encryptor, otherTenantID, "foreign-key")
+	repo.addCredential(own)
+	repo.addCredential(foreign)
+	resolver := NewCredentialResolver(repo, nil, encryptor)
+
+	credentials, err := resolver.ResolveBoundCredentials(context.Background(), tenantID, map[string]uuid.UUID{"account": own.ID}, domain.CredentialAccess{})
+	if err != nil {
+		t.Fatalf("ResolveBoundCredentials() error = %v", err)
+	}
+	account, _ := credentials["account"].(map[string]interface{})
+	if account["api_key"] != "own-key" {
+		t.Errorf("account credential = %v, want api_key own-key", credentials["account"])
+	}
+
+	_, err = resolver.ResolveBoundCredentials(context.Background(), tenantID, map[string]uuid.UUID{"account": foreign.ID}, domain.CredentialAccess{})
+	if !errors.Is(err, domain.ErrCredentialNotFound) {
+		t.Errorf("ResolveBoundCredentials() with another tenant's credential error = %v, want ErrCredentialNotFound", err)
+	}
+}
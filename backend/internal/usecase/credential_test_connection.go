@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/netguard"
+)
+
+// credentialTestTimeout bounds a connection test, including the provider call
+const credentialTestTimeout = 10 * time.Second
+
+// CredentialTestResult is the outcome of a credential connection test
+type CredentialTestResult struct {
+	Success    bool                   `json:"success"`
+	Provider   string                 `json:"provider"`              // Tester that ran the check (service name or credential type)
+	StatusCode int                    `json:"status_code,omitempty"` // HTTP status of the provider call
+	Message    string                 `json:"message"`
+	Details    map[string]interface{} `json:"details,omitempty"` // Provider-specific account details
+	DurationMs int                    `json:"duration_ms"`
+}
+
+// CredentialTester validates a credential with a lightweight call to its provider. A rejected
+// credential or an unreachable provider is reported as an unsuccessful result; an error means the
+// credential cannot be tested at all (for example, it lacks the URL to call).
+type CredentialTester interface {
+	Test(ctx context.Context, credential *domain.DecryptedCredential) (*CredentialTestResult, error)
+}
+
+// defaultCredentialTesters returns the testers registered on a new CredentialUsecase: provider
+// APIs by service name, and a request to the service URL for the generic credential types
+func defaultCredentialTesters() map[string]CredentialTester {
+	generic := NewHTTPCredentialTester()
+	return map[string]CredentialTester{
+		"slack":                                 NewSlackCredentialTester("https://slack.com/api"),
+		"github":                                NewGitHubCredentialTester("https://api.github.com"),
+		string(domain.CredentialTypeAPIKey):     generic,
+		string(domain.CredentialTypeBearer):     generic,
+		string(domain.CredentialTypeOAuth2):     generic,
+		string(domain.CredentialTypeBasic):      generic,
+		string(domain.CredentialTypeHeaderAuth): generic,
+		string(domain.CredentialTypeQueryAuth):  generic,
+	}
+}
+
+// WithCredentialTester registers the tester for credentials whose metadata.service_name or, when
+// no tester matches the service, credential type equals key
+func (u *CredentialUsecase) WithCredentialTester(key string, tester CredentialTester) *CredentialUsecase {
+	u.testers[strings.ToLower(key)] = tester
+	return u
+}
+
+// WithNetGuard sets the guard that blocks connection tests from reaching internal addresses
+func (u *CredentialUsecase) WithNetGuard(guard *netguard.Guard) *CredentialUsecase {
+	u.netGuard = guard
+	return u
+}
+
+// TestConnection validates a credential against its provider with the tester registered for its
+// service name, falling back to the tester for its credential type. actorID is the user running
+// the test, recorded in the access audit log.
+func (u *CredentialUsecase) TestConnection(ctx context.Context, tenantID, id uuid.UUID, actorID *uuid.UUID) (*CredentialTestResult, error) {
+	credential, err := u.GetDecrypted(ctx, tenantID, id, domain.CredentialAccess{
+		Purpose: domain.CredentialAccessConnectionTest,
+		ActorID: actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	provider, tester := u.testerFor(credential)
+	if tester == nil {
+		return nil, domain.NewValidationError("credential_type", fmt.Sprintf("connection test is not supported for %s credentials", credential.CredentialType))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialTestTimeout)
+	defer cancel()
+	if u.netGuard != nil {
+		ctx = netguard.WithContext(ctx, u.netGuard)
+	}
+
+	start := time.Now()
+	result, err := tester.Test(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = provider
+	result.DurationMs = int(time.Since(start).Milliseconds())
+	return result, nil
+}
+
+// testerFor returns the tester of a credential and the key it is registered under
+func (u *CredentialUsecase) testerFor(credential *domain.DecryptedCredential) (string, CredentialTester) {
+	if metadata := credentialMetadata(credential.Credential); metadata.ServiceName != "" {
+		service := strings.ToLower(metadata.ServiceName)
+		if tester, ok := u.testers[service]; ok {
+			return service, tester
+		}
+	}
+	credentialType := string(credential.CredentialType)
+	return credentialType, u.testers[credentialType]
+}
+
+// credentialMetadata returns the metadata of a credential, empty when it has none
+func credentialMetadata(credential *domain.Credential) *domain.CredentialMetadata {
+	if len(credential.Metadata) > 0 {
+		if metadata, err := domain.CredentialMetadataFromJSON(credential.Metadata); err == nil {
+			return metadata
+		}
+	}
+	return &domain.CredentialMetadata{}
+}
+
+// callProvider performs a connection test request with the HTTP adapter. Provider responses,
+// including 4xx/5xx ones, are returned as output; an error means no response was received.
+func callProvider(ctx context.Context, config adapter.HTTPConfig) (*adapter.HTTPOutput, error) {
+	config.TimeoutSec = int(credentialTestTimeout.Seconds())
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := adapter.NewHTTPAdapter().Execute(ctx, &adapter.Request{Config: configJSON})
+	if resp == nil {
+		return nil, err
+	}
+	var output adapter.HTTPOutput
+	if err := json.Unmarshal(resp.Output, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// unreachableResult reports a provider call that received no response
+func unreachableResult(err error) *CredentialTestResult {
+	return &CredentialTestResult{Message: fmt.Sprintf("could not reach the service: %v", err)}
+}
+
+// rejectedResult reports a provider response with an error status
+func rejectedResult(output *adapter.HTTPOutput) *CredentialTestResult {
+	message := fmt.Sprintf("service responded with status %d", output.StatusCode)
+	if output.StatusCode == http.StatusUnauthorized || output.StatusCode == http.StatusForbidden {
+		message = "credentials were rejected by the service"
+	}
+	return &CredentialTestResult{StatusCode: output.StatusCode, Message: message}
+}
+
+// credentialToken returns the token a credential authenticates with as a bearer token
+func credentialToken(data *domain.CredentialData) string {
+	if data.AccessToken != "" {
+		return data.AccessToken
+	}
+	return data.APIKey
+}
+
+// SlackCredentialTester validates Slack tokens with auth.test
+type SlackCredentialTester struct {
+	baseURL string
+}
+
+// NewSlackCredentialTester creates a Slack tester calling the Web API at baseURL
+func NewSlackCredentialTester(baseURL string) *SlackCredentialTester {
+	return &SlackCredentialTester{baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Test calls auth.test, which reports invalid tokens with "ok": false rather than a status code
+func (t *SlackCredentialTester) Test(ctx context.Context, credential *domain.DecryptedCredential) (*CredentialTestResult, error) {
+	output, err := callProvider(ctx, adapter.HTTPConfig{
+		URL:     t.baseURL + "/auth.test",
+		Method:  http.MethodPost,
+		Headers: map[string]string{"Authorization": "Bearer " + credentialToken(credential.Data)},
+	})
+	if err != nil {
+		return unreachableResult(err), nil
+	}
+	if output.StatusCode >= 400 {
+		return rejectedResult(output), nil
+	}
+
+	body, _ := output.Body.(map[string]interface{})
+	if ok, _ := body["ok"].(bool); !ok {
+		return &CredentialTestResult{StatusCode: output.StatusCode, Message: fmt.Sprintf("slack rejected the token: %v", body["error"])}, nil
+	}
+	details := make(map[string]interface{})
+	for _, key := range []string{"team", "team_id", "user", "user_id", "url"} {
+		if value, ok := body[key]; ok {
+			details[key] = value
+		}
+	}
+	return &CredentialTestResult{Success: true, StatusCode: output.StatusCode, Message: "authenticated with slack", Details: details}, nil
+}
+
+// GitHubCredentialTester validates GitHub tokens by fetching the authenticated user
+type GitHubCredentialTester struct {
+	baseURL string
+}
+
+// NewGitHubCredentialTester creates a GitHub tester calling the REST API at baseURL
+func NewGitHubCredentialTester(baseURL string) *GitHubCredentialTester {
+	return &GitHubCredentialTester{baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Test calls GET /user and reports the login and the token's scopes
+func (t *GitHubCredentialTester) Test(ctx context.Context, credential *domain.DecryptedCredential) (*CredentialTestResult, error) {
+	output, err := callProvider(ctx, adapter.HTTPConfig{
+		URL:    t.baseURL + "/user",
+		Method: http.MethodGet,
+		Headers: map[string]string{
+			"Authorization": "Bearer " + credentialToken(credential.Data),
+			"Accept":        "application/vnd.github+json",
+		},
+	})
+	if err != nil {
+		return unreachableResult(err), nil
+	}
+	if output.StatusCode >= 400 {
+		return rejectedResult(output), nil
+	}
+
+	details := make(map[string]interface{})
+	if body, ok := output.Body.(map[string]interface{}); ok && body["login"] != nil {
+		details["login"] = body["login"]
+	}
+	if scopes := output.Headers["X-Oauth-Scopes"]; scopes != "" {
+		details["scopes"] = scopes
+	}
+	return &CredentialTestResult{Success: true, StatusCode: output.StatusCode, Message: "authenticated with github", Details: details}, nil
+}
+
+// HTTPCredentialTester validates generic credentials with a HEAD request to metadata.service_url,
+// authenticated the way the credential type prescribes
+type HTTPCredentialTester struct{}
+
+// NewHTTPCredentialTester creates a tester for generic API credentials
+func NewHTTPCredentialTester() *HTTPCredentialTester {
+	return &HTTPCredentialTester{}
+}
+
+// Test sends a HEAD request to the credential's service URL
+func (t *HTTPCredentialTester) Test(ctx context.Context, credential *domain.DecryptedCredential) (*CredentialTestResult, error) {
+	serviceURL := credentialMetadata(credential.Credential).ServiceURL
+	if serviceURL == "" {
+		return nil, domain.NewValidationError("metadata.service_url", "service_url is required to test this credential")
+	}
+	if parsed, err := url.Parse(serviceURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, domain.NewValidationError("metadata.service_url", "service_url must be an http or https URL")
+	}
+
+	config := adapter.HTTPConfig{URL: serviceURL, Method: http.MethodHead, Headers: make(map[string]string)}
+	if name, value := credential.GetAuthHeader(); name != "" {
+		config.Headers[name] = value
+	}
+	for name, value := range credential.Data.Headers {
+		config.Headers[name] = value
+	}
+	if len(credential.Data.QueryParams) > 0 {
+		config.QueryParams = make(map[string]string, len(credential.Data.QueryParams))
+		for name, value := range credential.Data.QueryParams {
+			config.QueryParams[url.QueryEscape(name)] = url.QueryEscape(value)
+		}
+	}
+
+	output, err := callProvider(ctx, config)
+	if err != nil {
+		return unreachableResult(err), nil
+	}
+	if output.StatusCode >= 400 {
+		return rejectedResult(output), nil
+	}
+	return &CredentialTestResult{Success: true, StatusCode: output.StatusCode, Message: fmt.Sprintf("service responded with status %d", output.StatusCode)}, nil
+}
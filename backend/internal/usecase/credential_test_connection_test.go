@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/crypto"
+)
+
+func newCredentialTestUsecase(t *testing.T) (*CredentialUsecase, *mockCredentialRepoForShare, *crypto.Encryptor) {
+	t.Helper()
+	encryptor, err := crypto.NewEncryptorWithKey([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewEncryptorWithKey() error = %v", err)
+	}
+	repo := newMockCredentialRepoForShare()
+	return NewCredentialUsecase(repo, encryptor), repo, encryptor
+}
+
+func TestCredentialUsecase_TestConnection_Slack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth.test" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s, want POST /auth.test", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-valid" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "team": "Acme", "user": "bot", "team_id": "T1"}`))
+	}))
+	defer server.Close()
+
+	uc, repo, encryptor := newCredentialTestUsecase(t)
+	uc.WithCredentialTester("slack", NewSlackCredentialTester(server.URL))
+	tenantID := uuid.New()
+	valid := newEncryptedCredential(t, encryptor, tenantID, "xoxb-valid")
+	invalid := newEncryptedCredential(t, encryptor, tenantID, "xoxb-invalid")
+	for _, cred := range []*domain.Credential{valid, invalid} {
+		cred.Metadata = json.RawMessage(`{"service_name": "Slack"}`)
+		repo.addCredential(cred)
+	}
+
+	result, err := uc.TestConnection(context.Background(), tenantID, valid.ID, nil)
+	if err != nil {
+		t.Fatalf("TestConnection() error = %v", err)
+	}
+	if !result.Success || result.Provider != "slack" || result.Details["team"] != "Acme" {
+		t.Errorf("TestConnection() = %+v, want a successful slack test for team Acme", result)
+	}
+
+	result, err = uc.TestConnection(context.Background(), tenantID, invalid.ID, nil)
+	if err != nil {
+		t.Fatalf("TestConnection() error = %v", err)
+	}
+	if result.Success || result.Message != "slack rejected the token: invalid_auth" {
+		t.Errorf("TestConnection() with an invalid token = %+v, want the invalid_auth failure", result)
+	}
+}
+
+func TestCredentialUsecase_TestConnection_APIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		if r.Header.Get("Authorization") != "good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	uc, repo, encryptor := newCredentialTestUsecase(t)
+	tenantID := uuid.New()
+	good := newEncryptedCredential(t, encryptor, tenantID, "good-key")
+	bad := newEncryptedCredential(t, encryptor, tenantID, "bad-key")
+	for _, cred := range []*domain.Credential{good, bad} {
+		cred.Metadata = json.RawMessage(`{"service_url": "` + server.URL + `/v1/ping"}`)
+		repo.addCredential(cred)
+	}
+
+	tests := []struct {
+		name        string
+		id          uuid.UUID
+		wantSuccess bool
+		wantStatus  int
+	}{
+		{"accepted key", good.ID, true, http.StatusNoContent},
+		{"rejected key", bad.ID, false, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := uc.TestConnection(context.Background(), tenantID, tt.id, nil)
+			if err != nil {
+				t.Fatalf("TestConnection() error = %v", err)
+			}
+			if result.Success != tt.wantSuccess || result.StatusCode != tt.wantStatus || result.Provider != "api_key" {
+				t.Errorf("TestConnection() = %+v, want success = %v with status %d", result, tt.wantSuccess, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCredentialUsecase_TestConnection_Untestable(t *testing.T) {
+	uc, repo, encryptor := newCredentialTestUsecase(t)
+	tenantID := uuid.New()
+	noURL := newEncryptedCredential(t, encryptor, tenantID, "key")
+	custom := newEncryptedCredential(t, encryptor, tenantID, "custom")
+	custom.CredentialType = domain.CredentialTypeCustom
+	repo.addCredential(noURL)
+	repo.addCredential(custom)
+
+	for name, id := range map[string]uuid.UUID{"without service_url": noURL.ID, "custom type": custom.ID} {
+		_, err := uc.TestConnection(context.Background(), tenantID, id, nil)
+		var validationErr domain.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s: TestConnection() error = %v, want a validation error", name, err)
+		}
+	}
+}
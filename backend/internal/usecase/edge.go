@@ -131,6 +131,10 @@ func (u *EdgeUsecase) Create(ctx context.Context, input CreateEdgeInput) (*domai
 		// Group to group
 		newEdge = domain.NewGroupToGroupEdge(input.TenantID, input.ProjectID, *input.SourceBlockGroupID, *input.TargetBlockGroupID)
 	}
+	// Edges from a group keep the requested port (e.g. "error"), defaulting to "out"
+	if newEdge != nil && input.SourceBlockGroupID != nil && input.SourcePort != "" {
+		newEdge.SourcePort = input.SourcePort
+	}
 
 	// Only check for cycles on step-to-step edges for now
 	// TODO: Extend cycle detection to handle groups
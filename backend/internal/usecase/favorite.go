@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// FavoriteOwner identifies the user whose favorites filter a list
+type FavoriteOwner struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+}
+
+// WithFavoriteRepo sets the favorite repository used for favoriting and the favorites list filter
+func (u *ProjectUsecase) WithFavoriteRepo(repo repository.FavoriteRepository) *ProjectUsecase {
+	u.favoriteRepo = repo
+	return u
+}
+
+// Favorite pins a project for the user. Favoriting an already favorited project is a no-op.
+func (u *ProjectUsecase) Favorite(ctx context.Context, tenantID, userID, projectID uuid.UUID) error {
+	if _, err := u.projectRepo.GetByID(ctx, tenantID, projectID); err != nil {
+		return err
+	}
+	return u.favoriteRepo.Add(ctx, domain.NewFavorite(tenantID, userID, domain.FavoriteTargetProject, projectID))
+}
+
+// Unfavorite unpins a project for the user
+func (u *ProjectUsecase) Unfavorite(ctx context.Context, tenantID, userID, projectID uuid.UUID) error {
+	return u.favoriteRepo.Remove(ctx, tenantID, userID, domain.FavoriteTargetProject, projectID)
+}
+
+// WithFavoriteRepo sets the favorite repository used for favoriting and the favorites list filter
+func (u *TemplateUsecase) WithFavoriteRepo(repo repository.FavoriteRepository) *TemplateUsecase {
+	u.favoriteRepo = repo
+	return u
+}
+
+// Favorite pins a template the tenant can view for the user
+func (u *TemplateUsecase) Favorite(ctx context.Context, tenantID, userID, templateID uuid.UUID) error {
+	template, err := u.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if !template.CanView(tenantID) {
+		return domain.ErrTemplateNotFound
+	}
+	return u.favoriteRepo.Add(ctx, domain.NewFavorite(tenantID, userID, domain.FavoriteTargetTemplate, templateID))
+}
+
+// Unfavorite unpins a template for the user
+func (u *TemplateUsecase) Unfavorite(ctx context.Context, tenantID, userID, templateID uuid.UUID) error {
+	return u.favoriteRepo.Remove(ctx, tenantID, userID, domain.FavoriteTargetTemplate, templateID)
+}
+
+// favoriteIDs returns the user's favorited IDs of a target type.
+// The result is never nil, so it always restricts a list filter.
+func favoriteIDs(ctx context.Context, repo repository.FavoriteRepository, owner FavoriteOwner, targetType domain.FavoriteTargetType) ([]uuid.UUID, error) {
+	if repo == nil {
+		return []uuid.UUID{}, nil
+	}
+	ids, err := repo.ListTargetIDs(ctx, owner.TenantID, owner.UserID, targetType)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	return ids, nil
+}
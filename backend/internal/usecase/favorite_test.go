@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// mockFavoriteRepo is an in-memory FavoriteRepository
+type mockFavoriteRepo struct {
+	favorites []*domain.Favorite
+}
+
+func (m *mockFavoriteRepo) Add(ctx context.Context, favorite *domain.Favorite) error {
+	for _, f := range m.favorites {
+		if f.TenantID == favorite.TenantID && f.UserID == favorite.UserID &&
+			f.TargetType == favorite.TargetType && f.TargetID == favorite.TargetID {
+			return nil
+		}
+	}
+	m.favorites = append(m.favorites, favorite)
+	return nil
+}
+
+func (m *mockFavoriteRepo) Remove(ctx context.Context, tenantID, userID uuid.UUID, targetType domain.FavoriteTargetType, targetID uuid.UUID) error {
+	for i, f := range m.favorites {
+		if f.TenantID == tenantID && f.UserID == userID && f.TargetType == targetType && f.TargetID == targetID {
+			m.favorites = append(m.favorites[:i], m.favorites[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockFavoriteRepo) ListTargetIDs(ctx context.Context, tenantID, userID uuid.UUID, targetType domain.FavoriteTargetType) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, f := range m.favorites {
+		if f.TenantID == tenantID && f.UserID == userID && f.TargetType == targetType {
+			ids = append(ids, f.TargetID)
+		}
+	}
+	return ids, nil
+}
+
+func TestProjectUsecase_Favorite_FiltersListPerUser(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+
+	projectRepo := newMockProjectRepo()
+	pinned := domain.NewProject(tenantID, "Pinned", "")
+	other := domain.NewProject(tenantID, "Other", "")
+	projectRepo.projects[pinned.ID] = pinned
+	projectRepo.projects[other.ID] = other
+
+	favoriteRepo := &mockFavoriteRepo{}
+	uc := NewProjectUsecase(projectRepo, nil, nil, nil, nil).WithFavoriteRepo(favoriteRepo)
+
+	if err := uc.Favorite(ctx, tenantID, alice, pinned.ID); err != nil {
+		t.Fatalf("Favorite() error = %v", err)
+	}
+	// Favoriting twice is idempotent
+	if err := uc.Favorite(ctx, tenantID, alice, pinned.ID); err != nil {
+		t.Fatalf("Favorite() again error = %v", err)
+	}
+	if len(favoriteRepo.favorites) != 1 {
+		t.Fatalf("favorites = %d, want 1", len(favoriteRepo.favorites))
+	}
+
+	output, err := uc.List(ctx, ListProjectsInput{TenantID: tenantID, FavoritesOf: &alice})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(output.Projects) != 1 || output.Projects[0].ID != pinned.ID {
+		t.Errorf("alice's favorites = %v, want only %s", output.Projects, pinned.ID)
+	}
+
+	// Favorites are per-user: bob has none
+	output, err = uc.List(ctx, ListProjectsInput{TenantID: tenantID, FavoritesOf: &bob})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(output.Projects) != 0 {
+		t.Errorf("bob's favorites = %d projects, want 0", len(output.Projects))
+	}
+
+	// Without the filter every project is listed
+	output, _ = uc.List(ctx, ListProjectsInput{TenantID: tenantID})
+	if len(output.Projects) != 2 {
+		t.Errorf("unfiltered list = %d projects, want 2", len(output.Projects))
+	}
+
+	if err := uc.Unfavorite(ctx, tenantID, alice, pinned.ID); err != nil {
+		t.Fatalf("Unfavorite() error = %v", err)
+	}
+	output, _ = uc.List(ctx, ListProjectsInput{TenantID: tenantID, FavoritesOf: &alice})
+	if len(output.Projects) != 0 {
+		t.Errorf("after unfavorite = %d projects, want 0", len(output.Projects))
+	}
+}
+
+func TestProjectUsecase_Favorite_OtherTenantProject(t *testing.T) {
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(uuid.New(), "Foreign", "")
+	projectRepo.projects[project.ID] = project
+
+	uc := NewProjectUsecase(projectRepo, nil, nil, nil, nil).WithFavoriteRepo(&mockFavoriteRepo{})
+	if err := uc.Favorite(context.Background(), uuid.New(), uuid.New(), project.ID); !errors.Is(err, domain.ErrProjectNotFound) {
+		t.Errorf("Favorite() error = %v, want ErrProjectNotFound", err)
+	}
+}
+
+func TestTemplateUsecase_Favorite_FiltersList(t *testing.T) {
+	uc, templateRepo, _, template := setupTemplateUsecase(t)
+	ctx := context.Background()
+	uc.WithFavoriteRepo(&mockFavoriteRepo{})
+
+	unpinned := domain.NewProjectTemplate(nil, "Unpinned", "", nil)
+	unpinned.Visibility = domain.TemplateVisibilityPublic
+	templateRepo.templates[unpinned.ID] = unpinned
+
+	owner := FavoriteOwner{TenantID: uuid.New(), UserID: uuid.New()}
+	if err := uc.Favorite(ctx, owner.TenantID, owner.UserID, template.ID); err != nil {
+		t.Fatalf("Favorite() error = %v", err)
+	}
+
+	output, err := uc.List(ctx, ListTemplatesInput{FavoritesOf: &owner})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(output.Templates) != 1 || output.Templates[0].ID != template.ID {
+		t.Errorf("favorites = %v, want only %s", output.Templates, template.ID)
+	}
+
+	otherUser := FavoriteOwner{TenantID: owner.TenantID, UserID: uuid.New()}
+	output, _ = uc.List(ctx, ListTemplatesInput{FavoritesOf: &otherUser})
+	if len(output.Templates) != 0 {
+		t.Errorf("other user's favorites = %d templates, want 0", len(output.Templates))
+	}
+}
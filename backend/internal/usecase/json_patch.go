@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// applyJSONPatch applies a JSON Patch to the JSON representation of current and decodes the
+// result into patched. Only the top-level fields listed in patchable may change; a patch that
+// touches any other field (id, timestamps, ...) is rejected.
+func applyJSONPatch(current interface{}, patch domain.JSONPatch, patchable map[string]bool, patched interface{}) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	patchedDoc, err := patch.Apply(doc)
+	if err != nil {
+		return err
+	}
+
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(doc, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patchedDoc, &after); err != nil {
+		return domain.NewValidationError("patch", "patched document must be an object")
+	}
+	for _, fields := range []map[string]interface{}{before, after} {
+		for field := range fields {
+			if !patchable[field] && !reflect.DeepEqual(before[field], after[field]) {
+				return domain.NewValidationError(field, fmt.Sprintf("%s cannot be changed by a patch", field))
+			}
+		}
+	}
+
+	if err := json.Unmarshal(patchedDoc, patched); err != nil {
+		return domain.NewValidationError("patch", fmt.Sprintf("patched document is invalid: %v", err))
+	}
+	return nil
+}
+
+// isJSONObject reports whether raw is absent, null or a JSON object
+func isJSONObject(raw json.RawMessage) bool {
+	if len(raw) == 0 {
+		return true
+	}
+	var object map[string]interface{}
+	return json.Unmarshal(raw, &object) == nil
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func mustParseJSONPatch(t *testing.T, patch string) domain.JSONPatch {
+	t.Helper()
+	parsed, err := domain.ParseJSONPatch([]byte(patch))
+	if err != nil {
+		t.Fatalf("ParseJSONPatch() error = %v", err)
+	}
+	return parsed
+}
+
+// seedPatchStep creates an LLM step as read back from the database
+func seedPatchStep(stepRepo *mockStepRepo, tenantID, projectID uuid.UUID) *domain.Step {
+	step := domain.NewStep(tenantID, projectID, "Summarize", domain.StepTypeLLM,
+		json.RawMessage(`{"model":"gpt-4o","temperature":0.2,"prompt":"Summarize {{input}}"}`))
+	step.UpdatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stepRepo.steps[step.ID] = step
+	return step
+}
+
+func TestStepUsecase_Patch_SingleConfigField(t *testing.T) {
+	tenantID := uuid.New()
+	uc, stepRepo, project := newStepUsecaseForTypeTests(tenantID)
+	step := seedPatchStep(stepRepo, tenantID, project.ID)
+
+	patched, err := uc.Patch(context.Background(), PatchStepInput{
+		TenantID:  tenantID,
+		ProjectID: project.ID,
+		StepID:    step.ID,
+		Patch:     mustParseJSONPatch(t, `[{"op":"replace","path":"/config/model","value":"gpt-4o-mini"}]`),
+	})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(stepRepo.steps[step.ID].Config, &config); err != nil {
+		t.Fatalf("stored config is invalid: %v", err)
+	}
+	if config["model"] != "gpt-4o-mini" {
+		t.Errorf("model = %v, want gpt-4o-mini", config["model"])
+	}
+	if config["temperature"] != 0.2 || config["prompt"] != "Summarize {{input}}" {
+		t.Errorf("other config fields changed: %v", config)
+	}
+	if patched.Name != "Summarize" || patched.Type != domain.StepTypeLLM {
+		t.Errorf("Name = %q, Type = %q; want unchanged", patched.Name, patched.Type)
+	}
+	if !patched.UpdatedAt.After(step.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want after %v", patched.UpdatedAt, step.UpdatedAt)
+	}
+}
+
+func TestStepUsecase_Patch_RejectedAtomically(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		// The first operation is valid; the patched step as a whole is not
+		{name: "empty name", patch: `[{"op":"replace","path":"/config/model","value":"gpt-4o-mini"},{"op":"replace","path":"/name","value":""}]`},
+		{name: "unknown type", patch: `[{"op":"replace","path":"/config/model","value":"gpt-4o-mini"},{"op":"replace","path":"/type","value":"gpt"}]`},
+		{name: "config not an object", patch: `[{"op":"replace","path":"/config","value":["gpt-4o-mini"]}]`},
+		{name: "read-only field", patch: `[{"op":"replace","path":"/config/model","value":"gpt-4o-mini"},{"op":"replace","path":"/project_id","value":"` + uuid.NewString() + `"}]`},
+		{name: "failing operation", patch: `[{"op":"replace","path":"/config/model","value":"gpt-4o-mini"},{"op":"remove","path":"/config/missing"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, stepRepo, project := newStepUsecaseForTypeTests(tenantID)
+			step := seedPatchStep(stepRepo, tenantID, project.ID)
+			before := *step
+
+			_, err := uc.Patch(context.Background(), PatchStepInput{
+				TenantID:  tenantID,
+				ProjectID: project.ID,
+				StepID:    step.ID,
+				Patch:     mustParseJSONPatch(t, tt.patch),
+			})
+			if err == nil {
+				t.Fatal("Patch() error = nil, want rejection")
+			}
+
+			stored := stepRepo.steps[step.ID]
+			if string(stored.Config) != string(before.Config) || stored.Name != before.Name ||
+				stored.Type != before.Type || !stored.UpdatedAt.Equal(before.UpdatedAt) {
+				t.Errorf("step changed by a rejected patch: %+v", stored)
+			}
+		})
+	}
+}
+
+func TestStepUsecase_Patch_ConcurrentModification(t *testing.T) {
+	tenantID := uuid.New()
+	uc, stepRepo, project := newStepUsecaseForTypeTests(tenantID)
+	step := seedPatchStep(stepRepo, tenantID, project.ID)
+
+	// Another request writes the step while the patch is applied
+	concurrent := &concurrentStepRepo{mockStepRepo: stepRepo}
+	uc.stepRepo = concurrent
+
+	_, err := uc.Patch(context.Background(), PatchStepInput{
+		TenantID:  tenantID,
+		ProjectID: project.ID,
+		StepID:    step.ID,
+		Patch:     mustParseJSONPatch(t, `[{"op":"replace","path":"/name","value":"Renamed"}]`),
+	})
+	if !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Fatalf("Patch() error = %v, want ErrConcurrentModification", err)
+	}
+	if stepRepo.steps[step.ID].Name != "Concurrent" {
+		t.Errorf("Name = %q, want the concurrent write kept", stepRepo.steps[step.ID].Name)
+	}
+}
+
+// concurrentStepRepo simulates another request updating the step right after it is read
+type concurrentStepRepo struct {
+	*mockStepRepo
+}
+
+func (r *concurrentStepRepo) GetByID(ctx context.Context, tenantID, projectID, id uuid.UUID) (*domain.Step, error) {
+	step, err := r.mockStepRepo.GetByID(ctx, tenantID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	read := *step
+	step.Name = "Concurrent"
+	step.UpdatedAt = step.UpdatedAt.Add(time.Second)
+	return &read, nil
+}
+
+func TestProjectUsecase_Patch(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(tenantID, "Support Triage", "Routes tickets")
+	project.SetTags([]string{"support"})
+	projectRepo.projects[project.ID] = project
+	uc := &ProjectUsecase{projectRepo: projectRepo}
+
+	patched, err := uc.Patch(context.Background(), PatchProjectInput{
+		TenantID: tenantID,
+		ID:       project.ID,
+		Patch:    mustParseJSONPatch(t, `[{"op":"add","path":"/tags/-","value":"Urgent"},{"op":"add","path":"/run_retry","value":{"max_attempts":3}}]`),
+	})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if len(patched.Tags) != 2 || patched.Tags[0] != "support" || patched.Tags[1] != "urgent" {
+		t.Errorf("Tags = %v, want [support urgent]", patched.Tags)
+	}
+	if patched.RunRetry == nil || patched.RunRetry.MaxAttempts != 3 {
+		t.Errorf("RunRetry = %+v, want max_attempts 3", patched.RunRetry)
+	}
+	if patched.Name != "Support Triage" || patched.Description != "Routes tickets" {
+		t.Errorf("Name = %q, Description = %q; want unchanged", patched.Name, patched.Description)
+	}
+
+	// An invalid run retry policy rejects the whole patch
+	_, err = uc.Patch(context.Background(), PatchProjectInput{
+		TenantID: tenantID,
+		ID:       project.ID,
+		Patch:    mustParseJSONPatch(t, `[{"op":"replace","path":"/name","value":"Renamed"},{"op":"replace","path":"/run_retry/max_attempts","value":0}]`),
+	})
+	var validationErr domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Patch() error = %v, want ValidationError", err)
+	}
+	if projectRepo.projects[project.ID].Name != "Support Triage" {
+		t.Errorf("Name = %q, want unchanged after a rejected patch", projectRepo.projects[project.ID].Name)
+	}
+}
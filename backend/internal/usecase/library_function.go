@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/block/sandbox"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// LibraryFunctionUsecase manages the library functions of a tenant
+type LibraryFunctionUsecase struct {
+	functionRepo repository.LibraryFunctionRepository
+}
+
+// NewLibraryFunctionUsecase creates a new LibraryFunctionUsecase
+func NewLibraryFunctionUsecase(functionRepo repository.LibraryFunctionRepository) *LibraryFunctionUsecase {
+	return &LibraryFunctionUsecase{functionRepo: functionRepo}
+}
+
+// CreateLibraryFunctionInput represents input for creating a library function
+type CreateLibraryFunctionInput struct {
+	TenantID    uuid.UUID
+	Name        string
+	Description string
+	Code        string
+	CreatedBy   *uuid.UUID
+}
+
+// Create creates a library function
+func (u *LibraryFunctionUsecase) Create(ctx context.Context, input CreateLibraryFunctionInput) (*domain.LibraryFunction, error) {
+	function := domain.NewLibraryFunction(input.TenantID, input.Name, input.Description, input.Code, input.CreatedBy)
+	if err := validateLibraryFunction(function); err != nil {
+		return nil, err
+	}
+	if err := u.functionRepo.Create(ctx, function); err != nil {
+		return nil, err
+	}
+	return function, nil
+}
+
+// Get retrieves a library function
+func (u *LibraryFunctionUsecase) Get(ctx context.Context, tenantID, id uuid.UUID) (*domain.LibraryFunction, error) {
+	return u.functionRepo.GetByID(ctx, tenantID, id)
+}
+
+// List retrieves the library functions of a tenant
+func (u *LibraryFunctionUsecase) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.LibraryFunction, error) {
+	return u.functionRepo.ListByTenant(ctx, tenantID)
+}
+
+// UpdateLibraryFunctionInput represents input for updating a library function. Nil fields are
+// left unchanged. Renaming a library function breaks the scripts that require the old name.
+type UpdateLibraryFunctionInput struct {
+	TenantID    uuid.UUID
+	ID          uuid.UUID
+	Name        *string
+	Description *string
+	Code        *string
+}
+
+// Update updates a library function. Function steps load the new code from their next run.
+func (u *LibraryFunctionUsecase) Update(ctx context.Context, input UpdateLibraryFunctionInput) (*domain.LibraryFunction, error) {
+	function, err := u.functionRepo.GetByID(ctx, input.TenantID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		function.Name = *input.Name
+	}
+	if input.Description != nil {
+		function.Description = *input.Description
+	}
+	if input.Code != nil {
+		function.Code = *input.Code
+	}
+	if err := validateLibraryFunction(function); err != nil {
+		return nil, err
+	}
+	if err := u.functionRepo.Update(ctx, function); err != nil {
+		return nil, err
+	}
+	return function, nil
+}
+
+// Delete deletes a library function
+func (u *LibraryFunctionUsecase) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	return u.functionRepo.Delete(ctx, tenantID, id)
+}
+
+// validateLibraryFunction checks the fields of a library function and that its code compiles
+func validateLibraryFunction(function *domain.LibraryFunction) error {
+	if err := function.Validate(); err != nil {
+		return err
+	}
+	if err := sandbox.CheckLibrarySyntax(function.Code); err != nil {
+		return domain.NewValidationError("code", err.Error())
+	}
+	return nil
+}
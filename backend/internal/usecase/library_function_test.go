@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// mockLibraryFunctionRepo stores library functions in memory, unique by tenant and name
+type mockLibraryFunctionRepo struct {
+	repository.LibraryFunctionRepository
+	functions map[uuid.UUID]*domain.LibraryFunction
+}
+
+func (m *mockLibraryFunctionRepo) Create(ctx context.Context, function *domain.LibraryFunction) error {
+	for _, existing := range m.functions {
+		if existing.TenantID == function.TenantID && existing.Name == function.Name {
+			return domain.ErrLibraryFunctionExists
+		}
+	}
+	m.functions[function.ID] = function
+	return nil
+}
+
+func (m *mockLibraryFunctionRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.LibraryFunction, error) {
+	function, ok := m.functions[id]
+	if !ok || function.TenantID != tenantID {
+		return nil, domain.ErrLibraryFunctionNotFound
+	}
+	copied := *function
+	return &copied, nil
+}
+
+func (m *mockLibraryFunctionRepo) Update(ctx context.Context, function *domain.LibraryFunction) error {
+	m.functions[function.ID] = function
+	return nil
+}
+
+func TestLibraryFunctionUsecase_Create(t *testing.T) {
+	uc := NewLibraryFunctionUsecase(&mockLibraryFunctionRepo{functions: make(map[uuid.UUID]*domain.LibraryFunction)})
+	tenantID := uuid.New()
+
+	function, err := uc.Create(context.Background(), CreateLibraryFunctionInput{
+		TenantID: tenantID,
+		Name:     "text-utils",
+		Code:     `exports.slugify = function(s) { return s.toLowerCase(); };`,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if function.Name != "text-utils" || function.TenantID != tenantID {
+		t.Errorf("Create() = %+v", function)
+	}
+
+	_, err = uc.Create(context.Background(), CreateLibraryFunctionInput{TenantID: tenantID, Name: "text-utils", Code: `exports.x = 1;`})
+	if !errors.Is(err, domain.ErrLibraryFunctionExists) {
+		t.Errorf("Create() with a taken name error = %v, want ErrLibraryFunctionExists", err)
+	}
+}
+
+func TestLibraryFunctionUsecase_CreateValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input CreateLibraryFunctionInput
+		field string
+	}{
+		{"name must start with a letter", CreateLibraryFunctionInput{Name: "1utils", Code: `exports.x = 1;`}, "name"},
+		{"name must not contain dots", CreateLibraryFunctionInput{Name: "text.utils", Code: `exports.x = 1;`}, "name"},
+		{"code is required", CreateLibraryFunctionInput{Name: "utils", Code: "  "}, "code"},
+		{"code size is limited", CreateLibraryFunctionInput{Name: "utils", Code: strings.Repeat("x", domain.MaxLibraryFunctionCodeBytes+1)}, "code"},
+		{"code must compile", CreateLibraryFunctionInput{Name: "utils", Code: `exports.x = function( {`}, "code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewLibraryFunctionUsecase(&mockLibraryFunctionRepo{functions: make(map[uuid.UUID]*domain.LibraryFunction)})
+			_, err := uc.Create(context.Background(), tt.input)
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Create() error = %v, want a validation error of %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestLibraryFunctionUsecase_Update(t *testing.T) {
+	repo := &mockLibraryFunctionRepo{functions: make(map[uuid.UUID]*domain.LibraryFunction)}
+	uc := NewLibraryFunctionUsecase(repo)
+	tenantID := uuid.New()
+	function, err := uc.Create(context.Background(), CreateLibraryFunctionInput{TenantID: tenantID, Name: "utils", Code: `exports.x = 1;`})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	invalid := `exports.x = ;`
+	if _, err := uc.Update(context.Background(), UpdateLibraryFunctionInput{TenantID: tenantID, ID: function.ID, Code: &invalid}); err == nil {
+		t.Fatal("Update() with code that does not compile should fail")
+	}
+	if repo.functions[function.ID].Code != `exports.x = 1;` {
+		t.Error("a rejected update must not change the stored code")
+	}
+
+	code := `exports.x = 2;`
+	updated, err := uc.Update(context.Background(), UpdateLibraryFunctionInput{TenantID: tenantID, ID: function.ID, Code: &code})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Code != code || updated.Name != "utils" {
+		t.Errorf("Update() = %+v", updated)
+	}
+
+	if _, err := uc.Update(context.Background(), UpdateLibraryFunctionInput{TenantID: uuid.New(), ID: function.ID, Code: &code}); !errors.Is(err, domain.ErrLibraryFunctionNotFound) {
+		t.Errorf("Update() of another tenant's function error = %v, want ErrLibraryFunctionNotFound", err)
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// listPagination is the position of a list request: an offset page, or a keyset cursor returned
+// as the NextCursor of a previous page. A cursor takes precedence over the page.
+type listPagination struct {
+	Page  int
+	Limit int
+	After *repository.ListCursor
+}
+
+// newListPagination normalizes the page and limit and decodes the cursor
+func newListPagination(page, limit, defaultLimit int, cursor string) (listPagination, error) {
+	p := listPagination{}
+	p.Page, p.Limit = NormalizePaginationWithLimit(page, limit, defaultLimit)
+	if cursor != "" {
+		after, err := decodeListCursor(cursor)
+		if err != nil {
+			return p, err
+		}
+		p.Page = DefaultPage
+		p.After = after
+	}
+	return p, nil
+}
+
+// FetchLimit is the number of rows to request from the repository. Keyset pages fetch one extra
+// row to detect whether another page follows.
+func (p listPagination) FetchLimit() int {
+	if p.After != nil {
+		return p.Limit + 1
+	}
+	return p.Limit
+}
+
+// Next returns how many of the fetched rows belong to the page and whether another page follows
+// it, given the total number of matching rows
+func (p listPagination) Next(fetched, total int) (int, bool) {
+	if p.After != nil {
+		if fetched > p.Limit {
+			return p.Limit, true
+		}
+		return fetched, false
+	}
+	return fetched, fetched > 0 && p.Page*p.Limit < total
+}
+
+// encodeListCursor encodes a keyset position as an opaque, URL-safe string
+func encodeListCursor(cursor repository.ListCursor) string {
+	raw := cursor.Time.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeListCursor decodes a cursor produced by encodeListCursor
+func decodeListCursor(s string) (*repository.ListCursor, error) {
+	invalid := domain.NewValidationError("cursor", "invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	timeStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, invalid
+	}
+	t, err := time.Parse(time.RFC3339Nano, timeStr)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, invalid
+	}
+	return &repository.ListCursor{Time: t, ID: id}, nil
+}
+
+// pageCursorPrefix marks cursors that hold a page number instead of a keyset position
+const pageCursorPrefix = "page|"
+
+// encodePageCursor encodes the number of the next page of a ranked list as an opaque cursor.
+// Rankings such as template downloads and ratings change between requests, so ranked lists
+// resume by position rather than by keyset.
+func encodePageCursor(page int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageCursorPrefix + strconv.Itoa(page)))
+}
+
+// decodePageCursor decodes a cursor produced by encodePageCursor
+func decodePageCursor(s string) (int, error) {
+	invalid := domain.NewValidationError("cursor", "invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, invalid
+	}
+	pageStr, ok := strings.CutPrefix(string(raw), pageCursorPrefix)
+	if !ok {
+		return 0, invalid
+	}
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		return 0, invalid
+	}
+	return page, nil
+}
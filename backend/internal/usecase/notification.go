@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+	"github.com/souta/ai-orchestration/pkg/netguard"
+	"github.com/souta/ai-orchestration/pkg/webhook"
+)
+
+// notificationTimeout bounds a single delivery attempt
+const notificationTimeout = 10 * time.Second
+
+// Default delivery retries: up to 3 attempts, waiting 1s then 2s between them
+const (
+	defaultNotificationAttempts = 3
+	defaultNotificationBackoff  = time.Second
+)
+
+// NotificationService delivers notifications through one channel type
+type NotificationService interface {
+	Send(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) error
+}
+
+// NotificationDeliveryError is returned by notification services when a delivery fails.
+// Retryable marks transient failures (no response, 429 or 5xx) worth another attempt.
+type NotificationDeliveryError struct {
+	StatusCode int // Zero when no response was received
+	Retryable  bool
+	Err        error
+}
+
+func (e *NotificationDeliveryError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("notification delivery failed with status %d: %v", e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("notification delivery failed: %v", e.Err)
+}
+
+func (e *NotificationDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// permanentDeliveryError reports a delivery that cannot succeed on retry, such as a missing setting
+func permanentDeliveryError(format string, args ...interface{}) error {
+	return &NotificationDeliveryError{Err: fmt.Errorf(format, args...)}
+}
+
+// postNotification POSTs a JSON body and classifies the outcome. Requests are made through the
+// guard carried by ctx, if any, so channels cannot reach internal addresses.
+func postNotification(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return permanentDeliveryError("invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{}
+	if guard := netguard.FromContext(ctx); guard != nil {
+		transport := guard.Transport()
+		defer transport.CloseIdleConnections()
+		client.Transport = transport
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		blocked := errors.Is(err, netguard.ErrBlocked) || errors.Is(err, netguard.ErrDomainNotPermitted)
+		return &NotificationDeliveryError{Retryable: !blocked, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	return &NotificationDeliveryError{
+		StatusCode: resp.StatusCode,
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		Err:        errors.New(responseErrorMessage(resp)),
+	}
+}
+
+// responseErrorMessage describes a failed delivery with the start of the response body, or
+// with the status text alone when the body cannot be read
+func responseErrorMessage(resp *http.Response) string {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return fmt.Sprintf("%s (response body unreadable: %v)", http.StatusText(resp.StatusCode), err)
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// notificationText renders a notification as plain text
+func notificationText(notification domain.Notification) string {
+	text := notification.Message
+	if notification.URL != "" {
+		text += "\n" + notification.URL
+	}
+	return text
+}
+
+// SlackNotificationService posts notifications to Slack incoming webhooks
+type SlackNotificationService struct{}
+
+// NewSlackNotificationService creates a new SlackNotificationService
+func NewSlackNotificationService() *SlackNotificationService {
+	return &SlackNotificationService{}
+}
+
+// Send posts the notification to the channel's webhook_url
+func (s *SlackNotificationService) Send(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) error {
+	var config domain.SlackChannelConfig
+	if err := json.Unmarshal(channel.Config, &config); err != nil {
+		return permanentDeliveryError("invalid slack config: %w", err)
+	}
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", notification.Title, notificationText(notification)),
+	})
+	if err != nil {
+		return permanentDeliveryError("failed to marshal slack message: %w", err)
+	}
+	return postNotification(ctx, config.WebhookURL, nil, body)
+}
+
+// SendGridNotificationService sends notifications as email through the SendGrid v3 API
+type SendGridNotificationService struct {
+	baseURL string
+	apiKey  string
+	from    string // Sender when the channel sets none
+}
+
+// NewSendGridNotificationService creates a SendGrid service calling the API at baseURL
+func NewSendGridNotificationService(baseURL, apiKey, from string) *SendGridNotificationService {
+	return &SendGridNotificationService{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, from: from}
+}
+
+// Send emails the notification to the channel's recipients
+func (s *SendGridNotificationService) Send(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) error {
+	if s.apiKey == "" {
+		return permanentDeliveryError("SENDGRID_API_KEY is not configured")
+	}
+	var config domain.EmailChannelConfig
+	if err := json.Unmarshal(channel.Config, &config); err != nil {
+		return permanentDeliveryError("invalid email config: %w", err)
+	}
+	from := config.From
+	if from == "" {
+		from = s.from
+	}
+	if from == "" {
+		return permanentDeliveryError("no sender: set config.from or NOTIFICATION_EMAIL_FROM")
+	}
+
+	recipients := make([]map[string]string, len(config.To))
+	for i, address := range config.To {
+		recipients[i] = map[string]string{"email": address}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"personalizations": []map[string]interface{}{{"to": recipients}},
+		"from":             map[string]string{"email": from},
+		"subject":          notification.Title,
+		"content":          []map[string]string{{"type": "text/plain", "value": notificationText(notification)}},
+	})
+	if err != nil {
+		return permanentDeliveryError("failed to marshal email: %w", err)
+	}
+	return postNotification(ctx, s.baseURL+"/v3/mail/send", map[string]string{"Authorization": "Bearer " + s.apiKey}, body)
+}
+
+// WebhookNotificationService posts notifications as JSON to a URL
+type WebhookNotificationService struct{}
+
+// NewWebhookNotificationService creates a new WebhookNotificationService
+func NewWebhookNotificationService() *WebhookNotificationService {
+	return &WebhookNotificationService{}
+}
+
+// Send posts the notification with the channel's headers. With a secret, the body is signed in
+// X-Webhook-Signature as "sha256=" + hex HMAC-SHA256, the scheme webhook triggers verify.
+func (s *WebhookNotificationService) Send(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) error {
+	var config domain.WebhookChannelConfig
+	if err := json.Unmarshal(channel.Config, &config); err != nil {
+		return permanentDeliveryError("invalid webhook config: %w", err)
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return permanentDeliveryError("failed to marshal notification: %w", err)
+	}
+	headers := make(map[string]string, len(config.Headers)+1)
+	for key, value := range config.Headers {
+		headers[key] = value
+	}
+	if config.Secret != "" {
+		headers["X-Webhook-Signature"] = "sha256=" + webhook.Sign(config.Secret, body)
+	}
+	return postNotification(ctx, config.URL, headers, body)
+}
+
+// NotificationDispatcher delivers system event notifications to the notification channels of a
+// tenant. Each delivery is retried on transient failures and recorded in the audit log.
+type NotificationDispatcher struct {
+	channelRepo  repository.NotificationChannelRepository
+	services     map[domain.NotificationChannelType]NotificationService
+	auditService *AuditService
+	netGuard     *netguard.Guard
+	logger       *slog.Logger
+	maxAttempts  int
+	backoff      time.Duration
+	sleep        func(ctx context.Context, d time.Duration) error
+}
+
+// NewNotificationDispatcher creates a dispatcher with the Slack, SendGrid (SENDGRID_API_KEY,
+// NOTIFICATION_EMAIL_FROM) and webhook services
+func NewNotificationDispatcher(channelRepo repository.NotificationChannelRepository, auditService *AuditService, logger *slog.Logger) *NotificationDispatcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &NotificationDispatcher{
+		channelRepo: channelRepo,
+		services: map[domain.NotificationChannelType]NotificationService{
+			domain.NotificationChannelSlack:   NewSlackNotificationService(),
+			domain.NotificationChannelEmail:   NewSendGridNotificationService("https://api.sendgrid.com", os.Getenv("SENDGRID_API_KEY"), os.Getenv("NOTIFICATION_EMAIL_FROM")),
+			domain.NotificationChannelWebhook: NewWebhookNotificationService(),
+		},
+		auditService: auditService,
+		logger:       logger,
+		maxAttempts:  defaultNotificationAttempts,
+		backoff:      defaultNotificationBackoff,
+		sleep:        sleepContext,
+	}
+}
+
+// WithNotificationService registers the service delivering notifications of a channel type
+func (d *NotificationDispatcher) WithNotificationService(channelType domain.NotificationChannelType, service NotificationService) *NotificationDispatcher {
+	d.services[channelType] = service
+	return d
+}
+
+// WithNetGuard sets the guard that blocks deliveries to internal addresses
+func (d *NotificationDispatcher) WithNetGuard(guard *netguard.Guard) *NotificationDispatcher {
+	d.netGuard = guard
+	return d
+}
+
+// WithRetry sets the attempts per delivery and the backoff before the second attempt, doubled
+// before each further one
+func (d *NotificationDispatcher) WithRetry(maxAttempts int, backoff time.Duration) *NotificationDispatcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	d.maxAttempts = maxAttempts
+	d.backoff = backoff
+	return d
+}
+
+// Notify delivers a notification to every enabled channel of the tenant subscribed to its event.
+// Failed deliveries are joined into the returned error; the other channels are still notified.
+func (d *NotificationDispatcher) Notify(ctx context.Context, tenantID uuid.UUID, notification domain.Notification) error {
+	channels, err := d.channelRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to list notification channels: %w", err)
+	}
+	var errs []error
+	for _, channel := range channels {
+		if !channel.Enabled || !channel.Subscribes(notification.Event) {
+			continue
+		}
+		if err := d.deliver(ctx, channel, notification); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyChannel delivers a notification to one channel of the tenant, whatever events it
+// subscribes to. A disabled channel is a validation error.
+func (d *NotificationDispatcher) NotifyChannel(ctx context.Context, tenantID, channelID uuid.UUID, notification domain.Notification) error {
+	channel, err := d.channelRepo.GetByID(ctx, tenantID, channelID)
+	if err != nil {
+		return err
+	}
+	if !channel.Enabled {
+		return domain.NewValidationError("enabled", "notification channel is disabled")
+	}
+	return d.deliver(ctx, channel, notification)
+}
+
+// deliver sends a notification to a channel, retrying transient failures with exponential
+// backoff, and audit-logs the outcome
+func (d *NotificationDispatcher) deliver(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) error {
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now().UTC()
+	}
+	if d.netGuard != nil {
+		ctx = netguard.WithContext(ctx, d.netGuard)
+	}
+
+	attempts, err := d.send(ctx, channel, notification)
+
+	metadata := map[string]interface{}{
+		"event":        notification.Event,
+		"channel_type": channel.Type,
+		"attempts":     attempts,
+		"success":      err == nil,
+	}
+	if err != nil {
+		metadata["error"] = err.Error()
+		d.logger.Error("Notification delivery failed",
+			"channel_id", channel.ID,
+			"event", notification.Event,
+			"attempts", attempts,
+			"error", err,
+		)
+	}
+	if d.auditService != nil {
+		_ = d.auditService.Log(ctx, LogAuditInput{
+			TenantID:     channel.TenantID,
+			Action:       domain.AuditActionNotificationDeliver,
+			ResourceType: domain.AuditResourceNotificationChannel,
+			ResourceID:   &channel.ID,
+			Metadata:     metadata,
+		})
+	}
+	return err
+}
+
+// send calls the channel type's service until it succeeds, fails permanently or runs out of
+// attempts, and returns the number of attempts made
+func (d *NotificationDispatcher) send(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) (int, error) {
+	service, ok := d.services[channel.Type]
+	if !ok {
+		return 0, permanentDeliveryError("no notification service for channel type %s", channel.Type)
+	}
+	for attempt := 1; ; attempt++ {
+		err := service.Send(ctx, channel, notification)
+		if err == nil || attempt >= d.maxAttempts || !isRetryableDelivery(ctx, err) {
+			return attempt, err
+		}
+		d.logger.Warn("Notification delivery failed, retrying",
+			"channel_id", channel.ID,
+			"event", notification.Event,
+			"attempt", attempt,
+			"error", err,
+		)
+		if sleepErr := d.sleep(ctx, d.backoff<<(attempt-1)); sleepErr != nil {
+			return attempt, err
+		}
+	}
+}
+
+// isRetryableDelivery reports whether a failed delivery may succeed on another attempt. Errors of
+// services other than NotificationDeliveryError are assumed to be transient.
+func isRetryableDelivery(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var deliveryErr *NotificationDeliveryError
+	if errors.As(err, &deliveryErr) {
+		return deliveryErr.Retryable
+	}
+	return true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
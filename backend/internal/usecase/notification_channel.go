@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// NotificationChannelUsecase manages the notification channels of a tenant
+type NotificationChannelUsecase struct {
+	channelRepo repository.NotificationChannelRepository
+	dispatcher  *NotificationDispatcher
+}
+
+// NewNotificationChannelUsecase creates a new NotificationChannelUsecase
+func NewNotificationChannelUsecase(channelRepo repository.NotificationChannelRepository, dispatcher *NotificationDispatcher) *NotificationChannelUsecase {
+	return &NotificationChannelUsecase{
+		channelRepo: channelRepo,
+		dispatcher:  dispatcher,
+	}
+}
+
+// CreateNotificationChannelInput represents input for creating a notification channel
+type CreateNotificationChannelInput struct {
+	TenantID  uuid.UUID
+	Name      string
+	Type      domain.NotificationChannelType
+	Config    json.RawMessage
+	Events    []domain.NotificationEvent
+	Enabled   *bool
+	CreatedBy *uuid.UUID
+}
+
+// Create creates a notification channel
+func (u *NotificationChannelUsecase) Create(ctx context.Context, input CreateNotificationChannelInput) (*domain.NotificationChannel, error) {
+	channel := domain.NewNotificationChannel(input.TenantID, input.Name, input.Type, input.Config, input.Events, input.CreatedBy)
+	if input.Enabled != nil {
+		channel.Enabled = *input.Enabled
+	}
+	if channel.Events == nil {
+		channel.Events = []domain.NotificationEvent{}
+	}
+	if err := channel.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.channelRepo.Create(ctx, channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// Get retrieves a notification channel
+func (u *NotificationChannelUsecase) Get(ctx context.Context, tenantID, id uuid.UUID) (*domain.NotificationChannel, error) {
+	return u.channelRepo.GetByID(ctx, tenantID, id)
+}
+
+// List retrieves the notification channels of a tenant
+func (u *NotificationChannelUsecase) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.NotificationChannel, error) {
+	return u.channelRepo.ListByTenant(ctx, tenantID)
+}
+
+// UpdateNotificationChannelInput represents input for updating a notification channel. Nil
+// fields are left unchanged; the type of a channel cannot change.
+type UpdateNotificationChannelInput struct {
+	TenantID uuid.UUID
+	ID       uuid.UUID
+	Name     *string
+	Config   json.RawMessage
+	Events   []domain.NotificationEvent
+	Enabled  *bool
+}
+
+// Update updates a notification channel
+func (u *NotificationChannelUsecase) Update(ctx context.Context, input UpdateNotificationChannelInput) (*domain.NotificationChannel, error) {
+	channel, err := u.channelRepo.GetByID(ctx, input.TenantID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		channel.Name = *input.Name
+	}
+	if input.Config != nil {
+		channel.Config = input.Config
+	}
+	if input.Events != nil {
+		channel.Events = input.Events
+	}
+	if input.Enabled != nil {
+		channel.Enabled = *input.Enabled
+	}
+	if err := channel.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.channelRepo.Update(ctx, channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// Delete deletes a notification channel
+func (u *NotificationChannelUsecase) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	return u.channelRepo.Delete(ctx, tenantID, id)
+}
+
+// Test sends a test notification to a channel. Delivery failures are returned as errors, after
+// the dispatcher's retries.
+func (u *NotificationChannelUsecase) Test(ctx context.Context, tenantID, id uuid.UUID) error {
+	return u.dispatcher.NotifyChannel(ctx, tenantID, id, domain.Notification{
+		Event:   domain.NotificationEventTest,
+		Title:   "Test notification",
+		Message: "This channel is configured to receive notifications from AI Orchestration.",
+	})
+}
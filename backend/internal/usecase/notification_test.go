@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/pkg/webhook"
+)
+
+// mockNotificationChannelRepo stores notification channels in memory
+type mockNotificationChannelRepo struct {
+	channels map[uuid.UUID]*domain.NotificationChannel
+}
+
+func newMockNotificationChannelRepo(channels ...*domain.NotificationChannel) *mockNotificationChannelRepo {
+	repo := &mockNotificationChannelRepo{channels: make(map[uuid.UUID]*domain.NotificationChannel)}
+	for _, channel := range channels {
+		repo.channels[channel.ID] = channel
+	}
+	return repo
+}
+
+func (m *mockNotificationChannelRepo) Create(ctx context.Context, channel *domain.NotificationChannel) error {
+	m.channels[channel.ID] = channel
+	return nil
+}
+
+func (m *mockNotificationChannelRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.NotificationChannel, error) {
+	channel, ok := m.channels[id]
+	if !ok || channel.TenantID != tenantID {
+		return nil, domain.ErrNotificationChannelNotFound
+	}
+	return channel, nil
+}
+
+func (m *mockNotificationChannelRepo) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.NotificationChannel, error) {
+	var channels []*domain.NotificationChannel
+	for _, channel := range m.channels {
+		if channel.TenantID == tenantID {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+func (m *mockNotificationChannelRepo) Update(ctx context.Context, channel *domain.NotificationChannel) error {
+	m.channels[channel.ID] = channel
+	return nil
+}
+
+func (m *mockNotificationChannelRepo) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	delete(m.channels, id)
+	return nil
+}
+
+// mockNotificationService fails with the scripted errors, then succeeds
+type mockNotificationService struct {
+	errs      []error
+	delivered []domain.Notification
+	channels  []uuid.UUID // Channels of the delivered notifications
+	calls     int
+}
+
+func (m *mockNotificationService) Send(ctx context.Context, channel *domain.NotificationChannel, notification domain.Notification) error {
+	m.calls++
+	if m.calls <= len(m.errs) {
+		return m.errs[m.calls-1]
+	}
+	m.delivered = append(m.delivered, notification)
+	m.channels = append(m.channels, channel.ID)
+	return nil
+}
+
+// newTestDispatcher returns a dispatcher delivering webhook channels through service, recording
+// the backoff waits instead of sleeping
+func newTestDispatcher(repo *mockNotificationChannelRepo, service NotificationService, auditRepo *mockAuditLogRepo) (*NotificationDispatcher, *[]time.Duration) {
+	dispatcher := NewNotificationDispatcher(repo, NewAuditService(auditRepo), nil).
+		WithNotificationService(domain.NotificationChannelWebhook, service)
+	var waits []time.Duration
+	dispatcher.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return dispatcher, &waits
+}
+
+func newWebhookChannel(tenantID uuid.UUID, events ...domain.NotificationEvent) *domain.NotificationChannel {
+	return domain.NewNotificationChannel(tenantID, "ops", domain.NotificationChannelWebhook,
+		json.RawMessage(`{"url": "https://hooks.example.com/ops"}`), events, nil)
+}
+
+func TestNotificationDispatcher_Notify_DeliversToSubscribedChannels(t *testing.T) {
+	tenantID := uuid.New()
+	subscribed := newWebhookChannel(tenantID, domain.NotificationEventRunFailed)
+	other := newWebhookChannel(tenantID, domain.NotificationEventBudgetAlert)
+	disabled := newWebhookChannel(tenantID)
+	disabled.Enabled = false
+	service := &mockNotificationService{}
+	auditRepo := &mockAuditLogRepo{}
+	dispatcher, _ := newTestDispatcher(newMockNotificationChannelRepo(subscribed, other, disabled), service, auditRepo)
+
+	err := dispatcher.Notify(context.Background(), tenantID, domain.Notification{Event: domain.NotificationEventRunFailed, Title: "Run failed"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if len(service.delivered) != 1 || service.delivered[0].Title != "Run failed" {
+		t.Fatalf("delivered = %+v, want the notification once", service.delivered)
+	}
+	if service.delivered[0].CreatedAt.IsZero() {
+		t.Error("delivered notification has no created_at")
+	}
+	if len(auditRepo.logs) != 1 {
+		t.Fatalf("audit logs = %d, want 1", len(auditRepo.logs))
+	}
+	log := auditRepo.logs[0]
+	if log.Action != domain.AuditActionNotificationDeliver || *log.ResourceID != subscribed.ID {
+		t.Errorf("audit log = %s on %v, want notification.deliver on %s", log.Action, *log.ResourceID, subscribed.ID)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(log.Metadata, &metadata); err != nil {
+		t.Fatalf("invalid audit metadata: %v", err)
+	}
+	if metadata["success"] != true || metadata["attempts"] != float64(1) {
+		t.Errorf("audit metadata = %v, want success after 1 attempt", metadata)
+	}
+}
+
+func TestNotificationDispatcher_Notify_RetriesTransientFailures(t *testing.T) {
+	tenantID := uuid.New()
+	channel := newWebhookChannel(tenantID)
+	service := &mockNotificationService{errs: []error{
+		&NotificationDeliveryError{StatusCode: http.StatusServiceUnavailable, Retryable: true, Err: errors.New("unavailable")},
+		&NotificationDeliveryError{Retryable: true, Err: errors.New("connection reset")},
+	}}
+	auditRepo := &mockAuditLogRepo{}
+	dispatcher, waits := newTestDispatcher(newMockNotificationChannelRepo(channel), service, auditRepo)
+
+	err := dispatcher.Notify(context.Background(), tenantID, domain.Notification{Event: domain.NotificationEventBudgetAlert})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if service.calls != 3 || len(service.delivered) != 1 {
+		t.Fatalf("calls = %d, delivered = %d, want delivery on the third attempt", service.calls, len(service.delivered))
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second}; len(*waits) != 2 || (*waits)[0] != want[0] || (*waits)[1] != want[1] {
+		t.Errorf("backoff = %v, want %v", *waits, want)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(auditRepo.logs[0].Metadata, &metadata); err != nil {
+		t.Fatalf("invalid audit metadata: %v", err)
+	}
+	if metadata["success"] != true || metadata["attempts"] != float64(3) {
+		t.Errorf("audit metadata = %v, want success after 3 attempts", metadata)
+	}
+}
+
+func TestNotificationDispatcher_Notify_GivesUpAfterMaxAttempts(t *testing.T) {
+	tenantID := uuid.New()
+	channel := newWebhookChannel(tenantID)
+	transient := &NotificationDeliveryError{StatusCode: http.StatusBadGateway, Retryable: true, Err: errors.New("bad gateway")}
+	service := &mockNotificationService{errs: []error{transient, transient, transient}}
+	auditRepo := &mockAuditLogRepo{}
+	dispatcher, _ := newTestDispatcher(newMockNotificationChannelRepo(channel), service, auditRepo)
+
+	err := dispatcher.Notify(context.Background(), tenantID, domain.Notification{Event: domain.NotificationEventRunFailed})
+
+	if !errors.Is(err, transient) {
+		t.Fatalf("Notify() error = %v, want the last delivery error", err)
+	}
+	if service.calls != defaultNotificationAttempts {
+		t.Errorf("calls = %d, want %d", service.calls, defaultNotificationAttempts)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(auditRepo.logs[0].Metadata, &metadata); err != nil {
+		t.Fatalf("invalid audit metadata: %v", err)
+	}
+	if metadata["success"] != false || metadata["error"] == nil {
+		t.Errorf("audit metadata = %v, want a failed delivery with its error", metadata)
+	}
+}
+
+func TestNotificationDispatcher_Notify_DoesNotRetryPermanentFailures(t *testing.T) {
+	tenantID := uuid.New()
+	channel := newWebhookChannel(tenantID)
+	service := &mockNotificationService{errs: []error{
+		&NotificationDeliveryError{StatusCode: http.StatusNotFound, Err: errors.New("no such hook")},
+	}}
+	dispatcher, waits := newTestDispatcher(newMockNotificationChannelRepo(channel), service, &mockAuditLogRepo{})
+
+	if err := dispatcher.Notify(context.Background(), tenantID, domain.Notification{Event: domain.NotificationEventRunFailed}); err == nil {
+		t.Fatal("Notify() error = nil, want the delivery error")
+	}
+	if service.calls != 1 || len(*waits) != 0 {
+		t.Errorf("calls = %d, waits = %v, want a single attempt", service.calls, *waits)
+	}
+}
+
+func TestNotificationDispatcher_NotifyChannel_IgnoresSubscriptions(t *testing.T) {
+	tenantID := uuid.New()
+	channel := newWebhookChannel(tenantID, domain.NotificationEventBudgetAlert)
+	service := &mockNotificationService{}
+	dispatcher, _ := newTestDispatcher(newMockNotificationChannelRepo(channel), service, &mockAuditLogRepo{})
+
+	err := dispatcher.NotifyChannel(context.Background(), tenantID, channel.ID, domain.Notification{Event: domain.NotificationEventTest})
+	if err != nil {
+		t.Fatalf("NotifyChannel() error = %v", err)
+	}
+	if len(service.delivered) != 1 {
+		t.Errorf("delivered = %d, want 1", len(service.delivered))
+	}
+
+	if err := dispatcher.NotifyChannel(context.Background(), uuid.New(), channel.ID, domain.Notification{}); !errors.Is(err, domain.ErrNotificationChannelNotFound) {
+		t.Errorf("NotifyChannel() of another tenant error = %v, want ErrNotificationChannelNotFound", err)
+	}
+}
+
+func TestWebhookNotificationService_SignsBody(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(domain.WebhookChannelConfig{URL: server.URL, Secret: "s3cret", Headers: map[string]string{"X-Team": "ops"}})
+	channel := domain.NewNotificationChannel(uuid.New(), "ops", domain.NotificationChannelWebhook, config, nil, nil)
+	err := NewWebhookNotificationService().Send(context.Background(), channel, domain.Notification{Event: domain.NotificationEventRunFailed, Title: "Run failed"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if received.Header.Get("X-Team") != "ops" {
+		t.Errorf("X-Team = %q, want the channel header", received.Header.Get("X-Team"))
+	}
+	if err := (webhook.HMACVerifier{Header: "X-Webhook-Signature"}).Verify(received.Header, body, "s3cret"); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+	var notification domain.Notification
+	if err := json.Unmarshal(body, &notification); err != nil || notification.Title != "Run failed" {
+		t.Errorf("body = %s, want the notification", body)
+	}
+}
+
+func TestPostNotification_ClassifiesStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadRequest, false},
+		{http.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := postNotification(context.Background(), server.URL, nil, []byte(`{}`))
+
+			var deliveryErr *NotificationDeliveryError
+			if !errors.As(err, &deliveryErr) {
+				t.Fatalf("error = %v, want a NotificationDeliveryError", err)
+			}
+			if deliveryErr.StatusCode != tt.status || deliveryErr.Retryable != tt.retryable {
+				t.Errorf("status = %d, retryable = %v, want %d, %v", deliveryErr.StatusCode, deliveryErr.Retryable, tt.status, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestPostNotification_UnreadableBodyFallsBackToStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body ends before the declared length, so reading it fails
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("partial"))
+	}))
+	defer server.Close()
+
+	err := postNotification(context.Background(), server.URL, nil, []byte(`{}`))
+
+	var deliveryErr *NotificationDeliveryError
+	if !errors.As(err, &deliveryErr) {
+		t.Fatalf("error = %v, want a NotificationDeliveryError", err)
+	}
+	if deliveryErr.StatusCode != http.StatusBadGateway || !deliveryErr.Retryable {
+		t.Errorf("status = %d, retryable = %v, want %d, true", deliveryErr.StatusCode, deliveryErr.Retryable, http.StatusBadGateway)
+	}
+	if !strings.Contains(err.Error(), "Bad Gateway (response body unreadable") {
+		t.Errorf("error = %q, want the status text with the read failure", err.Error())
+	}
+}
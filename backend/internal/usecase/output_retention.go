@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// outputRetentionTenantPageSize is the number of tenants loaded per page by a retention sweep
+const outputRetentionTenantPageSize = 100
+
+// OutputRetentionUsecase clears step run payloads that are older than each tenant's
+// limits.output_retention_days, while runs, step run status/timing and usage records are kept
+type OutputRetentionUsecase struct {
+	tenantRepo  repository.TenantRepository
+	stepRunRepo repository.StepRunRepository
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+// NewOutputRetentionUsecase creates a new OutputRetentionUsecase
+func NewOutputRetentionUsecase(tenantRepo repository.TenantRepository, stepRunRepo repository.StepRunRepository, logger *slog.Logger) *OutputRetentionUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &OutputRetentionUsecase{
+		tenantRepo:  tenantRepo,
+		stepRunRepo: stepRunRepo,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// ClearExpiredOutputs clears the step run inputs and outputs of every tenant with an output
+// retention window, and returns the number of step runs cleared. A tenant that fails is logged
+// and skipped so one bad tenant does not stall the sweep.
+func (u *OutputRetentionUsecase) ClearExpiredOutputs(ctx context.Context) (int64, error) {
+	var cleared int64
+	for page := 1; ; page++ {
+		tenants, _, err := u.tenantRepo.List(ctx, repository.TenantFilter{Page: page, Limit: outputRetentionTenantPageSize})
+		if err != nil {
+			return cleared, err
+		}
+		for _, tenant := range tenants {
+			n, err := u.clearTenantOutputs(ctx, tenant)
+			if err != nil {
+				u.logger.Warn("Failed to clear expired step run outputs", "tenant_id", tenant.ID, "error", err)
+				continue
+			}
+			cleared += n
+		}
+		if len(tenants) < outputRetentionTenantPageSize {
+			return cleared, nil
+		}
+	}
+}
+
+// clearTenantOutputs clears the tenant's step run payloads past its output retention window
+func (u *OutputRetentionUsecase) clearTenantOutputs(ctx context.Context, tenant *domain.Tenant) (int64, error) {
+	if len(tenant.Limits) == 0 {
+		return 0, nil
+	}
+	limits, err := tenant.GetLimits()
+	if err != nil {
+		return 0, err
+	}
+	if limits.OutputRetentionDays <= 0 {
+		return 0, nil
+	}
+
+	before := u.now().UTC().AddDate(0, 0, -limits.OutputRetentionDays)
+	n, err := u.stepRunRepo.ClearOutputsBefore(ctx, tenant.ID, before)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		u.logger.Info("Cleared expired step run outputs",
+			"tenant_id", tenant.ID,
+			"output_retention_days", limits.OutputRetentionDays,
+			"step_runs", n,
+		)
+	}
+	return n, nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// retentionTenantRepo lists a fixed set of tenants
+type retentionTenantRepo struct {
+	repository.TenantRepository
+	tenants []*domain.Tenant
+}
+
+func (r *retentionTenantRepo) List(ctx context.Context, filter repository.TenantFilter) ([]*domain.Tenant, int, error) {
+	if filter.Page > 1 {
+		return nil, len(r.tenants), nil
+	}
+	return r.tenants, len(r.tenants), nil
+}
+
+// retentionStepRunRepo clears in-memory step runs like the postgres repository does
+type retentionStepRunRepo struct {
+	repository.StepRunRepository
+	stepRuns []*domain.StepRun
+}
+
+func (r *retentionStepRunRepo) ClearOutputsBefore(ctx context.Context, tenantID uuid.UUID, before time.Time) (int64, error) {
+	var cleared int64
+	now := time.Now().UTC()
+	for _, sr := range r.stepRuns {
+		if sr.TenantID != tenantID || !sr.CreatedAt.Before(before) || sr.OutputsClearedAt != nil {
+			continue
+		}
+		sr.Input, sr.Output, sr.StreamingOutput = nil, nil, json.RawMessage(`[]`)
+		sr.OutputsClearedAt = &now
+		cleared++
+	}
+	return cleared, nil
+}
+
+func tenantWithLimits(t *testing.T, limits domain.TenantLimits) *domain.Tenant {
+	t.Helper()
+	raw, err := json.Marshal(limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &domain.Tenant{ID: uuid.New(), Limits: raw}
+}
+
+func finishedStepRun(tenantID, runID uuid.UUID, createdAt time.Time, durationMs int) *domain.StepRun {
+	sr := domain.NewStepRun(tenantID, runID, uuid.New(), "summarize", 1)
+	sr.Input = json.RawMessage(`{"document": "a very long document"}`)
+	sr.Output = json.RawMessage(`{"summary": "short"}`)
+	sr.Status = domain.StepRunStatusCompleted
+	sr.DurationMs = &durationMs
+	sr.StartedAt = &createdAt
+	sr.CompletedAt = &createdAt
+	sr.CreatedAt = createdAt
+	return sr
+}
+
+func TestOutputRetention_ClearsOutputsButKeepsRunMetadata(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	retaining := tenantWithLimits(t, domain.TenantLimits{RetentionDays: 90, OutputRetentionDays: 7})
+	keepsAll := tenantWithLimits(t, domain.TenantLimits{RetentionDays: 90})
+
+	runID := uuid.New()
+	expired := finishedStepRun(retaining.ID, runID, now.AddDate(0, 0, -10), 1200)
+	expired.Status = domain.StepRunStatusFailed
+	expired.Error = "upstream returned 502"
+	sibling := finishedStepRun(retaining.ID, runID, now.AddDate(0, 0, -10), 300)
+	recent := finishedStepRun(retaining.ID, uuid.New(), now.AddDate(0, 0, -2), 500)
+	otherTenant := finishedStepRun(keepsAll.ID, uuid.New(), now.AddDate(0, 0, -30), 700)
+
+	stepRunRepo := &retentionStepRunRepo{stepRuns: []*domain.StepRun{expired, sibling, recent, otherTenant}}
+	uc := NewOutputRetentionUsecase(&retentionTenantRepo{tenants: []*domain.Tenant{retaining, keepsAll}}, stepRunRepo, nil)
+	uc.now = func() time.Time { return now }
+
+	// The run's cost comes from usage records, which retention never touches
+	usage := []domain.UsageRecord{{TenantID: retaining.ID, RunID: &runID, TotalTokens: 1500, TotalCostUSD: 0.042}}
+	runOutput := json.RawMessage(`{"summary": "short"}`)
+	before := domain.NewRunSummary([]*domain.StepRun{expired, sibling}, usage, runOutput)
+
+	cleared, err := uc.ClearExpiredOutputs(context.Background())
+	if err != nil {
+		t.Fatalf("ClearExpiredOutputs() error = %v", err)
+	}
+	if cleared != 2 {
+		t.Errorf("cleared = %d, want 2", cleared)
+	}
+
+	for _, sr := range []*domain.StepRun{expired, sibling} {
+		if sr.Input != nil || sr.Output != nil || sr.OutputsClearedAt == nil {
+			t.Errorf("step run %s payloads not cleared: input=%s output=%s", sr.ID, sr.Input, sr.Output)
+		}
+	}
+	if expired.Status != domain.StepRunStatusFailed || expired.Error != "upstream returned 502" ||
+		expired.DurationMs == nil || *expired.DurationMs != 1200 || expired.StartedAt == nil || expired.CompletedAt == nil {
+		t.Errorf("step run metadata must be kept, got %+v", expired)
+	}
+
+	after := domain.NewRunSummary([]*domain.StepRun{expired, sibling}, usage, runOutput)
+	if *after != *before {
+		t.Errorf("run summary after clearing = %+v, want %+v", after, before)
+	}
+
+	for _, sr := range []*domain.StepRun{recent, otherTenant} {
+		if sr.Output == nil || sr.OutputsClearedAt != nil {
+			t.Errorf("step run %s inside the window or without output retention was cleared", sr.ID)
+		}
+	}
+
+	// A second sweep has nothing left to clear
+	if cleared, err := uc.ClearExpiredOutputs(context.Background()); err != nil || cleared != 0 {
+		t.Errorf("second sweep = %d, %v, want 0", cleared, err)
+	}
+}
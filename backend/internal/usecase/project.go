@@ -1,12 +1,15 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
 	"github.com/souta/ai-orchestration/internal/domain"
 	"github.com/souta/ai-orchestration/internal/repository"
 )
@@ -19,6 +22,14 @@ type ProjectUsecase struct {
 	versionRepo    repository.ProjectVersionRepository
 	blockRepo      repository.BlockDefinitionRepository
 	blockGroupRepo repository.BlockGroupRepository
+	favoriteRepo   repository.FavoriteRepository
+	channelRepo    repository.NotificationChannelRepository
+
+	// Workflow description generation (optional)
+	adapterRegistry   *adapter.Registry
+	describeAdapterID string
+	describeModel     string
+	descriptions      *descriptionCache
 }
 
 // NewProjectUsecase creates a new ProjectUsecase
@@ -30,11 +41,12 @@ func NewProjectUsecase(
 	blockRepo repository.BlockDefinitionRepository,
 ) *ProjectUsecase {
 	return &ProjectUsecase{
-		projectRepo: projectRepo,
-		stepRepo:    stepRepo,
-		edgeRepo:    edgeRepo,
-		versionRepo: versionRepo,
-		blockRepo:   blockRepo,
+		projectRepo:  projectRepo,
+		stepRepo:     stepRepo,
+		edgeRepo:     edgeRepo,
+		versionRepo:  versionRepo,
+		blockRepo:    blockRepo,
+		descriptions: newDescriptionCache(),
 	}
 }
 
@@ -44,12 +56,20 @@ func (u *ProjectUsecase) WithBlockGroupRepo(repo repository.BlockGroupRepository
 	return u
 }
 
+// WithNotificationChannelRepo sets the notification channel repository that failure
+// notification rules are checked against
+func (u *ProjectUsecase) WithNotificationChannelRepo(repo repository.NotificationChannelRepository) *ProjectUsecase {
+	u.channelRepo = repo
+	return u
+}
+
 // CreateProjectInput represents input for creating a project
 type CreateProjectInput struct {
 	TenantID    uuid.UUID
 	Name        string
 	Description string
 	Variables   json.RawMessage
+	Tags        []string
 }
 
 // Create creates a new project with an auto-created Start node
@@ -59,6 +79,7 @@ func (u *ProjectUsecase) Create(ctx context.Context, input CreateProjectInput) (
 	}
 
 	project := domain.NewProject(input.TenantID, input.Name, input.Description)
+	project.SetTags(input.Tags)
 
 	if err := u.projectRepo.Create(ctx, project); err != nil {
 		return nil, err
@@ -104,26 +125,43 @@ func (u *ProjectUsecase) GetWithDetails(ctx context.Context, tenantID, id uuid.U
 type ListProjectsInput struct {
 	TenantID uuid.UUID
 	Status   *domain.ProjectStatus
-	Page     int
-	Limit    int
+	Tags     []string
+	// FavoritesOf restricts results to projects favorited by this user (nil = no restriction)
+	FavoritesOf *uuid.UUID
+	Cursor      string // Opaque cursor returned as NextCursor by a previous page; replaces Page
+	Page        int
+	Limit       int
 }
 
 // ListProjectsOutput represents output for listing projects
 type ListProjectsOutput struct {
-	Projects []*domain.Project
-	Total    int
-	Page     int
-	Limit    int
+	Projects   []*domain.Project
+	Total      int
+	Page       int
+	Limit      int
+	NextCursor string // Empty on the last page
 }
 
-// List lists projects with pagination
+// List lists projects, most recently updated first, by page or keyset cursor
 func (u *ProjectUsecase) List(ctx context.Context, input ListProjectsInput) (*ListProjectsOutput, error) {
-	input.Page, input.Limit = NormalizePagination(input.Page, input.Limit)
+	pagination, err := newListPagination(input.Page, input.Limit, DefaultLimit, input.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := repository.ProjectFilter{
 		Status: input.Status,
-		Page:   input.Page,
-		Limit:  input.Limit,
+		Tags:   domain.NormalizeTags(input.Tags),
+		After:  pagination.After,
+		Page:   pagination.Page,
+		Limit:  pagination.FetchLimit(),
+	}
+	if input.FavoritesOf != nil {
+		ids, err := favoriteIDs(ctx, u.favoriteRepo, FavoriteOwner{TenantID: input.TenantID, UserID: *input.FavoritesOf}, domain.FavoriteTargetProject)
+		if err != nil {
+			return nil, err
+		}
+		filter.IDs = ids
 	}
 
 	projects, total, err := u.projectRepo.List(ctx, input.TenantID, filter)
@@ -131,12 +169,18 @@ func (u *ProjectUsecase) List(ctx context.Context, input ListProjectsInput) (*Li
 		return nil, err
 	}
 
-	return &ListProjectsOutput{
-		Projects: projects,
+	n, more := pagination.Next(len(projects), total)
+	output := &ListProjectsOutput{
+		Projects: projects[:n],
 		Total:    total,
-		Page:     input.Page,
-		Limit:    input.Limit,
-	}, nil
+		Page:     pagination.Page,
+		Limit:    pagination.Limit,
+	}
+	if more {
+		last := output.Projects[n-1]
+		output.NextCursor = encodeListCursor(repository.ListCursor{Time: last.UpdatedAt, ID: last.ID})
+	}
+	return output, nil
 }
 
 // UpdateProjectInput represents input for updating a project
@@ -146,6 +190,11 @@ type UpdateProjectInput struct {
 	Name        string
 	Description string
 	Variables   json.RawMessage
+	Tags        []string        // nil leaves tags unchanged, an empty slice clears them
+	RunRetry    json.RawMessage // nil leaves the policy unchanged, null disables run retries
+	RunOutput   json.RawMessage // nil leaves the selection unchanged, null restores the terminal steps
+
+	FailureNotifications json.RawMessage // nil leaves the rules unchanged, null removes them
 }
 
 // Update updates a project
@@ -163,6 +212,30 @@ func (u *ProjectUsecase) Update(ctx context.Context, input UpdateProjectInput) (
 		project.Name = input.Name
 	}
 	project.Description = input.Description
+	if input.Tags != nil {
+		project.SetTags(input.Tags)
+	}
+	if input.RunRetry != nil {
+		policy, err := parseRunRetryPolicy(input.RunRetry)
+		if err != nil {
+			return nil, err
+		}
+		project.RunRetry = policy
+	}
+	if input.RunOutput != nil {
+		config, err := u.parseRunOutputConfig(ctx, project, input.RunOutput)
+		if err != nil {
+			return nil, err
+		}
+		project.RunOutput = config
+	}
+	if input.FailureNotifications != nil {
+		rules, err := u.parseFailureNotificationRules(ctx, project.TenantID, input.FailureNotifications)
+		if err != nil {
+			return nil, err
+		}
+		project.FailureNotifications = rules
+	}
 
 	if err := u.projectRepo.Update(ctx, project); err != nil {
 		return nil, err
@@ -171,6 +244,158 @@ func (u *ProjectUsecase) Update(ctx context.Context, input UpdateProjectInput) (
 	return project, nil
 }
 
+// projectPatchableFields are the project fields a JSON Patch may change, the same ones Update sets
+var projectPatchableFields = map[string]bool{
+	"name":        true,
+	"description": true,
+	"tags":        true,
+	"run_retry":   true,
+	"run_output":  true,
+
+	"failure_notifications": true,
+}
+
+// PatchProjectInput represents input for applying a JSON Patch to a project
+type PatchProjectInput struct {
+	TenantID uuid.UUID
+	ID       uuid.UUID
+	Patch    domain.JSONPatch
+}
+
+// Patch applies a JSON Patch (RFC 6902) to the project's JSON representation. The patched
+// project is validated as a whole and written only if no other update changed the project since
+// it was read, so a patch is either applied completely or not at all.
+func (u *ProjectUsecase) Patch(ctx context.Context, input PatchProjectInput) (*domain.Project, error) {
+	current, err := u.projectRepo.GetByID(ctx, input.TenantID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !current.CanEdit() {
+		return nil, domain.ErrProjectNotEditable
+	}
+
+	var patched domain.Project
+	if err := applyJSONPatch(current, input.Patch, projectPatchableFields, &patched); err != nil {
+		return nil, err
+	}
+
+	project := *current
+	if patched.Name == "" {
+		return nil, domain.NewValidationError("name", "name is required")
+	}
+	project.Name = patched.Name
+	project.Description = patched.Description
+	project.SetTags(patched.Tags)
+	if patched.RunRetry != nil {
+		if err := patched.RunRetry.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	project.RunRetry = patched.RunRetry
+	if patched.RunOutput != nil {
+		if err := u.validateRunOutputConfig(ctx, &project, patched.RunOutput); err != nil {
+			return nil, err
+		}
+	}
+	project.RunOutput = patched.RunOutput
+	if err := u.validateFailureNotificationRules(ctx, project.TenantID, patched.FailureNotifications); err != nil {
+		return nil, err
+	}
+	project.FailureNotifications = patched.FailureNotifications
+
+	if err := u.projectRepo.UpdateIfUnmodified(ctx, &project, current.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// parseRunRetryPolicy parses and validates a run retry policy; JSON null disables run retries
+func parseRunRetryPolicy(raw json.RawMessage) (*domain.RunRetryPolicy, error) {
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+	var policy domain.RunRetryPolicy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return nil, domain.NewValidationError("run_retry", "run_retry must be an object")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// parseFailureNotificationRules parses and validates failure notification rules; JSON null
+// removes them
+func (u *ProjectUsecase) parseFailureNotificationRules(ctx context.Context, tenantID uuid.UUID, raw json.RawMessage) ([]domain.FailureNotificationRule, error) {
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+	var rules []domain.FailureNotificationRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, domain.NewValidationError("failure_notifications", "failure_notifications must be an array of rules")
+	}
+	if err := u.validateFailureNotificationRules(ctx, tenantID, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// validateFailureNotificationRules checks failure notification rules and that their channels
+// belong to the tenant
+func (u *ProjectUsecase) validateFailureNotificationRules(ctx context.Context, tenantID uuid.UUID, rules []domain.FailureNotificationRule) error {
+	if err := domain.ValidateFailureNotificationRules(rules); err != nil {
+		return err
+	}
+	if u.channelRepo == nil {
+		return nil
+	}
+	for _, rule := range rules {
+		if _, err := u.channelRepo.GetByID(ctx, tenantID, rule.ChannelID); err != nil {
+			if errors.Is(err, domain.ErrNotificationChannelNotFound) {
+				return domain.NewValidationError("failure_notifications.channel_id", fmt.Sprintf("notification channel %s not found", rule.ChannelID))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRunOutputConfig parses and validates a run output selection; JSON null restores the
+// terminal step heuristic
+func (u *ProjectUsecase) parseRunOutputConfig(ctx context.Context, project *domain.Project, raw json.RawMessage) (*domain.RunOutputConfig, error) {
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+	var config domain.RunOutputConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, domain.NewValidationError("run_output", "run_output must be an object")
+	}
+	if err := u.validateRunOutputConfig(ctx, project, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// validateRunOutputConfig checks a run output selection against the project's steps
+func (u *ProjectUsecase) validateRunOutputConfig(ctx context.Context, project *domain.Project, config *domain.RunOutputConfig) error {
+	steps, err := u.stepRepo.ListByProject(ctx, project.TenantID, project.ID)
+	if err != nil {
+		return err
+	}
+	projectSteps := make([]domain.Step, len(steps))
+	for i, step := range steps {
+		projectSteps[i] = *step
+	}
+	return config.Validate(projectSteps)
+}
+
+// ListTags returns the tenant's distinct project tags with usage counts
+func (u *ProjectUsecase) ListTags(ctx context.Context, tenantID uuid.UUID) ([]domain.ProjectTagCount, error) {
+	return u.projectRepo.ListTags(ctx, tenantID)
+}
+
 // Delete deletes a project
 // System projects cannot be deleted
 func (u *ProjectUsecase) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
@@ -188,6 +413,24 @@ func (u *ProjectUsecase) Delete(ctx context.Context, tenantID, id uuid.UUID) err
 	return u.projectRepo.Delete(ctx, tenantID, id)
 }
 
+// SetPaused pauses or resumes a project. While paused, every trigger is rejected and due
+// schedules are skipped; runs already in flight continue. System projects cannot be paused.
+func (u *ProjectUsecase) SetPaused(ctx context.Context, tenantID, id uuid.UUID, paused bool) (*domain.Project, error) {
+	project, err := u.projectRepo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if project.IsSystem {
+		return nil, domain.ErrForbidden
+	}
+
+	if err := u.projectRepo.SetPaused(ctx, tenantID, id, paused); err != nil {
+		return nil, err
+	}
+	project.Paused = paused
+	return project, nil
+}
+
 // SaveProjectInput represents input for saving a project
 type SaveProjectInput struct {
 	TenantID    uuid.UUID
@@ -265,6 +508,7 @@ func (u *ProjectUsecase) Save(ctx context.Context, input SaveProjectInput) (*dom
 		Steps:       input.Steps,
 		Edges:       input.Edges,
 		BlockGroups: reloadedProject.BlockGroups,
+		RunOutput:   project.RunOutput,
 	}
 
 	definitionJSON, err := json.Marshal(definition)
@@ -718,10 +962,11 @@ func (u *ProjectUsecase) getBlockDefinitionForGroup(ctx context.Context, group *
 
 // ValidationCheck represents a single validation check result
 type ValidationCheck struct {
-	ID      string `json:"id"`
-	Label   string `json:"label"`
-	Status  string `json:"status"` // "passed", "warning", "error"
-	Message string `json:"message,omitempty"`
+	ID        string               `json:"id"`
+	Label     string               `json:"label"`
+	Status    string               `json:"status"` // "passed", "warning", "error"
+	Message   string               `json:"message,omitempty"`
+	Locations []ValidationLocation `json:"locations,omitempty"` // Steps or variables the check failed for
 }
 
 // ValidationResult represents the result of ValidateForPublish
@@ -839,7 +1084,7 @@ func (u *ProjectUsecase) ValidateForPublish(ctx context.Context, tenantID, proje
 		if err != nil || len(reqCreds) == 0 {
 			continue
 		}
-		bindings, err := step.GetCredentialBindings()
+		bindings, err := step.GetCredentialBindingRefs()
 		if err != nil {
 			continue
 		}
@@ -936,5 +1181,13 @@ func (u *ProjectUsecase) ValidateForPublish(ctx context.Context, tenantID, proje
 	}
 	result.Checks = append(result.Checks, configCheck)
 
+	// Checks 7-9: Hygiene lint (unused variables, undefined template fields, unreachable steps)
+	for _, check := range lintProject(project) {
+		if check.Status == "warning" {
+			result.WarningCount++
+		}
+		result.Checks = append(result.Checks, check)
+	}
+
 	return result, nil
 }
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// CloneProjectInput represents input for cloning a project
+type CloneProjectInput struct {
+	TenantID  uuid.UUID
+	ProjectID uuid.UUID
+	Name      string // Defaults to "<source name> (copy)"
+}
+
+// Clone deep-copies a project with its steps, edges, block groups, and variables into a new
+// project in the same tenant. All internal IDs are remapped; credential bindings are not copied
+// so the clone never silently uses the source's credentials.
+// The clone starts as an unsaved draft (version 0) built from the source's saved state.
+func (u *ProjectUsecase) Clone(ctx context.Context, input CloneProjectInput) (*domain.Project, error) {
+	source, err := u.getProjectWithStepsEdgesFromDB(ctx, input.TenantID, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*domain.BlockGroup
+	if u.blockGroupRepo != nil {
+		if groups, err = u.blockGroupRepo.ListByProject(ctx, input.TenantID, input.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	name := input.Name
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+
+	clone := domain.NewProject(input.TenantID, name, source.Description)
+	clone.Variables = cloneRawJSON(source.Variables)
+	clone.SetTags(source.Tags)
+	clone.ErrorWorkflowID = source.ErrorWorkflowID
+	clone.ErrorWorkflowConfig = cloneRawJSON(source.ErrorWorkflowConfig)
+	if source.RunRetry != nil {
+		policy := *source.RunRetry
+		policy.RetryOn = append([]string(nil), source.RunRetry.RetryOn...)
+		clone.RunRetry = &policy
+	}
+	for _, rule := range source.FailureNotifications {
+		rule.Categories = append([]string(nil), rule.Categories...)
+		clone.FailureNotifications = append(clone.FailureNotifications, rule)
+	}
+
+	if err := u.projectRepo.Create(ctx, clone); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	// Create block groups parents-first so parent_group_id references already exist
+	groupIDMap := make(map[uuid.UUID]uuid.UUID, len(groups)) // old ID -> new ID
+	for _, group := range groups {
+		groupIDMap[group.ID] = uuid.New()
+	}
+	for _, group := range orderGroupsParentFirst(groups) {
+		newGroup := *group
+		newGroup.ID = groupIDMap[group.ID]
+		newGroup.TenantID = input.TenantID
+		newGroup.ProjectID = clone.ID
+		newGroup.ParentGroupID = remapID(group.ParentGroupID, groupIDMap)
+		newGroup.Config = cloneRawJSON(group.Config)
+		newGroup.PreProcess = cloneString(group.PreProcess)
+		newGroup.PostProcess = cloneString(group.PostProcess)
+		newGroup.CreatedAt = now
+		newGroup.UpdatedAt = now
+		if err := u.blockGroupRepo.Create(ctx, &newGroup); err != nil {
+			return nil, err
+		}
+		clone.BlockGroups = append(clone.BlockGroups, newGroup)
+	}
+
+	stepIDMap := make(map[uuid.UUID]uuid.UUID, len(source.Steps)) // old ID -> new ID
+	for _, step := range source.Steps {
+		newStep := step
+		newStep.ID = uuid.New()
+		stepIDMap[step.ID] = newStep.ID
+
+		newStep.TenantID = input.TenantID
+		newStep.ProjectID = clone.ID
+		newStep.BlockGroupID = remapID(step.BlockGroupID, groupIDMap)
+		newStep.Config = cloneRawJSON(step.Config)
+		newStep.TriggerConfig = cloneRawJSON(step.TriggerConfig)
+		newStep.RetryConfig = cloneRawJSON(step.RetryConfig)
+		newStep.ToolInputSchema = cloneRawJSON(step.ToolInputSchema)
+		newStep.ToolName = cloneString(step.ToolName)
+		newStep.ToolDescription = cloneString(step.ToolDescription)
+		newStep.CredentialBindings = nil
+		newStep.CreatedAt = now
+		newStep.UpdatedAt = now
+		if err := u.stepRepo.Create(ctx, &newStep); err != nil {
+			return nil, err
+		}
+		clone.Steps = append(clone.Steps, newStep)
+	}
+
+	for _, edge := range source.Edges {
+		newEdge := edge
+		newEdge.ID = uuid.New()
+		newEdge.TenantID = input.TenantID
+		newEdge.ProjectID = clone.ID
+		newEdge.SourceStepID = remapID(edge.SourceStepID, stepIDMap)
+		newEdge.TargetStepID = remapID(edge.TargetStepID, stepIDMap)
+		newEdge.SourceBlockGroupID = remapID(edge.SourceBlockGroupID, groupIDMap)
+		newEdge.TargetBlockGroupID = remapID(edge.TargetBlockGroupID, groupIDMap)
+		newEdge.Condition = cloneString(edge.Condition)
+		newEdge.CreatedAt = now
+
+		// Skip edges whose endpoints were not copied (e.g. dangling references)
+		if (newEdge.SourceStepID == nil && newEdge.SourceBlockGroupID == nil) ||
+			(newEdge.TargetStepID == nil && newEdge.TargetBlockGroupID == nil) {
+			continue
+		}
+		if err := u.edgeRepo.Create(ctx, &newEdge); err != nil {
+			return nil, err
+		}
+		clone.Edges = append(clone.Edges, newEdge)
+	}
+
+	// The run output selection refers to steps, so it is set once they have their new IDs
+	if source.RunOutput != nil {
+		clone.RunOutput = remapRunOutput(source.RunOutput, stepIDMap)
+		if err := u.projectRepo.Update(ctx, clone); err != nil {
+			return nil, err
+		}
+	}
+
+	return clone, nil
+}
+
+// remapRunOutput copies a run output selection onto the cloned steps
+func remapRunOutput(config *domain.RunOutputConfig, stepIDMap map[uuid.UUID]uuid.UUID) *domain.RunOutputConfig {
+	remapped := &domain.RunOutputConfig{StepID: remapID(config.StepID, stepIDMap)}
+	if len(config.Mapping) > 0 {
+		remapped.Mapping = make(map[string]uuid.UUID, len(config.Mapping))
+		for field, stepID := range config.Mapping {
+			if newID, ok := stepIDMap[stepID]; ok {
+				remapped.Mapping[field] = newID
+			}
+		}
+	}
+	return remapped
+}
+
+// orderGroupsParentFirst returns groups sorted so every parent precedes its children.
+// Groups whose parent is missing (or part of a cycle) are appended at the end.
+func orderGroupsParentFirst(groups []*domain.BlockGroup) []*domain.BlockGroup {
+	known := make(map[uuid.UUID]bool, len(groups))
+	for _, g := range groups {
+		known[g.ID] = true
+	}
+
+	ordered := make([]*domain.BlockGroup, 0, len(groups))
+	placed := make(map[uuid.UUID]bool, len(groups))
+	for len(ordered) < len(groups) {
+		progressed := false
+		for _, g := range groups {
+			if placed[g.ID] {
+				continue
+			}
+			if g.ParentGroupID == nil || !known[*g.ParentGroupID] || placed[*g.ParentGroupID] {
+				ordered = append(ordered, g)
+				placed[g.ID] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			for _, g := range groups {
+				if !placed[g.ID] {
+					ordered = append(ordered, g)
+					placed[g.ID] = true
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// remapID maps an optional ID through idMap, returning nil when it is unset or unknown
+func remapID(id *uuid.UUID, idMap map[uuid.UUID]uuid.UUID) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+	mapped, ok := idMap[*id]
+	if !ok {
+		return nil
+	}
+	return &mapped
+}
+
+// cloneRawJSON returns a copy of raw so the clone does not share the source's backing array
+func cloneRawJSON(raw json.RawMessage) json.RawMessage {
+	if raw == nil {
+		return nil
+	}
+	return append(json.RawMessage(nil), raw...)
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
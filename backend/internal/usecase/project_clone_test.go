@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// ============================================================================
+// Mock Block Group Repository
+// ============================================================================
+
+type mockBlockGroupRepo struct {
+	groups map[uuid.UUID]*domain.BlockGroup
+}
+
+func newMockBlockGroupRepo() *mockBlockGroupRepo {
+	return &mockBlockGroupRepo{groups: make(map[uuid.UUID]*domain.BlockGroup)}
+}
+
+func (m *mockBlockGroupRepo) Create(ctx context.Context, group *domain.BlockGroup) error {
+	if group.ParentGroupID != nil {
+		if _, ok := m.groups[*group.ParentGroupID]; !ok {
+			return domain.ErrBlockGroupNotFound // Mirrors the parent_group_id foreign key
+		}
+	}
+	m.groups[group.ID] = group
+	return nil
+}
+
+func (m *mockBlockGroupRepo) GetByID(ctx context.Context, tenantID, projectID, id uuid.UUID) (*domain.BlockGroup, error) {
+	g, ok := m.groups[id]
+	if !ok || g.TenantID != tenantID || g.ProjectID != projectID {
+		return nil, domain.ErrBlockGroupNotFound
+	}
+	return g, nil
+}
+
+func (m *mockBlockGroupRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.BlockGroup, error) {
+	var result []*domain.BlockGroup
+	for _, g := range m.groups {
+		if g.TenantID == tenantID && g.ProjectID == projectID {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockBlockGroupRepo) ListByParent(ctx context.Context, tenantID, projectID, parentID uuid.UUID) ([]*domain.BlockGroup, error) {
+	var result []*domain.BlockGroup
+	for _, g := range m.groups {
+		if g.TenantID == tenantID && g.ProjectID == projectID && g.ParentGroupID != nil && *g.ParentGroupID == parentID {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockBlockGroupRepo) Update(ctx context.Context, group *domain.BlockGroup) error {
+	m.groups[group.ID] = group
+	return nil
+}
+
+func (m *mockBlockGroupRepo) Delete(ctx context.Context, tenantID, projectID, id uuid.UUID) error {
+	delete(m.groups, id)
+	return nil
+}
+
+// ============================================================================
+// Test Helpers
+// ============================================================================
+
+type cloneFixture struct {
+	projectRepo *mockProjectRepo
+	stepRepo    *mockStepRepo
+	edgeRepo    *mockEdgeRepo
+	groupRepo   *mockBlockGroupRepo
+	uc          *ProjectUsecase
+	source      *domain.Project
+}
+
+// newCloneFixture seeds a workflow: Start -> [Loop group containing a nested Map group] -> Notify,
+// where Notify has a credential binding
+func newCloneFixture(tenantID uuid.UUID) *cloneFixture {
+	f := &cloneFixture{
+		projectRepo: newMockProjectRepo(),
+		stepRepo:    newMockStepRepo(),
+		edgeRepo:    newMockEdgeRepo(),
+		groupRepo:   newMockBlockGroupRepo(),
+	}
+	f.uc = NewProjectUsecase(f.projectRepo, f.stepRepo, f.edgeRepo, nil, nil).WithBlockGroupRepo(f.groupRepo)
+
+	project := domain.NewProject(tenantID, "Order Sync", "Syncs orders")
+	project.Variables = json.RawMessage(`{"region":"eu"}`)
+	project.SetTags([]string{"sales"})
+	project.Version = 3
+	f.projectRepo.projects[project.ID] = project
+	f.source = project
+
+	outer := domain.NewBlockGroup(tenantID, project.ID, "Retry Loop", domain.BlockGroupTypeWhile)
+	outer.Config = json.RawMessage(`{"max_iterations":3}`)
+	inner := domain.NewBlockGroup(tenantID, project.ID, "Fan Out", domain.BlockGroupTypeForeach)
+	inner.ParentGroupID = &outer.ID
+	f.groupRepo.groups[outer.ID] = outer
+	f.groupRepo.groups[inner.ID] = inner
+
+	start := domain.NewStep(tenantID, project.ID, "Start", domain.StepTypeStart, json.RawMessage(`{}`))
+	fetch := domain.NewStep(tenantID, project.ID, "Fetch", domain.StepTypeTool, json.RawMessage(`{"url":"https://example.com"}`))
+	fetch.BlockGroupID = &inner.ID
+	notify := domain.NewStep(tenantID, project.ID, "Notify", "slack", json.RawMessage(`{"channel":"#orders"}`))
+	notify.CredentialBindings = json.RawMessage(`{"slack":"` + uuid.New().String() + `"}`)
+	for _, s := range []*domain.Step{start, fetch, notify} {
+		f.stepRepo.steps[s.ID] = s
+	}
+
+	edges := []*domain.Edge{
+		{ID: uuid.New(), TenantID: tenantID, ProjectID: project.ID, SourceStepID: &start.ID, TargetBlockGroupID: &outer.ID},
+		{ID: uuid.New(), TenantID: tenantID, ProjectID: project.ID, SourceBlockGroupID: &outer.ID, TargetStepID: &notify.ID, SourcePort: "out"},
+	}
+	for _, e := range edges {
+		f.edgeRepo.edges[e.ID] = e
+	}
+	return f
+}
+
+// structureSignature describes a project's graph by names only, so projects with different IDs can be compared
+func structureSignature(project *domain.Project, groups []*domain.BlockGroup) map[string]string {
+	stepNames := make(map[uuid.UUID]string)
+	for _, s := range project.Steps {
+		stepNames[s.ID] = s.Name
+	}
+	groupNames := make(map[uuid.UUID]string)
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
+	}
+	nameOf := func(stepID, groupID *uuid.UUID) string {
+		if stepID != nil {
+			return "step:" + stepNames[*stepID]
+		}
+		if groupID != nil {
+			return "group:" + groupNames[*groupID]
+		}
+		return ""
+	}
+
+	sig := make(map[string]string)
+	for _, s := range project.Steps {
+		sig["step:"+s.Name] = string(s.Type) + " " + string(s.Config) + " in " + nameOf(nil, s.BlockGroupID)
+	}
+	for _, g := range groups {
+		sig["group:"+g.Name] = string(g.Type) + " in " + nameOf(nil, g.ParentGroupID)
+	}
+	for _, e := range project.Edges {
+		sig["edge:"+nameOf(e.SourceStepID, e.SourceBlockGroupID)+"->"+nameOf(e.TargetStepID, e.TargetBlockGroupID)] = e.SourcePort
+	}
+	return sig
+}
+
+func (f *cloneFixture) load(t *testing.T, tenantID, projectID uuid.UUID) (*domain.Project, []*domain.BlockGroup) {
+	t.Helper()
+	project, err := f.uc.getProjectWithStepsEdgesFromDB(context.Background(), tenantID, projectID)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	groups, _ := f.groupRepo.ListByProject(context.Background(), tenantID, projectID)
+	return project, groups
+}
+
+// ============================================================================
+// Tests for Clone
+// ============================================================================
+
+func TestProjectUsecase_Clone_ReplicatesStructureWithNewIDs(t *testing.T) {
+	tenantID := uuid.New()
+	f := newCloneFixture(tenantID)
+	sourceProject, sourceGroups := f.load(t, tenantID, f.source.ID)
+
+	clone, err := f.uc.Clone(context.Background(), CloneProjectInput{TenantID: tenantID, ProjectID: f.source.ID})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	if clone.ID == f.source.ID {
+		t.Fatal("clone must have a new project ID")
+	}
+	if clone.Name != "Order Sync (copy)" {
+		t.Errorf("Name = %q, want %q", clone.Name, "Order Sync (copy)")
+	}
+	if clone.TenantID != tenantID || clone.Version != 0 {
+		t.Errorf("TenantID = %v, Version = %d; want same tenant, version 0", clone.TenantID, clone.Version)
+	}
+	if string(clone.Variables) != `{"region":"eu"}` || len(clone.Tags) != 1 {
+		t.Errorf("Variables = %s, Tags = %v; want copied", clone.Variables, clone.Tags)
+	}
+
+	cloneProject, cloneGroups := f.load(t, tenantID, clone.ID)
+
+	// Distinct IDs
+	sourceIDs := make(map[uuid.UUID]bool)
+	for _, s := range sourceProject.Steps {
+		sourceIDs[s.ID] = true
+	}
+	for _, e := range sourceProject.Edges {
+		sourceIDs[e.ID] = true
+	}
+	for _, g := range sourceGroups {
+		sourceIDs[g.ID] = true
+	}
+	for _, s := range cloneProject.Steps {
+		if sourceIDs[s.ID] {
+			t.Errorf("step %q reuses source ID", s.Name)
+		}
+		if s.BlockGroupID != nil && sourceIDs[*s.BlockGroupID] {
+			t.Errorf("step %q still references a source group", s.Name)
+		}
+		if len(s.CredentialBindings) != 0 {
+			t.Errorf("step %q credential bindings = %s, want unlinked", s.Name, s.CredentialBindings)
+		}
+	}
+	for _, e := range cloneProject.Edges {
+		for _, ref := range []*uuid.UUID{&e.ID, e.SourceStepID, e.TargetStepID, e.SourceBlockGroupID, e.TargetBlockGroupID} {
+			if ref != nil && sourceIDs[*ref] {
+				t.Errorf("edge references source ID %s", *ref)
+			}
+		}
+	}
+	for _, g := range cloneGroups {
+		if sourceIDs[g.ID] || (g.ParentGroupID != nil && sourceIDs[*g.ParentGroupID]) {
+			t.Errorf("group %q references source IDs", g.Name)
+		}
+	}
+
+	// Identical structure
+	want := structureSignature(sourceProject, sourceGroups)
+	got := structureSignature(cloneProject, cloneGroups)
+	if len(got) != len(want) {
+		t.Fatalf("structure = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("structure[%q] = %q, want %q", key, got[key], value)
+		}
+	}
+}
+
+func TestProjectUsecase_Clone_IsIndependentOfOriginal(t *testing.T) {
+	tenantID := uuid.New()
+	f := newCloneFixture(tenantID)
+
+	clone, err := f.uc.Clone(context.Background(), CloneProjectInput{TenantID: tenantID, ProjectID: f.source.ID, Name: "Order Sync v2"})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if clone.Name != "Order Sync v2" {
+		t.Errorf("Name = %q, want %q", clone.Name, "Order Sync v2")
+	}
+
+	// Edit the clone in place: rename, mutate config bytes, delete a step and an edge
+	if _, err := f.uc.Update(context.Background(), UpdateProjectInput{TenantID: tenantID, ID: clone.ID, Name: "Renamed"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	cloneProject, cloneGroups := f.load(t, tenantID, clone.ID)
+	for _, s := range cloneProject.Steps {
+		stored := f.stepRepo.steps[s.ID]
+		if len(stored.Config) > 0 {
+			stored.Config[0] = '['
+		}
+		if s.Name == "Notify" {
+			delete(f.stepRepo.steps, s.ID)
+		}
+	}
+	for _, e := range cloneProject.Edges {
+		delete(f.edgeRepo.edges, e.ID)
+		break
+	}
+	for _, g := range cloneGroups {
+		g.Name = "changed"
+	}
+	f.projectRepo.projects[clone.ID].Variables[2] = 'X'
+
+	original, originalGroups := f.load(t, tenantID, f.source.ID)
+	if original.Name != "Order Sync" {
+		t.Errorf("original Name = %q, want unchanged", original.Name)
+	}
+	if string(original.Variables) != `{"region":"eu"}` {
+		t.Errorf("original Variables = %s, want unchanged", original.Variables)
+	}
+	if len(original.Steps) != 3 || len(original.Edges) != 2 || len(originalGroups) != 2 {
+		t.Fatalf("original has %d steps, %d edges, %d groups; want 3, 2, 2", len(original.Steps), len(original.Edges), len(originalGroups))
+	}
+	for _, s := range original.Steps {
+		if !json.Valid(s.Config) || s.Config[0] != '{' {
+			t.Errorf("original step %q config = %s, want unchanged", s.Name, s.Config)
+		}
+	}
+	for _, g := range originalGroups {
+		if g.Name == "changed" {
+			t.Error("original group renamed by editing clone")
+		}
+	}
+}
+
+func TestProjectUsecase_Clone_NotFound(t *testing.T) {
+	f := newCloneFixture(uuid.New())
+
+	// Another tenant cannot clone the project
+	if _, err := f.uc.Clone(context.Background(), CloneProjectInput{TenantID: uuid.New(), ProjectID: f.source.ID}); err != domain.ErrProjectNotFound {
+		t.Errorf("Clone() error = %v, want ErrProjectNotFound", err)
+	}
+}
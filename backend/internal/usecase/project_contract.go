@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// UnknownSchema marks a contract schema that the workflow does not declare
+var UnknownSchema = json.RawMessage(`"unknown"`)
+
+// ContractSchema is the declared schema of one step in a workflow contract
+type ContractSchema struct {
+	StepID      uuid.UUID       `json:"step_id"`
+	StepName    string          `json:"step_name"`
+	TriggerType string          `json:"trigger_type,omitempty"` // Start steps only
+	Schema      json.RawMessage `json:"schema"`                 // JSON schema, or "unknown"
+}
+
+// WorkflowContract describes the data a workflow expects and produces.
+// Inputs come from the start steps' input_schema and Outputs from the output_schema of the
+// terminal steps reachable from them. Output is the schema of the run output: the terminal
+// step's schema when there is one, otherwise an object keyed by terminal step ID (matching how
+// the worker assembles run output).
+type WorkflowContract struct {
+	ProjectID uuid.UUID        `json:"project_id"`
+	Version   int              `json:"version"`
+	Inputs    []ContractSchema `json:"inputs"`
+	Outputs   []ContractSchema `json:"outputs"`
+	Output    json.RawMessage  `json:"output"`
+}
+
+// InferContract builds the input/output contract of the saved workflow from declared schemas
+func (u *ProjectUsecase) InferContract(ctx context.Context, tenantID, projectID uuid.UUID) (*WorkflowContract, error) {
+	project, err := u.getProjectWithStepsEdgesFromDB(ctx, tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := &WorkflowContract{
+		ProjectID: project.ID,
+		Version:   project.Version,
+		Inputs:    make([]ContractSchema, 0),
+		Outputs:   make([]ContractSchema, 0),
+	}
+
+	starts := make([]domain.Step, 0)
+	for _, step := range project.Steps {
+		if step.Type == domain.StepTypeStart || step.TriggerType != nil {
+			starts = append(starts, step)
+		}
+	}
+	sortStepsByName(starts)
+
+	for _, step := range starts {
+		schema := declaredSchema("input_schema", step.TriggerConfig, step.Config)
+		if schema == nil {
+			schema = UnknownSchema
+		}
+		var triggerType string
+		if step.TriggerType != nil {
+			triggerType = string(*step.TriggerType)
+		}
+		contract.Inputs = append(contract.Inputs, ContractSchema{
+			StepID:      step.ID,
+			StepName:    step.Name,
+			TriggerType: triggerType,
+			Schema:      schema,
+		})
+	}
+
+	for _, step := range terminalSteps(project, starts) {
+		schema, err := u.stepOutputSchema(ctx, tenantID, step)
+		if err != nil {
+			return nil, err
+		}
+		contract.Outputs = append(contract.Outputs, ContractSchema{
+			StepID:   step.ID,
+			StepName: step.Name,
+			Schema:   schema,
+		})
+	}
+	contract.Output = combineOutputSchemas(contract.Outputs)
+
+	return contract, nil
+}
+
+// stepOutputSchema returns the step's declared output_schema, falling back to its block definition's
+func (u *ProjectUsecase) stepOutputSchema(ctx context.Context, tenantID uuid.UUID, step domain.Step) (json.RawMessage, error) {
+	if schema := declaredSchema("output_schema", step.Config); schema != nil {
+		return schema, nil
+	}
+	if u.blockRepo == nil {
+		return UnknownSchema, nil
+	}
+
+	var block *domain.BlockDefinition
+	var err error
+	if step.BlockDefinitionID != nil {
+		block, err = u.blockRepo.GetByID(ctx, *step.BlockDefinitionID)
+	} else {
+		block, err = u.blockRepo.GetBySlug(ctx, &tenantID, string(step.Type))
+	}
+	if errors.Is(err, domain.ErrBlockDefinitionNotFound) {
+		return UnknownSchema, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if block == nil || !isSchema(block.OutputSchema) {
+		return UnknownSchema, nil
+	}
+	return block.OutputSchema, nil
+}
+
+// declaredSchema returns the first non-empty schema stored under key in the given config objects
+func declaredSchema(key string, sources ...json.RawMessage) json.RawMessage {
+	for _, source := range sources {
+		if len(source) == 0 {
+			continue
+		}
+		var holder map[string]json.RawMessage
+		if json.Unmarshal(source, &holder) != nil {
+			continue
+		}
+		if schema := holder[key]; isSchema(schema) {
+			return schema
+		}
+	}
+	return nil
+}
+
+// isSchema reports whether raw is a non-empty JSON object
+func isSchema(raw json.RawMessage) bool {
+	var schema map[string]interface{}
+	return len(raw) > 0 && json.Unmarshal(raw, &schema) == nil && len(schema) > 0
+}
+
+// terminalSteps walks the graph from the start steps (through block groups) and returns the
+// reachable top-level steps without outgoing edges, sorted by name
+func terminalSteps(project *domain.Project, starts []domain.Step) []domain.Step {
+	steps := make(map[uuid.UUID]domain.Step, len(project.Steps))
+	for _, step := range project.Steps {
+		steps[step.ID] = step
+	}
+
+	outgoing := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range project.Edges {
+		var source, target *uuid.UUID
+		if edge.SourceStepID != nil {
+			source = edge.SourceStepID
+		} else {
+			source = edge.SourceBlockGroupID
+		}
+		if edge.TargetStepID != nil {
+			target = edge.TargetStepID
+		} else {
+			target = edge.TargetBlockGroupID
+		}
+		if source != nil && target != nil {
+			outgoing[*source] = append(outgoing[*source], *target)
+		}
+	}
+
+	visited := make(map[uuid.UUID]bool)
+	queue := make([]uuid.UUID, 0, len(starts))
+	for _, start := range starts {
+		queue = append(queue, start.ID)
+	}
+	var terminals []domain.Step
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		next := outgoing[id]
+		queue = append(queue, next...)
+
+		step, isStep := steps[id]
+		if isStep && len(next) == 0 && step.BlockGroupID == nil && step.Type != domain.StepTypeNote {
+			terminals = append(terminals, step)
+		}
+	}
+
+	sortStepsByName(terminals)
+	return terminals
+}
+
+// combineOutputSchemas builds the schema of the run output from the terminal step schemas
+func combineOutputSchemas(outputs []ContractSchema) json.RawMessage {
+	switch len(outputs) {
+	case 0:
+		return UnknownSchema
+	case 1:
+		return outputs[0].Schema
+	}
+
+	properties := make(map[string]json.RawMessage, len(outputs))
+	known := false
+	for _, output := range outputs {
+		if string(output.Schema) == string(UnknownSchema) {
+			properties[output.StepID.String()] = json.RawMessage(`{}`)
+			continue
+		}
+		properties[output.StepID.String()] = output.Schema
+		known = true
+	}
+	if !known {
+		return UnknownSchema
+	}
+
+	combined, _ := json.Marshal(map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	})
+	return combined
+}
+
+func sortStepsByName(steps []domain.Step) {
+	sort.Slice(steps, func(i, j int) bool {
+		if steps[i].Name != steps[j].Name {
+			return steps[i].Name < steps[j].Name
+		}
+		return steps[i].ID.String() < steps[j].ID.String()
+	})
+}
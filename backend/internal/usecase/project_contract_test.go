@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// contractFixture holds the repositories of a workflow built for contract tests
+type contractFixture struct {
+	tenantID    uuid.UUID
+	project     *domain.Project
+	projectRepo *mockProjectRepo
+	stepRepo    *mockStepRepo
+	edgeRepo    *mockEdgeRepo
+	blockRepo   *mockBlockRepo
+}
+
+func newContractFixture() *contractFixture {
+	f := &contractFixture{
+		tenantID:    uuid.New(),
+		projectRepo: newMockProjectRepo(),
+		stepRepo:    newMockStepRepo(),
+		edgeRepo:    newMockEdgeRepo(),
+		blockRepo:   newMockBlockRepo(),
+	}
+	f.project = domain.NewProject(f.tenantID, "Webhook intake", "")
+	f.project.Version = 3
+	f.projectRepo.projects[f.project.ID] = f.project
+	return f
+}
+
+func (f *contractFixture) addStep(name string, stepType domain.StepType, config string) *domain.Step {
+	step := &domain.Step{ID: uuid.New(), TenantID: f.tenantID, ProjectID: f.project.ID, Name: name, Type: stepType}
+	if config != "" {
+		step.Config = json.RawMessage(config)
+	}
+	f.stepRepo.steps[step.ID] = step
+	return step
+}
+
+func (f *contractFixture) connect(source, target *domain.Step) {
+	edge := &domain.Edge{ID: uuid.New(), TenantID: f.tenantID, ProjectID: f.project.ID, SourceStepID: &source.ID, TargetStepID: &target.ID}
+	f.edgeRepo.edges[edge.ID] = edge
+}
+
+func (f *contractFixture) infer(t *testing.T) *WorkflowContract {
+	t.Helper()
+	uc := NewProjectUsecase(f.projectRepo, f.stepRepo, f.edgeRepo, nil, f.blockRepo)
+	contract, err := uc.InferContract(context.Background(), f.tenantID, f.project.ID)
+	if err != nil {
+		t.Fatalf("InferContract() error = %v", err)
+	}
+	return contract
+}
+
+func assertSchema(t *testing.T, want string, got json.RawMessage) {
+	t.Helper()
+	var wantValue, gotValue interface{}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected schema: %v", err)
+	}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid schema %s: %v", got, err)
+	}
+	wantJSON, _ := json.Marshal(wantValue)
+	gotJSON, _ := json.Marshal(gotValue)
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("schema = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestProjectUsecase_InferContract_DeclaredSchemas(t *testing.T) {
+	f := newContractFixture()
+	webhook := domain.StepTriggerType("webhook")
+	start := f.addStep("Start", domain.StepTypeStart, "")
+	start.TriggerType = &webhook
+	start.TriggerConfig = json.RawMessage(`{"input_schema": {"type": "object", "required": ["email"], "properties": {"email": {"type": "string"}}}}`)
+	enrich := f.addStep("Enrich", domain.StepTypeLLM, "")
+	reply := f.addStep("Reply", domain.StepTypeTool, `{"adapter_id": "http", "output_schema": {"type": "object", "properties": {"status": {"type": "integer"}}}}`)
+	f.connect(start, enrich)
+	f.connect(enrich, reply)
+
+	contract := f.infer(t)
+
+	if contract.ProjectID != f.project.ID || contract.Version != 3 {
+		t.Errorf("contract project/version = %s/%d, want %s/3", contract.ProjectID, contract.Version, f.project.ID)
+	}
+	if len(contract.Inputs) != 1 {
+		t.Fatalf("len(Inputs) = %d, want 1", len(contract.Inputs))
+	}
+	input := contract.Inputs[0]
+	if input.StepID != start.ID || input.TriggerType != "webhook" {
+		t.Errorf("input = %+v, want start step with webhook trigger", input)
+	}
+	assertSchema(t, `{"type": "object", "required": ["email"], "properties": {"email": {"type": "string"}}}`, input.Schema)
+
+	if len(contract.Outputs) != 1 || contract.Outputs[0].StepID != reply.ID {
+		t.Fatalf("Outputs = %+v, want only the terminal Reply step", contract.Outputs)
+	}
+	assertSchema(t, `{"type": "object", "properties": {"status": {"type": "integer"}}}`, contract.Outputs[0].Schema)
+	assertSchema(t, `{"type": "object", "properties": {"status": {"type": "integer"}}}`, contract.Output)
+}
+
+func TestProjectUsecase_InferContract_MultipleTerminalsAndUnknown(t *testing.T) {
+	f := newContractFixture()
+	slack := f.blockRepo.addSystemBlock("slack")
+	slack.OutputSchema = json.RawMessage(`{"type": "object", "properties": {"ts": {"type": "string"}}}`)
+
+	start := f.addStep("Start", domain.StepTypeStart, `{}`)
+	notify := f.addStep("Notify", "slack", "")
+	archive := f.addStep("Archive", "unknown_block", "")
+	f.addStep("Sticky note", domain.StepTypeNote, "")
+	f.connect(start, notify)
+	f.connect(start, archive)
+
+	contract := f.infer(t)
+
+	assertSchema(t, `"unknown"`, contract.Inputs[0].Schema)
+
+	if len(contract.Outputs) != 2 {
+		t.Fatalf("len(Outputs) = %d, want 2 (unreachable note excluded)", len(contract.Outputs))
+	}
+	if contract.Outputs[0].StepID != archive.ID || contract.Outputs[1].StepID != notify.ID {
+		t.Errorf("Outputs should be sorted by step name: %+v", contract.Outputs)
+	}
+	assertSchema(t, `"unknown"`, contract.Outputs[0].Schema)
+	assertSchema(t, `{"type": "object", "properties": {"ts": {"type": "string"}}}`, contract.Outputs[1].Schema)
+
+	// Multiple terminals produce an object keyed by step ID, like the worker's run output
+	assertSchema(t, `{"type": "object", "properties": {
+		"`+archive.ID.String()+`": {},
+		"`+notify.ID.String()+`": {"type": "object", "properties": {"ts": {"type": "string"}}}
+	}}`, contract.Output)
+}
+
+func TestProjectUsecase_InferContract_NoDeclaredSchemas(t *testing.T) {
+	f := newContractFixture()
+	start := f.addStep("Start", domain.StepTypeStart, "")
+	end := f.addStep("Transform", domain.StepTypeFunction, "")
+	f.connect(start, end)
+
+	contract := f.infer(t)
+
+	assertSchema(t, `"unknown"`, contract.Inputs[0].Schema)
+	assertSchema(t, `"unknown"`, contract.Outputs[0].Schema)
+	assertSchema(t, `"unknown"`, contract.Output)
+}
+
+func TestProjectUsecase_InferContract_NotFound(t *testing.T) {
+	f := newContractFixture()
+	uc := NewProjectUsecase(f.projectRepo, f.stepRepo, f.edgeRepo, nil, f.blockRepo)
+
+	if _, err := uc.InferContract(context.Background(), f.tenantID, uuid.New()); err == nil {
+		t.Error("InferContract() expected error for missing project")
+	}
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/engine"
+)
+
+const (
+	// charsPerToken approximates how many characters of prompt text make up one token
+	charsPerToken = 4
+	// minOutputShare is the share of max_tokens the low end of an estimate assumes is generated
+	minOutputShare = 0.25
+	// defaultWhileIterations matches the engine's safety limit for while groups
+	defaultWhileIterations = 100
+)
+
+// llmDefaults are the model and max_tokens the adapters use when a step does not configure them
+var llmDefaults = map[string]struct {
+	Model     string
+	MaxTokens int
+}{
+	"openai":    {Model: "gpt-4", MaxTokens: 2048},
+	"anthropic": {Model: "claude-3-sonnet-20240229", MaxTokens: 4096},
+}
+
+// CostRange is a low/high range of an estimated quantity
+type CostRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// CountRange is a low/high range of an estimated count
+type CountRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// StepCostEstimate is the estimated cost of one LLM step
+type StepCostEstimate struct {
+	StepID       uuid.UUID  `json:"step_id"`
+	StepName     string     `json:"step_name"`
+	Provider     string     `json:"provider"`
+	Model        string     `json:"model"`
+	MaxTokens    int        `json:"max_tokens"`
+	Calls        CountRange `json:"calls"`         // Iterations of enclosing foreach/while groups
+	InputTokens  int        `json:"input_tokens"`  // Per call
+	OutputTokens CountRange `json:"output_tokens"` // Total over all calls
+	CostUSD      CostRange  `json:"cost_usd"`
+	Priced       bool       `json:"priced"` // false when the model is not in the pricing table
+}
+
+// CostEstimate is a rough, pre-run cost estimate of a workflow. Token counts are approximated
+// from prompt and input sizes, and output is assumed to use between a quarter of and the full
+// max_tokens; actual usage can differ.
+type CostEstimate struct {
+	ProjectID    uuid.UUID          `json:"project_id"`
+	Version      int                `json:"version"`
+	Estimate     bool               `json:"estimate"` // Always true: the figures are not measured usage
+	LLMSteps     int                `json:"llm_steps"`
+	Models       []string           `json:"models"` // provider:model
+	Steps        []StepCostEstimate `json:"steps"`
+	InputTokens  CountRange         `json:"input_tokens"`
+	OutputTokens CountRange         `json:"output_tokens"`
+	CostUSD      CostRange          `json:"cost_usd"`
+	Warnings     []string           `json:"warnings"`
+}
+
+// llmStepConfig holds the step config fields that affect token usage
+type llmStepConfig struct {
+	Provider     string `json:"provider"`
+	Model        string `json:"model"`
+	MaxTokens    int    `json:"max_tokens"`
+	Prompt       string `json:"prompt"`
+	UserPrompt   string `json:"user_prompt"`
+	System       string `json:"system"`
+	SystemPrompt string `json:"system_prompt"`
+	Messages     []struct {
+		Content string `json:"content"`
+	} `json:"messages"`
+}
+
+// EstimateCost estimates the cost of running the saved workflow with the given sample input.
+// Steps inside foreach groups are multiplied by the number of items the group's input_path
+// selects from the sample input; steps inside while groups range from one iteration to the
+// group's max_iterations.
+func (u *ProjectUsecase) EstimateCost(ctx context.Context, tenantID, projectID uuid.UUID, input json.RawMessage) (*CostEstimate, error) {
+	project, err := u.getProjectWithStepsEdgesFromDB(ctx, tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[uuid.UUID]*domain.BlockGroup)
+	if u.blockGroupRepo != nil {
+		list, err := u.blockGroupRepo.ListByProject(ctx, tenantID, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range list {
+			groups[group.ID] = group
+		}
+	}
+
+	return estimateCost(project, groups, input), nil
+}
+
+// estimateCost builds the cost estimate of the project's LLM steps
+func estimateCost(project *domain.Project, groups map[uuid.UUID]*domain.BlockGroup, input json.RawMessage) *CostEstimate {
+	estimate := &CostEstimate{
+		ProjectID: project.ID,
+		Version:   project.Version,
+		Estimate:  true,
+		Models:    make([]string, 0),
+		Steps:     make([]StepCostEstimate, 0),
+		Warnings:  make([]string, 0),
+	}
+
+	var sample map[string]interface{}
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &sample); err != nil {
+			estimate.Warnings = append(estimate.Warnings, "sample input is not a JSON object; loop iterations are estimated as 1")
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return groups[ids[i]].Name < groups[ids[j]].Name })
+	for _, id := range ids {
+		if groups[id].Type == domain.BlockGroupTypeAgent {
+			estimate.Warnings = append(estimate.Warnings,
+				fmt.Sprintf("agent group %q is not included in the estimate", groups[id].Name))
+		}
+	}
+
+	steps := make([]domain.Step, 0)
+	for _, step := range project.Steps {
+		if step.Type == domain.StepTypeLLM {
+			steps = append(steps, step)
+		}
+	}
+	sortStepsByName(steps)
+
+	models := make(map[string]bool)
+	for _, step := range steps {
+		stepEstimate, warnings := estimateStepCost(step, groups, sample, len(input))
+		estimate.Steps = append(estimate.Steps, stepEstimate)
+		estimate.Warnings = append(estimate.Warnings, warnings...)
+
+		estimate.InputTokens.Min += stepEstimate.InputTokens * stepEstimate.Calls.Min
+		estimate.InputTokens.Max += stepEstimate.InputTokens * stepEstimate.Calls.Max
+		estimate.OutputTokens.Min += stepEstimate.OutputTokens.Min
+		estimate.OutputTokens.Max += stepEstimate.OutputTokens.Max
+		estimate.CostUSD.Min += stepEstimate.CostUSD.Min
+		estimate.CostUSD.Max += stepEstimate.CostUSD.Max
+		models[stepEstimate.Provider+":"+stepEstimate.Model] = true
+	}
+	estimate.LLMSteps = len(estimate.Steps)
+
+	for model := range models {
+		estimate.Models = append(estimate.Models, model)
+	}
+	sort.Strings(estimate.Models)
+
+	return estimate
+}
+
+// estimateStepCost estimates the calls, tokens and cost of one LLM step
+func estimateStepCost(step domain.Step, groups map[uuid.UUID]*domain.BlockGroup, sample map[string]interface{}, inputChars int) (StepCostEstimate, []string) {
+	warnings := make([]string, 0)
+
+	var config llmStepConfig
+	if len(step.Config) > 0 {
+		if err := json.Unmarshal(step.Config, &config); err != nil {
+			warnings = append(warnings, fmt.Sprintf("step %q: invalid config; adapter defaults are assumed", step.Name))
+		}
+	}
+	if config.Provider == "" {
+		config.Provider = "openai"
+	}
+	defaults := llmDefaults[config.Provider]
+	if config.Model == "" {
+		config.Model = defaults.Model
+	}
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = defaults.MaxTokens
+	}
+
+	calls, itemChars, loopWarnings := stepIterations(step, groups, sample)
+	warnings = append(warnings, loopWarnings...)
+	if itemChars >= 0 {
+		inputChars = itemChars
+	}
+
+	promptChars := len(config.Prompt) + len(config.UserPrompt) + len(config.System) + len(config.SystemPrompt)
+	if len(config.Messages) > 0 {
+		// A message array replaces the prompt fields
+		promptChars = 0
+		for _, msg := range config.Messages {
+			promptChars += len(msg.Content)
+		}
+	}
+	inputTokens := tokensForChars(promptChars + inputChars)
+	minOutput := int(math.Ceil(float64(config.MaxTokens) * minOutputShare))
+
+	stepEstimate := StepCostEstimate{
+		StepID:      step.ID,
+		StepName:    step.Name,
+		Provider:    config.Provider,
+		Model:       config.Model,
+		MaxTokens:   config.MaxTokens,
+		Calls:       calls,
+		InputTokens: inputTokens,
+		OutputTokens: CountRange{
+			Min: minOutput * calls.Min,
+			Max: config.MaxTokens * calls.Max,
+		},
+	}
+
+	if pricing := domain.GetPricing(config.Provider, config.Model); pricing != nil {
+		stepEstimate.Priced = true
+		_, _, low := domain.CalculateCost(config.Provider, config.Model, inputTokens*calls.Min, stepEstimate.OutputTokens.Min)
+		_, _, high := domain.CalculateCost(config.Provider, config.Model, inputTokens*calls.Max, stepEstimate.OutputTokens.Max)
+		stepEstimate.CostUSD = CostRange{Min: low, Max: high}
+	} else {
+		warnings = append(warnings, fmt.Sprintf("step %q: no pricing for %s:%s; its cost is counted as 0", step.Name, config.Provider, config.Model))
+	}
+
+	return stepEstimate, warnings
+}
+
+// stepIterations returns how many times the step runs per workflow run, multiplying the
+// iterations of its enclosing loop groups. itemChars is the average JSON size of the items of
+// the innermost foreach group, or -1 when the step is not inside one.
+func stepIterations(step domain.Step, groups map[uuid.UUID]*domain.BlockGroup, sample map[string]interface{}) (CountRange, int, []string) {
+	calls := CountRange{Min: 1, Max: 1}
+	itemChars := -1
+	warnings := make([]string, 0)
+
+	visited := make(map[uuid.UUID]bool)
+	for groupID := step.BlockGroupID; groupID != nil && !visited[*groupID]; {
+		visited[*groupID] = true
+		group, ok := groups[*groupID]
+		if !ok {
+			break
+		}
+
+		switch group.Type {
+		case domain.BlockGroupTypeForeach:
+			var config domain.ForeachConfig
+			if len(group.Config) > 0 {
+				_ = json.Unmarshal(group.Config, &config)
+			}
+			items, ok := sampleItems(config.InputPath, sample)
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("foreach group %q: items not found in the sample input; 1 iteration is assumed", group.Name))
+				break
+			}
+			calls.Min *= len(items)
+			calls.Max *= len(items)
+			if itemChars < 0 && len(items) > 0 {
+				data, _ := json.Marshal(items)
+				itemChars = len(data) / len(items)
+			}
+		case domain.BlockGroupTypeWhile:
+			var config domain.WhileConfig
+			if len(group.Config) > 0 {
+				_ = json.Unmarshal(group.Config, &config)
+			}
+			maxIterations := config.MaxIterations
+			if maxIterations <= 0 {
+				maxIterations = defaultWhileIterations
+			}
+			calls.Max *= maxIterations
+		}
+
+		groupID = group.ParentGroupID
+	}
+
+	return calls, itemChars, warnings
+}
+
+// sampleItems resolves a foreach input_path (default "$.items") against the sample input
+func sampleItems(inputPath string, sample map[string]interface{}) ([]interface{}, bool) {
+	if sample == nil {
+		return nil, false
+	}
+	if inputPath == "" {
+		inputPath = "$.items"
+	}
+	resolved, err := engine.NewConditionEvaluator().ResolveValue(inputPath, sample)
+	if err != nil {
+		return nil, false
+	}
+	items, ok := resolved.([]interface{})
+	return items, ok
+}
+
+// tokensForChars approximates the token count of text with the given number of characters
+func tokensForChars(chars int) int {
+	return (chars + charsPerToken - 1) / charsPerToken
+}
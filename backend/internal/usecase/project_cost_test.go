@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+const estimateLLMConfig = `{"provider": "openai", "model": "gpt-4o-mini", "max_tokens": 500, "user_prompt": "Summarize {{input}}"}`
+
+func (f *contractFixture) estimate(t *testing.T, input string) *CostEstimate {
+	t.Helper()
+	uc := NewProjectUsecase(f.projectRepo, f.stepRepo, f.edgeRepo, nil, f.blockRepo)
+	estimate, err := uc.EstimateCost(context.Background(), f.tenantID, f.project.ID, json.RawMessage(input))
+	if err != nil {
+		t.Fatalf("EstimateCost() error = %v", err)
+	}
+	return estimate
+}
+
+func assertCostClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestEstimateCost_ScalesWithLLMSteps(t *testing.T) {
+	one := newContractFixture()
+	one.addStep("Start", domain.StepTypeStart, "")
+	one.addStep("Summarize", domain.StepTypeLLM, estimateLLMConfig)
+
+	three := newContractFixture()
+	three.addStep("Start", domain.StepTypeStart, "")
+	three.addStep("Summarize", domain.StepTypeLLM, estimateLLMConfig)
+	three.addStep("Translate", domain.StepTypeLLM, estimateLLMConfig)
+	three.addStep("Review", domain.StepTypeLLM, estimateLLMConfig)
+
+	input := `{"text": "hello world"}`
+	single := one.estimate(t, input)
+	triple := three.estimate(t, input)
+
+	if !single.Estimate {
+		t.Error("Estimate = false, want the result labeled as an estimate")
+	}
+	if single.LLMSteps != 1 || triple.LLMSteps != 3 {
+		t.Fatalf("LLMSteps = %d and %d, want 1 and 3", single.LLMSteps, triple.LLMSteps)
+	}
+	if single.CostUSD.Min <= 0 || single.CostUSD.Max <= single.CostUSD.Min {
+		t.Fatalf("CostUSD = %+v, want a positive range", single.CostUSD)
+	}
+	assertCostClose(t, "3-step min cost", triple.CostUSD.Min, 3*single.CostUSD.Min)
+	assertCostClose(t, "3-step max cost", triple.CostUSD.Max, 3*single.CostUSD.Max)
+	if len(triple.Models) != 1 || triple.Models[0] != "openai:gpt-4o-mini" {
+		t.Errorf("Models = %v, want [openai:gpt-4o-mini]", triple.Models)
+	}
+}
+
+func TestEstimateCost_ScalesWithMaxTokens(t *testing.T) {
+	estimateWithMaxTokens := func(maxTokens int) *CostEstimate {
+		f := newContractFixture()
+		config, _ := json.Marshal(map[string]interface{}{
+			"provider": "anthropic", "model": "claude-3-haiku", "max_tokens": maxTokens, "user_prompt": "Summarize",
+		})
+		f.addStep("Summarize", domain.StepTypeLLM, string(config))
+		return f.estimate(t, `{}`)
+	}
+
+	small := estimateWithMaxTokens(1000)
+	large := estimateWithMaxTokens(4000)
+
+	if large.OutputTokens.Max != 4*small.OutputTokens.Max {
+		t.Errorf("max output tokens = %d and %d, want 4x", small.OutputTokens.Max, large.OutputTokens.Max)
+	}
+	if large.OutputTokens.Min != 4*small.OutputTokens.Min {
+		t.Errorf("min output tokens = %d and %d, want 4x", small.OutputTokens.Min, large.OutputTokens.Min)
+	}
+	if large.CostUSD.Max <= small.CostUSD.Max || large.CostUSD.Min <= small.CostUSD.Min {
+		t.Errorf("CostUSD = %+v and %+v, want a higher range for the larger max_tokens", small.CostUSD, large.CostUSD)
+	}
+}
+
+func TestEstimateCost_ForeachIterations(t *testing.T) {
+	projectID := uuid.New()
+	group := &domain.BlockGroup{ID: uuid.New(), ProjectID: projectID, Name: "Each document", Type: domain.BlockGroupTypeForeach,
+		Config: json.RawMessage(`{"input_path": "$.documents"}`)}
+	project := &domain.Project{ID: projectID, Steps: []domain.Step{
+		{ID: uuid.New(), Name: "Summarize", Type: domain.StepTypeLLM, BlockGroupID: &group.ID, Config: json.RawMessage(estimateLLMConfig)},
+	}}
+	groups := map[uuid.UUID]*domain.BlockGroup{group.ID: group}
+
+	estimate := estimateCost(project, groups, json.RawMessage(`{"documents": ["a", "b", "c", "d"]}`))
+	if got := estimate.Steps[0].Calls; got.Min != 4 || got.Max != 4 {
+		t.Errorf("Calls = %+v, want 4 iterations", got)
+	}
+	if len(estimate.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", estimate.Warnings)
+	}
+
+	missing := estimateCost(project, groups, json.RawMessage(`{}`))
+	if got := missing.Steps[0].Calls; got.Min != 1 || got.Max != 1 {
+		t.Errorf("Calls without items = %+v, want 1 iteration", got)
+	}
+	if len(missing.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want a warning about the missing items", missing.Warnings)
+	}
+}
+
+func TestEstimateCost_UnpricedModel(t *testing.T) {
+	f := newContractFixture()
+	f.addStep("Custom", domain.StepTypeLLM, `{"provider": "openai", "model": "unreleased-model"}`)
+
+	estimate := f.estimate(t, "")
+	if estimate.Steps[0].Priced || estimate.CostUSD.Max != 0 {
+		t.Errorf("step = %+v, want an unpriced step with zero cost", estimate.Steps[0])
+	}
+	if estimate.Steps[0].MaxTokens != 2048 {
+		t.Errorf("MaxTokens = %d, want the OpenAI adapter default 2048", estimate.Steps[0].MaxTokens)
+	}
+	if len(estimate.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want a missing pricing warning", estimate.Warnings)
+	}
+}
+
+func TestEstimateCost_CountsMessages(t *testing.T) {
+	estimateWithConfig := func(config map[string]interface{}) *CostEstimate {
+		f := newContractFixture()
+		config["provider"], config["model"] = "openai", "gpt-4o-mini"
+		raw, _ := json.Marshal(config)
+		f.addStep("Classify", domain.StepTypeLLM, string(raw))
+		return f.estimate(t, `{}`)
+	}
+
+	prompt := estimateWithConfig(map[string]interface{}{"user_prompt": "ignored"})
+	messages := estimateWithConfig(map[string]interface{}{
+		"user_prompt": "ignored",
+		"messages": []map[string]string{
+			{"role": "user", "content": "Example input that is quite a bit longer than the prompt"},
+			{"role": "assistant", "content": "positive"},
+			{"role": "user", "content": "Works great"},
+		},
+	})
+
+	if messages.InputTokens.Max <= prompt.InputTokens.Max {
+		t.Errorf("input tokens = %d with messages and %d with the prompt, want messages counted instead of the prompt",
+			messages.InputTokens.Max, prompt.InputTokens.Max)
+	}
+}
@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// Description sources
+const (
+	DescriptionSourceLLM      = "llm"
+	DescriptionSourceTemplate = "template"
+)
+
+// describeSystemPrompt instructs the LLM how to summarize a workflow
+const describeSystemPrompt = `You write short, human-readable summaries of automation workflows for a marketplace listing.
+Describe what the workflow does in 2-3 sentences. Do not list every step, do not mention IDs, and do not use markdown.`
+
+// WorkflowDescription represents a natural-language summary of a workflow
+type WorkflowDescription struct {
+	ProjectID   uuid.UUID `json:"project_id"`
+	Version     int       `json:"version"`
+	Summary     string    `json:"summary"`
+	Source      string    `json:"source"` // "llm" or "template"
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// maxCachedDescriptions bounds the description cache; the least recently used entry is evicted
+const maxCachedDescriptions = 1000
+
+// descriptionCache caches workflow descriptions keyed by project ID, evicting the least recently
+// used project beyond maxEntries. An entry is only valid for the project version and the workflow
+// content (see describeContentHash) it was generated from, as steps and edges are edited in
+// place without a new version.
+type descriptionCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front = most recently used
+	entries    map[uuid.UUID]*list.Element
+}
+
+type cachedDescription struct {
+	desc        *WorkflowDescription
+	contentHash string
+}
+
+func newDescriptionCache() *descriptionCache {
+	return &descriptionCache{
+		maxEntries: maxCachedDescriptions,
+		order:      list.New(),
+		entries:    make(map[uuid.UUID]*list.Element),
+	}
+}
+
+// get returns the cached description if it was generated for the given version and content
+func (c *descriptionCache) get(projectID uuid.UUID, version int, contentHash string) (*WorkflowDescription, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[projectID]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedDescription)
+	if entry.desc.Version != version || entry.contentHash != contentHash {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.desc, true
+}
+
+func (c *descriptionCache) set(desc *WorkflowDescription, contentHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &cachedDescription{desc: desc, contentHash: contentHash}
+	if elem, ok := c.entries[desc.ProjectID]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[desc.ProjectID] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedDescription).desc.ProjectID)
+	}
+}
+
+// describeContentHash hashes the workflow structure a description is generated from (name,
+// description, steps and connections), so edits that do not bump the version still regenerate it.
+// Steps and connections are hashed in a canonical order, independent of how they were loaded.
+func describeContentHash(project *domain.Project) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q\n%q\n", project.Name, project.Description)
+	for _, step := range orderStepsForDescription(project.Steps, project.Edges) {
+		fmt.Fprintf(h, "step %s %q %s\n", step.ID, step.Name, step.Type)
+	}
+	connections := make([]string, 0, len(project.Edges))
+	for _, edge := range project.Edges {
+		if edge.SourceStepID == nil || edge.TargetStepID == nil {
+			continue
+		}
+		connections = append(connections, fmt.Sprintf("edge %s %s %q\n", edge.SourceStepID, edge.TargetStepID, edge.SourcePort))
+	}
+	sort.Strings(connections)
+	for _, connection := range connections {
+		h.Write([]byte(connection))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WithDescriber configures the LLM adapter used to generate workflow descriptions.
+// If adapterID is empty or not registered, Describe falls back to a template-based summary.
+func (u *ProjectUsecase) WithDescriber(registry *adapter.Registry, adapterID, model string) *ProjectUsecase {
+	u.adapterRegistry = registry
+	u.describeAdapterID = adapterID
+	u.describeModel = model
+	return u
+}
+
+// Describe returns a natural-language summary of the saved workflow.
+// The result is cached per project version and content, and regenerated when either changes.
+// A template fallback caused by a failed LLM call is not cached, so the next call retries the LLM.
+func (u *ProjectUsecase) Describe(ctx context.Context, tenantID, projectID uuid.UUID) (*WorkflowDescription, error) {
+	// Describe the saved state (not the draft) so that the version is a valid cache key
+	project, err := u.getProjectWithStepsEdgesFromDB(ctx, tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.descriptions == nil {
+		u.descriptions = newDescriptionCache()
+	}
+	contentHash := describeContentHash(project)
+	if cached, ok := u.descriptions.get(project.ID, project.Version, contentHash); ok {
+		return cached, nil
+	}
+
+	desc := &WorkflowDescription{
+		ProjectID:   project.ID,
+		Version:     project.Version,
+		Source:      DescriptionSourceTemplate,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	if summary, err := u.describeWithLLM(ctx, project); err != nil {
+		slog.Warn("failed to generate workflow description with LLM, using template fallback",
+			"project_id", project.ID,
+			"adapter", u.describeAdapterID,
+			"error", err,
+		)
+		desc.Summary = templateDescription(project)
+		return desc, nil
+	} else if summary != "" {
+		desc.Summary = summary
+		desc.Source = DescriptionSourceLLM
+	} else {
+		desc.Summary = templateDescription(project)
+	}
+
+	u.descriptions.set(desc, contentHash)
+	return desc, nil
+}
+
+// describeWithLLM asks the configured LLM adapter for a summary.
+// Returns an empty string without error when no LLM is configured.
+func (u *ProjectUsecase) describeWithLLM(ctx context.Context, project *domain.Project) (string, error) {
+	if u.adapterRegistry == nil || u.describeAdapterID == "" {
+		return "", nil
+	}
+	llm, ok := u.adapterRegistry.Get(u.describeAdapterID)
+	if !ok {
+		return "", nil
+	}
+
+	config := map[string]interface{}{
+		"system":     describeSystemPrompt,
+		"prompt":     buildDescribePrompt(project),
+		"max_tokens": 300,
+	}
+	if u.describeModel != "" {
+		config["model"] = u.describeModel
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("marshal describe config: %w", err)
+	}
+
+	resp, err := llm.Execute(ctx, &adapter.Request{
+		Input:  json.RawMessage(`{}`),
+		Config: configJSON,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var output struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(resp.Output, &output); err != nil {
+		return "", fmt.Errorf("parse describe output: %w", err)
+	}
+	return strings.TrimSpace(output.Content), nil
+}
+
+// buildDescribePrompt composes the workflow structure into an LLM prompt
+func buildDescribePrompt(project *domain.Project) string {
+	names := make(map[uuid.UUID]string, len(project.Steps))
+	for _, step := range project.Steps {
+		names[step.ID] = step.Name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Workflow name: %s\n", project.Name)
+	if project.Description != "" {
+		fmt.Fprintf(&b, "Author description: %s\n", project.Description)
+	}
+	b.WriteString("\nSteps (name: block type):\n")
+	for _, step := range orderStepsForDescription(project.Steps, project.Edges) {
+		fmt.Fprintf(&b, "- %s: %s\n", step.Name, step.Type)
+	}
+	b.WriteString("\nConnections:\n")
+	for _, edge := range project.Edges {
+		if edge.SourceStepID == nil || edge.TargetStepID == nil {
+			continue
+		}
+		line := fmt.Sprintf("- %s -> %s", names[*edge.SourceStepID], names[*edge.TargetStepID])
+		if edge.SourcePort != "" {
+			line += fmt.Sprintf(" (port: %s)", edge.SourcePort)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\nWrite the summary now.")
+	return b.String()
+}
+
+// templateDescription builds a deterministic summary from the workflow structure.
+// Used when no LLM is configured or the LLM call fails.
+func templateDescription(project *domain.Project) string {
+	steps := orderStepsForDescription(project.Steps, project.Edges)
+	if len(steps) == 0 {
+		return fmt.Sprintf("Workflow %q has no steps yet.", project.Name)
+	}
+
+	connections := 0
+	for _, edge := range project.Edges {
+		if edge.SourceStepID != nil && edge.TargetStepID != nil {
+			connections++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Workflow %q has %d step(s) and %d connection(s).", project.Name, len(steps), connections)
+	fmt.Fprintf(&b, " It starts with %s (%s)", steps[0].Name, steps[0].Type)
+	if len(steps) > 2 {
+		middle := make([]string, 0, len(steps)-2)
+		for _, step := range steps[1 : len(steps)-1] {
+			middle = append(middle, fmt.Sprintf("%s (%s)", step.Name, step.Type))
+		}
+		fmt.Fprintf(&b, ", then runs %s", strings.Join(middle, ", "))
+	}
+	if len(steps) > 1 {
+		last := steps[len(steps)-1]
+		fmt.Fprintf(&b, ", and finishes with %s (%s)", last.Name, last.Type)
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// orderStepsForDescription orders steps topologically so the summary follows the flow.
+// Ties are broken by name and ID to keep the output deterministic.
+func orderStepsForDescription(steps []domain.Step, edges []domain.Edge) []domain.Step {
+	inDegree := make(map[uuid.UUID]int, len(steps))
+	stepMap := make(map[uuid.UUID]domain.Step, len(steps))
+	for _, step := range steps {
+		inDegree[step.ID] = 0
+		stepMap[step.ID] = step
+	}
+	adj := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range edges {
+		if edge.SourceStepID == nil || edge.TargetStepID == nil {
+			continue
+		}
+		if _, ok := stepMap[*edge.SourceStepID]; !ok {
+			continue
+		}
+		if _, ok := stepMap[*edge.TargetStepID]; !ok {
+			continue
+		}
+		adj[*edge.SourceStepID] = append(adj[*edge.SourceStepID], *edge.TargetStepID)
+		inDegree[*edge.TargetStepID]++
+	}
+
+	less := func(a, b domain.Step) bool {
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID.String() < b.ID.String()
+	}
+
+	var ready []domain.Step
+	for _, step := range steps {
+		if inDegree[step.ID] == 0 {
+			ready = append(ready, step)
+		}
+	}
+
+	ordered := make([]domain.Step, 0, len(steps))
+	visited := make(map[uuid.UUID]bool, len(steps))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		current := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, current)
+		visited[current.ID] = true
+		for _, next := range adj[current.ID] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, stepMap[next])
+			}
+		}
+	}
+
+	// Steps in cycles are appended in deterministic order
+	var remaining []domain.Step
+	for _, step := range steps {
+		if !visited[step.ID] {
+			remaining = append(remaining, step)
+		}
+	}
+	sort.Slice(remaining, func(i, j int) bool { return less(remaining[i], remaining[j]) })
+	return append(ordered, remaining...)
+}
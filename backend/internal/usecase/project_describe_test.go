@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/adapter"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// countingLLMAdapter is an LLM adapter stub that records how often it is called
+type countingLLMAdapter struct {
+	calls   int
+	content string
+	err     error
+}
+
+func (a *countingLLMAdapter) ID() string   { return "test-llm" }
+func (a *countingLLMAdapter) Name() string { return "Test LLM" }
+
+func (a *countingLLMAdapter) Execute(ctx context.Context, req *adapter.Request) (*adapter.Response, error) {
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+	output, _ := json.Marshal(map[string]string{"content": a.content})
+	return &adapter.Response{Output: output}, nil
+}
+
+func (a *countingLLMAdapter) InputSchema() json.RawMessage  { return nil }
+func (a *countingLLMAdapter) OutputSchema() json.RawMessage { return nil }
+
+// ============================================================================
+// Test Helpers
+// ============================================================================
+
+// seedDescribeProject creates a 3-step linear workflow: Start -> Summarize -> Notify
+func seedDescribeProject(projectRepo *mockProjectRepo, stepRepo *mockStepRepo, edgeRepo *mockEdgeRepo, tenantID uuid.UUID) *domain.Project {
+	project := domain.NewProject(tenantID, "Support Triage", "")
+	project.Version = 1
+	projectRepo.projects[project.ID] = project
+
+	start := &domain.Step{ID: uuid.New(), TenantID: tenantID, ProjectID: project.ID, Name: "Start", Type: "manual_trigger"}
+	llm := &domain.Step{ID: uuid.New(), TenantID: tenantID, ProjectID: project.ID, Name: "Summarize", Type: domain.StepTypeLLM}
+	notify := &domain.Step{ID: uuid.New(), TenantID: tenantID, ProjectID: project.ID, Name: "Notify", Type: "slack"}
+	for _, s := range []*domain.Step{notify, llm, start} {
+		stepRepo.steps[s.ID] = s
+	}
+
+	for _, pair := range [][2]*domain.Step{{start, llm}, {llm, notify}} {
+		edge := &domain.Edge{
+			ID:           uuid.New(),
+			TenantID:     tenantID,
+			ProjectID:    project.ID,
+			SourceStepID: &pair[0].ID,
+			TargetStepID: &pair[1].ID,
+		}
+		edgeRepo.edges[edge.ID] = edge
+	}
+	return project
+}
+
+// ============================================================================
+// Tests for Describe
+// ============================================================================
+
+func TestProjectUsecase_Describe_CachesByVersion(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo, stepRepo, edgeRepo := newMockProjectRepo(), newMockStepRepo(), newMockEdgeRepo()
+	project := seedDescribeProject(projectRepo, stepRepo, edgeRepo, tenantID)
+
+	llm := &countingLLMAdapter{content: "Triages support tickets and notifies the team."}
+	registry := adapter.NewRegistry()
+	registry.Register(llm)
+
+	uc := NewProjectUsecase(projectRepo, stepRepo, edgeRepo, nil, nil).
+		WithDescriber(registry, llm.ID(), "")
+
+	first, err := uc.Describe(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if first.Source != DescriptionSourceLLM {
+		t.Errorf("Source = %q, want %q", first.Source, DescriptionSourceLLM)
+	}
+	if first.Summary != llm.content {
+		t.Errorf("Summary = %q, want %q", first.Summary, llm.content)
+	}
+
+	// Same version: served from cache
+	if _, err := uc.Describe(context.Background(), tenantID, project.ID); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if llm.calls != 1 {
+		t.Errorf("LLM calls = %d, want 1 (cached)", llm.calls)
+	}
+
+	// New version: regenerated
+	projectRepo.projects[project.ID].Version = 2
+	llm.content = "Version two summary."
+	second, err := uc.Describe(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if llm.calls != 2 {
+		t.Errorf("LLM calls = %d, want 2 (regenerated on version change)", llm.calls)
+	}
+	if second.Version != 2 || second.Summary != "Version two summary." {
+		t.Errorf("got version %d summary %q, want version 2 regenerated summary", second.Version, second.Summary)
+	}
+}
+
+func TestProjectUsecase_Describe_TemplateFallback(t *testing.T) {
+	tenantID := uuid.New()
+	want := `Workflow "Support Triage" has 3 step(s) and 2 connection(s). It starts with Start (manual_trigger), then runs Summarize (llm), and finishes with Notify (slack).`
+
+	tests := []struct {
+		name     string
+		llm      *countingLLMAdapter
+		register bool
+	}{
+		{name: "no LLM configured"},
+		{name: "adapter not registered", llm: &countingLLMAdapter{}, register: false},
+		{name: "LLM error", llm: &countingLLMAdapter{err: errors.New("provider down")}, register: true},
+		{name: "LLM returns empty content", llm: &countingLLMAdapter{content: "  "}, register: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRepo, stepRepo, edgeRepo := newMockProjectRepo(), newMockStepRepo(), newMockEdgeRepo()
+			project := seedDescribeProject(projectRepo, stepRepo, edgeRepo, tenantID)
+
+			uc := NewProjectUsecase(projectRepo, stepRepo, edgeRepo, nil, nil)
+			if tt.llm != nil {
+				registry := adapter.NewRegistry()
+				if tt.register {
+					registry.Register(tt.llm)
+				}
+				uc.WithDescriber(registry, tt.llm.ID(), "")
+			}
+
+			// Run twice to verify the fallback is deterministic
+			for i := 0; i < 2; i++ {
+				uc.descriptions = newDescriptionCache()
+				desc, err := uc.Describe(context.Background(), tenantID, project.ID)
+				if err != nil {
+					t.Fatalf("Describe() error = %v", err)
+				}
+				if desc.Source != DescriptionSourceTemplate {
+					t.Errorf("Source = %q, want %q", desc.Source, DescriptionSourceTemplate)
+				}
+				if desc.Summary != want {
+					t.Errorf("Summary = %q, want %q", desc.Summary, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectUsecase_Describe_RegeneratesOnStepEdit(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo, stepRepo, edgeRepo := newMockProjectRepo(), newMockStepRepo(), newMockEdgeRepo()
+	project := seedDescribeProject(projectRepo, stepRepo, edgeRepo, tenantID)
+
+	llm := &countingLLMAdapter{content: "Original summary."}
+	registry := adapter.NewRegistry()
+	registry.Register(llm)
+	uc := NewProjectUsecase(projectRepo, stepRepo, edgeRepo, nil, nil).
+		WithDescriber(registry, llm.ID(), "")
+
+	if _, err := uc.Describe(context.Background(), tenantID, project.ID); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	// Steps and edges are edited in place, so the version stays the same
+	for _, step := range stepRepo.steps {
+		if step.Name == "Notify" {
+			step.Name = "Escalate"
+		}
+	}
+	llm.content = "Edited summary."
+	desc, err := uc.Describe(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if llm.calls != 2 {
+		t.Errorf("LLM calls = %d, want 2 (regenerated on step edit)", llm.calls)
+	}
+	if desc.Summary != "Edited summary." {
+		t.Errorf("Summary = %q, want the regenerated summary", desc.Summary)
+	}
+}
+
+func TestProjectUsecase_Describe_DoesNotCacheFailedLLM(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo, stepRepo, edgeRepo := newMockProjectRepo(), newMockStepRepo(), newMockEdgeRepo()
+	project := seedDescribeProject(projectRepo, stepRepo, edgeRepo, tenantID)
+
+	llm := &countingLLMAdapter{err: errors.New("provider down")}
+	registry := adapter.NewRegistry()
+	registry.Register(llm)
+	uc := NewProjectUsecase(projectRepo, stepRepo, edgeRepo, nil, nil).
+		WithDescriber(registry, llm.ID(), "")
+
+	desc, err := uc.Describe(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if desc.Source != DescriptionSourceTemplate {
+		t.Fatalf("Source = %q, want %q", desc.Source, DescriptionSourceTemplate)
+	}
+
+	// The provider recovers: the next call asks the LLM again instead of serving the fallback
+	llm.err = nil
+	llm.content = "Recovered summary."
+	desc, err = uc.Describe(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if llm.calls != 2 {
+		t.Errorf("LLM calls = %d, want 2", llm.calls)
+	}
+	if desc.Source != DescriptionSourceLLM || desc.Summary != "Recovered summary." {
+		t.Errorf("got source %q summary %q, want the LLM summary", desc.Source, desc.Summary)
+	}
+}
+
+func TestDescriptionCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newDescriptionCache()
+	cache.maxEntries = 2
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	cache.set(&WorkflowDescription{ProjectID: first, Version: 1}, "h")
+	cache.set(&WorkflowDescription{ProjectID: second, Version: 1}, "h")
+
+	// Reading first makes second the least recently used
+	if _, ok := cache.get(first, 1, "h"); !ok {
+		t.Fatal("first should be cached")
+	}
+	cache.set(&WorkflowDescription{ProjectID: third, Version: 1}, "h")
+
+	if _, ok := cache.get(second, 1, "h"); ok {
+		t.Error("second should have been evicted")
+	}
+	for _, id := range []uuid.UUID{first, third} {
+		if _, ok := cache.get(id, 1, "h"); !ok {
+			t.Errorf("%s should still be cached", id)
+		}
+	}
+	if _, ok := cache.get(first, 1, "other"); ok {
+		t.Error("an entry must not be served for different content")
+	}
+}
+
+func TestBuildDescribePrompt(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo, stepRepo, edgeRepo := newMockProjectRepo(), newMockStepRepo(), newMockEdgeRepo()
+	seeded := seedDescribeProject(projectRepo, stepRepo, edgeRepo, tenantID)
+
+	uc := NewProjectUsecase(projectRepo, stepRepo, edgeRepo, nil, nil)
+	project, err := uc.getProjectWithStepsEdgesFromDB(context.Background(), tenantID, seeded.ID)
+	if err != nil {
+		t.Fatalf("getProjectWithStepsEdgesFromDB() error = %v", err)
+	}
+
+	prompt := buildDescribePrompt(project)
+	for _, want := range []string{"Workflow name: Support Triage", "- Summarize: llm", "- Start -> Summarize", "- Summarize -> Notify"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestTemplateDescription_NoSteps(t *testing.T) {
+	project := domain.NewProject(uuid.New(), "Empty", "")
+	if got := templateDescription(project); got != `Workflow "Empty" has no steps yet.` {
+		t.Errorf("templateDescription() = %q", got)
+	}
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/engine"
+)
+
+// Weights of the edge suggestion heuristics; a candidate's score is their weighted sum (0-1)
+const (
+	edgeSuggestProximityWeight = 0.4
+	edgeSuggestDataFlowWeight  = 0.4
+	edgeSuggestPatternWeight   = 0.2
+
+	// edgeSuggestDistanceScale is the canvas distance at which the spatial proximity score halves
+	edgeSuggestDistanceScale = 400.0
+)
+
+// SuggestedEdge is a proposed connection into an orphan step
+type SuggestedEdge struct {
+	SourceStepID   uuid.UUID `json:"source_step_id"`
+	SourceStepName string    `json:"source_step_name"`
+	SourcePort     string    `json:"source_port"`
+	TargetStepID   uuid.UUID `json:"target_step_id"`
+	TargetStepName string    `json:"target_step_name"`
+	Score          float64   `json:"score"`   // 0-1, higher is more likely
+	Reasons        []string  `json:"reasons"` // Heuristics that produced the suggestion
+}
+
+// EdgeSuggestions lists the orphan steps of a workflow and the edges proposed to connect them
+type EdgeSuggestions struct {
+	ProjectID   uuid.UUID       `json:"project_id"`
+	Orphans     []uuid.UUID     `json:"orphans"`     // Steps without incoming edges, in flow order
+	Suggestions []SuggestedEdge `json:"suggestions"` // At most one per orphan
+}
+
+// SuggestEdges proposes an incoming edge for each orphan step of the saved workflow: a top-level
+// step other than a trigger or note that no edge targets, so it never runs. Suggestions are never
+// applied; the client creates the edges the user accepts.
+//
+// Steps are put in flow order (canvas position left to right, then top to bottom, then creation
+// order) and each orphan is connected from a step that precedes it, so accepting every suggestion
+// cannot create a cycle. Candidates are scored by:
+//   - proximity: how close the source is to the orphan, in flow order and on the canvas
+//   - data flow: how many of the orphan's template references and input_schema fields are
+//     properties of the source's output schema
+//   - block pattern: the source is a trigger or the end of a chain (no outgoing edge yet)
+func (u *ProjectUsecase) SuggestEdges(ctx context.Context, tenantID, projectID uuid.UUID) (*EdgeSuggestions, error) {
+	project, err := u.getProjectWithStepsEdgesFromDB(ctx, tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EdgeSuggestions{
+		ProjectID:   project.ID,
+		Orphans:     make([]uuid.UUID, 0),
+		Suggestions: make([]SuggestedEdge, 0),
+	}
+
+	// Top-level steps in flow order
+	steps := make([]domain.Step, 0, len(project.Steps))
+	for _, step := range project.Steps {
+		if step.BlockGroupID == nil && step.Type != domain.StepTypeNote {
+			steps = append(steps, step)
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return stepPrecedes(steps[i], steps[j]) })
+
+	incoming := make(map[uuid.UUID]bool)
+	outgoing := make(map[uuid.UUID]int)
+	successors := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range project.Edges {
+		source, target := edgeEndpoint(edge.SourceStepID, edge.SourceBlockGroupID), edgeEndpoint(edge.TargetStepID, edge.TargetBlockGroupID)
+		if target != nil {
+			incoming[*target] = true
+		}
+		if source != nil {
+			outgoing[*source]++
+			if target != nil {
+				successors[*source] = append(successors[*source], *target)
+			}
+		}
+	}
+
+	outputFields := make(map[uuid.UUID]map[string]bool, len(steps))
+	for _, step := range steps {
+		schema, err := u.stepOutputSchema(ctx, tenantID, step)
+		if err != nil {
+			return nil, err
+		}
+		outputFields[step.ID] = schemaProperties(schema)
+	}
+
+	for i, target := range steps {
+		if incoming[target.ID] || isTriggerStep(target) {
+			continue
+		}
+		result.Orphans = append(result.Orphans, target.ID)
+		inputs := stepInputFields(target)
+
+		var best *SuggestedEdge
+		for j := i - 1; j >= 0; j-- {
+			source := steps[j]
+			if !canBeEdgeSource(source) || reaches(successors, target.ID, source.ID) {
+				continue
+			}
+			candidate := scoreEdgeCandidate(source, target, i-j, outgoing[source.ID] == 0, outputFields[source.ID], inputs)
+			if best == nil || candidate.Score > best.Score {
+				best = &candidate
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		result.Suggestions = append(result.Suggestions, *best)
+		// Later orphans see the suggested edge, so a set of orphans is chained rather than fanned out
+		outgoing[best.SourceStepID]++
+		successors[best.SourceStepID] = append(successors[best.SourceStepID], best.TargetStepID)
+		incoming[best.TargetStepID] = true
+	}
+
+	return result, nil
+}
+
+// scoreEdgeCandidate scores the edge source -> target; gap is the distance between them in flow order
+func scoreEdgeCandidate(source, target domain.Step, gap int, sourceDangling bool, outputs, inputs map[string]bool) SuggestedEdge {
+	suggestion := SuggestedEdge{
+		SourceStepID:   source.ID,
+		SourceStepName: source.Name,
+		SourcePort:     defaultSourcePort(source.Type),
+		TargetStepID:   target.ID,
+		TargetStepName: target.Name,
+		Reasons:        make([]string, 0),
+	}
+
+	proximity := 1 / float64(gap)
+	if distance := math.Hypot(float64(target.PositionX-source.PositionX), float64(target.PositionY-source.PositionY)); distance > 0 {
+		proximity = (proximity + 1/(1+distance/edgeSuggestDistanceScale)) / 2
+	}
+	if gap == 1 {
+		suggestion.Reasons = append(suggestion.Reasons, "nearest preceding step")
+	}
+
+	var dataFlow float64
+	if len(inputs) > 0 && len(outputs) > 0 {
+		var matched []string
+		for field := range inputs {
+			if outputs[field] {
+				matched = append(matched, field)
+			}
+		}
+		if len(matched) > 0 {
+			sort.Strings(matched)
+			dataFlow = float64(len(matched)) / float64(len(inputs))
+			suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("output fields match inputs: %s", strings.Join(matched, ", ")))
+		}
+	}
+
+	var pattern float64
+	switch {
+	case isTriggerStep(source) && sourceDangling:
+		pattern = 1
+		suggestion.Reasons = append(suggestion.Reasons, "trigger has no outgoing edge")
+	case sourceDangling:
+		pattern = 0.5
+		suggestion.Reasons = append(suggestion.Reasons, "source has no outgoing edge")
+	}
+
+	score := edgeSuggestProximityWeight*proximity + edgeSuggestDataFlowWeight*dataFlow + edgeSuggestPatternWeight*pattern
+	suggestion.Score = math.Round(score*100) / 100
+	return suggestion
+}
+
+// stepPrecedes orders steps by canvas position (left to right, top to bottom), then creation time.
+// Steps created without a position all sit at the origin, so creation order decides for them.
+func stepPrecedes(a, b domain.Step) bool {
+	if a.PositionX != b.PositionX {
+		return a.PositionX < b.PositionX
+	}
+	if a.PositionY != b.PositionY {
+		return a.PositionY < b.PositionY
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+// isTriggerStep reports whether a step is a workflow entry point
+func isTriggerStep(step domain.Step) bool {
+	return step.Type == domain.StepTypeStart || domain.IsTriggerBlockSlug(string(step.Type))
+}
+
+// canBeEdgeSource reports whether a step can have outgoing edges
+func canBeEdgeSource(step domain.Step) bool {
+	return step.Type != domain.StepTypeError
+}
+
+// defaultSourcePort returns the output port a new edge from a step of the given type uses,
+// matching the copilot add_step tool
+func defaultSourcePort(stepType domain.StepType) string {
+	switch stepType {
+	case domain.StepTypeCondition:
+		return "true"
+	case domain.StepTypeSwitch:
+		return "default"
+	}
+	return "output"
+}
+
+// edgeEndpoint returns the step or block group at one end of an edge
+func edgeEndpoint(stepID, groupID *uuid.UUID) *uuid.UUID {
+	if stepID != nil {
+		return stepID
+	}
+	return groupID
+}
+
+// reaches reports whether to is reachable from from by following edges
+func reaches(successors map[uuid.UUID][]uuid.UUID, from, to uuid.UUID) bool {
+	visited := map[uuid.UUID]bool{from: true}
+	queue := []uuid.UUID{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			return true
+		}
+		for _, next := range successors[id] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// stepInputFields returns the input fields a step consumes: the first segment of its unprefixed
+// (or $input.) template references and the properties of its declared input_schema
+func stepInputFields(step domain.Step) map[string]bool {
+	fields := schemaProperties(declaredSchema("input_schema", step.Config))
+	paths, err := engine.TemplateReferences(step.Config)
+	if err != nil {
+		return fields
+	}
+	for _, path := range paths {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "$."), "$input.")
+		if strings.HasPrefix(path, "$") {
+			continue
+		}
+		if name, _, _ := strings.Cut(path, "."); name != "" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// schemaProperties returns the top-level property names of a JSON schema
+func schemaProperties(schema json.RawMessage) map[string]bool {
+	fields := make(map[string]bool)
+	if len(schema) == 0 {
+		return fields
+	}
+	var parsed struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if json.Unmarshal(schema, &parsed) != nil {
+		return fields
+	}
+	for name := range parsed.Properties {
+		fields[name] = true
+	}
+	return fields
+}
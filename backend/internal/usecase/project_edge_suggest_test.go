@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func (f *contractFixture) suggestEdges(t *testing.T) *EdgeSuggestions {
+	t.Helper()
+	uc := NewProjectUsecase(f.projectRepo, f.stepRepo, f.edgeRepo, nil, f.blockRepo)
+	suggestions, err := uc.SuggestEdges(context.Background(), f.tenantID, f.project.ID)
+	if err != nil {
+		t.Fatalf("SuggestEdges() error = %v", err)
+	}
+	return suggestions
+}
+
+func (f *contractFixture) addStepAt(name string, stepType domain.StepType, x, y int, config string) *domain.Step {
+	step := f.addStep(name, stepType, config)
+	step.SetPosition(x, y)
+	return step
+}
+
+func assertSuggestedEdges(t *testing.T, got []SuggestedEdge, want [][2]*domain.Step) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d suggestions %+v, want %d", len(got), got, len(want))
+	}
+	for i, pair := range want {
+		if got[i].SourceStepID != pair[0].ID || got[i].TargetStepID != pair[1].ID {
+			t.Errorf("suggestion %d = %s -> %s, want %s -> %s",
+				i, got[i].SourceStepName, got[i].TargetStepName, pair[0].Name, pair[1].Name)
+		}
+	}
+}
+
+func TestSuggestEdges_LinearOrphans(t *testing.T) {
+	f := newContractFixture()
+	start := f.addStepAt("Start", domain.StepTypeStart, 0, 0, "")
+	fetch := f.addStepAt("Fetch", domain.StepTypeTool, 250, 0,
+		`{"output_schema":{"type":"object","properties":{"body":{"type":"string"}}}}`)
+	summarize := f.addStepAt("Summarize", domain.StepTypeLLM, 500, 0, `{"prompt":"Summarize {{body}}"}`)
+	notify := f.addStepAt("Notify", domain.StepTypeTool, 750, 0, "")
+	f.addStepAt("Comment", domain.StepTypeNote, 600, 200, "")
+
+	result := f.suggestEdges(t)
+
+	if len(result.Orphans) != 3 {
+		t.Errorf("Orphans = %v, want Fetch, Summarize, Notify", result.Orphans)
+	}
+	assertSuggestedEdges(t, result.Suggestions, [][2]*domain.Step{
+		{start, fetch},
+		{fetch, summarize},
+		{summarize, notify},
+	})
+
+	dataFlow := result.Suggestions[1]
+	if dataFlow.SourcePort != "output" {
+		t.Errorf("SourcePort = %q, want output", dataFlow.SourcePort)
+	}
+	found := false
+	for _, reason := range dataFlow.Reasons {
+		if reason == "output fields match inputs: body" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Reasons = %v, want the matched body field", dataFlow.Reasons)
+	}
+	if dataFlow.Score <= result.Suggestions[2].Score {
+		t.Errorf("data-flow match score %v should exceed the plain chain score %v", dataFlow.Score, result.Suggestions[2].Score)
+	}
+}
+
+func TestSuggestEdges_KeepsExistingEdges(t *testing.T) {
+	f := newContractFixture()
+	start := f.addStepAt("Start", domain.StepTypeStart, 0, 0, "")
+	check := f.addStepAt("Check", domain.StepTypeCondition, 250, 0, "")
+	approve := f.addStepAt("Approve", domain.StepTypeTool, 500, 0, "")
+	f.connect(start, check)
+
+	result := f.suggestEdges(t)
+
+	assertSuggestedEdges(t, result.Suggestions, [][2]*domain.Step{{check, approve}})
+	if result.Suggestions[0].SourcePort != "true" {
+		t.Errorf("SourcePort = %q, want true for a condition source", result.Suggestions[0].SourcePort)
+	}
+}
+
+func TestSuggestEdges_UnpositionedStepsUseCreationOrder(t *testing.T) {
+	f := newContractFixture()
+	start := f.addStep("Start", domain.StepTypeStart, "")
+	first := f.addStep("First", domain.StepTypeTool, "")
+	second := f.addStep("Second", domain.StepTypeTool, "")
+	base := start.CreatedAt
+	first.CreatedAt = base.Add(1)
+	second.CreatedAt = base.Add(2)
+
+	result := f.suggestEdges(t)
+
+	assertSuggestedEdges(t, result.Suggestions, [][2]*domain.Step{{start, first}, {first, second}})
+}
+
+func TestSuggestEdges_NoOrphans(t *testing.T) {
+	f := newContractFixture()
+	start := f.addStepAt("Start", domain.StepTypeStart, 0, 0, "")
+	done := f.addStepAt("Done", domain.StepTypeTool, 250, 0, "")
+	f.connect(start, done)
+
+	result := f.suggestEdges(t)
+
+	if len(result.Orphans) != 0 || len(result.Suggestions) != 0 {
+		t.Errorf("got orphans %v, suggestions %+v; want none", result.Orphans, result.Suggestions)
+	}
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/engine"
+)
+
+// ValidationLocation points a validation check at the step or variable it concerns
+type ValidationLocation struct {
+	StepID   *uuid.UUID `json:"step_id,omitempty"`
+	StepName string     `json:"step_name,omitempty"`
+	Variable string     `json:"variable,omitempty"`
+	Path     string     `json:"path,omitempty"` // Template path, e.g. $project.api_url
+}
+
+// projectVariablePrefix is the template prefix of project variables
+const projectVariablePrefix = "$project."
+
+// lintProject returns the hygiene checks of a project. They never block publishing: a failing
+// check has the "warning" status and lists where the problem is.
+func lintProject(project *domain.Project) []ValidationCheck {
+	refs := stepTemplateReferences(project)
+	variables := projectVariableNames(project)
+	return []ValidationCheck{
+		lintUnusedVariables(variables, refs),
+		lintUndefinedTemplateFields(variables, refs),
+		lintUnreachableSteps(project),
+	}
+}
+
+// stepTemplateRef is a template path referenced by the config of a step
+type stepTemplateRef struct {
+	step *domain.Step
+	path string
+}
+
+// stepTemplateReferences returns the template paths of every step config and trigger config,
+// followed by those of block group configs (without a step)
+func stepTemplateReferences(project *domain.Project) []stepTemplateRef {
+	var refs []stepTemplateRef
+	for i := range project.Steps {
+		step := &project.Steps[i]
+		for _, config := range []json.RawMessage{step.Config, step.TriggerConfig} {
+			paths, err := engine.TemplateReferences(config)
+			if err != nil {
+				continue
+			}
+			for _, path := range paths {
+				refs = append(refs, stepTemplateRef{step: step, path: path})
+			}
+		}
+	}
+	for _, group := range project.BlockGroups {
+		paths, err := engine.TemplateReferences(group.Config)
+		if err != nil {
+			continue
+		}
+		for _, path := range paths {
+			refs = append(refs, stepTemplateRef{path: path})
+		}
+	}
+	return refs
+}
+
+// projectVariableNames returns the top-level project variable names
+func projectVariableNames(project *domain.Project) map[string]bool {
+	names := make(map[string]bool)
+	if len(project.Variables) == 0 {
+		return names
+	}
+	var variables map[string]interface{}
+	if err := json.Unmarshal(project.Variables, &variables); err != nil {
+		return names
+	}
+	for name := range variables {
+		names[name] = true
+	}
+	return names
+}
+
+// referencedVariable returns the project variable a template path can resolve to: the first
+// segment after $project., or of an unprefixed path, which falls back to project variables
+func referencedVariable(path string) string {
+	path = strings.TrimPrefix(path, "$.")
+	if strings.HasPrefix(path, projectVariablePrefix) {
+		path = strings.TrimPrefix(path, projectVariablePrefix)
+	} else if strings.HasPrefix(path, "$") {
+		return ""
+	}
+	name, _, _ := strings.Cut(path, ".")
+	return name
+}
+
+// lintUnusedVariables reports project variables no template references
+func lintUnusedVariables(variables map[string]bool, refs []stepTemplateRef) ValidationCheck {
+	check := ValidationCheck{
+		ID:     "noUnusedVariables",
+		Label:  "All project variables are used",
+		Status: "passed",
+	}
+	used := make(map[string]bool)
+	for _, ref := range refs {
+		used[referencedVariable(ref.path)] = true
+	}
+	var unused []string
+	for name := range variables {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		check.Status = "warning"
+		check.Message = fmt.Sprintf("%d unused variable(s)", len(unused))
+		for _, name := range unused {
+			check.Locations = append(check.Locations, ValidationLocation{Variable: name})
+		}
+	}
+	return check
+}
+
+// lintUndefinedTemplateFields reports {{$project.x}} references to undefined project variables.
+// Unprefixed paths are not reported, since they usually refer to step input.
+func lintUndefinedTemplateFields(variables map[string]bool, refs []stepTemplateRef) ValidationCheck {
+	check := ValidationCheck{
+		ID:     "noUndefinedTemplateFields",
+		Label:  "Template fields refer to defined variables",
+		Status: "passed",
+	}
+	for _, ref := range refs {
+		if !strings.HasPrefix(strings.TrimPrefix(ref.path, "$."), projectVariablePrefix) {
+			continue
+		}
+		name := referencedVariable(ref.path)
+		if variables[name] {
+			continue
+		}
+		location := ValidationLocation{Variable: name, Path: ref.path}
+		if ref.step != nil {
+			stepID := ref.step.ID
+			location.StepID = &stepID
+			location.StepName = ref.step.Name
+		}
+		check.Locations = append(check.Locations, location)
+	}
+	if len(check.Locations) > 0 {
+		check.Status = "warning"
+		check.Message = fmt.Sprintf("%d reference(s) to undefined template fields", len(check.Locations))
+	}
+	return check
+}
+
+// lintUnreachableSteps reports steps no trigger reaches by following edges
+func lintUnreachableSteps(project *domain.Project) ValidationCheck {
+	check := ValidationCheck{
+		ID:     "allStepsReachable",
+		Label:  "All steps are reachable from a trigger",
+		Status: "passed",
+	}
+	var roots []uuid.UUID
+	for _, step := range project.Steps {
+		if step.Type == domain.StepTypeStart || domain.IsTriggerBlockSlug(string(step.Type)) {
+			roots = append(roots, step.ID)
+		}
+	}
+	if len(roots) == 0 {
+		// Reported by the start block check
+		return check
+	}
+
+	graph := engine.BuildGraph(&domain.ProjectDefinition{
+		Steps:       project.Steps,
+		Edges:       project.Edges,
+		BlockGroups: project.BlockGroups,
+	})
+	reachable := graph.ReachableSteps(roots)
+	for _, step := range project.Steps {
+		if reachable[step.ID] {
+			continue
+		}
+		stepID := step.ID
+		check.Locations = append(check.Locations, ValidationLocation{StepID: &stepID, StepName: step.Name})
+	}
+	if len(check.Locations) > 0 {
+		check.Status = "warning"
+		check.Message = fmt.Sprintf("%d step(s) unreachable from any trigger", len(check.Locations))
+	}
+	return check
+}
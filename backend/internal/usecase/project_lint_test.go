@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func findCheck(t *testing.T, result *ValidationResult, id string) ValidationCheck {
+	t.Helper()
+	for _, check := range result.Checks {
+		if check.ID == id {
+			return check
+		}
+	}
+	t.Fatalf("check %q not found in %+v", id, result.Checks)
+	return ValidationCheck{}
+}
+
+func TestValidateForPublish_LintWarnings(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	uc := NewProjectUsecase(projectRepo, newMockStepRepo(), newMockEdgeRepo(), nil, nil)
+
+	project := domain.NewProject(tenantID, "lint", "")
+	project.Variables = json.RawMessage(`{"api_url": "https://api.example.com", "unused_token": "x"}`)
+	startID, fetchID, orphanID := uuid.New(), uuid.New(), uuid.New()
+	project.Steps = []domain.Step{
+		{ID: startID, Name: "start", Type: domain.StepTypeStart},
+		{ID: fetchID, Name: "fetch", Type: domain.StepTypeTool,
+			Config: json.RawMessage(`{"url": "{{$project.api_url}}/users/{{user_id}}", "token": "{{$project.missing_key}}"}`)},
+		{ID: orphanID, Name: "orphan", Type: domain.StepTypeFunction,
+			Config: json.RawMessage(`{"code": "return {}"}`)},
+	}
+	project.Edges = []domain.Edge{
+		{ID: uuid.New(), SourceStepID: &startID, TargetStepID: &fetchID},
+		// The orphan only has an outgoing edge, so it is connected but never reached
+		{ID: uuid.New(), SourceStepID: &orphanID, TargetStepID: &fetchID},
+	}
+	projectRepo.projects[project.ID] = project
+
+	result, err := uc.ValidateForPublish(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("ValidateForPublish() error = %v", err)
+	}
+	if !result.CanPublish {
+		t.Errorf("lint warnings must not block publishing: %+v", result.Checks)
+	}
+
+	unused := findCheck(t, result, "noUnusedVariables")
+	if unused.Status != "warning" || len(unused.Locations) != 1 || unused.Locations[0].Variable != "unused_token" {
+		t.Errorf("noUnusedVariables = %+v, want a warning for unused_token", unused)
+	}
+
+	undefined := findCheck(t, result, "noUndefinedTemplateFields")
+	if undefined.Status != "warning" || len(undefined.Locations) != 1 {
+		t.Fatalf("noUndefinedTemplateFields = %+v, want one warning", undefined)
+	}
+	if loc := undefined.Locations[0]; loc.Variable != "missing_key" || loc.StepName != "fetch" || loc.StepID == nil || *loc.StepID != fetchID {
+		t.Errorf("undefined field location = %+v", loc)
+	}
+
+	unreachable := findCheck(t, result, "allStepsReachable")
+	if unreachable.Status != "warning" || len(unreachable.Locations) != 1 {
+		t.Fatalf("allStepsReachable = %+v, want one warning", unreachable)
+	}
+	if loc := unreachable.Locations[0]; loc.StepName != "orphan" || loc.StepID == nil || *loc.StepID != orphanID {
+		t.Errorf("unreachable step location = %+v", loc)
+	}
+}
+
+func TestValidateForPublish_LintPassesCleanProject(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	uc := NewProjectUsecase(projectRepo, newMockStepRepo(), newMockEdgeRepo(), nil, nil)
+
+	project := domain.NewProject(tenantID, "clean", "")
+	// An unprefixed reference also uses a project variable, since it falls back to project scope
+	project.Variables = json.RawMessage(`{"greeting": "hello"}`)
+	startID, groupID, innerID := uuid.New(), uuid.New(), uuid.New()
+	project.Steps = []domain.Step{
+		{ID: startID, Name: "start", Type: domain.StepTypeStart},
+		{ID: innerID, Name: "inner", Type: domain.StepTypeFunction, BlockGroupID: &groupID,
+			Config: json.RawMessage(`{"message": "{{greeting}}"}`)},
+	}
+	project.Edges = []domain.Edge{
+		{ID: uuid.New(), SourceStepID: &startID, TargetBlockGroupID: &groupID},
+	}
+	projectRepo.projects[project.ID] = project
+
+	result, err := uc.ValidateForPublish(context.Background(), tenantID, project.ID)
+	if err != nil {
+		t.Fatalf("ValidateForPublish() error = %v", err)
+	}
+	for _, id := range []string{"noUnusedVariables", "noUndefinedTemplateFields", "allStepsReachable"} {
+		if check := findCheck(t, result, id); check.Status != "passed" {
+			t.Errorf("%s = %+v, want passed", id, check)
+		}
+	}
+}
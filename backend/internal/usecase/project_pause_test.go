@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// singleScheduleRepo serves one schedule, which is due while its next run has passed
+type singleScheduleRepo struct {
+	repository.ScheduleRepository
+	schedule *domain.Schedule
+}
+
+func (r *singleScheduleRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Schedule, error) {
+	if r.schedule.ID != id || r.schedule.TenantID != tenantID {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return r.schedule, nil
+}
+
+func (r *singleScheduleRepo) Update(ctx context.Context, schedule *domain.Schedule) error {
+	r.schedule = schedule
+	return nil
+}
+
+func (r *singleScheduleRepo) GetDueSchedules(ctx context.Context, limit int) ([]*domain.Schedule, error) {
+	if r.schedule.NextRunAt == nil || r.schedule.NextRunAt.After(time.Now()) {
+		return nil, nil
+	}
+	return []*domain.Schedule{r.schedule}, nil
+}
+
+func TestProjectPause_BlocksAllTriggers(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(tenantID, "Nightly Sync", "")
+	project.Status = domain.ProjectStatusPublished
+	projectRepo.projects[project.ID] = project
+
+	runRepo := newMockRunRepo()
+	// The queue is unreachable: runs are stored and then fail to enqueue
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:63790"})
+	defer redisClient.Close()
+	runUsecase := NewRunUsecase(projectRepo, runRepo, nil, nil, nil, nil, redisClient)
+
+	past := time.Now().Add(-time.Minute)
+	schedule := domain.NewSchedule(tenantID, project.ID, uuid.New(), project.Version, "Nightly", "0 3 * * *", "UTC", nil)
+	schedule.NextRunAt = &past
+	scheduleRepo := &singleScheduleRepo{schedule: schedule}
+	scheduleUsecase := NewScheduleUsecase(scheduleRepo, projectRepo, runRepo)
+	projectUsecase := NewProjectUsecase(projectRepo, nil, nil, nil, nil)
+
+	startRun := func(triggeredBy domain.TriggerType) error {
+		startStepID := uuid.New()
+		_, err := runUsecase.Create(ctx, CreateRunInput{
+			TenantID:    tenantID,
+			ProjectID:   project.ID,
+			TriggeredBy: triggeredBy,
+			StartStepID: &startStepID,
+		})
+		return err
+	}
+
+	paused, err := projectUsecase.SetPaused(ctx, tenantID, project.ID, true)
+	if err != nil {
+		t.Fatalf("SetPaused(true) error = %v", err)
+	}
+	if !paused.Paused {
+		t.Fatalf("SetPaused(true) returned an active project")
+	}
+
+	for _, triggeredBy := range []domain.TriggerType{domain.TriggerTypeManual, domain.TriggerTypeWebhook} {
+		if err := startRun(triggeredBy); !errors.Is(err, domain.ErrProjectPaused) {
+			t.Errorf("Create(%s) error = %v, want ErrProjectPaused", triggeredBy, err)
+		}
+	}
+	if _, err := scheduleUsecase.Trigger(ctx, tenantID, schedule.ID); !errors.Is(err, domain.ErrProjectPaused) {
+		t.Errorf("Trigger() error = %v, want ErrProjectPaused", err)
+	}
+	processed, err := scheduleUsecase.ProcessDueSchedules(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessDueSchedules() error = %v", err)
+	}
+	if processed != 0 {
+		t.Errorf("ProcessDueSchedules() processed %d, want the paused project skipped", processed)
+	}
+	if len(runRepo.runs) != 0 {
+		t.Fatalf("%d runs created while the project is paused, want none", len(runRepo.runs))
+	}
+	if schedule.NextRunAt == nil || !schedule.NextRunAt.After(time.Now()) {
+		t.Errorf("skipped schedule next run = %v, want the next occurrence", schedule.NextRunAt)
+	}
+
+	if _, err := projectUsecase.SetPaused(ctx, tenantID, project.ID, false); err != nil {
+		t.Fatalf("SetPaused(false) error = %v", err)
+	}
+
+	for _, triggeredBy := range []domain.TriggerType{domain.TriggerTypeManual, domain.TriggerTypeWebhook} {
+		if err := startRun(triggeredBy); errors.Is(err, domain.ErrProjectPaused) {
+			t.Errorf("Create(%s) after resume error = %v, want the run started", triggeredBy, err)
+		}
+	}
+	if _, err := scheduleUsecase.Trigger(ctx, tenantID, schedule.ID); err != nil {
+		t.Errorf("Trigger() after resume error = %v", err)
+	}
+	schedule.NextRunAt = &past
+	if processed, _ := scheduleUsecase.ProcessDueSchedules(ctx, 10); processed != 1 {
+		t.Errorf("ProcessDueSchedules() after resume processed %d, want 1", processed)
+	}
+	if len(runRepo.runs) != 4 {
+		t.Errorf("%d runs created after resume, want 4 (manual, webhook, schedule trigger, due schedule)", len(runRepo.runs))
+	}
+}
+
+func TestProjectUsecase_SetPaused_SystemProject(t *testing.T) {
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(uuid.New(), "Copilot", "")
+	project.IsSystem = true
+	projectRepo.projects[project.ID] = project
+
+	_, err := NewProjectUsecase(projectRepo, nil, nil, nil, nil).SetPaused(context.Background(), project.TenantID, project.ID, true)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("SetPaused() error = %v, want ErrForbidden", err)
+	}
+	if projectRepo.projects[project.ID].Paused {
+		t.Errorf("system project was paused")
+	}
+}
+
+func TestProjectPause_BlocksRunExecution(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(tenantID, "Nightly Sync", "")
+	project.Status = domain.ProjectStatusPublished
+	project.Paused = true
+	projectRepo.projects[project.ID] = project
+
+	runRepo := newMockRunRepo()
+	run := domain.NewRun(tenantID, project.ID, 1, nil, domain.TriggerTypeManual)
+	run.Status = domain.RunStatusCompleted
+	runRepo.runs[run.ID] = run
+	// Nothing past the pause check is reachable: no version, step or step run repositories
+	runUsecase := NewRunUsecase(projectRepo, runRepo, nil, nil, nil, nil, nil)
+	stepID := uuid.New()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"ExecuteSingleStep", func() error {
+			_, err := runUsecase.ExecuteSingleStep(ctx, ExecuteSingleStepInput{TenantID: tenantID, RunID: run.ID, StepID: stepID})
+			return err
+		}},
+		{"ResumeFromStep", func() error {
+			_, err := runUsecase.ResumeFromStep(ctx, ResumeFromStepInput{TenantID: tenantID, RunID: run.ID, FromStepID: stepID})
+			return err
+		}},
+		{"TestStepInline", func() error {
+			_, err := runUsecase.TestStepInline(ctx, TestStepInlineInput{TenantID: tenantID, ProjectID: project.ID, StepID: stepID})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, domain.ErrProjectPaused) {
+				t.Errorf("%s() error = %v, want ErrProjectPaused", tt.name, err)
+			}
+		})
+	}
+	if len(runRepo.runs) != 1 {
+		t.Errorf("%d runs stored, want no run created while the project is paused", len(runRepo.runs))
+	}
+}
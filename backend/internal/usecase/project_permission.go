@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// ProjectPermissionUsecase manages and enforces per-user workflow permissions. A workflow without
+// permissions is open to every user of the tenant, as before permissions existed; granting the
+// first permission restricts it to the listed users. Tenant admins always have full access.
+type ProjectPermissionUsecase struct {
+	permissionRepo repository.ProjectPermissionRepository
+	projectRepo    repository.ProjectRepository
+	runRepo        repository.RunRepository      // Optional; resolves the workflow of a run for CheckRun
+	scheduleRepo   repository.ScheduleRepository // Optional; resolves the workflow of a schedule for CheckSchedule
+}
+
+// NewProjectPermissionUsecase creates a new ProjectPermissionUsecase
+func NewProjectPermissionUsecase(permissionRepo repository.ProjectPermissionRepository, projectRepo repository.ProjectRepository) *ProjectPermissionUsecase {
+	return &ProjectPermissionUsecase{
+		permissionRepo: permissionRepo,
+		projectRepo:    projectRepo,
+	}
+}
+
+// WithRunRepository lets CheckRun resolve the workflow a run belongs to
+func (u *ProjectPermissionUsecase) WithRunRepository(runRepo repository.RunRepository) *ProjectPermissionUsecase {
+	u.runRepo = runRepo
+	return u
+}
+
+// WithScheduleRepository lets CheckSchedule resolve the workflow a schedule belongs to
+func (u *ProjectPermissionUsecase) WithScheduleRepository(scheduleRepo repository.ScheduleRepository) *ProjectPermissionUsecase {
+	u.scheduleRepo = scheduleRepo
+	return u
+}
+
+// WorkflowActor identifies the user accessing a workflow
+type WorkflowActor struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+	IsAdmin  bool
+}
+
+// Check returns ErrProjectAccessDenied unless the actor has at least the required role on the
+// workflow
+func (u *ProjectPermissionUsecase) Check(ctx context.Context, actor WorkflowActor, projectID uuid.UUID, required domain.WorkflowRole) error {
+	if actor.IsAdmin {
+		return nil
+	}
+	permissions, err := u.permissionRepo.ListByProject(ctx, actor.TenantID, projectID)
+	if err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	for _, permission := range permissions {
+		if permission.UserID == actor.UserID && permission.Role.Allows(required) {
+			return nil
+		}
+	}
+	return domain.ErrProjectAccessDenied
+}
+
+// CheckRun is Check on the workflow of a run. A run of another tenant is reported as not found.
+func (u *ProjectPermissionUsecase) CheckRun(ctx context.Context, actor WorkflowActor, runID uuid.UUID, required domain.WorkflowRole) error {
+	run, err := u.runRepo.GetByID(ctx, actor.TenantID, runID)
+	if err != nil {
+		return err
+	}
+	return u.Check(ctx, actor, run.ProjectID, required)
+}
+
+// CheckSchedule is Check on the workflow of a schedule. A schedule of another tenant is reported
+// as not found.
+func (u *ProjectPermissionUsecase) CheckSchedule(ctx context.Context, actor WorkflowActor, scheduleID uuid.UUID, required domain.WorkflowRole) error {
+	schedule, err := u.scheduleRepo.GetByID(ctx, actor.TenantID, scheduleID)
+	if err != nil {
+		return err
+	}
+	return u.Check(ctx, actor, schedule.ProjectID, required)
+}
+
+// Viewable returns which of the workflows the actor may view, for filtering lists that span
+// workflows. Each workflow's permissions are loaded once.
+func (u *ProjectPermissionUsecase) Viewable(ctx context.Context, actor WorkflowActor, projectIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	viewable := make(map[uuid.UUID]bool, len(projectIDs))
+	for _, projectID := range projectIDs {
+		if _, checked := viewable[projectID]; checked {
+			continue
+		}
+		err := u.Check(ctx, actor, projectID, domain.WorkflowRoleViewer)
+		if err != nil && !errors.Is(err, domain.ErrProjectAccessDenied) {
+			return nil, err
+		}
+		viewable[projectID] = err == nil
+	}
+	return viewable, nil
+}
+
+// List returns the permissions of a workflow. An empty list means the workflow is open to the
+// whole tenant.
+func (u *ProjectPermissionUsecase) List(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.ProjectPermission, error) {
+	if _, err := u.projectRepo.GetByID(ctx, tenantID, projectID); err != nil {
+		return nil, err
+	}
+	return u.permissionRepo.ListByProject(ctx, tenantID, projectID)
+}
+
+// GrantProjectPermissionInput represents input for granting a workflow permission
+type GrantProjectPermissionInput struct {
+	Actor     WorkflowActor
+	ProjectID uuid.UUID
+	UserID    uuid.UUID
+	Role      domain.WorkflowRole
+}
+
+// Grant gives a user a role on a workflow, replacing their current role. Granting the first
+// permission of an open workflow also makes the granting user an owner, so restricting a workflow
+// never locks out the user who restricted it.
+func (u *ProjectPermissionUsecase) Grant(ctx context.Context, input GrantProjectPermissionInput) (*domain.ProjectPermission, error) {
+	if !input.Role.IsValid() {
+		return nil, domain.NewValidationError("role", "role must be one of owner, editor, runner, viewer")
+	}
+	if input.UserID == uuid.Nil {
+		return nil, domain.NewValidationError("user_id", "user_id is required")
+	}
+	if _, err := u.projectRepo.GetByID(ctx, input.Actor.TenantID, input.ProjectID); err != nil {
+		return nil, err
+	}
+	permissions, err := u.permissionRepo.ListByProject(ctx, input.Actor.TenantID, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Role != domain.WorkflowRoleOwner && isLastOwner(permissions, input.UserID) {
+		return nil, domain.NewValidationError("role", "the last owner of a workflow cannot be demoted")
+	}
+
+	var grantedBy *uuid.UUID
+	if input.Actor.UserID != uuid.Nil {
+		grantedBy = &input.Actor.UserID
+		if len(permissions) == 0 && input.Actor.UserID != input.UserID {
+			owner := domain.NewProjectPermission(input.Actor.TenantID, input.ProjectID, input.Actor.UserID, domain.WorkflowRoleOwner, grantedBy)
+			if err := u.permissionRepo.Upsert(ctx, owner); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	permission := domain.NewProjectPermission(input.Actor.TenantID, input.ProjectID, input.UserID, input.Role, grantedBy)
+	if err := u.permissionRepo.Upsert(ctx, permission); err != nil {
+		return nil, err
+	}
+	return permission, nil
+}
+
+// Revoke removes a user's permission on a workflow. The last owner can only be removed together
+// with everyone else, which opens the workflow to the whole tenant again.
+func (u *ProjectPermissionUsecase) Revoke(ctx context.Context, tenantID, projectID, userID uuid.UUID) error {
+	permissions, err := u.permissionRepo.ListByProject(ctx, tenantID, projectID)
+	if err != nil {
+		return err
+	}
+	if len(permissions) > 1 && isLastOwner(permissions, userID) {
+		return domain.NewValidationError("user_id", "the last owner of a workflow cannot be removed while other users have permissions")
+	}
+	return u.permissionRepo.Delete(ctx, tenantID, projectID, userID)
+}
+
+// isLastOwner reports whether userID is the only owner among the permissions
+func isLastOwner(permissions []*domain.ProjectPermission, userID uuid.UUID) bool {
+	owners, isOwner := 0, false
+	for _, permission := range permissions {
+		if permission.Role == domain.WorkflowRoleOwner {
+			owners++
+			isOwner = isOwner || permission.UserID == userID
+		}
+	}
+	return isOwner && owners == 1
+}
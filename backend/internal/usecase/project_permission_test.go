@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+type mockProjectPermissionRepo struct {
+	permissions map[uuid.UUID]map[uuid.UUID]*domain.ProjectPermission
+}
+
+func newMockProjectPermissionRepo() *mockProjectPermissionRepo {
+	return &mockProjectPermissionRepo{permissions: make(map[uuid.UUID]map[uuid.UUID]*domain.ProjectPermission)}
+}
+
+func (m *mockProjectPermissionRepo) Upsert(ctx context.Context, permission *domain.ProjectPermission) error {
+	if m.permissions[permission.ProjectID] == nil {
+		m.permissions[permission.ProjectID] = make(map[uuid.UUID]*domain.ProjectPermission)
+	}
+	m.permissions[permission.ProjectID][permission.UserID] = permission
+	return nil
+}
+
+func (m *mockProjectPermissionRepo) Delete(ctx context.Context, tenantID, projectID, userID uuid.UUID) error {
+	if _, ok := m.permissions[projectID][userID]; !ok {
+		return domain.ErrProjectPermissionNotFound
+	}
+	delete(m.permissions[projectID], userID)
+	return nil
+}
+
+func (m *mockProjectPermissionRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.ProjectPermission, error) {
+	result := make([]*domain.ProjectPermission, 0)
+	for _, permission := range m.permissions[projectID] {
+		if permission.TenantID == tenantID {
+			result = append(result, permission)
+		}
+	}
+	return result, nil
+}
+
+func newProjectPermissionTest(t *testing.T) (*ProjectPermissionUsecase, *domain.Project) {
+	t.Helper()
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(uuid.New(), "restricted", "")
+	projectRepo.projects[project.ID] = project
+	return NewProjectPermissionUsecase(newMockProjectPermissionRepo(), projectRepo), project
+}
+
+func TestProjectPermissionUsecase_Check(t *testing.T) {
+	uc, project := newProjectPermissionTest(t)
+	ctx := context.Background()
+	owner, viewer, runner, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	actor := func(userID uuid.UUID) WorkflowActor {
+		return WorkflowActor{TenantID: project.TenantID, UserID: userID}
+	}
+
+	// Without permissions the workflow is open to the whole tenant
+	if err := uc.Check(ctx, actor(outsider), project.ID, domain.WorkflowRoleEditor); err != nil {
+		t.Fatalf("Check() on an open workflow error = %v, want nil", err)
+	}
+
+	for userID, role := range map[uuid.UUID]domain.WorkflowRole{viewer: domain.WorkflowRoleViewer, runner: domain.WorkflowRoleRunner} {
+		if _, err := uc.Grant(ctx, GrantProjectPermissionInput{Actor: actor(owner), ProjectID: project.ID, UserID: userID, Role: role}); err != nil {
+			t.Fatalf("Grant(%s) error = %v", role, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		actor    WorkflowActor
+		required domain.WorkflowRole
+		allowed  bool
+	}{
+		{"viewer can read", actor(viewer), domain.WorkflowRoleViewer, true},
+		{"viewer cannot run", actor(viewer), domain.WorkflowRoleRunner, false},
+		{"viewer cannot edit", actor(viewer), domain.WorkflowRoleEditor, false},
+		{"runner can trigger", actor(runner), domain.WorkflowRoleRunner, true},
+		{"runner cannot edit", actor(runner), domain.WorkflowRoleEditor, false},
+		{"granting user became owner", actor(owner), domain.WorkflowRoleOwner, true},
+		{"unlisted user is denied", actor(outsider), domain.WorkflowRoleViewer, false},
+		{"tenant admin bypasses", WorkflowActor{TenantID: project.TenantID, UserID: outsider, IsAdmin: true}, domain.WorkflowRoleOwner, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.Check(ctx, tt.actor, project.ID, tt.required)
+			if tt.allowed && err != nil {
+				t.Errorf("Check() error = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, domain.ErrProjectAccessDenied) {
+				t.Errorf("Check() error = %v, want ErrProjectAccessDenied", err)
+			}
+		})
+	}
+}
+
+func TestProjectPermissionUsecase_LastOwner(t *testing.T) {
+	uc, project := newProjectPermissionTest(t)
+	ctx := context.Background()
+	owner, editor := uuid.New(), uuid.New()
+	actor := WorkflowActor{TenantID: project.TenantID, UserID: owner}
+
+	if _, err := uc.Grant(ctx, GrantProjectPermissionInput{Actor: actor, ProjectID: project.ID, UserID: editor, Role: domain.WorkflowRoleEditor}); err != nil {
+		t.Fatalf("Grant() error = %v", err)
+	}
+
+	var validationErr domain.ValidationError
+	_, err := uc.Grant(ctx, GrantProjectPermissionInput{Actor: actor, ProjectID: project.ID, UserID: owner, Role: domain.WorkflowRoleViewer})
+	if !errors.As(err, &validationErr) {
+		t.Errorf("demoting the last owner error = %v, want a validation error", err)
+	}
+	if err := uc.Revoke(ctx, project.TenantID, project.ID, owner); !errors.As(err, &validationErr) {
+		t.Errorf("removing the last owner error = %v, want a validation error", err)
+	}
+
+	// Removing everyone opens the workflow to the tenant again
+	if err := uc.Revoke(ctx, project.TenantID, project.ID, editor); err != nil {
+		t.Fatalf("Revoke(editor) error = %v", err)
+	}
+	if err := uc.Revoke(ctx, project.TenantID, project.ID, owner); err != nil {
+		t.Fatalf("Revoke(owner) error = %v", err)
+	}
+	if err := uc.Check(ctx, WorkflowActor{TenantID: project.TenantID, UserID: uuid.New()}, project.ID, domain.WorkflowRoleEditor); err != nil {
+		t.Errorf("Check() after removing all permissions error = %v, want nil", err)
+	}
+}
+
+func TestProjectPermissionUsecase_Viewable(t *testing.T) {
+	uc, restricted := newProjectPermissionTest(t)
+	ctx := context.Background()
+	owner, outsider := uuid.New(), uuid.New()
+	open := uuid.New()
+
+	if _, err := uc.Grant(ctx, GrantProjectPermissionInput{Actor: WorkflowActor{TenantID: restricted.TenantID, UserID: owner}, ProjectID: restricted.ID, UserID: uuid.New(), Role: domain.WorkflowRoleViewer}); err != nil {
+		t.Fatalf("Grant() error = %v", err)
+	}
+
+	viewable, err := uc.Viewable(ctx, WorkflowActor{TenantID: restricted.TenantID, UserID: outsider}, []uuid.UUID{restricted.ID, open, restricted.ID})
+	if err != nil {
+		t.Fatalf("Viewable() error = %v", err)
+	}
+	if viewable[restricted.ID] || !viewable[open] {
+		t.Errorf("Viewable() = %v, want only the open workflow", viewable)
+	}
+}
@@ -0,0 +1,357 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// ============================================================================
+// Mock Repositories for Project Tests
+// ============================================================================
+
+type mockProjectRepo struct {
+	projects map[uuid.UUID]*domain.Project
+}
+
+func newMockProjectRepo() *mockProjectRepo {
+	return &mockProjectRepo{projects: make(map[uuid.UUID]*domain.Project)}
+}
+
+func (m *mockProjectRepo) Create(ctx context.Context, p *domain.Project) error {
+	m.projects[p.ID] = p
+	return nil
+}
+
+func (m *mockProjectRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error) {
+	p, ok := m.projects[id]
+	if !ok || (p.TenantID != tenantID && !p.IsSystem) {
+		return nil, domain.ErrProjectNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (m *mockProjectRepo) List(ctx context.Context, tenantID uuid.UUID, filter repository.ProjectFilter) ([]*domain.Project, int, error) {
+	var result []*domain.Project
+	for _, p := range m.projects {
+		if p.TenantID == tenantID && p.HasTags(filter.Tags) && matchesIDs(p.ID, filter.IDs) {
+			result = append(result, p)
+		}
+	}
+	return result, len(result), nil
+}
+
+// matchesIDs mirrors the repository IDs filter: nil means no restriction
+func matchesIDs(id uuid.UUID, ids []uuid.UUID) bool {
+	if ids == nil {
+		return true
+	}
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockProjectRepo) ListTags(ctx context.Context, tenantID uuid.UUID) ([]domain.ProjectTagCount, error) {
+	counts := make(map[string]int)
+	for _, p := range m.projects {
+		if p.TenantID == tenantID {
+			for _, tag := range p.Tags {
+				counts[tag]++
+			}
+		}
+	}
+	result := make([]domain.ProjectTagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, domain.ProjectTagCount{Tag: tag, Count: count})
+	}
+	return result, nil
+}
+
+func (m *mockProjectRepo) Update(ctx context.Context, p *domain.Project) error {
+	if _, ok := m.projects[p.ID]; !ok {
+		return domain.ErrProjectNotFound
+	}
+	m.projects[p.ID] = p
+	return nil
+}
+
+func (m *mockProjectRepo) UpdateIfUnmodified(ctx context.Context, p *domain.Project, unmodifiedSince time.Time) error {
+	existing, ok := m.projects[p.ID]
+	if !ok {
+		return domain.ErrProjectNotFound
+	}
+	if !existing.UpdatedAt.Equal(unmodifiedSince) {
+		return domain.ErrConcurrentModification
+	}
+	p.UpdatedAt = time.Now().UTC()
+	m.projects[p.ID] = p
+	return nil
+}
+
+func (m *mockProjectRepo) SetPaused(ctx context.Context, tenantID, id uuid.UUID, paused bool) error {
+	if _, err := m.GetByID(ctx, tenantID, id); err != nil {
+		return err
+	}
+	m.projects[id].Paused = paused
+	return nil
+}
+
+func (m *mockProjectRepo) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	delete(m.projects, id)
+	return nil
+}
+
+func (m *mockProjectRepo) GetWithStepsAndEdges(ctx context.Context, tenantID, id uuid.UUID) (*domain.Project, error) {
+	return m.GetByID(ctx, tenantID, id)
+}
+
+func (m *mockProjectRepo) GetSystemBySlug(ctx context.Context, slug string) (*domain.Project, error) {
+	return nil, domain.ErrProjectNotFound
+}
+
+type mockStepRepo struct {
+	steps    map[uuid.UUID]*domain.Step
+	edgeRepo *mockEdgeRepo // Optional: edges removed by DeleteBatch
+}
+
+func newMockStepRepo() *mockStepRepo {
+	return &mockStepRepo{steps: make(map[uuid.UUID]*domain.Step)}
+}
+
+func (m *mockStepRepo) Create(ctx context.Context, step *domain.Step) error {
+	m.steps[step.ID] = step
+	return nil
+}
+
+func (m *mockStepRepo) GetByID(ctx context.Context, tenantID, projectID, id uuid.UUID) (*domain.Step, error) {
+	s, ok := m.steps[id]
+	if !ok || s.TenantID != tenantID || s.ProjectID != projectID {
+		return nil, domain.ErrStepNotFound
+	}
+	return s, nil
+}
+
+func (m *mockStepRepo) GetByIDOnly(ctx context.Context, id uuid.UUID) (*domain.Step, error) {
+	s, ok := m.steps[id]
+	if !ok {
+		return nil, domain.ErrStepNotFound
+	}
+	return s, nil
+}
+
+func (m *mockStepRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.Step, error) {
+	var result []*domain.Step
+	for _, s := range m.steps {
+		if s.TenantID == tenantID && s.ProjectID == projectID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStepRepo) ListByBlockGroup(ctx context.Context, tenantID, blockGroupID uuid.UUID) ([]*domain.Step, error) {
+	var result []*domain.Step
+	for _, s := range m.steps {
+		if s.TenantID == tenantID && s.BlockGroupID != nil && *s.BlockGroupID == blockGroupID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStepRepo) ListStartSteps(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.Step, error) {
+	var result []*domain.Step
+	for _, s := range m.steps {
+		if s.TenantID == tenantID && s.ProjectID == projectID && s.Type == domain.StepTypeStart {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStepRepo) GetStartStepByTriggerType(ctx context.Context, tenantID, projectID uuid.UUID, triggerType domain.StepTriggerType) (*domain.Step, error) {
+	return nil, domain.ErrStepNotFound
+}
+
+func (m *mockStepRepo) Update(ctx context.Context, step *domain.Step) error {
+	m.steps[step.ID] = step
+	return nil
+}
+
+func (m *mockStepRepo) UpdateIfUnmodified(ctx context.Context, step *domain.Step, unmodifiedSince time.Time) error {
+	existing, ok := m.steps[step.ID]
+	if !ok {
+		return domain.ErrStepNotFound
+	}
+	if !existing.UpdatedAt.Equal(unmodifiedSince) {
+		return domain.ErrConcurrentModification
+	}
+	step.UpdatedAt = time.Now().UTC()
+	m.steps[step.ID] = step
+	return nil
+}
+
+func (m *mockStepRepo) Delete(ctx context.Context, tenantID, projectID, id uuid.UUID) error {
+	delete(m.steps, id)
+	return nil
+}
+
+// DeleteBatch removes steps and, when edgeRepo is linked, their connected edges
+func (m *mockStepRepo) DeleteBatch(ctx context.Context, tenantID, projectID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error) {
+	for _, id := range ids {
+		if s, ok := m.steps[id]; !ok || s.TenantID != tenantID || s.ProjectID != projectID {
+			return nil, domain.ErrStepNotFound
+		}
+	}
+	deleted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		delete(m.steps, id)
+		deleted[id] = true
+	}
+
+	edgeIDs := []uuid.UUID{}
+	if m.edgeRepo != nil {
+		for id, e := range m.edgeRepo.edges {
+			if (e.SourceStepID != nil && deleted[*e.SourceStepID]) || (e.TargetStepID != nil && deleted[*e.TargetStepID]) {
+				delete(m.edgeRepo.edges, id)
+				edgeIDs = append(edgeIDs, id)
+			}
+		}
+	}
+	return edgeIDs, nil
+}
+
+type mockEdgeRepo struct {
+	edges map[uuid.UUID]*domain.Edge
+}
+
+func newMockEdgeRepo() *mockEdgeRepo {
+	return &mockEdgeRepo{edges: make(map[uuid.UUID]*domain.Edge)}
+}
+
+func (m *mockEdgeRepo) Create(ctx context.Context, edge *domain.Edge) error {
+	m.edges[edge.ID] = edge
+	return nil
+}
+
+func (m *mockEdgeRepo) GetByID(ctx context.Context, tenantID, projectID, id uuid.UUID) (*domain.Edge, error) {
+	e, ok := m.edges[id]
+	if !ok || e.TenantID != tenantID || e.ProjectID != projectID {
+		return nil, domain.ErrEdgeNotFound
+	}
+	return e, nil
+}
+
+func (m *mockEdgeRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID) ([]*domain.Edge, error) {
+	var result []*domain.Edge
+	for _, e := range m.edges {
+		if e.TenantID == tenantID && e.ProjectID == projectID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockEdgeRepo) Delete(ctx context.Context, tenantID, projectID, id uuid.UUID) error {
+	delete(m.edges, id)
+	return nil
+}
+
+func (m *mockEdgeRepo) Exists(ctx context.Context, tenantID, projectID, sourceID, targetID uuid.UUID) (bool, error) {
+	for _, e := range m.edges {
+		if e.TenantID == tenantID && e.ProjectID == projectID &&
+			e.SourceStepID != nil && *e.SourceStepID == sourceID &&
+			e.TargetStepID != nil && *e.TargetStepID == targetID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ============================================================================
+// Tests for List / Update
+// ============================================================================
+
+func TestProjectUsecase_List_FilterByTags(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	uc := NewProjectUsecase(projectRepo, newMockStepRepo(), newMockEdgeRepo(), nil, nil)
+
+	for name, tags := range map[string][]string{
+		"crm-sync":     {"Sales", "CRM"},
+		"sales-report": {"sales", "weekly"},
+		"on-call":      {"ops"},
+	} {
+		project := domain.NewProject(tenantID, name, "")
+		project.SetTags(tags)
+		projectRepo.projects[project.ID] = project
+	}
+
+	tests := []struct {
+		name      string
+		tags      []string
+		wantNames []string
+	}{
+		{name: "single tag", tags: []string{"sales"}, wantNames: []string{"crm-sync", "sales-report"}},
+		{name: "single tag case-insensitive", tags: []string{"OPS"}, wantNames: []string{"on-call"}},
+		{name: "multiple tags (AND)", tags: []string{"sales", "crm"}, wantNames: []string{"crm-sync"}},
+		{name: "multiple tags no match", tags: []string{"ops", "sales"}, wantNames: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := uc.List(context.Background(), ListProjectsInput{TenantID: tenantID, Tags: tt.tags})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got := make(map[string]bool)
+			for _, p := range output.Projects {
+				got[p.Name] = true
+			}
+			if len(got) != len(tt.wantNames) {
+				t.Fatalf("List() returned %v, want %v", got, tt.wantNames)
+			}
+			for _, name := range tt.wantNames {
+				if !got[name] {
+					t.Errorf("List() missing %q", name)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectUsecase_Update_Tags(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	uc := NewProjectUsecase(projectRepo, newMockStepRepo(), newMockEdgeRepo(), nil, nil)
+
+	project := domain.NewProject(tenantID, "p", "")
+	project.SetTags([]string{"keep"})
+	projectRepo.projects[project.ID] = project
+
+	// nil tags leave existing tags unchanged
+	updated, err := uc.Update(context.Background(), UpdateProjectInput{TenantID: tenantID, ID: project.ID})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(updated.Tags) != 1 || updated.Tags[0] != "keep" {
+		t.Errorf("Tags = %v, want [keep]", updated.Tags)
+	}
+
+	// provided tags are normalized
+	updated, err = uc.Update(context.Background(), UpdateProjectInput{TenantID: tenantID, ID: project.ID, Tags: []string{" New ", "new"}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(updated.Tags) != 1 || updated.Tags[0] != "new" {
+		t.Errorf("Tags = %v, want [new]", updated.Tags)
+	}
+}
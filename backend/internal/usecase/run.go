@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -20,6 +23,18 @@ type RunUsecase struct {
 	edgeRepo    repository.EdgeRepository
 	stepRunRepo repository.StepRunRepository
 	queue       *engine.Queue
+
+	annotationRepo  repository.RunAnnotationRepository
+	queueAvailable  func() bool                    // Optional; reports whether the queue's Redis is reachable
+	maintenanceMode func(ctx context.Context) bool // Optional; reports whether read-only mode is on
+
+	inputLimits domain.InputLimits
+	tenantRepo  repository.TenantRepository // Optional; supplies per-tenant input and queue limits
+
+	maxQueueDepth       int64 // Runs waiting in the queue before creation is rejected; 0 disables
+	maxTenantQueueDepth int64 // Default limit of one tenant's waiting runs; 0 disables
+	// Reports the number of waiting runs overall and for the tenant; defaults to the queue's Depth
+	queueDepth func(ctx context.Context, tenantID uuid.UUID) (total, tenant int64, err error)
 }
 
 // NewRunUsecase creates a new RunUsecase
@@ -40,9 +55,109 @@ func NewRunUsecase(
 		edgeRepo:    edgeRepo,
 		stepRunRepo: stepRunRepo,
 		queue:       engine.NewQueue(redisClient),
+		inputLimits: domain.DefaultRunInputLimits(),
 	}
 }
 
+// WithQueueAvailability sets a check that reports whether the run queue is reachable. While it
+// reports false, operations that enqueue jobs fail fast with domain.ErrQueueUnavailable instead of
+// creating runs that can never be picked up.
+func (u *RunUsecase) WithQueueAvailability(available func() bool) *RunUsecase {
+	u.queueAvailable = available
+	return u
+}
+
+// WithMaintenanceMode sets a check that reports whether the deployment is in read-only
+// maintenance mode. While it reports true, operations that create runs fail with
+// domain.ErrMaintenanceMode.
+func (u *RunUsecase) WithMaintenanceMode(enabled func(ctx context.Context) bool) *RunUsecase {
+	u.maintenanceMode = enabled
+	return u
+}
+
+// checkCanEnqueue returns domain.ErrMaintenanceMode while read-only mode is on and
+// domain.ErrQueueUnavailable when the run queue is known to be down
+func (u *RunUsecase) checkCanEnqueue(ctx context.Context) error {
+	if u.maintenanceMode != nil && u.maintenanceMode(ctx) {
+		return domain.ErrMaintenanceMode
+	}
+	if u.queueAvailable != nil && !u.queueAvailable() {
+		return domain.ErrQueueUnavailable
+	}
+	return nil
+}
+
+// WithQueueLimits makes Create reject new runs while the queue holds maxDepth or more waiting
+// runs (domain.ErrQueueSaturated) or the tenant has maxTenantDepth or more of them
+// (domain.ErrTenantQueueSaturated). A limit of 0 disables that check; a tenant's max_queued_runs
+// limit overrides maxTenantDepth.
+func (u *RunUsecase) WithQueueLimits(maxDepth, maxTenantDepth int64) *RunUsecase {
+	u.maxQueueDepth = maxDepth
+	u.maxTenantQueueDepth = maxTenantDepth
+	return u
+}
+
+// checkQueueDepth rejects a new run for tenantID while the queue or the tenant's share of it is
+// saturated. It fails open when the depth cannot be read, leaving outages to checkCanEnqueue.
+func (u *RunUsecase) checkQueueDepth(ctx context.Context, tenantID uuid.UUID) error {
+	maxTenantDepth := u.maxTenantQueueDepth
+	if u.tenantRepo != nil {
+		if tenant, err := u.tenantRepo.GetByID(ctx, tenantID); err == nil {
+			if limits, err := tenant.GetLimits(); err == nil && limits.MaxQueuedRuns > 0 {
+				maxTenantDepth = int64(limits.MaxQueuedRuns)
+			}
+		}
+	}
+	if u.maxQueueDepth <= 0 && maxTenantDepth <= 0 {
+		return nil
+	}
+
+	depth := u.queueDepth
+	if depth == nil {
+		if u.queue == nil {
+			return nil
+		}
+		depth = u.queue.Depth
+	}
+	total, tenant, err := depth(ctx, tenantID)
+	if err != nil {
+		return nil
+	}
+	if u.maxQueueDepth > 0 && total >= u.maxQueueDepth {
+		return domain.ErrQueueSaturated
+	}
+	if maxTenantDepth > 0 && tenant >= maxTenantDepth {
+		return domain.ErrTenantQueueSaturated
+	}
+	return nil
+}
+
+// WithInputLimits sets the depth and size limits for run and step input submitted through the
+// API. When tenantRepo is set, a tenant's own input limits take precedence.
+func (u *RunUsecase) WithInputLimits(limits domain.InputLimits, tenantRepo repository.TenantRepository) *RunUsecase {
+	u.inputLimits = limits
+	u.tenantRepo = tenantRepo
+	return u
+}
+
+// checkInputLimits rejects input that exceeds the tenant's input limits with a ValidationError
+// for field. Empty input is always accepted.
+func (u *RunUsecase) checkInputLimits(ctx context.Context, tenantID uuid.UUID, field string, input json.RawMessage) error {
+	if len(input) == 0 {
+		return nil
+	}
+	limits := u.inputLimits
+	if u.tenantRepo != nil {
+		// The defaults still apply when the tenant cannot be loaded
+		if tenant, err := u.tenantRepo.GetByID(ctx, tenantID); err == nil {
+			if tenantLimits, err := tenant.GetLimits(); err == nil {
+				limits = limits.ForTenant(tenantLimits)
+			}
+		}
+	}
+	return limits.Validate(field, input)
+}
+
 // CreateRunInput represents input for creating a run
 type CreateRunInput struct {
 	TenantID    uuid.UUID
@@ -52,20 +167,39 @@ type CreateRunInput struct {
 	TriggeredBy domain.TriggerType // e.g., TriggerTypeManual, TriggerTypeTest
 	UserID      *uuid.UUID
 	StartStepID *uuid.UUID // Required: which Start block to execute from
+	// Optional trigger source and metadata recorded on the run, e.g. "webhook" with the request's
+	// remote_ip and user_agent (see domain.Run.TriggerInfo)
+	TriggerSource   string
+	TriggerMetadata map[string]interface{}
 }
 
 // Create creates and enqueues a new run
 func (u *RunUsecase) Create(ctx context.Context, input CreateRunInput) (*domain.Run, error) {
+	if err := u.checkCanEnqueue(ctx); err != nil {
+		return nil, err
+	}
+
 	// Validate start_step_id is required
 	if input.StartStepID == nil {
 		return nil, domain.NewValidationError("start_step_id", "start_step_id is required")
 	}
 
+	if err := u.checkInputLimits(ctx, input.TenantID, "input", input.Input); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkQueueDepth(ctx, input.TenantID); err != nil {
+		return nil, err
+	}
+
 	// Get project
 	project, err := u.projectRepo.GetByID(ctx, input.TenantID, input.ProjectID)
 	if err != nil {
 		return nil, err
 	}
+	if project.Paused {
+		return nil, domain.ErrProjectPaused
+	}
 
 	// Determine which version to use
 	// 0 means use latest (current project version)
@@ -97,6 +231,11 @@ func (u *RunUsecase) Create(ctx context.Context, input CreateRunInput) (*domain.
 	)
 	run.TriggeredByUser = input.UserID
 	run.StartStepID = input.StartStepID
+	if input.TriggerSource != "" {
+		if err := run.SetTriggerSource(input.TriggerSource, input.TriggerMetadata); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := u.runRepo.Create(ctx, run); err != nil {
 		return nil, err
@@ -168,6 +307,7 @@ func (u *RunUsecase) GetWithDetailsAndDefinition(ctx context.Context, tenantID,
 			Steps:       project.Steps,
 			Edges:       project.Edges,
 			BlockGroups: project.BlockGroups,
+			RunOutput:   project.RunOutput,
 		}
 	}
 
@@ -180,27 +320,33 @@ type ListRunsInput struct {
 	ProjectID   uuid.UUID
 	Status      *domain.RunStatus
 	TriggeredBy *domain.TriggerType // Optional filter by trigger type
+	Cursor      string              // Opaque cursor returned as NextCursor by a previous page; replaces Page
 	Page        int
 	Limit       int
 }
 
 // ListRunsOutput represents output for listing runs
 type ListRunsOutput struct {
-	Runs  []*domain.Run
-	Total int
-	Page  int
-	Limit int
+	Runs       []*domain.Run
+	Total      int
+	Page       int
+	Limit      int
+	NextCursor string // Empty on the last page
 }
 
-// List lists runs for a project
+// List lists runs for a project, newest first, by page or keyset cursor
 func (u *RunUsecase) List(ctx context.Context, input ListRunsInput) (*ListRunsOutput, error) {
-	input.Page, input.Limit = NormalizePagination(input.Page, input.Limit)
+	pagination, err := newListPagination(input.Page, input.Limit, DefaultLimit, input.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := repository.RunFilter{
 		Status:      input.Status,
 		TriggeredBy: input.TriggeredBy,
-		Page:        input.Page,
-		Limit:       input.Limit,
+		After:       pagination.After,
+		Page:        pagination.Page,
+		Limit:       pagination.FetchLimit(),
 	}
 
 	runs, total, err := u.runRepo.ListByProject(ctx, input.TenantID, input.ProjectID, filter)
@@ -208,17 +354,39 @@ func (u *RunUsecase) List(ctx context.Context, input ListRunsInput) (*ListRunsOu
 		return nil, err
 	}
 
-	return &ListRunsOutput{
-		Runs:  runs,
+	n, more := pagination.Next(len(runs), total)
+	output := &ListRunsOutput{
+		Runs:  runs[:n],
 		Total: total,
-		Page:  input.Page,
-		Limit: input.Limit,
-	}, nil
+		Page:  pagination.Page,
+		Limit: pagination.Limit,
+	}
+	if more {
+		last := output.Runs[n-1]
+		output.NextCursor = encodeListCursor(repository.ListCursor{Time: last.CreatedAt, ID: last.ID})
+	}
+	return output, nil
 }
 
-// Cancel cancels a running project
-func (u *RunUsecase) Cancel(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error) {
-	run, err := u.runRepo.GetByID(ctx, tenantID, id)
+// maxCancelReasonLength is the maximum length of a cancellation reason
+const maxCancelReasonLength = 500
+
+// CancelRunInput represents input for cancelling a run
+type CancelRunInput struct {
+	TenantID    uuid.UUID
+	RunID       uuid.UUID
+	CancelledBy *uuid.UUID // nil for automated cancellations
+	Reason      string     // Optional
+}
+
+// Cancel cancels a pending or running run, recording who cancelled it and why
+func (u *RunUsecase) Cancel(ctx context.Context, input CancelRunInput) (*domain.Run, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if utf8.RuneCountInString(reason) > maxCancelReasonLength {
+		return nil, domain.NewValidationError("reason", fmt.Sprintf("must be at most %d characters", maxCancelReasonLength))
+	}
+
+	run, err := u.runRepo.GetByID(ctx, input.TenantID, input.RunID)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +395,7 @@ func (u *RunUsecase) Cancel(ctx context.Context, tenantID, id uuid.UUID) (*domai
 		return nil, domain.ErrRunNotCancellable
 	}
 
-	run.Cancel()
+	run.Cancel(input.CancelledBy, reason)
 
 	if err := u.runRepo.Update(ctx, run); err != nil {
 		return nil, err
@@ -236,6 +404,19 @@ func (u *RunUsecase) Cancel(ctx context.Context, tenantID, id uuid.UUID) (*domai
 	return run, nil
 }
 
+// checkProjectNotPaused rejects executing a run of a paused project (ErrProjectPaused). Re-running
+// steps of an existing run starts execution as much as creating a run does.
+func (u *RunUsecase) checkProjectNotPaused(ctx context.Context, tenantID, projectID uuid.UUID) error {
+	project, err := u.projectRepo.GetByID(ctx, tenantID, projectID)
+	if err != nil {
+		return err
+	}
+	if project.Paused {
+		return domain.ErrProjectPaused
+	}
+	return nil
+}
+
 // ExecuteSingleStepInput represents input for executing a single step
 type ExecuteSingleStepInput struct {
 	TenantID uuid.UUID
@@ -246,6 +427,14 @@ type ExecuteSingleStepInput struct {
 
 // ExecuteSingleStep executes only one step from an existing run
 func (u *RunUsecase) ExecuteSingleStep(ctx context.Context, input ExecuteSingleStepInput) (*domain.StepRun, error) {
+	if err := u.checkCanEnqueue(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkInputLimits(ctx, input.TenantID, "input", input.Input); err != nil {
+		return nil, err
+	}
+
 	// 1. Get run and validate status (only completed/failed runs can be re-executed)
 	run, err := u.runRepo.GetByID(ctx, input.TenantID, input.RunID)
 	if err != nil {
@@ -254,6 +443,9 @@ func (u *RunUsecase) ExecuteSingleStep(ctx context.Context, input ExecuteSingleS
 	if run.Status != domain.RunStatusCompleted && run.Status != domain.RunStatusFailed {
 		return nil, domain.ErrRunNotResumable
 	}
+	if err := u.checkProjectNotPaused(ctx, input.TenantID, run.ProjectID); err != nil {
+		return nil, err
+	}
 
 	// 2. Get project definition from version snapshot
 	version, err := u.versionRepo.GetByProjectAndVersion(ctx, run.ProjectID, run.ProjectVersion)
@@ -359,6 +551,14 @@ type ResumeFromStepOutput struct {
 
 // ResumeFromStep resumes execution from a specific step through all downstream steps
 func (u *RunUsecase) ResumeFromStep(ctx context.Context, input ResumeFromStepInput) (*ResumeFromStepOutput, error) {
+	if err := u.checkCanEnqueue(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkInputLimits(ctx, input.TenantID, "input_override", input.InputOverride); err != nil {
+		return nil, err
+	}
+
 	// 1. Get run and validate status
 	run, err := u.runRepo.GetByID(ctx, input.TenantID, input.RunID)
 	if err != nil {
@@ -367,6 +567,9 @@ func (u *RunUsecase) ResumeFromStep(ctx context.Context, input ResumeFromStepInp
 	if run.Status != domain.RunStatusCompleted && run.Status != domain.RunStatusFailed {
 		return nil, domain.ErrRunNotResumable
 	}
+	if err := u.checkProjectNotPaused(ctx, input.TenantID, run.ProjectID); err != nil {
+		return nil, err
+	}
 
 	// 2. Get project definition from version snapshot
 	version, err := u.versionRepo.GetByProjectAndVersion(ctx, run.ProjectID, run.ProjectVersion)
@@ -478,6 +681,14 @@ type ExecuteSystemProjectOutput struct {
 // This is used for internal system calls (e.g., Copilot meta-project)
 // Returns immediately after creating the run - execution happens asynchronously
 func (u *RunUsecase) ExecuteSystemProject(ctx context.Context, input ExecuteSystemProjectInput) (*ExecuteSystemProjectOutput, error) {
+	if err := u.checkCanEnqueue(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkInputLimits(ctx, input.TenantID, "input", input.Input); err != nil {
+		return nil, err
+	}
+
 	// 1. Look up system project by slug
 	project, err := u.projectRepo.GetSystemBySlug(ctx, input.SystemSlug)
 	if err != nil {
@@ -586,11 +797,22 @@ type TestStepInlineOutput struct {
 // TestStepInline creates a test run and executes only a single step
 // This allows testing a step without requiring an existing run
 func (u *RunUsecase) TestStepInline(ctx context.Context, input TestStepInlineInput) (*TestStepInlineOutput, error) {
+	if err := u.checkCanEnqueue(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkInputLimits(ctx, input.TenantID, "input", input.Input); err != nil {
+		return nil, err
+	}
+
 	// 1. Get the current project (draft state)
 	project, err := u.projectRepo.GetByID(ctx, input.TenantID, input.ProjectID)
 	if err != nil {
 		return nil, err
 	}
+	if project.Paused {
+		return nil, domain.ErrProjectPaused
+	}
 
 	// 2. Get current steps from the project
 	steps, err := u.stepRepo.ListByProject(ctx, input.TenantID, input.ProjectID)
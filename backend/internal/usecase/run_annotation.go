@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// WithAnnotationRepo sets the run annotation repository
+func (u *RunUsecase) WithAnnotationRepo(repo repository.RunAnnotationRepository) *RunUsecase {
+	u.annotationRepo = repo
+	return u
+}
+
+// CreateRunAnnotationInput represents input for annotating a run
+type CreateRunAnnotationInput struct {
+	TenantID uuid.UUID
+	RunID    uuid.UUID
+	AuthorID uuid.UUID
+	StepID   *uuid.UUID // Optional step of the run's workflow the note refers to
+	Comment  string
+}
+
+// CreateAnnotation adds a note to a run
+func (u *RunUsecase) CreateAnnotation(ctx context.Context, input CreateRunAnnotationInput) (*domain.RunAnnotation, error) {
+	run, err := u.runRepo.GetByID(ctx, input.TenantID, input.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := domain.NewRunAnnotation(input.TenantID, run.ID, input.AuthorID, input.StepID, input.Comment)
+	if err := annotation.Validate(); err != nil {
+		return nil, err
+	}
+
+	if input.StepID != nil {
+		if _, err := u.stepRepo.GetByID(ctx, input.TenantID, run.ProjectID, *input.StepID); err != nil {
+			return nil, domain.NewValidationError("step_id", "step does not belong to the run's workflow")
+		}
+	}
+
+	if err := u.annotationRepo.Create(ctx, annotation); err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+// ListAnnotations lists a run's annotations, oldest first
+func (u *RunUsecase) ListAnnotations(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.RunAnnotation, error) {
+	if _, err := u.runRepo.GetByID(ctx, tenantID, runID); err != nil {
+		return nil, err
+	}
+	return u.annotationRepo.ListByRun(ctx, tenantID, runID)
+}
+
+// DeleteAnnotation deletes an annotation. Only its author may delete it.
+func (u *RunUsecase) DeleteAnnotation(ctx context.Context, tenantID, runID, annotationID, userID uuid.UUID) error {
+	annotation, err := u.annotationRepo.GetByID(ctx, tenantID, runID, annotationID)
+	if err != nil {
+		return err
+	}
+	if annotation.AuthorID != userID {
+		return domain.ErrForbidden
+	}
+	return u.annotationRepo.Delete(ctx, tenantID, runID, annotationID)
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// mockRunAnnotationRepo is an in-memory RunAnnotationRepository
+type mockRunAnnotationRepo struct {
+	annotations []*domain.RunAnnotation
+}
+
+func (m *mockRunAnnotationRepo) Create(ctx context.Context, annotation *domain.RunAnnotation) error {
+	m.annotations = append(m.annotations, annotation)
+	return nil
+}
+
+func (m *mockRunAnnotationRepo) GetByID(ctx context.Context, tenantID, runID, id uuid.UUID) (*domain.RunAnnotation, error) {
+	for _, a := range m.annotations {
+		if a.ID == id && a.RunID == runID && a.TenantID == tenantID {
+			return a, nil
+		}
+	}
+	return nil, domain.ErrRunAnnotationNotFound
+}
+
+func (m *mockRunAnnotationRepo) ListByRun(ctx context.Context, tenantID, runID uuid.UUID) ([]*domain.RunAnnotation, error) {
+	result := make([]*domain.RunAnnotation, 0)
+	for _, a := range m.annotations {
+		if a.RunID == runID && a.TenantID == tenantID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRunAnnotationRepo) Delete(ctx context.Context, tenantID, runID, id uuid.UUID) error {
+	for i, a := range m.annotations {
+		if a.ID == id && a.RunID == runID && a.TenantID == tenantID {
+			m.annotations = append(m.annotations[:i], m.annotations[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrRunAnnotationNotFound
+}
+
+func setupRunAnnotationUsecase(t *testing.T) (*RunUsecase, *domain.Run, *domain.Step) {
+	t.Helper()
+	tenantID := uuid.New()
+	projectID := uuid.New()
+
+	runRepo := newMockRunRepo()
+	run := &domain.Run{ID: uuid.New(), TenantID: tenantID, ProjectID: projectID, Status: domain.RunStatusFailed}
+	runRepo.runs[run.ID] = run
+
+	step := domain.NewStep(tenantID, projectID, "Fetch", domain.StepTypeTool, nil)
+	stepRepo := &mockStepRepo{steps: map[uuid.UUID]*domain.Step{step.ID: step}}
+
+	uc := (&RunUsecase{runRepo: runRepo, stepRepo: stepRepo}).WithAnnotationRepo(&mockRunAnnotationRepo{})
+	return uc, run, step
+}
+
+func TestRunUsecase_CreateAnnotation_ListsInOrder(t *testing.T) {
+	uc, run, step := setupRunAnnotationUsecase(t)
+	ctx := context.Background()
+	author := uuid.New()
+
+	first, err := uc.CreateAnnotation(ctx, CreateRunAnnotationInput{
+		TenantID: run.TenantID, RunID: run.ID, AuthorID: author, Comment: "  timeout upstream  ",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnnotation() error = %v", err)
+	}
+	if first.Comment != "timeout upstream" || first.AuthorID != author || first.StepID != nil {
+		t.Errorf("annotation = %+v, want trimmed comment by author without step", first)
+	}
+
+	time.Sleep(time.Millisecond)
+	second, err := uc.CreateAnnotation(ctx, CreateRunAnnotationInput{
+		TenantID: run.TenantID, RunID: run.ID, AuthorID: uuid.New(), StepID: &step.ID, Comment: "retry fixed it",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnnotation(step) error = %v", err)
+	}
+
+	annotations, err := uc.ListAnnotations(ctx, run.TenantID, run.ID)
+	if err != nil {
+		t.Fatalf("ListAnnotations() error = %v", err)
+	}
+	if len(annotations) != 2 || annotations[0].ID != first.ID || annotations[1].ID != second.ID {
+		t.Fatalf("annotations = %+v, want [first, second]", annotations)
+	}
+	if !annotations[0].CreatedAt.Before(annotations[1].CreatedAt) {
+		t.Errorf("annotations not in creation order")
+	}
+}
+
+func TestRunUsecase_CreateAnnotation_Validation(t *testing.T) {
+	uc, run, _ := setupRunAnnotationUsecase(t)
+	ctx := context.Background()
+
+	var validationErr domain.ValidationError
+	_, err := uc.CreateAnnotation(ctx, CreateRunAnnotationInput{TenantID: run.TenantID, RunID: run.ID, Comment: "   "})
+	if !errors.As(err, &validationErr) || validationErr.Field != "comment" {
+		t.Errorf("empty comment: error = %v, want comment validation error", err)
+	}
+
+	unknownStep := uuid.New()
+	_, err = uc.CreateAnnotation(ctx, CreateRunAnnotationInput{TenantID: run.TenantID, RunID: run.ID, StepID: &unknownStep, Comment: "x"})
+	if !errors.As(err, &validationErr) || validationErr.Field != "step_id" {
+		t.Errorf("unknown step: error = %v, want step_id validation error", err)
+	}
+}
+
+func TestRunUsecase_Annotations_TenantIsolation(t *testing.T) {
+	uc, run, _ := setupRunAnnotationUsecase(t)
+	ctx := context.Background()
+	author := uuid.New()
+
+	annotation, err := uc.CreateAnnotation(ctx, CreateRunAnnotationInput{
+		TenantID: run.TenantID, RunID: run.ID, AuthorID: author, Comment: "internal note",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnnotation() error = %v", err)
+	}
+
+	otherTenant := uuid.New()
+	if _, err := uc.ListAnnotations(ctx, otherTenant, run.ID); !errors.Is(err, domain.ErrRunNotFound) {
+		t.Errorf("ListAnnotations(other tenant) error = %v, want ErrRunNotFound", err)
+	}
+	if _, err := uc.CreateAnnotation(ctx, CreateRunAnnotationInput{
+		TenantID: otherTenant, RunID: run.ID, AuthorID: author, Comment: "sneaky",
+	}); !errors.Is(err, domain.ErrRunNotFound) {
+		t.Errorf("CreateAnnotation(other tenant) error = %v, want ErrRunNotFound", err)
+	}
+	if err := uc.DeleteAnnotation(ctx, otherTenant, run.ID, annotation.ID, author); !errors.Is(err, domain.ErrRunAnnotationNotFound) {
+		t.Errorf("DeleteAnnotation(other tenant) error = %v, want ErrRunAnnotationNotFound", err)
+	}
+
+	// Only the author can delete
+	if err := uc.DeleteAnnotation(ctx, run.TenantID, run.ID, annotation.ID, uuid.New()); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("DeleteAnnotation(non-author) error = %v, want ErrForbidden", err)
+	}
+	if err := uc.DeleteAnnotation(ctx, run.TenantID, run.ID, annotation.ID, author); err != nil {
+		t.Fatalf("DeleteAnnotation() error = %v", err)
+	}
+	annotations, _ := uc.ListAnnotations(ctx, run.TenantID, run.ID)
+	if len(annotations) != 0 {
+		t.Errorf("annotations after delete = %d, want 0", len(annotations))
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// MaxBatchRunIDs caps the number of run IDs of one batch status request
+const MaxBatchRunIDs = 100
+
+// RunStatusSummary is the summary-level status of a run, as returned by a batch status request
+type RunStatusSummary struct {
+	ID          uuid.UUID          `json:"id"`
+	ProjectID   uuid.UUID          `json:"project_id"`
+	Status      domain.RunStatus   `json:"status"`
+	RunNumber   int                `json:"run_number"`
+	Error       *string            `json:"error,omitempty"`
+	StartedAt   *time.Time         `json:"started_at,omitempty"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Summary     *domain.RunSummary `json:"summary,omitempty"`
+}
+
+// BatchRunStatusOutput represents output for a batch status request
+type BatchRunStatusOutput struct {
+	Runs     []RunStatusSummary `json:"runs"`      // Found runs, in request order
+	NotFound []uuid.UUID        `json:"not_found"` // Requested IDs with no run visible to the tenant
+}
+
+// GetBatchStatus returns the summary-level status of several runs of a tenant in one call.
+// Duplicate IDs are returned once; IDs of other tenants are reported as not found.
+func (u *RunUsecase) GetBatchStatus(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (*BatchRunStatusOutput, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, domain.NewValidationError("ids", "at least one run ID is required")
+	}
+	if len(unique) > MaxBatchRunIDs {
+		return nil, domain.NewValidationError("ids", fmt.Sprintf("at most %d run IDs are allowed", MaxBatchRunIDs))
+	}
+
+	runs, err := u.runRepo.GetByIDs(ctx, tenantID, unique)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.Run, len(runs))
+	for _, run := range runs {
+		byID[run.ID] = run
+	}
+
+	output := &BatchRunStatusOutput{Runs: []RunStatusSummary{}, NotFound: []uuid.UUID{}}
+	for _, id := range unique {
+		run, ok := byID[id]
+		if !ok {
+			output.NotFound = append(output.NotFound, id)
+			continue
+		}
+		output.Runs = append(output.Runs, RunStatusSummary{
+			ID:          run.ID,
+			ProjectID:   run.ProjectID,
+			Status:      run.Status,
+			RunNumber:   run.RunNumber,
+			Error:       run.Error,
+			StartedAt:   run.StartedAt,
+			CompletedAt: run.CompletedAt,
+			CreatedAt:   run.CreatedAt,
+			Summary:     run.Summary,
+		})
+	}
+	return output, nil
+}
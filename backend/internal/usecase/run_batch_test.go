@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func TestRunUsecase_GetBatchStatus_MixedIDs(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockRunRepo()
+	runs := seedSearchRuns(repo, tenantID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 2)
+	runs[1].Status = domain.RunStatusFailed
+	missing := uuid.New()
+
+	uc := &RunUsecase{runRepo: repo}
+	output, err := uc.GetBatchStatus(context.Background(), tenantID, []uuid.UUID{runs[1].ID, missing, runs[0].ID, runs[1].ID})
+	if err != nil {
+		t.Fatalf("GetBatchStatus() error = %v", err)
+	}
+
+	// Request order, duplicates returned once
+	if len(output.Runs) != 2 {
+		t.Fatalf("len(Runs) = %d, want 2", len(output.Runs))
+	}
+	if output.Runs[0].ID != runs[1].ID || output.Runs[0].Status != domain.RunStatusFailed {
+		t.Errorf("Runs[0] = %s (%s), want %s (failed)", output.Runs[0].ID, output.Runs[0].Status, runs[1].ID)
+	}
+	if output.Runs[1].ID != runs[0].ID || output.Runs[1].Status != domain.RunStatusCompleted {
+		t.Errorf("Runs[1] = %s (%s), want %s (completed)", output.Runs[1].ID, output.Runs[1].Status, runs[0].ID)
+	}
+	if len(output.NotFound) != 1 || output.NotFound[0] != missing {
+		t.Errorf("NotFound = %v, want [%s]", output.NotFound, missing)
+	}
+}
+
+func TestRunUsecase_GetBatchStatus_TenantIsolation(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockRunRepo()
+	own := seedSearchRuns(repo, tenantID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 1)
+	other := seedSearchRuns(repo, uuid.New(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	uc := &RunUsecase{runRepo: repo}
+	output, err := uc.GetBatchStatus(context.Background(), tenantID, []uuid.UUID{own[0].ID, other[0].ID})
+	if err != nil {
+		t.Fatalf("GetBatchStatus() error = %v", err)
+	}
+
+	if len(output.Runs) != 1 || output.Runs[0].ID != own[0].ID {
+		t.Errorf("Runs = %v, want only the tenant's run %s", output.Runs, own[0].ID)
+	}
+	if len(output.NotFound) != 1 || output.NotFound[0] != other[0].ID {
+		t.Errorf("NotFound = %v, want the other tenant's run %s", output.NotFound, other[0].ID)
+	}
+}
+
+func TestRunUsecase_GetBatchStatus_Validation(t *testing.T) {
+	tooMany := make([]uuid.UUID, MaxBatchRunIDs+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name string
+		ids  []uuid.UUID
+	}{
+		{name: "no IDs", ids: nil},
+		{name: "too many IDs", ids: tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &RunUsecase{runRepo: newMockRunRepo()}
+			_, err := uc.GetBatchStatus(context.Background(), uuid.New(), tt.ids)
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("GetBatchStatus() error = %v, want ValidationError", err)
+			}
+		})
+	}
+}
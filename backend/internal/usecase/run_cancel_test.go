@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func newCancellableRun(repo *mockRunRepo, tenantID uuid.UUID, status domain.RunStatus) *domain.Run {
+	run := domain.NewRun(tenantID, uuid.New(), 1, nil, domain.TriggerTypeManual)
+	run.Status = status
+	repo.runs[run.ID] = run
+	return run
+}
+
+func TestRunUsecase_Cancel_RecordsActorAndReason(t *testing.T) {
+	tenantID := uuid.New()
+	userID := uuid.New()
+	repo := newMockRunRepo()
+	run := newCancellableRun(repo, tenantID, domain.RunStatusRunning)
+
+	uc := &RunUsecase{runRepo: repo}
+	_, err := uc.Cancel(context.Background(), CancelRunInput{
+		TenantID:    tenantID,
+		RunID:       run.ID,
+		CancelledBy: &userID,
+		Reason:      "  wrong input  ",
+	})
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	stored := repo.runs[run.ID]
+	if stored.Status != domain.RunStatusCancelled {
+		t.Errorf("Status = %v, want %v", stored.Status, domain.RunStatusCancelled)
+	}
+	if stored.CancelledBy == nil || *stored.CancelledBy != userID {
+		t.Errorf("CancelledBy = %v, want %v", stored.CancelledBy, userID)
+	}
+	if stored.CancelReason == nil || *stored.CancelReason != "wrong input" {
+		t.Errorf("CancelReason = %v, want %q", stored.CancelReason, "wrong input")
+	}
+}
+
+func TestRunUsecase_Cancel_Automated(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockRunRepo()
+	run := newCancellableRun(repo, tenantID, domain.RunStatusPending)
+
+	uc := &RunUsecase{runRepo: repo}
+	if _, err := uc.Cancel(context.Background(), CancelRunInput{TenantID: tenantID, RunID: run.ID}); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	stored := repo.runs[run.ID]
+	if stored.CancelledBy != nil || stored.CancelReason != nil {
+		t.Errorf("CancelledBy = %v, CancelReason = %v, want nil", stored.CancelledBy, stored.CancelReason)
+	}
+}
+
+func TestRunUsecase_Cancel_Validation(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockRunRepo()
+	uc := &RunUsecase{runRepo: repo}
+
+	t.Run("reason too long", func(t *testing.T) {
+		run := newCancellableRun(repo, tenantID, domain.RunStatusRunning)
+		_, err := uc.Cancel(context.Background(), CancelRunInput{
+			TenantID: tenantID,
+			RunID:    run.ID,
+			Reason:   strings.Repeat("x", maxCancelReasonLength+1),
+		})
+		var validationErr domain.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Cancel() error = %v, want a validation error", err)
+		}
+		if repo.runs[run.ID].Status != domain.RunStatusRunning {
+			t.Error("run was cancelled despite the invalid reason")
+		}
+	})
+
+	t.Run("completed run", func(t *testing.T) {
+		run := newCancellableRun(repo, tenantID, domain.RunStatusCompleted)
+		_, err := uc.Cancel(context.Background(), CancelRunInput{TenantID: tenantID, RunID: run.ID, Reason: "too late"})
+		if !errors.Is(err, domain.ErrRunNotCancellable) {
+			t.Errorf("Cancel() error = %v, want ErrRunNotCancellable", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// RunFailureNotifier announces failed runs according to the failure notification rules of their
+// workflow. Workflows without rules notify every tenant channel subscribed to run.failed.
+type RunFailureNotifier struct {
+	projectRepo repository.ProjectRepository
+	runRepo     repository.RunRepository
+	dispatcher  *NotificationDispatcher
+}
+
+// NewRunFailureNotifier creates a new RunFailureNotifier
+func NewRunFailureNotifier(projectRepo repository.ProjectRepository, runRepo repository.RunRepository, dispatcher *NotificationDispatcher) *RunFailureNotifier {
+	return &RunFailureNotifier{
+		projectRepo: projectRepo,
+		runRepo:     runRepo,
+		dispatcher:  dispatcher,
+	}
+}
+
+// NotifyRunFailed notifies about a failed run whose error has the given
+// domain.RunErrorCategory*. projectTenantID is the tenant the project belongs to, which differs
+// from the run's tenant for system projects; notifications always go to the run's tenant. Runs
+// that did not fail are ignored.
+func (n *RunFailureNotifier) NotifyRunFailed(ctx context.Context, projectTenantID uuid.UUID, run *domain.Run, category string) error {
+	if run.Status != domain.RunStatusFailed {
+		return nil
+	}
+	project, err := n.projectRepo.GetByID(ctx, projectTenantID, run.ProjectID)
+	if err != nil {
+		return fmt.Errorf("load project: %w", err)
+	}
+	notification := runFailedNotification(project, run, category)
+	if len(project.FailureNotifications) == 0 {
+		return n.dispatcher.Notify(ctx, run.TenantID, notification)
+	}
+
+	consecutive := 1
+	if needsFailureStreak(project.FailureNotifications) {
+		if consecutive, err = n.runRepo.CountConsecutiveFailures(ctx, run.TenantID, run.ProjectID); err != nil {
+			return err
+		}
+	}
+	notification.Data["consecutive_failures"] = consecutive
+
+	var errs []error
+	notified := make(map[uuid.UUID]bool)
+	for _, rule := range project.FailureNotifications {
+		if notified[rule.ChannelID] || !rule.Matches(category, consecutive) {
+			continue
+		}
+		notified[rule.ChannelID] = true
+		if err := n.dispatcher.NotifyChannel(ctx, run.TenantID, rule.ChannelID, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// needsFailureStreak reports whether any rule depends on the number of consecutive failures
+func needsFailureStreak(rules []domain.FailureNotificationRule) bool {
+	for _, rule := range rules {
+		if rule.ConsecutiveFailures > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// runFailedNotification builds the run.failed notification of a run
+func runFailedNotification(project *domain.Project, run *domain.Run, category string) domain.Notification {
+	message := "The run failed."
+	if run.Error != nil {
+		message = *run.Error
+	}
+	return domain.Notification{
+		Event:   domain.NotificationEventRunFailed,
+		Title:   fmt.Sprintf("Run of %s failed", project.Name),
+		Message: message,
+		URL:     fmt.Sprintf("/api/v1/runs/%s", run.ID),
+		Data: map[string]interface{}{
+			"run_id":         run.ID,
+			"project_id":     run.ProjectID,
+			"error_category": category,
+		},
+	}
+}
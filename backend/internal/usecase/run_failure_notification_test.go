@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// failureNotificationFixture holds a project whose runs are announced through webhook channels
+type failureNotificationFixture struct {
+	project  *domain.Project
+	runRepo  *mockRunRepo
+	service  *mockNotificationService
+	notifier *RunFailureNotifier
+	clock    time.Time
+}
+
+func newFailureNotificationFixture(t *testing.T, channels []*domain.NotificationChannel, rules []domain.FailureNotificationRule) *failureNotificationFixture {
+	t.Helper()
+	tenantID := uuid.New()
+	for _, channel := range channels {
+		channel.TenantID = tenantID
+	}
+	project := domain.NewProject(tenantID, "Nightly sync", "")
+	project.FailureNotifications = rules
+	projectRepo := newMockProjectRepo()
+	projectRepo.projects[project.ID] = project
+	runRepo := newMockRunRepo()
+	service := &mockNotificationService{}
+	dispatcher, _ := newTestDispatcher(newMockNotificationChannelRepo(channels...), service, &mockAuditLogRepo{})
+	return &failureNotificationFixture{
+		project:  project,
+		runRepo:  runRepo,
+		service:  service,
+		notifier: NewRunFailureNotifier(projectRepo, runRepo, dispatcher),
+		clock:    time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// finishRun records a finished run of the project and notifies about it like the worker does
+func (f *failureNotificationFixture) finishRun(t *testing.T, failed bool, category string) {
+	t.Helper()
+	run := domain.NewRun(f.project.TenantID, f.project.ID, 1, nil, domain.TriggerTypeSchedule)
+	if failed {
+		run.Fail("step failed")
+	} else {
+		run.Complete(nil)
+	}
+	f.clock = f.clock.Add(time.Minute)
+	completedAt := f.clock
+	run.CompletedAt = &completedAt
+	f.runRepo.runs[run.ID] = run
+
+	if err := f.notifier.NotifyRunFailed(context.Background(), f.project.TenantID, run, category); err != nil {
+		t.Fatalf("NotifyRunFailed() error = %v", err)
+	}
+}
+
+func TestRunFailureNotifier_ConsecutiveFailureThreshold(t *testing.T) {
+	channel := newWebhookChannel(uuid.Nil)
+	f := newFailureNotificationFixture(t, []*domain.NotificationChannel{channel},
+		[]domain.FailureNotificationRule{{ChannelID: channel.ID, ConsecutiveFailures: 3}})
+
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	if len(f.service.delivered) != 0 {
+		t.Fatalf("delivered %d notifications before the threshold, want 0", len(f.service.delivered))
+	}
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	if len(f.service.delivered) != 1 {
+		t.Fatalf("delivered %d notifications at the threshold, want 1", len(f.service.delivered))
+	}
+	if got := f.service.delivered[0].Data["consecutive_failures"]; got != 3 {
+		t.Errorf("consecutive_failures = %v, want 3", got)
+	}
+
+	// A completed run ends the streak, so the count starts over
+	f.finishRun(t, false, "")
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	if len(f.service.delivered) != 1 {
+		t.Fatalf("delivered %d notifications after the streak was reset, want 1", len(f.service.delivered))
+	}
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	if len(f.service.delivered) != 2 {
+		t.Errorf("delivered %d notifications at the threshold of the new streak, want 2", len(f.service.delivered))
+	}
+}
+
+func TestRunFailureNotifier_CategoryFiltering(t *testing.T) {
+	oncall := newWebhookChannel(uuid.Nil)
+	team := newWebhookChannel(uuid.Nil)
+	f := newFailureNotificationFixture(t, []*domain.NotificationChannel{oncall, team}, []domain.FailureNotificationRule{
+		{ChannelID: oncall.ID, Categories: []string{domain.RunErrorCategoryTimeout, domain.RunErrorCategoryMaxDuration}},
+		{ChannelID: team.ID},
+	})
+
+	f.finishRun(t, true, domain.RunErrorCategoryRateLimit)
+	if len(f.service.channels) != 1 || f.service.channels[0] != team.ID {
+		t.Fatalf("rate limit failure notified %v, want only the unfiltered channel", f.service.channels)
+	}
+
+	f.finishRun(t, true, domain.RunErrorCategoryTimeout)
+	if len(f.service.channels) != 3 {
+		t.Fatalf("timeout failure notified %v, want both channels", f.service.channels[1:])
+	}
+	if got := f.service.delivered[1].Data["error_category"]; got != domain.RunErrorCategoryTimeout {
+		t.Errorf("error_category = %v, want %q", got, domain.RunErrorCategoryTimeout)
+	}
+}
+
+func TestRunFailureNotifier_WithoutRulesNotifiesSubscribedChannels(t *testing.T) {
+	subscribed := newWebhookChannel(uuid.Nil, domain.NotificationEventRunFailed)
+	other := newWebhookChannel(uuid.Nil, domain.NotificationEventBudgetAlert)
+	f := newFailureNotificationFixture(t, []*domain.NotificationChannel{subscribed, other}, nil)
+
+	f.finishRun(t, false, "")
+	f.finishRun(t, true, domain.RunErrorCategoryOther)
+	if len(f.service.channels) != 1 || f.service.channels[0] != subscribed.ID {
+		t.Errorf("notified %v, want only the channel subscribed to run.failed", f.service.channels)
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// limitsTenantRepo returns a tenant with the given limits
+type limitsTenantRepo struct {
+	repository.TenantRepository
+	limits domain.TenantLimits
+}
+
+func (r *limitsTenantRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+	limits, _ := json.Marshal(r.limits)
+	return &domain.Tenant{ID: id, Limits: limits}, nil
+}
+
+func createWithInput(uc *RunUsecase, input json.RawMessage) error {
+	startStepID := uuid.New()
+	_, err := uc.Create(context.Background(), CreateRunInput{
+		TenantID:    uuid.New(),
+		ProjectID:   uuid.New(),
+		Input:       input,
+		TriggeredBy: domain.TriggerTypeManual,
+		StartStepID: &startStepID,
+	})
+	return err
+}
+
+func TestRunUsecase_Create_RejectsInputOverLimits(t *testing.T) {
+	uc := (&RunUsecase{runRepo: newMockRunRepo()}).WithInputLimits(domain.InputLimits{MaxDepth: 3, MaxBytes: 32}, nil)
+
+	tests := []struct {
+		name  string
+		input json.RawMessage
+	}{
+		{name: "too deep", input: json.RawMessage(`{"a":{"b":{"c":{"d":1}}}}`)},
+		{name: "oversized", input: json.RawMessage(`{"text":"` + strings.Repeat("x", 40) + `"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := createWithInput(uc, tt.input)
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != "input" {
+				t.Fatalf("Create() error = %v, want a ValidationError for input", err)
+			}
+		})
+	}
+}
+
+func TestRunUsecase_Create_TenantInputLimits(t *testing.T) {
+	tenants := &limitsTenantRepo{limits: domain.TenantLimits{MaxInputDepth: 2}}
+	uc := (&RunUsecase{runRepo: newMockRunRepo()}).WithInputLimits(domain.DefaultRunInputLimits(), tenants)
+
+	var validationErr domain.ValidationError
+	if err := createWithInput(uc, json.RawMessage(`{"a":{"b":{"c":1}}}`)); !errors.As(err, &validationErr) {
+		t.Fatalf("Create() error = %v, want the tenant depth limit to reject the input", err)
+	}
+}
+
+func TestRunUsecase_ResumeFromStep_RejectsDeepOverride(t *testing.T) {
+	uc := (&RunUsecase{runRepo: newMockRunRepo()}).WithInputLimits(domain.InputLimits{MaxDepth: 1}, nil)
+
+	_, err := uc.ResumeFromStep(context.Background(), ResumeFromStepInput{
+		TenantID:      uuid.New(),
+		RunID:         uuid.New(),
+		InputOverride: json.RawMessage(`{"a":{"b":1}}`),
+	})
+	var validationErr domain.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "input_override" {
+		t.Fatalf("ResumeFromStep() error = %v, want a ValidationError for input_override", err)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// slowestStepCount is the number of steps highlighted as slowest in a project profile
+const slowestStepCount = 3
+
+// ProfileProjectInput represents input for profiling a project's step durations
+type ProfileProjectInput struct {
+	TenantID  uuid.UUID
+	ProjectID uuid.UUID
+	Runs      int // Number of most recent runs to profile (default DefaultLimit, at most MaxLimit)
+}
+
+// ProjectProfile aggregates step durations across a project's recent runs
+type ProjectProfile struct {
+	ProjectID      uuid.UUID            `json:"project_id"`
+	RunCount       int                  `json:"run_count"`
+	Steps          []domain.StepProfile `json:"steps"`            // Slowest first by p95 duration
+	SlowestStepIDs []uuid.UUID          `json:"slowest_step_ids"` // The first steps of Steps
+}
+
+// Profile aggregates the step run durations of a project's most recent runs into p50/p95/max
+// timings per step, so slow steps can be found with a single call
+func (u *RunUsecase) Profile(ctx context.Context, input ProfileProjectInput) (*ProjectProfile, error) {
+	if _, err := u.projectRepo.GetByID(ctx, input.TenantID, input.ProjectID); err != nil {
+		return nil, err
+	}
+	_, limit := NormalizePagination(1, input.Runs)
+
+	runs, _, err := u.runRepo.ListByProject(ctx, input.TenantID, input.ProjectID, repository.RunFilter{Page: 1, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	var stepRuns []*domain.StepRun
+	for _, run := range runs {
+		runStepRuns, err := u.stepRunRepo.ListByRun(ctx, input.TenantID, run.ID)
+		if err != nil {
+			return nil, err
+		}
+		stepRuns = append(stepRuns, runStepRuns...)
+	}
+
+	profile := &ProjectProfile{
+		ProjectID:      input.ProjectID,
+		RunCount:       len(runs),
+		Steps:          domain.NewStepProfiles(stepRuns),
+		SlowestStepIDs: []uuid.UUID{},
+	}
+	for i := 0; i < len(profile.Steps) && i < slowestStepCount; i++ {
+		profile.SlowestStepIDs = append(profile.SlowestStepIDs, profile.Steps[i].StepID)
+	}
+	return profile, nil
+}
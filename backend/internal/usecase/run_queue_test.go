@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func TestRunUsecase_Create_QueueUnavailable(t *testing.T) {
+	repo := newMockRunRepo()
+	uc := (&RunUsecase{runRepo: repo}).WithQueueAvailability(func() bool { return false })
+
+	startStepID := uuid.New()
+	run, err := uc.Create(context.Background(), CreateRunInput{
+		TenantID:    uuid.New(),
+		ProjectID:   uuid.New(),
+		TriggeredBy: domain.TriggerTypeManual,
+		StartStepID: &startStepID,
+	})
+	if !errors.Is(err, domain.ErrQueueUnavailable) {
+		t.Fatalf("Create() error = %v, want ErrQueueUnavailable", err)
+	}
+	if run != nil {
+		t.Errorf("Create() run = %v, want nil", run)
+	}
+	if len(repo.runs) != 0 {
+		t.Errorf("%d runs stored, want none while the queue is unavailable", len(repo.runs))
+	}
+}
+
+func TestRunUsecase_ResumeFromStep_QueueUnavailable(t *testing.T) {
+	uc := (&RunUsecase{runRepo: newMockRunRepo()}).WithQueueAvailability(func() bool { return false })
+
+	_, err := uc.ResumeFromStep(context.Background(), ResumeFromStepInput{TenantID: uuid.New(), RunID: uuid.New()})
+	if !errors.Is(err, domain.ErrQueueUnavailable) {
+		t.Fatalf("ResumeFromStep() error = %v, want ErrQueueUnavailable", err)
+	}
+}
+
+func TestRunUsecase_Create_MaintenanceMode(t *testing.T) {
+	repo := newMockRunRepo()
+	uc := (&RunUsecase{runRepo: repo}).WithMaintenanceMode(func(ctx context.Context) bool { return true })
+
+	startStepID := uuid.New()
+	_, err := uc.Create(context.Background(), CreateRunInput{
+		TenantID:    uuid.New(),
+		ProjectID:   uuid.New(),
+		TriggeredBy: domain.TriggerTypeWebhook,
+		StartStepID: &startStepID,
+	})
+	if !errors.Is(err, domain.ErrMaintenanceMode) {
+		t.Fatalf("Create() error = %v, want ErrMaintenanceMode", err)
+	}
+	if len(repo.runs) != 0 {
+		t.Errorf("%d runs stored, want none in maintenance mode", len(repo.runs))
+	}
+}
+
+// queueDepthStub reports a fixed total depth and per-tenant depths
+func queueDepthStub(total int64, perTenant map[uuid.UUID]int64) func(ctx context.Context, tenantID uuid.UUID) (int64, int64, error) {
+	return func(ctx context.Context, tenantID uuid.UUID) (int64, int64, error) {
+		return total, perTenant[tenantID], nil
+	}
+}
+
+func createForTenant(uc *RunUsecase, tenantID uuid.UUID) error {
+	startStepID := uuid.New()
+	_, err := uc.Create(context.Background(), CreateRunInput{
+		TenantID:    tenantID,
+		ProjectID:   uuid.New(),
+		TriggeredBy: domain.TriggerTypeManual,
+		StartStepID: &startStepID,
+	})
+	return err
+}
+
+func TestRunUsecase_Create_QueueSaturated(t *testing.T) {
+	repo := newMockRunRepo()
+	uc := (&RunUsecase{runRepo: repo}).WithQueueLimits(100, 0)
+	uc.queueDepth = queueDepthStub(100, nil)
+
+	if err := createForTenant(uc, uuid.New()); !errors.Is(err, domain.ErrQueueSaturated) {
+		t.Fatalf("Create() error = %v, want ErrQueueSaturated", err)
+	}
+	if len(repo.runs) != 0 {
+		t.Errorf("%d runs stored, want none while the queue is saturated", len(repo.runs))
+	}
+}
+
+func TestRunUsecase_Create_TenantQueueSaturated(t *testing.T) {
+	busy, quiet := uuid.New(), uuid.New()
+	tests := []struct {
+		name       string
+		tenantRepo *limitsTenantRepo
+		depth      int64
+		wantErr    bool
+	}{
+		{name: "default tenant limit reached", depth: 10, wantErr: true},
+		{name: "below default tenant limit", depth: 9},
+		{name: "tenant limit override raises limit", tenantRepo: &limitsTenantRepo{limits: domain.TenantLimits{MaxQueuedRuns: 50}}, depth: 10},
+		{name: "tenant limit override lowers limit", tenantRepo: &limitsTenantRepo{limits: domain.TenantLimits{MaxQueuedRuns: 5}}, depth: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := (&RunUsecase{runRepo: newMockRunRepo()}).WithQueueLimits(0, 10)
+			if tt.tenantRepo != nil {
+				uc.tenantRepo = tt.tenantRepo
+			}
+			uc.queueDepth = queueDepthStub(tt.depth, map[uuid.UUID]int64{busy: tt.depth})
+
+			err := uc.checkQueueDepth(context.Background(), busy)
+			if tt.wantErr != errors.Is(err, domain.ErrTenantQueueSaturated) {
+				t.Fatalf("checkQueueDepth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if err := createForTenant(uc, busy); !errors.Is(err, domain.ErrTenantQueueSaturated) {
+					t.Fatalf("Create() error = %v, want ErrTenantQueueSaturated", err)
+				}
+			}
+
+			// Another tenant's runs are not held back by the busy tenant
+			if err := uc.checkQueueDepth(context.Background(), quiet); err != nil {
+				t.Errorf("checkQueueDepth() for another tenant = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRunUsecase_Create_QueueDepthUnavailable(t *testing.T) {
+	uc := (&RunUsecase{runRepo: newMockRunRepo()}).WithQueueLimits(1, 1)
+	uc.queueDepth = func(ctx context.Context, tenantID uuid.UUID) (int64, int64, error) {
+		return 0, 0, errors.New("redis: connection refused")
+	}
+
+	if err := uc.checkQueueDepth(context.Background(), uuid.New()); err != nil {
+		t.Errorf("checkQueueDepth() = %v, want nil when the depth cannot be read", err)
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// SearchRunsInput represents input for searching runs across a tenant's workflows
+type SearchRunsInput struct {
+	TenantID         uuid.UUID
+	ProjectID        *uuid.UUID
+	Status           *domain.RunStatus
+	CreatedFrom      *time.Time      // Inclusive
+	CreatedTo        *time.Time      // Exclusive
+	InputContains    json.RawMessage // JSON object/array that run input must contain
+	OutputContains   json.RawMessage // JSON object/array that run output must contain
+	MetadataContains json.RawMessage // JSON object/array that trigger metadata must contain
+	Cursor           string          // Opaque cursor returned as NextCursor by a previous search
+	Limit            int
+}
+
+// SearchRunsOutput represents output for searching runs
+type SearchRunsOutput struct {
+	Runs       []*domain.Run
+	NextCursor string // Empty when there are no more results
+	Limit      int
+}
+
+// Search finds runs across all workflows of a tenant.
+// Results are ordered newest first and paginated with an opaque keyset cursor.
+func (u *RunUsecase) Search(ctx context.Context, input SearchRunsInput) (*SearchRunsOutput, error) {
+	_, limit := NormalizePagination(1, input.Limit)
+
+	if input.CreatedFrom != nil && input.CreatedTo != nil && !input.CreatedFrom.Before(*input.CreatedTo) {
+		return nil, domain.NewValidationError("from", "from must be before to")
+	}
+
+	filter := repository.RunSearchFilter{
+		ProjectID:   input.ProjectID,
+		Status:      input.Status,
+		CreatedFrom: input.CreatedFrom,
+		CreatedTo:   input.CreatedTo,
+		Limit:       limit + 1, // Fetch one extra row to detect the next page
+	}
+
+	var err error
+	if filter.InputContains, err = normalizeContainment("input", input.InputContains); err != nil {
+		return nil, err
+	}
+	if filter.OutputContains, err = normalizeContainment("output", input.OutputContains); err != nil {
+		return nil, err
+	}
+	if filter.MetadataContains, err = normalizeContainment("metadata", input.MetadataContains); err != nil {
+		return nil, err
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeListCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &repository.RunSearchCursor{CreatedAt: cursor.Time, ID: cursor.ID}
+	}
+
+	runs, err := u.runRepo.Search(ctx, input.TenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &SearchRunsOutput{Runs: runs, Limit: limit}
+	if len(runs) > limit {
+		output.Runs = runs[:limit]
+		last := output.Runs[limit-1]
+		output.NextCursor = encodeListCursor(repository.ListCursor{Time: last.CreatedAt, ID: last.ID})
+	}
+	return output, nil
+}
+
+// normalizeContainment validates a JSON containment filter.
+// Only objects and arrays are accepted; scalars would match almost nothing useful with @>.
+func normalizeContainment(field string, raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" {
+		return nil, nil
+	}
+	if !json.Valid([]byte(trimmed)) {
+		return nil, domain.NewValidationError(field, "must be valid JSON")
+	}
+	if trimmed[0] != '{' && trimmed[0] != '[' {
+		return nil, domain.NewValidationError(field, "must be a JSON object or array")
+	}
+	return json.RawMessage(trimmed), nil
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// ============================================================================
+// Mock Repository for Run Search Tests
+// ============================================================================
+
+// mockRunRepo implements repository.RunRepository.
+// Search applies tenant, date-range, and cursor filters in memory and records the last filter.
+type mockRunRepo struct {
+	runs       map[uuid.UUID]*domain.Run
+	lastFilter repository.RunSearchFilter
+}
+
+func newMockRunRepo() *mockRunRepo {
+	return &mockRunRepo{runs: make(map[uuid.UUID]*domain.Run)}
+}
+
+func (m *mockRunRepo) Create(ctx context.Context, run *domain.Run) error {
+	m.runs[run.ID] = run
+	return nil
+}
+
+func (m *mockRunRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error) {
+	run, ok := m.runs[id]
+	if !ok || run.TenantID != tenantID {
+		return nil, domain.ErrRunNotFound
+	}
+	return run, nil
+}
+
+func (m *mockRunRepo) GetByIDs(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]*domain.Run, error) {
+	var result []*domain.Run
+	for _, id := range ids {
+		if run, err := m.GetByID(ctx, tenantID, id); err == nil {
+			result = append(result, run)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRunRepo) ListByProject(ctx context.Context, tenantID, projectID uuid.UUID, filter repository.RunFilter) ([]*domain.Run, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockRunRepo) ListByStartStep(ctx context.Context, tenantID, projectID, startStepID uuid.UUID, filter repository.RunFilter) ([]*domain.Run, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockRunRepo) Update(ctx context.Context, run *domain.Run) error {
+	m.runs[run.ID] = run
+	return nil
+}
+
+func (m *mockRunRepo) GetWithStepRuns(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error) {
+	return m.GetByID(ctx, tenantID, id)
+}
+
+func (m *mockRunRepo) ListReferencedStepIDs(ctx context.Context, projectID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (m *mockRunRepo) CountConsecutiveFailures(ctx context.Context, tenantID, projectID uuid.UUID) (int, error) {
+	var lastCompleted time.Time
+	for _, run := range m.runs {
+		if run.TenantID == tenantID && run.ProjectID == projectID && run.Status == domain.RunStatusCompleted && run.CompletedAt.After(lastCompleted) {
+			lastCompleted = *run.CompletedAt
+		}
+	}
+	count := 0
+	for _, run := range m.runs {
+		if run.TenantID == tenantID && run.ProjectID == projectID && run.Status == domain.RunStatusFailed && run.CompletedAt.After(lastCompleted) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockRunRepo) Search(ctx context.Context, tenantID uuid.UUID, filter repository.RunSearchFilter) ([]*domain.Run, error) {
+	m.lastFilter = filter
+	var result []*domain.Run
+	for _, run := range m.runs {
+		if run.TenantID != tenantID {
+			continue
+		}
+		if filter.CreatedFrom != nil && run.CreatedAt.Before(*filter.CreatedFrom) {
+			continue
+		}
+		if filter.CreatedTo != nil && !run.CreatedAt.Before(*filter.CreatedTo) {
+			continue
+		}
+		if filter.After != nil && !sortsAfter(run, filter.After) {
+			continue
+		}
+		result = append(result, run)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return sortsAfter(result[j], &repository.RunSearchCursor{CreatedAt: result[i].CreatedAt, ID: result[i].ID})
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// sortsAfter reports whether run sorts after the cursor in (created_at DESC, id DESC) order
+func sortsAfter(run *domain.Run, cursor *repository.RunSearchCursor) bool {
+	if !run.CreatedAt.Equal(cursor.CreatedAt) {
+		return run.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return run.ID.String() < cursor.ID.String()
+}
+
+// seedSearchRuns creates one run per day starting at base, oldest first
+func seedSearchRuns(repo *mockRunRepo, tenantID uuid.UUID, base time.Time, days int) []*domain.Run {
+	runs := make([]*domain.Run, days)
+	for i := 0; i < days; i++ {
+		run := &domain.Run{
+			ID:        uuid.New(),
+			TenantID:  tenantID,
+			ProjectID: uuid.New(),
+			Status:    domain.RunStatusCompleted,
+			CreatedAt: base.AddDate(0, 0, i),
+		}
+		repo.runs[run.ID] = run
+		runs[i] = run
+	}
+	return runs
+}
+
+// ============================================================================
+// Tests for Search
+// ============================================================================
+
+func TestRunUsecase_Search_DateRange(t *testing.T) {
+	tenantID := uuid.New()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newMockRunRepo()
+	runs := seedSearchRuns(repo, tenantID, base, 5) // Mar 1 .. Mar 5
+	seedSearchRuns(repo, uuid.New(), base, 5)       // other tenant
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	uc := &RunUsecase{runRepo: repo}
+	output, err := uc.Search(context.Background(), SearchRunsInput{
+		TenantID:    tenantID,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	// Only Mar 2 and Mar 3 of this tenant, newest first
+	if len(output.Runs) != 2 {
+		t.Fatalf("len(Runs) = %d, want 2", len(output.Runs))
+	}
+	if output.Runs[0].ID != runs[2].ID || output.Runs[1].ID != runs[1].ID {
+		t.Errorf("got runs created at %v, %v; want Mar 3, Mar 2", output.Runs[0].CreatedAt, output.Runs[1].CreatedAt)
+	}
+	if output.NextCursor != "" {
+		t.Errorf("NextCursor = %q, want empty", output.NextCursor)
+	}
+}
+
+func TestRunUsecase_Search_CursorPagination(t *testing.T) {
+	tenantID := uuid.New()
+	repo := newMockRunRepo()
+	runs := seedSearchRuns(repo, tenantID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 5)
+
+	uc := &RunUsecase{runRepo: repo}
+	var got []uuid.UUID
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		output, err := uc.Search(context.Background(), SearchRunsInput{TenantID: tenantID, Cursor: cursor, Limit: 2})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		for _, run := range output.Runs {
+			got = append(got, run.ID)
+		}
+		if output.NextCursor == "" {
+			break
+		}
+		cursor = output.NextCursor
+	}
+
+	if len(got) != len(runs) {
+		t.Fatalf("collected %d runs, want %d", len(got), len(runs))
+	}
+	for i, id := range got {
+		if want := runs[len(runs)-1-i].ID; id != want {
+			t.Errorf("run[%d] = %s, want %s", i, id, want)
+		}
+	}
+}
+
+func TestRunUsecase_Search_PassesContainmentFilters(t *testing.T) {
+	repo := newMockRunRepo()
+	uc := &RunUsecase{runRepo: repo}
+
+	_, err := uc.Search(context.Background(), SearchRunsInput{
+		TenantID:      uuid.New(),
+		InputContains: json.RawMessage(` {"customer":{"tier":"gold"}} `),
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := string(repo.lastFilter.InputContains); got != `{"customer":{"tier":"gold"}}` {
+		t.Errorf("InputContains = %q", got)
+	}
+	if repo.lastFilter.Limit != DefaultLimit+1 {
+		t.Errorf("Limit = %d, want %d (one extra row to detect next page)", repo.lastFilter.Limit, DefaultLimit+1)
+	}
+}
+
+func TestRunUsecase_Search_Validation(t *testing.T) {
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		input SearchRunsInput
+	}{
+		{name: "from after to", input: SearchRunsInput{CreatedFrom: &from, CreatedTo: &to}},
+		{name: "invalid JSON", input: SearchRunsInput{InputContains: json.RawMessage(`{"a":`)}},
+		{name: "scalar JSON", input: SearchRunsInput{OutputContains: json.RawMessage(`"done"`)}},
+		{name: "invalid cursor", input: SearchRunsInput{Cursor: "not-a-cursor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &RunUsecase{runRepo: newMockRunRepo()}
+			_, err := uc.Search(context.Background(), tt.input)
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Search() error = %v, want ValidationError", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +16,9 @@ type ScheduleUsecase struct {
 	scheduleRepo repository.ScheduleRepository
 	projectRepo  repository.ProjectRepository
 	runRepo      repository.RunRepository
+	locker       Locker // Optional; deduplicates due-schedule firing across instances
+
+	costEstimator CostEstimator // Optional; prices the runs of schedule previews
 }
 
 // NewScheduleUsecase creates a new ScheduleUsecase
@@ -53,10 +57,22 @@ func (u *ScheduleUsecase) Create(ctx context.Context, input CreateScheduleInput)
 		return nil, domain.NewValidationError("cron_expression", "cron expression is required")
 	}
 
-	// Validate cron expression
-	nextRun, err := ParseCron(input.CronExpression, input.Timezone)
+	// Fall back to the deployment default timezone
+	if input.Timezone == "" {
+		input.Timezone = domain.DefaultTimezone()
+	}
+	if err := domain.ValidateTimezone(input.Timezone); err != nil {
+		return nil, err
+	}
+
+	// Validate and normalize the cron expression
+	cronExpression, err := NormalizeCron(input.CronExpression)
 	if err != nil {
-		return nil, domain.ErrScheduleInvalidCron
+		return nil, err
+	}
+	nextRun, err := ParseCron(cronExpression, input.Timezone)
+	if err != nil {
+		return nil, err
 	}
 
 	// Verify project exists and is published
@@ -68,18 +84,13 @@ func (u *ScheduleUsecase) Create(ctx context.Context, input CreateScheduleInput)
 		return nil, domain.NewValidationError("project_id", "project must be published")
 	}
 
-	// Set default timezone
-	if input.Timezone == "" {
-		input.Timezone = "UTC"
-	}
-
 	schedule := domain.NewSchedule(
 		input.TenantID,
 		input.ProjectID,
 		input.StartStepID,
 		project.Version,
 		input.Name,
-		input.CronExpression,
+		cronExpression,
 		input.Timezone,
 		input.Input,
 	)
@@ -104,27 +115,33 @@ type ListSchedulesInput struct {
 	TenantID  uuid.UUID
 	ProjectID *uuid.UUID
 	Status    *domain.ScheduleStatus
+	Cursor    string // Opaque cursor returned as NextCursor by a previous page; replaces Page
 	Page      int
 	Limit     int
 }
 
 // ListSchedulesOutput represents output for listing schedules
 type ListSchedulesOutput struct {
-	Schedules []*domain.Schedule
-	Total     int
-	Page      int
-	Limit     int
+	Schedules  []*domain.Schedule
+	Total      int
+	Page       int
+	Limit      int
+	NextCursor string // Empty on the last page
 }
 
-// List lists schedules with pagination
+// List lists schedules, newest first, by page or keyset cursor
 func (u *ScheduleUsecase) List(ctx context.Context, input ListSchedulesInput) (*ListSchedulesOutput, error) {
-	input.Page, input.Limit = NormalizePagination(input.Page, input.Limit)
+	pagination, err := newListPagination(input.Page, input.Limit, DefaultLimit, input.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := repository.ScheduleFilter{
 		ProjectID: input.ProjectID,
 		Status:    input.Status,
-		Page:      input.Page,
-		Limit:     input.Limit,
+		After:     pagination.After,
+		Page:      pagination.Page,
+		Limit:     pagination.FetchLimit(),
 	}
 
 	schedules, total, err := u.scheduleRepo.ListByTenant(ctx, input.TenantID, filter)
@@ -132,12 +149,18 @@ func (u *ScheduleUsecase) List(ctx context.Context, input ListSchedulesInput) (*
 		return nil, err
 	}
 
-	return &ListSchedulesOutput{
-		Schedules: schedules,
+	n, more := pagination.Next(len(schedules), total)
+	output := &ListSchedulesOutput{
+		Schedules: schedules[:n],
 		Total:     total,
-		Page:      input.Page,
-		Limit:     input.Limit,
-	}, nil
+		Page:      pagination.Page,
+		Limit:     pagination.Limit,
+	}
+	if more {
+		last := output.Schedules[n-1]
+		output.NextCursor = encodeListCursor(repository.ListCursor{Time: last.CreatedAt, ID: last.ID})
+	}
+	return output, nil
 }
 
 // UpdateScheduleInput represents input for updating a schedule
@@ -164,17 +187,27 @@ func (u *ScheduleUsecase) Update(ctx context.Context, input UpdateScheduleInput)
 	}
 	schedule.Description = input.Description
 
+	if input.Timezone != "" {
+		if err := domain.ValidateTimezone(input.Timezone); err != nil {
+			return nil, err
+		}
+	}
+
 	if input.CronExpression != "" {
 		// Validate new cron expression
 		tz := input.Timezone
 		if tz == "" {
 			tz = schedule.Timezone
 		}
-		nextRun, err := ParseCron(input.CronExpression, tz)
+		cronExpression, err := NormalizeCron(input.CronExpression)
+		if err != nil {
+			return nil, err
+		}
+		nextRun, err := ParseCron(cronExpression, tz)
 		if err != nil {
-			return nil, domain.ErrScheduleInvalidCron
+			return nil, err
 		}
-		schedule.CronExpression = input.CronExpression
+		schedule.CronExpression = cronExpression
 		schedule.UpdateNextRun(nextRun)
 	}
 
@@ -243,12 +276,49 @@ func (u *ScheduleUsecase) Resume(ctx context.Context, tenantID, id uuid.UUID) (*
 	return schedule, nil
 }
 
-// TriggerSchedule manually triggers a schedule
+// PauseAll pauses every active schedule of a tenant and returns the IDs of the paused
+// schedules. Schedules that are already paused or disabled are left as they are, so ResumeAll
+// reactivates only the schedules paused here.
+func (u *ScheduleUsecase) PauseAll(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error) {
+	return u.scheduleRepo.PauseAll(ctx, tenantID)
+}
+
+// ResumeAll reactivates the schedules of a tenant paused by PauseAll, recalculating their next
+// run, and returns the IDs of the resumed schedules
+func (u *ScheduleUsecase) ResumeAll(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error) {
+	schedules, err := u.scheduleRepo.ListBulkPaused(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRuns := make(map[uuid.UUID]*time.Time, len(schedules))
+	for _, schedule := range schedules {
+		nextRun, _ := ParseCron(schedule.CronExpression, schedule.Timezone)
+		nextRuns[schedule.ID] = nextRun
+	}
+	return u.scheduleRepo.ResumeBulkPaused(ctx, tenantID, nextRuns)
+}
+
+// TriggerSchedule manually triggers a schedule. Manual triggers always create a run; only
+// due-schedule processing is deduplicated across instances (see WithLocker).
 func (u *ScheduleUsecase) Trigger(ctx context.Context, tenantID, id uuid.UUID) (*domain.Run, error) {
 	schedule, err := u.scheduleRepo.GetByID(ctx, tenantID, id)
 	if err != nil {
 		return nil, err
 	}
+	return u.trigger(ctx, schedule)
+}
+
+// trigger creates a run of the schedule and records it on the schedule. It returns
+// ErrProjectPaused without a run while the project is paused.
+func (u *ScheduleUsecase) trigger(ctx context.Context, schedule *domain.Schedule) (*domain.Run, error) {
+	project, err := u.projectRepo.GetByID(ctx, schedule.TenantID, schedule.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.Paused {
+		return nil, domain.ErrProjectPaused
+	}
 
 	// Create a new run
 	run := domain.NewRun(
@@ -258,6 +328,12 @@ func (u *ScheduleUsecase) Trigger(ctx context.Context, tenantID, id uuid.UUID) (
 		schedule.Input,
 		domain.TriggerTypeSchedule,
 	)
+	if err := run.SetTriggerSource("schedule", map[string]interface{}{
+		"schedule_id":   schedule.ID.String(),
+		"schedule_name": schedule.Name,
+	}); err != nil {
+		return nil, err
+	}
 
 	if err := u.runRepo.Create(ctx, run); err != nil {
 		return nil, err
@@ -283,8 +359,15 @@ func (u *ScheduleUsecase) ProcessDueSchedules(ctx context.Context, limit int) (i
 
 	processed := 0
 	for _, schedule := range schedules {
-		_, err := u.Trigger(ctx, schedule.TenantID, schedule.ID)
-		if err != nil {
+		run, err := u.fireDue(ctx, schedule)
+		if errors.Is(err, domain.ErrProjectPaused) {
+			// Skip the occurrence rather than firing it late once the project is resumed
+			nextRun, _ := ParseCron(schedule.CronExpression, schedule.Timezone)
+			schedule.UpdateNextRun(nextRun)
+			_ = u.scheduleRepo.Update(ctx, schedule)
+			continue
+		}
+		if err != nil || run == nil {
 			// Log error but continue processing other schedules
 			continue
 		}
@@ -293,17 +376,3 @@ func (u *ScheduleUsecase) ProcessDueSchedules(ctx context.Context, limit int) (i
 
 	return processed, nil
 }
-
-// ParseCron parses a cron expression and returns the next run time
-func ParseCron(expression, timezone string) (*time.Time, error) {
-	// Simple implementation - in production, use a proper cron parser like robfig/cron
-	loc, err := time.LoadLocation(timezone)
-	if err != nil {
-		loc = time.UTC
-	}
-
-	// For now, just return 1 minute from now as a placeholder
-	// In production, this should properly parse the cron expression
-	next := time.Now().In(loc).Add(time.Minute)
-	return &next, nil
-}
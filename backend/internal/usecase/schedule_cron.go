@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// MaxCronPreviewRuns caps the number of fire times NextCronRuns computes
+const MaxCronPreviewRuns = 50
+
+// scheduleCronParser parses the cron expressions of schedules: standard 5-field expressions or
+// 6-field expressions with a leading seconds field
+var scheduleCronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// cronMacros maps the supported @-macros to the 5-field expressions they stand for
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCronSchedule normalizes and parses a cron expression, returning *domain.InvalidCronError
+// when it is invalid
+func parseCronSchedule(expression string) (cron.Schedule, string, error) {
+	normalized, err := normalizeCronFields(expression)
+	if err != nil {
+		return nil, "", err
+	}
+	schedule, err := scheduleCronParser.Parse(normalized)
+	if err != nil {
+		return nil, "", &domain.InvalidCronError{Expression: expression, Reason: err.Error()}
+	}
+	return schedule, normalized, nil
+}
+
+// normalizeCronFields expands @-macros, upper-cases month and weekday names, collapses
+// whitespace and drops a zero seconds field, so equivalent expressions are stored alike
+func normalizeCronFields(expression string) (string, error) {
+	trimmed := strings.TrimSpace(expression)
+	if trimmed == "" {
+		return "", &domain.InvalidCronError{Expression: expression, Reason: "expression is empty"}
+	}
+	// TZ= and CRON_TZ= prefixes are rejected; schedules carry their timezone separately
+	if strings.HasPrefix(trimmed, "TZ=") || strings.HasPrefix(trimmed, "CRON_TZ=") {
+		return "", &domain.InvalidCronError{Expression: expression, Reason: "set the timezone with the timezone field instead of a TZ= prefix"}
+	}
+	if strings.HasPrefix(trimmed, "@") {
+		macro, ok := cronMacros[strings.ToLower(trimmed)]
+		if !ok {
+			return "", &domain.InvalidCronError{Expression: expression, Reason: fmt.Sprintf("unsupported macro %q (use @yearly, @monthly, @weekly, @daily or @hourly)", trimmed)}
+		}
+		return macro, nil
+	}
+
+	fields := strings.Fields(strings.ToUpper(trimmed))
+	if len(fields) == 6 && fields[0] == "0" {
+		fields = fields[1:]
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// NormalizeCron validates a cron expression and returns its normalized form: macros expanded to
+// 5 fields, names upper-cased, single spaces, and a zero seconds field dropped
+func NormalizeCron(expression string) (string, error) {
+	_, normalized, err := parseCronSchedule(expression)
+	return normalized, err
+}
+
+// ValidateCron checks that a cron expression can be scheduled
+func ValidateCron(expression string) error {
+	_, _, err := parseCronSchedule(expression)
+	return err
+}
+
+// NextCronRuns returns the next count fire times of a cron expression after from, evaluated in
+// the timezone. Times are returned in that timezone; count is capped at MaxCronPreviewRuns.
+func NextCronRuns(expression, timezone string, from time.Time, count int) ([]time.Time, error) {
+	schedule, _, err := parseCronSchedule(expression)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, domain.NewValidationError("timezone", fmt.Sprintf("unknown timezone %q", timezone))
+	}
+	if count > MaxCronPreviewRuns {
+		count = MaxCronPreviewRuns
+	}
+
+	runs := make([]time.Time, 0, count)
+	next := from.In(loc)
+	for len(runs) < count {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			// The expression never fires (e.g. February 30)
+			break
+		}
+		runs = append(runs, next)
+	}
+	return runs, nil
+}
+
+// ParseCron parses a cron expression and returns the next run time. An unknown timezone falls
+// back to UTC.
+func ParseCron(expression, timezone string) (*time.Time, error) {
+	schedule, _, err := parseCronSchedule(expression)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	next := schedule.Next(time.Now().In(loc))
+	if next.IsZero() {
+		return nil, &domain.InvalidCronError{Expression: expression, Reason: "expression never fires"}
+	}
+	return &next, nil
+}
+
+var (
+	cronMonthNames   = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	cronWeekdayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+)
+
+// DescribeCron returns an English description of a cron expression, such as
+// "At 09:00 on Monday through Friday"
+func DescribeCron(expression string) (string, error) {
+	normalized, err := NormalizeCron(expression)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(normalized)
+	for i, field := range fields {
+		if field == "?" {
+			fields[i] = "*"
+		}
+	}
+	second := "0"
+	if len(fields) == 6 {
+		second, fields = fields[0], fields[1:]
+	}
+	minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	var b strings.Builder
+	m, minuteErr := strconv.Atoi(minute)
+	h, hourErr := strconv.Atoi(hour)
+	sec, secondErr := strconv.Atoi(second)
+	atTime := minuteErr == nil && hourErr == nil
+	switch {
+	case atTime && sec == 0 && secondErr == nil:
+		fmt.Fprintf(&b, "At %02d:%02d", h, m)
+	case atTime && secondErr == nil:
+		fmt.Fprintf(&b, "At %02d:%02d:%02d", h, m, sec)
+	case second != "0":
+		seconds := describeCronField(second, "second", nil)
+		if !strings.HasPrefix(seconds, "every") {
+			seconds = "at " + seconds + " of every minute"
+		}
+		b.WriteString(capitalize(seconds))
+		if minute == "0" && hour == "*" {
+			b.WriteString(", at minute 0 past every hour")
+		} else if minute != "*" || hour != "*" {
+			fmt.Fprintf(&b, ", %s", lowerFirst(describeCronTime(minute, hour)))
+		}
+	default:
+		b.WriteString(describeCronTime(minute, hour))
+	}
+
+	switch {
+	case dom != "*" && dow != "*":
+		// Both day fields restricted: the expression fires when either matches
+		fmt.Fprintf(&b, " on %s of the month or on %s", describeCronField(dom, "day", nil), describeCronField(dow, "day", cronWeekdayNames))
+	case dom != "*":
+		fmt.Fprintf(&b, " on %s of the month", describeCronField(dom, "day", nil))
+	case dow != "*":
+		fmt.Fprintf(&b, " on %s", describeCronField(dow, "day", cronWeekdayNames))
+	case atTime:
+		b.WriteString(", every day")
+	}
+
+	if month != "*" {
+		fmt.Fprintf(&b, " in %s", describeCronField(month, "month", cronMonthNames))
+	}
+	return b.String(), nil
+}
+
+// describeCronTime describes the minute and hour fields of an expression that does not fire at
+// a single time of day
+func describeCronTime(minute, hour string) string {
+	switch {
+	case minute == "0" && hour == "*":
+		return "Every hour"
+	case minute == "*" || strings.HasPrefix(minute, "*/"):
+		text := capitalize(describeCronField(minute, "minute", nil))
+		if hour != "*" {
+			text += " during " + describeCronField(hour, "hour", nil)
+		}
+		return text
+	default:
+		return fmt.Sprintf("At %s past %s", describeCronField(minute, "minute", nil), describeCronField(hour, "hour", nil))
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// describeCronField describes one cron field. unit names the values ("hour 9 through 17"), or
+// names replace them ("Monday through Friday").
+func describeCronField(field, unit string, names []string) string {
+	if field == "*" {
+		return "every " + unit
+	}
+	if strings.HasPrefix(field, "*/") {
+		return fmt.Sprintf("every %s %ss", strings.TrimPrefix(field, "*/"), unit)
+	}
+
+	items := strings.Split(field, ",")
+	stepped := false
+	for i, item := range items {
+		base, step, hasStep := strings.Cut(item, "/")
+		text := base
+		if low, high, isRange := strings.Cut(base, "-"); isRange {
+			text = cronValueName(low, names) + " through " + cronValueName(high, names)
+		} else if base != "*" {
+			text = cronValueName(base, names)
+		}
+		if hasStep {
+			text = fmt.Sprintf("every %s %ss from %s", step, unit, text)
+			stepped = true
+		}
+		items[i] = text
+	}
+
+	text := items[0]
+	if len(items) > 1 {
+		text = strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	}
+	if names == nil && !stepped {
+		text = unit + " " + text
+	}
+	return text
+}
+
+// cronValueName returns the name of a numeric or three-letter cron value, or the value itself
+// when there are no names
+func cronValueName(value string, names []string) string {
+	if names == nil {
+		return value
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		if n >= 0 && n < len(names) {
+			return names[n]
+		}
+		return value
+	}
+	for _, name := range names {
+		if name != "" && strings.EqualFold(name[:3], value) {
+			return name
+		}
+	}
+	return value
+}
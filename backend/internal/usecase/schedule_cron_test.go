@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+func TestValidateCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		valid      bool
+	}{
+		{expression: "0 9 * * *", valid: true},
+		{expression: "*/15 9-17 * * 1-5", valid: true},
+		{expression: "0 0 1,15 * *", valid: true},
+		{expression: "30 6 * JAN,JUL MON", valid: true},
+		{expression: "", valid: false},
+		{expression: "0 9 * *", valid: false},
+		{expression: "60 9 * * *", valid: false},
+		{expression: "0 25 * * *", valid: false},
+		{expression: "0 9 * * MON-FOO", valid: false},
+		{expression: "every day", valid: false},
+		{expression: "CRON_TZ=Asia/Tokyo 0 9 * * *", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			err := ValidateCron(tt.expression)
+			if tt.valid {
+				if err != nil {
+					t.Errorf("ValidateCron() error = %v, want nil", err)
+				}
+				return
+			}
+			var cronErr *domain.InvalidCronError
+			if !errors.As(err, &cronErr) {
+				t.Fatalf("ValidateCron() error = %v, want InvalidCronError", err)
+			}
+			if cronErr.Reason == "" {
+				t.Errorf("InvalidCronError has no reason")
+			}
+		})
+	}
+}
+
+func TestNextCronRuns_Timezone(t *testing.T) {
+	from := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timezone string
+		want     []time.Time
+	}{
+		{
+			name:     "UTC",
+			timezone: "UTC",
+			want: []time.Time{
+				time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "Asia/Tokyo is UTC+9",
+			timezone: "Asia/Tokyo",
+			want: []time.Time{
+				time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "America/New_York fires later the same day",
+			timezone: "America/New_York",
+			want: []time.Time{
+				time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := NextCronRuns("0 9 * * *", tt.timezone, from, len(tt.want))
+			if err != nil {
+				t.Fatalf("NextCronRuns() error = %v", err)
+			}
+			if len(runs) != len(tt.want) {
+				t.Fatalf("NextCronRuns() = %v, want %v", runs, tt.want)
+			}
+			for i := range runs {
+				if !runs[i].Equal(tt.want[i]) {
+					t.Errorf("run %d = %v, want %v", i, runs[i].UTC(), tt.want[i])
+				}
+				if runs[i].Location().String() != tt.timezone {
+					t.Errorf("run %d location = %v, want %s", i, runs[i].Location(), tt.timezone)
+				}
+			}
+		})
+	}
+
+	// 09:00 in New York is 14:00 UTC before and 13:00 UTC after the switch to daylight time
+	runs, err := NextCronRuns("0 9 * * *", "America/New_York", time.Date(2026, 3, 7, 15, 0, 0, 0, time.UTC), 2)
+	if err != nil {
+		t.Fatalf("NextCronRuns() error = %v", err)
+	}
+	if want := time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC); !runs[0].Equal(want) {
+		t.Errorf("first run after DST = %v, want %v", runs[0].UTC(), want)
+	}
+}
+
+func TestNextCronRuns_Errors(t *testing.T) {
+	from := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := NextCronRuns("0 9 * * *", "Mars/Olympus_Mons", from, 1); !errors.As(err, new(domain.ValidationError)) {
+		t.Errorf("unknown timezone error = %v, want ValidationError", err)
+	}
+	if _, err := NextCronRuns("0 9 * *", "UTC", from, 1); !errors.Is(err, domain.ErrScheduleInvalidCron) {
+		t.Errorf("invalid expression error = %v, want ErrScheduleInvalidCron", err)
+	}
+
+	runs, err := NextCronRuns("0 0 30 2 *", "UTC", from, 3)
+	if err != nil {
+		t.Fatalf("NextCronRuns() error = %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("February 30 fires at %v, want never", runs)
+	}
+}
+
+func TestDescribeCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{expression: "0 9 * * *", want: "At 09:00, every day"},
+		{expression: "30 18 * * 1-5", want: "At 18:30 on Monday through Friday"},
+		{expression: "* * * * *", want: "Every minute"},
+		{expression: "*/15 * * * *", want: "Every 15 minutes"},
+		{expression: "*/15 9-17 * * *", want: "Every 15 minutes during hour 9 through 17"},
+		{expression: "0 * * * *", want: "Every hour"},
+		{expression: "0 */2 * * *", want: "At minute 0 past every 2 hours"},
+		{expression: "0 0 1,15 * *", want: "At 00:00 on day 1 and 15 of the month"},
+		{expression: "0 8 * JAN,JUL MON", want: "At 08:00 on Monday in January and July"},
+		{expression: "0 12 1 * SUN", want: "At 12:00 on day 1 of the month or on Sunday"},
+		{expression: "@weekly", want: "At 00:00 on Sunday"},
+		{expression: "30 0 9 * * *", want: "At 09:00:30, every day"},
+		{expression: "*/10 * * * * *", want: "Every 10 seconds"},
+		{expression: "15 * * * * *", want: "At second 15 of every minute"},
+		{expression: "*/10 * 9 * * MON", want: "Every 10 seconds, every minute during hour 9 on Monday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := DescribeCron(tt.expression)
+			if err != nil {
+				t.Fatalf("DescribeCron() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DescribeCron() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{expression: "0 9 * * *", want: "0 9 * * *"},
+		{expression: "  0  9 *\t* * ", want: "0 9 * * *"},
+		{expression: "0 8 * jan,jul mon", want: "0 8 * JAN,JUL MON"},
+		{expression: "0 0 9 * * *", want: "0 9 * * *"},
+		{expression: "30 0 9 * * *", want: "30 0 9 * * *"},
+		{expression: "@yearly", want: "0 0 1 1 *"},
+		{expression: "@annually", want: "0 0 1 1 *"},
+		{expression: "@monthly", want: "0 0 1 * *"},
+		{expression: "@weekly", want: "0 0 * * 0"},
+		{expression: "@daily", want: "0 0 * * *"},
+		{expression: "@midnight", want: "0 0 * * *"},
+		{expression: "@HOURLY", want: "0 * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := NormalizeCron(tt.expression)
+			if err != nil {
+				t.Fatalf("NormalizeCron() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeCron() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"@every 1h", "@reboot", "@fortnightly", "0 0 0 9 * * *", "60 0 9 * * *"} {
+		if _, err := NormalizeCron(invalid); !errors.Is(err, domain.ErrScheduleInvalidCron) {
+			t.Errorf("NormalizeCron(%q) error = %v, want ErrScheduleInvalidCron", invalid, err)
+		}
+	}
+}
+
+func TestNextCronRuns_Macros(t *testing.T) {
+	// Saturday, 2026-03-14 10:30 UTC
+	from := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		macro string
+		want  []time.Time
+	}{
+		{macro: "@yearly", want: []time.Time{time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{macro: "@monthly", want: []time.Time{time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)}},
+		{macro: "@weekly", want: []time.Time{time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)}},
+		{macro: "@daily", want: []time.Time{time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)}},
+		{macro: "@hourly", want: []time.Time{time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC), time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.macro, func(t *testing.T) {
+			runs, err := NextCronRuns(tt.macro, "UTC", from, len(tt.want))
+			if err != nil {
+				t.Fatalf("NextCronRuns() error = %v", err)
+			}
+			if len(runs) != len(tt.want) {
+				t.Fatalf("NextCronRuns() = %v, want %v", runs, tt.want)
+			}
+			for i := range runs {
+				if !runs[i].Equal(tt.want[i]) {
+					t.Errorf("run %d = %v, want %v", i, runs[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextCronRuns_Seconds(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 30, 5, 0, time.UTC)
+
+	runs, err := NextCronRuns("*/20 * * * * *", "UTC", from, 4)
+	if err != nil {
+		t.Fatalf("NextCronRuns() error = %v", err)
+	}
+	want := []time.Time{
+		time.Date(2026, 3, 14, 10, 30, 20, 0, time.UTC),
+		time.Date(2026, 3, 14, 10, 30, 40, 0, time.UTC),
+		time.Date(2026, 3, 14, 10, 31, 0, 0, time.UTC),
+		time.Date(2026, 3, 14, 10, 31, 20, 0, time.UTC),
+	}
+	for i := range want {
+		if i >= len(runs) || !runs[i].Equal(want[i]) {
+			t.Fatalf("NextCronRuns() = %v, want %v", runs, want)
+		}
+	}
+
+	runs, err = NextCronRuns("30 0 9 * * *", "UTC", from, 1)
+	if err != nil {
+		t.Fatalf("NextCronRuns() error = %v", err)
+	}
+	if want := time.Date(2026, 3, 15, 9, 0, 30, 0, time.UTC); len(runs) != 1 || !runs[0].Equal(want) {
+		t.Errorf("NextCronRuns() = %v, want [%v]", runs, want)
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// scheduleFireLockTTL is how long a fired occurrence stays locked. The lock is kept after a
+// successful fire so that instances which listed the schedule before it was updated skip the
+// occurrence, and it expires well before a schedule could be due at the same time again.
+const scheduleFireLockTTL = 10 * time.Minute
+
+// Locker acquires distributed locks shared by all instances of the service
+type Locker interface {
+	// TryLock acquires the lock without waiting; acquired is false when it is already held
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(context.Context) error, acquired bool, err error)
+}
+
+// WithLocker sets the distributed lock used to fire each due schedule occurrence exactly once
+// when several instances process due schedules
+func (u *ScheduleUsecase) WithLocker(locker Locker) *ScheduleUsecase {
+	u.locker = locker
+	return u
+}
+
+// scheduleFireLockKey identifies one occurrence of a schedule
+func scheduleFireLockKey(schedule *domain.Schedule) string {
+	var fireAt int64
+	if schedule.NextRunAt != nil {
+		fireAt = schedule.NextRunAt.Unix()
+	}
+	return fmt.Sprintf("aio:schedules:fire:%s:%d", schedule.ID, fireAt)
+}
+
+// fireDue triggers a due schedule unless another instance already fired the same occurrence,
+// in which case it returns a nil run. Without a locker every call triggers the schedule.
+func (u *ScheduleUsecase) fireDue(ctx context.Context, schedule *domain.Schedule) (*domain.Run, error) {
+	if u.locker == nil {
+		return u.trigger(ctx, schedule)
+	}
+
+	release, acquired, err := u.locker.TryLock(ctx, scheduleFireLockKey(schedule), scheduleFireLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, nil
+	}
+
+	run, err := u.trigger(ctx, schedule)
+	if err != nil {
+		// Let another instance (or the next poll) retry the occurrence
+		if releaseErr := release(context.WithoutCancel(ctx)); releaseErr != nil {
+			// The occurrence stays locked, and is not retried, until the lock expires
+			slog.Warn("Failed to release schedule fire lock",
+				"schedule_id", schedule.ID, "ttl", scheduleFireLockTTL, "error", releaseErr)
+		}
+		return nil, err
+	}
+	return run, nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// memLocker is an in-process Locker shared by the simulated instances
+type memLocker struct {
+	mu    sync.Mutex
+	held  map[string]bool
+	calls int
+}
+
+func newMemLocker() *memLocker {
+	return &memLocker{held: make(map[string]bool)}
+}
+
+func (l *memLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func(context.Context) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+		return nil
+	}, true, nil
+}
+
+// dueScheduleRepo returns the same due schedules to every caller, like concurrent polls would
+type dueScheduleRepo struct {
+	repository.ScheduleRepository
+	due []*domain.Schedule
+}
+
+func (r *dueScheduleRepo) GetDueSchedules(ctx context.Context, limit int) ([]*domain.Schedule, error) {
+	// Each instance reads its own copy of the rows
+	schedules := make([]*domain.Schedule, len(r.due))
+	for i, schedule := range r.due {
+		copied := *schedule
+		schedules[i] = &copied
+	}
+	return schedules, nil
+}
+
+func (r *dueScheduleRepo) Update(ctx context.Context, schedule *domain.Schedule) error {
+	return nil
+}
+
+// countingRunRepo records created runs and can fail the first creations
+type countingRunRepo struct {
+	repository.RunRepository
+	mu       sync.Mutex
+	created  int
+	failures int
+}
+
+func (r *countingRunRepo) Create(ctx context.Context, run *domain.Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("database unavailable")
+	}
+	r.created++
+	return nil
+}
+
+func newDueSchedule() *domain.Schedule {
+	fireAt := time.Now().Add(-time.Second).UTC()
+	return &domain.Schedule{
+		ID:             uuid.New(),
+		TenantID:       uuid.New(),
+		ProjectID:      uuid.New(),
+		CronExpression: "* * * * *",
+		Timezone:       "UTC",
+		Status:         domain.ScheduleStatusActive,
+		NextRunAt:      &fireAt,
+	}
+}
+
+// projectRepoFor holds the projects of the schedules
+func projectRepoFor(schedules ...*domain.Schedule) *mockProjectRepo {
+	repo := newMockProjectRepo()
+	for _, schedule := range schedules {
+		project := domain.NewProject(schedule.TenantID, "Scheduled", "")
+		project.ID = schedule.ProjectID
+		project.Status = domain.ProjectStatusPublished
+		repo.projects[project.ID] = project
+	}
+	return repo
+}
+
+func TestProcessDueSchedules_ConcurrentInstancesFireOnce(t *testing.T) {
+	scheduleRepo := &dueScheduleRepo{due: []*domain.Schedule{newDueSchedule()}}
+	projectRepo := projectRepoFor(scheduleRepo.due...)
+	runRepo := &countingRunRepo{}
+	locker := newMemLocker()
+
+	const instances = 2
+	processed := make([]int, instances)
+	var wg sync.WaitGroup
+	for i := 0; i < instances; i++ {
+		uc := NewScheduleUsecase(scheduleRepo, projectRepo, runRepo).WithLocker(locker)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			n, err := uc.ProcessDueSchedules(context.Background(), 10)
+			if err != nil {
+				t.Errorf("ProcessDueSchedules() error = %v", err)
+			}
+			processed[i] = n
+		}(i)
+	}
+	wg.Wait()
+
+	if runRepo.created != 1 {
+		t.Errorf("created %d runs, want exactly 1", runRepo.created)
+	}
+	if processed[0]+processed[1] != 1 {
+		t.Errorf("processed = %v, want the occurrence counted by one instance", processed)
+	}
+	if locker.calls != instances {
+		t.Errorf("TryLock called %d times, want %d", locker.calls, instances)
+	}
+}
+
+func TestProcessDueSchedules_FailedFireReleasesLock(t *testing.T) {
+	scheduleRepo := &dueScheduleRepo{due: []*domain.Schedule{newDueSchedule()}}
+	runRepo := &countingRunRepo{failures: 1}
+	uc := NewScheduleUsecase(scheduleRepo, projectRepoFor(scheduleRepo.due...), runRepo).WithLocker(newMemLocker())
+
+	if n, _ := uc.ProcessDueSchedules(context.Background(), 10); n != 0 {
+		t.Fatalf("first poll processed %d, want 0 after the failure", n)
+	}
+	if n, _ := uc.ProcessDueSchedules(context.Background(), 10); n != 1 {
+		t.Errorf("second poll processed %d, want the occurrence retried", n)
+	}
+	if runRepo.created != 1 {
+		t.Errorf("created %d runs, want 1", runRepo.created)
+	}
+}
+
+func TestProcessDueSchedules_NextOccurrenceUsesNewLock(t *testing.T) {
+	schedule := newDueSchedule()
+	scheduleRepo := &dueScheduleRepo{due: []*domain.Schedule{schedule}}
+	runRepo := &countingRunRepo{}
+	uc := NewScheduleUsecase(scheduleRepo, projectRepoFor(schedule), runRepo).WithLocker(newMemLocker())
+
+	uc.ProcessDueSchedules(context.Background(), 10)
+	next := schedule.NextRunAt.Add(time.Minute)
+	schedule.NextRunAt = &next
+	uc.ProcessDueSchedules(context.Background(), 10)
+
+	if runRepo.created != 2 {
+		t.Errorf("created %d runs, want one per occurrence", runRepo.created)
+	}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+const (
+	// DefaultSchedulePreviewDays is the length of the preview range when no end is given
+	DefaultSchedulePreviewDays = 30
+	// MaxSchedulePreviewDays caps the length of the preview range
+	MaxSchedulePreviewDays = 366
+	// DefaultSchedulePreviewFireTimes is the number of fire times a preview lists by default
+	DefaultSchedulePreviewFireTimes = 100
+	// MaxSchedulePreviewFireTimes caps the number of fire times a preview lists
+	MaxSchedulePreviewFireTimes = 1000
+	// MaxSchedulePreviewRunCount caps the runs a preview counts, so per-second schedules over
+	// long ranges stay cheap to preview
+	MaxSchedulePreviewRunCount = 100000
+	// highFrequencyRunsPerDay is the daily run count above which a preview warns
+	highFrequencyRunsPerDay = 24 * 12
+)
+
+// CostEstimator estimates the cost of one run of a workflow, such as ProjectUsecase
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, tenantID, projectID uuid.UUID, input json.RawMessage) (*CostEstimate, error)
+}
+
+// WithCostEstimator sets the estimator that prices the runs of schedule previews
+func (u *ScheduleUsecase) WithCostEstimator(estimator CostEstimator) *ScheduleUsecase {
+	u.costEstimator = estimator
+	return u
+}
+
+// PreviewScheduleInput represents input for previewing a schedule before creating it
+type PreviewScheduleInput struct {
+	TenantID       uuid.UUID
+	CronExpression string
+	Timezone       string     // Defaults to the deployment default timezone
+	Start          *time.Time // Defaults to now
+	End            *time.Time // Defaults to DefaultSchedulePreviewDays after Start
+	Limit          int        // Fire times to list, defaults to DefaultSchedulePreviewFireTimes
+	ProjectID      *uuid.UUID // Optional: prices the runs with the workflow's cost estimate
+	Input          json.RawMessage
+}
+
+// SchedulePreview lists when a cron expression fires within a range and what the runs would
+// cost. The cost is the workflow's per-run estimate times the run count.
+type SchedulePreview struct {
+	CronExpression     string      `json:"cron_expression"` // Normalized as a schedule stores it
+	Description        string      `json:"description"`
+	Timezone           string      `json:"timezone"`
+	Start              time.Time   `json:"start"`
+	End                time.Time   `json:"end"`
+	FireTimes          []time.Time `json:"fire_times"`
+	FireTimesTruncated bool        `json:"fire_times_truncated"` // More runs than listed
+	RunCount           int         `json:"run_count"`
+	RunCountCapped     bool        `json:"run_count_capped"` // Counting stopped at MaxSchedulePreviewRunCount
+	RunsPerDay         float64     `json:"runs_per_day"`
+	CostPerRunUSD      *CostRange  `json:"cost_per_run_usd,omitempty"`
+	EstimatedCostUSD   *CostRange  `json:"estimated_cost_usd,omitempty"`
+	Warnings           []string    `json:"warnings"`
+}
+
+// Preview computes the fire times of a cron expression in [Start, End) and, when a project is
+// given and a cost estimator is set, the estimated cost of the runs. Nothing is created.
+func (u *ScheduleUsecase) Preview(ctx context.Context, input PreviewScheduleInput) (*SchedulePreview, error) {
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = domain.DefaultTimezone()
+	}
+	if err := domain.ValidateTimezone(timezone); err != nil {
+		return nil, err
+	}
+	loc, _ := time.LoadLocation(timezone)
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = DefaultSchedulePreviewFireTimes
+	}
+	if limit < 1 || limit > MaxSchedulePreviewFireTimes {
+		return nil, domain.NewValidationError("limit", fmt.Sprintf("limit must be between 1 and %d", MaxSchedulePreviewFireTimes))
+	}
+
+	start := time.Now()
+	if input.Start != nil {
+		start = *input.Start
+	}
+	end := start.AddDate(0, 0, DefaultSchedulePreviewDays)
+	if input.End != nil {
+		end = *input.End
+	}
+	if !end.After(start) {
+		return nil, domain.NewValidationError("end", "end must be after start")
+	}
+	if end.Sub(start) > MaxSchedulePreviewDays*24*time.Hour {
+		return nil, domain.NewValidationError("end", fmt.Sprintf("the range must not exceed %d days", MaxSchedulePreviewDays))
+	}
+
+	schedule, normalized, err := parseCronSchedule(input.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+	description, _ := DescribeCron(input.CronExpression)
+
+	preview := &SchedulePreview{
+		CronExpression: normalized,
+		Description:    description,
+		Timezone:       timezone,
+		Start:          start.In(loc),
+		End:            end.In(loc),
+		FireTimes:      []time.Time{},
+		Warnings:       []string{},
+	}
+	// Next returns times strictly after its argument, so start one nanosecond early to include a
+	// fire time at start
+	for next := schedule.Next(start.In(loc).Add(-time.Nanosecond)); !next.IsZero() && next.Before(end); next = schedule.Next(next) {
+		if preview.RunCount == MaxSchedulePreviewRunCount {
+			preview.RunCountCapped = true
+			break
+		}
+		preview.RunCount++
+		if len(preview.FireTimes) < limit {
+			preview.FireTimes = append(preview.FireTimes, next)
+		}
+	}
+	preview.FireTimesTruncated = preview.RunCount > len(preview.FireTimes)
+	preview.RunsPerDay = float64(preview.RunCount) / end.Sub(start).Hours() * 24
+
+	if preview.RunCountCapped {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("the schedule fires more than %d times in the range; the run count and cost are lower bounds", MaxSchedulePreviewRunCount))
+	}
+	if preview.RunsPerDay > highFrequencyRunsPerDay {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("the schedule fires about %.0f times a day", preview.RunsPerDay))
+	}
+
+	if input.ProjectID != nil && u.costEstimator != nil {
+		estimate, err := u.costEstimator.EstimateCost(ctx, input.TenantID, *input.ProjectID, input.Input)
+		if err != nil {
+			return nil, err
+		}
+		runs := float64(preview.RunCount)
+		preview.CostPerRunUSD = &estimate.CostUSD
+		preview.EstimatedCostUSD = &CostRange{Min: estimate.CostUSD.Min * runs, Max: estimate.CostUSD.Max * runs}
+		preview.Warnings = append(preview.Warnings, estimate.Warnings...)
+	}
+	return preview, nil
+}
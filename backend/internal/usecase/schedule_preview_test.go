@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+)
+
+// fixedCostEstimator estimates the same cost for every run
+type fixedCostEstimator struct {
+	cost      CostRange
+	projectID uuid.UUID
+}
+
+func (e *fixedCostEstimator) EstimateCost(ctx context.Context, tenantID, projectID uuid.UUID, input json.RawMessage) (*CostEstimate, error) {
+	if projectID != e.projectID {
+		return nil, domain.ErrProjectNotFound
+	}
+	return &CostEstimate{ProjectID: projectID, CostUSD: e.cost, Warnings: []string{}}, nil
+}
+
+func TestScheduleUsecase_Preview_FireTimes(t *testing.T) {
+	uc := NewScheduleUsecase(nil, nil, nil)
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	// Monday 2026-10-12 09:00 in Tokyo, so the first fire time is at the start of the range
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, tokyo)
+	end := start.AddDate(0, 0, 7)
+
+	preview, err := uc.Preview(context.Background(), PreviewScheduleInput{
+		CronExpression: "0 9 * * 1-5",
+		Timezone:       "Asia/Tokyo",
+		Start:          &start,
+		End:            &end,
+	})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	if preview.RunCount != 5 || len(preview.FireTimes) != 5 || preview.FireTimesTruncated {
+		t.Fatalf("Preview() run count = %d with %d fire times (truncated %v), want 5 weekday runs", preview.RunCount, len(preview.FireTimes), preview.FireTimesTruncated)
+	}
+	for i, fireTime := range preview.FireTimes {
+		want := start.AddDate(0, 0, i)
+		if !fireTime.Equal(want) || fireTime.Location().String() != "Asia/Tokyo" {
+			t.Errorf("fire time %d = %v, want %v in Asia/Tokyo", i, fireTime, want)
+		}
+	}
+	if preview.Description != "At 09:00 on Monday through Friday" {
+		t.Errorf("Description = %q", preview.Description)
+	}
+	if preview.CostPerRunUSD != nil || preview.EstimatedCostUSD != nil {
+		t.Errorf("a preview without a project has no cost, got %v", preview.EstimatedCostUSD)
+	}
+}
+
+func TestScheduleUsecase_Preview_RunCountAndCost(t *testing.T) {
+	projectID := uuid.New()
+	uc := NewScheduleUsecase(nil, nil, nil).WithCostEstimator(&fixedCostEstimator{projectID: projectID, cost: CostRange{Min: 0.01, Max: 0.04}})
+	start := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	preview, err := uc.Preview(context.Background(), PreviewScheduleInput{
+		CronExpression: "*/15 * * * *",
+		Timezone:       "UTC",
+		Start:          &start,
+		End:            &end,
+		Limit:          10,
+		ProjectID:      &projectID,
+	})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	if preview.RunCount != 96 || preview.RunsPerDay != 96 {
+		t.Errorf("RunCount = %d, RunsPerDay = %v, want 96 runs in a day", preview.RunCount, preview.RunsPerDay)
+	}
+	if len(preview.FireTimes) != 10 || !preview.FireTimesTruncated {
+		t.Errorf("listed %d fire times (truncated %v), want the first 10", len(preview.FireTimes), preview.FireTimesTruncated)
+	}
+	if !preview.FireTimes[1].Equal(start.Add(15 * time.Minute)) {
+		t.Errorf("second fire time = %v, want 00:15", preview.FireTimes[1])
+	}
+	if preview.EstimatedCostUSD == nil {
+		t.Fatal("EstimatedCostUSD is nil, want 96 runs x [0.01, 0.04]")
+	}
+	assertCostClose(t, "EstimatedCostUSD.Min", preview.EstimatedCostUSD.Min, 0.96)
+	assertCostClose(t, "EstimatedCostUSD.Max", preview.EstimatedCostUSD.Max, 3.84)
+	if len(preview.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none for a quarter-hourly schedule", preview.Warnings)
+	}
+}
+
+func TestScheduleUsecase_Preview_HighFrequency(t *testing.T) {
+	uc := NewScheduleUsecase(nil, nil, nil)
+	start := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+
+	preview, err := uc.Preview(context.Background(), PreviewScheduleInput{CronExpression: "* * * * * *", Timezone: "UTC", Start: &start, End: &end})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if preview.RunCount != MaxSchedulePreviewRunCount || !preview.RunCountCapped {
+		t.Errorf("RunCount = %d (capped %v), want the count capped at %d", preview.RunCount, preview.RunCountCapped, MaxSchedulePreviewRunCount)
+	}
+	if len(preview.FireTimes) != DefaultSchedulePreviewFireTimes {
+		t.Errorf("listed %d fire times, want %d", len(preview.FireTimes), DefaultSchedulePreviewFireTimes)
+	}
+	if len(preview.Warnings) != 2 {
+		t.Errorf("Warnings = %v, want the capped count and the frequency warnings", preview.Warnings)
+	}
+}
+
+func TestScheduleUsecase_Preview_Invalid(t *testing.T) {
+	uc := NewScheduleUsecase(nil, nil, nil)
+	start := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+	tooLate := start.AddDate(0, 0, MaxSchedulePreviewDays+1)
+
+	tests := []struct {
+		name  string
+		input PreviewScheduleInput
+		field string
+	}{
+		{"end before start", PreviewScheduleInput{CronExpression: "0 * * * *", Start: &start, End: &before}, "end"},
+		{"range too long", PreviewScheduleInput{CronExpression: "0 * * * *", Start: &start, End: &tooLate}, "end"},
+		{"unknown timezone", PreviewScheduleInput{CronExpression: "0 * * * *", Timezone: "Mars/Olympus"}, "timezone"},
+		{"limit too large", PreviewScheduleInput{CronExpression: "0 * * * *", Limit: MaxSchedulePreviewFireTimes + 1}, "limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Preview(context.Background(), tt.input)
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Preview() error = %v, want a validation error of %s", err, tt.field)
+			}
+		})
+	}
+
+	_, err := uc.Preview(context.Background(), PreviewScheduleInput{CronExpression: "61 * * * *"})
+	var cronErr *domain.InvalidCronError
+	if !errors.As(err, &cronErr) {
+		t.Errorf("Preview() with an invalid cron error = %v, want InvalidCronError", err)
+	}
+}
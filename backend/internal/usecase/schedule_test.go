@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// createdScheduleRepo records the schedules passed to Create
+type createdScheduleRepo struct {
+	repository.ScheduleRepository
+	created []*domain.Schedule
+}
+
+func (r *createdScheduleRepo) Create(ctx context.Context, schedule *domain.Schedule) error {
+	r.created = append(r.created, schedule)
+	return nil
+}
+
+func newScheduleUsecaseForCreateTests(tenantID uuid.UUID) (*ScheduleUsecase, *createdScheduleRepo, *domain.Project) {
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(tenantID, "Daily Report", "")
+	project.Status = domain.ProjectStatusPublished
+	projectRepo.projects[project.ID] = project
+
+	scheduleRepo := &createdScheduleRepo{}
+	return NewScheduleUsecase(scheduleRepo, projectRepo, nil), scheduleRepo, project
+}
+
+func TestScheduleUsecase_Create_DefaultTimezone(t *testing.T) {
+	t.Cleanup(func() { _ = domain.SetDefaultTimezone("") })
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name            string
+		defaultTimezone string
+		timezone        string
+		want            string
+	}{
+		{name: "unconfigured deployment", want: "UTC"},
+		{name: "deployment default applied", defaultTimezone: "Europe/Berlin", want: "Europe/Berlin"},
+		{name: "explicit timezone kept", defaultTimezone: "Europe/Berlin", timezone: "America/New_York", want: "America/New_York"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := domain.SetDefaultTimezone(tt.defaultTimezone); err != nil {
+				t.Fatalf("SetDefaultTimezone() error = %v", err)
+			}
+			uc, scheduleRepo, project := newScheduleUsecaseForCreateTests(tenantID)
+
+			schedule, err := uc.Create(context.Background(), CreateScheduleInput{
+				TenantID:       tenantID,
+				ProjectID:      project.ID,
+				StartStepID:    uuid.New(),
+				Name:           "Every morning",
+				CronExpression: "0 9 * * *",
+				Timezone:       tt.timezone,
+			})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if schedule.Timezone != tt.want {
+				t.Errorf("Timezone = %q, want %q", schedule.Timezone, tt.want)
+			}
+			if len(scheduleRepo.created) != 1 || scheduleRepo.created[0].Timezone != tt.want {
+				t.Errorf("stored schedules = %+v, want one with timezone %q", scheduleRepo.created, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleUsecase_Create_InvalidTimezone(t *testing.T) {
+	tenantID := uuid.New()
+	uc, scheduleRepo, project := newScheduleUsecaseForCreateTests(tenantID)
+
+	_, err := uc.Create(context.Background(), CreateScheduleInput{
+		TenantID:       tenantID,
+		ProjectID:      project.ID,
+		StartStepID:    uuid.New(),
+		Name:           "Every morning",
+		CronExpression: "0 9 * * *",
+		Timezone:       "Mars/Olympus_Mons",
+	})
+	var validationErr domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Create() error = %v, want ValidationError", err)
+	}
+	if len(scheduleRepo.created) != 0 {
+		t.Errorf("schedule stored despite an invalid timezone")
+	}
+}
+
+func TestScheduleUsecase_Create_NormalizesCron(t *testing.T) {
+	tenantID := uuid.New()
+	uc, scheduleRepo, project := newScheduleUsecaseForCreateTests(tenantID)
+
+	schedule, err := uc.Create(context.Background(), CreateScheduleInput{
+		TenantID:       tenantID,
+		ProjectID:      project.ID,
+		StartStepID:    uuid.New(),
+		Name:           "Every day",
+		CronExpression: " @Daily ",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if schedule.CronExpression != "0 0 * * *" || scheduleRepo.created[0].CronExpression != "0 0 * * *" {
+		t.Errorf("stored cron expression = %q, want the normalized %q", scheduleRepo.created[0].CronExpression, "0 0 * * *")
+	}
+	if schedule.NextRunAt == nil || schedule.NextRunAt.Hour() != 0 || schedule.NextRunAt.Minute() != 0 {
+		t.Errorf("next run = %v, want midnight", schedule.NextRunAt)
+	}
+}
+
+func TestScheduleUsecase_Create_InvalidCron(t *testing.T) {
+	tenantID := uuid.New()
+	uc, scheduleRepo, project := newScheduleUsecaseForCreateTests(tenantID)
+
+	_, err := uc.Create(context.Background(), CreateScheduleInput{
+		TenantID:       tenantID,
+		ProjectID:      project.ID,
+		StartStepID:    uuid.New(),
+		Name:           "Every morning",
+		CronExpression: "0 25 * * *",
+	})
+	var cronErr *domain.InvalidCronError
+	if !errors.As(err, &cronErr) || !errors.Is(err, domain.ErrScheduleInvalidCron) {
+		t.Fatalf("Create() error = %v, want InvalidCronError", err)
+	}
+	if cronErr.Reason == "" {
+		t.Errorf("InvalidCronError has no reason")
+	}
+	if len(scheduleRepo.created) != 0 {
+		t.Errorf("schedule stored despite an invalid cron expression")
+	}
+}
+
+// bulkScheduleRepo keeps schedules in memory for bulk pause and resume
+type bulkScheduleRepo struct {
+	repository.ScheduleRepository
+	schedules map[uuid.UUID]*domain.Schedule
+}
+
+func (r *bulkScheduleRepo) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Schedule, error) {
+	schedule, ok := r.schedules[id]
+	if !ok || schedule.TenantID != tenantID {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+func (r *bulkScheduleRepo) Update(ctx context.Context, schedule *domain.Schedule) error {
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (r *bulkScheduleRepo) PauseAll(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, schedule := range r.schedules {
+		if schedule.TenantID == tenantID && schedule.Status == domain.ScheduleStatusActive {
+			schedule.Status = domain.ScheduleStatusPaused
+			schedule.BulkPaused = true
+			ids = append(ids, schedule.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *bulkScheduleRepo) ListBulkPaused(ctx context.Context, tenantID uuid.UUID) ([]*domain.Schedule, error) {
+	var schedules []*domain.Schedule
+	for _, schedule := range r.schedules {
+		if schedule.TenantID == tenantID && schedule.Status == domain.ScheduleStatusPaused && schedule.BulkPaused {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (r *bulkScheduleRepo) ResumeBulkPaused(ctx context.Context, tenantID uuid.UUID, nextRuns map[uuid.UUID]*time.Time) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for id, nextRunAt := range nextRuns {
+		schedule := r.schedules[id]
+		if schedule.TenantID == tenantID && schedule.Status == domain.ScheduleStatusPaused && schedule.BulkPaused {
+			schedule.Status = domain.ScheduleStatusActive
+			schedule.BulkPaused = false
+			schedule.NextRunAt = nextRunAt
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func TestScheduleUsecase_PauseAllResumeAll(t *testing.T) {
+	tenantID := uuid.New()
+	repo := &bulkScheduleRepo{schedules: map[uuid.UUID]*domain.Schedule{}}
+	add := func(tenantID uuid.UUID, status domain.ScheduleStatus) *domain.Schedule {
+		schedule := domain.NewSchedule(tenantID, uuid.New(), uuid.New(), 1, "schedule", "0 9 * * *", "UTC", nil)
+		schedule.Status = status
+		repo.schedules[schedule.ID] = schedule
+		return schedule
+	}
+	active1 := add(tenantID, domain.ScheduleStatusActive)
+	active2 := add(tenantID, domain.ScheduleStatusActive)
+	paused := add(tenantID, domain.ScheduleStatusPaused)
+	disabled := add(tenantID, domain.ScheduleStatusDisabled)
+	otherTenant := add(uuid.New(), domain.ScheduleStatusActive)
+	uc := NewScheduleUsecase(repo, nil, nil)
+	ctx := context.Background()
+
+	pausedIDs, err := uc.PauseAll(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("PauseAll() error = %v", err)
+	}
+	if !sameScheduleIDs(pausedIDs, active1.ID, active2.ID) {
+		t.Errorf("PauseAll() paused %v, want the active schedules %v and %v", pausedIDs, active1.ID, active2.ID)
+	}
+	for _, schedule := range []*domain.Schedule{active1, active2, paused} {
+		if schedule.Status != domain.ScheduleStatusPaused {
+			t.Errorf("schedule %s status after PauseAll = %q, want paused", schedule.ID, schedule.Status)
+		}
+	}
+	if otherTenant.Status != domain.ScheduleStatusActive {
+		t.Errorf("schedule of another tenant status = %q, want active", otherTenant.Status)
+	}
+
+	// Pausing again while everything is paused changes nothing
+	again, err := uc.PauseAll(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("second PauseAll() error = %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("second PauseAll() paused %v, want none", again)
+	}
+
+	resumedIDs, err := uc.ResumeAll(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("ResumeAll() error = %v", err)
+	}
+	if !sameScheduleIDs(resumedIDs, active1.ID, active2.ID) {
+		t.Errorf("ResumeAll() resumed %v, want exactly the previously active schedules %v and %v", resumedIDs, active1.ID, active2.ID)
+	}
+	for _, schedule := range []*domain.Schedule{active1, active2} {
+		if schedule.Status != domain.ScheduleStatusActive || schedule.BulkPaused {
+			t.Errorf("schedule %s after ResumeAll: status %q, bulk paused %v; want active", schedule.ID, schedule.Status, schedule.BulkPaused)
+		}
+		if schedule.NextRunAt == nil {
+			t.Errorf("schedule %s after ResumeAll has no next run", schedule.ID)
+		}
+	}
+	if paused.Status != domain.ScheduleStatusPaused {
+		t.Errorf("previously paused schedule status = %q, want paused", paused.Status)
+	}
+	if disabled.Status != domain.ScheduleStatusDisabled {
+		t.Errorf("disabled schedule status = %q, want disabled", disabled.Status)
+	}
+}
+
+func TestScheduleUsecase_ResumeAll_SkipsIndividuallyPaused(t *testing.T) {
+	tenantID := uuid.New()
+	repo := &bulkScheduleRepo{schedules: map[uuid.UUID]*domain.Schedule{}}
+	kept := domain.NewSchedule(tenantID, uuid.New(), uuid.New(), 1, "kept", "0 9 * * *", "UTC", nil)
+	repo.schedules[kept.ID] = kept
+	uc := NewScheduleUsecase(repo, nil, nil)
+	ctx := context.Background()
+
+	if _, err := uc.PauseAll(ctx, tenantID); err != nil {
+		t.Fatalf("PauseAll() error = %v", err)
+	}
+	// Pausing the schedule on its own while bulk-paused keeps it paused on resume-all
+	if _, err := uc.Pause(ctx, tenantID, kept.ID); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+
+	resumedIDs, err := uc.ResumeAll(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("ResumeAll() error = %v", err)
+	}
+	if len(resumedIDs) != 0 {
+		t.Errorf("ResumeAll() resumed %v, want none", resumedIDs)
+	}
+	if kept.Status != domain.ScheduleStatusPaused {
+		t.Errorf("status = %q, want paused", kept.Status)
+	}
+}
+
+// sameScheduleIDs reports whether ids holds exactly the wanted IDs in any order
+func sameScheduleIDs(ids []uuid.UUID, want ...uuid.UUID) bool {
+	if len(ids) != len(want) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	for _, id := range want {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestScheduleUsecase_Trigger_RecordsSchedule(t *testing.T) {
+	tenantID := uuid.New()
+	projectRepo := newMockProjectRepo()
+	project := domain.NewProject(tenantID, "Nightly Sync", "")
+	projectRepo.projects[project.ID] = project
+	runRepo := newMockRunRepo()
+	schedule := domain.NewSchedule(tenantID, project.ID, uuid.New(), project.Version, "Nightly", "0 3 * * *", "UTC", nil)
+	uc := NewScheduleUsecase(&singleScheduleRepo{schedule: schedule}, projectRepo, runRepo)
+
+	run, err := uc.Trigger(context.Background(), tenantID, schedule.ID)
+	if err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	trigger := run.TriggerInfo()
+	if trigger["type"] != "schedule" || trigger["source"] != "schedule" {
+		t.Errorf("trigger = %v, want type and source schedule", trigger)
+	}
+	if trigger["schedule_id"] != schedule.ID.String() || trigger["schedule_name"] != "Nightly" {
+		t.Errorf("trigger = %v, want the schedule ID and name", trigger)
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -34,7 +35,7 @@ func NewStepUsecase(
 	}
 }
 
-// validateCredentialBindingsTenant validates that all credential IDs in bindings belong to the tenant
+// validateCredentialBindingsTenant validates that all static credential IDs in bindings belong to the tenant
 func (u *StepUsecase) validateCredentialBindingsTenant(ctx context.Context, tenantID uuid.UUID, bindings json.RawMessage) error {
 	if len(bindings) == 0 || string(bindings) == "null" {
 		return nil
@@ -46,7 +47,8 @@ func (u *StepUsecase) validateCredentialBindingsTenant(ctx context.Context, tena
 	}
 
 	for _, credIDStr := range parsed {
-		if credIDStr == "" {
+		// Template bindings are resolved and checked against the tenant at execution time
+		if credIDStr == "" || domain.IsCredentialBindingTemplate(credIDStr) {
 			continue
 		}
 		credID, err := uuid.Parse(credIDStr)
@@ -61,6 +63,45 @@ func (u *StepUsecase) validateCredentialBindingsTenant(ctx context.Context, tena
 	return nil
 }
 
+// resolveStepType checks that a step type is a built-in type or resolves to a tenant or system
+// block definition. Unknown types fail with an InvalidStepTypeError listing the closest valid slugs.
+// Returns nil for built-in types.
+func (u *StepUsecase) resolveStepType(ctx context.Context, tenantID uuid.UUID, stepType domain.StepType) (*domain.BlockDefinition, error) {
+	if stepType.IsValid() {
+		return nil, nil
+	}
+
+	blockDef, err := u.blockDefRepo.GetBySlug(ctx, &tenantID, string(stepType))
+	if err != nil {
+		return nil, err
+	}
+	if blockDef == nil {
+		// Also try system blocks (tenant_id = NULL)
+		if blockDef, err = u.blockDefRepo.GetBySlug(ctx, nil, string(stepType)); err != nil {
+			return nil, err
+		}
+	}
+	if blockDef != nil {
+		return blockDef, nil
+	}
+
+	blocks, err := u.blockDefRepo.List(ctx, &tenantID, repository.BlockDefinitionFilter{EnabledOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	available := make([]string, 0, len(blocks)+len(domain.ValidStepTypes()))
+	for _, b := range blocks {
+		available = append(available, b.Slug)
+	}
+	for _, t := range domain.ValidStepTypes() {
+		available = append(available, string(t))
+	}
+	return nil, &domain.InvalidStepTypeError{
+		Type:        string(stepType),
+		Suggestions: domain.SuggestBlockTypes(string(stepType), available),
+	}
+}
+
 // CreateStepInput represents input for creating a step
 type CreateStepInput struct {
 	TenantID           uuid.UUID
@@ -88,18 +129,9 @@ func (u *StepUsecase) Create(ctx context.Context, input CreateStepInput) (*domai
 	}
 
 	// Check if type is a built-in step type or a custom block definition
-	var blockDef *domain.BlockDefinition
-	if !input.Type.IsValid() {
-		// Try to find as a custom block definition
-		var err error
-		blockDef, err = u.blockDefRepo.GetBySlug(ctx, &input.TenantID, string(input.Type))
-		if err != nil || blockDef == nil {
-			// Also try system blocks (tenant_id = NULL)
-			blockDef, err = u.blockDefRepo.GetBySlug(ctx, nil, string(input.Type))
-			if err != nil || blockDef == nil {
-				return nil, domain.ErrInvalidStepType
-			}
-		}
+	blockDef, err := u.resolveStepType(ctx, input.TenantID, input.Type)
+	if err != nil {
+		return nil, err
 	}
 
 	// Apply ConfigDefaults from BlockDefinition if available
@@ -151,6 +183,33 @@ func (u *StepUsecase) Create(ctx context.Context, input CreateStepInput) (*domai
 	return step, nil
 }
 
+// CreateOrGet creates a step unless the project already has a step with the same name, in which
+// case that step is returned unchanged and created is false. This makes retried or double-submitted
+// create requests safe, matching the copilot add_step tool.
+func (u *StepUsecase) CreateOrGet(ctx context.Context, input CreateStepInput) (step *domain.Step, created bool, err error) {
+	if _, err := u.projectChecker.CheckEditable(ctx, input.TenantID, input.ProjectID); err != nil {
+		return nil, false, err
+	}
+
+	if input.Name != "" {
+		steps, err := u.stepRepo.ListByProject(ctx, input.TenantID, input.ProjectID)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, existing := range steps {
+			if existing.Name == input.Name {
+				return existing, false, nil
+			}
+		}
+	}
+
+	step, err = u.Create(ctx, input)
+	if err != nil {
+		return nil, false, err
+	}
+	return step, true, nil
+}
+
 // GetByID retrieves a step by ID
 func (u *StepUsecase) GetByID(ctx context.Context, tenantID, projectID, stepID uuid.UUID) (*domain.Step, error) {
 	// Verify project exists
@@ -215,8 +274,14 @@ func (u *StepUsecase) Update(ctx context.Context, input UpdateStepInput) (*domai
 				input.TriggerType = string(domain.GetTriggerTypeFromSlug(string(input.Type)))
 			}
 		}
-		if stepType.IsValid() {
-			step.Type = stepType
+		blockDef, err := u.resolveStepType(ctx, input.TenantID, input.Type)
+		if err != nil {
+			return nil, err
+		}
+		step.Type = stepType
+		step.BlockDefinitionID = nil
+		if blockDef != nil {
+			step.BlockDefinitionID = &blockDef.ID
 		}
 	}
 	if input.Config != nil {
@@ -253,6 +318,98 @@ func (u *StepUsecase) Update(ctx context.Context, input UpdateStepInput) (*domai
 	return step, nil
 }
 
+// stepPatchableFields are the step fields a JSON Patch may change, the same ones Update sets
+var stepPatchableFields = map[string]bool{
+	"name":                true,
+	"type":                true,
+	"config":              true,
+	"position_x":          true,
+	"position_y":          true,
+	"trigger_type":        true,
+	"trigger_config":      true,
+	"credential_bindings": true,
+}
+
+// PatchStepInput represents input for applying a JSON Patch to a step
+type PatchStepInput struct {
+	TenantID  uuid.UUID
+	ProjectID uuid.UUID
+	StepID    uuid.UUID
+	Patch     domain.JSONPatch
+}
+
+// Patch applies a JSON Patch (RFC 6902) to the step's JSON representation. The patched step is
+// validated as a whole and written only if no other update changed the step since it was read,
+// so a patch is either applied completely or not at all.
+func (u *StepUsecase) Patch(ctx context.Context, input PatchStepInput) (*domain.Step, error) {
+	// Verify project is editable
+	if _, err := u.projectChecker.CheckEditable(ctx, input.TenantID, input.ProjectID); err != nil {
+		return nil, err
+	}
+
+	current, err := u.stepRepo.GetByID(ctx, input.TenantID, input.ProjectID, input.StepID)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched domain.Step
+	if err := applyJSONPatch(current, input.Patch, stepPatchableFields, &patched); err != nil {
+		return nil, err
+	}
+
+	step := *current
+	if patched.Name == "" {
+		return nil, domain.NewValidationError("name", "name is required")
+	}
+	step.Name = patched.Name
+	if patched.Type != current.Type {
+		blockDef, err := u.resolveStepType(ctx, input.TenantID, patched.Type)
+		if err != nil {
+			return nil, err
+		}
+		step.Type = patched.Type
+		step.TriggerType = patched.TriggerType
+		// Normalize trigger block types to "start"
+		if domain.IsTriggerBlockSlug(string(patched.Type)) {
+			step.Type = domain.StepTypeStart
+			if step.TriggerType == nil {
+				tt := domain.GetTriggerTypeFromSlug(string(patched.Type))
+				step.TriggerType = &tt
+			}
+		}
+		step.BlockDefinitionID = nil
+		if blockDef != nil {
+			step.BlockDefinitionID = &blockDef.ID
+		}
+	} else {
+		step.TriggerType = patched.TriggerType
+	}
+	if step.TriggerType != nil && !step.TriggerType.IsValid() {
+		return nil, domain.NewValidationError("trigger_type", fmt.Sprintf("unknown trigger type %q", *step.TriggerType))
+	}
+	if !isJSONObject(patched.Config) {
+		return nil, domain.NewValidationError("config", "config must be an object")
+	}
+	step.Config = patched.Config
+	step.PositionX = patched.PositionX
+	step.PositionY = patched.PositionY
+	step.TriggerConfig = patched.TriggerConfig
+
+	if !isJSONObject(patched.CredentialBindings) {
+		return nil, domain.NewValidationError("credential_bindings", "credential_bindings must be an object")
+	}
+	if err := u.validateCredentialBindingsTenant(ctx, input.TenantID, patched.CredentialBindings); err != nil {
+		return nil, err
+	}
+	step.CredentialBindings = patched.CredentialBindings
+
+	if err := u.stepRepo.UpdateIfUnmodified(ctx, &step, current.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &step, nil
+}
+
 // Delete deletes a step
 func (u *StepUsecase) Delete(ctx context.Context, tenantID, projectID, stepID uuid.UUID) error {
 	// Verify project is editable
@@ -263,6 +420,60 @@ func (u *StepUsecase) Delete(ctx context.Context, tenantID, projectID, stepID uu
 	return u.stepRepo.Delete(ctx, tenantID, projectID, stepID)
 }
 
+// MaxBatchDeleteSteps is the maximum number of steps that can be deleted in one request
+const MaxBatchDeleteSteps = 500
+
+// DeleteStepsOutput represents output for deleting steps in bulk
+type DeleteStepsOutput struct {
+	DeletedStepIDs []uuid.UUID `json:"deleted_step_ids"`
+	RemovedEdgeIDs []uuid.UUID `json:"removed_edge_ids"`
+}
+
+// DeleteBatch deletes multiple steps and every edge connected to them atomically.
+// All IDs must belong to the project; otherwise nothing is deleted.
+func (u *StepUsecase) DeleteBatch(ctx context.Context, tenantID, projectID uuid.UUID, stepIDs []uuid.UUID) (*DeleteStepsOutput, error) {
+	// Verify project is editable
+	if _, err := u.projectChecker.CheckEditable(ctx, tenantID, projectID); err != nil {
+		return nil, err
+	}
+
+	if len(stepIDs) == 0 {
+		return nil, domain.NewValidationError("step_ids", "at least one step ID is required")
+	}
+	if len(stepIDs) > MaxBatchDeleteSteps {
+		return nil, domain.NewValidationError("step_ids", fmt.Sprintf("cannot delete more than %d steps at once", MaxBatchDeleteSteps))
+	}
+
+	existing, err := u.stepRepo.ListByProject(ctx, tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	inProject := make(map[uuid.UUID]bool, len(existing))
+	for _, step := range existing {
+		inProject[step.ID] = true
+	}
+
+	ids := make([]uuid.UUID, 0, len(stepIDs))
+	seen := make(map[uuid.UUID]bool, len(stepIDs))
+	for _, id := range stepIDs {
+		if seen[id] {
+			continue
+		}
+		if !inProject[id] {
+			return nil, domain.NewValidationError("step_ids", fmt.Sprintf("step %s does not belong to this workflow", id))
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	edgeIDs, err := u.stepRepo.DeleteBatch(ctx, tenantID, projectID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeleteStepsOutput{DeletedStepIDs: ids, RemovedEdgeIDs: edgeIDs}, nil
+}
+
 // UpdateRetryConfigInput represents input for updating retry config
 type UpdateRetryConfigInput struct {
 	TenantID   uuid.UUID
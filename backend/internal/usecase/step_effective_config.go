@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/engine"
+)
+
+// Sources of an effective config key
+const (
+	ConfigSourceDefault  = "default"  // Only the block defaults set the key
+	ConfigSourceConfig   = "config"   // Only the step config sets the key
+	ConfigSourceOverride = "override" // The step config replaces a different block default
+)
+
+// StepEffectiveConfig shows how a custom block's config defaults merge with a step's config
+type StepEffectiveConfig struct {
+	StepID          uuid.UUID              `json:"step_id"`
+	BlockSlug       string                 `json:"block_slug,omitempty"` // Empty for built-in step types, which have no block defaults
+	Defaults        map[string]interface{} `json:"defaults"`
+	Config          map[string]interface{} `json:"config"`
+	EffectiveConfig map[string]interface{} `json:"effective_config"`
+	Sources         map[string]string      `json:"sources"`         // Effective config key -> ConfigSource*
+	OverriddenKeys  []string               `json:"overridden_keys"` // Keys whose block default the step config replaces
+}
+
+// GetEffectiveConfig returns the block defaults, the step's explicit config and the config the
+// step runs with, merged the way the executor merges them
+func (u *StepUsecase) GetEffectiveConfig(ctx context.Context, tenantID, projectID, stepID uuid.UUID) (*StepEffectiveConfig, error) {
+	step, err := u.stepRepo.GetByID(ctx, tenantID, projectID, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockDef, err := u.runtimeBlockDefinition(ctx, tenantID, step)
+	if err != nil {
+		return nil, err
+	}
+
+	// Built-in steps have no block defaults and run with their config as it is
+	var defaults json.RawMessage
+	result := &StepEffectiveConfig{StepID: step.ID}
+	if blockDef != nil {
+		result.BlockSlug = blockDef.Slug
+		defaults = blockDef.GetEffectiveConfigDefaults()
+	}
+	result.Defaults = engine.MergeBlockConfig(nil, defaults)
+	result.Config = engine.MergeBlockConfig(step.Config, nil)
+	result.EffectiveConfig = engine.MergeBlockConfig(step.Config, defaults)
+
+	result.Sources = make(map[string]string, len(result.EffectiveConfig))
+	result.OverriddenKeys = []string{}
+	for key := range result.EffectiveConfig {
+		defaultValue, hasDefault := result.Defaults[key]
+		configValue, hasConfig := result.Config[key]
+		switch {
+		case !hasConfig:
+			result.Sources[key] = ConfigSourceDefault
+		case hasDefault && !reflect.DeepEqual(defaultValue, configValue):
+			result.Sources[key] = ConfigSourceOverride
+			result.OverriddenKeys = append(result.OverriddenKeys, key)
+		default:
+			result.Sources[key] = ConfigSourceConfig
+		}
+	}
+	sort.Strings(result.OverriddenKeys)
+
+	return result, nil
+}
+
+// runtimeBlockDefinition returns the block definition the executor runs a step with: by the
+// step's block definition ID, then by its type slug. Built-in step types return nil.
+func (u *StepUsecase) runtimeBlockDefinition(ctx context.Context, tenantID uuid.UUID, step *domain.Step) (*domain.BlockDefinition, error) {
+	if step.Type.IsValid() {
+		return nil, nil
+	}
+	if step.BlockDefinitionID != nil {
+		blockDef, err := u.blockDefRepo.GetByID(ctx, *step.BlockDefinitionID)
+		if err != nil {
+			return nil, err
+		}
+		if blockDef == nil || (blockDef.TenantID != nil && *blockDef.TenantID != tenantID) {
+			return nil, domain.ErrBlockDefinitionNotFound
+		}
+		return blockDef, nil
+	}
+
+	blockDef, err := u.blockDefRepo.GetBySlug(ctx, &tenantID, string(step.Type))
+	if err != nil {
+		return nil, err
+	}
+	if blockDef == nil {
+		if blockDef, err = u.blockDefRepo.GetBySlug(ctx, nil, string(step.Type)); err != nil {
+			return nil, err
+		}
+	}
+	if blockDef == nil {
+		return nil, domain.ErrBlockDefinitionNotFound
+	}
+	return blockDef, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/engine"
+)
+
+func TestStepUsecase_GetEffectiveConfig_MatchesExecutorMerge(t *testing.T) {
+	tenantID, projectID := uuid.New(), uuid.New()
+	stepRepo, blockRepo := newMockStepRepo(), newMockBlockRepo()
+	uc := NewStepUsecase(newMockProjectRepo(), stepRepo, blockRepo, newMockCredentialRepoForStep())
+
+	block := domain.NewBlockDefinition(&tenantID, "crm-lookup", "CRM Lookup", domain.BlockCategoryCustom)
+	block.ConfigDefaults = json.RawMessage(`{"method": "GET"}`)
+	// Inherited defaults take precedence over the block's own, as at runtime
+	block.ResolvedConfigDefaults = json.RawMessage(`{"method": "GET", "timeout": 30, "retries": 2}`)
+	blockRepo.blocks[block.ID] = block
+
+	step := &domain.Step{
+		ID:                uuid.New(),
+		TenantID:          tenantID,
+		ProjectID:         projectID,
+		Name:              "lookup",
+		Type:              domain.StepType("crm-lookup"),
+		BlockDefinitionID: &block.ID,
+		Config:            json.RawMessage(`{"method": "POST", "retries": 2, "url": "https://crm.example.com"}`),
+	}
+	stepRepo.steps[step.ID] = step
+
+	got, err := uc.GetEffectiveConfig(context.Background(), tenantID, projectID, step.ID)
+	if err != nil {
+		t.Fatalf("GetEffectiveConfig() error = %v", err)
+	}
+
+	want := engine.MergeBlockConfig(step.Config, block.GetEffectiveConfigDefaults())
+	if !reflect.DeepEqual(got.EffectiveConfig, want) {
+		t.Errorf("EffectiveConfig = %v, want the executor merge %v", got.EffectiveConfig, want)
+	}
+	if got.BlockSlug != "crm-lookup" || len(got.Defaults) != 3 || len(got.Config) != 3 {
+		t.Errorf("block slug, defaults or config = %q, %v, %v", got.BlockSlug, got.Defaults, got.Config)
+	}
+	wantSources := map[string]string{
+		"method":  ConfigSourceOverride,
+		"timeout": ConfigSourceDefault,
+		"retries": ConfigSourceConfig,
+		"url":     ConfigSourceConfig,
+	}
+	if !reflect.DeepEqual(got.Sources, wantSources) {
+		t.Errorf("Sources = %v, want %v", got.Sources, wantSources)
+	}
+	if !reflect.DeepEqual(got.OverriddenKeys, []string{"method"}) {
+		t.Errorf("OverriddenKeys = %v, want [method]", got.OverriddenKeys)
+	}
+}
+
+func TestStepUsecase_GetEffectiveConfig_BuiltInStepHasNoDefaults(t *testing.T) {
+	tenantID, projectID := uuid.New(), uuid.New()
+	stepRepo, blockRepo := newMockStepRepo(), newMockBlockRepo()
+	uc := NewStepUsecase(newMockProjectRepo(), stepRepo, blockRepo, newMockCredentialRepoForStep())
+
+	// A system block with the same slug is not applied to the built-in step
+	llmBlock := blockRepo.addSystemBlock("llm")
+	llmBlock.ConfigDefaults = json.RawMessage(`{"temperature": 0.7}`)
+
+	step := &domain.Step{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		ProjectID: projectID,
+		Name:      "summarize",
+		Type:      domain.StepTypeLLM,
+		Config:    json.RawMessage(`{"model": "gpt-4o"}`),
+	}
+	stepRepo.steps[step.ID] = step
+
+	got, err := uc.GetEffectiveConfig(context.Background(), tenantID, projectID, step.ID)
+	if err != nil {
+		t.Fatalf("GetEffectiveConfig() error = %v", err)
+	}
+	if got.BlockSlug != "" || len(got.Defaults) != 0 {
+		t.Errorf("built-in step got block %q defaults %v, want none", got.BlockSlug, got.Defaults)
+	}
+	if !reflect.DeepEqual(got.EffectiveConfig, map[string]interface{}{"model": "gpt-4o"}) {
+		t.Errorf("EffectiveConfig = %v, want the step config", got.EffectiveConfig)
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/google/uuid"
@@ -255,3 +256,283 @@ func TestStepUsecase_ValidateCredentialBindingsTenant_MultipleBindings(t *testin
 		}
 	})
 }
+
+// ============================================================================
+// Tests for Step Type Validation
+// ============================================================================
+
+func newStepUsecaseForTypeTests(tenantID uuid.UUID) (*StepUsecase, *mockStepRepo, *domain.Project) {
+	projectRepo, stepRepo, blockRepo := newMockProjectRepo(), newMockStepRepo(), newMockBlockRepo()
+	project := domain.NewProject(tenantID, "Type Validation", "")
+	projectRepo.projects[project.ID] = project
+	for _, slug := range []string{"llm", "http", "slack", "manual_trigger"} {
+		blockRepo.addSystemBlock(slug)
+	}
+	custom := domain.NewBlockDefinition(&tenantID, "crm-lookup", "CRM Lookup", domain.BlockCategoryCustom)
+	blockRepo.blocks[custom.ID] = custom
+
+	return NewStepUsecase(projectRepo, stepRepo, blockRepo, newMockCredentialRepoForStep()), stepRepo, project
+}
+
+func TestStepUsecase_Create_RejectsUnknownType(t *testing.T) {
+	tenantID := uuid.New()
+	uc, stepRepo, project := newStepUsecaseForTypeTests(tenantID)
+
+	tests := []struct {
+		stepType        string
+		wantSuggestions []string
+	}{
+		{stepType: "gpt", wantSuggestions: []string{"llm"}},
+		{stepType: "slak", wantSuggestions: []string{"slack"}},
+		{stepType: "crm", wantSuggestions: []string{"crm-lookup"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stepType, func(t *testing.T) {
+			_, err := uc.Create(context.Background(), CreateStepInput{
+				TenantID:  tenantID,
+				ProjectID: project.ID,
+				Name:      "Step",
+				Type:      domain.StepType(tt.stepType),
+			})
+			if !errors.Is(err, domain.ErrInvalidStepType) {
+				t.Fatalf("Create() error = %v, want ErrInvalidStepType", err)
+			}
+			var typeErr *domain.InvalidStepTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("Create() error = %T, want *InvalidStepTypeError", err)
+			}
+			if len(typeErr.Suggestions) == 0 || typeErr.Suggestions[0] != tt.wantSuggestions[0] {
+				t.Errorf("Suggestions = %v, want first %q", typeErr.Suggestions, tt.wantSuggestions[0])
+			}
+		})
+	}
+
+	if len(stepRepo.steps) != 0 {
+		t.Errorf("no steps should be saved, got %d", len(stepRepo.steps))
+	}
+}
+
+func TestStepUsecase_Create_AcceptsKnownTypes(t *testing.T) {
+	tenantID := uuid.New()
+	uc, _, project := newStepUsecaseForTypeTests(tenantID)
+
+	tests := []struct {
+		stepType     string
+		wantBlockDef bool
+	}{
+		{stepType: "slack", wantBlockDef: true},      // System block
+		{stepType: "crm-lookup", wantBlockDef: true}, // Tenant block
+		{stepType: "condition", wantBlockDef: false}, // Built-in step type
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stepType, func(t *testing.T) {
+			step, err := uc.Create(context.Background(), CreateStepInput{
+				TenantID:  tenantID,
+				ProjectID: project.ID,
+				Name:      "Step",
+				Type:      domain.StepType(tt.stepType),
+			})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if got := step.BlockDefinitionID != nil; got != tt.wantBlockDef {
+				t.Errorf("BlockDefinitionID set = %v, want %v", got, tt.wantBlockDef)
+			}
+		})
+	}
+}
+
+func TestStepUsecase_CreateOrGet_DuplicateNameReturnsExisting(t *testing.T) {
+	tenantID := uuid.New()
+	uc, stepRepo, project := newStepUsecaseForTypeTests(tenantID)
+
+	input := CreateStepInput{
+		TenantID:  tenantID,
+		ProjectID: project.ID,
+		Name:      "Notify",
+		Type:      "slack",
+		Config:    json.RawMessage(`{"channel":"#ops"}`),
+	}
+	first, created, err := uc.CreateOrGet(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CreateOrGet() error = %v", err)
+	}
+	if !created {
+		t.Fatal("created = false for a new name, want true")
+	}
+
+	// A retry with the same name, even with a different body, returns the existing step
+	input.Config = json.RawMessage(`{"channel":"#alerts"}`)
+	second, created, err := uc.CreateOrGet(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CreateOrGet() retry error = %v", err)
+	}
+	if created {
+		t.Error("created = true for a duplicate name, want false")
+	}
+	if second.ID != first.ID {
+		t.Errorf("ID = %s, want existing step %s", second.ID, first.ID)
+	}
+	if len(stepRepo.steps) != 1 {
+		t.Errorf("len(steps) = %d, want 1", len(stepRepo.steps))
+	}
+	if string(stepRepo.steps[first.ID].Config) != string(first.Config) {
+		t.Errorf("existing step config changed to %s", stepRepo.steps[first.ID].Config)
+	}
+
+	// A different name still creates a new step
+	input.Name = "Notify again"
+	if _, created, err := uc.CreateOrGet(context.Background(), input); err != nil || !created {
+		t.Errorf("CreateOrGet() with a new name = created %v, error %v; want created", created, err)
+	}
+	if len(stepRepo.steps) != 2 {
+		t.Errorf("len(steps) = %d, want 2", len(stepRepo.steps))
+	}
+}
+
+func TestStepUsecase_Update_ValidatesType(t *testing.T) {
+	tenantID := uuid.New()
+	uc, stepRepo, project := newStepUsecaseForTypeTests(tenantID)
+
+	step, err := uc.Create(context.Background(), CreateStepInput{
+		TenantID:  tenantID,
+		ProjectID: project.ID,
+		Name:      "Step",
+		Type:      "slack",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	_, err = uc.Update(context.Background(), UpdateStepInput{
+		TenantID:  tenantID,
+		ProjectID: project.ID,
+		StepID:    step.ID,
+		Type:      "gpt",
+	})
+	var typeErr *domain.InvalidStepTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Update() error = %v, want *InvalidStepTypeError", err)
+	}
+	if stepRepo.steps[step.ID].Type != "slack" {
+		t.Errorf("Type = %q, want unchanged %q", stepRepo.steps[step.ID].Type, "slack")
+	}
+
+	updated, err := uc.Update(context.Background(), UpdateStepInput{
+		TenantID:  tenantID,
+		ProjectID: project.ID,
+		StepID:    step.ID,
+		Type:      "http",
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Type != "http" || updated.BlockDefinitionID == nil {
+		t.Errorf("Type = %q, BlockDefinitionID = %v; want http with block definition", updated.Type, updated.BlockDefinitionID)
+	}
+}
+
+// ============================================================================
+// Tests for DeleteBatch
+// ============================================================================
+
+// seedBatchDeleteProject creates A -> B -> C -> D plus A -> D and returns the steps by name
+func seedBatchDeleteProject(tenantID uuid.UUID) (*StepUsecase, *mockStepRepo, *mockEdgeRepo, *domain.Project, map[string]*domain.Step, map[string]*domain.Edge) {
+	projectRepo, stepRepo, edgeRepo := newMockProjectRepo(), newMockStepRepo(), newMockEdgeRepo()
+	stepRepo.edgeRepo = edgeRepo
+	project := domain.NewProject(tenantID, "Batch Delete", "")
+	projectRepo.projects[project.ID] = project
+
+	steps := make(map[string]*domain.Step)
+	for _, name := range []string{"A", "B", "C", "D"} {
+		step := domain.NewStep(tenantID, project.ID, name, domain.StepTypeFunction, json.RawMessage(`{}`))
+		stepRepo.steps[step.ID] = step
+		steps[name] = step
+	}
+	edges := make(map[string]*domain.Edge)
+	for _, pair := range []string{"AB", "BC", "CD", "AD"} {
+		edge := domain.NewEdge(tenantID, project.ID, steps[pair[:1]].ID, steps[pair[1:]].ID, "")
+		edgeRepo.edges[edge.ID] = edge
+		edges[pair] = edge
+	}
+
+	uc := NewStepUsecase(projectRepo, stepRepo, newMockBlockRepo(), newMockCredentialRepoForStep())
+	return uc, stepRepo, edgeRepo, project, steps, edges
+}
+
+func TestStepUsecase_DeleteBatch_RemovesConnectedEdges(t *testing.T) {
+	tenantID := uuid.New()
+	uc, stepRepo, edgeRepo, project, steps, edges := seedBatchDeleteProject(tenantID)
+
+	// Duplicate IDs are tolerated
+	output, err := uc.DeleteBatch(context.Background(), tenantID, project.ID, []uuid.UUID{steps["B"].ID, steps["C"].ID, steps["B"].ID})
+	if err != nil {
+		t.Fatalf("DeleteBatch() error = %v", err)
+	}
+
+	if len(output.DeletedStepIDs) != 2 {
+		t.Errorf("DeletedStepIDs = %v, want 2 IDs", output.DeletedStepIDs)
+	}
+	removed := make(map[uuid.UUID]bool)
+	for _, id := range output.RemovedEdgeIDs {
+		removed[id] = true
+	}
+	for _, pair := range []string{"AB", "BC", "CD"} {
+		if !removed[edges[pair].ID] {
+			t.Errorf("edge %s should be reported as removed", pair)
+		}
+		if _, ok := edgeRepo.edges[edges[pair].ID]; ok {
+			t.Errorf("edge %s should be deleted", pair)
+		}
+	}
+	if len(output.RemovedEdgeIDs) != 3 {
+		t.Errorf("RemovedEdgeIDs = %d, want 3", len(output.RemovedEdgeIDs))
+	}
+
+	// Unrelated steps and edges are untouched
+	for _, name := range []string{"A", "D"} {
+		if _, ok := stepRepo.steps[steps[name].ID]; !ok {
+			t.Errorf("step %s should not be deleted", name)
+		}
+	}
+	if _, ok := edgeRepo.edges[edges["AD"].ID]; !ok {
+		t.Error("edge AD should not be deleted")
+	}
+}
+
+func TestStepUsecase_DeleteBatch_Validation(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name string
+		ids  func(steps map[string]*domain.Step) []uuid.UUID
+	}{
+		{
+			name: "empty",
+			ids:  func(steps map[string]*domain.Step) []uuid.UUID { return nil },
+		},
+		{
+			name: "step from another workflow",
+			ids: func(steps map[string]*domain.Step) []uuid.UUID {
+				return []uuid.UUID{steps["A"].ID, uuid.New()}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, stepRepo, edgeRepo, project, steps, _ := seedBatchDeleteProject(tenantID)
+
+			_, err := uc.DeleteBatch(context.Background(), tenantID, project.ID, tt.ids(steps))
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("DeleteBatch() error = %v, want ValidationError", err)
+			}
+			if len(stepRepo.steps) != 4 || len(edgeRepo.edges) != 4 {
+				t.Errorf("nothing should be deleted, have %d steps and %d edges", len(stepRepo.steps), len(edgeRepo.edges))
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/souta/ai-orchestration/internal/domain"
@@ -16,6 +18,8 @@ type TemplateUsecase struct {
 	projectRepo  repository.ProjectRepository
 	stepRepo     repository.StepRepository
 	edgeRepo     repository.EdgeRepository
+	usageRepo    repository.TemplateUsageRepository
+	favoriteRepo repository.FavoriteRepository
 }
 
 // NewTemplateUsecase creates a new TemplateUsecase
@@ -35,6 +39,13 @@ func NewTemplateUsecase(
 	}
 }
 
+// WithUsageRepo sets the template usage repository.
+// When set, UseTemplate records who used a template and AddReview only accepts reviews from those users.
+func (u *TemplateUsecase) WithUsageRepo(repo repository.TemplateUsageRepository) *TemplateUsecase {
+	u.usageRepo = repo
+	return u
+}
+
 // CreateTemplateInput represents input for creating a template
 type CreateTemplateInput struct {
 	TenantID    uuid.UUID
@@ -149,6 +160,22 @@ func (u *TemplateUsecase) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pr
 	return u.templateRepo.GetByID(ctx, id)
 }
 
+// Preview returns a sanitized structural view of a template without creating a project.
+// Step configs, variable values, and credential IDs are stripped.
+func (u *TemplateUsecase) Preview(ctx context.Context, tenantID, id uuid.UUID) (*domain.TemplatePreview, error) {
+	template, err := u.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user can view this template
+	if !template.CanView(tenantID) {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	return domain.NewTemplatePreview(template)
+}
+
 // ListTemplatesInput represents input for listing templates
 type ListTemplatesInput struct {
 	TenantID   *uuid.UUID
@@ -158,20 +185,40 @@ type ListTemplatesInput struct {
 	IsFeatured *bool
 	MinRating  *float64
 	Visibility *domain.TemplateVisibility
-	Page       int
-	Limit      int
+	Sort       domain.TemplateSort
+	// FavoritesOf restricts results to templates favorited by this user (nil = no restriction)
+	FavoritesOf *FavoriteOwner
+	Cursor      string // Opaque cursor returned as NextCursor by a previous page; replaces Page
+	Page        int
+	Limit       int
 }
 
 // ListTemplatesOutput represents output for listing templates
 type ListTemplatesOutput struct {
-	Templates []*domain.ProjectTemplate
-	Page      int
-	Limit     int
-	Total     int
+	Templates  []*domain.ProjectTemplate
+	Page       int
+	Limit      int
+	Total      int
+	NextCursor string // Empty on the last page
 }
 
-// List lists templates
+// List lists templates in the order of the sort. Since the rankings change, the cursor of the
+// next page holds its page number rather than a keyset position.
 func (u *TemplateUsecase) List(ctx context.Context, input ListTemplatesInput) (*ListTemplatesOutput, error) {
+	if !input.Sort.IsValid() {
+		return nil, domain.NewValidationError("sort", "sort must be one of rating, popularity, recent")
+	}
+	if input.MinRating != nil && (*input.MinRating < 0 || *input.MinRating > 5) {
+		return nil, domain.NewValidationError("min_rating", "min_rating must be between 0 and 5")
+	}
+	if input.Cursor != "" {
+		page, err := decodePageCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		input.Page = page
+	}
+
 	filter := repository.TemplateFilter{
 		Category:   input.Category,
 		Tags:       input.Tags,
@@ -179,9 +226,17 @@ func (u *TemplateUsecase) List(ctx context.Context, input ListTemplatesInput) (*
 		IsFeatured: input.IsFeatured,
 		MinRating:  input.MinRating,
 		Visibility: input.Visibility,
+		Sort:       input.Sort,
 		Page:       input.Page,
 		Limit:      input.Limit,
 	}
+	if input.FavoritesOf != nil {
+		ids, err := favoriteIDs(ctx, u.favoriteRepo, *input.FavoritesOf, domain.FavoriteTargetTemplate)
+		if err != nil {
+			return nil, err
+		}
+		filter.IDs = ids
+	}
 
 	var templates []*domain.ProjectTemplate
 	var total int
@@ -197,12 +252,17 @@ func (u *TemplateUsecase) List(ctx context.Context, input ListTemplatesInput) (*
 		return nil, err
 	}
 
-	return &ListTemplatesOutput{
+	output := &ListTemplatesOutput{
 		Templates: templates,
 		Page:      input.Page,
 		Limit:     input.Limit,
 		Total:     total,
-	}, nil
+	}
+	page := max(input.Page, 1)
+	if input.Limit > 0 && len(templates) > 0 && page*input.Limit < total {
+		output.NextCursor = encodePageCursor(page + 1)
+	}
+	return output, nil
 }
 
 // UpdateTemplateInput represents input for updating a template
@@ -275,7 +335,7 @@ func (u *TemplateUsecase) Delete(ctx context.Context, tenantID, id uuid.UUID) er
 }
 
 // UseTemplate creates a new project from a template
-func (u *TemplateUsecase) UseTemplate(ctx context.Context, tenantID uuid.UUID, templateID uuid.UUID, projectName string) (*domain.Project, error) {
+func (u *TemplateUsecase) UseTemplate(ctx context.Context, tenantID uuid.UUID, userID uuid.UUID, templateID uuid.UUID, projectName string) (*domain.Project, error) {
 	template, err := u.templateRepo.GetByID(ctx, templateID)
 	if err != nil {
 		return nil, err
@@ -351,46 +411,92 @@ func (u *TemplateUsecase) UseTemplate(ctx context.Context, tenantID uuid.UUID, t
 		// Non-critical error, log but don't fail
 	}
 
+	// Record the usage so the user may review the template
+	if u.usageRepo != nil {
+		if err := u.usageRepo.Create(ctx, domain.NewTemplateUsage(templateID, tenantID, userID, project.ID)); err != nil {
+			slog.Warn("failed to record template usage", "template_id", templateID, "user_id", userID, "error", err)
+		}
+	}
+
 	return project, nil
 }
 
-// AddReview adds a review to a template
-func (u *TemplateUsecase) AddReview(ctx context.Context, tenantID uuid.UUID, templateID uuid.UUID, userID uuid.UUID, rating int, comment string) (*domain.TemplateReview, error) {
-	template, err := u.templateRepo.GetByID(ctx, templateID)
+// AddReviewInput represents input for adding or editing a template review
+type AddReviewInput struct {
+	TenantID   uuid.UUID
+	TemplateID uuid.UUID
+	UserID     uuid.UUID
+	Rating     int
+	Comment    string
+	// Upsert edits the user's existing review instead of rejecting the request
+	Upsert bool
+}
+
+// AddReview adds the user's review to a template. Each user has at most one review per
+// template and must have used the template first. An existing review is edited when
+// input.Upsert is set; otherwise ErrTemplateReviewExists is returned.
+func (u *TemplateUsecase) AddReview(ctx context.Context, input AddReviewInput) (*domain.TemplateReview, error) {
+	template, err := u.templateRepo.GetByID(ctx, input.TemplateID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Check if user can view this template
-	if !template.CanView(tenantID) {
+	if !template.CanView(input.TenantID) {
 		return nil, domain.ErrTemplateNotFound
 	}
 
 	// Validate rating
-	if rating < 1 || rating > 5 {
+	if input.Rating < 1 || input.Rating > 5 {
 		return nil, domain.NewValidationError("rating", "rating must be between 1 and 5")
 	}
 
-	// Check if user already reviewed
-	existing, _ := u.reviewRepo.GetByTemplateAndUser(ctx, templateID, userID)
-	if existing != nil {
-		return nil, domain.NewValidationError("review", "user already reviewed this template")
+	// Only users who have used the template may review it
+	if u.usageRepo != nil {
+		used, err := u.usageRepo.HasUsed(ctx, input.TemplateID, input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !used {
+			return nil, domain.ErrTemplateNotUsed
+		}
 	}
 
-	review := domain.NewTemplateReview(templateID, userID, rating, comment)
-	if err := u.reviewRepo.Create(ctx, review); err != nil {
+	review := domain.NewTemplateReview(input.TemplateID, input.UserID, input.Rating, input.Comment)
+	if input.Upsert {
+		err = u.reviewRepo.Upsert(ctx, review)
+	} else {
+		// Check if user already reviewed; the unique constraint covers concurrent requests
+		existing, lookupErr := u.reviewRepo.GetByTemplateAndUser(ctx, input.TemplateID, input.UserID)
+		if lookupErr != nil && !errors.Is(lookupErr, domain.ErrTemplateReviewNotFound) {
+			return nil, lookupErr
+		}
+		if existing != nil {
+			return nil, domain.ErrTemplateReviewExists
+		}
+		err = u.reviewRepo.Create(ctx, review)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	// Update template rating
-	template.UpdateRating(rating)
-	if err := u.templateRepo.Update(ctx, template); err != nil {
+	if err := u.refreshRating(ctx, template); err != nil {
 		return nil, err
 	}
 
 	return review, nil
 }
 
+// refreshRating recomputes and stores the template's aggregate rating from its reviews
+func (u *TemplateUsecase) refreshRating(ctx context.Context, template *domain.ProjectTemplate) error {
+	reviews, err := u.reviewRepo.ListByTemplate(ctx, template.ID)
+	if err != nil {
+		return err
+	}
+	template.RecalculateRating(reviews)
+	return u.templateRepo.Update(ctx, template)
+}
+
 // GetReviews gets reviews for a template
 func (u *TemplateUsecase) GetReviews(ctx context.Context, templateID uuid.UUID) ([]*domain.TemplateReview, error) {
 	return u.reviewRepo.ListByTemplate(ctx, templateID)
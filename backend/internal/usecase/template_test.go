@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/souta/ai-orchestration/internal/domain"
+	"github.com/souta/ai-orchestration/internal/repository"
+)
+
+// mockTemplateRepo is an in-memory ProjectTemplateRepository
+type mockTemplateRepo struct {
+	templates  map[uuid.UUID]*domain.ProjectTemplate
+	lastFilter repository.TemplateFilter
+}
+
+func newMockTemplateRepo() *mockTemplateRepo {
+	return &mockTemplateRepo{templates: make(map[uuid.UUID]*domain.ProjectTemplate)}
+}
+
+func (m *mockTemplateRepo) Create(ctx context.Context, template *domain.ProjectTemplate) error {
+	m.templates[template.ID] = template
+	return nil
+}
+
+func (m *mockTemplateRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProjectTemplate, error) {
+	template, ok := m.templates[id]
+	if !ok {
+		return nil, domain.ErrTemplateNotFound
+	}
+	copied := *template
+	return &copied, nil
+}
+
+func (m *mockTemplateRepo) List(ctx context.Context, filter repository.TemplateFilter) ([]*domain.ProjectTemplate, int, error) {
+	m.lastFilter = filter
+	result := make([]*domain.ProjectTemplate, 0, len(m.templates))
+	for _, template := range m.templates {
+		if matchesIDs(template.ID, filter.IDs) {
+			result = append(result, template)
+		}
+	}
+	return result, len(result), nil
+}
+
+func (m *mockTemplateRepo) ListPublic(ctx context.Context, filter repository.TemplateFilter) ([]*domain.ProjectTemplate, int, error) {
+	return m.List(ctx, filter)
+}
+
+func (m *mockTemplateRepo) ListByTenant(ctx context.Context, tenantID uuid.UUID, filter repository.TemplateFilter) ([]*domain.ProjectTemplate, int, error) {
+	return m.List(ctx, filter)
+}
+
+func (m *mockTemplateRepo) Update(ctx context.Context, template *domain.ProjectTemplate) error {
+	if _, ok := m.templates[template.ID]; !ok {
+		return domain.ErrTemplateNotFound
+	}
+	copied := *template
+	m.templates[template.ID] = &copied
+	return nil
+}
+
+func (m *mockTemplateRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.templates, id)
+	return nil
+}
+
+func (m *mockTemplateRepo) IncrementDownloadCount(ctx context.Context, id uuid.UUID) error {
+	if template, ok := m.templates[id]; ok {
+		template.DownloadCount++
+	}
+	return nil
+}
+
+var errReviewNotFound = errors.New("review not found")
+
+// mockTemplateReviewRepo is an in-memory TemplateReviewRepository
+type mockTemplateReviewRepo struct {
+	reviews []*domain.TemplateReview
+}
+
+func (m *mockTemplateReviewRepo) Create(ctx context.Context, review *domain.TemplateReview) error {
+	m.reviews = append(m.reviews, review)
+	return nil
+}
+
+func (m *mockTemplateReviewRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.TemplateReview, error) {
+	for _, review := range m.reviews {
+		if review.ID == id {
+			return review, nil
+		}
+	}
+	return nil, errReviewNotFound
+}
+
+func (m *mockTemplateReviewRepo) ListByTemplate(ctx context.Context, templateID uuid.UUID) ([]*domain.TemplateReview, error) {
+	var result []*domain.TemplateReview
+	for _, review := range m.reviews {
+		if review.TemplateID == templateID {
+			result = append(result, review)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockTemplateReviewRepo) GetByTemplateAndUser(ctx context.Context, templateID, userID uuid.UUID) (*domain.TemplateReview, error) {
+	for _, review := range m.reviews {
+		if review.TemplateID == templateID && review.UserID == userID {
+			return review, nil
+		}
+	}
+	return nil, domain.ErrTemplateReviewNotFound
+}
+
+func (m *mockTemplateReviewRepo) Upsert(ctx context.Context, review *domain.TemplateReview) error {
+	for _, existing := range m.reviews {
+		if existing.TemplateID == review.TemplateID && existing.UserID == review.UserID {
+			existing.Rating = review.Rating
+			existing.Comment = review.Comment
+			review.ID = existing.ID
+			review.CreatedAt = existing.CreatedAt
+			return nil
+		}
+	}
+	m.reviews = append(m.reviews, review)
+	return nil
+}
+
+func (m *mockTemplateReviewRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	for i, review := range m.reviews {
+		if review.ID == id {
+			m.reviews = append(m.reviews[:i], m.reviews[i+1:]...)
+			return nil
+		}
+	}
+	return errReviewNotFound
+}
+
+// mockTemplateUsageRepo is an in-memory TemplateUsageRepository
+type mockTemplateUsageRepo struct {
+	usages []*domain.TemplateUsage
+}
+
+func (m *mockTemplateUsageRepo) Create(ctx context.Context, usage *domain.TemplateUsage) error {
+	m.usages = append(m.usages, usage)
+	return nil
+}
+
+func (m *mockTemplateUsageRepo) HasUsed(ctx context.Context, templateID, userID uuid.UUID) (bool, error) {
+	for _, usage := range m.usages {
+		if usage.TemplateID == templateID && usage.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// use records that userID created a project from the template
+func (m *mockTemplateUsageRepo) use(templateID, userID uuid.UUID) {
+	m.usages = append(m.usages, domain.NewTemplateUsage(templateID, uuid.New(), userID, uuid.New()))
+}
+
+func setupTemplateUsecase(t *testing.T) (*TemplateUsecase, *mockTemplateRepo, *mockTemplateUsageRepo, *domain.ProjectTemplate) {
+	t.Helper()
+	templateRepo := newMockTemplateRepo()
+	template := domain.NewProjectTemplate(nil, "Public Template", "", json.RawMessage(`{}`))
+	template.Visibility = domain.TemplateVisibilityPublic
+	templateRepo.templates[template.ID] = template
+
+	usageRepo := &mockTemplateUsageRepo{}
+	uc := NewTemplateUsecase(templateRepo, &mockTemplateReviewRepo{}, nil, nil, nil).WithUsageRepo(usageRepo)
+	return uc, templateRepo, usageRepo, template
+}
+
+func TestTemplateUsecase_AddReview_RecomputesAggregate(t *testing.T) {
+	uc, templateRepo, usageRepo, template := setupTemplateUsecase(t)
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	for _, rating := range []int{5, 4, 2} {
+		userID := uuid.New()
+		usageRepo.use(template.ID, userID)
+		input := AddReviewInput{TenantID: tenantID, TemplateID: template.ID, UserID: userID, Rating: rating}
+		if _, err := uc.AddReview(ctx, input); err != nil {
+			t.Fatalf("AddReview(%d) error = %v", rating, err)
+		}
+	}
+
+	stored := templateRepo.templates[template.ID]
+	if stored.ReviewCount != 3 {
+		t.Errorf("ReviewCount = %d, want 3", stored.ReviewCount)
+	}
+	if stored.Rating == nil {
+		t.Fatal("Rating is nil, want average")
+	}
+	if *stored.Rating < 3.66 || *stored.Rating > 3.67 {
+		t.Errorf("Rating = %v, want ~3.67", *stored.Rating)
+	}
+}
+
+func TestTemplateUsecase_List_ValidatesSortAndMinRating(t *testing.T) {
+	uc, templateRepo, _, _ := setupTemplateUsecase(t)
+	ctx := context.Background()
+
+	var validationErr domain.ValidationError
+	if _, err := uc.List(ctx, ListTemplatesInput{Sort: "alphabetical"}); !errors.As(err, &validationErr) || validationErr.Field != "sort" {
+		t.Errorf("invalid sort: error = %v, want validation error", err)
+	}
+
+	tooHigh := 6.0
+	if _, err := uc.List(ctx, ListTemplatesInput{MinRating: &tooHigh}); !errors.As(err, &validationErr) || validationErr.Field != "min_rating" {
+		t.Errorf("min_rating out of range: error = %v, want validation error", err)
+	}
+
+	minRating := 4.0
+	if _, err := uc.List(ctx, ListTemplatesInput{Sort: domain.TemplateSortRating, MinRating: &minRating}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if templateRepo.lastFilter.Sort != domain.TemplateSortRating {
+		t.Errorf("filter.Sort = %q, want %q", templateRepo.lastFilter.Sort, domain.TemplateSortRating)
+	}
+	if templateRepo.lastFilter.MinRating == nil || *templateRepo.lastFilter.MinRating != 4.0 {
+		t.Errorf("filter.MinRating = %v, want 4", templateRepo.lastFilter.MinRating)
+	}
+}
+
+func TestTemplateUsecase_AddReview_OnePerUser(t *testing.T) {
+	uc, templateRepo, usageRepo, template := setupTemplateUsecase(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	usageRepo.use(template.ID, userID)
+
+	input := AddReviewInput{TenantID: uuid.New(), TemplateID: template.ID, UserID: userID, Rating: 5}
+	if _, err := uc.AddReview(ctx, input); err != nil {
+		t.Fatalf("first AddReview() error = %v", err)
+	}
+
+	input.Rating = 1
+	if _, err := uc.AddReview(ctx, input); !errors.Is(err, domain.ErrTemplateReviewExists) {
+		t.Fatalf("second AddReview() error = %v, want ErrTemplateReviewExists", err)
+	}
+
+	stored := templateRepo.templates[template.ID]
+	if stored.ReviewCount != 1 || stored.Rating == nil || *stored.Rating != 5 {
+		t.Errorf("aggregate = (%d, %v), want (1, 5)", stored.ReviewCount, stored.Rating)
+	}
+}
+
+// failingReviewLookupRepo fails to look up existing reviews
+type failingReviewLookupRepo struct {
+	mockTemplateReviewRepo
+}
+
+func (m *failingReviewLookupRepo) GetByTemplateAndUser(ctx context.Context, templateID, userID uuid.UUID) (*domain.TemplateReview, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTemplateUsecase_AddReview_LookupFailure(t *testing.T) {
+	templateRepo := newMockTemplateRepo()
+	template := domain.NewProjectTemplate(nil, "Public Template", "", json.RawMessage(`{}`))
+	template.Visibility = domain.TemplateVisibilityPublic
+	templateRepo.templates[template.ID] = template
+	usageRepo := &mockTemplateUsageRepo{}
+	reviewRepo := &failingReviewLookupRepo{}
+	uc := NewTemplateUsecase(templateRepo, reviewRepo, nil, nil, nil).WithUsageRepo(usageRepo)
+	userID := uuid.New()
+	usageRepo.use(template.ID, userID)
+
+	_, err := uc.AddReview(context.Background(), AddReviewInput{TenantID: uuid.New(), TemplateID: template.ID, UserID: userID, Rating: 5})
+	if err == nil || err.Error() != "connection refused" {
+		t.Fatalf("AddReview() error = %v, want the lookup error", err)
+	}
+	if len(reviewRepo.reviews) != 0 {
+		t.Errorf("%d reviews created after a failed lookup, want none", len(reviewRepo.reviews))
+	}
+}
+
+func TestTemplateUsecase_AddReview_EditOwn(t *testing.T) {
+	uc, templateRepo, usageRepo, template := setupTemplateUsecase(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	usageRepo.use(template.ID, userID)
+
+	input := AddReviewInput{TenantID: uuid.New(), TemplateID: template.ID, UserID: userID, Rating: 2, Comment: "meh"}
+	original, err := uc.AddReview(ctx, input)
+	if err != nil {
+		t.Fatalf("AddReview() error = %v", err)
+	}
+
+	input.Rating = 4
+	input.Comment = "better after the update"
+	input.Upsert = true
+	edited, err := uc.AddReview(ctx, input)
+	if err != nil {
+		t.Fatalf("AddReview(upsert) error = %v", err)
+	}
+	if edited.ID != original.ID {
+		t.Errorf("edited review ID = %s, want existing %s", edited.ID, original.ID)
+	}
+
+	reviews, _ := uc.GetReviews(ctx, template.ID)
+	if len(reviews) != 1 || reviews[0].Rating != 4 || reviews[0].Comment != "better after the update" {
+		t.Fatalf("reviews = %+v, want single edited review", reviews)
+	}
+	stored := templateRepo.templates[template.ID]
+	if stored.ReviewCount != 1 || stored.Rating == nil || *stored.Rating != 4 {
+		t.Errorf("aggregate = (%d, %v), want (1, 4)", stored.ReviewCount, stored.Rating)
+	}
+}
+
+func TestTemplateUsecase_AddReview_RequiresUse(t *testing.T) {
+	uc, _, _, template := setupTemplateUsecase(t)
+
+	input := AddReviewInput{TenantID: uuid.New(), TemplateID: template.ID, UserID: uuid.New(), Rating: 5, Upsert: true}
+	if _, err := uc.AddReview(context.Background(), input); !errors.Is(err, domain.ErrTemplateNotUsed) {
+		t.Fatalf("AddReview() error = %v, want ErrTemplateNotUsed", err)
+	}
+}
@@ -0,0 +1,5 @@
+-- Rollback: 016_project_tags.sql
+
+DROP INDEX IF EXISTS idx_projects_tags;
+
+ALTER TABLE projects DROP COLUMN IF EXISTS tags;
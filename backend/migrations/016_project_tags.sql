@@ -0,0 +1,11 @@
+-- Project Tags Migration
+-- Adds organizational tags to projects for tag-based listing
+-- Migration: 016_project_tags.sql
+
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN projects.tags IS 'Organizational tags, stored lowercase for case-insensitive matching';
+
+-- GIN index for array containment queries (tags @> ARRAY[...])
+CREATE INDEX IF NOT EXISTS idx_projects_tags ON projects USING GIN(tags);
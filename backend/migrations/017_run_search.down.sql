@@ -0,0 +1,6 @@
+-- Rollback: 017_run_search.sql
+
+DROP INDEX IF EXISTS idx_runs_trigger_metadata_gin;
+DROP INDEX IF EXISTS idx_runs_output_gin;
+DROP INDEX IF EXISTS idx_runs_input_gin;
+DROP INDEX IF EXISTS idx_runs_tenant_created;
@@ -0,0 +1,11 @@
+-- Run Search Migration
+-- Adds indexes for tenant-wide run search with JSONB containment filters
+-- Migration: 017_run_search.sql
+
+-- Keyset pagination over a tenant's runs (ORDER BY created_at DESC, id DESC)
+CREATE INDEX IF NOT EXISTS idx_runs_tenant_created ON runs(tenant_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- GIN indexes for JSONB containment queries (input @> '{...}')
+CREATE INDEX IF NOT EXISTS idx_runs_input_gin ON runs USING GIN(input jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_runs_output_gin ON runs USING GIN(output jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_runs_trigger_metadata_gin ON runs USING GIN(trigger_metadata jsonb_path_ops);
@@ -0,0 +1,4 @@
+-- Rollback: 018_template_usages.sql
+
+DROP INDEX IF EXISTS idx_template_usages_template_user;
+DROP TABLE IF EXISTS template_usages;
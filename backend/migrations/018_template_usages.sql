@@ -0,0 +1,14 @@
+-- Template Usages Migration
+-- Records which user created a project from a template, so only real users can review it
+-- Migration: 018_template_usages.sql
+
+CREATE TABLE IF NOT EXISTS template_usages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL REFERENCES project_templates(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_template_usages_template_user ON template_usages(template_id, user_id);
@@ -0,0 +1,4 @@
+-- Rollback: 019_favorites.sql
+
+DROP INDEX IF EXISTS idx_favorites_target;
+DROP TABLE IF EXISTS favorites;
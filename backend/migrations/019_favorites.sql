@@ -0,0 +1,14 @@
+-- Favorites Migration
+-- Per-user favorites (pinned workflows and templates) within a tenant
+-- Migration: 019_favorites.sql
+
+CREATE TABLE IF NOT EXISTS favorites (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('project', 'template')),
+    target_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, user_id, target_type, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_target ON favorites(target_type, target_id);
@@ -0,0 +1,4 @@
+-- Rollback: 020_run_annotations.sql
+
+DROP INDEX IF EXISTS idx_run_annotations_run;
+DROP TABLE IF EXISTS run_annotations;
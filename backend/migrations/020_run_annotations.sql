@@ -0,0 +1,16 @@
+-- Run Annotations Migration
+-- User notes on runs (optionally referencing a step) for collaborative debugging
+-- Migration: 020_run_annotations.sql
+
+CREATE TABLE IF NOT EXISTS run_annotations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    run_id UUID NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    step_id UUID,
+    author_id UUID NOT NULL,
+    comment TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_run_annotations_run ON run_annotations(tenant_id, run_id, created_at);
@@ -0,0 +1,5 @@
+-- Rollback: 021_copilot_session_memory.sql
+
+ALTER TABLE copilot_sessions
+    DROP COLUMN IF EXISTS memory_cleared_at,
+    DROP COLUMN IF EXISTS memory_window;
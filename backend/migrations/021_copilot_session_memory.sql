@@ -0,0 +1,7 @@
+-- Copilot Session Memory Migration
+-- Per-session agent memory window and memory reset marker
+-- Migration: 021_copilot_session_memory.sql
+
+ALTER TABLE copilot_sessions
+    ADD COLUMN IF NOT EXISTS memory_window INTEGER CHECK (memory_window IS NULL OR memory_window > 0),
+    ADD COLUMN IF NOT EXISTS memory_cleared_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Rollback: 022_vector_collection_metric.sql
+
+ALTER TABLE vector_collections
+    DROP COLUMN IF EXISTS distance_metric;
@@ -0,0 +1,7 @@
+-- Vector Collection Metric Migration
+-- Distance metric per vector collection (cosine, l2, inner_product)
+-- Migration: 022_vector_collection_metric.sql
+
+ALTER TABLE vector_collections
+    ADD COLUMN IF NOT EXISTS distance_metric VARCHAR(20) NOT NULL DEFAULT 'cosine'
+        CHECK (distance_metric IN ('cosine', 'l2', 'inner_product'));
@@ -0,0 +1,4 @@
+-- Rollback: 023_run_checkpoints.sql
+
+DROP INDEX IF EXISTS idx_run_checkpoints_run;
+DROP TABLE IF EXISTS run_checkpoints;
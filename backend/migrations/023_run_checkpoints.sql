@@ -0,0 +1,17 @@
+-- Run Checkpoints Migration
+-- Accumulated step outputs saved when a step marked `checkpoint: true` completes,
+-- so a failed run can resume downstream of its last checkpoint
+-- Migration: 023_run_checkpoints.sql
+
+CREATE TABLE IF NOT EXISTS run_checkpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    run_id UUID NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    step_id UUID NOT NULL,
+    output_port VARCHAR(100) NOT NULL DEFAULT 'output',
+    outputs JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (run_id, step_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_run_checkpoints_run ON run_checkpoints(tenant_id, run_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- Rollback: 024_seed_content_hash.sql
+
+ALTER TABLE project_versions
+    DROP COLUMN IF EXISTS content_hash;
+
+ALTER TABLE block_versions
+    DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,9 @@
+-- Seed Content Hash Migration
+-- Content hash of the seeded definition on version records, used by the seeder to detect changes
+-- Migration: 024_seed_content_hash.sql
+
+ALTER TABLE block_versions
+    ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+
+ALTER TABLE project_versions
+    ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
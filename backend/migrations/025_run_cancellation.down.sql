@@ -0,0 +1,5 @@
+-- Rollback: 025_run_cancellation.sql
+
+ALTER TABLE runs
+    DROP COLUMN IF EXISTS cancel_reason,
+    DROP COLUMN IF EXISTS cancelled_by;
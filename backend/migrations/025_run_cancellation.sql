@@ -0,0 +1,10 @@
+-- Run Cancellation Migration
+-- Who cancelled a run and why, to tell user cancellations from automated ones
+-- Migration: 025_run_cancellation.sql
+
+ALTER TABLE runs
+    ADD COLUMN IF NOT EXISTS cancelled_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT;
+
+COMMENT ON COLUMN runs.cancelled_by IS 'User who cancelled the run; NULL for automated cancellations';
+COMMENT ON COLUMN runs.cancel_reason IS 'Reason given when the run was cancelled';
@@ -0,0 +1,4 @@
+-- Rollback: 026_run_summary.sql
+
+ALTER TABLE runs
+    DROP COLUMN IF EXISTS summary;
@@ -0,0 +1,36 @@
+-- Run Summary Migration
+-- Aggregates persisted on the run at completion, so list and dashboard views need not join step runs
+-- Migration: 026_run_summary.sql
+
+ALTER TABLE runs
+    ADD COLUMN IF NOT EXISTS summary JSONB;
+
+COMMENT ON COLUMN runs.summary IS 'Aggregates at completion: step_count, failed_step_count, total_cost_usd, total_tokens, total_duration_ms, output_preview';
+
+-- Backfill finished runs. The output preview is taken from the jsonb text form, which may differ
+-- in whitespace from the compact form written by the worker.
+UPDATE runs r
+SET summary = (
+    SELECT jsonb_strip_nulls(jsonb_build_object(
+        'step_count', s.step_count,
+        'failed_step_count', s.failed_step_count,
+        'total_cost_usd', u.total_cost_usd,
+        'total_tokens', u.total_tokens,
+        'total_duration_ms', s.total_duration_ms,
+        'output_preview', NULLIF(left(r.output::text, 200), 'null')
+    ))
+    FROM (
+        SELECT COUNT(*) AS step_count,
+               COUNT(*) FILTER (WHERE status = 'failed') AS failed_step_count,
+               COALESCE(SUM(duration_ms), 0) AS total_duration_ms
+        FROM step_runs
+        WHERE run_id = r.id
+    ) s, (
+        SELECT COALESCE(SUM(total_tokens), 0) AS total_tokens,
+               COALESCE(SUM(total_cost_usd), 0) AS total_cost_usd
+        FROM usage_records
+        WHERE run_id = r.id
+    ) u
+)
+WHERE r.summary IS NULL
+  AND r.status IN ('completed', 'failed', 'cancelled');
@@ -0,0 +1,10 @@
+-- Rollback: 027_run_retry.sql
+
+DROP INDEX IF EXISTS idx_runs_retry_of;
+
+ALTER TABLE runs
+    DROP COLUMN IF EXISTS retry_attempt,
+    DROP COLUMN IF EXISTS retry_of_run_id;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS run_retry;
@@ -0,0 +1,18 @@
+-- Run Retry Migration
+-- Opt-in per-project policy that re-runs the whole workflow after a run fails with a retryable
+-- error, and the link from each automatic retry to the run it retries
+-- Migration: 027_run_retry.sql
+
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS run_retry JSONB;
+
+COMMENT ON COLUMN projects.run_retry IS 'Run retry policy: {"max_attempts": 3, "backoff_seconds": 30, "backoff_multiplier": 2, "retry_on": ["timeout", ...]}; NULL disables retries';
+
+ALTER TABLE runs
+    ADD COLUMN IF NOT EXISTS retry_of_run_id UUID REFERENCES runs(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS retry_attempt INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN runs.retry_of_run_id IS 'First run of the chain this automatic retry belongs to';
+COMMENT ON COLUMN runs.retry_attempt IS 'Number of automatic retries before this run (0 for the original run)';
+
+CREATE INDEX IF NOT EXISTS idx_runs_retry_of ON runs (retry_of_run_id) WHERE retry_of_run_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Rollback: 028_output_retention.sql
+
+DROP INDEX IF EXISTS idx_step_runs_outputs_retained;
+
+ALTER TABLE step_runs
+    DROP COLUMN IF EXISTS outputs_cleared_at;
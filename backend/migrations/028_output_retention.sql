@@ -0,0 +1,11 @@
+-- Output Retention Migration
+-- Marks step runs whose input/output payloads were cleared by the tenant's output retention
+-- window, while the step run record (status, timing, error) is kept
+-- Migration: 028_output_retention.sql
+
+ALTER TABLE step_runs
+    ADD COLUMN IF NOT EXISTS outputs_cleared_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN step_runs.outputs_cleared_at IS 'When output retention cleared input, output and streaming_output; NULL while the payloads are kept';
+
+CREATE INDEX IF NOT EXISTS idx_step_runs_outputs_retained ON step_runs (tenant_id, created_at) WHERE outputs_cleared_at IS NULL;
@@ -0,0 +1,4 @@
+-- Rollback: 029_project_permissions.sql
+
+DROP INDEX IF EXISTS idx_project_permissions_tenant;
+DROP TABLE IF EXISTS project_permissions;
@@ -0,0 +1,17 @@
+-- Project Permissions Migration
+-- Per-user workflow access (owner, editor, runner, viewer). A workflow without permissions is
+-- open to every user of the tenant; granting the first permission restricts it to the listed users
+-- Migration: 029_project_permissions.sql
+
+CREATE TABLE IF NOT EXISTS project_permissions (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'runner', 'viewer')),
+    granted_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_permissions_tenant ON project_permissions(tenant_id, project_id);
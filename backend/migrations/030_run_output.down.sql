@@ -0,0 +1,4 @@
+-- Rollback: 030_run_output.sql
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS run_output;
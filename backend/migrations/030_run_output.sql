@@ -0,0 +1,9 @@
+-- Run Output Migration
+-- Optional per-project selection of the run output: one designated step, or a mapping of output
+-- fields to steps, instead of the terminal step heuristic
+-- Migration: 030_run_output.sql
+
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS run_output JSONB;
+
+COMMENT ON COLUMN projects.run_output IS 'Run output selection: {"step_id": "..."} or {"mapping": {"field": "<step_id>", ...}}; NULL uses the terminal steps';
@@ -0,0 +1,4 @@
+-- Rollback: 031_notification_channels.sql
+
+DROP INDEX IF EXISTS idx_notification_channels_tenant;
+DROP TABLE IF EXISTS notification_channels;
@@ -0,0 +1,19 @@
+-- Notification Channels Migration
+-- Per-tenant destinations (Slack, email, webhook) for system event notifications such as budget
+-- alerts, run failures and approval requests
+-- Migration: 031_notification_channels.sql
+
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('slack', 'email', 'webhook')),
+    config JSONB NOT NULL DEFAULT '{}',
+    events TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_channels_tenant ON notification_channels(tenant_id);
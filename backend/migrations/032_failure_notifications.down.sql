@@ -0,0 +1,4 @@
+-- Rollback: 032_failure_notifications.sql
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS failure_notifications;
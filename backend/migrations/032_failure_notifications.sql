@@ -0,0 +1,9 @@
+-- Failure Notifications Migration
+-- Optional per-project rules for notifying a channel when a run fails: on every failure, every
+-- N consecutive failures, or only for some error categories
+-- Migration: 032_failure_notifications.sql
+
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS failure_notifications JSONB;
+
+COMMENT ON COLUMN projects.failure_notifications IS 'Run failure notification rules: [{"channel_id": "...", "consecutive_failures": N, "categories": [...]}]; NULL notifies the tenant channels subscribed to run.failed';
@@ -0,0 +1,4 @@
+-- Rollback: 033_schedule_bulk_pause.sql
+
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS bulk_paused;
@@ -0,0 +1,9 @@
+-- Schedule Bulk Pause Migration
+-- Marks the schedules paused by a tenant-wide pause-all so that resume-all reactivates only the
+-- schedules that were active before
+-- Migration: 033_schedule_bulk_pause.sql
+
+ALTER TABLE schedules
+    ADD COLUMN IF NOT EXISTS bulk_paused BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN schedules.bulk_paused IS 'True while the schedule is paused by pause-all; resume-all reactivates only these schedules';
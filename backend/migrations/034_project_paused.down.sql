@@ -0,0 +1,4 @@
+-- Rollback: 034_project_paused.sql
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS paused;
@@ -0,0 +1,9 @@
+-- Project Paused Migration
+-- A single switch that stops a project from starting runs through any trigger (API, webhook,
+-- schedule) without deleting it; in-flight runs continue
+-- Migration: 034_project_paused.sql
+
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS paused BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN projects.paused IS 'True while the project is paused: new runs are rejected and due schedules are skipped';
@@ -0,0 +1,4 @@
+-- Rollback: 035_block_examples.sql
+
+ALTER TABLE block_definitions
+    DROP COLUMN IF EXISTS examples;
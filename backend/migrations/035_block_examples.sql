@@ -0,0 +1,9 @@
+-- Block Examples Migration
+-- Sample config, input and expected output of a block, shown in the block palette and used by
+-- the copilot when it configures a step
+-- Migration: 035_block_examples.sql
+
+ALTER TABLE block_definitions
+    ADD COLUMN IF NOT EXISTS examples JSONB;
+
+COMMENT ON COLUMN block_definitions.examples IS 'Array of {name, config, input, output} examples; NULL when the block has none';
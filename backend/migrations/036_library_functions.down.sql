@@ -0,0 +1,3 @@
+-- Rollback: 036_library_functions.sql
+
+DROP TABLE IF EXISTS library_functions;
@@ -0,0 +1,15 @@
+-- Library Functions Migration
+-- Per-tenant JavaScript helpers that function steps load by name with require()
+-- Migration: 036_library_functions.sql
+
+CREATE TABLE IF NOT EXISTS library_functions (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    code TEXT NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
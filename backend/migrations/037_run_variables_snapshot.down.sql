@@ -0,0 +1,4 @@
+-- Rollback: 037_run_variables_snapshot.sql
+
+ALTER TABLE runs
+    DROP COLUMN IF EXISTS variables_snapshot;
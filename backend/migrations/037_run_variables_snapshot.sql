@@ -0,0 +1,9 @@
+-- Run Variables Snapshot Migration
+-- Organization, project and personal variables captured when a run is created, so execution,
+-- resumes and retries use the same values even after the live variables change
+-- Migration: 037_run_variables_snapshot.sql
+
+ALTER TABLE runs
+    ADD COLUMN IF NOT EXISTS variables_snapshot JSONB;
+
+COMMENT ON COLUMN runs.variables_snapshot IS 'Organization, project and personal variables at run creation: {"org": {...}, "project": {...}, "personal": {...}}; secret variables stay encrypted';
@@ -0,0 +1,4 @@
+-- Rollback: 038_step_run_evaluation.sql
+
+ALTER TABLE step_runs
+    DROP COLUMN IF EXISTS evaluation;
@@ -0,0 +1,9 @@
+-- Step Run Evaluation Migration
+-- Condition and switch steps pass their input through to the selected branch; how the branch was
+-- chosen is recorded on the step run instead of in the output
+-- Migration: 038_step_run_evaluation.sql
+
+ALTER TABLE step_runs
+    ADD COLUMN IF NOT EXISTS evaluation JSONB;
+
+COMMENT ON COLUMN step_runs.evaluation IS 'How a condition or switch step chose its branch, e.g. {"expression": "...", "result": true}; NULL for other steps';
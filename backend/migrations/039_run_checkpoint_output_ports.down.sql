@@ -0,0 +1,4 @@
+-- Rollback: 039_run_checkpoint_output_ports.sql
+
+ALTER TABLE run_checkpoints
+    DROP COLUMN IF EXISTS output_ports;
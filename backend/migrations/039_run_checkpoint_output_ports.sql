@@ -0,0 +1,9 @@
+-- Run Checkpoint Output Ports Migration
+-- The output port every checkpointed step and block group completed with, so a resumed run
+-- continues each parallel branch that had not finished, not only the checkpoint step's
+-- Migration: 039_run_checkpoint_output_ports.sql
+
+ALTER TABLE run_checkpoints
+    ADD COLUMN IF NOT EXISTS output_ports JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN run_checkpoints.output_ports IS 'Step/block group ID -> output port it completed with; empty for checkpoints saved before this column';
@@ -0,0 +1,4 @@
+-- Rollback: 040_step_run_item_index.sql
+
+ALTER TABLE step_runs
+    DROP COLUMN IF EXISTS item_index;
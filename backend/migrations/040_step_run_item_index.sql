@@ -0,0 +1,9 @@
+-- Step Run Item Index Migration
+-- The steps on a streaming map's "item" port run once per item; each of those runs is recorded
+-- as its own step run with the index of the item
+-- Migration: 040_step_run_item_index.sql
+
+ALTER TABLE step_runs
+    ADD COLUMN IF NOT EXISTS item_index INTEGER;
+
+COMMENT ON COLUMN step_runs.item_index IS 'Index of the map item a streaming map''s item step ran for; NULL for other step runs';
@@ -0,0 +1,221 @@
+// Package migrate applies versioned SQL schema migrations and tracks them in a schema_migrations table.
+//
+// Migration files are named NNN_description.sql (up) with an optional NNN_description.down.sql
+// (down). Files that do not start with a version number, such as init.sql, are ignored.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrNoDownMigration is returned when rolling back a migration that has no .down.sql file
+var ErrNoDownMigration = errors.New("migration has no down file")
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	UpSQL   string
+	DownSQL string // Empty when the migration cannot be rolled back
+}
+
+// AppliedMigration is a row in the schema_migrations table
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// Status describes whether a known migration has been applied
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	HasDown   bool
+}
+
+// Store persists applied versions and executes migration SQL
+type Store interface {
+	// Lock blocks until the migration lock is held and returns a function that releases it
+	Lock(ctx context.Context) (unlock func(context.Context) error, err error)
+	// EnsureVersionTable creates the schema_migrations table if it does not exist
+	EnsureVersionTable(ctx context.Context) error
+	// Applied returns the applied migrations keyed by version
+	Applied(ctx context.Context) (map[int]AppliedMigration, error)
+	// Apply runs the up SQL and records the version atomically
+	Apply(ctx context.Context, m Migration) error
+	// Revert runs the down SQL and removes the version record atomically
+	Revert(ctx context.Context, m Migration) error
+}
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_\-]+?)(\.down)?\.sql$`)
+
+// Load reads migrations from the root of fsys, sorted by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	downs := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version %q: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		if match[3] != "" {
+			if _, dup := downs[version]; dup {
+				return nil, fmt.Errorf("duplicate down migration for version %d", version)
+			}
+			downs[version] = string(content)
+			continue
+		}
+		if existing, dup := byVersion[version]; dup {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, existing.Name, match[2])
+		}
+		byVersion[version] = &Migration{Version: version, Name: match[2], UpSQL: string(content)}
+	}
+
+	for version, down := range downs {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down migration for version %d has no matching up migration", version)
+		}
+		m.DownSQL = down
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies and rolls back migrations against a Store
+type Migrator struct {
+	store      Store
+	migrations []Migration
+}
+
+// NewMigrator creates a new Migrator. migrations must be sorted by version (as returned by Load).
+func NewMigrator(store Store, migrations []Migration) *Migrator {
+	return &Migrator{store: store, migrations: migrations}
+}
+
+// Up applies all pending migrations in version order and returns the ones applied.
+// Re-running Up when everything is applied is a no-op.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(done map[int]AppliedMigration) error {
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.store.Apply(ctx, migration); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the most recently applied migrations, newest first, and returns the ones reverted.
+// steps < 1 is treated as 1.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		steps = 1
+	}
+
+	var reverted []Migration
+	err := m.withLock(ctx, func(done map[int]AppliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.DownSQL == "" {
+				return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, ErrNoDownMigration)
+			}
+			if err := m.store.Revert(ctx, migration); err != nil {
+				return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status reports the applied state of every known migration
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.store.EnsureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			HasDown: migration.DownSQL != "",
+		}
+		if applied, ok := done[migration.Version]; ok {
+			appliedAt := applied.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// withLock holds the migration lock while fn runs with a fresh view of the applied versions,
+// so concurrent runners never apply the same migration twice
+func (m *Migrator) withLock(ctx context.Context, fn func(done map[int]AppliedMigration) error) (err error) {
+	unlock, err := m.store.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+			err = fmt.Errorf("release migration lock: %w", unlockErr)
+		}
+	}()
+
+	if err := m.store.EnsureVersionTable(ctx); err != nil {
+		return err
+	}
+	done, err := m.store.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(done)
+}
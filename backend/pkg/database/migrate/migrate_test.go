@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store that records executed SQL
+type memoryStore struct {
+	applied  map[int]AppliedMigration
+	executed []string
+	locked   bool
+	failOn   int // Version whose Apply fails
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{applied: make(map[int]AppliedMigration)}
+}
+
+func (s *memoryStore) Lock(ctx context.Context) (func(context.Context) error, error) {
+	if s.locked {
+		return nil, errors.New("lock already held")
+	}
+	s.locked = true
+	return func(context.Context) error {
+		s.locked = false
+		return nil
+	}, nil
+}
+
+func (s *memoryStore) EnsureVersionTable(ctx context.Context) error { return nil }
+
+func (s *memoryStore) Applied(ctx context.Context) (map[int]AppliedMigration, error) {
+	copied := make(map[int]AppliedMigration, len(s.applied))
+	for k, v := range s.applied {
+		copied[k] = v
+	}
+	return copied, nil
+}
+
+func (s *memoryStore) Apply(ctx context.Context, m Migration) error {
+	if m.Version == s.failOn {
+		return errors.New("syntax error")
+	}
+	s.executed = append(s.executed, m.UpSQL)
+	s.applied[m.Version] = AppliedMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}
+	return nil
+}
+
+func (s *memoryStore) Revert(ctx context.Context, m Migration) error {
+	s.executed = append(s.executed, m.DownSQL)
+	delete(s.applied, m.Version)
+	return nil
+}
+
+func testMigrations(t *testing.T) []Migration {
+	t.Helper()
+	migrations, err := Load(fstest.MapFS{
+		"init.sql":                 {Data: []byte("CREATE EXTENSION x;")},
+		"002_add_tags.sql":         {Data: []byte("up 2")},
+		"002_add_tags.down.sql":    {Data: []byte("down 2")},
+		"001_create_runs.sql":      {Data: []byte("up 1")},
+		"001_create_runs.down.sql": {Data: []byte("down 1")},
+		"010_add_index.sql":        {Data: []byte("up 10")},
+		"README.md":                {Data: []byte("not a migration")},
+		"subdir/003_ignored.sql":   {Data: []byte("ignored")},
+	})
+	require.NoError(t, err)
+	return migrations
+}
+
+func TestLoad(t *testing.T) {
+	migrations := testMigrations(t)
+
+	require.Len(t, migrations, 3)
+	assert.Equal(t, Migration{Version: 1, Name: "create_runs", UpSQL: "up 1", DownSQL: "down 1"}, migrations[0])
+	assert.Equal(t, Migration{Version: 2, Name: "add_tags", UpSQL: "up 2", DownSQL: "down 2"}, migrations[1])
+	assert.Equal(t, Migration{Version: 10, Name: "add_index", UpSQL: "up 10"}, migrations[2])
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"001_a.sql": {Data: []byte("a")},
+				"001_b.sql": {Data: []byte("b")},
+			},
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{
+				"001_a.down.sql": {Data: []byte("a")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMigrator_Up_TracksVersionsAndIsIdempotent(t *testing.T) {
+	store := newMemoryStore()
+	migrator := NewMigrator(store, testMigrations(t))
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	assert.Equal(t, []string{"up 1", "up 2", "up 10"}, store.executed)
+	assert.False(t, store.locked, "lock should be released")
+
+	// Re-running applies nothing
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Len(t, store.executed, 3)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.True(t, s.Applied, "version %d should be applied", s.Version)
+		assert.NotNil(t, s.AppliedAt)
+	}
+}
+
+func TestMigrator_Up_AppliesOnlyPending(t *testing.T) {
+	store := newMemoryStore()
+	store.applied[1] = AppliedMigration{Version: 1, Name: "create_runs"}
+	migrator := NewMigrator(store, testMigrations(t))
+
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, []string{"up 2", "up 10"}, store.executed)
+}
+
+func TestMigrator_Up_StopsOnFailure(t *testing.T) {
+	store := newMemoryStore()
+	store.failOn = 2
+	migrator := NewMigrator(store, testMigrations(t))
+
+	applied, err := migrator.Up(context.Background())
+	require.Error(t, err)
+	assert.Len(t, applied, 1)
+	assert.NotContains(t, store.applied, 2)
+	assert.NotContains(t, store.applied, 10, "later migrations must not run after a failure")
+	assert.False(t, store.locked, "lock should be released on failure")
+}
+
+func TestMigrator_Down(t *testing.T) {
+	store := newMemoryStore()
+	migrations := testMigrations(t)
+	migrator := NewMigrator(store, migrations)
+	ctx := context.Background()
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// Latest migration (010) has no down file
+	_, err = migrator.Down(ctx, 1)
+	assert.ErrorIs(t, err, ErrNoDownMigration)
+	assert.Contains(t, store.applied, 10)
+
+	// Without 010, down reverts newest first
+	delete(store.applied, 10)
+	store.executed = nil
+	reverted, err := migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, reverted, 2)
+	assert.Equal(t, []string{"down 2", "down 1"}, store.executed)
+	assert.Empty(t, store.applied)
+
+	// Nothing left to revert
+	reverted, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, reverted)
+}
+
+func TestMigrator_Status_Pending(t *testing.T) {
+	store := newMemoryStore()
+	store.applied[1] = AppliedMigration{Version: 1, Name: "create_runs", AppliedAt: time.Now()}
+	migrator := NewMigrator(store, testMigrations(t))
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+	assert.False(t, statuses[2].Applied)
+	assert.False(t, statuses[2].HasDown)
+}
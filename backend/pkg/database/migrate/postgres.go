@@ -0,0 +1,103 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// advisoryLockKey identifies the migration lock in pg_advisory_lock ("aiomigr" as an int64)
+const advisoryLockKey int64 = 0x61696f6d696772
+
+// PostgresStore implements Store on a single connection.
+// A dedicated connection is required because advisory locks are held per session.
+type PostgresStore struct {
+	conn *pgx.Conn
+}
+
+// NewPostgresStore creates a new PostgresStore
+func NewPostgresStore(conn *pgx.Conn) *PostgresStore {
+	return &PostgresStore{conn: conn}
+}
+
+// Lock acquires a session-level advisory lock, waiting for other runners to finish
+func (s *PostgresStore) Lock(ctx context.Context) (func(context.Context) error, error) {
+	if _, err := s.conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		_, err := s.conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, advisoryLockKey)
+		return err
+	}, nil
+}
+
+// EnsureVersionTable creates schema_migrations if it does not exist
+func (s *PostgresStore) EnsureVersionTable(ctx context.Context) error {
+	_, err := s.conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// Applied returns the recorded migrations keyed by version
+func (s *PostgresStore) Applied(ctx context.Context) (map[int]AppliedMigration, error) {
+	rows, err := s.conn.Query(ctx, `SELECT version, name, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]AppliedMigration)
+	for rows.Next() {
+		var m AppliedMigration
+		if err := rows.Scan(&m.Version, &m.Name, &m.AppliedAt); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[m.Version] = m
+	}
+	return applied, rows.Err()
+}
+
+// Apply runs the up SQL and records the version in one transaction
+func (s *PostgresStore) Apply(ctx context.Context, m Migration) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		// No arguments: pgx uses the simple protocol, which allows multiple statements
+		if _, err := tx.Exec(ctx, m.UpSQL); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+		return err
+	})
+}
+
+// Revert runs the down SQL and deletes the version record in one transaction
+func (s *PostgresStore) Revert(ctx context.Context, m Migration) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, m.DownSQL); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+		return err
+	})
+}
+
+func (s *PostgresStore) inTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
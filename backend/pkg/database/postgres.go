@@ -3,6 +3,9 @@ package database
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +19,9 @@ type Config struct {
 	MinConns        int32
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+
+	// SlowQueryThreshold enables slow-query logging when positive
+	SlowQueryThreshold time.Duration
 }
 
 // DefaultConfig returns default database configuration
@@ -26,7 +32,58 @@ func DefaultConfig(url string) *Config {
 		MinConns:        5,
 		MaxConnLifetime: time.Hour,
 		MaxConnIdleTime: 30 * time.Minute,
+
+		SlowQueryThreshold: SlowQueryThresholdFromEnv(),
+	}
+}
+
+// Environment variables for pool tuning
+const (
+	EnvMaxConns        = "DB_MAX_CONNS"
+	EnvMinConns        = "DB_MIN_CONNS"
+	EnvMaxConnLifetime = "DB_MAX_CONN_LIFETIME"  // Go duration, e.g. "1h"
+	EnvMaxConnIdleTime = "DB_MAX_CONN_IDLE_TIME" // Go duration, e.g. "30m"
+)
+
+// ConfigFromEnv returns DefaultConfig with pool parameters overridden from the environment.
+// Unset or invalid values keep the defaults; MinConns is capped at MaxConns.
+func ConfigFromEnv(url string) *Config {
+	cfg := DefaultConfig(url)
+	cfg.MaxConns = envInt32(EnvMaxConns, cfg.MaxConns, 1)
+	cfg.MinConns = envInt32(EnvMinConns, cfg.MinConns, 0)
+	cfg.MaxConnLifetime = envDuration(EnvMaxConnLifetime, cfg.MaxConnLifetime)
+	cfg.MaxConnIdleTime = envDuration(EnvMaxConnIdleTime, cfg.MaxConnIdleTime)
+	if cfg.MinConns > cfg.MaxConns {
+		slog.Warn("min connections exceeds max connections, capping", "min_conns", cfg.MinConns, "max_conns", cfg.MaxConns)
+		cfg.MinConns = cfg.MaxConns
+	}
+	return cfg
+}
+
+func envInt32(key string, defaultValue, minValue int32) int32 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || int32(n) < minValue {
+		slog.Warn("ignoring invalid database pool setting", "env", key, "value", value)
+		return defaultValue
+	}
+	return int32(n)
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
 	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		slog.Warn("ignoring invalid database pool setting", "env", key, "value", value)
+		return defaultValue
+	}
+	return d
 }
 
 // NewPool creates a new PostgreSQL connection pool
@@ -40,6 +97,9 @@ func NewPool(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
 	poolConfig.MinConns = cfg.MinConns
 	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	if cfg.SlowQueryThreshold > 0 {
+		poolConfig.ConnConfig.Tracer = NewSlowQueryTracer(cfg.SlowQueryThreshold)
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
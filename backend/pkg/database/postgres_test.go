@@ -1,7 +1,9 @@
 package database
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -58,3 +60,81 @@ func TestSetTenantContext_SQLInjectionPrevention(t *testing.T) {
 		assert.Error(t, err, "SQL injection attempt %q should be rejected", attempt)
 	}
 }
+
+func TestConfigFromEnv(t *testing.T) {
+	defaults := DefaultConfig("postgres://localhost/test")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(*Config)
+	}{
+		{
+			name: "unset uses defaults",
+			env:  map[string]string{},
+			want: func(c *Config) {},
+		},
+		{
+			name: "all values overridden",
+			env: map[string]string{
+				EnvMaxConns:        "50",
+				EnvMinConns:        "10",
+				EnvMaxConnLifetime: "2h",
+				EnvMaxConnIdleTime: "5m",
+			},
+			want: func(c *Config) {
+				c.MaxConns = 50
+				c.MinConns = 10
+				c.MaxConnLifetime = 2 * time.Hour
+				c.MaxConnIdleTime = 5 * time.Minute
+			},
+		},
+		{
+			name: "invalid values keep defaults",
+			env: map[string]string{
+				EnvMaxConns:        "0",
+				EnvMinConns:        "-1",
+				EnvMaxConnLifetime: "forever",
+				EnvMaxConnIdleTime: "-5m",
+			},
+			want: func(c *Config) {},
+		},
+		{
+			name: "min conns capped at max conns",
+			env: map[string]string{
+				EnvMaxConns: "4",
+			},
+			want: func(c *Config) {
+				c.MaxConns = 4
+				c.MinConns = 4
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{EnvMaxConns, EnvMinConns, EnvMaxConnLifetime, EnvMaxConnIdleTime} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			want := *defaults
+			tt.want(&want)
+
+			got := ConfigFromEnv("postgres://localhost/test")
+			assert.Equal(t, want, *got)
+		})
+	}
+}
+
+func TestPoolStats_WritePrometheus(t *testing.T) {
+	stats := PoolStats{AcquiredConns: 3, IdleConns: 2, TotalConns: 5, MaxConns: 25, EmptyAcquireCount: 7}
+
+	var b strings.Builder
+	assert.NoError(t, stats.WritePrometheus(&b))
+
+	out := b.String()
+	assert.Contains(t, out, "# TYPE db_pool_acquired_conns gauge\ndb_pool_acquired_conns 3\n")
+	assert.Contains(t, out, "db_pool_idle_conns 2\n")
+	assert.Contains(t, out, "db_pool_total_conns 5\n")
+	assert.Contains(t, out, "# TYPE db_pool_empty_acquire_total counter\ndb_pool_empty_acquire_total 7\n")
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolStats is a snapshot of connection pool statistics
+type PoolStats struct {
+	AcquiredConns     int32 `json:"acquired_conns"`
+	IdleConns         int32 `json:"idle_conns"`
+	TotalConns        int32 `json:"total_conns"`
+	MaxConns          int32 `json:"max_conns"`
+	AcquireCount      int64 `json:"acquire_count"`
+	EmptyAcquireCount int64 `json:"empty_acquire_count"` // Acquires that had to wait for a connection
+	AcquireDurationMs int64 `json:"acquire_duration_ms"` // Cumulative time spent acquiring connections
+	CanceledAcquires  int64 `json:"canceled_acquire_count"`
+}
+
+// StatsFromPool takes a snapshot of the pool statistics
+func StatsFromPool(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		AcquiredConns:     stat.AcquiredConns(),
+		IdleConns:         stat.IdleConns(),
+		TotalConns:        stat.TotalConns(),
+		MaxConns:          stat.MaxConns(),
+		AcquireCount:      stat.AcquireCount(),
+		EmptyAcquireCount: stat.EmptyAcquireCount(),
+		AcquireDurationMs: stat.AcquireDuration().Milliseconds(),
+		CanceledAcquires:  stat.CanceledAcquireCount(),
+	}
+}
+
+// WritePrometheus writes the stats in the Prometheus text exposition format
+func (s PoolStats) WritePrometheus(w io.Writer) error {
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value int64
+	}{
+		{"db_pool_acquired_conns", "gauge", "Connections currently in use.", int64(s.AcquiredConns)},
+		{"db_pool_idle_conns", "gauge", "Idle connections in the pool.", int64(s.IdleConns)},
+		{"db_pool_total_conns", "gauge", "Total connections in the pool.", int64(s.TotalConns)},
+		{"db_pool_max_conns", "gauge", "Maximum pool size.", int64(s.MaxConns)},
+		{"db_pool_acquire_total", "counter", "Successful connection acquires.", s.AcquireCount},
+		{"db_pool_empty_acquire_total", "counter", "Acquires that waited because the pool was empty.", s.EmptyAcquireCount},
+		{"db_pool_acquire_duration_ms_total", "counter", "Cumulative time spent acquiring connections in milliseconds.", s.AcquireDurationMs},
+		{"db_pool_canceled_acquire_total", "counter", "Acquires canceled by their context.", s.CanceledAcquires},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", m.name, m.help, m.name, m.kind, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EnvSlowQueryThreshold enables slow-query logging when set to a positive duration (e.g. "200ms")
+const EnvSlowQueryThreshold = "DB_SLOW_QUERY_THRESHOLD"
+
+// Limits applied to the SQL and argument summary of a slow query log entry
+const (
+	maxSlowQuerySQLLen = 500
+	maxSlowQueryArgs   = 10
+	maxSlowQueryArgLen = 64
+)
+
+// SlowQuery describes a query that exceeded the slow-query threshold
+type SlowQuery struct {
+	SQL      string // Whitespace-collapsed, truncated SQL
+	Args     string // Truncated summary of the query arguments
+	Duration time.Duration
+	Err      error
+}
+
+// SlowQueryTracer is a pgx.QueryTracer that reports queries slower than Threshold
+type SlowQueryTracer struct {
+	Threshold   time.Duration
+	OnSlowQuery func(ctx context.Context, q SlowQuery)
+
+	now func() time.Time // Overridable for tests
+}
+
+// NewSlowQueryTracer creates a tracer that logs slow queries with slog at warn level
+func NewSlowQueryTracer(threshold time.Duration) *SlowQueryTracer {
+	return &SlowQueryTracer{
+		Threshold:   threshold,
+		OnSlowQuery: logSlowQuery,
+		now:         time.Now,
+	}
+}
+
+type slowQueryCtxKey struct{}
+
+type slowQueryStart struct {
+	startedAt time.Time
+	sql       string
+	args      []any
+}
+
+// TraceQueryStart implements pgx.QueryTracer
+func (t *SlowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryCtxKey{}, &slowQueryStart{
+		startedAt: t.clock(),
+		sql:       data.SQL,
+		args:      data.Args,
+	})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer
+func (t *SlowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(slowQueryCtxKey{}).(*slowQueryStart)
+	if !ok || t.OnSlowQuery == nil {
+		return
+	}
+	duration := t.clock().Sub(start.startedAt)
+	if duration < t.Threshold {
+		return
+	}
+	t.OnSlowQuery(ctx, SlowQuery{
+		SQL:      summarizeSQL(start.sql),
+		Args:     summarizeArgs(start.args),
+		Duration: duration,
+		Err:      data.Err,
+	})
+}
+
+func (t *SlowQueryTracer) clock() time.Time {
+	if t.now == nil {
+		return time.Now()
+	}
+	return t.now()
+}
+
+func logSlowQuery(ctx context.Context, q SlowQuery) {
+	attrs := []any{
+		"duration_ms", q.Duration.Milliseconds(),
+		"sql", q.SQL,
+		"args", q.Args,
+	}
+	if q.Err != nil {
+		attrs = append(attrs, "error", q.Err)
+	}
+	slog.WarnContext(ctx, "slow query", attrs...)
+}
+
+// summarizeSQL collapses whitespace and truncates the statement
+func summarizeSQL(sql string) string {
+	return truncate(strings.Join(strings.Fields(sql), " "), maxSlowQuerySQLLen)
+}
+
+// summarizeArgs renders up to maxSlowQueryArgs arguments, each truncated,
+// so large JSON payloads or secrets do not end up in logs verbatim
+func summarizeArgs(args []any) string {
+	if len(args) == 0 {
+		return "[]"
+	}
+	parts := make([]string, 0, min(len(args), maxSlowQueryArgs)+1)
+	for i, arg := range args {
+		if i == maxSlowQueryArgs {
+			parts = append(parts, fmt.Sprintf("... (%d more)", len(args)-maxSlowQueryArgs))
+			break
+		}
+		var s string
+		switch v := arg.(type) {
+		case []byte:
+			s = string(v)
+		default:
+			s = fmt.Sprintf("%v", v)
+		}
+		parts = append(parts, truncate(s, maxSlowQueryArgLen))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
+
+// SlowQueryThresholdFromEnv reads DB_SLOW_QUERY_THRESHOLD.
+// Returns 0 (disabled) when unset, invalid, or not positive.
+func SlowQueryThresholdFromEnv() time.Duration {
+	value := os.Getenv(EnvSlowQueryThreshold)
+	if value == "" {
+		return 0
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold <= 0 {
+		slog.Warn("ignoring invalid slow query threshold", "env", EnvSlowQueryThreshold, "value", value)
+		return 0
+	}
+	return threshold
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock returns a controllable time source for tracer tests
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.current }
+func (c *fakeClock) advance(d time.Duration) { c.current = c.current.Add(d) }
+func newFakeClock() *fakeClock {
+	return &fakeClock{current: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func newTestTracer(threshold time.Duration, clock *fakeClock) (*SlowQueryTracer, *[]SlowQuery) {
+	var reported []SlowQuery
+	tracer := &SlowQueryTracer{
+		Threshold: threshold,
+		OnSlowQuery: func(ctx context.Context, q SlowQuery) {
+			reported = append(reported, q)
+		},
+		now: clock.now,
+	}
+	return tracer, &reported
+}
+
+func TestSlowQueryTracer_SlowQueryTriggersCallback(t *testing.T) {
+	clock := newFakeClock()
+	tracer, reported := newTestTracer(100*time.Millisecond, clock)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "SELECT pg_sleep($1)\n\t\tFROM runs",
+		Args: []any{0.25},
+	})
+	clock.advance(250 * time.Millisecond)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	require.Len(t, *reported, 1)
+	q := (*reported)[0]
+	assert.Equal(t, 250*time.Millisecond, q.Duration)
+	assert.Equal(t, "SELECT pg_sleep($1) FROM runs", q.SQL)
+	assert.Equal(t, "[0.25]", q.Args)
+	assert.NoError(t, q.Err)
+}
+
+func TestSlowQueryTracer_FastQueryIsIgnored(t *testing.T) {
+	clock := newFakeClock()
+	tracer, reported := newTestTracer(100*time.Millisecond, clock)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	clock.advance(99 * time.Millisecond)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	assert.Empty(t, *reported)
+}
+
+func TestSlowQueryTracer_ReportsError(t *testing.T) {
+	clock := newFakeClock()
+	tracer, reported := newTestTracer(10*time.Millisecond, clock)
+
+	queryErr := errors.New("canceling statement due to statement timeout")
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	clock.advance(time.Second)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: queryErr})
+
+	require.Len(t, *reported, 1)
+	assert.ErrorIs(t, (*reported)[0].Err, queryErr)
+}
+
+func TestSummarizeArgs_Truncates(t *testing.T) {
+	args := make([]any, 12)
+	for i := range args {
+		args[i] = i
+	}
+	args[0] = []byte(strings.Repeat("x", 100))
+
+	summary := summarizeArgs(args)
+	assert.True(t, strings.HasPrefix(summary, "["+strings.Repeat("x", maxSlowQueryArgLen)+"..., 1, 2"), summary)
+	assert.True(t, strings.HasSuffix(summary, "9, ... (2 more)]"), summary)
+}
+
+func TestSlowQueryThresholdFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "250ms", want: 250 * time.Millisecond},
+		{value: "1s", want: time.Second},
+		{value: "0", want: 0},
+		{value: "-5ms", want: 0},
+		{value: "slow", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(EnvSlowQueryThreshold, tt.value)
+			assert.Equal(t, tt.want, SlowQueryThresholdFromEnv())
+		})
+	}
+}
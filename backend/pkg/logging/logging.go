@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Environment variables read at startup
+const (
+	EnvLogLevel  = "LOG_LEVEL"  // Initial log level
+	EnvLogFormat = "LOG_FORMAT" // Log output format: json or text
+)
+
+// Log output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// ParseLevel parses a log level name (debug, info, warn/warning, error).
+// Names are case-insensitive and may carry an offset such as "info+2".
+// An empty string yields slog.LevelInfo.
+func ParseLevel(s string) (slog.Level, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if strings.EqualFold(s, "warning") {
+		s = "warn"
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: must be one of debug, info, warn, error", s)
+	}
+	return level, nil
+}
+
+// NewLevelVar creates a LevelVar initialized from a LOG_LEVEL value.
+// An invalid value falls back to info; the parse error is returned so the caller can report it
+// once the logger is set up.
+func NewLevelVar(value string) (*slog.LevelVar, error) {
+	levelVar := new(slog.LevelVar)
+	level, err := ParseLevel(value)
+	levelVar.Set(level)
+	return levelVar, err
+}
+
+// NewHandler returns a slog handler for a LOG_FORMAT value.
+// "text" selects slog.TextHandler; empty selects slog.JSONHandler to preserve production behavior.
+// An unknown value falls back to JSON and returns an error for the caller to report.
+func NewHandler(format string, w io.Writer, opts *slog.HandlerOptions) (slog.Handler, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	case FormatText:
+		return slog.NewTextHandler(w, opts), nil
+	default:
+		return slog.NewJSONHandler(w, opts), fmt.Errorf("invalid log format %q: must be %q or %q", format, FormatJSON, FormatText)
+	}
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{input: "", want: slog.LevelInfo},
+		{input: "debug", want: slog.LevelDebug},
+		{input: "DEBUG", want: slog.LevelDebug},
+		{input: " info ", want: slog.LevelInfo},
+		{input: "warn", want: slog.LevelWarn},
+		{input: "Warning", want: slog.LevelWarn},
+		{input: "error", want: slog.LevelError},
+		{input: "info+2", want: slog.LevelInfo + 2},
+		{input: "verbose", want: slog.LevelInfo, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLevelVar_InvalidFallsBackToInfo(t *testing.T) {
+	levelVar, err := NewLevelVar("loud")
+	if err == nil {
+		t.Error("NewLevelVar() expected error for invalid level")
+	}
+	if levelVar.Level() != slog.LevelInfo {
+		t.Errorf("Level() = %v, want %v", levelVar.Level(), slog.LevelInfo)
+	}
+}
+
+func TestLevelVar_RuntimeSwitch(t *testing.T) {
+	levelVar, err := NewLevelVar("info")
+	if err != nil {
+		t.Fatalf("NewLevelVar() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: levelVar}))
+
+	logger.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("debug log emitted at info level: %s", buf.String())
+	}
+
+	// Changing the LevelVar affects the already-constructed logger
+	levelVar.Set(slog.LevelDebug)
+	logger.Debug("visible")
+	if !bytes.Contains(buf.Bytes(), []byte("visible")) {
+		t.Errorf("debug log not emitted after switching to debug: %q", buf.String())
+	}
+
+	levelVar.Set(slog.LevelError)
+	if logger.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("warn should be disabled after switching to error")
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	tests := []struct {
+		format   string
+		wantText bool
+		wantErr  bool
+	}{
+		{format: ""},
+		{format: "json"},
+		{format: "JSON"},
+		{format: "text", wantText: true},
+		{format: " Text ", wantText: true},
+		{format: "logfmt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			handler, err := NewHandler(tt.format, io.Discard, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewHandler(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+			switch handler.(type) {
+			case *slog.TextHandler:
+				if !tt.wantText {
+					t.Errorf("NewHandler(%q) = TextHandler, want JSONHandler", tt.format)
+				}
+			case *slog.JSONHandler:
+				if tt.wantText {
+					t.Errorf("NewHandler(%q) = JSONHandler, want TextHandler", tt.format)
+				}
+			default:
+				t.Errorf("NewHandler(%q) returned unexpected handler type %T", tt.format, handler)
+			}
+		})
+	}
+}
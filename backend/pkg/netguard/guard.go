@@ -0,0 +1,277 @@
+// Package netguard blocks outbound connections from user-controlled HTTP requests to internal
+// addresses (SSRF protection). The check runs when a connection is dialed, after DNS resolution,
+// so it also covers redirects and hosts that resolve to internal addresses.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlocked is returned when a request targets an internal address
+var ErrBlocked = errors.New("blocked: internal address")
+
+// ErrDomainNotPermitted is returned when a request targets a host excluded by the guard's domain rules
+var ErrDomainNotPermitted = errors.New("domain not permitted")
+
+// extraBlocked lists internal ranges not covered by the netip.Addr classification methods
+var extraBlocked = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT, also used for cloud metadata services
+}
+
+// allAddresses allows every address, leaving only domain rules in effect
+var allAddresses = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/0"),
+	netip.MustParsePrefix("::/0"),
+}
+
+// Guard decides which addresses outbound requests may connect to. Loopback, private,
+// link-local (including the 169.254.169.254 metadata endpoint) and unspecified addresses are
+// blocked unless they fall within an allowlisted prefix. Domain rules, when set, further restrict
+// which hosts may be called.
+type Guard struct {
+	allow        []netip.Prefix
+	allowDomains []string
+	denyDomains  []string
+}
+
+// New creates a guard that blocks internal addresses except the allowed prefixes
+func New(allow ...netip.Prefix) *Guard {
+	return &Guard{allow: allow}
+}
+
+// Configure builds the guard from deployment settings: nil when the protection is disabled,
+// otherwise a guard allowing the comma-separated prefixes in allowlist
+func Configure(enabled bool, allowlist string) (*Guard, error) {
+	if !enabled {
+		return nil, nil
+	}
+	prefixes, err := ParsePrefixes(strings.Split(allowlist, ","))
+	if err != nil {
+		return nil, err
+	}
+	return New(prefixes...), nil
+}
+
+// Allow returns a copy of the guard that additionally allows the given prefixes
+func (g *Guard) Allow(prefixes ...netip.Prefix) *Guard {
+	if g == nil {
+		return nil
+	}
+	allow := make([]netip.Prefix, 0, len(g.allow)+len(prefixes))
+	allow = append(allow, g.allow...)
+	allow = append(allow, prefixes...)
+	return &Guard{allow: allow, allowDomains: g.allowDomains, denyDomains: g.denyDomains}
+}
+
+// WithDomains returns a copy of the guard that also restricts requests by host name: a host is
+// permitted when it matches no denied domain and, if allowed is not empty, matches an allowed
+// one. A domain matches itself and its subdomains. Domains are expected in the form returned by
+// ParseDomains. On a nil guard the rules apply without blocking internal addresses.
+func (g *Guard) WithDomains(allowed, denied []string) *Guard {
+	if len(allowed) == 0 && len(denied) == 0 {
+		return g
+	}
+	base := g
+	if base == nil {
+		base = New(allAddresses...)
+	}
+	return &Guard{
+		allow:        base.allow,
+		allowDomains: append(append([]string{}, base.allowDomains...), allowed...),
+		denyDomains:  append(append([]string{}, base.denyDomains...), denied...),
+	}
+}
+
+// ParseDomains normalizes domain rules: lowercased, without a trailing dot, and with a leading
+// "*." removed since every domain already matches its subdomains. IP addresses are accepted as is.
+func ParseDomains(values []string) ([]string, error) {
+	domains := make([]string, 0, len(values))
+	for _, value := range values {
+		domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+		domain = strings.TrimPrefix(domain, "*.")
+		if domain == "" {
+			continue
+		}
+		if _, err := netip.ParseAddr(domain); err != nil && !isHostname(domain) {
+			return nil, fmt.Errorf("invalid domain %q", value)
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+func isHostname(s string) bool {
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CheckHost returns an error wrapping ErrDomainNotPermitted when host is excluded by the domain
+// rules. A nil guard permits every host.
+func (g *Guard) CheckHost(host string) error {
+	if g == nil || len(g.allowDomains) == 0 && len(g.denyDomains) == 0 {
+		return nil
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if matchesDomain(host, g.denyDomains) ||
+		len(g.allowDomains) > 0 && !matchesDomain(host, g.allowDomains) {
+		return fmt.Errorf("%w: %s", ErrDomainNotPermitted, host)
+	}
+	return nil
+}
+
+// CheckTarget checks host the way connecting to it would: against the domain rules, then every
+// address it resolves to. Callers that may answer a request without connecting, such as a
+// response cache, use it to apply the guard first. A nil guard permits every host.
+func (g *Guard) CheckTarget(ctx context.Context, host string) error {
+	if g == nil {
+		return nil
+	}
+	if err := g.CheckHost(host); err != nil {
+		return err
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.Check(addr)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := g.Check(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePrefixes parses CIDR prefixes ("10.1.0.0/16") and single addresses ("10.1.2.3")
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowlist entry %q: %w", value, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist entry %q: %w", value, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Check returns an error wrapping ErrBlocked when addr is internal and not allowlisted.
+// A nil guard allows every address.
+func (g *Guard) Check(addr netip.Addr) error {
+	if g == nil {
+		return nil
+	}
+	addr = addr.Unmap()
+	if !isInternal(addr) {
+		return nil
+	}
+	for _, prefix := range g.allow {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %s", ErrBlocked, addr)
+}
+
+func isInternal(addr netip.Addr) bool {
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, prefix := range extraBlocked {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Control checks the resolved address of a connection before it is made. It has the signature
+// of net.Dialer.Control.
+func (g *Guard) Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: unparseable address %s", ErrBlocked, address)
+	}
+	return g.Check(addrPort.Addr())
+}
+
+// Transport returns an HTTP transport whose connections are checked by the guard: the host name
+// against the domain rules before dialing, and the resolved address before connecting. Its
+// connection pool belongs to the guard alone, so no connection opened elsewhere is reused. A
+// proxy would make the dialed address the proxy's, hiding the real target, so the transport
+// always connects directly.
+func (g *Guard) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   g.Control,
+	}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		if err := g.CheckHost(host); err != nil {
+			return nil, err
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+	return transport
+}
+
+type contextKey struct{}
+
+// WithContext returns a context carrying the guard for requests made with it
+func WithContext(ctx context.Context, g *Guard) context.Context {
+	return context.WithValue(ctx, contextKey{}, g)
+}
+
+// FromContext returns the guard carried by ctx, or nil
+func FromContext(ctx context.Context) *Guard {
+	g, _ := ctx.Value(contextKey{}).(*Guard)
+	return g
+}
@@ -0,0 +1,154 @@
+package netguard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard_Check(t *testing.T) {
+	guard := New()
+
+	blocked := []string{
+		"169.254.169.254", // Cloud metadata endpoint
+		"10.0.0.1",
+		"10.255.255.255",
+		"172.16.0.1",
+		"192.168.1.1",
+		"127.0.0.1",
+		"0.0.0.0",
+		"100.100.100.200",
+		"::1",
+		"fe80::1",
+		"fd00:ec2::254",
+		"::ffff:10.0.0.1", // IPv4-mapped private address
+	}
+	for _, ip := range blocked {
+		err := guard.Check(netip.MustParseAddr(ip))
+		assert.ErrorIs(t, err, ErrBlocked, ip)
+	}
+
+	for _, ip := range []string{"8.8.8.8", "172.32.0.1", "2001:4860:4860::8888"} {
+		assert.NoError(t, guard.Check(netip.MustParseAddr(ip)), ip)
+	}
+}
+
+func TestGuard_Allowlist(t *testing.T) {
+	prefixes, err := ParsePrefixes([]string{"10.1.0.0/16", " 192.168.5.5 "})
+	require.NoError(t, err)
+	guard := New().Allow(prefixes...)
+
+	assert.NoError(t, guard.Check(netip.MustParseAddr("10.1.2.3")))
+	assert.NoError(t, guard.Check(netip.MustParseAddr("192.168.5.5")))
+	assert.ErrorIs(t, guard.Check(netip.MustParseAddr("10.2.0.1")), ErrBlocked)
+	assert.ErrorIs(t, guard.Check(netip.MustParseAddr("192.168.5.6")), ErrBlocked)
+	assert.ErrorIs(t, New().Check(netip.MustParseAddr("10.1.2.3")), ErrBlocked, "Allow does not modify the original guard")
+}
+
+func TestConfigure(t *testing.T) {
+	guard, err := Configure(false, "")
+	require.NoError(t, err)
+	assert.Nil(t, guard, "a disabled guard is nil")
+	assert.NoError(t, guard.Check(netip.MustParseAddr("169.254.169.254")), "a nil guard allows everything")
+
+	guard, err = Configure(true, "10.0.0.0/8")
+	require.NoError(t, err)
+	assert.NoError(t, guard.Check(netip.MustParseAddr("10.3.4.5")))
+
+	_, err = Configure(true, "10.0.0.0/8,not-an-ip")
+	assert.Error(t, err)
+}
+
+func TestTransport_BlocksInternalAddresses(t *testing.T) {
+	client := &http.Client{Transport: New().Transport()}
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data/", "http://10.0.0.1/"} {
+		_, err := client.Get(url)
+		require.Error(t, err, url)
+		assert.ErrorIs(t, err, ErrBlocked, url)
+		assert.Contains(t, err.Error(), "blocked: internal address", url)
+	}
+}
+
+func TestTransport_AllowlistedServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := (&http.Client{Transport: New().Transport()}).Get(server.URL)
+	assert.ErrorIs(t, err, ErrBlocked, "the loopback test server is internal")
+
+	loopback, err := ParsePrefixes([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: New(loopback...).Transport()}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestWithContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	guard := New()
+	assert.Same(t, guard, FromContext(WithContext(context.Background(), guard)))
+}
+
+func TestGuard_CheckHost(t *testing.T) {
+	allowed, err := ParseDomains([]string{"Example.com", "*.api.partner.io", "203.0.113.7"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "api.partner.io", "203.0.113.7"}, allowed)
+	denied, err := ParseDomains([]string{"internal.example.com."})
+	require.NoError(t, err)
+	guard := New().WithDomains(allowed, denied)
+
+	for _, host := range []string{"example.com", "www.example.com", "EXAMPLE.COM.", "v1.api.partner.io", "203.0.113.7"} {
+		assert.NoError(t, guard.CheckHost(host), host)
+	}
+	for _, host := range []string{"evil.com", "badexample.com", "partner.io", "internal.example.com", "a.internal.example.com"} {
+		err := guard.CheckHost(host)
+		assert.ErrorIs(t, err, ErrDomainNotPermitted, host)
+		assert.Contains(t, err.Error(), "domain not permitted: ", host)
+	}
+
+	denyOnly := New().WithDomains(nil, []string{"evil.com"})
+	assert.NoError(t, denyOnly.CheckHost("example.com"), "an empty allowlist allows every domain")
+	assert.ErrorIs(t, denyOnly.CheckHost("evil.com"), ErrDomainNotPermitted)
+
+	_, err = ParseDomains([]string{"exa mple.com"})
+	assert.Error(t, err)
+}
+
+func TestGuard_WithDomainsOnNilGuard(t *testing.T) {
+	var disabled *Guard
+	assert.Nil(t, disabled.WithDomains(nil, nil), "no rules keep the protection disabled")
+
+	guard := disabled.WithDomains(nil, []string{"evil.com"})
+	require.NotNil(t, guard)
+	assert.NoError(t, guard.Check(netip.MustParseAddr("10.0.0.1")), "internal addresses stay allowed when SSRF protection is disabled")
+	assert.ErrorIs(t, guard.CheckHost("evil.com"), ErrDomainNotPermitted)
+}
+
+func TestTransport_DomainRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	localhostURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	loopback, err := ParsePrefixes([]string{"127.0.0.0/8", "::1"})
+	require.NoError(t, err)
+
+	allowed := &http.Client{Transport: New(loopback...).WithDomains([]string{"localhost"}, nil).Transport()}
+	resp, err := allowed.Get(localhostURL)
+	require.NoError(t, err, "allowlisted domains are reachable")
+	resp.Body.Close()
+
+	_, err = allowed.Get("http://example.com/")
+	assert.ErrorIs(t, err, ErrDomainNotPermitted, "domains outside the allowlist are rejected before dialing")
+
+	denied := &http.Client{Transport: New(loopback...).WithDomains(nil, []string{"localhost"}).Transport()}
+	_, err = denied.Get(localhostURL)
+	assert.ErrorIs(t, err, ErrDomainNotPermitted)
+	assert.Contains(t, err.Error(), "domain not permitted: localhost")
+}
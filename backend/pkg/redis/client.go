@@ -2,7 +2,10 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -14,8 +17,56 @@ type Config struct {
 	DB       int
 }
 
+// ErrUnavailable indicates that Redis did not respond within the startup retry period
+var ErrUnavailable = errors.New("redis is unavailable")
+
 // NewClient creates a new Redis client
 func NewClient(ctx context.Context, cfg *Config) (*redis.Client, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Test connection
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping Redis: %w", err)
+	}
+
+	return client, nil
+}
+
+// Connect creates a new Redis client, retrying the initial ping with exponential backoff
+// (500ms doubling up to 5s) for at most retryFor. If Redis is still unreachable, it returns
+// the client together with an error wrapping ErrUnavailable: the client reconnects on its own
+// once Redis is back, so callers that can run degraded may keep using it. A configuration
+// error returns a nil client.
+func Connect(ctx context.Context, cfg *Config, retryFor time.Duration) (*redis.Client, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(retryFor)
+	backoff := 500 * time.Millisecond
+	for {
+		err := client.Ping(ctx).Err()
+		if err == nil {
+			return client, nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return client, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		slog.Warn("Redis not reachable, retrying", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return client, fmt.Errorf("%w: %v", ErrUnavailable, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+}
+
+func newClient(cfg *Config) (*redis.Client, error) {
 	opt, err := redis.ParseURL(cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
@@ -28,12 +79,5 @@ func NewClient(ctx context.Context, cfg *Config) (*redis.Client, error) {
 		opt.DB = cfg.DB
 	}
 
-	client := redis.NewClient(opt)
-
-	// Test connection
-	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to ping Redis: %w", err)
-	}
-
-	return client, nil
+	return redis.NewClient(opt), nil
 }
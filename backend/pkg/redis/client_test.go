@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnect_UnreachableReturnsDegradedClient(t *testing.T) {
+	start := time.Now()
+	client, err := Connect(context.Background(), &Config{URL: "redis://localhost:63790"}, 100*time.Millisecond)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnavailable), "error = %v, want ErrUnavailable", err)
+	require.NotNil(t, client, "the client is returned so the service can run degraded")
+	defer client.Close()
+	assert.Less(t, time.Since(start), 5*time.Second, "retries are bounded by retryFor")
+}
+
+func TestConnect_InvalidURL(t *testing.T) {
+	client, err := Connect(context.Background(), &Config{URL: "not-a-url"}, time.Second)
+
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnavailable))
+	assert.Nil(t, client)
+}
+
+func TestMonitor_ReportsOutage(t *testing.T) {
+	client, _ := Connect(context.Background(), &Config{URL: "redis://localhost:63790"}, 0)
+	require.NotNil(t, client)
+	defer client.Close()
+
+	monitor := NewMonitor(client, true, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.True(t, monitor.Available())
+
+	monitor.check(context.Background())
+	assert.False(t, monitor.Available())
+}
+
+func TestLocker_TryLockUnavailable(t *testing.T) {
+	client, _ := Connect(context.Background(), &Config{URL: "redis://localhost:63790"}, 0)
+	require.NotNil(t, client)
+	defer client.Close()
+
+	release, acquired, err := NewLocker(client).TryLock(context.Background(), "test:lock", time.Minute)
+	assert.Error(t, err)
+	assert.False(t, acquired)
+	assert.Nil(t, release)
+}
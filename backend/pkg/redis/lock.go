@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes a lock only if it still holds the caller's token, so that a caller
+// whose lock expired cannot release a lock since acquired by someone else
+var releaseScript = redis.NewScript(`
+	if redis.call('GET', KEYS[1]) == ARGV[1] then
+		return redis.call('DEL', KEYS[1])
+	end
+	return 0
+`)
+
+// Locker acquires distributed locks shared by all processes using the same Redis (SET NX with a TTL)
+type Locker struct {
+	client *redis.Client
+}
+
+// NewLocker creates a new Redis-backed locker
+func NewLocker(client *redis.Client) *Locker {
+	return &Locker{client: client}
+}
+
+// TryLock acquires the lock without waiting. It returns acquired=false when another holder has
+// the lock. The lock expires after ttl unless released earlier with the returned function.
+func (l *Locker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	token := uuid.New().String()
+	acquired, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+
+	release := func(ctx context.Context) error {
+		if err := releaseScript.Run(ctx, l.client, []string{key}, token).Err(); err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", key, err)
+		}
+		return nil
+	}
+	return release, true, nil
+}
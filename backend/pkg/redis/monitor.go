@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Monitor tracks whether Redis is reachable by pinging it periodically, so that services can
+// run in a degraded mode during an outage and recover once Redis is back
+type Monitor struct {
+	client    *redis.Client
+	available atomic.Bool
+	logger    *slog.Logger
+}
+
+// NewMonitor creates a monitor with the given initial availability
+func NewMonitor(client *redis.Client, available bool, logger *slog.Logger) *Monitor {
+	m := &Monitor{client: client, logger: logger}
+	m.available.Store(available)
+	return m
+}
+
+// Available reports whether the last check reached Redis
+func (m *Monitor) Available() bool {
+	return m.available.Load()
+}
+
+// Run pings Redis every interval until ctx is done, logging availability changes
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// check pings Redis once and records the result
+func (m *Monitor) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	err := m.client.Ping(pingCtx).Err()
+
+	wasAvailable := m.available.Swap(err == nil)
+	switch {
+	case err == nil && !wasAvailable:
+		m.logger.Info("Redis connection restored; leaving degraded mode")
+	case err != nil && wasAvailable:
+		m.logger.Warn("Redis unavailable; running in degraded mode", "error", err)
+	}
+}
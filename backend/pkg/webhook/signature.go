@@ -0,0 +1,219 @@
+// Package webhook verifies the signatures of inbound webhook requests. Each provider signs
+// requests its own way, so verifiers are registered per signature scheme.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Signature schemes registered by NewRegistry
+const (
+	SchemeGitHub = "github"
+	SchemeStripe = "stripe"
+	SchemeSlack  = "slack"
+	SchemeHMAC   = "hmac"
+)
+
+// DefaultTolerance is how far the timestamp of a Stripe or Slack signature may be from the
+// current time, which limits replaying a captured request
+const DefaultTolerance = 5 * time.Minute
+
+// Errors
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrUnknownScheme    = errors.New("unknown signature scheme")
+)
+
+// Verifier checks the signature of a webhook request against the shared secret
+type Verifier interface {
+	// Verify returns an error wrapping ErrMissingSignature or ErrInvalidSignature when the
+	// request is not signed with secret
+	Verify(header http.Header, body []byte, secret string) error
+}
+
+// VerifierFunc adapts a function to the Verifier interface
+type VerifierFunc func(header http.Header, body []byte, secret string) error
+
+// Verify calls f
+func (f VerifierFunc) Verify(header http.Header, body []byte, secret string) error {
+	return f(header, body, secret)
+}
+
+// Registry holds the verifiers by signature scheme
+type Registry struct {
+	mu        sync.RWMutex
+	verifiers map[string]Verifier
+}
+
+// NewRegistry creates a registry with the built-in schemes: github, stripe, slack and hmac
+func NewRegistry() *Registry {
+	r := &Registry{verifiers: make(map[string]Verifier)}
+	r.Register(SchemeGitHub, GitHubVerifier{})
+	r.Register(SchemeStripe, StripeVerifier{Tolerance: DefaultTolerance})
+	r.Register(SchemeSlack, SlackVerifier{Tolerance: DefaultTolerance})
+	r.Register(SchemeHMAC, HMACVerifier{Header: "X-Webhook-Signature"})
+	return r
+}
+
+// Register adds or replaces the verifier for a scheme
+func (r *Registry) Register(scheme string, verifier Verifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifiers[scheme] = verifier
+}
+
+// Get returns the verifier for a scheme
+func (r *Registry) Get(scheme string) (Verifier, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	verifier, ok := r.verifiers[scheme]
+	return verifier, ok
+}
+
+// Verify checks the request with the scheme's verifier
+func (r *Registry) Verify(scheme string, header http.Header, body []byte, secret string) error {
+	verifier, ok := r.Get(scheme)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownScheme, scheme)
+	}
+	return verifier.Verify(header, body, secret)
+}
+
+// HMACVerifier checks a hex HMAC-SHA256 of the body, optionally prefixed with "sha256=", sent
+// in Header
+type HMACVerifier struct {
+	Header string
+}
+
+// Verify implements Verifier
+func (v HMACVerifier) Verify(header http.Header, body []byte, secret string) error {
+	signature := header.Get(v.Header)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if !hmacEqual(secret, body, strings.TrimPrefix(signature, "sha256=")) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// GitHubVerifier checks GitHub's X-Hub-Signature-256 header ("sha256=" + hex HMAC-SHA256 of
+// the body)
+type GitHubVerifier struct{}
+
+// Verify implements Verifier
+func (GitHubVerifier) Verify(header http.Header, body []byte, secret string) error {
+	signature := header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	hexMAC, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok || !hmacEqual(secret, body, hexMAC) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// StripeVerifier checks Stripe's Stripe-Signature header ("t=<unix>,v1=<hex>[,v1=<hex>...]"),
+// an HMAC-SHA256 of "<t>.<body>". Any v1 signature may match, which covers secret rotation.
+type StripeVerifier struct {
+	Tolerance time.Duration    // Maximum age of the timestamp (0 = not checked)
+	Now       func() time.Time // Defaults to time.Now
+}
+
+// Verify implements Verifier
+func (v StripeVerifier) Verify(header http.Header, body []byte, secret string) error {
+	signature := header.Get("Stripe-Signature")
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if err := checkTimestamp(timestamp, v.Tolerance, v.Now); err != nil {
+		return err
+	}
+	payload := append([]byte(timestamp+"."), body...)
+	for _, sig := range signatures {
+		if hmacEqual(secret, payload, sig) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// SlackVerifier checks Slack's X-Slack-Signature header ("v0=" + hex HMAC-SHA256 of
+// "v0:<X-Slack-Request-Timestamp>:<body>") using the app's signing secret
+type SlackVerifier struct {
+	Tolerance time.Duration    // Maximum age of the timestamp (0 = not checked)
+	Now       func() time.Time // Defaults to time.Now
+}
+
+// Verify implements Verifier
+func (v SlackVerifier) Verify(header http.Header, body []byte, secret string) error {
+	signature := header.Get("X-Slack-Signature")
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+	if err := checkTimestamp(timestamp, v.Tolerance, v.Now); err != nil {
+		return err
+	}
+	hexMAC, ok := strings.CutPrefix(signature, "v0=")
+	if !ok || !hmacEqual(secret, append([]byte("v0:"+timestamp+":"), body...), hexMAC) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// checkTimestamp rejects a Unix timestamp further than tolerance from now
+func checkTimestamp(timestamp string, tolerance time.Duration, now func() time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+	}
+	if tolerance <= 0 {
+		return nil
+	}
+	if now == nil {
+		now = time.Now
+	}
+	age := now().Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// hmacEqual reports whether hexMAC is the hex HMAC-SHA256 of payload, compared in constant time
+func hmacEqual(secret string, payload []byte, hexMAC string) bool {
+	return hmac.Equal([]byte(strings.ToLower(hexMAC)), []byte(Sign(secret, payload)))
+}
+
+// Sign returns the hex HMAC-SHA256 of payload, as used by every built-in scheme
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
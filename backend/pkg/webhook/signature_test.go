@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "It's a Secret to Everybody"
+
+var testBody = []byte(`{"action":"opened","number":1}`)
+
+func header(pairs ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i < len(pairs); i += 2 {
+		h.Set(pairs[i], pairs[i+1])
+	}
+	return h
+}
+
+func TestGitHubVerifier(t *testing.T) {
+	registry := NewRegistry()
+	// Example from GitHub's webhook documentation
+	known := header("X-Hub-Signature-256", "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17")
+	assert.NoError(t, registry.Verify(SchemeGitHub, known, []byte("Hello, World!"), testSecret))
+
+	valid := header("X-Hub-Signature-256", "sha256="+Sign(testSecret, testBody))
+	assert.NoError(t, registry.Verify(SchemeGitHub, valid, testBody, testSecret))
+
+	tampered := []byte(`{"action":"closed","number":1}`)
+	assert.ErrorIs(t, registry.Verify(SchemeGitHub, valid, tampered, testSecret), ErrInvalidSignature)
+	assert.ErrorIs(t, registry.Verify(SchemeGitHub, valid, testBody, "wrong secret"), ErrInvalidSignature)
+	assert.ErrorIs(t, registry.Verify(SchemeGitHub, header("X-Hub-Signature-256", Sign(testSecret, testBody)), testBody, testSecret),
+		ErrInvalidSignature, "the sha256= prefix is required")
+	assert.ErrorIs(t, registry.Verify(SchemeGitHub, http.Header{}, testBody, testSecret), ErrMissingSignature)
+}
+
+func TestHMACVerifier(t *testing.T) {
+	registry := NewRegistry()
+	signature := Sign(testSecret, testBody)
+
+	assert.NoError(t, registry.Verify(SchemeHMAC, header("X-Webhook-Signature", signature), testBody, testSecret))
+	assert.NoError(t, registry.Verify(SchemeHMAC, header("X-Webhook-Signature", "sha256="+signature), testBody, testSecret))
+
+	assert.ErrorIs(t, registry.Verify(SchemeHMAC, header("X-Webhook-Signature", signature), append(testBody, ' '), testSecret), ErrInvalidSignature)
+	assert.ErrorIs(t, registry.Verify(SchemeHMAC, header("X-Webhook-Signature", "deadbeef"), testBody, testSecret), ErrInvalidSignature)
+	assert.ErrorIs(t, registry.Verify(SchemeHMAC, http.Header{}, testBody, testSecret), ErrMissingSignature)
+}
+
+func TestStripeVerifier(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	verifier := StripeVerifier{Tolerance: DefaultTolerance, Now: func() time.Time { return now }}
+	stripeHeader := func(ts time.Time, body []byte) http.Header {
+		stamp := strconv.FormatInt(ts.Unix(), 10)
+		return header("Stripe-Signature", fmt.Sprintf("t=%s,v1=%s,v0=ignored", stamp, Sign(testSecret, append([]byte(stamp+"."), body...))))
+	}
+
+	assert.NoError(t, verifier.Verify(stripeHeader(now, testBody), testBody, testSecret))
+	assert.ErrorIs(t, verifier.Verify(stripeHeader(now, testBody), []byte(`{}`), testSecret), ErrInvalidSignature)
+	assert.ErrorIs(t, verifier.Verify(stripeHeader(now.Add(-10*time.Minute), testBody), testBody, testSecret), ErrInvalidSignature, "stale timestamps are rejected")
+	assert.ErrorIs(t, verifier.Verify(header("Stripe-Signature", "t=1700000000"), testBody, testSecret), ErrInvalidSignature)
+
+	// Any v1 signature may match, e.g. while the secret is rotated
+	rotated := stripeHeader(now, testBody)
+	rotated.Set("Stripe-Signature", "v1=0000,"+rotated.Get("Stripe-Signature"))
+	assert.NoError(t, verifier.Verify(rotated, testBody, testSecret))
+}
+
+func TestSlackVerifier(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	verifier := SlackVerifier{Tolerance: DefaultTolerance, Now: func() time.Time { return now }}
+	ts := strconv.FormatInt(now.Unix(), 10)
+	signed := header(
+		"X-Slack-Request-Timestamp", ts,
+		"X-Slack-Signature", "v0="+Sign(testSecret, append([]byte("v0:"+ts+":"), testBody...)),
+	)
+
+	assert.NoError(t, verifier.Verify(signed, testBody, testSecret))
+	assert.ErrorIs(t, verifier.Verify(signed, []byte(`{}`), testSecret), ErrInvalidSignature)
+
+	replayed := signed.Clone()
+	replayed.Set("X-Slack-Request-Timestamp", strconv.FormatInt(now.Add(-time.Hour).Unix(), 10))
+	assert.ErrorIs(t, verifier.Verify(replayed, testBody, testSecret), ErrInvalidSignature)
+
+	assert.ErrorIs(t, verifier.Verify(header("X-Slack-Signature", "v0=abc"), testBody, testSecret), ErrMissingSignature)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	for _, scheme := range []string{SchemeGitHub, SchemeStripe, SchemeSlack, SchemeHMAC} {
+		_, ok := registry.Get(scheme)
+		assert.True(t, ok, scheme)
+	}
+
+	err := registry.Verify("unknown", http.Header{}, testBody, testSecret)
+	assert.ErrorIs(t, err, ErrUnknownScheme)
+
+	registry.Register("token", VerifierFunc(func(header http.Header, body []byte, secret string) error {
+		if header.Get("X-Token") != secret {
+			return ErrInvalidSignature
+		}
+		return nil
+	}))
+	require.NoError(t, registry.Verify("token", header("X-Token", testSecret), testBody, testSecret))
+	assert.True(t, errors.Is(registry.Verify("token", http.Header{}, testBody, testSecret), ErrInvalidSignature))
+}
@@ -133,7 +133,8 @@ CREATE TABLE public.project_versions (
     version integer NOT NULL,
     definition jsonb NOT NULL,
     saved_by uuid,
-    saved_at timestamp with time zone DEFAULT now()
+    saved_at timestamp with time zone DEFAULT now(),
+    content_hash character varying(64)
 );
 
 COMMENT ON TABLE public.project_versions IS 'Version history for projects (immutable snapshots)';
@@ -302,7 +303,8 @@ CREATE TABLE public.block_versions (
     ui_config jsonb NOT NULL,
     change_summary text,
     changed_by uuid,
-    created_at timestamp with time zone DEFAULT now() NOT NULL
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    content_hash character varying(64)
 );
 
 COMMENT ON TABLE public.block_versions IS 'Version history for block definitions, enables rollback';
@@ -333,7 +335,13 @@ CREATE TABLE public.runs (
     created_at timestamp with time zone DEFAULT now(),
     trigger_source character varying(100),
     trigger_metadata jsonb DEFAULT '{}'::jsonb,
-    deleted_at timestamp with time zone
+    deleted_at timestamp with time zone,
+    cancelled_by uuid,
+    cancel_reason text,
+    summary jsonb,
+    retry_of_run_id uuid,
+    retry_attempt integer DEFAULT 0 NOT NULL,
+    variables_snapshot jsonb
 );
 
 COMMENT ON COLUMN public.runs.project_id IS 'Reference to parent project';
@@ -342,6 +350,12 @@ COMMENT ON COLUMN public.runs.start_step_id IS 'Which Start block triggered this
 COMMENT ON COLUMN public.runs.trigger_source IS 'Internal trigger source identifier: copilot, audit-system, etc.';
 COMMENT ON COLUMN public.runs.trigger_metadata IS 'Additional metadata about the trigger: feature, user_id, session_id, etc.';
 COMMENT ON COLUMN public.runs.run_number IS 'Sequential run number per project + triggered_by combination';
+COMMENT ON COLUMN public.runs.cancelled_by IS 'User who cancelled the run; NULL for automated cancellations';
+COMMENT ON COLUMN public.runs.cancel_reason IS 'Reason given when the run was cancelled';
+COMMENT ON COLUMN public.runs.summary IS 'Aggregates at completion: step_count, failed_step_count, total_cost_usd, total_tokens, total_duration_ms, output_preview';
+COMMENT ON COLUMN public.runs.retry_of_run_id IS 'First run of the chain this automatic retry belongs to';
+COMMENT ON COLUMN public.runs.retry_attempt IS 'Number of automatic retries before this run (0 for the original run)';
+COMMENT ON COLUMN public.runs.variables_snapshot IS 'Organization, project and personal variables at run creation: {"org": {...}, "project": {...}, "personal": {...}}; secret variables stay encrypted';
 
 --
 -- Name: run_number_sequences; Type: TABLE; Schema: public; Owner: -
@@ -374,10 +388,14 @@ CREATE TABLE public.step_runs (
     started_at timestamp with time zone,
     completed_at timestamp with time zone,
     duration_ms integer,
-    created_at timestamp with time zone DEFAULT now()
+    created_at timestamp with time zone DEFAULT now(),
+    evaluation jsonb,
+    item_index integer
 );
 
 COMMENT ON COLUMN public.step_runs.sequence_number IS 'Execution order within the same run and attempt (1-indexed)';
+COMMENT ON COLUMN public.step_runs.evaluation IS 'How a condition or switch step chose its branch, e.g. {"expression": "...", "result": true}; NULL for other steps';
+COMMENT ON COLUMN public.step_runs.item_index IS 'Index of the map item a streaming map''s item step ran for; NULL for other step runs';
 
 -- ============================================================================
 -- Scheduling
@@ -618,6 +636,10 @@ CREATE TABLE public.copilot_sessions (
     spec jsonb,
     project_id uuid,
 
+    -- Agent memory controls
+    memory_window integer,
+    memory_cleared_at timestamp with time zone,
+
     -- Timestamps
     created_at timestamp with time zone DEFAULT now() NOT NULL,
     updated_at timestamp with time zone DEFAULT now() NOT NULL,
@@ -625,12 +647,15 @@ CREATE TABLE public.copilot_sessions (
     CONSTRAINT copilot_sessions_mode_check CHECK (((mode)::text = ANY ((ARRAY['create'::character varying, 'enhance'::character varying, 'explain'::character varying])::text[]))),
     CONSTRAINT copilot_sessions_status_check CHECK (((status)::text = ANY ((ARRAY['hearing'::character varying, 'building'::character varying, 'reviewing'::character varying, 'refining'::character varying, 'completed'::character varying, 'abandoned'::character varying])::text[]))),
     CONSTRAINT copilot_sessions_phase_check CHECK (((hearing_phase)::text = ANY ((ARRAY['analysis'::character varying, 'proposal'::character varying, 'completed'::character varying])::text[]))),
-    CONSTRAINT copilot_sessions_progress_check CHECK ((hearing_progress >= 0 AND hearing_progress <= 100))
+    CONSTRAINT copilot_sessions_progress_check CHECK ((hearing_progress >= 0 AND hearing_progress <= 100)),
+    CONSTRAINT copilot_sessions_memory_window_check CHECK ((memory_window IS NULL OR memory_window > 0))
 );
 
 COMMENT ON TABLE public.copilot_sessions IS 'AI Copilot sessions for interactive workflow creation/enhancement';
 COMMENT ON COLUMN public.copilot_sessions.context_project_id IS 'The workflow this session is scoped to (NULL for global create)';
 COMMENT ON COLUMN public.copilot_sessions.mode IS 'Copilot mode: create (new workflow), enhance (improve existing), explain (understand)';
+COMMENT ON COLUMN public.copilot_sessions.memory_window IS 'Per-session override of the agent memory window (NULL = workflow default)';
+COMMENT ON COLUMN public.copilot_sessions.memory_cleared_at IS 'Messages created before this time are not sent to the agent as history';
 COMMENT ON COLUMN public.copilot_sessions.status IS 'Session status: hearing, building, reviewing, refining, completed, abandoned';
 COMMENT ON COLUMN public.copilot_sessions.hearing_phase IS 'Current hearing phase: analysis, proposal, completed';
 COMMENT ON COLUMN public.copilot_sessions.spec IS 'WorkflowSpec DSL as JSON';
@@ -909,6 +934,7 @@ ALTER TABLE ONLY public.runs ADD CONSTRAINT runs_tenant_id_fkey FOREIGN KEY (ten
 ALTER TABLE ONLY public.runs ADD CONSTRAINT runs_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id);
 ALTER TABLE ONLY public.runs ADD CONSTRAINT runs_start_step_id_fkey FOREIGN KEY (start_step_id) REFERENCES public.steps(id);
 ALTER TABLE ONLY public.runs ADD CONSTRAINT runs_triggered_by_user_fkey FOREIGN KEY (triggered_by_user) REFERENCES public.users(id);
+ALTER TABLE ONLY public.runs ADD CONSTRAINT runs_cancelled_by_fkey FOREIGN KEY (cancelled_by) REFERENCES public.users(id);
 
 -- Run Number Sequences
 ALTER TABLE ONLY public.run_number_sequences ADD CONSTRAINT run_number_sequences_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE CASCADE;
@@ -970,13 +996,16 @@ CREATE TABLE public.vector_collections (
     embedding_provider character varying(50) DEFAULT 'openai'::character varying NOT NULL,
     embedding_model character varying(100) DEFAULT 'text-embedding-3-small'::character varying NOT NULL,
     dimension integer DEFAULT 1536 NOT NULL,
+    distance_metric character varying(20) DEFAULT 'cosine'::character varying NOT NULL,
     document_count integer DEFAULT 0,
     metadata jsonb DEFAULT '{}'::jsonb,
     created_at timestamp with time zone DEFAULT now(),
-    updated_at timestamp with time zone DEFAULT now()
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT vector_collections_distance_metric_check CHECK (((distance_metric)::text = ANY ((ARRAY['cosine'::character varying, 'l2'::character varying, 'inner_product'::character varying])::text[])))
 );
 
 COMMENT ON TABLE public.vector_collections IS 'RAG vector collections with tenant isolation';
+COMMENT ON COLUMN public.vector_collections.distance_metric IS 'Similarity metric used by queries: cosine (<=>), l2 (<->), inner_product (<#>)';
 COMMENT ON COLUMN public.vector_collections.dimension IS 'Vector dimension (1536 for text-embedding-3-small, 3072 for text-embedding-3-large). Note: vector_documents.embedding is fixed at 1536d - use separate collections for different dimensions.';
 
 --
@@ -1306,6 +1335,278 @@ ALTER TABLE ONLY public.custom_block_packages ADD CONSTRAINT custom_block_packag
 -- Custom Block Packages Trigger
 CREATE TRIGGER trigger_custom_block_packages_updated_at BEFORE UPDATE ON public.custom_block_packages FOR EACH ROW EXECUTE FUNCTION public.update_updated_at_column();
 
+-- ============================================================================
+-- Project Tags
+-- ============================================================================
+
+ALTER TABLE public.projects ADD COLUMN tags text[] DEFAULT '{}'::text[] NOT NULL;
+
+COMMENT ON COLUMN public.projects.tags IS 'Organizational tags, stored lowercase for case-insensitive matching';
+
+CREATE INDEX idx_projects_tags ON public.projects USING gin (tags);
+
+-- ============================================================================
+-- Run Retry
+-- ============================================================================
+
+ALTER TABLE public.projects ADD COLUMN run_retry jsonb;
+
+COMMENT ON COLUMN public.projects.run_retry IS 'Run retry policy: {"max_attempts": 3, "backoff_seconds": 30, "backoff_multiplier": 2, "retry_on": ["timeout", ...]}; NULL disables retries';
+
+ALTER TABLE ONLY public.runs ADD CONSTRAINT runs_retry_of_run_id_fkey FOREIGN KEY (retry_of_run_id) REFERENCES public.runs(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_runs_retry_of ON public.runs USING btree (retry_of_run_id) WHERE (retry_of_run_id IS NOT NULL);
+
+-- ============================================================================
+-- Output Retention
+-- ============================================================================
+
+ALTER TABLE public.step_runs ADD COLUMN outputs_cleared_at timestamp with time zone;
+
+COMMENT ON COLUMN public.step_runs.outputs_cleared_at IS 'When output retention cleared input, output and streaming_output; NULL while the payloads are kept';
+
+CREATE INDEX idx_step_runs_outputs_retained ON public.step_runs USING btree (tenant_id, created_at) WHERE (outputs_cleared_at IS NULL);
+
+-- ============================================================================
+-- Run Search
+-- ============================================================================
+
+CREATE INDEX idx_runs_tenant_created ON public.runs USING btree (tenant_id, created_at DESC, id DESC) WHERE (deleted_at IS NULL);
+
+CREATE INDEX idx_runs_input_gin ON public.runs USING gin (input jsonb_path_ops);
+
+CREATE INDEX idx_runs_output_gin ON public.runs USING gin (output jsonb_path_ops);
+
+CREATE INDEX idx_runs_trigger_metadata_gin ON public.runs USING gin (trigger_metadata jsonb_path_ops);
+
+-- ============================================================================
+-- Schema Migrations (managed by cmd/migrate)
+-- ============================================================================
+
+CREATE TABLE public.schema_migrations (
+    version bigint NOT NULL,
+    name text NOT NULL,
+    applied_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE public.schema_migrations IS 'Versioned SQL migrations applied from backend/migrations by cmd/migrate';
+
+ALTER TABLE ONLY public.schema_migrations ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+
+-- Template Usages
+CREATE TABLE public.template_usages (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    template_id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    project_id uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE public.template_usages IS 'Projects created from templates, per user (required to review a template)';
+
+-- Template Usages Constraints
+ALTER TABLE ONLY public.template_usages ADD CONSTRAINT template_usages_pkey PRIMARY KEY (id);
+
+-- Template Usages Indexes
+CREATE INDEX idx_template_usages_template_user ON public.template_usages USING btree (template_id, user_id);
+
+-- Template Usages Foreign Keys
+ALTER TABLE ONLY public.template_usages ADD CONSTRAINT template_usages_template_id_fkey FOREIGN KEY (template_id) REFERENCES public.project_templates(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.template_usages ADD CONSTRAINT template_usages_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.template_usages ADD CONSTRAINT template_usages_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE SET NULL;
+
+-- Favorites
+CREATE TABLE public.favorites (
+    tenant_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    target_type character varying(20) NOT NULL,
+    target_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT favorites_target_type_check CHECK (((target_type)::text = ANY ((ARRAY['project'::character varying, 'template'::character varying])::text[])))
+);
+
+COMMENT ON TABLE public.favorites IS 'Per-user favorite workflows and templates within a tenant';
+
+-- Favorites Constraints
+ALTER TABLE ONLY public.favorites ADD CONSTRAINT favorites_pkey PRIMARY KEY (tenant_id, user_id, target_type, target_id);
+
+-- Favorites Indexes
+CREATE INDEX idx_favorites_target ON public.favorites USING btree (target_type, target_id);
+
+-- Favorites Foreign Keys
+ALTER TABLE ONLY public.favorites ADD CONSTRAINT favorites_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+
+-- Run Annotations
+CREATE TABLE public.run_annotations (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    tenant_id uuid NOT NULL,
+    run_id uuid NOT NULL,
+    step_id uuid,
+    author_id uuid NOT NULL,
+    comment text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE public.run_annotations IS 'User notes on runs, optionally referencing a step';
+
+-- Run Annotations Constraints
+ALTER TABLE ONLY public.run_annotations ADD CONSTRAINT run_annotations_pkey PRIMARY KEY (id);
+
+-- Run Annotations Indexes
+CREATE INDEX idx_run_annotations_run ON public.run_annotations USING btree (tenant_id, run_id, created_at);
+
+-- Run Annotations Foreign Keys
+ALTER TABLE ONLY public.run_annotations ADD CONSTRAINT run_annotations_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.run_annotations ADD CONSTRAINT run_annotations_run_id_fkey FOREIGN KEY (run_id) REFERENCES public.runs(id) ON DELETE CASCADE;
+
+-- Run Checkpoints
+CREATE TABLE public.run_checkpoints (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    tenant_id uuid NOT NULL,
+    run_id uuid NOT NULL,
+    step_id uuid NOT NULL,
+    output_port character varying(100) DEFAULT 'output'::character varying NOT NULL,
+    outputs jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    output_ports jsonb DEFAULT '{}'::jsonb NOT NULL
+);
+
+COMMENT ON TABLE public.run_checkpoints IS 'Accumulated step outputs at checkpoint steps, used to resume failed runs';
+COMMENT ON COLUMN public.run_checkpoints.output_ports IS 'Step/block group ID -> output port it completed with; empty for checkpoints saved before this column';
+
+-- Run Checkpoints Constraints
+ALTER TABLE ONLY public.run_checkpoints ADD CONSTRAINT run_checkpoints_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.run_checkpoints ADD CONSTRAINT run_checkpoints_run_id_step_id_key UNIQUE (run_id, step_id);
+
+-- Run Checkpoints Indexes
+CREATE INDEX idx_run_checkpoints_run ON public.run_checkpoints USING btree (tenant_id, run_id, created_at DESC);
+
+-- Run Checkpoints Foreign Keys
+ALTER TABLE ONLY public.run_checkpoints ADD CONSTRAINT run_checkpoints_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.run_checkpoints ADD CONSTRAINT run_checkpoints_run_id_fkey FOREIGN KEY (run_id) REFERENCES public.runs(id) ON DELETE CASCADE;
+
+-- ============================================================================
+-- Project Permissions
+-- ============================================================================
+
+CREATE TABLE public.project_permissions (
+    tenant_id uuid NOT NULL,
+    project_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    role character varying(20) NOT NULL,
+    granted_by uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT project_permissions_role_check CHECK (((role)::text = ANY ((ARRAY['owner'::character varying, 'editor'::character varying, 'runner'::character varying, 'viewer'::character varying])::text[])))
+);
+
+COMMENT ON TABLE public.project_permissions IS 'Per-user workflow access; a workflow without permissions is open to every user of the tenant';
+
+-- Project Permissions Constraints
+ALTER TABLE ONLY public.project_permissions ADD CONSTRAINT project_permissions_pkey PRIMARY KEY (project_id, user_id);
+
+-- Project Permissions Indexes
+CREATE INDEX idx_project_permissions_tenant ON public.project_permissions USING btree (tenant_id, project_id);
+
+-- Project Permissions Foreign Keys
+ALTER TABLE ONLY public.project_permissions ADD CONSTRAINT project_permissions_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.project_permissions ADD CONSTRAINT project_permissions_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE CASCADE;
+
+-- ============================================================================
+-- Run Output
+-- ============================================================================
+
+ALTER TABLE public.projects ADD COLUMN run_output jsonb;
+
+COMMENT ON COLUMN public.projects.run_output IS 'Run output selection: {"step_id": "..."} or {"mapping": {"field": "<step_id>", ...}}; NULL uses the terminal steps';
+
+-- ============================================================================
+-- Notification Channels
+-- ============================================================================
+
+CREATE TABLE public.notification_channels (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    type character varying(20) NOT NULL,
+    config jsonb DEFAULT '{}'::jsonb NOT NULL,
+    events text[] DEFAULT '{}'::text[] NOT NULL,
+    enabled boolean DEFAULT true NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT notification_channels_type_check CHECK (((type)::text = ANY ((ARRAY['slack'::character varying, 'email'::character varying, 'webhook'::character varying])::text[])))
+);
+
+COMMENT ON TABLE public.notification_channels IS 'Per-tenant destinations (Slack, email, webhook) for system event notifications';
+
+-- Notification Channels Constraints
+ALTER TABLE ONLY public.notification_channels ADD CONSTRAINT notification_channels_pkey PRIMARY KEY (id);
+
+-- Notification Channels Indexes
+CREATE INDEX idx_notification_channels_tenant ON public.notification_channels USING btree (tenant_id);
+
+-- Notification Channels Foreign Keys
+ALTER TABLE ONLY public.notification_channels ADD CONSTRAINT notification_channels_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+
+-- ============================================================================
+-- Failure Notifications
+-- ============================================================================
+
+ALTER TABLE public.projects ADD COLUMN failure_notifications jsonb;
+
+COMMENT ON COLUMN public.projects.failure_notifications IS 'Run failure notification rules: [{"channel_id": "...", "consecutive_failures": N, "categories": [...]}]; NULL notifies the tenant channels subscribed to run.failed';
+
+-- ============================================================================
+-- Schedule Bulk Pause
+-- ============================================================================
+
+ALTER TABLE public.schedules ADD COLUMN bulk_paused boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN public.schedules.bulk_paused IS 'True while the schedule is paused by pause-all; resume-all reactivates only these schedules';
+
+-- ============================================================================
+-- Project Paused
+-- ============================================================================
+
+ALTER TABLE public.projects ADD COLUMN paused boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN public.projects.paused IS 'True while the project is paused: new runs are rejected and due schedules are skipped';
+
+-- ============================================================================
+-- Block Examples
+-- ============================================================================
+
+ALTER TABLE public.block_definitions ADD COLUMN examples jsonb;
+
+COMMENT ON COLUMN public.block_definitions.examples IS 'Array of {name, config, input, output} examples; NULL when the block has none';
+
+-- ============================================================================
+-- Library Functions
+-- ============================================================================
+
+CREATE TABLE public.library_functions (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    name character varying(64) NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    code text NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE public.library_functions IS 'Per-tenant JavaScript helpers that function steps load by name with require()';
+
+-- Library Functions Constraints
+ALTER TABLE ONLY public.library_functions ADD CONSTRAINT library_functions_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.library_functions ADD CONSTRAINT library_functions_tenant_id_name_key UNIQUE (tenant_id, name);
+
+-- Library Functions Foreign Keys
+ALTER TABLE ONLY public.library_functions ADD CONSTRAINT library_functions_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON DELETE CASCADE;
+
 --
 -- PostgreSQL database dump complete
 --
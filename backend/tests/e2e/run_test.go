@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -262,6 +263,42 @@ func TestRunWithVersion(t *testing.T) {
 	assert.Equal(t, 1, createResp.Data.ProjectVersion)
 }
 
+func TestRunWithVersionSnapshotsVersionVariables(t *testing.T) {
+	wfInfo := createTestWorkflowForRuns(t)
+	defer makeRequest(t, "DELETE", "/api/v1/workflows/"+wfInfo.WorkflowID, nil)
+
+	// Version 1 was published with the project's variables; edit the live variables afterwards
+	var versionVariables string
+	err := db.QueryRow(`SELECT COALESCE(definition->'variables', '{}'::jsonb)::text FROM project_versions WHERE project_id = $1 AND version = 1`,
+		wfInfo.WorkflowID).Scan(&versionVariables)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE projects SET variables = '{"region": "edited-after-publish"}' WHERE id = $1`, wfInfo.WorkflowID)
+	require.NoError(t, err)
+
+	runReq := map[string]interface{}{
+		"input":         map[string]string{},
+		"triggered_by":  "test",
+		"start_step_id": wfInfo.StartStepID,
+		"version":       1,
+	}
+	resp, body := makeRequest(t, "POST", fmt.Sprintf("/api/v1/workflows/%s/runs", wfInfo.WorkflowID), runReq)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "Create response: %s", string(body))
+
+	var createResp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &createResp))
+
+	// The run's project variables are those of the pinned version, not the edited live ones
+	var snapshotVariables string
+	err = db.QueryRow(`SELECT (variables_snapshot->'project')::text FROM runs WHERE id = $1`, createResp.Data.ID).Scan(&snapshotVariables)
+	require.NoError(t, err)
+	assert.JSONEq(t, versionVariables, snapshotVariables)
+	assert.NotContains(t, snapshotVariables, "edited-after-publish")
+}
+
 func TestRunWithInvalidVersion(t *testing.T) {
 	wfInfo := createTestWorkflowForRuns(t)
 	defer makeRequest(t, "DELETE", "/api/v1/workflows/"+wfInfo.WorkflowID, nil)
@@ -425,6 +462,100 @@ func TestEdgeOperations(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 }
 
+func TestStepBatchDelete(t *testing.T) {
+	createReq := map[string]string{
+		"name": "Step Batch Delete Test " + time.Now().Format("20060102150405"),
+	}
+	resp, body := makeRequest(t, "POST", "/api/v1/workflows", createReq)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var createResp struct {
+		Data Workflow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &createResp))
+	workflowID := createResp.Data.ID
+	defer makeRequest(t, "DELETE", "/api/v1/workflows/"+workflowID, nil)
+
+	_, steps := getWorkflowWithSteps(t, workflowID)
+	startStep := findStartStep(steps)
+	require.NotNil(t, startStep)
+
+	createStep := func(name string) string {
+		resp, body := makeRequest(t, "POST", fmt.Sprintf("/api/v1/workflows/%s/steps", workflowID), map[string]interface{}{
+			"name":   name,
+			"type":   "tool",
+			"config": map[string]string{"adapter_id": "mock"},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var stepResp struct {
+			Data Step `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &stepResp))
+		return stepResp.Data.ID
+	}
+	createEdge := func(sourceID, targetID string) string {
+		resp, body := makeRequest(t, "POST", fmt.Sprintf("/api/v1/workflows/%s/edges", workflowID), map[string]string{
+			"source_step_id": sourceID,
+			"target_step_id": targetID,
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var edgeResp struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &edgeResp))
+		return edgeResp.Data.ID
+	}
+
+	first := createStep("First")
+	second := createStep("Second")
+	keep := createStep("Keep")
+	startToFirst := createEdge(startStep.ID, first)
+	firstToSecond := createEdge(first, second)
+	startToKeep := createEdge(startStep.ID, keep)
+
+	// Unknown step IDs are rejected without deleting anything
+	resp, _ = makeRequest(t, "DELETE", fmt.Sprintf("/api/v1/workflows/%s/steps/batch", workflowID), map[string][]string{
+		"step_ids": {first, "00000000-0000-0000-0000-000000000001"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body = makeRequest(t, "DELETE", fmt.Sprintf("/api/v1/workflows/%s/steps/batch", workflowID), map[string][]string{
+		"step_ids": {first, second},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var deleteResp struct {
+		Data struct {
+			DeletedStepIDs []string `json:"deleted_step_ids"`
+			RemovedEdgeIDs []string `json:"removed_edge_ids"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &deleteResp))
+	assert.ElementsMatch(t, []string{first, second}, deleteResp.Data.DeletedStepIDs)
+	assert.ElementsMatch(t, []string{startToFirst, firstToSecond}, deleteResp.Data.RemovedEdgeIDs)
+
+	// The unrelated step and edge remain
+	_, steps = getWorkflowWithSteps(t, workflowID)
+	stepIDs := make([]string, 0, len(steps))
+	for _, s := range steps {
+		stepIDs = append(stepIDs, s.ID)
+	}
+	assert.ElementsMatch(t, []string{startStep.ID, keep}, stepIDs)
+
+	resp, body = makeRequest(t, "GET", fmt.Sprintf("/api/v1/workflows/%s/edges", workflowID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var edgeList struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &edgeList))
+	require.Len(t, edgeList.Data, 1)
+	assert.Equal(t, startToKeep, edgeList.Data[0].ID)
+}
+
 func TestVersionOperations(t *testing.T) {
 	wfInfo := createTestWorkflowForRuns(t)
 	defer makeRequest(t, "DELETE", "/api/v1/workflows/"+wfInfo.WorkflowID, nil)
@@ -505,3 +636,76 @@ func TestRunCompletionWithStepRuns(t *testing.T) {
 	// Step runs should be populated
 	assert.GreaterOrEqual(t, len(runWithSteps.Data.StepRuns), 1, "Should have step runs after completion")
 }
+
+func TestRunSearch(t *testing.T) {
+	wfInfo := createTestWorkflowForRuns(t)
+	defer makeRequest(t, "DELETE", "/api/v1/workflows/"+wfInfo.WorkflowID, nil)
+
+	tier := "tier-" + time.Now().Format("20060102150405.000000")
+	before := time.Now().UTC().Add(-time.Second)
+
+	// Create runs with nested input; only two match the searched tier
+	for _, customerTier := range []string{tier, tier, "other"} {
+		runReq := map[string]interface{}{
+			"input":         map[string]interface{}{"customer": map[string]string{"tier": customerTier}},
+			"triggered_by":  "test",
+			"start_step_id": wfInfo.StartStepID,
+		}
+		resp, body := makeRequest(t, "POST", fmt.Sprintf("/api/v1/workflows/%s/runs", wfInfo.WorkflowID), runReq)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "create response: %s", string(body))
+	}
+
+	type searchResponse struct {
+		Data []Run `json:"data"`
+		Meta struct {
+			Limit      int    `json:"limit"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"meta"`
+	}
+	search := func(query url.Values) searchResponse {
+		resp, body := makeRequest(t, "GET", "/api/v1/runs/search?"+query.Encode(), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "search response: %s", string(body))
+		var result searchResponse
+		require.NoError(t, json.Unmarshal(body, &result))
+		return result
+	}
+
+	t.Run("nested input containment", func(t *testing.T) {
+		result := search(url.Values{"input": {fmt.Sprintf(`{"customer":{"tier":%q}}`, tier)}})
+		assert.Len(t, result.Data, 2)
+		for _, run := range result.Data {
+			assert.Equal(t, wfInfo.WorkflowID, run.ProjectID)
+		}
+	})
+
+	t.Run("keyset pagination", func(t *testing.T) {
+		query := url.Values{"input": {fmt.Sprintf(`{"customer":{"tier":%q}}`, tier)}, "limit": {"1"}}
+		first := search(query)
+		require.Len(t, first.Data, 1)
+		require.NotEmpty(t, first.Meta.NextCursor)
+
+		query.Set("cursor", first.Meta.NextCursor)
+		second := search(query)
+		require.Len(t, second.Data, 1)
+		assert.NotEqual(t, first.Data[0].ID, second.Data[0].ID)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		inRange := search(url.Values{
+			"project_id": {wfInfo.WorkflowID},
+			"from":       {before.Format(time.RFC3339)},
+		})
+		assert.Len(t, inRange.Data, 3)
+
+		outOfRange := search(url.Values{
+			"project_id": {wfInfo.WorkflowID},
+			"to":         {before.Format(time.RFC3339)},
+		})
+		assert.Empty(t, outOfRange.Data)
+	})
+
+	t.Run("invalid JSON filter", func(t *testing.T) {
+		resp, _ := makeRequest(t, "GET", "/api/v1/runs/search?"+url.Values{"input": {`{"a":`}}.Encode(), nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
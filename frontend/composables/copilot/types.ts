@@ -248,17 +248,25 @@ export interface AgentThinkingEvent {
 
 export interface AgentToolCallEvent {
   type: 'tool_call'
-  data: { tool_name: string; arguments: Record<string, unknown> }
+  data: { iteration: number; tool_name: string; tool_call_id?: string; arguments: Record<string, unknown> }
 }
 
 export interface AgentToolResultEvent {
   type: 'tool_result'
-  data: { tool_name: string; result: unknown; is_error: boolean }
+  data: {
+    iteration: number
+    tool_name: string
+    tool_call_id?: string
+    result: unknown
+    truncated?: boolean // result was shortened for streaming
+    is_error: boolean
+    duration_ms: number
+  }
 }
 
 export interface AgentPartialTextEvent {
   type: 'partial_text'
-  data: { content: string }
+  data: { iteration: number; content: string }
 }
 
 export interface AgentCompleteEvent {
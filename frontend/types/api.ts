@@ -21,6 +21,7 @@ export interface Project {
   status: 'draft' | 'published'
   version: number
   variables?: object // Project-level shared variables
+  tags?: string[] // Normalized (lowercase) organizational tags
   is_system?: boolean
   system_slug?: string
   draft?: object // Draft state (unsaved changes)
@@ -121,6 +122,7 @@ export interface ProjectDefinition {
   name: string
   description: string
   variables?: object // Project-level shared variables
+  tags?: string[] // Normalized (lowercase) organizational tags
   steps: Step[]
   edges: Edge[]
   block_groups?: BlockGroup[]